@@ -10,8 +10,8 @@ import (
 
 	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcutil"
-	"github.com/coreos/bbolt"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 )
 
@@ -50,7 +50,7 @@ func ChannelGraphFromDatabase(db *channeldb.ChannelGraph) ChannelGraph {
 // channeldb.LightningNode. The wrapper method implement the autopilot.Node
 // interface.
 type dbNode struct {
-	tx *bbolt.Tx
+	tx kvdb.Tx
 
 	node *channeldb.LightningNode
 }
@@ -83,7 +83,7 @@ func (d dbNode) Addrs() []net.Addr {
 //
 // NOTE: Part of the autopilot.Node interface.
 func (d dbNode) ForEachChannel(cb func(ChannelEdge) error) error {
-	return d.node.ForEachChannel(d.tx, func(tx *bbolt.Tx,
+	return d.node.ForEachChannel(d.tx, func(tx kvdb.Tx,
 		ei *channeldb.ChannelEdgeInfo, ep, _ *channeldb.ChannelEdgePolicy) error {
 
 		// Skip channels for which no outgoing edge policy is available.
@@ -120,7 +120,7 @@ func (d dbNode) ForEachChannel(cb func(ChannelEdge) error) error {
 //
 // NOTE: Part of the autopilot.ChannelGraph interface.
 func (d *databaseChannelGraph) ForEachNode(cb func(Node) error) error {
-	return d.db.ForEachNode(nil, func(tx *bbolt.Tx, n *channeldb.LightningNode) error {
+	return d.db.ForEachNode(nil, func(tx kvdb.Tx, n *channeldb.LightningNode) error {
 
 		// We'll skip over any node that doesn't have any advertised
 		// addresses. As we won't be able to reach them to actually
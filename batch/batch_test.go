@@ -0,0 +1,189 @@
+package batch
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+)
+
+var testBucket = []byte("test")
+
+// makeTestDB creates a fresh bolt backed database containing a single bucket
+// for use in tests.
+func makeTestDB(t *testing.T) (kvdb.Backend, func()) {
+	t.Helper()
+
+	tempDir, err := ioutil.TempDir("", "batch")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+
+	db, err := kvdb.OpenBoltBackend(filepath.Join(tempDir, "test.db"))
+	if err != nil {
+		os.RemoveAll(tempDir)
+		t.Fatalf("unable to open db: %v", err)
+	}
+
+	err = db.Update(func(tx kvdb.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(testBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		os.RemoveAll(tempDir)
+		t.Fatalf("unable to create bucket: %v", err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(tempDir)
+	}
+}
+
+// TestTimeSchedulerBatch asserts that lazily scheduled requests are executed
+// within a single transaction, and that a failing request is retried in
+// isolation without affecting the rest of its batch.
+func TestTimeSchedulerBatch(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	scheduler := NewTimeScheduler(db, &sync.Mutex{}, time.Second)
+
+	const numRequests = 10
+	failIdx := numRequests / 2
+	errFail := errors.New("request failed")
+
+	var (
+		wg      sync.WaitGroup
+		txs     = make([]kvdb.Tx, numRequests)
+		results = make([]error, numRequests)
+	)
+	for i := 0; i < numRequests; i++ {
+		i := i
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			r := &Request{
+				Update: func(tx kvdb.Tx) error {
+					if i == failIdx {
+						return errFail
+					}
+
+					// Only the transaction of the last
+					// attempt is committed.
+					txs[i] = tx
+
+					return tx.Bucket(testBucket).Put(
+						[]byte{byte(i)}, []byte{byte(i)},
+					)
+				},
+			}
+			LazyAdd()(r)
+
+			results[i] = scheduler.Execute(r)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range results {
+		switch {
+		case i == failIdx && err != errFail:
+			t.Fatalf("expected request %d to fail with %v, got %v",
+				i, errFail, err)
+
+		case i != failIdx && err != nil:
+			t.Fatalf("unable to execute request %d: %v", i, err)
+		}
+	}
+
+	// All successful requests were pending at the same time, so they
+	// should have been committed in the same transaction, after the failed
+	// request had been removed from the batch.
+	committed := make(map[kvdb.Tx]struct{})
+	for i, tx := range txs {
+		if i != failIdx {
+			committed[tx] = struct{}{}
+		}
+	}
+	if len(committed) != 1 {
+		t.Fatalf("expected requests to be batched into a single "+
+			"transaction, got %d", len(committed))
+	}
+
+	err := db.View(func(tx kvdb.Tx) error {
+		bucket := tx.Bucket(testBucket)
+		for i := 0; i < numRequests; i++ {
+			value := bucket.Get([]byte{byte(i)})
+			switch {
+			case i == failIdx && value != nil:
+				t.Fatalf("found value of failed request %d", i)
+
+			case i != failIdx && value == nil:
+				t.Fatalf("value of request %d not found", i)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read db: %v", err)
+	}
+}
+
+// TestTimeSchedulerOnCommit asserts that a request's OnCommit closure is able
+// to override the result returned to the caller, and that a non-lazy request
+// is executed without waiting for the scheduler's full duration.
+func TestTimeSchedulerOnCommit(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	scheduler := NewTimeScheduler(db, nil, time.Hour)
+
+	errAlreadyExists := errors.New("already exists")
+
+	var exists bool
+	r := &Request{
+		Reset: func() {
+			exists = false
+		},
+		Update: func(tx kvdb.Tx) error {
+			exists = true
+			return nil
+		},
+		OnCommit: func(err error) error {
+			if err != nil {
+				return err
+			}
+			if exists {
+				return errAlreadyExists
+			}
+			return nil
+		},
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- scheduler.Execute(r)
+	}()
+
+	select {
+	case err := <-errChan:
+		if err != errAlreadyExists {
+			t.Fatalf("expected %v, got %v", errAlreadyExists, err)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("non-lazy request was not executed")
+	}
+}
@@ -0,0 +1,256 @@
+package blockfetch
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/litecoinfinance/btcd/blockchain"
+	"github.com/litecoinfinance/btcd/chaincfg"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/peer"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/build"
+)
+
+const (
+	// DefaultTimeout is the default time we'll wait for a single peer to
+	// complete the handshake and deliver a block.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultMaxPeers is the default number of peers we'll ask for a
+	// block before giving up.
+	DefaultMaxPeers = 8
+
+	// userAgentName is the user agent we announce to the peers we fetch
+	// blocks from.
+	userAgentName = "lnd-blockfetch"
+)
+
+var (
+	// ErrNoPeers is returned when a block is to be fetched from the
+	// network, but there are no peers to fetch it from.
+	ErrNoPeers = errors.New("no peers to fetch block from")
+
+	// errNotFullNode is returned when a peer doesn't serve historical
+	// blocks, as it's pruned itself.
+	errNotFullNode = errors.New("peer doesn't serve historical blocks")
+
+	// errBlockNotFound is returned when a peer replies that it doesn't
+	// have the requested block.
+	errBlockNotFound = errors.New("peer doesn't have block")
+)
+
+// IsBlockPruned returns true if the given error was returned by the chain
+// backend in response to a request for a block it has pruned.
+func IsBlockPruned(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "pruned data")
+}
+
+// Config houses the dependencies of the Fetcher.
+type Config struct {
+	// ChainParams are the parameters of the chain whose P2P network
+	// blocks are fetched from.
+	ChainParams *chaincfg.Params
+
+	// Peers returns the addresses of the peers blocks may be fetched
+	// from, in order of preference.
+	Peers func() ([]string, error)
+
+	// Dial connects to the peer with the given address.
+	Dial func(network, address string) (net.Conn, error)
+
+	// Timeout is the time we'll wait for a single peer to complete the
+	// handshake and deliver a block.
+	Timeout time.Duration
+
+	// MaxPeers is the number of peers we'll ask for a block before giving
+	// up.
+	MaxPeers int
+}
+
+// Fetcher retrieves blocks the chain backend has pruned from the peers of
+// the chain's P2P network. For each block, a short-lived connection is made
+// to each of the candidate peers in turn until one of them delivers it. As
+// the hash of the requested block is known, the block is authenticated by
+// checking its header hash, merkle root and witness commitment before it's
+// returned.
+type Fetcher struct {
+	cfg *Config
+}
+
+// New creates a new block fetcher backed by the given config.
+func New(cfg *Config) *Fetcher {
+	return &Fetcher{
+		cfg: cfg,
+	}
+}
+
+// GetBlock returns the block with the given hash as retrieved by fetchBlock
+// from the chain backend. If the backend has pruned the block, it's fetched
+// from the P2P network instead. A nil Fetcher is valid, and only queries the
+// backend.
+func (f *Fetcher) GetBlock(hash *chainhash.Hash,
+	fetchBlock func(*chainhash.Hash) (*wire.MsgBlock, error)) (
+	*wire.MsgBlock, error) {
+
+	block, err := fetchBlock(hash)
+	if f == nil || !IsBlockPruned(err) {
+		return block, err
+	}
+
+	log.Debugf("Block %v pruned by backend, fetching it from peers", hash)
+
+	return f.FetchBlock(hash)
+}
+
+// FetchBlock fetches the block with the given hash from the peers of the P2P
+// network.
+func (f *Fetcher) FetchBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	addrs, err := f.cfg.Peers()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch peers: %v", err)
+	}
+	if len(addrs) > f.cfg.MaxPeers {
+		addrs = addrs[:f.cfg.MaxPeers]
+	}
+	if len(addrs) == 0 {
+		return nil, ErrNoPeers
+	}
+
+	for _, addr := range addrs {
+		block, err := f.fetchFromPeer(addr, hash)
+		if err != nil {
+			log.Debugf("Unable to fetch block %v from peer %v: %v",
+				hash, addr, err)
+			continue
+		}
+
+		log.Infof("Fetched pruned block %v from peer %v", hash, addr)
+
+		return block, nil
+	}
+
+	return nil, fmt.Errorf("unable to fetch block %v from any of %d "+
+		"peers", hash, len(addrs))
+}
+
+// fetchFromPeer connects to the peer with the given address, and requests
+// the block with the given hash from it.
+func (f *Fetcher) fetchFromPeer(addr string,
+	hash *chainhash.Hash) (*wire.MsgBlock, error) {
+
+	verAck := make(chan struct{}, 1)
+	blocks := make(chan *wire.MsgBlock, 1)
+	notFound := make(chan struct{}, 1)
+
+	peerCfg := &peer.Config{
+		UserAgentName:    userAgentName,
+		UserAgentVersion: build.Version(),
+		ChainParams:      f.cfg.ChainParams,
+		Listeners: peer.MessageListeners{
+			OnVerAck: func(*peer.Peer, *wire.MsgVerAck) {
+				select {
+				case verAck <- struct{}{}:
+				default:
+				}
+			},
+			OnBlock: func(_ *peer.Peer, msg *wire.MsgBlock,
+				_ []byte) {
+
+				if msg.BlockHash() != *hash {
+					return
+				}
+
+				select {
+				case blocks <- msg:
+				default:
+				}
+			},
+			OnNotFound: func(*peer.Peer, *wire.MsgNotFound) {
+				select {
+				case notFound <- struct{}{}:
+				default:
+				}
+			},
+		},
+	}
+
+	p, err := peer.NewOutboundPeer(peerCfg, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := f.cfg.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	p.AssociateConnection(conn)
+	defer func() {
+		p.Disconnect()
+		p.WaitForDisconnect()
+	}()
+
+	timeout := time.After(f.cfg.Timeout)
+
+	select {
+	case <-verAck:
+	case <-timeout:
+		return nil, errors.New("handshake timed out")
+	}
+
+	// Pruned peers only serve the most recent blocks, so there's no point
+	// in asking them for historical ones.
+	if p.Services()&wire.SFNodeNetwork == 0 {
+		return nil, errNotFullNode
+	}
+
+	getData := wire.NewMsgGetData()
+	err = getData.AddInvVect(
+		wire.NewInvVect(wire.InvTypeWitnessBlock, hash),
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.QueueMessage(getData, nil)
+
+	var block *wire.MsgBlock
+	select {
+	case block = <-blocks:
+	case <-notFound:
+		return nil, errBlockNotFound
+	case <-timeout:
+		return nil, errors.New("block request timed out")
+	}
+
+	if err := validateBlock(block); err != nil {
+		return nil, fmt.Errorf("invalid block: %v", err)
+	}
+
+	return block, nil
+}
+
+// validateBlock ensures that the transactions of a block match the merkle
+// root and witness commitment of its header. Along with the hash of the
+// header matching the one requested, this ensures the block is the one we
+// asked for.
+func validateBlock(block *wire.MsgBlock) error {
+	if len(block.Transactions) == 0 {
+		return errors.New("block has no transactions")
+	}
+
+	utilBlock := btcutil.NewBlock(block)
+	merkles := blockchain.BuildMerkleTreeStore(
+		utilBlock.Transactions(), false,
+	)
+	merkleRoot := merkles[len(merkles)-1]
+	if !merkleRoot.IsEqual(&block.Header.MerkleRoot) {
+		return fmt.Errorf("merkle root mismatch: expected %v, got %v",
+			block.Header.MerkleRoot, merkleRoot)
+	}
+
+	return blockchain.ValidateWitnessCommitment(utilBlock)
+}
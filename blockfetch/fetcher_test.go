@@ -0,0 +1,201 @@
+package blockfetch
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/blockchain"
+	"github.com/litecoinfinance/btcd/chaincfg"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/peer"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+)
+
+// newTestBlock returns a block with a single transaction, which is made
+// unique by the given seed.
+func newTestBlock(seed byte) *wire.MsgBlock {
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript:  []byte{seed},
+	})
+	tx.AddTxOut(&wire.TxOut{Value: 1})
+
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Nonce: uint32(seed),
+		},
+		Transactions: []*wire.MsgTx{tx},
+	}
+	merkles := blockchain.BuildMerkleTreeStore(
+		btcutil.NewBlock(block).Transactions(), false,
+	)
+	block.Header.MerkleRoot = *merkles[len(merkles)-1]
+
+	return block
+}
+
+// servePeer accepts connections on a new listener, acting as a peer with the
+// given services that serves the given blocks. The address of the listener
+// is returned.
+func servePeer(t *testing.T, services wire.ServiceFlag,
+	blocks ...*wire.MsgBlock) string {
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	blockIndex := make(map[chainhash.Hash]*wire.MsgBlock)
+	for _, block := range blocks {
+		blockIndex[block.BlockHash()] = block
+	}
+
+	onGetData := func(p *peer.Peer, msg *wire.MsgGetData) {
+		notFound := wire.NewMsgNotFound()
+		for _, inv := range msg.InvList {
+			block, ok := blockIndex[inv.Hash]
+			if !ok {
+				notFound.AddInvVect(inv)
+				continue
+			}
+			p.QueueMessage(block, nil)
+		}
+		if len(notFound.InvList) > 0 {
+			p.QueueMessage(notFound, nil)
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			p := peer.NewInboundPeer(&peer.Config{
+				ChainParams: &chaincfg.RegressionNetParams,
+				Services:    services,
+				Listeners: peer.MessageListeners{
+					OnGetData: onGetData,
+				},
+			})
+			p.AssociateConnection(conn)
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func newTestFetcher(addrs ...string) *Fetcher {
+	return New(&Config{
+		ChainParams: &chaincfg.RegressionNetParams,
+		Peers: func() ([]string, error) {
+			return addrs, nil
+		},
+		Dial:     net.Dial,
+		Timeout:  10 * time.Second,
+		MaxPeers: DefaultMaxPeers,
+	})
+}
+
+// TestFetchBlock ensures that blocks are fetched from the first peer that
+// serves them intact, skipping pruned peers and those serving blocks that
+// don't match their header.
+func TestFetchBlock(t *testing.T) {
+	t.Parallel()
+
+	block := newTestBlock(1)
+
+	// The tampered block has the same header, but a different set of
+	// transactions.
+	tampered := *block
+	tampered.Transactions = newTestBlock(2).Transactions
+
+	fetcher := newTestFetcher(
+		servePeer(t, 0, block),
+		servePeer(t, wire.SFNodeNetwork, &tampered),
+		servePeer(t, wire.SFNodeNetwork),
+		servePeer(t, wire.SFNodeNetwork, block),
+	)
+
+	hash := block.BlockHash()
+	fetched, err := fetcher.FetchBlock(&hash)
+	if err != nil {
+		t.Fatalf("unable to fetch block: %v", err)
+	}
+	if fetched.BlockHash() != hash ||
+		fetched.Transactions[0].TxHash() !=
+			block.Transactions[0].TxHash() {
+
+		t.Fatalf("fetched wrong block")
+	}
+
+	// A block none of the peers serves can't be fetched.
+	unknownHash := newTestBlock(3).BlockHash()
+	if _, err := fetcher.FetchBlock(&unknownHash); err == nil {
+		t.Fatalf("expected unknown block not to be fetched")
+	}
+
+	// Without any peers, the fetcher should fail right away.
+	_, err = newTestFetcher().FetchBlock(&hash)
+	if err != ErrNoPeers {
+		t.Fatalf("expected ErrNoPeers, got %v", err)
+	}
+}
+
+// TestGetBlock ensures that blocks are only fetched from peers if the chain
+// backend has pruned them.
+func TestGetBlock(t *testing.T) {
+	t.Parallel()
+
+	block := newTestBlock(1)
+	hash := block.BlockHash()
+	fetcher := newTestFetcher(servePeer(t, wire.SFNodeNetwork, block))
+
+	errPruned := errors.New("-1: Block not available (pruned data)")
+	errOther := errors.New("connection refused")
+
+	fromBackend := func(err error) func(*chainhash.Hash) (*wire.MsgBlock,
+		error) {
+
+		return func(*chainhash.Hash) (*wire.MsgBlock, error) {
+			if err != nil {
+				return nil, err
+			}
+			return block, nil
+		}
+	}
+
+	// Blocks available from the backend are returned as is, as are errors
+	// other than the block being pruned.
+	fetched, err := fetcher.GetBlock(&hash, fromBackend(nil))
+	if err != nil || fetched != block {
+		t.Fatalf("expected block from backend, got %v", err)
+	}
+	if _, err := fetcher.GetBlock(&hash, fromBackend(errOther)); err !=
+		errOther {
+
+		t.Fatalf("expected %v, got %v", errOther, err)
+	}
+
+	// Pruned blocks are fetched from the peers instead.
+	fetched, err = fetcher.GetBlock(&hash, fromBackend(errPruned))
+	if err != nil {
+		t.Fatalf("unable to fetch pruned block: %v", err)
+	}
+	if fetched.BlockHash() != hash {
+		t.Fatalf("fetched wrong block")
+	}
+
+	// Without a fetcher, the error of the backend is returned.
+	var noFetcher *Fetcher
+	if _, err := noFetcher.GetBlock(&hash, fromBackend(errPruned)); err !=
+		errPruned {
+
+		t.Fatalf("expected %v, got %v", errPruned, err)
+	}
+}
@@ -14,11 +14,11 @@ import (
 	"github.com/litecoinfinance/btcd/txscript"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
 
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/htlcswitch"
 	"github.com/litecoinfinance/lnd/input"
 	"github.com/litecoinfinance/lnd/lnwallet"
@@ -897,6 +897,12 @@ func (bo *breachedOutput) CraftInputScript(signer input.Signer, txn *wire.MsgTx,
 // must be built on top of the confirmation height before the output can be
 // spent.
 func (bo *breachedOutput) BlocksToMaturity() uint32 {
+	// Our output on the commitment of a channel using anchor outputs can
+	// only be spent once the commitment has confirmed.
+	if bo.witnessType == input.CommitmentToRemoteConfirmed {
+		return 1
+	}
+
 	return 0
 }
 
@@ -945,11 +951,18 @@ func newRetributionInfo(chanPoint *wire.OutPoint,
 	// First, record the breach information for the local channel point if
 	// it is not considered dust, which is signaled by a non-nil sign
 	// descriptor. Here we use CommitmentNoDelay since this output belongs
-	// to us and has no time-based constraints on spending.
+	// to us and has no time-based constraints on spending, unless the
+	// channel uses anchor outputs, in which case it can only be spent once
+	// the commitment has confirmed.
 	if breachInfo.LocalOutputSignDesc != nil {
+		witnessType := input.CommitmentNoDelay
+		if breachInfo.LocalDelay != 0 {
+			witnessType = input.CommitmentToRemoteConfirmed
+		}
+
 		localOutput := makeBreachedOutput(
 			&breachInfo.LocalOutpoint,
-			input.CommitmentNoDelay,
+			witnessType,
 			// No second level script as this is a commitment
 			// output.
 			nil,
@@ -1051,6 +1064,9 @@ func (b *breachArbiter) createJusticeTx(
 		case input.CommitmentNoDelay:
 			witnessWeight = input.P2WKHWitnessSize
 
+		case input.CommitmentToRemoteConfirmed:
+			witnessWeight = input.ToRemoteConfirmedWitnessSize
+
 		case input.CommitmentRevoke:
 			witnessWeight = input.ToLocalPenaltyWitnessSize
 
@@ -1125,6 +1141,7 @@ func (b *breachArbiter) sweepSpendableOutputsTxn(txWeight int64,
 	for _, input := range inputs {
 		txn.AddTxIn(&wire.TxIn{
 			PreviousOutPoint: *input.OutPoint(),
+			Sequence:         input.BlocksToMaturity(),
 		})
 	}
 
@@ -1231,7 +1248,7 @@ func newRetributionStore(db *channeldb.DB) *retributionStore {
 // Add adds a retribution state to the retributionStore, which is then persisted
 // to disk.
 func (rs *retributionStore) Add(ret *retributionInfo) error {
-	return rs.db.Update(func(tx *bbolt.Tx) error {
+	return rs.db.Update(func(tx kvdb.Tx) error {
 		// If this is our first contract breach, the retributionBucket
 		// won't exist, in which case, we just create a new bucket.
 		retBucket, err := tx.CreateBucketIfNotExists(retributionBucket)
@@ -1258,7 +1275,7 @@ func (rs *retributionStore) Add(ret *retributionInfo) error {
 // startup and re-register for confirmation notifications.
 func (rs *retributionStore) Finalize(chanPoint *wire.OutPoint,
 	finalTx *wire.MsgTx) error {
-	return rs.db.Update(func(tx *bbolt.Tx) error {
+	return rs.db.Update(func(tx kvdb.Tx) error {
 		justiceBkt, err := tx.CreateBucketIfNotExists(justiceTxnBucket)
 		if err != nil {
 			return err
@@ -1285,7 +1302,7 @@ func (rs *retributionStore) GetFinalizedTxn(
 	chanPoint *wire.OutPoint) (*wire.MsgTx, error) {
 
 	var finalTxBytes []byte
-	if err := rs.db.View(func(tx *bbolt.Tx) error {
+	if err := rs.db.View(func(tx kvdb.Tx) error {
 		justiceBkt := tx.Bucket(justiceTxnBucket)
 		if justiceBkt == nil {
 			return nil
@@ -1319,7 +1336,7 @@ func (rs *retributionStore) GetFinalizedTxn(
 // that has already been breached.
 func (rs *retributionStore) IsBreached(chanPoint *wire.OutPoint) (bool, error) {
 	var found bool
-	err := rs.db.View(func(tx *bbolt.Tx) error {
+	err := rs.db.View(func(tx kvdb.Tx) error {
 		retBucket := tx.Bucket(retributionBucket)
 		if retBucket == nil {
 			return nil
@@ -1344,7 +1361,7 @@ func (rs *retributionStore) IsBreached(chanPoint *wire.OutPoint) (bool, error) {
 // Remove removes a retribution state and finalized justice transaction by
 // channel point  from the retribution store.
 func (rs *retributionStore) Remove(chanPoint *wire.OutPoint) error {
-	return rs.db.Update(func(tx *bbolt.Tx) error {
+	return rs.db.Update(func(tx kvdb.Tx) error {
 		retBucket := tx.Bucket(retributionBucket)
 
 		// We return an error if the bucket is not already created,
@@ -1383,7 +1400,7 @@ func (rs *retributionStore) Remove(chanPoint *wire.OutPoint) error {
 // ForAll iterates through all stored retributions and executes the passed
 // callback function on each retribution.
 func (rs *retributionStore) ForAll(cb func(*retributionInfo) error) error {
-	return rs.db.View(func(tx *bbolt.Tx) error {
+	return rs.db.View(func(tx kvdb.Tx) error {
 		// If the bucket does not exist, then there are no pending
 		// retributions.
 		retBucket := tx.Bucket(retributionBucket)
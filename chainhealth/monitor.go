@@ -0,0 +1,312 @@
+package chainhealth
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/ticker"
+)
+
+const (
+	// DefaultInterval is the default interval at which the chain backend
+	// is polled.
+	DefaultInterval = time.Minute
+
+	// DefaultMaxLag is the default number of blocks the chain backend may
+	// lag behind the tip of the network before it's considered out of
+	// sync.
+	DefaultMaxLag = 6
+
+	// DefaultMaxTipAge is the default age the best block of the chain
+	// backend may reach before the backend is considered out of sync.
+	DefaultMaxTipAge = 2 * time.Hour
+
+	// latencyWeight is the weight of the latest sample within the moving
+	// average of the backend's latency.
+	latencyWeight = 0.2
+)
+
+// ChainSource is the chain backend whose health is monitored.
+type ChainSource interface {
+	// GetBestBlock returns the hash and height of the best block known to
+	// the backend.
+	GetBestBlock() (*chainhash.Hash, int32, error)
+
+	// GetBlockHeader returns the header of the block with the given hash.
+	GetBlockHeader(blockHash *chainhash.Hash) (*wire.BlockHeader, error)
+}
+
+// Config houses the dependencies of the Monitor.
+type Config struct {
+	// Backend is the name of the chain backend, e.g. bitcoind.
+	Backend string
+
+	// Chain is the chain backend to monitor.
+	Chain ChainSource
+
+	// NetworkHeight returns the height of the best header the backend has
+	// learned of from its peers, which approximates the tip of the
+	// network. It may be nil if the backend doesn't expose it, in which
+	// case only the age of the best block is checked.
+	NetworkHeight func() (int32, error)
+
+	// MaxLag is the number of blocks the backend may lag behind the tip
+	// of the network before it's considered out of sync.
+	MaxLag uint32
+
+	// MaxTipAge is the age the best block of the backend may reach before
+	// the backend is considered out of sync.
+	MaxTipAge time.Duration
+
+	// Ticker is used to periodically poll the backend.
+	Ticker ticker.Ticker
+
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+// Status is a snapshot of the health of the chain backend.
+type Status struct {
+	// Backend is the name of the chain backend.
+	Backend string
+
+	// Reachable is true if the backend responded to the last poll.
+	Reachable bool
+
+	// Synced is true if the backend was reachable during the last poll,
+	// and neither lagged behind the network nor had a stale best block.
+	Synced bool
+
+	// BestHash is the hash of the best block of the backend.
+	BestHash chainhash.Hash
+
+	// BestHeight is the height of the best block of the backend.
+	BestHeight int32
+
+	// BestBlockTime is the timestamp of the best block of the backend.
+	BestBlockTime time.Time
+
+	// NetworkHeight is the height of the tip of the network as seen by
+	// the backend, or zero if unknown.
+	NetworkHeight int32
+
+	// Latency is the time the backend took to respond to the last poll.
+	Latency time.Duration
+
+	// AvgLatency is the moving average of the backend's latency.
+	AvgLatency time.Duration
+
+	// NumDisconnects is the number of times the backend became
+	// unreachable.
+	NumDisconnects uint32
+
+	// NumAlerts is the number of times the backend became unreachable or
+	// fell out of sync.
+	NumAlerts uint32
+
+	// LastPoll is the time the backend was last polled, or the zero time
+	// if it hasn't been polled yet.
+	LastPoll time.Time
+
+	// LastError is the error encountered by the last poll, if any.
+	LastError error
+}
+
+// Monitor periodically polls the chain backend, tracking how far its best
+// block lags behind the network and how quickly it responds. Whenever the
+// backend becomes unreachable or falls out of sync, an alert is logged, such
+// that a stalled backend is noticed before channels start failing.
+type Monitor struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *Config
+
+	// healthy is false while an alert about the backend is outstanding.
+	healthy bool
+
+	// mu guards status.
+	mu     sync.Mutex
+	status Status
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new chain health monitor backed by the given config.
+func New(cfg *Config) *Monitor {
+	return &Monitor{
+		cfg:     cfg,
+		healthy: true,
+		status: Status{
+			Backend: cfg.Backend,
+		},
+		quit: make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine responsible for polling the backend.
+func (m *Monitor) Start() error {
+	if !atomic.CompareAndSwapUint32(&m.started, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Chain health monitor starting for %v backend",
+		m.cfg.Backend)
+
+	m.cfg.Ticker.Resume()
+
+	m.wg.Add(1)
+	go m.pollHandler()
+
+	return nil
+}
+
+// Stop signals the monitor for a graceful shutdown.
+func (m *Monitor) Stop() error {
+	if !atomic.CompareAndSwapUint32(&m.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Info("Chain health monitor shutting down")
+
+	close(m.quit)
+	m.cfg.Ticker.Stop()
+	m.wg.Wait()
+
+	return nil
+}
+
+// Status returns a snapshot of the health of the backend as of the last
+// poll.
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.status
+}
+
+// pollHandler is the main event loop of the monitor, which polls the backend
+// each time the ticker fires.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Monitor) pollHandler() {
+	defer m.wg.Done()
+
+	m.poll()
+
+	for {
+		select {
+		case <-m.cfg.Ticker.Ticks():
+			m.poll()
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// poll queries the best block of the backend and the tip of the network,
+// updates the status accordingly, and logs an alert if the backend became
+// unhealthy.
+func (m *Monitor) poll() {
+	start := m.cfg.Now()
+
+	bestHash, bestHeight, err := m.cfg.Chain.GetBestBlock()
+	var header *wire.BlockHeader
+	if err == nil {
+		header, err = m.cfg.Chain.GetBlockHeader(bestHash)
+	}
+
+	now := m.cfg.Now()
+	latency := now.Sub(start)
+
+	var networkHeight int32
+	if err == nil && m.cfg.NetworkHeight != nil {
+		networkHeight, err = m.cfg.NetworkHeight()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := &m.status
+	wasReachable := status.Reachable
+	status.LastPoll = now
+	status.LastError = err
+
+	if err != nil {
+		status.Reachable = false
+		status.Synced = false
+
+		if wasReachable {
+			status.NumDisconnects++
+		}
+		m.setHealthy(false, "backend unreachable: %v", err)
+
+		return
+	}
+
+	status.Reachable = true
+	status.BestHash = *bestHash
+	status.BestHeight = bestHeight
+	status.BestBlockTime = header.Timestamp
+	status.NetworkHeight = networkHeight
+
+	status.Latency = latency
+	if status.AvgLatency == 0 {
+		status.AvgLatency = latency
+	} else {
+		status.AvgLatency += time.Duration(
+			latencyWeight * float64(latency-status.AvgLatency),
+		)
+	}
+
+	var lag uint32
+	if networkHeight > bestHeight {
+		lag = uint32(networkHeight - bestHeight)
+	}
+	tipAge := now.Sub(header.Timestamp)
+
+	switch {
+	case lag > m.cfg.MaxLag:
+		status.Synced = false
+		m.setHealthy(false, "backend at height %d is %d blocks behind "+
+			"the network at height %d", bestHeight, lag,
+			networkHeight)
+
+	case tipAge > m.cfg.MaxTipAge:
+		status.Synced = false
+		m.setHealthy(false, "best block %v of backend at height "+
+			"%d was mined %v ago", bestHash, bestHeight,
+			tipAge.Truncate(time.Second))
+
+	default:
+		status.Synced = true
+		m.setHealthy(true, "backend synced to height %d", bestHeight)
+	}
+}
+
+// setHealthy records whether the backend is healthy. An alert with the given
+// reason is logged if the backend became unhealthy, and a notice if it
+// recovered.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *Monitor) setHealthy(healthy bool, format string,
+	params ...interface{}) {
+
+	switch {
+	case m.healthy && !healthy:
+		m.status.NumAlerts++
+		log.Warnf("Chain backend %v unhealthy: "+format,
+			append([]interface{}{m.cfg.Backend}, params...)...)
+
+	case !m.healthy && healthy:
+		log.Infof("Chain backend %v recovered: "+format,
+			append([]interface{}{m.cfg.Backend}, params...)...)
+	}
+
+	m.healthy = healthy
+}
@@ -0,0 +1,159 @@
+package chainhealth
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/ticker"
+)
+
+// mockChain is a ChainSource whose best block and responsiveness are set by
+// the test.
+type mockChain struct {
+	sync.Mutex
+
+	height    int32
+	timestamp time.Time
+	err       error
+}
+
+func (c *mockChain) GetBestBlock() (*chainhash.Hash, int32, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.err != nil {
+		return nil, 0, c.err
+	}
+
+	return &chainhash.Hash{byte(c.height)}, c.height, nil
+}
+
+func (c *mockChain) GetBlockHeader(
+	hash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &wire.BlockHeader{Timestamp: c.timestamp}, nil
+}
+
+// TestMonitorPoll ensures that the Monitor tracks whether the backend is
+// reachable and synced, and alerts once each time the backend becomes
+// unhealthy.
+func TestMonitorPoll(t *testing.T) {
+	t.Parallel()
+
+	// Each reading of the clock advances it by a second, such that every
+	// poll takes a second.
+	now := time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	chain := &mockChain{
+		height:    100,
+		timestamp: now,
+	}
+	networkHeight := int32(100)
+
+	monitor := New(&Config{
+		Backend: "mock",
+		Chain:   chain,
+		NetworkHeight: func() (int32, error) {
+			return networkHeight, nil
+		},
+		MaxLag:    DefaultMaxLag,
+		MaxTipAge: DefaultMaxTipAge,
+		Ticker:    ticker.NewForce(DefaultInterval),
+		Now:       clock,
+	})
+
+	assertStatus := func(reachable, synced bool, disconnects,
+		alerts uint32) Status {
+
+		t.Helper()
+
+		status := monitor.Status()
+		if status.Reachable != reachable || status.Synced != synced {
+			t.Fatalf("expected reachable=%v, synced=%v, got "+
+				"reachable=%v, synced=%v", reachable, synced,
+				status.Reachable, status.Synced)
+		}
+		if status.NumDisconnects != disconnects {
+			t.Fatalf("expected %d disconnects, got %d",
+				disconnects, status.NumDisconnects)
+		}
+		if status.NumAlerts != alerts {
+			t.Fatalf("expected %d alerts, got %d", alerts,
+				status.NumAlerts)
+		}
+
+		return status
+	}
+
+	// Initially, the backend is at the tip of the network.
+	monitor.poll()
+	status := assertStatus(true, true, 0, 0)
+	if status.BestHeight != 100 || status.NetworkHeight != 100 {
+		t.Fatalf("unexpected heights: %d, %d", status.BestHeight,
+			status.NetworkHeight)
+	}
+	if status.Latency != time.Second || status.AvgLatency != time.Second {
+		t.Fatalf("unexpected latency: %v, %v", status.Latency,
+			status.AvgLatency)
+	}
+
+	// A lag within the tolerance doesn't affect the backend's health.
+	networkHeight = 100 + DefaultMaxLag
+	monitor.poll()
+	assertStatus(true, true, 0, 0)
+
+	// Once the lag exceeds the tolerance, the backend is considered out
+	// of sync, and an alert is raised only once.
+	networkHeight++
+	monitor.poll()
+	assertStatus(true, false, 0, 1)
+	monitor.poll()
+	assertStatus(true, false, 0, 1)
+
+	// Catching up restores the backend's health.
+	chain.Lock()
+	chain.height = networkHeight
+	chain.Unlock()
+	monitor.poll()
+	assertStatus(true, true, 0, 1)
+
+	// If the backend becomes unreachable, it's counted as a disconnect.
+	chain.Lock()
+	chain.err = errors.New("connection refused")
+	chain.Unlock()
+	monitor.poll()
+	status = assertStatus(false, false, 1, 2)
+	if status.LastError == nil {
+		t.Fatalf("expected last error to be set")
+	}
+	monitor.poll()
+	assertStatus(false, false, 1, 2)
+
+	chain.Lock()
+	chain.err = nil
+	chain.Unlock()
+	monitor.poll()
+	assertStatus(true, true, 1, 2)
+
+	// Finally, a best block that's too old also indicates a stalled
+	// backend, even if the tip of the network is unknown.
+	monitor.cfg.NetworkHeight = nil
+	now = now.Add(DefaultMaxTipAge)
+	monitor.poll()
+	assertStatus(true, false, 1, 3)
+}
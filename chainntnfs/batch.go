@@ -0,0 +1,171 @@
+package chainntnfs
+
+import (
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+)
+
+// ConfRegistration is a single request for confirmation notifications within
+// a batch. Its fields mirror the parameters of RegisterConfirmationsNtfn.
+type ConfRegistration struct {
+	// TxID is the transaction to be notified of the confirmation of. If
+	// nil, the notification is dispatched upon the confirmation of
+	// PkScript instead.
+	TxID *chainhash.Hash
+
+	// PkScript is an output script created by the transaction.
+	PkScript []byte
+
+	// NumConfs is the number of confirmations to wait for.
+	NumConfs uint32
+
+	// HeightHint is the earliest height at which the transaction could
+	// have been included in the chain.
+	HeightHint uint32
+}
+
+// BatchConfNotifier is a ChainNotifier that can register for the
+// confirmations of many transactions at once. Rather than rescanning the
+// chain for each of them in turn, all of those that may have confirmed
+// already are looked for within a single rescan pass.
+type BatchConfNotifier interface {
+	ChainNotifier
+
+	// RegisterConfirmationsBatch registers for the confirmations of each
+	// of the given requests, returning their events in the same order.
+	RegisterConfirmationsBatch(
+		regs []ConfRegistration) ([]*ConfirmationEvent, error)
+}
+
+// RegisterConfirmations registers for the confirmations of each of the given
+// requests, returning their events in the same order. If the notifier is a
+// BatchConfNotifier, the requests are registered as a single batch, otherwise
+// they're registered one by one.
+func RegisterConfirmations(notifier ChainNotifier,
+	regs []ConfRegistration) ([]*ConfirmationEvent, error) {
+
+	if batchNotifier, ok := notifier.(BatchConfNotifier); ok {
+		return batchNotifier.RegisterConfirmationsBatch(regs)
+	}
+
+	events := make([]*ConfirmationEvent, 0, len(regs))
+	for _, reg := range regs {
+		event, err := notifier.RegisterConfirmationsNtfn(
+			reg.TxID, reg.PkScript, reg.NumConfs, reg.HeightHint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// HistoricalConfBatch is a batch of historical rescans for the confirmations
+// of multiple requests, which are to be performed within a single pass over
+// the union of their height ranges.
+type HistoricalConfBatch struct {
+	// Dispatches are the historical rescans of the batch.
+	Dispatches []*HistoricalConfDispatch
+}
+
+// BlockScanner returns the block at the given height, which is to be scanned
+// for the confirmation of any of the given requests. If none of them can be
+// included in the block, e.g. as determined by its compact filter, a nil
+// block may be returned to skip it.
+type BlockScanner func(height uint32,
+	pending []ConfRequest) (*chainhash.Hash, *wire.MsgBlock, error)
+
+// ScanConfDetails looks up whether each of the requests of the given
+// dispatches has already been included in a block within its height range.
+// The blocks are scanned from the highest height of all dispatches downwards,
+// and each is fetched at most once, no matter how many of the dispatches
+// cover it. Scanning ends as soon as all requests have been found. The
+// confirmation details are returned in the order of the dispatches, with nil
+// for those that weren't found.
+func ScanConfDetails(dispatches []*HistoricalConfDispatch,
+	scanBlock BlockScanner,
+	quit <-chan struct{}) ([]*TxConfirmation, error) {
+
+	confs := make([]*TxConfirmation, len(dispatches))
+	if len(dispatches) == 0 {
+		return confs, nil
+	}
+
+	startHeight := dispatches[0].StartHeight
+	endHeight := dispatches[0].EndHeight
+	for _, dispatch := range dispatches[1:] {
+		if dispatch.StartHeight < startHeight {
+			startHeight = dispatch.StartHeight
+		}
+		if dispatch.EndHeight > endHeight {
+			endHeight = dispatch.EndHeight
+		}
+	}
+
+	numPending := len(dispatches)
+	for height := endHeight; height >= startHeight && height > 0; height-- {
+		if numPending == 0 {
+			break
+		}
+
+		// Ensure we haven't been requested to shut down before
+		// processing the next height.
+		select {
+		case <-quit:
+			return nil, ErrChainNotifierShuttingDown
+		default:
+		}
+
+		// Gather the requests still pending whose range covers this
+		// height. If there aren't any, we can skip the block entirely.
+		var pending []ConfRequest
+		for i, dispatch := range dispatches {
+			if confs[i] != nil || !dispatch.covers(height) {
+				continue
+			}
+			pending = append(pending, dispatch.ConfRequest)
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		blockHash, block, err := scanBlock(height, pending)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			continue
+		}
+
+		// As blocks are scanned from the tip downwards, the first match
+		// of each request is its latest inclusion within the chain.
+		for txIndex, tx := range block.Transactions {
+			for i, dispatch := range dispatches {
+				matches := confs[i] == nil &&
+					dispatch.covers(height) &&
+					dispatch.ConfRequest.MatchesTx(tx)
+				if !matches {
+					continue
+				}
+
+				confs[i] = &TxConfirmation{
+					Tx:          tx,
+					BlockHash:   blockHash,
+					BlockHeight: height,
+					TxIndex:     uint32(txIndex),
+				}
+				numPending--
+			}
+		}
+	}
+
+	return confs, nil
+}
+
+// covers returns whether the given height lies within the range of blocks to
+// be scanned by the dispatch.
+func (d *HistoricalConfDispatch) covers(height uint32) bool {
+	return height >= d.StartHeight && height <= d.EndHeight
+}
@@ -0,0 +1,141 @@
+package chainntnfs_test
+
+import (
+	"testing"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/chainntnfs"
+)
+
+// TestScanConfDetails ensures that a batch of historical dispatches is
+// resolved within a single scan of the chain, in which each block is fetched
+// at most once, and each request only matches blocks within its own range.
+func TestScanConfDetails(t *testing.T) {
+	t.Parallel()
+
+	const numBlocks = 10
+
+	// We'll create a chain in which each block contains a transaction
+	// paying to the test script, such that each block is distinct.
+	blocks := make(map[uint32]*wire.MsgBlock)
+	for height := uint32(1); height <= numBlocks; height++ {
+		tx := wire.NewMsgTx(1)
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Index: height},
+		})
+		tx.AddTxOut(&wire.TxOut{PkScript: testRawScript})
+
+		blocks[height] = &wire.MsgBlock{
+			Header: wire.BlockHeader{
+				Nonce: height,
+			},
+			Transactions: []*wire.MsgTx{tx},
+		}
+	}
+
+	txidAt := func(height uint32) *chainhash.Hash {
+		txid := blocks[height].Transactions[0].TxHash()
+		return &txid
+	}
+	newDispatch := func(txid *chainhash.Hash, startHeight,
+		endHeight uint32) *chainntnfs.HistoricalConfDispatch {
+
+		confRequest, err := chainntnfs.NewConfRequest(
+			txid, testRawScript,
+		)
+		if err != nil {
+			t.Fatalf("unable to create conf request: %v", err)
+		}
+
+		return &chainntnfs.HistoricalConfDispatch{
+			ConfRequest: confRequest,
+			StartHeight: startHeight,
+			EndHeight:   endHeight,
+		}
+	}
+
+	fetches := make(map[uint32]int)
+	scanBlock := func(height uint32,
+		_ []chainntnfs.ConfRequest) (*chainhash.Hash, *wire.MsgBlock,
+		error) {
+
+		fetches[height]++
+
+		blockHash := blocks[height].BlockHash()
+		return &blockHash, blocks[height], nil
+	}
+
+	dispatches := []*chainntnfs.HistoricalConfDispatch{
+		// The transaction confirmed at height 3 is found within its
+		// range.
+		newDispatch(txidAt(3), 1, 5),
+
+		// The transaction confirmed at height 8 lies outside of the
+		// range of this dispatch.
+		newDispatch(txidAt(8), 1, 5),
+
+		// The script is matched by its latest inclusion within the
+		// range.
+		newDispatch(nil, 2, 7),
+	}
+	confs, err := chainntnfs.ScanConfDetails(dispatches, scanBlock, nil)
+	if err != nil {
+		t.Fatalf("unable to scan chain: %v", err)
+	}
+
+	expectedHeights := []uint32{3, 0, 7}
+	for i, conf := range confs {
+		switch {
+		case expectedHeights[i] == 0 && conf != nil:
+			t.Fatalf("expected request %d not to be found, found "+
+				"at height %d", i, conf.BlockHeight)
+
+		case expectedHeights[i] == 0:
+
+		case conf == nil:
+			t.Fatalf("expected request %d to be found", i)
+
+		case conf.BlockHeight != expectedHeights[i]:
+			t.Fatalf("expected request %d at height %d, found at "+
+				"height %d", i, expectedHeights[i],
+				conf.BlockHeight)
+		}
+	}
+
+	// Only the blocks within the range of any dispatch should have been
+	// fetched, each of them only once.
+	for height := uint32(1); height <= numBlocks; height++ {
+		expectedFetches := 1
+		if height > 7 {
+			expectedFetches = 0
+		}
+		if fetches[height] != expectedFetches {
+			t.Fatalf("expected block at height %d to be fetched "+
+				"%d times, got %d", height, expectedFetches,
+				fetches[height])
+		}
+	}
+
+	// Once all requests have been found, the scan should end without
+	// fetching any more blocks.
+	fetches = make(map[uint32]int)
+	dispatches = []*chainntnfs.HistoricalConfDispatch{
+		newDispatch(txidAt(9), 1, numBlocks),
+		newDispatch(nil, 1, numBlocks),
+	}
+	confs, err = chainntnfs.ScanConfDetails(dispatches, scanBlock, nil)
+	if err != nil {
+		t.Fatalf("unable to scan chain: %v", err)
+	}
+	if confs[0] == nil || confs[0].BlockHeight != 9 {
+		t.Fatalf("expected transaction to be found at height 9")
+	}
+	if confs[1] == nil || confs[1].BlockHeight != numBlocks {
+		t.Fatalf("expected script to be found at height %d", numBlocks)
+	}
+	if len(fetches) != 2 {
+		t.Fatalf("expected 2 blocks to be fetched, got %d",
+			len(fetches))
+	}
+}
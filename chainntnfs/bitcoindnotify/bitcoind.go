@@ -15,6 +15,7 @@ import (
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
 	"github.com/litecoinfinance/btcwallet/chain"
+	"github.com/litecoinfinance/lnd/blockfetch"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/queue"
 )
@@ -33,6 +34,59 @@ type chainUpdate struct {
 	blockHeight int32
 }
 
+// ChainClient is the connection to bitcoind the BitcoindNotifier queries the
+// chain through, and receives block notifications from. It's implemented by
+// the ZMQ backed chain.BitcoindClient, as well as the PollingClient for nodes
+// that don't expose ZMQ.
+type ChainClient interface {
+	chainntnfs.ChainConn
+
+	// Start connects the client to bitcoind.
+	Start() error
+
+	// Stop disconnects the client from bitcoind.
+	Stop()
+
+	// NotifyBlocks requests notifications of connected and disconnected
+	// blocks to be delivered over the Notifications channel.
+	NotifyBlocks() error
+
+	// NotifyReceived requests notifications of transactions paying to
+	// any of the given addresses.
+	NotifyReceived(addrs []btcutil.Address) error
+
+	// NotifySpent requests notifications of transactions spending any of
+	// the given outpoints.
+	NotifySpent(outPoints []*wire.OutPoint) error
+
+	// Notifications returns the channel notifications are delivered
+	// over.
+	Notifications() <-chan interface{}
+
+	// GetBestBlock returns the hash and height of the best block.
+	GetBestBlock() (*chainhash.Hash, int32, error)
+
+	// GetBlock returns the block with the given hash.
+	GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error)
+
+	// GetBlockVerbose returns the verbose block with the given hash.
+	GetBlockVerbose(blockHash *chainhash.Hash) (
+		*btcjson.GetBlockVerboseResult, error)
+
+	// GetBlockHeight returns the height of the block with the given hash.
+	GetBlockHeight(blockHash *chainhash.Hash) (int32, error)
+
+	// GetRawTransactionVerbose returns the verbose transaction with the
+	// given hash.
+	GetRawTransactionVerbose(
+		txHash *chainhash.Hash) (*btcjson.TxRawResult, error)
+
+	// GetTxOut returns the unspent output with the given outpoint, or nil
+	// if it has been spent.
+	GetTxOut(txHash *chainhash.Hash, index uint32,
+		mempool bool) (*btcjson.GetTxOutResult, error)
+}
+
 // TODO(roasbeef): generalize struct below:
 //  * move chans to config
 //  * extract common code
@@ -49,9 +103,13 @@ type BitcoindNotifier struct {
 	started int32 // To be used atomically.
 	stopped int32 // To be used atomically.
 
-	chainConn   *chain.BitcoindClient
+	chainConn   ChainClient
 	chainParams *chaincfg.Params
 
+	// blockFetcher fetches the historical blocks bitcoind has pruned from
+	// its peers. It's nil if bitcoind doesn't prune blocks.
+	blockFetcher *blockfetch.Fetcher
+
 	notificationCancels  chan interface{}
 	notificationRegistry chan interface{}
 
@@ -79,15 +137,39 @@ type BitcoindNotifier struct {
 // time.
 var _ chainntnfs.ChainNotifier = (*BitcoindNotifier)(nil)
 
+// Ensure BitcoindNotifier implements the BatchConfNotifier interface at
+// compile time.
+var _ chainntnfs.BatchConfNotifier = (*BitcoindNotifier)(nil)
+
 // New returns a new BitcoindNotifier instance. This function assumes the
 // bitcoind node detailed in the passed configuration is already running, and
-// willing to accept RPC requests and new zmq clients.
+// willing to accept RPC requests and new zmq clients. If bitcoind prunes
+// blocks, the historical blocks scanned for confirmations and spends are
+// fetched through the given block fetcher once pruned. Otherwise, it may be
+// nil.
 func New(chainConn *chain.BitcoindConn, chainParams *chaincfg.Params,
 	spendHintCache chainntnfs.SpendHintCache,
-	confirmHintCache chainntnfs.ConfirmHintCache) *BitcoindNotifier {
+	confirmHintCache chainntnfs.ConfirmHintCache,
+	blockFetcher *blockfetch.Fetcher) *BitcoindNotifier {
+
+	return NewWithClient(
+		chainConn.NewBitcoindClient(), chainParams, spendHintCache,
+		confirmHintCache, blockFetcher,
+	)
+}
+
+// NewWithClient returns a new BitcoindNotifier instance that receives block
+// notifications from, and queries the chain through, the given client rather
+// than a ZMQ backed client of a chain.BitcoindConn.
+func NewWithClient(chainConn ChainClient, chainParams *chaincfg.Params,
+	spendHintCache chainntnfs.SpendHintCache,
+	confirmHintCache chainntnfs.ConfirmHintCache,
+	blockFetcher *blockfetch.Fetcher) *BitcoindNotifier {
 
-	notifier := &BitcoindNotifier{
-		chainParams: chainParams,
+	return &BitcoindNotifier{
+		chainConn:    chainConn,
+		chainParams:  chainParams,
+		blockFetcher: blockFetcher,
 
 		notificationCancels:  make(chan interface{}),
 		notificationRegistry: make(chan interface{}),
@@ -99,14 +181,10 @@ func New(chainConn *chain.BitcoindConn, chainParams *chaincfg.Params,
 
 		quit: make(chan struct{}),
 	}
-
-	notifier.chainConn = chainConn.NewBitcoindClient()
-
-	return notifier
 }
 
-// Start connects to the running bitcoind node over websockets, registers for
-// block notifications, and finally launches all related helper goroutines.
+// Start connects to the running bitcoind node, registers for block
+// notifications, and finally launches all related helper goroutines.
 func (b *BitcoindNotifier) Start() error {
 	// Already started?
 	if atomic.AddInt32(&b.started, 1) != 1 {
@@ -256,6 +334,17 @@ out:
 					}
 				}()
 
+			case *chainntnfs.HistoricalConfBatch:
+				// The rescans of a batch are performed within
+				// a single pass over the chain, again in a
+				// goroutine as they may take a while.
+				b.wg.Add(1)
+				go func() {
+					defer b.wg.Done()
+
+					b.historicalConfBatch(msg.Dispatches)
+				}()
+
 			case *chainntnfs.HistoricalSpendDispatch:
 				// In order to ensure we don't block the caller
 				// on what may be a long rescan, we'll launch a
@@ -586,52 +675,104 @@ func (b *BitcoindNotifier) confDetailsManually(confRequest chainntnfs.ConfReques
 	heightHint, currentHeight uint32) (*chainntnfs.TxConfirmation,
 	chainntnfs.TxConfStatus, error) {
 
-	// Begin scanning blocks at every height to determine where the
-	// transaction was included in.
-	for height := currentHeight; height >= heightHint && height > 0; height-- {
-		// Ensure we haven't been requested to shut down before
-		// processing the next height.
-		select {
-		case <-b.quit:
-			return nil, chainntnfs.TxNotFoundManually,
-				chainntnfs.ErrChainNotifierShuttingDown
-		default:
-		}
+	dispatch := &chainntnfs.HistoricalConfDispatch{
+		ConfRequest: confRequest,
+		StartHeight: heightHint,
+		EndHeight:   currentHeight,
+	}
+	confs, err := chainntnfs.ScanConfDetails(
+		[]*chainntnfs.HistoricalConfDispatch{dispatch}, b.scanBlock,
+		b.quit,
+	)
+	if err != nil {
+		return nil, chainntnfs.TxNotFoundManually, err
+	}
 
-		blockHash, err := b.chainConn.GetBlockHash(int64(height))
-		if err != nil {
-			return nil, chainntnfs.TxNotFoundManually,
-				fmt.Errorf("unable to get hash from block "+
-					"with height %d", height)
+	// If we were not able to find the transaction within a block, we
+	// avoid returning an error.
+	if confs[0] == nil {
+		return nil, chainntnfs.TxNotFoundManually, nil
+	}
+
+	return confs[0], chainntnfs.TxFoundManually, nil
+}
+
+// historicalConfBatch determines whether any of the requests of a batch of
+// historical dispatches have already been included in the active chain, and
+// updates their confirmation details accordingly. Transactions are looked up
+// within the backend's txindex first, while all remaining requests are looked
+// for within a single scan of the chain.
+func (b *BitcoindNotifier) historicalConfBatch(
+	dispatches []*chainntnfs.HistoricalConfDispatch) {
+
+	var manualDispatches []*chainntnfs.HistoricalConfDispatch
+	for _, dispatch := range dispatches {
+		if dispatch.TxID == chainntnfs.ZeroHash {
+			manualDispatches = append(manualDispatches, dispatch)
+			continue
 		}
 
-		block, err := b.chainConn.GetBlock(blockHash)
+		confDetails, _, err := b.confDetailsFromTxIndex(&dispatch.TxID)
 		if err != nil {
-			return nil, chainntnfs.TxNotFoundManually,
-				fmt.Errorf("unable to get block with hash "+
-					"%v: %v", blockHash, err)
+			chainntnfs.Log.Debugf("Failed getting conf details "+
+				"from index (%v), scanning manually", err)
+			manualDispatches = append(manualDispatches, dispatch)
+			continue
 		}
 
-		// For every transaction in the block, check which one matches
-		// our request. If we find one that does, we can dispatch its
-		// confirmation details.
-		for txIndex, tx := range block.Transactions {
-			if !confRequest.MatchesTx(tx) {
-				continue
-			}
+		b.updateConfDetails(dispatch.ConfRequest, confDetails)
+	}
 
-			return &chainntnfs.TxConfirmation{
-				Tx:          tx,
-				BlockHash:   blockHash,
-				BlockHeight: height,
-				TxIndex:     uint32(txIndex),
-			}, chainntnfs.TxFoundManually, nil
-		}
+	if len(manualDispatches) == 0 {
+		return
 	}
 
-	// If we reach here, then we were not able to find the transaction
-	// within a block, so we avoid returning an error.
-	return nil, chainntnfs.TxNotFoundManually, nil
+	confs, err := chainntnfs.ScanConfDetails(
+		manualDispatches, b.scanBlock, b.quit,
+	)
+	if err != nil {
+		chainntnfs.Log.Errorf("Rescan to determine the conf details "+
+			"of %d requests failed: %v", len(manualDispatches), err)
+		return
+	}
+
+	for i, dispatch := range manualDispatches {
+		b.updateConfDetails(dispatch.ConfRequest, confs[i])
+	}
+}
+
+// updateConfDetails hands the confirmation details found by a historical
+// rescan to the TxNotifier. It's invoked even if none were found, which allows
+// the notifier to begin safely updating the height hint cache at tip, since
+// the pending rescan has now completed.
+func (b *BitcoindNotifier) updateConfDetails(confRequest chainntnfs.ConfRequest,
+	confDetails *chainntnfs.TxConfirmation) {
+
+	err := b.txNotifier.UpdateConfDetails(confRequest, confDetails)
+	if err != nil {
+		chainntnfs.Log.Errorf("Unable to update conf details of %v: %v",
+			confRequest, err)
+	}
+}
+
+// scanBlock fetches the block at the given height, such that it can be
+// scanned for the confirmations of historical dispatches.
+func (b *BitcoindNotifier) scanBlock(height uint32,
+	_ []chainntnfs.ConfRequest) (*chainhash.Hash, *wire.MsgBlock, error) {
+
+	blockHash, err := b.chainConn.GetBlockHash(int64(height))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get hash from block "+
+			"with height %d", height)
+	}
+
+	block, err := b.blockFetcher.GetBlock(blockHash, b.chainConn.GetBlock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get block with hash "+
+			"%v: %v", blockHash, err)
+	}
+
+	return blockHash, block, nil
 }
 
 // handleBlockConnected applies a chain update for a new block. Any watched
@@ -871,7 +1012,9 @@ func (b *BitcoindNotifier) historicalSpendDetails(
 			return nil, fmt.Errorf("unable to retrieve hash for "+
 				"block with height %d: %v", height, err)
 		}
-		block, err := b.chainConn.GetBlock(blockHash)
+		block, err := b.blockFetcher.GetBlock(
+			blockHash, b.chainConn.GetBlock,
+		)
 		if err != nil {
 			return nil, fmt.Errorf("unable to retrieve block "+
 				"with hash %v: %v", blockHash, err)
@@ -916,12 +1059,88 @@ func (b *BitcoindNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	pkScript []byte,
 	numConfs, heightHint uint32) (*chainntnfs.ConfirmationEvent, error) {
 
-	// Construct a notification request for the transaction and send it to
-	// the main event loop.
+	ntfn, dispatch, err := b.registerConf(
+		txid, pkScript, numConfs, heightHint,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispatch == nil {
+		return ntfn.Event, nil
+	}
+
+	select {
+	case b.notificationRegistry <- dispatch:
+		return ntfn.Event, nil
+	case <-b.quit:
+		return nil, chainntnfs.ErrChainNotifierShuttingDown
+	}
+}
+
+// RegisterConfirmationsBatch registers for the confirmations of each of the
+// given requests, returning their events in the same order. The historical
+// rescans of all requests that may have confirmed already are performed within
+// a single pass over the chain.
+//
+// NOTE: This is part of the chainntnfs.BatchConfNotifier interface.
+func (b *BitcoindNotifier) RegisterConfirmationsBatch(
+	regs []chainntnfs.ConfRegistration) ([]*chainntnfs.ConfirmationEvent,
+	error) {
+
+	var (
+		events      = make([]*chainntnfs.ConfirmationEvent, 0, len(regs))
+		batch       = &chainntnfs.HistoricalConfBatch{}
+		registerErr error
+	)
+	for _, reg := range regs {
+		ntfn, dispatch, err := b.registerConf(
+			reg.TxID, reg.PkScript, reg.NumConfs, reg.HeightHint,
+		)
+		if err != nil {
+			registerErr = err
+			break
+		}
+
+		events = append(events, ntfn.Event)
+		if dispatch != nil {
+			batch.Dispatches = append(batch.Dispatches, dispatch)
+		}
+	}
+
+	// The rescans of the requests registered are dispatched even if a
+	// later one failed to register, as the TxNotifier awaits their
+	// completion.
+	if len(batch.Dispatches) > 0 {
+		select {
+		case b.notificationRegistry <- batch:
+		case <-b.quit:
+			return nil, chainntnfs.ErrChainNotifierShuttingDown
+		}
+	}
+
+	if registerErr != nil {
+		for _, event := range events {
+			event.Cancel()
+		}
+		return nil, registerErr
+	}
+
+	return events, nil
+}
+
+// registerConf registers a confirmation request with the TxNotifier. A non-nil
+// historical dispatch is returned if the caller is required to perform a
+// manual scan for the confirmation.
+func (b *BitcoindNotifier) registerConf(txid *chainhash.Hash, pkScript []byte,
+	numConfs, heightHint uint32) (*chainntnfs.ConfNtfn,
+	*chainntnfs.HistoricalConfDispatch, error) {
+
+	// Construct a notification request for the transaction.
 	confID := atomic.AddUint64(&b.confClientCounter, 1)
 	confRequest, err := chainntnfs.NewConfRequest(txid, pkScript)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	ntfn := &chainntnfs.ConfNtfn{
 		ConfID:           confID,
@@ -942,19 +1161,10 @@ func (b *BitcoindNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	// watching at tip for the transaction to confirm.
 	dispatch, _, err := b.txNotifier.RegisterConf(ntfn)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if dispatch == nil {
-		return ntfn.Event, nil
-	}
-
-	select {
-	case b.notificationRegistry <- dispatch:
-		return ntfn.Event, nil
-	case <-b.quit:
-		return nil, chainntnfs.ErrChainNotifierShuttingDown
-	}
+	return ntfn, dispatch, nil
 }
 
 // blockEpochRegistration represents a client's intent to receive a
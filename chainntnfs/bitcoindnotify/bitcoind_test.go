@@ -58,7 +58,7 @@ func setUpNotifier(t *testing.T, bitcoindConn *chain.BitcoindConn,
 
 	notifier := New(
 		bitcoindConn, chainntnfs.NetParams, spendHintCache,
-		confirmHintCache,
+		confirmHintCache, nil,
 	)
 	if err := notifier.Start(); err != nil {
 		t.Fatalf("unable to start notifier: %v", err)
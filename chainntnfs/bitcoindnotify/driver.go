@@ -6,21 +6,28 @@ import (
 
 	"github.com/litecoinfinance/btcd/chaincfg"
 	"github.com/litecoinfinance/btcwallet/chain"
+	"github.com/litecoinfinance/lnd/blockfetch"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 )
 
 // createNewNotifier creates a new instance of the ChainNotifier interface
 // implemented by BitcoindNotifier.
 func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
-	if len(args) != 4 {
+	if len(args) != 5 {
 		return nil, fmt.Errorf("incorrect number of arguments to "+
-			".New(...), expected 4, instead passed %v", len(args))
+			".New(...), expected 5, instead passed %v", len(args))
 	}
 
-	chainConn, ok := args[0].(*chain.BitcoindConn)
-	if !ok {
+	var chainClient ChainClient
+	switch chainConn := args[0].(type) {
+	case *chain.BitcoindConn:
+		chainClient = chainConn.NewBitcoindClient()
+	case ChainClient:
+		chainClient = chainConn
+	default:
 		return nil, errors.New("first argument to bitcoindnotify.New " +
-			"is incorrect, expected a *chain.BitcoindConn")
+			"is incorrect, expected a *chain.BitcoindConn or " +
+			"ChainClient")
 	}
 
 	chainParams, ok := args[1].(*chaincfg.Params)
@@ -41,7 +48,16 @@ func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
 			"is incorrect, expected a chainntnfs.ConfirmHintCache")
 	}
 
-	return New(chainConn, chainParams, spendHintCache, confirmHintCache), nil
+	blockFetcher, ok := args[4].(*blockfetch.Fetcher)
+	if !ok {
+		return nil, errors.New("fifth argument to bitcoindnotify.New " +
+			"is incorrect, expected a *blockfetch.Fetcher")
+	}
+
+	return NewWithClient(
+		chainClient, chainParams, spendHintCache, confirmHintCache,
+		blockFetcher,
+	), nil
 }
 
 // init registers a driver for the BtcdNotifier concrete implementation of the
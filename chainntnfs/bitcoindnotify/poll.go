@@ -0,0 +1,250 @@
+package bitcoindnotify
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/rpcclient"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/btcwallet/chain"
+	"github.com/litecoinfinance/btcwallet/wtxmgr"
+	"github.com/litecoinfinance/lnd/chainntnfs"
+)
+
+const (
+	// DefaultMinPollInterval is the default interval at which bitcoind is
+	// polled right after a new block was found.
+	DefaultMinPollInterval = 2 * time.Second
+
+	// DefaultMaxPollInterval is the default interval polling backs off to
+	// while no new blocks are found.
+	DefaultMaxPollInterval = 30 * time.Second
+)
+
+// PollingClient is a ChainClient that learns of new blocks by polling bitcoind
+// over RPC, for nodes that can't expose ZMQ. The polling interval adapts to
+// the chain: it's reset to its minimum whenever a new block is found, so that
+// blocks arriving in quick succession are picked up swiftly, and doubles up to
+// its maximum after each poll that finds none.
+//
+// Unlike the ZMQ backed client, mempool transactions aren't watched, as the
+// BitcoindNotifier only dispatches spends once confirmed, and scans every
+// connected block for them itself.
+type PollingClient struct {
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	*rpcclient.Client
+
+	minInterval time.Duration
+	maxInterval time.Duration
+
+	// bestBlock is the best block as of the last poll.
+	bestBlock chainntnfs.BlockEpoch
+
+	notifications chan interface{}
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// Compile time check to ensure PollingClient satisfies the ChainClient
+// interface.
+var _ ChainClient = (*PollingClient)(nil)
+
+// NewPollingClient creates a new PollingClient that polls bitcoind through
+// the given RPC client, which should be in HTTP POST mode. The polling
+// interval ranges from minInterval to maxInterval.
+func NewPollingClient(client *rpcclient.Client, minInterval,
+	maxInterval time.Duration) *PollingClient {
+
+	return &PollingClient{
+		Client:        client,
+		minInterval:   minInterval,
+		maxInterval:   maxInterval,
+		notifications: make(chan interface{}),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start queries the current best block, and launches the goroutine polling
+// bitcoind for new ones.
+func (c *PollingClient) Start() error {
+	if atomic.AddInt32(&c.started, 1) != 1 {
+		return nil
+	}
+
+	hash, height, err := c.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	c.bestBlock = chainntnfs.BlockEpoch{
+		Hash:   hash,
+		Height: height,
+	}
+
+	c.wg.Add(1)
+	go c.pollHandler()
+
+	return nil
+}
+
+// Stop stops polling bitcoind, and shuts down the RPC client.
+func (c *PollingClient) Stop() {
+	if atomic.AddInt32(&c.stopped, 1) != 1 {
+		return
+	}
+
+	close(c.quit)
+	c.wg.Wait()
+
+	c.Client.Shutdown()
+}
+
+// NotifyBlocks requests notifications of connected and disconnected blocks.
+// As they're always delivered, this is a no-op.
+func (c *PollingClient) NotifyBlocks() error {
+	return nil
+}
+
+// NotifyReceived is a no-op, as mempool transactions aren't watched.
+func (c *PollingClient) NotifyReceived(_ []btcutil.Address) error {
+	return nil
+}
+
+// NotifySpent is a no-op, as mempool transactions aren't watched.
+func (c *PollingClient) NotifySpent(_ []*wire.OutPoint) error {
+	return nil
+}
+
+// Notifications returns the channel block notifications are delivered over.
+func (c *PollingClient) Notifications() <-chan interface{} {
+	return c.notifications
+}
+
+// GetBestBlock returns the hash and height of the best block of bitcoind.
+func (c *PollingClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	hash, err := c.Client.GetBestBlockHash()
+	if err != nil {
+		return nil, 0, err
+	}
+	height, err := c.GetBlockHeight(hash)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return hash, height, nil
+}
+
+// GetBlockHeight returns the height of the block with the given hash.
+func (c *PollingClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	header, err := c.Client.GetBlockHeaderVerbose(hash)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Height, nil
+}
+
+// pollHandler polls bitcoind for new blocks at an adaptive interval until the
+// client is stopped.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *PollingClient) pollHandler() {
+	defer c.wg.Done()
+
+	interval := c.minInterval
+	for {
+		select {
+		case <-time.After(interval):
+		case <-c.quit:
+			return
+		}
+
+		found, err := c.poll()
+		switch {
+		case err != nil:
+			chainntnfs.Log.Errorf("Unable to poll bitcoind for "+
+				"new blocks: %v", err)
+			fallthrough
+
+		case !found:
+			interval *= 2
+			if interval > c.maxInterval {
+				interval = c.maxInterval
+			}
+
+		default:
+			interval = c.minInterval
+		}
+	}
+}
+
+// poll queries the best block of bitcoind, and delivers a notification for
+// each block connected since the last poll. It returns whether any new blocks
+// were found.
+func (c *PollingClient) poll() (bool, error) {
+	hash, height, err := c.GetBestBlock()
+	if err != nil {
+		return false, err
+	}
+	if *hash == *c.bestBlock.Hash {
+		return false, nil
+	}
+
+	// If the chain was extended, we'll deliver each of the blocks
+	// connected to our best block in order. Otherwise, the chain was
+	// reorganized, and we'll only deliver the new best block, leaving it
+	// to the notifier to rewind to the common ancestor and catch up.
+	startHeight := height
+	if height > c.bestBlock.Height {
+		bestHeight := int64(c.bestBlock.Height)
+		hashAtBest, err := c.Client.GetBlockHash(bestHeight)
+		if err != nil {
+			return false, err
+		}
+		if *hashAtBest == *c.bestBlock.Hash {
+			startHeight = c.bestBlock.Height + 1
+		}
+	}
+
+	for blockHeight := startHeight; blockHeight <= height; blockHeight++ {
+		blockHash := hash
+		if blockHeight != height {
+			blockHash, err = c.Client.GetBlockHash(
+				int64(blockHeight),
+			)
+			if err != nil {
+				return false, err
+			}
+		}
+		header, err := c.Client.GetBlockHeaderVerbose(blockHash)
+		if err != nil {
+			return false, err
+		}
+
+		blockConnected := chain.BlockConnected(wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{
+				Hash:   *blockHash,
+				Height: blockHeight,
+			},
+			Time: time.Unix(header.Time, 0),
+		})
+
+		select {
+		case c.notifications <- blockConnected:
+		case <-c.quit:
+			return false, nil
+		}
+
+		c.bestBlock = chainntnfs.BlockEpoch{
+			Hash:   blockHash,
+			Height: blockHeight,
+		}
+	}
+
+	return true, nil
+}
@@ -92,6 +92,10 @@ type BtcdNotifier struct {
 // Ensure BtcdNotifier implements the ChainNotifier interface at compile time.
 var _ chainntnfs.ChainNotifier = (*BtcdNotifier)(nil)
 
+// Ensure BtcdNotifier implements the BatchConfNotifier interface at compile
+// time.
+var _ chainntnfs.BatchConfNotifier = (*BtcdNotifier)(nil)
+
 // New returns a new BtcdNotifier instance. This function assumes the btcd node
 // detailed in the passed configuration is already running, and willing to
 // accept new websockets clients.
@@ -323,6 +327,17 @@ out:
 					}
 				}()
 
+			case *chainntnfs.HistoricalConfBatch:
+				// The rescans of a batch are performed within
+				// a single pass over the chain, again in a
+				// goroutine as they may take a while.
+				b.wg.Add(1)
+				go func() {
+					defer b.wg.Done()
+
+					b.historicalConfBatch(msg.Dispatches)
+				}()
+
 			case *blockEpochRegistration:
 				chainntnfs.Log.Infof("New block epoch subscription")
 
@@ -612,53 +627,105 @@ func (b *BtcdNotifier) confDetailsManually(confRequest chainntnfs.ConfRequest,
 	startHeight, endHeight uint32) (*chainntnfs.TxConfirmation,
 	chainntnfs.TxConfStatus, error) {
 
-	// Begin scanning blocks at every height to determine where the
-	// transaction was included in.
-	for height := endHeight; height >= startHeight && height > 0; height-- {
-		// Ensure we haven't been requested to shut down before
-		// processing the next height.
-		select {
-		case <-b.quit:
-			return nil, chainntnfs.TxNotFoundManually,
-				chainntnfs.ErrChainNotifierShuttingDown
-		default:
-		}
+	dispatch := &chainntnfs.HistoricalConfDispatch{
+		ConfRequest: confRequest,
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	}
+	confs, err := chainntnfs.ScanConfDetails(
+		[]*chainntnfs.HistoricalConfDispatch{dispatch}, b.scanBlock,
+		b.quit,
+	)
+	if err != nil {
+		return nil, chainntnfs.TxNotFoundManually, err
+	}
 
-		blockHash, err := b.chainConn.GetBlockHash(int64(height))
-		if err != nil {
-			return nil, chainntnfs.TxNotFoundManually,
-				fmt.Errorf("unable to get hash from block "+
-					"with height %d", height)
+	// If we were not able to find the transaction within a block, we
+	// avoid returning an error.
+	if confs[0] == nil {
+		return nil, chainntnfs.TxNotFoundManually, nil
+	}
+
+	return confs[0], chainntnfs.TxFoundManually, nil
+}
+
+// historicalConfBatch determines whether any of the requests of a batch of
+// historical dispatches have already been included in the active chain, and
+// updates their confirmation details accordingly. Transactions are looked up
+// within the backend's txindex first, while all remaining requests are looked
+// for within a single scan of the chain.
+func (b *BtcdNotifier) historicalConfBatch(
+	dispatches []*chainntnfs.HistoricalConfDispatch) {
+
+	var manualDispatches []*chainntnfs.HistoricalConfDispatch
+	for _, dispatch := range dispatches {
+		if dispatch.TxID == chainntnfs.ZeroHash {
+			manualDispatches = append(manualDispatches, dispatch)
+			continue
 		}
 
-		// TODO: fetch the neutrino filters instead.
-		block, err := b.chainConn.GetBlock(blockHash)
+		confDetails, _, err := b.confDetailsFromTxIndex(&dispatch.TxID)
 		if err != nil {
-			return nil, chainntnfs.TxNotFoundManually,
-				fmt.Errorf("unable to get block with hash "+
-					"%v: %v", blockHash, err)
+			chainntnfs.Log.Debugf("Failed getting conf details "+
+				"from index (%v), scanning manually", err)
+			manualDispatches = append(manualDispatches, dispatch)
+			continue
 		}
 
-		// For every transaction in the block, check which one matches
-		// our request. If we find one that does, we can dispatch its
-		// confirmation details.
-		for txIndex, tx := range block.Transactions {
-			if !confRequest.MatchesTx(tx) {
-				continue
-			}
+		b.updateConfDetails(dispatch.ConfRequest, confDetails)
+	}
 
-			return &chainntnfs.TxConfirmation{
-				Tx:          tx,
-				BlockHash:   blockHash,
-				BlockHeight: height,
-				TxIndex:     uint32(txIndex),
-			}, chainntnfs.TxFoundManually, nil
-		}
+	if len(manualDispatches) == 0 {
+		return
+	}
+
+	confs, err := chainntnfs.ScanConfDetails(
+		manualDispatches, b.scanBlock, b.quit,
+	)
+	if err != nil {
+		chainntnfs.Log.Errorf("Rescan to determine the conf details "+
+			"of %d requests failed: %v", len(manualDispatches), err)
+		return
 	}
 
-	// If we reach here, then we were not able to find the transaction
-	// within a block, so we avoid returning an error.
-	return nil, chainntnfs.TxNotFoundManually, nil
+	for i, dispatch := range manualDispatches {
+		b.updateConfDetails(dispatch.ConfRequest, confs[i])
+	}
+}
+
+// updateConfDetails hands the confirmation details found by a historical
+// rescan to the TxNotifier. It's invoked even if none were found, which allows
+// the notifier to begin safely updating the height hint cache at tip, since
+// the pending rescan has now completed.
+func (b *BtcdNotifier) updateConfDetails(confRequest chainntnfs.ConfRequest,
+	confDetails *chainntnfs.TxConfirmation) {
+
+	err := b.txNotifier.UpdateConfDetails(confRequest, confDetails)
+	if err != nil {
+		chainntnfs.Log.Errorf("Unable to update conf details of %v: %v",
+			confRequest, err)
+	}
+}
+
+// scanBlock fetches the block at the given height, such that it can be
+// scanned for the confirmations of historical dispatches.
+func (b *BtcdNotifier) scanBlock(height uint32,
+	_ []chainntnfs.ConfRequest) (*chainhash.Hash, *wire.MsgBlock, error) {
+
+	blockHash, err := b.chainConn.GetBlockHash(int64(height))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get hash from block "+
+			"with height %d", height)
+	}
+
+	// TODO: fetch the neutrino filters instead.
+	block, err := b.chainConn.GetBlock(blockHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get block with hash "+
+			"%v: %v", blockHash, err)
+	}
+
+	return blockHash, block, nil
 }
 
 // handleBlockConnected applies a chain update for a new block. Any watched
@@ -940,12 +1007,88 @@ func (b *BtcdNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	pkScript []byte,
 	numConfs, heightHint uint32) (*chainntnfs.ConfirmationEvent, error) {
 
-	// Construct a notification request for the transaction and send it to
-	// the main event loop.
+	ntfn, dispatch, err := b.registerConf(
+		txid, pkScript, numConfs, heightHint,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispatch == nil {
+		return ntfn.Event, nil
+	}
+
+	select {
+	case b.notificationRegistry <- dispatch:
+		return ntfn.Event, nil
+	case <-b.quit:
+		return nil, chainntnfs.ErrChainNotifierShuttingDown
+	}
+}
+
+// RegisterConfirmationsBatch registers for the confirmations of each of the
+// given requests, returning their events in the same order. The historical
+// rescans of all requests that may have confirmed already are performed within
+// a single pass over the chain.
+//
+// NOTE: This is part of the chainntnfs.BatchConfNotifier interface.
+func (b *BtcdNotifier) RegisterConfirmationsBatch(
+	regs []chainntnfs.ConfRegistration) ([]*chainntnfs.ConfirmationEvent,
+	error) {
+
+	var (
+		events      = make([]*chainntnfs.ConfirmationEvent, 0, len(regs))
+		batch       = &chainntnfs.HistoricalConfBatch{}
+		registerErr error
+	)
+	for _, reg := range regs {
+		ntfn, dispatch, err := b.registerConf(
+			reg.TxID, reg.PkScript, reg.NumConfs, reg.HeightHint,
+		)
+		if err != nil {
+			registerErr = err
+			break
+		}
+
+		events = append(events, ntfn.Event)
+		if dispatch != nil {
+			batch.Dispatches = append(batch.Dispatches, dispatch)
+		}
+	}
+
+	// The rescans of the requests registered are dispatched even if a
+	// later one failed to register, as the TxNotifier awaits their
+	// completion.
+	if len(batch.Dispatches) > 0 {
+		select {
+		case b.notificationRegistry <- batch:
+		case <-b.quit:
+			return nil, chainntnfs.ErrChainNotifierShuttingDown
+		}
+	}
+
+	if registerErr != nil {
+		for _, event := range events {
+			event.Cancel()
+		}
+		return nil, registerErr
+	}
+
+	return events, nil
+}
+
+// registerConf registers a confirmation request with the TxNotifier. A non-nil
+// historical dispatch is returned if the caller is required to perform a
+// manual scan for the confirmation.
+func (b *BtcdNotifier) registerConf(txid *chainhash.Hash, pkScript []byte,
+	numConfs, heightHint uint32) (*chainntnfs.ConfNtfn,
+	*chainntnfs.HistoricalConfDispatch, error) {
+
+	// Construct a notification request for the transaction.
 	confID := atomic.AddUint64(&b.confClientCounter, 1)
 	confRequest, err := chainntnfs.NewConfRequest(txid, pkScript)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	ntfn := &chainntnfs.ConfNtfn{
 		ConfID:           confID,
@@ -966,19 +1109,10 @@ func (b *BtcdNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	// watching at tip for the transaction to confirm.
 	dispatch, _, err := b.txNotifier.RegisterConf(ntfn)
 	if err != nil {
-		return nil, err
-	}
-
-	if dispatch == nil {
-		return ntfn.Event, nil
+		return nil, nil, err
 	}
 
-	select {
-	case b.notificationRegistry <- dispatch:
-		return ntfn.Event, nil
-	case <-b.quit:
-		return nil, chainntnfs.ErrChainNotifierShuttingDown
-	}
+	return ntfn, dispatch, nil
 }
 
 // blockEpochRegistration represents a client's intent to receive a
@@ -1918,7 +1918,7 @@ func TestInterfaces(t *testing.T) {
 			newNotifier = func() (chainntnfs.TestChainNotifier, error) {
 				return bitcoindnotify.New(
 					bitcoindConn, chainntnfs.NetParams,
-					hintCache, hintCache,
+					hintCache, hintCache, nil,
 				), nil
 			}
 
@@ -81,6 +81,10 @@ type NeutrinoNotifier struct {
 // Ensure NeutrinoNotifier implements the ChainNotifier interface at compile time.
 var _ chainntnfs.ChainNotifier = (*NeutrinoNotifier)(nil)
 
+// Ensure NeutrinoNotifier implements the BatchConfNotifier interface at
+// compile time.
+var _ chainntnfs.BatchConfNotifier = (*NeutrinoNotifier)(nil)
+
 // New creates a new instance of the NeutrinoNotifier concrete implementation
 // of the ChainNotifier interface.
 //
@@ -338,6 +342,18 @@ out:
 					}
 				}()
 
+			case *chainntnfs.HistoricalConfBatch:
+				// The rescans of a batch are performed within
+				// a single pass over the chain, matching the
+				// filter of each block against all of their
+				// scripts at once.
+				n.wg.Add(1)
+				go func() {
+					defer n.wg.Done()
+
+					n.historicalConfBatch(msg.Dispatches)
+				}()
+
 			case *blockEpochRegistration:
 				chainntnfs.Log.Infof("New block epoch subscription")
 
@@ -501,82 +517,106 @@ out:
 func (n *NeutrinoNotifier) historicalConfDetails(confRequest chainntnfs.ConfRequest,
 	startHeight, endHeight uint32) (*chainntnfs.TxConfirmation, error) {
 
-	// Starting from the height hint, we'll walk forwards in the chain to
-	// see if this transaction/output script has already been confirmed.
-	for scanHeight := endHeight; scanHeight >= startHeight && scanHeight > 0; scanHeight-- {
-		// Ensure we haven't been requested to shut down before
-		// processing the next height.
-		select {
-		case <-n.quit:
-			return nil, chainntnfs.ErrChainNotifierShuttingDown
-		default:
-		}
+	dispatch := &chainntnfs.HistoricalConfDispatch{
+		ConfRequest: confRequest,
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	}
+	confs, err := chainntnfs.ScanConfDetails(
+		[]*chainntnfs.HistoricalConfDispatch{dispatch}, n.scanBlock,
+		n.quit,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-		// First, we'll fetch the block header for this height so we
-		// can compute the current block hash.
-		blockHash, err := n.p2pNode.GetBlockHash(int64(scanHeight))
-		if err != nil {
-			return nil, fmt.Errorf("unable to get header for height=%v: %v",
-				scanHeight, err)
-		}
+	return confs[0], nil
+}
+
+// historicalConfBatch determines whether any of the requests of a batch of
+// historical dispatches have already been included in the active chain
+// within a single scan of the chain, and updates their confirmation details
+// accordingly.
+func (n *NeutrinoNotifier) historicalConfBatch(
+	dispatches []*chainntnfs.HistoricalConfDispatch) {
+
+	confs, err := chainntnfs.ScanConfDetails(dispatches, n.scanBlock, n.quit)
+	if err != nil {
+		chainntnfs.Log.Error(err)
+		return
+	}
 
-		// With the hash computed, we can now fetch the basic filter
-		// for this height.
-		regFilter, err := n.p2pNode.GetCFilter(
-			*blockHash, wire.GCSFilterRegular,
+	// If the historical dispatch finished without error, we will invoke
+	// UpdateConfDetails even if none were found. This allows the notifier
+	// to begin safely updating the height hint cache at tip, since any
+	// pending rescans have now completed.
+	for i, dispatch := range dispatches {
+		err := n.txNotifier.UpdateConfDetails(
+			dispatch.ConfRequest, confs[i],
 		)
 		if err != nil {
-			return nil, fmt.Errorf("unable to retrieve regular filter for "+
-				"height=%v: %v", scanHeight, err)
+			chainntnfs.Log.Error(err)
 		}
+	}
+}
 
-		// If the block has no transactions other than the Coinbase
-		// transaction, then the filter may be nil, so we'll continue
-		// forward int that case.
-		if regFilter == nil {
-			continue
-		}
+// scanBlock fetches the block at the given height if its filter matches the
+// script of any of the pending requests, such that it can be scanned for
+// their confirmations. Otherwise, a nil block is returned.
+func (n *NeutrinoNotifier) scanBlock(height uint32,
+	pending []chainntnfs.ConfRequest) (*chainhash.Hash, *wire.MsgBlock,
+	error) {
 
-		// In the case that the filter exists, we'll attempt to see if
-		// any element in it matches our target public key script.
-		key := builder.DeriveKey(blockHash)
-		match, err := regFilter.Match(key, confRequest.PkScript.Script())
-		if err != nil {
-			return nil, fmt.Errorf("unable to query filter: %v", err)
-		}
+	// First, we'll fetch the block header for this height so we can
+	// compute the current block hash.
+	blockHash, err := n.p2pNode.GetBlockHash(int64(height))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get header for "+
+			"height=%v: %v", height, err)
+	}
 
-		// If there's no match, then we can continue forward to the
-		// next block.
-		if !match {
-			continue
-		}
+	// With the hash computed, we can now fetch the basic filter for this
+	// height.
+	regFilter, err := n.p2pNode.GetCFilter(
+		*blockHash, wire.GCSFilterRegular,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to retrieve regular "+
+			"filter for height=%v: %v", height, err)
+	}
 
-		// In the case that we do have a match, we'll fetch the block
-		// from the network so we can find the positional data required
-		// to send the proper response.
-		block, err := n.p2pNode.GetBlock(*blockHash)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get block from network: %v", err)
-		}
+	// If the block has no transactions other than the Coinbase
+	// transaction, then the filter may be nil, so we'll skip the block in
+	// that case.
+	if regFilter == nil {
+		return blockHash, nil, nil
+	}
 
-		// For every transaction in the block, check which one matches
-		// our request. If we find one that does, we can dispatch its
-		// confirmation details.
-		for i, tx := range block.Transactions() {
-			if !confRequest.MatchesTx(tx.MsgTx()) {
-				continue
-			}
+	// In the case that the filter exists, we'll attempt to see if any
+	// element in it matches the scripts of the pending requests.
+	scripts := make([][]byte, 0, len(pending))
+	for _, confRequest := range pending {
+		scripts = append(scripts, confRequest.PkScript.Script())
+	}
+	key := builder.DeriveKey(blockHash)
+	match, err := regFilter.MatchAny(key, scripts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to query filter: %v", err)
+	}
+	if !match {
+		return blockHash, nil, nil
+	}
 
-			return &chainntnfs.TxConfirmation{
-				Tx:          tx.MsgTx(),
-				BlockHash:   blockHash,
-				BlockHeight: scanHeight,
-				TxIndex:     uint32(i),
-			}, nil
-		}
+	// In the case that we do have a match, we'll fetch the block from the
+	// network so it can be scanned for the positional data required to
+	// send the proper response.
+	block, err := n.p2pNode.GetBlock(*blockHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get block from "+
+			"network: %v", err)
 	}
 
-	return nil, nil
+	return blockHash, block.MsgBlock(), nil
 }
 
 // handleBlockConnected applies a chain update for a new block. Any watched
@@ -808,12 +848,130 @@ func (n *NeutrinoNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	pkScript []byte,
 	numConfs, heightHint uint32) (*chainntnfs.ConfirmationEvent, error) {
 
-	// Construct a notification request for the transaction and send it to
-	// the main event loop.
+	ntfn, dispatch, txNotifierTip, err := n.registerConf(
+		txid, pkScript, numConfs, heightHint,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// To determine whether this transaction has confirmed on-chain, we'll
+	// update our filter to watch for the transaction at tip and we'll also
+	// dispatch a historical rescan to determine if it has confirmed in the
+	// past.
+	//
+	// We'll update our filter first to ensure we can immediately detect the
+	// confirmation at tip.
+	err = n.updateConfFilter(
+		[]chainntnfs.ConfRequest{ntfn.ConfRequest}, txNotifierTip,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// If a historical rescan was not requested by the txNotifier, then we
+	// can return to the caller.
+	if dispatch == nil {
+		return ntfn.Event, nil
+	}
+
+	// Finally, with the filter updated, we can dispatch the historical
+	// rescan to ensure we can detect if the event happened in the past.
+	select {
+	case n.notificationRegistry <- dispatch:
+	case <-n.quit:
+		return nil, chainntnfs.ErrChainNotifierShuttingDown
+	}
+
+	return ntfn.Event, nil
+}
+
+// RegisterConfirmationsBatch registers for the confirmations of each of the
+// given requests, returning their events in the same order. Our filter is
+// updated for all of them at once, after which the historical rescans of
+// those that may have confirmed already are performed within a single pass
+// over the chain.
+//
+// NOTE: This is part of the chainntnfs.BatchConfNotifier interface.
+func (n *NeutrinoNotifier) RegisterConfirmationsBatch(
+	regs []chainntnfs.ConfRegistration) ([]*chainntnfs.ConfirmationEvent,
+	error) {
+
+	events := make([]*chainntnfs.ConfirmationEvent, 0, len(regs))
+	var (
+		confRequests  = make([]chainntnfs.ConfRequest, 0, len(regs))
+		batch         = &chainntnfs.HistoricalConfBatch{}
+		txNotifierTip uint32
+		registerErr   error
+	)
+	for _, reg := range regs {
+		ntfn, dispatch, tip, err := n.registerConf(
+			reg.TxID, reg.PkScript, reg.NumConfs, reg.HeightHint,
+		)
+		if err != nil {
+			registerErr = err
+			break
+		}
+
+		// Our filter is rewound to the earliest tip any of the
+		// requests were registered at.
+		if len(events) == 0 || tip < txNotifierTip {
+			txNotifierTip = tip
+		}
+
+		events = append(events, ntfn.Event)
+		confRequests = append(confRequests, ntfn.ConfRequest)
+		if dispatch != nil {
+			batch.Dispatches = append(batch.Dispatches, dispatch)
+		}
+	}
+
+	cancelEvents := func() {
+		for _, event := range events {
+			event.Cancel()
+		}
+	}
+
+	if len(confRequests) > 0 {
+		err := n.updateConfFilter(confRequests, txNotifierTip)
+		if err != nil {
+			cancelEvents()
+			return nil, err
+		}
+	}
+
+	// The rescans of the requests registered are dispatched even if a
+	// later one failed to register, as the TxNotifier awaits their
+	// completion.
+	if len(batch.Dispatches) > 0 {
+		select {
+		case n.notificationRegistry <- batch:
+		case <-n.quit:
+			return nil, chainntnfs.ErrChainNotifierShuttingDown
+		}
+	}
+
+	if registerErr != nil {
+		cancelEvents()
+		return nil, registerErr
+	}
+
+	return events, nil
+}
+
+// registerConf registers a confirmation request with the TxNotifier. A non-nil
+// historical dispatch is returned if the caller is required to perform a
+// manual scan for the confirmation, along with the tip of the TxNotifier at
+// the time of registration.
+func (n *NeutrinoNotifier) registerConf(txid *chainhash.Hash, pkScript []byte,
+	numConfs, heightHint uint32) (*chainntnfs.ConfNtfn,
+	*chainntnfs.HistoricalConfDispatch, uint32, error) {
+
+	// Construct a notification request for the transaction.
 	confID := atomic.AddUint64(&n.confClientCounter, 1)
 	confRequest, err := chainntnfs.NewConfRequest(txid, pkScript)
 	if err != nil {
-		return nil, err
+		return nil, nil, 0, err
 	}
 	ntfn := &chainntnfs.ConfNtfn{
 		ConfID:           confID,
@@ -834,24 +992,31 @@ func (n *NeutrinoNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	// watching at tip for the transaction to confirm.
 	dispatch, txNotifierTip, err := n.txNotifier.RegisterConf(ntfn)
 	if err != nil {
-		return nil, err
+		return nil, nil, 0, err
 	}
 
-	// To determine whether this transaction has confirmed on-chain, we'll
-	// update our filter to watch for the transaction at tip and we'll also
-	// dispatch a historical rescan to determine if it has confirmed in the
-	// past.
-	//
-	// We'll update our filter first to ensure we can immediately detect the
-	// confirmation at tip. To do so, we'll map the script into an address
-	// type so we can instruct neutrino to match if the transaction
-	// containing the script is found in a block.
+	return ntfn, dispatch, txNotifierTip, nil
+}
+
+// updateConfFilter updates our filter to watch for the scripts of the given
+// requests, rewinding it to the given height so that confirmations at tip
+// aren't missed.
+func (n *NeutrinoNotifier) updateConfFilter(
+	confRequests []chainntnfs.ConfRequest, rewindHeight uint32) error {
+
+	// To do so, we'll map each script into an address type so we can
+	// instruct neutrino to match if the transaction containing the script
+	// is found in a block.
 	params := n.p2pNode.ChainParams()
-	_, addrs, _, err := txscript.ExtractPkScriptAddrs(
-		confRequest.PkScript.Script(), &params,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract script: %v", err)
+	var addrs []btcutil.Address
+	for _, confRequest := range confRequests {
+		_, scriptAddrs, _, err := txscript.ExtractPkScriptAddrs(
+			confRequest.PkScript.Script(), &params,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to extract script: %v", err)
+		}
+		addrs = append(addrs, scriptAddrs...)
 	}
 
 	// We'll send the filter update request to the notifier's main event
@@ -861,39 +1026,26 @@ func (n *NeutrinoNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	case n.notificationRegistry <- &rescanFilterUpdate{
 		updateOptions: []neutrino.UpdateOption{
 			neutrino.AddAddrs(addrs...),
-			neutrino.Rewind(txNotifierTip),
+			neutrino.Rewind(rewindHeight),
 			neutrino.DisableDisconnectedNtfns(true),
 		},
 		errChan: errChan,
 	}:
 	case <-n.quit:
-		return nil, chainntnfs.ErrChainNotifierShuttingDown
+		return chainntnfs.ErrChainNotifierShuttingDown
 	}
 
+	var err error
 	select {
 	case err = <-errChan:
 	case <-n.quit:
-		return nil, chainntnfs.ErrChainNotifierShuttingDown
+		return chainntnfs.ErrChainNotifierShuttingDown
 	}
 	if err != nil {
-		return nil, fmt.Errorf("unable to update filter: %v", err)
+		return fmt.Errorf("unable to update filter: %v", err)
 	}
 
-	// If a historical rescan was not requested by the txNotifier, then we
-	// can return to the caller.
-	if dispatch == nil {
-		return ntfn.Event, nil
-	}
-
-	// Finally, with the filter updated, we can dispatch the historical
-	// rescan to ensure we can detect if the event happened in the past.
-	select {
-	case n.notificationRegistry <- dispatch:
-	case <-n.quit:
-		return nil, chainntnfs.ErrChainNotifierShuttingDown
-	}
-
-	return ntfn.Event, nil
+	return nil
 }
 
 // blockEpochRegistration represents a client's intent to receive a
@@ -1,6 +1,8 @@
 package lnd
 
 import (
+	"bytes"
+
 	"github.com/litecoinfinance/btcd/chaincfg"
 	bitcoinCfg "github.com/litecoinfinance/btcd/chaincfg"
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
@@ -135,6 +137,51 @@ func applyLitecoinfinanceParams(params *bitcoinNetParams, litecoinfinanceParams
 	params.CoinType = litecoinfinanceParams.CoinType
 }
 
+// applyLitecoinfinanceLightClientParams applies the consensus parameters of
+// the litecoinfinance chain that are required by the neutrino light client on
+// top of those applied by applyLitecoinfinanceParams. Unlike a full node
+// backend, the light client validates the headers of the chain and builds the
+// filter of the genesis block itself, and it discovers its peers through the
+// DNS seeds of the chain.
+func applyLitecoinfinanceLightClientParams(params *bitcoinNetParams,
+	litecoinfinanceParams *litecoinfinanceNetParams) error {
+
+	// The genesis block is converted through its serialization, as its
+	// type is specific to the litecoinfinance wire package.
+	var b bytes.Buffer
+	err := litecoinfinanceParams.GenesisBlock.Serialize(&b)
+	if err != nil {
+		return err
+	}
+	genesisBlock := &bitcoinWire.MsgBlock{}
+	if err := genesisBlock.Deserialize(&b); err != nil {
+		return err
+	}
+	params.GenesisBlock = genesisBlock
+
+	params.PowLimit = litecoinfinanceParams.PowLimit
+	params.PowLimitBits = litecoinfinanceParams.PowLimitBits
+	params.TargetTimespan = litecoinfinanceParams.TargetTimespan
+	params.TargetTimePerBlock = litecoinfinanceParams.TargetTimePerBlock
+	params.RetargetAdjustmentFactor =
+		litecoinfinanceParams.RetargetAdjustmentFactor
+	params.ReduceMinDifficulty = litecoinfinanceParams.ReduceMinDifficulty
+	params.MinDiffReductionTime = litecoinfinanceParams.MinDiffReductionTime
+
+	dnsSeeds := make(
+		[]chaincfg.DNSSeed, len(litecoinfinanceParams.DNSSeeds),
+	)
+	for i, seed := range litecoinfinanceParams.DNSSeeds {
+		dnsSeeds[i] = chaincfg.DNSSeed{
+			Host:         seed.Host,
+			HasFiltering: seed.HasFiltering,
+		}
+	}
+	params.DNSSeeds = dnsSeeds
+
+	return nil
+}
+
 // isTestnet tests if the given params correspond to a testnet
 // parameter configuration.
 func isTestnet(params *bitcoinNetParams) bool {
@@ -14,11 +14,13 @@ import (
 
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/rpcclient"
+	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
 	"github.com/litecoinfinance/btcwallet/chain"
 	"github.com/litecoinfinance/btcwallet/wallet"
 	"github.com/litecoinfinance/btcwallet/walletdb"
 	"github.com/litecoinfinance/neutrino"
+	"github.com/litecoinfinance/lnd/blockfetch"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/chainntnfs/bitcoindnotify"
 	"github.com/litecoinfinance/lnd/chainntnfs/btcdnotify"
@@ -29,8 +31,10 @@ import (
 	"github.com/litecoinfinance/lnd/keychain"
 	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/lnwallet/btcwallet"
+	"github.com/litecoinfinance/lnd/lnwallet/rpcwallet"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/routing/chainview"
+	"github.com/litecoinfinance/lnd/ticker"
 )
 
 const (
@@ -118,6 +122,14 @@ type chainControl struct {
 
 	chainView chainview.FilteredChainView
 
+	// chainSource is the client through which the wallet talks to the
+	// chain backend.
+	chainSource chain.Interface
+
+	// networkHeight returns the height of the best header known to the
+	// chain backend. It's nil if the backend doesn't expose it.
+	networkHeight func() (int32, error)
+
 	wallet *lnwallet.LightningWallet
 
 	routingPolicy htlcswitch.ForwardingPolicy
@@ -145,6 +157,7 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 
 	cc := &chainControl{}
 
+	var defaultStaticFeePerKW lnwallet.SatPerKWeight
 	switch registeredChains.PrimaryChain() {
 	case bitcoinChain:
 		cc.routingPolicy = htlcswitch.ForwardingPolicy{
@@ -153,9 +166,7 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 			FeeRate:       cfg.Bitcoin.FeeRate,
 			TimeLockDelta: cfg.Bitcoin.TimeLockDelta,
 		}
-		cc.feeEstimator = lnwallet.NewStaticFeeEstimator(
-			defaultBitcoinStaticFeePerKW, 0,
-		)
+		defaultStaticFeePerKW = defaultBitcoinStaticFeePerKW
 	case litecoinfinanceChain:
 		cc.routingPolicy = htlcswitch.ForwardingPolicy{
 			MinHTLC:       cfg.Litecoinfinance.MinHTLC,
@@ -163,13 +174,14 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 			FeeRate:       cfg.Litecoinfinance.FeeRate,
 			TimeLockDelta: cfg.Litecoinfinance.TimeLockDelta,
 		}
-		cc.feeEstimator = lnwallet.NewStaticFeeEstimator(
-			defaultLitecoinfinanceStaticFeePerKW, 0,
-		)
+		defaultStaticFeePerKW = defaultLitecoinfinanceStaticFeePerKW
 	default:
 		return nil, fmt.Errorf("Default routing policy for chain %v is "+
 			"unknown", registeredChains.PrimaryChain())
 	}
+	cc.feeEstimator = lnwallet.NewStaticFeeEstimator(
+		defaultStaticFeePerKW, 0,
+	)
 
 	walletConfig := &btcwallet.Config{
 		PrivatePass:    privateWalletPw,
@@ -191,6 +203,14 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 			"cache: %v", err)
 	}
 
+	// The blocks fetched by our view of the chain are cached, such that
+	// repeated requests for the same blocks don't all reach the backend.
+	blockCache := chainview.NewBlockCache(chainview.DefaultBlockCacheSize)
+
+	// blockFetcher retrieves the blocks pruned by the chain backend from
+	// the P2P network. It's only set for pruned bitcoind backends.
+	var blockFetcher *blockfetch.Fetcher
+
 	// If spv mode is active, then we'll be using a distinct set of
 	// chainControl interfaces that interface directly with the p2p network
 	// of the selected chain.
@@ -202,7 +222,9 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 		cc.chainNotifier = neutrinonotify.New(
 			neutrinoCS, hintCache, hintCache,
 		)
-		cc.chainView, err = chainview.NewCfFilteredChainView(neutrinoCS)
+		cc.chainView, err = chainview.NewCfFilteredChainView(
+			neutrinoCS, blockCache,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -215,7 +237,7 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 				lnwallet.SparseConfFeeSource{
 					URL: cfg.NeutrinoMode.FeeURL,
 				},
-				defaultBitcoinStaticFeePerKW,
+				defaultStaticFeePerKW,
 			)
 
 			if err := estimator.Start(); err != nil {
@@ -291,14 +313,6 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 				"%v", err)
 		}
 
-		cc.chainNotifier = bitcoindnotify.New(
-			bitcoindConn, activeNetParams.Params, hintCache, hintCache,
-		)
-		cc.chainView = chainview.NewBitcoindFilteredChainView(bitcoindConn)
-		walletConfig.ChainSource = bitcoindConn.NewBitcoindClient()
-
-		// If we're not in regtest mode, then we'll attempt to use a
-		// proper fee estimator for testnet.
 		rpcConfig := &rpcclient.ConnConfig{
 			Host:                 bitcoindHost,
 			User:                 bitcoindMode.RPCUser,
@@ -308,6 +322,81 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 			DisableTLS:           true,
 			HTTPPostMode:         true,
 		}
+		bitcoindRPC, err := rpcclient.New(rpcConfig, nil)
+		if err != nil {
+			return nil, err
+		}
+		cc.networkHeight = bestHeaderHeight(bitcoindRPC)
+
+		// If bitcoind prunes its blocks, those needed to resolve
+		// channels or validate the channel graph are fetched from the
+		// P2P network once bitcoind has pruned them.
+		if bitcoindMode.Pruned {
+			blockFetcher = newPrunedBlockFetcher(
+				bitcoindMode.PrunedBlockPeers, bitcoindRPC,
+			)
+		}
+
+		// Unless bitcoind doesn't expose ZMQ to us, in which case our
+		// chain notifier polls it over RPC for new blocks instead, the
+		// notifier receives them over ZMQ.
+		if bitcoindMode.PollBlocks {
+			pollRPC, err := rpcclient.New(rpcConfig, nil)
+			if err != nil {
+				return nil, err
+			}
+			pollClient := bitcoindnotify.NewPollingClient(
+				pollRPC, bitcoindnotify.DefaultMinPollInterval,
+				bitcoindnotify.DefaultMaxPollInterval,
+			)
+			cc.chainNotifier = bitcoindnotify.NewWithClient(
+				pollClient, activeNetParams.Params, hintCache,
+				hintCache, blockFetcher,
+			)
+		} else {
+			cc.chainNotifier = bitcoindnotify.New(
+				bitcoindConn, activeNetParams.Params, hintCache,
+				hintCache, blockFetcher,
+			)
+		}
+		walletConfig.ChainSource = bitcoindConn.NewBitcoindClient()
+
+		// If bitcoind maintains an index of compact block filters, our
+		// view of the chain can match those rather than scanning each
+		// block for the spends of channel outputs.
+		if bitcoindMode.BlockFilters {
+			cc.chainView, err = chainview.NewBitcoindCfFilteredChainView(
+				bitcoindConn, *rpcConfig, blockCache,
+			)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			cc.chainView = chainview.NewBitcoindFilteredChainView(
+				bitcoindConn, blockCache,
+			)
+		}
+
+		// If requested, the btcd node of the active chain takes over
+		// watching for closed channels whenever bitcoind can't be
+		// reached.
+		if bitcoindMode.FallbackBtcd {
+			btcdMode := cfg.BtcdMode
+			if cfg.Litecoinfinance.Active {
+				btcdMode = cfg.LtfndMode
+			}
+
+			cc.chainView, err = newFailoverChainView(
+				cc.chainView, bitcoindConn, btcdMode,
+				blockCache,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// If we're not in regtest mode, then we'll attempt to use a
+		// proper fee estimator for testnet.
 		if cfg.Bitcoin.Active && !cfg.Bitcoin.RegTest {
 			ltndLog.Infof("Initializing bitcoind backed fee estimator")
 
@@ -345,11 +434,6 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 		}
 	case "btcd", "ltfnd":
 		// Otherwise, we'll be speaking directly via RPC to a node.
-		//
-		// So first we'll load btcd/ltfnd's TLS cert for the RPC
-		// connection. If a raw cert was specified in the config, then
-		// we'll set that directly. Otherwise, we attempt to read the
-		// cert from the path specified in the config.
 		var btcdMode *btcdConfig
 		switch {
 		case cfg.Bitcoin.Active:
@@ -357,50 +441,11 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 		case cfg.Litecoinfinance.Active:
 			btcdMode = cfg.LtfndMode
 		}
-		var rpcCert []byte
-		if btcdMode.RawRPCCert != "" {
-			rpcCert, err = hex.DecodeString(btcdMode.RawRPCCert)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			certFile, err := os.Open(btcdMode.RPCCert)
-			if err != nil {
-				return nil, err
-			}
-			rpcCert, err = ioutil.ReadAll(certFile)
-			if err != nil {
-				return nil, err
-			}
-			if err := certFile.Close(); err != nil {
-				return nil, err
-			}
-		}
-
-		// If the specified host for the btcd/ltfnd RPC server already
-		// has a port specified, then we use that directly. Otherwise,
-		// we assume the default port according to the selected chain
-		// parameters.
-		var btcdHost string
-		if strings.Contains(btcdMode.RPCHost, ":") {
-			btcdHost = btcdMode.RPCHost
-		} else {
-			btcdHost = fmt.Sprintf("%v:%v", btcdMode.RPCHost,
-				activeNetParams.rpcPort)
+		rpcConfig, err := newBtcdRPCConfig(btcdMode)
+		if err != nil {
+			return nil, err
 		}
 
-		btcdUser := btcdMode.RPCUser
-		btcdPass := btcdMode.RPCPass
-		rpcConfig := &rpcclient.ConnConfig{
-			Host:                 btcdHost,
-			Endpoint:             "ws",
-			User:                 btcdUser,
-			Pass:                 btcdPass,
-			Certificates:         rpcCert,
-			DisableTLS:           false,
-			DisableConnectOnNew:  true,
-			DisableAutoReconnect: false,
-		}
 		cc.chainNotifier, err = btcdnotify.New(
 			rpcConfig, activeNetParams.Params, hintCache, hintCache,
 		)
@@ -410,7 +455,9 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 
 		// Finally, we'll create an instance of the default chain view to be
 		// used within the routing layer.
-		cc.chainView, err = chainview.NewBtcdFilteredChainView(*rpcConfig)
+		cc.chainView, err = chainview.NewBtcdFilteredChainView(
+			*rpcConfig, blockCache,
+		)
 		if err != nil {
 			srvrLog.Errorf("unable to create chain view: %v", err)
 			return nil, err
@@ -418,13 +465,15 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 
 		// Create a special websockets rpc client for btcd which will be used
 		// by the wallet for notifications, calls, etc.
-		chainRPC, err := chain.NewRPCClient(activeNetParams.Params, btcdHost,
-			btcdUser, btcdPass, rpcCert, false, 20)
+		chainRPC, err := chain.NewRPCClient(activeNetParams.Params,
+			rpcConfig.Host, rpcConfig.User, rpcConfig.Pass,
+			rpcConfig.Certificates, false, 20)
 		if err != nil {
 			return nil, err
 		}
 
 		walletConfig.ChainSource = chainRPC
+		cc.networkHeight = bestHeaderHeight(chainRPC.Client)
 
 		// If we're not in simnet or regtest mode, then we'll attempt
 		// to use a proper fee estimator for testnet.
@@ -453,6 +502,8 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 			homeChainConfig.Node)
 	}
 
+	cc.chainSource = walletConfig.ChainSource
+
 	wc, err := btcwallet.New(*walletConfig)
 	if err != nil {
 		fmt.Printf("unable to create wallet controller: %v\n", err)
@@ -464,6 +515,13 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 	cc.chainIO = wc
 	cc.wc = wc
 
+	if blockFetcher != nil {
+		cc.chainIO = &prunedChainIO{
+			BlockChainIO: wc,
+			blockFetcher: blockFetcher,
+		}
+	}
+
 	// Select the default channel constraints for the primary chain.
 	channelConstraints := defaultBtcChannelConstraints
 	if registeredChains.PrimaryChain() == litecoinfinanceChain {
@@ -475,6 +533,29 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 	)
 	cc.keyRing = keyRing
 
+	// If a remote signer is used, the keys of our channels are derived and
+	// signed with by it, while the keys of the on-chain wallet and our
+	// node key remain with the local wallet.
+	if cfg.RemoteSigner.Enable {
+		conn, err := rpcwallet.Connect(
+			cfg.RemoteSigner.RPCHost, cfg.RemoteSigner.TLSCertPath,
+			cfg.RemoteSigner.MacaroonPath,
+			cfg.RemoteSigner.Timeout,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcKeyRing := rpcwallet.NewRPCKeyRing(
+			keyRing, wc, conn, cfg.RemoteSigner.Timeout,
+		)
+		cc.keyRing = rpcKeyRing
+		cc.signer = rpcKeyRing
+
+		ltndLog.Infof("Using remote signer at %v for the keys of "+
+			"channels", cfg.RemoteSigner.RPCHost)
+	}
+
 	// Create, and start the lnwallet, which handles the core payment
 	// channel logic, and exposes control via proxy state machines.
 	walletCfg := lnwallet.Config{
@@ -483,7 +564,7 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 		WalletController:   wc,
 		Signer:             cc.signer,
 		FeeEstimator:       cc.feeEstimator,
-		SecretKeyRing:      keyRing,
+		SecretKeyRing:      cc.keyRing,
 		ChainIO:            cc.chainIO,
 		DefaultConstraints: channelConstraints,
 		NetParams:          *activeNetParams.Params,
@@ -675,6 +756,182 @@ func (c *chainRegistry) NumActiveChains() uint32 {
 	return uint32(len(c.activeChains))
 }
 
+// prunedChainIO is a BlockChainIO backed by a pruned chain backend. Blocks the
+// backend has pruned are fetched from the P2P network instead.
+type prunedChainIO struct {
+	lnwallet.BlockChainIO
+
+	blockFetcher *blockfetch.Fetcher
+}
+
+// GetBlock returns the block with the given hash, fetching it from the P2P
+// network if the backend has pruned it.
+//
+// NOTE: This is part of the lnwallet.BlockChainIO interface.
+func (p *prunedChainIO) GetBlock(
+	blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+
+	return p.blockFetcher.GetBlock(blockHash, p.BlockChainIO.GetBlock)
+}
+
+// newPrunedBlockFetcher creates a block fetcher that retrieves the blocks
+// pruned by bitcoind from the given peers, followed by the outbound peers of
+// bitcoind itself.
+func newPrunedBlockFetcher(peers []string,
+	bitcoindRPC *rpcclient.Client) *blockfetch.Fetcher {
+
+	return blockfetch.New(&blockfetch.Config{
+		ChainParams: activeNetParams.Params,
+		Peers: func() ([]string, error) {
+			addrs := make([]string, 0, len(peers))
+			for _, addr := range peers {
+				_, _, err := net.SplitHostPort(addr)
+				if err != nil {
+					port := activeNetParams.DefaultPort
+					addr = net.JoinHostPort(addr, port)
+				}
+				addrs = append(addrs, addr)
+			}
+
+			// The peers bitcoind connected to itself are likely
+			// reachable, though only those it dialed are listening
+			// on the address it reports.
+			peerInfo, err := bitcoindRPC.GetPeerInfo()
+			if err != nil {
+				if len(addrs) == 0 {
+					return nil, err
+				}
+
+				ltndLog.Warnf("Unable to fetch peers of "+
+					"bitcoind: %v", err)
+				return addrs, nil
+			}
+			for _, info := range peerInfo {
+				if info.Inbound {
+					continue
+				}
+				addrs = append(addrs, info.Addr)
+			}
+
+			return addrs, nil
+		},
+		Dial:     cfg.net.Dial,
+		Timeout:  blockfetch.DefaultTimeout,
+		MaxPeers: blockfetch.DefaultMaxPeers,
+	})
+}
+
+// bestHeaderHeight returns a closure that queries the height of the best
+// header known to the backend behind the given RPC client.
+func bestHeaderHeight(client *rpcclient.Client) func() (int32, error) {
+	return func() (int32, error) {
+		info, err := client.GetBlockChainInfo()
+		if err != nil {
+			return 0, err
+		}
+
+		return info.Headers, nil
+	}
+}
+
+// newBtcdRPCConfig returns the config of a websockets RPC connection to the
+// btcd/ltfnd node described by the given config.
+func newBtcdRPCConfig(btcdMode *btcdConfig) (*rpcclient.ConnConfig, error) {
+	// First we'll load btcd/ltfnd's TLS cert for the RPC connection. If a
+	// raw cert was specified in the config, then we'll set that directly.
+	// Otherwise, we attempt to read the cert from the path specified in
+	// the config.
+	var (
+		rpcCert []byte
+		err     error
+	)
+	if btcdMode.RawRPCCert != "" {
+		rpcCert, err = hex.DecodeString(btcdMode.RawRPCCert)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		certFile, err := os.Open(btcdMode.RPCCert)
+		if err != nil {
+			return nil, err
+		}
+		rpcCert, err = ioutil.ReadAll(certFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := certFile.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	// If the specified host for the btcd/ltfnd RPC server already has a
+	// port specified, then we use that directly. Otherwise, we assume the
+	// default port according to the selected chain parameters.
+	var btcdHost string
+	if strings.Contains(btcdMode.RPCHost, ":") {
+		btcdHost = btcdMode.RPCHost
+	} else {
+		btcdHost = fmt.Sprintf("%v:%v", btcdMode.RPCHost,
+			activeNetParams.rpcPort)
+	}
+
+	return &rpcclient.ConnConfig{
+		Host:                 btcdHost,
+		Endpoint:             "ws",
+		User:                 btcdMode.RPCUser,
+		Pass:                 btcdMode.RPCPass,
+		Certificates:         rpcCert,
+		DisableTLS:           false,
+		DisableConnectOnNew:  true,
+		DisableAutoReconnect: false,
+	}, nil
+}
+
+// newFailoverChainView wraps the chain view of a bitcoind backend, such that
+// the given btcd node is used to detect closed channels for as long as
+// bitcoind is unavailable. The btcd chain view fetches blocks through the
+// passed cache.
+func newFailoverChainView(bitcoindView chainview.FilteredChainView,
+	bitcoindConn *chain.BitcoindConn, btcdMode *btcdConfig,
+	blockCache *chainview.BlockCache) (chainview.FilteredChainView, error) {
+
+	rpcConfig, err := newBtcdRPCConfig(btcdMode)
+	if err != nil {
+		return nil, err
+	}
+	btcdView, err := chainview.NewBtcdFilteredChainView(
+		*rpcConfig, blockCache,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The chain view only polls btcd for its best block, so a plain HTTP
+	// POST client suffices for that.
+	pollConfig := *rpcConfig
+	pollConfig.HTTPPostMode = true
+	btcdClient, err := rpcclient.New(&pollConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return chainview.NewFailoverFilteredChainView(
+		[]*chainview.FailoverBackend{
+			{
+				Name:      "bitcoind",
+				ChainView: bitcoindView,
+				Chain:     bitcoindConn.NewBitcoindClient(),
+			},
+			{
+				Name:      "btcd",
+				ChainView: btcdView,
+				Chain:     btcdClient,
+			},
+		},
+		ticker.New(chainview.DefaultFailoverCheckInterval),
+	)
+}
+
 // initNeutrinoBackend inits a new instance of the neutrino light client
 // backend given a target chain directory to store the chain state.
 func initNeutrinoBackend(chainDir string) (*neutrino.ChainService, func(), error) {
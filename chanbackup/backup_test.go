@@ -45,7 +45,7 @@ func (m *mockChannelSource) FetchChannel(chanPoint wire.OutPoint) (*channeldb.Op
 
 	channel, ok := m.chans[chanPoint]
 	if !ok {
-		return nil, fmt.Errorf("can't find chan")
+		return nil, channeldb.ErrChannelNotFound
 	}
 
 	return channel, nil
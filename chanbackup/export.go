@@ -0,0 +1,306 @@
+package chanbackup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/keychain"
+)
+
+var (
+	// ErrBackupMismatch is returned if a backup read back from its
+	// destination doesn't match the backup that was written to it.
+	ErrBackupMismatch = errors.New("backup read back from destination " +
+		"doesn't match backup written")
+)
+
+const (
+	// BackupHashHeader is the HTTP header carrying the hex encoded sha256
+	// of the uploaded backup, allowing the receiving endpoint to verify
+	// its integrity.
+	BackupHashHeader = "X-Backup-Sha256"
+)
+
+// Uploader is an interface that allows the Exporter to copy each new packed
+// multi backup to a destination other than the main backup file, such as
+// another disk, or a remote endpoint reached over HTTP or SFTP.
+type Uploader interface {
+	// Name returns a human readable description of the destination.
+	Name() string
+
+	// Upload copies the packed multi backup to the destination, and
+	// returns a non-nil error if its integrity couldn't be verified once
+	// written.
+	Upload(newBackup PackedMulti) error
+}
+
+// FileUploader is an Uploader that atomically writes backups to a path on the
+// local file system, typically located on another disk than the main backup
+// file.
+type FileUploader struct {
+	path string
+
+	file *MultiFile
+}
+
+// A compile time check to ensure that FileUploader implements the Uploader
+// interface.
+var _ Uploader = (*FileUploader)(nil)
+
+// NewFileUploader creates a new FileUploader writing backups to path.
+func NewFileUploader(path string) *FileUploader {
+	return &FileUploader{
+		path: path,
+		file: NewMultiFile(path),
+	}
+}
+
+// Name returns the path backups are written to.
+//
+// NOTE: This is part of the Uploader interface.
+func (f *FileUploader) Name() string {
+	return f.path
+}
+
+// Upload atomically swaps the file at the target path for the new backup, and
+// then reads it back to verify that it was written intact.
+//
+// NOTE: This is part of the Uploader interface.
+func (f *FileUploader) Upload(newBackup PackedMulti) error {
+	if err := f.file.UpdateAndSwap(newBackup); err != nil {
+		return err
+	}
+
+	written, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("unable to read back backup: %v", err)
+	}
+	if !bytes.Equal(written, newBackup) {
+		return ErrBackupMismatch
+	}
+
+	return nil
+}
+
+// HTTPUploader is an Uploader that PUTs backups to an HTTP endpoint. The
+// sha256 of each backup is passed in the BackupHashHeader, and the upload is
+// only considered successful if the endpoint responds with a 2xx status.
+type HTTPUploader struct {
+	url string
+
+	client *http.Client
+}
+
+// A compile time check to ensure that HTTPUploader implements the Uploader
+// interface.
+var _ Uploader = (*HTTPUploader)(nil)
+
+// NewHTTPUploader creates a new HTTPUploader that uploads backups to url,
+// failing each upload that takes longer than timeout.
+func NewHTTPUploader(url string, timeout time.Duration) *HTTPUploader {
+	return &HTTPUploader{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Name returns the URL backups are uploaded to.
+//
+// NOTE: This is part of the Uploader interface.
+func (h *HTTPUploader) Name() string {
+	return h.url
+}
+
+// Upload PUTs the backup to the endpoint.
+//
+// NOTE: This is part of the Uploader interface.
+func (h *HTTPUploader) Upload(newBackup PackedMulti) error {
+	req, err := http.NewRequest(
+		http.MethodPut, h.url, bytes.NewReader(newBackup),
+	)
+	if err != nil {
+		return err
+	}
+
+	backupHash := sha256.Sum256(newBackup)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set(BackupHashHeader, hex.EncodeToString(backupHash[:]))
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %v",
+			resp.Status)
+	}
+
+	return nil
+}
+
+// UploadStatus describes the outcome of the most recent upload to a backup
+// destination.
+type UploadStatus struct {
+	// Destination is the name of the destination.
+	Destination string
+
+	// LastAttempt is the time of the most recent upload, or the zero time
+	// if no backup has been uploaded yet.
+	LastAttempt time.Time
+
+	// LastSuccess is the time of the most recent successful upload.
+	LastSuccess time.Time
+
+	// LastErr is the reason the most recent upload failed, or nil if it
+	// succeeded.
+	LastErr error
+}
+
+// Exporter is a Swapper that, after swapping out the main backup file, copies
+// the new backup to a set of additional destinations. As the SubSwapper swaps
+// the backup every time a channel is opened or closed, each destination
+// always holds the backup of our latest set of channels. A failure to upload
+// to a destination is logged and recorded in its status, but doesn't fail the
+// swap, such that an unreachable endpoint can't hold back the main backup.
+type Exporter struct {
+	mainFile Swapper
+
+	uploaders []Uploader
+
+	statusMtx sync.RWMutex
+	status    []UploadStatus
+}
+
+// A compile time check to ensure that Exporter implements the Swapper
+// interface.
+var _ Swapper = (*Exporter)(nil)
+
+// NewExporter creates a new Exporter that swaps out mainFile, and then
+// uploads each backup to the given uploaders in order.
+func NewExporter(mainFile Swapper, uploaders ...Uploader) *Exporter {
+	status := make([]UploadStatus, len(uploaders))
+	for i, uploader := range uploaders {
+		status[i].Destination = uploader.Name()
+	}
+
+	return &Exporter{
+		mainFile:  mainFile,
+		uploaders: uploaders,
+		status:    status,
+	}
+}
+
+// UpdateAndSwap atomically swaps out the main backup file for the new backup,
+// and then uploads it to each destination. Only a failure to swap the main
+// backup file is returned.
+//
+// NOTE: This is part of the Swapper interface.
+func (e *Exporter) UpdateAndSwap(newBackup PackedMulti) error {
+	if err := e.mainFile.UpdateAndSwap(newBackup); err != nil {
+		return err
+	}
+
+	for i, uploader := range e.uploaders {
+		log.Debugf("Exporting backup to %v", uploader.Name())
+
+		err := uploader.Upload(newBackup)
+		if err != nil {
+			log.Errorf("Unable to export backup to %v: %v",
+				uploader.Name(), err)
+		}
+
+		e.statusMtx.Lock()
+		e.status[i].LastAttempt = time.Now()
+		e.status[i].LastErr = err
+		if err == nil {
+			e.status[i].LastSuccess = e.status[i].LastAttempt
+		}
+		e.statusMtx.Unlock()
+	}
+
+	return nil
+}
+
+// Status returns the outcome of the most recent upload to each destination.
+func (e *Exporter) Status() []UploadStatus {
+	e.statusMtx.RLock()
+	defer e.statusMtx.RUnlock()
+
+	status := make([]UploadStatus, len(e.status))
+	copy(status, e.status)
+
+	return status
+}
+
+// BackupFileInfo describes a multi-channel backup file on disk.
+type BackupFileInfo struct {
+	// Path is the path of the file.
+	Path string
+
+	// Size is the size of the file in bytes.
+	Size int64
+
+	// ModTime is the time the file was last modified.
+	ModTime time.Time
+
+	// Sha256 is the hash of the file's contents.
+	Sha256 [sha256.Size]byte
+
+	// ChanPoints are the funding outpoints of the channels in the backup.
+	ChanPoints []wire.OutPoint
+
+	// Err is the reason the backup couldn't be decrypted or decoded, or
+	// nil if it's intact.
+	Err error
+}
+
+// InspectBackupFile reads the multi-channel backup file at path, and decrypts
+// it using the keyRing to list the channels it contains. An error is only
+// returned if the file can't be read, while an invalid backup is reported
+// through the Err field of the returned info.
+func InspectBackupFile(path string,
+	keyRing keychain.KeyRing) (*BackupFileInfo, error) {
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	multiBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &BackupFileInfo{
+		Path:    path,
+		Size:    fileInfo.Size(),
+		ModTime: fileInfo.ModTime(),
+		Sha256:  sha256.Sum256(multiBytes),
+	}
+
+	packedMulti := PackedMulti(multiBytes)
+	multi, err := packedMulti.Unpack(keyRing)
+	if err != nil {
+		info.Err = err
+		return info, nil
+	}
+
+	for _, backup := range multi.StaticBackups {
+		info.ChanPoints = append(info.ChanPoints, backup.FundingOutpoint)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,113 @@
+package chanbackup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// failingUploader is an Uploader that fails every upload.
+type failingUploader struct{}
+
+func (f *failingUploader) Name() string {
+	return "failing"
+}
+
+func (f *failingUploader) Upload(newBackup PackedMulti) error {
+	return fmt.Errorf("fail")
+}
+
+// TestExporter tests that the Exporter swaps out the main backup file and
+// copies each backup to its destinations, recording the outcome of each
+// upload, and that the backup files can then be inspected.
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	tempTestDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unable to make temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempTestDir)
+
+	exportDir := filepath.Join(tempTestDir, "export")
+	if err := os.Mkdir(exportDir, 0700); err != nil {
+		t.Fatalf("unable to make export dir: %v", err)
+	}
+
+	mainPath := filepath.Join(tempTestDir, DefaultBackupFileName)
+	exportPath := filepath.Join(exportDir, DefaultBackupFileName)
+	exporter := NewExporter(
+		NewMultiFile(mainPath), NewFileUploader(exportPath),
+		&failingUploader{},
+	)
+
+	// We'll pack a multi containing a single channel, which should end up
+	// in both the main backup file and the exported file.
+	channel, err := genRandomOpenChannelShell()
+	if err != nil {
+		t.Fatalf("unable to make test chan: %v", err)
+	}
+	multi := Multi{
+		StaticBackups: []Single{NewSingle(channel, nil)},
+	}
+	keyRing := &mockKeyRing{}
+	var b bytes.Buffer
+	if err := multi.PackToWriter(&b, keyRing); err != nil {
+		t.Fatalf("unable to pack multi: %v", err)
+	}
+	packedMulti := PackedMulti(b.Bytes())
+
+	// The failing destination shouldn't fail the swap itself.
+	if err := exporter.UpdateAndSwap(packedMulti); err != nil {
+		t.Fatalf("unable to swap backup: %v", err)
+	}
+	assertBackupMatches(t, mainPath, packedMulti)
+	assertBackupMatches(t, exportPath, packedMulti)
+
+	status := exporter.Status()
+	if len(status) != 2 {
+		t.Fatalf("expected 2 destinations, got %v", len(status))
+	}
+	if status[0].Destination != exportPath || status[0].LastErr != nil ||
+		status[0].LastSuccess.IsZero() {
+
+		t.Fatalf("expected successful upload to %v, got %v",
+			exportPath, status[0])
+	}
+	if status[1].LastErr == nil || !status[1].LastSuccess.IsZero() {
+		t.Fatalf("expected failed upload, got %v", status[1])
+	}
+
+	// Inspecting the exported file should reveal the single channel.
+	info, err := InspectBackupFile(exportPath, keyRing)
+	if err != nil {
+		t.Fatalf("unable to inspect backup: %v", err)
+	}
+	if info.Err != nil {
+		t.Fatalf("expected valid backup, got %v", info.Err)
+	}
+	if info.Size != int64(len(packedMulti)) ||
+		info.Sha256 != sha256.Sum256(packedMulti) {
+
+		t.Fatalf("unexpected backup file info: %v", info)
+	}
+	if len(info.ChanPoints) != 1 ||
+		info.ChanPoints[0] != channel.FundingOutpoint {
+
+		t.Fatalf("unexpected channels: %v", info.ChanPoints)
+	}
+
+	// Finally, a backup that can't be decrypted should be reported as
+	// invalid rather than failing the inspection.
+	info, err = InspectBackupFile(exportPath, &mockKeyRing{fail: true})
+	if err != nil {
+		t.Fatalf("unable to inspect backup: %v", err)
+	}
+	if info.Err == nil {
+		t.Fatalf("expected invalid backup")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -165,13 +166,21 @@ func (s *SubSwapper) Stop() error {
 // the SubSwapper.
 func (s *SubSwapper) updateBackupFile() error {
 	// With our updated channel state obtained, we'll create a new multi
-	// from our series of singles.
+	// from our series of singles. The singles are sorted by their funding
+	// outpoint, such that the same set of channels always results in the
+	// same plaintext backup.
 	var newMulti Multi
 	for _, backup := range s.backupState {
 		newMulti.StaticBackups = append(
 			newMulti.StaticBackups, backup,
 		)
 	}
+	sort.Slice(newMulti.StaticBackups, func(i, j int) bool {
+		return outPointLess(
+			newMulti.StaticBackups[i].FundingOutpoint,
+			newMulti.StaticBackups[j].FundingOutpoint,
+		)
+	})
 
 	// Now that our multi has been assembled, we'll attempt to pack
 	// (encrypt+encode) the new channel state to our target reader.
@@ -191,6 +200,17 @@ func (s *SubSwapper) updateBackupFile() error {
 	return nil
 }
 
+// outPointLess returns true if the outpoint a sorts before b, ordering by txid
+// and then by output index.
+func outPointLess(a, b wire.OutPoint) bool {
+	cmp := bytes.Compare(a.Hash[:], b.Hash[:])
+	if cmp != 0 {
+		return cmp < 0
+	}
+
+	return a.Index < b.Index
+}
+
 // backupFileUpdater is the primary goroutine of the SubSwapper which is
 // responsible for listening for changes to the channel, and updating the
 // persistent multi backup state with a new packed multi of the latest channel
@@ -102,13 +102,22 @@ func assertExpectedBackupSwap(t *testing.T, swapper *mockSwapper,
 		}
 
 		// We should also find all the old and new channels in this new
-		// backup.
-		for _, backup := range newMulti.StaticBackups {
+		// backup, sorted by their funding outpoint.
+		for i, backup := range newMulti.StaticBackups {
 			_, ok := expectedChanSet[backup.FundingOutpoint]
 			if !ok {
 				t.Fatalf("didn't find backup in original set: %v",
 					backup.FundingOutpoint)
 			}
+
+			if i == 0 {
+				continue
+			}
+			prevPoint := newMulti.StaticBackups[i-1].FundingOutpoint
+			if !outPointLess(prevPoint, backup.FundingOutpoint) {
+				t.Fatalf("backups not sorted: %v before %v",
+					prevPoint, backup.FundingOutpoint)
+			}
 		}
 
 		// The internal state of the sub-swapper should also be one
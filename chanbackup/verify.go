@@ -0,0 +1,318 @@
+package chanbackup
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// PeerProber is an interface that allows the Verifier to check whether the
+// remote node of a backed up channel can currently be reached, as recovering
+// the channel requires its cooperation.
+type PeerProber interface {
+	// ProbePeer returns a non-nil error if the target node can't be
+	// reached at any of the given addresses.
+	ProbePeer(node *btcec.PublicKey, addrs []net.Addr) error
+}
+
+// TowerCoverage is an interface that allows the Verifier to check whether the
+// revoked states of a channel have been backed up to a watchtower.
+type TowerCoverage interface {
+	// BackedUpHeight returns the highest commit height of the channel's
+	// revoked states that has been acked by a tower. The boolean is false
+	// if none of the channel's states are known to be backed up.
+	BackedUpHeight(chanID lnwire.ChannelID) (uint64, bool)
+}
+
+// VerifierConfig houses the resources required by the Verifier.
+type VerifierConfig struct {
+	// FetchBackup returns the latest multi-channel backup, i.e. the one
+	// that would be used to recover our channels.
+	FetchBackup func() (*Multi, error)
+
+	// ChanSource is used to look up the current state of each backed up
+	// channel.
+	ChanSource LiveChannelSource
+
+	// Prober checks the reachability of the peer of each channel.
+	Prober PeerProber
+
+	// Towers, if non-nil, is consulted to check whether the revoked states
+	// of each channel have been backed up to a watchtower.
+	Towers TowerCoverage
+
+	// Interval is the duration between periodic verifications of the
+	// backup. If zero, the backup is only verified on demand.
+	Interval time.Duration
+}
+
+// ChannelRecoverability describes whether a channel of the backup can be
+// recovered.
+type ChannelRecoverability struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// RemoteNodePub is the identity public key of the channel's peer.
+	RemoteNodePub *btcec.PublicKey
+
+	// ChannelOpen is false if the channel is no longer open, e.g. because
+	// it was closed since the backup was last updated.
+	ChannelOpen bool
+
+	// PeerReachable is true if the channel's peer could be reached at one
+	// of the backed up addresses, or is currently connected.
+	PeerReachable bool
+
+	// PeerErr is the reason the peer couldn't be reached.
+	PeerErr error
+
+	// TowerChecked is true if watchtower coverage of the channel was
+	// checked, which requires an active watchtower client.
+	TowerChecked bool
+
+	// RevokedStates is the number of revoked states of the channel.
+	RevokedStates uint64
+
+	// StatesBehind is the number of the most recent revoked states that
+	// have yet to be acked by a watchtower.
+	StatesBehind uint64
+}
+
+// TowerCovered returns true if all revoked states of the channel are known to
+// be backed up to a watchtower.
+func (c *ChannelRecoverability) TowerCovered() bool {
+	return c.TowerChecked && c.StatesBehind == 0
+}
+
+// RecoverabilityReport is the result of verifying the channels of a backup.
+type RecoverabilityReport struct {
+	// Timestamp is the time at which the backup was verified.
+	Timestamp time.Time
+
+	// Channels describes the recoverability of each channel of the
+	// backup.
+	Channels []ChannelRecoverability
+}
+
+// Verifier verifies that the channels in the latest multi-channel backup can
+// be recovered, by checking that their peers are reachable and that their
+// recent states are covered by watchtower sessions. The backup is verified
+// periodically as well as on demand, such that operators can trust their
+// backups before they need them.
+type Verifier struct {
+	started uint32
+	stopped uint32
+
+	cfg *VerifierConfig
+
+	// verifyMtx serializes verifications, such that periodic and on
+	// demand verifications don't probe the same peers concurrently.
+	verifyMtx sync.Mutex
+
+	reportMtx sync.RWMutex
+	report    *RecoverabilityReport
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewVerifier creates a new Verifier from the given config.
+func NewVerifier(cfg *VerifierConfig) *Verifier {
+	return &Verifier{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start starts the periodic verification of the backup, if an interval has
+// been configured.
+func (v *Verifier) Start() error {
+	if !atomic.CompareAndSwapUint32(&v.started, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Starting chanbackup.Verifier")
+
+	if v.cfg.Interval > 0 {
+		v.wg.Add(1)
+		go v.verifyPeriodically()
+	}
+
+	return nil
+}
+
+// Stop signals the Verifier to stop its periodic verification.
+func (v *Verifier) Stop() error {
+	if !atomic.CompareAndSwapUint32(&v.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Stopping chanbackup.Verifier")
+
+	close(v.quit)
+	v.wg.Wait()
+
+	return nil
+}
+
+// verifyPeriodically verifies the backup once every configured interval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (v *Verifier) verifyPeriodically() {
+	defer v.wg.Done()
+
+	ticker := time.NewTicker(v.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			report, err := v.Verify()
+			if err != nil {
+				log.Errorf("Unable to verify channel "+
+					"backup: %v", err)
+				continue
+			}
+
+			logReport(report)
+
+		case <-v.quit:
+			return
+		}
+	}
+}
+
+// LatestReport returns the report of the most recent verification, or nil if
+// the backup hasn't been verified yet.
+func (v *Verifier) LatestReport() *RecoverabilityReport {
+	v.reportMtx.RLock()
+	defer v.reportMtx.RUnlock()
+
+	return v.report
+}
+
+// Verify verifies the recoverability of each channel in the latest backup,
+// returning the resulting report.
+func (v *Verifier) Verify() (*RecoverabilityReport, error) {
+	v.verifyMtx.Lock()
+	defer v.verifyMtx.Unlock()
+
+	backup, err := v.cfg.FetchBackup()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RecoverabilityReport{
+		Timestamp: time.Now(),
+		Channels: make(
+			[]ChannelRecoverability, 0, len(backup.StaticBackups),
+		),
+	}
+
+	// Peers are only probed once, even if we have several channels with
+	// them.
+	peerErrs := make(map[[33]byte]error)
+
+	for _, single := range backup.StaticBackups {
+		chanReport := ChannelRecoverability{
+			ChanPoint:     single.FundingOutpoint,
+			RemoteNodePub: single.RemoteNodePub,
+		}
+
+		var nodePub [33]byte
+		copy(nodePub[:], single.RemoteNodePub.SerializeCompressed())
+
+		peerErr, ok := peerErrs[nodePub]
+		if !ok {
+			peerErr = v.cfg.Prober.ProbePeer(
+				single.RemoteNodePub, single.Addresses,
+			)
+			peerErrs[nodePub] = peerErr
+		}
+		chanReport.PeerReachable = peerErr == nil
+		chanReport.PeerErr = peerErr
+
+		channel, err := v.cfg.ChanSource.FetchChannel(
+			single.FundingOutpoint,
+		)
+		switch {
+		case err == channeldb.ErrChannelNotFound:
+			report.Channels = append(report.Channels, chanReport)
+			continue
+
+		case err != nil:
+			return nil, err
+		}
+		chanReport.ChannelOpen = true
+
+		// The states preceding the remote party's current commitment
+		// have all been revoked, and should be backed up to a tower.
+		chanReport.RevokedStates = channel.RemoteCommitment.CommitHeight
+		if v.cfg.Towers != nil {
+			chanReport.TowerChecked = true
+			chanReport.StatesBehind = statesBehind(
+				v.cfg.Towers, single.FundingOutpoint,
+				chanReport.RevokedStates,
+			)
+		}
+
+		report.Channels = append(report.Channels, chanReport)
+	}
+
+	v.reportMtx.Lock()
+	v.report = report
+	v.reportMtx.Unlock()
+
+	return report, nil
+}
+
+// statesBehind returns the number of the most recent of the channel's revoked
+// states that the towers have yet to ack.
+func statesBehind(towers TowerCoverage, chanPoint wire.OutPoint,
+	revokedStates uint64) uint64 {
+
+	height, ok := towers.BackedUpHeight(
+		lnwire.NewChanIDFromOutPoint(&chanPoint),
+	)
+	if !ok {
+		return revokedStates
+	}
+
+	if height+1 >= revokedStates {
+		return 0
+	}
+
+	return revokedStates - (height + 1)
+}
+
+// logReport logs a summary of the given report, warning about each channel
+// that may not be recoverable.
+func logReport(report *RecoverabilityReport) {
+	var numUnreachable, numUncovered int
+	for _, c := range report.Channels {
+		if !c.PeerReachable {
+			numUnreachable++
+			log.Warnf("Peer %x of ChannelPoint(%v) is "+
+				"unreachable: %v",
+				c.RemoteNodePub.SerializeCompressed(),
+				c.ChanPoint, c.PeerErr)
+		}
+
+		if c.TowerChecked && !c.TowerCovered() {
+			numUncovered++
+			log.Warnf("Latest %d revoked states of "+
+				"ChannelPoint(%v) aren't backed up to a "+
+				"watchtower", c.StatesBehind, c.ChanPoint)
+		}
+	}
+
+	log.Infof("Verified channel backup of %d channels: %d with "+
+		"unreachable peers, %d not covered by watchtowers",
+		len(report.Channels), numUnreachable, numUncovered)
+}
@@ -0,0 +1,182 @@
+package chanbackup
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+type mockPeerProber struct {
+	unreachable map[[33]byte]struct{}
+
+	numProbes int
+}
+
+func (m *mockPeerProber) ProbePeer(node *btcec.PublicKey,
+	addrs []net.Addr) error {
+
+	m.numProbes++
+
+	var nodeKey [33]byte
+	copy(nodeKey[:], node.SerializeCompressed())
+
+	if _, ok := m.unreachable[nodeKey]; ok {
+		return errors.New("unreachable")
+	}
+
+	return nil
+}
+
+type mockTowerCoverage struct {
+	heights map[lnwire.ChannelID]uint64
+}
+
+func (m *mockTowerCoverage) BackedUpHeight(
+	chanID lnwire.ChannelID) (uint64, bool) {
+
+	height, ok := m.heights[chanID]
+	return height, ok
+}
+
+// TestVerifierReport asserts that the Verifier reports the reachability of the
+// peer of each backed up channel, along with the number of its revoked states
+// that have yet to be backed up to a watchtower.
+func TestVerifierReport(t *testing.T) {
+	t.Parallel()
+
+	// We'll create three channels: a fully covered one with a reachable
+	// peer, one whose peer is unreachable and whose latest states aren't
+	// backed up, and one that has been closed since the backup was made.
+	chanSource := newMockChannelSource()
+	var singles []Single
+	for i := 0; i < 3; i++ {
+		channel, err := genRandomOpenChannelShell()
+		if err != nil {
+			t.Fatalf("unable to generate chan: %v", err)
+		}
+		channel.RemoteCommitment.CommitHeight = uint64(10 * (i + 1))
+
+		if i < 2 {
+			chanSource.chans[channel.FundingOutpoint] = channel
+		}
+
+		singles = append(singles, NewSingle(channel, []net.Addr{addr1}))
+	}
+
+	var unreachable [33]byte
+	copy(unreachable[:], singles[1].RemoteNodePub.SerializeCompressed())
+	prober := &mockPeerProber{
+		unreachable: map[[33]byte]struct{}{
+			unreachable: {},
+		},
+	}
+
+	chanID := func(i int) lnwire.ChannelID {
+		return lnwire.NewChanIDFromOutPoint(&singles[i].FundingOutpoint)
+	}
+	towers := &mockTowerCoverage{
+		heights: map[lnwire.ChannelID]uint64{
+			chanID(0): 9,
+			chanID(1): 14,
+		},
+	}
+
+	cfg := &VerifierConfig{
+		FetchBackup: func() (*Multi, error) {
+			return &Multi{StaticBackups: singles}, nil
+		},
+		ChanSource: chanSource,
+		Prober:     prober,
+		Towers:     towers,
+	}
+	verifier := NewVerifier(cfg)
+
+	if verifier.LatestReport() != nil {
+		t.Fatalf("expected no report before verification")
+	}
+
+	report, err := verifier.Verify()
+	if err != nil {
+		t.Fatalf("unable to verify backup: %v", err)
+	}
+	if verifier.LatestReport() != report {
+		t.Fatalf("latest report not updated")
+	}
+
+	expReports := []struct {
+		open          bool
+		reachable     bool
+		revokedStates uint64
+		statesBehind  uint64
+		covered       bool
+	}{
+		{
+			open:          true,
+			reachable:     true,
+			revokedStates: 10,
+			statesBehind:  0,
+			covered:       true,
+		},
+		{
+			open:          true,
+			reachable:     false,
+			revokedStates: 20,
+			statesBehind:  5,
+			covered:       false,
+		},
+		{
+			open:      false,
+			reachable: true,
+		},
+	}
+	if len(report.Channels) != len(expReports) {
+		t.Fatalf("expected %d channels, got %d", len(expReports),
+			len(report.Channels))
+	}
+
+	for i, exp := range expReports {
+		c := report.Channels[i]
+		if c.ChanPoint != singles[i].FundingOutpoint {
+			t.Fatalf("#%d: unexpected chan point %v", i,
+				c.ChanPoint)
+		}
+		if c.ChannelOpen != exp.open {
+			t.Fatalf("#%d: expected open=%v", i, exp.open)
+		}
+		if c.PeerReachable != exp.reachable {
+			t.Fatalf("#%d: expected reachable=%v", i,
+				exp.reachable)
+		}
+		if c.PeerReachable != (c.PeerErr == nil) {
+			t.Fatalf("#%d: unexpected peer error: %v", i,
+				c.PeerErr)
+		}
+		if c.RevokedStates != exp.revokedStates {
+			t.Fatalf("#%d: expected %d revoked states, got %d",
+				i, exp.revokedStates, c.RevokedStates)
+		}
+		if c.StatesBehind != exp.statesBehind {
+			t.Fatalf("#%d: expected %d states behind, got %d", i,
+				exp.statesBehind, c.StatesBehind)
+		}
+		if c.TowerCovered() != exp.covered {
+			t.Fatalf("#%d: expected covered=%v", i, exp.covered)
+		}
+	}
+
+	// Without an active watchtower client, tower coverage shouldn't be
+	// checked at all.
+	cfg.Towers = nil
+	report, err = NewVerifier(cfg).Verify()
+	if err != nil {
+		t.Fatalf("unable to verify backup: %v", err)
+	}
+	for i, c := range report.Channels {
+		if c.TowerChecked || c.TowerCovered() {
+			t.Fatalf("#%d: tower coverage checked", i)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package lnd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/litecoinfinance/btcd/txscript"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnrpc"
+	"github.com/litecoinfinance/lnd/lnwallet"
+)
+
+// errBatchAborted is the reason the funding flows of a channel batch are
+// failed with, once any of them failed.
+var errBatchAborted = errors.New("channel batch aborted")
+
+// batchChannel tracks the funding flow of a single channel of a batch.
+type batchChannel struct {
+	updates chan *lnrpc.OpenStatusUpdate
+	err     chan error
+
+	// pendingChanID is the pending channel ID of the funding flow, which
+	// is known once the PSBT funding template of the channel arrived.
+	pendingChanID *[32]byte
+
+	// fundingOutput is the funding output of the channel.
+	fundingOutput *wire.TxOut
+
+	// chanPoint is the funding outpoint of the channel, which is set once
+	// the channel is pending.
+	chanPoint *wire.OutPoint
+}
+
+// BatchOpenChannels opens a channel for each of the passed requests, funded by
+// a single transaction of the wallet, paying the given fee rate, whose coins
+// are selected according to the given strategy. The funding flows are
+// coordinated such that the transaction is only published once all channels
+// are pending, which means every peer signed our version of the commitment
+// transaction. If any of the flows fail, all of them are aborted. The funding
+// outpoints of the channels are returned in the order of the requests.
+func (s *server) BatchOpenChannels(reqs []*openChanReq,
+	feeRate lnwallet.SatPerKWeight, minConfs int32,
+	strategy lnwallet.CoinSelectionStrategy) ([]*wire.OutPoint, error) {
+
+	// Each channel is funded externally, by the batch transaction, which
+	// we'll publish ourselves.
+	channels := make([]*batchChannel, len(reqs))
+	for i, req := range reqs {
+		req.psbtFunding = true
+		req.noFundingTx = true
+		req.fundingFeePerKw = feeRate
+
+		updates, errChan := s.OpenChannel(req)
+		channels[i] = &batchChannel{
+			updates: updates,
+			err:     errChan,
+		}
+	}
+
+	// We'll wait for the funding template of every channel, or its flow
+	// to fail, before proceeding. A peer that stops responding will have
+	// its reservation pruned by the funding manager eventually.
+	var batchErr error
+	for _, channel := range channels {
+		err := s.waitForBatchTemplate(channel)
+		if err != nil && batchErr == nil {
+			batchErr = err
+		}
+	}
+	if batchErr != nil {
+		s.abortChannelBatch(channels, nil)
+		return nil, batchErr
+	}
+
+	outputs := make([]*wire.TxOut, len(channels))
+	for i, channel := range channels {
+		outputs[i] = channel.fundingOutput
+	}
+	batchTx, err := s.cc.wallet.FundBatchTx(
+		outputs, feeRate, minConfs, strategy,
+	)
+	if err != nil {
+		s.abortChannelBatch(channels, nil)
+		return nil, err
+	}
+
+	// With the batch transaction signed, its txid is final, which allows
+	// each flow to proceed to the exchange of commitment signatures.
+	for _, channel := range channels {
+		err := s.fundingMgr.ProcessPsbtFinalize(
+			*channel.pendingChanID, batchTx,
+		)
+		if err != nil {
+			s.abortChannelBatch(channels, batchTx)
+			return nil, err
+		}
+	}
+
+	for _, channel := range channels {
+		err := s.waitForBatchPending(channel)
+		if err != nil && batchErr == nil {
+			batchErr = err
+		}
+	}
+	if batchErr != nil {
+		s.abortChannelBatch(channels, batchTx)
+		return nil, batchErr
+	}
+
+	srvrLog.Infof("Publishing batch tx %v funding %v channels",
+		batchTx.TxHash(), len(channels))
+
+	// All channels are pending now, so the batch transaction can safely
+	// be published. If this fails, we'll keep the channels regardless, in
+	// case the transaction made it to the network.
+	if err := s.cc.wallet.PublishTransaction(batchTx); err != nil {
+		return nil, fmt.Errorf("unable to publish batch tx %v: %v",
+			batchTx.TxHash(), err)
+	}
+
+	chanPoints := make([]*wire.OutPoint, len(channels))
+	for i, channel := range channels {
+		chanPoints[i] = channel.chanPoint
+	}
+
+	return chanPoints, nil
+}
+
+// waitForBatchTemplate waits for the PSBT funding template of the channel,
+// from which its pending channel ID and funding output are extracted.
+func (s *server) waitForBatchTemplate(channel *batchChannel) error {
+	var template *lnrpc.ReadyForPsbtFunding
+	select {
+	case upd := <-channel.updates:
+		psbtFund, ok := upd.Update.(*lnrpc.OpenStatusUpdate_PsbtFund)
+		if !ok {
+			return fmt.Errorf("unexpected funding update %T",
+				upd.Update)
+		}
+		template = psbtFund.PsbtFund
+
+	case err := <-channel.err:
+		return err
+
+	case <-s.quit:
+		return ErrServerShuttingDown
+	}
+
+	var pendingChanID [32]byte
+	copy(pendingChanID[:], template.PendingChanId)
+	channel.pendingChanID = &pendingChanID
+
+	fundingAddr, err := btcutil.DecodeAddress(
+		template.FundingAddress, activeNetParams.Params,
+	)
+	if err != nil {
+		return err
+	}
+	pkScript, err := txscript.PayToAddrScript(fundingAddr)
+	if err != nil {
+		return err
+	}
+	channel.fundingOutput = &wire.TxOut{
+		Value:    template.FundingAmount,
+		PkScript: pkScript,
+	}
+
+	return nil
+}
+
+// waitForBatchPending waits for the channel to be committed to disk, after
+// which it's pending the confirmation of the batch transaction.
+func (s *server) waitForBatchPending(channel *batchChannel) error {
+	select {
+	case upd := <-channel.updates:
+		channel.chanPoint = pendingChanPoint(upd)
+		if channel.chanPoint == nil {
+			return fmt.Errorf("unexpected funding update %T",
+				upd.Update)
+		}
+		return nil
+
+	case err := <-channel.err:
+		return err
+
+	case <-s.quit:
+		return ErrServerShuttingDown
+	}
+}
+
+// abortChannelBatch fails the funding flows of all channels of a batch that
+// are still in progress, and abandons those that were already committed to
+// disk. As the batch transaction is never published, any coins it spends are
+// released again.
+func (s *server) abortChannelBatch(channels []*batchChannel,
+	batchTx *wire.MsgTx) {
+
+	for _, channel := range channels {
+		if channel.pendingChanID == nil {
+			continue
+		}
+
+		// If the reservation of the channel no longer exists, its
+		// flow either failed, or completed, in which case the pending
+		// update will be waiting for us.
+		if channel.chanPoint == nil {
+			err := s.fundingMgr.CancelReservation(
+				*channel.pendingChanID, errBatchAborted,
+			)
+			if err == nil {
+				continue
+			}
+
+			select {
+			case upd := <-channel.updates:
+				channel.chanPoint = pendingChanPoint(upd)
+			default:
+			}
+			if channel.chanPoint == nil {
+				continue
+			}
+		}
+
+		if err := s.abandonBatchChannel(channel.chanPoint); err != nil {
+			srvrLog.Errorf("Unable to abandon ChannelPoint(%v) of "+
+				"aborted batch: %v", channel.chanPoint, err)
+		}
+	}
+
+	if batchTx != nil {
+		s.cc.wallet.ReleaseBatchTx(batchTx)
+	}
+}
+
+// abandonBatchChannel closes the pending channel of an aborted batch within
+// the database, as its funding transaction will never confirm.
+func (s *server) abandonBatchChannel(chanPoint *wire.OutPoint) error {
+	dbChan, err := s.chanDB.FetchChannel(*chanPoint)
+	if err != nil {
+		return err
+	}
+
+	_, bestHeight, err := s.cc.chainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	srvrLog.Infof("Abandoning ChannelPoint(%v) of aborted batch",
+		chanPoint)
+
+	localBalance := dbChan.LocalCommitment.LocalBalance.ToSatoshis()
+	return dbChan.CloseChannel(&channeldb.ChannelCloseSummary{
+		CloseType:               channeldb.FundingCanceled,
+		ChanPoint:               *chanPoint,
+		ChainHash:               dbChan.ChainHash,
+		CloseHeight:             uint32(bestHeight),
+		RemotePub:               dbChan.IdentityPub,
+		Capacity:                dbChan.Capacity,
+		SettledBalance:          localBalance,
+		RemoteCurrentRevocation: dbChan.RemoteCurrentRevocation,
+		RemoteNextRevocation:    dbChan.RemoteNextRevocation,
+		LocalChanConfig:         dbChan.LocalChanCfg,
+	})
+}
+
+// pendingChanPoint returns the funding outpoint of the channel a ChanPending
+// update refers to, or nil if the update is of another kind.
+func pendingChanPoint(upd *lnrpc.OpenStatusUpdate) *wire.OutPoint {
+	pending, ok := upd.Update.(*lnrpc.OpenStatusUpdate_ChanPending)
+	if !ok {
+		return nil
+	}
+
+	var chanPoint wire.OutPoint
+	copy(chanPoint.Hash[:], pending.ChanPending.Txid)
+	chanPoint.Index = pending.ChanPending.OutputIndex
+
+	return &chanPoint
+}
@@ -2,6 +2,7 @@ package lnd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/litecoinfinance/btcd/txscript"
 	"github.com/litecoinfinance/btcd/wire"
@@ -25,6 +26,16 @@ var (
 	// ErrInvalidState is returned when the closing state machine receives
 	// a message while it is in an unknown state.
 	ErrInvalidState = fmt.Errorf("invalid state")
+
+	// ErrCloseFeeOutOfRange is returned when the remote party keeps
+	// proposing a closing fee outside of our acceptable range, while we've
+	// already conceded as far as that range allows.
+	ErrCloseFeeOutOfRange = fmt.Errorf("remote closing fee outside of " +
+		"acceptable range")
+
+	// ErrInvalidDeliveryScript is returned when the remote party asks to
+	// have its funds sent to a non-standard script.
+	ErrInvalidDeliveryScript = fmt.Errorf("invalid delivery script")
 )
 
 // closeState represents all the possible states the channel closer state
@@ -82,6 +93,15 @@ type chanCloseCfg struct {
 	// forward payments.
 	disableChannel func(wire.OutPoint) error
 
+	// closeSigner is an optional external signer which produces our
+	// signatures for the closing transaction. If nil, the channel's
+	// regular signer is used.
+	closeSigner lnwallet.CloseSigner
+
+	// closeSigTimeout is the duration we'll wait for the closeSigner to
+	// deliver a signature before failing the negotiation.
+	closeSigTimeout time.Duration
+
 	// quit is a channel that should be sent upon in the occasion the state
 	// machine should cease all progress and shutdown.
 	quit chan struct{}
@@ -116,6 +136,12 @@ type channelCloser struct {
 	// offer when starting negotiation. This will be used as a baseline.
 	idealFeeSat btcutil.Amount
 
+	// minFeeSat and maxFeeSat bound the closing fees that we'll propose
+	// and accept during negotiation. A zero maxFeeSat means that the
+	// negotiation isn't bounded from above.
+	minFeeSat btcutil.Amount
+	maxFeeSat btcutil.Amount
+
 	// lastFeeProposal is the last fee that we proposed to the remote
 	// party. We'll use this as a pivot point to rachet our next offer up,
 	// or down, or simply accept the remote party's prior offer.
@@ -173,6 +199,20 @@ func newChannelCloser(cfg chanCloseCfg, deliveryScript []byte,
 		idealFeeSat = channelCommitFee
 	}
 
+	// If we're the initiator of the closure, the caller may have
+	// restricted the range of fees it's willing to pay. Our ideal fee
+	// must then lie within that range.
+	var minFeeSat, maxFeeSat btcutil.Amount
+	if closeReq != nil {
+		if closeReq.MinFeePerKw != 0 {
+			minFeeSat = cfg.channel.CalcFee(closeReq.MinFeePerKw)
+		}
+		if closeReq.MaxFeePerKw != 0 {
+			maxFeeSat = cfg.channel.CalcFee(closeReq.MaxFeePerKw)
+		}
+	}
+	idealFeeSat = clampFee(idealFeeSat, minFeeSat, maxFeeSat)
+
 	peerLog.Infof("Ideal fee for closure of ChannelPoint(%v) is: %v sat",
 		cfg.channel.ChannelPoint(), int64(idealFeeSat))
 
@@ -185,6 +225,8 @@ func newChannelCloser(cfg chanCloseCfg, deliveryScript []byte,
 		cfg:                 cfg,
 		negotiationHeight:   negotiationHeight,
 		idealFeeSat:         idealFeeSat,
+		minFeeSat:           minFeeSat,
+		maxFeeSat:           maxFeeSat,
 		localDeliveryScript: deliveryScript,
 		priorFeeOffers:      make(map[btcutil.Amount]*lnwire.ClosingSigned),
 	}
@@ -285,6 +327,9 @@ func (c *channelCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message, b
 		// Next, we'll note the other party's preference for their
 		// delivery address. We'll use this when we craft the closure
 		// transaction.
+		if !isStandardDeliveryScript(shutDownMsg.Address) {
+			return nil, false, ErrInvalidDeliveryScript
+		}
 		c.remoteDeliveryScript = shutDownMsg.Address
 
 		// We'll generate a shutdown message of our own to send across
@@ -334,6 +379,9 @@ func (c *channelCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message, b
 
 		// Now that we know this is a valid shutdown message, we'll
 		// record their preferred delivery closing script.
+		if !isStandardDeliveryScript(shutDownMsg.Address) {
+			return nil, false, ErrInvalidDeliveryScript
+		}
 		c.remoteDeliveryScript = shutDownMsg.Address
 
 		// At this point, we can now start the fee negotiation state,
@@ -380,10 +428,27 @@ func (c *channelCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message, b
 			// We'll now attempt to rachet towards a fee deemed
 			// acceptable by both parties, factoring in our ideal
 			// fee rate, and the last proposed fee by both sides.
+			// If we were given an explicit fee range, any offer
+			// within it is good enough to end the negotiation.
 			feeProposal := calcCompromiseFee(c.chanPoint,
 				c.idealFeeSat, c.lastFeeProposal,
 				remoteProposedFee,
 			)
+			feeProposal = clampFee(
+				feeProposal, c.minFeeSat, c.maxFeeSat,
+			)
+			if c.feeInRange(remoteProposedFee) {
+				feeProposal = remoteProposedFee
+			}
+
+			// If we can't move any closer to their offer, then
+			// the negotiation would never terminate, so we'll
+			// bail out instead.
+			if len(c.priorFeeOffers) != 0 &&
+				feeProposal == c.lastFeeProposal {
+
+				return nil, false, ErrCloseFeeOutOfRange
+			}
 
 			// With our new fee proposal calculated, we'll craft a
 			// new close signed signature to send to the other
@@ -482,9 +547,20 @@ func (c *channelCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message, b
 // current compromise fee.
 func (c *channelCloser) proposeCloseSigned(fee btcutil.Amount) (*lnwire.ClosingSigned, error) {
 
-	rawSig, _, _, err := c.cfg.channel.CreateCloseProposal(
-		fee, c.localDeliveryScript, c.remoteDeliveryScript,
+	var (
+		rawSig []byte
+		err    error
 	)
+	if c.cfg.closeSigner != nil {
+		rawSig, _, _, err = c.cfg.channel.CreateCloseProposalExternal(
+			fee, c.localDeliveryScript, c.remoteDeliveryScript,
+			c.cfg.closeSigner, c.cfg.closeSigTimeout, c.cfg.quit,
+		)
+	} else {
+		rawSig, _, _, err = c.cfg.channel.CreateCloseProposal(
+			fee, c.localDeliveryScript, c.remoteDeliveryScript,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -513,6 +589,44 @@ func (c *channelCloser) proposeCloseSigned(fee btcutil.Amount) (*lnwire.ClosingS
 	return closeSignedMsg, nil
 }
 
+// feeInRange returns true if the passed fee lies within the explicit fee range
+// of the closing request. If no such range was given, then false is returned.
+func (c *channelCloser) feeInRange(fee btcutil.Amount) bool {
+	if c.minFeeSat == 0 && c.maxFeeSat == 0 {
+		return false
+	}
+
+	return fee >= c.minFeeSat && (c.maxFeeSat == 0 || fee <= c.maxFeeSat)
+}
+
+// clampFee returns the passed fee bounded by the given minimum and maximum. A
+// zero maximum leaves the fee unbounded from above.
+func clampFee(fee, minFee, maxFee btcutil.Amount) btcutil.Amount {
+	if fee < minFee {
+		fee = minFee
+	}
+	if maxFee != 0 && fee > maxFee {
+		fee = maxFee
+	}
+
+	return fee
+}
+
+// isStandardDeliveryScript returns true if the passed script is one of the
+// script types a delivery address is allowed to take on: p2pkh, p2sh, p2wkh or
+// p2wsh.
+func isStandardDeliveryScript(script []byte) bool {
+	switch txscript.GetScriptClass(script) {
+	case txscript.PubKeyHashTy, txscript.ScriptHashTy,
+		txscript.WitnessV0PubKeyHashTy, txscript.WitnessV0ScriptHashTy:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
 // feeInAcceptableRange returns true if the passed remote fee is deemed to be
 // in an "acceptable" range to our local fee. This is an attempt at a
 // compromise and to ensure that the fee negotiation has a stopping point. We
@@ -537,13 +651,20 @@ func feeInAcceptableRange(localFee, remoteFee btcutil.Amount) bool {
 // offered fee. Otherwise, if up is false, then we'll attempt to decrease our
 // offered fee.
 func rachetFee(fee btcutil.Amount, up bool) btcutil.Amount {
+	// We'll move our fee by 10%, but always by at least a single satoshi
+	// to ensure that small fees still converge.
+	step := (fee * 1) / 10
+	if step == 0 {
+		step = 1
+	}
+
 	// If we need to rachet up, then we'll increase our fee by 10%.
 	if up {
-		return fee + ((fee * 1) / 10)
+		return fee + step
 	}
 
 	// Otherwise, we'll *decrease* our fee by 10%.
-	return fee - ((fee * 1) / 10)
+	return fee - step
 }
 
 // calcCompromiseFee performs the current fee negotiation algorithm, taking
@@ -0,0 +1,70 @@
+package lnd
+
+import (
+	"testing"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+)
+
+// TestCompromiseFeeConverges asserts that the fee negotiation always makes
+// progress towards the offer of the remote party, even for tiny fees, and
+// that it never leaves the bounds of the fee range.
+func TestCompromiseFeeConverges(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		lastSent  btcutil.Amount
+		remoteFee btcutil.Amount
+		minFee    btcutil.Amount
+		maxFee    btcutil.Amount
+		expected  btcutil.Amount
+	}{
+		// A fee of less than 10 satoshis still moves by one satoshi.
+		{lastSent: 5, remoteFee: 50, expected: 6},
+		{lastSent: 5, remoteFee: 1, expected: 4},
+
+		// Larger fees move by 10%.
+		{lastSent: 1000, remoteFee: 5000, expected: 1100},
+		{lastSent: 1000, remoteFee: 100, expected: 900},
+
+		// Offers close enough to our last one are accepted.
+		{lastSent: 1000, remoteFee: 1200, expected: 1200},
+
+		// The compromise is clamped to the fee range.
+		{
+			lastSent: 1000, remoteFee: 5000, maxFee: 1050,
+			expected: 1050,
+		},
+		{
+			lastSent: 1000, remoteFee: 100, minFee: 950,
+			expected: 950,
+		},
+	}
+
+	for i, test := range testCases {
+		fee := calcCompromiseFee(
+			wire.OutPoint{}, 2000, test.lastSent, test.remoteFee,
+		)
+		fee = clampFee(fee, test.minFee, test.maxFee)
+		if fee != test.expected {
+			t.Fatalf("test #%d: expected fee %v, got %v", i,
+				test.expected, fee)
+		}
+	}
+}
+
+// TestStandardDeliveryScript asserts that only the script types allowed for
+// delivery addresses are accepted.
+func TestStandardDeliveryScript(t *testing.T) {
+	t.Parallel()
+
+	p2wkh := append([]byte{0x00, 0x14}, make([]byte, 20)...)
+	if !isStandardDeliveryScript(p2wkh) {
+		t.Fatalf("expected p2wkh script to be accepted")
+	}
+
+	if isStandardDeliveryScript(dummyDeliveryScript) {
+		t.Fatalf("expected non-standard script to be rejected")
+	}
+}
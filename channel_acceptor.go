@@ -0,0 +1,125 @@
+package lnd
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+const (
+	// channelAcceptTimeout is the time we'll wait for a client of the
+	// ChannelAcceptor RPC to pick up, and respond to, a request of a peer
+	// to open a channel, before rejecting it.
+	channelAcceptTimeout = time.Minute
+)
+
+// channelAcceptRequest is a request of a peer to open a channel with us.
+type channelAcceptRequest struct {
+	// nodePubKey is the identity key of the peer.
+	nodePubKey *btcec.PublicKey
+
+	// openChan is the OpenChannel message of the peer, carrying the
+	// parameters of the proposed channel.
+	openChan *lnwire.OpenChannel
+
+	// anchors indicates that the channel would use anchor outputs.
+	anchors bool
+
+	// resp is the channel over which the decision on the request is
+	// delivered.
+	//
+	// NOTE: This channel MUST be buffered.
+	resp chan *channelAcceptResponse
+}
+
+// channelAcceptResponse is the decision on a channelAcceptRequest. Each of the
+// constraints we require of the peer overrides our default if non-zero.
+type channelAcceptResponse struct {
+	// accept indicates whether we accept the channel.
+	accept bool
+
+	// reason is the reason sent to the peer if the channel is rejected.
+	reason string
+
+	remoteCsvDelay    uint16
+	remoteChanReserve btcutil.Amount
+	remoteMaxValue    lnwire.MilliSatoshi
+	remoteMaxHtlcs    uint16
+	remoteMinHtlc     lnwire.MilliSatoshi
+
+	// minAcceptDepth is the number of confirmations we require before the
+	// channel is open.
+	minAcceptDepth uint16
+}
+
+// channelAcceptor hands inbound channel requests to the clients of the
+// ChannelAcceptor RPC, who decide whether we accept them. Channels are only
+// subject to a decision while a client is connected, otherwise they're
+// accepted with our default constraints.
+type channelAcceptor struct {
+	clients int32 // To be used atomically.
+
+	requests chan *channelAcceptRequest
+	timeout  time.Duration
+	quit     <-chan struct{}
+}
+
+// newChannelAcceptor creates a new channelAcceptor which gives its clients the
+// passed timeout to decide on each request.
+func newChannelAcceptor(timeout time.Duration,
+	quit <-chan struct{}) *channelAcceptor {
+
+	return &channelAcceptor{
+		requests: make(chan *channelAcceptRequest),
+		timeout:  timeout,
+		quit:     quit,
+	}
+}
+
+// addClient registers a client that decides on requests, after which they're
+// no longer accepted by default.
+func (a *channelAcceptor) addClient() {
+	atomic.AddInt32(&a.clients, 1)
+}
+
+// removeClient unregisters a client added by addClient.
+func (a *channelAcceptor) removeClient() {
+	atomic.AddInt32(&a.clients, -1)
+}
+
+// Accept blocks until a client decided on the given request, returning the
+// decision. If no client is connected, the channel is accepted right away.
+func (a *channelAcceptor) Accept(
+	req *channelAcceptRequest) *channelAcceptResponse {
+
+	if atomic.LoadInt32(&a.clients) == 0 {
+		return &channelAcceptResponse{accept: true}
+	}
+
+	reject := &channelAcceptResponse{
+		reason: "channel acceptor unavailable",
+	}
+
+	req.resp = make(chan *channelAcceptResponse, 1)
+	timeout := time.After(a.timeout)
+
+	select {
+	case a.requests <- req:
+	case <-timeout:
+		return reject
+	case <-a.quit:
+		return reject
+	}
+
+	select {
+	case resp := <-req.resp:
+		return resp
+	case <-timeout:
+		return reject
+	case <-a.quit:
+		return reject
+	}
+}
@@ -0,0 +1,203 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+var (
+	// channelAuditLogBucket is the top-level bucket that stores the audit
+	// logs of the channel state machines. Each channel has a nested bucket
+	// keyed by its channel point, within which the events are keyed by
+	// their sequence number.
+	//
+	// maps: chanPoint -> seq -> event
+	channelAuditLogBucket = []byte("channel-audit-log")
+)
+
+// ChannelAuditEvent is an entry within the audit log of a channel. Each event
+// records a channel state update message exchanged with the remote peer:
+// HTLC adds, settles and fails, fee updates, commitment signatures and
+// revocations.
+type ChannelAuditEvent struct {
+	// Seq is the sequence number of the event within the audit log of its
+	// channel, starting at 1. It's assigned once the event is added.
+	Seq uint64
+
+	// Timestamp is the time at which the message was sent or received.
+	Timestamp time.Time
+
+	// Outgoing is true if the message was sent to the remote peer, and
+	// false if it was received from them.
+	Outgoing bool
+
+	// Msg is the state update message that was exchanged.
+	Msg lnwire.Message
+}
+
+// IsAuditedMsg returns whether the message is a channel state update that is
+// recorded within the audit log of a channel.
+func IsAuditedMsg(msg lnwire.Message) bool {
+	switch msg.(type) {
+	case *lnwire.UpdateAddHTLC,
+		*lnwire.UpdateFulfillHTLC,
+		*lnwire.UpdateFailHTLC,
+		*lnwire.UpdateFailMalformedHTLC,
+		*lnwire.UpdateFee,
+		*lnwire.CommitSig,
+		*lnwire.RevokeAndAck:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
+// ChannelAuditLog returns an instance of the ChannelAuditLog backed by the
+// target database instance.
+func (d *DB) ChannelAuditLog() *ChannelAuditLog {
+	return &ChannelAuditLog{
+		db: d,
+	}
+}
+
+// ChannelAuditLog is an append-only log of the state update messages each
+// channel exchanged with its remote peer. As the messages are stored in full,
+// including their signatures and revealed secrets, the evolution of the
+// state of a channel can be reconstructed from its log. The log of a channel
+// is retained after it's closed.
+type ChannelAuditLog struct {
+	db *DB
+}
+
+// AddEvent appends the event to the audit log of the channel, assigning its
+// sequence number.
+func (a *ChannelAuditLog) AddEvent(chanPoint *wire.OutPoint,
+	event *ChannelAuditEvent) error {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := encodeChannelAuditEvent(&b, event); err != nil {
+		return err
+	}
+
+	return a.db.Batch(func(tx kvdb.Tx) error {
+		auditLogs, err := tx.CreateBucketIfNotExists(
+			channelAuditLogBucket,
+		)
+		if err != nil {
+			return err
+		}
+		chanLog, err := auditLogs.CreateBucketIfNotExists(
+			chanKey.Bytes(),
+		)
+		if err != nil {
+			return err
+		}
+
+		seq, err := chanLog.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var seqKey [8]byte
+		byteOrder.PutUint64(seqKey[:], seq)
+		if err := chanLog.Put(seqKey[:], b.Bytes()); err != nil {
+			return err
+		}
+
+		event.Seq = seq
+		return nil
+	})
+}
+
+// FetchEvents returns up to maxEvents events from the audit log of the
+// channel, starting at the given sequence number. The events are ordered by
+// sequence number, and none are returned if the channel has no audit log.
+func (a *ChannelAuditLog) FetchEvents(chanPoint *wire.OutPoint,
+	startSeq uint64, maxEvents uint32) ([]ChannelAuditEvent, error) {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return nil, err
+	}
+
+	var events []ChannelAuditEvent
+	err := a.db.View(func(tx kvdb.Tx) error {
+		auditLogs := tx.Bucket(channelAuditLogBucket)
+		if auditLogs == nil {
+			return nil
+		}
+		chanLog := auditLogs.Bucket(chanKey.Bytes())
+		if chanLog == nil {
+			return nil
+		}
+
+		var startKey [8]byte
+		byteOrder.PutUint64(startKey[:], startSeq)
+
+		c := chanLog.Cursor()
+		for k, v := c.Seek(startKey[:]); k != nil; k, v = c.Next() {
+			if uint32(len(events)) >= maxEvents {
+				break
+			}
+
+			event, err := decodeChannelAuditEvent(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			event.Seq = byteOrder.Uint64(k)
+
+			events = append(events, *event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// encodeChannelAuditEvent writes out the event to the passed io.Writer. The
+// sequence number isn't serialized, as it's the key of the event.
+func encodeChannelAuditEvent(w io.Writer, e *ChannelAuditEvent) error {
+	err := WriteElements(w, uint64(e.Timestamp.UnixNano()), e.Outgoing)
+	if err != nil {
+		return err
+	}
+
+	_, err = lnwire.WriteMessage(w, e.Msg, 0)
+	return err
+}
+
+// decodeChannelAuditEvent reads an event from the passed io.Reader.
+func decodeChannelAuditEvent(r io.Reader) (*ChannelAuditEvent, error) {
+	var (
+		timestamp uint64
+		e         ChannelAuditEvent
+	)
+	if err := ReadElements(r, &timestamp, &e.Outgoing); err != nil {
+		return nil, err
+	}
+	e.Timestamp = time.Unix(0, int64(timestamp))
+
+	msg, err := lnwire.ReadMessage(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	e.Msg = msg
+
+	return &e, nil
+}
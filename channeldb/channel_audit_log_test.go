@@ -0,0 +1,116 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestChannelAuditLog tests that the events added to the audit log of a
+// channel are returned in order, separately from those of other channels.
+func TestChannelAuditLog(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+	auditLog := db.ChannelAuditLog()
+
+	chanPoint := &wire.OutPoint{Index: 1}
+	otherChanPoint := &wire.OutPoint{Index: 2}
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+
+	// No events should be returned for a channel without an audit log.
+	events, err := auditLog.FetchEvents(chanPoint, 1, 10)
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events, got %v", len(events))
+	}
+
+	// Use single second precision to avoid false positive test failures
+	// due to the monotonic time component.
+	now := time.Unix(time.Now().Unix(), 0)
+	expectedEvents := []ChannelAuditEvent{
+		{
+			Timestamp: now,
+			Outgoing:  true,
+			Msg: &lnwire.UpdateAddHTLC{
+				ChanID:      chanID,
+				ID:          0,
+				Amount:      1000,
+				PaymentHash: [32]byte{1},
+				Expiry:      144,
+			},
+		},
+		{
+			Timestamp: now.Add(time.Second),
+			Outgoing:  true,
+			Msg: &lnwire.CommitSig{
+				ChanID:   chanID,
+				HtlcSigs: []lnwire.Sig{{2}},
+			},
+		},
+		{
+			Timestamp: now.Add(2 * time.Second),
+			Msg: &lnwire.UpdateFulfillHTLC{
+				ChanID:          chanID,
+				ID:              0,
+				PaymentPreimage: [32]byte{3},
+			},
+		},
+	}
+	for i := range expectedEvents {
+		err := auditLog.AddEvent(chanPoint, &expectedEvents[i])
+		if err != nil {
+			t.Fatalf("unable to add event: %v", err)
+		}
+		if expectedEvents[i].Seq != uint64(i+1) {
+			t.Fatalf("expected sequence number %v, got %v", i+1,
+				expectedEvents[i].Seq)
+		}
+	}
+
+	// An event of another channel shouldn't affect the sequence numbers
+	// or events of the first.
+	otherEvent := &ChannelAuditEvent{
+		Timestamp: now,
+		Msg: &lnwire.UpdateFailHTLC{
+			ChanID: lnwire.NewChanIDFromOutPoint(otherChanPoint),
+			Reason: lnwire.OpaqueReason{4},
+		},
+	}
+	if err := auditLog.AddEvent(otherChanPoint, otherEvent); err != nil {
+		t.Fatalf("unable to add event: %v", err)
+	}
+	if otherEvent.Seq != 1 {
+		t.Fatalf("expected sequence number 1, got %v", otherEvent.Seq)
+	}
+
+	events, err = auditLog.FetchEvents(chanPoint, 1, 10)
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if !reflect.DeepEqual(events, expectedEvents) {
+		t.Fatalf("expected events %v, got %v",
+			spew.Sdump(expectedEvents), spew.Sdump(events))
+	}
+
+	// The range of events returned should be bounded by the start
+	// sequence number and the maximum number of events.
+	events, err = auditLog.FetchEvents(chanPoint, 2, 1)
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if !reflect.DeepEqual(events, expectedEvents[1:2]) {
+		t.Fatalf("expected events %v, got %v",
+			spew.Sdump(expectedEvents[1:2]), spew.Sdump(events))
+	}
+}
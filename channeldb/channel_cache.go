@@ -48,3 +48,15 @@ func (c *channelCache) insert(chanid uint64, channel ChannelEdge) {
 func (c *channelCache) remove(chanid uint64) {
 	delete(c.channels, chanid)
 }
+
+// resize changes the maximum capacity of the cache to n channels, evicting
+// channels at random until the cache fits within its new capacity.
+func (c *channelCache) resize(n int) {
+	c.n = n
+	for id := range c.channels {
+		if len(c.channels) <= n {
+			break
+		}
+		delete(c.channels, id)
+	}
+}
@@ -0,0 +1,343 @@
+package channeldb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/coreos/bbolt"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+)
+
+const (
+	// tempCompactDBName is the name of the file the compacted copy of the
+	// database is written to, before it replaces the database.
+	tempCompactDBName = "channel.db.compact"
+)
+
+// ErrCompactionUnsupported is returned when attempting to compact a database
+// that isn't stored within a local bolt database file.
+var ErrCompactionUnsupported = errors.New("compaction is only supported " +
+	"for bolt databases")
+
+// dbGate tracks the transactions in flight against the database, such that it
+// can be swapped out for its compacted copy once none are. Unlike with a
+// sync.RWMutex, a pending swap doesn't hold back new transactions, as those
+// may be nested within a transaction in flight, which would then deadlock.
+type dbGate struct {
+	mtx  sync.Mutex
+	cond *sync.Cond
+
+	// active is the number of transactions in flight.
+	active int
+
+	// swapping is true while the database is being swapped out.
+	swapping bool
+}
+
+// newDBGate creates a new dbGate.
+func newDBGate() *dbGate {
+	g := &dbGate{}
+	g.cond = sync.NewCond(&g.mtx)
+	return g
+}
+
+// enter registers a new transaction, blocking while the database is being
+// swapped out.
+func (g *dbGate) enter() {
+	g.mtx.Lock()
+	for g.swapping {
+		g.cond.Wait()
+	}
+	g.active++
+	g.mtx.Unlock()
+}
+
+// exit unregisters a transaction once it has completed.
+func (g *dbGate) exit() {
+	g.mtx.Lock()
+	g.active--
+	g.mtx.Unlock()
+	g.cond.Broadcast()
+}
+
+// lock waits for all transactions in flight to complete, and then blocks new
+// ones until unlock is called.
+func (g *dbGate) lock() {
+	g.mtx.Lock()
+	for g.swapping || g.active > 0 {
+		g.cond.Wait()
+	}
+	g.swapping = true
+	g.mtx.Unlock()
+}
+
+// unlock lets new transactions through again.
+func (g *dbGate) unlock() {
+	g.mtx.Lock()
+	g.swapping = false
+	g.mtx.Unlock()
+	g.cond.Broadcast()
+}
+
+// View executes a read-only transaction against the database.
+func (d *DB) View(fn func(kvdb.Tx) error) error {
+	d.gate.enter()
+	defer d.gate.exit()
+
+	return d.backend.View(fn)
+}
+
+// Update executes a read-write transaction against the database.
+func (d *DB) Update(fn func(kvdb.Tx) error) error {
+	d.gate.enter()
+	defer d.gate.exit()
+
+	return d.backend.Update(fn)
+}
+
+// Batch executes a read-write transaction against the database, which may be
+// coalesced with those of concurrent callers.
+func (d *DB) Batch(fn func(kvdb.Tx) error) error {
+	d.gate.enter()
+	defer d.gate.exit()
+
+	return d.backend.Batch(fn)
+}
+
+// Close waits for all transactions in flight to complete, and then closes the
+// database.
+func (d *DB) Close() error {
+	d.gate.lock()
+	defer d.gate.unlock()
+
+	return d.backend.Close()
+}
+
+// CompactionReport describes the outcome of compacting the database.
+type CompactionReport struct {
+	// FreePageRatio is the ratio of free pages in the database prior to
+	// compaction.
+	FreePageRatio float64
+
+	// SizeBefore is the size of the database file in bytes prior to
+	// compaction.
+	SizeBefore int64
+
+	// SizeAfter is the size of the database file in bytes after
+	// compaction.
+	SizeAfter int64
+
+	// Duration is the time it took to compact the database.
+	Duration time.Duration
+}
+
+// Reclaimed returns the number of bytes reclaimed by the compaction.
+func (r *CompactionReport) Reclaimed() int64 {
+	return r.SizeBefore - r.SizeAfter
+}
+
+// FreePageRatio returns the ratio of the pages of the database file that are
+// free. As bbolt never shrinks its file, these pages can only be reclaimed by
+// compacting the database.
+func (d *DB) FreePageRatio() (float64, error) {
+	d.gate.enter()
+	defer d.gate.exit()
+
+	bdb, err := d.boltDB()
+	if err != nil {
+		return 0, err
+	}
+
+	return freePageRatio(bdb)
+}
+
+// FileSize returns the size of the database file in bytes.
+func (d *DB) FileSize() (int64, error) {
+	bdb, err := d.boltDB()
+	if err != nil {
+		return 0, err
+	}
+
+	fileInfo, err := os.Stat(bdb.Path())
+	if err != nil {
+		return 0, err
+	}
+
+	return fileInfo.Size(), nil
+}
+
+// boltDB returns the bolt database backing the channeldb, or
+// ErrCompactionUnsupported if it's stored within another backend.
+func (d *DB) boltDB() (*bbolt.DB, error) {
+	backend, ok := d.backend.(*kvdb.BoltBackend)
+	if !ok {
+		return nil, ErrCompactionUnsupported
+	}
+
+	return backend.DB(), nil
+}
+
+// freePageRatio returns the ratio of the pages of the bbolt database file that
+// are free.
+func freePageRatio(bdb *bbolt.DB) (float64, error) {
+	fileInfo, err := os.Stat(bdb.Path())
+	if err != nil {
+		return 0, err
+	}
+
+	numPages := fileInfo.Size() / int64(bdb.Info().PageSize)
+	if numPages == 0 {
+		return 0, nil
+	}
+
+	stats := bdb.Stats()
+	freePages := int64(stats.FreePageN + stats.PendingPageN)
+
+	return float64(freePages) / float64(numPages), nil
+}
+
+// Compact rewrites the database into a new file without any free pages, and
+// then replaces the database with it. This is done while the database is in
+// use: once all transactions in flight have completed, new ones are held back
+// until the compacted database is in place. As all contents of the database
+// are copied, this may take a while for large databases.
+func (d *DB) Compact() (*CompactionReport, error) {
+	d.gate.lock()
+	defer d.gate.unlock()
+
+	return d.compact()
+}
+
+// compact rewrites the database into a compacted copy, and swaps it in.
+//
+// NOTE: The caller must ensure that no transactions are in flight.
+func (d *DB) compact() (*CompactionReport, error) {
+	start := time.Now()
+
+	bdb, err := d.boltDB()
+	if err != nil {
+		return nil, err
+	}
+
+	path := bdb.Path()
+	tempPath := filepath.Join(filepath.Dir(path), tempCompactDBName)
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	ratio, err := freePageRatio(bdb)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Compacting channel.db of %d bytes with free page ratio "+
+		"%.2f", fileInfo.Size(), ratio)
+
+	// A copy left over by an interrupted compaction is of no use, so
+	// we'll start afresh.
+	if err := os.Remove(tempPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	compactDB, err := bbolt.Open(tempPath, dbFilePermission, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := copyDB(compactDB, bdb); err != nil {
+		compactDB.Close()
+		os.Remove(tempPath)
+		return nil, fmt.Errorf("unable to copy database: %v", err)
+	}
+	if err := compactDB.Close(); err != nil {
+		os.Remove(tempPath)
+		return nil, err
+	}
+
+	// With the compacted copy fully written, we'll close the database and
+	// atomically replace its file with the copy.
+	if err := bdb.Close(); err != nil {
+		os.Remove(tempPath)
+		return nil, err
+	}
+	renameErr := os.Rename(tempPath, path)
+
+	// Whether or not the copy replaced it, we'll reopen the database.
+	backend, err := kvdb.OpenBoltBackend(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reopen database: %v", err)
+	}
+	d.backend = backend
+
+	if renameErr != nil {
+		os.Remove(tempPath)
+		return nil, renameErr
+	}
+
+	fileInfoAfter, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CompactionReport{
+		FreePageRatio: ratio,
+		SizeBefore:    fileInfo.Size(),
+		SizeAfter:     fileInfoAfter.Size(),
+		Duration:      time.Since(start),
+	}
+
+	log.Infof("Compacted channel.db in %v, reclaiming %d bytes",
+		report.Duration, report.Reclaimed())
+
+	return report, nil
+}
+
+// copyDB copies the contents of the src database into the empty dst database,
+// filling its pages entirely. Each top-level bucket is copied in a transaction
+// of its own to bound the memory used.
+func copyDB(dst, src *bbolt.DB) error {
+	copyTopLevel := func(name []byte, srcBucket *bbolt.Bucket) error {
+		return dst.Update(func(dstTx *bbolt.Tx) error {
+			dstBucket, err := dstTx.CreateBucket(name)
+			if err != nil {
+				return err
+			}
+
+			return compactBucket(dstBucket, srcBucket)
+		})
+	}
+
+	return src.View(func(srcTx *bbolt.Tx) error {
+		return srcTx.ForEach(copyTopLevel)
+	})
+}
+
+// compactBucket recursively copies the keys, nested buckets and sequence of
+// the src bucket into the dst bucket, filling its pages entirely.
+func compactBucket(dst, src *bbolt.Bucket) error {
+	// As the keys are inserted in order, the pages of the bucket can be
+	// filled entirely without causing any splits.
+	dst.FillPercent = 1.0
+
+	if err := dst.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+
+	return src.ForEach(func(k, v []byte) error {
+		// A nil value indicates a nested bucket.
+		if v == nil {
+			nested, err := dst.CreateBucket(k)
+			if err != nil {
+				return err
+			}
+
+			return compactBucket(nested, src.Bucket(k))
+		}
+
+		return dst.Put(k, v)
+	})
+}
@@ -0,0 +1,127 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+)
+
+// TestCompact tests that compacting the database reclaims the space of its
+// free pages, while preserving its contents, including the sequences of its
+// buckets.
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	var (
+		testBucket = []byte("test-bucket")
+		nestedKey  = []byte("nested")
+		keptKey    = []byte("kept")
+		keptValue  = []byte("value")
+	)
+
+	// We'll fill a bucket with enough data to grow the database file,
+	// along with a nested bucket whose sequence should survive the
+	// compaction.
+	err = cdb.Update(func(tx kvdb.Tx) error {
+		bucket, err := tx.CreateBucket(testBucket)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < 10000; i++ {
+			key := []byte{byte(i >> 8), byte(i)}
+			err := bucket.Put(key, make([]byte, 1000))
+			if err != nil {
+				return err
+			}
+		}
+
+		nested, err := bucket.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		if err := nested.SetSequence(42); err != nil {
+			return err
+		}
+
+		return nested.Put(keptKey, keptValue)
+	})
+	if err != nil {
+		t.Fatalf("unable to fill database: %v", err)
+	}
+
+	// Deleting all keys other than the nested bucket frees most of the
+	// pages of the database.
+	err = cdb.Update(func(tx kvdb.Tx) error {
+		bucket := tx.Bucket(testBucket)
+		for i := 0; i < 10000; i++ {
+			key := []byte{byte(i >> 8), byte(i)}
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to delete keys: %v", err)
+	}
+
+	ratio, err := cdb.FreePageRatio()
+	if err != nil {
+		t.Fatalf("unable to fetch free page ratio: %v", err)
+	}
+	if ratio < 0.5 {
+		t.Fatalf("expected most pages to be free, ratio is %v", ratio)
+	}
+
+	report, err := cdb.Compact()
+	if err != nil {
+		t.Fatalf("unable to compact database: %v", err)
+	}
+	if report.Reclaimed() <= 0 {
+		t.Fatalf("expected space to be reclaimed: %v", *report)
+	}
+
+	size, err := cdb.FileSize()
+	if err != nil {
+		t.Fatalf("unable to fetch file size: %v", err)
+	}
+	if size != report.SizeAfter {
+		t.Fatalf("expected size %v, got %v", report.SizeAfter, size)
+	}
+
+	// The compacted database should be in use, and hold the remaining
+	// contents.
+	err = cdb.View(func(tx kvdb.Tx) error {
+		nested := tx.Bucket(testBucket).Bucket(nestedKey)
+		if nested == nil {
+			t.Fatalf("nested bucket not found")
+		}
+		if nested.Sequence() != 42 {
+			t.Fatalf("expected sequence 42, got %v",
+				nested.Sequence())
+		}
+		if !bytes.Equal(nested.Get(keptKey), keptValue) {
+			t.Fatalf("expected value %x, got %x", keptValue,
+				nested.Get(keptKey))
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read database: %v", err)
+	}
+
+	// Finally, the database's own contents should still be intact.
+	if _, err := cdb.FetchAllChannels(); err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+}
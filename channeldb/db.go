@@ -11,8 +11,8 @@ import (
 
 	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcd/wire"
-	"github.com/coreos/bbolt"
 	"github.com/go-errors/errors"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 )
 
@@ -24,7 +24,7 @@ const (
 // migration is a function which takes a prior outdated version of the database
 // instances and mutates the key/bucket structure to arrive at a more
 // up-to-date version of the database.
-type migration func(tx *bbolt.Tx) error
+type migration func(tx kvdb.Tx) error
 
 type version struct {
 	number    uint32
@@ -107,46 +107,87 @@ var (
 // information related to nodes, routing data, open/closed channels, fee
 // schedules, and reputation data.
 type DB struct {
-	*bbolt.DB
-	dbPath string
-	graph  *ChannelGraph
+	backend kvdb.Backend
+	dbPath  string
+	graph   *ChannelGraph
+
+	// gate holds back transactions while the database is swapped out for
+	// its compacted copy.
+	gate *dbGate
 }
 
-// Open opens an existing channeldb. Any necessary schemas migrations due to
-// updates will take place as necessary.
+// Open opens an existing channeldb stored within a bolt database file in the
+// given directory, creating it if it doesn't exist yet. Any necessary schemas
+// migrations due to updates will take place as necessary.
 func Open(dbPath string, modifiers ...OptionModifier) (*DB, error) {
-	path := filepath.Join(dbPath, dbName)
-
-	if !fileExists(path) {
-		if err := createChannelDB(dbPath); err != nil {
+	if !fileExists(dbPath) {
+		if err := os.MkdirAll(dbPath, 0700); err != nil {
 			return nil, err
 		}
 	}
 
+	path := filepath.Join(dbPath, dbName)
+	backend, err := kvdb.OpenBoltBackend(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return OpenWithBackend(dbPath, backend, modifiers...)
+}
+
+// OpenWithBackend opens the channeldb stored within the passed backend,
+// initializing it if it's empty. The directory dbPath is still used to store
+// the auxiliary files of the daemon. Any necessary schemas migrations due to
+// updates will take place as necessary. The backend is closed if the
+// database can't be opened.
+func OpenWithBackend(dbPath string, backend kvdb.Backend,
+	modifiers ...OptionModifier) (*DB, error) {
+
 	opts := DefaultOptions()
 	for _, modifier := range modifiers {
 		modifier(&opts)
 	}
 
-	bdb, err := bbolt.Open(path, dbFilePermission, nil)
-	if err != nil {
+	if err := initChannelDB(backend); err != nil {
+		backend.Close()
 		return nil, err
 	}
 
 	chanDB := &DB{
-		DB:     bdb,
-		dbPath: dbPath,
+		backend: backend,
+		dbPath:  dbPath,
+		gate:    newDBGate(),
 	}
 	chanDB.graph = newChannelGraph(
 		chanDB, opts.RejectCacheSize, opts.ChannelCacheSize,
+		opts.BatchCommitInterval,
 	)
 
 	// Synchronize the version of database and apply migrations if needed.
 	if err := chanDB.syncVersions(dbVersions); err != nil {
-		bdb.Close()
+		backend.Close()
 		return nil, err
 	}
 
+	// If enough of the database has been freed, we'll compact it before
+	// it's put to use. Only bolt databases are in need of compaction.
+	_, isBolt := backend.(*kvdb.BoltBackend)
+	if isBolt && opts.AutoCompactRatio > 0 {
+		ratio, err := chanDB.FreePageRatio()
+		if err != nil {
+			chanDB.Close()
+			return nil, err
+		}
+
+		if ratio >= opts.AutoCompactRatio {
+			if _, err := chanDB.Compact(); err != nil {
+				chanDB.Close()
+				return nil, fmt.Errorf("unable to compact "+
+					"database: %v", err)
+			}
+		}
+	}
+
 	return chanDB, nil
 }
 
@@ -159,41 +200,41 @@ func (d *DB) Path() string {
 // database. The deletion is done in a single transaction, therefore this
 // operation is fully atomic.
 func (d *DB) Wipe() error {
-	return d.Update(func(tx *bbolt.Tx) error {
+	return d.Update(func(tx kvdb.Tx) error {
 		err := tx.DeleteBucket(openChannelBucket)
-		if err != nil && err != bbolt.ErrBucketNotFound {
+		if err != nil && err != kvdb.ErrBucketNotFound {
 			return err
 		}
 
 		err = tx.DeleteBucket(closedChannelBucket)
-		if err != nil && err != bbolt.ErrBucketNotFound {
+		if err != nil && err != kvdb.ErrBucketNotFound {
 			return err
 		}
 
 		err = tx.DeleteBucket(invoiceBucket)
-		if err != nil && err != bbolt.ErrBucketNotFound {
+		if err != nil && err != kvdb.ErrBucketNotFound {
 			return err
 		}
 
 		err = tx.DeleteBucket(nodeInfoBucket)
-		if err != nil && err != bbolt.ErrBucketNotFound {
+		if err != nil && err != kvdb.ErrBucketNotFound {
 			return err
 		}
 
 		err = tx.DeleteBucket(nodeBucket)
-		if err != nil && err != bbolt.ErrBucketNotFound {
+		if err != nil && err != kvdb.ErrBucketNotFound {
 			return err
 		}
 		err = tx.DeleteBucket(edgeBucket)
-		if err != nil && err != bbolt.ErrBucketNotFound {
+		if err != nil && err != kvdb.ErrBucketNotFound {
 			return err
 		}
 		err = tx.DeleteBucket(edgeIndexBucket)
-		if err != nil && err != bbolt.ErrBucketNotFound {
+		if err != nil && err != kvdb.ErrBucketNotFound {
 			return err
 		}
 		err = tx.DeleteBucket(graphMetaBucket)
-		if err != nil && err != bbolt.ErrBucketNotFound {
+		if err != nil && err != kvdb.ErrBucketNotFound {
 			return err
 		}
 
@@ -201,24 +242,17 @@ func (d *DB) Wipe() error {
 	})
 }
 
-// createChannelDB creates and initializes a fresh version of channeldb. In
-// the case that the target path has not yet been created or doesn't yet exist,
-// then the path is created. Additionally, all required top-level buckets used
-// within the database are created.
-func createChannelDB(dbPath string) error {
-	if !fileExists(dbPath) {
-		if err := os.MkdirAll(dbPath, 0700); err != nil {
-			return err
+// initChannelDB initializes a fresh version of channeldb within the backend,
+// creating all required top-level buckets used within the database. Backends
+// which already hold a channeldb are left untouched.
+func initChannelDB(backend kvdb.Backend) error {
+	err := backend.Update(func(tx kvdb.Tx) error {
+		// The meta bucket is created along with all others, so if it
+		// exists, the database has already been initialized.
+		if tx.Bucket(metaBucket) != nil {
+			return nil
 		}
-	}
-
-	path := filepath.Join(dbPath, dbName)
-	bdb, err := bbolt.Open(path, dbFilePermission, nil)
-	if err != nil {
-		return err
-	}
 
-	err = bdb.Update(func(tx *bbolt.Tx) error {
 		if _, err := tx.CreateBucket(openChannelBucket); err != nil {
 			return err
 		}
@@ -295,10 +329,10 @@ func createChannelDB(dbPath string) error {
 		return putMeta(meta, tx)
 	})
 	if err != nil {
-		return fmt.Errorf("unable to create new channeldb")
+		return fmt.Errorf("unable to create new channeldb: %v", err)
 	}
 
-	return bdb.Close()
+	return nil
 }
 
 // fileExists returns true if the file exists, and false otherwise.
@@ -318,7 +352,7 @@ func fileExists(path string) bool {
 // zero-length slice is returned.
 func (d *DB) FetchOpenChannels(nodeID *btcec.PublicKey) ([]*OpenChannel, error) {
 	var channels []*OpenChannel
-	err := d.View(func(tx *bbolt.Tx) error {
+	err := d.View(func(tx kvdb.Tx) error {
 		var err error
 		channels, err = d.fetchOpenChannels(tx, nodeID)
 		return err
@@ -331,7 +365,7 @@ func (d *DB) FetchOpenChannels(nodeID *btcec.PublicKey) ([]*OpenChannel, error)
 // stored currently active/open channels associated with the target nodeID. In
 // the case that no active channels are known to have been created with this
 // node, then a zero-length slice is returned.
-func (d *DB) fetchOpenChannels(tx *bbolt.Tx,
+func (d *DB) fetchOpenChannels(tx kvdb.Tx,
 	nodeID *btcec.PublicKey) ([]*OpenChannel, error) {
 
 	// Get the bucket dedicated to storing the metadata for open channels.
@@ -384,7 +418,7 @@ func (d *DB) fetchOpenChannels(tx *bbolt.Tx,
 // fetchNodeChannels retrieves all active channels from the target chainBucket
 // which is under a node's dedicated channel bucket. This function is typically
 // used to fetch all the active channels related to a particular node.
-func (d *DB) fetchNodeChannels(chainBucket *bbolt.Bucket) ([]*OpenChannel, error) {
+func (d *DB) fetchNodeChannels(chainBucket kvdb.Bucket) ([]*OpenChannel, error) {
 
 	var channels []*OpenChannel
 
@@ -443,7 +477,7 @@ func (d *DB) FetchChannel(chanPoint wire.OutPoint) (*OpenChannel, error) {
 	// structure and skipping fully decoding each channel, we save a good
 	// bit of CPU as we don't need to do things like decompress public
 	// keys.
-	chanScan := func(tx *bbolt.Tx) error {
+	chanScan := func(tx kvdb.Tx) error {
 		// Get the bucket dedicated to storing the metadata for open
 		// channels.
 		openChanBucket := tx.Bucket(openChannelBucket)
@@ -587,7 +621,7 @@ func (d *DB) FetchWaitingCloseChannels() ([]*OpenChannel, error) {
 func fetchChannels(d *DB, pending, waitingClose bool) ([]*OpenChannel, error) {
 	var channels []*OpenChannel
 
-	err := d.View(func(tx *bbolt.Tx) error {
+	err := d.View(func(tx kvdb.Tx) error {
 		// Get the bucket dedicated to storing the metadata for open
 		// channels.
 		openChanBucket := tx.Bucket(openChannelBucket)
@@ -674,7 +708,7 @@ func fetchChannels(d *DB, pending, waitingClose bool) ([]*OpenChannel, error) {
 func (d *DB) FetchClosedChannels(pendingOnly bool) ([]*ChannelCloseSummary, error) {
 	var chanSummaries []*ChannelCloseSummary
 
-	if err := d.View(func(tx *bbolt.Tx) error {
+	if err := d.View(func(tx kvdb.Tx) error {
 		closeBucket := tx.Bucket(closedChannelBucket)
 		if closeBucket == nil {
 			return ErrNoClosedChannels
@@ -712,7 +746,7 @@ var ErrClosedChannelNotFound = errors.New("unable to find closed channel summary
 // point of the channel in question.
 func (d *DB) FetchClosedChannel(chanID *wire.OutPoint) (*ChannelCloseSummary, error) {
 	var chanSummary *ChannelCloseSummary
-	if err := d.View(func(tx *bbolt.Tx) error {
+	if err := d.View(func(tx kvdb.Tx) error {
 		closeBucket := tx.Bucket(closedChannelBucket)
 		if closeBucket == nil {
 			return ErrClosedChannelNotFound
@@ -746,7 +780,7 @@ func (d *DB) FetchClosedChannelForID(cid lnwire.ChannelID) (
 	*ChannelCloseSummary, error) {
 
 	var chanSummary *ChannelCloseSummary
-	if err := d.View(func(tx *bbolt.Tx) error {
+	if err := d.View(func(tx kvdb.Tx) error {
 		closeBucket := tx.Bucket(closedChannelBucket)
 		if closeBucket == nil {
 			return ErrClosedChannelNotFound
@@ -794,7 +828,7 @@ func (d *DB) FetchClosedChannelForID(cid lnwire.ChannelID) (
 // the pending funds in a channel that has been forcibly closed have been
 // swept.
 func (d *DB) MarkChanFullyClosed(chanPoint *wire.OutPoint) error {
-	return d.Update(func(tx *bbolt.Tx) error {
+	return d.Update(func(tx kvdb.Tx) error {
 		var b bytes.Buffer
 		if err := writeOutpoint(&b, chanPoint); err != nil {
 			return err
@@ -847,7 +881,7 @@ func (d *DB) MarkChanFullyClosed(chanPoint *wire.OutPoint) error {
 // pruneLinkNode determines whether we should garbage collect a link node from
 // the database due to no longer having any open channels with it. If there are
 // any left, then this acts as a no-op.
-func (d *DB) pruneLinkNode(tx *bbolt.Tx, remotePub *btcec.PublicKey) error {
+func (d *DB) pruneLinkNode(tx kvdb.Tx, remotePub *btcec.PublicKey) error {
 	openChannels, err := d.fetchOpenChannels(tx, remotePub)
 	if err != nil {
 		return fmt.Errorf("unable to fetch open channels for peer %x: "+
@@ -867,7 +901,7 @@ func (d *DB) pruneLinkNode(tx *bbolt.Tx, remotePub *btcec.PublicKey) error {
 // PruneLinkNodes attempts to prune all link nodes found within the databse with
 // whom we no longer have any open channels with.
 func (d *DB) PruneLinkNodes() error {
-	return d.Update(func(tx *bbolt.Tx) error {
+	return d.Update(func(tx kvdb.Tx) error {
 		linkNodes, err := d.fetchAllLinkNodes(tx)
 		if err != nil {
 			return err
@@ -911,7 +945,7 @@ func (d *DB) RestoreChannelShells(channelShells ...*ChannelShell) error {
 	defer chanGraph.cacheMu.Unlock()
 
 	var chansRestored []uint64
-	err := d.Update(func(tx *bbolt.Tx) error {
+	err := d.Update(func(tx kvdb.Tx) error {
 		for _, channelShell := range channelShells {
 			channel := channelShell.Chan
 
@@ -1022,7 +1056,7 @@ func (d *DB) AddrsForNode(nodePub *btcec.PublicKey) ([]net.Addr, error) {
 		graphNode LightningNode
 	)
 
-	dbErr := d.View(func(tx *bbolt.Tx) error {
+	dbErr := d.View(func(tx kvdb.Tx) error {
 		var err error
 
 		linkNode, err = fetchLinkNode(tx, nodePub)
@@ -1111,7 +1145,7 @@ func (d *DB) syncVersions(versions []version) error {
 	migrations, migrationVersions := getMigrationsToApply(
 		versions, meta.DbVersionNumber,
 	)
-	return d.Update(func(tx *bbolt.Tx) error {
+	return d.Update(func(tx kvdb.Tx) error {
 		for i, migration := range migrations {
 			if migration == nil {
 				continue
@@ -0,0 +1,392 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+var (
+	// forwardingSummaryBucket is the bucket that stores the daily summaries
+	// of the forwarding events that have been pruned from the forwarding
+	// log. Each key is the start of a day (in seconds since the unix
+	// epoch) followed by a short channel ID, and the value the summary of
+	// the events that channel was part of during that day.
+	forwardingSummaryBucket = []byte("circuit-fwd-summary")
+)
+
+const (
+	// forwardingSummaryKeySize is the size of the key of a forwarding
+	// summary: an 8 byte day || 8 byte short channel ID.
+	forwardingSummaryKeySize = 16
+
+	// maxPrunedEventsPerTx is the maximum number of forwarding events
+	// rolled into summaries within a single transaction, which bounds the
+	// size of the transactions made when first pruning a large log.
+	maxPrunedEventsPerTx = 10000
+
+	// summaryPeriod is the period summarized by a forwarding summary.
+	summaryPeriod = 24 * time.Hour
+)
+
+// ForwardingSummary is the summary of the forwarding events a channel was
+// part of during a single day (in UTC).
+type ForwardingSummary struct {
+	// Day is the start of the day that is summarized.
+	Day time.Time
+
+	// ChanID is the short channel ID of the summarized channel.
+	ChanID lnwire.ShortChannelID
+
+	// NumIncoming is the number of forwarded HTLCs that were received
+	// through the channel.
+	NumIncoming uint64
+
+	// NumOutgoing is the number of forwarded HTLCs that were sent through
+	// the channel.
+	NumOutgoing uint64
+
+	// AmtIn is the total amount of the forwarded HTLCs received through
+	// the channel.
+	AmtIn lnwire.MilliSatoshi
+
+	// AmtOut is the total amount of the forwarded HTLCs sent through the
+	// channel.
+	AmtOut lnwire.MilliSatoshi
+
+	// Fees is the total of the fees earned by forwarding HTLCs through the
+	// channel. As a forwarding fee is charged according to the policy of
+	// the outgoing channel, it's attributed to that channel.
+	Fees lnwire.MilliSatoshi
+}
+
+// add accounts for the forwarding event within the summary of the given
+// channel.
+func (s *ForwardingSummary) add(chanID lnwire.ShortChannelID,
+	event *ForwardingEvent) {
+
+	if event.IncomingChanID == chanID {
+		s.NumIncoming++
+		s.AmtIn += event.AmtIn
+	}
+
+	if event.OutgoingChanID == chanID {
+		s.NumOutgoing++
+		s.AmtOut += event.AmtOut
+		s.Fees += event.AmtIn - event.AmtOut
+	}
+}
+
+// merge adds the totals of the other summary of the same day and channel to
+// the summary.
+func (s *ForwardingSummary) merge(other *ForwardingSummary) {
+	s.NumIncoming += other.NumIncoming
+	s.NumOutgoing += other.NumOutgoing
+	s.AmtIn += other.AmtIn
+	s.AmtOut += other.AmtOut
+	s.Fees += other.Fees
+}
+
+// summaryKey identifies the summary of a channel during a particular day.
+type summaryKey [forwardingSummaryKeySize]byte
+
+// newSummaryKey returns the key of the summary of the channel during the day
+// that includes the given time.
+func newSummaryKey(t time.Time, chanID lnwire.ShortChannelID) summaryKey {
+	var key summaryKey
+	byteOrder.PutUint64(key[:8], uint64(startOfDay(t).Unix()))
+	byteOrder.PutUint64(key[8:], chanID.ToUint64())
+	return key
+}
+
+// startOfDay returns the start of the day (in UTC) that includes the given
+// time.
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(summaryPeriod)
+}
+
+// summaryMap accumulates the summaries of forwarding events by their key.
+type summaryMap map[summaryKey]*ForwardingSummary
+
+// addEvent accounts for the forwarding event within the summaries of both of
+// its channels.
+func (m summaryMap) addEvent(event *ForwardingEvent) {
+	chanIDs := []lnwire.ShortChannelID{event.IncomingChanID}
+	if event.OutgoingChanID != event.IncomingChanID {
+		chanIDs = append(chanIDs, event.OutgoingChanID)
+	}
+
+	for _, chanID := range chanIDs {
+		summary := m.get(newSummaryKey(event.Timestamp, chanID))
+		summary.add(chanID, event)
+	}
+}
+
+// get returns the summary of the given key, creating an empty one if it
+// doesn't exist yet.
+func (m summaryMap) get(key summaryKey) *ForwardingSummary {
+	summary, ok := m[key]
+	if !ok {
+		summary = &ForwardingSummary{
+			Day: time.Unix(
+				int64(byteOrder.Uint64(key[:8])), 0,
+			).UTC(),
+			ChanID: lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(key[8:]),
+			),
+		}
+		m[key] = summary
+	}
+
+	return summary
+}
+
+// sorted returns the summaries ordered by day, and then by channel.
+func (m summaryMap) sorted() []ForwardingSummary {
+	keys := make([]summaryKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+
+	summaries := make([]ForwardingSummary, 0, len(keys))
+	for _, key := range keys {
+		summaries = append(summaries, *m[key])
+	}
+
+	return summaries
+}
+
+// encodeForwardingSummary writes out the totals of the forwarding summary to
+// the passed io.Writer. The day and channel aren't serialized, as they make up
+// the key of the summary within the bucket.
+func encodeForwardingSummary(w io.Writer, s *ForwardingSummary) error {
+	return WriteElements(
+		w, s.NumIncoming, s.NumOutgoing, s.AmtIn, s.AmtOut, s.Fees,
+	)
+}
+
+// decodeForwardingSummary reads the totals of a forwarding summary from the
+// passed io.Reader.
+func decodeForwardingSummary(r io.Reader, s *ForwardingSummary) error {
+	return ReadElements(
+		r, &s.NumIncoming, &s.NumOutgoing, &s.AmtIn, &s.AmtOut,
+		&s.Fees,
+	)
+}
+
+// PruneEvents removes all forwarding events that occurred before the given
+// time from the log. Rather than being lost, the pruned events are rolled
+// into the daily summaries of their channels, which remain queryable through
+// QuerySummaries. The number of pruned events is returned.
+func (f *ForwardingLog) PruneEvents(before time.Time) (int, error) {
+	var total int
+	for {
+		numPruned, err := f.pruneEventBatch(before)
+		if err != nil {
+			return total, err
+		}
+
+		total += numPruned
+		if numPruned < maxPrunedEventsPerTx {
+			return total, nil
+		}
+	}
+}
+
+// pruneEventBatch rolls up to maxPrunedEventsPerTx of the forwarding events
+// that occurred before the given time into their summaries, and removes them
+// from the log, all within a single transaction.
+func (f *ForwardingLog) pruneEventBatch(before time.Time) (int, error) {
+	var beforeKey [8]byte
+	byteOrder.PutUint64(beforeKey[:], uint64(before.UnixNano()))
+
+	var numPruned int
+	err := f.db.Update(func(tx kvdb.Tx) error {
+		logBucket := tx.Bucket(forwardingLogBucket)
+		if logBucket == nil {
+			return nil
+		}
+
+		// We'll first collect the events to prune, as keys can't be
+		// deleted while iterating over them.
+		var (
+			prunedKeys [][]byte
+			summaries  = make(summaryMap)
+		)
+		logCursor := logBucket.Cursor()
+		timestamp, events := logCursor.First()
+		for ; timestamp != nil; timestamp, events = logCursor.Next() {
+			if bytes.Compare(timestamp, beforeKey[:]) >= 0 ||
+				len(prunedKeys) == maxPrunedEventsPerTx {
+
+				break
+			}
+
+			eventTime := time.Unix(
+				0, int64(byteOrder.Uint64(timestamp)),
+			)
+
+			readBuf := bytes.NewReader(events)
+			for readBuf.Len() != 0 {
+				var event ForwardingEvent
+				err := decodeForwardingEvent(readBuf, &event)
+				if err != nil {
+					return err
+				}
+
+				event.Timestamp = eventTime
+				summaries.addEvent(&event)
+			}
+
+			// The key is copied, as it may not remain valid once
+			// the database is written to.
+			prunedKeys = append(
+				prunedKeys, append([]byte(nil), timestamp...),
+			)
+		}
+
+		if len(prunedKeys) == 0 {
+			return nil
+		}
+
+		summaryBucket, err := tx.CreateBucketIfNotExists(
+			forwardingSummaryBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		// With the events summarized, we'll add their totals to those
+		// of the events that were rolled up before.
+		for key, summary := range summaries {
+			summaryBytes := summaryBucket.Get(key[:])
+			if summaryBytes != nil {
+				var stored ForwardingSummary
+				err := decodeForwardingSummary(
+					bytes.NewReader(summaryBytes), &stored,
+				)
+				if err != nil {
+					return err
+				}
+
+				summary.merge(&stored)
+			}
+
+			var b bytes.Buffer
+			err := encodeForwardingSummary(&b, summary)
+			if err != nil {
+				return err
+			}
+
+			err = summaryBucket.Put(key[:], b.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, key := range prunedKeys {
+			if err := logBucket.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		numPruned = len(prunedKeys)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numPruned, nil
+}
+
+// QuerySummaries returns the daily summaries of the forwarding events of each
+// channel, for all days (in UTC) from the one including startTime up to and
+// including the one including endTime. Both the events that have been pruned
+// from the log and those still within it are accounted for. The summaries are
+// ordered by day, and then by channel.
+func (f *ForwardingLog) QuerySummaries(startTime,
+	endTime time.Time) ([]ForwardingSummary, error) {
+
+	firstDay := startOfDay(startTime)
+	endDay := startOfDay(endTime).Add(summaryPeriod)
+
+	summaries := make(summaryMap)
+	err := f.db.View(func(tx kvdb.Tx) error {
+		// We'll start with the summaries of the events that have
+		// already been pruned.
+		summaryBucket := tx.Bucket(forwardingSummaryBucket)
+		if summaryBucket != nil {
+			var startKey, endKey [8]byte
+			byteOrder.PutUint64(startKey[:], uint64(firstDay.Unix()))
+			byteOrder.PutUint64(endKey[:], uint64(endDay.Unix()))
+
+			c := summaryBucket.Cursor()
+			k, v := c.Seek(startKey[:])
+			for ; k != nil; k, v = c.Next() {
+				if bytes.Compare(k[:8], endKey[:]) >= 0 {
+					break
+				}
+
+				var key summaryKey
+				copy(key[:], k)
+
+				err := decodeForwardingSummary(
+					bytes.NewReader(v), summaries.get(key),
+				)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// Then, we'll add the events within the range that are still
+		// in the log.
+		logBucket := tx.Bucket(forwardingLogBucket)
+		if logBucket == nil {
+			return nil
+		}
+
+		var startKey, endKey [8]byte
+		byteOrder.PutUint64(startKey[:], uint64(firstDay.UnixNano()))
+		byteOrder.PutUint64(endKey[:], uint64(endDay.UnixNano()))
+
+		c := logBucket.Cursor()
+		timestamp, events := c.Seek(startKey[:])
+		for ; timestamp != nil; timestamp, events = c.Next() {
+			if bytes.Compare(timestamp, endKey[:]) >= 0 {
+				break
+			}
+
+			eventTime := time.Unix(
+				0, int64(byteOrder.Uint64(timestamp)),
+			)
+
+			readBuf := bytes.NewReader(events)
+			for readBuf.Len() != 0 {
+				var event ForwardingEvent
+				err := decodeForwardingEvent(readBuf, &event)
+				if err != nil {
+					return err
+				}
+
+				event.Timestamp = eventTime
+				summaries.addEvent(&event)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries.sorted(), nil
+}
@@ -0,0 +1,164 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestForwardingLogPruneAndSummarize tests that pruning the forwarding log
+// removes the events prior to the cutoff, while the daily summaries of the
+// channels remain unchanged by rolling up the pruned events.
+func TestForwardingLogPruneAndSummarize(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+	log := ForwardingLog{
+		db: db,
+	}
+
+	var (
+		chanA = lnwire.NewShortChanIDFromInt(1)
+		chanB = lnwire.NewShortChanIDFromInt(2)
+		chanC = lnwire.NewShortChanIDFromInt(3)
+
+		firstDay  = time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+		secondDay = firstDay.Add(24 * time.Hour)
+	)
+
+	// We'll forward from A to B twice on the first day, and from B to C
+	// once on each day.
+	events := []ForwardingEvent{
+		{
+			Timestamp:      firstDay.Add(time.Hour),
+			IncomingChanID: chanA,
+			OutgoingChanID: chanB,
+			AmtIn:          1010,
+			AmtOut:         1000,
+		},
+		{
+			Timestamp:      firstDay.Add(2 * time.Hour),
+			IncomingChanID: chanA,
+			OutgoingChanID: chanB,
+			AmtIn:          2020,
+			AmtOut:         2000,
+		},
+		{
+			Timestamp:      firstDay.Add(3 * time.Hour),
+			IncomingChanID: chanB,
+			OutgoingChanID: chanC,
+			AmtIn:          505,
+			AmtOut:         500,
+		},
+		{
+			Timestamp:      secondDay.Add(time.Hour),
+			IncomingChanID: chanB,
+			OutgoingChanID: chanC,
+			AmtIn:          303,
+			AmtOut:         300,
+		},
+	}
+	if err := log.AddForwardingEvents(events); err != nil {
+		t.Fatalf("unable to add events: %v", err)
+	}
+
+	expectedSummaries := []ForwardingSummary{
+		{
+			Day:         firstDay,
+			ChanID:      chanA,
+			NumIncoming: 2,
+			AmtIn:       3030,
+		},
+		{
+			Day:         firstDay,
+			ChanID:      chanB,
+			NumIncoming: 1,
+			NumOutgoing: 2,
+			AmtIn:       505,
+			AmtOut:      3000,
+			Fees:        30,
+		},
+		{
+			Day:         firstDay,
+			ChanID:      chanC,
+			NumOutgoing: 1,
+			AmtOut:      500,
+			Fees:        5,
+		},
+		{
+			Day:         secondDay,
+			ChanID:      chanB,
+			NumIncoming: 1,
+			AmtIn:       303,
+		},
+		{
+			Day:         secondDay,
+			ChanID:      chanC,
+			NumOutgoing: 1,
+			AmtOut:      300,
+			Fees:        3,
+		},
+	}
+
+	assertSummaries := func(start, end time.Time,
+		expected []ForwardingSummary) {
+
+		t.Helper()
+
+		summaries, err := log.QuerySummaries(start, end)
+		if err != nil {
+			t.Fatalf("unable to query summaries: %v", err)
+		}
+		if !reflect.DeepEqual(summaries, expected) {
+			t.Fatalf("expected summaries %v, got %v",
+				spew.Sdump(expected), spew.Sdump(summaries))
+		}
+	}
+
+	// Prior to pruning, the summaries are computed from the log alone.
+	lastEvent := secondDay.Add(time.Hour)
+	assertSummaries(firstDay, lastEvent, expectedSummaries)
+
+	// Pruning the events of the first day should only remove those
+	// events from the log.
+	numPruned, err := log.PruneEvents(secondDay)
+	if err != nil {
+		t.Fatalf("unable to prune events: %v", err)
+	}
+	if numPruned != 3 {
+		t.Fatalf("expected 3 pruned events, got %v", numPruned)
+	}
+
+	timeSlice, err := log.Query(ForwardingEventQuery{
+		StartTime:    firstDay,
+		EndTime:      lastEvent,
+		NumMaxEvents: 100,
+	})
+	if err != nil {
+		t.Fatalf("unable to query log: %v", err)
+	}
+	if len(timeSlice.ForwardingEvents) != 1 {
+		t.Fatalf("expected 1 remaining event, got %v",
+			len(timeSlice.ForwardingEvents))
+	}
+
+	// The summaries should be unaffected by the pruning, and be limited to
+	// the days queried for.
+	assertSummaries(firstDay, lastEvent, expectedSummaries)
+	assertSummaries(firstDay, firstDay, expectedSummaries[:3])
+	assertSummaries(secondDay, secondDay, expectedSummaries[3:])
+
+	// Finally, the remaining event should be rolled up alongside the
+	// summaries stored previously.
+	if _, err := log.PruneEvents(lastEvent.Add(time.Second)); err != nil {
+		t.Fatalf("unable to prune events: %v", err)
+	}
+	assertSummaries(firstDay, lastEvent, expectedSummaries)
+}
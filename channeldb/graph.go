@@ -18,7 +18,8 @@ import (
 	"github.com/litecoinfinance/btcd/txscript"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
-	"github.com/coreos/bbolt"
+	"github.com/litecoinfinance/lnd/batch"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 )
 
@@ -111,11 +112,13 @@ var (
 	// responsible for maintaining an index of zombie channels. Each entry
 	// exists within the bucket as follows:
 	//
-	// maps: chanID -> pubKey1 || pubKey2
+	// maps: chanID -> pubKey1 || pubKey2 || [edgeInfo]
 	//
 	// The chanID represents the channel ID of the edge that is marked as a
 	// zombie and is used as the key, which maps to the public keys of the
-	// edge's participants.
+	// edge's participants. Edges marked as zombies after being deleted
+	// from the graph also retain their serialized edge info, such that
+	// they can be restored without receiving their announcement again.
 	zombieBucket = []byte("zombie-index")
 
 	// graphMetaBucket is a top-level bucket which stores various meta-deta
@@ -161,16 +164,38 @@ type ChannelGraph struct {
 	cacheMu     sync.RWMutex
 	rejectCache *rejectCache
 	chanCache   *channelCache
+
+	// chanScheduler and nodeScheduler coalesce concurrent writes of
+	// channel edges and nodes into batched database transactions. This
+	// drastically reduces disk I/O while processing large amounts of
+	// graph updates, e.g. during a historical graph sync.
+	chanScheduler *batch.TimeScheduler
+	nodeScheduler *batch.TimeScheduler
 }
 
 // newChannelGraph allocates a new ChannelGraph backed by a DB instance. The
-// returned instance has its own unique reject cache and channel cache.
-func newChannelGraph(db *DB, rejectCacheSize, chanCacheSize int) *ChannelGraph {
-	return &ChannelGraph{
+// returned instance has its own unique reject cache and channel cache. Writes
+// that are scheduled lazily will be held back for up to batchCommitInterval in
+// order to be batched with other writes.
+func newChannelGraph(db *DB, rejectCacheSize, chanCacheSize int,
+	batchCommitInterval time.Duration) *ChannelGraph {
+
+	g := &ChannelGraph{
 		db:          db,
 		rejectCache: newRejectCache(rejectCacheSize),
 		chanCache:   newChannelCache(chanCacheSize),
 	}
+
+	// As the channel scheduler updates our caches once a batch has been
+	// committed, it must hold the cache lock while doing so.
+	g.chanScheduler = batch.NewTimeScheduler(
+		db, &g.cacheMu, batchCommitInterval,
+	)
+	g.nodeScheduler = batch.NewTimeScheduler(
+		db, nil, batchCommitInterval,
+	)
+
+	return g
 }
 
 // Database returns a pointer to the underlying database.
@@ -178,6 +203,25 @@ func (c *ChannelGraph) Database() *DB {
 	return c.db
 }
 
+// SetCacheSizes changes the maximum number of entries held by the reject cache
+// and the channel cache. If a cache currently holds more entries than its new
+// capacity, entries are evicted at random.
+func (c *ChannelGraph) SetCacheSizes(rejectCacheSize, chanCacheSize int) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	c.rejectCache.resize(rejectCacheSize)
+	c.chanCache.resize(chanCacheSize)
+}
+
+// SetBatchCommitInterval changes the maximum duration writes that are
+// scheduled lazily will be held back for in order to be batched with other
+// writes.
+func (c *ChannelGraph) SetBatchCommitInterval(interval time.Duration) {
+	c.chanScheduler.SetDuration(interval)
+	c.nodeScheduler.SetDuration(interval)
+}
+
 // ForEachChannel iterates through all the channel edges stored within the
 // graph and invokes the passed callback for each edge. The callback takes two
 // edges as since this is a directed graph, both the in/out edges are visited.
@@ -190,7 +234,7 @@ func (c *ChannelGraph) Database() *DB {
 func (c *ChannelGraph) ForEachChannel(cb func(*ChannelEdgeInfo, *ChannelEdgePolicy, *ChannelEdgePolicy) error) error {
 	// TODO(roasbeef): ptr map to reduce # of allocs? no duplicates
 
-	return c.db.View(func(tx *bbolt.Tx) error {
+	return c.db.View(func(tx kvdb.Tx) error {
 		// First, grab the node bucket. This will be used to populate
 		// the Node pointers in each edge read from disk.
 		nodes := tx.Bucket(nodeBucket)
@@ -248,8 +292,8 @@ func (c *ChannelGraph) ForEachChannel(cb func(*ChannelEdgeInfo, *ChannelEdgePoli
 //
 // TODO(roasbeef): add iterator interface to allow for memory efficient graph
 // traversal when graph gets mega
-func (c *ChannelGraph) ForEachNode(tx *bbolt.Tx, cb func(*bbolt.Tx, *LightningNode) error) error {
-	traversal := func(tx *bbolt.Tx) error {
+func (c *ChannelGraph) ForEachNode(tx kvdb.Tx, cb func(kvdb.Tx, *LightningNode) error) error {
+	traversal := func(tx kvdb.Tx) error {
 		// First grab the nodes bucket which stores the mapping from
 		// pubKey to node information.
 		nodes := tx.Bucket(nodeBucket)
@@ -295,7 +339,7 @@ func (c *ChannelGraph) ForEachNode(tx *bbolt.Tx, cb func(*bbolt.Tx, *LightningNo
 // node based off the source node.
 func (c *ChannelGraph) SourceNode() (*LightningNode, error) {
 	var source *LightningNode
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		// First grab the nodes bucket which stores the mapping from
 		// pubKey to node information.
 		nodes := tx.Bucket(nodeBucket)
@@ -322,7 +366,7 @@ func (c *ChannelGraph) SourceNode() (*LightningNode, error) {
 // of the graph. The source node is treated as the center node within a
 // star-graph. This method may be used to kick off a path finding algorithm in
 // order to explore the reachability of another node based off the source node.
-func (c *ChannelGraph) sourceNode(nodes *bbolt.Bucket) (*LightningNode, error) {
+func (c *ChannelGraph) sourceNode(nodes kvdb.Bucket) (*LightningNode, error) {
 	selfPub := nodes.Get(sourceKey)
 	if selfPub == nil {
 		return nil, ErrSourceNodeNotSet
@@ -345,7 +389,7 @@ func (c *ChannelGraph) sourceNode(nodes *bbolt.Bucket) (*LightningNode, error) {
 func (c *ChannelGraph) SetSourceNode(node *LightningNode) error {
 	nodePubBytes := node.PubKeyBytes[:]
 
-	return c.db.Update(func(tx *bbolt.Tx) error {
+	return c.db.Update(func(tx kvdb.Tx) error {
 		// First grab the nodes bucket which stores the mapping from
 		// pubKey to node information.
 		nodes, err := tx.CreateBucketIfNotExists(nodeBucket)
@@ -372,14 +416,27 @@ func (c *ChannelGraph) SetSourceNode(node *LightningNode) error {
 // an already present node from a node announcement, or to insert a node found
 // in a channel update.
 //
+// The write may be batched with other concurrent writes to the graph. Passing
+// batch.LazyAdd allows it to be held back in order to be batched with writes
+// that follow.
+//
 // TODO(roasbeef): also need sig of announcement
-func (c *ChannelGraph) AddLightningNode(node *LightningNode) error {
-	return c.db.Update(func(tx *bbolt.Tx) error {
-		return addLightningNode(tx, node)
-	})
+func (c *ChannelGraph) AddLightningNode(node *LightningNode,
+	op ...batch.SchedulerOption) error {
+
+	r := &batch.Request{
+		Update: func(tx kvdb.Tx) error {
+			return addLightningNode(tx, node)
+		},
+	}
+	for _, f := range op {
+		f(r)
+	}
+
+	return c.nodeScheduler.Execute(r)
 }
 
-func addLightningNode(tx *bbolt.Tx, node *LightningNode) error {
+func addLightningNode(tx kvdb.Tx, node *LightningNode) error {
 	nodes, err := tx.CreateBucketIfNotExists(nodeBucket)
 	if err != nil {
 		return err
@@ -405,7 +462,7 @@ func addLightningNode(tx *bbolt.Tx, node *LightningNode) error {
 func (c *ChannelGraph) LookupAlias(pub *btcec.PublicKey) (string, error) {
 	var alias string
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		nodes := tx.Bucket(nodeBucket)
 		if nodes == nil {
 			return ErrGraphNodesNotFound
@@ -438,7 +495,7 @@ func (c *ChannelGraph) LookupAlias(pub *btcec.PublicKey) (string, error) {
 // from the database according to the node's public key.
 func (c *ChannelGraph) DeleteLightningNode(nodePub *btcec.PublicKey) error {
 	// TODO(roasbeef): ensure dangling edges are removed...
-	return c.db.Update(func(tx *bbolt.Tx) error {
+	return c.db.Update(func(tx kvdb.Tx) error {
 		nodes := tx.Bucket(nodeBucket)
 		if nodes == nil {
 			return ErrGraphNodeNotFound
@@ -452,7 +509,7 @@ func (c *ChannelGraph) DeleteLightningNode(nodePub *btcec.PublicKey) error {
 
 // deleteLightningNode uses an existing database transaction to remove a
 // vertex/node from the database according to the node's public key.
-func (c *ChannelGraph) deleteLightningNode(nodes *bbolt.Bucket,
+func (c *ChannelGraph) deleteLightningNode(nodes kvdb.Bucket,
 	compressedPubKey []byte) error {
 
 	aliases := nodes.Bucket(aliasIndexBucket)
@@ -501,26 +558,55 @@ func (c *ChannelGraph) deleteLightningNode(nodes *bbolt.Bucket,
 // the keys involved in creation of the channel, and the set of features that
 // the channel supports. The chanPoint and chanID are used to uniquely identify
 // the edge globally within the database.
-func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo) error {
-	c.cacheMu.Lock()
-	defer c.cacheMu.Unlock()
+//
+// The write may be batched with other concurrent writes to the graph. Passing
+// batch.LazyAdd allows it to be held back in order to be batched with writes
+// that follow.
+func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo,
+	op ...batch.SchedulerOption) error {
+
+	var alreadyExists bool
+	r := &batch.Request{
+		Reset: func() {
+			alreadyExists = false
+		},
+		Update: func(tx kvdb.Tx) error {
+			err := c.addChannelEdge(tx, edge)
+
+			// Silence ErrEdgeAlreadyExist so that the batch can
+			// succeed, but propagate the error via local state.
+			if err == ErrEdgeAlreadyExist {
+				alreadyExists = true
+				return nil
+			}
 
-	err := c.db.Update(func(tx *bbolt.Tx) error {
-		return c.addChannelEdge(tx, edge)
-	})
-	if err != nil {
-		return err
-	}
+			return err
+		},
+		OnCommit: func(err error) error {
+			switch {
+			case err != nil:
+				return err
 
-	c.rejectCache.remove(edge.ChannelID)
-	c.chanCache.remove(edge.ChannelID)
+			case alreadyExists:
+				return ErrEdgeAlreadyExist
 
-	return nil
+			default:
+				c.rejectCache.remove(edge.ChannelID)
+				c.chanCache.remove(edge.ChannelID)
+				return nil
+			}
+		},
+	}
+	for _, f := range op {
+		f(r)
+	}
+
+	return c.chanScheduler.Execute(r)
 }
 
 // addChannelEdge is the private form of AddChannelEdge that allows callers to
 // utilize an existing db transaction.
-func (c *ChannelGraph) addChannelEdge(tx *bbolt.Tx, edge *ChannelEdgeInfo) error {
+func (c *ChannelGraph) addChannelEdge(tx kvdb.Tx, edge *ChannelEdgeInfo) error {
 	// Construct the channel's primary key which is the 8-byte channel ID.
 	var chanKey [8]byte
 	binary.BigEndian.PutUint64(chanKey[:], edge.ChannelID)
@@ -655,7 +741,7 @@ func (c *ChannelGraph) HasChannelEdge(
 		return upd1Time, upd2Time, exists, isZombie, nil
 	}
 
-	if err := c.db.View(func(tx *bbolt.Tx) error {
+	if err := c.db.View(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrGraphNoEdgesFound
@@ -732,7 +818,7 @@ func (c *ChannelGraph) UpdateChannelEdge(edge *ChannelEdgeInfo) error {
 	var chanKey [8]byte
 	binary.BigEndian.PutUint64(chanKey[:], edge.ChannelID)
 
-	return c.db.Update(func(tx *bbolt.Tx) error {
+	return c.db.Update(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edge == nil {
 			return ErrEdgeNotFound
@@ -775,7 +861,7 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 
 	var chansClosed []*ChannelEdgeInfo
 
-	err := c.db.Update(func(tx *bbolt.Tx) error {
+	err := c.db.Update(func(tx kvdb.Tx) error {
 		// First grab the edges bucket which houses the information
 		// we'd like to delete
 		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
@@ -889,7 +975,7 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 // that we only maintain a graph of reachable nodes. In the event that a pruned
 // node gains more channels, it will be re-added back to the graph.
 func (c *ChannelGraph) PruneGraphNodes() error {
-	return c.db.Update(func(tx *bbolt.Tx) error {
+	return c.db.Update(func(tx kvdb.Tx) error {
 		nodes := tx.Bucket(nodeBucket)
 		if nodes == nil {
 			return ErrGraphNodesNotFound
@@ -910,8 +996,8 @@ func (c *ChannelGraph) PruneGraphNodes() error {
 // pruneGraphNodes attempts to remove any nodes from the graph who have had a
 // channel closed within the current block. If the node still has existing
 // channels in the graph, this will act as a no-op.
-func (c *ChannelGraph) pruneGraphNodes(nodes *bbolt.Bucket,
-	edgeIndex *bbolt.Bucket) error {
+func (c *ChannelGraph) pruneGraphNodes(nodes kvdb.Bucket,
+	edgeIndex kvdb.Bucket) error {
 
 	log.Trace("Pruning nodes from graph with no open channels")
 
@@ -1037,7 +1123,7 @@ func (c *ChannelGraph) DisconnectBlockAtHeight(height uint32) ([]*ChannelEdgeInf
 	// Keep track of the channels that are removed from the graph.
 	var removedChans []*ChannelEdgeInfo
 
-	if err := c.db.Update(func(tx *bbolt.Tx) error {
+	if err := c.db.Update(func(tx kvdb.Tx) error {
 		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
 		if err != nil {
 			return err
@@ -1130,7 +1216,7 @@ func (c *ChannelGraph) PruneTip() (*chainhash.Hash, uint32, error) {
 		tipHeight uint32
 	)
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		graphMeta := tx.Bucket(graphMetaBucket)
 		if graphMeta == nil {
 			return ErrGraphNotFound
@@ -1175,7 +1261,7 @@ func (c *ChannelGraph) DeleteChannelEdges(chanIDs ...uint64) error {
 	c.cacheMu.Lock()
 	defer c.cacheMu.Unlock()
 
-	err := c.db.Update(func(tx *bbolt.Tx) error {
+	err := c.db.Update(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrEdgeNotFound
@@ -1228,7 +1314,7 @@ func (c *ChannelGraph) DeleteChannelEdges(chanIDs ...uint64) error {
 // the database, then ErrEdgeNotFound is returned.
 func (c *ChannelGraph) ChannelID(chanPoint *wire.OutPoint) (uint64, error) {
 	var chanID uint64
-	if err := c.db.View(func(tx *bbolt.Tx) error {
+	if err := c.db.View(func(tx kvdb.Tx) error {
 		var err error
 		chanID, err = getChanID(tx, chanPoint)
 		return err
@@ -1240,7 +1326,7 @@ func (c *ChannelGraph) ChannelID(chanPoint *wire.OutPoint) (uint64, error) {
 }
 
 // getChanID returns the assigned channel ID for a given channel point.
-func getChanID(tx *bbolt.Tx, chanPoint *wire.OutPoint) (uint64, error) {
+func getChanID(tx kvdb.Tx, chanPoint *wire.OutPoint) (uint64, error) {
 	var b bytes.Buffer
 	if err := writeOutpoint(&b, chanPoint); err != nil {
 		return 0, err
@@ -1273,7 +1359,7 @@ func getChanID(tx *bbolt.Tx, chanPoint *wire.OutPoint) (uint64, error) {
 func (c *ChannelGraph) HighestChanID() (uint64, error) {
 	var cid uint64
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrGraphNoEdgesFound
@@ -1337,7 +1423,7 @@ func (c *ChannelGraph) ChanUpdatesInHorizon(startTime, endTime time.Time) ([]Cha
 	defer c.cacheMu.Unlock()
 
 	var hits int
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrGraphNoEdgesFound
@@ -1457,7 +1543,7 @@ func (c *ChannelGraph) ChanUpdatesInHorizon(startTime, endTime time.Time) ([]Cha
 func (c *ChannelGraph) NodeUpdatesInHorizon(startTime, endTime time.Time) ([]LightningNode, error) {
 	var nodesInHorizon []LightningNode
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		nodes := tx.Bucket(nodeBucket)
 		if nodes == nil {
 			return ErrGraphNodesNotFound
@@ -1519,7 +1605,7 @@ func (c *ChannelGraph) NodeUpdatesInHorizon(startTime, endTime time.Time) ([]Lig
 func (c *ChannelGraph) FilterKnownChanIDs(chanIDs []uint64) ([]uint64, error) {
 	var newChanIDs []uint64
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrGraphNoEdgesFound
@@ -1595,7 +1681,7 @@ func (c *ChannelGraph) FilterChannelRange(startHeight, endHeight uint32) ([]uint
 	byteOrder.PutUint64(chanIDStart[:], startChanID.ToUint64())
 	byteOrder.PutUint64(chanIDEnd[:], endChanID.ToUint64())
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrGraphNoEdgesFound
@@ -1648,7 +1734,7 @@ func (c *ChannelGraph) FetchChanInfos(chanIDs []uint64) ([]ChannelEdge, error) {
 		cidBytes  [8]byte
 	)
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrGraphNoEdgesFound
@@ -1703,7 +1789,7 @@ func (c *ChannelGraph) FetchChanInfos(chanIDs []uint64) ([]ChannelEdge, error) {
 	return chanEdges, nil
 }
 
-func delEdgeUpdateIndexEntry(edgesBucket *bbolt.Bucket, chanID uint64,
+func delEdgeUpdateIndexEntry(edgesBucket kvdb.Bucket, chanID uint64,
 	edge1, edge2 *ChannelEdgePolicy) error {
 
 	// First, we'll fetch the edge update index bucket which currently
@@ -1742,7 +1828,7 @@ func delEdgeUpdateIndexEntry(edgesBucket *bbolt.Bucket, chanID uint64,
 }
 
 func delChannelEdge(edges, edgeIndex, chanIndex, zombieIndex,
-	nodes *bbolt.Bucket, chanID []byte, isZombie bool) error {
+	nodes kvdb.Bucket, chanID []byte, isZombie bool) error {
 
 	edgeInfo, err := fetchChanEdgeInfo(edgeIndex, chanID)
 	if err != nil {
@@ -1785,6 +1871,14 @@ func delChannelEdge(edges, edgeIndex, chanIndex, zombieIndex,
 		}
 	}
 
+	// If the edge is becoming a zombie, we'll retain its info within the
+	// zombie index in case it comes back to life. The bytes are copied, as
+	// they're only valid until the entry is deleted.
+	var edgeInfoBytes []byte
+	if isZombie {
+		edgeInfoBytes = append(edgeInfoBytes, edgeIndex.Get(chanID)...)
+	}
+
 	// With the edge data deleted, we can purge the information from the two
 	// edge indexes.
 	if err := edgeIndex.Delete(chanID); err != nil {
@@ -1807,7 +1901,7 @@ func delChannelEdge(edges, edgeIndex, chanIndex, zombieIndex,
 
 	return markEdgeZombie(
 		zombieIndex, byteOrder.Uint64(chanID), edgeInfo.NodeKey1Bytes,
-		edgeInfo.NodeKey2Bytes,
+		edgeInfo.NodeKey2Bytes, edgeInfoBytes,
 	)
 }
 
@@ -1818,20 +1912,63 @@ func delChannelEdge(edges, edgeIndex, chanIndex, zombieIndex,
 // updated, otherwise it's the second node's information. The node ordering is
 // determined by the lexicographical ordering of the identity public keys of
 // the nodes on either side of the channel.
-func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy) error {
-	c.cacheMu.Lock()
-	defer c.cacheMu.Unlock()
+//
+// The write may be batched with other concurrent writes to the graph. Passing
+// batch.LazyAdd allows it to be held back in order to be batched with writes
+// that follow.
+func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy,
+	op ...batch.SchedulerOption) error {
 
-	var isUpdate1 bool
-	err := c.db.Update(func(tx *bbolt.Tx) error {
-		var err error
-		isUpdate1, err = updateEdgePolicy(tx, edge)
-		return err
-	})
-	if err != nil {
-		return err
+	var (
+		isUpdate1    bool
+		edgeNotFound bool
+	)
+	r := &batch.Request{
+		Reset: func() {
+			isUpdate1 = false
+			edgeNotFound = false
+		},
+		Update: func(tx kvdb.Tx) error {
+			var err error
+			isUpdate1, err = updateEdgePolicy(tx, edge)
+
+			// Silence ErrEdgeNotFound so that the batch can
+			// succeed, but propagate the error via local state.
+			if err == ErrEdgeNotFound {
+				edgeNotFound = true
+				return nil
+			}
+
+			return err
+		},
+		OnCommit: func(err error) error {
+			switch {
+			case err != nil:
+				return err
+
+			case edgeNotFound:
+				return ErrEdgeNotFound
+
+			default:
+				c.updateEdgeCache(edge, isUpdate1)
+				return nil
+			}
+		},
+	}
+	for _, f := range op {
+		f(r)
 	}
 
+	return c.chanScheduler.Execute(r)
+}
+
+// updateEdgeCache updates our caches with the policy of the given edge that
+// was just written.
+//
+// NOTE: This method must be called with the cacheMu lock held.
+func (c *ChannelGraph) updateEdgeCache(edge *ChannelEdgePolicy,
+	isUpdate1 bool) {
+
 	// If an entry for this channel is found in reject cache, we'll modify
 	// the entry with the updated timestamp for the direction that was just
 	// written. If the edge doesn't exist, we'll load the cache entry lazily
@@ -1857,15 +1994,13 @@ func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy) error {
 		}
 		c.chanCache.insert(edge.ChannelID, channel)
 	}
-
-	return nil
 }
 
 // updateEdgePolicy attempts to update an edge's policy within the relevant
 // buckets using an existing database transaction. The returned boolean will be
 // true if the updated policy belongs to node1, and false if the policy belonged
 // to node2.
-func updateEdgePolicy(tx *bbolt.Tx, edge *ChannelEdgePolicy) (bool, error) {
+func updateEdgePolicy(tx kvdb.Tx, edge *ChannelEdgePolicy) (bool, error) {
 	edges := tx.Bucket(edgeBucket)
 	if edges == nil {
 		return false, ErrEdgeNotFound
@@ -2043,14 +2178,14 @@ func (l *LightningNode) NodeAnnouncement(signed bool) (*lnwire.NodeAnnouncement,
 // isPublic determines whether the node is seen as public within the graph from
 // the source node's point of view. An existing database transaction can also be
 // specified.
-func (l *LightningNode) isPublic(tx *bbolt.Tx, sourcePubKey []byte) (bool, error) {
+func (l *LightningNode) isPublic(tx kvdb.Tx, sourcePubKey []byte) (bool, error) {
 	// In order to determine whether this node is publicly advertised within
 	// the graph, we'll need to look at all of its edges and check whether
 	// they extend to any other node than the source node. errDone will be
 	// used to terminate the check early.
 	nodeIsPublic := false
 	errDone := errors.New("done")
-	err := l.ForEachChannel(tx, func(_ *bbolt.Tx, info *ChannelEdgeInfo,
+	err := l.ForEachChannel(tx, func(_ kvdb.Tx, info *ChannelEdgeInfo,
 		_, _ *ChannelEdgePolicy) error {
 
 		// If this edge doesn't extend to the source node, we'll
@@ -2087,7 +2222,7 @@ func (l *LightningNode) isPublic(tx *bbolt.Tx, sourcePubKey []byte) (bool, error
 func (c *ChannelGraph) FetchLightningNode(pub *btcec.PublicKey) (*LightningNode, error) {
 	var node *LightningNode
 	nodePub := pub.SerializeCompressed()
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		// First grab the nodes bucket which stores the mapping from
 		// pubKey to node information.
 		nodes := tx.Bucket(nodeBucket)
@@ -2133,7 +2268,7 @@ func (c *ChannelGraph) HasLightningNode(nodePub [33]byte) (time.Time, bool, erro
 		exists     bool
 	)
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		// First grab the nodes bucket which stores the mapping from
 		// pubKey to node information.
 		nodes := tx.Bucket(nodeBucket)
@@ -2182,12 +2317,12 @@ func (c *ChannelGraph) HasLightningNode(nodePub [33]byte) (time.Time, bool, erro
 // should be passed as the first argument.  Otherwise the first argument should
 // be nil and a fresh transaction will be created to execute the graph
 // traversal.
-func (l *LightningNode) ForEachChannel(tx *bbolt.Tx,
-	cb func(*bbolt.Tx, *ChannelEdgeInfo, *ChannelEdgePolicy, *ChannelEdgePolicy) error) error {
+func (l *LightningNode) ForEachChannel(tx kvdb.Tx,
+	cb func(kvdb.Tx, *ChannelEdgeInfo, *ChannelEdgePolicy, *ChannelEdgePolicy) error) error {
 
 	nodePub := l.PubKeyBytes[:]
 
-	traversal := func(tx *bbolt.Tx) error {
+	traversal := func(tx kvdb.Tx) error {
 		nodes := tx.Bucket(nodeBucket)
 		if nodes == nil {
 			return ErrGraphNotFound
@@ -2452,7 +2587,7 @@ func (c *ChannelEdgeInfo) OtherNodeKeyBytes(thisNodeKey []byte) (
 // the target node in the channel. This is useful when one knows the pubkey of
 // one of the nodes, and wishes to obtain the full LightningNode for the other
 // end of the channel.
-func (c *ChannelEdgeInfo) FetchOtherNode(tx *bbolt.Tx, thisNodeKey []byte) (*LightningNode, error) {
+func (c *ChannelEdgeInfo) FetchOtherNode(tx kvdb.Tx, thisNodeKey []byte) (*LightningNode, error) {
 
 	// Ensure that the node passed in is actually a member of the channel.
 	var targetNodeBytes [33]byte
@@ -2466,7 +2601,7 @@ func (c *ChannelEdgeInfo) FetchOtherNode(tx *bbolt.Tx, thisNodeKey []byte) (*Lig
 	}
 
 	var targetNode *LightningNode
-	fetchNodeFunc := func(tx *bbolt.Tx) error {
+	fetchNodeFunc := func(tx kvdb.Tx) error {
 		// First grab the nodes bucket which stores the mapping from
 		// pubKey to node information.
 		nodes := tx.Bucket(nodeBucket)
@@ -2732,7 +2867,7 @@ func (c *ChannelGraph) FetchChannelEdgesByOutpoint(op *wire.OutPoint,
 		policy2  *ChannelEdgePolicy
 	)
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		// First, grab the node bucket. This will be used to populate
 		// the Node pointers in each edge read from disk.
 		nodes := tx.Bucket(nodeBucket)
@@ -2816,7 +2951,7 @@ func (c *ChannelGraph) FetchChannelEdgesByID(chanID uint64,
 		channelID [8]byte
 	)
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		// First, grab the node bucket. This will be used to populate
 		// the Node pointers in each edge read from disk.
 		nodes := tx.Bucket(nodeBucket)
@@ -2906,7 +3041,7 @@ func (c *ChannelGraph) FetchChannelEdgesByID(chanID uint64,
 // source node's point of view.
 func (c *ChannelGraph) IsPublicNode(pubKey [33]byte) (bool, error) {
 	var nodeIsPublic bool
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		nodes := tx.Bucket(nodeBucket)
 		if nodes == nil {
 			return ErrGraphNodesNotFound
@@ -2992,7 +3127,7 @@ func (e *EdgePoint) String() string {
 // closes on the resident blockchain.
 func (c *ChannelGraph) ChannelView() ([]EdgePoint, error) {
 	var edgePoints []EdgePoint
-	if err := c.db.View(func(tx *bbolt.Tx) error {
+	if err := c.db.View(func(tx kvdb.Tx) error {
 		// We're going to iterate over the entire channel index, so
 		// we'll need to fetch the edgeBucket to get to the index as
 		// it's a sub-bucket.
@@ -3057,18 +3192,19 @@ func (c *ChannelGraph) NewChannelEdgePolicy() *ChannelEdgePolicy {
 
 // markEdgeZombie marks an edge as a zombie within our zombie index. The public
 // keys should represent the node public keys of the two parties involved in the
-// edge.
-func markEdgeZombie(zombieIndex *bbolt.Bucket, chanID uint64, pubKey1,
-	pubKey2 [33]byte) error {
+// edge. The serialized edge info, if known, is stored alongside them.
+func markEdgeZombie(zombieIndex kvdb.Bucket, chanID uint64, pubKey1,
+	pubKey2 [33]byte, edgeInfoBytes []byte) error {
 
 	var k [8]byte
 	byteOrder.PutUint64(k[:], chanID)
 
-	var v [66]byte
+	v := make([]byte, 66+len(edgeInfoBytes))
 	copy(v[:33], pubKey1[:])
-	copy(v[33:], pubKey2[:])
+	copy(v[33:66], pubKey2[:])
+	copy(v[66:], edgeInfoBytes)
 
-	return zombieIndex.Put(k[:], v[:])
+	return zombieIndex.Put(k[:], v)
 }
 
 // MarkEdgeLive clears an edge from our zombie index, deeming it as live.
@@ -3076,7 +3212,7 @@ func (c *ChannelGraph) MarkEdgeLive(chanID uint64) error {
 	c.cacheMu.Lock()
 	defer c.cacheMu.Unlock()
 
-	err := c.db.Update(func(tx *bbolt.Tx) error {
+	err := c.db.Update(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrGraphNoEdgesFound
@@ -3100,6 +3236,49 @@ func (c *ChannelGraph) MarkEdgeLive(chanID uint64) error {
 	return nil
 }
 
+// FetchZombieEdge returns the info the edge had when it was marked as a zombie.
+// ErrEdgeNotFound is returned if the edge isn't a zombie, or if its info wasn't
+// retained, in which case its announcement needs to be received again before
+// it can be restored.
+func (c *ChannelGraph) FetchZombieEdge(chanID uint64) (*ChannelEdgeInfo,
+	error) {
+
+	var edgeInfo *ChannelEdgeInfo
+	err := c.db.View(func(tx kvdb.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		if edges == nil {
+			return ErrGraphNoEdgesFound
+		}
+		zombieIndex := edges.Bucket(zombieBucket)
+		if zombieIndex == nil {
+			return ErrEdgeNotFound
+		}
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], chanID)
+
+		v := zombieIndex.Get(k[:])
+		if len(v) <= 66 {
+			return ErrEdgeNotFound
+		}
+
+		edge, err := deserializeChanEdgeInfo(bytes.NewReader(v[66:]))
+		if err != nil {
+			return err
+		}
+
+		edgeInfo = &edge
+		edgeInfo.db = c.db
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return edgeInfo, nil
+}
+
 // IsZombieEdge returns whether the edge is considered zombie. If it is a
 // zombie, then the two node public keys corresponding to this edge are also
 // returned.
@@ -3109,7 +3288,7 @@ func (c *ChannelGraph) IsZombieEdge(chanID uint64) (bool, [33]byte, [33]byte) {
 		pubKey1, pubKey2 [33]byte
 	)
 
-	err := c.db.View(func(tx *bbolt.Tx) error {
+	err := c.db.View(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrGraphNoEdgesFound
@@ -3132,7 +3311,7 @@ func (c *ChannelGraph) IsZombieEdge(chanID uint64) (bool, [33]byte, [33]byte) {
 // isZombieEdge returns whether an entry exists for the given channel in the
 // zombie index. If an entry exists, then the two node public keys corresponding
 // to this edge are also returned.
-func isZombieEdge(zombieIndex *bbolt.Bucket,
+func isZombieEdge(zombieIndex kvdb.Bucket,
 	chanID uint64) (bool, [33]byte, [33]byte) {
 
 	var k [8]byte
@@ -3145,13 +3324,13 @@ func isZombieEdge(zombieIndex *bbolt.Bucket,
 
 	var pubKey1, pubKey2 [33]byte
 	copy(pubKey1[:], v[:33])
-	copy(pubKey2[:], v[33:])
+	copy(pubKey2[:], v[33:66])
 
 	return true, pubKey1, pubKey2
 }
 
-func putLightningNode(nodeBucket *bbolt.Bucket, aliasBucket *bbolt.Bucket,
-	updateIndex *bbolt.Bucket, node *LightningNode) error {
+func putLightningNode(nodeBucket kvdb.Bucket, aliasBucket kvdb.Bucket,
+	updateIndex kvdb.Bucket, node *LightningNode) error {
 
 	var (
 		scratch [16]byte
@@ -3279,7 +3458,7 @@ func putLightningNode(nodeBucket *bbolt.Bucket, aliasBucket *bbolt.Bucket,
 	return nodeBucket.Put(nodePub, b.Bytes())
 }
 
-func fetchLightningNode(nodeBucket *bbolt.Bucket,
+func fetchLightningNode(nodeBucket kvdb.Bucket,
 	nodePub []byte) (LightningNode, error) {
 
 	nodeBytes := nodeBucket.Get(nodePub)
@@ -3385,7 +3564,7 @@ func deserializeLightningNode(r io.Reader) (LightningNode, error) {
 	return node, nil
 }
 
-func putChanEdgeInfo(edgeIndex *bbolt.Bucket, edgeInfo *ChannelEdgeInfo, chanID [8]byte) error {
+func putChanEdgeInfo(edgeIndex kvdb.Bucket, edgeInfo *ChannelEdgeInfo, chanID [8]byte) error {
 	var b bytes.Buffer
 
 	if _, err := b.Write(edgeInfo.NodeKey1Bytes[:]); err != nil {
@@ -3451,7 +3630,7 @@ func putChanEdgeInfo(edgeIndex *bbolt.Bucket, edgeInfo *ChannelEdgeInfo, chanID
 	return edgeIndex.Put(chanID[:], b.Bytes())
 }
 
-func fetchChanEdgeInfo(edgeIndex *bbolt.Bucket,
+func fetchChanEdgeInfo(edgeIndex kvdb.Bucket,
 	chanID []byte) (ChannelEdgeInfo, error) {
 
 	edgeInfoBytes := edgeIndex.Get(chanID)
@@ -3540,7 +3719,7 @@ func deserializeChanEdgeInfo(r io.Reader) (ChannelEdgeInfo, error) {
 	return edgeInfo, nil
 }
 
-func putChanEdgePolicy(edges, nodes *bbolt.Bucket, edge *ChannelEdgePolicy,
+func putChanEdgePolicy(edges, nodes kvdb.Bucket, edge *ChannelEdgePolicy,
 	from, to []byte) error {
 
 	var edgeKey [33 + 8]byte
@@ -3607,7 +3786,7 @@ func putChanEdgePolicy(edges, nodes *bbolt.Bucket, edge *ChannelEdgePolicy,
 
 // putChanEdgePolicyUnknown marks the edge policy as unknown
 // in the edges bucket.
-func putChanEdgePolicyUnknown(edges *bbolt.Bucket, channelID uint64,
+func putChanEdgePolicyUnknown(edges kvdb.Bucket, channelID uint64,
 	from []byte) error {
 
 	var edgeKey [33 + 8]byte
@@ -3622,8 +3801,8 @@ func putChanEdgePolicyUnknown(edges *bbolt.Bucket, channelID uint64,
 	return edges.Put(edgeKey[:], unknownPolicy)
 }
 
-func fetchChanEdgePolicy(edges *bbolt.Bucket, chanID []byte,
-	nodePub []byte, nodes *bbolt.Bucket) (*ChannelEdgePolicy, error) {
+func fetchChanEdgePolicy(edges kvdb.Bucket, chanID []byte,
+	nodePub []byte, nodes kvdb.Bucket) (*ChannelEdgePolicy, error) {
 
 	var edgeKey [33 + 8]byte
 	copy(edgeKey[:], nodePub)
@@ -3655,8 +3834,8 @@ func fetchChanEdgePolicy(edges *bbolt.Bucket, chanID []byte,
 	return ep, nil
 }
 
-func fetchChanEdgePolicies(edgeIndex *bbolt.Bucket, edges *bbolt.Bucket,
-	nodes *bbolt.Bucket, chanID []byte,
+func fetchChanEdgePolicies(edgeIndex kvdb.Bucket, edges kvdb.Bucket,
+	nodes kvdb.Bucket, chanID []byte,
 	db *DB) (*ChannelEdgePolicy, *ChannelEdgePolicy, error) {
 
 	edgeInfo := edgeIndex.Get(chanID)
@@ -3764,7 +3943,7 @@ func serializeChanEdgePolicy(w io.Writer, edge *ChannelEdgePolicy,
 }
 
 func deserializeChanEdgePolicy(r io.Reader,
-	nodes *bbolt.Bucket) (*ChannelEdgePolicy, error) {
+	nodes kvdb.Bucket) (*ChannelEdgePolicy, error) {
 
 	edge := &ChannelEdgePolicy{}
 
@@ -17,8 +17,8 @@ import (
 	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 )
 
@@ -879,7 +879,7 @@ func TestGraphTraversal(t *testing.T) {
 
 	// Iterate over each node as returned by the graph, if all nodes are
 	// reached, then the map created above should be empty.
-	err = graph.ForEachNode(nil, func(_ *bbolt.Tx, node *LightningNode) error {
+	err = graph.ForEachNode(nil, func(_ kvdb.Tx, node *LightningNode) error {
 		delete(nodeIndex, node.Alias)
 		return nil
 	})
@@ -975,7 +975,7 @@ func TestGraphTraversal(t *testing.T) {
 	// Finally, we want to test the ability to iterate over all the
 	// outgoing channels for a particular node.
 	numNodeChans := 0
-	err = firstNode.ForEachChannel(nil, func(_ *bbolt.Tx, _ *ChannelEdgeInfo,
+	err = firstNode.ForEachChannel(nil, func(_ kvdb.Tx, _ *ChannelEdgeInfo,
 		outEdge, inEdge *ChannelEdgePolicy) error {
 
 		// All channels between first and second node should have fully
@@ -1048,7 +1048,7 @@ func assertNumChans(t *testing.T, graph *ChannelGraph, n int) {
 
 func assertNumNodes(t *testing.T, graph *ChannelGraph, n int) {
 	numNodes := 0
-	err := graph.ForEachNode(nil, func(_ *bbolt.Tx, _ *LightningNode) error {
+	err := graph.ForEachNode(nil, func(_ kvdb.Tx, _ *LightningNode) error {
 		numNodes++
 		return nil
 	})
@@ -2097,7 +2097,7 @@ func TestIncompleteChannelPolicies(t *testing.T) {
 
 	checkPolicies := func(node *LightningNode, expectedIn, expectedOut bool) {
 		calls := 0
-		node.ForEachChannel(nil, func(_ *bbolt.Tx, _ *ChannelEdgeInfo,
+		node.ForEachChannel(nil, func(_ kvdb.Tx, _ *ChannelEdgeInfo,
 			outEdge, inEdge *ChannelEdgePolicy) error {
 
 			if !expectedOut && outEdge != nil {
@@ -2230,7 +2230,7 @@ func TestChannelEdgePruningUpdateIndexDeletion(t *testing.T) {
 			timestampSet[t] = struct{}{}
 		}
 
-		err := db.View(func(tx *bbolt.Tx) error {
+		err := db.View(func(tx kvdb.Tx) error {
 			edges := tx.Bucket(edgeBucket)
 			if edges == nil {
 				return ErrGraphNoEdgesFound
@@ -2240,7 +2240,15 @@ func TestChannelEdgePruningUpdateIndexDeletion(t *testing.T) {
 				return ErrGraphNoEdgesFound
 			}
 
-			numEntries := edgeUpdateIndex.Stats().KeyN
+			var numEntries int
+			err := edgeUpdateIndex.ForEach(func(_, _ []byte) error {
+				numEntries++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
 			expectedEntries := len(timestampSet)
 			if numEntries != expectedEntries {
 				return fmt.Errorf("expected %v entries in the "+
@@ -2746,7 +2754,7 @@ func TestEdgePolicyMissingMaxHtcl(t *testing.T) {
 
 	// Attempting to deserialize these bytes should return an error.
 	r := bytes.NewReader(stripped)
-	err = db.View(func(tx *bbolt.Tx) error {
+	err = db.View(func(tx kvdb.Tx) error {
 		nodes := tx.Bucket(nodeBucket)
 		if nodes == nil {
 			return ErrGraphNotFound
@@ -2766,7 +2774,7 @@ func TestEdgePolicyMissingMaxHtcl(t *testing.T) {
 	}
 
 	// Put the stripped bytes in the DB.
-	err = db.Update(func(tx *bbolt.Tx) error {
+	err = db.Update(func(tx kvdb.Tx) error {
 		edges := tx.Bucket(edgeBucket)
 		if edges == nil {
 			return ErrEdgeNotFound
@@ -2901,6 +2909,14 @@ func TestGraphZombieIndex(t *testing.T) {
 			pubKey2)
 	}
 
+	// The info of the deleted edge should have been retained, such that
+	// it can be restored.
+	zombieEdge, err := graph.FetchZombieEdge(edge.ChannelID)
+	if err != nil {
+		t.Fatalf("unable to fetch zombie edge: %v", err)
+	}
+	assertEdgeInfoEqual(t, edge, zombieEdge)
+
 	// Similarly, if we mark the same edge as live, we should no longer see
 	// it within the index.
 	if err := graph.MarkEdgeLive(edge.ChannelID); err != nil {
@@ -2910,6 +2926,10 @@ func TestGraphZombieIndex(t *testing.T) {
 	if isZombie {
 		t.Fatal("expected edge to not be marked as zombie")
 	}
+	_, err = graph.FetchZombieEdge(edge.ChannelID)
+	if err != ErrEdgeNotFound {
+		t.Fatalf("expected ErrEdgeNotFound, got: %v", err)
+	}
 }
 
 // compareNodes is used to compare two LightningNodes while excluding the
@@ -0,0 +1,699 @@
+package channeldb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// IntegrityIssueType identifies the kind of inconsistency found by
+// CheckIntegrity.
+type IntegrityIssueType uint8
+
+const (
+	// IntegrityOrphanedHTLCs indicates forwarding packages, which hold the
+	// HTLCs received over a channel, that don't belong to any channel
+	// within the database.
+	IntegrityOrphanedHTLCs IntegrityIssueType = iota
+
+	// IntegrityDanglingEdge indicates a channel edge, or one of its index
+	// entries, that refers to a node or edge missing from the graph.
+	IntegrityDanglingEdge
+
+	// IntegrityMissingRevocationLog indicates an open channel whose
+	// revocation log lacks some of the revoked states of the remote
+	// party, which then can't be punished if broadcast.
+	IntegrityMissingRevocationLog
+
+	// IntegrityInvalidInvoiceIndex indicates an entry within one of the
+	// invoice indexes that doesn't match the invoice it refers to, or an
+	// invoice missing from an index.
+	IntegrityInvalidInvoiceIndex
+
+	// IntegrityCorruptRecord indicates a channel or invoice that can't be
+	// decoded.
+	IntegrityCorruptRecord
+)
+
+// String returns a human readable name of the IntegrityIssueType.
+func (t IntegrityIssueType) String() string {
+	switch t {
+	case IntegrityOrphanedHTLCs:
+		return "OrphanedHTLCs"
+	case IntegrityDanglingEdge:
+		return "DanglingEdge"
+	case IntegrityMissingRevocationLog:
+		return "MissingRevocationLog"
+	case IntegrityInvalidInvoiceIndex:
+		return "InvalidInvoiceIndex"
+	case IntegrityCorruptRecord:
+		return "CorruptRecord"
+	default:
+		return "Unknown"
+	}
+}
+
+// IntegrityIssue is an inconsistency found within the database.
+type IntegrityIssue struct {
+	// Type is the kind of inconsistency.
+	Type IntegrityIssueType
+
+	// Description describes the inconsistency and the records involved.
+	Description string
+
+	// Repairable indicates whether the inconsistency can be repaired
+	// without losing any data that is still needed.
+	Repairable bool
+
+	// Repaired indicates whether the inconsistency has been repaired.
+	Repaired bool
+}
+
+// integrityCheck scans the records of the database within the transaction,
+// returning the inconsistencies found. The repairable ones are repaired if
+// requested, in which case the transaction is writable.
+type integrityCheck func(tx kvdb.Tx, repair bool) ([]IntegrityIssue, error)
+
+// CheckIntegrity scans the database for orphaned HTLCs, dangling graph
+// edges, missing revocation logs and invalid invoice indexes, returning the
+// inconsistencies found. If repair is true, the inconsistencies that can be
+// repaired safely are repaired as well.
+func (d *DB) CheckIntegrity(repair bool) ([]IntegrityIssue, error) {
+	checks := []integrityCheck{
+		checkChannelIntegrity,
+		checkInvoiceIntegrity,
+	}
+
+	var issues []IntegrityIssue
+	for _, check := range checks {
+		var checkIssues []IntegrityIssue
+		run := func(tx kvdb.Tx) error {
+			var err error
+			checkIssues, err = check(tx, repair)
+			return err
+		}
+
+		var err error
+		if repair {
+			err = d.Update(run)
+		} else {
+			err = d.View(run)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, checkIssues...)
+	}
+
+	graphIssues, err := d.graph.checkIntegrity(repair)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(issues, graphIssues...), nil
+}
+
+// checkChannelIntegrity checks that the revocation log of each open channel
+// contains all revoked states of the remote party, and that each forwarding
+// package belongs to a channel. Forwarding packages of channels that are no
+// longer open are deleted when repairing.
+func checkChannelIntegrity(tx kvdb.Tx, repair bool) ([]IntegrityIssue,
+	error) {
+
+	var issues []IntegrityIssue
+
+	// We'll record the short channel IDs of all channels, whether pending,
+	// open or waiting to be closed, as their forwarding packages are still
+	// in use.
+	shortChanIDs := make(map[lnwire.ShortChannelID]struct{})
+	checkChannel := func(chanBucket kvdb.Bucket, chanPoint []byte) error {
+		var outPoint wire.OutPoint
+		err := readOutpoint(bytes.NewReader(chanPoint), &outPoint)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &outPoint)
+		if err != nil {
+			issues = append(issues, IntegrityIssue{
+				Type: IntegrityCorruptRecord,
+				Description: fmt.Sprintf("channel %v can't be "+
+					"decoded: %v", outPoint, err),
+			})
+			return nil
+		}
+		shortChanIDs[channel.ShortChannelID] = struct{}{}
+
+		// Restored channels and those that lost their state don't
+		// have a revocation log to begin with.
+		if channel.HasChanStatus(ChanStatusRestored) ||
+			channel.HasChanStatus(ChanStatusLocalDataLoss) {
+
+			return nil
+		}
+
+		// Each revoked state of the remote party, from the initial
+		// one up to the current one, should be within the log.
+		numRevoked := channel.RemoteCommitment.CommitHeight
+		var numLogged uint64
+		logBucket := chanBucket.Bucket(revocationLogBucket)
+		if logBucket != nil {
+			err := logBucket.ForEach(func(_, _ []byte) error {
+				numLogged++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if numLogged < numRevoked {
+			issues = append(issues, IntegrityIssue{
+				Type: IntegrityMissingRevocationLog,
+				Description: fmt.Sprintf("revocation log of "+
+					"channel %v holds %d of %d revoked "+
+					"states", outPoint, numLogged,
+					numRevoked),
+			})
+		}
+
+		return nil
+	}
+
+	openChanBucket := tx.Bucket(openChannelBucket)
+	if openChanBucket != nil {
+		err := forEachChanBucket(openChanBucket, checkChannel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fwdPkgBkt := tx.Bucket(fwdPackagesKey)
+	if fwdPkgBkt == nil {
+		return issues, nil
+	}
+
+	var orphanedSources [][]byte
+	err := fwdPkgBkt.ForEach(func(source, v []byte) error {
+		if v != nil || len(source) != 8 {
+			return nil
+		}
+
+		shortChanID := lnwire.NewShortChanIDFromInt(
+			byteOrder.Uint64(source),
+		)
+		if _, ok := shortChanIDs[shortChanID]; ok {
+			return nil
+		}
+
+		var numPkgs int
+		err := fwdPkgBkt.Bucket(source).ForEach(func(_, _ []byte) error {
+			numPkgs++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		issues = append(issues, IntegrityIssue{
+			Type: IntegrityOrphanedHTLCs,
+			Description: fmt.Sprintf("%d forwarding packages of "+
+				"channel %v, which isn't open", numPkgs,
+				shortChanID),
+			Repairable: true,
+			Repaired:   repair,
+		})
+		orphanedSources = append(
+			orphanedSources, append([]byte(nil), source...),
+		)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !repair {
+		return issues, nil
+	}
+
+	for _, source := range orphanedSources {
+		if err := fwdPkgBkt.DeleteBucket(source); err != nil {
+			return nil, err
+		}
+	}
+
+	return issues, nil
+}
+
+// forEachChanBucket calls f with the bucket and serialized channel point of
+// each channel within the open channel bucket, regardless of whether its
+// peer is still known.
+func forEachChanBucket(openChanBucket kvdb.Bucket,
+	f func(chanBucket kvdb.Bucket, chanPoint []byte) error) error {
+
+	return openChanBucket.ForEach(func(nodeKey, v []byte) error {
+		if v != nil {
+			return nil
+		}
+		nodeChanBucket := openChanBucket.Bucket(nodeKey)
+
+		return nodeChanBucket.ForEach(func(chainHash, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			chainBucket := nodeChanBucket.Bucket(chainHash)
+
+			return chainBucket.ForEach(func(k, v []byte) error {
+				if v != nil {
+					return nil
+				}
+
+				return f(chainBucket.Bucket(k), k)
+			})
+		})
+	})
+}
+
+// checkInvoiceIntegrity checks that the payment hash, add and settle indexes
+// match the invoices they refer to. Index entries referring to missing
+// invoices are deleted, and missing entries are restored, when repairing.
+func checkInvoiceIntegrity(tx kvdb.Tx, repair bool) ([]IntegrityIssue,
+	error) {
+
+	invoices := tx.Bucket(invoiceBucket)
+	if invoices == nil {
+		return nil, nil
+	}
+
+	var issues []IntegrityIssue
+	addIssue := func(repairable bool, format string, a ...interface{}) {
+		issues = append(issues, IntegrityIssue{
+			Type:        IntegrityInvalidInvoiceIndex,
+			Description: fmt.Sprintf(format, a...),
+			Repairable:  repairable,
+			Repaired:    repairable && repair,
+		})
+	}
+
+	// We'll start by decoding all invoices, such that the index entries
+	// can be checked against them.
+	decoded := make(map[uint32]*Invoice)
+	err := invoices.ForEach(func(k, v []byte) error {
+		if v == nil || len(k) != 4 {
+			return nil
+		}
+
+		invoice, err := deserializeInvoice(bytes.NewReader(v))
+		if err != nil {
+			issues = append(issues, IntegrityIssue{
+				Type: IntegrityCorruptRecord,
+				Description: fmt.Sprintf("invoice %d can't be "+
+					"decoded: %v", byteOrder.Uint32(k), err),
+			})
+			return nil
+		}
+		decoded[byteOrder.Uint32(k)] = &invoice
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// exists returns whether the invoice key refers to a stored invoice,
+	// even if it can't be decoded.
+	exists := func(invoiceKey []byte) bool {
+		return len(invoiceKey) == 4 && invoices.Get(invoiceKey) != nil
+	}
+
+	// The entries of the payment hash index should refer to existing
+	// invoices, and each invoice should be indexed.
+	var (
+		staleHashes [][]byte
+		indexed     = make(map[uint32]struct{})
+	)
+	invoiceIndex := invoices.Bucket(invoiceIndexBucket)
+	if invoiceIndex != nil {
+		err := invoiceIndex.ForEach(func(k, v []byte) error {
+			if bytes.Equal(k, numInvoicesKey) {
+				return nil
+			}
+
+			if !exists(v) {
+				addIssue(true, "payment hash %x refers to "+
+					"missing invoice", k)
+				staleHashes = append(
+					staleHashes, append([]byte(nil), k...),
+				)
+				return nil
+			}
+			indexed[byteOrder.Uint32(v)] = struct{}{}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The entries of the add and settle indexes should refer to existing
+	// invoices with the same index.
+	checkSeqIndex := func(index kvdb.Bucket, name string,
+		seqNo func(*Invoice) uint64) ([][]byte, error) {
+
+		if index == nil {
+			return nil, nil
+		}
+
+		var stale [][]byte
+		err := index.ForEach(func(k, v []byte) error {
+			if len(k) != 8 {
+				return nil
+			}
+
+			if !exists(v) {
+				addIssue(true, "%v index %d refers to missing "+
+					"invoice", name, byteOrder.Uint64(k))
+				stale = append(stale, append([]byte(nil), k...))
+				return nil
+			}
+
+			invoice, ok := decoded[byteOrder.Uint32(v)]
+			if ok && seqNo(invoice) != byteOrder.Uint64(k) {
+				addIssue(true, "%v index %d refers to invoice "+
+					"%d with %v index %d", name,
+					byteOrder.Uint64(k), byteOrder.Uint32(v),
+					name, seqNo(invoice))
+				stale = append(stale, append([]byte(nil), k...))
+			}
+
+			return nil
+		})
+
+		return stale, err
+	}
+
+	addIndex := invoices.Bucket(addIndexBucket)
+	staleAdds, err := checkSeqIndex(
+		addIndex, "add", func(i *Invoice) uint64 {
+			return i.AddIndex
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	settleIndex := invoices.Bucket(settleIndexBucket)
+	staleSettles, err := checkSeqIndex(
+		settleIndex, "settle", func(i *Invoice) uint64 {
+			return i.SettleIndex
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Finally, each invoice should be found within the indexes through
+	// its own payment hash, add index and settle index.
+	type indexEntry struct {
+		index kvdb.Bucket
+		key   []byte
+		value []byte
+	}
+	var missing []indexEntry
+	for num, invoice := range decoded {
+		var invoiceKey [4]byte
+		byteOrder.PutUint32(invoiceKey[:], num)
+
+		if _, ok := indexed[num]; !ok {
+			// The payment hash can only be restored if the
+			// preimage is known, and not used by another invoice.
+			preimage := invoice.Terms.PaymentPreimage
+			hash := sha256.Sum256(preimage[:])
+			repairable := invoiceIndex != nil &&
+				preimage != UnknownPreimage &&
+				invoiceIndex.Get(hash[:]) == nil
+
+			addIssue(repairable, "invoice %d is missing from the "+
+				"payment hash index", num)
+			if repairable {
+				missing = append(missing, indexEntry{
+					index: invoiceIndex,
+					key:   hash[:],
+					value: invoiceKey[:],
+				})
+			}
+		}
+
+		seqIndexes := []struct {
+			name  string
+			index kvdb.Bucket
+			seqNo uint64
+		}{
+			{"add", addIndex, invoice.AddIndex},
+			{"settle", settleIndex, invoice.SettleIndex},
+		}
+		for _, s := range seqIndexes {
+			if s.index == nil || s.seqNo == 0 {
+				continue
+			}
+
+			var seqKey [8]byte
+			byteOrder.PutUint64(seqKey[:], s.seqNo)
+			if bytes.Equal(s.index.Get(seqKey[:]), invoiceKey[:]) {
+				continue
+			}
+
+			addIssue(true, "invoice %d is missing from the %v "+
+				"index", num, s.name)
+			missing = append(missing, indexEntry{
+				index: s.index,
+				key:   seqKey[:],
+				value: invoiceKey[:],
+			})
+		}
+	}
+
+	if !repair {
+		return issues, nil
+	}
+
+	deletions := []struct {
+		index kvdb.Bucket
+		keys  [][]byte
+	}{
+		{invoiceIndex, staleHashes},
+		{addIndex, staleAdds},
+		{settleIndex, staleSettles},
+	}
+	for _, d := range deletions {
+		for _, key := range d.keys {
+			if err := d.index.Delete(key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, entry := range missing {
+		if err := entry.index.Put(entry.key, entry.value); err != nil {
+			return nil, err
+		}
+	}
+
+	return issues, nil
+}
+
+// checkIntegrity checks that all channel edges refer to nodes within the
+// graph, and that all edge policies and index entries refer to existing
+// edges. Dangling edges and entries are deleted when repairing.
+func (c *ChannelGraph) checkIntegrity(repair bool) ([]IntegrityIssue, error) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	var (
+		issues          []IntegrityIssue
+		danglingChanIDs []uint64
+	)
+	check := func(tx kvdb.Tx) error {
+		issues = nil
+		danglingChanIDs = nil
+
+		nodes := tx.Bucket(nodeBucket)
+		edges := tx.Bucket(edgeBucket)
+		if nodes == nil || edges == nil {
+			return nil
+		}
+		edgeIndex := edges.Bucket(edgeIndexBucket)
+		if edgeIndex == nil {
+			return nil
+		}
+
+		addIssue := func(format string, a ...interface{}) {
+			issues = append(issues, IntegrityIssue{
+				Type:        IntegrityDanglingEdge,
+				Description: fmt.Sprintf(format, a...),
+				Repairable:  true,
+				Repaired:    repair,
+			})
+		}
+
+		// Each edge should refer to nodes within the graph. Those that
+		// don't are deleted along with all of their index entries
+		// below, as if they were never added.
+		var danglingEdges [][]byte
+		validEdges := make(map[uint64]struct{})
+		err := edgeIndex.ForEach(func(chanID, v []byte) error {
+			if len(chanID) != 8 {
+				return nil
+			}
+
+			cid := byteOrder.Uint64(chanID)
+			if len(v) < 66 || nodes.Get(v[:33]) == nil ||
+				nodes.Get(v[33:66]) == nil {
+
+				addIssue("channel %v refers to a missing node",
+					lnwire.NewShortChanIDFromInt(cid))
+				danglingEdges = append(
+					danglingEdges,
+					append([]byte(nil), chanID...),
+				)
+				danglingChanIDs = append(danglingChanIDs, cid)
+				return nil
+			}
+			validEdges[cid] = struct{}{}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// The edge policies, keyed by pubKey || chanID, as well as
+		// the channel point and update indexes should only refer to
+		// valid edges.
+		isDangling := func(chanID []byte) bool {
+			_, ok := validEdges[byteOrder.Uint64(chanID)]
+			return !ok
+		}
+
+		var danglingPolicies [][]byte
+		err = edges.ForEach(func(k, v []byte) error {
+			if v == nil || len(k) != 33+8 || !isDangling(k[33:]) {
+				return nil
+			}
+
+			addIssue("policy of node %x refers to missing channel "+
+				"%v", k[:33], lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(k[33:]),
+			))
+			danglingPolicies = append(
+				danglingPolicies, append([]byte(nil), k...),
+			)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		collectIndex := func(index kvdb.Bucket, name string,
+			chanIDOf func(k, v []byte) []byte) ([][]byte, error) {
+
+			if index == nil {
+				return nil, nil
+			}
+
+			var dangling [][]byte
+			err := index.ForEach(func(k, v []byte) error {
+				chanID := chanIDOf(k, v)
+				if len(chanID) != 8 || !isDangling(chanID) {
+					return nil
+				}
+
+				addIssue("%v index entry refers to missing "+
+					"channel %v", name,
+					lnwire.NewShortChanIDFromInt(
+						byteOrder.Uint64(chanID),
+					))
+				dangling = append(
+					dangling, append([]byte(nil), k...),
+				)
+				return nil
+			})
+
+			return dangling, err
+		}
+
+		chanIndex := edges.Bucket(channelPointBucket)
+		danglingChanPoints, err := collectIndex(
+			chanIndex, "channel point", func(_, v []byte) []byte {
+				return v
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		updateIndex := edges.Bucket(edgeUpdateIndexBucket)
+		danglingUpdates, err := collectIndex(
+			updateIndex, "update", func(k, _ []byte) []byte {
+				if len(k) != 8+8 {
+					return nil
+				}
+				return k[8:]
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if !repair {
+			return nil
+		}
+
+		deletions := []struct {
+			bucket kvdb.Bucket
+			keys   [][]byte
+		}{
+			{edgeIndex, danglingEdges},
+			{edges, danglingPolicies},
+			{chanIndex, danglingChanPoints},
+			{updateIndex, danglingUpdates},
+		}
+		for _, d := range deletions {
+			for _, key := range d.keys {
+				if err := d.bucket.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	var err error
+	if repair {
+		err = c.db.Update(check)
+	} else {
+		err = c.db.View(check)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The deleted edges may have been cached, so we'll remove them from
+	// the caches as well.
+	if repair {
+		for _, chanID := range danglingChanIDs {
+			c.rejectCache.remove(chanID)
+			c.chanCache.remove(chanID)
+		}
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,144 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestCheckIntegrity tests that invalid invoice indexes and orphaned
+// forwarding packages are reported, and only repaired if requested.
+func TestCheckIntegrity(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// A freshly created database should be consistent.
+	issues, err := db.CheckIntegrity(false)
+	if err != nil {
+		t.Fatalf("unable to check integrity: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	hashes := make([][32]byte, 2)
+	for i := range hashes {
+		invoice, err := randInvoice(amt)
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+
+		hashes[i] = sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+		if _, err := db.AddInvoice(invoice, hashes[i]); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+	}
+
+	// We'll now drop the first invoice from the payment hash index, the
+	// second from the add index, add an add index entry referring to a
+	// missing invoice, and add a forwarding package for a channel that
+	// doesn't exist.
+	err = db.Update(func(tx kvdb.Tx) error {
+		invoices := tx.Bucket(invoiceBucket)
+		invoiceIndex := invoices.Bucket(invoiceIndexBucket)
+		if err := invoiceIndex.Delete(hashes[0][:]); err != nil {
+			return err
+		}
+
+		addIndex := invoices.Bucket(addIndexBucket)
+		var addKey [8]byte
+		byteOrder.PutUint64(addKey[:], 2)
+		if err := addIndex.Delete(addKey[:]); err != nil {
+			return err
+		}
+
+		var invoiceKey [4]byte
+		byteOrder.PutUint32(invoiceKey[:], 100)
+		byteOrder.PutUint64(addKey[:], 100)
+		if err := addIndex.Put(addKey[:], invoiceKey[:]); err != nil {
+			return err
+		}
+
+		source := lnwire.NewShortChanIDFromInt(42)
+		packager := NewChannelPackager(source)
+		return packager.AddFwdPkg(tx, NewFwdPkg(source, 1, nil, nil))
+	})
+	if err != nil {
+		t.Fatalf("unable to corrupt db: %v", err)
+	}
+
+	expectedTypes := map[IntegrityIssueType]int{
+		IntegrityInvalidInvoiceIndex: 3,
+		IntegrityOrphanedHTLCs:       1,
+	}
+	assertIssues := func(issues []IntegrityIssue, repaired bool) {
+		t.Helper()
+
+		types := make(map[IntegrityIssueType]int)
+		for _, issue := range issues {
+			if !issue.Repairable {
+				t.Fatalf("expected issue to be repairable: %v",
+					issue.Description)
+			}
+			if issue.Repaired != repaired {
+				t.Fatalf("expected repaired=%v, got %v: %v",
+					repaired, issue.Repaired,
+					issue.Description)
+			}
+			types[issue.Type]++
+		}
+
+		if len(types) != len(expectedTypes) {
+			t.Fatalf("expected issues %v, got %v", expectedTypes,
+				types)
+		}
+		for issueType, num := range expectedTypes {
+			if types[issueType] != num {
+				t.Fatalf("expected %d issues of type %v, got "+
+					"%d", num, issueType, types[issueType])
+			}
+		}
+	}
+
+	// Checking without repairing should leave the database untouched,
+	// such that the same issues are reported again.
+	for i := 0; i < 2; i++ {
+		issues, err = db.CheckIntegrity(false)
+		if err != nil {
+			t.Fatalf("unable to check integrity: %v", err)
+		}
+		assertIssues(issues, false)
+	}
+
+	if _, err := db.LookupInvoice(hashes[0]); err != ErrInvoiceNotFound {
+		t.Fatalf("expected ErrInvoiceNotFound, got %v", err)
+	}
+
+	issues, err = db.CheckIntegrity(true)
+	if err != nil {
+		t.Fatalf("unable to repair db: %v", err)
+	}
+	assertIssues(issues, true)
+
+	// Once repaired, no issues should remain, and the first invoice
+	// should be found by its payment hash again.
+	issues, err = db.CheckIntegrity(false)
+	if err != nil {
+		t.Fatalf("unable to check integrity: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+
+	if _, err := db.LookupInvoice(hashes[0]); err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+}
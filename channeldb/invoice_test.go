@@ -1,12 +1,15 @@
 package channeldb
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/lnd/lntypes"
 	"github.com/litecoinfinance/lnd/lnwire"
 )
 
@@ -99,7 +102,8 @@ func TestInvoiceWorkflow(t *testing.T) {
 	// now have the settled bit toggle to true and a non-default
 	// SettledDate
 	payAmt := fakeInvoice.Terms.Value * 2
-	if _, err := db.AcceptOrSettleInvoice(paymentHash, payAmt); err != nil {
+	_, err = db.AcceptOrSettleInvoice(paymentHash, payAmt, nil)
+	if err != nil {
 		t.Fatalf("unable to settle invoice: %v", err)
 	}
 	dbInvoice2, err := db.LookupInvoice(paymentHash)
@@ -261,7 +265,7 @@ func TestInvoiceAddTimeSeries(t *testing.T) {
 
 		paymentHash := invoice.Terms.PaymentPreimage.Hash()
 
-		_, err := db.AcceptOrSettleInvoice(paymentHash, 0)
+		_, err := db.AcceptOrSettleInvoice(paymentHash, 0, nil)
 		if err != nil {
 			t.Fatalf("unable to settle invoice: %v", err)
 		}
@@ -342,7 +346,7 @@ func TestDuplicateSettleInvoice(t *testing.T) {
 	}
 
 	// With the invoice in the DB, we'll now attempt to settle the invoice.
-	dbInvoice, err := db.AcceptOrSettleInvoice(payHash, amt)
+	dbInvoice, err := db.AcceptOrSettleInvoice(payHash, amt, nil)
 	if err != nil {
 		t.Fatalf("unable to settle invoice: %v", err)
 	}
@@ -362,7 +366,7 @@ func TestDuplicateSettleInvoice(t *testing.T) {
 
 	// If we try to settle the invoice again, then we should get the very
 	// same invoice back, but with an error this time.
-	dbInvoice, err = db.AcceptOrSettleInvoice(payHash, amt)
+	dbInvoice, err = db.AcceptOrSettleInvoice(payHash, amt, nil)
 	if err != ErrInvoiceAlreadySettled {
 		t.Fatalf("expected ErrInvoiceAlreadySettled")
 	}
@@ -407,7 +411,8 @@ func TestQueryInvoices(t *testing.T) {
 
 		// We'll only settle half of all invoices created.
 		if i%2 == 0 {
-			if _, err := db.AcceptOrSettleInvoice(paymentHash, i); err != nil {
+			_, err := db.AcceptOrSettleInvoice(paymentHash, i, nil)
+			if err != nil {
 				t.Fatalf("unable to settle invoice: %v", err)
 			}
 		}
@@ -648,3 +653,483 @@ func TestQueryInvoices(t *testing.T) {
 		}
 	}
 }
+
+// TestQueryInvoicesFilters asserts that invoices can be filtered by their
+// state, creation date and memo, and that filtered invoices don't count
+// towards the maximum number of invoices returned.
+func TestQueryInvoicesFilters(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// We'll add 10 invoices, each created a minute after the previous
+	// one. Every third invoice is settled, and every even invoice has a
+	// coffee memo.
+	start := time.Unix(1500000000, 0)
+	const numInvoices = 10
+	for i := lnwire.MilliSatoshi(1); i <= numInvoices; i++ {
+		invoice, err := randInvoice(i)
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		invoice.CreationDate = start.Add(time.Duration(i) * time.Minute)
+		if i%2 == 0 {
+			invoice.Memo = []byte("one coffee")
+		}
+
+		paymentHash := invoice.Terms.PaymentPreimage.Hash()
+		if _, err := db.AddInvoice(invoice, paymentHash); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		if i%3 == 0 {
+			_, err := db.AcceptOrSettleInvoice(paymentHash, i, nil)
+			if err != nil {
+				t.Fatalf("unable to settle invoice: %v", err)
+			}
+		}
+	}
+
+	testCases := []struct {
+		query    InvoiceQuery
+		expected []lnwire.MilliSatoshi
+	}{
+		// Only the settled invoices should be returned.
+		{
+			query: InvoiceQuery{
+				States:         []ContractState{ContractSettled},
+				NumMaxInvoices: numInvoices,
+			},
+			expected: []lnwire.MilliSatoshi{3, 6, 9},
+		},
+		// Multiple states can be requested at once.
+		{
+			query: InvoiceQuery{
+				States: []ContractState{
+					ContractSettled, ContractCanceled,
+				},
+				NumMaxInvoices: numInvoices,
+			},
+			expected: []lnwire.MilliSatoshi{3, 6, 9},
+		},
+		// The creation date range includes its start, but not its end.
+		{
+			query: InvoiceQuery{
+				CreatedAfter:   start.Add(4 * time.Minute),
+				CreatedBefore:  start.Add(7 * time.Minute),
+				NumMaxInvoices: numInvoices,
+			},
+			expected: []lnwire.MilliSatoshi{4, 5, 6},
+		},
+		// The memo filter matches substrings.
+		{
+			query: InvoiceQuery{
+				MemoSubstring:  "coffee",
+				NumMaxInvoices: numInvoices,
+			},
+			expected: []lnwire.MilliSatoshi{2, 4, 6, 8, 10},
+		},
+		// Filters can be combined, and only the invoices matching them
+		// count towards the maximum returned.
+		{
+			query: InvoiceQuery{
+				States:         []ContractState{ContractOpen},
+				MemoSubstring:  "coffee",
+				NumMaxInvoices: 2,
+			},
+			expected: []lnwire.MilliSatoshi{2, 4},
+		},
+		// Paginating forwards from an offset should skip the invoices
+		// that don't match.
+		{
+			query: InvoiceQuery{
+				IndexOffset:    3,
+				States:         []ContractState{ContractOpen},
+				MemoSubstring:  "coffee",
+				NumMaxInvoices: 2,
+			},
+			expected: []lnwire.MilliSatoshi{4, 8},
+		},
+		// Combining a state with PendingOnly should only return the
+		// invoices satisfying both.
+		{
+			query: InvoiceQuery{
+				States:         []ContractState{ContractSettled},
+				PendingOnly:    true,
+				NumMaxInvoices: numInvoices,
+			},
+			expected: nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		response, err := db.QueryInvoices(testCase.query)
+		if err != nil {
+			t.Fatalf("unable to query invoice database: %v", err)
+		}
+
+		var amts []lnwire.MilliSatoshi
+		for _, invoice := range response.Invoices {
+			amts = append(amts, invoice.Terms.Value)
+		}
+		if !reflect.DeepEqual(amts, testCase.expected) {
+			t.Fatalf("test #%d: expected invoices %v, got %v", i,
+				testCase.expected, amts)
+		}
+	}
+}
+
+// TestInvoiceFiatRates asserts that the fiat metadata of invoices is
+// persisted, and that invoices stored without it can still be read.
+func TestInvoiceFiatRates(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	invoice, err := randInvoice(amt)
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	invoice.FiatCurrency = "USD"
+	invoice.CreationFiatRate = &FiatRate{
+		Rate:      100.5,
+		Timestamp: time.Unix(0, 1000),
+	}
+
+	payHash := invoice.Terms.PaymentPreimage.Hash()
+	if _, err := db.AddInvoice(invoice, payHash); err != nil {
+		t.Fatalf("unable to add invoice %v", err)
+	}
+
+	dbInvoice, err := db.LookupInvoice(payHash)
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	if !reflect.DeepEqual(&dbInvoice, invoice) {
+		t.Fatalf("wrong invoice, expected %v got %v",
+			spew.Sdump(invoice), spew.Sdump(dbInvoice))
+	}
+
+	// The settle rate can't be recorded before the invoice is settled.
+	settleRate := &FiatRate{
+		Rate:      101.5,
+		Timestamp: time.Unix(0, 2000),
+	}
+	_, err = db.SetInvoiceSettleFiatRate(payHash, settleRate)
+	if err == nil {
+		t.Fatalf("expected settle rate of open invoice to be rejected")
+	}
+
+	if _, err := db.AcceptOrSettleInvoice(payHash, amt, nil); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	_, err = db.SetInvoiceSettleFiatRate(payHash, settleRate)
+	if err != nil {
+		t.Fatalf("unable to set settle rate: %v", err)
+	}
+
+	dbInvoice, err = db.LookupInvoice(payHash)
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	if !reflect.DeepEqual(dbInvoice.SettleFiatRate, settleRate) {
+		t.Fatalf("expected settle rate %v, got %v", settleRate,
+			dbInvoice.SettleFiatRate)
+	}
+	if dbInvoice.FiatCurrency != "USD" {
+		t.Fatalf("expected fiat currency USD, got %v",
+			dbInvoice.FiatCurrency)
+	}
+
+	// An invoice serialized before the fiat metadata was introduced lacks
+	// the trailing currency and rates, which should be tolerated.
+	invoice.FiatCurrency = ""
+	invoice.CreationFiatRate = nil
+	var b bytes.Buffer
+	if err := serializeInvoice(&b, invoice); err != nil {
+		t.Fatalf("unable to serialize invoice: %v", err)
+	}
+	legacy := b.Bytes()[:b.Len()-3]
+
+	legacyInvoice, err := deserializeInvoice(bytes.NewReader(legacy))
+	if err != nil {
+		t.Fatalf("unable to deserialize legacy invoice: %v", err)
+	}
+	if !reflect.DeepEqual(&legacyInvoice, invoice) {
+		t.Fatalf("wrong legacy invoice, expected %v got %v",
+			spew.Sdump(invoice), spew.Sdump(legacyInvoice))
+	}
+}
+
+// TestDeleteInvoices tests that the invoices selected by a deletion query are
+// removed along with their index entries, while the remaining invoices can
+// still be queried in both directions.
+func TestDeleteInvoices(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	const (
+		oldOpen = iota
+		newOpen
+		settled
+		canceled
+		accepted
+		numInvoices
+	)
+
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	hashes := make([]lntypes.Hash, numInvoices)
+	for i := range hashes {
+		invoice, err := randInvoice(amt)
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+
+		switch i {
+		case oldOpen:
+			invoice.CreationDate = invoice.CreationDate.Add(
+				-time.Hour,
+			)
+
+		// The preimage of a hold invoice is unknown, such that it
+		// will be accepted rather than settled once paid.
+		case accepted:
+			invoice.Terms.PaymentPreimage = UnknownPreimage
+		}
+
+		hashes[i] = lntypes.Hash(sha256.Sum256([]byte{byte(i)}))
+		if _, err := db.AddInvoice(invoice, hashes[i]); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+	}
+
+	for _, i := range []int{settled, accepted} {
+		_, err := db.AcceptOrSettleInvoice(hashes[i], amt, nil)
+		if err != nil {
+			t.Fatalf("unable to pay invoice: %v", err)
+		}
+	}
+	if _, err := db.CancelInvoice(hashes[canceled]); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+
+	// Settling the invoice is preceded by its creation, so the settled
+	// invoice isn't selected by a cutoff between the two.
+	settledInvoice, err := db.LookupInvoice(hashes[settled])
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	numDeleted, err := db.DeleteInvoices(InvoiceDeletionQuery{
+		SettledBefore: settledInvoice.SettleDate,
+	})
+	if err != nil {
+		t.Fatalf("unable to delete invoices: %v", err)
+	}
+	if numDeleted != 0 {
+		t.Fatalf("expected no deleted invoices, got %v", numDeleted)
+	}
+
+	numDeleted, err = db.DeleteInvoices(InvoiceDeletionQuery{
+		SettledBefore: settledInvoice.SettleDate.Add(time.Second),
+		Canceled:      true,
+		OpenBefore:    time.Now().Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("unable to delete invoices: %v", err)
+	}
+	if numDeleted != 3 {
+		t.Fatalf("expected 3 deleted invoices, got %v", numDeleted)
+	}
+
+	for i, hash := range hashes {
+		_, err := db.LookupInvoice(hash)
+		switch i {
+		case oldOpen, settled, canceled:
+			if err != ErrInvoiceNotFound {
+				t.Fatalf("expected invoice %d to be deleted, "+
+					"got %v", i, err)
+			}
+
+		default:
+			if err != nil {
+				t.Fatalf("unable to lookup invoice %d: %v", i,
+					err)
+			}
+		}
+	}
+
+	settledSince, err := db.InvoicesSettledSince(0)
+	if err != nil {
+		t.Fatalf("unable to query settled invoices: %v", err)
+	}
+	if len(settledSince) != 0 {
+		t.Fatalf("expected no settled invoices, got %v",
+			len(settledSince))
+	}
+
+	// Paginating backwards from the last remaining invoice should skip
+	// over the gaps left within the add index.
+	lastInvoice, err := db.LookupInvoice(hashes[accepted])
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	resp, err := db.QueryInvoices(InvoiceQuery{
+		IndexOffset:    lastInvoice.AddIndex,
+		NumMaxInvoices: 10,
+		Reversed:       true,
+	})
+	if err != nil {
+		t.Fatalf("unable to query invoices: %v", err)
+	}
+	if len(resp.Invoices) != 1 ||
+		resp.Invoices[0].Terms.State != ContractOpen {
+
+		t.Fatalf("expected the new open invoice, got %v",
+			spew.Sdump(resp.Invoices))
+	}
+}
+
+// TestInvoiceHtlcs tests that the htlcs paying to an invoice are recorded
+// along with it, and resolved along with the invoice.
+func TestInvoiceHtlcs(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	invoice, err := randInvoice(amt)
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	preimage := invoice.Terms.PaymentPreimage
+	payHash := preimage.Hash()
+
+	// Add the invoice as a hold invoice, such that its htlcs are held
+	// until it's settled.
+	invoice.Terms.PaymentPreimage = UnknownPreimage
+	if _, err := db.AddInvoice(invoice, payHash); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	htlcDesc := func(chanID, htlcID uint64) *HtlcAcceptDesc {
+		return &HtlcAcceptDesc{
+			CircuitKey: CircuitKey{
+				ChanID: lnwire.NewShortChanIDFromInt(chanID),
+				HtlcID: htlcID,
+			},
+			AcceptHeight: 100,
+			Expiry:       140,
+		}
+	}
+	first, second, third := htlcDesc(1, 1), htlcDesc(2, 1), htlcDesc(1, 2)
+
+	// assertHtlcStates asserts that the invoice holds exactly the given
+	// htlcs in the given states.
+	assertHtlcStates := func(invoice *Invoice,
+		expected map[*HtlcAcceptDesc]HtlcState) {
+
+		t.Helper()
+
+		if len(invoice.Htlcs) != len(expected) {
+			t.Fatalf("expected %d htlcs, got %d", len(expected),
+				len(invoice.Htlcs))
+		}
+		for desc, state := range expected {
+			htlc, ok := invoice.Htlcs[desc.CircuitKey]
+			switch {
+			case !ok:
+				t.Fatalf("htlc %v not recorded", desc.CircuitKey)
+
+			case htlc.State != state:
+				t.Fatalf("expected htlc %v to be %v, got %v",
+					desc.CircuitKey, state, htlc.State)
+
+			case htlc.Amt != amt ||
+				htlc.AcceptHeight != desc.AcceptHeight ||
+				htlc.Expiry != desc.Expiry:
+
+				t.Fatalf("unexpected htlc %v: %v",
+					desc.CircuitKey, spew.Sdump(htlc))
+
+			case (state == HtlcStateAccepted) !=
+				htlc.ResolveTime.IsZero():
+
+				t.Fatalf("unexpected resolve time of htlc %v: "+
+					"%v", desc.CircuitKey, htlc.ResolveTime)
+			}
+		}
+	}
+
+	// The first htlc accepts the invoice.
+	dbInvoice, err := db.AcceptOrSettleInvoice(payHash, amt, first)
+	if err != nil {
+		t.Fatalf("unable to accept invoice: %v", err)
+	}
+	if dbInvoice.Terms.State != ContractAccepted {
+		t.Fatalf("expected invoice to be accepted, got %v",
+			dbInvoice.Terms.State)
+	}
+	assertHtlcStates(dbInvoice, map[*HtlcAcceptDesc]HtlcState{
+		first: HtlcStateAccepted,
+	})
+
+	// A second htlc is recorded as well, while notifying the first one
+	// once more leaves the invoice untouched.
+	dbInvoice, err = db.AcceptOrSettleInvoice(payHash, amt, second)
+	if err != nil {
+		t.Fatalf("unable to accept second htlc: %v", err)
+	}
+	_, err = db.AcceptOrSettleInvoice(payHash, amt, first)
+	if err != ErrInvoiceAlreadyAccepted {
+		t.Fatalf("expected ErrInvoiceAlreadyAccepted, got %v", err)
+	}
+	assertHtlcStates(dbInvoice, map[*HtlcAcceptDesc]HtlcState{
+		first:  HtlcStateAccepted,
+		second: HtlcStateAccepted,
+	})
+
+	// Settling the invoice settles all of its htlcs.
+	dbInvoice, err = db.SettleHoldInvoice(preimage)
+	if err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	assertHtlcStates(dbInvoice, map[*HtlcAcceptDesc]HtlcState{
+		first:  HtlcStateSettled,
+		second: HtlcStateSettled,
+	})
+
+	// An htlc paying to the settled invoice is recorded as settled right
+	// away, and all htlcs survive a round trip through the database.
+	_, err = db.AcceptOrSettleInvoice(payHash, amt, third)
+	if err != ErrInvoiceAlreadySettled {
+		t.Fatalf("expected ErrInvoiceAlreadySettled, got %v", err)
+	}
+
+	lookedUp, err := db.LookupInvoice(payHash)
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	assertHtlcStates(&lookedUp, map[*HtlcAcceptDesc]HtlcState{
+		first:  HtlcStateSettled,
+		second: HtlcStateSettled,
+		third:  HtlcStateSettled,
+	})
+}
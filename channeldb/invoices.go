@@ -6,10 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"time"
 
 	"github.com/litecoinfinance/btcd/wire"
-	"github.com/coreos/bbolt"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lntypes"
 	"github.com/litecoinfinance/lnd/lnwire"
 )
@@ -92,6 +93,10 @@ const (
 	// TODO(halseth): determine the max length payment request when field
 	// lengths are final.
 	MaxPaymentRequestSize = 4096
+
+	// FiatCurrencySize is the size of the ISO 4217 code of the fiat
+	// currency an invoice may be denominated in.
+	FiatCurrencySize = 3
 )
 
 // ContractState describes the state the invoice is in.
@@ -152,8 +157,9 @@ type ContractTerm struct {
 // existing financial system within PayPal, etc.  Invoices are added to the
 // database when a payment is requested, then can be settled manually once the
 // payment is received at the upper layer. For record keeping purposes,
-// invoices are never deleted from the database, instead a bit is toggled
-// denoting the invoice has been fully settled. Within the database, all
+// invoices aren't deleted from the database once settled, instead a bit is
+// toggled denoting the invoice has been fully settled. They're only removed
+// when explicitly requested through DeleteInvoices. Within the database, all
 // invoices must have a unique payment hash which is generated by taking the
 // sha256 of the payment preimage.
 type Invoice struct {
@@ -209,6 +215,104 @@ type Invoice struct {
 	// that the invoice originally didn't specify an amount, or the sender
 	// overpaid.
 	AmtPaid lnwire.MilliSatoshi
+
+	// FiatCurrency is the optional ISO 4217 code of the fiat currency in
+	// which the invoice's value should be displayed and accounted for.
+	FiatCurrency string
+
+	// CreationFiatRate is the exchange rate of the FiatCurrency at the
+	// time the invoice was created. It is nil if no rate was available.
+	CreationFiatRate *FiatRate
+
+	// SettleFiatRate is the exchange rate of the FiatCurrency at the time
+	// the invoice was settled. It is nil if the invoice hasn't been
+	// settled, or no rate was available.
+	SettleFiatRate *FiatRate
+
+	// Htlcs records the htlcs that paid to this invoice, keyed by the
+	// channel and index they were received with. Htlcs settled on-chain
+	// without having been received off-chain first aren't recorded.
+	Htlcs map[CircuitKey]*InvoiceHTLC
+}
+
+// HtlcState defines the states an htlc paying to an invoice can be in.
+type HtlcState uint8
+
+const (
+	// HtlcStateAccepted indicates the htlc is held until the invoice is
+	// either settled or canceled.
+	HtlcStateAccepted HtlcState = 0
+
+	// HtlcStateSettled indicates the htlc was settled.
+	HtlcStateSettled HtlcState = 1
+
+	// HtlcStateCanceled indicates the htlc was canceled back.
+	HtlcStateCanceled HtlcState = 2
+)
+
+// String returns a human readable identifier for the htlc state.
+func (s HtlcState) String() string {
+	switch s {
+	case HtlcStateAccepted:
+		return "Accepted"
+	case HtlcStateSettled:
+		return "Settled"
+	case HtlcStateCanceled:
+		return "Canceled"
+	}
+
+	return "Unknown"
+}
+
+// InvoiceHTLC describes an htlc that paid to an invoice.
+type InvoiceHTLC struct {
+	// Amt is the amount that is carried by this htlc.
+	Amt lnwire.MilliSatoshi
+
+	// AcceptHeight is the block height at which the htlc was accepted.
+	AcceptHeight uint32
+
+	// AcceptTime is the time at which the htlc was accepted.
+	AcceptTime time.Time
+
+	// ResolveTime is the time at which the htlc was settled or canceled.
+	// It is the zero value as long as the htlc is accepted.
+	ResolveTime time.Time
+
+	// Expiry is the height at which the htlc expires.
+	Expiry uint32
+
+	// State indicates the state the htlc is currently in.
+	State HtlcState
+}
+
+// HtlcAcceptDesc describes an htlc that pays to an invoice, such that it can
+// be recorded along with the invoice.
+type HtlcAcceptDesc struct {
+	// CircuitKey is the channel and index the htlc was received with.
+	CircuitKey CircuitKey
+
+	// AcceptHeight is the current block height.
+	AcceptHeight uint32
+
+	// Expiry is the height at which the htlc expires.
+	Expiry uint32
+}
+
+// FiatRate is a snapshot of the exchange rate between the chain's native
+// currency and a fiat currency.
+type FiatRate struct {
+	// Rate is the value of a single coin, i.e. 100,000,000 of the chain's
+	// base units, in the fiat currency.
+	Rate float64
+
+	// Timestamp is the time at which the rate was obtained.
+	Timestamp time.Time
+}
+
+// Amount returns the value of the given amount in the fiat currency.
+func (f *FiatRate) Amount(amt lnwire.MilliSatoshi) float64 {
+	return amt.ToBTC() * f.Rate
 }
 
 func validateInvoice(i *Invoice) error {
@@ -226,6 +330,10 @@ func validateInvoice(i *Invoice) error {
 			"provided was %v", MaxPaymentRequestSize,
 			len(i.PaymentRequest))
 	}
+	if i.FiatCurrency != "" && len(i.FiatCurrency) != FiatCurrencySize {
+		return fmt.Errorf("fiat currency must be a %v letter code, "+
+			"got %q", FiatCurrencySize, i.FiatCurrency)
+	}
 	return nil
 }
 
@@ -242,7 +350,7 @@ func (d *DB) AddInvoice(newInvoice *Invoice, paymentHash lntypes.Hash) (
 	}
 
 	var invoiceAddIndex uint64
-	err := d.Update(func(tx *bbolt.Tx) error {
+	err := d.Update(func(tx kvdb.Tx) error {
 		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
 		if err != nil {
 			return err
@@ -320,7 +428,7 @@ func (d *DB) InvoicesAddedSince(sinceAddIndex uint64) ([]Invoice, error) {
 	var startIndex [8]byte
 	byteOrder.PutUint64(startIndex[:], sinceAddIndex)
 
-	err := d.DB.View(func(tx *bbolt.Tx) error {
+	err := d.View(func(tx kvdb.Tx) error {
 		invoices := tx.Bucket(invoiceBucket)
 		if invoices == nil {
 			return ErrNoInvoicesCreated
@@ -375,7 +483,7 @@ func (d *DB) InvoicesAddedSince(sinceAddIndex uint64) ([]Invoice, error) {
 // terms of the payment.
 func (d *DB) LookupInvoice(paymentHash [32]byte) (Invoice, error) {
 	var invoice Invoice
-	err := d.View(func(tx *bbolt.Tx) error {
+	err := d.View(func(tx kvdb.Tx) error {
 		invoices := tx.Bucket(invoiceBucket)
 		if invoices == nil {
 			return ErrNoInvoicesCreated
@@ -415,7 +523,7 @@ func (d *DB) LookupInvoice(paymentHash [32]byte) (Invoice, error) {
 func (d *DB) FetchAllInvoices(pendingOnly bool) ([]Invoice, error) {
 	var invoices []Invoice
 
-	err := d.View(func(tx *bbolt.Tx) error {
+	err := d.View(func(tx kvdb.Tx) error {
 		invoiceB := tx.Bucket(invoiceBucket)
 		if invoiceB == nil {
 			return ErrNoInvoicesCreated
@@ -472,6 +580,63 @@ type InvoiceQuery struct {
 	// Reversed, if set, indicates that the invoices returned should start
 	// from the IndexOffset and go backwards.
 	Reversed bool
+
+	// States, if non-empty, restricts the invoices returned to those in
+	// one of the given states.
+	States []ContractState
+
+	// CreatedAfter, if non-zero, restricts the invoices returned to those
+	// created at or after this time.
+	CreatedAfter time.Time
+
+	// CreatedBefore, if non-zero, restricts the invoices returned to
+	// those created before this time.
+	CreatedBefore time.Time
+
+	// MemoSubstring, if non-empty, restricts the invoices returned to
+	// those whose memo contains it.
+	MemoSubstring string
+}
+
+// matches returns whether the invoice satisfies the filters of the query.
+// Invoices that don't are skipped without counting towards NumMaxInvoices.
+func (q *InvoiceQuery) matches(invoice *Invoice) bool {
+	state := invoice.Terms.State
+	if q.PendingOnly && state == ContractSettled {
+		return false
+	}
+
+	if len(q.States) > 0 {
+		var found bool
+		for _, s := range q.States {
+			if s == state {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	switch {
+	case !q.CreatedAfter.IsZero() &&
+		invoice.CreationDate.Before(q.CreatedAfter):
+
+		return false
+
+	case !q.CreatedBefore.IsZero() &&
+		!invoice.CreationDate.Before(q.CreatedBefore):
+
+		return false
+
+	case q.MemoSubstring != "" &&
+		!bytes.Contains(invoice.Memo, []byte(q.MemoSubstring)):
+
+		return false
+	}
+
+	return true
 }
 
 // InvoiceSlice is the response to a invoice query. It includes the original
@@ -500,13 +665,13 @@ type InvoiceSlice struct {
 }
 
 // QueryInvoices allows a caller to query the invoice database for invoices
-// within the specified add index range.
+// within the specified add index range, matching the filters of the query.
 func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 	resp := InvoiceSlice{
 		InvoiceQuery: q,
 	}
 
-	err := d.View(func(tx *bbolt.Tx) error {
+	err := d.View(func(tx kvdb.Tx) error {
 		// If the bucket wasn't found, then there aren't any invoices
 		// within the database yet, so we can simply exit.
 		invoices := tx.Bucket(invoiceBucket)
@@ -520,7 +685,7 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 
 		// keyForIndex is a helper closure that retrieves the invoice
 		// key for the given add index of an invoice.
-		keyForIndex := func(c *bbolt.Cursor, index uint64) []byte {
+		keyForIndex := func(c kvdb.Cursor, index uint64) []byte {
 			var keyIndex [8]byte
 			byteOrder.PutUint64(keyIndex[:], index)
 			_, invoiceKey := c.Seek(keyIndex[:])
@@ -529,7 +694,7 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 
 		// nextKey is a helper closure to determine what the next
 		// invoice key is when iterating over the invoice add index.
-		nextKey := func(c *bbolt.Cursor) ([]byte, []byte) {
+		nextKey := func(c kvdb.Cursor) ([]byte, []byte) {
 			if q.Reversed {
 				return c.Prev()
 			}
@@ -561,9 +726,17 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 				return nil
 
 			// Otherwise we start iteration at the invoice prior to
-			// the offset.
+			// the offset. As invoices may have been deleted, we'll
+			// seek to the offset itself and step back from there.
 			default:
-				invoiceKey = keyForIndex(c, q.IndexOffset-1)
+				var keyIndex [8]byte
+				byteOrder.PutUint64(keyIndex[:], q.IndexOffset)
+				addSeqNo, _ := c.Seek(keyIndex[:])
+				if addSeqNo == nil {
+					_, invoiceKey = c.Last()
+				} else {
+					_, invoiceKey = c.Prev()
+				}
 			}
 		}
 
@@ -583,11 +756,9 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 				return err
 			}
 
-			// Skip any settled invoices if the caller is only
-			// interested in unsettled.
-			if q.PendingOnly &&
-				invoice.Terms.State == ContractSettled {
-
+			// Skip any invoices the caller isn't interested in,
+			// such as settled ones if only unsettled are queried.
+			if !q.matches(&invoice) {
 				continue
 			}
 
@@ -631,11 +802,19 @@ func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
 //
 // When the preimage for the invoice is unknown (hold invoice), the invoice is
 // marked as accepted.
+//
+// If htlc is non-nil, the htlc is recorded along with the invoice, even if the
+// invoice was already accepted, settled or canceled before. If the invoice was
+// already accepted and the htlc is recorded for the first time, no error is
+// returned, as the invoice was updated just like when it's accepted.
 func (d *DB) AcceptOrSettleInvoice(paymentHash [32]byte,
-	amtPaid lnwire.MilliSatoshi) (*Invoice, error) {
+	amtPaid lnwire.MilliSatoshi, htlc *HtlcAcceptDesc) (*Invoice, error) {
 
-	var settledInvoice *Invoice
-	err := d.Update(func(tx *bbolt.Tx) error {
+	var (
+		settledInvoice *Invoice
+		stateErr       error
+	)
+	err := d.Update(func(tx kvdb.Tx) error {
 		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
 		if err != nil {
 			return err
@@ -661,13 +840,27 @@ func (d *DB) AcceptOrSettleInvoice(paymentHash [32]byte,
 		}
 
 		settledInvoice, err = acceptOrSettleInvoice(
-			invoices, settleIndex, invoiceNum, amtPaid,
+			invoices, settleIndex, invoiceNum, amtPaid, htlc,
 		)
 
+		// The htlc is recorded regardless of the state the invoice
+		// was already in, so we'll only report the state once the
+		// transaction has been committed.
+		switch err {
+		case ErrInvoiceAlreadyAccepted, ErrInvoiceAlreadySettled,
+			ErrInvoiceAlreadyCanceled:
+
+			stateErr = err
+			return nil
+		}
+
 		return err
 	})
+	if err != nil {
+		return settledInvoice, err
+	}
 
-	return settledInvoice, err
+	return settledInvoice, stateErr
 }
 
 // SettleHoldInvoice sets the preimage of a hodl invoice and marks the invoice
@@ -675,7 +868,7 @@ func (d *DB) AcceptOrSettleInvoice(paymentHash [32]byte,
 func (d *DB) SettleHoldInvoice(preimage lntypes.Preimage) (*Invoice, error) {
 	var updatedInvoice *Invoice
 	hash := preimage.Hash()
-	err := d.Update(func(tx *bbolt.Tx) error {
+	err := d.Update(func(tx kvdb.Tx) error {
 		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
 		if err != nil {
 			return err
@@ -714,7 +907,7 @@ func (d *DB) SettleHoldInvoice(preimage lntypes.Preimage) (*Invoice, error) {
 // payment hash.
 func (d *DB) CancelInvoice(paymentHash lntypes.Hash) (*Invoice, error) {
 	var canceledInvoice *Invoice
-	err := d.Update(func(tx *bbolt.Tx) error {
+	err := d.Update(func(tx kvdb.Tx) error {
 		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
 		if err != nil {
 			return err
@@ -741,6 +934,179 @@ func (d *DB) CancelInvoice(paymentHash lntypes.Hash) (*Invoice, error) {
 	return canceledInvoice, err
 }
 
+// InvoiceDeletionQuery selects the invoices to be deleted by DeleteInvoices.
+// Accepted invoices, whose HTLCs are still held, are never deleted.
+type InvoiceDeletionQuery struct {
+	// SettledBefore selects the invoices that were settled before this
+	// time. The zero value selects none.
+	SettledBefore time.Time
+
+	// Canceled selects all canceled invoices.
+	Canceled bool
+
+	// OpenBefore selects the open invoices, which haven't been paid yet,
+	// that were created before this time. The zero value selects none.
+	OpenBefore time.Time
+}
+
+// matches returns whether the invoice is selected by the query.
+func (q *InvoiceDeletionQuery) matches(invoice *Invoice) bool {
+	switch invoice.Terms.State {
+	case ContractSettled:
+		return invoice.SettleDate.Before(q.SettledBefore)
+
+	case ContractCanceled:
+		return q.Canceled
+
+	case ContractOpen:
+		return invoice.CreationDate.Before(q.OpenBefore)
+
+	default:
+		return false
+	}
+}
+
+// DeleteInvoices deletes the invoices selected by the query, along with their
+// entries within the payment hash, add and settle indexes. The number of
+// invoices deleted is returned. The add and settle indexes of the remaining
+// invoices are left unchanged.
+func (d *DB) DeleteInvoices(q InvoiceDeletionQuery) (int, error) {
+	var numDeleted int
+	err := d.Update(func(tx kvdb.Tx) error {
+		numDeleted = 0
+
+		invoices := tx.Bucket(invoiceBucket)
+		if invoices == nil {
+			return nil
+		}
+		invoiceIndex := invoices.Bucket(invoiceIndexBucket)
+		if invoiceIndex == nil {
+			return nil
+		}
+
+		// The invoices themselves don't store their payment hash, as
+		// the preimage of hold invoices may be unknown, so we'll
+		// consult the payment hash index to find it.
+		payHashes := make(map[string][]byte)
+		err := invoiceIndex.ForEach(func(k, v []byte) error {
+			if bytes.Equal(k, numInvoicesKey) {
+				return nil
+			}
+
+			payHashes[string(v)] = append([]byte(nil), k...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// With the payment hashes known, we'll collect the invoices to
+		// delete, as keys can't be deleted while iterating over them.
+		type deletedInvoice struct {
+			invoiceNum []byte
+			payHash    []byte
+			invoice    Invoice
+		}
+		var deleted []deletedInvoice
+		err = invoices.ForEach(func(k, v []byte) error {
+			// Skip the nested index buckets.
+			if v == nil {
+				return nil
+			}
+
+			invoice, err := deserializeInvoice(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			if q.matches(&invoice) {
+				deleted = append(deleted, deletedInvoice{
+					invoiceNum: append([]byte(nil), k...),
+					payHash:    payHashes[string(k)],
+					invoice:    invoice,
+				})
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		addIndex := invoices.Bucket(addIndexBucket)
+		settleIndex := invoices.Bucket(settleIndexBucket)
+		for _, del := range deleted {
+			err := deleteInvoice(
+				invoices, invoiceIndex, addIndex, settleIndex,
+				del.invoiceNum, del.payHash, &del.invoice,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		numDeleted = len(deleted)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numDeleted, nil
+}
+
+// SetInvoiceSettleFiatRate records the exchange rate of the fiat currency of a
+// settled invoice at the time of its settlement. The updated invoice is
+// returned.
+func (d *DB) SetInvoiceSettleFiatRate(paymentHash lntypes.Hash,
+	rate *FiatRate) (*Invoice, error) {
+
+	var updatedInvoice *Invoice
+	err := d.Update(func(tx kvdb.Tx) error {
+		invoices := tx.Bucket(invoiceBucket)
+		if invoices == nil {
+			return ErrNoInvoicesCreated
+		}
+		invoiceIndex := invoices.Bucket(invoiceIndexBucket)
+		if invoiceIndex == nil {
+			return ErrNoInvoicesCreated
+		}
+
+		invoiceNum := invoiceIndex.Get(paymentHash[:])
+		if invoiceNum == nil {
+			return ErrInvoiceNotFound
+		}
+
+		invoice, err := fetchInvoice(invoiceNum, invoices)
+		if err != nil {
+			return err
+		}
+
+		if invoice.Terms.State != ContractSettled {
+			return fmt.Errorf("unable to set settle fiat rate of "+
+				"invoice in state %v", invoice.Terms.State)
+		}
+
+		invoice.SettleFiatRate = rate
+
+		var buf bytes.Buffer
+		if err := serializeInvoice(&buf, &invoice); err != nil {
+			return err
+		}
+		if err := invoices.Put(invoiceNum, buf.Bytes()); err != nil {
+			return err
+		}
+
+		updatedInvoice = &invoice
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedInvoice, nil
+}
+
 // InvoicesSettledSince can be used by callers to catch up any settled invoices
 // they missed within the settled invoice time series. We'll return all known
 // settled invoice that have a settle index higher than the passed
@@ -760,7 +1126,7 @@ func (d *DB) InvoicesSettledSince(sinceSettleIndex uint64) ([]Invoice, error) {
 	var startIndex [8]byte
 	byteOrder.PutUint64(startIndex[:], sinceSettleIndex)
 
-	err := d.DB.View(func(tx *bbolt.Tx) error {
+	err := d.View(func(tx kvdb.Tx) error {
 		invoices := tx.Bucket(invoiceBucket)
 		if invoices == nil {
 			return ErrNoInvoicesCreated
@@ -802,7 +1168,7 @@ func (d *DB) InvoicesSettledSince(sinceSettleIndex uint64) ([]Invoice, error) {
 	return settledInvoices, nil
 }
 
-func putInvoice(invoices, invoiceIndex, addIndex *bbolt.Bucket,
+func putInvoice(invoices, invoiceIndex, addIndex kvdb.Bucket,
 	i *Invoice, invoiceNum uint32, paymentHash lntypes.Hash) (
 	uint64, error) {
 
@@ -860,6 +1226,40 @@ func putInvoice(invoices, invoiceIndex, addIndex *bbolt.Bucket,
 	return nextAddSeqNo, nil
 }
 
+// deleteInvoice removes the invoice from the invoice bucket, along with its
+// entries within the payment hash, add and settle indexes. The index buckets
+// may be nil if they haven't been created yet.
+func deleteInvoice(invoices, invoiceIndex, addIndex, settleIndex kvdb.Bucket,
+	invoiceNum, payHash []byte, invoice *Invoice) error {
+
+	if err := invoices.Delete(invoiceNum); err != nil {
+		return err
+	}
+
+	if payHash != nil {
+		if err := invoiceIndex.Delete(payHash); err != nil {
+			return err
+		}
+	}
+
+	var seqNo [8]byte
+	if addIndex != nil && invoice.AddIndex != 0 {
+		byteOrder.PutUint64(seqNo[:], invoice.AddIndex)
+		if err := addIndex.Delete(seqNo[:]); err != nil {
+			return err
+		}
+	}
+
+	if settleIndex != nil && invoice.SettleIndex != 0 {
+		byteOrder.PutUint64(seqNo[:], invoice.SettleIndex)
+		if err := settleIndex.Delete(seqNo[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func serializeInvoice(w io.Writer, i *Invoice) error {
 	if err := wire.WriteVarBytes(w, 0, i.Memo[:]); err != nil {
 		return err
@@ -913,10 +1313,83 @@ func serializeInvoice(w io.Writer, i *Invoice) error {
 		return err
 	}
 
+	// The fiat metadata is written last, such that invoices stored before
+	// it was introduced can still be read.
+	err = wire.WriteVarBytes(w, 0, []byte(i.FiatCurrency))
+	if err != nil {
+		return err
+	}
+	if err := serializeFiatRate(w, i.CreationFiatRate); err != nil {
+		return err
+	}
+	if err := serializeFiatRate(w, i.SettleFiatRate); err != nil {
+		return err
+	}
+
+	return serializeHtlcs(w, i.Htlcs)
+}
+
+func serializeFiatRate(w io.Writer, rate *FiatRate) error {
+	if rate == nil {
+		return binary.Write(w, byteOrder, false)
+	}
+
+	if err := binary.Write(w, byteOrder, true); err != nil {
+		return err
+	}
+	rateBits := math.Float64bits(rate.Rate)
+	if err := binary.Write(w, byteOrder, rateBits); err != nil {
+		return err
+	}
+
+	return binary.Write(w, byteOrder, rate.Timestamp.UnixNano())
+}
+
+// serializeHtlcs writes the htlcs of an invoice to w.
+func serializeHtlcs(w io.Writer, htlcs map[CircuitKey]*InvoiceHTLC) error {
+	numHtlcs := uint32(len(htlcs))
+	if err := binary.Write(w, byteOrder, numHtlcs); err != nil {
+		return err
+	}
+
+	for key, htlc := range htlcs {
+		fields := []interface{}{
+			key.ChanID.ToUint64(), key.HtlcID, uint64(htlc.Amt),
+			htlc.AcceptHeight, timeToUnixNano(htlc.AcceptTime),
+			timeToUnixNano(htlc.ResolveTime), htlc.Expiry,
+			uint8(htlc.State),
+		}
+		for _, field := range fields {
+			err := binary.Write(w, byteOrder, field)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
-func fetchInvoice(invoiceNum []byte, invoices *bbolt.Bucket) (Invoice, error) {
+// timeToUnixNano returns the unix timestamp in nanoseconds of t, or zero if t
+// is the zero value.
+func timeToUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixNano()
+}
+
+// unixNanoToTime is the inverse of timeToUnixNano.
+func unixNanoToTime(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nanos)
+}
+
+func fetchInvoice(invoiceNum []byte, invoices kvdb.Bucket) (Invoice, error) {
 	invoiceBytes := invoices.Get(invoiceNum)
 	if invoiceBytes == nil {
 		return Invoice{}, ErrInvoiceNotFound
@@ -985,26 +1458,153 @@ func deserializeInvoice(r io.Reader) (Invoice, error) {
 		return invoice, err
 	}
 
+	// Invoices stored before the fiat metadata was introduced end here, so
+	// we'll ignore the EOF error and return the invoice as is.
+	fiatCurrency, err := wire.ReadVarBytes(
+		r, 0, FiatCurrencySize, "fiat currency",
+	)
+	switch {
+	case err == io.EOF:
+		return invoice, nil
+	case err != nil:
+		return invoice, err
+	}
+	invoice.FiatCurrency = string(fiatCurrency)
+
+	invoice.CreationFiatRate, err = deserializeFiatRate(r)
+	if err != nil {
+		return invoice, err
+	}
+	invoice.SettleFiatRate, err = deserializeFiatRate(r)
+	if err != nil {
+		return invoice, err
+	}
+
+	// Likewise, invoices stored before their htlcs were recorded end
+	// here.
+	var numHtlcs uint32
+	err = binary.Read(r, byteOrder, &numHtlcs)
+	switch {
+	case err == io.EOF:
+		return invoice, nil
+	case err != nil:
+		return invoice, err
+	}
+
+	invoice.Htlcs, err = deserializeHtlcs(r, numHtlcs)
+	if err != nil {
+		return invoice, err
+	}
+
 	return invoice, nil
 }
 
-func acceptOrSettleInvoice(invoices, settleIndex *bbolt.Bucket, invoiceNum []byte,
-	amtPaid lnwire.MilliSatoshi) (*Invoice, error) {
+// deserializeHtlcs reads numHtlcs htlcs of an invoice from r. If there are
+// none, nil is returned.
+func deserializeHtlcs(r io.Reader, numHtlcs uint32) (
+	map[CircuitKey]*InvoiceHTLC, error) {
+
+	if numHtlcs == 0 {
+		return nil, nil
+	}
+
+	htlcs := make(map[CircuitKey]*InvoiceHTLC)
+	for i := uint32(0); i < numHtlcs; i++ {
+		var (
+			chanID, htlcID, amt     uint64
+			acceptTime, resolveTime int64
+			state                   uint8
+			htlc                    InvoiceHTLC
+		)
+		fields := []interface{}{
+			&chanID, &htlcID, &amt, &htlc.AcceptHeight,
+			&acceptTime, &resolveTime, &htlc.Expiry, &state,
+		}
+		for _, field := range fields {
+			err := binary.Read(r, byteOrder, field)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		htlc.Amt = lnwire.MilliSatoshi(amt)
+		htlc.AcceptTime = unixNanoToTime(acceptTime)
+		htlc.ResolveTime = unixNanoToTime(resolveTime)
+		htlc.State = HtlcState(state)
+
+		key := CircuitKey{
+			ChanID: lnwire.NewShortChanIDFromInt(chanID),
+			HtlcID: htlcID,
+		}
+		htlcs[key] = &htlc
+	}
+
+	return htlcs, nil
+}
+
+func deserializeFiatRate(r io.Reader) (*FiatRate, error) {
+	var hasRate bool
+	if err := binary.Read(r, byteOrder, &hasRate); err != nil {
+		return nil, err
+	}
+	if !hasRate {
+		return nil, nil
+	}
+
+	var (
+		rateBits  uint64
+		timestamp int64
+	)
+	if err := binary.Read(r, byteOrder, &rateBits); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, byteOrder, &timestamp); err != nil {
+		return nil, err
+	}
+
+	return &FiatRate{
+		Rate:      math.Float64frombits(rateBits),
+		Timestamp: time.Unix(0, timestamp),
+	}, nil
+}
+
+func acceptOrSettleInvoice(invoices, settleIndex kvdb.Bucket, invoiceNum []byte,
+	amtPaid lnwire.MilliSatoshi, htlc *HtlcAcceptDesc) (*Invoice, error) {
 
 	invoice, err := fetchInvoice(invoiceNum, invoices)
 	if err != nil {
 		return nil, err
 	}
 
-	state := invoice.Terms.State
+	var stateErr error
+	switch invoice.Terms.State {
+	case ContractAccepted:
+		stateErr = ErrInvoiceAlreadyAccepted
+	case ContractSettled:
+		stateErr = ErrInvoiceAlreadySettled
+	case ContractCanceled:
+		stateErr = ErrInvoiceAlreadyCanceled
+	}
 
-	switch {
-	case state == ContractAccepted:
-		return &invoice, ErrInvoiceAlreadyAccepted
-	case state == ContractSettled:
-		return &invoice, ErrInvoiceAlreadySettled
-	case state == ContractCanceled:
-		return &invoice, ErrInvoiceAlreadyCanceled
+	// If the invoice isn't open anymore, the htlc is resolved in line
+	// with the state of the invoice. We'll still record it if it's new.
+	if stateErr != nil {
+		if !addInvoiceHtlc(&invoice, amtPaid, htlc) {
+			return &invoice, stateErr
+		}
+
+		var buf bytes.Buffer
+		if err := serializeInvoice(&buf, &invoice); err != nil {
+			return nil, err
+		}
+		if err := invoices.Put(invoiceNum[:], buf.Bytes()); err != nil {
+			return nil, err
+		}
+
+		if stateErr == ErrInvoiceAlreadyAccepted {
+			return &invoice, nil
+		}
+		return &invoice, stateErr
 	}
 
 	holdInvoice := invoice.Terms.PaymentPreimage == UnknownPreimage
@@ -1018,6 +1618,7 @@ func acceptOrSettleInvoice(invoices, settleIndex *bbolt.Bucket, invoiceNum []byt
 	}
 
 	invoice.AmtPaid = amtPaid
+	addInvoiceHtlc(&invoice, amtPaid, htlc)
 
 	var buf bytes.Buffer
 	if err := serializeInvoice(&buf, &invoice); err != nil {
@@ -1031,7 +1632,59 @@ func acceptOrSettleInvoice(invoices, settleIndex *bbolt.Bucket, invoiceNum []byt
 	return &invoice, nil
 }
 
-func setSettleFields(settleIndex *bbolt.Bucket, invoiceNum []byte,
+// addInvoiceHtlc records the htlc described by desc along with the invoice, in
+// a state matching the one of the invoice. It returns false if desc is nil or
+// the htlc was already recorded.
+func addInvoiceHtlc(invoice *Invoice, amt lnwire.MilliSatoshi,
+	desc *HtlcAcceptDesc) bool {
+
+	if desc == nil {
+		return false
+	}
+	if _, ok := invoice.Htlcs[desc.CircuitKey]; ok {
+		return false
+	}
+
+	htlc := &InvoiceHTLC{
+		Amt:          amt,
+		AcceptHeight: desc.AcceptHeight,
+		AcceptTime:   time.Now(),
+		Expiry:       desc.Expiry,
+	}
+	switch invoice.Terms.State {
+	case ContractSettled:
+		htlc.State = HtlcStateSettled
+		htlc.ResolveTime = htlc.AcceptTime
+	case ContractCanceled:
+		htlc.State = HtlcStateCanceled
+		htlc.ResolveTime = htlc.AcceptTime
+	default:
+		htlc.State = HtlcStateAccepted
+	}
+
+	if invoice.Htlcs == nil {
+		invoice.Htlcs = make(map[CircuitKey]*InvoiceHTLC)
+	}
+	invoice.Htlcs[desc.CircuitKey] = htlc
+
+	return true
+}
+
+// resolveInvoiceHtlcs moves all accepted htlcs of the invoice to the given
+// state.
+func resolveInvoiceHtlcs(invoice *Invoice, state HtlcState) {
+	now := time.Now()
+	for _, htlc := range invoice.Htlcs {
+		if htlc.State != HtlcStateAccepted {
+			continue
+		}
+
+		htlc.State = state
+		htlc.ResolveTime = now
+	}
+}
+
+func setSettleFields(settleIndex kvdb.Bucket, invoiceNum []byte,
 	invoice *Invoice) error {
 
 	// Now that we know the invoice hasn't already been settled, we'll
@@ -1055,7 +1708,7 @@ func setSettleFields(settleIndex *bbolt.Bucket, invoiceNum []byte,
 	return nil
 }
 
-func settleHoldInvoice(invoices, settleIndex *bbolt.Bucket,
+func settleHoldInvoice(invoices, settleIndex kvdb.Bucket,
 	invoiceNum []byte, preimage lntypes.Preimage) (*Invoice,
 	error) {
 
@@ -1079,6 +1732,7 @@ func settleHoldInvoice(invoices, settleIndex *bbolt.Bucket,
 	if err != nil {
 		return nil, err
 	}
+	resolveInvoiceHtlcs(&invoice, HtlcStateSettled)
 
 	var buf bytes.Buffer
 	if err := serializeInvoice(&buf, &invoice); err != nil {
@@ -1092,7 +1746,7 @@ func settleHoldInvoice(invoices, settleIndex *bbolt.Bucket,
 	return &invoice, nil
 }
 
-func cancelInvoice(invoices *bbolt.Bucket, invoiceNum []byte) (
+func cancelInvoice(invoices kvdb.Bucket, invoiceNum []byte) (
 	*Invoice, error) {
 
 	invoice, err := fetchInvoice(invoiceNum, invoices)
@@ -1111,6 +1765,7 @@ func cancelInvoice(invoices *bbolt.Bucket, invoiceNum []byte) (
 
 	// Set AmtPaid back to 0, in case the invoice was already accepted.
 	invoice.AmtPaid = 0
+	resolveInvoiceHtlcs(&invoice, HtlcStateCanceled)
 
 	var buf bytes.Buffer
 	if err := serializeInvoice(&buf, &invoice); err != nil {
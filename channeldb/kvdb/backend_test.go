@@ -0,0 +1,392 @@
+package kvdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// backendTestCase is a test of the semantics that every backend must provide.
+type backendTestCase struct {
+	name string
+	test func(t *testing.T, b Backend)
+}
+
+var backendTestCases = []backendTestCase{
+	{
+		name: "buckets",
+		test: testBuckets,
+	},
+	{
+		name: "iteration",
+		test: testIteration,
+	},
+	{
+		name: "sequence",
+		test: testSequence,
+	},
+	{
+		name: "rollback",
+		test: testRollback,
+	},
+}
+
+// makeBoltBackend opens a BoltBackend within a temporary directory.
+func makeBoltBackend(t *testing.T) (Backend, func()) {
+	tempDir, err := ioutil.TempDir("", "kvdb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+
+	b, err := OpenBoltBackend(filepath.Join(tempDir, "test.db"))
+	if err != nil {
+		os.RemoveAll(tempDir)
+		t.Fatalf("unable to open bolt backend: %v", err)
+	}
+
+	cleanUp := func() {
+		b.Close()
+		os.RemoveAll(tempDir)
+	}
+
+	return b, cleanUp
+}
+
+// makePostgresBackend opens a PostgresBackend within a fresh table of the
+// Postgres database identified by the data source name in the
+// KVDB_POSTGRES_DSN environment variable. The test is skipped if it isn't set.
+func makePostgresBackend(t *testing.T) (Backend, func()) {
+	dsn := os.Getenv("KVDB_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("KVDB_POSTGRES_DSN not set")
+	}
+
+	table := fmt.Sprintf("kvdb_test_%d", time.Now().UnixNano())
+	b, err := OpenPostgresBackend(dsn, table)
+	if err != nil {
+		t.Fatalf("unable to open postgres backend: %v", err)
+	}
+
+	cleanUp := func() {
+		b.db.Exec("DROP TABLE " + table)
+		b.Close()
+	}
+
+	return b, cleanUp
+}
+
+// TestBackends runs the backend test cases against each backend.
+func TestBackends(t *testing.T) {
+	backends := []struct {
+		name string
+		make func(t *testing.T) (Backend, func())
+	}{
+		{
+			name: "bolt",
+			make: makeBoltBackend,
+		},
+		{
+			name: "postgres",
+			make: makePostgresBackend,
+		},
+	}
+
+	for _, backend := range backends {
+		backend := backend
+		for _, test := range backendTestCases {
+			test := test
+			name := backend.name + "/" + test.name
+			t.Run(name, func(t *testing.T) {
+				b, cleanUp := backend.make(t)
+				defer cleanUp()
+
+				test.test(t, b)
+			})
+		}
+	}
+}
+
+// testBuckets asserts that buckets and their keys can be created, read and
+// deleted, and that the errors of misuse are consistent across backends.
+func testBuckets(t *testing.T, b Backend) {
+	var (
+		topKey    = []byte("top")
+		nestedKey = []byte("nested")
+		key       = []byte("key")
+		value     = []byte("value")
+	)
+
+	err := b.Update(func(tx Tx) error {
+		top, err := tx.CreateBucket(topKey)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(topKey); err != ErrBucketExists {
+			return fmt.Errorf("expected ErrBucketExists, got %v",
+				err)
+		}
+
+		nested, err := top.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		if err := nested.Put(key, value); err != nil {
+			return err
+		}
+
+		// Keys and nested buckets can't be mistaken for each other.
+		err = top.Put(nestedKey, value)
+		if err != ErrIncompatibleValue {
+			return fmt.Errorf("expected ErrIncompatibleValue, "+
+				"got %v", err)
+		}
+		_, err = nested.CreateBucket(key)
+		if err != ErrIncompatibleValue {
+			return fmt.Errorf("expected ErrIncompatibleValue, "+
+				"got %v", err)
+		}
+		if top.Get(nestedKey) != nil {
+			return errors.New("expected nil value for bucket")
+		}
+		if nested.Bucket(key) != nil {
+			return errors.New("expected nil bucket for key")
+		}
+
+		_, err = top.CreateBucketIfNotExists(nestedKey)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unable to create buckets: %v", err)
+	}
+
+	err = b.View(func(tx Tx) error {
+		if !bytes.Equal(tx.Bucket(topKey).Bucket(nestedKey).Get(key),
+			value) {
+
+			return errors.New("value not found")
+		}
+		if tx.Bucket(nestedKey) != nil {
+			return errors.New("unexpected top-level bucket")
+		}
+
+		_, err := tx.CreateBucket(nestedKey)
+		if err != ErrTxNotWritable {
+			return fmt.Errorf("expected ErrTxNotWritable, got %v",
+				err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read buckets: %v", err)
+	}
+
+	// Deleting the top-level bucket should delete its nested bucket as
+	// well.
+	err = b.Update(func(tx Tx) error {
+		if err := tx.DeleteBucket(topKey); err != nil {
+			return err
+		}
+		if err := tx.DeleteBucket(topKey); err != ErrBucketNotFound {
+			return fmt.Errorf("expected ErrBucketNotFound, got %v",
+				err)
+		}
+
+		top, err := tx.CreateBucket(topKey)
+		if err != nil {
+			return err
+		}
+		if top.Bucket(nestedKey) != nil {
+			return errors.New("nested bucket not deleted")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to delete buckets: %v", err)
+	}
+}
+
+// testIteration asserts that the keys of a bucket are iterated over in byte
+// order, both by ForEach and by cursors.
+func testIteration(t *testing.T, b Backend) {
+	bucketKey := []byte("bucket")
+	keys := [][]byte{{0x00}, {0x00, 0x01}, {0x01}, {0x7f}, {0x80}, {0xff}}
+
+	err := b.Update(func(tx Tx) error {
+		bucket, err := tx.CreateBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+
+		// Insert the keys in reverse, such that their order can't be
+		// that of insertion.
+		for i := len(keys) - 1; i >= 0; i-- {
+			if err := bucket.Put(keys[i], keys[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to insert keys: %v", err)
+	}
+
+	err = b.View(func(tx Tx) error {
+		bucket := tx.Bucket(bucketKey)
+
+		var i int
+		err := bucket.ForEach(func(k, v []byte) error {
+			if !bytes.Equal(k, keys[i]) || !bytes.Equal(v, keys[i]) {
+				return fmt.Errorf("expected key %x at %d, "+
+					"got %x", keys[i], i, k)
+			}
+			i++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		c := bucket.Cursor()
+		i = 0
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if !bytes.Equal(k, keys[i]) {
+				return fmt.Errorf("expected key %x at %d, "+
+					"got %x", keys[i], i, k)
+			}
+			i++
+		}
+		if i != len(keys) {
+			return fmt.Errorf("expected %d keys, got %d",
+				len(keys), i)
+		}
+
+		i = len(keys) - 1
+		for k, _ := c.Last(); k != nil; k, _ = c.Prev() {
+			if !bytes.Equal(k, keys[i]) {
+				return fmt.Errorf("expected key %x at %d, "+
+					"got %x", keys[i], i, k)
+			}
+			i--
+		}
+
+		if k, _ := c.Seek([]byte{0x02}); !bytes.Equal(k, keys[3]) {
+			return fmt.Errorf("expected seek to %x, got %x",
+				keys[3], k)
+		}
+		if k, _ := c.Seek([]byte{0xff, 0x00}); k != nil {
+			return fmt.Errorf("expected seek past last key, got %x",
+				k)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to iterate over keys: %v", err)
+	}
+
+	// Deleting through a cursor should leave the remaining keys intact.
+	err = b.Update(func(tx Tx) error {
+		c := tx.Bucket(bucketKey).Cursor()
+		if k, _ := c.Seek(keys[2]); !bytes.Equal(k, keys[2]) {
+			return fmt.Errorf("expected key %x, got %x", keys[2], k)
+		}
+
+		return c.Delete()
+	})
+	if err != nil {
+		t.Fatalf("unable to delete key: %v", err)
+	}
+
+	err = b.View(func(tx Tx) error {
+		bucket := tx.Bucket(bucketKey)
+		if bucket.Get(keys[2]) != nil {
+			return errors.New("key not deleted")
+		}
+		if !bytes.Equal(bucket.Get(keys[3]), keys[3]) {
+			return errors.New("key unexpectedly deleted")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read keys: %v", err)
+	}
+}
+
+// testSequence asserts that the sequences of buckets are incremented and set
+// independently of each other.
+func testSequence(t *testing.T, b Backend) {
+	err := b.Update(func(tx Tx) error {
+		first, err := tx.CreateBucket([]byte("first"))
+		if err != nil {
+			return err
+		}
+		second, err := tx.CreateBucket([]byte("second"))
+		if err != nil {
+			return err
+		}
+
+		for i := uint64(1); i <= 3; i++ {
+			seq, err := first.NextSequence()
+			if err != nil {
+				return err
+			}
+			if seq != i {
+				return fmt.Errorf("expected sequence %d, got %d",
+					i, seq)
+			}
+		}
+
+		return second.SetSequence(42)
+	})
+	if err != nil {
+		t.Fatalf("unable to update sequences: %v", err)
+	}
+
+	err = b.View(func(tx Tx) error {
+		if seq := tx.Bucket([]byte("first")).Sequence(); seq != 3 {
+			return fmt.Errorf("expected sequence 3, got %d", seq)
+		}
+		if seq := tx.Bucket([]byte("second")).Sequence(); seq != 42 {
+			return fmt.Errorf("expected sequence 42, got %d", seq)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read sequences: %v", err)
+	}
+}
+
+// testRollback asserts that the writes of a transaction whose closure fails
+// are discarded.
+func testRollback(t *testing.T, b Backend) {
+	errRollback := errors.New("rollback")
+	err := b.Update(func(tx Tx) error {
+		if _, err := tx.CreateBucket([]byte("bucket")); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if err != errRollback {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	err = b.View(func(tx Tx) error {
+		return tx.ForEach(func(name []byte, _ Bucket) error {
+			return fmt.Errorf("unexpected bucket %s", name)
+		})
+	})
+	if err != nil {
+		t.Fatalf("bucket not rolled back: %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package kvdb
+
+import (
+	"github.com/coreos/bbolt"
+)
+
+// BoltBackend is a Backend stored within a single local bolt database file.
+type BoltBackend struct {
+	db *bbolt.DB
+}
+
+// A compile time check to ensure that BoltBackend implements the Backend
+// interface.
+var _ Backend = (*BoltBackend)(nil)
+
+// NewBoltBackend creates a new Backend on top of the passed, already opened,
+// bolt database.
+func NewBoltBackend(db *bbolt.DB) *BoltBackend {
+	return &BoltBackend{
+		db: db,
+	}
+}
+
+// OpenBoltBackend opens the bolt database file at path, creating it if it
+// doesn't exist yet.
+func OpenBoltBackend(path string) (*BoltBackend, error) {
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBoltBackend(db), nil
+}
+
+// DB returns the underlying bolt database.
+func (b *BoltBackend) DB() *bbolt.DB {
+	return b.db
+}
+
+// View executes the passed closure within a read-only transaction.
+//
+// NOTE: This is part of the Backend interface.
+func (b *BoltBackend) View(fn func(tx Tx) error) error {
+	return b.db.View(func(tx *bbolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+// Update executes the passed closure within a read-write transaction.
+//
+// NOTE: This is part of the Backend interface.
+func (b *BoltBackend) Update(fn func(tx Tx) error) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+// Batch executes the passed closure within a read-write transaction, which
+// may be coalesced with those of concurrent callers.
+//
+// NOTE: This is part of the Backend interface.
+func (b *BoltBackend) Batch(fn func(tx Tx) error) error {
+	return b.db.Batch(func(tx *bbolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+// Close closes the bolt database.
+//
+// NOTE: This is part of the Backend interface.
+func (b *BoltBackend) Close() error {
+	return b.db.Close()
+}
+
+// boltErr translates the errors of bolt into those of kvdb, such that callers
+// can check for them regardless of the backend.
+func boltErr(err error) error {
+	switch err {
+	case bbolt.ErrBucketNotFound:
+		return ErrBucketNotFound
+
+	case bbolt.ErrBucketExists:
+		return ErrBucketExists
+
+	case bbolt.ErrIncompatibleValue:
+		return ErrIncompatibleValue
+
+	case bbolt.ErrTxNotWritable:
+		return ErrTxNotWritable
+
+	default:
+		return err
+	}
+}
+
+// newBoltBucket wraps the bolt bucket, taking care to return a nil interface
+// if the bucket doesn't exist.
+func newBoltBucket(bucket *bbolt.Bucket) Bucket {
+	if bucket == nil {
+		return nil
+	}
+
+	return &boltBucket{bucket: bucket}
+}
+
+// boltTx is a Tx wrapping a bolt transaction.
+type boltTx struct {
+	tx *bbolt.Tx
+}
+
+func (t *boltTx) Bucket(name []byte) Bucket {
+	return newBoltBucket(t.tx.Bucket(name))
+}
+
+func (t *boltTx) CreateBucket(name []byte) (Bucket, error) {
+	bucket, err := t.tx.CreateBucket(name)
+	if err != nil {
+		return nil, boltErr(err)
+	}
+
+	return newBoltBucket(bucket), nil
+}
+
+func (t *boltTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	bucket, err := t.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, boltErr(err)
+	}
+
+	return newBoltBucket(bucket), nil
+}
+
+func (t *boltTx) DeleteBucket(name []byte) error {
+	return boltErr(t.tx.DeleteBucket(name))
+}
+
+func (t *boltTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	return t.tx.ForEach(func(name []byte, b *bbolt.Bucket) error {
+		return fn(name, newBoltBucket(b))
+	})
+}
+
+func (t *boltTx) Writable() bool {
+	return t.tx.Writable()
+}
+
+// boltBucket is a Bucket wrapping a bolt bucket.
+type boltBucket struct {
+	bucket *bbolt.Bucket
+}
+
+func (b *boltBucket) Get(key []byte) []byte {
+	return b.bucket.Get(key)
+}
+
+func (b *boltBucket) Put(key, value []byte) error {
+	return boltErr(b.bucket.Put(key, value))
+}
+
+func (b *boltBucket) Delete(key []byte) error {
+	return boltErr(b.bucket.Delete(key))
+}
+
+func (b *boltBucket) Bucket(key []byte) Bucket {
+	return newBoltBucket(b.bucket.Bucket(key))
+}
+
+func (b *boltBucket) CreateBucket(key []byte) (Bucket, error) {
+	bucket, err := b.bucket.CreateBucket(key)
+	if err != nil {
+		return nil, boltErr(err)
+	}
+
+	return newBoltBucket(bucket), nil
+}
+
+func (b *boltBucket) CreateBucketIfNotExists(key []byte) (Bucket, error) {
+	bucket, err := b.bucket.CreateBucketIfNotExists(key)
+	if err != nil {
+		return nil, boltErr(err)
+	}
+
+	return newBoltBucket(bucket), nil
+}
+
+func (b *boltBucket) DeleteBucket(key []byte) error {
+	return boltErr(b.bucket.DeleteBucket(key))
+}
+
+func (b *boltBucket) ForEach(fn func(k, v []byte) error) error {
+	return b.bucket.ForEach(fn)
+}
+
+func (b *boltBucket) Cursor() Cursor {
+	return &boltCursor{cursor: b.bucket.Cursor()}
+}
+
+func (b *boltBucket) NextSequence() (uint64, error) {
+	seq, err := b.bucket.NextSequence()
+	return seq, boltErr(err)
+}
+
+func (b *boltBucket) Sequence() uint64 {
+	return b.bucket.Sequence()
+}
+
+func (b *boltBucket) SetSequence(v uint64) error {
+	return boltErr(b.bucket.SetSequence(v))
+}
+
+// boltCursor is a Cursor wrapping a bolt cursor.
+type boltCursor struct {
+	cursor *bbolt.Cursor
+}
+
+func (c *boltCursor) First() ([]byte, []byte) {
+	return c.cursor.First()
+}
+
+func (c *boltCursor) Last() ([]byte, []byte) {
+	return c.cursor.Last()
+}
+
+func (c *boltCursor) Next() ([]byte, []byte) {
+	return c.cursor.Next()
+}
+
+func (c *boltCursor) Prev() ([]byte, []byte) {
+	return c.cursor.Prev()
+}
+
+func (c *boltCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.cursor.Seek(seek)
+}
+
+func (c *boltCursor) Delete() error {
+	return boltErr(c.cursor.Delete())
+}
@@ -0,0 +1,153 @@
+package kvdb
+
+import "errors"
+
+var (
+	// ErrBucketNotFound is returned when trying to access a bucket that
+	// has not been created yet.
+	ErrBucketNotFound = errors.New("bucket not found")
+
+	// ErrBucketExists is returned when creating a bucket that already
+	// exists.
+	ErrBucketExists = errors.New("bucket already exists")
+
+	// ErrIncompatibleValue is returned when trying to create or delete a
+	// bucket on an existing non-bucket key, or to put or delete a value
+	// at the key of a bucket.
+	ErrIncompatibleValue = errors.New("incompatible value")
+
+	// ErrTxNotWritable is returned when attempting to write within a
+	// read-only transaction.
+	ErrTxNotWritable = errors.New("tx not writable")
+)
+
+// Backend is a key/value store holding a hierarchy of buckets, which is
+// accessed through transactions. Every backend provides the same semantics as
+// a bolt database: transactions are serializable, and the keys of each bucket
+// are iterated over in byte order.
+type Backend interface {
+	// View executes the passed closure within a read-only transaction.
+	View(fn func(tx Tx) error) error
+
+	// Update executes the passed closure within a read-write transaction,
+	// which is committed if the closure returns a nil error, and rolled
+	// back otherwise.
+	Update(fn func(tx Tx) error) error
+
+	// Batch executes the passed closure within a read-write transaction,
+	// which the backend may coalesce with those of concurrent callers.
+	// As the closure may be executed multiple times, it must be
+	// idempotent.
+	Batch(fn func(tx Tx) error) error
+
+	// Close closes the backend, once all transactions in flight have
+	// completed.
+	Close() error
+}
+
+// Tx is a transaction against a Backend, providing access to its top-level
+// buckets.
+type Tx interface {
+	// Bucket returns the top-level bucket with the given name, or nil if
+	// it doesn't exist.
+	Bucket(name []byte) Bucket
+
+	// CreateBucket creates a new top-level bucket, returning
+	// ErrBucketExists if it already exists.
+	CreateBucket(name []byte) (Bucket, error)
+
+	// CreateBucketIfNotExists creates a new top-level bucket if it doesn't
+	// exist yet, and returns it.
+	CreateBucketIfNotExists(name []byte) (Bucket, error)
+
+	// DeleteBucket deletes the top-level bucket with the given name, along
+	// with all of its contents, returning ErrBucketNotFound if it doesn't
+	// exist.
+	DeleteBucket(name []byte) error
+
+	// ForEach calls fn for each top-level bucket in byte order of their
+	// names.
+	ForEach(fn func(name []byte, b Bucket) error) error
+
+	// Writable returns true if this is a read-write transaction.
+	Writable() bool
+}
+
+// Bucket is a collection of key/value pairs and nested buckets within a
+// transaction. Values returned by a bucket must not be modified, and are only
+// valid for the life of the transaction.
+type Bucket interface {
+	// Get returns the value of the given key, or nil if the key doesn't
+	// exist or is a nested bucket.
+	Get(key []byte) []byte
+
+	// Put sets the value of the given key, returning ErrIncompatibleValue
+	// if the key is a nested bucket.
+	Put(key, value []byte) error
+
+	// Delete removes the given key, returning ErrIncompatibleValue if the
+	// key is a nested bucket. Deleting a key that doesn't exist isn't an
+	// error.
+	Delete(key []byte) error
+
+	// Bucket returns the nested bucket with the given key, or nil if it
+	// doesn't exist.
+	Bucket(key []byte) Bucket
+
+	// CreateBucket creates a new nested bucket, returning ErrBucketExists
+	// if it already exists.
+	CreateBucket(key []byte) (Bucket, error)
+
+	// CreateBucketIfNotExists creates a new nested bucket if it doesn't
+	// exist yet, and returns it.
+	CreateBucketIfNotExists(key []byte) (Bucket, error)
+
+	// DeleteBucket deletes the nested bucket with the given key, along
+	// with all of its contents, returning ErrBucketNotFound if it doesn't
+	// exist.
+	DeleteBucket(key []byte) error
+
+	// ForEach calls fn for each key of the bucket in byte order. The value
+	// passed for the keys of nested buckets is nil. The bucket must not
+	// be modified from within fn.
+	ForEach(fn func(k, v []byte) error) error
+
+	// Cursor returns a new cursor over the keys of the bucket.
+	Cursor() Cursor
+
+	// NextSequence increments the sequence of the bucket, and returns its
+	// new value.
+	NextSequence() (uint64, error)
+
+	// Sequence returns the current sequence of the bucket.
+	Sequence() uint64
+
+	// SetSequence sets the sequence of the bucket.
+	SetSequence(v uint64) error
+}
+
+// Cursor iterates over the keys of a bucket in byte order. Each method returns
+// the key and value the cursor is positioned at, or a nil key once it has moved
+// past the first or last key. The value returned for the keys of nested
+// buckets is nil.
+type Cursor interface {
+	// First moves the cursor to the first key of the bucket.
+	First() (k, v []byte)
+
+	// Last moves the cursor to the last key of the bucket.
+	Last() (k, v []byte)
+
+	// Next moves the cursor to the next key.
+	Next() (k, v []byte)
+
+	// Prev moves the cursor to the previous key.
+	Prev() (k, v []byte)
+
+	// Seek moves the cursor to the given key, or the key following it if
+	// it doesn't exist.
+	Seek(seek []byte) (k, v []byte)
+
+	// Delete removes the key the cursor is positioned at, returning
+	// ErrIncompatibleValue if it's a nested bucket.
+	Delete() error
+}
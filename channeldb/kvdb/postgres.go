@@ -0,0 +1,580 @@
+package kvdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sync"
+
+	// Register the Postgres driver with database/sql.
+	_ "github.com/lib/pq"
+)
+
+const (
+	// rootBucketID is the id of the row acting as the parent of all
+	// top-level buckets.
+	rootBucketID = 0
+
+	// pgForEachPageSize is the number of keys fetched at a time when
+	// iterating over a bucket.
+	pgForEachPageSize = 1000
+)
+
+// pgTableName matches the table names accepted by OpenPostgresBackend, which
+// are interpolated into queries.
+var pgTableName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// PostgresBackend is a Backend stored within a table of a Postgres database,
+// allowing the state of lnd to be replicated by Postgres rather than residing
+// in a single local file. Each key and bucket is a row of the table, which
+// refers to the row of its parent bucket.
+//
+// Writes are serialized within the process, so only a single lnd instance may
+// use the table at a time.
+type PostgresBackend struct {
+	db    *sql.DB
+	table string
+
+	// writeMtx serializes the read-write transactions, mirroring the
+	// single writer of bolt.
+	writeMtx sync.Mutex
+}
+
+// A compile time check to ensure that PostgresBackend implements the Backend
+// interface.
+var _ Backend = (*PostgresBackend)(nil)
+
+// OpenPostgresBackend connects to the Postgres database identified by the
+// given data source name, and creates the table holding the backend's keys if
+// it doesn't exist yet.
+func OpenPostgresBackend(dsn, table string) (*PostgresBackend, error) {
+	if !pgTableName.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	b := &PostgresBackend{
+		db:    db,
+		table: table,
+	}
+
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS %[1]s (
+			id BIGSERIAL PRIMARY KEY,
+			parent_id BIGINT REFERENCES %[1]s (id)
+				ON DELETE CASCADE,
+			key BYTEA NOT NULL,
+			value BYTEA,
+			sequence BIGINT NOT NULL DEFAULT 0,
+			UNIQUE (parent_id, key)
+		)`,
+		`INSERT INTO %[1]s (id, parent_id, key)
+			VALUES (0, NULL, '') ON CONFLICT DO NOTHING`,
+	}
+	for _, stmt := range schema {
+		if _, err := db.Exec(fmt.Sprintf(stmt, table)); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// View executes the passed closure within a read-only transaction.
+//
+// NOTE: This is part of the Backend interface.
+func (b *PostgresBackend) View(fn func(tx Tx) error) error {
+	return b.executeTx(false, fn)
+}
+
+// Update executes the passed closure within a read-write transaction.
+//
+// NOTE: This is part of the Backend interface.
+func (b *PostgresBackend) Update(fn func(tx Tx) error) error {
+	b.writeMtx.Lock()
+	defer b.writeMtx.Unlock()
+
+	return b.executeTx(true, fn)
+}
+
+// Batch executes the passed closure within a read-write transaction. Unlike
+// bolt, writes aren't coalesced, as Postgres commits are cheap in comparison.
+//
+// NOTE: This is part of the Backend interface.
+func (b *PostgresBackend) Batch(fn func(tx Tx) error) error {
+	return b.Update(fn)
+}
+
+// Close closes the connections to the database.
+//
+// NOTE: This is part of the Backend interface.
+func (b *PostgresBackend) Close() error {
+	return b.db.Close()
+}
+
+// executeTx executes the closure within a new transaction, which is committed
+// if the transaction is writable and neither the closure nor any of the
+// queries made on its behalf failed.
+func (b *PostgresBackend) executeTx(writable bool, fn func(tx Tx) error) error {
+	sqlTx, err := b.db.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  !writable,
+	})
+	if err != nil {
+		return err
+	}
+
+	tx := &pgTx{
+		tx:       sqlTx,
+		table:    b.table,
+		writable: writable,
+	}
+
+	if err := fn(tx); err != nil {
+		sqlTx.Rollback()
+		return err
+	}
+
+	// As the methods of buckets returning values can't return errors,
+	// failed queries are recorded in the transaction instead.
+	if tx.err != nil {
+		sqlTx.Rollback()
+		return tx.err
+	}
+
+	if !writable {
+		return sqlTx.Rollback()
+	}
+
+	return sqlTx.Commit()
+}
+
+// pgTx is a Tx wrapping a Postgres transaction.
+type pgTx struct {
+	tx       *sql.Tx
+	table    string
+	writable bool
+
+	// err is the first error encountered by a query that couldn't be
+	// returned to the caller.
+	err error
+}
+
+// fail records the first error that couldn't be returned to the caller, which
+// fails the transaction once the closure returns.
+func (t *pgTx) fail(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+// query formats the query with the name of the backend's table.
+func (t *pgTx) query(query string) string {
+	return fmt.Sprintf(query, t.table)
+}
+
+// root returns the bucket holding the top-level buckets.
+func (t *pgTx) root() *pgBucket {
+	return &pgBucket{tx: t, id: rootBucketID}
+}
+
+func (t *pgTx) Bucket(name []byte) Bucket {
+	return t.root().Bucket(name)
+}
+
+func (t *pgTx) CreateBucket(name []byte) (Bucket, error) {
+	return t.root().CreateBucket(name)
+}
+
+func (t *pgTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	return t.root().CreateBucketIfNotExists(name)
+}
+
+func (t *pgTx) DeleteBucket(name []byte) error {
+	return t.root().DeleteBucket(name)
+}
+
+func (t *pgTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	root := t.root()
+	return root.ForEach(func(k, v []byte) error {
+		// The root bucket should only hold buckets, but we'll skip
+		// any values nonetheless.
+		if v != nil {
+			return nil
+		}
+
+		return fn(k, root.Bucket(k))
+	})
+}
+
+func (t *pgTx) Writable() bool {
+	return t.writable
+}
+
+// pgBucket is a Bucket identified by the id of its row.
+type pgBucket struct {
+	tx *pgTx
+	id int64
+}
+
+// lookup returns the id of the row of the given key, and whether it's a nested
+// bucket. A nil id is returned if the key doesn't exist.
+func (b *pgBucket) lookup(key []byte) (*int64, bool, error) {
+	var (
+		id    int64
+		value []byte
+	)
+	err := b.tx.tx.QueryRow(
+		b.tx.query(`SELECT id, value FROM %s
+			WHERE parent_id = $1 AND key = $2`),
+		b.id, key,
+	).Scan(&id, &value)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, false, nil
+
+	case err != nil:
+		return nil, false, err
+	}
+
+	return &id, value == nil, nil
+}
+
+func (b *pgBucket) Get(key []byte) []byte {
+	var value []byte
+	err := b.tx.tx.QueryRow(
+		b.tx.query(`SELECT value FROM %s
+			WHERE parent_id = $1 AND key = $2`),
+		b.id, key,
+	).Scan(&value)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+
+	case err != nil:
+		b.tx.fail(err)
+		return nil
+	}
+
+	return value
+}
+
+func (b *pgBucket) Put(key, value []byte) error {
+	if !b.tx.writable {
+		return ErrTxNotWritable
+	}
+
+	id, isBucket, err := b.lookup(key)
+	switch {
+	case err != nil:
+		return err
+
+	case id != nil && isBucket:
+		return ErrIncompatibleValue
+	}
+
+	// Values are never NULL, as that denotes a nested bucket.
+	if value == nil {
+		value = []byte{}
+	}
+
+	_, err = b.tx.tx.Exec(
+		b.tx.query(`INSERT INTO %s (parent_id, key, value)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (parent_id, key)
+			DO UPDATE SET value = EXCLUDED.value`),
+		b.id, key, value,
+	)
+	return err
+}
+
+func (b *pgBucket) Delete(key []byte) error {
+	if !b.tx.writable {
+		return ErrTxNotWritable
+	}
+
+	id, isBucket, err := b.lookup(key)
+	switch {
+	case err != nil:
+		return err
+
+	case id == nil:
+		return nil
+
+	case isBucket:
+		return ErrIncompatibleValue
+	}
+
+	_, err = b.tx.tx.Exec(
+		b.tx.query(`DELETE FROM %s WHERE id = $1`), *id,
+	)
+	return err
+}
+
+func (b *pgBucket) Bucket(key []byte) Bucket {
+	id, isBucket, err := b.lookup(key)
+	switch {
+	case err != nil:
+		b.tx.fail(err)
+		return nil
+
+	case id == nil || !isBucket:
+		return nil
+	}
+
+	return &pgBucket{tx: b.tx, id: *id}
+}
+
+func (b *pgBucket) CreateBucket(key []byte) (Bucket, error) {
+	if !b.tx.writable {
+		return nil, ErrTxNotWritable
+	}
+
+	id, isBucket, err := b.lookup(key)
+	switch {
+	case err != nil:
+		return nil, err
+
+	case id != nil && isBucket:
+		return nil, ErrBucketExists
+
+	case id != nil:
+		return nil, ErrIncompatibleValue
+	}
+
+	var newID int64
+	err = b.tx.tx.QueryRow(
+		b.tx.query(`INSERT INTO %s (parent_id, key) VALUES ($1, $2)
+			RETURNING id`),
+		b.id, key,
+	).Scan(&newID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pgBucket{tx: b.tx, id: newID}, nil
+}
+
+func (b *pgBucket) CreateBucketIfNotExists(key []byte) (Bucket, error) {
+	bucket, err := b.CreateBucket(key)
+	if err == ErrBucketExists {
+		return b.Bucket(key), nil
+	}
+
+	return bucket, err
+}
+
+func (b *pgBucket) DeleteBucket(key []byte) error {
+	if !b.tx.writable {
+		return ErrTxNotWritable
+	}
+
+	id, isBucket, err := b.lookup(key)
+	switch {
+	case err != nil:
+		return err
+
+	case id == nil:
+		return ErrBucketNotFound
+
+	case !isBucket:
+		return ErrIncompatibleValue
+	}
+
+	// The rows of all keys and buckets nested within the bucket are
+	// deleted along with it.
+	_, err = b.tx.tx.Exec(
+		b.tx.query(`DELETE FROM %s WHERE id = $1`), *id,
+	)
+	return err
+}
+
+func (b *pgBucket) ForEach(fn func(k, v []byte) error) error {
+	// We'll fetch the keys a page at a time, such that large buckets
+	// needn't be held in memory entirely, while fn is free to query the
+	// database in between.
+	var lastKey []byte
+	for {
+		keys, values, err := b.page(lastKey)
+		if err != nil {
+			return err
+		}
+
+		for i := range keys {
+			if err := fn(keys[i], values[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(keys) < pgForEachPageSize {
+			return nil
+		}
+		lastKey = keys[len(keys)-1]
+	}
+}
+
+// page returns the next page of keys following afterKey, or the first page if
+// afterKey is nil.
+func (b *pgBucket) page(afterKey []byte) ([][]byte, [][]byte, error) {
+	query := `SELECT key, value FROM %s WHERE parent_id = $1
+		ORDER BY key LIMIT $2`
+	args := []interface{}{b.id, pgForEachPageSize}
+	if afterKey != nil {
+		query = `SELECT key, value FROM %s WHERE parent_id = $1
+			AND key > $3 ORDER BY key LIMIT $2`
+		args = append(args, afterKey)
+	}
+
+	rows, err := b.tx.tx.Query(b.tx.query(query), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var keys, values [][]byte
+	for rows.Next() {
+		var k, v []byte
+		if err := rows.Scan(&k, &v); err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+
+	return keys, values, rows.Err()
+}
+
+func (b *pgBucket) Cursor() Cursor {
+	return &pgCursor{bucket: b}
+}
+
+func (b *pgBucket) NextSequence() (uint64, error) {
+	if !b.tx.writable {
+		return 0, ErrTxNotWritable
+	}
+
+	var seq int64
+	err := b.tx.tx.QueryRow(
+		b.tx.query(`UPDATE %s SET sequence = sequence + 1
+			WHERE id = $1 RETURNING sequence`),
+		b.id,
+	).Scan(&seq)
+
+	return uint64(seq), err
+}
+
+func (b *pgBucket) Sequence() uint64 {
+	var seq int64
+	err := b.tx.tx.QueryRow(
+		b.tx.query(`SELECT sequence FROM %s WHERE id = $1`), b.id,
+	).Scan(&seq)
+	if err != nil {
+		b.tx.fail(err)
+		return 0
+	}
+
+	return uint64(seq)
+}
+
+func (b *pgBucket) SetSequence(v uint64) error {
+	if !b.tx.writable {
+		return ErrTxNotWritable
+	}
+
+	_, err := b.tx.tx.Exec(
+		b.tx.query(`UPDATE %s SET sequence = $1 WHERE id = $2`),
+		int64(v), b.id,
+	)
+	return err
+}
+
+// pgCursor is a Cursor over the keys of a pgBucket. Rather than holding a
+// result set open, each move queries the key adjacent to the current one.
+type pgCursor struct {
+	bucket *pgBucket
+
+	// key is the key the cursor is positioned at, or nil if it isn't
+	// positioned at any key.
+	key []byte
+}
+
+// move positions the cursor at the first key in the given order, among those
+// matching the condition on the key, if any. The key passed as the second
+// argument of the query can be referred to by the condition.
+func (c *pgCursor) move(cond, order string, args ...interface{}) ([]byte,
+	[]byte) {
+
+	query := `SELECT key, value FROM %s WHERE parent_id = $1`
+	if cond != "" {
+		query += ` AND ` + cond
+	}
+	query += ` ORDER BY key ` + order + ` LIMIT 1`
+
+	args = append([]interface{}{c.bucket.id}, args...)
+
+	var k, v []byte
+	err := c.bucket.tx.tx.QueryRow(
+		c.bucket.tx.query(query), args...,
+	).Scan(&k, &v)
+	switch {
+	case err == sql.ErrNoRows:
+		c.key = nil
+		return nil, nil
+
+	case err != nil:
+		c.bucket.tx.fail(err)
+		c.key = nil
+		return nil, nil
+	}
+
+	c.key = k
+	return k, v
+}
+
+func (c *pgCursor) First() ([]byte, []byte) {
+	return c.move("", "ASC")
+}
+
+func (c *pgCursor) Last() ([]byte, []byte) {
+	return c.move("", "DESC")
+}
+
+func (c *pgCursor) Next() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+
+	return c.move(`key > $2`, "ASC", c.key)
+}
+
+func (c *pgCursor) Prev() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+
+	return c.move(`key < $2`, "DESC", c.key)
+}
+
+func (c *pgCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.move(`key >= $2`, "ASC", seek)
+}
+
+func (c *pgCursor) Delete() error {
+	if c.key == nil {
+		return nil
+	}
+
+	return c.bucket.Delete(c.key)
+}
@@ -9,9 +9,9 @@ import (
 	"testing"
 
 	"github.com/litecoinfinance/btcutil"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 )
 
@@ -56,7 +56,7 @@ func TestPaymentStatusesMigration(t *testing.T) {
 		// locally-sourced payment should end up with an InFlight
 		// status, while the other should remain unchanged, which
 		// defaults to Grounded.
-		err = d.Update(func(tx *bbolt.Tx) error {
+		err = d.Update(func(tx kvdb.Tx) error {
 			circuits, err := tx.CreateBucketIfNotExists(
 				[]byte("circuit-adds"),
 			)
@@ -383,7 +383,7 @@ func TestMigrateOptionalChannelCloseSummaryFields(t *testing.T) {
 			// Get the old serialization format for this test's
 			// close summary, and it to the closed channel bucket.
 			old := test.oldSerialization(test.closeSummary)
-			err = d.Update(func(tx *bbolt.Tx) error {
+			err = d.Update(func(tx kvdb.Tx) error {
 				closedChanBucket, err := tx.CreateBucketIfNotExists(
 					closedChannelBucket,
 				)
@@ -419,7 +419,7 @@ func TestMigrateOptionalChannelCloseSummaryFields(t *testing.T) {
 			newSerialization := b.Bytes()
 
 			var dbSummary []byte
-			err = d.View(func(tx *bbolt.Tx) error {
+			err = d.View(func(tx kvdb.Tx) error {
 				closedChanBucket := tx.Bucket(closedChannelBucket)
 				if closedChanBucket == nil {
 					return errors.New("unable to find bucket")
@@ -497,7 +497,7 @@ func TestMigrateGossipMessageStoreKeys(t *testing.T) {
 			t.Fatalf("unable to serialize message: %v", err)
 		}
 
-		err := db.Update(func(tx *bbolt.Tx) error {
+		err := db.Update(func(tx kvdb.Tx) error {
 			messageStore, err := tx.CreateBucketIfNotExists(
 				messageStoreBucket,
 			)
@@ -526,7 +526,7 @@ func TestMigrateGossipMessageStoreKeys(t *testing.T) {
 		}
 
 		var rawMsg []byte
-		err = db.View(func(tx *bbolt.Tx) error {
+		err = db.View(func(tx kvdb.Tx) error {
 			messageStore := tx.Bucket(messageStoreBucket)
 			if messageStore == nil {
 				return errors.New("message store bucket not " +
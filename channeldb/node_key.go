@@ -0,0 +1,371 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+var (
+	// nodeKeyBucket is the top-level bucket that stores the state of the
+	// node identity key.
+	nodeKeyBucket = []byte("node-key")
+
+	// activeNodeKeyIndexKey is the key within the nodeKeyBucket that
+	// stores the index of the node key family from which the current node
+	// identity key is derived. If it's not present, the identity key is
+	// derived from the first index.
+	activeNodeKeyIndexKey = []byte("active-index")
+
+	// nodeKeyRotationBucket is a sub-bucket of the nodeKeyBucket that
+	// stores the log of all node key rotations ever requested.
+	//
+	// maps: seqNo -> NodeKeyRotation
+	nodeKeyRotationBucket = []byte("rotations")
+
+	// ErrNodeKeyRotationPending is returned when attempting to request a
+	// node key rotation while another one is still pending.
+	ErrNodeKeyRotationPending = fmt.Errorf("node key rotation already " +
+		"pending")
+
+	// ErrNoNodeKeyRotationPending is returned when attempting to resolve a
+	// pending node key rotation, though there is none.
+	ErrNoNodeKeyRotationPending = fmt.Errorf("no node key rotation " +
+		"pending")
+)
+
+// NodeKeyRotationStatus denotes the state of a node key rotation.
+type NodeKeyRotationStatus uint8
+
+const (
+	// NodeKeyRotationPending denotes a rotation that has been requested,
+	// but will only take effect on the next start.
+	NodeKeyRotationPending NodeKeyRotationStatus = 0
+
+	// NodeKeyRotationActivated denotes a rotation whose new key has become
+	// the node identity key.
+	NodeKeyRotationActivated NodeKeyRotationStatus = 1
+
+	// NodeKeyRotationAborted denotes a rotation that was abandoned before
+	// it took effect.
+	NodeKeyRotationAborted NodeKeyRotationStatus = 2
+)
+
+// String returns a human readable version of the rotation status.
+func (s NodeKeyRotationStatus) String() string {
+	switch s {
+	case NodeKeyRotationPending:
+		return "Pending"
+	case NodeKeyRotationActivated:
+		return "Activated"
+	case NodeKeyRotationAborted:
+		return "Aborted"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(s))
+	}
+}
+
+// NodeKeyRotation is an entry of the audit log of node identity key
+// rotations. Besides the old and new keys, it records a linkage proof: a pair
+// of signatures over both keys, one by each key, which allows third parties to
+// verify that the new identity is controlled by the operator of the old one.
+type NodeKeyRotation struct {
+	// Status is the current state of the rotation.
+	Status NodeKeyRotationStatus
+
+	// OldKeyIndex is the index of the node key family from which the
+	// identity key being rotated out was derived.
+	OldKeyIndex uint32
+
+	// NewKeyIndex is the index of the node key family from which the new
+	// identity key is derived.
+	NewKeyIndex uint32
+
+	// OldPubKey is the identity key being rotated out.
+	OldPubKey *btcec.PublicKey
+
+	// NewPubKey is the identity key being rotated in.
+	NewPubKey *btcec.PublicKey
+
+	// OldKeySig is the signature of the linkage message under the old
+	// identity key.
+	OldKeySig lnwire.Sig
+
+	// NewKeySig is the signature of the linkage message under the new
+	// identity key.
+	NewKeySig lnwire.Sig
+
+	// RequestTime is the time at which the rotation was requested.
+	RequestTime time.Time
+
+	// ResolveTime is the time at which the rotation was either activated
+	// or aborted. It is zero while the rotation is pending.
+	ResolveTime time.Time
+
+	// seqNo is the position of the rotation within the log.
+	seqNo uint64
+}
+
+// NodeKeyIndex returns the index of the node key family from which the current
+// node identity key is derived.
+func (d *DB) NodeKeyIndex() (uint32, error) {
+	var index uint32
+	err := d.View(func(tx kvdb.Tx) error {
+		nodeKey := tx.Bucket(nodeKeyBucket)
+		if nodeKey == nil {
+			return nil
+		}
+
+		indexBytes := nodeKey.Get(activeNodeKeyIndexKey)
+		if indexBytes == nil {
+			return nil
+		}
+
+		index = byteOrder.Uint32(indexBytes)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return index, nil
+}
+
+// AddNodeKeyRotation records a new pending node key rotation. Only a single
+// rotation can be pending at any time, otherwise ErrNodeKeyRotationPending is
+// returned.
+func (d *DB) AddNodeKeyRotation(rotation *NodeKeyRotation) error {
+	return d.Update(func(tx kvdb.Tx) error {
+		nodeKey, err := tx.CreateBucketIfNotExists(nodeKeyBucket)
+		if err != nil {
+			return err
+		}
+		rotations, err := nodeKey.CreateBucketIfNotExists(
+			nodeKeyRotationBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		pending, err := fetchPendingNodeKeyRotation(rotations)
+		if err != nil {
+			return err
+		}
+		if pending != nil {
+			return ErrNodeKeyRotationPending
+		}
+
+		seqNo, err := rotations.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		rotation.Status = NodeKeyRotationPending
+		rotation.seqNo = seqNo
+
+		return putNodeKeyRotation(rotations, rotation)
+	})
+}
+
+// PendingNodeKeyRotation returns the pending node key rotation, or nil if
+// there is none.
+func (d *DB) PendingNodeKeyRotation() (*NodeKeyRotation, error) {
+	var pending *NodeKeyRotation
+	err := d.View(func(tx kvdb.Tx) error {
+		rotations := fetchNodeKeyRotationBucket(tx)
+		if rotations == nil {
+			return nil
+		}
+
+		var err error
+		pending, err = fetchPendingNodeKeyRotation(rotations)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// ResolveNodeKeyRotation resolves the pending node key rotation. If activate
+// is true, the new key becomes the node identity key, otherwise the rotation
+// is aborted. ErrNoNodeKeyRotationPending is returned if there is no pending
+// rotation.
+func (d *DB) ResolveNodeKeyRotation(activate bool) (*NodeKeyRotation, error) {
+	var pending *NodeKeyRotation
+	err := d.Update(func(tx kvdb.Tx) error {
+		nodeKey := tx.Bucket(nodeKeyBucket)
+		if nodeKey == nil {
+			return ErrNoNodeKeyRotationPending
+		}
+		rotations := nodeKey.Bucket(nodeKeyRotationBucket)
+		if rotations == nil {
+			return ErrNoNodeKeyRotationPending
+		}
+
+		var err error
+		pending, err = fetchPendingNodeKeyRotation(rotations)
+		if err != nil {
+			return err
+		}
+		if pending == nil {
+			return ErrNoNodeKeyRotationPending
+		}
+
+		pending.Status = NodeKeyRotationAborted
+		pending.ResolveTime = time.Now()
+
+		if activate {
+			pending.Status = NodeKeyRotationActivated
+
+			var indexBytes [4]byte
+			byteOrder.PutUint32(indexBytes[:], pending.NewKeyIndex)
+			err := nodeKey.Put(activeNodeKeyIndexKey, indexBytes[:])
+			if err != nil {
+				return err
+			}
+		}
+
+		return putNodeKeyRotation(rotations, pending)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// FetchNodeKeyRotations returns the log of all node key rotations, in the
+// order they were requested.
+func (d *DB) FetchNodeKeyRotations() ([]*NodeKeyRotation, error) {
+	var rotations []*NodeKeyRotation
+	err := d.View(func(tx kvdb.Tx) error {
+		rotationBucket := fetchNodeKeyRotationBucket(tx)
+		if rotationBucket == nil {
+			return nil
+		}
+
+		return rotationBucket.ForEach(func(k, v []byte) error {
+			rotation, err := deserializeNodeKeyRotation(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			rotation.seqNo = byteOrder.Uint64(k)
+
+			rotations = append(rotations, rotation)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rotations, nil
+}
+
+// fetchNodeKeyRotationBucket returns the bucket storing the node key rotation
+// log, or nil if it hasn't been created yet.
+func fetchNodeKeyRotationBucket(tx kvdb.Tx) kvdb.Bucket {
+	nodeKey := tx.Bucket(nodeKeyBucket)
+	if nodeKey == nil {
+		return nil
+	}
+
+	return nodeKey.Bucket(nodeKeyRotationBucket)
+}
+
+// fetchPendingNodeKeyRotation returns the pending rotation within the log, or
+// nil if there is none. As rotations can only be requested once the prior one
+// has been resolved, only the latest entry of the log can be pending.
+func fetchPendingNodeKeyRotation(
+	rotations kvdb.Bucket) (*NodeKeyRotation, error) {
+
+	k, v := rotations.Cursor().Last()
+	if k == nil {
+		return nil, nil
+	}
+
+	rotation, err := deserializeNodeKeyRotation(bytes.NewReader(v))
+	if err != nil {
+		return nil, err
+	}
+	if rotation.Status != NodeKeyRotationPending {
+		return nil, nil
+	}
+	rotation.seqNo = byteOrder.Uint64(k)
+
+	return rotation, nil
+}
+
+// putNodeKeyRotation serializes the rotation, and stores it under its sequence
+// number.
+func putNodeKeyRotation(rotations kvdb.Bucket,
+	rotation *NodeKeyRotation) error {
+
+	var b bytes.Buffer
+	if err := serializeNodeKeyRotation(&b, rotation); err != nil {
+		return err
+	}
+
+	var seqNo [8]byte
+	byteOrder.PutUint64(seqNo[:], rotation.seqNo)
+
+	return rotations.Put(seqNo[:], b.Bytes())
+}
+
+func serializeNodeKeyRotation(w io.Writer, r *NodeKeyRotation) error {
+	var resolveTime uint64
+	if !r.ResolveTime.IsZero() {
+		resolveTime = uint64(r.ResolveTime.UnixNano())
+	}
+
+	return WriteElements(w,
+		uint16(r.Status), r.OldKeyIndex, r.NewKeyIndex, r.OldPubKey,
+		r.NewPubKey, r.OldKeySig[:], r.NewKeySig[:],
+		uint64(r.RequestTime.UnixNano()), resolveTime,
+	)
+}
+
+func deserializeNodeKeyRotation(r io.Reader) (*NodeKeyRotation, error) {
+	var (
+		rotation    NodeKeyRotation
+		status      uint16
+		oldKeySig   []byte
+		newKeySig   []byte
+		requestTime uint64
+		resolveTime uint64
+	)
+	err := ReadElements(r,
+		&status, &rotation.OldKeyIndex, &rotation.NewKeyIndex,
+		&rotation.OldPubKey, &rotation.NewPubKey, &oldKeySig,
+		&newKeySig, &requestTime, &resolveTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(oldKeySig) != len(rotation.OldKeySig) ||
+		len(newKeySig) != len(rotation.NewKeySig) {
+
+		return nil, fmt.Errorf("invalid node key linkage signature " +
+			"length")
+	}
+	copy(rotation.OldKeySig[:], oldKeySig)
+	copy(rotation.NewKeySig[:], newKeySig)
+
+	rotation.Status = NodeKeyRotationStatus(status)
+	rotation.RequestTime = time.Unix(0, int64(requestTime))
+	if resolveTime != 0 {
+		rotation.ResolveTime = time.Unix(0, int64(resolveTime))
+	}
+
+	return &rotation, nil
+}
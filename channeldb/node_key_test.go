@@ -0,0 +1,140 @@
+package channeldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+)
+
+// TestNodeKeyRotation asserts that node key rotations are logged, and that the
+// active node key index only changes once a rotation is activated.
+func TestNodeKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	assertIndex := func(expected uint32) {
+		t.Helper()
+
+		index, err := cdb.NodeKeyIndex()
+		if err != nil {
+			t.Fatalf("unable to fetch node key index: %v", err)
+		}
+		if index != expected {
+			t.Fatalf("expected node key index %d, got %d",
+				expected, index)
+		}
+	}
+
+	assertPending := func(expected *NodeKeyRotation) {
+		t.Helper()
+
+		pending, err := cdb.PendingNodeKeyRotation()
+		if err != nil {
+			t.Fatalf("unable to fetch pending rotation: %v", err)
+		}
+		switch {
+		case expected == nil && pending != nil:
+			t.Fatalf("expected no pending rotation, got %v",
+				pending.NewKeyIndex)
+		case expected != nil && pending == nil:
+			t.Fatalf("expected pending rotation")
+		case expected != nil &&
+			!pending.NewPubKey.IsEqual(expected.NewPubKey):
+
+			t.Fatalf("pending rotation doesn't match")
+		}
+	}
+
+	// Initially, the identity key is derived from the first index, and
+	// there are no rotations.
+	assertIndex(0)
+	assertPending(nil)
+	_, err = cdb.ResolveNodeKeyRotation(true)
+	if err != ErrNoNodeKeyRotationPending {
+		t.Fatalf("expected ErrNoNodeKeyRotationPending, got %v", err)
+	}
+
+	newRotation := func(oldIndex uint32) *NodeKeyRotation {
+		_, oldKey := btcec.PrivKeyFromBytes(btcec.S256(), []byte{0x01})
+		_, newKey := btcec.PrivKeyFromBytes(btcec.S256(), []byte{0x02})
+
+		return &NodeKeyRotation{
+			OldKeyIndex: oldIndex,
+			NewKeyIndex: oldIndex + 1,
+			OldPubKey:   oldKey,
+			NewPubKey:   newKey,
+			OldKeySig:   [64]byte{0x03},
+			NewKeySig:   [64]byte{0x04},
+			RequestTime: time.Unix(0, 1000),
+		}
+	}
+
+	// Requesting a rotation shouldn't affect the active index until it is
+	// activated, and no other rotation can be requested in the meantime.
+	rotation := newRotation(0)
+	if err := cdb.AddNodeKeyRotation(rotation); err != nil {
+		t.Fatalf("unable to add rotation: %v", err)
+	}
+	assertIndex(0)
+	assertPending(rotation)
+
+	err = cdb.AddNodeKeyRotation(newRotation(0))
+	if err != ErrNodeKeyRotationPending {
+		t.Fatalf("expected ErrNodeKeyRotationPending, got %v", err)
+	}
+
+	activated, err := cdb.ResolveNodeKeyRotation(true)
+	if err != nil {
+		t.Fatalf("unable to activate rotation: %v", err)
+	}
+	if activated.Status != NodeKeyRotationActivated {
+		t.Fatalf("expected status %v, got %v",
+			NodeKeyRotationActivated, activated.Status)
+	}
+	assertIndex(1)
+	assertPending(nil)
+
+	// An aborted rotation should leave the active index untouched.
+	if err := cdb.AddNodeKeyRotation(newRotation(1)); err != nil {
+		t.Fatalf("unable to add rotation: %v", err)
+	}
+	if _, err := cdb.ResolveNodeKeyRotation(false); err != nil {
+		t.Fatalf("unable to abort rotation: %v", err)
+	}
+	assertIndex(1)
+	assertPending(nil)
+
+	// Both rotations should be present in the log, in order.
+	rotations, err := cdb.FetchNodeKeyRotations()
+	if err != nil {
+		t.Fatalf("unable to fetch rotations: %v", err)
+	}
+	if len(rotations) != 2 {
+		t.Fatalf("expected 2 rotations, got %d", len(rotations))
+	}
+	expStatus := []NodeKeyRotationStatus{
+		NodeKeyRotationActivated, NodeKeyRotationAborted,
+	}
+	for i, rotation := range rotations {
+		if rotation.Status != expStatus[i] {
+			t.Fatalf("expected rotation %d to have status %v, "+
+				"got %v", i, expStatus[i], rotation.Status)
+		}
+		if rotation.OldKeyIndex != uint32(i) {
+			t.Fatalf("expected rotation %d to have old index %d, "+
+				"got %d", i, i, rotation.OldKeyIndex)
+		}
+		if rotation.NewKeySig != [64]byte{0x04} {
+			t.Fatalf("linkage signature not persisted")
+		}
+		if rotation.ResolveTime.IsZero() {
+			t.Fatalf("expected rotation %d to be resolved", i)
+		}
+	}
+}
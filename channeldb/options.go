@@ -1,5 +1,7 @@
 package channeldb
 
+import "time"
+
 const (
 	// DefaultRejectCacheSize is the default number of rejectCacheEntries to
 	// cache for use in the rejection cache of incoming gossip traffic. This
@@ -10,6 +12,11 @@ const (
 	// in order to reply to gossip queries. This produces a cache size of
 	// around 40MB.
 	DefaultChannelCacheSize = 20000
+
+	// DefaultBatchCommitInterval is the default maximum duration writes of
+	// graph updates received from the network will be held back, such
+	// that they can be coalesced into a single database transaction.
+	DefaultBatchCommitInterval = 500 * time.Millisecond
 )
 
 // Options holds parameters for tuning and customizing a channeldb.DB.
@@ -21,13 +28,24 @@ type Options struct {
 	// ChannelCacheSize is the maximum number of ChannelEdges to hold in the
 	// channel cache.
 	ChannelCacheSize int
+
+	// BatchCommitInterval is the maximum duration the channel graph batch
+	// schedulers will wait before attempting to commit a batch of pending
+	// updates that were scheduled lazily.
+	BatchCommitInterval time.Duration
+
+	// AutoCompactRatio is the ratio of free pages of the database above
+	// which it's compacted when opened. If zero, the database is never
+	// compacted automatically.
+	AutoCompactRatio float64
 }
 
 // DefaultOptions returns an Options populated with default values.
 func DefaultOptions() Options {
 	return Options{
-		RejectCacheSize:  DefaultRejectCacheSize,
-		ChannelCacheSize: DefaultChannelCacheSize,
+		RejectCacheSize:     DefaultRejectCacheSize,
+		ChannelCacheSize:    DefaultChannelCacheSize,
+		BatchCommitInterval: DefaultBatchCommitInterval,
 	}
 }
 
@@ -47,3 +65,17 @@ func OptionSetChannelCacheSize(n int) OptionModifier {
 		o.ChannelCacheSize = n
 	}
 }
+
+// OptionSetAutoCompactRatio sets the AutoCompactRatio to ratio.
+func OptionSetAutoCompactRatio(ratio float64) OptionModifier {
+	return func(o *Options) {
+		o.AutoCompactRatio = ratio
+	}
+}
+
+// OptionSetBatchCommitInterval sets the BatchCommitInterval to interval.
+func OptionSetBatchCommitInterval(interval time.Duration) OptionModifier {
+	return func(o *Options) {
+		o.BatchCommitInterval = interval
+	}
+}
@@ -5,8 +5,9 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+	"time"
 
-	"github.com/coreos/bbolt"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 )
 
@@ -118,7 +119,7 @@ func (db *DB) AddPayment(payment *OutgoingPayment) error {
 	}
 	paymentBytes := b.Bytes()
 
-	return db.Batch(func(tx *bbolt.Tx) error {
+	return db.Batch(func(tx kvdb.Tx) error {
 		payments, err := tx.CreateBucketIfNotExists(paymentBucket)
 		if err != nil {
 			return err
@@ -144,7 +145,7 @@ func (db *DB) AddPayment(payment *OutgoingPayment) error {
 func (db *DB) FetchAllPayments() ([]*OutgoingPayment, error) {
 	var payments []*OutgoingPayment
 
-	err := db.View(func(tx *bbolt.Tx) error {
+	err := db.View(func(tx kvdb.Tx) error {
 		bucket := tx.Bucket(paymentBucket)
 		if bucket == nil {
 			return ErrNoPaymentsCreated
@@ -176,9 +177,9 @@ func (db *DB) FetchAllPayments() ([]*OutgoingPayment, error) {
 
 // DeleteAllPayments deletes all payments from DB.
 func (db *DB) DeleteAllPayments() error {
-	return db.Update(func(tx *bbolt.Tx) error {
+	return db.Update(func(tx kvdb.Tx) error {
 		err := tx.DeleteBucket(paymentBucket)
-		if err != nil && err != bbolt.ErrBucketNotFound {
+		if err != nil && err != kvdb.ErrBucketNotFound {
 			return err
 		}
 
@@ -187,10 +188,116 @@ func (db *DB) DeleteAllPayments() error {
 	})
 }
 
+// PaymentDeletionQuery selects the payment records to be deleted by
+// DeletePayments. Payments that are still in flight are never deleted.
+type PaymentDeletionQuery struct {
+	// Failed selects the status records of all payments that failed. As
+	// failed payments are otherwise only recorded within the payment
+	// status index, this doesn't affect the payments returned by
+	// FetchAllPayments.
+	Failed bool
+
+	// CompletedBefore selects the completed payments that were created
+	// before this time. Their status records are retained, such that the
+	// payment hashes can't be paid again. The zero value selects none.
+	CompletedBefore time.Time
+}
+
+// DeletePayments deletes the payment records selected by the query, returning
+// the number of records deleted.
+func (db *DB) DeletePayments(q PaymentDeletionQuery) (int, error) {
+	var numDeleted int
+	err := db.Update(func(tx kvdb.Tx) error {
+		numDeleted = 0
+
+		payments := tx.Bucket(paymentBucket)
+		if payments != nil && !q.CompletedBefore.IsZero() {
+			// We'll first collect the keys of the payments to
+			// delete, as keys can't be deleted while iterating
+			// over them.
+			var keys [][]byte
+			err := payments.ForEach(func(k, v []byte) error {
+				if v == nil {
+					return nil
+				}
+
+				payment, err := deserializeOutgoingPayment(
+					bytes.NewReader(v),
+				)
+				if err != nil {
+					return err
+				}
+
+				created := payment.Invoice.CreationDate
+				if !created.Before(q.CompletedBefore) {
+					return nil
+				}
+
+				// The key is copied, as it's only valid for
+				// the lifetime of the iteration.
+				k = append([]byte(nil), k...)
+				keys = append(keys, k)
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, k := range keys {
+				if err := payments.Delete(k); err != nil {
+					return err
+				}
+			}
+			numDeleted += len(keys)
+		}
+
+		statuses := tx.Bucket(paymentStatusBucket)
+		if statuses == nil || !q.Failed {
+			return nil
+		}
+
+		// As the status of a payment defaults to StatusGrounded, the
+		// records of failed payments can be removed without changing
+		// the status reported for their payment hashes.
+		var hashes [][]byte
+		err := statuses.ForEach(func(k, v []byte) error {
+			var status PaymentStatus
+			if err := status.FromBytes(v); err != nil {
+				return err
+			}
+
+			if status == StatusGrounded {
+				hash := append([]byte(nil), k...)
+				hashes = append(hashes, hash)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, hash := range hashes {
+			if err := statuses.Delete(hash); err != nil {
+				return err
+			}
+		}
+		numDeleted += len(hashes)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numDeleted, nil
+}
+
 // UpdatePaymentStatus sets the payment status for outgoing/finished payments in
 // local database.
 func (db *DB) UpdatePaymentStatus(paymentHash [32]byte, status PaymentStatus) error {
-	return db.Batch(func(tx *bbolt.Tx) error {
+	return db.Batch(func(tx kvdb.Tx) error {
 		return UpdatePaymentStatusTx(tx, paymentHash, status)
 	})
 }
@@ -199,7 +306,7 @@ func (db *DB) UpdatePaymentStatus(paymentHash [32]byte, status PaymentStatus) er
 // outgoing/finished payments in the local database. This method accepts a
 // boltdb transaction such that the operation can be composed into other
 // database transactions.
-func UpdatePaymentStatusTx(tx *bbolt.Tx,
+func UpdatePaymentStatusTx(tx kvdb.Tx,
 	paymentHash [32]byte, status PaymentStatus) error {
 
 	paymentStatuses, err := tx.CreateBucketIfNotExists(paymentStatusBucket)
@@ -214,7 +321,7 @@ func UpdatePaymentStatusTx(tx *bbolt.Tx,
 // If status of the payment isn't found, it will default to "StatusGrounded".
 func (db *DB) FetchPaymentStatus(paymentHash [32]byte) (PaymentStatus, error) {
 	var paymentStatus = StatusGrounded
-	err := db.View(func(tx *bbolt.Tx) error {
+	err := db.View(func(tx kvdb.Tx) error {
 		var err error
 		paymentStatus, err = FetchPaymentStatusTx(tx, paymentHash)
 		return err
@@ -230,7 +337,7 @@ func (db *DB) FetchPaymentStatus(paymentHash [32]byte) (PaymentStatus, error) {
 // outgoing payment.  If status of the payment isn't found, it will default to
 // "StatusGrounded". It accepts the boltdb transactions such that this method
 // can be composed into other atomic operations.
-func FetchPaymentStatusTx(tx *bbolt.Tx, paymentHash [32]byte) (PaymentStatus, error) {
+func FetchPaymentStatusTx(tx kvdb.Tx, paymentHash [32]byte) (PaymentStatus, error) {
 	// The default status for all payments that aren't recorded in database.
 	var paymentStatus = StatusGrounded
 
@@ -2,6 +2,7 @@ package channeldb
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"math/rand"
 	"reflect"
@@ -251,3 +252,89 @@ func TestPaymentStatusWorkflow(t *testing.T) {
 		}
 	}
 }
+
+// TestDeletePayments tests that only the payment records selected by a
+// deletion query are removed, and that in-flight and completed payments
+// retain their status.
+func TestDeletePayments(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	oldPayment, err := makeRandomFakePayment()
+	if err != nil {
+		t.Fatalf("unable to create payment: %v", err)
+	}
+	oldPayment.Invoice.CreationDate = oldPayment.Invoice.CreationDate.Add(
+		-time.Hour,
+	)
+	newPayment, err := makeRandomFakePayment()
+	if err != nil {
+		t.Fatalf("unable to create payment: %v", err)
+	}
+	for _, payment := range []*OutgoingPayment{oldPayment, newPayment} {
+		if err := db.AddPayment(payment); err != nil {
+			t.Fatalf("unable to add payment: %v", err)
+		}
+
+		hash := sha256.Sum256(payment.PaymentPreimage[:])
+		err := db.UpdatePaymentStatus(hash, StatusCompleted)
+		if err != nil {
+			t.Fatalf("unable to update payment status: %v", err)
+		}
+	}
+
+	failedHash := makeFakePaymentHash()
+	inFlightHash := makeFakePaymentHash()
+	err = db.UpdatePaymentStatus(failedHash, StatusGrounded)
+	if err != nil {
+		t.Fatalf("unable to update payment status: %v", err)
+	}
+	err = db.UpdatePaymentStatus(inFlightHash, StatusInFlight)
+	if err != nil {
+		t.Fatalf("unable to update payment status: %v", err)
+	}
+
+	numDeleted, err := db.DeletePayments(PaymentDeletionQuery{
+		Failed:          true,
+		CompletedBefore: time.Now().Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("unable to delete payments: %v", err)
+	}
+	if numDeleted != 2 {
+		t.Fatalf("expected 2 deleted records, got %v", numDeleted)
+	}
+
+	payments, err := db.FetchAllPayments()
+	if err != nil {
+		t.Fatalf("unable to fetch payments: %v", err)
+	}
+	expectedPayments := []*OutgoingPayment{newPayment}
+	if !reflect.DeepEqual(payments, expectedPayments) {
+		t.Fatalf("expected payments %v, got %v",
+			spew.Sdump(expectedPayments), spew.Sdump(payments))
+	}
+
+	// The deleted payment should still be known to have completed, such
+	// that it can't be paid again.
+	statuses := map[[32]byte]PaymentStatus{
+		sha256.Sum256(oldPayment.PaymentPreimage[:]): StatusCompleted,
+		failedHash:   StatusGrounded,
+		inFlightHash: StatusInFlight,
+	}
+	for hash, expected := range statuses {
+		status, err := db.FetchPaymentStatus(hash)
+		if err != nil {
+			t.Fatalf("unable to fetch payment status: %v", err)
+		}
+		if status != expected {
+			t.Fatalf("expected status %v, got %v", expected,
+				status)
+		}
+	}
+}
@@ -93,3 +93,15 @@ func (c *rejectCache) insert(chanid uint64, entry rejectCacheEntry) {
 func (c *rejectCache) remove(chanid uint64) {
 	delete(c.edges, chanid)
 }
+
+// resize changes the maximum capacity of the cache to n entries, evicting
+// entries at random until the cache fits within its new capacity.
+func (c *rejectCache) resize(n int) {
+	c.n = n
+	for id := range c.edges {
+		if len(c.edges) <= n {
+			break
+		}
+		delete(c.edges, id)
+	}
+}
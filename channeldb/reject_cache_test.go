@@ -75,6 +75,37 @@ func TestRejectCache(t *testing.T) {
 
 }
 
+// TestRejectCacheResize asserts that shrinking the reject cache evicts entries
+// until it fits within its new capacity, and that growing it allows more
+// entries to be inserted without eviction.
+func TestRejectCacheResize(t *testing.T) {
+	const cacheSize = 100
+
+	c := newRejectCache(cacheSize)
+	for i := uint64(0); i < cacheSize; i++ {
+		c.insert(i, entryForInt(i))
+	}
+
+	// Shrinking the cache should evict entries until it fits.
+	c.resize(cacheSize / 2)
+	if len(c.edges) != cacheSize/2 {
+		t.Fatalf("expected %d entries after shrinking, got %d",
+			cacheSize/2, len(c.edges))
+	}
+
+	// Growing it again shouldn't evict anything, and allow the cache to
+	// be filled up to its new capacity.
+	c.resize(2 * cacheSize)
+	for i := uint64(cacheSize); i < 2*cacheSize; i++ {
+		c.insert(i, entryForInt(i))
+	}
+	if len(c.edges) != cacheSize/2+cacheSize {
+		t.Fatalf("expected %d entries after growing, got %d",
+			cacheSize/2+cacheSize, len(c.edges))
+	}
+	assertHasEntries(t, c, cacheSize, 2*cacheSize)
+}
+
 // assertHasEntries queries the reject cache for all channels in the range [start,
 // end), asserting that they exist and their value matches the entry produced by
 // entryForInt.
@@ -0,0 +1,38 @@
+package channeldb
+
+import (
+	"errors"
+	"io"
+
+	"github.com/coreos/bbolt"
+)
+
+// ErrSnapshotUnsupported is returned when attempting to snapshot a database
+// that isn't stored within a local bolt database file.
+var ErrSnapshotUnsupported = errors.New("snapshots are only supported for " +
+	"bolt databases")
+
+// WriteSnapshot writes a consistent copy of the database file to w, returning
+// the number of bytes written. The copy is taken within a read-only
+// transaction, so the database remains fully usable while it's written, and
+// reflects its state at the time the transaction was opened. As the copy is
+// written page by page, the pages of the database that haven't changed
+// between two snapshots are found at the same offsets within both.
+func (d *DB) WriteSnapshot(w io.Writer) (int64, error) {
+	d.gate.enter()
+	defer d.gate.exit()
+
+	bdb, err := d.boltDB()
+	if err != nil {
+		return 0, ErrSnapshotUnsupported
+	}
+
+	var n int64
+	err = bdb.View(func(tx *bbolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+
+	return n, err
+}
@@ -0,0 +1,396 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+var (
+	// spliceKey stores the pending splice of a channel, if any, within
+	// the bucket of the channel.
+	spliceKey = []byte("splice-key")
+
+	// ErrNoSplice is returned when a channel doesn't have a pending
+	// splice.
+	ErrNoSplice = fmt.Errorf("no pending splice found")
+
+	// ErrSpliceExists is returned when adding a splice to a channel that
+	// already has a pending splice.
+	ErrSpliceExists = fmt.Errorf("channel already has a pending splice")
+)
+
+// ChannelSplice is a pending splice of a channel. The splice transaction
+// spends the current funding output of the channel, and pays to the new one,
+// which holds the spliced capacity. Until the splice transaction confirms,
+// both the current commitments of the channel and the ones spending the new
+// funding output are valid, so the channel isn't updated off-chain anymore.
+type ChannelSplice struct {
+	// SpliceTx is the splice transaction. The initiator of the splice
+	// stores it along with the witnesses of its own inputs, the responder
+	// without any witnesses.
+	SpliceTx *wire.MsgTx
+
+	// FundingOutpoint is the new funding outpoint of the channel, created
+	// by the splice transaction.
+	FundingOutpoint wire.OutPoint
+
+	// Capacity is the capacity of the channel once spliced.
+	Capacity btcutil.Amount
+
+	// LocalCommitment is our commitment spending the new funding output.
+	// It's at the same height as our current commitment, and carries the
+	// signature of the remote party.
+	LocalCommitment ChannelCommitment
+
+	// RemoteCommitment is the commitment of the remote party spending the
+	// new funding output. It's at the same height as their current
+	// commitment, and carries our signature for it.
+	RemoteCommitment ChannelCommitment
+
+	// IsInitiator is true if we initiated the splice, and are therefore
+	// the party completing, and broadcasting, the splice transaction.
+	IsInitiator bool
+
+	// FundingSig is our signature for the input of the splice
+	// transaction spending the current funding output.
+	FundingSig []byte
+
+	// Complete is true once the splice transaction carries all of its
+	// witnesses.
+	Complete bool
+
+	// BroadcastHeight is the height at which the splice was signed, which
+	// serves as the height hint when waiting for the splice transaction
+	// to confirm.
+	BroadcastHeight uint32
+}
+
+// AddSplice persists a pending splice of the channel, and marks the channel
+// with the ChanStatusSplicing status, such that its commitments are no longer
+// updated.
+func (c *OpenChannel) AddSplice(splice *ChannelSplice) error {
+	c.Lock()
+	defer c.Unlock()
+
+	var status ChannelStatus
+	if err := c.Db.Update(func(tx kvdb.Tx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		if chanBucket.Get(spliceKey) != nil {
+			return ErrSpliceExists
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		status = channel.chanStatus | ChanStatusSplicing
+		channel.chanStatus = status
+
+		if err := putSplice(chanBucket, splice); err != nil {
+			return err
+		}
+
+		return putOpenChannel(chanBucket, channel)
+	}); err != nil {
+		return err
+	}
+
+	c.chanStatus = status
+
+	return nil
+}
+
+// UpdateSplice overwrites the pending splice of the channel, which is used by
+// the initiator of the splice to store the completed splice transaction.
+func (c *OpenChannel) UpdateSplice(splice *ChannelSplice) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx kvdb.Tx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		if chanBucket.Get(spliceKey) == nil {
+			return ErrNoSplice
+		}
+
+		return putSplice(chanBucket, splice)
+	})
+}
+
+// Splice returns the pending splice of the channel. If the channel doesn't
+// have one, ErrNoSplice is returned.
+func (c *OpenChannel) Splice() (*ChannelSplice, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var splice *ChannelSplice
+	err := c.Db.View(func(tx kvdb.Tx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		switch err {
+		case nil:
+		case ErrNoChanDBExists, ErrNoActiveChannels, ErrChannelNotFound:
+			return ErrNoSplice
+		default:
+			return err
+		}
+
+		splice, err = fetchSplice(chanBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return splice, nil
+}
+
+// RemoveSplice removes the pending splice of the channel, and clears its
+// ChanStatusSplicing status. This is only safe as long as the splice
+// transaction can't be completed by the remote party, as the channel would
+// otherwise be spliced without us noticing.
+func (c *OpenChannel) RemoveSplice() error {
+	c.Lock()
+	defer c.Unlock()
+
+	var status ChannelStatus
+	if err := c.Db.Update(func(tx kvdb.Tx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		if chanBucket.Get(spliceKey) == nil {
+			return ErrNoSplice
+		}
+		if err := chanBucket.Delete(spliceKey); err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		status = channel.chanStatus & ^ChanStatusSplicing
+		channel.chanStatus = status
+
+		return putOpenChannel(chanBucket, channel)
+	}); err != nil {
+		return err
+	}
+
+	c.chanStatus = status
+
+	return nil
+}
+
+// ApplySplice moves the channel over to the new funding output once its
+// splice transaction confirmed at the given location. The commitments of the
+// splice replace the current ones, and as the channel is identified by its
+// funding outpoint, its data is moved to the new funding outpoint as well.
+// The revocation log is kept, as the commitment heights of the channel carry
+// on from its current ones.
+func (c *OpenChannel) ApplySplice(shortChanID lnwire.ShortChannelID) error {
+	c.Lock()
+	defer c.Unlock()
+
+	var channel *OpenChannel
+	if err := c.Db.Update(func(tx kvdb.Tx) error {
+		chainBucket, err := fetchChainBucket(
+			tx, c.IdentityPub, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		var oldChanPoint bytes.Buffer
+		err = writeOutpoint(&oldChanPoint, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+		chanBucket := chainBucket.Bucket(oldChanPoint.Bytes())
+		if chanBucket == nil {
+			return ErrChannelNotFound
+		}
+
+		splice, err := fetchSplice(chanBucket)
+		if err != nil {
+			return err
+		}
+
+		channel, err = fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		channel.FundingOutpoint = splice.FundingOutpoint
+		channel.Capacity = splice.Capacity
+		channel.ShortChannelID = shortChanID
+		channel.FundingBroadcastHeight = splice.BroadcastHeight
+		channel.LocalCommitment = splice.LocalCommitment
+		channel.RemoteCommitment = splice.RemoteCommitment
+		channel.chanStatus &= ^ChanStatusSplicing
+
+		var newChanPoint bytes.Buffer
+		err = writeOutpoint(&newChanPoint, &splice.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+		newChanBucket, err := chainBucket.CreateBucket(
+			newChanPoint.Bytes(),
+		)
+		if err != nil {
+			return err
+		}
+
+		// The remaining state of the channel, such as its revocation
+		// log, is carried over as is.
+		if err := copyBucket(newChanBucket, chanBucket); err != nil {
+			return err
+		}
+		if err := newChanBucket.Delete(spliceKey); err != nil {
+			return err
+		}
+		if err := putOpenChannel(newChanBucket, channel); err != nil {
+			return err
+		}
+
+		return chainBucket.DeleteBucket(oldChanPoint.Bytes())
+	}); err != nil {
+		return err
+	}
+
+	c.FundingOutpoint = channel.FundingOutpoint
+	c.Capacity = channel.Capacity
+	c.ShortChannelID = shortChanID
+	c.FundingBroadcastHeight = channel.FundingBroadcastHeight
+	c.LocalCommitment = channel.LocalCommitment
+	c.RemoteCommitment = channel.RemoteCommitment
+	c.chanStatus = channel.chanStatus
+	c.Packager = NewChannelPackager(shortChanID)
+
+	return nil
+}
+
+// fetchChainBucket returns the bucket holding the channels with the given
+// node on the given chain.
+func fetchChainBucket(tx kvdb.Tx, nodeKey *btcec.PublicKey,
+	chainHash chainhash.Hash) (kvdb.Bucket, error) {
+
+	openChanBucket := tx.Bucket(openChannelBucket)
+	if openChanBucket == nil {
+		return nil, ErrNoChanDBExists
+	}
+
+	nodeChanBucket := openChanBucket.Bucket(nodeKey.SerializeCompressed())
+	if nodeChanBucket == nil {
+		return nil, ErrNoActiveChannels
+	}
+
+	chainBucket := nodeChanBucket.Bucket(chainHash[:])
+	if chainBucket == nil {
+		return nil, ErrNoActiveChannels
+	}
+
+	return chainBucket, nil
+}
+
+// copyBucket copies all keys, and nested buckets, of src into dst.
+func copyBucket(dst, src kvdb.Bucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		// A nil value indicates a nested bucket.
+		if v != nil {
+			return dst.Put(k, v)
+		}
+
+		nestedDst, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+
+		return copyBucket(nestedDst, src.Bucket(k))
+	})
+}
+
+func putSplice(chanBucket kvdb.Bucket, splice *ChannelSplice) error {
+	var b bytes.Buffer
+	if err := serializeSplice(&b, splice); err != nil {
+		return err
+	}
+
+	return chanBucket.Put(spliceKey, b.Bytes())
+}
+
+func fetchSplice(chanBucket kvdb.Bucket) (*ChannelSplice, error) {
+	spliceBytes := chanBucket.Get(spliceKey)
+	if spliceBytes == nil {
+		return nil, ErrNoSplice
+	}
+
+	return deserializeSplice(bytes.NewReader(spliceBytes))
+}
+
+func serializeSplice(w io.Writer, splice *ChannelSplice) error {
+	err := WriteElements(w,
+		splice.SpliceTx, splice.FundingOutpoint, splice.Capacity,
+		splice.IsInitiator, splice.FundingSig, splice.Complete,
+		splice.BroadcastHeight,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := serializeChanCommit(w, &splice.LocalCommitment); err != nil {
+		return err
+	}
+
+	return serializeChanCommit(w, &splice.RemoteCommitment)
+}
+
+func deserializeSplice(r io.Reader) (*ChannelSplice, error) {
+	splice := &ChannelSplice{}
+	err := ReadElements(r,
+		&splice.SpliceTx, &splice.FundingOutpoint, &splice.Capacity,
+		&splice.IsInitiator, &splice.FundingSig, &splice.Complete,
+		&splice.BroadcastHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	splice.LocalCommitment, err = deserializeChanCommit(r)
+	if err != nil {
+		return nil, err
+	}
+
+	splice.RemoteCommitment, err = deserializeChanCommit(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return splice, nil
+}
@@ -0,0 +1,137 @@
+package channeldb
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestChannelSplice asserts that a pending splice is persisted along with the
+// channel, and that applying it moves the channel to its new funding outpoint.
+func TestChannelSplice(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	channel, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	addr := &net.TCPAddr{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: 18555,
+	}
+	if err := channel.SyncPending(addr, 101); err != nil {
+		t.Fatalf("unable to save channel state: %v", err)
+	}
+	err = channel.MarkAsOpen(lnwire.NewShortChanIDFromInt(1))
+	if err != nil {
+		t.Fatalf("unable to mark channel open: %v", err)
+	}
+	oldChanPoint := channel.FundingOutpoint
+
+	if _, err := channel.Splice(); err != ErrNoSplice {
+		t.Fatalf("expected ErrNoSplice, got %v", err)
+	}
+
+	localCommit := channel.LocalCommitment
+	localCommit.LocalBalance += 5000
+	remoteCommit := channel.RemoteCommitment
+	remoteCommit.RemoteBalance += 5000
+	splice := &ChannelSplice{
+		SpliceTx: testTx,
+		FundingOutpoint: wire.OutPoint{
+			Hash:  testTx.TxHash(),
+			Index: 1,
+		},
+		Capacity:         channel.Capacity + 5,
+		LocalCommitment:  localCommit,
+		RemoteCommitment: remoteCommit,
+		IsInitiator:      true,
+		FundingSig:       []byte{1, 2, 3},
+		BroadcastHeight:  120,
+	}
+	if err := channel.AddSplice(splice); err != nil {
+		t.Fatalf("unable to add splice: %v", err)
+	}
+	if err := channel.AddSplice(splice); err != ErrSpliceExists {
+		t.Fatalf("expected ErrSpliceExists, got %v", err)
+	}
+
+	// The splicing status must be persisted along with the splice.
+	dbChannel, err := cdb.FetchChannel(oldChanPoint)
+	if err != nil {
+		t.Fatalf("unable to fetch channel: %v", err)
+	}
+	if !dbChannel.HasChanStatus(ChanStatusSplicing) {
+		t.Fatalf("expected channel to be splicing, got status %v",
+			dbChannel.ChanStatus())
+	}
+
+	dbSplice, err := dbChannel.Splice()
+	if err != nil {
+		t.Fatalf("unable to fetch splice: %v", err)
+	}
+	if !reflect.DeepEqual(splice, dbSplice) {
+		t.Fatalf("splice mismatch: expected %v, got %v", splice,
+			dbSplice)
+	}
+
+	splice.Complete = true
+	if err := channel.UpdateSplice(splice); err != nil {
+		t.Fatalf("unable to update splice: %v", err)
+	}
+
+	shortChanID := lnwire.ShortChannelID{
+		BlockHeight: 130,
+		TxIndex:     2,
+		TxPosition:  1,
+	}
+	if err := channel.ApplySplice(shortChanID); err != nil {
+		t.Fatalf("unable to apply splice: %v", err)
+	}
+
+	// The channel must no longer be found under its old funding
+	// outpoint.
+	if _, err := cdb.FetchChannel(oldChanPoint); err == nil {
+		t.Fatalf("expected channel to be moved")
+	}
+
+	dbChannel, err = cdb.FetchChannel(splice.FundingOutpoint)
+	if err != nil {
+		t.Fatalf("unable to fetch spliced channel: %v", err)
+	}
+	if _, err := dbChannel.Splice(); err != ErrNoSplice {
+		t.Fatalf("expected ErrNoSplice, got %v", err)
+	}
+
+	for _, c := range []*OpenChannel{channel, dbChannel} {
+		switch {
+		case c.HasChanStatus(ChanStatusSplicing):
+			t.Fatalf("expected splicing status to be cleared")
+
+		case c.Capacity != splice.Capacity:
+			t.Fatalf("expected capacity %v, got %v",
+				splice.Capacity, c.Capacity)
+
+		case c.ShortChanID() != shortChanID:
+			t.Fatalf("expected short chan id %v, got %v",
+				shortChanID, c.ShortChanID())
+
+		case c.FundingBroadcastHeight != splice.BroadcastHeight:
+			t.Fatalf("expected broadcast height %v, got %v",
+				splice.BroadcastHeight,
+				c.FundingBroadcastHeight)
+		}
+
+		assertCommitmentEqual(t, &c.LocalCommitment, &localCommit)
+		assertCommitmentEqual(t, &c.RemoteCommitment, &remoteCommit)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/lnd/brontide"
 	"github.com/litecoinfinance/lnd/chanbackup"
 	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/contractcourt"
@@ -202,3 +203,44 @@ func (s *server) ConnectPeer(nodePub *btcec.PublicKey, addrs []net.Addr) error {
 	return fmt.Errorf("unable to connect to peer %x for SCB restore",
 		nodePub.SerializeCompressed())
 }
+
+// A compile-time constraint to ensure server implements
+// chanbackup.PeerProber.
+var _ chanbackup.PeerProber = (*server)(nil)
+
+// ProbePeer checks whether the target node can currently be reached, either
+// because we're already connected to it, or because we're able to establish a
+// connection to one of the given addresses. Such probe connections are closed
+// right away.
+//
+// NOTE: Part of the chanbackup.PeerProber interface.
+func (s *server) ProbePeer(nodePub *btcec.PublicKey, addrs []net.Addr) error {
+	if _, err := s.FindPeer(nodePub); err == nil {
+		return nil
+	}
+
+	if len(addrs) == 0 {
+		return fmt.Errorf("not connected to peer %x, and no addresses "+
+			"are known", nodePub.SerializeCompressed())
+	}
+
+	var dialErr error
+	for _, addr := range addrs {
+		netAddr := &lnwire.NetAddress{
+			IdentityKey: nodePub,
+			Address:     addr,
+		}
+
+		conn, err := brontide.Dial(s.identityPriv, netAddr, cfg.net.Dial)
+		if err != nil {
+			dialErr = err
+			continue
+		}
+
+		return conn.Close()
+	}
+
+	return fmt.Errorf("unable to reach peer %x at any of its %d "+
+		"addresses: %v", nodePub.SerializeCompressed(), len(addrs),
+		dialErr)
+}
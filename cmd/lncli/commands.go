@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -15,6 +16,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
@@ -106,11 +108,21 @@ var newAddressCommand = cli.Command{
 	Name:      "newaddress",
 	Category:  "Wallet",
 	Usage:     "Generates a new address.",
-	ArgsUsage: "address-type",
+	ArgsUsage: "address-type [--account=N]",
 	Description: `
 	Generate a wallet new address. Address-types has to be one of:
 	    - p2wkh:  Pay to witness key hash
-	    - np2wkh: Pay to nested witness key hash`,
+	    - np2wkh: Pay to nested witness key hash
+
+	The address is derived from the default account of the wallet, unless
+	another account is specified.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "account",
+			Usage: "(optional) the name of the account to derive " +
+				"the address from",
+		},
+	},
 	Action: actionDecorator(newAddress),
 }
 
@@ -135,7 +147,8 @@ func newAddress(ctx *cli.Context) error {
 
 	ctxb := context.Background()
 	addr, err := client.NewAddress(ctxb, &lnrpc.NewAddressRequest{
-		Type: addrType,
+		Type:    addrType,
+		Account: ctx.String("account"),
 	})
 	if err != nil {
 		return err
@@ -191,6 +204,37 @@ func estimateFees(ctx *cli.Context) error {
 	return nil
 }
 
+var coinSelectionStrategyFlag = cli.StringFlag{
+	Name: "coin_selection_strategy",
+	Usage: "(optional) the order in which the coins of the wallet " +
+		"are selected to fund the transaction, one of " +
+		"largest-first, smallest-first, random, or " +
+		"branch-and-bound, the latter of which avoids a change " +
+		"output if possible",
+}
+
+// parseCoinSelectionStrategy returns the coin selection strategy set by the
+// coin_selection_strategy flag.
+func parseCoinSelectionStrategy(
+	ctx *cli.Context) (lnrpc.CoinSelectionStrategy, error) {
+
+	switch ctx.String(coinSelectionStrategyFlag.Name) {
+	case "":
+		return lnrpc.CoinSelectionStrategy_STRATEGY_DEFAULT, nil
+	case "largest-first":
+		return lnrpc.CoinSelectionStrategy_STRATEGY_LARGEST_FIRST, nil
+	case "smallest-first":
+		return lnrpc.CoinSelectionStrategy_STRATEGY_SMALLEST_FIRST, nil
+	case "random":
+		return lnrpc.CoinSelectionStrategy_STRATEGY_RANDOM, nil
+	case "branch-and-bound":
+		return lnrpc.CoinSelectionStrategy_STRATEGY_BRANCH_AND_BOUND, nil
+	default:
+		return 0, fmt.Errorf("unknown coin selection strategy %q",
+			ctx.String(coinSelectionStrategyFlag.Name))
+	}
+}
+
 var sendCoinsCommand = cli.Command{
 	Name:      "sendcoins",
 	Category:  "On-chain",
@@ -232,6 +276,7 @@ var sendCoinsCommand = cli.Command{
 				"sat/byte that should be used when crafting " +
 				"the transaction",
 		},
+		coinSelectionStrategyFlag,
 	},
 	Action: actionDecorator(sendCoins),
 }
@@ -281,6 +326,11 @@ func sendCoins(ctx *cli.Context) error {
 			"sweep all coins out of the wallet")
 	}
 
+	strategy, err := parseCoinSelectionStrategy(ctx)
+	if err != nil {
+		return err
+	}
+
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
@@ -291,6 +341,8 @@ func sendCoins(ctx *cli.Context) error {
 		TargetConf: int32(ctx.Int64("conf_target")),
 		SatPerByte: ctx.Int64("sat_per_byte"),
 		SendAll:    ctx.Bool("sweepall"),
+
+		CoinSelectionStrategy: strategy,
 	}
 	txid, err := client.SendCoins(ctxb, req)
 	if err != nil {
@@ -446,6 +498,7 @@ var sendManyCommand = cli.Command{
 			Usage: "(optional) a manual fee expressed in sat/byte that should be " +
 				"used when crafting the transaction",
 		},
+		coinSelectionStrategyFlag,
 	},
 	Action: actionDecorator(sendMany),
 }
@@ -463,14 +516,20 @@ func sendMany(ctx *cli.Context) error {
 			"set, but not both")
 	}
 
+	strategy, err := parseCoinSelectionStrategy(ctx)
+	if err != nil {
+		return err
+	}
+
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
 	txid, err := client.SendMany(ctxb, &lnrpc.SendManyRequest{
-		AddrToAmount: amountToAddr,
-		TargetConf:   int32(ctx.Int64("conf_target")),
-		SatPerByte:   ctx.Int64("sat_per_byte"),
+		AddrToAmount:          amountToAddr,
+		TargetConf:            int32(ctx.Int64("conf_target")),
+		SatPerByte:            ctx.Int64("sat_per_byte"),
+		CoinSelectionStrategy: strategy,
 	})
 	if err != nil {
 		return err
@@ -588,7 +647,11 @@ var openChannelCommand = cli.Command{
 	a channelPoint (txid:vout) of the funding output is returned.
 
 	One can manually set the fee to be used for the funding transaction via either
-	the --conf_target or --sat_per_byte arguments. This is optional.`,
+	the --conf_target or --sat_per_byte arguments. This is optional.
+
+	If --psbt is set, the channel is funded from an external wallet instead.
+	A PSBT funding template paying local-amt satoshis to the funding output is
+	printed, after which the signed funding transaction is read from stdin.`,
 	ArgsUsage: "node-key local-amt push-amt",
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -612,6 +675,20 @@ var openChannelCommand = cli.Command{
 				"channel and sending the remote party funds, " +
 				"but done all in one step",
 		},
+		cli.Int64Flag{
+			Name: "remote_amt",
+			Usage: "(optional) the number of satoshis the remote " +
+				"node is requested to commit to the channel, " +
+				"which requires it to support dual funding. " +
+				"Can't be combined with push_amt",
+		},
+		cli.BoolFlag{
+			Name: "psbt",
+			Usage: "(optional) fund the channel from an external " +
+				"wallet. A PSBT funding template is printed, " +
+				"after which the signed funding transaction " +
+				"is read from stdin",
+		},
 		cli.BoolFlag{
 			Name:  "block",
 			Usage: "block and wait until the channel is fully open",
@@ -656,6 +733,26 @@ var openChannelCommand = cli.Command{
 				"transaction must satisfy",
 			Value: 1,
 		},
+		coinSelectionStrategyFlag,
+		cli.Int64Flag{
+			Name: "dust_limit",
+			Usage: "(optional) the dust limit in satoshis of " +
+				"our commitment transactions, which can't be " +
+				"lower than the default of the chain",
+		},
+		cli.Int64Flag{
+			Name: "remote_reserve",
+			Usage: "(optional) the channel reserve in satoshis " +
+				"we require the channel counterparty to keep " +
+				"in the channel. If this is not set, it will " +
+				"be 1% of the channel size",
+		},
+		cli.BoolFlag{
+			Name: "zero_conf",
+			Usage: "(optional) request the channel to be usable " +
+				"before the funding transaction confirms, " +
+				"which the counterparty must accept",
+		},
 	},
 	Action: actionDecorator(openChannel),
 }
@@ -675,12 +772,24 @@ func openChannel(ctx *cli.Context) error {
 		return nil
 	}
 
+	strategy, err := parseCoinSelectionStrategy(ctx)
+	if err != nil {
+		return err
+	}
+
 	req := &lnrpc.OpenChannelRequest{
-		TargetConf:     int32(ctx.Int64("conf_target")),
-		SatPerByte:     ctx.Int64("sat_per_byte"),
-		MinHtlcMsat:    ctx.Int64("min_htlc_msat"),
-		RemoteCsvDelay: uint32(ctx.Uint64("remote_csv_delay")),
-		MinConfs:       int32(ctx.Uint64("min_confs")),
+		TargetConf:          int32(ctx.Int64("conf_target")),
+		SatPerByte:          ctx.Int64("sat_per_byte"),
+		MinHtlcMsat:         ctx.Int64("min_htlc_msat"),
+		RemoteCsvDelay:      uint32(ctx.Uint64("remote_csv_delay")),
+		MinConfs:            int32(ctx.Uint64("min_confs")),
+		RemoteFundingAmount: ctx.Int64("remote_amt"),
+		PsbtFunding:         ctx.Bool("psbt"),
+
+		CoinSelectionStrategy: strategy,
+		DustLimitSat:          ctx.Int64("dust_limit"),
+		RemoteChanReserveSat:  ctx.Int64("remote_reserve"),
+		ZeroConf:              ctx.Bool("zero_conf"),
 	}
 
 	switch {
@@ -763,6 +872,12 @@ func openChannel(ctx *cli.Context) error {
 		}
 
 		switch update := resp.Update.(type) {
+		case *lnrpc.OpenStatusUpdate_PsbtFund:
+			err := fundPsbt(ctxb, client, update.PsbtFund)
+			if err != nil {
+				return err
+			}
+
 		case *lnrpc.OpenStatusUpdate_ChanPending:
 			txid, err := chainhash.NewHash(update.ChanPending.Txid)
 			if err != nil {
@@ -816,47 +931,62 @@ func openChannel(ctx *cli.Context) error {
 	}
 }
 
-// TODO(roasbeef): also allow short relative channel ID.
+// fundPsbt prints the PSBT funding template of an externally funded channel,
+// then reads the signed funding transaction from stdin and hands it to lnd.
+func fundPsbt(ctxb context.Context, client lnrpc.LightningClient,
+	psbtFund *lnrpc.ReadyForPsbtFunding) error {
 
-var closeChannelCommand = cli.Command{
-	Name:     "closechannel",
-	Category: "Channels",
-	Usage:    "Close an existing channel.",
-	Description: `
-	Close an existing channel. The channel can be closed either cooperatively,
-	or unilaterally (--force).
+	printJSON(struct {
+		FundingAddress string `json:"funding_address"`
+		FundingAmount  int64  `json:"funding_amount"`
+		Psbt           string `json:"psbt"`
+	}{
+		FundingAddress: psbtFund.FundingAddress,
+		FundingAmount:  psbtFund.FundingAmount,
+		Psbt:           base64.StdEncoding.EncodeToString(psbtFund.Psbt),
+	})
 
-	A unilateral channel closure means that the latest commitment
-	transaction will be broadcast to the network. As a result, any settled
-	funds will be time locked for a few blocks before they can be spent.
+	fmt.Printf("Fund the PSBT from your wallet, then paste the hex " +
+		"encoded, signed funding transaction: ")
 
-	In the case of a cooperative closure, One can manually set the fee to
-	be used for the closing transaction via either the --conf_target or
-	--sat_per_byte arguments. This will be the starting value used during
-	fee negotiation. This is optional.
+	reader := bufio.NewReader(os.Stdin)
+	signedTxHex, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	signedTx, err := hex.DecodeString(strings.TrimSpace(signedTxHex))
+	if err != nil {
+		return fmt.Errorf("unable to decode funding tx: %v", err)
+	}
 
-	To view which funding_txids/output_indexes can be used for a channel close,
-	see the channel_point values within the listchannels command output.
-	The format for a channel_point is 'funding_txid:output_index'.`,
-	ArgsUsage: "funding_txid [output_index]",
+	_, err = client.FundingStateStep(ctxb, &lnrpc.FundingStateStepRequest{
+		PendingChanId: psbtFund.PendingChanId,
+		SignedTx:      signedTx,
+	})
+	return err
+}
+
+var batchOpenChannelCommand = cli.Command{
+	Name:     "batchopenchannel",
+	Category: "Channels",
+	Usage: "Open multiple channels to connected peers with a single " +
+		"funding transaction.",
+	ArgsUsage: "channels-json",
+	Description: `
+	Attempt to open a new channel to each of the given connected peers,
+	all of them funded by a single transaction of the wallet. The
+	transaction is only broadcast once every peer signed our commitment,
+	which saves on-chain fees compared to opening each channel on its own.
+	If the funding workflow with any of the peers fails, none of the
+	channels are opened.
+
+	The channels-json param decodes the channels to open in the following
+	format, where all fields but node_pubkey and local_amt are optional:
+
+	    '[{"node_pubkey": "<hex pubkey>", "local_amt": N, "push_amt": M,
+	       "private": false, "min_htlc_msat": H, "remote_csv_delay": D}]'
+	`,
 	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name:  "funding_txid",
-			Usage: "the txid of the channel's funding transaction",
-		},
-		cli.IntFlag{
-			Name: "output_index",
-			Usage: "the output index for the funding output of the funding " +
-				"transaction",
-		},
-		cli.BoolFlag{
-			Name:  "force",
-			Usage: "attempt an uncooperative closure",
-		},
-		cli.BoolFlag{
-			Name:  "block",
-			Usage: "block until the channel is closed",
-		},
 		cli.Int64Flag{
 			Name: "conf_target",
 			Usage: "(optional) the number of blocks that the " +
@@ -869,329 +999,211 @@ var closeChannelCommand = cli.Command{
 				"sat/byte that should be used when crafting " +
 				"the transaction",
 		},
+		cli.Uint64Flag{
+			Name: "min_confs",
+			Usage: "(optional) the minimum number of confirmations " +
+				"each one of your outputs used for the funding " +
+				"transaction must satisfy",
+			Value: 1,
+		},
+		coinSelectionStrategyFlag,
 	},
-	Action: actionDecorator(closeChannel),
+	Action: actionDecorator(batchOpenChannel),
 }
 
-func closeChannel(ctx *cli.Context) error {
+func batchOpenChannel(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	// Show command help if no arguments and flags were provided.
-	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
-		cli.ShowCommandHelp(ctx, "closechannel")
-		return nil
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "batchopenchannel")
 	}
 
-	channelPoint, err := parseChannelPoint(ctx)
+	var channels []struct {
+		NodePubkey     string `json:"node_pubkey"`
+		LocalAmt       int64  `json:"local_amt"`
+		PushAmt        int64  `json:"push_amt"`
+		Private        bool   `json:"private"`
+		MinHtlcMsat    int64  `json:"min_htlc_msat"`
+		RemoteCsvDelay uint32 `json:"remote_csv_delay"`
+	}
+	err := json.Unmarshal([]byte(ctx.Args().First()), &channels)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to decode channels: %v", err)
 	}
 
-	// TODO(roasbeef): implement time deadline within server
-	req := &lnrpc.CloseChannelRequest{
-		ChannelPoint: channelPoint,
-		Force:        ctx.Bool("force"),
-		TargetConf:   int32(ctx.Int64("conf_target")),
-		SatPerByte:   ctx.Int64("sat_per_byte"),
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_byte") {
+		return fmt.Errorf("either conf_target or sat_per_byte should be " +
+			"set, but not both")
 	}
 
-	// After parsing the request, we'll spin up a goroutine that will
-	// retrieve the closing transaction ID when attempting to close the
-	// channel. We do this to because `executeChannelClose` can block, so we
-	// would like to present the closing transaction ID to the user as soon
-	// as it is broadcasted.
-	var wg sync.WaitGroup
-	txidChan := make(chan string, 1)
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
-		printJSON(struct {
-			ClosingTxid string `json:"closing_txid"`
-		}{
-			ClosingTxid: <-txidChan,
-		})
-	}()
-
-	err = executeChannelClose(client, req, txidChan, ctx.Bool("block"))
+	strategy, err := parseCoinSelectionStrategy(ctx)
 	if err != nil {
 		return err
 	}
 
-	// In the case that the user did not provide the `block` flag, then we
-	// need to wait for the goroutine to be done to prevent it from being
-	// destroyed when exiting before printing the closing transaction ID.
-	wg.Wait()
-
-	return nil
-}
+	minConfs := int32(ctx.Uint64("min_confs"))
+	req := &lnrpc.BatchOpenChannelRequest{
+		TargetConf:            int32(ctx.Int64("conf_target")),
+		SatPerByte:            ctx.Int64("sat_per_byte"),
+		MinConfs:              minConfs,
+		SpendUnconfirmed:      minConfs == 0,
+		CoinSelectionStrategy: strategy,
+	}
+	for _, channel := range channels {
+		nodePubKey, err := hex.DecodeString(channel.NodePubkey)
+		if err != nil {
+			return fmt.Errorf("unable to decode node public key: %v",
+				err)
+		}
 
-// executeChannelClose attempts to close the channel from a request. The closing
-// transaction ID is sent through `txidChan` as soon as it is broadcasted to the
-// network. The block boolean is used to determine if we should block until the
-// closing transaction receives all of its required confirmations.
-func executeChannelClose(client lnrpc.LightningClient, req *lnrpc.CloseChannelRequest,
-	txidChan chan<- string, block bool) error {
+		req.Channels = append(req.Channels, &lnrpc.BatchOpenChannel{
+			NodePubkey:         nodePubKey,
+			LocalFundingAmount: channel.LocalAmt,
+			PushSat:            channel.PushAmt,
+			Private:            channel.Private,
+			MinHtlcMsat:        channel.MinHtlcMsat,
+			RemoteCsvDelay:     channel.RemoteCsvDelay,
+		})
+	}
 
-	stream, err := client.CloseChannel(context.Background(), req)
+	resp, err := client.BatchOpenChannel(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	for {
-		resp, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		} else if err != nil {
+	// We'll print the channel points rather than the raw pending updates,
+	// with the txids in their usual, reversed, byte order.
+	chanPoints := make([]string, len(resp.PendingChannels))
+	for i, pending := range resp.PendingChannels {
+		txid, err := chainhash.NewHash(pending.Txid)
+		if err != nil {
 			return err
 		}
 
-		switch update := resp.Update.(type) {
-		case *lnrpc.CloseStatusUpdate_ClosePending:
-			closingHash := update.ClosePending.Txid
-			txid, err := chainhash.NewHash(closingHash)
-			if err != nil {
-				return err
-			}
+		chanPoints[i] = fmt.Sprintf("%v:%v", txid, pending.OutputIndex)
+	}
 
-			txidChan <- txid.String()
+	printJSON(struct {
+		ChannelPoints []string `json:"channel_points"`
+	}{
+		ChannelPoints: chanPoints,
+	})
 
-			if !block {
-				return nil
-			}
-		case *lnrpc.CloseStatusUpdate_ChanClose:
-			return nil
-		}
-	}
+	return nil
 }
 
-var closeAllChannelsCommand = cli.Command{
-	Name:     "closeallchannels",
+var spliceInCommand = cli.Command{
+	Name:     "splicein",
 	Category: "Channels",
-	Usage:    "Close all existing channels.",
+	Usage:    "Add funds of the wallet to an active channel.",
 	Description: `
-	Close all existing channels.
-
-	Channels will be closed either cooperatively or unilaterally, depending
-	on whether the channel is active or not. If the channel is inactive, any
-	settled funds within it will be time locked for a few blocks before they
-	can be spent.
-
-	One can request to close inactive channels only by using the
-	--inactive_only flag.
+	Add funds of the wallet to the capacity of an active channel, without
+	closing it. The channel is paused until the splice transaction
+	confirms, after which it resumes under the returned channel point.
 
-	By default, one is prompted for confirmation every time an inactive
-	channel is requested to be closed. To avoid this, one can set the
-	--force flag, which will only prompt for confirmation once for all
-	inactive channels and proceed to close them.`,
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index] amt",
 	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name:  "inactive_only",
-			Usage: "close inactive channels only",
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
 		},
-		cli.BoolFlag{
-			Name: "force",
-			Usage: "ask for confirmation once before attempting " +
-				"to close existing channels",
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the number of satoshis to add to the channel",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
 		},
+		cli.Int64Flag{
+			Name: "sat_per_byte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/byte that should be used when crafting " +
+				"the transaction",
+		},
+		cli.Uint64Flag{
+			Name: "min_confs",
+			Usage: "(optional) the minimum number of confirmations " +
+				"each one of your outputs used for the splice " +
+				"transaction must satisfy",
+			Value: 1,
+		},
+		coinSelectionStrategyFlag,
 	},
-	Action: actionDecorator(closeAllChannels),
+	Action: actionDecorator(spliceIn),
 }
 
-func closeAllChannels(ctx *cli.Context) error {
+func spliceIn(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	listReq := &lnrpc.ListChannelsRequest{}
-	openChannels, err := client.ListChannels(context.Background(), listReq)
-	if err != nil {
-		return fmt.Errorf("unable to fetch open channels: %v", err)
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "splicein")
+		return nil
 	}
 
-	if len(openChannels.Channels) == 0 {
-		return errors.New("no open channels to close")
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_byte") {
+		return fmt.Errorf("either conf_target or sat_per_byte should be " +
+			"set, but not both")
 	}
 
-	var channelsToClose []*lnrpc.Channel
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
 
-	switch {
-	case ctx.Bool("force") && ctx.Bool("inactive_only"):
-		msg := "Unilaterally close all inactive channels? The funds " +
-			"within these channels will be locked for some blocks " +
-			"(CSV delay) before they can be spent. (yes/no): "
-
-		confirmed := promptForConfirmation(msg)
-
-		// We can safely exit if the user did not confirm.
-		if !confirmed {
-			return nil
-		}
-
-		// Go through the list of open channels and only add inactive
-		// channels to the closing list.
-		for _, channel := range openChannels.Channels {
-			if !channel.GetActive() {
-				channelsToClose = append(
-					channelsToClose, channel,
-				)
-			}
-		}
-	case ctx.Bool("force"):
-		msg := "Close all active and inactive channels? Inactive " +
-			"channels will be closed unilaterally, so funds " +
-			"within them will be locked for a few blocks (CSV " +
-			"delay) before they can be spent. (yes/no): "
-
-		confirmed := promptForConfirmation(msg)
-
-		// We can safely exit if the user did not confirm.
-		if !confirmed {
-			return nil
-		}
-
-		channelsToClose = openChannels.Channels
-	default:
-		// Go through the list of open channels and determine which
-		// should be added to the closing list.
-		for _, channel := range openChannels.Channels {
-			// If the channel is inactive, we'll attempt to
-			// unilaterally close the channel, so we should prompt
-			// the user for confirmation beforehand.
-			if !channel.GetActive() {
-				msg := fmt.Sprintf("Unilaterally close channel "+
-					"with node %s and channel point %s? "+
-					"The closing transaction will need %d "+
-					"confirmations before the funds can be "+
-					"spent. (yes/no): ", channel.RemotePubkey,
-					channel.ChannelPoint, channel.CsvDelay)
-
-				confirmed := promptForConfirmation(msg)
-
-				if confirmed {
-					channelsToClose = append(
-						channelsToClose, channel,
-					)
-				}
-			} else if !ctx.Bool("inactive_only") {
-				// Otherwise, we'll only add active channels if
-				// we were not requested to close inactive
-				// channels only.
-				channelsToClose = append(
-					channelsToClose, channel,
-				)
-			}
-		}
+	if !ctx.IsSet("amt") {
+		return fmt.Errorf("amt argument missing")
 	}
 
-	// result defines the result of closing a channel. The closing
-	// transaction ID is populated if a channel is successfully closed.
-	// Otherwise, the error that prevented closing the channel is populated.
-	type result struct {
-		RemotePubKey string `json:"remote_pub_key"`
-		ChannelPoint string `json:"channel_point"`
-		ClosingTxid  string `json:"closing_txid"`
-		FailErr      string `json:"error"`
+	strategy, err := parseCoinSelectionStrategy(ctx)
+	if err != nil {
+		return err
 	}
 
-	// Launch each channel closure in a goroutine in order to execute them
-	// in parallel. Once they're all executed, we will print the results as
-	// they come.
-	resultChan := make(chan result, len(channelsToClose))
-	for _, channel := range channelsToClose {
-		go func(channel *lnrpc.Channel) {
-			res := result{}
-			res.RemotePubKey = channel.RemotePubkey
-			res.ChannelPoint = channel.ChannelPoint
-			defer func() {
-				resultChan <- res
-			}()
-
-			// Parse the channel point in order to create the close
-			// channel request.
-			s := strings.Split(res.ChannelPoint, ":")
-			if len(s) != 2 {
-				res.FailErr = "expected channel point with " +
-					"format txid:index"
-				return
-			}
-			index, err := strconv.ParseUint(s[1], 10, 32)
-			if err != nil {
-				res.FailErr = fmt.Sprintf("unable to parse "+
-					"channel point output index: %v", err)
-				return
-			}
-
-			req := &lnrpc.CloseChannelRequest{
-				ChannelPoint: &lnrpc.ChannelPoint{
-					FundingTxid: &lnrpc.ChannelPoint_FundingTxidStr{
-						FundingTxidStr: s[0],
-					},
-					OutputIndex: uint32(index),
-				},
-				Force: !channel.GetActive(),
-			}
-
-			txidChan := make(chan string, 1)
-			err = executeChannelClose(client, req, txidChan, false)
-			if err != nil {
-				res.FailErr = fmt.Sprintf("unable to close "+
-					"channel: %v", err)
-				return
-			}
-
-			res.ClosingTxid = <-txidChan
-		}(channel)
+	minConfs := int32(ctx.Uint64("min_confs"))
+	req := &lnrpc.SpliceInRequest{
+		ChannelPoint:          channelPoint,
+		Amount:                ctx.Int64("amt"),
+		TargetConf:            int32(ctx.Int64("conf_target")),
+		SatPerByte:            ctx.Int64("sat_per_byte"),
+		MinConfs:              minConfs,
+		SpendUnconfirmed:      minConfs == 0,
+		CoinSelectionStrategy: strategy,
 	}
 
-	for range channelsToClose {
-		res := <-resultChan
-		printJSON(res)
+	resp, err := client.SpliceIn(ctxb, req)
+	if err != nil {
+		return err
 	}
 
-	return nil
-}
-
-// promptForConfirmation continuously prompts the user for the message until
-// receiving a response of "yes" or "no" and returns their answer as a bool.
-func promptForConfirmation(msg string) bool {
-	reader := bufio.NewReader(os.Stdin)
-
-	for {
-		fmt.Print(msg)
-
-		answer, err := reader.ReadString('\n')
-		if err != nil {
-			return false
-		}
-
-		answer = strings.ToLower(strings.TrimSpace(answer))
-
-		switch {
-		case answer == "yes":
-			return true
-		case answer == "no":
-			return false
-		default:
-			continue
-		}
-	}
+	return printSplicedChanPoint(resp)
 }
 
-var abandonChannelCommand = cli.Command{
-	Name:     "abandonchannel",
+var spliceOutCommand = cli.Command{
+	Name:     "spliceout",
 	Category: "Channels",
-	Usage:    "Abandons an existing channel.",
+	Usage:    "Pay funds out of an active channel to an on-chain address.",
 	Description: `
-	Removes all channel state from the database except for a close
-	summary. This method can be used to get rid of permanently unusable
-	channels due to bugs fixed in newer versions of lnd.
+	Pay funds out of an active channel, without closing it. The amount,
+	along with the fee of the splice transaction, is deducted from our
+	balance in the channel. If no address is given, the funds are paid to
+	a new address of the wallet. The channel is paused until the splice
+	transaction confirms, after which it resumes under the returned channel
+	point.
 
-	Only available when lnd is built in debug mode.
-
-	To view which funding_txids/output_indexes can be used for this command,
-	see the channel_point values within the listchannels command output.
 	The format for a channel_point is 'funding_txid:output_index'.`,
 	ArgsUsage: "funding_txid [output_index]",
 	Flags: []cli.Flag{
@@ -1204,1906 +1216,4121 @@ var abandonChannelCommand = cli.Command{
 			Usage: "the output index for the funding output of the funding " +
 				"transaction",
 		},
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "the number of satoshis to pay out of the " +
+				"channel",
+		},
+		cli.StringFlag{
+			Name: "addr",
+			Usage: "(optional) the address to pay the funds to, " +
+				"a new address of the wallet is used if empty",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Int64Flag{
+			Name: "sat_per_byte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/byte that should be used when crafting " +
+				"the transaction",
+		},
 	},
-	Action: actionDecorator(abandonChannel),
+	Action: actionDecorator(spliceOut),
 }
 
-func abandonChannel(ctx *cli.Context) error {
+func spliceOut(ctx *cli.Context) error {
 	ctxb := context.Background()
-
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
 	// Show command help if no arguments and flags were provided.
 	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
-		cli.ShowCommandHelp(ctx, "abandonchannel")
+		cli.ShowCommandHelp(ctx, "spliceout")
 		return nil
 	}
 
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_byte") {
+		return fmt.Errorf("either conf_target or sat_per_byte should be " +
+			"set, but not both")
+	}
+
 	channelPoint, err := parseChannelPoint(ctx)
 	if err != nil {
 		return err
 	}
 
-	req := &lnrpc.AbandonChannelRequest{
+	if !ctx.IsSet("amt") {
+		return fmt.Errorf("amt argument missing")
+	}
+
+	req := &lnrpc.SpliceOutRequest{
 		ChannelPoint: channelPoint,
+		Amount:       ctx.Int64("amt"),
+		Address:      ctx.String("addr"),
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		SatPerByte:   ctx.Int64("sat_per_byte"),
 	}
 
-	resp, err := client.AbandonChannel(ctxb, req)
+	resp, err := client.SpliceOut(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(resp)
-	return nil
+	return printSplicedChanPoint(resp)
 }
 
-// parseChannelPoint parses a funding txid and output index from the command
-// line. Both named options as well as unnamed parameters are supported.
-func parseChannelPoint(ctx *cli.Context) (*lnrpc.ChannelPoint, error) {
-	channelPoint := &lnrpc.ChannelPoint{}
-
-	args := ctx.Args()
-
-	switch {
-	case ctx.IsSet("funding_txid"):
-		channelPoint.FundingTxid = &lnrpc.ChannelPoint_FundingTxidStr{
-			FundingTxidStr: ctx.String("funding_txid"),
-		}
-	case args.Present():
-		channelPoint.FundingTxid = &lnrpc.ChannelPoint_FundingTxidStr{
-			FundingTxidStr: args.First(),
-		}
-		args = args.Tail()
-	default:
-		return nil, fmt.Errorf("funding txid argument missing")
-	}
-
-	switch {
-	case ctx.IsSet("output_index"):
-		channelPoint.OutputIndex = uint32(ctx.Int("output_index"))
-	case args.Present():
-		index, err := strconv.ParseUint(args.First(), 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("unable to decode output index: %v", err)
-		}
-		channelPoint.OutputIndex = uint32(index)
-	default:
-		channelPoint.OutputIndex = 0
+// printSplicedChanPoint prints the new channel point of a spliced channel, with
+// its txid in the usual, reversed, byte order.
+func printSplicedChanPoint(chanPoint *lnrpc.ChannelPoint) error {
+	txid, err := chainhash.NewHash(chanPoint.GetFundingTxidBytes())
+	if err != nil {
+		return err
 	}
 
-	return channelPoint, nil
-}
+	printJSON(struct {
+		ChannelPoint string `json:"channel_point"`
+	}{
+		ChannelPoint: fmt.Sprintf("%v:%v", txid, chanPoint.OutputIndex),
+	})
 
-var listPeersCommand = cli.Command{
-	Name:     "listpeers",
-	Category: "Peers",
-	Usage:    "List all active, currently connected peers.",
-	Action:   actionDecorator(listPeers),
+	return nil
 }
 
-func listPeers(ctx *cli.Context) error {
-	ctxb := context.Background()
-	client, cleanUp := getClient(ctx)
-	defer cleanUp()
+// TODO(roasbeef): also allow short relative channel ID.
 
-	req := &lnrpc.ListPeersRequest{}
-	resp, err := client.ListPeers(ctxb, req)
-	if err != nil {
-		return err
-	}
-
-	printRespJSON(resp)
-	return nil
-}
-
-var createCommand = cli.Command{
-	Name:     "create",
-	Category: "Startup",
-	Usage:    "Initialize a wallet when starting lnd for the first time.",
+var closeChannelCommand = cli.Command{
+	Name:     "closechannel",
+	Category: "Channels",
+	Usage:    "Close an existing channel.",
 	Description: `
-	The create command is used to initialize an lnd wallet from scratch for
-	the very first time. This is interactive command with one required
-	argument (the password), and one optional argument (the mnemonic
-	passphrase).
-
-	The first argument (the password) is required and MUST be greater than
-	8 characters. This will be used to encrypt the wallet within lnd. This
-	MUST be remembered as it will be required to fully start up the daemon.
+	Close an existing channel. The channel can be closed either cooperatively,
+	or unilaterally (--force).
 
-	The second argument is an optional 24-word mnemonic derived from BIP
-	39. If provided, then the internal wallet will use the seed derived
-	from this mnemonic to generate all keys.
+	A unilateral channel closure means that the latest commitment
+	transaction will be broadcast to the network. As a result, any settled
+	funds will be time locked for a few blocks before they can be spent.
 
-	This command returns a 24-word seed in the scenario that NO mnemonic
-	was provided by the user. This should be written down as it can be used
-	to potentially recover all on-chain funds, and most off-chain funds as
-	well.
+	In the case of a cooperative closure, One can manually set the fee to
+	be used for the closing transaction via either the --conf_target or
+	--sat_per_byte arguments. This will be the starting value used during
+	fee negotiation. This is optional. The range of fees that are acceptable
+	during negotiation can be bounded via the --min_sat_per_byte and
+	--max_sat_per_byte arguments, while --delivery_addr sets the address
+	the settled funds are sent to.
 
-	Finally, it's also possible to use this command and a set of static
-	channel backups to trigger a recover attempt for the provided Static
-	Channel Backups. Only one of the three parameters will be accepted. See
-	the restorechanbackup command for further details w.r.t the format
-	accepted.
-	`,
+	To view which funding_txids/output_indexes can be used for a channel close,
+	see the channel_point values within the listchannels command output.
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name: "single_backup",
-			Usage: "a hex encoded single channel backup obtained " +
-				"from exportchanbackup",
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
 		},
-		cli.StringFlag{
-			Name: "multi_backup",
-			Usage: "a hex encoded multi-channel backup obtained " +
-				"from exportchanbackup",
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "attempt an uncooperative closure",
+		},
+		cli.BoolFlag{
+			Name:  "block",
+			Usage: "block until the channel is closed",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Int64Flag{
+			Name: "sat_per_byte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/byte that should be used when crafting " +
+				"the transaction",
+		},
+		cli.Int64Flag{
+			Name: "min_sat_per_byte",
+			Usage: "(optional) the minimum fee in sat/byte that " +
+				"is acceptable for the closing transaction",
+		},
+		cli.Int64Flag{
+			Name: "max_sat_per_byte",
+			Usage: "(optional) the maximum fee in sat/byte that " +
+				"is acceptable for the closing transaction",
 		},
 		cli.StringFlag{
-			Name:  "multi_file",
-			Usage: "the path to a multi-channel back up file",
+			Name: "delivery_addr",
+			Usage: "(optional) the address to send the settled " +
+				"funds to in the case of a cooperative close",
 		},
 	},
-	Action: actionDecorator(create),
+	Action: actionDecorator(closeChannel),
 }
 
-// monowidthColumns takes a set of words, and the number of desired columns,
-// and returns a new set of words that have had white space appended to the
-// word in order to create a mono-width column.
-func monowidthColumns(words []string, ncols int) []string {
-	// Determine max size of words in each column.
-	colWidths := make([]int, ncols)
-	for i, word := range words {
-		col := i % ncols
-		curWidth := colWidths[col]
-		if len(word) > curWidth {
-			colWidths[col] = len(word)
-		}
+func closeChannel(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "closechannel")
+		return nil
 	}
 
-	// Append whitespace to each word to make columns mono-width.
-	finalWords := make([]string, len(words))
-	for i, word := range words {
-		col := i % ncols
-		width := colWidths[col]
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
 
-		diff := width - len(word)
-		finalWords[i] = word + strings.Repeat(" ", diff)
+	// TODO(roasbeef): implement time deadline within server
+	req := &lnrpc.CloseChannelRequest{
+		ChannelPoint:    channelPoint,
+		Force:           ctx.Bool("force"),
+		TargetConf:      int32(ctx.Int64("conf_target")),
+		SatPerByte:      ctx.Int64("sat_per_byte"),
+		DeliveryAddress: ctx.String("delivery_addr"),
+		MinSatPerByte:   ctx.Int64("min_sat_per_byte"),
+		MaxSatPerByte:   ctx.Int64("max_sat_per_byte"),
 	}
 
-	return finalWords
-}
+	// After parsing the request, we'll spin up a goroutine that will
+	// retrieve the closing transaction ID when attempting to close the
+	// channel. We do this to because `executeChannelClose` can block, so we
+	// would like to present the closing transaction ID to the user as soon
+	// as it is broadcasted.
+	var wg sync.WaitGroup
+	txidChan := make(chan string, 1)
 
-func create(ctx *cli.Context) error {
-	ctxb := context.Background()
-	client, cleanUp := getWalletUnlockerClient(ctx)
-	defer cleanUp()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
 
-	// First, we'll prompt the user for their passphrase twice to ensure
-	// both attempts match up properly.
-	fmt.Printf("Input wallet password: ")
-	pw1, err := terminal.ReadPassword(int(syscall.Stdin))
-	if err != nil {
-		return err
-	}
-	fmt.Println()
+		printJSON(struct {
+			ClosingTxid string `json:"closing_txid"`
+		}{
+			ClosingTxid: <-txidChan,
+		})
+	}()
 
-	fmt.Printf("Confirm wallet password: ")
-	pw2, err := terminal.ReadPassword(int(syscall.Stdin))
+	err = executeChannelClose(client, req, txidChan, ctx.Bool("block"))
 	if err != nil {
 		return err
 	}
-	fmt.Println()
 
-	// If the passwords don't match, then we'll return an error.
-	if !bytes.Equal(pw1, pw2) {
-		return fmt.Errorf("passwords don't match")
-	}
+	// In the case that the user did not provide the `block` flag, then we
+	// need to wait for the goroutine to be done to prevent it from being
+	// destroyed when exiting before printing the closing transaction ID.
+	wg.Wait()
 
-	// If the password length is less than 8 characters, then we'll
-	// return an error.
-	pwErr := walletunlocker.ValidatePassword(pw1)
-	if pwErr != nil {
-		return pwErr
-	}
+	return nil
+}
 
-	// Next, we'll see if the user has 24-word mnemonic they want to use to
-	// derive a seed within the wallet.
-	var (
-		hasMnemonic bool
-	)
+// executeChannelClose attempts to close the channel from a request. The closing
+// transaction ID is sent through `txidChan` as soon as it is broadcasted to the
+// network. The block boolean is used to determine if we should block until the
+// closing transaction receives all of its required confirmations.
+func executeChannelClose(client lnrpc.LightningClient, req *lnrpc.CloseChannelRequest,
+	txidChan chan<- string, block bool) error {
 
-mnemonicCheck:
-	for {
-		fmt.Println()
-		fmt.Printf("Do you have an existing cipher seed " +
-			"mnemonic you want to use? (Enter y/n): ")
+	stream, err := client.CloseChannel(context.Background(), req)
+	if err != nil {
+		return err
+	}
 
-		reader := bufio.NewReader(os.Stdin)
-		answer, err := reader.ReadString('\n')
-		if err != nil {
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
 			return err
 		}
 
-		fmt.Println()
+		switch update := resp.Update.(type) {
+		case *lnrpc.CloseStatusUpdate_ClosePending:
+			closingHash := update.ClosePending.Txid
+			txid, err := chainhash.NewHash(closingHash)
+			if err != nil {
+				return err
+			}
 
-		answer = strings.TrimSpace(answer)
-		answer = strings.ToLower(answer)
+			txidChan <- txid.String()
 
-		switch answer {
-		case "y":
-			hasMnemonic = true
-			break mnemonicCheck
-		case "n":
-			hasMnemonic = false
-			break mnemonicCheck
+			if !block {
+				return nil
+			}
+		case *lnrpc.CloseStatusUpdate_ChanClose:
+			return nil
 		}
 	}
+}
 
-	// If the user *does* have an existing seed they want to use, then
-	// we'll read that in directly from the terminal.
-	var (
-		cipherSeedMnemonic []string
-		aezeedPass         []byte
-		recoveryWindow     int32
-	)
-	if hasMnemonic {
-		// We'll now prompt the user to enter in their 24-word
-		// mnemonic.
-		fmt.Printf("Input your 24-word mnemonic separated by spaces: ")
-		reader := bufio.NewReader(os.Stdin)
-		mnemonic, err := reader.ReadString('\n')
-		if err != nil {
-			return err
-		}
+var closeAllChannelsCommand = cli.Command{
+	Name:     "closeallchannels",
+	Category: "Channels",
+	Usage:    "Close all existing channels.",
+	Description: `
+	Close all existing channels.
 
-		// We'll trim off extra spaces, and ensure the mnemonic is all
-		// lower case, then populate our request.
-		mnemonic = strings.TrimSpace(mnemonic)
-		mnemonic = strings.ToLower(mnemonic)
+	Channels will be closed either cooperatively or unilaterally, depending
+	on whether the channel is active or not. If the channel is inactive, any
+	settled funds within it will be time locked for a few blocks before they
+	can be spent.
 
-		cipherSeedMnemonic = strings.Split(mnemonic, " ")
+	One can request to close inactive channels only by using the
+	--inactive_only flag.
 
-		fmt.Println()
+	By default, one is prompted for confirmation every time an inactive
+	channel is requested to be closed. To avoid this, one can set the
+	--force flag, which will only prompt for confirmation once for all
+	inactive channels and proceed to close them.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "inactive_only",
+			Usage: "close inactive channels only",
+		},
+		cli.BoolFlag{
+			Name: "force",
+			Usage: "ask for confirmation once before attempting " +
+				"to close existing channels",
+		},
+	},
+	Action: actionDecorator(closeAllChannels),
+}
 
-		if len(cipherSeedMnemonic) != 24 {
-			return fmt.Errorf("wrong cipher seed mnemonic "+
-				"length: got %v words, expecting %v words",
-				len(cipherSeedMnemonic), 24)
+func closeAllChannels(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	listReq := &lnrpc.ListChannelsRequest{}
+	openChannels, err := client.ListChannels(context.Background(), listReq)
+	if err != nil {
+		return fmt.Errorf("unable to fetch open channels: %v", err)
+	}
+
+	if len(openChannels.Channels) == 0 {
+		return errors.New("no open channels to close")
+	}
+
+	var channelsToClose []*lnrpc.Channel
+
+	switch {
+	case ctx.Bool("force") && ctx.Bool("inactive_only"):
+		msg := "Unilaterally close all inactive channels? The funds " +
+			"within these channels will be locked for some blocks " +
+			"(CSV delay) before they can be spent. (yes/no): "
+
+		confirmed := promptForConfirmation(msg)
+
+		// We can safely exit if the user did not confirm.
+		if !confirmed {
+			return nil
 		}
 
-		// Additionally, the user may have a passphrase, that will also
-		// need to be provided so the daemon can properly decipher the
-		// cipher seed.
-		fmt.Printf("Input your cipher seed passphrase (press enter if " +
-			"your seed doesn't have a passphrase): ")
-		passphrase, err := terminal.ReadPassword(int(syscall.Stdin))
-		if err != nil {
-			return err
+		// Go through the list of open channels and only add inactive
+		// channels to the closing list.
+		for _, channel := range openChannels.Channels {
+			if !channel.GetActive() {
+				channelsToClose = append(
+					channelsToClose, channel,
+				)
+			}
 		}
+	case ctx.Bool("force"):
+		msg := "Close all active and inactive channels? Inactive " +
+			"channels will be closed unilaterally, so funds " +
+			"within them will be locked for a few blocks (CSV " +
+			"delay) before they can be spent. (yes/no): "
 
-		aezeedPass = []byte(passphrase)
+		confirmed := promptForConfirmation(msg)
 
-		for {
-			fmt.Println()
-			fmt.Printf("Input an optional address look-ahead "+
-				"used to scan for used keys (default %d): ",
-				defaultRecoveryWindow)
+		// We can safely exit if the user did not confirm.
+		if !confirmed {
+			return nil
+		}
 
-			reader := bufio.NewReader(os.Stdin)
-			answer, err := reader.ReadString('\n')
+		channelsToClose = openChannels.Channels
+	default:
+		// Go through the list of open channels and determine which
+		// should be added to the closing list.
+		for _, channel := range openChannels.Channels {
+			// If the channel is inactive, we'll attempt to
+			// unilaterally close the channel, so we should prompt
+			// the user for confirmation beforehand.
+			if !channel.GetActive() {
+				msg := fmt.Sprintf("Unilaterally close channel "+
+					"with node %s and channel point %s? "+
+					"The closing transaction will need %d "+
+					"confirmations before the funds can be "+
+					"spent. (yes/no): ", channel.RemotePubkey,
+					channel.ChannelPoint, channel.CsvDelay)
+
+				confirmed := promptForConfirmation(msg)
+
+				if confirmed {
+					channelsToClose = append(
+						channelsToClose, channel,
+					)
+				}
+			} else if !ctx.Bool("inactive_only") {
+				// Otherwise, we'll only add active channels if
+				// we were not requested to close inactive
+				// channels only.
+				channelsToClose = append(
+					channelsToClose, channel,
+				)
+			}
+		}
+	}
+
+	// result defines the result of closing a channel. The closing
+	// transaction ID is populated if a channel is successfully closed.
+	// Otherwise, the error that prevented closing the channel is populated.
+	type result struct {
+		RemotePubKey string `json:"remote_pub_key"`
+		ChannelPoint string `json:"channel_point"`
+		ClosingTxid  string `json:"closing_txid"`
+		FailErr      string `json:"error"`
+	}
+
+	// Launch each channel closure in a goroutine in order to execute them
+	// in parallel. Once they're all executed, we will print the results as
+	// they come.
+	resultChan := make(chan result, len(channelsToClose))
+	for _, channel := range channelsToClose {
+		go func(channel *lnrpc.Channel) {
+			res := result{}
+			res.RemotePubKey = channel.RemotePubkey
+			res.ChannelPoint = channel.ChannelPoint
+			defer func() {
+				resultChan <- res
+			}()
+
+			// Parse the channel point in order to create the close
+			// channel request.
+			s := strings.Split(res.ChannelPoint, ":")
+			if len(s) != 2 {
+				res.FailErr = "expected channel point with " +
+					"format txid:index"
+				return
+			}
+			index, err := strconv.ParseUint(s[1], 10, 32)
 			if err != nil {
-				return err
+				res.FailErr = fmt.Sprintf("unable to parse "+
+					"channel point output index: %v", err)
+				return
 			}
 
-			fmt.Println()
+			req := &lnrpc.CloseChannelRequest{
+				ChannelPoint: &lnrpc.ChannelPoint{
+					FundingTxid: &lnrpc.ChannelPoint_FundingTxidStr{
+						FundingTxidStr: s[0],
+					},
+					OutputIndex: uint32(index),
+				},
+				Force: !channel.GetActive(),
+			}
 
-			answer = strings.TrimSpace(answer)
+			txidChan := make(chan string, 1)
+			err = executeChannelClose(client, req, txidChan, false)
+			if err != nil {
+				res.FailErr = fmt.Sprintf("unable to close "+
+					"channel: %v", err)
+				return
+			}
+
+			res.ClosingTxid = <-txidChan
+		}(channel)
+	}
+
+	for range channelsToClose {
+		res := <-resultChan
+		printJSON(res)
+	}
+
+	return nil
+}
+
+// promptForConfirmation continuously prompts the user for the message until
+// receiving a response of "yes" or "no" and returns their answer as a bool.
+func promptForConfirmation(msg string) bool {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print(msg)
+
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			return false
+		}
+
+		answer = strings.ToLower(strings.TrimSpace(answer))
+
+		switch {
+		case answer == "yes":
+			return true
+		case answer == "no":
+			return false
+		default:
+			continue
+		}
+	}
+}
+
+var abandonChannelCommand = cli.Command{
+	Name:     "abandonchannel",
+	Category: "Channels",
+	Usage:    "Abandons an existing channel.",
+	Description: `
+	Removes all channel state from the database except for a close
+	summary. This method can be used to get rid of permanently unusable
+	channels due to bugs fixed in newer versions of lnd.
+
+	Only available when lnd is built in debug mode.
+
+	To view which funding_txids/output_indexes can be used for this command,
+	see the channel_point values within the listchannels command output.
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+	},
+	Action: actionDecorator(abandonChannel),
+}
+
+func abandonChannel(ctx *cli.Context) error {
+	ctxb := context.Background()
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "abandonchannel")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.AbandonChannelRequest{
+		ChannelPoint: channelPoint,
+	}
+
+	resp, err := client.AbandonChannel(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// parseChannelPoint parses a funding txid and output index from the command
+// line. Both named options as well as unnamed parameters are supported.
+func parseChannelPoint(ctx *cli.Context) (*lnrpc.ChannelPoint, error) {
+	channelPoint := &lnrpc.ChannelPoint{}
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("funding_txid"):
+		channelPoint.FundingTxid = &lnrpc.ChannelPoint_FundingTxidStr{
+			FundingTxidStr: ctx.String("funding_txid"),
+		}
+	case args.Present():
+		channelPoint.FundingTxid = &lnrpc.ChannelPoint_FundingTxidStr{
+			FundingTxidStr: args.First(),
+		}
+		args = args.Tail()
+	default:
+		return nil, fmt.Errorf("funding txid argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("output_index"):
+		channelPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case args.Present():
+		index, err := strconv.ParseUint(args.First(), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode output index: %v", err)
+		}
+		channelPoint.OutputIndex = uint32(index)
+	default:
+		channelPoint.OutputIndex = 0
+	}
+
+	return channelPoint, nil
+}
+
+var listPeersCommand = cli.Command{
+	Name:     "listpeers",
+	Category: "Peers",
+	Usage:    "List all active, currently connected peers.",
+	Action:   actionDecorator(listPeers),
+}
+
+func listPeers(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListPeersRequest{}
+	resp, err := client.ListPeers(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var createCommand = cli.Command{
+	Name:     "create",
+	Category: "Startup",
+	Usage:    "Initialize a wallet when starting lnd for the first time.",
+	Description: `
+	The create command is used to initialize an lnd wallet from scratch for
+	the very first time. This is interactive command with one required
+	argument (the password), and one optional argument (the mnemonic
+	passphrase).
+
+	The first argument (the password) is required and MUST be greater than
+	8 characters. This will be used to encrypt the wallet within lnd. This
+	MUST be remembered as it will be required to fully start up the daemon.
+
+	The second argument is an optional 24-word mnemonic derived from BIP
+	39. If provided, then the internal wallet will use the seed derived
+	from this mnemonic to generate all keys.
+
+	This command returns a 24-word seed in the scenario that NO mnemonic
+	was provided by the user. This should be written down as it can be used
+	to potentially recover all on-chain funds, and most off-chain funds as
+	well.
+
+	Finally, it's also possible to use this command and a set of static
+	channel backups to trigger a recover attempt for the provided Static
+	Channel Backups. Only one of the three parameters will be accepted. See
+	the restorechanbackup command for further details w.r.t the format
+	accepted.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "single_backup",
+			Usage: "a hex encoded single channel backup obtained " +
+				"from exportchanbackup",
+		},
+		cli.StringFlag{
+			Name: "multi_backup",
+			Usage: "a hex encoded multi-channel backup obtained " +
+				"from exportchanbackup",
+		},
+		cli.StringFlag{
+			Name:  "multi_file",
+			Usage: "the path to a multi-channel back up file",
+		},
+	},
+	Action: actionDecorator(create),
+}
+
+// monowidthColumns takes a set of words, and the number of desired columns,
+// and returns a new set of words that have had white space appended to the
+// word in order to create a mono-width column.
+func monowidthColumns(words []string, ncols int) []string {
+	// Determine max size of words in each column.
+	colWidths := make([]int, ncols)
+	for i, word := range words {
+		col := i % ncols
+		curWidth := colWidths[col]
+		if len(word) > curWidth {
+			colWidths[col] = len(word)
+		}
+	}
+
+	// Append whitespace to each word to make columns mono-width.
+	finalWords := make([]string, len(words))
+	for i, word := range words {
+		col := i % ncols
+		width := colWidths[col]
+
+		diff := width - len(word)
+		finalWords[i] = word + strings.Repeat(" ", diff)
+	}
+
+	return finalWords
+}
+
+func create(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getWalletUnlockerClient(ctx)
+	defer cleanUp()
+
+	// First, we'll prompt the user for their passphrase twice to ensure
+	// both attempts match up properly.
+	fmt.Printf("Input wallet password: ")
+	pw1, err := terminal.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	fmt.Printf("Confirm wallet password: ")
+	pw2, err := terminal.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	// If the passwords don't match, then we'll return an error.
+	if !bytes.Equal(pw1, pw2) {
+		return fmt.Errorf("passwords don't match")
+	}
+
+	// If the password length is less than 8 characters, then we'll
+	// return an error.
+	pwErr := walletunlocker.ValidatePassword(pw1)
+	if pwErr != nil {
+		return pwErr
+	}
+
+	// Next, we'll see if the user has 24-word mnemonic they want to use to
+	// derive a seed within the wallet.
+	var (
+		hasMnemonic bool
+	)
+
+mnemonicCheck:
+	for {
+		fmt.Println()
+		fmt.Printf("Do you have an existing cipher seed " +
+			"mnemonic you want to use? (Enter y/n): ")
+
+		reader := bufio.NewReader(os.Stdin)
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+
+		answer = strings.TrimSpace(answer)
+		answer = strings.ToLower(answer)
+
+		switch answer {
+		case "y":
+			hasMnemonic = true
+			break mnemonicCheck
+		case "n":
+			hasMnemonic = false
+			break mnemonicCheck
+		}
+	}
+
+	// If the user *does* have an existing seed they want to use, then
+	// we'll read that in directly from the terminal.
+	var (
+		cipherSeedMnemonic []string
+		aezeedPass         []byte
+		recoveryWindow     int32
+	)
+	if hasMnemonic {
+		// We'll now prompt the user to enter in their 24-word
+		// mnemonic.
+		fmt.Printf("Input your 24-word mnemonic separated by spaces: ")
+		reader := bufio.NewReader(os.Stdin)
+		mnemonic, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		// We'll trim off extra spaces, and ensure the mnemonic is all
+		// lower case, then populate our request.
+		mnemonic = strings.TrimSpace(mnemonic)
+		mnemonic = strings.ToLower(mnemonic)
+
+		cipherSeedMnemonic = strings.Split(mnemonic, " ")
+
+		fmt.Println()
+
+		if len(cipherSeedMnemonic) != 24 {
+			return fmt.Errorf("wrong cipher seed mnemonic "+
+				"length: got %v words, expecting %v words",
+				len(cipherSeedMnemonic), 24)
+		}
+
+		// Additionally, the user may have a passphrase, that will also
+		// need to be provided so the daemon can properly decipher the
+		// cipher seed.
+		fmt.Printf("Input your cipher seed passphrase (press enter if " +
+			"your seed doesn't have a passphrase): ")
+		passphrase, err := terminal.ReadPassword(int(syscall.Stdin))
+		if err != nil {
+			return err
+		}
+
+		aezeedPass = []byte(passphrase)
+
+		for {
+			fmt.Println()
+			fmt.Printf("Input an optional address look-ahead "+
+				"used to scan for used keys (default %d): ",
+				defaultRecoveryWindow)
+
+			reader := bufio.NewReader(os.Stdin)
+			answer, err := reader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+
+			fmt.Println()
+
+			answer = strings.TrimSpace(answer)
+
+			if len(answer) == 0 {
+				recoveryWindow = defaultRecoveryWindow
+				break
+			}
+
+			lookAhead, err := strconv.Atoi(answer)
+			if err != nil {
+				fmt.Println("Unable to parse recovery "+
+					"window: %v", err)
+				continue
+			}
+
+			recoveryWindow = int32(lookAhead)
+			break
+		}
+	} else {
+		// Otherwise, if the user doesn't have a mnemonic that they
+		// want to use, we'll generate a fresh one with the GenSeed
+		// command.
+		fmt.Println("Your cipher seed can optionally be encrypted.")
+		fmt.Printf("Input your passphrase if you wish to encrypt it " +
+			"(or press enter to proceed without a cipher seed " +
+			"passphrase): ")
+		aezeedPass1, err := terminal.ReadPassword(int(syscall.Stdin))
+		if err != nil {
+			return err
+		}
+		fmt.Println()
+
+		if len(aezeedPass1) != 0 {
+			fmt.Printf("Confirm cipher seed passphrase: ")
+			aezeedPass2, err := terminal.ReadPassword(
+				int(syscall.Stdin),
+			)
+			if err != nil {
+				return err
+			}
+			fmt.Println()
+
+			// If the passwords don't match, then we'll return an
+			// error.
+			if !bytes.Equal(aezeedPass1, aezeedPass2) {
+				return fmt.Errorf("cipher seed pass phrases " +
+					"don't match")
+			}
+		}
+
+		fmt.Println()
+		fmt.Println("Generating fresh cipher seed...")
+		fmt.Println()
+
+		genSeedReq := &lnrpc.GenSeedRequest{
+			AezeedPassphrase: aezeedPass1,
+		}
+		seedResp, err := client.GenSeed(ctxb, genSeedReq)
+		if err != nil {
+			return fmt.Errorf("unable to generate seed: %v", err)
+		}
+
+		cipherSeedMnemonic = seedResp.CipherSeedMnemonic
+		aezeedPass = aezeedPass1
+	}
+
+	// Before we initialize the wallet, we'll display the cipher seed to
+	// the user so they can write it down.
+	mnemonicWords := cipherSeedMnemonic
+
+	fmt.Println("!!!YOU MUST WRITE DOWN THIS SEED TO BE ABLE TO " +
+		"RESTORE THE WALLET!!!\n")
+
+	fmt.Println("---------------BEGIN LND CIPHER SEED---------------")
+
+	numCols := 4
+	colWords := monowidthColumns(mnemonicWords, numCols)
+	for i := 0; i < len(colWords); i += numCols {
+		fmt.Printf("%2d. %3s  %2d. %3s  %2d. %3s  %2d. %3s\n",
+			i+1, colWords[i], i+2, colWords[i+1], i+3,
+			colWords[i+2], i+4, colWords[i+3])
+	}
+
+	fmt.Println("---------------END LND CIPHER SEED-----------------")
+
+	fmt.Println("\n!!!YOU MUST WRITE DOWN THIS SEED TO BE ABLE TO " +
+		"RESTORE THE WALLET!!!")
+
+	// We'll also check to see if they provided any static channel backups,
+	// if so, then we'll also tack these onto the final innit wallet
+	// request.
+	var chanBackups *lnrpc.ChanBackupSnapshot
+	backups, err := parseChanBackups(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to parse chan "+
+			"backups: %v", err)
+	}
+
+	if backups != nil {
+		switch {
+		case backups.GetChanBackups() != nil:
+			singleBackup := backups.GetChanBackups()
+			chanBackups.SingleChanBackups = singleBackup
+
+		case backups.GetMultiChanBackup() != nil:
+			multiBackup := backups.GetMultiChanBackup()
+			chanBackups.MultiChanBackup = &lnrpc.MultiChanBackup{
+				MultiChanBackup: multiBackup,
+			}
+		}
+	}
+
+	// With either the user's prior cipher seed, or a newly generated one,
+	// we'll go ahead and initialize the wallet.
+	req := &lnrpc.InitWalletRequest{
+		WalletPassword:     pw1,
+		CipherSeedMnemonic: cipherSeedMnemonic,
+		AezeedPassphrase:   aezeedPass,
+		RecoveryWindow:     recoveryWindow,
+		ChannelBackups:     chanBackups,
+	}
+	if _, err := client.InitWallet(ctxb, req); err != nil {
+		return err
+	}
+
+	fmt.Println("\nlnd successfully initialized!")
+
+	return nil
+}
+
+var unlockCommand = cli.Command{
+	Name:     "unlock",
+	Category: "Startup",
+	Usage:    "Unlock an encrypted wallet at startup.",
+	Description: `
+	The unlock command is used to decrypt lnd's wallet state in order to
+	start up. This command MUST be run after booting up lnd before it's
+	able to carry out its duties. An exception is if a user is running with
+	--noseedbackup, then a default passphrase will be used.
+	`,
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name: "recovery_window",
+			Usage: "address lookahead to resume recovery rescan, " +
+				"value should be non-zero --  To recover all " +
+				"funds, this should be greater than the " +
+				"maximum number of consecutive, unused " +
+				"addresses ever generated by the wallet.",
+		},
+	},
+	Action: actionDecorator(unlock),
+}
+
+func unlock(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getWalletUnlockerClient(ctx)
+	defer cleanUp()
+
+	fmt.Printf("Input wallet password: ")
+	pw, err := terminal.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	args := ctx.Args()
+
+	// Parse the optional recovery window if it is specified. By default,
+	// the recovery window will be 0, indicating no lookahead should be
+	// used.
+	var recoveryWindow int32
+	switch {
+	case ctx.IsSet("recovery_window"):
+		recoveryWindow = int32(ctx.Int64("recovery_window"))
+	case args.Present():
+		window, err := strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return err
+		}
+		recoveryWindow = int32(window)
+	}
+
+	req := &lnrpc.UnlockWalletRequest{
+		WalletPassword: pw,
+		RecoveryWindow: recoveryWindow,
+	}
+	_, err = client.UnlockWallet(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nlnd successfully unlocked!")
+
+	// TODO(roasbeef): add ability to accept hex single and multi backups
+
+	return nil
+}
+
+var changePasswordCommand = cli.Command{
+	Name:     "changepassword",
+	Category: "Startup",
+	Usage:    "Change an encrypted wallet's password at startup.",
+	Description: `
+	The changepassword command is used to Change lnd's encrypted wallet's
+	password. It will automatically unlock the daemon if the password change
+	is successful.
+
+	If one did not specify a password for their wallet (running lnd with
+	--noseedbackup), one must restart their daemon without
+	--noseedbackup and use this command. The "current password" field
+	should be left empty.
+	`,
+	Action: actionDecorator(changePassword),
+}
+
+func changePassword(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getWalletUnlockerClient(ctx)
+	defer cleanUp()
+
+	fmt.Printf("Input current wallet password: ")
+	currentPw, err := terminal.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	fmt.Printf("Input new wallet password: ")
+	newPw, err := terminal.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	fmt.Printf("Confirm new wallet password: ")
+	confirmPw, err := terminal.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	if !bytes.Equal(newPw, confirmPw) {
+		return fmt.Errorf("passwords don't match")
+	}
+
+	req := &lnrpc.ChangePasswordRequest{
+		CurrentPassword: currentPw,
+		NewPassword:     newPw,
+	}
+
+	_, err = client.ChangePassword(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var walletBalanceCommand = cli.Command{
+	Name:     "walletbalance",
+	Category: "Wallet",
+	Usage:    "Compute and display the wallet's current balance.",
+	Action:   actionDecorator(walletBalance),
+}
+
+func walletBalance(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.WalletBalanceRequest{}
+	resp, err := client.WalletBalance(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var channelBalanceCommand = cli.Command{
+	Name:     "channelbalance",
+	Category: "Channels",
+	Usage: "Returns the sum of the total available channel balance across " +
+		"all open channels.",
+	Action: actionDecorator(channelBalance),
+}
+
+func channelBalance(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	unit, err := parseDisplayUnit(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.ChannelBalanceRequest{}
+	resp, err := client.ChannelBalance(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	if unit != nil {
+		printJSON(NewChannelBalanceFromProto(resp, unit))
+		return nil
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var getInfoCommand = cli.Command{
+	Name:   "getinfo",
+	Usage:  "Returns basic information related to the active daemon.",
+	Action: actionDecorator(getInfo),
+}
+
+type chain struct {
+	Chain   string `json:"chain"`
+	Network string `json:"network"`
+}
+
+func getInfo(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GetInfoRequest{}
+	resp, err := client.GetInfo(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	chains := make([]chain, len(resp.Chains))
+	for i, c := range resp.Chains {
+		chains[i] = chain{
+			Chain:   c.Chain,
+			Network: c.Network,
+		}
+	}
+
+	// We print a struct that mimics the proto definition of GetInfoResponse
+	// but has a better ordering for the same list of fields.
+	printJSON(struct {
+		Version             string   `json:"version"`
+		IdentityPubkey      string   `json:"identity_pubkey"`
+		Alias               string   `json:"alias"`
+		NumPendingChannels  uint32   `json:"num_pending_channels"`
+		NumActiveChannels   uint32   `json:"num_active_channels"`
+		NumInactiveChannels uint32   `json:"num_inactive_channels"`
+		NumPeers            uint32   `json:"num_peers"`
+		BlockHeight         uint32   `json:"block_height"`
+		BlockHash           string   `json:"block_hash"`
+		BestHeaderTimestamp int64    `json:"best_header_timestamp"`
+		SyncedToChain       bool     `json:"synced_to_chain"`
+		Testnet             bool     `json:"testnet"`
+		Chains              []chain  `json:"chains"`
+		Uris                []string `json:"uris"`
+	}{
+		Version:             resp.Version,
+		IdentityPubkey:      resp.IdentityPubkey,
+		Alias:               resp.Alias,
+		NumPendingChannels:  resp.NumPendingChannels,
+		NumActiveChannels:   resp.NumActiveChannels,
+		NumInactiveChannels: resp.NumInactiveChannels,
+		NumPeers:            resp.NumPeers,
+		BlockHeight:         resp.BlockHeight,
+		BlockHash:           resp.BlockHash,
+		BestHeaderTimestamp: resp.BestHeaderTimestamp,
+		SyncedToChain:       resp.SyncedToChain,
+		Testnet:             resp.Testnet,
+		Chains:              chains,
+		Uris:                resp.Uris,
+	})
+	return nil
+}
+
+var pendingChannelsCommand = cli.Command{
+	Name:     "pendingchannels",
+	Category: "Channels",
+	Usage:    "Display information pertaining to pending channels.",
+	Action:   actionDecorator(pendingChannels),
+}
+
+func pendingChannels(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.PendingChannelsRequest{}
+	resp, err := client.PendingChannels(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listChannelsCommand = cli.Command{
+	Name:     "listchannels",
+	Category: "Channels",
+	Usage:    "List all open channels.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "active_only",
+			Usage: "only list channels which are currently active",
+		},
+		cli.BoolFlag{
+			Name:  "inactive_only",
+			Usage: "only list channels which are currently inactive",
+		},
+		cli.BoolFlag{
+			Name:  "public_only",
+			Usage: "only list channels which are currently public",
+		},
+		cli.BoolFlag{
+			Name:  "private_only",
+			Usage: "only list channels which are currently private",
+		},
+	},
+	Action: actionDecorator(listChannels),
+}
+
+func listChannels(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListChannelsRequest{
+		ActiveOnly:   ctx.Bool("active_only"),
+		InactiveOnly: ctx.Bool("inactive_only"),
+		PublicOnly:   ctx.Bool("public_only"),
+		PrivateOnly:  ctx.Bool("private_only"),
+	}
+
+	resp, err := client.ListChannels(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	// TODO(roasbeef): defer close the client for the all
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var closedChannelsCommand = cli.Command{
+	Name:     "closedchannels",
+	Category: "Channels",
+	Usage:    "List all closed channels.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "cooperative",
+			Usage: "list channels that were closed cooperatively",
+		},
+		cli.BoolFlag{
+			Name: "local_force",
+			Usage: "list channels that were force-closed " +
+				"by the local node",
+		},
+		cli.BoolFlag{
+			Name: "remote_force",
+			Usage: "list channels that were force-closed " +
+				"by the remote node",
+		},
+		cli.BoolFlag{
+			Name: "breach",
+			Usage: "list channels for which the remote node " +
+				"attempted to broadcast a prior " +
+				"revoked channel state",
+		},
+		cli.BoolFlag{
+			Name:  "funding_canceled",
+			Usage: "list channels that were never fully opened",
+		},
+		cli.BoolFlag{
+			Name: "abandoned",
+			Usage: "list channels that were abandoned by " +
+				"the local node",
+		},
+	},
+	Action: actionDecorator(closedChannels),
+}
+
+func closedChannels(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ClosedChannelsRequest{
+		Cooperative:     ctx.Bool("cooperative"),
+		LocalForce:      ctx.Bool("local_force"),
+		RemoteForce:     ctx.Bool("remote_force"),
+		Breach:          ctx.Bool("breach"),
+		FundingCanceled: ctx.Bool("funding_cancelled"),
+		Abandoned:       ctx.Bool("abandoned"),
+	}
+
+	resp, err := client.ClosedChannels(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var cltvLimitFlag = cli.UintFlag{
+	Name: "cltv_limit",
+	Usage: "the maximum time lock that may be used for " +
+		"this payment",
+}
+
+var maxShardsFlag = cli.UintFlag{
+	Name: "max_shards",
+	Usage: "the maximum number of shards the payment may be " +
+		"split into if no single route can carry it, requires " +
+		"the payee to accept partial payments",
+}
+
+var feeWeightFlag = cli.Float64Flag{
+	Name: "fee_weight",
+	Usage: "the weight of the routing fees when selecting a route, " +
+		"replaces the configured path finding weights if any " +
+		"weight flag is set",
+}
+
+var riskFactorFlag = cli.Uint64Flag{
+	Name: "risk_factor",
+	Usage: "the cost in billionths per millisatoshi locked up per " +
+		"block when selecting a route, replaces the configured " +
+		"path finding weights if any weight flag is set",
+}
+
+var holdTimeWeightFlag = cli.Uint64Flag{
+	Name: "hold_time_weight",
+	Usage: "the cost in millisatoshis per second nodes took on " +
+		"average to resolve past HTLCs when selecting a route, " +
+		"replaces the configured path finding weights if any " +
+		"weight flag is set",
+}
+
+var probabilityWeightFlag = cli.Uint64Flag{
+	Name: "probability_weight",
+	Usage: "the cost in millisatoshis of channels with a low " +
+		"historical success probability when selecting a route, " +
+		"replaces the configured path finding weights if any " +
+		"weight flag is set",
+}
+
+var sendPaymentCommand = cli.Command{
+	Name:     "sendpayment",
+	Category: "Payments",
+	Usage:    "Send a payment over lightning.",
+	Description: `
+	Send a payment over Lightning. One can either specify the full
+	parameters of the payment, or just use a payment request which encodes
+	all the payment details.
+
+	If payment isn't manually specified, then only a payment request needs
+	to be passed using the --pay_req argument.
+
+	If the payment *is* manually specified, then all four alternative
+	arguments need to be specified in order to complete the payment:
+	    * --dest=N
+	    * --amt=A
+	    * --final_cltv_delta=T
+	    * --payment_hash=H
+
+	The --debug_send flag is provided for usage *purely* in test
+	environments. If specified, then the payment hash isn't required, as
+	it'll use the hash of all zeroes. This mode allows one to quickly test
+	payment connectivity without having to create an invoice at the
+	destination.
+	`,
+	ArgsUsage: "dest amt payment_hash final_cltv_delta | --pay_req=[payment request]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "dest, d",
+			Usage: "the compressed identity pubkey of the " +
+				"payment recipient",
+		},
+		cli.Int64Flag{
+			Name:  "amt, a",
+			Usage: "number of satoshis to send",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "maximum fee allowed in satoshis when sending" +
+				"the payment",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "percentage of the payment's amount used as the" +
+				"maximum fee allowed when sending the payment",
+		},
+		cltvLimitFlag,
+		maxShardsFlag,
+		feeWeightFlag,
+		riskFactorFlag,
+		probabilityWeightFlag,
+		holdTimeWeightFlag,
+		cli.StringFlag{
+			Name:  "payment_hash, r",
+			Usage: "the hash to use within the payment's HTLC",
+		},
+		cli.BoolFlag{
+			Name:  "debug_send",
+			Usage: "use the debug rHash when sending the HTLC",
+		},
+		cli.StringFlag{
+			Name:  "pay_req",
+			Usage: "a zpay32 encoded payment request to fulfill",
+		},
+		cli.Int64Flag{
+			Name:  "final_cltv_delta",
+			Usage: "the number of blocks the last hop has to reveal the preimage",
+		},
+		cli.Uint64Flag{
+			Name: "outgoing_chan_id",
+			Usage: "short channel id of the outgoing channel to " +
+				"use for the first hop of the payment",
+			Value: 0,
+		},
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "will skip payment request confirmation",
+		},
+	},
+	Action: sendPayment,
+}
+
+// retrieveFeeLimit retrieves the fee limit based on the different fee limit
+// flags passed.
+func retrieveFeeLimit(ctx *cli.Context) (*lnrpc.FeeLimit, error) {
+	switch {
+	case ctx.IsSet("fee_limit") && ctx.IsSet("fee_limit_percent"):
+		return nil, fmt.Errorf("either fee_limit or fee_limit_percent " +
+			"can be set, but not both")
+	case ctx.IsSet("fee_limit"):
+		return &lnrpc.FeeLimit{
+			Limit: &lnrpc.FeeLimit_Fixed{
+				Fixed: ctx.Int64("fee_limit"),
+			},
+		}, nil
+	case ctx.IsSet("fee_limit_percent"):
+		return &lnrpc.FeeLimit{
+			Limit: &lnrpc.FeeLimit_Percent{
+				Percent: ctx.Int64("fee_limit_percent"),
+			},
+		}, nil
+	}
+
+	// Since the fee limit flags aren't required, we don't return an error
+	// if they're not set.
+	return nil, nil
+}
+
+// retrievePathWeights returns the path finding weights set through the weight
+// flags. If none of them are set, nil is returned such that the weights
+// configured within lnd apply. Otherwise, any weight that isn't set is zero.
+func retrievePathWeights(ctx *cli.Context) *lnrpc.PathWeights {
+	if !ctx.IsSet(feeWeightFlag.Name) && !ctx.IsSet(riskFactorFlag.Name) &&
+		!ctx.IsSet(probabilityWeightFlag.Name) &&
+		!ctx.IsSet(holdTimeWeightFlag.Name) {
+
+		return nil
+	}
+
+	return &lnrpc.PathWeights{
+		FeeWeight:         ctx.Float64(feeWeightFlag.Name),
+		RiskFactor:        ctx.Uint64(riskFactorFlag.Name),
+		ProbabilityWeight: ctx.Uint64(probabilityWeightFlag.Name),
+		HoldTimeWeight:    ctx.Uint64(holdTimeWeightFlag.Name),
+	}
+}
+
+func confirmPayReq(ctx *cli.Context, client lnrpc.LightningClient, payReq string) error {
+	ctxb := context.Background()
+
+	req := &lnrpc.PayReqString{PayReq: payReq}
+	resp, err := client.DecodePayReq(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	// If the amount was not included in the invoice, then we let
+	// the payee specify the amount of satoshis they wish to send.
+	amt := resp.GetNumSatoshis()
+	if amt == 0 {
+		amt = ctx.Int64("amt")
+		if amt == 0 {
+			return fmt.Errorf("amount must be specified when " +
+				"paying a zero amount invoice")
+		}
+	}
+
+	fmt.Printf("Description: %v\n", resp.GetDescription())
+	fmt.Printf("Amount (in satoshis): %v\n", amt)
+	fmt.Printf("Destination: %v\n", resp.GetDestination())
+
+	confirm := promptForConfirmation("Confirm payment (yes/no): ")
+	if !confirm {
+		return fmt.Errorf("payment not confirmed")
+	}
+
+	return nil
+}
+
+func sendPayment(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+	// Show command help if no arguments provided
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "sendpayment")
+		return nil
+	}
+
+	// First, we'll retrieve the fee limit value passed since it can apply
+	// to both ways of sending payments (with the payment request or
+	// providing the details manually).
+	feeLimit, err := retrieveFeeLimit(ctx)
+	if err != nil {
+		return err
+	}
+
+	// If a payment request was provided, we can exit early since all of the
+	// details of the payment are encoded within the request.
+	if ctx.IsSet("pay_req") {
+		if !ctx.Bool("force") {
+			err = confirmPayReq(ctx, client, ctx.String("pay_req"))
+			if err != nil {
+				return err
+			}
+		}
+		req := &lnrpc.SendRequest{
+			PaymentRequest: ctx.String("pay_req"),
+			Amt:            ctx.Int64("amt"),
+			FeeLimit:       feeLimit,
+			OutgoingChanId: ctx.Uint64("outgoing_chan_id"),
+			CltvLimit:      uint32(ctx.Int(cltvLimitFlag.Name)),
+			MaxShards:      uint32(ctx.Uint(maxShardsFlag.Name)),
+			PathWeights:    retrievePathWeights(ctx),
+		}
+
+		return sendPaymentRequest(client, req)
+	}
+
+	var (
+		destNode []byte
+		amount   int64
+	)
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("dest"):
+		destNode, err = hex.DecodeString(ctx.String("dest"))
+	case args.Present():
+		destNode, err = hex.DecodeString(args.First())
+		args = args.Tail()
+	default:
+		return fmt.Errorf("destination txid argument missing")
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(destNode) != 33 {
+		return fmt.Errorf("dest node pubkey must be exactly 33 bytes, is "+
+			"instead: %v", len(destNode))
+	}
+
+	if ctx.IsSet("amt") {
+		amount = ctx.Int64("amt")
+	} else if args.Present() {
+		amount, err = strconv.ParseInt(args.First(), 10, 64)
+		args = args.Tail()
+		if err != nil {
+			return fmt.Errorf("unable to decode payment amount: %v", err)
+		}
+	}
+
+	req := &lnrpc.SendRequest{
+		Dest:        destNode,
+		Amt:         amount,
+		FeeLimit:    feeLimit,
+		MaxShards:   uint32(ctx.Uint(maxShardsFlag.Name)),
+		PathWeights: retrievePathWeights(ctx),
+	}
+
+	if ctx.Bool("debug_send") && (ctx.IsSet("payment_hash") || args.Present()) {
+		return fmt.Errorf("do not provide a payment hash with debug send")
+	} else if !ctx.Bool("debug_send") {
+		var rHash []byte
+
+		switch {
+		case ctx.IsSet("payment_hash"):
+			rHash, err = hex.DecodeString(ctx.String("payment_hash"))
+		case args.Present():
+			rHash, err = hex.DecodeString(args.First())
+			args = args.Tail()
+		default:
+			return fmt.Errorf("payment hash argument missing")
+		}
+
+		if err != nil {
+			return err
+		}
+		if len(rHash) != 32 {
+			return fmt.Errorf("payment hash must be exactly 32 "+
+				"bytes, is instead %v", len(rHash))
+		}
+		req.PaymentHash = rHash
+
+		switch {
+		case ctx.IsSet("final_cltv_delta"):
+			req.FinalCltvDelta = int32(ctx.Int64("final_cltv_delta"))
+		case args.Present():
+			delta, err := strconv.ParseInt(args.First(), 10, 64)
+			if err != nil {
+				return err
+			}
+			req.FinalCltvDelta = int32(delta)
+		}
+	}
+
+	return sendPaymentRequest(client, req)
+}
+
+func sendPaymentRequest(client lnrpc.LightningClient, req *lnrpc.SendRequest) error {
+	paymentStream, err := client.SendPayment(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if err := paymentStream.Send(req); err != nil {
+		return err
+	}
+
+	resp, err := paymentStream.Recv()
+	if err != nil {
+		return err
+	}
+
+	paymentStream.CloseSend()
+
+	printJSON(struct {
+		E string       `json:"payment_error"`
+		P string       `json:"payment_preimage"`
+		R *lnrpc.Route `json:"payment_route"`
+	}{
+		E: resp.PaymentError,
+		P: hex.EncodeToString(resp.PaymentPreimage),
+		R: resp.PaymentRoute,
+	})
+
+	// If we get a payment error back, we pass an error
+	// up to main which eventually calls fatal() and returns
+	// with a non-zero exit code.
+	if resp.PaymentError != "" {
+		return errors.New(resp.PaymentError)
+	}
+
+	return nil
+}
+
+var payInvoiceCommand = cli.Command{
+	Name:      "payinvoice",
+	Category:  "Payments",
+	Usage:     "Pay an invoice over lightning.",
+	ArgsUsage: "pay_req",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pay_req",
+			Usage: "a zpay32 encoded payment request to fulfill",
+		},
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "(optional) number of satoshis to fulfill the " +
+				"invoice",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "maximum fee allowed in satoshis when sending " +
+				"the payment",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "percentage of the payment's amount used as the" +
+				"maximum fee allowed when sending the payment",
+		},
+		cltvLimitFlag,
+		maxShardsFlag,
+		feeWeightFlag,
+		riskFactorFlag,
+		probabilityWeightFlag,
+		holdTimeWeightFlag,
+		cli.Uint64Flag{
+			Name: "outgoing_chan_id",
+			Usage: "short channel id of the outgoing channel to " +
+				"use for the first hop of the payment",
+			Value: 0,
+		},
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "will skip payment request confirmation",
+		},
+	},
+	Action: actionDecorator(payInvoice),
+}
+
+func payInvoice(ctx *cli.Context) error {
+	args := ctx.Args()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var payReq string
+	switch {
+	case ctx.IsSet("pay_req"):
+		payReq = ctx.String("pay_req")
+	case args.Present():
+		payReq = args.First()
+	default:
+		return fmt.Errorf("pay_req argument missing")
+	}
+
+	feeLimit, err := retrieveFeeLimit(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.Bool("force") {
+		err = confirmPayReq(ctx, client, payReq)
+		if err != nil {
+			return err
+		}
+	}
+
+	req := &lnrpc.SendRequest{
+		PaymentRequest: payReq,
+		Amt:            ctx.Int64("amt"),
+		FeeLimit:       feeLimit,
+		OutgoingChanId: ctx.Uint64("outgoing_chan_id"),
+		CltvLimit:      uint32(ctx.Int(cltvLimitFlag.Name)),
+		MaxShards:      uint32(ctx.Uint(maxShardsFlag.Name)),
+		PathWeights:    retrievePathWeights(ctx),
+	}
+
+	return sendPaymentRequest(client, req)
+}
+
+var sendToRouteCommand = cli.Command{
+	Name:  "sendtoroute",
+	Usage: "send a payment over a predefined route",
+	Description: `
+	Send a payment over Lightning using a specific route. One must specify
+	a list of routes to attempt and the payment hash. This command can even
+	be chained with the response to queryroutes. This command can be used
+	to implement channel rebalancing by crafting a self-route, or even
+	atomic swaps using a self-route that crosses multiple chains.
+
+	There are three ways to specify routes:
+	   * using the --routes parameter to manually specify a JSON encoded
+	     set of routes in the format of the return value of queryroutes:
+	         (lncli sendtoroute --payment_hash=<pay_hash> --routes=<route>)
+
+	   * passing the routes as a positional argument:
+	         (lncli sendtoroute --payment_hash=pay_hash <route>)
+
+	   * or reading in the routes from stdin, which can allow chaining the
+	     response from queryroutes, or even read in a file with a set of
+	     pre-computed routes:
+	         (lncli queryroutes --args.. | lncli sendtoroute --payment_hash= -
+
+	     notice the '-' at the end, which signals that lncli should read
+	     the route in from stdin
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "payment_hash, pay_hash",
+			Usage: "the hash to use within the payment's HTLC",
+		},
+		cli.StringFlag{
+			Name: "routes, r",
+			Usage: "a json array string in the format of the response " +
+				"of queryroutes that denotes which routes to use",
+		},
+	},
+	Action: sendToRoute,
+}
+
+func sendToRoute(ctx *cli.Context) error {
+	// Show command help if no arguments provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "sendtoroute")
+		return nil
+	}
+
+	args := ctx.Args()
+
+	var (
+		rHash []byte
+		err   error
+	)
+	switch {
+	case ctx.IsSet("payment_hash"):
+		rHash, err = hex.DecodeString(ctx.String("payment_hash"))
+	case args.Present():
+		rHash, err = hex.DecodeString(args.First())
+
+		args = args.Tail()
+	default:
+		return fmt.Errorf("payment hash argument missing")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(rHash) != 32 {
+		return fmt.Errorf("payment hash must be exactly 32 "+
+			"bytes, is instead %d", len(rHash))
+	}
+
+	var jsonRoutes string
+	switch {
+	// The user is specifying the routes explicitly via the key word
+	// argument.
+	case ctx.IsSet("routes"):
+		jsonRoutes = ctx.String("routes")
+
+	// The user is specifying the routes as a positional argument.
+	case args.Present() && args.First() != "-":
+		jsonRoutes = args.First()
+
+	// The user is signalling that we should read stdin in order to parse
+	// the set of target routes.
+	case args.Present() && args.First() == "-":
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		if len(b) == 0 {
+			return fmt.Errorf("queryroutes output is empty")
+		}
+
+		jsonRoutes = string(b)
+	}
+
+	routes := &lnrpc.QueryRoutesResponse{}
+	err = jsonpb.UnmarshalString(jsonRoutes, routes)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal json string "+
+			"from incoming array of routes: %v", err)
+	}
+
+	req := &lnrpc.SendToRouteRequest{
+		PaymentHash: rHash,
+		Routes:      routes.Routes,
+	}
+
+	return sendToRouteRequest(ctx, req)
+}
+
+func sendToRouteRequest(ctx *cli.Context, req *lnrpc.SendToRouteRequest) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	paymentStream, err := client.SendToRoute(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if err := paymentStream.Send(req); err != nil {
+		return err
+	}
+
+	resp, err := paymentStream.Recv()
+	if err != nil {
+		return err
+	}
+
+	printJSON(struct {
+		E string       `json:"payment_error"`
+		P string       `json:"payment_preimage"`
+		R *lnrpc.Route `json:"payment_route"`
+	}{
+		E: resp.PaymentError,
+		P: hex.EncodeToString(resp.PaymentPreimage),
+		R: resp.PaymentRoute,
+	})
+
+	return nil
+}
+
+var addInvoiceCommand = cli.Command{
+	Name:     "addinvoice",
+	Category: "Payments",
+	Usage:    "Add a new invoice.",
+	Description: `
+	Add a new invoice, expressing intent for a future payment.
+
+	Invoices without an amount can be created by not supplying any
+	parameters or providing an amount of 0. These invoices allow the payee
+	to specify the amount of satoshis they wish to send.`,
+	ArgsUsage: "value preimage",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "memo",
+			Usage: "a description of the payment to attach along " +
+				"with the invoice (default=\"\")",
+		},
+		cli.StringFlag{
+			Name:  "receipt",
+			Usage: "an optional cryptographic receipt of payment",
+		},
+		cli.StringFlag{
+			Name: "preimage",
+			Usage: "the hex-encoded preimage (32 byte) which will " +
+				"allow settling an incoming HTLC payable to this " +
+				"preimage. If not set, a random preimage will be " +
+				"created.",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the amt of satoshis in this invoice",
+		},
+		cli.StringFlag{
+			Name: "description_hash",
+			Usage: "SHA-256 hash of the description of the payment. " +
+				"Used if the purpose of payment cannot naturally " +
+				"fit within the memo. If provided this will be " +
+				"used instead of the description(memo) field in " +
+				"the encoded invoice.",
+		},
+		cli.StringFlag{
+			Name: "fallback_addr",
+			Usage: "fallback on-chain address that can be used in " +
+				"case the lightning payment fails",
+		},
+		cli.Int64Flag{
+			Name: "expiry",
+			Usage: "the invoice's expiry time in seconds. If not " +
+				"specified an expiry of 3600 seconds (1 hour) " +
+				"is implied.",
+		},
+		cli.BoolTFlag{
+			Name: "private",
+			Usage: "encode routing hints in the invoice with " +
+				"private channels in order to assist the " +
+				"payer in reaching you",
+		},
+		cli.StringFlag{
+			Name: "fiat_currency",
+			Usage: "the ISO 4217 code of the fiat currency in " +
+				"which the invoice should be accounted for, " +
+				"e.g. USD. The exchange rate is recorded when " +
+				"the invoice is created and settled",
+		},
+	},
+	Action: actionDecorator(addInvoice),
+}
+
+func addInvoice(ctx *cli.Context) error {
+	var (
+		preimage []byte
+		descHash []byte
+		receipt  []byte
+		amt      int64
+		err      error
+	)
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("amt"):
+		amt = ctx.Int64("amt")
+	case args.Present():
+		amt, err = strconv.ParseInt(args.First(), 10, 64)
+		args = args.Tail()
+		if err != nil {
+			return fmt.Errorf("unable to decode amt argument: %v", err)
+		}
+	}
+
+	switch {
+	case ctx.IsSet("preimage"):
+		preimage, err = hex.DecodeString(ctx.String("preimage"))
+	case args.Present():
+		preimage, err = hex.DecodeString(args.First())
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to parse preimage: %v", err)
+	}
+
+	descHash, err = hex.DecodeString(ctx.String("description_hash"))
+	if err != nil {
+		return fmt.Errorf("unable to parse description_hash: %v", err)
+	}
+
+	receipt, err = hex.DecodeString(ctx.String("receipt"))
+	if err != nil {
+		return fmt.Errorf("unable to parse receipt: %v", err)
+	}
+
+	invoice := &lnrpc.Invoice{
+		Memo:            ctx.String("memo"),
+		Receipt:         receipt,
+		RPreimage:       preimage,
+		Value:           amt,
+		DescriptionHash: descHash,
+		FallbackAddr:    ctx.String("fallback_addr"),
+		Expiry:          ctx.Int64("expiry"),
+		Private:         ctx.Bool("private"),
+		FiatCurrency:    ctx.String("fiat_currency"),
+	}
+
+	resp, err := client.AddInvoice(context.Background(), invoice)
+	if err != nil {
+		return err
+	}
+
+	printJSON(struct {
+		RHash        string              `json:"r_hash"`
+		PayReq       string              `json:"pay_req"`
+		AddIndex     uint64              `json:"add_index"`
+		CreationFiat *lnrpc.FiatSnapshot `json:"creation_fiat,omitempty"`
+	}{
+		RHash:        hex.EncodeToString(resp.RHash),
+		PayReq:       resp.PaymentRequest,
+		AddIndex:     resp.AddIndex,
+		CreationFiat: resp.CreationFiat,
+	})
+
+	return nil
+}
+
+var lookupInvoiceCommand = cli.Command{
+	Name:      "lookupinvoice",
+	Category:  "Payments",
+	Usage:     "Lookup an existing invoice by its payment hash.",
+	ArgsUsage: "rhash",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "rhash",
+			Usage: "the 32 byte payment hash of the invoice to query for, the hash " +
+				"should be a hex-encoded string",
+		},
+	},
+	Action: actionDecorator(lookupInvoice),
+}
+
+func lookupInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		rHash []byte
+		err   error
+	)
+
+	switch {
+	case ctx.IsSet("rhash"):
+		rHash, err = hex.DecodeString(ctx.String("rhash"))
+	case ctx.Args().Present():
+		rHash, err = hex.DecodeString(ctx.Args().First())
+	default:
+		return fmt.Errorf("rhash argument missing")
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to decode rhash argument: %v", err)
+	}
+
+	req := &lnrpc.PaymentHash{
+		RHash: rHash,
+	}
+
+	invoice, err := client.LookupInvoice(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(invoice)
+
+	return nil
+}
+
+var listInvoicesCommand = cli.Command{
+	Name:     "listinvoices",
+	Category: "Payments",
+	Usage: "List all invoices currently stored within the database. Any " +
+		"active debug invoices are ignored.",
+	Description: `
+	This command enables the retrieval of all invoices currently stored
+	within the database. It has full support for paginationed responses,
+	allowing users to query for specific invoices through their add_index.
+	This can be done by using either the first_index_offset or
+	last_index_offset fields included in the response as the index_offset of
+	the next request. The reversed flag is set by default in order to
+	paginate backwards. If you wish to paginate forwards, you must
+	explicitly set the flag to false. If none of the parameters are
+	specified, then the last 100 invoices will be returned.
+
+	For example: if you have 200 invoices, "lncli listinvoices" will return
+	the last 100 created. If you wish to retrieve the previous 100, the
+	first_offset_index of the response can be used as the index_offset of
+	the next listinvoices request.
+
+	The invoices can be filtered by their state (--state, which can be
+	repeated), by the time they were created (--created_after and
+	--created_before, expressed in seconds since the unix epoch), and by
+	their memo (--memo). Filtered invoices don't count towards
+	max_invoices.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "pending_only",
+			Usage: "toggles if all invoices should be returned, " +
+				"or only those that are currently unsettled",
+		},
+		cli.Uint64Flag{
+			Name: "index_offset",
+			Usage: "the index of an invoice that will be used as " +
+				"either the start or end of a query to " +
+				"determine which invoices should be returned " +
+				"in the response",
+		},
+		cli.Uint64Flag{
+			Name:  "max_invoices",
+			Usage: "the max number of invoices to return",
+		},
+		cli.BoolTFlag{
+			Name: "reversed",
+			Usage: "if set, the invoices returned precede the " +
+				"given index_offset, allowing backwards " +
+				"pagination",
+		},
+		cli.StringSliceFlag{
+			Name: "state",
+			Usage: "only return invoices in this state: open, " +
+				"settled, canceled or accepted; can be " +
+				"specified multiple times",
+		},
+		cli.Int64Flag{
+			Name: "created_after",
+			Usage: "only return invoices created at or after " +
+				"this time, expressed in seconds since the " +
+				"unix epoch",
+		},
+		cli.Int64Flag{
+			Name: "created_before",
+			Usage: "only return invoices created before this " +
+				"time, expressed in seconds since the unix " +
+				"epoch",
+		},
+		cli.StringFlag{
+			Name:  "memo",
+			Usage: "only return invoices whose memo contains this",
+		},
+	},
+	Action: actionDecorator(listInvoices),
+}
+
+func listInvoices(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListInvoiceRequest{
+		PendingOnly:       ctx.Bool("pending_only"),
+		IndexOffset:       ctx.Uint64("index_offset"),
+		NumMaxInvoices:    ctx.Uint64("max_invoices"),
+		Reversed:          ctx.Bool("reversed"),
+		CreationDateStart: ctx.Int64("created_after"),
+		CreationDateEnd:   ctx.Int64("created_before"),
+		MemoSubstring:     ctx.String("memo"),
+	}
+
+	for _, state := range ctx.StringSlice("state") {
+		name := strings.ToUpper(state)
+		value, ok := lnrpc.Invoice_InvoiceState_value[name]
+		if !ok {
+			return fmt.Errorf("unknown invoice state %v", state)
+		}
+		req.States = append(
+			req.States, lnrpc.Invoice_InvoiceState(value),
+		)
+	}
+
+	invoices, err := client.ListInvoices(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(invoices)
+
+	return nil
+}
+
+var deleteInvoicesCommand = cli.Command{
+	Name:     "deleteinvoices",
+	Category: "Payments",
+	Usage:    "Delete the selected invoices from the database.",
+	Description: `
+	Delete the invoices that were settled before a particular time
+	(--settled_before), all canceled invoices (--canceled), and/or the open
+	invoices that haven't been paid yet and were created before a particular
+	time (--open_before). Times are expressed in seconds since the Unix
+	epoch. Accepted invoices, whose HTLCs are still being held, are never
+	deleted.`,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "settled_before",
+			Usage: "delete the invoices settled before this time, " +
+				"expressed in seconds since the unix epoch",
+		},
+		cli.BoolFlag{
+			Name:  "canceled",
+			Usage: "delete all canceled invoices",
+		},
+		cli.Int64Flag{
+			Name: "open_before",
+			Usage: "delete the unpaid invoices created before " +
+				"this time, expressed in seconds since the " +
+				"unix epoch",
+		},
+	},
+	Action: actionDecorator(deleteInvoices),
+}
+
+func deleteInvoices(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.DeleteInvoicesRequest{
+		SettledBefore: ctx.Int64("settled_before"),
+		Canceled:      ctx.Bool("canceled"),
+		OpenBefore:    ctx.Int64("open_before"),
+	}
+
+	resp, err := client.DeleteInvoices(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var describeGraphCommand = cli.Command{
+	Name:     "describegraph",
+	Category: "Peers",
+	Description: "Prints a human readable version of the known channel " +
+		"graph from the PoV of the node",
+	Usage: "Describe the network graph.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "include_unannounced",
+			Usage: "If set, unannounced channels will be included in the " +
+				"graph. Unannounced channels are both private channels, and " +
+				"public channels that are not yet announced to the network.",
+		},
+	},
+	Action: actionDecorator(describeGraph),
+}
 
-			if len(answer) == 0 {
-				recoveryWindow = defaultRecoveryWindow
-				break
-			}
+func describeGraph(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-			lookAhead, err := strconv.Atoi(answer)
-			if err != nil {
-				fmt.Println("Unable to parse recovery "+
-					"window: %v", err)
-				continue
-			}
+	req := &lnrpc.ChannelGraphRequest{
+		IncludeUnannounced: ctx.Bool("include_unannounced"),
+	}
 
-			recoveryWindow = int32(lookAhead)
-			break
-		}
-	} else {
-		// Otherwise, if the user doesn't have a mnemonic that they
-		// want to use, we'll generate a fresh one with the GenSeed
-		// command.
-		fmt.Println("Your cipher seed can optionally be encrypted.")
-		fmt.Printf("Input your passphrase if you wish to encrypt it " +
-			"(or press enter to proceed without a cipher seed " +
-			"passphrase): ")
-		aezeedPass1, err := terminal.ReadPassword(int(syscall.Stdin))
-		if err != nil {
-			return err
-		}
-		fmt.Println()
+	graph, err := client.DescribeGraph(context.Background(), req)
+	if err != nil {
+		return err
+	}
 
-		if len(aezeedPass1) != 0 {
-			fmt.Printf("Confirm cipher seed passphrase: ")
-			aezeedPass2, err := terminal.ReadPassword(
-				int(syscall.Stdin),
-			)
-			if err != nil {
-				return err
-			}
-			fmt.Println()
+	printRespJSON(graph)
+	return nil
+}
 
-			// If the passwords don't match, then we'll return an
-			// error.
-			if !bytes.Equal(aezeedPass1, aezeedPass2) {
-				return fmt.Errorf("cipher seed pass phrases " +
-					"don't match")
-			}
-		}
+// normalizeFunc is a factory function which returns a function that normalizes
+// the capacity of edges within the graph. The value of the returned
+// function can be used to either plot the capacities, or to use a weight in a
+// rendering of the graph.
+func normalizeFunc(edges []*lnrpc.ChannelEdge, scaleFactor float64) func(int64) float64 {
+	var (
+		min float64 = math.MaxInt64
+		max float64
+	)
 
-		fmt.Println()
-		fmt.Println("Generating fresh cipher seed...")
-		fmt.Println()
+	for _, edge := range edges {
+		// In order to obtain saner values, we reduce the capacity of a
+		// channel to its base 2 logarithm.
+		z := math.Log2(float64(edge.Capacity))
 
-		genSeedReq := &lnrpc.GenSeedRequest{
-			AezeedPassphrase: aezeedPass1,
+		if z < min {
+			min = z
 		}
-		seedResp, err := client.GenSeed(ctxb, genSeedReq)
-		if err != nil {
-			return fmt.Errorf("unable to generate seed: %v", err)
+		if z > max {
+			max = z
 		}
+	}
 
-		cipherSeedMnemonic = seedResp.CipherSeedMnemonic
-		aezeedPass = aezeedPass1
+	return func(x int64) float64 {
+		y := math.Log2(float64(x))
+
+		// TODO(roasbeef): results in min being zero
+		return (y - min) / (max - min) * scaleFactor
 	}
+}
 
-	// Before we initialize the wallet, we'll display the cipher seed to
-	// the user so they can write it down.
-	mnemonicWords := cipherSeedMnemonic
+var listPaymentsCommand = cli.Command{
+	Name:     "listpayments",
+	Category: "Payments",
+	Usage:    "List all outgoing payments.",
+	Action:   actionDecorator(listPayments),
+}
 
-	fmt.Println("!!!YOU MUST WRITE DOWN THIS SEED TO BE ABLE TO " +
-		"RESTORE THE WALLET!!!\n")
+func listPayments(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-	fmt.Println("---------------BEGIN LND CIPHER SEED---------------")
+	req := &lnrpc.ListPaymentsRequest{}
 
-	numCols := 4
-	colWords := monowidthColumns(mnemonicWords, numCols)
-	for i := 0; i < len(colWords); i += numCols {
-		fmt.Printf("%2d. %3s  %2d. %3s  %2d. %3s  %2d. %3s\n",
-			i+1, colWords[i], i+2, colWords[i+1], i+3,
-			colWords[i+2], i+4, colWords[i+3])
+	payments, err := client.ListPayments(context.Background(), req)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("---------------END LND CIPHER SEED-----------------")
+	printRespJSON(payments)
+	return nil
+}
 
-	fmt.Println("\n!!!YOU MUST WRITE DOWN THIS SEED TO BE ABLE TO " +
-		"RESTORE THE WALLET!!!")
+var deletePaymentsCommand = cli.Command{
+	Name:     "deletepayments",
+	Category: "Payments",
+	Usage:    "Delete the selected payment records from the database.",
+	Description: `
+	Delete the records of all failed payments (--failed), and/or the
+	completed payments that were created before a particular time
+	(--completed_before), expressed in seconds since the Unix epoch. The
+	payment hashes of deleted completed payments are still known to have
+	been paid, such that they can't be paid twice. Payments that are still
+	in flight are never deleted.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "failed",
+			Usage: "delete the records of all failed payments",
+		},
+		cli.Int64Flag{
+			Name: "completed_before",
+			Usage: "delete the completed payments created before " +
+				"this time, expressed in seconds since the " +
+				"unix epoch",
+		},
+	},
+	Action: actionDecorator(deletePayments),
+}
 
-	// We'll also check to see if they provided any static channel backups,
-	// if so, then we'll also tack these onto the final innit wallet
-	// request.
-	var chanBackups *lnrpc.ChanBackupSnapshot
-	backups, err := parseChanBackups(ctx)
+func deletePayments(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.DeletePaymentsRequest{
+		Failed:          ctx.Bool("failed"),
+		CompletedBefore: ctx.Int64("completed_before"),
+	}
+
+	resp, err := client.DeletePayments(context.Background(), req)
 	if err != nil {
-		return fmt.Errorf("unable to parse chan "+
-			"backups: %v", err)
+		return err
 	}
 
-	if backups != nil {
-		switch {
-		case backups.GetChanBackups() != nil:
-			singleBackup := backups.GetChanBackups()
-			chanBackups.SingleChanBackups = singleBackup
+	printRespJSON(resp)
+	return nil
+}
 
-		case backups.GetMultiChanBackup() != nil:
-			multiBackup := backups.GetMultiChanBackup()
-			chanBackups.MultiChanBackup = &lnrpc.MultiChanBackup{
-				MultiChanBackup: multiBackup,
-			}
-		}
-	}
+var feeBudgetCommand = cli.Command{
+	Name:     "feebudget",
+	Category: "Payments",
+	Usage:    "Show the remaining daily routing fee budget.",
+	Description: `
+	Returns the daily routing fee budget, along with the routing fees paid
+	by outgoing payments within the last 24 hours, the fees of payment
+	attempts in flight, and the part of the budget that remains. The
+	default fee limit of payments that don't specify one is included as
+	well.`,
+	Action: actionDecorator(feeBudget),
+}
 
-	// With either the user's prior cipher seed, or a newly generated one,
-	// we'll go ahead and initialize the wallet.
-	req := &lnrpc.InitWalletRequest{
-		WalletPassword:     pw1,
-		CipherSeedMnemonic: cipherSeedMnemonic,
-		AezeedPassphrase:   aezeedPass,
-		RecoveryWindow:     recoveryWindow,
-		ChannelBackups:     chanBackups,
-	}
-	if _, err := client.InitWallet(ctxb, req); err != nil {
+func feeBudget(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GetFeeBudgetRequest{}
+
+	budget, err := client.GetFeeBudget(context.Background(), req)
+	if err != nil {
 		return err
 	}
 
-	fmt.Println("\nlnd successfully initialized!")
-
+	printRespJSON(budget)
 	return nil
 }
 
-var unlockCommand = cli.Command{
-	Name:     "unlock",
-	Category: "Startup",
-	Usage:    "Unlock an encrypted wallet at startup.",
+var addScheduleCommand = cli.Command{
+	Name:     "addschedule",
+	Category: "Payments",
+	Usage:    "Schedule recurring payments towards a destination.",
 	Description: `
-	The unlock command is used to decrypt lnd's wallet state in order to
-	start up. This command MUST be run after booting up lnd before it's
-	able to carry out its duties. An exception is if a user is running with
-	--noseedbackup, then a default passphrase will be used.
-	`,
+	Adds a new schedule of recurring payments. The payee provides a set of
+	payment requests, all paying the same destination and amount, the next
+	of which is paid each time a payment is due. Payments are only made as
+	long as the total amount spent, including routing fees, stays within the
+	budget of the schedule. Further payment requests can be added to the
+	schedule at any time using the extendschedule command.`,
+	ArgsUsage: "pay_req [pay_req...]",
 	Flags: []cli.Flag{
-		cli.IntFlag{
-			Name: "recovery_window",
-			Usage: "address lookahead to resume recovery rescan, " +
-				"value should be non-zero --  To recover all " +
-				"funds, this should be greater than the " +
-				"maximum number of consecutive, unused " +
-				"addresses ever generated by the wallet.",
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "the amount in satoshis to send with each " +
+				"payment, only required if the payment " +
+				"requests don't specify an amount",
+		},
+		cli.Uint64Flag{
+			Name:  "interval",
+			Usage: "the number of seconds between two payments",
+		},
+		cli.Int64Flag{
+			Name: "max_fee",
+			Usage: "the maximum routing fee in satoshis to pay " +
+				"for each payment",
+		},
+		cli.Int64Flag{
+			Name: "budget",
+			Usage: "the maximum total amount in satoshis, " +
+				"including routing fees, that may be spent",
+		},
+		cli.Int64Flag{
+			Name: "start_time",
+			Usage: "the unix timestamp at which the first " +
+				"payment is due, if not set the first " +
+				"payment is made right away",
 		},
 	},
-	Action: actionDecorator(unlock),
+	Action: actionDecorator(addSchedule),
 }
 
-func unlock(ctx *cli.Context) error {
+func addSchedule(ctx *cli.Context) error {
 	ctxb := context.Background()
-	client, cleanUp := getWalletUnlockerClient(ctx)
+	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	fmt.Printf("Input wallet password: ")
-	pw, err := terminal.ReadPassword(int(syscall.Stdin))
+	if !ctx.Args().Present() {
+		return fmt.Errorf("at least one payment request must be " +
+			"specified")
+	}
+
+	req := &lnrpc.AddPaymentScheduleRequest{
+		PaymentRequests: ctx.Args(),
+		Amt:             ctx.Int64("amt"),
+		Interval:        ctx.Uint64("interval"),
+		MaxFee:          ctx.Int64("max_fee"),
+		Budget:          ctx.Int64("budget"),
+		StartTime:       ctx.Int64("start_time"),
+	}
+
+	schedule, err := client.AddPaymentSchedule(ctxb, req)
 	if err != nil {
 		return err
 	}
-	fmt.Println()
+
+	printRespJSON(schedule)
+	return nil
+}
+
+var listSchedulesCommand = cli.Command{
+	Name:     "listschedules",
+	Category: "Payments",
+	Usage:    "List all recurring payment schedules.",
+	Action:   actionDecorator(listSchedules),
+}
+
+func listSchedules(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListPaymentSchedulesRequest{}
+
+	schedules, err := client.ListPaymentSchedules(
+		context.Background(), req,
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(schedules)
+	return nil
+}
+
+var extendScheduleCommand = cli.Command{
+	Name:     "extendschedule",
+	Category: "Payments",
+	Usage:    "Add payment requests to a recurring payment schedule.",
+	Description: `
+	Appends the given payment requests to those of an existing payment
+	schedule. If the schedule was disabled after too many consecutive
+	payment failures, it is re-activated.`,
+	ArgsUsage: "id pay_req [pay_req...]",
+	Action:    actionDecorator(extendSchedule),
+}
+
+func extendSchedule(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
 	args := ctx.Args()
+	if len(args) < 2 {
+		return cli.ShowCommandHelp(ctx, "extendschedule")
+	}
 
-	// Parse the optional recovery window if it is specified. By default,
-	// the recovery window will be 0, indicating no lookahead should be
-	// used.
-	var recoveryWindow int32
-	switch {
-	case ctx.IsSet("recovery_window"):
-		recoveryWindow = int32(ctx.Int64("recovery_window"))
-	case args.Present():
-		window, err := strconv.ParseInt(args.First(), 10, 64)
-		if err != nil {
-			return err
-		}
-		recoveryWindow = int32(window)
+	id, err := strconv.ParseUint(args.First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unable to decode schedule id: %v", err)
 	}
 
-	req := &lnrpc.UnlockWalletRequest{
-		WalletPassword: pw,
-		RecoveryWindow: recoveryWindow,
+	req := &lnrpc.ExtendPaymentScheduleRequest{
+		Id:              id,
+		PaymentRequests: args.Tail(),
 	}
-	_, err = client.UnlockWallet(ctxb, req)
+
+	schedule, err := client.ExtendPaymentSchedule(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("\nlnd successfully unlocked!")
-
-	// TODO(roasbeef): add ability to accept hex single and multi backups
-
+	printRespJSON(schedule)
 	return nil
 }
 
-var changePasswordCommand = cli.Command{
-	Name:     "changepassword",
-	Category: "Startup",
-	Usage:    "Change an encrypted wallet's password at startup.",
-	Description: `
-	The changepassword command is used to Change lnd's encrypted wallet's
-	password. It will automatically unlock the daemon if the password change
-	is successful.
-
-	If one did not specify a password for their wallet (running lnd with
-	--noseedbackup), one must restart their daemon without
-	--noseedbackup and use this command. The "current password" field
-	should be left empty.
-	`,
-	Action: actionDecorator(changePassword),
+var removeScheduleCommand = cli.Command{
+	Name:      "removeschedule",
+	Category:  "Payments",
+	Usage:     "Remove a recurring payment schedule.",
+	ArgsUsage: "id",
+	Action:    actionDecorator(removeSchedule),
 }
 
-func changePassword(ctx *cli.Context) error {
+func removeSchedule(ctx *cli.Context) error {
 	ctxb := context.Background()
-	client, cleanUp := getWalletUnlockerClient(ctx)
+	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	fmt.Printf("Input current wallet password: ")
-	currentPw, err := terminal.ReadPassword(int(syscall.Stdin))
-	if err != nil {
-		return err
+	if !ctx.Args().Present() {
+		return cli.ShowCommandHelp(ctx, "removeschedule")
 	}
-	fmt.Println()
 
-	fmt.Printf("Input new wallet password: ")
-	newPw, err := terminal.ReadPassword(int(syscall.Stdin))
+	id, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to decode schedule id: %v", err)
 	}
-	fmt.Println()
 
-	fmt.Printf("Confirm new wallet password: ")
-	confirmPw, err := terminal.ReadPassword(int(syscall.Stdin))
+	req := &lnrpc.RemovePaymentScheduleRequest{
+		Id: id,
+	}
+
+	resp, err := client.RemovePaymentSchedule(ctxb, req)
 	if err != nil {
 		return err
 	}
-	fmt.Println()
 
-	if !bytes.Equal(newPw, confirmPw) {
-		return fmt.Errorf("passwords don't match")
-	}
+	printRespJSON(resp)
+	return nil
+}
 
-	req := &lnrpc.ChangePasswordRequest{
-		CurrentPassword: currentPw,
-		NewPassword:     newPw,
-	}
+var deriveSwapKeyCommand = cli.Command{
+	Name:     "deriveswapkey",
+	Category: "Swaps",
+	Usage:    "Derive a key for the on-chain HTLC of a submarine swap.",
+	Description: `
+	Derives a fresh key to be used within the on-chain HTLC of a new
+	submarine swap. The public key is to be handed to the swap provider,
+	while the key index is needed to register the swap with lnd.`,
+	Action: actionDecorator(deriveSwapKey),
+}
 
-	_, err = client.ChangePassword(ctxb, req)
+func deriveSwapKey(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.DeriveSwapKeyRequest{}
+
+	resp, err := client.DeriveSwapKey(context.Background(), req)
 	if err != nil {
 		return err
 	}
 
+	printRespJSON(resp)
 	return nil
 }
 
-var walletBalanceCommand = cli.Command{
-	Name:     "walletbalance",
-	Category: "Wallet",
-	Usage:    "Compute and display the wallet's current balance.",
-	Action:   actionDecorator(walletBalance),
+var listSwapsCommand = cli.Command{
+	Name:     "listswaps",
+	Category: "Swaps",
+	Usage:    "List all registered submarine swaps.",
+	Action:   actionDecorator(listSwaps),
 }
 
-func walletBalance(ctx *cli.Context) error {
-	ctxb := context.Background()
+func listSwaps(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.WalletBalanceRequest{}
-	resp, err := client.WalletBalance(ctxb, req)
+	req := &lnrpc.ListSwapsRequest{}
+
+	swaps, err := client.ListSwaps(context.Background(), req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(resp)
+	printRespJSON(swaps)
 	return nil
 }
 
-var channelBalanceCommand = cli.Command{
-	Name:     "channelbalance",
+var getChanInfoCommand = cli.Command{
+	Name:     "getchaninfo",
 	Category: "Channels",
-	Usage: "Returns the sum of the total available channel balance across " +
-		"all open channels.",
-	Action: actionDecorator(channelBalance),
+	Usage:    "Get the state of a channel.",
+	Description: "Prints out the latest authenticated state for a " +
+		"particular channel",
+	ArgsUsage: "chan_id",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "chan_id",
+			Usage: "the 8-byte compact channel ID to query for",
+		},
+	},
+	Action: actionDecorator(getChanInfo),
 }
 
-func channelBalance(ctx *cli.Context) error {
+func getChanInfo(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ChannelBalanceRequest{}
-	resp, err := client.ChannelBalance(ctxb, req)
+	var (
+		chanID int64
+		err    error
+	)
+
+	switch {
+	case ctx.IsSet("chan_id"):
+		chanID = ctx.Int64("chan_id")
+	case ctx.Args().Present():
+		chanID, err = strconv.ParseInt(ctx.Args().First(), 10, 64)
+	default:
+		return fmt.Errorf("chan_id argument missing")
+	}
+
+	req := &lnrpc.ChanInfoRequest{
+		ChanId: uint64(chanID),
+	}
+
+	chanInfo, err := client.GetChanInfo(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(resp)
+	printRespJSON(chanInfo)
 	return nil
 }
 
-var getInfoCommand = cli.Command{
-	Name:   "getinfo",
-	Usage:  "Returns basic information related to the active daemon.",
-	Action: actionDecorator(getInfo),
+var getNodeInfoCommand = cli.Command{
+	Name:     "getnodeinfo",
+	Category: "Peers",
+	Usage:    "Get information on a specific node.",
+	Description: "Prints out the latest authenticated node state for an " +
+		"advertised node",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "pub_key",
+			Usage: "the 33-byte hex-encoded compressed public of the target " +
+				"node",
+		},
+	},
+	Action: actionDecorator(getNodeInfo),
 }
 
-type chain struct {
-	Chain   string `json:"chain"`
-	Network string `json:"network"`
+func getNodeInfo(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	var pubKey string
+	switch {
+	case ctx.IsSet("pub_key"):
+		pubKey = ctx.String("pub_key")
+	case args.Present():
+		pubKey = args.First()
+	default:
+		return fmt.Errorf("pub_key argument missing")
+	}
+
+	req := &lnrpc.NodeInfoRequest{
+		PubKey: pubKey,
+	}
+
+	nodeInfo, err := client.GetNodeInfo(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(nodeInfo)
+	return nil
 }
 
-func getInfo(ctx *cli.Context) error {
+var queryRoutesCommand = cli.Command{
+	Name:        "queryroutes",
+	Category:    "Payments",
+	Usage:       "Query a route to a destination.",
+	Description: "Queries the channel router for a potential path to the destination that has sufficient flow for the amount including fees",
+	ArgsUsage:   "dest amt",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "dest",
+			Usage: "the 33-byte hex-encoded public key for the payment " +
+				"destination",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the amount to send expressed in satoshis",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "maximum fee allowed in satoshis when sending " +
+				"the payment",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "percentage of the payment's amount used as the " +
+				"maximum fee allowed when sending the payment",
+		},
+		cli.Int64Flag{
+			Name:  "num_max_routes",
+			Usage: "the max number of routes to be returned",
+			Value: 10,
+		},
+		cli.Int64Flag{
+			Name: "final_cltv_delta",
+			Usage: "(optional) number of blocks the last hop has to reveal " +
+				"the preimage",
+		},
+		cli.Uint64Flag{
+			Name: "cltv_limit",
+			Usage: "(optional) the maximum total time lock of the " +
+				"route, expressed as the number of blocks from " +
+				"the current height",
+		},
+		cli.BoolFlag{
+			Name: "explain",
+			Usage: "also return the most promising candidate routes " +
+				"that were rejected, along with the reason each " +
+				"was rejected",
+		},
+		cli.Uint64Flag{
+			Name: "num_rejected",
+			Usage: "the max number of rejected routes to be " +
+				"returned if --explain is set",
+			Value: 5,
+		},
+		feeWeightFlag,
+		riskFactorFlag,
+		probabilityWeightFlag,
+		holdTimeWeightFlag,
+	},
+	Action: actionDecorator(queryRoutes),
+}
+
+func queryRoutes(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.GetInfoRequest{}
-	resp, err := client.GetInfo(ctxb, req)
+	var (
+		dest string
+		amt  int64
+		err  error
+	)
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("dest"):
+		dest = ctx.String("dest")
+	case args.Present():
+		dest = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("dest argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("amt"):
+		amt = ctx.Int64("amt")
+	case args.Present():
+		amt, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt argument: %v", err)
+		}
+	default:
+		return fmt.Errorf("amt argument missing")
+	}
+
+	feeLimit, err := retrieveFeeLimit(ctx)
 	if err != nil {
 		return err
 	}
 
-	chains := make([]chain, len(resp.Chains))
-	for i, c := range resp.Chains {
-		chains[i] = chain{
-			Chain:   c.Chain,
-			Network: c.Network,
-		}
+	req := &lnrpc.QueryRoutesRequest{
+		PubKey:         dest,
+		Amt:            amt,
+		FeeLimit:       feeLimit,
+		NumRoutes:      int32(ctx.Int("num_max_routes")),
+		FinalCltvDelta: int32(ctx.Int("final_cltv_delta")),
+		CltvLimit:      uint32(ctx.Uint64("cltv_limit")),
+		Explain:        ctx.Bool("explain"),
+		NumRejected:    uint32(ctx.Uint64("num_rejected")),
+		PathWeights:    retrievePathWeights(ctx),
 	}
 
-	// We print a struct that mimics the proto definition of GetInfoResponse
-	// but has a better ordering for the same list of fields.
-	printJSON(struct {
-		Version             string   `json:"version"`
-		IdentityPubkey      string   `json:"identity_pubkey"`
-		Alias               string   `json:"alias"`
-		NumPendingChannels  uint32   `json:"num_pending_channels"`
-		NumActiveChannels   uint32   `json:"num_active_channels"`
-		NumInactiveChannels uint32   `json:"num_inactive_channels"`
-		NumPeers            uint32   `json:"num_peers"`
-		BlockHeight         uint32   `json:"block_height"`
-		BlockHash           string   `json:"block_hash"`
-		BestHeaderTimestamp int64    `json:"best_header_timestamp"`
-		SyncedToChain       bool     `json:"synced_to_chain"`
-		Testnet             bool     `json:"testnet"`
-		Chains              []chain  `json:"chains"`
-		Uris                []string `json:"uris"`
-	}{
-		Version:             resp.Version,
-		IdentityPubkey:      resp.IdentityPubkey,
-		Alias:               resp.Alias,
-		NumPendingChannels:  resp.NumPendingChannels,
-		NumActiveChannels:   resp.NumActiveChannels,
-		NumInactiveChannels: resp.NumInactiveChannels,
-		NumPeers:            resp.NumPeers,
-		BlockHeight:         resp.BlockHeight,
-		BlockHash:           resp.BlockHash,
-		BestHeaderTimestamp: resp.BestHeaderTimestamp,
-		SyncedToChain:       resp.SyncedToChain,
-		Testnet:             resp.Testnet,
-		Chains:              chains,
-		Uris:                resp.Uris,
-	})
+	route, err := client.QueryRoutes(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(route)
 	return nil
 }
 
-var pendingChannelsCommand = cli.Command{
-	Name:     "pendingchannels",
+var getNetworkInfoCommand = cli.Command{
+	Name:     "getnetworkinfo",
 	Category: "Channels",
-	Usage:    "Display information pertaining to pending channels.",
-	Action:   actionDecorator(pendingChannels),
+	Usage: "Get statistical information about the current " +
+		"state of the network.",
+	Description: "Returns a set of statistics pertaining to the known " +
+		"channel graph",
+	Action: actionDecorator(getNetworkInfo),
 }
 
-func pendingChannels(ctx *cli.Context) error {
+func getNetworkInfo(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.PendingChannelsRequest{}
-	resp, err := client.PendingChannels(ctxb, req)
+	req := &lnrpc.NetworkInfoRequest{}
+
+	netInfo, err := client.GetNetworkInfo(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(resp)
-
+	printRespJSON(netInfo)
 	return nil
 }
 
-var listChannelsCommand = cli.Command{
-	Name:     "listchannels",
-	Category: "Channels",
-	Usage:    "List all open channels.",
-	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name:  "active_only",
-			Usage: "only list channels which are currently active",
-		},
-		cli.BoolFlag{
-			Name:  "inactive_only",
-			Usage: "only list channels which are currently inactive",
-		},
-		cli.BoolFlag{
-			Name:  "public_only",
-			Usage: "only list channels which are currently public",
-		},
-		cli.BoolFlag{
-			Name:  "private_only",
-			Usage: "only list channels which are currently private",
-		},
-	},
-	Action: actionDecorator(listChannels),
+var gossipStatsCommand = cli.Command{
+	Name:     "gossipstats",
+	Category: "Peers",
+	Usage:    "Display the gossip traffic exchanged with each peer.",
+	Description: `
+	Returns the number of announcements received from each peer, how many
+	of them were applied to our graph or were already known, the number of
+	gossip queries of the peer we've replied to, and the number of gossip
+	bytes exchanged with it. Peers are ordered by the number of updates
+	applied, such that those contributing the most to our graph are
+	listed first.`,
+	Action: actionDecorator(gossipStats),
 }
 
-func listChannels(ctx *cli.Context) error {
+func gossipStats(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ListChannelsRequest{
-		ActiveOnly:   ctx.Bool("active_only"),
-		InactiveOnly: ctx.Bool("inactive_only"),
-		PublicOnly:   ctx.Bool("public_only"),
-		PrivateOnly:  ctx.Bool("private_only"),
-	}
-
-	resp, err := client.ListChannels(ctxb, req)
+	req := &lnrpc.GossipStatsRequest{}
+	resp, err := client.GossipStats(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	// TODO(roasbeef): defer close the client for the all
-
 	printRespJSON(resp)
-
 	return nil
 }
 
-var closedChannelsCommand = cli.Command{
-	Name:     "closedchannels",
-	Category: "Channels",
-	Usage:    "List all closed channels.",
+var updateGossipHorizonCommand = cli.Command{
+	Name:      "updategossiphorizon",
+	Category:  "Peers",
+	Usage:     "Change how far back the graph updates requested from peers reach.",
+	ArgsUsage: "horizon",
+	Description: `
+	Changes how far back in time the graph updates we request from the peers
+	we receive new graph updates from should reach. The horizon is given as a
+	duration, e.g. 24h to only request the graph updates of the last day,
+	which is suited for constrained nodes. Archival nodes can request the
+	full history of their peers by passing a horizon reaching back beyond the
+	unix epoch, e.g. 500000h. A horizon of 0 only requests new graph
+	updates.`,
 	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name:  "cooperative",
-			Usage: "list channels that were closed cooperatively",
-		},
-		cli.BoolFlag{
-			Name: "local_force",
-			Usage: "list channels that were force-closed " +
-				"by the local node",
-		},
-		cli.BoolFlag{
-			Name: "remote_force",
-			Usage: "list channels that were force-closed " +
-				"by the remote node",
-		},
-		cli.BoolFlag{
-			Name: "breach",
-			Usage: "list channels for which the remote node " +
-				"attempted to broadcast a prior " +
-				"revoked channel state",
-		},
-		cli.BoolFlag{
-			Name:  "funding_canceled",
-			Usage: "list channels that were never fully opened",
-		},
-		cli.BoolFlag{
-			Name: "abandoned",
-			Usage: "list channels that were abandoned by " +
-				"the local node",
+		cli.StringFlag{
+			Name: "horizon",
+			Usage: "the duration the requested graph updates " +
+				"should reach back, e.g. 24h",
 		},
 	},
-	Action: actionDecorator(closedChannels),
+	Action: actionDecorator(updateGossipHorizon),
 }
 
-func closedChannels(ctx *cli.Context) error {
+func updateGossipHorizon(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ClosedChannelsRequest{
-		Cooperative:     ctx.Bool("cooperative"),
-		LocalForce:      ctx.Bool("local_force"),
-		RemoteForce:     ctx.Bool("remote_force"),
-		Breach:          ctx.Bool("breach"),
-		FundingCanceled: ctx.Bool("funding_cancelled"),
-		Abandoned:       ctx.Bool("abandoned"),
+	var horizonStr string
+	switch {
+	case ctx.IsSet("horizon"):
+		horizonStr = ctx.String("horizon")
+	case ctx.Args().Present():
+		horizonStr = ctx.Args().First()
+	default:
+		return fmt.Errorf("horizon argument missing")
 	}
 
-	resp, err := client.ClosedChannels(ctxb, req)
+	horizon, err := time.ParseDuration(horizonStr)
+	if err != nil {
+		return fmt.Errorf("unable to parse horizon: %v", err)
+	}
+	if horizon < 0 {
+		return fmt.Errorf("horizon must be non-negative")
+	}
+
+	req := &lnrpc.UpdateGossipHorizonRequest{
+		HorizonSeconds: uint64(horizon / time.Second),
+	}
+	resp, err := client.UpdateGossipHorizon(ctxb, req)
 	if err != nil {
 		return err
 	}
 
 	printRespJSON(resp)
-
 	return nil
 }
 
-var cltvLimitFlag = cli.UintFlag{
-	Name: "cltv_limit",
-	Usage: "the maximum time lock that may be used for " +
-		"this payment",
-}
-
-var sendPaymentCommand = cli.Command{
-	Name:     "sendpayment",
-	Category: "Payments",
-	Usage:    "Send a payment over lightning.",
+var exportGraphCommand = cli.Command{
+	Name:     "exportgraph",
+	Category: "Peers",
+	Usage:    "Export a snapshot of the known channel graph.",
 	Description: `
-	Send a payment over Lightning. One can either specify the full
-	parameters of the payment, or just use a payment request which encodes
-	all the payment details.
-
-	If payment isn't manually specified, then only a payment request needs
-	to be passed using the --pay_req argument.
-
-	If the payment *is* manually specified, then all four alternative
-	arguments need to be specified in order to complete the payment:
-	    * --dest=N
-	    * --amt=A
-	    * --final_cltv_delta=T
-	    * --payment_hash=H
-
-	The --debug_send flag is provided for usage *purely* in test
-	environments. If specified, then the payment hash isn't required, as
-	it'll use the hash of all zeroes. This mode allows one to quickly test
-	payment connectivity without having to create an invoice at the
-	destination.
-	`,
-	ArgsUsage: "dest amt payment_hash final_cltv_delta | --pay_req=[payment request]",
+	Writes a compact binary snapshot of the node's entire view of the public
+	channel graph to the target file. The snapshot can be imported on a fresh
+	node using the importgraph command in order to skip the initial
+	historical gossip sync.
+
+	If --dump is set, the graph is instead written as a serialized GraphDump
+	protobuf message, which holds each gossip message in its wire encoding.`,
+	ArgsUsage: "output_file",
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name: "dest, d",
-			Usage: "the compressed identity pubkey of the " +
-				"payment recipient",
-		},
-		cli.Int64Flag{
-			Name:  "amt, a",
-			Usage: "number of satoshis to send",
-		},
-		cli.Int64Flag{
-			Name: "fee_limit",
-			Usage: "maximum fee allowed in satoshis when sending" +
-				"the payment",
-		},
-		cli.Int64Flag{
-			Name: "fee_limit_percent",
-			Usage: "percentage of the payment's amount used as the" +
-				"maximum fee allowed when sending the payment",
-		},
-		cltvLimitFlag,
-		cli.StringFlag{
-			Name:  "payment_hash, r",
-			Usage: "the hash to use within the payment's HTLC",
-		},
-		cli.BoolFlag{
-			Name:  "debug_send",
-			Usage: "use the debug rHash when sending the HTLC",
-		},
-		cli.StringFlag{
-			Name:  "pay_req",
-			Usage: "a zpay32 encoded payment request to fulfill",
-		},
-		cli.Int64Flag{
-			Name:  "final_cltv_delta",
-			Usage: "the number of blocks the last hop has to reveal the preimage",
-		},
-		cli.Uint64Flag{
-			Name: "outgoing_chan_id",
-			Usage: "short channel id of the outgoing channel to " +
-				"use for the first hop of the payment",
-			Value: 0,
+			Name:  "output_file",
+			Usage: "the file the graph snapshot should be written to",
 		},
-		cli.BoolFlag{
-			Name:  "force, f",
-			Usage: "will skip payment request confirmation",
-		},
-	},
-	Action: sendPayment,
-}
-
-// retrieveFeeLimit retrieves the fee limit based on the different fee limit
-// flags passed.
-func retrieveFeeLimit(ctx *cli.Context) (*lnrpc.FeeLimit, error) {
-	switch {
-	case ctx.IsSet("fee_limit") && ctx.IsSet("fee_limit_percent"):
-		return nil, fmt.Errorf("either fee_limit or fee_limit_percent " +
-			"can be set, but not both")
-	case ctx.IsSet("fee_limit"):
-		return &lnrpc.FeeLimit{
-			Limit: &lnrpc.FeeLimit_Fixed{
-				Fixed: ctx.Int64("fee_limit"),
-			},
-		}, nil
-	case ctx.IsSet("fee_limit_percent"):
-		return &lnrpc.FeeLimit{
-			Limit: &lnrpc.FeeLimit_Percent{
-				Percent: ctx.Int64("fee_limit_percent"),
-			},
-		}, nil
-	}
-
-	// Since the fee limit flags aren't required, we don't return an error
-	// if they're not set.
-	return nil, nil
+		cli.BoolFlag{
+			Name:  "dump",
+			Usage: "write the graph as a GraphDump message",
+		},
+	},
+	Action: actionDecorator(exportGraph),
 }
 
-func confirmPayReq(ctx *cli.Context, client lnrpc.LightningClient, payReq string) error {
+func exportGraph(ctx *cli.Context) error {
 	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-	req := &lnrpc.PayReqString{PayReq: payReq}
-	resp, err := client.DecodePayReq(ctxb, req)
-	if err != nil {
-		return err
+	var outputFile string
+	switch {
+	case ctx.IsSet("output_file"):
+		outputFile = ctx.String("output_file")
+	case ctx.Args().Present():
+		outputFile = ctx.Args().First()
+	default:
+		return fmt.Errorf("output_file argument missing")
 	}
 
-	// If the amount was not included in the invoice, then we let
-	// the payee specify the amount of satoshis they wish to send.
-	amt := resp.GetNumSatoshis()
-	if amt == 0 {
-		amt = ctx.Int64("amt")
-		if amt == 0 {
-			return fmt.Errorf("amount must be specified when " +
-				"paying a zero amount invoice")
+	if ctx.Bool("dump") {
+		dump, err := client.ExportGraphDump(
+			ctxb, &lnrpc.ExportGraphDumpRequest{},
+		)
+		if err != nil {
+			return err
 		}
-	}
 
-	fmt.Printf("Description: %v\n", resp.GetDescription())
-	fmt.Printf("Amount (in satoshis): %v\n", amt)
-	fmt.Printf("Destination: %v\n", resp.GetDestination())
+		dumpBytes, err := proto.Marshal(dump)
+		if err != nil {
+			return err
+		}
 
-	confirm := promptForConfirmation("Confirm payment (yes/no): ")
-	if !confirm {
-		return fmt.Errorf("payment not confirmed")
+		return ioutil.WriteFile(outputFile, dumpBytes, 0666)
 	}
 
-	return nil
+	snapshot, err := client.ExportGraphSnapshot(
+		ctxb, &lnrpc.ExportGraphSnapshotRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(outputFile, snapshot.Snapshot, 0666)
 }
 
-func sendPayment(ctx *cli.Context) error {
+var importGraphCommand = cli.Command{
+	Name:     "importgraph",
+	Category: "Peers",
+	Usage:    "Import a snapshot of the channel graph.",
+	Description: `
+	Imports a graph snapshot previously created with the exportgraph command.
+	Every message within the snapshot is validated before being added to the
+	channel graph. Once imported, the node will no longer attempt an initial
+	historical sync with its gossip peers.
+
+	If --dump is set, the file must instead contain a serialized GraphDump
+	protobuf message, e.g. as written by exportgraph --dump, or obtained from
+	an archive node. The dump is imported as a whole, after which the number
+	of channels, updates and nodes added to the graph is reported.`,
+	ArgsUsage: "input_file",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "input_file",
+			Usage: "the file containing the graph snapshot",
+		},
+		cli.BoolFlag{
+			Name:  "dump",
+			Usage: "read the graph from a GraphDump message",
+		},
+	},
+	Action: actionDecorator(importGraph),
+}
+
+func importGraph(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
-	// Show command help if no arguments provided
-	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
-		cli.ShowCommandHelp(ctx, "sendpayment")
-		return nil
+
+	var inputFile string
+	switch {
+	case ctx.IsSet("input_file"):
+		inputFile = ctx.String("input_file")
+	case ctx.Args().Present():
+		inputFile = ctx.Args().First()
+	default:
+		return fmt.Errorf("input_file argument missing")
 	}
 
-	// First, we'll retrieve the fee limit value passed since it can apply
-	// to both ways of sending payments (with the payment request or
-	// providing the details manually).
-	feeLimit, err := retrieveFeeLimit(ctx)
+	snapshot, err := ioutil.ReadFile(inputFile)
 	if err != nil {
 		return err
 	}
 
-	// If a payment request was provided, we can exit early since all of the
-	// details of the payment are encoded within the request.
-	if ctx.IsSet("pay_req") {
-		if !ctx.Bool("force") {
-			err = confirmPayReq(ctx, client, ctx.String("pay_req"))
-			if err != nil {
-				return err
-			}
-		}
-		req := &lnrpc.SendRequest{
-			PaymentRequest: ctx.String("pay_req"),
-			Amt:            ctx.Int64("amt"),
-			FeeLimit:       feeLimit,
-			OutgoingChanId: ctx.Uint64("outgoing_chan_id"),
-			CltvLimit:      uint32(ctx.Int(cltvLimitFlag.Name)),
+	if ctx.Bool("dump") {
+		var dump lnrpc.GraphDump
+		if err := proto.Unmarshal(snapshot, &dump); err != nil {
+			return fmt.Errorf("unable to decode graph dump: %v", err)
 		}
 
-		return sendPaymentRequest(client, req)
+		return importGraphDump(client, &dump)
 	}
 
-	var (
-		destNode []byte
-		amount   int64
-	)
-
-	args := ctx.Args()
-
-	switch {
-	case ctx.IsSet("dest"):
-		destNode, err = hex.DecodeString(ctx.String("dest"))
-	case args.Present():
-		destNode, err = hex.DecodeString(args.First())
-		args = args.Tail()
-	default:
-		return fmt.Errorf("destination txid argument missing")
-	}
+	resp, err := client.ImportGraphSnapshot(ctxb, &lnrpc.GraphSnapshot{
+		Snapshot: snapshot,
+	})
 	if err != nil {
 		return err
 	}
 
-	if len(destNode) != 33 {
-		return fmt.Errorf("dest node pubkey must be exactly 33 bytes, is "+
-			"instead: %v", len(destNode))
+	printRespJSON(resp)
+	return nil
+}
+
+// graphDumpPartSize is the maximum number of gossip messages sent within each
+// part of a graph dump, which keeps the parts well below the message size
+// limit of the gRPC server.
+const graphDumpPartSize = 5000
+
+// importGraphDump streams the passed graph dump to lnd in parts of at most
+// graphDumpPartSize messages, and prints the outcome of the import.
+func importGraphDump(client lnrpc.LightningClient,
+	dump *lnrpc.GraphDump) error {
+
+	stream, err := client.ImportGraphDump(context.Background())
+	if err != nil {
+		return err
 	}
 
-	if ctx.IsSet("amt") {
-		amount = ctx.Int64("amt")
-	} else if args.Present() {
-		amount, err = strconv.ParseInt(args.First(), 10, 64)
-		args = args.Tail()
-		if err != nil {
-			return fmt.Errorf("unable to decode payment amount: %v", err)
+	// nextPart removes up to n messages from the front of msgs, and
+	// returns them.
+	nextPart := func(msgs *[][]byte, n int) [][]byte {
+		if n > len(*msgs) {
+			n = len(*msgs)
 		}
+		part := (*msgs)[:n]
+		*msgs = (*msgs)[n:]
+		return part
 	}
 
-	req := &lnrpc.SendRequest{
-		Dest:     destNode,
-		Amt:      amount,
-		FeeLimit: feeLimit,
-	}
+	for {
+		var part lnrpc.GraphDump
+		remaining := graphDumpPartSize
 
-	if ctx.Bool("debug_send") && (ctx.IsSet("payment_hash") || args.Present()) {
-		return fmt.Errorf("do not provide a payment hash with debug send")
-	} else if !ctx.Bool("debug_send") {
-		var rHash []byte
+		part.ChannelAnnouncements = nextPart(
+			&dump.ChannelAnnouncements, remaining,
+		)
+		remaining -= len(part.ChannelAnnouncements)
 
-		switch {
-		case ctx.IsSet("payment_hash"):
-			rHash, err = hex.DecodeString(ctx.String("payment_hash"))
-		case args.Present():
-			rHash, err = hex.DecodeString(args.First())
-			args = args.Tail()
-		default:
-			return fmt.Errorf("payment hash argument missing")
-		}
+		part.ChannelUpdates = nextPart(&dump.ChannelUpdates, remaining)
+		remaining -= len(part.ChannelUpdates)
 
-		if err != nil {
-			return err
-		}
-		if len(rHash) != 32 {
-			return fmt.Errorf("payment hash must be exactly 32 "+
-				"bytes, is instead %v", len(rHash))
+		part.NodeAnnouncements = nextPart(
+			&dump.NodeAnnouncements, remaining,
+		)
+		remaining -= len(part.NodeAnnouncements)
+
+		if remaining == graphDumpPartSize {
+			break
 		}
-		req.PaymentHash = rHash
 
-		switch {
-		case ctx.IsSet("final_cltv_delta"):
-			req.FinalCltvDelta = int32(ctx.Int64("final_cltv_delta"))
-		case args.Present():
-			delta, err := strconv.ParseInt(args.First(), 10, 64)
-			if err != nil {
-				return err
-			}
-			req.FinalCltvDelta = int32(delta)
+		if err := stream.Send(&part); err != nil {
+			return err
 		}
 	}
 
-	return sendPaymentRequest(client, req)
-}
-
-func sendPaymentRequest(client lnrpc.LightningClient, req *lnrpc.SendRequest) error {
-	paymentStream, err := client.SendPayment(context.Background())
+	resp, err := stream.CloseAndRecv()
 	if err != nil {
 		return err
 	}
 
-	if err := paymentStream.Send(req); err != nil {
-		return err
+	printRespJSON(resp)
+	return nil
+}
+
+var debugLevelCommand = cli.Command{
+	Name:  "debuglevel",
+	Usage: "Set the debug level.",
+	Description: `Logging level for all subsystems {trace, debug, info, warn, error, critical, off}
+	You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems
+
+	Use show to list available subsystems`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "show",
+			Usage: "if true, then the list of available sub-systems will be printed out",
+		},
+		cli.StringFlag{
+			Name:  "level",
+			Usage: "the level specification to target either a coarse logging level, or granular set of specific sub-systems with logging levels for each",
+		},
+	},
+	Action: actionDecorator(debugLevel),
+}
+
+func debugLevel(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+	req := &lnrpc.DebugLevelRequest{
+		Show:      ctx.Bool("show"),
+		LevelSpec: ctx.String("level"),
 	}
 
-	resp, err := paymentStream.Recv()
+	resp, err := client.DebugLevel(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	paymentStream.CloseSend()
+	printRespJSON(resp)
+	return nil
+}
 
-	printJSON(struct {
-		E string       `json:"payment_error"`
-		P string       `json:"payment_preimage"`
-		R *lnrpc.Route `json:"payment_route"`
-	}{
-		E: resp.PaymentError,
-		P: hex.EncodeToString(resp.PaymentPreimage),
-		R: resp.PaymentRoute,
-	})
+var compactDBCommand = cli.Command{
+	Name:  "compactdb",
+	Usage: "Compact the channel database.",
+	Description: `
+	Compact lnd's channel.db, reclaiming the space of its free pages. As
+	bbolt never shrinks its file, this is the only way to reclaim the space
+	of deleted data, such as closed channels and pruned graph entries.
+
+	All access to the database is held back while it's being compacted,
+	which may stall the node for a while if the database is large. Use
+	--dry_run to only report the size and the ratio of free pages of the
+	database.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "dry_run",
+			Usage: "only report the size and free page ratio of " +
+				"the database",
+		},
+	},
+	Action: actionDecorator(compactDB),
+}
 
-	// If we get a payment error back, we pass an error
-	// up to main which eventually calls fatal() and returns
-	// with a non-zero exit code.
-	if resp.PaymentError != "" {
-		return errors.New(resp.PaymentError)
+func compactDB(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+	req := &lnrpc.CompactDatabaseRequest{
+		DryRun: ctx.Bool("dry_run"),
+	}
+
+	resp, err := client.CompactDatabase(ctxb, req)
+	if err != nil {
+		return err
 	}
 
+	printRespJSON(resp)
 	return nil
 }
 
-var payInvoiceCommand = cli.Command{
-	Name:      "payinvoice",
-	Category:  "Payments",
-	Usage:     "Pay an invoice over lightning.",
-	ArgsUsage: "pay_req",
-	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name:  "pay_req",
-			Usage: "a zpay32 encoded payment request to fulfill",
-		},
-		cli.Int64Flag{
-			Name: "amt",
-			Usage: "(optional) number of satoshis to fulfill the " +
-				"invoice",
-		},
-		cli.Int64Flag{
-			Name: "fee_limit",
-			Usage: "maximum fee allowed in satoshis when sending " +
-				"the payment",
-		},
-		cli.Int64Flag{
-			Name: "fee_limit_percent",
-			Usage: "percentage of the payment's amount used as the" +
-				"maximum fee allowed when sending the payment",
-		},
-		cltvLimitFlag,
-		cli.Uint64Flag{
-			Name: "outgoing_chan_id",
-			Usage: "short channel id of the outgoing channel to " +
-				"use for the first hop of the payment",
-			Value: 0,
-		},
+var checkDBCommand = cli.Command{
+	Name:  "checkdb",
+	Usage: "Check the integrity of the channel database.",
+	Description: `
+	Scan lnd's channel.db for orphaned HTLCs, dangling graph edges, missing
+	revocation logs and invalid invoice indexes, and list the
+	inconsistencies found.
+
+	Use --repair to also repair the inconsistencies that can be repaired
+	without losing data that is still needed, such as by deleting orphaned
+	records and restoring missing index entries. The same check can be run
+	without starting the daemon through lnd --check-db or --repair-db.`,
+	Flags: []cli.Flag{
 		cli.BoolFlag{
-			Name:  "force, f",
-			Usage: "will skip payment request confirmation",
+			Name:  "repair",
+			Usage: "repair the inconsistencies that can be repaired",
 		},
 	},
-	Action: actionDecorator(payInvoice),
+	Action: actionDecorator(checkDB),
 }
 
-func payInvoice(ctx *cli.Context) error {
-	args := ctx.Args()
+func checkDB(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
-
-	var payReq string
-	switch {
-	case ctx.IsSet("pay_req"):
-		payReq = ctx.String("pay_req")
-	case args.Present():
-		payReq = args.First()
-	default:
-		return fmt.Errorf("pay_req argument missing")
+	req := &lnrpc.CheckDatabaseRequest{
+		Repair: ctx.Bool("repair"),
 	}
 
-	feeLimit, err := retrieveFeeLimit(ctx)
+	resp, err := client.CheckDatabase(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	if !ctx.Bool("force") {
-		err = confirmPayReq(ctx, client, payReq)
-		if err != nil {
-			return err
-		}
-	}
-
-	req := &lnrpc.SendRequest{
-		PaymentRequest: payReq,
-		Amt:            ctx.Int64("amt"),
-		FeeLimit:       feeLimit,
-		OutgoingChanId: ctx.Uint64("outgoing_chan_id"),
-		CltvLimit:      uint32(ctx.Int(cltvLimitFlag.Name)),
-	}
-
-	return sendPaymentRequest(client, req)
+	printRespJSON(resp)
+	return nil
 }
 
-var sendToRouteCommand = cli.Command{
-	Name:  "sendtoroute",
-	Usage: "send a payment over a predefined route",
-	Description: `
-	Send a payment over Lightning using a specific route. One must specify
-	a list of routes to attempt and the payment hash. This command can even
-	be chained with the response to queryroutes. This command can be used
-	to implement channel rebalancing by crafting a self-route, or even
-	atomic swaps using a self-route that crosses multiple chains.
-
-	There are three ways to specify routes:
-	   * using the --routes parameter to manually specify a JSON encoded
-	     set of routes in the format of the return value of queryroutes:
-	         (lncli sendtoroute --payment_hash=<pay_hash> --routes=<route>)
-
-	   * passing the routes as a positional argument:
-	         (lncli sendtoroute --payment_hash=pay_hash <route>)
-
-	   * or reading in the routes from stdin, which can allow chaining the
-	     response from queryroutes, or even read in a file with a set of
-	     pre-computed routes:
-	         (lncli queryroutes --args.. | lncli sendtoroute --payment_hash= -
-
-	     notice the '-' at the end, which signals that lncli should read
-	     the route in from stdin
-	`,
+var decodePayReqCommand = cli.Command{
+	Name:        "decodepayreq",
+	Category:    "Payments",
+	Usage:       "Decode a payment request.",
+	Description: "Decode the passed payment request revealing the destination, payment hash and value of the payment request",
+	ArgsUsage:   "pay_req",
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name:  "payment_hash, pay_hash",
-			Usage: "the hash to use within the payment's HTLC",
-		},
-		cli.StringFlag{
-			Name: "routes, r",
-			Usage: "a json array string in the format of the response " +
-				"of queryroutes that denotes which routes to use",
+			Name:  "pay_req",
+			Usage: "the bech32 encoded payment request",
 		},
 	},
-	Action: sendToRoute,
+	Action: actionDecorator(decodePayReq),
 }
 
-func sendToRoute(ctx *cli.Context) error {
-	// Show command help if no arguments provided.
-	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
-		cli.ShowCommandHelp(ctx, "sendtoroute")
-		return nil
-	}
+func decodePayReq(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-	args := ctx.Args()
+	var payreq string
 
-	var (
-		rHash []byte
-		err   error
-	)
 	switch {
-	case ctx.IsSet("payment_hash"):
-		rHash, err = hex.DecodeString(ctx.String("payment_hash"))
-	case args.Present():
-		rHash, err = hex.DecodeString(args.First())
-
-		args = args.Tail()
+	case ctx.IsSet("pay_req"):
+		payreq = ctx.String("pay_req")
+	case ctx.Args().Present():
+		payreq = ctx.Args().First()
 	default:
-		return fmt.Errorf("payment hash argument missing")
+		return fmt.Errorf("pay_req argument missing")
 	}
 
+	resp, err := client.DecodePayReq(ctxb, &lnrpc.PayReqString{
+		PayReq: payreq,
+	})
 	if err != nil {
 		return err
 	}
 
-	if len(rHash) != 32 {
-		return fmt.Errorf("payment hash must be exactly 32 "+
-			"bytes, is instead %d", len(rHash))
-	}
-
-	var jsonRoutes string
-	switch {
-	// The user is specifying the routes explicitly via the key word
-	// argument.
-	case ctx.IsSet("routes"):
-		jsonRoutes = ctx.String("routes")
+	printRespJSON(resp)
+	return nil
+}
 
-	// The user is specifying the routes as a positional argument.
-	case args.Present() && args.First() != "-":
-		jsonRoutes = args.First()
+var listChainTxnsCommand = cli.Command{
+	Name:        "listchaintxns",
+	Category:    "On-chain",
+	Usage:       "List transactions from the wallet.",
+	Description: "List all transactions an address of the wallet was involved in.",
+	Action:      actionDecorator(listChainTxns),
+}
 
-	// The user is signalling that we should read stdin in order to parse
-	// the set of target routes.
-	case args.Present() && args.First() == "-":
-		b, err := ioutil.ReadAll(os.Stdin)
-		if err != nil {
-			return err
-		}
-		if len(b) == 0 {
-			return fmt.Errorf("queryroutes output is empty")
-		}
+func listChainTxns(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-		jsonRoutes = string(b)
-	}
+	resp, err := client.GetTransactions(ctxb, &lnrpc.GetTransactionsRequest{})
 
-	routes := &lnrpc.QueryRoutesResponse{}
-	err = jsonpb.UnmarshalString(jsonRoutes, routes)
 	if err != nil {
-		return fmt.Errorf("unable to unmarshal json string "+
-			"from incoming array of routes: %v", err)
+		return err
 	}
 
-	req := &lnrpc.SendToRouteRequest{
-		PaymentHash: rHash,
-		Routes:      routes.Routes,
-	}
+	printRespJSON(resp)
+	return nil
+}
 
-	return sendToRouteRequest(ctx, req)
+var listSubsystemsCommand = cli.Command{
+	Name:  "listsubsystems",
+	Usage: "List the status of all controllable subsystems.",
+	Description: `
+	Returns whether each subsystem that can be stopped and started through
+	modifysubsystem is available and currently running.`,
+	Action: actionDecorator(listSubsystems),
 }
 
-func sendToRouteRequest(ctx *cli.Context, req *lnrpc.SendToRouteRequest) error {
+func listSubsystems(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	paymentStream, err := client.SendToRoute(context.Background())
-	if err != nil {
-		return err
-	}
-
-	if err := paymentStream.Send(req); err != nil {
-		return err
-	}
-
-	resp, err := paymentStream.Recv()
+	resp, err := client.ListSubsystems(ctxb, &lnrpc.ListSubsystemsRequest{})
 	if err != nil {
 		return err
 	}
 
-	printJSON(struct {
-		E string       `json:"payment_error"`
-		P string       `json:"payment_preimage"`
-		R *lnrpc.Route `json:"payment_route"`
-	}{
-		E: resp.PaymentError,
-		P: hex.EncodeToString(resp.PaymentPreimage),
-		R: resp.PaymentRoute,
-	})
-
+	printRespJSON(resp)
 	return nil
 }
 
-var addInvoiceCommand = cli.Command{
-	Name:     "addinvoice",
-	Category: "Payments",
-	Usage:    "Add a new invoice.",
+var modifySubsystemCommand = cli.Command{
+	Name:      "modifysubsystem",
+	Usage:     "Stop or start a single subsystem at runtime.",
+	ArgsUsage: "subsystem",
 	Description: `
-	Add a new invoice, expressing intent for a future payment.
+	Stop or start a single subsystem without shutting down the daemon, e.g.
+	for incident response. The subsystem must be one of:
 
-	Invoices without an amount can be created by not supplying any
-	parameters or providing an amount of 0. These invoices allow the payee
-	to specify the amount of satoshis they wish to send.`,
-	ArgsUsage: "value preimage",
+	 * autopilot
+	 * watchtower
+	 * watchtower_client
+	 * gossip_active_sync
+
+	Requests resulting in an unsafe combination of running subsystems, such
+	as running autopilot without active gossip syncing, are rejected.
+
+	Positional arguments and flags can be used interchangeably but not at the same time!`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name: "memo",
-			Usage: "a description of the payment to attach along " +
-				"with the invoice (default=\"\")",
-		},
-		cli.StringFlag{
-			Name:  "receipt",
-			Usage: "an optional cryptographic receipt of payment",
-		},
-		cli.StringFlag{
-			Name: "preimage",
-			Usage: "the hex-encoded preimage (32 byte) which will " +
-				"allow settling an incoming HTLC payable to this " +
-				"preimage. If not set, a random preimage will be " +
-				"created.",
-		},
-		cli.Int64Flag{
-			Name:  "amt",
-			Usage: "the amt of satoshis in this invoice",
-		},
-		cli.StringFlag{
-			Name: "description_hash",
-			Usage: "SHA-256 hash of the description of the payment. " +
-				"Used if the purpose of payment cannot naturally " +
-				"fit within the memo. If provided this will be " +
-				"used instead of the description(memo) field in " +
-				"the encoded invoice.",
-		},
-		cli.StringFlag{
-			Name: "fallback_addr",
-			Usage: "fallback on-chain address that can be used in " +
-				"case the lightning payment fails",
-		},
-		cli.Int64Flag{
-			Name: "expiry",
-			Usage: "the invoice's expiry time in seconds. If not " +
-				"specified an expiry of 3600 seconds (1 hour) " +
-				"is implied.",
-		},
-		cli.BoolTFlag{
-			Name: "private",
-			Usage: "encode routing hints in the invoice with " +
-				"private channels in order to assist the " +
-				"payer in reaching you",
+			Name:  "subsystem",
+			Usage: "the subsystem to stop or start",
 		},
-	},
-	Action: actionDecorator(addInvoice),
-}
-
-func addInvoice(ctx *cli.Context) error {
-	var (
-		preimage []byte
-		descHash []byte
-		receipt  []byte
-		amt      int64
-		err      error
-	)
+		cli.BoolFlag{
+			Name:  "enable",
+			Usage: "start the subsystem",
+		},
+		cli.BoolFlag{
+			Name:  "disable",
+			Usage: "stop the subsystem",
+		},
+	},
+	Action: actionDecorator(modifySubsystem),
+}
 
+func modifySubsystem(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
+	var subsystemName string
 	args := ctx.Args()
 
 	switch {
-	case ctx.IsSet("amt"):
-		amt = ctx.Int64("amt")
+	case ctx.IsSet("subsystem"):
+		subsystemName = ctx.String("subsystem")
 	case args.Present():
-		amt, err = strconv.ParseInt(args.First(), 10, 64)
-		args = args.Tail()
-		if err != nil {
-			return fmt.Errorf("unable to decode amt argument: %v", err)
-		}
+		subsystemName = args.First()
+	default:
+		return fmt.Errorf("subsystem argument missing")
 	}
 
-	switch {
-	case ctx.IsSet("preimage"):
-		preimage, err = hex.DecodeString(ctx.String("preimage"))
-	case args.Present():
-		preimage, err = hex.DecodeString(args.First())
+	subsystem, ok := lnrpc.Subsystem_value[strings.ToUpper(subsystemName)]
+	if !ok {
+		return fmt.Errorf("unknown subsystem: %v", subsystemName)
 	}
 
-	if err != nil {
-		return fmt.Errorf("unable to parse preimage: %v", err)
+	if ctx.Bool("enable") == ctx.Bool("disable") {
+		return fmt.Errorf("exactly one of --enable or --disable must " +
+			"be set")
 	}
 
-	descHash, err = hex.DecodeString(ctx.String("description_hash"))
+	req := &lnrpc.ModifySubsystemRequest{
+		Subsystem: lnrpc.Subsystem(subsystem),
+		Enable:    ctx.Bool("enable"),
+	}
+	resp, err := client.ModifySubsystem(ctxb, req)
 	if err != nil {
-		return fmt.Errorf("unable to parse description_hash: %v", err)
+		return err
 	}
 
-	receipt, err = hex.DecodeString(ctx.String("receipt"))
+	printRespJSON(resp)
+	return nil
+}
+
+var getResourceBudgetCommand = cli.Command{
+	Name:  "getresourcebudget",
+	Usage: "Display the resource profile and the bounds in effect.",
+	Description: `
+	Returns the resource profile currently selected, along with the bounds
+	it places on worker pools, caches, gossip batch sizes and database batch
+	intervals.`,
+	Action: actionDecorator(getResourceBudget),
+}
+
+func getResourceBudget(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetResourceBudget(
+		ctxb, &lnrpc.GetResourceBudgetRequest{},
+	)
 	if err != nil {
-		return fmt.Errorf("unable to parse receipt: %v", err)
+		return err
 	}
 
-	invoice := &lnrpc.Invoice{
-		Memo:            ctx.String("memo"),
-		Receipt:         receipt,
-		RPreimage:       preimage,
-		Value:           amt,
-		DescriptionHash: descHash,
-		FallbackAddr:    ctx.String("fallback_addr"),
-		Expiry:          ctx.Int64("expiry"),
-		Private:         ctx.Bool("private"),
+	printRespJSON(resp)
+	return nil
+}
+
+var setResourceProfileCommand = cli.Command{
+	Name:      "setresourceprofile",
+	Usage:     "Select a different resource profile at runtime.",
+	ArgsUsage: "profile",
+	Description: `
+	Select the resource profile bounding worker pools, caches, gossip batch
+	sizes and database batch intervals. The profile must be one of:
+
+	 * default
+	 * raspberry-pi
+	 * server
+
+	Cache sizes, the gossip batch size and the database batch interval
+	take effect right away. Worker pools only adopt the profile once lnd is
+	restarted with --resourceprofile set, which is also required for the
+	selection to persist across restarts.`,
+	Action: actionDecorator(setResourceProfile),
+}
+
+func setResourceProfile(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.Args().Present() {
+		return fmt.Errorf("profile argument missing")
 	}
 
-	resp, err := client.AddInvoice(context.Background(), invoice)
+	req := &lnrpc.SetResourceProfileRequest{
+		Profile: ctx.Args().First(),
+	}
+	resp, err := client.SetResourceProfile(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printJSON(struct {
-		RHash    string `json:"r_hash"`
-		PayReq   string `json:"pay_req"`
-		AddIndex uint64 `json:"add_index"`
-	}{
-		RHash:    hex.EncodeToString(resp.RHash),
-		PayReq:   resp.PaymentRequest,
-		AddIndex: resp.AddIndex,
-	})
+	printRespJSON(resp)
+	return nil
+}
+
+var stopCommand = cli.Command{
+	Name:  "stop",
+	Usage: "Stop and shutdown the daemon.",
+	Description: `
+	Gracefully stop all daemon subsystems before stopping the daemon itself.
+	This is equivalent to stopping it using CTRL-C.`,
+	Action: actionDecorator(stopDaemon),
+}
+
+func stopDaemon(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	_, err := client.StopDaemon(ctxb, &lnrpc.StopRequest{})
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-var lookupInvoiceCommand = cli.Command{
-	Name:      "lookupinvoice",
-	Category:  "Payments",
-	Usage:     "Lookup an existing invoice by its payment hash.",
-	ArgsUsage: "rhash",
+var rotateNodeKeyCommand = cli.Command{
+	Name:      "rotatenodekey",
+	Usage:     "Rotate the node's identity key on the next restart.",
+	ArgsUsage: "current_pubkey",
+	Description: `
+	Request the node's identity key to be replaced with a freshly derived
+	one. As channels are bound to the identity key, the node must not have
+	any channels, and the rotation is aborted if any are opened before the
+	daemon is restarted. The new key takes effect on the next restart.
+
+	To guard against accidental rotations, the current identity key must be
+	passed in. The returned signatures by both the old and new key prove
+	that the new identity is operated by the same node, and can be
+	published to peers.
+
+	Positional arguments and flags can be used interchangeably but not at the same time!`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name: "rhash",
-			Usage: "the 32 byte payment hash of the invoice to query for, the hash " +
-				"should be a hex-encoded string",
+			Name:  "current_pubkey",
+			Usage: "the current identity pubkey of the node",
+		},
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "skip the confirmation prompt",
 		},
 	},
-	Action: actionDecorator(lookupInvoice),
+	Action: actionDecorator(rotateNodeKey),
 }
 
-func lookupInvoice(ctx *cli.Context) error {
+func rotateNodeKey(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var (
-		rHash []byte
-		err   error
-	)
+	var currentPubKey string
+	args := ctx.Args()
 
 	switch {
-	case ctx.IsSet("rhash"):
-		rHash, err = hex.DecodeString(ctx.String("rhash"))
-	case ctx.Args().Present():
-		rHash, err = hex.DecodeString(ctx.Args().First())
+	case ctx.IsSet("current_pubkey"):
+		currentPubKey = ctx.String("current_pubkey")
+	case args.Present():
+		currentPubKey = args.First()
 	default:
-		return fmt.Errorf("rhash argument missing")
-	}
-
-	if err != nil {
-		return fmt.Errorf("unable to decode rhash argument: %v", err)
+		return fmt.Errorf("current_pubkey argument missing")
 	}
 
-	req := &lnrpc.PaymentHash{
-		RHash: rHash,
+	if !ctx.Bool("force") {
+		msg := "Rotate the node identity key? The node will be " +
+			"known under a new identity after the next restart. " +
+			"(yes/no): "
+		if !promptForConfirmation(msg) {
+			return nil
+		}
 	}
 
-	invoice, err := client.LookupInvoice(context.Background(), req)
+	resp, err := client.RotateNodeKey(ctxb, &lnrpc.RotateNodeKeyRequest{
+		CurrentPubKey: currentPubKey,
+	})
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(invoice)
-
+	printRespJSON(resp)
 	return nil
 }
 
-var listInvoicesCommand = cli.Command{
-	Name:     "listinvoices",
-	Category: "Payments",
-	Usage: "List all invoices currently stored within the database. Any " +
-		"active debug invoices are ignored.",
+var listNodeKeyRotationsCommand = cli.Command{
+	Name:  "listnodekeyrotations",
+	Usage: "List all requested node identity key rotations.",
 	Description: `
-	This command enables the retrieval of all invoices currently stored
-	within the database. It has full support for paginationed responses,
-	allowing users to query for specific invoices through their add_index.
-	This can be done by using either the first_index_offset or
-	last_index_offset fields included in the response as the index_offset of
-	the next request. The reversed flag is set by default in order to
-	paginate backwards. If you wish to paginate forwards, you must
-	explicitly set the flag to false. If none of the parameters are
-	specified, then the last 100 invoices will be returned.
-
-	For example: if you have 200 invoices, "lncli listinvoices" will return
-	the last 100 created. If you wish to retrieve the previous 100, the
-	first_offset_index of the response can be used as the index_offset of
-	the next listinvoices request.`,
-	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name: "pending_only",
-			Usage: "toggles if all invoices should be returned, " +
-				"or only those that are currently unsettled",
-		},
-		cli.Uint64Flag{
-			Name: "index_offset",
-			Usage: "the index of an invoice that will be used as " +
-				"either the start or end of a query to " +
-				"determine which invoices should be returned " +
-				"in the response",
-		},
-		cli.Uint64Flag{
-			Name:  "max_invoices",
-			Usage: "the max number of invoices to return",
-		},
-		cli.BoolTFlag{
-			Name: "reversed",
-			Usage: "if set, the invoices returned precede the " +
-				"given index_offset, allowing backwards " +
-				"pagination",
-		},
-	},
-	Action: actionDecorator(listInvoices),
+	Returns the audit log of all node identity key rotations, along with the
+	signatures linking each new identity key to the prior one.`,
+	Action: actionDecorator(listNodeKeyRotations),
 }
 
-func listInvoices(ctx *cli.Context) error {
+func listNodeKeyRotations(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ListInvoiceRequest{
-		PendingOnly:    ctx.Bool("pending_only"),
-		IndexOffset:    ctx.Uint64("index_offset"),
-		NumMaxInvoices: ctx.Uint64("max_invoices"),
-		Reversed:       ctx.Bool("reversed"),
-	}
-
-	invoices, err := client.ListInvoices(context.Background(), req)
+	req := &lnrpc.ListNodeKeyRotationsRequest{}
+	resp, err := client.ListNodeKeyRotations(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(invoices)
-
+	printRespJSON(resp)
 	return nil
 }
 
-var describeGraphCommand = cli.Command{
-	Name:     "describegraph",
-	Category: "Peers",
-	Description: "Prints a human readable version of the known channel " +
-		"graph from the PoV of the node",
-	Usage: "Describe the network graph.",
+var signMessageCommand = cli.Command{
+	Name:      "signmessage",
+	Category:  "Wallet",
+	Usage:     "Sign a message with the node's private key.",
+	ArgsUsage: "msg",
+	Description: `
+	Sign msg with the resident node's private key.
+	Returns the signature as a zbase32 string.
+
+	Positional arguments and flags can be used interchangeably but not at the same time!`,
 	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name: "include_unannounced",
-			Usage: "If set, unannounced channels will be included in the " +
-				"graph. Unannounced channels are both private channels, and " +
-				"public channels that are not yet announced to the network.",
+		cli.StringFlag{
+			Name:  "msg",
+			Usage: "the message to sign",
 		},
 	},
-	Action: actionDecorator(describeGraph),
+	Action: actionDecorator(signMessage),
 }
 
-func describeGraph(ctx *cli.Context) error {
+func signMessage(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ChannelGraphRequest{
-		IncludeUnannounced: ctx.Bool("include_unannounced"),
+	var msg []byte
+
+	switch {
+	case ctx.IsSet("msg"):
+		msg = []byte(ctx.String("msg"))
+	case ctx.Args().Present():
+		msg = []byte(ctx.Args().First())
+	default:
+		return fmt.Errorf("msg argument missing")
 	}
 
-	graph, err := client.DescribeGraph(context.Background(), req)
+	resp, err := client.SignMessage(ctxb, &lnrpc.SignMessageRequest{Msg: msg})
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(graph)
+	printRespJSON(resp)
 	return nil
 }
 
-// normalizeFunc is a factory function which returns a function that normalizes
-// the capacity of edges within the graph. The value of the returned
-// function can be used to either plot the capacities, or to use a weight in a
-// rendering of the graph.
-func normalizeFunc(edges []*lnrpc.ChannelEdge, scaleFactor float64) func(int64) float64 {
+var verifyMessageCommand = cli.Command{
+	Name:      "verifymessage",
+	Category:  "Wallet",
+	Usage:     "Verify a message signed with the signature.",
+	ArgsUsage: "msg signature",
+	Description: `
+	Verify that the message was signed with a properly-formed signature
+	The signature must be zbase32 encoded and signed with the private key of
+	an active node in the resident node's channel database.
+
+	Positional arguments and flags can be used interchangeably but not at the same time!`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "msg",
+			Usage: "the message to verify",
+		},
+		cli.StringFlag{
+			Name:  "sig",
+			Usage: "the zbase32 encoded signature of the message",
+		},
+	},
+	Action: actionDecorator(verifyMessage),
+}
+
+func verifyMessage(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
 	var (
-		min float64 = math.MaxInt64
-		max float64
+		msg []byte
+		sig string
 	)
 
-	for _, edge := range edges {
-		// In order to obtain saner values, we reduce the capacity of a
-		// channel to its base 2 logarithm.
-		z := math.Log2(float64(edge.Capacity))
+	args := ctx.Args()
 
-		if z < min {
-			min = z
-		}
-		if z > max {
-			max = z
-		}
+	switch {
+	case ctx.IsSet("msg"):
+		msg = []byte(ctx.String("msg"))
+	case args.Present():
+		msg = []byte(ctx.Args().First())
+		args = args.Tail()
+	default:
+		return fmt.Errorf("msg argument missing")
 	}
 
-	return func(x int64) float64 {
-		y := math.Log2(float64(x))
+	switch {
+	case ctx.IsSet("sig"):
+		sig = ctx.String("sig")
+	case args.Present():
+		sig = args.First()
+	default:
+		return fmt.Errorf("signature argument missing")
+	}
 
-		// TODO(roasbeef): results in min being zero
-		return (y - min) / (max - min) * scaleFactor
+	req := &lnrpc.VerifyMessageRequest{Msg: msg, Signature: sig}
+	resp, err := client.VerifyMessage(ctxb, req)
+	if err != nil {
+		return err
 	}
+
+	printRespJSON(resp)
+	return nil
 }
 
-var listPaymentsCommand = cli.Command{
-	Name:     "listpayments",
-	Category: "Payments",
-	Usage:    "List all outgoing payments.",
-	Action:   actionDecorator(listPayments),
+var feeReportCommand = cli.Command{
+	Name:     "feereport",
+	Category: "Channels",
+	Usage:    "Display the current fee policies of all active channels.",
+	Description: `
+	Returns the current fee policies of all active channels.
+	Fee policies can be updated using the updatechanpolicy command.`,
+	Action: actionDecorator(feeReport),
 }
 
-func listPayments(ctx *cli.Context) error {
+func feeReport(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ListPaymentsRequest{}
+	unit, err := parseDisplayUnit(ctx)
+	if err != nil {
+		return err
+	}
 
-	payments, err := client.ListPayments(context.Background(), req)
+	req := &lnrpc.FeeReportRequest{}
+	resp, err := client.FeeReport(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(payments)
+	if unit != nil {
+		printJSON(NewFeeReportFromProto(resp, unit))
+		return nil
+	}
+
+	printRespJSON(resp)
 	return nil
 }
 
-var getChanInfoCommand = cli.Command{
-	Name:     "getchaninfo",
+var updateChannelPolicyCommand = cli.Command{
+	Name:     "updatechanpolicy",
 	Category: "Channels",
-	Usage:    "Get the state of a channel.",
-	Description: "Prints out the latest authenticated state for a " +
-		"particular channel",
-	ArgsUsage: "chan_id",
+	Usage: "Update the channel policy for all channels, or a single " +
+		"channel.",
+	ArgsUsage: "base_fee_msat fee_rate time_lock_delta [channel_point]",
+	Description: `
+	Updates the channel policy for all channels, or just a particular channel
+	identified by its channel point. The update will be committed, and
+	broadcast to the rest of the network within the next batch.
+	Channel points are encoded as: funding_txid:output_index`,
 	Flags: []cli.Flag{
 		cli.Int64Flag{
-			Name:  "chan_id",
-			Usage: "the 8-byte compact channel ID to query for",
+			Name: "base_fee_msat",
+			Usage: "the base fee in milli-satoshis that will " +
+				"be charged for each forwarded HTLC, regardless " +
+				"of payment size",
+		},
+		cli.StringFlag{
+			Name: "fee_rate",
+			Usage: "the fee rate that will be charged " +
+				"proportionally based on the value of each " +
+				"forwarded HTLC, the lowest possible rate is 0.000001",
+		},
+		cli.Int64Flag{
+			Name: "time_lock_delta",
+			Usage: "the CLTV delta that will be applied to all " +
+				"forwarded HTLCs",
+		},
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "The channel whose fee policy should be " +
+				"updated, if nil the policies for all channels " +
+				"will be updated. Takes the form of: txid:output_index",
 		},
 	},
-	Action: actionDecorator(getChanInfo),
+	Action: actionDecorator(updateChannelPolicy),
 }
 
-func getChanInfo(ctx *cli.Context) error {
-	ctxb := context.Background()
-	client, cleanUp := getClient(ctx)
-	defer cleanUp()
-
-	var (
-		chanID int64
-		err    error
-	)
-
-	switch {
-	case ctx.IsSet("chan_id"):
-		chanID = ctx.Int64("chan_id")
-	case ctx.Args().Present():
-		chanID, err = strconv.ParseInt(ctx.Args().First(), 10, 64)
-	default:
-		return fmt.Errorf("chan_id argument missing")
+func parseChanPoint(s string) (*lnrpc.ChannelPoint, error) {
+	split := strings.Split(s, ":")
+	if len(split) != 2 {
+		return nil, fmt.Errorf("expecting chan_point to be in format of: " +
+			"txid:index")
 	}
 
-	req := &lnrpc.ChanInfoRequest{
-		ChanId: uint64(chanID),
+	index, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode output index: %v", err)
 	}
 
-	chanInfo, err := client.GetChanInfo(ctxb, req)
+	txid, err := chainhash.NewHashFromStr(split[0])
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("unable to parse hex string: %v", err)
 	}
 
-	printRespJSON(chanInfo)
-	return nil
-}
-
-var getNodeInfoCommand = cli.Command{
-	Name:     "getnodeinfo",
-	Category: "Peers",
-	Usage:    "Get information on a specific node.",
-	Description: "Prints out the latest authenticated node state for an " +
-		"advertised node",
-	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name: "pub_key",
-			Usage: "the 33-byte hex-encoded compressed public of the target " +
-				"node",
+	return &lnrpc.ChannelPoint{
+		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: txid[:],
 		},
-	},
-	Action: actionDecorator(getNodeInfo),
+		OutputIndex: uint32(index),
+	}, nil
 }
 
-func getNodeInfo(ctx *cli.Context) error {
+func updateChannelPolicy(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
+	var (
+		baseFee       int64
+		feeRate       float64
+		timeLockDelta int64
+		err           error
+	)
 	args := ctx.Args()
 
-	var pubKey string
 	switch {
-	case ctx.IsSet("pub_key"):
-		pubKey = ctx.String("pub_key")
+	case ctx.IsSet("base_fee_msat"):
+		baseFee = ctx.Int64("base_fee_msat")
 	case args.Present():
-		pubKey = args.First()
+		baseFee, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode base_fee_msat: %v", err)
+		}
+		args = args.Tail()
 	default:
-		return fmt.Errorf("pub_key argument missing")
+		return fmt.Errorf("base_fee_msat argument missing")
 	}
 
-	req := &lnrpc.NodeInfoRequest{
-		PubKey: pubKey,
+	switch {
+	case ctx.IsSet("fee_rate"):
+		feeRate = ctx.Float64("fee_rate")
+	case args.Present():
+		feeRate, err = strconv.ParseFloat(args.First(), 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode fee_rate: %v", err)
+		}
+
+		args = args.Tail()
+	default:
+		return fmt.Errorf("fee_rate argument missing")
 	}
 
-	nodeInfo, err := client.GetNodeInfo(ctxb, req)
+	switch {
+	case ctx.IsSet("time_lock_delta"):
+		timeLockDelta = ctx.Int64("time_lock_delta")
+	case args.Present():
+		timeLockDelta, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode time_lock_delta: %v",
+				err)
+		}
+
+		args = args.Tail()
+	default:
+		return fmt.Errorf("time_lock_delta argument missing")
+	}
+
+	var (
+		chanPoint    *lnrpc.ChannelPoint
+		chanPointStr string
+	)
+
+	switch {
+	case ctx.IsSet("chan_point"):
+		chanPointStr = ctx.String("chan_point")
+	case args.Present():
+		chanPointStr = args.First()
+	}
+
+	if chanPointStr != "" {
+		chanPoint, err = parseChanPoint(chanPointStr)
+		if err != nil {
+			return fmt.Errorf("unable to parse chan point: %v", err)
+		}
+	}
+
+	req := &lnrpc.PolicyUpdateRequest{
+		BaseFeeMsat:   baseFee,
+		FeeRate:       feeRate,
+		TimeLockDelta: uint32(timeLockDelta),
+	}
+
+	if chanPoint != nil {
+		req.Scope = &lnrpc.PolicyUpdateRequest_ChanPoint{
+			ChanPoint: chanPoint,
+		}
+	} else {
+		req.Scope = &lnrpc.PolicyUpdateRequest_Global{
+			Global: true,
+		}
+	}
+
+	resp, err := client.UpdateChannelPolicy(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(nodeInfo)
+	printRespJSON(resp)
 	return nil
 }
 
-var queryRoutesCommand = cli.Command{
-	Name:        "queryroutes",
-	Category:    "Payments",
-	Usage:       "Query a route to a destination.",
-	Description: "Queries the channel router for a potential path to the destination that has sufficient flow for the amount including fees",
-	ArgsUsage:   "dest amt",
+var forwardingHistoryCommand = cli.Command{
+	Name:      "fwdinghistory",
+	Category:  "Payments",
+	Usage:     "Query the history of all forwarded HTLCs.",
+	ArgsUsage: "start_time [end_time] [index_offset] [max_events]",
+	Description: `
+	Query the HTLC switch's internal forwarding log for all completed
+	payment circuits (HTLCs) over a particular time range (--start_time and
+	--end_time). The start and end times are meant to be expressed in
+	seconds since the Unix epoch. If a start and end time aren't provided,
+	then events over the past 24 hours are queried for.
+
+	The max number of events returned is 50k. The default number is 100,
+	callers can use the --max_events param to modify this value.
+
+	Finally, callers can skip a series of events using the --index_offset
+	parameter. Each response will contain the offset index of the last
+	entry. Using this callers can manually paginate within a time slice.
+	`,
 	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name: "dest",
-			Usage: "the 33-byte hex-encoded public key for the payment " +
-				"destination",
-		},
-		cli.Int64Flag{
-			Name:  "amt",
-			Usage: "the amount to send expressed in satoshis",
-		},
 		cli.Int64Flag{
-			Name: "fee_limit",
-			Usage: "maximum fee allowed in satoshis when sending " +
-				"the payment",
+			Name: "start_time",
+			Usage: "the starting time for the query, expressed in " +
+				"seconds since the unix epoch",
 		},
 		cli.Int64Flag{
-			Name: "fee_limit_percent",
-			Usage: "percentage of the payment's amount used as the " +
-				"maximum fee allowed when sending the payment",
+			Name: "end_time",
+			Usage: "the end time for the query, expressed in " +
+				"seconds since the unix epoch",
 		},
 		cli.Int64Flag{
-			Name:  "num_max_routes",
-			Usage: "the max number of routes to be returned",
-			Value: 10,
+			Name:  "index_offset",
+			Usage: "the number of events to skip",
 		},
 		cli.Int64Flag{
-			Name: "final_cltv_delta",
-			Usage: "(optional) number of blocks the last hop has to reveal " +
-				"the preimage",
+			Name:  "max_events",
+			Usage: "the max number of events to return",
 		},
 	},
-	Action: actionDecorator(queryRoutes),
+	Action: actionDecorator(forwardingHistory),
 }
 
-func queryRoutes(ctx *cli.Context) error {
+func forwardingHistory(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
 	var (
-		dest string
-		amt  int64
-		err  error
+		startTime, endTime     uint64
+		indexOffset, maxEvents uint32
+		err                    error
 	)
-
 	args := ctx.Args()
 
 	switch {
-	case ctx.IsSet("dest"):
-		dest = ctx.String("dest")
+	case ctx.IsSet("start_time"):
+		startTime = ctx.Uint64("start_time")
 	case args.Present():
-		dest = args.First()
+		startTime, err = strconv.ParseUint(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode start_time %v", err)
+		}
 		args = args.Tail()
-	default:
-		return fmt.Errorf("dest argument missing")
 	}
 
 	switch {
-	case ctx.IsSet("amt"):
-		amt = ctx.Int64("amt")
+	case ctx.IsSet("end_time"):
+		endTime = ctx.Uint64("end_time")
 	case args.Present():
-		amt, err = strconv.ParseInt(args.First(), 10, 64)
+		endTime, err = strconv.ParseUint(args.First(), 10, 64)
 		if err != nil {
-			return fmt.Errorf("unable to decode amt argument: %v", err)
+			return fmt.Errorf("unable to decode end_time: %v", err)
 		}
-	default:
-		return fmt.Errorf("amt argument missing")
+		args = args.Tail()
 	}
 
-	feeLimit, err := retrieveFeeLimit(ctx)
+	switch {
+	case ctx.IsSet("index_offset"):
+		indexOffset = uint32(ctx.Int64("index_offset"))
+	case args.Present():
+		i, err := strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode index_offset: %v", err)
+		}
+		indexOffset = uint32(i)
+		args = args.Tail()
+	}
+
+	switch {
+	case ctx.IsSet("max_events"):
+		maxEvents = uint32(ctx.Int64("max_events"))
+	case args.Present():
+		m, err := strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode max_events: %v", err)
+		}
+		maxEvents = uint32(m)
+		args = args.Tail()
+	}
+
+	unit, err := parseDisplayUnit(ctx)
 	if err != nil {
 		return err
 	}
 
-	req := &lnrpc.QueryRoutesRequest{
-		PubKey:         dest,
-		Amt:            amt,
-		FeeLimit:       feeLimit,
-		NumRoutes:      int32(ctx.Int("num_max_routes")),
-		FinalCltvDelta: int32(ctx.Int("final_cltv_delta")),
+	req := &lnrpc.ForwardingHistoryRequest{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		IndexOffset:  indexOffset,
+		NumMaxEvents: maxEvents,
 	}
-
-	route, err := client.QueryRoutes(ctxb, req)
+	resp, err := client.ForwardingHistory(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(route)
+	if unit != nil {
+		printJSON(NewForwardingHistoryFromProto(resp, unit))
+		return nil
+	}
+
+	printRespJSON(resp)
 	return nil
 }
 
-var getNetworkInfoCommand = cli.Command{
-	Name:     "getnetworkinfo",
-	Category: "Channels",
-	Usage: "Get statistical information about the current " +
-		"state of the network.",
-	Description: "Returns a set of statistics pertaining to the known " +
-		"channel graph",
-	Action: actionDecorator(getNetworkInfo),
+var forwardingSummaryCommand = cli.Command{
+	Name:     "fwdingsummary",
+	Category: "Payments",
+	Usage: "Query the daily summaries of the HTLCs forwarded through " +
+		"each channel.",
+	Description: `
+	Query the daily summaries of the HTLCs forwarded through each channel
+	over a particular time range (--start_time and --end_time), expressed in
+	seconds since the Unix epoch. Each summary covers a day in UTC, and
+	accounts for the events that have already been pruned from the
+	forwarding log due to its retention policy (--fwdlog.retentiondays). If
+	a start and end time aren't provided, then the summaries of the past 30
+	days are queried for.
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "start_time",
+			Usage: "the starting time for the query, expressed in " +
+				"seconds since the unix epoch",
+		},
+		cli.Uint64Flag{
+			Name: "end_time",
+			Usage: "the end time for the query, expressed in " +
+				"seconds since the unix epoch",
+		},
+	},
+	Action: actionDecorator(forwardingSummary),
 }
 
-func getNetworkInfo(ctx *cli.Context) error {
+func forwardingSummary(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.NetworkInfoRequest{}
+	unit, err := parseDisplayUnit(ctx)
+	if err != nil {
+		return err
+	}
 
-	netInfo, err := client.GetNetworkInfo(ctxb, req)
+	req := &lnrpc.ForwardingSummaryRequest{
+		StartTime: ctx.Uint64("start_time"),
+		EndTime:   ctx.Uint64("end_time"),
+	}
+	resp, err := client.ForwardingSummary(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(netInfo)
+	if unit != nil {
+		printJSON(NewForwardingSummariesFromProto(resp, unit))
+		return nil
+	}
+
+	printRespJSON(resp)
 	return nil
 }
 
-var debugLevelCommand = cli.Command{
-	Name:  "debuglevel",
-	Usage: "Set the debug level.",
-	Description: `Logging level for all subsystems {trace, debug, info, warn, error, critical, off}
-	You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems
-
-	Use show to list available subsystems`,
-	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name:  "show",
-			Usage: "if true, then the list of available sub-systems will be printed out",
-		},
-		cli.StringFlag{
-			Name:  "level",
-			Usage: "the level specification to target either a coarse logging level, or granular set of specific sub-systems with logging levels for each",
-		},
-	},
-	Action: actionDecorator(debugLevel),
+var htlcLatencyCommand = cli.Command{
+	Name:     "htlclatency",
+	Category: "Channels",
+	Usage: "Display the time it took for offered HTLCs to be resolved " +
+		"per channel and peer.",
+	Description: `
+	Returns the percentiles of the time it took for the HTLCs offered to
+	each of our channels and peers to be settled or failed, since lnd was
+	last started. Channels and peers are ordered by their 90th percentile,
+	such that the slowest ones are listed first.
+
+	In addition, the average hold times of the nodes along the routes of
+	our payments and forwards are listed, slowest first. Slow nodes are
+	avoided during path finding according to the hold time weight.`,
+	Action: actionDecorator(htlcLatency),
 }
 
-func debugLevel(ctx *cli.Context) error {
+func htlcLatency(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
-	req := &lnrpc.DebugLevelRequest{
-		Show:      ctx.Bool("show"),
-		LevelSpec: ctx.String("level"),
-	}
 
-	resp, err := client.DebugLevel(ctxb, req)
+	req := &lnrpc.HtlcLatencyReportRequest{}
+	resp, err := client.HtlcLatencyReport(ctxb, req)
 	if err != nil {
 		return err
 	}
@@ -3112,129 +5339,243 @@ func debugLevel(ctx *cli.Context) error {
 	return nil
 }
 
-var decodePayReqCommand = cli.Command{
-	Name:        "decodepayreq",
-	Category:    "Payments",
-	Usage:       "Decode a payment request.",
-	Description: "Decode the passed payment request revealing the destination, payment hash and value of the payment request",
-	ArgsUsage:   "pay_req",
+var exportChanBackupCommand = cli.Command{
+	Name:     "exportchanbackup",
+	Category: "Channels",
+	Usage: "Obtain a static channel back up for a selected channels, " +
+		"or all known channels",
+	ArgsUsage: "[chan_point] [--all] [--output_file]",
+	Description: `
+	This command allows a user to export a Static Channel Backup (SCB) for
+	a selected channel. SCB's are encrypted backups of a channel's initial
+	state that are encrypted with a key derived from the seed of a user. In
+	the case of partial or complete data loss, the SCB will allow the user
+	to reclaim settled funds in the channel at its final state. The
+	exported channel backups can be restored at a later time using the
+	restorechanbackup command.
+
+	This command will return one of two types of channel backups depending
+	on the set of passed arguments:
+
+	   * If a target channel point is specified, then a single channel
+	     backup containing only the information for that channel will be
+	     returned.
+
+	   * If the --all flag is passed, then a multi-channel backup will be
+	     returned. A multi backup is a single encrypted blob (displayed in
+	     hex encoding) that contains several channels in a single cipher
+	     text.
+
+	Both of the backup types can be restored using the restorechanbackup
+	command.
+	`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name:  "pay_req",
-			Usage: "the bech32 encoded payment request",
+			Name:  "chan_point",
+			Usage: "the target channel to obtain an SCB for",
+		},
+		cli.BoolFlag{
+			Name: "all",
+			Usage: "if specified, then a multi backup of all " +
+				"active channels will be returned",
+		},
+		cli.StringFlag{
+			Name: "output_file",
+			Usage: `
+			if specified, then rather than printing a JSON output
+			of the static channel backup, a serialized version of
+			the backup (either Single or Multi) will be written to
+			the target file, this is the same format used by lnd in
+			its channels.backup file `,
 		},
 	},
-	Action: actionDecorator(decodePayReq),
+	Action: actionDecorator(exportChanBackup),
 }
 
-func decodePayReq(ctx *cli.Context) error {
+func exportChanBackup(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var payreq string
+	// Show command help if no arguments provided
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "exportchanbackup")
+		return nil
+	}
+
+	var (
+		err          error
+		chanPointStr string
+	)
+	args := ctx.Args()
 
 	switch {
-	case ctx.IsSet("pay_req"):
-		payreq = ctx.String("pay_req")
-	case ctx.Args().Present():
-		payreq = ctx.Args().First()
-	default:
-		return fmt.Errorf("pay_req argument missing")
-	}
+	case ctx.IsSet("chan_point"):
+		chanPointStr = ctx.String("chan_point")
 
-	resp, err := client.DecodePayReq(ctxb, &lnrpc.PayReqString{
-		PayReq: payreq,
-	})
-	if err != nil {
-		return err
+	case args.Present():
+		chanPointStr = args.First()
+
+	case !ctx.IsSet("all"):
+		return fmt.Errorf("must specify chan_point if --all isn't set")
 	}
 
-	printRespJSON(resp)
-	return nil
-}
+	if chanPointStr != "" {
+		chanPointRPC, err := parseChanPoint(chanPointStr)
+		if err != nil {
+			return err
+		}
 
-var listChainTxnsCommand = cli.Command{
-	Name:        "listchaintxns",
-	Category:    "On-chain",
-	Usage:       "List transactions from the wallet.",
-	Description: "List all transactions an address of the wallet was involved in.",
-	Action:      actionDecorator(listChainTxns),
-}
+		chanBackup, err := client.ExportChannelBackup(
+			ctxb, &lnrpc.ExportChannelBackupRequest{
+				ChanPoint: chanPointRPC,
+			},
+		)
+		if err != nil {
+			return err
+		}
 
-func listChainTxns(ctx *cli.Context) error {
-	ctxb := context.Background()
-	client, cleanUp := getClient(ctx)
-	defer cleanUp()
+		txid, err := chainhash.NewHash(
+			chanPointRPC.GetFundingTxidBytes(),
+		)
+		if err != nil {
+			return err
+		}
 
-	resp, err := client.GetTransactions(ctxb, &lnrpc.GetTransactionsRequest{})
+		chanPoint := wire.OutPoint{
+			Hash:  *txid,
+			Index: chanPointRPC.OutputIndex,
+		}
+
+		printJSON(struct {
+			ChanPoint  string `json:"chan_point"`
+			ChanBackup string `json:"chan_backup"`
+		}{
+			ChanPoint: chanPoint.String(),
+			ChanBackup: hex.EncodeToString(
+				chanBackup.ChanBackup,
+			),
+		},
+		)
+		return nil
+	}
+
+	if !ctx.IsSet("all") {
+		return fmt.Errorf("if a channel isn't specified, -all must be")
+	}
 
+	chanBackup, err := client.ExportAllChannelBackups(
+		ctxb, &lnrpc.ChanBackupExportRequest{},
+	)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(resp)
-	return nil
-}
+	if ctx.IsSet("output_file") {
+		return ioutil.WriteFile(
+			ctx.String("output_file"),
+			chanBackup.MultiChanBackup.MultiChanBackup,
+			0666,
+		)
+	}
 
-var stopCommand = cli.Command{
-	Name:  "stop",
-	Usage: "Stop and shutdown the daemon.",
-	Description: `
-	Gracefully stop all daemon subsystems before stopping the daemon itself.
-	This is equivalent to stopping it using CTRL-C.`,
-	Action: actionDecorator(stopDaemon),
-}
+	// TODO(roasbeef): support for export | restore ?
 
-func stopDaemon(ctx *cli.Context) error {
-	ctxb := context.Background()
-	client, cleanUp := getClient(ctx)
-	defer cleanUp()
+	var chanPoints []string
+	for _, chanPoint := range chanBackup.MultiChanBackup.ChanPoints {
+		txid, err := chainhash.NewHash(chanPoint.GetFundingTxidBytes())
+		if err != nil {
+			return err
+		}
 
-	_, err := client.StopDaemon(ctxb, &lnrpc.StopRequest{})
-	if err != nil {
-		return err
+		chanPoints = append(chanPoints, wire.OutPoint{
+			Hash:  *txid,
+			Index: chanPoint.OutputIndex,
+		}.String())
 	}
 
+	printJSON(struct {
+		ChanPoints      []string `json:"chan_points"`
+		MultiChanBackup string   `json:"multi_chan_backup"`
+	}{
+		ChanPoints: chanPoints,
+		MultiChanBackup: hex.EncodeToString(
+			chanBackup.MultiChanBackup.MultiChanBackup,
+		),
+	},
+	)
 	return nil
 }
 
-var signMessageCommand = cli.Command{
-	Name:      "signmessage",
-	Category:  "Wallet",
-	Usage:     "Sign a message with the node's private key.",
-	ArgsUsage: "msg",
+var verifyChanBackupCommand = cli.Command{
+	Name:      "verifychanbackup",
+	Category:  "Channels",
+	Usage:     "Verify an existing channel backup",
+	ArgsUsage: "[--single_backup] [--multi_backup] [--multi_file]",
 	Description: `
-	Sign msg with the resident node's private key.
-	Returns the signature as a zbase32 string.
+    This command allows a user to verify an existing Single or Multi channel
+    backup for integrity. This is useful when a user has a backup, but is
+    unsure as to if it's valid or for the target node.
 
-	Positional arguments and flags can be used interchangeably but not at the same time!`,
+    The command will accept backups in one of three forms:
+
+       * A single channel packed SCB, which can be obtained from
+	 exportchanbackup. This should be passed in hex encoded format.
+
+       * A packed multi-channel SCB, which couples several individual
+	 static channel backups in single blob.
+
+       * A file path which points to a packed multi-channel backup within a
+	 file, using the same format that lnd does in its channels.backup
+	 file.
+    `,
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name:  "msg",
-			Usage: "the message to sign",
+			Name: "single_backup",
+			Usage: "a hex encoded single channel backup obtained " +
+				"from exportchanbackup",
+		},
+		cli.StringFlag{
+			Name: "multi_backup",
+			Usage: "a hex encoded multi-channel backup obtained " +
+				"from exportchanbackup",
+		},
+		cli.StringFlag{
+			Name:  "multi_file",
+			Usage: "the path to a multi-channel back up file",
 		},
 	},
-	Action: actionDecorator(signMessage),
+	Action: actionDecorator(verifyChanBackup),
 }
 
-func signMessage(ctx *cli.Context) error {
+func verifyChanBackup(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var msg []byte
+	// Show command help if no arguments provided
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "verifychanbackup")
+		return nil
+	}
 
-	switch {
-	case ctx.IsSet("msg"):
-		msg = []byte(ctx.String("msg"))
-	case ctx.Args().Present():
-		msg = []byte(ctx.Args().First())
-	default:
-		return fmt.Errorf("msg argument missing")
+	backups, err := parseChanBackups(ctx)
+	if err != nil {
+		return err
 	}
 
-	resp, err := client.SignMessage(ctxb, &lnrpc.SignMessageRequest{Msg: msg})
+	verifyReq := lnrpc.ChanBackupSnapshot{}
+
+	if backups.GetChanBackups() != nil {
+		verifyReq.SingleChanBackups = backups.GetChanBackups()
+	}
+	if backups.GetMultiChanBackup() != nil {
+		verifyReq.MultiChanBackup = &lnrpc.MultiChanBackup{
+			MultiChanBackup: backups.GetMultiChanBackup(),
+		}
+	}
+
+	resp, err := client.VerifyChanBackup(ctxb, &verifyReq)
 	if err != nil {
 		return err
 	}
@@ -3243,63 +5584,39 @@ func signMessage(ctx *cli.Context) error {
 	return nil
 }
 
-var verifyMessageCommand = cli.Command{
-	Name:      "verifymessage",
-	Category:  "Wallet",
-	Usage:     "Verify a message signed with the signature.",
-	ArgsUsage: "msg signature",
+var backupRecoverabilityCommand = cli.Command{
+	Name:     "backuprecoverability",
+	Category: "Channels",
+	Usage: "Report whether the channels of the channel backup file " +
+		"could be recovered",
 	Description: `
-	Verify that the message was signed with a properly-formed signature
-	The signature must be zbase32 encoded and signed with the private key of
-	an active node in the resident node's channel database.
-
-	Positional arguments and flags can be used interchangeably but not at the same time!`,
+    Reports whether the channels in lnd's channels.backup file could be
+    recovered. For each channel, lnd checks that the channel's peer can be
+    reached, and, if the watchtower client is active, how many of the
+    channel's most recent revoked states have yet to be backed up to a
+    watchtower.
+
+    The report of the most recent periodic verification is returned, unless
+    the --refresh flag is set.
+    `,
 	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name:  "msg",
-			Usage: "the message to verify",
-		},
-		cli.StringFlag{
-			Name:  "sig",
-			Usage: "the zbase32 encoded signature of the message",
+		cli.BoolFlag{
+			Name:  "refresh",
+			Usage: "verify the backup anew",
 		},
 	},
-	Action: actionDecorator(verifyMessage),
+	Action: actionDecorator(backupRecoverability),
 }
 
-func verifyMessage(ctx *cli.Context) error {
+func backupRecoverability(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var (
-		msg []byte
-		sig string
-	)
-
-	args := ctx.Args()
-
-	switch {
-	case ctx.IsSet("msg"):
-		msg = []byte(ctx.String("msg"))
-	case args.Present():
-		msg = []byte(ctx.Args().First())
-		args = args.Tail()
-	default:
-		return fmt.Errorf("msg argument missing")
-	}
-
-	switch {
-	case ctx.IsSet("sig"):
-		sig = ctx.String("sig")
-	case args.Present():
-		sig = args.First()
-	default:
-		return fmt.Errorf("signature argument missing")
+	req := &lnrpc.RecoverabilityRequest{
+		Refresh: ctx.Bool("refresh"),
 	}
-
-	req := &lnrpc.VerifyMessageRequest{Msg: msg, Signature: sig}
-	resp, err := client.VerifyMessage(ctxb, req)
+	resp, err := client.ChanBackupRecoverability(ctxb, req)
 	if err != nil {
 		return err
 	}
@@ -3308,23 +5625,27 @@ func verifyMessage(ctx *cli.Context) error {
 	return nil
 }
 
-var feeReportCommand = cli.Command{
-	Name:     "feereport",
+var listBackupFilesCommand = cli.Command{
+	Name:     "listbackupfiles",
 	Category: "Channels",
-	Usage:    "Display the current fee policies of all active channels.",
+	Usage:    "List the channel backup file and its exported copies",
 	Description: `
-	Returns the current fee policies of all active channels.
-	Fee policies can be updated using the updatechanpolicy command.`,
-	Action: actionDecorator(feeReport),
+    Inspects lnd's channels.backup file, along with the copies exported to
+    the paths set through backupexport.path, returning the size, sha256 and
+    channels of each file. In addition, the outcome of the most recent
+    export to each destination, including the URLs set through
+    backupexport.url, is returned.
+    `,
+	Action: actionDecorator(listBackupFiles),
 }
 
-func feeReport(ctx *cli.Context) error {
+func listBackupFiles(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.FeeReportRequest{}
-	resp, err := client.FeeReport(ctxb, req)
+	req := &lnrpc.ListBackupFilesRequest{}
+	resp, err := client.ListBackupFiles(ctxb, req)
 	if err != nil {
 		return err
 	}
@@ -3333,161 +5654,230 @@ func feeReport(ctx *cli.Context) error {
 	return nil
 }
 
-var updateChannelPolicyCommand = cli.Command{
-	Name:     "updatechanpolicy",
+var restoreChanBackupCommand = cli.Command{
+	Name:     "restorechanbackup",
 	Category: "Channels",
-	Usage: "Update the channel policy for all channels, or a single " +
-		"channel.",
-	ArgsUsage: "base_fee_msat fee_rate time_lock_delta [channel_point]",
+	Usage: "Restore an existing single or multi-channel static channel " +
+		"backup",
+	ArgsUsage: "[--single_backup] [--multi_backup] [--multi_file=",
 	Description: `
-	Updates the channel policy for all channels, or just a particular channel
-	identified by its channel point. The update will be committed, and
-	broadcast to the rest of the network within the next batch.
-	Channel points are encoded as: funding_txid:output_index`,
+	Allows a suer to restore a Static Channel Backup (SCB) that was
+	obtained either via the exportchanbackup command, or from lnd's
+	automatically manged channels.backup file. This command should be used
+	if a user is attempting to restore a channel due to data loss on a
+	running node restored with the same seed as the node that created the
+	channel. If successful, this command will allows the user to recover
+	the settled funds stored in the recovered channels.
+
+	The command will accept backups in one of three forms:
+
+	   * A single channel packed SCB, which can be obtained from
+	     exportchanbackup. This should be passed in hex encoded format.
+
+	   * A packed multi-channel SCB, which couples several individual
+	     static channel backups in single blob.
+
+	   * A file path which points to a packed multi-channel backup within a
+	     file, using the same format that lnd does in its channels.backup
+	     file.
+	`,
 	Flags: []cli.Flag{
-		cli.Int64Flag{
-			Name: "base_fee_msat",
-			Usage: "the base fee in milli-satoshis that will " +
-				"be charged for each forwarded HTLC, regardless " +
-				"of payment size",
-		},
 		cli.StringFlag{
-			Name: "fee_rate",
-			Usage: "the fee rate that will be charged " +
-				"proportionally based on the value of each " +
-				"forwarded HTLC, the lowest possible rate is 0.000001",
+			Name: "single_backup",
+			Usage: "a hex encoded single channel backup obtained " +
+				"from exportchanbackup",
 		},
-		cli.Int64Flag{
-			Name: "time_lock_delta",
-			Usage: "the CLTV delta that will be applied to all " +
-				"forwarded HTLCs",
+		cli.StringFlag{
+			Name: "multi_backup",
+			Usage: "a hex encoded multi-channel backup obtained " +
+				"from exportchanbackup",
 		},
 		cli.StringFlag{
-			Name: "chan_point",
-			Usage: "The channel whose fee policy should be " +
-				"updated, if nil the policies for all channels " +
-				"will be updated. Takes the form of: txid:output_index",
+			Name:  "multi_file",
+			Usage: "the path to a multi-channel back up file",
 		},
 	},
-	Action: actionDecorator(updateChannelPolicy),
+	Action: actionDecorator(restoreChanBackup),
 }
 
-func parseChanPoint(s string) (*lnrpc.ChannelPoint, error) {
-	split := strings.Split(s, ":")
-	if len(split) != 2 {
-		return nil, fmt.Errorf("expecting chan_point to be in format of: " +
-			"txid:index")
-	}
+func parseChanBackups(ctx *cli.Context) (*lnrpc.RestoreChanBackupRequest, error) {
+	switch {
+	case ctx.IsSet("single_backup"):
+		packedBackup, err := hex.DecodeString(
+			ctx.String("single_backup"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode single packed "+
+				"backup: %v", err)
+		}
 
-	index, err := strconv.ParseInt(split[1], 10, 32)
-	if err != nil {
-		return nil, fmt.Errorf("unable to decode output index: %v", err)
-	}
+		return &lnrpc.RestoreChanBackupRequest{
+			Backup: &lnrpc.RestoreChanBackupRequest_ChanBackups{
+				ChanBackups: &lnrpc.ChannelBackups{
+					ChanBackups: []*lnrpc.ChannelBackup{
+						{
+							ChanBackup: packedBackup,
+						},
+					},
+				},
+			},
+		}, nil
 
-	txid, err := chainhash.NewHashFromStr(split[0])
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse hex string: %v", err)
-	}
+	case ctx.IsSet("multi_backup"):
+		packedMulti, err := hex.DecodeString(
+			ctx.String("multi_backup"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode multi packed "+
+				"backup: %v", err)
+		}
 
-	return &lnrpc.ChannelPoint{
-		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
-			FundingTxidBytes: txid[:],
-		},
-		OutputIndex: uint32(index),
-	}, nil
+		return &lnrpc.RestoreChanBackupRequest{
+			Backup: &lnrpc.RestoreChanBackupRequest_MultiChanBackup{
+				MultiChanBackup: packedMulti,
+			},
+		}, nil
+
+	case ctx.IsSet("multi_file"):
+		packedMulti, err := ioutil.ReadFile(ctx.String("multi_file"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode multi packed "+
+				"backup: %v", err)
+		}
+
+		return &lnrpc.RestoreChanBackupRequest{
+			Backup: &lnrpc.RestoreChanBackupRequest_MultiChanBackup{
+				MultiChanBackup: packedMulti,
+			},
+		}, nil
+
+	default:
+		return nil, nil
+	}
 }
 
-func updateChannelPolicy(ctx *cli.Context) error {
+func restoreChanBackup(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var (
-		baseFee       int64
-		feeRate       float64
-		timeLockDelta int64
-		err           error
-	)
-	args := ctx.Args()
-
-	switch {
-	case ctx.IsSet("base_fee_msat"):
-		baseFee = ctx.Int64("base_fee_msat")
-	case args.Present():
-		baseFee, err = strconv.ParseInt(args.First(), 10, 64)
-		if err != nil {
-			return fmt.Errorf("unable to decode base_fee_msat: %v", err)
-		}
-		args = args.Tail()
-	default:
-		return fmt.Errorf("base_fee_msat argument missing")
+	// Show command help if no arguments provided
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "restorechanbackup")
+		return nil
 	}
 
-	switch {
-	case ctx.IsSet("fee_rate"):
-		feeRate = ctx.Float64("fee_rate")
-	case args.Present():
-		feeRate, err = strconv.ParseFloat(args.First(), 64)
-		if err != nil {
-			return fmt.Errorf("unable to decode fee_rate: %v", err)
-		}
+	var req lnrpc.RestoreChanBackupRequest
 
-		args = args.Tail()
-	default:
-		return fmt.Errorf("fee_rate argument missing")
+	backups, err := parseChanBackups(ctx)
+	if err != nil {
+		return err
 	}
 
-	switch {
-	case ctx.IsSet("time_lock_delta"):
-		timeLockDelta = ctx.Int64("time_lock_delta")
-	case args.Present():
-		timeLockDelta, err = strconv.ParseInt(args.First(), 10, 64)
-		if err != nil {
-			return fmt.Errorf("unable to decode time_lock_delta: %v",
-				err)
-		}
+	req.Backup = backups.Backup
 
-		args = args.Tail()
-	default:
-		return fmt.Errorf("time_lock_delta argument missing")
+	_, err = client.RestoreChannelBackups(ctxb, &req)
+	if err != nil {
+		return fmt.Errorf("unable to restore chan backups: %v", err)
 	}
 
-	var (
-		chanPoint    *lnrpc.ChannelPoint
-		chanPointStr string
-	)
+	return nil
+}
 
-	switch {
-	case ctx.IsSet("chan_point"):
-		chanPointStr = ctx.String("chan_point")
-	case args.Present():
-		chanPointStr = args.First()
-	}
+var exportJournalCommand = cli.Command{
+	Name:      "exportjournal",
+	Category:  "Journal",
+	Usage:     "Export entries of the event journal",
+	ArgsUsage: "[--start_seq=N] [--end_seq=N]",
+	Description: `
+    Exports a range of entries of the event journal, which records channel
+    openings and closings, payments above the configured threshold, and
+    administrative RPC calls along with the macaroon they were made with.
 
-	if chanPointStr != "" {
-		chanPoint, err = parseChanPoint(chanPointStr)
-		if err != nil {
-			return fmt.Errorf("unable to parse chan point: %v", err)
-		}
+    Each entry commits to the hash of its predecessor. lnd verifies the
+    integrity of the entire journal before any entries are exported.
+    `,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "start_seq",
+			Usage: "the sequence number of the first entry to " +
+				"export",
+			Value: 1,
+		},
+		cli.Uint64Flag{
+			Name: "end_seq",
+			Usage: "(optional) the sequence number of the last " +
+				"entry to export, if unset all entries from " +
+				"start_seq onwards are exported",
+		},
+	},
+	Action: actionDecorator(exportJournal),
+}
+
+func exportJournal(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ExportJournalRequest{
+		StartSeq: ctx.Uint64("start_seq"),
+		EndSeq:   ctx.Uint64("end_seq"),
 	}
+	resp, err := client.ExportJournal(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var anchorJournalCommand = cli.Command{
+	Name:      "anchorjournal",
+	Category:  "Journal",
+	Usage:     "Commit the event journal on-chain",
+	ArgsUsage: "[--conf_target=N] [--sat_per_byte=P]",
+	Description: `
+    Commits the hash of the last entry of the event journal on-chain, within
+    an OP_RETURN output of a transaction funded by the wallet. As each entry
+    commits to all preceding ones, the anchor proves that the journal up to
+    that entry hasn't been rewritten since.
+
+    The fee of the anchor transaction can be specified via the --conf_target,
+    or --sat_per_byte optional flags.
+    `,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Int64Flag{
+			Name: "sat_per_byte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/byte that should be used when crafting " +
+				"the transaction",
+		},
+	},
+	Action: actionDecorator(anchorJournal),
+}
 
-	req := &lnrpc.PolicyUpdateRequest{
-		BaseFeeMsat:   baseFee,
-		FeeRate:       feeRate,
-		TimeLockDelta: uint32(timeLockDelta),
-	}
+func anchorJournal(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-	if chanPoint != nil {
-		req.Scope = &lnrpc.PolicyUpdateRequest_ChanPoint{
-			ChanPoint: chanPoint,
-		}
-	} else {
-		req.Scope = &lnrpc.PolicyUpdateRequest_Global{
-			Global: true,
-		}
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_byte") {
+		return fmt.Errorf("either conf_target or sat_per_byte should " +
+			"be set, but not both")
 	}
 
-	resp, err := client.UpdateChannelPolicy(ctxb, req)
+	req := &lnrpc.AnchorJournalRequest{
+		TargetConf: int32(ctx.Int64("conf_target")),
+		SatPerByte: ctx.Int64("sat_per_byte"),
+	}
+	resp, err := client.AnchorJournal(ctxb, req)
 	if err != nil {
 		return err
 	}
@@ -3496,113 +5886,66 @@ func updateChannelPolicy(ctx *cli.Context) error {
 	return nil
 }
 
-var forwardingHistoryCommand = cli.Command{
-	Name:      "fwdinghistory",
-	Category:  "Payments",
-	Usage:     "Query the history of all forwarded HTLCs.",
-	ArgsUsage: "start_time [end_time] [index_offset] [max_events]",
+var exportChanAuditLogCommand = cli.Command{
+	Name:      "exportchanauditlog",
+	Category:  "Channels",
+	Usage:     "Export events of the audit log of a channel.",
+	ArgsUsage: "funding_txid [output_index]",
 	Description: `
-	Query the HTLC switch's internal forwarding log for all completed
-	payment circuits (HTLCs) over a particular time range (--start_time and
-	--end_time). The start and end times are meant to be expressed in
-	seconds since the Unix epoch. If a start and end time aren't provided,
-	then events over the past 24 hours are queried for.
-
-	The max number of events returned is 50k. The default number is 100,
-	callers can use the --max_events param to modify this value.
-
-	Finally, callers can skip a series of events using the --index_offset
-	parameter. Each response will contain the offset index of the last
-	entry. Using this callers can manually paginate within a time slice.
+	Exports a range of the audit log of the channel identified by its
+	channel point. When enabled through --chanauditlog, the audit log of a
+	channel records every HTLC add, settle and fail, fee update, commitment
+	signature and revocation exchanged with its peer. Each event includes
+	the message as encoded on the wire, such that the evolution of the
+	state of the channel can be reconstructed.
 	`,
 	Flags: []cli.Flag{
-		cli.Int64Flag{
-			Name: "start_time",
-			Usage: "the starting time for the query, expressed in " +
-				"seconds since the unix epoch",
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
 		},
-		cli.Int64Flag{
-			Name: "end_time",
-			Usage: "the end time for the query, expressed in " +
-				"seconds since the unix epoch",
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of " +
+				"the funding transaction",
 		},
-		cli.Int64Flag{
-			Name:  "index_offset",
-			Usage: "the number of events to skip",
+		cli.Uint64Flag{
+			Name: "start_seq",
+			Usage: "the sequence number of the first event to " +
+				"export",
+			Value: 1,
 		},
-		cli.Int64Flag{
-			Name:  "max_events",
-			Usage: "the max number of events to return",
+		cli.Uint64Flag{
+			Name: "max_events",
+			Usage: "the maximum number of events to export, " +
+				"1000 if unset",
 		},
 	},
-	Action: actionDecorator(forwardingHistory),
+	Action: actionDecorator(exportChanAuditLog),
 }
 
-func forwardingHistory(ctx *cli.Context) error {
+func exportChanAuditLog(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var (
-		startTime, endTime     uint64
-		indexOffset, maxEvents uint32
-		err                    error
-	)
-	args := ctx.Args()
-
-	switch {
-	case ctx.IsSet("start_time"):
-		startTime = ctx.Uint64("start_time")
-	case args.Present():
-		startTime, err = strconv.ParseUint(args.First(), 10, 64)
-		if err != nil {
-			return fmt.Errorf("unable to decode start_time %v", err)
-		}
-		args = args.Tail()
-	}
-
-	switch {
-	case ctx.IsSet("end_time"):
-		endTime = ctx.Uint64("end_time")
-	case args.Present():
-		endTime, err = strconv.ParseUint(args.First(), 10, 64)
-		if err != nil {
-			return fmt.Errorf("unable to decode end_time: %v", err)
-		}
-		args = args.Tail()
-	}
-
-	switch {
-	case ctx.IsSet("index_offset"):
-		indexOffset = uint32(ctx.Int64("index_offset"))
-	case args.Present():
-		i, err := strconv.ParseInt(args.First(), 10, 64)
-		if err != nil {
-			return fmt.Errorf("unable to decode index_offset: %v", err)
-		}
-		indexOffset = uint32(i)
-		args = args.Tail()
+	// Show command help if no arguments provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "exportchanauditlog")
+		return nil
 	}
 
-	switch {
-	case ctx.IsSet("max_events"):
-		maxEvents = uint32(ctx.Int64("max_events"))
-	case args.Present():
-		m, err := strconv.ParseInt(args.First(), 10, 64)
-		if err != nil {
-			return fmt.Errorf("unable to decode max_events: %v", err)
-		}
-		maxEvents = uint32(m)
-		args = args.Tail()
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
 	}
 
-	req := &lnrpc.ForwardingHistoryRequest{
-		StartTime:    startTime,
-		EndTime:      endTime,
-		IndexOffset:  indexOffset,
-		NumMaxEvents: maxEvents,
+	req := &lnrpc.ExportChannelAuditLogRequest{
+		ChannelPoint: channelPoint,
+		StartSeq:     ctx.Uint64("start_seq"),
+		MaxEvents:    uint32(ctx.Uint64("max_events")),
 	}
-	resp, err := client.ForwardingHistory(ctxb, req)
+	resp, err := client.ExportChannelAuditLog(ctxb, req)
 	if err != nil {
 		return err
 	}
@@ -3611,243 +5954,275 @@ func forwardingHistory(ctx *cli.Context) error {
 	return nil
 }
 
-var exportChanBackupCommand = cli.Command{
-	Name:     "exportchanbackup",
+var rebalanceCommand = cli.Command{
+	Name:     "rebalance",
 	Category: "Channels",
-	Usage: "Obtain a static channel back up for a selected channels, " +
-		"or all known channels",
-	ArgsUsage: "[chan_point] [--all] [--output_file]",
+	Usage:    "Move funds between two channels through a circular payment.",
+	ArgsUsage: "--outgoing_chan_id=O --incoming_chan_id=I --amt=A " +
+		"[--max_fee_msat=F]",
 	Description: `
-	This command allows a user to export a Static Channel Backup (SCB) for
-	a selected channel. SCB's are encrypted backups of a channel's initial
-	state that are encrypted with a key derived from the seed of a user. In
-	the case of partial or complete data loss, the SCB will allow the user
-	to reclaim settled funds in the channel at its final state. The
-	exported channel backups can be restored at a later time using the
-	restorechanbackup command.
-
-	This command will return one of two types of channel backups depending
-	on the set of passed arguments:
-
-	   * If a target channel point is specified, then a single channel
-	     backup containing only the information for that channel will be
-	     returned.
-
-	   * If the --all flag is passed, then a multi-channel backup will be
-	     returned. A multi backup is a single encrypted blob (displayed in
-	     hex encoding) that contains several channels in a single cipher
-	     text.
-
-	Both of the backup types can be restored using the restorechanbackup
-	command.
-	`,
+	Moves funds from the outgoing channel to the incoming channel by paying
+	ourselves along a circular route, which leaves through the outgoing
+	channel and returns through the incoming one. The amount is added to the
+	local balance of the incoming channel, while the amount along with the
+	routing fee is deducted from the outgoing channel.
+
+	If --max_fee_msat isn't set, the routing fee is limited by the
+	configured rebalance.maxfeerate. The fees of all rebalances are bounded
+	by rebalance.dailyfeebudget.`,
 	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name:  "chan_point",
-			Usage: "the target channel to obtain an SCB for",
+		cli.Uint64Flag{
+			Name: "outgoing_chan_id",
+			Usage: "the short channel id of the channel to " +
+				"move funds out of",
 		},
-		cli.BoolFlag{
-			Name: "all",
-			Usage: "if specified, then a multi backup of all " +
-				"active channels will be returned",
+		cli.Uint64Flag{
+			Name: "incoming_chan_id",
+			Usage: "the short channel id of the channel to " +
+				"move funds into",
 		},
-		cli.StringFlag{
-			Name: "output_file",
-			Usage: `
-			if specified, then rather than printing a JSON output
-			of the static channel backup, a serialized version of
-			the backup (either Single or Multi) will be written to
-			the target file, this is the same format used by lnd in
-			its channels.backup file `,
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the amount in satoshis to move",
+		},
+		cli.Int64Flag{
+			Name: "max_fee_msat",
+			Usage: "(optional) the maximum routing fee in " +
+				"milli-satoshis to pay",
 		},
 	},
-	Action: actionDecorator(exportChanBackup),
+	Action: actionDecorator(rebalance),
 }
 
-func exportChanBackup(ctx *cli.Context) error {
+func rebalance(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	// Show command help if no arguments provided
-	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
-		cli.ShowCommandHelp(ctx, "exportchanbackup")
-		return nil
+	if !ctx.IsSet("outgoing_chan_id") || !ctx.IsSet("incoming_chan_id") ||
+		!ctx.IsSet("amt") {
+
+		return cli.ShowCommandHelp(ctx, "rebalance")
 	}
 
-	var (
-		err          error
-		chanPointStr string
-	)
+	req := &lnrpc.RebalanceRequest{
+		OutgoingChanId: ctx.Uint64("outgoing_chan_id"),
+		IncomingChanId: ctx.Uint64("incoming_chan_id"),
+		Amt:            ctx.Int64("amt"),
+		MaxFeeMsat:     ctx.Int64("max_fee_msat"),
+	}
+
+	resp, err := client.Rebalance(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var setRebalanceTargetCommand = cli.Command{
+	Name:      "setrebalancetarget",
+	Category:  "Channels",
+	Usage:     "Set the target local balance ratio of a channel.",
+	ArgsUsage: "chan_id ratio",
+	Description: `
+	Sets the fraction of the channel's capacity, between 0 and 1, that should
+	make up our local balance. If rebalance.active is set, channels whose
+	local balance deviates from their target by more than the configured
+	rebalance.tolerance are periodically rebalanced against one another.`,
+	Action: actionDecorator(setRebalanceTarget),
+}
+
+func setRebalanceTarget(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
 	args := ctx.Args()
+	if len(args) != 2 {
+		return cli.ShowCommandHelp(ctx, "setrebalancetarget")
+	}
 
-	switch {
-	case ctx.IsSet("chan_point"):
-		chanPointStr = ctx.String("chan_point")
+	chanID, err := strconv.ParseUint(args.First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unable to decode chan_id: %v", err)
+	}
+	ratio, err := strconv.ParseFloat(args.Get(1), 64)
+	if err != nil {
+		return fmt.Errorf("unable to decode ratio: %v", err)
+	}
 
-	case args.Present():
-		chanPointStr = args.First()
+	req := &lnrpc.RebalanceTarget{
+		ChanId: chanID,
+		Ratio:  ratio,
+	}
 
-	case !ctx.IsSet("all"):
-		return fmt.Errorf("must specify chan_point if --all isn't set")
+	resp, err := client.SetRebalanceTarget(ctxb, req)
+	if err != nil {
+		return err
 	}
 
-	if chanPointStr != "" {
-		chanPointRPC, err := parseChanPoint(chanPointStr)
-		if err != nil {
-			return err
-		}
+	printRespJSON(resp)
+	return nil
+}
 
-		chanBackup, err := client.ExportChannelBackup(
-			ctxb, &lnrpc.ExportChannelBackupRequest{
-				ChanPoint: chanPointRPC,
-			},
-		)
-		if err != nil {
-			return err
-		}
+var removeRebalanceTargetCommand = cli.Command{
+	Name:      "removerebalancetarget",
+	Category:  "Channels",
+	Usage:     "Stop rebalancing a channel automatically.",
+	ArgsUsage: "chan_id",
+	Action:    actionDecorator(removeRebalanceTarget),
+}
 
-		txid, err := chainhash.NewHash(
-			chanPointRPC.GetFundingTxidBytes(),
-		)
-		if err != nil {
-			return err
-		}
+func removeRebalanceTarget(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-		chanPoint := wire.OutPoint{
-			Hash:  *txid,
-			Index: chanPointRPC.OutputIndex,
-		}
+	if !ctx.Args().Present() {
+		return cli.ShowCommandHelp(ctx, "removerebalancetarget")
+	}
 
-		printJSON(struct {
-			ChanPoint  string `json:"chan_point"`
-			ChanBackup string `json:"chan_backup"`
-		}{
-			ChanPoint: chanPoint.String(),
-			ChanBackup: hex.EncodeToString(
-				chanBackup.ChanBackup,
-			),
-		},
-		)
-		return nil
+	chanID, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unable to decode chan_id: %v", err)
 	}
 
-	if !ctx.IsSet("all") {
-		return fmt.Errorf("if a channel isn't specified, -all must be")
+	req := &lnrpc.RemoveRebalanceTargetRequest{
+		ChanId: chanID,
 	}
 
-	chanBackup, err := client.ExportAllChannelBackups(
-		ctxb, &lnrpc.ChanBackupExportRequest{},
-	)
+	resp, err := client.RemoveRebalanceTarget(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	if ctx.IsSet("output_file") {
-		return ioutil.WriteFile(
-			ctx.String("output_file"),
-			chanBackup.MultiChanBackup.MultiChanBackup,
-			0666,
-		)
-	}
+	printRespJSON(resp)
+	return nil
+}
 
-	// TODO(roasbeef): support for export | restore ?
+var listRebalanceTargetsCommand = cli.Command{
+	Name:     "listrebalancetargets",
+	Category: "Channels",
+	Usage:    "List the target local balance ratios of all channels.",
+	Action:   actionDecorator(listRebalanceTargets),
+}
 
-	var chanPoints []string
-	for _, chanPoint := range chanBackup.MultiChanBackup.ChanPoints {
-		txid, err := chainhash.NewHash(chanPoint.GetFundingTxidBytes())
-		if err != nil {
-			return err
-		}
+func listRebalanceTargets(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-		chanPoints = append(chanPoints, wire.OutPoint{
-			Hash:  *txid,
-			Index: chanPoint.OutputIndex,
-		}.String())
+	req := &lnrpc.ListRebalanceTargetsRequest{}
+	resp, err := client.ListRebalanceTargets(ctxb, req)
+	if err != nil {
+		return err
 	}
 
-	printJSON(struct {
-		ChanPoints      []string `json:"chan_points"`
-		MultiChanBackup string   `json:"multi_chan_backup"`
-	}{
-		ChanPoints: chanPoints,
-		MultiChanBackup: hex.EncodeToString(
-			chanBackup.MultiChanBackup.MultiChanBackup,
-		),
-	},
-	)
+	printRespJSON(resp)
 	return nil
 }
 
-var verifyChanBackupCommand = cli.Command{
-	Name:      "verifychanbackup",
-	Category:  "Channels",
-	Usage:     "Verify an existing channel backup",
-	ArgsUsage: "[--single_backup] [--multi_backup] [--multi_file]",
-	Description: `
-    This command allows a user to verify an existing Single or Multi channel
-    backup for integrity. This is useful when a user has a backup, but is
-    unsure as to if it's valid or for the target node.
+var listRebalancesCommand = cli.Command{
+	Name:     "listrebalances",
+	Category: "Channels",
+	Usage:    "List all completed rebalances.",
+	Action:   actionDecorator(listRebalances),
+}
 
-    The command will accept backups in one of three forms:
+func listRebalances(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-       * A single channel packed SCB, which can be obtained from
-	 exportchanbackup. This should be passed in hex encoded format.
+	req := &lnrpc.ListRebalancesRequest{}
+	resp, err := client.ListRebalances(ctxb, req)
+	if err != nil {
+		return err
+	}
 
-       * A packed multi-channel SCB, which couples several individual
-	 static channel backups in single blob.
+	printRespJSON(resp)
+	return nil
+}
 
-       * A file path which points to a packed multi-channel backup within a
-	 file, using the same format that lnd does in its channels.backup
-	 file.
-    `,
+var setFeeScheduleCommand = cli.Command{
+	Name:      "setfeeschedule",
+	Category:  "Channels",
+	Usage:     "Schedule the routing policy of a channel.",
+	ArgsUsage: "chan_id",
+	Description: `
+	Sets the schedule that selects the routing policy of a channel based on
+	its local balance and the time of day. The schedule is evaluated
+	periodically, and whenever the selected policy changes, the new policy
+	is announced to the network.
+
+	The default policy, set through the flags, is applied while none of the
+	rules hold. Rules are passed as a JSON array, where the first rule that
+	holds is applied, e.g.:
+
+	--rules='[{"below_local_ratio": 0.2, "policy": {"base_fee_msat": 1000,
+	"fee_rate_ppm": 500, "time_lock_delta": 40}}, {"start_hour": 22,
+	"end_hour": 6, "policy": {"base_fee_msat": 0, "fee_rate_ppm": 50,
+	"time_lock_delta": 40}}]'
+
+	Local ratios are fractions of the channel's capacity held on our side,
+	and hours are in UTC.`,
 	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name: "single_backup",
-			Usage: "a hex encoded single channel backup obtained " +
-				"from exportchanbackup",
+		cli.Int64Flag{
+			Name: "base_fee_msat",
+			Usage: "the base fee in milli-satoshis of the default " +
+				"policy",
 		},
-		cli.StringFlag{
-			Name: "multi_backup",
-			Usage: "a hex encoded multi-channel backup obtained " +
-				"from exportchanbackup",
+		cli.Int64Flag{
+			Name: "fee_rate_ppm",
+			Usage: "the fee rate in parts per million of the " +
+				"default policy",
+		},
+		cli.Int64Flag{
+			Name:  "time_lock_delta",
+			Usage: "the CLTV delta of the default policy",
+			Value: 40,
 		},
 		cli.StringFlag{
-			Name:  "multi_file",
-			Usage: "the path to a multi-channel back up file",
+			Name:  "rules",
+			Usage: "a JSON array of the rules of the schedule",
 		},
 	},
-	Action: actionDecorator(verifyChanBackup),
+	Action: actionDecorator(setFeeSchedule),
 }
 
-func verifyChanBackup(ctx *cli.Context) error {
+func setFeeSchedule(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	// Show command help if no arguments provided
-	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
-		cli.ShowCommandHelp(ctx, "verifychanbackup")
-		return nil
+	if !ctx.Args().Present() {
+		return cli.ShowCommandHelp(ctx, "setfeeschedule")
 	}
 
-	backups, err := parseChanBackups(ctx)
+	chanID, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to decode chan_id: %v", err)
 	}
 
-	verifyReq := lnrpc.ChanBackupSnapshot{}
-
-	if backups.GetChanBackups() != nil {
-		verifyReq.SingleChanBackups = backups.GetChanBackups()
-	}
-	if backups.GetMultiChanBackup() != nil {
-		verifyReq.MultiChanBackup = &lnrpc.MultiChanBackup{
-			MultiChanBackup: backups.GetMultiChanBackup(),
+	req := &lnrpc.FeeSchedule{}
+	if ctx.IsSet("rules") {
+		jsonSchedule := fmt.Sprintf(`{"rules": %s}`, ctx.String("rules"))
+		err := jsonpb.UnmarshalString(jsonSchedule, req)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal rules: %v", err)
 		}
 	}
 
-	resp, err := client.VerifyChanBackup(ctxb, &verifyReq)
+	req.ChanId = chanID
+	req.DefaultPolicy = &lnrpc.FeePolicy{
+		BaseFeeMsat:   uint64(ctx.Int64("base_fee_msat")),
+		FeeRatePpm:    uint32(ctx.Int64("fee_rate_ppm")),
+		TimeLockDelta: uint32(ctx.Int64("time_lock_delta")),
+	}
+
+	resp, err := client.SetFeeSchedule(ctxb, req)
 	if err != nil {
 		return err
 	}
@@ -3856,132 +6231,87 @@ func verifyChanBackup(ctx *cli.Context) error {
 	return nil
 }
 
-var restoreChanBackupCommand = cli.Command{
-	Name:     "restorechanbackup",
-	Category: "Channels",
-	Usage: "Restore an existing single or multi-channel static channel " +
-		"backup",
-	ArgsUsage: "[--single_backup] [--multi_backup] [--multi_file=",
+var removeFeeScheduleCommand = cli.Command{
+	Name:      "removefeeschedule",
+	Category:  "Channels",
+	Usage:     "Stop scheduling the routing policy of a channel.",
+	ArgsUsage: "chan_id",
 	Description: `
-	Allows a suer to restore a Static Channel Backup (SCB) that was
-	obtained either via the exportchanbackup command, or from lnd's
-	automatically manged channels.backup file. This command should be used
-	if a user is attempting to restore a channel due to data loss on a
-	running node restored with the same seed as the node that created the
-	channel. If successful, this command will allows the user to recover
-	the settled funds stored in the recovered channels.
-
-	The command will accept backups in one of three forms:
-
-	   * A single channel packed SCB, which can be obtained from
-	     exportchanbackup. This should be passed in hex encoded format.
-
-	   * A packed multi-channel SCB, which couples several individual
-	     static channel backups in single blob.
-
-	   * A file path which points to a packed multi-channel backup within a
-	     file, using the same format that lnd does in its channels.backup
-	     file.
-	`,
-	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name: "single_backup",
-			Usage: "a hex encoded single channel backup obtained " +
-				"from exportchanbackup",
-		},
-		cli.StringFlag{
-			Name: "multi_backup",
-			Usage: "a hex encoded multi-channel backup obtained " +
-				"from exportchanbackup",
-		},
-		cli.StringFlag{
-			Name:  "multi_file",
-			Usage: "the path to a multi-channel back up file",
-		},
-	},
-	Action: actionDecorator(restoreChanBackup),
+	Removes the fee schedule of a channel. The policy last applied to the
+	channel remains in effect.`,
+	Action: actionDecorator(removeFeeSchedule),
 }
 
-func parseChanBackups(ctx *cli.Context) (*lnrpc.RestoreChanBackupRequest, error) {
-	switch {
-	case ctx.IsSet("single_backup"):
-		packedBackup, err := hex.DecodeString(
-			ctx.String("single_backup"),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to decode single packed "+
-				"backup: %v", err)
-		}
+func removeFeeSchedule(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-		return &lnrpc.RestoreChanBackupRequest{
-			Backup: &lnrpc.RestoreChanBackupRequest_ChanBackups{
-				ChanBackups: &lnrpc.ChannelBackups{
-					ChanBackups: []*lnrpc.ChannelBackup{
-						{
-							ChanBackup: packedBackup,
-						},
-					},
-				},
-			},
-		}, nil
+	if !ctx.Args().Present() {
+		return cli.ShowCommandHelp(ctx, "removefeeschedule")
+	}
 
-	case ctx.IsSet("multi_backup"):
-		packedMulti, err := hex.DecodeString(
-			ctx.String("multi_backup"),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to decode multi packed "+
-				"backup: %v", err)
-		}
+	chanID, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unable to decode chan_id: %v", err)
+	}
 
-		return &lnrpc.RestoreChanBackupRequest{
-			Backup: &lnrpc.RestoreChanBackupRequest_MultiChanBackup{
-				MultiChanBackup: packedMulti,
-			},
-		}, nil
+	req := &lnrpc.RemoveFeeScheduleRequest{
+		ChanId: chanID,
+	}
 
-	case ctx.IsSet("multi_file"):
-		packedMulti, err := ioutil.ReadFile(ctx.String("multi_file"))
-		if err != nil {
-			return nil, fmt.Errorf("unable to decode multi packed "+
-				"backup: %v", err)
-		}
+	resp, err := client.RemoveFeeSchedule(ctxb, req)
+	if err != nil {
+		return err
+	}
 
-		return &lnrpc.RestoreChanBackupRequest{
-			Backup: &lnrpc.RestoreChanBackupRequest_MultiChanBackup{
-				MultiChanBackup: packedMulti,
-			},
-		}, nil
+	printRespJSON(resp)
+	return nil
+}
 
-	default:
-		return nil, nil
-	}
+var listFeeSchedulesCommand = cli.Command{
+	Name:     "listfeeschedules",
+	Category: "Channels",
+	Usage:    "List the fee schedules of all channels.",
+	Action:   actionDecorator(listFeeSchedules),
 }
 
-func restoreChanBackup(ctx *cli.Context) error {
+func listFeeSchedules(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	// Show command help if no arguments provided
-	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
-		cli.ShowCommandHelp(ctx, "restorechanbackup")
-		return nil
-	}
-
-	var req lnrpc.RestoreChanBackupRequest
-
-	backups, err := parseChanBackups(ctx)
+	req := &lnrpc.ListFeeSchedulesRequest{}
+	resp, err := client.ListFeeSchedules(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	req.Backup = backups.Backup
+	printRespJSON(resp)
+	return nil
+}
 
-	_, err = client.RestoreChannelBackups(ctxb, &req)
+var getChainStatusCommand = cli.Command{
+	Name:  "getchainstatus",
+	Usage: "Returns the health of the chain backend.",
+	Description: `
+	Returns whether the chain backend is reachable and synced to the tip of
+	the network, along with its best block, its latency, and how often it
+	disconnected or fell behind since lnd was started.`,
+	Action: actionDecorator(getChainStatus),
+}
+
+func getChainStatus(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GetChainStatusRequest{}
+	resp, err := client.GetChainStatus(ctxb, req)
 	if err != nil {
-		return fmt.Errorf("unable to restore chan backups: %v", err)
+		return err
 	}
 
+	printRespJSON(resp)
 	return nil
 }
@@ -251,6 +251,7 @@ func main() {
 			Name:  "macaroonip",
 			Usage: "if set, lock macaroon to specific IP address",
 		},
+		unitFlag,
 	}
 	app.Commands = []cli.Command{
 		createCommand,
@@ -264,6 +265,9 @@ func main() {
 		connectCommand,
 		disconnectCommand,
 		openChannelCommand,
+		batchOpenChannelCommand,
+		spliceInCommand,
+		spliceOutCommand,
 		closeChannelCommand,
 		closeAllChannelsCommand,
 		abandonChannelCommand,
@@ -278,31 +282,70 @@ func main() {
 		addInvoiceCommand,
 		lookupInvoiceCommand,
 		listInvoicesCommand,
+		deleteInvoicesCommand,
 		listChannelsCommand,
 		closedChannelsCommand,
 		listPaymentsCommand,
+		deletePaymentsCommand,
+		feeBudgetCommand,
+		addScheduleCommand,
+		listSchedulesCommand,
+		extendScheduleCommand,
+		removeScheduleCommand,
+		deriveSwapKeyCommand,
+		listSwapsCommand,
 		describeGraphCommand,
 		getChanInfoCommand,
 		getNodeInfoCommand,
 		queryRoutesCommand,
 		getNetworkInfoCommand,
+		gossipStatsCommand,
+		updateGossipHorizonCommand,
+		exportGraphCommand,
+		importGraphCommand,
 		debugLevelCommand,
+		compactDBCommand,
+		checkDBCommand,
 		decodePayReqCommand,
 		listChainTxnsCommand,
+		listSubsystemsCommand,
+		modifySubsystemCommand,
+		getResourceBudgetCommand,
+		setResourceProfileCommand,
 		stopCommand,
+		rotateNodeKeyCommand,
+		listNodeKeyRotationsCommand,
 		signMessageCommand,
 		verifyMessageCommand,
 		feeReportCommand,
 		updateChannelPolicyCommand,
 		forwardingHistoryCommand,
+		forwardingSummaryCommand,
+		htlcLatencyCommand,
 		exportChanBackupCommand,
 		verifyChanBackupCommand,
+		backupRecoverabilityCommand,
+		listBackupFilesCommand,
 		restoreChanBackupCommand,
+		exportJournalCommand,
+		anchorJournalCommand,
+		exportChanAuditLogCommand,
+		rebalanceCommand,
+		setRebalanceTargetCommand,
+		removeRebalanceTargetCommand,
+		listRebalanceTargetsCommand,
+		listRebalancesCommand,
+		setFeeScheduleCommand,
+		removeFeeScheduleCommand,
+		listFeeSchedulesCommand,
+		getChainStatusCommand,
 	}
 
 	// Add any extra autopilot commands determined by build flags.
 	app.Commands = append(app.Commands, autopilotCommands()...)
 	app.Commands = append(app.Commands, invoicesCommands()...)
+	app.Commands = append(app.Commands, towerCommands()...)
+	app.Commands = append(app.Commands, wtclientCommands()...)
 
 	if err := app.Run(os.Args); err != nil {
 		fatal(err)
@@ -0,0 +1,393 @@
+// +build towerrpc
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/litecoinfinance/lnd/lnrpc/towerrpc"
+	"github.com/urfave/cli"
+)
+
+func getTowerClient(ctx *cli.Context) (towerrpc.TowerClient, func()) {
+	conn := getClientConn(ctx, false)
+
+	cleanUp := func() {
+		conn.Close()
+	}
+
+	return towerrpc.NewTowerClient(conn), cleanUp
+}
+
+var towerInfoCommand = cli.Command{
+	Name:        "info",
+	Usage:       "Get the identity and resource usage of the watchtower.",
+	Description: "",
+	Action:      actionDecorator(towerInfo),
+}
+
+func towerInfo(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getTowerClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetInfo(ctxb, &towerrpc.GetInfoRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var towerSessionsCommand = cli.Command{
+	Name:        "sessions",
+	Usage:       "List the sessions negotiated with the tower's clients.",
+	Description: "",
+	Action:      actionDecorator(towerSessions),
+}
+
+func towerSessions(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getTowerClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListSessions(
+		ctxb, &towerrpc.ListSessionsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// parseTowerClientID parses the hex encoded session id of a tower client from
+// the first positional argument.
+func parseTowerClientID(ctx *cli.Context) ([]byte, error) {
+	if !ctx.Args().Present() {
+		return nil, fmt.Errorf("session id argument missing")
+	}
+
+	id, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode session id: %v", err)
+	}
+
+	return id, nil
+}
+
+var towerBlacklistCommand = cli.Command{
+	Name:      "blacklist",
+	Usage:     "Refuse any further connections from a client.",
+	ArgsUsage: "session_id",
+	Description: `
+	Blacklist the client with the given hex encoded session id. The client
+	is disconnected if it is currently connected, though its sessions
+	continue to be watched.`,
+	Action: actionDecorator(towerBlacklist),
+}
+
+func towerBlacklist(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getTowerClient(ctx)
+	defer cleanUp()
+
+	id, err := parseTowerClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.BlacklistClient(
+		ctxb, &towerrpc.BlacklistClientRequest{Id: id},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var towerUnblacklistCommand = cli.Command{
+	Name:        "unblacklist",
+	Usage:       "Allow a blacklisted client to connect once again.",
+	ArgsUsage:   "session_id",
+	Description: "",
+	Action:      actionDecorator(towerUnblacklist),
+}
+
+func towerUnblacklist(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getTowerClient(ctx)
+	defer cleanUp()
+
+	id, err := parseTowerClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.UnblacklistClient(
+		ctxb, &towerrpc.UnblacklistClientRequest{Id: id},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var towerBlacklistedCommand = cli.Command{
+	Name:        "blacklisted",
+	Usage:       "List the blacklisted clients.",
+	Description: "",
+	Action:      actionDecorator(towerBlacklisted),
+}
+
+func towerBlacklisted(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getTowerClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListBlacklistedClients(
+		ctxb, &towerrpc.ListBlacklistedClientsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var towerPruneCommand = cli.Command{
+	Name:  "prune",
+	Usage: "Delete sessions whose clients have been inactive.",
+	Description: `
+	Delete all sessions that haven't been updated by their client for at
+	least the given number of seconds, along with their encrypted blobs.`,
+	Action: actionDecorator(towerPrune),
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "inactive_secs",
+			Usage: "the number of seconds a session must have " +
+				"been inactive for to be pruned",
+		},
+	},
+}
+
+func towerPrune(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getTowerClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("inactive_secs") {
+		return fmt.Errorf("inactive_secs must be set")
+	}
+
+	resp, err := client.PruneSessions(ctxb, &towerrpc.PruneSessionsRequest{
+		InactiveSecs: ctx.Uint64("inactive_secs"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var towerPolicyBoundsCommand = cli.Command{
+	Name:        "policybounds",
+	Usage:       "Get the bounds restricting new session policies.",
+	Description: "",
+	Action:      actionDecorator(towerPolicyBounds),
+}
+
+func towerPolicyBounds(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getTowerClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetPolicyBounds(
+		ctxb, &towerrpc.GetPolicyBoundsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var towerSetPolicyBoundsCommand = cli.Command{
+	Name:  "setpolicybounds",
+	Usage: "Update the bounds restricting new session policies.",
+	Description: `
+	Update the bounds restricting the policies clients may negotiate new
+	sessions with. Bounds that aren't specified retain their current
+	value, while a value of zero disables the respective maximum. Sessions
+	that have already been negotiated aren't affected.`,
+	Action: actionDecorator(towerSetPolicyBounds),
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "max_updates",
+			Usage: "the largest number of updates a client may " +
+				"request for a single session",
+		},
+		cli.Uint64Flag{
+			Name: "min_reward_base",
+			Usage: "the minimum fixed reward in litoshis of " +
+				"reward sessions",
+		},
+		cli.Uint64Flag{
+			Name: "max_reward_base",
+			Usage: "the maximum fixed reward in litoshis of " +
+				"reward sessions",
+		},
+		cli.Uint64Flag{
+			Name: "min_reward_rate",
+			Usage: "the minimum proportional reward in millionths " +
+				"of reward sessions",
+		},
+		cli.Uint64Flag{
+			Name: "max_reward_rate",
+			Usage: "the maximum proportional reward in millionths " +
+				"of reward sessions",
+		},
+		cli.Int64Flag{
+			Name:  "min_sweep_fee_rate",
+			Usage: "the minimum sweep fee rate in sat/kw",
+		},
+		cli.Int64Flag{
+			Name:  "max_sweep_fee_rate",
+			Usage: "the maximum sweep fee rate in sat/kw",
+		},
+		cli.Int64SliceFlag{
+			Name: "blob_type",
+			Usage: "a blob type clients may negotiate, can be " +
+				"specified multiple times",
+		},
+		cli.BoolFlag{
+			Name:  "all_blob_types",
+			Usage: "accept all blob types supported by the tower",
+		},
+	},
+}
+
+func towerSetPolicyBounds(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getTowerClient(ctx)
+	defer cleanUp()
+
+	if ctx.NumFlags() == 0 {
+		return cli.ShowCommandHelp(ctx, "setpolicybounds")
+	}
+
+	if ctx.IsSet("blob_type") && ctx.Bool("all_blob_types") {
+		return fmt.Errorf("blob_type and all_blob_types are mutually " +
+			"exclusive")
+	}
+
+	// Start from the bounds currently enforced by the tower, such that
+	// only the specified bounds are modified.
+	bounds, err := client.GetPolicyBounds(
+		ctxb, &towerrpc.GetPolicyBoundsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	if ctx.IsSet("max_updates") {
+		bounds.MaxUpdates = uint32(ctx.Uint64("max_updates"))
+	}
+	if ctx.IsSet("min_reward_base") {
+		bounds.MinRewardBase = uint32(ctx.Uint64("min_reward_base"))
+	}
+	if ctx.IsSet("max_reward_base") {
+		bounds.MaxRewardBase = uint32(ctx.Uint64("max_reward_base"))
+	}
+	if ctx.IsSet("min_reward_rate") {
+		bounds.MinRewardRate = uint32(ctx.Uint64("min_reward_rate"))
+	}
+	if ctx.IsSet("max_reward_rate") {
+		bounds.MaxRewardRate = uint32(ctx.Uint64("max_reward_rate"))
+	}
+	if ctx.IsSet("min_sweep_fee_rate") {
+		bounds.MinSweepFeeRate = ctx.Int64("min_sweep_fee_rate")
+	}
+	if ctx.IsSet("max_sweep_fee_rate") {
+		bounds.MaxSweepFeeRate = ctx.Int64("max_sweep_fee_rate")
+	}
+	switch {
+	case ctx.IsSet("blob_type"):
+		bounds.BlobTypes = nil
+		for _, blobType := range ctx.Int64Slice("blob_type") {
+			bounds.BlobTypes = append(
+				bounds.BlobTypes, uint32(blobType),
+			)
+		}
+
+	case ctx.Bool("all_blob_types"):
+		bounds.BlobTypes = nil
+	}
+
+	resp, err := client.SetPolicyBounds(
+		ctxb, &towerrpc.SetPolicyBoundsRequest{Bounds: bounds},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var towerPoliciesCommand = cli.Command{
+	Name:        "policies",
+	Usage:       "List the distinct policies negotiated by clients.",
+	Description: "",
+	Action:      actionDecorator(towerPolicies),
+}
+
+func towerPolicies(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getTowerClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListSessionPolicies(
+		ctxb, &towerrpc.ListSessionPoliciesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// towerCommands will return the set of commands to enable for towerrpc builds.
+func towerCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:        "tower",
+			Category:    "Watchtower",
+			Usage:       "Administer the watchtower.",
+			Description: "",
+			Subcommands: []cli.Command{
+				towerInfoCommand,
+				towerSessionsCommand,
+				towerBlacklistCommand,
+				towerUnblacklistCommand,
+				towerBlacklistedCommand,
+				towerPruneCommand,
+				towerPolicyBoundsCommand,
+				towerSetPolicyBoundsCommand,
+				towerPoliciesCommand,
+			},
+		},
+	}
+}
@@ -0,0 +1,10 @@
+// +build !towerrpc
+
+package main
+
+import "github.com/urfave/cli"
+
+// towerCommands will return nil for non-towerrpc builds.
+func towerCommands() []cli.Command {
+	return nil
+}
@@ -38,3 +38,145 @@ func NewUtxoFromProto(utxo *lnrpc.Utxo) *Utxo {
 		Confirmations: utxo.Confirmations,
 	}
 }
+
+// ChannelBalance displays the channel balances of the node in a display unit.
+type ChannelBalance struct {
+	Balance            string `json:"balance"`
+	PendingOpenBalance string `json:"pending_open_balance"`
+}
+
+// NewChannelBalanceFromProto formats the milli-litoshi balances of the
+// ChannelBalanceResponse proto in the given display unit.
+func NewChannelBalanceFromProto(resp *lnrpc.ChannelBalanceResponse,
+	unit *displayUnit) *ChannelBalance {
+
+	return &ChannelBalance{
+		Balance: unit.FormatSigned(resp.BalanceMsat),
+		PendingOpenBalance: unit.FormatSigned(
+			resp.PendingOpenBalanceMsat,
+		),
+	}
+}
+
+// FeeReport displays the fee schedule of each channel, along with the fee
+// revenue of the node in a display unit.
+type FeeReport struct {
+	ChannelFees []*ChannelFeeReport `json:"channel_fees"`
+	DayFeeSum   string              `json:"day_fee_sum"`
+	WeekFeeSum  string              `json:"week_fee_sum"`
+	MonthFeeSum string              `json:"month_fee_sum"`
+}
+
+// ChannelFeeReport displays the fee schedule of a channel, with its base fee
+// in a display unit.
+type ChannelFeeReport struct {
+	ChanPoint string  `json:"channel_point"`
+	BaseFee   string  `json:"base_fee"`
+	FeePerMil int64   `json:"fee_per_mil"`
+	FeeRate   float64 `json:"fee_rate"`
+}
+
+// NewFeeReportFromProto formats the milli-litoshi amounts of the
+// FeeReportResponse proto in the given display unit.
+func NewFeeReportFromProto(resp *lnrpc.FeeReportResponse,
+	unit *displayUnit) *FeeReport {
+
+	channelFees := make([]*ChannelFeeReport, 0, len(resp.ChannelFees))
+	for _, chanFee := range resp.ChannelFees {
+		channelFees = append(channelFees, &ChannelFeeReport{
+			ChanPoint: chanFee.ChanPoint,
+			BaseFee:   unit.FormatSigned(chanFee.BaseFeeMsat),
+			FeePerMil: chanFee.FeePerMil,
+			FeeRate:   chanFee.FeeRate,
+		})
+	}
+
+	return &FeeReport{
+		ChannelFees: channelFees,
+		DayFeeSum:   unit.Format(resp.DayFeeSumMsat),
+		WeekFeeSum:  unit.Format(resp.WeekFeeSumMsat),
+		MonthFeeSum: unit.Format(resp.MonthFeeSumMsat),
+	}
+}
+
+// ForwardingHistory displays a slice of the forwarding log, with the amounts
+// of each event in a display unit.
+type ForwardingHistory struct {
+	ForwardingEvents []*ForwardingEvent `json:"forwarding_events"`
+	LastOffsetIndex  uint32             `json:"last_offset_index"`
+}
+
+// ForwardingEvent displays a forwarding event, with its amounts in a display
+// unit.
+type ForwardingEvent struct {
+	Timestamp uint64 `json:"timestamp"`
+	ChanIDIn  uint64 `json:"chan_id_in"`
+	ChanIDOut uint64 `json:"chan_id_out"`
+	AmtIn     string `json:"amt_in"`
+	AmtOut    string `json:"amt_out"`
+	Fee       string `json:"fee"`
+}
+
+// NewForwardingHistoryFromProto formats the milli-litoshi amounts of the
+// ForwardingHistoryResponse proto in the given display unit.
+func NewForwardingHistoryFromProto(resp *lnrpc.ForwardingHistoryResponse,
+	unit *displayUnit) *ForwardingHistory {
+
+	events := make([]*ForwardingEvent, 0, len(resp.ForwardingEvents))
+	for _, event := range resp.ForwardingEvents {
+		events = append(events, &ForwardingEvent{
+			Timestamp: event.Timestamp,
+			ChanIDIn:  event.ChanIdIn,
+			ChanIDOut: event.ChanIdOut,
+			AmtIn:     unit.Format(event.AmtInMsat),
+			AmtOut:    unit.Format(event.AmtOutMsat),
+			Fee:       unit.Format(event.FeeMsat),
+		})
+	}
+
+	return &ForwardingHistory{
+		ForwardingEvents: events,
+		LastOffsetIndex:  resp.LastOffsetIndex,
+	}
+}
+
+// ForwardingSummaries displays the daily forwarding summaries of channels,
+// with the amounts of each summary in a display unit.
+type ForwardingSummaries struct {
+	Summaries []*ChannelForwardingSummary `json:"summaries"`
+}
+
+// ChannelForwardingSummary displays the forwarding summary of a channel during
+// a day, with its amounts in a display unit.
+type ChannelForwardingSummary struct {
+	Day         uint64 `json:"day"`
+	ChanID      uint64 `json:"chan_id"`
+	NumIncoming uint64 `json:"num_incoming"`
+	NumOutgoing uint64 `json:"num_outgoing"`
+	AmtIn       string `json:"amt_in"`
+	AmtOut      string `json:"amt_out"`
+	Fee         string `json:"fee"`
+}
+
+// NewForwardingSummariesFromProto formats the milli-litoshi amounts of the
+// ForwardingSummaryResponse proto in the given display unit.
+func NewForwardingSummariesFromProto(resp *lnrpc.ForwardingSummaryResponse,
+	unit *displayUnit) *ForwardingSummaries {
+
+	summaries := make([]*ChannelForwardingSummary, 0, len(resp.Summaries))
+	for _, summary := range resp.Summaries {
+		summaries = append(summaries, &ChannelForwardingSummary{
+			Day:         summary.Day,
+			ChanID:      summary.ChanId,
+			NumIncoming: summary.NumIncoming,
+			NumOutgoing: summary.NumOutgoing,
+			AmtIn:       unit.Format(summary.AmtInMsat),
+			AmtOut:      unit.Format(summary.AmtOutMsat),
+			Fee:         unit.Format(summary.FeeMsat),
+		})
+	}
+
+	return &ForwardingSummaries{
+		Summaries: summaries,
+	}
+}
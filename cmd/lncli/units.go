@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// displayUnit is a denomination in which lncli can display amounts.
+type displayUnit struct {
+	// name is the suffix appended to amounts displayed in this unit.
+	name string
+
+	// msatPerUnit is the number of milli-litoshis in a single unit. It
+	// must be a power of ten.
+	msatPerUnit uint64
+}
+
+var (
+	// unitMilliLitoshi displays amounts in milli-litoshis, the smallest
+	// unit used within channels.
+	unitMilliLitoshi = &displayUnit{name: "mlit", msatPerUnit: 1}
+
+	// unitLitoshi displays amounts in litoshis, the smallest unit that can
+	// be settled on chain.
+	unitLitoshi = &displayUnit{name: "lit", msatPerUnit: 1000}
+
+	// unitLTFN displays amounts in whole coins.
+	unitLTFN = &displayUnit{name: "LTFN", msatPerUnit: 100000000000}
+
+	// displayUnits maps the names accepted by the --unit flag to the
+	// corresponding display units.
+	displayUnits = map[string]*displayUnit{
+		"msat":    unitMilliLitoshi,
+		"mlit":    unitMilliLitoshi,
+		"sat":     unitLitoshi,
+		"lit":     unitLitoshi,
+		"litoshi": unitLitoshi,
+		"ltfn":    unitLTFN,
+	}
+)
+
+// unitFlag is the global flag selecting the unit in which amounts are
+// displayed by the commands supporting it.
+var unitFlag = cli.StringFlag{
+	Name: "unit",
+	Usage: "if set, display amounts in the given unit rather than " +
+		"returning the raw response, one of mlit (msat), lit " +
+		"(litoshi, sat), or ltfn",
+}
+
+// parseDisplayUnit returns the display unit selected by the --unit flag, or
+// nil if none was selected.
+func parseDisplayUnit(ctx *cli.Context) (*displayUnit, error) {
+	name := strings.ToLower(ctx.GlobalString(unitFlag.Name))
+	if name == "" {
+		return nil, nil
+	}
+
+	unit, ok := displayUnits[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown display unit: %v", name)
+	}
+
+	return unit, nil
+}
+
+// Format returns the given milli-litoshi amount expressed in the unit. The
+// amount is exact, as trailing zeros are the only digits trimmed from the
+// fractional part.
+func (u *displayUnit) Format(msat uint64) string {
+	whole := msat / u.msatPerUnit
+	frac := msat % u.msatPerUnit
+
+	if frac == 0 {
+		return fmt.Sprintf("%d %s", whole, u.name)
+	}
+
+	// The number of decimals is the number of digits needed to express
+	// the largest fractional part.
+	decimals := len(fmt.Sprintf("%d", u.msatPerUnit-1))
+	fracStr := strings.TrimRight(fmt.Sprintf("%0*d", decimals, frac), "0")
+
+	return fmt.Sprintf("%d.%s %s", whole, fracStr, u.name)
+}
+
+// FormatSigned returns the given signed milli-litoshi amount expressed in the
+// unit.
+func (u *displayUnit) FormatSigned(msat int64) string {
+	if msat < 0 {
+		return "-" + u.Format(uint64(-msat))
+	}
+
+	return u.Format(uint64(msat))
+}
@@ -0,0 +1,136 @@
+// +build wtclientrpc
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/litecoinfinance/lnd/lnrpc/wtclientrpc"
+	"github.com/urfave/cli"
+)
+
+func getWtclient(ctx *cli.Context) (wtclientrpc.WatchtowerClientClient,
+	func()) {
+
+	conn := getClientConn(ctx, false)
+
+	cleanUp := func() {
+		conn.Close()
+	}
+
+	return wtclientrpc.NewWatchtowerClientClient(conn), cleanUp
+}
+
+var wtclientSessionsCommand = cli.Command{
+	Name: "sessions",
+	Usage: "List the watchtower client's sessions that have not been " +
+		"exhausted, and the number of backups each can still hold.",
+	Description: "",
+	Action:      actionDecorator(wtclientSessions),
+}
+
+func wtclientSessions(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListSessions(
+		ctxb, &wtclientrpc.ListSessionsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var wtclientUpdateTowerCommand = cli.Command{
+	Name:      "updatetower",
+	Usage:     "Replace the addresses of a watchtower.",
+	ArgsUsage: "pubkey address [address...]",
+	Description: `
+	Replace the addresses at which the watchtower client reaches the tower
+	with the given public key, without having to remove and re-add the
+	tower. The addresses are tried in the given order, and may include
+	onion addresses if lnd is configured to use Tor.`,
+	Action: actionDecorator(wtclientUpdateTower),
+}
+
+func wtclientUpdateTower(ctx *cli.Context) error {
+	ctxb := context.Background()
+
+	args := ctx.Args()
+	if len(args) < 2 {
+		return cli.ShowCommandHelp(ctx, "updatetower")
+	}
+
+	pubKey, err := hex.DecodeString(args.First())
+	if err != nil {
+		return fmt.Errorf("invalid tower pubkey: %v", err)
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	resp, err := client.UpdateTowerAddresses(
+		ctxb, &wtclientrpc.UpdateTowerAddressesRequest{
+			Pubkey:    pubKey,
+			Addresses: args.Tail(),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var wtclientStatsCommand = cli.Command{
+	Name: "stats",
+	Usage: "Account for the backups made to each watchtower, and the " +
+		"value they protect.",
+	Description: `
+	For each watchtower and each session negotiated with it, including
+	exhausted sessions, report the number of backups sent, acked and
+	pending, the negotiated policy, and an estimate of the on-chain value
+	protected by the acked backups.`,
+	Action: actionDecorator(wtclientStats),
+}
+
+func wtclientStats(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	resp, err := client.WtclientSessionStats(
+		ctxb, &wtclientrpc.WtclientSessionStatsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// wtclientCommands will return the set of commands to enable for wtclientrpc
+// builds.
+func wtclientCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:        "wtclient",
+			Category:    "Watchtower",
+			Usage:       "Inspect and manage the watchtower client.",
+			Description: "",
+			Subcommands: []cli.Command{
+				wtclientSessionsCommand,
+				wtclientUpdateTowerCommand,
+				wtclientStatsCommand,
+			},
+		},
+	}
+}
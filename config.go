@@ -5,8 +5,10 @@
 package lnd
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
@@ -19,17 +21,25 @@ import (
 	"strings"
 	"time"
 
-	"github.com/litecoinfinance/btcutil"
 	flags "github.com/jessevdk/go-flags"
+	"github.com/litecoinfinance/btcutil"
 	"github.com/litecoinfinance/lnd/build"
+	"github.com/litecoinfinance/lnd/chainhealth"
 	"github.com/litecoinfinance/lnd/chanbackup"
 	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/discovery"
+	"github.com/litecoinfinance/lnd/feepolicy"
+	"github.com/litecoinfinance/lnd/htlcswitch"
 	"github.com/litecoinfinance/lnd/htlcswitch/hodl"
+	"github.com/litecoinfinance/lnd/invoices"
 	"github.com/litecoinfinance/lnd/lncfg"
 	"github.com/litecoinfinance/lnd/lnrpc/signrpc"
+	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/rebalance"
+	"github.com/litecoinfinance/lnd/resources"
 	"github.com/litecoinfinance/lnd/routing"
+	"github.com/litecoinfinance/lnd/sweep"
 	"github.com/litecoinfinance/lnd/tor"
 )
 
@@ -51,6 +61,7 @@ const (
 	defaultPeerPort                 = 9735
 	defaultRPCHost                  = "localhost"
 	defaultMaxPendingChannels       = 1
+	defaultMaxZeroConfExposure      = 5000000
 	defaultNoSeedBackup             = false
 	defaultTrickleDelay             = 90 * 1000
 	defaultChanStatusSampleInterval = time.Minute
@@ -60,6 +71,7 @@ const (
 	defaultMaxLogFileSize           = 10
 	defaultMinBackoff               = time.Second
 	defaultMaxBackoff               = time.Hour
+	defaultBackupVerifyInterval     = 24 * time.Hour
 
 	defaultTorSOCKSPort            = 9050
 	defaultTorDNSHost              = "soa.nodes.lightning.directory"
@@ -136,7 +148,7 @@ var (
 	defaultLtfndDir         = btcutil.AppDataDir("ltfnd", false)
 	defaultLtfndRPCCertFile = filepath.Join(defaultLtfndDir, "rpc.cert")
 
-	defaultBitcoindDir  = btcutil.AppDataDir("bitcoin", false)
+	defaultBitcoindDir         = btcutil.AppDataDir("bitcoin", false)
 	defaultLitecoinfinancedDir = btcutil.AppDataDir("litecoinfinance", false)
 
 	defaultTorSOCKS   = net.JoinHostPort("localhost", strconv.Itoa(defaultTorSOCKSPort))
@@ -182,12 +194,17 @@ type btcdConfig struct {
 }
 
 type bitcoindConfig struct {
-	Dir            string `long:"dir" description:"The base directory that contains the node's data, logs, configuration file, etc."`
-	RPCHost        string `long:"rpchost" description:"The daemon's rpc listening address. If a port is omitted, then the default port for the selected chain parameters will be used."`
-	RPCUser        string `long:"rpcuser" description:"Username for RPC connections"`
-	RPCPass        string `long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
-	ZMQPubRawBlock string `long:"zmqpubrawblock" description:"The address listening for ZMQ connections to deliver raw block notifications"`
-	ZMQPubRawTx    string `long:"zmqpubrawtx" description:"The address listening for ZMQ connections to deliver raw transaction notifications"`
+	Dir              string   `long:"dir" description:"The base directory that contains the node's data, logs, configuration file, etc."`
+	RPCHost          string   `long:"rpchost" description:"The daemon's rpc listening address. If a port is omitted, then the default port for the selected chain parameters will be used."`
+	RPCUser          string   `long:"rpcuser" description:"Username for RPC connections"`
+	RPCPass          string   `long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
+	ZMQPubRawBlock   string   `long:"zmqpubrawblock" description:"The address listening for ZMQ connections to deliver raw block notifications"`
+	ZMQPubRawTx      string   `long:"zmqpubrawtx" description:"The address listening for ZMQ connections to deliver raw transaction notifications"`
+	PollBlocks       bool     `long:"pollblocks" description:"Poll the daemon over RPC for the new blocks chain notifications are dispatched for, rather than receiving them over ZMQ. Useful if the daemon's ZMQ notifications can't be reached"`
+	BlockFilters     bool     `long:"blockfilters" description:"Match the compact block filters served by the daemon against channel outputs, rather than scanning every block for their spends. Requires the daemon to run with blockfilterindex=1"`
+	Pruned           bool     `long:"pruned" description:"Set if the daemon prunes its blocks. Historical blocks needed to resolve channels or validate the channel graph are then fetched from the P2P network once the daemon has pruned them"`
+	PrunedBlockPeers []string `long:"prunedblockpeer" description:"A full node to fetch pruned blocks from, in addition to the outbound peers of the daemon. Can be set multiple times"`
+	FallbackBtcd     bool     `long:"fallbackbtcd" description:"Whenever the daemon can't be reached, watch for closed channels through the btcd/ltfnd node configured in the corresponding section instead"`
 }
 
 type autoPilotConfig struct {
@@ -217,7 +234,8 @@ type torConfig struct {
 // See loadConfig for further details regarding the configuration
 // loading+parsing process.
 type config struct {
-	ShowVersion bool `short:"V" long:"version" description:"Display version information and exit"`
+	ShowVersion    bool `short:"V" long:"version" description:"Display version information and exit"`
+	ValidateConfig bool `long:"validateconfig" description:"Load and validate the configuration, print the fully resolved effective configuration and exit"`
 
 	LndDir         string `long:"lnddir" description:"The base directory that contains lnd's data, logs, configuration file, etc."`
 	ConfigFile     string `long:"C" long:"configfile" description:"Path to configuration file"`
@@ -263,13 +281,15 @@ type config struct {
 	MaxPendingChannels int    `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
 	BackupFilePath     string `long:"backupfilepath" description:"The target location of the channel backup file"`
 
+	BackupVerifyInterval time.Duration `long:"backupverifyinterval" description:"The interval between verifications that the channels of the channel backup file could be recovered, by checking that their peers are reachable. Set to 0 to only verify the backup on demand."`
+
 	Bitcoin      *chainConfig    `group:"Bitcoin" namespace:"bitcoin"`
 	BtcdMode     *btcdConfig     `group:"btcd" namespace:"btcd"`
 	BitcoindMode *bitcoindConfig `group:"bitcoind" namespace:"bitcoind"`
 	NeutrinoMode *neutrinoConfig `group:"neutrino" namespace:"neutrino"`
 
 	Litecoinfinance      *chainConfig    `group:"Litecoinfinance" namespace:"litecoinfinance"`
-	LtfndMode      *btcdConfig     `group:"ltfnd" namespace:"ltfnd"`
+	LtfndMode            *btcdConfig     `group:"ltfnd" namespace:"ltfnd"`
 	LitecoinfinancedMode *bitcoindConfig `group:"litecoinfinanced" namespace:"litecoinfinanced"`
 
 	Autopilot *autoPilotConfig `group:"Autopilot" namespace:"autopilot"`
@@ -295,9 +315,24 @@ type config struct {
 
 	NumGraphSyncPeers      int           `long:"numgraphsyncpeers" description:"The number of peers that we should receive new graph updates from. This option can be tuned to save bandwidth for light clients or routing nodes."`
 	HistoricalSyncInterval time.Duration `long:"historicalsyncinterval" description:"The polling interval between historical graph sync attempts. Each historical graph sync attempt ensures we reconcile with the remote peer's graph from the genesis block."`
+	StaleSyncerTimeout     time.Duration `long:"stalesyncertimeout" description:"The duration after which a peer we receive new graph updates from is replaced with another one if it hasn't delivered any updates, while other peers have. Set to 0 to disable."`
+	NoSyncersTimeout       time.Duration `long:"nosyncerstimeout" description:"The duration after which fresh peers are dialed from the DNS seeds of the active chain if we haven't had any peers to receive graph updates from, e.g. after mass disconnects. Set to 0 to disable."`
+	GossipHorizon          time.Duration `long:"gossiphorizon" description:"How far back in time the graph updates we request from the peers we receive new graph updates from should reach, e.g. 24h for constrained nodes. Values reaching back beyond the unix epoch request their full history, as archival nodes may want to. Set to 0 to only receive new updates."`
+	BatchCommitInterval    time.Duration `long:"batchcommitinterval" description:"The maximum duration graph updates received from peers are held back, in order to be written to the database in a single transaction together with other updates. Larger values reduce disk I/O during graph syncs at the cost of slower propagation."`
+
+	KeepAliveUpdateInterval time.Duration `long:"keepaliveupdateinterval" description:"The minimum interval between rebroadcasts of channel updates for the same channel that only refresh its timestamp without changing its policy. Such updates are still applied to our graph. Set to 0 to always rebroadcast them."`
+
+	CoopCloseSigTimeout time.Duration `long:"coopclosesigtimeout" description:"The maximum duration to wait for an external signer to deliver our signature for a cooperative close transaction before the close negotiation is failed. Only applies if an external close signer is in use."`
+
+	MaxRemoteFeeRateMultiplier uint32 `long:"maxremotefeeratemultiplier" description:"The multiple of our own fee estimate up to which commitment fee rates proposed by the remote party of a channel are accepted. Channels receiving fee updates above it, or below the minimum relay fee rate, are disconnected without being force closed. Set to 0 to accept any fee rate."`
 
 	RejectPush bool `long:"rejectpush" description:"If true, lnd will not accept channel opening requests with non-zero push amounts. This should prevent accidental pushes to merchant nodes."`
 
+	ChanAuditLog bool `long:"chanauditlog" description:"If true, every HTLC add, settle and fail, fee update, commitment signature and revocation exchanged with a peer is recorded in an append-only audit log of its channel, which can be exported through exportchanauditlog."`
+
+	CheckDB  bool `long:"check-db" description:"Scan the channel database for orphaned HTLCs, dangling graph edges, missing revocation logs and invalid invoice indexes, report the inconsistencies found and exit without starting the daemon."`
+	RepairDB bool `long:"repair-db" description:"Like check-db, but also repair the inconsistencies that can be repaired without losing data that is still needed, such as by deleting orphaned records and restoring index entries."`
+
 	StaggerInitialReconnect bool `long:"stagger-initial-reconnect" description:"If true, will apply a randomized staggering between 0s and 30s when reconnecting to persistent peers on startup. The first 10 reconnections will be attempted instantly, regardless of the flag's value"`
 
 	net tor.Net
@@ -307,16 +342,58 @@ type config struct {
 	Workers *lncfg.Workers `group:"workers" namespace:"workers"`
 
 	Caches *lncfg.Caches `group:"caches" namespace:"caches"`
+
+	DB *lncfg.DB `group:"db" namespace:"db"`
+
+	Payments *lncfg.Payments `group:"payments" namespace:"payments"`
+
+	PathFinding *lncfg.PathFinding `group:"pathfinding" namespace:"pathfinding"`
+
+	Journal *lncfg.Journal `group:"journal" namespace:"journal"`
+
+	Rebalance *lncfg.Rebalance `group:"rebalance" namespace:"rebalance"`
+
+	FeePolicy *lncfg.FeePolicy `group:"feepolicy" namespace:"feepolicy"`
+
+	ChainHealth *lncfg.ChainHealth `group:"chainhealth" namespace:"chainhealth"`
+
+	CommitFee *lncfg.CommitFee `group:"commitfee" namespace:"commitfee"`
+
+	Sweeper *lncfg.Sweeper `group:"sweeper" namespace:"sweeper"`
+
+	RemoteSigner *lncfg.RemoteSigner `group:"remotesigner" namespace:"remotesigner"`
+
+	BackupExport *lncfg.BackupExport `group:"backupexport" namespace:"backupexport"`
+
+	DBBackup *lncfg.DBBackup `group:"dbbackup" namespace:"dbbackup"`
+
+	FwdLog *lncfg.FwdLog `group:"fwdlog" namespace:"fwdlog"`
+
+	GC *lncfg.GC `group:"gc" namespace:"gc"`
+
+	Protocol *lncfg.Protocol `group:"protocol" namespace:"protocol"`
+
+	ResourceProfile string `long:"resourceprofile" description:"The resource profile that coherently bounds worker pools, caches, gossip batch sizes and database batch intervals. Must be one of default, raspberry-pi or server. Options of the workers and caches groups, and batchcommitinterval, take precedence over the profile if set. The profile can be changed at runtime through the SetResourceProfile RPC."`
+
+	// resourceBudget is the budget derived from the resource profile and
+	// any resource bounds that were set explicitly.
+	resourceBudget resources.Budget
+
+	FiatRates []string `long:"fiatrate" description:"A static exchange rate used to snapshot the value of invoices denominated in a fiat currency, in the format CURRENCY:RATE where RATE is the value of a single coin, e.g. USD:85.5. Can be specified multiple times."`
+
+	// fiatRates are the parsed static exchange rates, keyed by their
+	// currency code.
+	fiatRates invoices.StaticRateProvider
 }
 
 // loadConfig initializes and parses the config using a config file and command
 // line options.
 //
 // The configuration proceeds as follows:
-// 	1) Start with a default config with sane settings
-// 	2) Pre-parse the command line to check for an alternative config file
-// 	3) Load configuration file overwriting defaults with any specified options
-// 	4) Parse CLI options and overwrite/add any specified options
+//  1. Start with a default config with sane settings
+//  2. Pre-parse the command line to check for an alternative config file
+//  3. Load configuration file overwriting defaults with any specified options
+//  4. Parse CLI options and overwrite/add any specified options
 func loadConfig() (*config, error) {
 	defaultCfg := config{
 		LndDir:         defaultLndDir,
@@ -360,10 +437,11 @@ func loadConfig() (*config, error) {
 			Dir:     defaultLitecoinfinancedDir,
 			RPCHost: defaultRPCHost,
 		},
-		MaxPendingChannels: defaultMaxPendingChannels,
-		NoSeedBackup:       defaultNoSeedBackup,
-		MinBackoff:         defaultMinBackoff,
-		MaxBackoff:         defaultMaxBackoff,
+		MaxPendingChannels:   defaultMaxPendingChannels,
+		NoSeedBackup:         defaultNoSeedBackup,
+		MinBackoff:           defaultMinBackoff,
+		MaxBackoff:           defaultMaxBackoff,
+		BackupVerifyInterval: defaultBackupVerifyInterval,
 		SubRPCServers: &subRPCServerConfigs{
 			SignRPC: &signrpc.Config{},
 		},
@@ -376,15 +454,20 @@ func loadConfig() (*config, error) {
 				"preferential": 1.0,
 			},
 		},
-		TrickleDelay:             defaultTrickleDelay,
-		ChanStatusSampleInterval: defaultChanStatusSampleInterval,
-		ChanEnableTimeout:        defaultChanEnableTimeout,
-		ChanDisableTimeout:       defaultChanDisableTimeout,
-		Alias:                    defaultAlias,
-		Color:                    defaultColor,
-		MinChanSize:              int64(minChanFundingSize),
-		NumGraphSyncPeers:        defaultMinPeers,
-		HistoricalSyncInterval:   discovery.DefaultHistoricalSyncInterval,
+		TrickleDelay:               defaultTrickleDelay,
+		ChanStatusSampleInterval:   defaultChanStatusSampleInterval,
+		ChanEnableTimeout:          defaultChanEnableTimeout,
+		ChanDisableTimeout:         defaultChanDisableTimeout,
+		Alias:                      defaultAlias,
+		Color:                      defaultColor,
+		MinChanSize:                int64(minChanFundingSize),
+		NumGraphSyncPeers:          defaultMinPeers,
+		HistoricalSyncInterval:     discovery.DefaultHistoricalSyncInterval,
+		StaleSyncerTimeout:         discovery.DefaultStaleSyncerTimeout,
+		NoSyncersTimeout:           discovery.DefaultNoSyncersTimeout,
+		BatchCommitInterval:        channeldb.DefaultBatchCommitInterval,
+		CoopCloseSigTimeout:        lnwallet.DefaultCloseSigTimeout,
+		MaxRemoteFeeRateMultiplier: htlcswitch.DefaultMaxRemoteFeeRateMultiplier,
 		Tor: &torConfig{
 			SOCKS:   defaultTorSOCKS,
 			DNS:     defaultTorDNS,
@@ -400,6 +483,53 @@ func loadConfig() (*config, error) {
 			RejectCacheSize:  channeldb.DefaultRejectCacheSize,
 			ChannelCacheSize: channeldb.DefaultChannelCacheSize,
 		},
+		DB: &lncfg.DB{
+			Backend:       lncfg.BoltBackend,
+			PostgresTable: lncfg.DefaultPostgresTable,
+		},
+		Payments: &lncfg.Payments{
+			FeeBudgetWarnPercent: lncfg.DefaultFeeBudgetWarnPercent,
+		},
+		PathFinding: &lncfg.PathFinding{
+			FeeWeight:      routing.DefaultPathWeights.FeeWeight,
+			RiskFactor:     routing.RiskFactorBillionths,
+			HoldTimeWeight: routing.DefaultHoldTimeWeight,
+			MaxCltvExpiry:  routing.DefaultMaxCltvExpiry,
+		},
+		Journal: &lncfg.Journal{},
+		Rebalance: &lncfg.Rebalance{
+			Interval:   rebalance.DefaultInterval,
+			MaxFeeRate: rebalance.DefaultMaxFeeRate,
+			Tolerance:  rebalance.DefaultTolerance,
+		},
+		FeePolicy: &lncfg.FeePolicy{
+			Interval: feepolicy.DefaultInterval,
+		},
+		ChainHealth: &lncfg.ChainHealth{
+			Interval:  chainhealth.DefaultInterval,
+			MaxLag:    chainhealth.DefaultMaxLag,
+			MaxTipAge: chainhealth.DefaultMaxTipAge,
+		},
+		CommitFee: &lncfg.CommitFee{},
+		Sweeper: &lncfg.Sweeper{
+			BatchWindowDuration: sweep.DefaultBatchWindowDuration,
+			FeeRateBucketSize:   sweep.DefaultFeeRateBucketSize,
+		},
+		RemoteSigner: &lncfg.RemoteSigner{
+			Timeout: lncfg.DefaultRemoteSignerRPCTimeout,
+		},
+		BackupExport: &lncfg.BackupExport{
+			Timeout: lncfg.DefaultBackupExportTimeout,
+		},
+		DBBackup: &lncfg.DBBackup{
+			Interval:     lncfg.DefaultDBBackupInterval,
+			NumSnapshots: lncfg.DefaultDBBackupNumSnapshots,
+		},
+		FwdLog: &lncfg.FwdLog{},
+		GC:     &lncfg.GC{},
+		Protocol: &lncfg.Protocol{
+			MaxZeroConfExposure: defaultMaxZeroConfExposure,
+		},
 	}
 
 	// Pre-parse the command line options to pick up an alternative config
@@ -432,18 +562,30 @@ func loadConfig() (*config, error) {
 		}
 	}
 
-	// Next, load any additional configuration options from the file.
+	// Next, load any additional configuration options from the file. The
+	// file is first resolved, expanding any environment variables and
+	// include directives within it.
 	var configFileError error
 	cfg := preCfg
-	if err := flags.IniParse(configFilePath, &cfg); err != nil {
-		// If it's a parsing related error, then we'll return
-		// immediately, otherwise we can proceed as possibly the config
-		// file doesn't exist which is OK.
-		if _, ok := err.(*flags.IniError); ok {
-			return nil, err
-		}
-
+	configFile, err := lncfg.ReadConfigFile(configFilePath)
+	switch {
+	// The config file doesn't exist, which is OK, so we'll proceed with
+	// the defaults.
+	case os.IsNotExist(err):
 		configFileError = err
+
+	case err != nil:
+		return nil, err
+
+	default:
+		parser := flags.NewParser(&cfg, flags.Default)
+		err := flags.NewIniParser(parser).Parse(
+			bytes.NewReader(configFile),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse config file "+
+				"%v: %v", configFilePath, err)
+		}
 	}
 
 	// Finally, parse the remaining command line options again to ensure
@@ -490,6 +632,17 @@ func loadConfig() (*config, error) {
 	cfg.AdminMacPath = cleanAndExpandPath(cfg.AdminMacPath)
 	cfg.ReadMacPath = cleanAndExpandPath(cfg.ReadMacPath)
 	cfg.InvoiceMacPath = cleanAndExpandPath(cfg.InvoiceMacPath)
+	cfg.RemoteSigner.MacaroonPath = cleanAndExpandPath(
+		cfg.RemoteSigner.MacaroonPath,
+	)
+	cfg.RemoteSigner.TLSCertPath = cleanAndExpandPath(
+		cfg.RemoteSigner.TLSCertPath,
+	)
+	for i, path := range cfg.BackupExport.Paths {
+		cfg.BackupExport.Paths[i] = cleanAndExpandPath(path)
+	}
+	cfg.DBBackup.Dir = cleanAndExpandPath(cfg.DBBackup.Dir)
+	cfg.DBBackup.KeyFile = cleanAndExpandPath(cfg.DBBackup.KeyFile)
 	cfg.LogDir = cleanAndExpandPath(cfg.LogDir)
 	cfg.BtcdMode.Dir = cleanAndExpandPath(cfg.BtcdMode.Dir)
 	cfg.LtfndMode.Dir = cleanAndExpandPath(cfg.LtfndMode.Dir)
@@ -530,6 +683,33 @@ func loadConfig() (*config, error) {
 		return nil, err
 	}
 
+	// Keep-alive channel updates must still be relayed often enough for
+	// the rest of the network not to prune the channel as a zombie.
+	if cfg.KeepAliveUpdateInterval < 0 ||
+		cfg.KeepAliveUpdateInterval >= routing.DefaultChannelPruneExpiry {
+
+		str := "%s: keepaliveupdateinterval must be non-negative " +
+			"and below %v"
+		err := fmt.Errorf(str, funcName,
+			routing.DefaultChannelPruneExpiry)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, err
+	}
+
+	if cfg.GossipHorizon < 0 {
+		str := "%s: gossiphorizon must be non-negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, err
+	}
+
+	if cfg.CoopCloseSigTimeout <= 0 {
+		str := "%s: coopclosesigtimeout must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, err
+	}
+
 	// Ensure that the specified values for the min and max channel size
 	// don't are within the bounds of the normal chan size constraints.
 	if cfg.Autopilot.MinChannelSize < int64(minChanFundingSize) {
@@ -707,9 +887,22 @@ func loadConfig() (*config, error) {
 					"credentials for litecoinfinanced: %v", err)
 				return nil, err
 			}
+		case "neutrino":
+			// No need to get RPC parameters, but the light client
+			// needs the consensus parameters of the chain to
+			// validate its headers.
+			err := applyLitecoinfinanceLightClientParams(
+				&activeNetParams, &ltfnParams,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("%s: unable to apply "+
+					"light client params: %v", funcName,
+					err)
+			}
 		default:
-			str := "%s: only ltfnd and litecoinfinanced mode supported for " +
-				"litecoinfinance at this time"
+			str := "%s: only ltfnd, litecoinfinanced, and " +
+				"neutrino mode supported for litecoinfinance " +
+				"at this time"
 			return nil, fmt.Errorf(str, funcName)
 		}
 
@@ -1041,15 +1234,52 @@ func loadConfig() (*config, error) {
 			"minbackoff")
 	}
 
-	// Validate the subconfigs for workers and caches.
+	// Apply the resource profile to all resource bounds that weren't set
+	// explicitly.
+	cfg.resourceBudget, err = applyResourceProfile(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the static exchange rates used for invoices denominated in a
+	// fiat currency.
+	cfg.fiatRates, err = parseFiatRates(cfg.FiatRates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the subconfigs for workers, caches, the database, payments,
+	// path finding, the journal, the rebalancer, the fee policy manager,
+	// the chain health monitor, the commitment fee bounds, the sweeper,
+	// the remote signer, the backup export, the database backups and the
+	// forwarding log.
 	err = lncfg.Validate(
 		cfg.Workers,
 		cfg.Caches,
+		cfg.DB,
+		cfg.Payments,
+		cfg.PathFinding,
+		cfg.Journal,
+		cfg.Rebalance,
+		cfg.FeePolicy,
+		cfg.ChainHealth,
+		cfg.CommitFee,
+		cfg.Sweeper,
+		cfg.RemoteSigner,
+		cfg.BackupExport,
+		cfg.DBBackup,
+		cfg.FwdLog,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Snapshots can only be taken of a local bolt database file.
+	if cfg.DBBackup.Enabled() && cfg.DB.Backend != lncfg.BoltBackend {
+		return nil, fmt.Errorf("dbbackup.dir is only supported by the " +
+			"bolt backend")
+	}
+
 	// Finally, ensure that the user's color is correctly formatted,
 	// otherwise the server will not be able to start after the unlocking
 	// the wallet.
@@ -1065,9 +1295,98 @@ func loadConfig() (*config, error) {
 		ltndLog.Warnf("%v", configFileError)
 	}
 
+	// If the user only wanted to validate their configuration, we'll print
+	// the fully resolved configuration and exit.
+	if cfg.ValidateConfig {
+		if err := writeEffectiveConfig(os.Stdout, &cfg); err != nil {
+			return nil, err
+		}
+		os.Exit(0)
+	}
+
 	return &cfg, nil
 }
 
+// writeEffectiveConfig writes the given configuration, including all options
+// left at their default value, to the passed writer in the config file format.
+func writeEffectiveConfig(w io.Writer, cfg *config) error {
+	parser := flags.NewParser(cfg, flags.Default)
+	return flags.NewIniParser(parser).Write(w, flags.IniIncludeDefaults)
+}
+
+// parseFiatRates parses exchange rates in the format CURRENCY:RATE, keyed by
+// their upper case currency code.
+func parseFiatRates(rawRates []string) (invoices.StaticRateProvider, error) {
+	rates := make(invoices.StaticRateProvider, len(rawRates))
+	for _, rawRate := range rawRates {
+		parts := strings.Split(rawRate, ":")
+		if len(parts) != 2 ||
+			len(parts[0]) != channeldb.FiatCurrencySize {
+
+			return nil, fmt.Errorf("invalid fiat rate %q, expected "+
+				"format CURRENCY:RATE", rawRate)
+		}
+
+		rate, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid fiat rate %q, rate "+
+				"must be a positive number", rawRate)
+		}
+
+		rates[strings.ToUpper(parts[0])] = rate
+	}
+
+	return rates, nil
+}
+
+// applyResourceProfile applies the budget of the configured resource profile to
+// all resource bounds that were left at their defaults, and returns the
+// resulting budget. Bounds that were set explicitly take precedence over the
+// profile, and are reflected in the returned budget as well.
+func applyResourceProfile(cfg *config) (resources.Budget, error) {
+	budget, err := resources.ProfileBudget(
+		resources.Profile(cfg.ResourceProfile),
+	)
+	if err != nil {
+		return resources.Budget{}, err
+	}
+
+	applyInt := func(value *int, defaultValue int, budgetValue *int) {
+		if *value == defaultValue {
+			*value = *budgetValue
+		} else {
+			*budgetValue = *value
+		}
+	}
+	applyInt(
+		&cfg.Workers.Read, lncfg.DefaultReadWorkers,
+		&budget.ReadWorkers,
+	)
+	applyInt(
+		&cfg.Workers.Write, lncfg.DefaultWriteWorkers,
+		&budget.WriteWorkers,
+	)
+	applyInt(
+		&cfg.Workers.Sig, lncfg.DefaultSigWorkers, &budget.SigWorkers,
+	)
+	applyInt(
+		&cfg.Caches.RejectCacheSize, channeldb.DefaultRejectCacheSize,
+		&budget.RejectCacheSize,
+	)
+	applyInt(
+		&cfg.Caches.ChannelCacheSize, channeldb.DefaultChannelCacheSize,
+		&budget.ChannelCacheSize,
+	)
+
+	if cfg.BatchCommitInterval == channeldb.DefaultBatchCommitInterval {
+		cfg.BatchCommitInterval = budget.BatchCommitInterval
+	} else {
+		budget.BatchCommitInterval = cfg.BatchCommitInterval
+	}
+
+	return budget, nil
+}
+
 // cleanAndExpandPath expands environment variables and leading ~ in the
 // passed path, cleans the result, and returns it.
 // This function is taken from https://github.com/litecoinfinance/btcd
@@ -1472,3 +1791,16 @@ func normalizeNetwork(network string) string {
 
 	return network
 }
+
+// commitFeeBounds returns the commitment fee bounds of the passed config. If
+// it's unset, the commitment fee rates of our channels are left unrestricted.
+func commitFeeBounds(commitFee *lncfg.CommitFee) lnwallet.CommitFeeBounds {
+	if commitFee == nil {
+		return lnwallet.CommitFeeBounds{}
+	}
+
+	return lnwallet.CommitFeeBounds{
+		MinFeeRate: lnwallet.SatPerKWeight(commitFee.MinFeeRate),
+		MaxFeeRate: lnwallet.SatPerKWeight(commitFee.MaxFeeRate),
+	}
+}
@@ -80,6 +80,12 @@ type ChainArbitratorConfig struct {
 	// continually be rebroadcast if needed.
 	PublishTx func(*wire.MsgTx) error
 
+	// BumpCommitFee is a function that spends our anchor output on a
+	// broadcast commitment transaction in order to bump its fee using
+	// CPFP. It may be nil, in which case the fee of commitments with
+	// anchors won't be bumped.
+	BumpCommitFee func(*lnwallet.AnchorResolution) error
+
 	// DeliverResolutionMsg is a function that will append an outgoing
 	// message to the "out box" for a ChannelLink. This is used to cancel
 	// backwards any HTLC's that are either dust, we're timing out, or
@@ -286,7 +292,7 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 	// TODO(roasbeef); abstraction leak...
 	//  * rework: adaptor method to set log scope w/ factory func
 	chanLog, err := newBoltArbitratorLog(
-		c.chanSource.DB, arbCfg, c.cfg.ChainHash, chanPoint,
+		c.chanSource, arbCfg, c.cfg.ChainHash, chanPoint,
 	)
 	if err != nil {
 		blockEpoch.Cancel()
@@ -343,6 +349,42 @@ func (c *ChainArbitrator) resolveContract(chanPoint wire.OutPoint,
 	return nil
 }
 
+// SpliceChannel moves the watch over a channel from its old funding outpoint
+// to the one created by its splice transaction. The arbitrator and chain
+// watcher of the old funding outpoint are stopped, and new ones are created
+// for the spliced channel.
+func (c *ChainArbitrator) SpliceChannel(oldChanPoint wire.OutPoint,
+	newChan *channeldb.OpenChannel) error {
+
+	log.Infof("Moving ChannelArbitrator for ChannelPoint(%v) to "+
+		"ChannelPoint(%v)", oldChanPoint, newChan.FundingOutpoint)
+
+	c.Lock()
+	channelArb, ok := c.activeChannels[oldChanPoint]
+	delete(c.activeChannels, oldChanPoint)
+
+	chainWatcher, watcherOk := c.activeWatchers[oldChanPoint]
+	delete(c.activeWatchers, oldChanPoint)
+	c.Unlock()
+
+	if watcherOk {
+		chainWatcher.Stop()
+	}
+
+	// The arbitrator of the old funding outpoint didn't act upon its
+	// state, so we wipe its log, as nothing refers to it anymore.
+	if ok {
+		if err := channelArb.Stop(); err != nil {
+			return err
+		}
+		if err := channelArb.log.WipeHistory(); err != nil {
+			return err
+		}
+	}
+
+	return c.WatchNewChannel(newChan)
+}
+
 // Start launches all goroutines that the ChainArbitrator needs to operate.
 func (c *ChainArbitrator) Start() error {
 	if !atomic.CompareAndSwapInt32(&c.started, 0, 1) {
@@ -436,7 +478,7 @@ func (c *ChainArbitrator) Start() error {
 			CloseType:             closeChanInfo.CloseType,
 		}
 		chanLog, err := newBoltArbitratorLog(
-			c.chanSource.DB, arbCfg, c.cfg.ChainHash, chanPoint,
+			c.chanSource, arbCfg, c.cfg.ChainHash, chanPoint,
 		)
 		if err != nil {
 			blockEpoch.Cancel()
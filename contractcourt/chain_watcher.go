@@ -279,7 +279,8 @@ func (c *chainWatcher) SubscribeChannelEvents() *ChainEventSubscription {
 // based off of only the set of outputs included.
 func isOurCommitment(localChanCfg, remoteChanCfg channeldb.ChannelConfig,
 	commitSpend *chainntnfs.SpendDetail, broadcastStateNum uint64,
-	revocationProducer shachain.Producer) (bool, error) {
+	revocationProducer shachain.Producer,
+	chanType channeldb.ChannelType) (bool, error) {
 
 	// First, we'll re-derive our commitment point for this state since
 	// this is what we use to randomize each of the keys for this state.
@@ -299,7 +300,9 @@ func isOurCommitment(localChanCfg, remoteChanCfg channeldb.ChannelConfig,
 
 	// With the keys derived, we'll construct the remote script that'll be
 	// present if they have a non-dust balance on the commitment.
-	remotePkScript, err := input.CommitScriptUnencumbered(remotePayKey)
+	_, remotePkScript, err := lnwallet.CommitScriptToRemote(
+		chanType, remotePayKey,
+	)
 	if err != nil {
 		return false, err
 	}
@@ -371,6 +374,27 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 		// revoked state...!!!
 		commitTxBroadcast := commitSpend.SpendingTx
 
+		// If the funding output was spent by the pending splice of
+		// the channel, then the channel isn't closed, but moves over
+		// to its new funding output, so there's nothing for us to do.
+		splice, err := c.cfg.chanState.Splice()
+		switch {
+		case err == nil:
+			spliceTxid := splice.SpliceTx.TxHash()
+			if commitTxBroadcast.TxHash() == spliceTxid {
+				log.Infof("ChannelPoint(%v) spliced by txid=%v",
+					c.cfg.chanState.FundingOutpoint,
+					spliceTxid)
+				return
+			}
+
+		case err != channeldb.ErrNoSplice:
+			log.Errorf("Unable to fetch splice for "+
+				"chan_point=%v: %v",
+				c.cfg.chanState.FundingOutpoint, err)
+			return
+		}
+
 		localCommit, remoteCommit, err := c.cfg.chanState.LatestCommitments()
 		if err != nil {
 			log.Errorf("Unable to fetch channel state for "+
@@ -404,6 +428,7 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 			c.cfg.chanState.LocalChanCfg,
 			c.cfg.chanState.RemoteChanCfg, commitSpend,
 			broadcastStateNum, c.cfg.chanState.RevocationProducer,
+			c.cfg.chanState.ChanType,
 		)
 		if err != nil {
 			log.Errorf("unable to determine self commit for "+
@@ -740,6 +740,19 @@ func (c *ChannelArbitrator) stateStep(triggerHeight uint32,
 			}
 		}
 
+		// If the commitment carries our anchor, we'll make sure it
+		// pays a fee that's adequate for the current conditions, as
+		// it was signed at a fee rate negotiated long ago. Failing to
+		// do so isn't fatal, as the commitment may still confirm.
+		anchorRes := closeSummary.AnchorResolution
+		if anchorRes != nil && c.cfg.BumpCommitFee != nil {
+			if err := c.cfg.BumpCommitFee(anchorRes); err != nil {
+				log.Errorf("ChannelArbitrator(%v): unable to "+
+					"bump commitment fee: %v",
+					c.cfg.ChanPoint, err)
+			}
+		}
+
 		if err := c.cfg.MarkCommitmentBroadcasted(); err != nil {
 			log.Errorf("ChannelArbitrator(%v): unable to "+
 				"mark commitment broadcasted: %v",
@@ -812,7 +825,7 @@ func (c *ChannelArbitrator) stateStep(triggerHeight uint32,
 		// trimmed.  We'll need to wait for a CSV timeout before we can
 		// reclaim the funds.
 		commitRes := contractResolutions.CommitResolution
-		if commitRes != nil && commitRes.MaturityDelay > 0 {
+		if commitRes != nil && isLocalCommitOutput(commitRes) {
 			log.Infof("ChannelArbitrator(%v): sending commit "+
 				"output for incubation", c.cfg.ChanPoint)
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/litecoinfinance/lnd/input"
 
+	"github.com/litecoinfinance/btcd/txscript"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/lnd/lnwallet"
 )
@@ -79,26 +80,36 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 		return nil, fmt.Errorf("quitting")
 	}
 
-	// We're dealing with our commitment transaction if the delay on the
-	// resolution isn't zero.
-	isLocalCommitTx := c.commitResolution.MaturityDelay != 0
+	isLocalCommitTx := isLocalCommitOutput(&c.commitResolution)
+	signDesc := &c.commitResolution.SelfOutputSignDesc
 
 	if !isLocalCommitTx {
 		// We'll craft an input with all the information required for
 		// the sweeper to create a fully valid sweeping transaction to
 		// recover these coins.
-		inp := input.MakeBaseInput(
-			&c.commitResolution.SelfOutPoint,
-			input.CommitmentNoDelay,
-			&c.commitResolution.SelfOutputSignDesc,
-			c.broadcastHeight,
-		)
+		var inp input.Input
+		if c.commitResolution.MaturityDelay != 0 {
+			csvInp := input.MakeCsvInput(
+				&c.commitResolution.SelfOutPoint,
+				input.CommitmentToRemoteConfirmed, signDesc,
+				c.broadcastHeight,
+				c.commitResolution.MaturityDelay,
+			)
+			inp = &csvInp
+		} else {
+			baseInp := input.MakeBaseInput(
+				&c.commitResolution.SelfOutPoint,
+				input.CommitmentNoDelay, signDesc,
+				c.broadcastHeight,
+			)
+			inp = &baseInp
+		}
 
 		// With our input constructed, we'll now offer it to the
 		// sweeper.
 		log.Infof("%T(%v): sweeping commit output", c, c.chanPoint)
 
-		resultChan, err := c.Sweeper.SweepInput(&inp)
+		resultChan, err := c.Sweeper.SweepInput(inp)
 		if err != nil {
 			log.Errorf("%T(%v): unable to sweep input: %v",
 				c, c.chanPoint, err)
@@ -199,6 +210,17 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 	return nil, c.Checkpoint(c)
 }
 
+// isLocalCommitOutput returns true if the given resolution is for our output
+// on our own commitment transaction. This is the case if the output is
+// encumbered by the to-self script, which has the revocation clause as its
+// first branch. We can't rely on the delay of the resolution alone, as our
+// output on the remote commitment of channels using anchor outputs is also
+// delayed, by a single block.
+func isLocalCommitOutput(res *lnwallet.CommitOutputResolution) bool {
+	witnessScript := res.SelfOutputSignDesc.WitnessScript
+	return len(witnessScript) > 0 && witnessScript[0] == txscript.OP_IF
+}
+
 // Stop signals the resolver to cancel any current resolution processes, and
 // suspend.
 //
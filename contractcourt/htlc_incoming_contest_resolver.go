@@ -128,8 +128,12 @@ func (h *htlcIncomingContestResolver) Resolve() (ContractResolver, error) {
 
 	// Notify registry that we are potentially settling as exit hop
 	// on-chain, so that we will get a hodl event when a corresponding hodl
-	// invoice is settled.
-	event, err := h.Registry.NotifyExitHopHtlc(h.payHash, h.htlcAmt, hodlChan)
+	// invoice is settled. The htlc itself isn't recorded, as its index
+	// isn't known to the resolver.
+	event, err := h.Registry.NotifyExitHopHtlc(
+		h.payHash, h.htlcAmt, h.htlcExpiry, uint32(currentHeight), nil,
+		hodlChan,
+	)
 	if err != nil && err != channeldb.ErrInvoiceNotFound {
 		return nil, err
 	}
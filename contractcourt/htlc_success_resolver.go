@@ -180,10 +180,10 @@ func (h *htlcSuccessResolver) Resolve() (ContractResolver, error) {
 		// With the HTLC claimed, we can attempt to settle its
 		// corresponding invoice if we were the original destination. As
 		// the htlc is already settled at this point, we don't need to
-		// read on the hodl channel.
+		// read on the hodl channel, nor watch its expiry.
 		hodlChan := make(chan interface{}, 1)
 		_, err = h.Registry.NotifyExitHopHtlc(
-			h.payHash, h.htlcAmt, hodlChan,
+			h.payHash, h.htlcAmt, 0, 0, nil, hodlChan,
 		)
 		if err != nil && err != channeldb.ErrInvoiceNotFound {
 			log.Errorf("Unable to settle invoice with payment "+
@@ -258,9 +258,11 @@ func (h *htlcSuccessResolver) Resolve() (ContractResolver, error) {
 	// With the HTLC claimed, we can attempt to settle its corresponding
 	// invoice if we were the original destination. As the htlc is already
 	// settled at this point, we don't need to read on the hodl
-	// channel.
+	// channel, nor watch its expiry.
 	hodlChan := make(chan interface{}, 1)
-	_, err = h.Registry.NotifyExitHopHtlc(h.payHash, h.htlcAmt, hodlChan)
+	_, err = h.Registry.NotifyExitHopHtlc(
+		h.payHash, h.htlcAmt, 0, 0, nil, hodlChan,
+	)
 	if err != nil && err != channeldb.ErrInvoiceNotFound {
 		log.Errorf("Unable to settle invoice with payment "+
 			"hash %x: %v", h.payHash, err)
@@ -0,0 +1,426 @@
+package contractcourt
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/txscript"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/chainntnfs"
+	"github.com/litecoinfinance/lnd/input"
+	"github.com/litecoinfinance/lnd/keychain"
+	"github.com/litecoinfinance/lnd/lntypes"
+	"github.com/litecoinfinance/lnd/sweep"
+)
+
+// SwapRole denotes the side of the on-chain HTLC of a submarine swap that
+// we're on.
+type SwapRole uint8
+
+const (
+	// SwapRoleReceiver signals that we're the receiver of the on-chain
+	// HTLC, and are able to claim it once we know the payment preimage.
+	// This is the case for loop out swaps.
+	SwapRoleReceiver SwapRole = iota
+
+	// SwapRoleSender signals that we're the sender of the on-chain HTLC,
+	// and are able to reclaim it once its CLTV expiry has been reached.
+	// This is the case for loop in swaps.
+	SwapRoleSender
+)
+
+// String returns a human readable version of the swap role.
+func (r SwapRole) String() string {
+	switch r {
+	case SwapRoleReceiver:
+		return "Receiver"
+	case SwapRoleSender:
+		return "Sender"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(r))
+	}
+}
+
+// SwapHTLC describes the on-chain HTLC of a submarine swap that we're a party
+// to.
+type SwapHTLC struct {
+	// Role is the side of the HTLC that we're on.
+	Role SwapRole
+
+	// PaymentHash is the payment hash the HTLC is locked to.
+	PaymentHash lntypes.Hash
+
+	// LocalKey is the key we've derived for the swap. It is the receiver
+	// key of the HTLC if we're the receiver, and the sender key otherwise.
+	LocalKey keychain.KeyDescriptor
+
+	// RemoteKey is the key of the swap provider.
+	RemoteKey *btcec.PublicKey
+
+	// CltvExpiry is the absolute height after which the sender of the HTLC
+	// is able to reclaim it.
+	CltvExpiry uint32
+
+	// HeightHint is the earliest height at which the HTLC could have been
+	// confirmed.
+	HeightHint uint32
+
+	// OutPoint is the outpoint of the HTLC. It is nil until the HTLC has
+	// been found within the chain.
+	OutPoint *wire.OutPoint
+
+	// Amount is the value of the HTLC output. It is only known once the
+	// HTLC has been found within the chain.
+	Amount btcutil.Amount
+}
+
+// WitnessScript returns the witness script of the HTLC.
+func (h *SwapHTLC) WitnessScript() ([]byte, error) {
+	senderKey, receiverKey := h.RemoteKey, h.LocalKey.PubKey
+	if h.Role == SwapRoleSender {
+		senderKey, receiverKey = receiverKey, senderKey
+	}
+
+	return input.SwapHTLCScript(
+		h.CltvExpiry, senderKey, receiverKey, h.PaymentHash[:],
+	)
+}
+
+// PkScript returns the p2wsh public key script of the HTLC.
+func (h *SwapHTLC) PkScript() ([]byte, error) {
+	witnessScript, err := h.WitnessScript()
+	if err != nil {
+		return nil, err
+	}
+
+	return input.WitnessScriptHash(witnessScript)
+}
+
+// SwapOutcome describes which clause of a submarine swap HTLC was used to
+// spend it.
+type SwapOutcome uint8
+
+const (
+	// SwapOutcomeSuccess signals that the HTLC was claimed by its receiver
+	// using the payment preimage.
+	SwapOutcomeSuccess SwapOutcome = iota
+
+	// SwapOutcomeTimeout signals that the HTLC was reclaimed by its sender
+	// after its CLTV expiry.
+	SwapOutcomeTimeout
+)
+
+// String returns a human readable version of the swap outcome.
+func (o SwapOutcome) String() string {
+	switch o {
+	case SwapOutcomeSuccess:
+		return "Success"
+	case SwapOutcomeTimeout:
+		return "Timeout"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(o))
+	}
+}
+
+// SwapResolution describes how the on-chain HTLC of a submarine swap was
+// resolved.
+type SwapResolution struct {
+	// Outcome is the clause that was used to spend the HTLC.
+	Outcome SwapOutcome
+
+	// SpendTxid is the hash of the transaction that spent the HTLC.
+	SpendTxid chainhash.Hash
+
+	// Preimage is the payment preimage revealed by the spend. It is only
+	// set if the outcome is SwapOutcomeSuccess.
+	Preimage lntypes.Preimage
+}
+
+// SwapResolverConfig houses the dependencies of a SwapResolver.
+type SwapResolverConfig struct {
+	// Notifier is used to watch for the confirmation and spend of the
+	// HTLC, as well as its expiry.
+	Notifier chainntnfs.ChainNotifier
+
+	// PreimageDB is used to look up the preimage of the HTLC if we're its
+	// receiver. Any preimage revealed by a spend of the HTLC is added to
+	// it.
+	PreimageDB WitnessBeacon
+
+	// SweepInput offers the HTLC to the sweeper once we're able to spend
+	// it.
+	SweepInput func(input.Input) (chan sweep.Result, error)
+
+	// Checkpoint is called to persist the HTLC once its outpoint has been
+	// found within the chain.
+	Checkpoint func(*SwapHTLC) error
+}
+
+// SwapResolver resolves the on-chain HTLC of a submarine swap. If we're the
+// receiver of the HTLC, it is claimed as soon as the preimage is known to the
+// PreimageDB. If we're the sender, it is reclaimed once the CLTV expiry has
+// been reached. As opposed to the resolvers of channel contracts, the HTLC
+// isn't part of any commitment transaction, so the resolver waits for the
+// HTLC to confirm by its script if its outpoint isn't known yet.
+type SwapResolver struct {
+	htlc *SwapHTLC
+
+	cfg SwapResolverConfig
+
+	quit     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSwapResolver creates a new resolver for the given swap HTLC.
+func NewSwapResolver(htlc *SwapHTLC, cfg SwapResolverConfig) *SwapResolver {
+	return &SwapResolver{
+		htlc: htlc,
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Stop signals the resolver to exit. A pending call to Resolve will return an
+// error.
+func (s *SwapResolver) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.quit)
+	})
+}
+
+// Resolve blocks until the HTLC has been spent, offering it to the sweeper as
+// soon as we're able to spend it ourselves.
+func (s *SwapResolver) Resolve() (*SwapResolution, error) {
+	witnessScript, err := s.htlc.WitnessScript()
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := input.WitnessScriptHash(witnessScript)
+	if err != nil {
+		return nil, err
+	}
+
+	// If we don't know the outpoint of the HTLC yet, we'll wait for a
+	// transaction paying to its script to confirm.
+	if s.htlc.OutPoint == nil {
+		if err := s.waitForConfirmation(pkScript); err != nil {
+			return nil, err
+		}
+	}
+
+	spendNtfn, err := s.cfg.Notifier.RegisterSpendNtfn(
+		s.htlc.OutPoint, pkScript, s.htlc.HeightHint,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer spendNtfn.Cancel()
+
+	// Depending on our role, we'll either be waiting for the preimage to
+	// be known, or the HTLC to expire before we can sweep it.
+	var (
+		preimageUpdates <-chan lntypes.Preimage
+		epochs          <-chan *chainntnfs.BlockEpoch
+		sweepInput      input.Input
+	)
+	signDesc := &input.SignDescriptor{
+		KeyDesc:       s.htlc.LocalKey,
+		WitnessScript: witnessScript,
+		Output: &wire.TxOut{
+			PkScript: pkScript,
+			Value:    int64(s.htlc.Amount),
+		},
+		HashType: txscript.SigHashAll,
+	}
+	switch s.htlc.Role {
+	case SwapRoleReceiver:
+		preimageSub := s.cfg.PreimageDB.SubscribeUpdates()
+		defer preimageSub.CancelSubscription()
+		preimageUpdates = preimageSub.WitnessUpdates
+
+		preimage, ok := s.cfg.PreimageDB.LookupPreimage(
+			s.htlc.PaymentHash,
+		)
+		if ok {
+			sweepInput = s.successInput(signDesc, preimage)
+		}
+
+	case SwapRoleSender:
+		blockEpochs, err := s.cfg.Notifier.RegisterBlockEpochNtfn(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer blockEpochs.Cancel()
+		epochs = blockEpochs.Epochs
+
+	default:
+		return nil, fmt.Errorf("unknown swap role: %v", s.htlc.Role)
+	}
+
+	var sweepResults chan sweep.Result
+	for {
+		// Offer the HTLC to the sweeper once we're able to spend it.
+		if sweepInput != nil && sweepResults == nil {
+			log.Infof("Sweeping swap htlc %v with payment hash %v "+
+				"using %v", s.htlc.OutPoint, s.htlc.PaymentHash,
+				sweepInput.WitnessType())
+
+			sweepResults, err = s.cfg.SweepInput(sweepInput)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		select {
+		case spend, ok := <-spendNtfn.Spend:
+			if !ok {
+				return nil, fmt.Errorf("quitting")
+			}
+
+			return s.handleSpend(spend)
+
+		case preimage, ok := <-preimageUpdates:
+			if !ok {
+				return nil, fmt.Errorf("quitting")
+			}
+
+			if sweepInput != nil ||
+				!preimage.Matches(s.htlc.PaymentHash) {
+
+				continue
+			}
+
+			sweepInput = s.successInput(signDesc, preimage)
+
+		case epoch, ok := <-epochs:
+			if !ok {
+				return nil, fmt.Errorf("quitting")
+			}
+
+			// The sweeper uses the current height as the lock
+			// time of its sweep transactions, so the HTLC can be
+			// swept once the chain has reached its expiry.
+			if sweepInput != nil ||
+				uint32(epoch.Height) < s.htlc.CltvExpiry {
+
+				continue
+			}
+
+			inp := input.MakeBaseInput(
+				s.htlc.OutPoint, input.SwapHTLCTimeout, signDesc,
+				s.htlc.HeightHint,
+			)
+			sweepInput = &inp
+
+		case result := <-sweepResults:
+			// The spend itself is handled once the spend
+			// notification is delivered, so we only need to act on
+			// failures to sweep the HTLC ourselves.
+			if result.Err != nil && result.Err != sweep.ErrRemoteSpend {
+				return nil, fmt.Errorf("unable to sweep swap "+
+					"htlc %v: %v", s.htlc.OutPoint, result.Err)
+			}
+
+		case <-s.quit:
+			return nil, fmt.Errorf("quitting")
+		}
+	}
+}
+
+// waitForConfirmation waits for a transaction paying to the HTLC's script to
+// confirm, and checkpoints the HTLC with the outpoint found.
+func (s *SwapResolver) waitForConfirmation(pkScript []byte) error {
+	log.Infof("Waiting for swap htlc with payment hash %v to confirm",
+		s.htlc.PaymentHash)
+
+	confNtfn, err := s.cfg.Notifier.RegisterConfirmationsNtfn(
+		nil, pkScript, 1, s.htlc.HeightHint,
+	)
+	if err != nil {
+		return err
+	}
+	defer confNtfn.Cancel()
+
+	var conf *chainntnfs.TxConfirmation
+	select {
+	case c, ok := <-confNtfn.Confirmed:
+		if !ok {
+			return fmt.Errorf("quitting")
+		}
+		conf = c
+
+	case <-s.quit:
+		return fmt.Errorf("quitting")
+	}
+
+	for i, txOut := range conf.Tx.TxOut {
+		if !bytes.Equal(txOut.PkScript, pkScript) {
+			continue
+		}
+
+		s.htlc.OutPoint = &wire.OutPoint{
+			Hash:  conf.Tx.TxHash(),
+			Index: uint32(i),
+		}
+		s.htlc.Amount = btcutil.Amount(txOut.Value)
+		s.htlc.HeightHint = conf.BlockHeight
+
+		log.Infof("Swap htlc with payment hash %v confirmed at %v",
+			s.htlc.PaymentHash, s.htlc.OutPoint)
+
+		return s.cfg.Checkpoint(s.htlc)
+	}
+
+	return fmt.Errorf("confirmed tx %v doesn't pay to swap htlc",
+		conf.Tx.TxHash())
+}
+
+// successInput returns the input claiming the HTLC with the given preimage.
+func (s *SwapResolver) successInput(signDesc *input.SignDescriptor,
+	preimage lntypes.Preimage) input.Input {
+
+	inp := input.MakeSwapHTLCSuccessInput(
+		s.htlc.OutPoint, signDesc, preimage[:], s.htlc.HeightHint,
+	)
+	return &inp
+}
+
+// handleSpend determines the outcome of the swap from the transaction that
+// spent the HTLC. If the HTLC was claimed with the preimage, it is added to
+// the PreimageDB.
+func (s *SwapResolver) handleSpend(
+	spend *chainntnfs.SpendDetail) (*SwapResolution, error) {
+
+	resolution := &SwapResolution{
+		Outcome:   SwapOutcomeTimeout,
+		SpendTxid: *spend.SpenderTxHash,
+	}
+
+	// A spend through the success clause carries the 32 byte preimage as
+	// the second witness element.
+	witness := spend.SpendingTx.TxIn[spend.SpenderInputIndex].Witness
+	if len(witness) == 3 && len(witness[1]) == lntypes.PreimageSize {
+		var preimage lntypes.Preimage
+		copy(preimage[:], witness[1])
+
+		if preimage.Matches(s.htlc.PaymentHash) {
+			resolution.Outcome = SwapOutcomeSuccess
+			resolution.Preimage = preimage
+
+			err := s.cfg.PreimageDB.AddPreimages(preimage)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	log.Infof("Swap htlc %v with payment hash %v resolved by tx %v: %v",
+		s.htlc.OutPoint, s.htlc.PaymentHash, resolution.SpendTxid,
+		resolution.Outcome)
+
+	return resolution, nil
+}
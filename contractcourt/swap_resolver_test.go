@@ -0,0 +1,242 @@
+package contractcourt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/chainntnfs"
+	"github.com/litecoinfinance/lnd/input"
+	"github.com/litecoinfinance/lnd/keychain"
+	"github.com/litecoinfinance/lnd/lntypes"
+	"github.com/litecoinfinance/lnd/sweep"
+)
+
+// swapResolverHarness bundles a SwapResolver with the mocks backing it.
+type swapResolverHarness struct {
+	t *testing.T
+
+	resolver *SwapResolver
+	htlc     *SwapHTLC
+
+	notifier *mockNotifier
+	beacon   *mockWitnessBeacon
+	sweeps   chan input.Input
+
+	resolutions chan *SwapResolution
+	errs        chan error
+}
+
+func newSwapResolverHarness(t *testing.T, role SwapRole,
+	preimage lntypes.Preimage) *swapResolverHarness {
+
+	_, localKey := btcec.PrivKeyFromBytes(btcec.S256(), []byte{0x01})
+	_, remoteKey := btcec.PrivKeyFromBytes(btcec.S256(), []byte{0x02})
+
+	h := &swapResolverHarness{
+		t: t,
+		htlc: &SwapHTLC{
+			Role:        role,
+			PaymentHash: preimage.Hash(),
+			LocalKey: keychain.KeyDescriptor{
+				PubKey: localKey,
+			},
+			RemoteKey:  remoteKey,
+			CltvExpiry: 100,
+			HeightHint: 10,
+			OutPoint: &wire.OutPoint{
+				Hash:  chainhash.Hash{0x01},
+				Index: 1,
+			},
+			Amount: 100000,
+		},
+		notifier: &mockNotifier{
+			spendChan: make(chan *chainntnfs.SpendDetail),
+			epochChan: make(chan *chainntnfs.BlockEpoch),
+		},
+		beacon: &mockWitnessBeacon{
+			preImageUpdates: make(chan lntypes.Preimage),
+			newPreimages:    make(chan []lntypes.Preimage, 1),
+		},
+		sweeps:      make(chan input.Input, 1),
+		resolutions: make(chan *SwapResolution, 1),
+		errs:        make(chan error, 1),
+	}
+
+	h.resolver = NewSwapResolver(h.htlc, SwapResolverConfig{
+		Notifier:   h.notifier,
+		PreimageDB: h.beacon,
+		SweepInput: func(inp input.Input) (chan sweep.Result, error) {
+			h.sweeps <- inp
+			return make(chan sweep.Result), nil
+		},
+		Checkpoint: func(*SwapHTLC) error {
+			return nil
+		},
+	})
+
+	go func() {
+		resolution, err := h.resolver.Resolve()
+		if err != nil {
+			h.errs <- err
+			return
+		}
+		h.resolutions <- resolution
+	}()
+
+	return h
+}
+
+// assertSweep asserts that the HTLC is offered to the sweeper using the given
+// witness type.
+func (h *swapResolverHarness) assertSweep(witnessType input.WitnessType) {
+	h.t.Helper()
+
+	select {
+	case inp := <-h.sweeps:
+		if inp.WitnessType() != witnessType {
+			h.t.Fatalf("expected witness type %v, got %v",
+				witnessType, inp.WitnessType())
+		}
+		if *inp.OutPoint() != *h.htlc.OutPoint {
+			h.t.Fatalf("expected outpoint %v, got %v",
+				h.htlc.OutPoint, inp.OutPoint())
+		}
+	case <-time.After(5 * time.Second):
+		h.t.Fatalf("htlc not offered to sweeper")
+	}
+}
+
+// assertNoSweep asserts that the HTLC isn't offered to the sweeper.
+func (h *swapResolverHarness) assertNoSweep() {
+	h.t.Helper()
+
+	select {
+	case <-h.sweeps:
+		h.t.Fatalf("htlc unexpectedly offered to sweeper")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// spend notifies the resolver of a spend of the HTLC with the given witness,
+// and returns the resulting resolution.
+func (h *swapResolverHarness) spend(
+	witness wire.TxWitness) *SwapResolution {
+
+	h.t.Helper()
+
+	spendTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: *h.htlc.OutPoint,
+			Witness:          witness,
+		}},
+	}
+	spendTxid := spendTx.TxHash()
+
+	h.notifier.spendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:     h.htlc.OutPoint,
+		SpendingTx:        spendTx,
+		SpenderTxHash:     &spendTxid,
+		SpenderInputIndex: 0,
+	}
+
+	select {
+	case resolution := <-h.resolutions:
+		if resolution.SpendTxid != spendTxid {
+			h.t.Fatalf("expected spend txid %v, got %v", spendTxid,
+				resolution.SpendTxid)
+		}
+		return resolution
+
+	case err := <-h.errs:
+		h.t.Fatalf("unable to resolve swap htlc: %v", err)
+
+	case <-time.After(5 * time.Second):
+		h.t.Fatalf("swap htlc not resolved")
+	}
+
+	return nil
+}
+
+// TestSwapResolverClaim asserts that the receiver of a swap HTLC claims it
+// once the preimage becomes known.
+func TestSwapResolverClaim(t *testing.T) {
+	t.Parallel()
+
+	preimage := lntypes.Preimage{0x01}
+	h := newSwapResolverHarness(t, SwapRoleReceiver, preimage)
+
+	// An unrelated preimage shouldn't cause the HTLC to be swept.
+	h.beacon.preImageUpdates <- lntypes.Preimage{0x02}
+	h.assertNoSweep()
+
+	// Once the preimage is known, the HTLC should be offered to the
+	// sweeper using the success clause.
+	h.beacon.preImageUpdates <- preimage
+	h.assertSweep(input.SwapHTLCSuccess)
+
+	resolution := h.spend(wire.TxWitness{
+		[]byte{0x30}, preimage[:], []byte{0x51},
+	})
+	if resolution.Outcome != SwapOutcomeSuccess {
+		t.Fatalf("expected outcome %v, got %v", SwapOutcomeSuccess,
+			resolution.Outcome)
+	}
+	if resolution.Preimage != preimage {
+		t.Fatalf("expected preimage %v, got %v", preimage,
+			resolution.Preimage)
+	}
+}
+
+// TestSwapResolverRefund asserts that the sender of a swap HTLC reclaims it
+// once it has expired, and that the preimage is extracted if the HTLC is
+// claimed by the receiver instead.
+func TestSwapResolverRefund(t *testing.T) {
+	t.Parallel()
+
+	preimage := lntypes.Preimage{0x01}
+	h := newSwapResolverHarness(t, SwapRoleSender, preimage)
+
+	// Before the HTLC has expired, it shouldn't be swept.
+	h.notifier.epochChan <- &chainntnfs.BlockEpoch{
+		Height: int32(h.htlc.CltvExpiry - 1),
+	}
+	h.assertNoSweep()
+
+	// Once the chain has reached its expiry, the HTLC should be offered to
+	// the sweeper using the timeout clause.
+	h.notifier.epochChan <- &chainntnfs.BlockEpoch{
+		Height: int32(h.htlc.CltvExpiry),
+	}
+	h.assertSweep(input.SwapHTLCTimeout)
+
+	// If the receiver manages to claim the HTLC first, the preimage
+	// should be added to the PreimageDB.
+	resolution := h.spend(wire.TxWitness{
+		[]byte{0x30}, preimage[:], []byte{0x51},
+	})
+	if resolution.Outcome != SwapOutcomeSuccess {
+		t.Fatalf("expected outcome %v, got %v", SwapOutcomeSuccess,
+			resolution.Outcome)
+	}
+
+	select {
+	case preimages := <-h.beacon.newPreimages:
+		if len(preimages) != 1 || preimages[0] != preimage {
+			t.Fatalf("expected preimage %v to be added, got %v",
+				preimage, preimages)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("preimage not added")
+	}
+
+	// A spend through the timeout clause should be reported as such.
+	h = newSwapResolverHarness(t, SwapRoleSender, preimage)
+	resolution = h.spend(wire.TxWitness{[]byte{0x30}, nil, []byte{0x51}})
+	if resolution.Outcome != SwapOutcomeTimeout {
+		t.Fatalf("expected outcome %v, got %v", SwapOutcomeTimeout,
+			resolution.Outcome)
+	}
+}
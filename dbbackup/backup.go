@@ -0,0 +1,232 @@
+package dbbackup
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// ChunkSize is the size in bytes of the chunks snapshots are split
+	// into. It's a multiple of the page size of the database, such that a
+	// page changed in between two snapshots only causes a single chunk to
+	// be added by the latter.
+	ChunkSize = 64 * 1024
+)
+
+// Config houses the database the Backuper takes snapshots of, and where and
+// how often it does so.
+type Config struct {
+	// WriteSnapshot writes a consistent copy of the database file to w,
+	// returning the number of bytes written.
+	WriteSnapshot func(w io.Writer) (int64, error)
+
+	// Store is the store the snapshots are written to.
+	Store *Store
+
+	// Interval is the interval at which snapshots are taken.
+	Interval time.Duration
+
+	// NumSnapshots is the number of most recent snapshots that are kept
+	// within the store. Older ones are pruned after each backup.
+	NumSnapshots int
+}
+
+// BackupReport describes the outcome of a backup.
+type BackupReport struct {
+	// Manifest is the manifest of the snapshot taken.
+	Manifest *Manifest
+
+	// NumNewChunks is the number of chunks that weren't yet held by the
+	// store, and were thus written by the backup.
+	NumNewChunks int
+
+	// Duration is the time it took to take the snapshot.
+	Duration time.Duration
+}
+
+// Backuper periodically takes incremental, encrypted snapshots of the
+// database while the node is running. Each snapshot is split into chunks,
+// of which only those not yet held by the store are written, such that
+// frequent snapshots are cheap as long as the database doesn't change much
+// in between.
+type Backuper struct {
+	started uint32
+	stopped uint32
+
+	cfg *Config
+
+	// mu serializes backups, as a backup must not run concurrently with
+	// pruning the store.
+	mu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewBackuper creates a new Backuper from the given config.
+func NewBackuper(cfg *Config) *Backuper {
+	return &Backuper{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine taking a snapshot at each interval, starting
+// with one right away.
+func (b *Backuper) Start() error {
+	if !atomic.CompareAndSwapUint32(&b.started, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Starting database backups every %v", b.cfg.Interval)
+
+	b.wg.Add(1)
+	go b.backupPeriodically()
+
+	return nil
+}
+
+// Stop signals the Backuper to stop taking snapshots, and waits for a backup
+// in progress to complete.
+func (b *Backuper) Stop() error {
+	if !atomic.CompareAndSwapUint32(&b.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Stopping database backups")
+
+	close(b.quit)
+	b.wg.Wait()
+
+	return nil
+}
+
+// backupPeriodically takes a snapshot at each interval until the Backuper is
+// stopped. A failed backup is retried at the next interval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (b *Backuper) backupPeriodically() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := b.Backup(); err != nil {
+			log.Errorf("Unable to back up database: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-b.quit:
+			return
+		}
+	}
+}
+
+// Backup takes a snapshot of the database, writing the chunks not yet held
+// by the store, followed by its manifest. Once written, the store is pruned
+// down to the configured number of snapshots.
+func (b *Backuper) Backup() (*BackupReport, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	start := time.Now()
+
+	w := &chunkWriter{
+		store: b.cfg.Store,
+		buf:   make([]byte, 0, ChunkSize),
+	}
+	size, err := b.cfg.WriteSnapshot(w)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.flush(); err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Timestamp: start,
+		Size:      uint64(size),
+		Chunks:    w.chunks,
+	}
+	if err := b.cfg.Store.putManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	report := &BackupReport{
+		Manifest:     manifest,
+		NumNewChunks: w.numNewChunks,
+		Duration:     time.Since(start),
+	}
+
+	log.Infof("Backed up database of %d bytes in %v, writing %d of %d "+
+		"chunks", size, report.Duration, report.NumNewChunks,
+		len(manifest.Chunks))
+
+	if _, err := b.cfg.Store.Prune(b.cfg.NumSnapshots); err != nil {
+		log.Errorf("Unable to prune database backups: %v", err)
+	}
+
+	return report, nil
+}
+
+// chunkWriter is an io.Writer splitting the data written to it into chunks
+// of ChunkSize bytes, each of which is written to the store unless the store
+// already holds it.
+type chunkWriter struct {
+	store *Store
+	buf   []byte
+
+	chunks       []ChunkID
+	numNewChunks int
+}
+
+// Write appends p to the current chunk, writing out each chunk as soon as
+// it's full.
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		free := ChunkSize - len(w.buf)
+		if free > len(p) {
+			free = len(p)
+		}
+		w.buf = append(w.buf, p[:free]...)
+		p = p[free:]
+
+		if len(w.buf) == ChunkSize {
+			if err := w.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// flush writes out the current chunk, which may be partial if it's the last
+// one, unless the store already holds it.
+func (w *chunkWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	id := w.store.key.chunkID(w.buf)
+	w.chunks = append(w.chunks, id)
+
+	exists, err := w.store.hasChunk(id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := w.store.putChunk(id, w.buf); err != nil {
+			return err
+		}
+		w.numNewChunks++
+	}
+
+	w.buf = w.buf[:0]
+	return nil
+}
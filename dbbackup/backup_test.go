@@ -0,0 +1,224 @@
+package dbbackup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testDB is an in-memory database file whose snapshots are written by
+// WriteSnapshot.
+type testDB struct {
+	contents []byte
+}
+
+// WriteSnapshot writes the contents of the database to w.
+func (d *testDB) WriteSnapshot(w io.Writer) (int64, error) {
+	n, err := w.Write(d.contents)
+	return int64(n), err
+}
+
+// newTestBackuper creates a Backuper for the given database backed by a store
+// within a temporary directory, which is removed by the returned cleanup
+// function.
+func newTestBackuper(t *testing.T, db *testDB,
+	numSnapshots int) (*Backuper, *Store, func()) {
+
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "dbbackup")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+
+	var secret [KeySize]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	store, err := NewStore(filepath.Join(dir, "store"), NewKey(secret))
+	if err != nil {
+		t.Fatalf("unable to create store: %v", err)
+	}
+
+	backuper := NewBackuper(&Config{
+		WriteSnapshot: db.WriteSnapshot,
+		Store:         store,
+		NumSnapshots:  numSnapshots,
+	})
+
+	return backuper, store, func() { os.RemoveAll(dir) }
+}
+
+// assertRestore asserts that the latest snapshot of the store restores to
+// the expected contents.
+func assertRestore(t *testing.T, store *Store, expected []byte) {
+	t.Helper()
+
+	manifest, err := store.LatestSnapshot()
+	if err != nil {
+		t.Fatalf("unable to fetch latest snapshot: %v", err)
+	}
+
+	path := filepath.Join(store.dir, "restored.db")
+	defer os.Remove(path)
+
+	if err := Restore(store, manifest, path); err != nil {
+		t.Fatalf("unable to restore snapshot: %v", err)
+	}
+
+	restored, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read restored database: %v", err)
+	}
+	if !bytes.Equal(restored, expected) {
+		t.Fatalf("restored database doesn't match snapshot")
+	}
+}
+
+// TestIncrementalBackup tests that each backup only writes the chunks that
+// changed since the previous one, and that each snapshot can be restored.
+func TestIncrementalBackup(t *testing.T) {
+	t.Parallel()
+
+	db := &testDB{
+		contents: make([]byte, 3*ChunkSize+ChunkSize/2),
+	}
+	if _, err := rand.Read(db.contents); err != nil {
+		t.Fatalf("unable to generate contents: %v", err)
+	}
+
+	backuper, store, cleanUp := newTestBackuper(t, db, 10)
+	defer cleanUp()
+
+	// The first backup should write all chunks, including the final
+	// partial one.
+	report, err := backuper.Backup()
+	if err != nil {
+		t.Fatalf("unable to back up: %v", err)
+	}
+	if report.NumNewChunks != 4 || len(report.Manifest.Chunks) != 4 {
+		t.Fatalf("expected 4 new of 4 chunks, got %d of %d",
+			report.NumNewChunks, len(report.Manifest.Chunks))
+	}
+	assertRestore(t, store, db.contents)
+
+	// An unchanged database shouldn't cause any chunks to be written.
+	report, err = backuper.Backup()
+	if err != nil {
+		t.Fatalf("unable to back up: %v", err)
+	}
+	if report.NumNewChunks != 0 {
+		t.Fatalf("expected no new chunks, got %d", report.NumNewChunks)
+	}
+
+	// Changing a single byte should only cause its chunk to be written.
+	db.contents[ChunkSize+1] ^= 0xff
+	report, err = backuper.Backup()
+	if err != nil {
+		t.Fatalf("unable to back up: %v", err)
+	}
+	if report.NumNewChunks != 1 {
+		t.Fatalf("expected 1 new chunk, got %d", report.NumNewChunks)
+	}
+	assertRestore(t, store, db.contents)
+
+	snapshots, err := store.Snapshots()
+	if err != nil {
+		t.Fatalf("unable to list snapshots: %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(snapshots))
+	}
+}
+
+// TestBackupPrune tests that only the configured number of snapshots is
+// kept, along with the chunks they refer to.
+func TestBackupPrune(t *testing.T) {
+	t.Parallel()
+
+	db := &testDB{
+		contents: make([]byte, 2*ChunkSize),
+	}
+
+	backuper, store, cleanUp := newTestBackuper(t, db, 2)
+	defer cleanUp()
+
+	// Each backup changes the first chunk, such that the first chunk of
+	// the oldest snapshot is deleted along with it once pruned.
+	for i := 0; i < 4; i++ {
+		db.contents[0] = byte(i)
+		if _, err := backuper.Backup(); err != nil {
+			t.Fatalf("unable to back up: %v", err)
+		}
+	}
+
+	snapshots, err := store.Snapshots()
+	if err != nil {
+		t.Fatalf("unable to list snapshots: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(snapshots))
+	}
+
+	chunks, err := ioutil.ReadDir(filepath.Join(store.dir, chunksDir))
+	if err != nil {
+		t.Fatalf("unable to list chunks: %v", err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+
+	assertRestore(t, store, db.contents)
+}
+
+// TestRestoreTamperedChunk tests that a snapshot isn't restored if one of its
+// chunks has been tampered with, or if the file to restore to exists.
+func TestRestoreTamperedChunk(t *testing.T) {
+	t.Parallel()
+
+	db := &testDB{
+		contents: make([]byte, 2*ChunkSize),
+	}
+
+	backuper, store, cleanUp := newTestBackuper(t, db, 1)
+	defer cleanUp()
+
+	report, err := backuper.Backup()
+	if err != nil {
+		t.Fatalf("unable to back up: %v", err)
+	}
+
+	path := filepath.Join(store.dir, "restored.db")
+	if err := ioutil.WriteFile(path, nil, filePermission); err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+	if err := Restore(store, report.Manifest, path); err == nil {
+		t.Fatalf("expected existing file not to be overwritten")
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("unable to remove file: %v", err)
+	}
+
+	chunkPath := store.chunkPath(report.Manifest.Chunks[0])
+	ciphertext, err := ioutil.ReadFile(chunkPath)
+	if err != nil {
+		t.Fatalf("unable to read chunk: %v", err)
+	}
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	err = ioutil.WriteFile(chunkPath, ciphertext, filePermission)
+	if err != nil {
+		t.Fatalf("unable to write chunk: %v", err)
+	}
+
+	if err := Restore(store, report.Manifest, path); err == nil {
+		t.Fatalf("expected tampered chunk to be rejected")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no file to be restored, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package dbbackup
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// KeySize is the size in bytes of the key backups are encrypted with.
+	KeySize = 32
+
+	// keyFilePermission is the file mode of a newly created key file,
+	// which must only be readable by its owner.
+	keyFilePermission = 0600
+)
+
+// Key is the secret all backups of a store are encrypted and authenticated
+// with. Separate keys for encryption and for deriving the IDs of chunks are
+// derived from it, such that the IDs don't reveal anything about the
+// contents of the chunks.
+type Key struct {
+	encKey [KeySize]byte
+	macKey [KeySize]byte
+}
+
+// NewKey derives a Key from the raw secret read from a key file.
+func NewKey(secret [KeySize]byte) *Key {
+	//  encKey = SHA256("dbbackup-enc" || secret)
+	//  macKey = SHA256("dbbackup-mac" || secret)
+	deriveKey := func(label string) [KeySize]byte {
+		return sha256.Sum256(append([]byte(label), secret[:]...))
+	}
+
+	return &Key{
+		encKey: deriveKey("dbbackup-enc"),
+		macKey: deriveKey("dbbackup-mac"),
+	}
+}
+
+// LoadKey reads the hex encoded secret of a Key from the file at path.
+func LoadKey(path string) (*Key, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	secretBytes, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("key file %v isn't hex encoded: %v", path,
+			err)
+	}
+	if len(secretBytes) != KeySize {
+		return nil, fmt.Errorf("key file %v holds a key of %d bytes, "+
+			"expected %d", path, len(secretBytes), KeySize)
+	}
+
+	var secret [KeySize]byte
+	copy(secret[:], secretBytes)
+
+	return NewKey(secret), nil
+}
+
+// LoadOrCreateKey reads the Key from the file at path, after creating the file
+// with a new random secret if it doesn't exist yet.
+func LoadOrCreateKey(path string) (*Key, error) {
+	_, err := os.Stat(path)
+	switch {
+	case err == nil:
+		return LoadKey(path)
+
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	var secret [KeySize]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return nil, err
+	}
+
+	content := []byte(hex.EncodeToString(secret[:]) + "\n")
+	err = ioutil.WriteFile(path, content, keyFilePermission)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Warnf("Created new database backup key file %v. Store a copy of "+
+		"it apart from the node, as the backups can't be restored "+
+		"without it", path)
+
+	return NewKey(secret), nil
+}
+
+// chunkID returns the ID of the chunk with the given contents, which is an
+// HMAC of the contents keyed by the MAC key.
+func (k *Key) chunkID(chunk []byte) ChunkID {
+	mac := hmac.New(sha256.New, k.macKey[:])
+	mac.Write(chunk)
+
+	var id ChunkID
+	copy(id[:], mac.Sum(nil))
+
+	return id
+}
+
+// encrypt encrypts the plaintext with XChaCha20-Poly1305, using a random
+// nonce that's prepended to the ciphertext. The associated data binds the
+// ciphertext to its purpose, such that e.g. a chunk can't be passed off as a
+// manifest.
+func (k *Key) encrypt(plaintext, ad []byte) ([]byte, error) {
+	cipher, err := chacha20poly1305.NewX(k.encKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return cipher.Seal(nonce, nonce, plaintext, ad), nil
+}
+
+// decrypt decrypts a ciphertext produced by encrypt with the same associated
+// data.
+func (k *Key) decrypt(ciphertext, ad []byte) ([]byte, error) {
+	if len(ciphertext) < chacha20poly1305.NonceSizeX {
+		return nil, fmt.Errorf("ciphertext size too small, must be at "+
+			"least %v bytes", chacha20poly1305.NonceSizeX)
+	}
+
+	cipher, err := chacha20poly1305.NewX(k.encKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := ciphertext[:chacha20poly1305.NonceSizeX]
+	return cipher.Open(
+		nil, nonce, ciphertext[chacha20poly1305.NonceSizeX:], ad,
+	)
+}
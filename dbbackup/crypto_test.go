@@ -0,0 +1,59 @@
+package dbbackup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadOrCreateKey tests that a key file is created with a new key if it
+// doesn't exist, and that the same key is loaded from it afterwards.
+func TestLoadOrCreateKey(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "dbbackup")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "dbbackup.key")
+	if _, err := LoadKey(path); err == nil {
+		t.Fatalf("expected missing key file to be rejected")
+	}
+
+	key, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("unable to stat key file: %v", err)
+	}
+	if fileInfo.Mode().Perm() != keyFilePermission {
+		t.Fatalf("expected key file mode %v, got %v",
+			os.FileMode(keyFilePermission), fileInfo.Mode().Perm())
+	}
+
+	for _, load := range []func(string) (*Key, error){
+		LoadKey, LoadOrCreateKey,
+	} {
+		loadedKey, err := load(path)
+		if err != nil {
+			t.Fatalf("unable to load key: %v", err)
+		}
+		if *loadedKey != *key {
+			t.Fatalf("loaded key doesn't match created key")
+		}
+	}
+
+	// A key file holding a key of the wrong size should be rejected.
+	if err := ioutil.WriteFile(path, []byte("abcd"), 0600); err != nil {
+		t.Fatalf("unable to write key file: %v", err)
+	}
+	if _, err := LoadKey(path); err == nil {
+		t.Fatalf("expected key of invalid size to be rejected")
+	}
+}
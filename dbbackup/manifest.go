@@ -0,0 +1,113 @@
+package dbbackup
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// manifestVersion is the version of the manifest encoding.
+	manifestVersion = 1
+
+	// maxNumChunks is the maximum number of chunks a manifest may refer
+	// to, bounding the memory allocated when decoding one.
+	maxNumChunks = 1 << 24
+)
+
+// byteOrder is the byte order integers are encoded with.
+var byteOrder = binary.BigEndian
+
+// ChunkID identifies a chunk within a store by its contents.
+type ChunkID [32]byte
+
+// String returns the hex encoding of the ChunkID.
+func (c ChunkID) String() string {
+	return hex.EncodeToString(c[:])
+}
+
+// Manifest describes a snapshot of the database: the chunks it's made of, in
+// order. Since chunks are identified by their contents, snapshots share the
+// chunks the database didn't change in between.
+type Manifest struct {
+	// Timestamp is the time at which the snapshot was taken. It also
+	// identifies the snapshot within its store.
+	Timestamp time.Time
+
+	// Size is the size of the database file in bytes.
+	Size uint64
+
+	// Chunks are the IDs of the chunks the database file is made of, in
+	// order. All chunks but the last one are ChunkSize bytes large.
+	Chunks []ChunkID
+}
+
+// encode writes the manifest to w.
+func (m *Manifest) encode(w io.Writer) error {
+	header := []interface{}{
+		uint8(manifestVersion),
+		m.Timestamp.UnixNano(),
+		m.Size,
+		uint32(len(m.Chunks)),
+	}
+	for _, field := range header {
+		if err := binary.Write(w, byteOrder, field); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range m.Chunks {
+		if _, err := w.Write(id[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeManifest reads a manifest from r.
+func decodeManifest(r io.Reader) (*Manifest, error) {
+	var (
+		version   uint8
+		timestamp int64
+		numChunks uint32
+		m         Manifest
+	)
+	header := []interface{}{&version, &timestamp, &m.Size, &numChunks}
+	for _, field := range header {
+		if err := binary.Read(r, byteOrder, field); err != nil {
+			return nil, err
+		}
+	}
+
+	if version != manifestVersion {
+		return nil, fmt.Errorf("unknown manifest version %d", version)
+	}
+	if numChunks > maxNumChunks {
+		return nil, fmt.Errorf("manifest refers to %d chunks, more "+
+			"than the maximum of %d", numChunks, maxNumChunks)
+	}
+
+	m.Timestamp = time.Unix(0, timestamp)
+	m.Chunks = make([]ChunkID, numChunks)
+	for i := range m.Chunks {
+		if _, err := io.ReadFull(r, m.Chunks[i][:]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &m, nil
+}
+
+// serialize returns the encoding of the manifest.
+func (m *Manifest) serialize() ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+package dbbackup
+
+import (
+	"fmt"
+	"os"
+)
+
+// Restore writes the database file of the snapshot described by the manifest
+// to path, verifying the integrity of each chunk along the way. The file is
+// only created once the snapshot has been restored in full, and an existing
+// file is never overwritten.
+func Restore(store *Store, manifest *Manifest, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("unable to restore to %v: file exists", path)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	log.Infof("Restoring database snapshot taken at %v to %v",
+		manifest.Timestamp, path)
+
+	tempPath := path + tempSuffix
+	f, err := os.OpenFile(
+		tempPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePermission,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := writeChunks(store, manifest, f); err != nil {
+		f.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
+
+// writeChunks writes the chunks of the snapshot described by the manifest to
+// the file in order, checking that they add up to the size of the snapshot.
+func writeChunks(store *Store, manifest *Manifest, f *os.File) error {
+	var size uint64
+	for i, id := range manifest.Chunks {
+		chunk, err := store.fetchChunk(id)
+		if err != nil {
+			return err
+		}
+
+		isLast := i == len(manifest.Chunks)-1
+		if len(chunk) > ChunkSize || !isLast && len(chunk) != ChunkSize {
+			return fmt.Errorf("chunk %v of snapshot has invalid "+
+				"size %d", i, len(chunk))
+		}
+
+		if _, err := f.Write(chunk); err != nil {
+			return err
+		}
+		size += uint64(len(chunk))
+	}
+
+	if size != manifest.Size {
+		return fmt.Errorf("snapshot holds %d bytes, expected %d", size,
+			manifest.Size)
+	}
+
+	return nil
+}
@@ -0,0 +1,306 @@
+package dbbackup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// chunksDir is the directory of a store holding the encrypted chunks,
+	// each within a file named by its hex encoded ID.
+	chunksDir = "chunks"
+
+	// snapshotsDir is the directory of a store holding the encrypted
+	// manifests, each within a file named by the unix timestamp in
+	// nanoseconds at which its snapshot was taken.
+	snapshotsDir = "snapshots"
+
+	// tempSuffix is the suffix of the files being written, which are
+	// renamed once complete.
+	tempSuffix = ".tmp"
+
+	// dirPermission is the file mode of the directories of a store.
+	dirPermission = 0700
+
+	// filePermission is the file mode of the files of a store.
+	filePermission = 0600
+)
+
+var (
+	// chunkAD is the associated data chunks are encrypted with.
+	chunkAD = []byte("chunk")
+
+	// manifestAD is the associated data manifests are encrypted with.
+	manifestAD = []byte("manifest")
+
+	// ErrNoSnapshots is returned when restoring from a store that doesn't
+	// hold any snapshots.
+	ErrNoSnapshots = errors.New("no snapshots found")
+)
+
+// Store is a directory holding encrypted snapshots of the database. The
+// contents of each snapshot are split into chunks, which are stored once and
+// shared by all snapshots containing them, such that each snapshot only adds
+// the chunks that changed since the previous one. Any directory can serve as
+// a store, though it should be located on another disk than the database,
+// e.g. a network share or a directory that is synced off-site.
+type Store struct {
+	dir string
+	key *Key
+}
+
+// NewStore creates a new Store within dir, whose snapshots are encrypted with
+// key. The directory is created if it doesn't exist yet.
+func NewStore(dir string, key *Key) (*Store, error) {
+	for _, subDir := range []string{chunksDir, snapshotsDir} {
+		err := os.MkdirAll(filepath.Join(dir, subDir), dirPermission)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Store{
+		dir: dir,
+		key: key,
+	}, nil
+}
+
+// chunkPath returns the path of the file holding the chunk with the given ID.
+func (s *Store) chunkPath(id ChunkID) string {
+	return filepath.Join(s.dir, chunksDir, id.String())
+}
+
+// manifestPath returns the path of the file holding the manifest of the
+// snapshot taken at the given time.
+func (s *Store) manifestPath(timestamp time.Time) string {
+	name := fmt.Sprintf("%020d", timestamp.UnixNano())
+	return filepath.Join(s.dir, snapshotsDir, name)
+}
+
+// hasChunk returns whether the store holds the chunk with the given ID.
+func (s *Store) hasChunk(id ChunkID) (bool, error) {
+	_, err := os.Stat(s.chunkPath(id))
+	switch {
+	case err == nil:
+		return true, nil
+
+	case os.IsNotExist(err):
+		return false, nil
+
+	default:
+		return false, err
+	}
+}
+
+// putChunk encrypts and writes the chunk with the given ID.
+func (s *Store) putChunk(id ChunkID, chunk []byte) error {
+	ciphertext, err := s.key.encrypt(chunk, chunkAD)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.chunkPath(id), ciphertext)
+}
+
+// fetchChunk reads and decrypts the chunk with the given ID, verifying that
+// its contents match the ID.
+func (s *Store) fetchChunk(id ChunkID) ([]byte, error) {
+	ciphertext, err := ioutil.ReadFile(s.chunkPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	chunk, err := s.key.decrypt(ciphertext, chunkAD)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt chunk %v: %v", id,
+			err)
+	}
+
+	if actualID := s.key.chunkID(chunk); actualID != id {
+		return nil, fmt.Errorf("chunk %v has ID %v", id, actualID)
+	}
+
+	return chunk, nil
+}
+
+// putManifest encrypts and writes the manifest of a snapshot. The snapshot
+// is only complete once its manifest is written, so all of its chunks must
+// be written beforehand.
+func (s *Store) putManifest(m *Manifest) error {
+	plaintext, err := m.serialize()
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := s.key.encrypt(plaintext, manifestAD)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.manifestPath(m.Timestamp), ciphertext)
+}
+
+// FetchManifest reads and decrypts the manifest of the snapshot taken at the
+// given time.
+func (s *Store) FetchManifest(timestamp time.Time) (*Manifest, error) {
+	ciphertext, err := ioutil.ReadFile(s.manifestPath(timestamp))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := s.key.decrypt(ciphertext, manifestAD)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt manifest of "+
+			"snapshot %v: %v", timestamp, err)
+	}
+
+	m, err := decodeManifest(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	if !m.Timestamp.Equal(timestamp) {
+		return nil, fmt.Errorf("manifest of snapshot %v is of "+
+			"snapshot %v", timestamp, m.Timestamp)
+	}
+
+	return m, nil
+}
+
+// Snapshots returns the times at which the complete snapshots within the
+// store were taken, oldest first.
+func (s *Store) Snapshots() ([]time.Time, error) {
+	files, err := ioutil.ReadDir(filepath.Join(s.dir, snapshotsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	var timestamps []time.Time
+	for _, file := range files {
+		nanos, err := strconv.ParseInt(file.Name(), 10, 64)
+		if err != nil {
+			// Manifests still being written, or left over by
+			// an interrupted backup, aren't part of the store.
+			continue
+		}
+
+		timestamps = append(timestamps, time.Unix(0, nanos))
+	}
+
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i].Before(timestamps[j])
+	})
+
+	return timestamps, nil
+}
+
+// LatestSnapshot returns the manifest of the most recent snapshot within the
+// store, or ErrNoSnapshots if it doesn't hold any.
+func (s *Store) LatestSnapshot() (*Manifest, error) {
+	timestamps, err := s.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+	if len(timestamps) == 0 {
+		return nil, ErrNoSnapshots
+	}
+
+	return s.FetchManifest(timestamps[len(timestamps)-1])
+}
+
+// Prune deletes all but the numSnapshots most recent snapshots, along with
+// the chunks no longer referred to by any of the remaining snapshots. It
+// returns the number of snapshots deleted.
+//
+// NOTE: Chunks are written before the manifest of their snapshot, so this
+// must not run concurrently with a backup to the same store.
+func (s *Store) Prune(numSnapshots int) (int, error) {
+	timestamps, err := s.Snapshots()
+	if err != nil {
+		return 0, err
+	}
+
+	var numDeleted int
+	for len(timestamps)-numDeleted > numSnapshots {
+		err := os.Remove(s.manifestPath(timestamps[numDeleted]))
+		if err != nil {
+			return numDeleted, err
+		}
+		numDeleted++
+	}
+
+	referenced := make(map[string]struct{})
+	for _, timestamp := range timestamps[numDeleted:] {
+		m, err := s.FetchManifest(timestamp)
+		if err != nil {
+			return numDeleted, err
+		}
+
+		for _, id := range m.Chunks {
+			referenced[id.String()] = struct{}{}
+		}
+	}
+
+	chunksPath := filepath.Join(s.dir, chunksDir)
+	files, err := ioutil.ReadDir(chunksPath)
+	if err != nil {
+		return numDeleted, err
+	}
+
+	var numChunks int
+	for _, file := range files {
+		if _, ok := referenced[file.Name()]; ok {
+			continue
+		}
+
+		// Besides the chunks of the deleted snapshots, this removes
+		// those left over by interrupted backups.
+		path := filepath.Join(chunksPath, file.Name())
+		if err := os.Remove(path); err != nil {
+			return numDeleted, err
+		}
+		numChunks++
+	}
+
+	log.Debugf("Pruned %d snapshots and %d chunks from %v", numDeleted,
+		numChunks, s.dir)
+
+	return numDeleted, nil
+}
+
+// writeFileAtomic writes data to a temporary file, syncs it to disk, and
+// then renames it to path, such that path either doesn't exist or holds the
+// data in full.
+func writeFileAtomic(path string, data []byte) error {
+	tempPath := path + tempSuffix
+
+	f, err := os.OpenFile(
+		tempPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePermission,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
@@ -1,10 +1,12 @@
 package discovery
 
 import (
+	"io"
 	"time"
 
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/routing/route"
 )
@@ -57,6 +59,36 @@ type ChannelGraphTimeSeries interface {
 	// channel, then an empty slice will be returned.
 	FetchChanUpdates(chain chainhash.Hash,
 		shortChanID lnwire.ShortChannelID) ([]*lnwire.ChannelUpdate, error)
+
+	// FetchChanUpdateInfo returns the timestamps and checksums of the
+	// latest channel updates of each of the specified short channel ID's,
+	// at the same index. Unknown channels, and directions without a known
+	// channel update, are left zero valued. We'll use this to fill in the
+	// extended fields of a ReplyChannelRange, and to determine which
+	// channel updates of a remote peer are newer than ours.
+	FetchChanUpdateInfo(chain chainhash.Hash,
+		shortChanIDs []lnwire.ShortChannelID) ([]ChanUpdateInfo, error)
+
+	// ExportGraphSnapshot writes a compact binary snapshot of our entire
+	// view of the public channel graph to the passed writer. The snapshot
+	// contains every advertised channel along with its latest updates,
+	// and the announcements of all nodes that take part in them.
+	ExportGraphSnapshot(chain chainhash.Hash, w io.Writer) error
+
+	// ImportGraphSnapshot decodes a snapshot previously created by
+	// ExportGraphSnapshot for the target chain. The contained messages are
+	// returned in an order suitable for processing, such that channel
+	// announcements precede their updates, and node announcements follow
+	// the channels they take part in.
+	ImportGraphSnapshot(chain chainhash.Hash,
+		r io.Reader) ([]lnwire.Message, error)
+}
+
+// ChanUpdateInfo holds the timestamps and checksums of the latest channel
+// updates of both directions of a channel.
+type ChanUpdateInfo struct {
+	lnwire.ChanUpdateTimestamps
+	lnwire.ChanUpdateChecksums
 }
 
 // ChanSeries is an implementation of the ChannelGraphTimeSeries
@@ -368,6 +400,181 @@ func (c *ChanSeries) FetchChanUpdates(chain chainhash.Hash,
 	return chanUpdates, nil
 }
 
+// FetchChanUpdateInfo returns the timestamps and checksums of the latest
+// channel updates of each of the specified short channel ID's, at the same
+// index. Unknown channels, and directions without a known channel update, are
+// left zero valued.
+//
+// NOTE: This is part of the ChannelGraphTimeSeries interface.
+func (c *ChanSeries) FetchChanUpdateInfo(chain chainhash.Hash,
+	shortChanIDs []lnwire.ShortChannelID) ([]ChanUpdateInfo, error) {
+
+	chanIDs := make([]uint64, 0, len(shortChanIDs))
+	for _, chanID := range shortChanIDs {
+		chanIDs = append(chanIDs, chanID.ToUint64())
+	}
+
+	channels, err := c.graph.FetchChanInfos(chanIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// As unknown channels are skipped, we'll index the channels we found
+	// by their ID in order to line them up with the queried ID's.
+	chanIndex := make(map[uint64]channeldb.ChannelEdge, len(channels))
+	for _, channel := range channels {
+		chanIndex[channel.Info.ChannelID] = channel
+	}
+
+	updateInfo := make([]ChanUpdateInfo, len(shortChanIDs))
+	for i, chanID := range chanIDs {
+		channel, ok := chanIndex[chanID]
+		if !ok {
+			continue
+		}
+
+		info := &updateInfo[i]
+		if channel.Policy1 != nil {
+			info.Timestamp1 = uint32(channel.Policy1.LastUpdate.Unix())
+			info.Checksum1, err = policyChecksum(
+				channel.Info, channel.Policy1,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if channel.Policy2 != nil {
+			info.Timestamp2 = uint32(channel.Policy2.LastUpdate.Unix())
+			info.Checksum2, err = policyChecksum(
+				channel.Info, channel.Policy2,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return updateInfo, nil
+}
+
+// policyChecksum computes the checksum of the channel update that produced
+// the given policy.
+func policyChecksum(info *channeldb.ChannelEdgeInfo,
+	policy *channeldb.ChannelEdgePolicy) (uint32, error) {
+
+	return lnwire.ChannelUpdateChecksum(&lnwire.ChannelUpdate{
+		ChainHash:       info.ChainHash,
+		ShortChannelID:  lnwire.NewShortChanIDFromInt(info.ChannelID),
+		Timestamp:       uint32(policy.LastUpdate.Unix()),
+		MessageFlags:    policy.MessageFlags,
+		ChannelFlags:    policy.ChannelFlags,
+		TimeLockDelta:   policy.TimeLockDelta,
+		HtlcMinimumMsat: policy.MinHTLC,
+		HtlcMaximumMsat: policy.MaxHTLC,
+		BaseFee:         uint32(policy.FeeBaseMSat),
+		FeeRate:         uint32(policy.FeeProportionalMillionths),
+		ExtraOpaqueData: policy.ExtraOpaqueData,
+	})
+}
+
+// ExportGraphSnapshot writes a compact binary snapshot of our entire view of
+// the public channel graph to the passed writer. The snapshot contains every
+// advertised channel along with its latest updates, and the announcements of
+// all nodes that take part in them.
+//
+// NOTE: This is part of the ChannelGraphTimeSeries interface.
+func (c *ChanSeries) ExportGraphSnapshot(chain chainhash.Hash,
+	w io.Writer) error {
+
+	var (
+		msgs []lnwire.Message
+
+		// publicNodes tracks the set of nodes that have at least one
+		// advertised channel, as only their announcements should be
+		// included within the snapshot.
+		publicNodes = make(map[route.Vertex]struct{})
+	)
+
+	// First, we'll gather all of the channels that have been fully
+	// advertised, along with their latest channel updates.
+	err := c.graph.ForEachChannel(func(info *channeldb.ChannelEdgeInfo,
+		e1, e2 *channeldb.ChannelEdgePolicy) error {
+
+		// Private channels, or those that haven't been announced yet,
+		// can't be included as we'd be unable to construct a full
+		// authentication proof for them.
+		if info.AuthProof == nil {
+			return nil
+		}
+
+		chanAnn, edge1, edge2, err := CreateChanAnnouncement(
+			info.AuthProof, info, e1, e2,
+		)
+		if err != nil {
+			return err
+		}
+
+		msgs = append(msgs, chanAnn)
+		if edge1 != nil {
+			msgs = append(msgs, edge1)
+		}
+		if edge2 != nil {
+			msgs = append(msgs, edge2)
+		}
+
+		publicNodes[info.NodeKey1Bytes] = struct{}{}
+		publicNodes[info.NodeKey2Bytes] = struct{}{}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Next, we'll add the node announcements for all of the public nodes
+	// we know of. We add these after the channels to ensure they follow
+	// the channels that make them public when the snapshot is imported.
+	err = c.graph.ForEachNode(nil, func(_ kvdb.Tx,
+		node *channeldb.LightningNode) error {
+
+		if !node.HaveNodeAnnouncement {
+			return nil
+		}
+		if _, ok := publicNodes[node.PubKeyBytes]; !ok {
+			return nil
+		}
+
+		nodeAnn, err := node.NodeAnnouncement(true)
+		if err != nil {
+			return err
+		}
+
+		msgs = append(msgs, nodeAnn)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Exporting graph snapshot with %d messages", len(msgs))
+
+	return writeGraphSnapshot(w, chain, msgs)
+}
+
+// ImportGraphSnapshot decodes a snapshot previously created by
+// ExportGraphSnapshot for the target chain. The contained messages are
+// returned in an order suitable for processing, such that channel
+// announcements precede their updates, and node announcements follow the
+// channels they take part in.
+//
+// NOTE: This is part of the ChannelGraphTimeSeries interface.
+func (c *ChanSeries) ImportGraphSnapshot(chain chainhash.Hash,
+	r io.Reader) ([]lnwire.Message, error) {
+
+	return readGraphSnapshot(r, chain)
+}
+
 // A compile-time assertion to ensure that ChanSeries meets the
 // ChannelGraphTimeSeries interface.
 var _ ChannelGraphTimeSeries = (*ChanSeries)(nil)
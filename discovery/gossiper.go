@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -14,6 +15,7 @@ import (
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/lnd/batch"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/lnpeer"
@@ -205,6 +207,53 @@ type Config struct {
 	// activeSyncer due to the current one not completing its state machine
 	// within the timeout.
 	ActiveSyncerTimeoutTicker ticker.Ticker
+
+	// SyncerHealthCheckTicker is a ticker responsible for notifying the
+	// syncManager when it should check whether any of its active syncers
+	// have stopped delivering graph updates.
+	SyncerHealthCheckTicker ticker.Ticker
+
+	// StaleSyncerTimeout is the duration after which an active syncer
+	// that hasn't delivered any graph updates, while other peers have, is
+	// replaced with a passive one. A zero value disables the check.
+	StaleSyncerTimeout time.Duration
+
+	// NoSyncersTimeout is the duration we may go without any gossip
+	// syncers before RequestBootstrap is invoked. A zero value disables
+	// the fallback.
+	NoSyncersTimeout time.Duration
+
+	// RequestBootstrap is called to prompt the network bootstrapper to
+	// dial fresh peers once we've been without any gossip syncers for
+	// longer than NoSyncersTimeout. It must not block.
+	RequestBootstrap func()
+
+	// IsChainSynced returns whether the chain backend has completed its
+	// initial block download. Until it has, we won't actively sync the
+	// channel graph with any peer. If nil, the chain is assumed to be
+	// synced.
+	IsChainSynced func() (bool, error)
+
+	// ChainSyncTicker is a ticker responsible for notifying the
+	// syncManager when it should check whether the chain backend has
+	// completed its initial block download.
+	ChainSyncTicker ticker.Ticker
+
+	// KeepAliveUpdateInterval is the minimum interval between rebroadcasts
+	// of remote ChannelUpdates for the same channel direction that only
+	// refresh the timestamp of its policy. Such updates are still applied
+	// to our graph. A zero value disables the rate limiting.
+	KeepAliveUpdateInterval time.Duration
+
+	// GossipHorizon determines how far back in time the graph updates we
+	// request from the peers we're actively syncing with should reach. A
+	// zero value signals that we're only interested in new graph updates.
+	GossipHorizon time.Duration
+
+	// RequestBatchSize is the maximum number of channels we'll query the
+	// peers we're syncing with for in a single request. If zero,
+	// DefaultRequestBatchSize is used.
+	RequestBatchSize int32
 }
 
 // AuthenticatedGossiper is a subsystem which is responsible for receiving
@@ -274,6 +323,10 @@ type AuthenticatedGossiper struct {
 	rejectMtx     sync.RWMutex
 	recentRejects map[uint64]struct{}
 
+	// keepAliveThrottle rate limits the rebroadcast of remote
+	// ChannelUpdates that don't change a channel's policy.
+	keepAliveThrottle *keepAliveThrottle
+
 	// syncMgr is a subsystem responsible for managing the gossip syncers
 	// for peers currently connected. When a new peer is connected, the
 	// manager will create its accompanying gossip syncer and determine
@@ -306,12 +359,23 @@ func New(cfg Config, selfKey *btcec.PublicKey) *AuthenticatedGossiper {
 		prematureChannelUpdates: make(map[uint64][]*networkMsg),
 		channelMtx:              multimutex.NewMutex(),
 		recentRejects:           make(map[uint64]struct{}),
+		keepAliveThrottle: newKeepAliveThrottle(
+			cfg.KeepAliveUpdateInterval,
+		),
 		syncMgr: newSyncManager(&SyncManagerCfg{
 			ChainHash:            cfg.ChainHash,
 			ChanSeries:           cfg.ChanSeries,
 			RotateTicker:         cfg.RotateTicker,
 			HistoricalSyncTicker: cfg.HistoricalSyncTicker,
+			HealthCheckTicker:    cfg.SyncerHealthCheckTicker,
+			StaleSyncerTimeout:   cfg.StaleSyncerTimeout,
+			NoSyncersTimeout:     cfg.NoSyncersTimeout,
+			RequestBootstrap:     cfg.RequestBootstrap,
+			IsChainSynced:        cfg.IsChainSynced,
+			ChainSyncTicker:      cfg.ChainSyncTicker,
 			NumActiveSyncers:     cfg.NumActiveSyncers,
+			GossipHorizon:        cfg.GossipHorizon,
+			RequestBatchSize:     cfg.RequestBatchSize,
 		}),
 	}
 
@@ -529,6 +593,9 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 
 	errChan := make(chan error, 1)
 
+	// Account for the message within the stats of the peer's syncer.
+	d.syncMgr.RecordReceivedMsg(peer.PubKey(), msg)
+
 	// For messages in the known set of channel series queries, we'll
 	// dispatch the message directly to the GossipSyncer, and skip the main
 	// processing loop.
@@ -578,6 +645,14 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 
 		errChan <- nil
 		return errChan
+
+	// Graph updates are tracked by the SyncManager in order to detect any
+	// active syncers that have stopped delivering them.
+	case *lnwire.ChannelAnnouncement,
+		*lnwire.ChannelUpdate,
+		*lnwire.NodeAnnouncement:
+
+		d.syncMgr.RecordGraphUpdate(peer.PubKey())
 	}
 
 	nMsg := &networkMsg{
@@ -632,6 +707,48 @@ func (d *AuthenticatedGossiper) ProcessLocalAnnouncement(msg lnwire.Message,
 	return nMsg.err
 }
 
+// ExportGraphSnapshot writes a compact binary snapshot of our entire view of
+// the public channel graph to the passed writer. The snapshot can later be
+// imported by a fresh node using ImportGraphSnapshot.
+func (d *AuthenticatedGossiper) ExportGraphSnapshot(w io.Writer) error {
+	return d.cfg.ChanSeries.ExportGraphSnapshot(d.cfg.ChainHash, w)
+}
+
+// ImportGraphSnapshot decodes a graph snapshot, as created by the
+// ChannelGraphTimeSeries, and feeds every message within it through our
+// regular processing pipeline. As the snapshot may originate from an
+// untrusted source, each message is validated as if it were received from a
+// remote peer. Once all messages have been queued for processing, the initial
+// historical sync will be skipped as we already hold a full view of the
+// graph. The number of messages queued is returned.
+func (d *AuthenticatedGossiper) ImportGraphSnapshot(r io.Reader) (int, error) {
+	msgs, err := d.cfg.ChanSeries.ImportGraphSnapshot(d.cfg.ChainHash, r)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Infof("Importing graph snapshot with %d messages", len(msgs))
+
+	for _, msg := range msgs {
+		nMsg := &networkMsg{
+			msg:      msg,
+			isRemote: true,
+			source:   d.selfKey,
+			err:      make(chan error, 1),
+		}
+
+		select {
+		case d.networkMsgs <- nMsg:
+		case <-d.quit:
+			return 0, ErrGossiperShuttingDown
+		}
+	}
+
+	d.syncMgr.MarkGraphBootstrapped()
+
+	return len(msgs), nil
+}
+
 // channelUpdateID is a unique identifier for ChannelUpdate messages, as
 // channel updates can be identified by the (ShortChannelID, ChannelFlags)
 // tuple.
@@ -1117,6 +1234,8 @@ func (d *AuthenticatedGossiper) networkHandler() {
 					"channels: %v", err)
 			}
 
+			d.keepAliveThrottle.prune(time.Now())
+
 		// The gossiper has been signalled to exit, to we exit our
 		// main loop so the wait group can be decremented.
 		case <-d.quit:
@@ -1451,6 +1570,53 @@ func (d *AuthenticatedGossiper) processRejectedEdge(
 	return announcements, nil
 }
 
+// processZombieUpdate marks the zombie channel of a fresh channel update as
+// live, after making sure the update has been signed by the correct party. If
+// the router is able to restore the channel right away, the channel is returned
+// so the update can be applied to it. Otherwise, ErrEdgeNotFound is returned,
+// as the update can only be applied once the channel's announcement is received
+// again.
+func (d *AuthenticatedGossiper) processZombieUpdate(
+	chanInfo *channeldb.ChannelEdgeInfo, msg *lnwire.ChannelUpdate) (
+	*channeldb.ChannelEdgeInfo, *channeldb.ChannelEdgePolicy,
+	*channeldb.ChannelEdgePolicy, error) {
+
+	// The least-significant bit in the flag on the channel update tells
+	// us which edge is being updated.
+	var pubKey *btcec.PublicKey
+	switch {
+	case msg.ChannelFlags&lnwire.ChanUpdateDirection == 0:
+		pubKey, _ = chanInfo.NodeKey1()
+	case msg.ChannelFlags&lnwire.ChanUpdateDirection == 1:
+		pubKey, _ = chanInfo.NodeKey2()
+	}
+
+	err := routing.VerifyChannelUpdateSignature(msg, pubKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to verify channel "+
+			"update signature: %v", err)
+	}
+
+	// With the signature valid, we'll proceed to mark the edge as live.
+	// If its info was retained, it's added back to the graph right away.
+	restored, err := d.cfg.Router.ResurrectEdge(msg.ShortChannelID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to resurrect edge "+
+			"with chan_id=%v: %v", msg.ShortChannelID, err)
+	}
+
+	log.Debugf("Removed edge with chan_id=%v from zombie index",
+		msg.ShortChannelID)
+
+	// Otherwise, we'll need to wait for the channel announcement to come
+	// through again before the update can be applied.
+	if !restored {
+		return nil, nil, nil, channeldb.ErrEdgeNotFound
+	}
+
+	return d.cfg.Router.GetChannelByID(msg.ShortChannelID)
+}
+
 // processNetworkAnnouncement processes a new network relate authenticated
 // channel or node announcement or announcements proofs. If the announcement
 // didn't affect the internal state due to either being out of date, invalid,
@@ -1466,6 +1632,16 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 		return chanID.BlockHeight+delta > bestHeight
 	}
 
+	// Announcements received from remote peers are written to the graph
+	// lazily, such that they can be batched together with other
+	// concurrent writes. This greatly reduces disk I/O while we're
+	// processing large amounts of announcements, e.g. during a historical
+	// sync.
+	var schedulerOp []batch.SchedulerOption
+	if nMsg.isRemote {
+		schedulerOp = append(schedulerOp, batch.LazyAdd())
+	}
+
 	var announcements []networkMsg
 
 	switch msg := nMsg.msg.(type) {
@@ -1480,6 +1656,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 		// newer update for this node so we can skip validating
 		// signatures if not required.
 		if d.cfg.Router.IsStaleNode(msg.NodeID, timestamp) {
+			d.recordUpdateResult(nMsg, false)
 			nMsg.err <- nil
 			return nil
 		}
@@ -1507,10 +1684,11 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 			ExtraOpaqueData:      msg.ExtraOpaqueData,
 		}
 
-		if err := d.cfg.Router.AddNode(node); err != nil {
+		if err := d.cfg.Router.AddNode(node, schedulerOp...); err != nil {
 			if routing.IsError(err, routing.ErrOutdated,
 				routing.ErrIgnored) {
 
+				d.recordUpdateResult(nMsg, false)
 				log.Debug(err)
 			} else {
 				log.Error(err)
@@ -1519,6 +1697,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 			nMsg.err <- err
 			return nil
 		}
+		d.recordUpdateResult(nMsg, true)
 
 		// In order to ensure we don't leak unadvertised nodes, we'll
 		// make a quick check to ensure this node intends to publicly
@@ -1670,7 +1849,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 		// writes to the DB.
 		d.channelMtx.Lock(msg.ShortChannelID.ToUint64())
 		defer d.channelMtx.Unlock(msg.ShortChannelID.ToUint64())
-		if err := d.cfg.Router.AddEdge(edge); err != nil {
+		if err := d.cfg.Router.AddEdge(edge, schedulerOp...); err != nil {
 			// If the edge was rejected due to already being known,
 			// then it may be that case that this new message has a
 			// fresh channel proof, so we'll check.
@@ -1699,6 +1878,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 
 				// Otherwise, this is just a regular rejected
 				// edge.
+				d.recordUpdateResult(nMsg, false)
 				log.Debugf("Router rejected channel "+
 					"edge: %v", err)
 			} else {
@@ -1709,6 +1889,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 			nMsg.err <- err
 			return nil
 		}
+		d.recordUpdateResult(nMsg, true)
 
 		// If we earlier received any ChannelUpdates for this channel,
 		// we can now process them, as the channel is added to the
@@ -1824,6 +2005,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 		if d.cfg.Router.IsStaleEdgePolicy(
 			msg.ShortChannelID, timestamp, msg.ChannelFlags,
 		) {
+			d.recordUpdateResult(nMsg, false)
 			nMsg.err <- nil
 			return nil
 		}
@@ -1838,56 +2020,28 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 		// point and when we call UpdateEdge() later.
 		d.channelMtx.Lock(msg.ShortChannelID.ToUint64())
 		defer d.channelMtx.Unlock(msg.ShortChannelID.ToUint64())
-		chanInfo, _, _, err := d.cfg.Router.GetChannelByID(msg.ShortChannelID)
-		switch err {
-		// No error, break.
-		case nil:
-			break
-
-		case channeldb.ErrZombieEdge:
-			// Since we've deemed the update as not stale above,
-			// before marking it live, we'll make sure it has been
-			// signed by the correct party. The least-significant
-			// bit in the flag on the channel update tells us which
-			// edge is being updated.
-			var pubKey *btcec.PublicKey
-			switch {
-			case msg.ChannelFlags&lnwire.ChanUpdateDirection == 0:
-				pubKey, _ = chanInfo.NodeKey1()
-			case msg.ChannelFlags&lnwire.ChanUpdateDirection == 1:
-				pubKey, _ = chanInfo.NodeKey2()
-			}
-
-			err := routing.VerifyChannelUpdateSignature(msg, pubKey)
-			if err != nil {
-				err := fmt.Errorf("unable to verify channel "+
-					"update signature: %v", err)
-				log.Error(err)
-				nMsg.err <- err
-				return nil
-			}
+		chanInfo, e1, e2, err := d.cfg.Router.GetChannelByID(
+			msg.ShortChannelID,
+		)
 
-			// With the signature valid, we'll proceed to mark the
-			// edge as live and wait for the channel announcement to
-			// come through again.
-			err = d.cfg.Router.MarkEdgeLive(msg.ShortChannelID)
-			if err != nil {
-				err := fmt.Errorf("unable to remove edge with "+
-					"chan_id=%v from zombie index: %v",
-					msg.ShortChannelID, err)
+		// Since we've deemed the update as not stale above, it brings
+		// the channel back to life if it was marked as a zombie.
+		if err == channeldb.ErrZombieEdge {
+			chanInfo, e1, e2, err = d.processZombieUpdate(
+				chanInfo, msg,
+			)
+			if err != nil && err != channeldb.ErrEdgeNotFound {
 				log.Error(err)
 				nMsg.err <- err
 				return nil
 			}
+		}
 
-			log.Debugf("Removed edge with chan_id=%v from zombie "+
-				"index", msg.ShortChannelID)
+		switch err {
+		// No error, break.
+		case nil:
+			break
 
-			// We'll fallthrough to ensure we stash the update until
-			// we receive its corresponding ChannelAnnouncement.
-			// This is needed to ensure the edge exists in the graph
-			// before applying the update.
-			fallthrough
 		case channeldb.ErrGraphNotFound:
 			fallthrough
 		case channeldb.ErrGraphNoEdgesFound:
@@ -1937,12 +2091,17 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 		// The least-significant bit in the flag on the channel update
 		// announcement tells us "which" side of the channels directed
 		// edge is being updated.
-		var pubKey *btcec.PublicKey
+		var (
+			pubKey     *btcec.PublicKey
+			prevPolicy *channeldb.ChannelEdgePolicy
+		)
 		switch {
 		case msg.ChannelFlags&lnwire.ChanUpdateDirection == 0:
 			pubKey, _ = chanInfo.NodeKey1()
+			prevPolicy = e1
 		case msg.ChannelFlags&lnwire.ChanUpdateDirection == 1:
 			pubKey, _ = chanInfo.NodeKey2()
+			prevPolicy = e2
 		}
 
 		// Validate the channel announcement with the expected public key and
@@ -1973,9 +2132,11 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 			ExtraOpaqueData:           msg.ExtraOpaqueData,
 		}
 
-		if err := d.cfg.Router.UpdateEdge(update); err != nil {
+		err = d.cfg.Router.UpdateEdge(update, schedulerOp...)
+		if err != nil {
 			if routing.IsError(err, routing.ErrOutdated,
 				routing.ErrIgnored) {
+				d.recordUpdateResult(nMsg, false)
 				log.Debug(err)
 			} else {
 				d.rejectMtx.Lock()
@@ -1987,6 +2148,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 			nMsg.err <- err
 			return nil
 		}
+		d.recordUpdateResult(nMsg, true)
 
 		// If this is a local ChannelUpdate without an AuthProof, it
 		// means it is an update to a channel that is not (yet)
@@ -2014,6 +2176,21 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 			}
 		}
 
+		// Remote updates that merely refresh the timestamp of a
+		// channel's policy are only relayed once per
+		// KeepAliveUpdateInterval in order to reduce our outbound
+		// gossip bandwidth.
+		if nMsg.isRemote && !d.keepAliveThrottle.shouldBroadcast(
+			prevPolicy, msg, time.Now(),
+		) {
+
+			log.Debugf("Suppressing rebroadcast of keep-alive "+
+				"ChannelUpdate for short_chan_id=%v", shortChanID)
+
+			nMsg.err <- nil
+			return nil
+		}
+
 		// Channel update announcement was successfully processed and
 		// now it can be broadcast to the rest of the network. However,
 		// we'll only broadcast the channel update announcement if it
@@ -2544,6 +2721,18 @@ func (d *AuthenticatedGossiper) updateChannel(info *channeldb.ChannelEdgeInfo,
 	return chanAnn, chanUpdate, err
 }
 
+// recordUpdateResult accounts for the outcome of processing an announcement
+// within the stats of the GossipSyncer of the peer that sent it to us.
+func (d *AuthenticatedGossiper) recordUpdateResult(nMsg *networkMsg,
+	applied bool) {
+
+	if !nMsg.isRemote || nMsg.peer == nil {
+		return
+	}
+
+	d.syncMgr.RecordUpdateResult(nMsg.peer.PubKey(), applied)
+}
+
 // SyncManager returns the gossiper's SyncManager instance.
 func (d *AuthenticatedGossiper) SyncManager() *SyncManager {
 	return d.syncMgr
@@ -21,6 +21,7 @@ import (
 	"github.com/litecoinfinance/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
+	"github.com/litecoinfinance/lnd/batch"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/lnpeer"
@@ -113,25 +114,29 @@ func (n *mockSigner) SignMessage(pubKey *btcec.PublicKey,
 type mockGraphSource struct {
 	bestHeight uint32
 
-	mu      sync.Mutex
-	nodes   []channeldb.LightningNode
-	infos   map[uint64]channeldb.ChannelEdgeInfo
-	edges   map[uint64][]channeldb.ChannelEdgePolicy
-	zombies map[uint64][][33]byte
+	mu          sync.Mutex
+	nodes       []channeldb.LightningNode
+	infos       map[uint64]channeldb.ChannelEdgeInfo
+	edges       map[uint64][]channeldb.ChannelEdgePolicy
+	zombies     map[uint64][][33]byte
+	zombieInfos map[uint64]channeldb.ChannelEdgeInfo
 }
 
 func newMockRouter(height uint32) *mockGraphSource {
 	return &mockGraphSource{
-		bestHeight: height,
-		infos:      make(map[uint64]channeldb.ChannelEdgeInfo),
-		edges:      make(map[uint64][]channeldb.ChannelEdgePolicy),
-		zombies:    make(map[uint64][][33]byte),
+		bestHeight:  height,
+		infos:       make(map[uint64]channeldb.ChannelEdgeInfo),
+		edges:       make(map[uint64][]channeldb.ChannelEdgePolicy),
+		zombies:     make(map[uint64][][33]byte),
+		zombieInfos: make(map[uint64]channeldb.ChannelEdgeInfo),
 	}
 }
 
 var _ routing.ChannelGraphSource = (*mockGraphSource)(nil)
 
-func (r *mockGraphSource) AddNode(node *channeldb.LightningNode) error {
+func (r *mockGraphSource) AddNode(node *channeldb.LightningNode,
+	_ ...batch.SchedulerOption) error {
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -139,7 +144,9 @@ func (r *mockGraphSource) AddNode(node *channeldb.LightningNode) error {
 	return nil
 }
 
-func (r *mockGraphSource) AddEdge(info *channeldb.ChannelEdgeInfo) error {
+func (r *mockGraphSource) AddEdge(info *channeldb.ChannelEdgeInfo,
+	_ ...batch.SchedulerOption) error {
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -151,7 +158,9 @@ func (r *mockGraphSource) AddEdge(info *channeldb.ChannelEdgeInfo) error {
 	return nil
 }
 
-func (r *mockGraphSource) UpdateEdge(edge *channeldb.ChannelEdgePolicy) error {
+func (r *mockGraphSource) UpdateEdge(edge *channeldb.ChannelEdgePolicy,
+	_ ...batch.SchedulerOption) error {
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -389,6 +398,44 @@ func (r *mockGraphSource) MarkEdgeLive(chanID lnwire.ShortChannelID) error {
 	return nil
 }
 
+// ResurrectEdge clears an edge from our zombie index, and adds it back to the
+// graph if its info was retained when it was pruned.
+//
+// NOTE: This method is part of the ChannelGraphSource interface.
+func (r *mockGraphSource) ResurrectEdge(chanID lnwire.ShortChannelID) (bool,
+	error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.zombies, chanID.ToUint64())
+
+	info, ok := r.zombieInfos[chanID.ToUint64()]
+	if !ok {
+		return false, nil
+	}
+	delete(r.zombieInfos, chanID.ToUint64())
+	r.infos[chanID.ToUint64()] = info
+
+	return true, nil
+}
+
+// pruneZombieEdge removes an edge from the graph and marks it as a zombie,
+// retaining its info like the router does for pruned edges.
+func (r *mockGraphSource) pruneZombieEdge(chanID lnwire.ShortChannelID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info := r.infos[chanID.ToUint64()]
+	delete(r.infos, chanID.ToUint64())
+	delete(r.edges, chanID.ToUint64())
+
+	r.zombies[chanID.ToUint64()] = [][33]byte{
+		info.NodeKey1Bytes, info.NodeKey2Bytes,
+	}
+	r.zombieInfos[chanID.ToUint64()] = info
+}
+
 // MarkEdgeZombie marks an edge as a zombie within our zombie index.
 func (r *mockGraphSource) MarkEdgeZombie(chanID lnwire.ShortChannelID, pubKey1,
 	pubKey2 [33]byte) error {
@@ -750,8 +797,11 @@ func createTestCtx(startHeight uint32) (*testCtx, func(), error) {
 		MessageStore:         newMockMessageStore(),
 		RotateTicker:         ticker.NewForce(DefaultSyncerRotationInterval),
 		HistoricalSyncTicker: ticker.NewForce(DefaultHistoricalSyncInterval),
-		NumActiveSyncers:     3,
-		AnnSigner:            &mockSigner{nodeKeyPriv1},
+		SyncerHealthCheckTicker: ticker.NewForce(
+			DefaultSyncerHealthCheckInterval,
+		),
+		NumActiveSyncers: 3,
+		AnnSigner:        &mockSigner{nodeKeyPriv1},
 	}, nodeKeyPub1)
 
 	if err := gossiper.Start(); err != nil {
@@ -1492,7 +1542,10 @@ func TestSignatureAnnouncementRetryAtStartup(t *testing.T) {
 		MessageStore:         ctx.gossiper.cfg.MessageStore,
 		RotateTicker:         ticker.NewForce(DefaultSyncerRotationInterval),
 		HistoricalSyncTicker: ticker.NewForce(DefaultHistoricalSyncInterval),
-		NumActiveSyncers:     3,
+		SyncerHealthCheckTicker: ticker.NewForce(
+			DefaultSyncerHealthCheckInterval,
+		),
+		NumActiveSyncers: 3,
 	}, ctx.gossiper.selfKey)
 	if err != nil {
 		t.Fatalf("unable to recreate gossiper: %v", err)
@@ -2405,6 +2458,75 @@ func TestProcessZombieEdgeNowLive(t *testing.T) {
 	}
 }
 
+// TestProcessZombieEdgeRestored ensures that a fresh channel update for a
+// channel that was pruned as a zombie is applied right away, as the channel is
+// restored from the info retained in the zombie index.
+func TestProcessZombieEdgeRestored(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanup, err := createTestCtx(0)
+	if err != nil {
+		t.Fatalf("unable to create test context: %v", err)
+	}
+	defer cleanup()
+
+	batch, err := createAnnouncements(0)
+	if err != nil {
+		t.Fatalf("unable to create announcements: %v", err)
+	}
+
+	remotePrivKey := nodeKeyPriv2
+	remotePeer := &mockPeer{pk: remotePrivKey.PubKey()}
+
+	// processAnnouncement is a helper closure that processes the
+	// announcement and ensures it's broadcast.
+	processAnnouncement := func(ann lnwire.Message) {
+		t.Helper()
+
+		select {
+		case err := <-ctx.gossiper.ProcessRemoteAnnouncement(
+			ann, remotePeer,
+		):
+			if err != nil {
+				t.Fatalf("unable to process announcement: %v",
+					err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected to process announcement")
+		}
+
+		select {
+		case msgWithSenders := <-ctx.broadcastedMessage:
+			assertMessage(t, ann, msgWithSenders.msg)
+		case <-time.After(2 * trickleDelay):
+			t.Fatal("expected to broadcast announcement")
+		}
+	}
+
+	// We'll first add the channel to the graph, and then prune it as a
+	// zombie.
+	processAnnouncement(batch.remoteChanAnn)
+
+	chanID := batch.remoteChanAnn.ShortChannelID
+	ctx.router.pruneZombieEdge(chanID)
+
+	// A fresh update signed by the remote node should resurrect the
+	// channel, without having to wait for its announcement.
+	batch.chanUpdAnn2.Timestamp = uint32(time.Now().Unix())
+	if err := signUpdate(remotePrivKey, batch.chanUpdAnn2); err != nil {
+		t.Fatalf("unable to sign update with new timestamp: %v", err)
+	}
+	processAnnouncement(batch.chanUpdAnn2)
+
+	_, _, e2, err := ctx.router.GetChannelByID(chanID)
+	if err != nil {
+		t.Fatalf("unable to fetch restored channel: %v", err)
+	}
+	if e2 == nil {
+		t.Fatal("expected update to be applied to restored channel")
+	}
+}
+
 // TestReceiveRemoteChannelUpdateFirst tests that if we receive a ChannelUpdate
 // from the remote before we have processed our own ChannelAnnouncement, it will
 // be reprocessed later, after our ChannelAnnouncement.
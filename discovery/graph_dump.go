@@ -0,0 +1,443 @@
+package discovery
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/litecoinfinance/lnd/batch"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing"
+	"github.com/litecoinfinance/lnd/routing/route"
+)
+
+// numGraphImportWorkers is the number of messages of a graph dump that are
+// validated and added to the graph concurrently. Validating a channel
+// announcement requires its funding output to be fetched from the chain, so
+// processing the messages one by one would make large imports take hours.
+const numGraphImportWorkers = 16
+
+// errAlreadyKnown is returned internally when a message of a graph dump
+// doesn't contain any information we don't already have.
+var errAlreadyKnown = errors.New("already known")
+
+// GraphDump is a dump of the public channel graph, as obtained from another
+// node, e.g. a well-connected archive node. Each entry is a complete gossip
+// message in its wire encoding, including the leading message type.
+type GraphDump struct {
+	// ChannelAnnouncements are the announcements of the channels within
+	// the graph.
+	ChannelAnnouncements [][]byte
+
+	// ChannelUpdates are the latest updates of the channels within the
+	// graph.
+	ChannelUpdates [][]byte
+
+	// NodeAnnouncements are the announcements of the nodes within the
+	// graph.
+	NodeAnnouncements [][]byte
+}
+
+// GraphImportResult summarizes the outcome of importing a graph dump.
+type GraphImportResult struct {
+	// NumChannels is the number of channels added to the graph.
+	NumChannels uint32
+
+	// NumUpdates is the number of channel updates applied to the graph.
+	NumUpdates uint32
+
+	// NumNodes is the number of node announcements applied to the graph.
+	NumNodes uint32
+
+	// NumKnown is the number of messages skipped as the graph already
+	// held the same or more recent information.
+	NumKnown uint32
+
+	// NumRejected is the number of messages that were malformed, failed
+	// validation, or referred to a channel not within the graph.
+	NumRejected uint32
+}
+
+// ExportGraphDump returns a dump of our view of the public channel graph. The
+// dump contains every advertised channel along with its latest updates, and
+// the announcements of all nodes that take part in them.
+func (d *AuthenticatedGossiper) ExportGraphDump() (*GraphDump, error) {
+	var (
+		dump GraphDump
+
+		// publicNodes tracks the set of nodes that have at least one
+		// advertised channel, as only their announcements should be
+		// included within the dump.
+		publicNodes = make(map[route.Vertex]struct{})
+	)
+
+	encode := func(msg lnwire.Message) ([]byte, error) {
+		var b bytes.Buffer
+		if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	}
+
+	err := d.cfg.Router.ForEachChannel(func(info *channeldb.ChannelEdgeInfo,
+		e1, e2 *channeldb.ChannelEdgePolicy) error {
+
+		// Channels without an authentication proof can't be announced.
+		if info.AuthProof == nil {
+			return nil
+		}
+
+		chanAnn, edge1, edge2, err := CreateChanAnnouncement(
+			info.AuthProof, info, e1, e2,
+		)
+		if err != nil {
+			return err
+		}
+
+		b, err := encode(chanAnn)
+		if err != nil {
+			return err
+		}
+		dump.ChannelAnnouncements = append(dump.ChannelAnnouncements, b)
+
+		for _, upd := range []*lnwire.ChannelUpdate{edge1, edge2} {
+			if upd == nil {
+				continue
+			}
+
+			b, err := encode(upd)
+			if err != nil {
+				return err
+			}
+			dump.ChannelUpdates = append(dump.ChannelUpdates, b)
+		}
+
+		publicNodes[info.NodeKey1Bytes] = struct{}{}
+		publicNodes[info.NodeKey2Bytes] = struct{}{}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.cfg.Router.ForEachNode(func(
+		node *channeldb.LightningNode) error {
+
+		if !node.HaveNodeAnnouncement {
+			return nil
+		}
+		if _, ok := publicNodes[node.PubKeyBytes]; !ok {
+			return nil
+		}
+
+		nodeAnn, err := node.NodeAnnouncement(true)
+		if err != nil {
+			return err
+		}
+
+		b, err := encode(nodeAnn)
+		if err != nil {
+			return err
+		}
+		dump.NodeAnnouncements = append(dump.NodeAnnouncements, b)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dump, nil
+}
+
+// ImportGraphDump validates the messages of the given graph dump and adds
+// them to the channel graph. Unlike ImportGraphSnapshot, the messages are
+// handed directly to the router rather than queued for processing, so the
+// outcome of each message is known once the import returns. The channel
+// announcements are imported first, such that the channel updates and node
+// announcements that follow can be validated against the channels they
+// refer to. As the dump may originate from an untrusted source, the signatures
+// of every message are verified, and each channel is checked against the
+// chain before being added. Invalid messages are skipped, and only cause the
+// import to fail if the gossiper or router are shutting down.
+func (d *AuthenticatedGossiper) ImportGraphDump(
+	dump *GraphDump) (*GraphImportResult, error) {
+
+	log.Infof("Importing graph dump with %d channel announcements, %d "+
+		"channel updates and %d node announcements",
+		len(dump.ChannelAnnouncements), len(dump.ChannelUpdates),
+		len(dump.NodeAnnouncements))
+
+	var result GraphImportResult
+	err := d.importGraphMsgs(
+		dump.ChannelAnnouncements, d.importChannelAnn,
+		&result.NumChannels, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.importGraphMsgs(
+		dump.ChannelUpdates, d.importChannelUpdate,
+		&result.NumUpdates, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.importGraphMsgs(
+		dump.NodeAnnouncements, d.importNodeAnn,
+		&result.NumNodes, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Imported graph dump: channels=%v, updates=%v, nodes=%v, "+
+		"known=%v, rejected=%v", result.NumChannels, result.NumUpdates,
+		result.NumNodes, result.NumKnown, result.NumRejected)
+
+	// With the channels added, we no longer need to bootstrap the graph
+	// through a historical sync with our gossip peers.
+	if result.NumChannels > 0 {
+		d.syncMgr.MarkGraphBootstrapped()
+	}
+
+	return &result, nil
+}
+
+// importGraphMsgs imports the given serialized messages using importMsg,
+// spread over numGraphImportWorkers goroutines. Successful imports are tallied
+// in numImported, and all other outcomes within the passed result.
+func (d *AuthenticatedGossiper) importGraphMsgs(msgs [][]byte,
+	importMsg func([]byte) error, numImported *uint32,
+	result *GraphImportResult) error {
+
+	var (
+		mu      sync.Mutex
+		fatal   error
+		wg      sync.WaitGroup
+		msgChan = make(chan []byte)
+	)
+
+	for i := 0; i < numGraphImportWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for msg := range msgChan {
+				err := importMsg(msg)
+				isKnown := err == errAlreadyKnown ||
+					routing.IsError(
+						err, routing.ErrOutdated,
+						routing.ErrIgnored,
+					)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					*numImported++
+
+				case isKnown:
+					result.NumKnown++
+
+				case err == routing.ErrRouterShuttingDown:
+					fatal = err
+
+				default:
+					log.Debugf("Rejected message of graph "+
+						"dump: %v", err)
+					result.NumRejected++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, msg := range msgs {
+		select {
+		case msgChan <- msg:
+		case <-d.quit:
+			close(msgChan)
+			wg.Wait()
+			return ErrGossiperShuttingDown
+		}
+	}
+	close(msgChan)
+	wg.Wait()
+
+	return fatal
+}
+
+// decodeGraphMsg decodes a serialized message of a graph dump, and ensures
+// it's of the expected type.
+func decodeGraphMsg(b []byte, msgType lnwire.MessageType) (lnwire.Message,
+	error) {
+
+	msg, err := lnwire.ReadMessage(bytes.NewReader(b), 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %v: %v", msgType, err)
+	}
+	if msg.MsgType() != msgType {
+		return nil, fmt.Errorf("expected %v, got %v", msgType,
+			msg.MsgType())
+	}
+
+	return msg, nil
+}
+
+// importChannelAnn validates a serialized channel announcement and adds the
+// channel to the graph.
+func (d *AuthenticatedGossiper) importChannelAnn(b []byte) error {
+	msg, err := decodeGraphMsg(b, lnwire.MsgChannelAnnouncement)
+	if err != nil {
+		return err
+	}
+	ann := msg.(*lnwire.ChannelAnnouncement)
+
+	if !bytes.Equal(ann.ChainHash[:], d.cfg.ChainHash[:]) {
+		return fmt.Errorf("channel %v is for chain %v",
+			ann.ShortChannelID, ann.ChainHash)
+	}
+
+	if d.cfg.Router.IsKnownEdge(ann.ShortChannelID) {
+		return errAlreadyKnown
+	}
+
+	if err := routing.ValidateChannelAnn(ann); err != nil {
+		return fmt.Errorf("unable to validate announcement of channel "+
+			"%v: %v", ann.ShortChannelID, err)
+	}
+
+	var featureBuf bytes.Buffer
+	if err := ann.Features.Encode(&featureBuf); err != nil {
+		return err
+	}
+
+	edge := &channeldb.ChannelEdgeInfo{
+		ChannelID:        ann.ShortChannelID.ToUint64(),
+		ChainHash:        ann.ChainHash,
+		NodeKey1Bytes:    ann.NodeID1,
+		NodeKey2Bytes:    ann.NodeID2,
+		BitcoinKey1Bytes: ann.BitcoinKey1,
+		BitcoinKey2Bytes: ann.BitcoinKey2,
+		AuthProof: &channeldb.ChannelAuthProof{
+			NodeSig1Bytes:    ann.NodeSig1.ToSignatureBytes(),
+			NodeSig2Bytes:    ann.NodeSig2.ToSignatureBytes(),
+			BitcoinSig1Bytes: ann.BitcoinSig1.ToSignatureBytes(),
+			BitcoinSig2Bytes: ann.BitcoinSig2.ToSignatureBytes(),
+		},
+		Features:        featureBuf.Bytes(),
+		ExtraOpaqueData: ann.ExtraOpaqueData,
+	}
+
+	// The router checks the funding output of the channel on chain, and
+	// fills in its capacity and channel point.
+	d.channelMtx.Lock(edge.ChannelID)
+	defer d.channelMtx.Unlock(edge.ChannelID)
+
+	return d.cfg.Router.AddEdge(edge, batch.LazyAdd())
+}
+
+// importChannelUpdate validates a serialized channel update against the
+// channel it refers to, and applies it to the graph.
+func (d *AuthenticatedGossiper) importChannelUpdate(b []byte) error {
+	msg, err := decodeGraphMsg(b, lnwire.MsgChannelUpdate)
+	if err != nil {
+		return err
+	}
+	upd := msg.(*lnwire.ChannelUpdate)
+
+	if !bytes.Equal(upd.ChainHash[:], d.cfg.ChainHash[:]) {
+		return fmt.Errorf("update of channel %v is for chain %v",
+			upd.ShortChannelID, upd.ChainHash)
+	}
+
+	timestamp := time.Unix(int64(upd.Timestamp), 0)
+	if d.cfg.Router.IsStaleEdgePolicy(
+		upd.ShortChannelID, timestamp, upd.ChannelFlags,
+	) {
+		return errAlreadyKnown
+	}
+
+	shortChanID := upd.ShortChannelID.ToUint64()
+	d.channelMtx.Lock(shortChanID)
+	defer d.channelMtx.Unlock(shortChanID)
+
+	chanInfo, _, _, err := d.cfg.Router.GetChannelByID(upd.ShortChannelID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch channel %v: %v",
+			upd.ShortChannelID, err)
+	}
+
+	nodeKey := chanInfo.NodeKey1
+	if upd.ChannelFlags&lnwire.ChanUpdateDirection == 1 {
+		nodeKey = chanInfo.NodeKey2
+	}
+	pubKey, err := nodeKey()
+	if err != nil {
+		return err
+	}
+
+	err = routing.ValidateChannelUpdateAnn(pubKey, chanInfo.Capacity, upd)
+	if err != nil {
+		return fmt.Errorf("unable to validate update of channel %v: %v",
+			upd.ShortChannelID, err)
+	}
+
+	policy := &channeldb.ChannelEdgePolicy{
+		SigBytes:                  upd.Signature.ToSignatureBytes(),
+		ChannelID:                 shortChanID,
+		LastUpdate:                timestamp,
+		MessageFlags:              upd.MessageFlags,
+		ChannelFlags:              upd.ChannelFlags,
+		TimeLockDelta:             upd.TimeLockDelta,
+		MinHTLC:                   upd.HtlcMinimumMsat,
+		MaxHTLC:                   upd.HtlcMaximumMsat,
+		FeeBaseMSat:               lnwire.MilliSatoshi(upd.BaseFee),
+		FeeProportionalMillionths: lnwire.MilliSatoshi(upd.FeeRate),
+		ExtraOpaqueData:           upd.ExtraOpaqueData,
+	}
+
+	return d.cfg.Router.UpdateEdge(policy, batch.LazyAdd())
+}
+
+// importNodeAnn validates a serialized node announcement and applies it to
+// the graph. The router ignores announcements of nodes without any channels.
+func (d *AuthenticatedGossiper) importNodeAnn(b []byte) error {
+	msg, err := decodeGraphMsg(b, lnwire.MsgNodeAnnouncement)
+	if err != nil {
+		return err
+	}
+	ann := msg.(*lnwire.NodeAnnouncement)
+
+	timestamp := time.Unix(int64(ann.Timestamp), 0)
+	if d.cfg.Router.IsStaleNode(ann.NodeID, timestamp) {
+		return errAlreadyKnown
+	}
+
+	if err := routing.ValidateNodeAnn(ann); err != nil {
+		return fmt.Errorf("unable to validate announcement of node "+
+			"%x: %v", ann.NodeID[:], err)
+	}
+
+	node := &channeldb.LightningNode{
+		HaveNodeAnnouncement: true,
+		LastUpdate:           timestamp,
+		Addresses:            ann.Addresses,
+		PubKeyBytes:          ann.NodeID,
+		Alias:                ann.Alias.String(),
+		AuthSigBytes:         ann.Signature.ToSignatureBytes(),
+		Features: lnwire.NewFeatureVector(
+			ann.Features, lnwire.GlobalFeatures,
+		),
+		Color:           ann.RGBColor,
+		ExtraOpaqueData: ann.ExtraOpaqueData,
+	}
+
+	return d.cfg.Router.AddNode(node, batch.LazyAdd())
+}
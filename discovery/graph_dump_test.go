@@ -0,0 +1,112 @@
+package discovery
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestImportGraphDump ensures that the valid messages of a graph dump are
+// added to the graph, while invalid ones and those already known are skipped
+// and reported as such.
+func TestImportGraphDump(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanup, err := createTestCtx(proofMatureDelta)
+	if err != nil {
+		t.Fatalf("can't create context: %v", err)
+	}
+	defer cleanup()
+
+	batch, err := createAnnouncements(0)
+	if err != nil {
+		t.Fatalf("can't generate announcements: %v", err)
+	}
+
+	encode := func(msg lnwire.Message) []byte {
+		var b bytes.Buffer
+		if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+			t.Fatalf("unable to encode %v: %v", msg.MsgType(), err)
+		}
+		return b.Bytes()
+	}
+
+	dump := &GraphDump{
+		ChannelAnnouncements: [][]byte{encode(batch.remoteChanAnn)},
+		ChannelUpdates: [][]byte{
+			encode(batch.chanUpdAnn1), encode(batch.chanUpdAnn2),
+		},
+		NodeAnnouncements: [][]byte{
+			encode(batch.nodeAnn1), encode(batch.nodeAnn2),
+		},
+	}
+
+	// We'll also include an update that was tampered with after being
+	// signed, an update of a channel that isn't part of the dump, and a
+	// message that can't be decoded at all.
+	tampered := *batch.chanUpdAnn1
+	tampered.Timestamp++
+
+	unknownChan, err := createUpdateAnnouncement(1, 0, nodeKeyPriv1, 123456)
+	if err != nil {
+		t.Fatalf("can't create update: %v", err)
+	}
+
+	invalidDump := &GraphDump{
+		ChannelAnnouncements: append(
+			dump.ChannelAnnouncements, []byte{0x01},
+		),
+		ChannelUpdates: append(
+			dump.ChannelUpdates, encode(&tampered),
+			encode(unknownChan),
+		),
+		NodeAnnouncements: dump.NodeAnnouncements,
+	}
+
+	result, err := ctx.gossiper.ImportGraphDump(invalidDump)
+	if err != nil {
+		t.Fatalf("unable to import graph dump: %v", err)
+	}
+
+	expected := GraphImportResult{
+		NumChannels: 1,
+		NumUpdates:  2,
+		NumNodes:    2,
+		NumRejected: 3,
+	}
+	if *result != expected {
+		t.Fatalf("expected result %+v, got %+v", expected, *result)
+	}
+
+	if len(ctx.router.infos) != 1 {
+		t.Fatalf("expected 1 channel in graph, got %d",
+			len(ctx.router.infos))
+	}
+	chanID := batch.remoteChanAnn.ShortChannelID.ToUint64()
+	if ctx.router.infos[chanID].AuthProof == nil {
+		t.Fatalf("expected channel to be added with its proof")
+	}
+	if len(ctx.router.edges[chanID]) != 2 {
+		t.Fatalf("expected 2 policies in graph, got %d",
+			len(ctx.router.edges[chanID]))
+	}
+	if len(ctx.router.nodes) != 2 {
+		t.Fatalf("expected 2 nodes in graph, got %d",
+			len(ctx.router.nodes))
+	}
+
+	// Importing the valid part of the dump once more shouldn't change
+	// anything, as each of its messages is already known.
+	result, err = ctx.gossiper.ImportGraphDump(dump)
+	if err != nil {
+		t.Fatalf("unable to import graph dump: %v", err)
+	}
+
+	expected = GraphImportResult{
+		NumKnown: 5,
+	}
+	if *result != expected {
+		t.Fatalf("expected result %+v, got %+v", expected, *result)
+	}
+}
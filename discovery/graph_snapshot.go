@@ -0,0 +1,143 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+const (
+	// graphSnapshotVersion is the current version of the graph snapshot
+	// encoding. It's bumped whenever the format below changes in a way
+	// that older nodes wouldn't be able to understand.
+	graphSnapshotVersion uint8 = 0
+)
+
+var (
+	// graphSnapshotMagic is the set of bytes every graph snapshot begins
+	// with, allowing us to quickly reject arbitrary input.
+	graphSnapshotMagic = [4]byte{'l', 'n', 'g', 's'}
+
+	// byteOrder is the byte order used for all integers within a graph
+	// snapshot.
+	byteOrder = binary.BigEndian
+)
+
+var (
+	// ErrInvalidGraphSnapshot is returned when we attempt to decode a
+	// graph snapshot that is malformed, or of an unknown version.
+	ErrInvalidGraphSnapshot = errors.New("invalid graph snapshot")
+
+	// ErrGraphSnapshotChainMismatch is returned when we attempt to import
+	// a graph snapshot that was created for a different chain than the
+	// one we're currently operating on.
+	ErrGraphSnapshotChainMismatch = errors.New("graph snapshot is for a " +
+		"different chain")
+)
+
+// writeGraphSnapshot serializes the set of gossip messages into the compact
+// binary graph snapshot format. A snapshot consists of a small header (magic,
+// version, chain hash and the number of messages), followed by each message
+// in its wire encoding, prefixed by its length.
+func writeGraphSnapshot(w io.Writer, chain chainhash.Hash,
+	msgs []lnwire.Message) error {
+
+	var header [4 + 1 + chainhash.HashSize + 4]byte
+	copy(header[:4], graphSnapshotMagic[:])
+	header[4] = graphSnapshotVersion
+	copy(header[5:5+chainhash.HashSize], chain[:])
+	byteOrder.PutUint32(header[5+chainhash.HashSize:], uint32(len(msgs)))
+
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+
+	var (
+		b      bytes.Buffer
+		msgLen [2]byte
+	)
+	for _, msg := range msgs {
+		b.Reset()
+		if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+			return err
+		}
+
+		byteOrder.PutUint16(msgLen[:], uint16(b.Len()))
+		if _, err := w.Write(msgLen[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(b.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readGraphSnapshot decodes a graph snapshot created by writeGraphSnapshot.
+// The snapshot must have been created for the given chain. Only the messages
+// that make up the public channel graph (channel announcements, channel
+// updates and node announcements) are accepted.
+func readGraphSnapshot(r io.Reader, chain chainhash.Hash) ([]lnwire.Message,
+	error) {
+
+	var header [4 + 1 + chainhash.HashSize + 4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, ErrInvalidGraphSnapshot
+	}
+
+	if !bytes.Equal(header[:4], graphSnapshotMagic[:]) {
+		return nil, ErrInvalidGraphSnapshot
+	}
+	if header[4] != graphSnapshotVersion {
+		return nil, fmt.Errorf("%v: unknown version %d",
+			ErrInvalidGraphSnapshot, header[4])
+	}
+	if !bytes.Equal(header[5:5+chainhash.HashSize], chain[:]) {
+		return nil, ErrGraphSnapshotChainMismatch
+	}
+
+	numMsgs := byteOrder.Uint32(header[5+chainhash.HashSize:])
+
+	// We won't trust the number of messages for our allocation, as it's
+	// possible for the snapshot to be truncated.
+	var (
+		msgs   []lnwire.Message
+		msgLen [2]byte
+	)
+	for i := uint32(0); i < numMsgs; i++ {
+		if _, err := io.ReadFull(r, msgLen[:]); err != nil {
+			return nil, ErrInvalidGraphSnapshot
+		}
+
+		msgBytes := make([]byte, byteOrder.Uint16(msgLen[:]))
+		if _, err := io.ReadFull(r, msgBytes); err != nil {
+			return nil, ErrInvalidGraphSnapshot
+		}
+
+		msg, err := lnwire.ReadMessage(bytes.NewReader(msgBytes), 0)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", ErrInvalidGraphSnapshot,
+				err)
+		}
+
+		switch msg.(type) {
+		case *lnwire.ChannelAnnouncement,
+			*lnwire.ChannelUpdate,
+			*lnwire.NodeAnnouncement:
+
+		default:
+			return nil, fmt.Errorf("%v: unexpected message %v",
+				ErrInvalidGraphSnapshot, msg.MsgType())
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
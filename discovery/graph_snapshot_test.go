@@ -0,0 +1,83 @@
+package discovery
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestGraphSnapshotRoundTrip ensures that a set of gossip messages written as
+// a graph snapshot can be read back exactly as they were.
+func TestGraphSnapshotRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	chain := chainhash.Hash{0x01}
+	msgs := []lnwire.Message{
+		&lnwire.ChannelAnnouncement{
+			ChainHash:      chain,
+			ShortChannelID: lnwire.NewShortChanIDFromInt(1),
+			Features:       lnwire.NewRawFeatureVector(),
+		},
+		&lnwire.ChannelUpdate{
+			ChainHash:      chain,
+			ShortChannelID: lnwire.NewShortChanIDFromInt(1),
+			Timestamp:      1000,
+			BaseFee:        1,
+			FeeRate:        2,
+		},
+	}
+
+	var b bytes.Buffer
+	if err := writeGraphSnapshot(&b, chain, msgs); err != nil {
+		t.Fatalf("unable to write snapshot: %v", err)
+	}
+
+	readMsgs, err := readGraphSnapshot(bytes.NewReader(b.Bytes()), chain)
+	if err != nil {
+		t.Fatalf("unable to read snapshot: %v", err)
+	}
+	if !reflect.DeepEqual(msgs, readMsgs) {
+		t.Fatalf("snapshot mismatch: expected %v, got %v",
+			spew.Sdump(msgs), spew.Sdump(readMsgs))
+	}
+
+	// Reading the same snapshot for a different chain should fail.
+	_, err = readGraphSnapshot(
+		bytes.NewReader(b.Bytes()), chainhash.Hash{0x02},
+	)
+	if err != ErrGraphSnapshotChainMismatch {
+		t.Fatalf("expected ErrGraphSnapshotChainMismatch, got %v", err)
+	}
+
+	// A truncated snapshot should also be rejected.
+	_, err = readGraphSnapshot(bytes.NewReader(b.Bytes()[:b.Len()-1]), chain)
+	if err != ErrInvalidGraphSnapshot {
+		t.Fatalf("expected ErrInvalidGraphSnapshot, got %v", err)
+	}
+}
+
+// TestGraphSnapshotInvalid ensures that we reject snapshots with an unknown
+// magic, or those containing messages outside of the channel graph.
+func TestGraphSnapshotInvalid(t *testing.T) {
+	t.Parallel()
+
+	chain := chainhash.Hash{0x01}
+
+	_, err := readGraphSnapshot(bytes.NewReader(make([]byte, 64)), chain)
+	if err != ErrInvalidGraphSnapshot {
+		t.Fatalf("expected ErrInvalidGraphSnapshot, got %v", err)
+	}
+
+	var b bytes.Buffer
+	msgs := []lnwire.Message{lnwire.NewPing(0)}
+	if err := writeGraphSnapshot(&b, chain, msgs); err != nil {
+		t.Fatalf("unable to write snapshot: %v", err)
+	}
+	if _, err := readGraphSnapshot(&b, chain); err == nil {
+		t.Fatalf("expected snapshot with ping message to be rejected")
+	}
+}
@@ -0,0 +1,103 @@
+package discovery
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// isKeepAliveUpdate returns true if the given ChannelUpdate only refreshes the
+// timestamp of the existing policy, without changing any of its fields.
+func isKeepAliveUpdate(prev *channeldb.ChannelEdgePolicy,
+	msg *lnwire.ChannelUpdate) bool {
+
+	if prev == nil {
+		return false
+	}
+
+	return prev.MessageFlags == msg.MessageFlags &&
+		prev.ChannelFlags == msg.ChannelFlags &&
+		prev.TimeLockDelta == msg.TimeLockDelta &&
+		prev.MinHTLC == msg.HtlcMinimumMsat &&
+		prev.MaxHTLC == msg.HtlcMaximumMsat &&
+		prev.FeeBaseMSat == lnwire.MilliSatoshi(msg.BaseFee) &&
+		prev.FeeProportionalMillionths == lnwire.MilliSatoshi(msg.FeeRate) &&
+		bytes.Equal(prev.ExtraOpaqueData, msg.ExtraOpaqueData)
+}
+
+// keepAliveKey uniquely identifies a single direction of a channel.
+type keepAliveKey struct {
+	chanID    uint64
+	direction lnwire.ChanUpdateChanFlags
+}
+
+// keepAliveThrottle rate limits the rebroadcast of keep-alive ChannelUpdates,
+// i.e. updates that only refresh the timestamp of a channel's policy. Such
+// updates are still applied to our graph, but they'll only be relayed to our
+// peers once per interval for each direction of a channel.
+type keepAliveThrottle struct {
+	interval time.Duration
+
+	// lastBroadcast tracks the last time we've relayed an update for each
+	// direction of a channel.
+	lastBroadcast map[keepAliveKey]time.Time
+	mu            sync.Mutex
+}
+
+// newKeepAliveThrottle creates a new keepAliveThrottle that relays keep-alive
+// updates for a channel direction at most once per interval. An interval of
+// zero disables throttling.
+func newKeepAliveThrottle(interval time.Duration) *keepAliveThrottle {
+	return &keepAliveThrottle{
+		interval:      interval,
+		lastBroadcast: make(map[keepAliveKey]time.Time),
+	}
+}
+
+// shouldBroadcast determines whether the given ChannelUpdate should be relayed
+// to our peers. Updates that change the channel's policy are always relayed,
+// while keep-alive updates are only relayed if the interval has elapsed since
+// the last relayed update for the same channel direction.
+func (t *keepAliveThrottle) shouldBroadcast(prev *channeldb.ChannelEdgePolicy,
+	msg *lnwire.ChannelUpdate, now time.Time) bool {
+
+	if t.interval == 0 {
+		return true
+	}
+
+	key := keepAliveKey{
+		chanID:    msg.ShortChannelID.ToUint64(),
+		direction: msg.ChannelFlags & lnwire.ChanUpdateDirection,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lastBroadcast, ok := t.lastBroadcast[key]
+	if ok && isKeepAliveUpdate(prev, msg) &&
+		now.Sub(lastBroadcast) < t.interval {
+
+		return false
+	}
+
+	t.lastBroadcast[key] = now
+
+	return true
+}
+
+// prune removes all channel directions for which the interval has elapsed
+// since their last relayed update, as their next update will be relayed
+// regardless.
+func (t *keepAliveThrottle) prune(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, lastBroadcast := range t.lastBroadcast {
+		if now.Sub(lastBroadcast) >= t.interval {
+			delete(t.lastBroadcast, key)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestKeepAliveThrottle ensures that keep-alive ChannelUpdates are only relayed
+// once per interval for each channel direction, while updates that change the
+// channel's policy are always relayed.
+func TestKeepAliveThrottle(t *testing.T) {
+	t.Parallel()
+
+	const interval = time.Hour
+
+	throttle := newKeepAliveThrottle(interval)
+
+	prev := &channeldb.ChannelEdgePolicy{
+		TimeLockDelta:             144,
+		MinHTLC:                   1000,
+		FeeBaseMSat:               1000,
+		FeeProportionalMillionths: 1,
+	}
+	newUpdate := func(chanFlags lnwire.ChanUpdateChanFlags,
+		feeRate uint32) *lnwire.ChannelUpdate {
+
+		return &lnwire.ChannelUpdate{
+			ShortChannelID:  lnwire.NewShortChanIDFromInt(1),
+			ChannelFlags:    chanFlags,
+			TimeLockDelta:   prev.TimeLockDelta,
+			HtlcMinimumMsat: prev.MinHTLC,
+			BaseFee:         uint32(prev.FeeBaseMSat),
+			FeeRate:         feeRate,
+		}
+	}
+
+	keepAlive := newUpdate(0, 1)
+	if !isKeepAliveUpdate(prev, keepAlive) {
+		t.Fatalf("expected update to be a keep-alive")
+	}
+
+	now := time.Now()
+	assertBroadcast := func(msg *lnwire.ChannelUpdate, elapsed time.Duration,
+		expected bool) {
+
+		t.Helper()
+
+		broadcast := throttle.shouldBroadcast(prev, msg, now.Add(elapsed))
+		if broadcast != expected {
+			t.Fatalf("expected broadcast=%v after %v, got %v",
+				expected, elapsed, broadcast)
+		}
+	}
+
+	// The first update we see for a channel direction should always be
+	// relayed, while a following keep-alive within the interval shouldn't.
+	assertBroadcast(keepAlive, 0, true)
+	assertBroadcast(keepAlive, interval/2, false)
+
+	// The other direction of the channel is tracked separately.
+	assertBroadcast(newUpdate(lnwire.ChanUpdateDirection, 1), interval/2, true)
+
+	// Updates that change the channel's policy, including disabling it,
+	// are always relayed.
+	assertBroadcast(newUpdate(0, 2), interval/2, true)
+	assertBroadcast(newUpdate(lnwire.ChanUpdateDisabled, 1), interval/2, true)
+
+	// Once the interval has elapsed since the last relayed update, a
+	// keep-alive should be relayed again.
+	assertBroadcast(keepAlive, interval, false)
+	assertBroadcast(keepAlive, interval/2+interval, true)
+
+	// Pruning should only remove channel directions for which the interval
+	// has elapsed.
+	throttle.prune(now.Add(interval/2 + interval))
+	if len(throttle.lastBroadcast) != 1 {
+		t.Fatalf("expected 1 channel direction after pruning, got %d",
+			len(throttle.lastBroadcast))
+	}
+}
@@ -9,9 +9,9 @@ import (
 	"testing"
 
 	"github.com/litecoinfinance/btcd/btcec"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 )
 
@@ -236,7 +236,7 @@ func TestMessageStoreUnsupportedMessage(t *testing.T) {
 	if _, err := lnwire.WriteMessage(&rawMsg, unsupportedMsg, 0); err != nil {
 		t.Fatalf("unable to serialize message: %v", err)
 	}
-	err = msgStore.db.Update(func(tx *bbolt.Tx) error {
+	err = msgStore.db.Update(func(tx kvdb.Tx) error {
 		messageStore := tx.Bucket(messageStoreBucket)
 		return messageStore.Put(msgKey, rawMsg.Bytes())
 	})
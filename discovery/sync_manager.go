@@ -3,6 +3,7 @@ package discovery
 import (
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
@@ -21,6 +22,25 @@ const (
 	// force a historical sync to ensure we have as much of the public
 	// network as possible.
 	DefaultHistoricalSyncInterval = time.Hour
+
+	// DefaultStaleSyncerTimeout is the default duration an active syncer
+	// may go without delivering any graph updates, while other peers
+	// still do, before it's replaced with a passive one.
+	DefaultStaleSyncerTimeout = 10 * time.Minute
+
+	// DefaultSyncerHealthCheckInterval is the default interval in which
+	// we'll check our active syncers for staleness.
+	DefaultSyncerHealthCheckInterval = time.Minute
+
+	// DefaultNoSyncersTimeout is the default duration the SyncManager may
+	// go without any gossip syncers before it requests fresh peers to be
+	// bootstrapped.
+	DefaultNoSyncersTimeout = 5 * time.Minute
+
+	// DefaultChainSyncPollInterval is the default interval in which we'll
+	// check whether the chain backend has completed its initial block
+	// download, while it hasn't.
+	DefaultChainSyncPollInterval = 30 * time.Second
 )
 
 var (
@@ -82,6 +102,59 @@ type SyncManagerCfg struct {
 	// SyncManager when it should attempt a historical sync with a gossip
 	// sync peer.
 	HistoricalSyncTicker ticker.Ticker
+
+	// HealthCheckTicker is a ticker responsible for notifying the
+	// SyncManager when it should check whether any of its active syncers
+	// have become stale.
+	HealthCheckTicker ticker.Ticker
+
+	// StaleSyncerTimeout is the duration after which an active syncer
+	// that hasn't delivered any graph updates is considered stale and
+	// replaced with a passive one. Syncers are only deemed stale if we
+	// have received graph updates from any other peer within this window,
+	// as otherwise the network itself is likely just quiet. A zero value
+	// disables the check.
+	StaleSyncerTimeout time.Duration
+
+	// NoSyncersTimeout is the duration the SyncManager may go without any
+	// gossip syncers, e.g. after all of our peers have disconnected,
+	// before it invokes RequestBootstrap. A zero value disables the
+	// fallback.
+	NoSyncersTimeout time.Duration
+
+	// RequestBootstrap is called once we've had no gossip syncers for
+	// longer than NoSyncersTimeout, and again for every subsequent period
+	// we remain without any. It should prompt the network bootstrapper to
+	// dial fresh peers, and must not block.
+	RequestBootstrap func()
+
+	// IsChainSynced returns whether the chain backend has completed its
+	// initial block download. Until it has, all GossipSyncers are kept
+	// passive and historical syncs are deferred, such that we don't
+	// compete with the chain backend for bandwidth, nor validate gossip
+	// against an incomplete chain. If nil, the chain is assumed to be
+	// synced.
+	IsChainSynced func() (bool, error)
+
+	// ChainSyncTicker is a ticker responsible for notifying the
+	// SyncManager when it should check whether the chain backend has
+	// completed its initial block download. It's only used while it
+	// hasn't.
+	ChainSyncTicker ticker.Ticker
+
+	// GossipHorizon is the initial gossip horizon of the SyncManager. It
+	// determines how far back in time the graph updates we request from
+	// our active syncers should reach. A zero value signals that we're
+	// only interested in new graph updates, while a value reaching back
+	// beyond the unix epoch requests the full history. It can be changed
+	// at runtime through SetGossipHorizon.
+	GossipHorizon time.Duration
+
+	// RequestBatchSize is the initial maximum number of channels our
+	// syncers will query their remote peer for in a single
+	// QueryShortChanIDs message. If zero, DefaultRequestBatchSize is used.
+	// It can be changed at runtime through SetRequestBatchSize.
+	RequestBatchSize int32
 }
 
 // SyncManager is a subsystem of the gossiper that manages the gossip syncers
@@ -94,6 +167,25 @@ type SyncManagerCfg struct {
 // attempt a historical sync to ensure we have as much of the public channel
 // graph as possible.
 type SyncManager struct {
+	// lastGraphUpdate is the unix timestamp in nanoseconds of the last
+	// graph update received from any peer.
+	//
+	// NOTE: This variable MUST be used atomically.
+	lastGraphUpdate int64
+
+	// gossipHorizon is the currently configured gossip horizon, stored as
+	// a time.Duration.
+	//
+	// NOTE: This variable MUST be used atomically.
+	gossipHorizon int64
+
+	// requestBatchSize is the currently configured maximum number of
+	// channels our syncers will query in a single QueryShortChanIDs
+	// message.
+	//
+	// NOTE: This variable MUST be used atomically.
+	requestBatchSize int32
+
 	start sync.Once
 	stop  sync.Once
 
@@ -111,6 +203,11 @@ type SyncManager struct {
 	// GossipSyncers for disconnected peers.
 	staleSyncers chan *staleSyncer
 
+	// graphBootstrapped is a channel we'll use to signal that our view of
+	// the channel graph has been bootstrapped through other means, such as
+	// a graph snapshot, so the initial historical sync can be skipped.
+	graphBootstrapped chan struct{}
+
 	// syncersMu guards the read and write access to the activeSyncers and
 	// inactiveSyncers maps below.
 	syncersMu sync.Mutex
@@ -124,16 +221,40 @@ type SyncManager struct {
 	// currently receiving new graph updates from.
 	inactiveSyncers map[route.Vertex]*GossipSyncer
 
+	// activeSyncPaused indicates whether active syncing has been paused,
+	// in which case all GossipSyncers are kept passive.
+	activeSyncPaused bool
+
+	// activeSyncResumed is a channel we'll use to signal the
+	// syncerHandler that active syncing has been resumed, so that it can
+	// fill the set of active syncers once again.
+	activeSyncResumed chan struct{}
+
+	// noSyncersSince is the time at which we first noticed we had no
+	// gossip syncers. It's zero if we currently have any.
+	//
+	// NOTE: This must only be accessed by the syncerHandler.
+	noSyncersSince time.Time
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
 
 // newSyncManager constructs a new SyncManager backed by the given config.
 func newSyncManager(cfg *SyncManagerCfg) *SyncManager {
+	requestBatchSize := cfg.RequestBatchSize
+	if requestBatchSize <= 0 {
+		requestBatchSize = DefaultRequestBatchSize
+	}
+
 	return &SyncManager{
-		cfg:          *cfg,
-		newSyncers:   make(chan *newSyncer),
-		staleSyncers: make(chan *staleSyncer),
+		gossipHorizon:     int64(cfg.GossipHorizon),
+		requestBatchSize:  requestBatchSize,
+		cfg:               *cfg,
+		newSyncers:        make(chan *newSyncer),
+		staleSyncers:      make(chan *staleSyncer),
+		graphBootstrapped: make(chan struct{}),
+		activeSyncResumed: make(chan struct{}, 1),
 		activeSyncers: make(
 			map[route.Vertex]*GossipSyncer, cfg.NumActiveSyncers,
 		),
@@ -175,6 +296,14 @@ func (m *SyncManager) Stop() {
 // 3. Finding new peers to force a historical sync with to ensure we have as
 //    much of the public network as possible.
 //
+// 4. Replacing active GossipSyncers that have stopped delivering graph updates.
+//
+// 5. Requesting fresh peers to be bootstrapped if we've been left without any
+//    GossipSyncers for too long.
+//
+// 6. Deferring active GossipSyncers and historical syncs until the chain
+//    backend has completed its initial block download.
+//
 // NOTE: This must be run as a goroutine.
 func (m *SyncManager) syncerHandler() {
 	defer m.wg.Done()
@@ -185,6 +314,9 @@ func (m *SyncManager) syncerHandler() {
 	m.cfg.HistoricalSyncTicker.Resume()
 	defer m.cfg.HistoricalSyncTicker.Stop()
 
+	m.cfg.HealthCheckTicker.Resume()
+	defer m.cfg.HealthCheckTicker.Stop()
+
 	var (
 		// attemptInitialHistoricalSync determines whether we should
 		// attempt an initial historical sync when a new peer connects.
@@ -209,8 +341,28 @@ func (m *SyncManager) syncerHandler() {
 		// attempted just because the initialHistoricalSyncer was
 		// disconnected.
 		initialHistoricalSyncSignal chan struct{}
+
+		// chainSynced determines whether the chain backend has
+		// completed its initial block download. Until it has, any
+		// transitions to ActiveSync and historical syncs are queued.
+		chainSynced = m.isChainSynced()
+
+		// chainSyncTicks fires whenever we should check whether the
+		// chain backend has completed its initial block download. It's
+		// nil once it has.
+		chainSyncTicks <-chan time.Time
 	)
 
+	if !chainSynced {
+		log.Info("Chain backend is still syncing, deferring active " +
+			"gossip syncing and historical syncs")
+
+		m.cfg.ChainSyncTicker.Resume()
+		defer m.cfg.ChainSyncTicker.Stop()
+
+		chainSyncTicks = m.cfg.ChainSyncTicker.Ticks()
+	}
+
 	for {
 		select {
 		// A new peer has been connected, so we'll create its
@@ -228,9 +380,15 @@ func (m *SyncManager) syncerHandler() {
 			m.syncersMu.Lock()
 			switch {
 			// If we've exceeded our total number of active syncers,
-			// we'll initialize this GossipSyncer as passive.
+			// active syncing is paused, or the chain backend is
+			// still syncing, we'll initialize this GossipSyncer as
+			// passive.
 			case len(m.activeSyncers) >= m.cfg.NumActiveSyncers:
 				fallthrough
+			case m.activeSyncPaused:
+				fallthrough
+			case !chainSynced:
+				fallthrough
 
 			// Otherwise, it should be initialized as active. If the
 			// initial historical sync has yet to complete, then
@@ -257,8 +415,9 @@ func (m *SyncManager) syncerHandler() {
 
 			// We'll force a historical sync with the first peer we
 			// connect to, to ensure we get as much of the graph as
-			// possible.
-			if !attemptInitialHistoricalSync {
+			// possible. If the chain backend is still syncing, it
+			// will be attempted once it's done.
+			if !attemptInitialHistoricalSync || !chainSynced {
 				continue
 			}
 
@@ -332,27 +491,79 @@ func (m *SyncManager) syncerHandler() {
 			log.Debug("Initial historical sync completed")
 
 			// With the initial historical sync complete, we can
-			// begin receiving new graph updates at tip. We'll
-			// determine whether we can have any more active
-			// GossipSyncers. If we do, we'll randomly select some
-			// that are currently passive to transition.
-			m.syncersMu.Lock()
-			numActiveLeft := m.cfg.NumActiveSyncers - len(m.activeSyncers)
-			if numActiveLeft <= 0 {
-				m.syncersMu.Unlock()
+			// begin receiving new graph updates at tip.
+			m.fillActiveSyncers()
+
+		// Our graph has been bootstrapped through other means, so
+		// there's no need to perform an initial historical sync.
+		case <-m.graphBootstrapped:
+			if initialHistoricalSyncCompleted {
 				continue
 			}
 
-			log.Debugf("Attempting to transition %v passive "+
-				"GossipSyncers to active", numActiveLeft)
+			log.Info("Channel graph bootstrapped, skipping initial " +
+				"historical sync")
+
+			attemptInitialHistoricalSync = false
+			initialHistoricalSyncer = nil
+			initialHistoricalSyncSignal = nil
+			initialHistoricalSyncCompleted = true
+
+			// We can now begin receiving new graph updates at tip,
+			// unless the chain backend is still syncing.
+			if chainSynced {
+				m.fillActiveSyncers()
+			}
 
-			for i := 0; i < numActiveLeft; i++ {
-				chooseRandomSyncer(
-					m.inactiveSyncers, m.transitionPassiveSyncer,
-				)
+		// Active syncing has been resumed, so we can begin receiving
+		// new graph updates at tip again, as long as the initial
+		// historical sync has completed.
+		case <-m.activeSyncResumed:
+			if !initialHistoricalSyncCompleted || !chainSynced {
+				continue
 			}
 
-			m.syncersMu.Unlock()
+			m.fillActiveSyncers()
+
+		// Our ChainSyncTicker has ticked, so we'll check whether the
+		// chain backend has completed its initial block download. If
+		// it has, we'll carry out the sync transitions we've deferred.
+		case <-chainSyncTicks:
+			if !m.isChainSynced() {
+				continue
+			}
+
+			log.Info("Chain backend synced, resuming active " +
+				"gossip syncing")
+
+			chainSynced = true
+			chainSyncTicks = nil
+			m.cfg.ChainSyncTicker.Pause()
+
+			// If the initial historical sync has already been
+			// completed, e.g. because our graph was bootstrapped,
+			// we can begin receiving new graph updates at tip.
+			if initialHistoricalSyncCompleted {
+				m.fillActiveSyncers()
+				continue
+			}
+
+			// Otherwise, we'll attempt it with any of our peers.
+			// If we don't have any, it'll be attempted with the
+			// next one to connect.
+			s := m.forceHistoricalSync()
+			if s == nil {
+				log.Debug("No eligible GossipSyncer found for " +
+					"initial historical sync")
+				continue
+			}
+
+			log.Debugf("Attempting initial historical sync with "+
+				"GossipSyncer(%x)", s.cfg.peerPub)
+
+			attemptInitialHistoricalSync = false
+			initialHistoricalSyncer = s
+			initialHistoricalSyncSignal = s.ResetSyncedSignal()
 
 		// Our RotateTicker has ticked, so we'll attempt to rotate a
 		// single active syncer with a passive one.
@@ -360,29 +571,97 @@ func (m *SyncManager) syncerHandler() {
 			m.rotateActiveSyncerCandidate()
 
 		// Our HistoricalSyncTicker has ticked, so we'll randomly select
-		// a peer and force a historical sync with them.
+		// a peer and force a historical sync with them, unless the
+		// chain backend is still syncing.
 		case <-m.cfg.HistoricalSyncTicker.Ticks():
+			if !chainSynced {
+				continue
+			}
+
 			m.forceHistoricalSync()
 
+		// Our HealthCheckTicker has ticked, so we'll replace any active
+		// syncers that have gone stale, and make sure we haven't been
+		// left without any syncers. If active syncing is paused, we'll
+		// instead retry demoting any syncers that couldn't be demoted
+		// when it was paused.
+		case <-m.cfg.HealthCheckTicker.Ticks():
+			if m.ActiveSyncPaused() {
+				m.demoteActiveSyncers()
+			} else {
+				m.replaceStaleActiveSyncers()
+			}
+			m.maybeRequestBootstrap(time.Now())
+
 		case <-m.quit:
 			return
 		}
 	}
 }
 
+// isChainSynced returns whether the chain backend has completed its initial
+// block download. If we're unable to tell, we'll assume it hasn't.
+func (m *SyncManager) isChainSynced() bool {
+	if m.cfg.IsChainSynced == nil {
+		return true
+	}
+
+	synced, err := m.cfg.IsChainSynced()
+	if err != nil {
+		log.Errorf("Unable to determine whether chain backend is "+
+			"synced: %v", err)
+		return false
+	}
+
+	return synced
+}
+
+// fillActiveSyncers determines whether we can have any more active
+// GossipSyncers. If we do, we'll randomly select some that are currently
+// passive to transition.
+func (m *SyncManager) fillActiveSyncers() {
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	if m.activeSyncPaused {
+		return
+	}
+
+	numActiveLeft := m.cfg.NumActiveSyncers - len(m.activeSyncers)
+	if numActiveLeft <= 0 {
+		return
+	}
+
+	log.Debugf("Attempting to transition %v passive GossipSyncers to "+
+		"active", numActiveLeft)
+
+	for i := 0; i < numActiveLeft; i++ {
+		chooseRandomSyncer(m.inactiveSyncers, m.transitionPassiveSyncer)
+	}
+}
+
 // createGossipSyncer creates the GossipSyncer for a newly connected peer.
 func (m *SyncManager) createGossipSyncer(peer lnpeer.Peer) *GossipSyncer {
 	nodeID := route.Vertex(peer.PubKey())
 	log.Infof("Creating new GossipSyncer for peer=%x", nodeID[:])
 
+	// We'll only request the timestamps and checksums of the peer's
+	// channel updates if it has signaled that it understands the extended
+	// gossip queries.
+	extendedQueries := peer.RemoteLocalFeatures().HasFeature(
+		lnwire.GossipQueriesExOptional,
+	)
+
 	encoding := lnwire.EncodingSortedPlain
 	s := newGossipSyncer(gossipSyncerCfg{
-		chainHash:     m.cfg.ChainHash,
-		peerPub:       nodeID,
-		channelSeries: m.cfg.ChanSeries,
-		encodingType:  encoding,
-		chunkSize:     encodingTypeToChunkSize[encoding],
-		batchSize:     requestBatchSize,
+		chainHash:       m.cfg.ChainHash,
+		peerPub:         nodeID,
+		channelSeries:   m.cfg.ChanSeries,
+		encodingType:    encoding,
+		chunkSize:       encodingTypeToChunkSize[encoding],
+		batchSize:       m.RequestBatchSize(),
+		extendedQueries: extendedQueries,
+		gossipHorizon:   m.GossipHorizon,
 		sendToPeer: func(msgs ...lnwire.Message) error {
 			return peer.SendMessageLazy(false, msgs...)
 		},
@@ -443,6 +722,10 @@ func (m *SyncManager) rotateActiveSyncerCandidate() {
 	m.syncersMu.Lock()
 	defer m.syncersMu.Unlock()
 
+	if m.activeSyncPaused {
+		return
+	}
+
 	// If we couldn't find an eligible active syncer to rotate, we can
 	// return early.
 	activeSyncer := chooseRandomSyncer(m.activeSyncers, nil)
@@ -477,6 +760,160 @@ func (m *SyncManager) rotateActiveSyncerCandidate() {
 	}
 }
 
+// replaceStaleActiveSyncers replaces each active syncer that hasn't delivered
+// any graph updates within the StaleSyncerTimeout with a passive one. This is
+// only done if we've received graph updates from other peers within the same
+// window, as otherwise we can't tell a stale syncer apart from a quiet network.
+func (m *SyncManager) replaceStaleActiveSyncers() {
+	if m.cfg.StaleSyncerTimeout == 0 {
+		return
+	}
+
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	staleCutoff := time.Now().Add(-m.cfg.StaleSyncerTimeout)
+	if !m.LastGraphUpdate().After(staleCutoff) {
+		log.Debug("No graph updates received recently, skipping " +
+			"active syncer health check")
+		return
+	}
+
+	var staleSyncers []*GossipSyncer
+	for _, s := range m.activeSyncers {
+		// Syncers that are not in a chansSynced state can't process
+		// a sync transition, so we'll skip them for now.
+		if s.syncState() != chansSynced {
+			continue
+		}
+
+		if s.LastGraphUpdate().After(staleCutoff) {
+			continue
+		}
+
+		staleSyncers = append(staleSyncers, s)
+	}
+
+	for _, s := range staleSyncers {
+		candidate := chooseRandomSyncer(m.inactiveSyncers, nil)
+		if candidate == nil {
+			log.Debug("No eligible candidate to replace stale " +
+				"active syncer")
+			return
+		}
+
+		log.Infof("Replacing stale active GossipSyncer(%x), last "+
+			"graph update at %v, with GossipSyncer(%x)",
+			s.cfg.peerPub, s.LastGraphUpdate(),
+			candidate.cfg.peerPub)
+
+		if err := m.transitionActiveSyncer(s); err != nil {
+			log.Errorf("Unable to transition active "+
+				"GossipSyncer(%x): %v", s.cfg.peerPub, err)
+			continue
+		}
+
+		if err := m.transitionPassiveSyncer(candidate); err != nil {
+			log.Errorf("Unable to transition passive "+
+				"GossipSyncer(%x): %v", candidate.cfg.peerPub,
+				err)
+		}
+	}
+}
+
+// maybeRequestBootstrap requests fresh peers to be bootstrapped if we've had
+// no gossip syncers for longer than the NoSyncersTimeout. Without any syncers,
+// our view of the channel graph would otherwise silently go stale until some
+// peer happens to connect to us.
+//
+// NOTE: This must only be called by the syncerHandler.
+func (m *SyncManager) maybeRequestBootstrap(now time.Time) {
+	if m.cfg.NoSyncersTimeout == 0 || m.cfg.RequestBootstrap == nil {
+		return
+	}
+
+	m.syncersMu.Lock()
+	numSyncers := len(m.activeSyncers) + len(m.inactiveSyncers)
+	m.syncersMu.Unlock()
+
+	switch {
+	case numSyncers > 0:
+		m.noSyncersSince = time.Time{}
+		return
+
+	case m.noSyncersSince.IsZero():
+		m.noSyncersSince = now
+		return
+
+	case now.Sub(m.noSyncersSince) < m.cfg.NoSyncersTimeout:
+		return
+	}
+
+	log.Infof("No gossip syncers since %v, requesting fresh peers from "+
+		"network bootstrapper", m.noSyncersSince)
+
+	// We'll reset our timer such that we only request another bootstrap
+	// if this one doesn't result in any new syncers.
+	m.noSyncersSince = now
+	m.cfg.RequestBootstrap()
+}
+
+// demoteActiveSyncers transitions all active syncers that are able to process
+// a sync transition to passive ones.
+func (m *SyncManager) demoteActiveSyncers() {
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	for _, s := range m.activeSyncers {
+		// Syncers that are not in a chansSynced state can't process
+		// a sync transition, so they'll be retried later on.
+		if s.syncState() != chansSynced {
+			continue
+		}
+
+		if err := m.transitionActiveSyncer(s); err != nil {
+			log.Errorf("Unable to transition active "+
+				"GossipSyncer(%x): %v", s.cfg.peerPub, err)
+		}
+	}
+}
+
+// PauseActiveSync stops receiving new graph updates at tip by transitioning
+// all active GossipSyncers to passive ones. No GossipSyncer will become
+// active until ResumeActiveSync is called. Historical syncs are unaffected.
+func (m *SyncManager) PauseActiveSync() {
+	m.syncersMu.Lock()
+	m.activeSyncPaused = true
+	m.syncersMu.Unlock()
+
+	log.Info("Pausing active gossip syncing")
+
+	m.demoteActiveSyncers()
+}
+
+// ResumeActiveSync resumes receiving new graph updates at tip after a prior
+// call to PauseActiveSync.
+func (m *SyncManager) ResumeActiveSync() {
+	m.syncersMu.Lock()
+	m.activeSyncPaused = false
+	m.syncersMu.Unlock()
+
+	log.Info("Resuming active gossip syncing")
+
+	select {
+	case m.activeSyncResumed <- struct{}{}:
+	default:
+	}
+}
+
+// ActiveSyncPaused returns whether active syncing is currently paused.
+func (m *SyncManager) ActiveSyncPaused() bool {
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	return m.activeSyncPaused
+}
+
 // transitionActiveSyncer transitions an active syncer to a passive one.
 //
 // NOTE: This must be called with the syncersMu lock held.
@@ -606,6 +1043,101 @@ func (m *SyncManager) PruneSyncState(peer route.Vertex) {
 	}
 }
 
+// MarkGraphBootstrapped is called by outside sub-systems once our view of the
+// channel graph has been populated through means other than gossip, e.g. by
+// importing a graph snapshot. If the initial historical sync hasn't completed
+// yet, it'll be skipped, and any passive GossipSyncers will be transitioned to
+// active ones in order to start receiving new graph updates at tip.
+func (m *SyncManager) MarkGraphBootstrapped() {
+	select {
+	case m.graphBootstrapped <- struct{}{}:
+	case <-m.quit:
+	}
+}
+
+// RecordGraphUpdate is called by outside sub-systems when a new graph update
+// has been received from the given peer. This is used to determine the health
+// of our active syncers.
+func (m *SyncManager) RecordGraphUpdate(peer route.Vertex) {
+	atomic.StoreInt64(&m.lastGraphUpdate, time.Now().UnixNano())
+
+	if s, ok := m.GossipSyncer(peer); ok {
+		s.recordGraphUpdate()
+	}
+}
+
+// RecordReceivedMsg is called by outside sub-systems when a gossip message has
+// been received from the given peer, such that it's accounted for within the
+// stats of the peer's GossipSyncer.
+func (m *SyncManager) RecordReceivedMsg(peer route.Vertex, msg lnwire.Message) {
+	if s, ok := m.GossipSyncer(peer); ok {
+		s.stats.recordReceived(msg)
+	}
+}
+
+// RecordUpdateResult is called by outside sub-systems once an announcement
+// received from the given peer has been processed, signaling whether it was
+// applied to our graph or was stale.
+func (m *SyncManager) RecordUpdateResult(peer route.Vertex, applied bool) {
+	if s, ok := m.GossipSyncer(peer); ok {
+		s.stats.recordUpdate(applied)
+	}
+}
+
+// LastGraphUpdate returns the time at which we last received a graph update
+// from any peer.
+func (m *SyncManager) LastGraphUpdate() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&m.lastGraphUpdate))
+}
+
+// GossipHorizon returns the currently configured gossip horizon.
+func (m *SyncManager) GossipHorizon() time.Duration {
+	return time.Duration(atomic.LoadInt64(&m.gossipHorizon))
+}
+
+// SetGossipHorizon changes how far back in time the graph updates we request
+// from our active syncers should reach. Our active syncers will send their
+// updated horizon to their remote peers right away, or once they're done with
+// any ongoing sync, while passive syncers will use it once they become active.
+func (m *SyncManager) SetGossipHorizon(horizon time.Duration) {
+	atomic.StoreInt64(&m.gossipHorizon, int64(horizon))
+
+	log.Infof("Setting gossip horizon to %v", horizon)
+
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	for _, s := range m.activeSyncers {
+		s.UpdateGossipHorizon()
+	}
+}
+
+// RequestBatchSize returns the currently configured maximum number of channels
+// our syncers will query in a single QueryShortChanIDs message.
+func (m *SyncManager) RequestBatchSize() int32 {
+	return atomic.LoadInt32(&m.requestBatchSize)
+}
+
+// SetRequestBatchSize changes the maximum number of channels our syncers will
+// query in a single QueryShortChanIDs message. Smaller batches bound the
+// memory used to process the replies of the remote peer, at the cost of more
+// round trips.
+func (m *SyncManager) SetRequestBatchSize(batchSize int32) {
+	atomic.StoreInt32(&m.requestBatchSize, batchSize)
+
+	log.Infof("Setting gossip request batch size to %v", batchSize)
+
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	for _, s := range m.activeSyncers {
+		s.setBatchSize(batchSize)
+	}
+	for _, s := range m.inactiveSyncers {
+		s.setBatchSize(batchSize)
+	}
+}
+
 // GossipSyncer returns the associated gossip syncer of a peer. The boolean
 // returned signals whether there exists a gossip syncer for the peer.
 func (m *SyncManager) GossipSyncer(peer route.Vertex) (*GossipSyncer, bool) {
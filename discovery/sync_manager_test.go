@@ -33,6 +33,9 @@ func newTestSyncManager(numActiveSyncers int) *SyncManager {
 		ChanSeries:           newMockChannelGraphTimeSeries(hID),
 		RotateTicker:         ticker.NewForce(DefaultSyncerRotationInterval),
 		HistoricalSyncTicker: ticker.NewForce(DefaultHistoricalSyncInterval),
+		HealthCheckTicker:    ticker.NewForce(DefaultSyncerHealthCheckInterval),
+		ChainSyncTicker:      ticker.NewForce(DefaultChainSyncPollInterval),
+		StaleSyncerTimeout:   DefaultStaleSyncerTimeout,
 		NumActiveSyncers:     numActiveSyncers,
 	})
 }
@@ -177,6 +180,228 @@ func TestSyncManagerRotateActiveSyncerCandidate(t *testing.T) {
 	assertPassiveSyncerTransition(t, passiveSyncer, passiveSyncPeer)
 }
 
+// TestSyncManagerReplaceStaleActiveSyncer ensures that an active syncer which
+// hasn't delivered any graph updates within the StaleSyncerTimeout is replaced
+// with a passive one, but only while other peers are still delivering updates.
+func TestSyncManagerReplaceStaleActiveSyncer(t *testing.T) {
+	t.Parallel()
+
+	syncMgr := newTestSyncManager(1)
+	syncMgr.Start()
+	defer syncMgr.Stop()
+
+	// The first syncer registered always performs a historical sync.
+	activeSyncPeer := randPeer(t, syncMgr.quit)
+	syncMgr.InitSyncState(activeSyncPeer)
+	activeSyncer := assertSyncerExistence(t, syncMgr, activeSyncPeer)
+	assertTransitionToChansSynced(t, activeSyncer, activeSyncPeer)
+	assertActiveGossipTimestampRange(t, activeSyncPeer)
+	assertSyncerStatus(t, activeSyncer, chansSynced, ActiveSync)
+
+	passiveSyncPeer := randPeer(t, syncMgr.quit)
+	syncMgr.InitSyncState(passiveSyncPeer)
+	passiveSyncer := assertSyncerExistence(t, syncMgr, passiveSyncPeer)
+	assertSyncerStatus(t, passiveSyncer, chansSynced, PassiveSync)
+
+	// We'll mark our active syncer as stale. Since we haven't received
+	// any graph updates at all, the network is seemingly quiet, so it
+	// shouldn't be replaced.
+	staleTime := time.Now().Add(-2 * DefaultStaleSyncerTimeout)
+	atomic.StoreInt64(&activeSyncer.lastGraphUpdate, staleTime.UnixNano())
+
+	syncMgr.cfg.HealthCheckTicker.(*ticker.Force).Force <- time.Time{}
+	assertNoMsgSent(t, activeSyncPeer)
+	assertSyncerStatus(t, activeSyncer, chansSynced, ActiveSync)
+
+	// Once another peer delivers a graph update, the active syncer should
+	// be replaced with the passive one.
+	syncMgr.RecordGraphUpdate(passiveSyncPeer.PubKey())
+
+	syncMgr.cfg.HealthCheckTicker.(*ticker.Force).Force <- time.Time{}
+	assertActiveSyncerTransition(t, activeSyncer, activeSyncPeer)
+	assertPassiveSyncerTransition(t, passiveSyncer, passiveSyncPeer)
+
+	// The newly active syncer is given a full window to deliver updates,
+	// so it shouldn't be replaced on the next health check.
+	syncMgr.cfg.HealthCheckTicker.(*ticker.Force).Force <- time.Time{}
+	assertNoMsgSent(t, passiveSyncPeer)
+	assertSyncerStatus(t, passiveSyncer, chansSynced, ActiveSync)
+}
+
+// TestSyncManagerPauseActiveSync ensures that pausing active syncing demotes
+// all active syncers, keeps new syncers passive, and that resuming it fills
+// the set of active syncers once again.
+func TestSyncManagerPauseActiveSync(t *testing.T) {
+	t.Parallel()
+
+	syncMgr := newTestSyncManager(1)
+	syncMgr.Start()
+	defer syncMgr.Stop()
+
+	// The first syncer registered always performs a historical sync.
+	activeSyncPeer := randPeer(t, syncMgr.quit)
+	syncMgr.InitSyncState(activeSyncPeer)
+	activeSyncer := assertSyncerExistence(t, syncMgr, activeSyncPeer)
+	assertTransitionToChansSynced(t, activeSyncer, activeSyncPeer)
+	assertActiveGossipTimestampRange(t, activeSyncPeer)
+	assertSyncerStatus(t, activeSyncer, chansSynced, ActiveSync)
+
+	// Pausing active syncing should transition our active syncer to a
+	// passive one.
+	go syncMgr.PauseActiveSync()
+	assertActiveSyncerTransition(t, activeSyncer, activeSyncPeer)
+
+	err := lntest.WaitNoError(func() error {
+		if !syncMgr.ActiveSyncPaused() {
+			return fmt.Errorf("expected active sync to be paused")
+		}
+		return nil
+	}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// While paused, syncers should neither be rotated nor created as
+	// active.
+	syncMgr.cfg.RotateTicker.(*ticker.Force).Force <- time.Time{}
+	assertNoMsgSent(t, activeSyncPeer)
+	assertSyncerStatus(t, activeSyncer, chansSynced, PassiveSync)
+
+	passiveSyncPeer := randPeer(t, syncMgr.quit)
+	syncMgr.InitSyncState(passiveSyncPeer)
+	passiveSyncer := assertSyncerExistence(t, syncMgr, passiveSyncPeer)
+	assertSyncerStatus(t, passiveSyncer, chansSynced, PassiveSync)
+
+	// Once resumed, one of the passive syncers should become active
+	// again.
+	syncMgr.ResumeActiveSync()
+
+	var (
+		msgSent lnwire.Message
+		resumed *GossipSyncer
+	)
+	select {
+	case msgSent = <-activeSyncPeer.sentMsgs:
+		resumed = activeSyncer
+	case msgSent = <-passiveSyncPeer.sentMsgs:
+		resumed = passiveSyncer
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a syncer to become active")
+	}
+
+	msg, ok := msgSent.(*lnwire.GossipTimestampRange)
+	if !ok || msg.FirstTimestamp == 0 {
+		t.Fatalf("expected active GossipTimestampRange message, got "+
+			"%v", spew.Sdump(msgSent))
+	}
+	assertSyncerStatus(t, resumed, chansSynced, ActiveSync)
+}
+
+// TestSyncManagerSetRequestBatchSize asserts that changing the request batch
+// size of the SyncManager applies to both existing and new syncers.
+func TestSyncManagerSetRequestBatchSize(t *testing.T) {
+	t.Parallel()
+
+	syncMgr := newTestSyncManager(1)
+	syncMgr.Start()
+	defer syncMgr.Stop()
+
+	if syncMgr.RequestBatchSize() != DefaultRequestBatchSize {
+		t.Fatalf("expected default batch size %d, got %d",
+			DefaultRequestBatchSize, syncMgr.RequestBatchSize())
+	}
+
+	assertBatchSize := func(s *GossipSyncer, expected int32) {
+		t.Helper()
+
+		batchSize := atomic.LoadInt32(&s.cfg.batchSize)
+		if batchSize != expected {
+			t.Fatalf("expected batch size %d, got %d", expected,
+				batchSize)
+		}
+	}
+
+	peer := randPeer(t, syncMgr.quit)
+	syncMgr.InitSyncState(peer)
+	s := assertSyncerExistence(t, syncMgr, peer)
+	assertBatchSize(s, DefaultRequestBatchSize)
+
+	const batchSize = 100
+	syncMgr.SetRequestBatchSize(batchSize)
+	assertBatchSize(s, batchSize)
+
+	newPeer := randPeer(t, syncMgr.quit)
+	syncMgr.InitSyncState(newPeer)
+	newSyncer := assertSyncerExistence(t, syncMgr, newPeer)
+	assertBatchSize(newSyncer, batchSize)
+}
+
+// TestSyncManagerNoSyncersBootstrap ensures that the SyncManager requests fresh
+// peers to be bootstrapped once it has been without any gossip syncers for
+// longer than the NoSyncersTimeout.
+func TestSyncManagerNoSyncersBootstrap(t *testing.T) {
+	t.Parallel()
+
+	var numRequests int
+	syncMgr := newTestSyncManager(1)
+	syncMgr.cfg.NoSyncersTimeout = DefaultNoSyncersTimeout
+	syncMgr.cfg.RequestBootstrap = func() {
+		numRequests++
+	}
+
+	assertNumRequests := func(expected int) {
+		t.Helper()
+
+		if numRequests != expected {
+			t.Fatalf("expected %d bootstrap requests, got %d",
+				expected, numRequests)
+		}
+	}
+
+	// The first health check without any syncers should only start the
+	// timer, and we shouldn't request a bootstrap until it has expired.
+	now := time.Now()
+	syncMgr.maybeRequestBootstrap(now)
+	assertNumRequests(0)
+
+	now = now.Add(DefaultNoSyncersTimeout / 2)
+	syncMgr.maybeRequestBootstrap(now)
+	assertNumRequests(0)
+
+	now = now.Add(DefaultNoSyncersTimeout / 2)
+	syncMgr.maybeRequestBootstrap(now)
+	assertNumRequests(1)
+
+	// If the bootstrap didn't result in any syncers, we should only
+	// request another once the timeout has expired again.
+	now = now.Add(DefaultNoSyncersTimeout / 2)
+	syncMgr.maybeRequestBootstrap(now)
+	assertNumRequests(1)
+
+	now = now.Add(DefaultNoSyncersTimeout / 2)
+	syncMgr.maybeRequestBootstrap(now)
+	assertNumRequests(2)
+
+	// Once we have a syncer, the timer should be reset, so we shouldn't
+	// request a bootstrap right away after losing it again.
+	peer := randPeer(t, syncMgr.quit)
+	syncMgr.inactiveSyncers[peer.PubKey()] = syncMgr.createGossipSyncer(peer)
+
+	now = now.Add(DefaultNoSyncersTimeout)
+	syncMgr.maybeRequestBootstrap(now)
+	assertNumRequests(2)
+
+	delete(syncMgr.inactiveSyncers, peer.PubKey())
+
+	now = now.Add(DefaultNoSyncersTimeout / 2)
+	syncMgr.maybeRequestBootstrap(now)
+	assertNumRequests(2)
+
+	now = now.Add(DefaultNoSyncersTimeout)
+	syncMgr.maybeRequestBootstrap(now)
+	assertNumRequests(3)
+}
+
 // TestSyncManagerInitialHistoricalSync ensures that we only attempt a single
 // historical sync during the SyncManager's startup. If the peer corresponding
 // to the initial historical syncer disconnects, we should attempt to find a
@@ -314,6 +539,80 @@ func TestSyncManagerWaitUntilInitialHistoricalSync(t *testing.T) {
 	}
 }
 
+// TestSyncManagerDeferActiveSyncUntilChainSynced ensures that no GossipSyncers
+// are initialized as ActiveSync, and no historical syncs are attempted, while
+// the chain backend is still performing its initial block download. Once it
+// has completed, the deferred initial historical sync should be attempted,
+// followed by the transitions to ActiveSync.
+func TestSyncManagerDeferActiveSyncUntilChainSynced(t *testing.T) {
+	t.Parallel()
+
+	const numActiveSyncers = 2
+
+	var chainSynced uint32
+	syncMgr := newTestSyncManager(numActiveSyncers)
+	syncMgr.cfg.IsChainSynced = func() (bool, error) {
+		return atomic.LoadUint32(&chainSynced) == 1, nil
+	}
+	syncMgr.Start()
+	defer syncMgr.Stop()
+
+	// While the chain backend is still syncing, all of our syncers should
+	// be initialized as passive, and none of them should attempt the
+	// initial historical sync.
+	peers := make([]*mockPeer, 0, numActiveSyncers)
+	syncers := make(map[*mockPeer]*GossipSyncer, numActiveSyncers)
+	for i := 0; i < numActiveSyncers; i++ {
+		peer := randPeer(t, syncMgr.quit)
+		peers = append(peers, peer)
+
+		syncMgr.InitSyncState(peer)
+		s := assertSyncerExistence(t, syncMgr, peer)
+		syncers[peer] = s
+
+		assertNoMsgSent(t, peer)
+		assertSyncerStatus(t, s, chansSynced, PassiveSync)
+	}
+
+	// Routine historical syncs should also be deferred, as well as any
+	// checks while the chain backend has yet to complete.
+	syncMgr.cfg.HistoricalSyncTicker.(*ticker.Force).Force <- time.Time{}
+	syncMgr.cfg.ChainSyncTicker.(*ticker.Force).Force <- time.Time{}
+	for _, peer := range peers {
+		assertNoMsgSent(t, peer)
+	}
+
+	// Once the chain backend has synced, the initial historical sync
+	// should be attempted with one of our peers.
+	atomic.StoreUint32(&chainSynced, 1)
+	syncMgr.cfg.ChainSyncTicker.(*ticker.Force).Force <- time.Time{}
+
+	var historicalPeer *mockPeer
+	err := lntest.WaitNoError(func() error {
+		for _, peer := range peers {
+			state := syncers[peer].syncState()
+			if state == syncingChans {
+				historicalPeer = peer
+				return nil
+			}
+		}
+
+		return fmt.Errorf("expected initial historical sync to be " +
+			"attempted")
+	}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After it completes, all of our syncers should transition to active.
+	assertTransitionToChansSynced(
+		t, syncers[historicalPeer], historicalPeer,
+	)
+	for _, peer := range peers {
+		assertPassiveSyncerTransition(t, syncers[peer], peer)
+	}
+}
+
 // assertNoMsgSent is a helper function that ensures a peer hasn't sent any
 // messages.
 func assertNoMsgSent(t *testing.T, peer *mockPeer) {
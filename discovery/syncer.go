@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/lnd/lnpeer"
 	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing"
 	"golang.org/x/time/rate"
 )
 
@@ -137,9 +139,22 @@ const (
 	// to when attempting to perform a sync transition.
 	syncTransitionTimeout = 5 * time.Second
 
-	// requestBatchSize is the maximum number of channels we will query the
-	// remote peer for in a QueryShortChanIDs message.
-	requestBatchSize = 500
+	// DefaultRequestBatchSize is the default maximum number of channels
+	// we will query the remote peer for in a QueryShortChanIDs message.
+	DefaultRequestBatchSize = 500
+
+	// extendedChunkSize is the max number of short chan IDs we'll send
+	// within a single ReplyChannelRange message if the timestamps and
+	// checksums of their channel updates were requested. Each of them adds
+	// another 8 bytes per short chan ID, so we'll need to reduce the chunk
+	// size accordingly.
+	extendedChunkSize = 2500
+
+	// chanUpdateRefreshAge is the age after which we'll query the remote
+	// peer for a newer channel update, even if its checksum signals that
+	// it only refreshes the timestamp of the one we know of. Otherwise,
+	// the channel could be pruned from our graph as a zombie.
+	chanUpdateRefreshAge = routing.DefaultChannelPruneExpiry / 2
 )
 
 var (
@@ -205,6 +220,8 @@ type gossipSyncerCfg struct {
 
 	// batchSize is the max number of channels the syncer will query from
 	// the remote node in a single QueryShortChanIDs request.
+	//
+	// NOTE: This variable MUST be used atomically.
 	batchSize int32
 
 	// sendToPeer sends a variadic number of messages to the remote peer.
@@ -235,6 +252,19 @@ type gossipSyncerCfg struct {
 	// replyHandler, meaning we will not reply to queries from our remote
 	// peer.
 	noReplyQueries bool
+
+	// extendedQueries signals that the remote peer understands the
+	// extended gossip queries. If set, we'll request the timestamps and
+	// checksums of its channel updates when querying its channel range,
+	// allowing us to also query the channels we already know of, but for
+	// which the remote peer has newer channel updates.
+	extendedQueries bool
+
+	// gossipHorizon returns how far back in time the update horizon we
+	// request from the remote peer should reach while we're actively
+	// syncing with it. A zero duration signals that we're only interested
+	// in new graph updates.
+	gossipHorizon func() time.Duration
 }
 
 // GossipSyncer is a struct that handles synchronizing the channel graph state
@@ -245,6 +275,18 @@ type gossipSyncerCfg struct {
 // update horizon. If the update horizon isn't specified, then we won't send
 // them any channel updates at all.
 type GossipSyncer struct {
+	// lastGraphUpdate is the unix timestamp in nanoseconds of the last
+	// graph update received from the remote peer, or the time at which
+	// the syncer last became active, whichever is more recent.
+	//
+	// NOTE: This variable MUST be used atomically.
+	lastGraphUpdate int64
+
+	// stats tracks the gossip traffic exchanged with the remote peer. It's
+	// placed at the top of the struct to ensure 64-bit alignment of its
+	// counters.
+	stats syncerStats
+
 	started sync.Once
 	stopped sync.Once
 
@@ -279,6 +321,13 @@ type GossipSyncer struct {
 	// machine behaves as expected.
 	historicalSyncReqs chan *historicalSyncReq
 
+	// horizonUpdates is a channel that serves as a signal for the gossip
+	// syncer to resend its update horizon to the remote peer, as the
+	// configured gossip horizon has changed. It's buffered such that the
+	// signal is retained until the gossip syncer reaches its chansSynced
+	// state.
+	horizonUpdates chan struct{}
+
 	// genHistoricalChanRangeQuery when true signals to the gossip syncer
 	// that it should request the remote peer for all of its known channel
 	// IDs starting from the genesis block of the chain. This can only
@@ -300,11 +349,21 @@ type GossipSyncer struct {
 	// buffer all the chunked response to our query.
 	bufferedChanRangeReplies []lnwire.ShortChannelID
 
+	// bufferedChanUpdateInfo is used in the waitingQueryChanReply state to
+	// buffer the timestamps and checksums of the remote peer's channel
+	// updates, if it included them within its responses.
+	bufferedChanUpdateInfo map[lnwire.ShortChannelID]ChanUpdateInfo
+
 	// newChansToQuery is used to pass the set of channels we should query
 	// for from the waitingQueryChanReply state to the queryNewChannels
 	// state.
 	newChansToQuery []lnwire.ShortChannelID
 
+	// newChanQueryFlags holds the query flags of each of the channels
+	// within newChansToQuery. It's only set if the remote peer understands
+	// the extended gossip queries.
+	newChanQueryFlags map[lnwire.ShortChannelID]lnwire.QueryFlag
+
 	cfg gossipSyncerCfg
 
 	// rateLimiter dictates the frequency with which we will reply to gossip
@@ -338,6 +397,12 @@ func newGossipSyncer(cfg gossipSyncerCfg) *GossipSyncer {
 		cfg.delayedQueryReplyInterval = DefaultDelayedQueryReplyInterval
 	}
 
+	// If no gossip horizon was specified, we'll only request new graph
+	// updates from the remote peer.
+	if cfg.gossipHorizon == nil {
+		cfg.gossipHorizon = func() time.Duration { return 0 }
+	}
+
 	// Construct a rate limiter that will govern how frequently we reply to
 	// gossip queries from this peer. The limiter will automatically adjust
 	// during periods of quiescence, and increase the reply interval under
@@ -347,15 +412,35 @@ func newGossipSyncer(cfg gossipSyncerCfg) *GossipSyncer {
 		interval, cfg.maxUndelayedQueryReplies,
 	)
 
-	return &GossipSyncer{
-		cfg:                cfg,
+	g := &GossipSyncer{
 		rateLimiter:        rateLimiter,
 		syncTransitionReqs: make(chan *syncTransitionReq),
 		historicalSyncReqs: make(chan *historicalSyncReq),
+		horizonUpdates:     make(chan struct{}, 1),
 		gossipMsgs:         make(chan lnwire.Message, 100),
 		queryMsgs:          make(chan lnwire.Message, 100),
 		quit:               make(chan struct{}),
 	}
+
+	// Account for all messages sent to the remote peer within our stats.
+	sendToPeer, sendToPeerSync := cfg.sendToPeer, cfg.sendToPeerSync
+	cfg.sendToPeer = func(msgs ...lnwire.Message) error {
+		if err := sendToPeer(msgs...); err != nil {
+			return err
+		}
+		g.stats.recordSent(msgs...)
+		return nil
+	}
+	cfg.sendToPeerSync = func(msgs ...lnwire.Message) error {
+		if err := sendToPeerSync(msgs...); err != nil {
+			return err
+		}
+		g.stats.recordSent(msgs...)
+		return nil
+	}
+	g.cfg = cfg
+
+	return g
 }
 
 // Start starts the GossipSyncer and any goroutines that it needs to carry out
@@ -527,7 +612,7 @@ func (g *GossipSyncer) channelGraphSyncer() {
 			// do so now.
 			if g.localUpdateHorizon == nil && syncType == ActiveSync {
 				err := g.sendGossipTimestampRange(
-					time.Now(), math.MaxUint32,
+					g.horizonStart(), math.MaxUint32,
 				)
 				if err != nil {
 					log.Errorf("Unable to send update "+
@@ -546,6 +631,9 @@ func (g *GossipSyncer) channelGraphSyncer() {
 			case req := <-g.historicalSyncReqs:
 				g.handleHistoricalSync(req)
 
+			case <-g.horizonUpdates:
+				g.handleHorizonUpdate()
+
 			case <-g.quit:
 				return
 			}
@@ -616,6 +704,61 @@ func (g *GossipSyncer) sendGossipTimestampRange(firstTimestamp time.Time,
 	return nil
 }
 
+// horizonStart returns the first timestamp of the update horizon we'll request
+// from the remote peer while actively syncing with it, based on the currently
+// configured gossip horizon.
+func (g *GossipSyncer) horizonStart() time.Time {
+	now := time.Now()
+
+	horizon := g.cfg.gossipHorizon()
+	if horizon <= 0 {
+		return now
+	}
+
+	// A horizon reaching back beyond the unix epoch can't be expressed
+	// within the GossipTimestampRange message, so we'll request the full
+	// history of the remote peer's graph instead.
+	epoch := time.Unix(0, 0)
+	if horizon >= now.Sub(epoch) {
+		return epoch
+	}
+
+	return now.Add(-horizon)
+}
+
+// UpdateGossipHorizon signals the gossip syncer that the configured gossip
+// horizon has changed. If the syncer is active, it'll resend its update horizon
+// to the remote peer once it's done with any ongoing sync. This method doesn't
+// block.
+func (g *GossipSyncer) UpdateGossipHorizon() {
+	select {
+	case g.horizonUpdates <- struct{}{}:
+	default:
+	}
+}
+
+// setBatchSize changes the max number of channels the syncer will query from
+// the remote node in a single QueryShortChanIDs request. It takes effect with
+// the syncer's next query.
+func (g *GossipSyncer) setBatchSize(batchSize int32) {
+	atomic.StoreInt32(&g.cfg.batchSize, batchSize)
+}
+
+// handleHorizonUpdate resends our update horizon to the remote peer based on
+// the currently configured gossip horizon. Passive syncers don't receive any
+// graph updates, so they're left untouched.
+func (g *GossipSyncer) handleHorizonUpdate() {
+	if g.SyncType() != ActiveSync {
+		return
+	}
+
+	err := g.sendGossipTimestampRange(g.horizonStart(), math.MaxUint32)
+	if err != nil {
+		log.Errorf("Unable to send updated horizon to %x: %v",
+			g.cfg.peerPub, err)
+	}
+}
+
 // synchronizeChanIDs is called by the channelGraphSyncer when we need to query
 // the remote peer for its known set of channel IDs within a particular block
 // range. This method will be called continually until the entire range has
@@ -629,6 +772,7 @@ func (g *GossipSyncer) synchronizeChanIDs() (bool, error) {
 	if len(g.newChansToQuery) == 0 {
 		log.Infof("GossipSyncer(%x): no more chans to query",
 			g.cfg.peerPub[:])
+		g.newChanQueryFlags = nil
 		return true, nil
 	}
 
@@ -638,7 +782,8 @@ func (g *GossipSyncer) synchronizeChanIDs() (bool, error) {
 
 	// If the number of channels to query for is less than the chunk size,
 	// then we can issue a single query.
-	if int32(len(g.newChansToQuery)) < g.cfg.batchSize {
+	batchSize := atomic.LoadInt32(&g.cfg.batchSize)
+	if int32(len(g.newChansToQuery)) < batchSize {
 		queryChunk = g.newChansToQuery
 		g.newChansToQuery = nil
 
@@ -646,8 +791,8 @@ func (g *GossipSyncer) synchronizeChanIDs() (bool, error) {
 		// Otherwise, we'll need to only query for the next chunk.
 		// We'll slice into our query chunk, then slide down our main
 		// pointer down by the chunk size.
-		queryChunk = g.newChansToQuery[:g.cfg.batchSize]
-		g.newChansToQuery = g.newChansToQuery[g.cfg.batchSize:]
+		queryChunk = g.newChansToQuery[:batchSize]
+		g.newChansToQuery = g.newChansToQuery[batchSize:]
 	}
 
 	log.Infof("GossipSyncer(%x): querying for %v new channels",
@@ -655,11 +800,23 @@ func (g *GossipSyncer) synchronizeChanIDs() (bool, error) {
 
 	// With our chunk obtained, we'll send over our next query, then return
 	// false indicating that we're net yet fully synced.
-	err := g.cfg.sendToPeer(&lnwire.QueryShortChanIDs{
+	query := &lnwire.QueryShortChanIDs{
 		ChainHash:    g.cfg.chainHash,
 		EncodingType: lnwire.EncodingSortedPlain,
 		ShortChanIDs: queryChunk,
-	})
+	}
+
+	// If we know which announcements of each channel we're interested in,
+	// we'll let the remote peer know, so it only sends those.
+	if g.newChanQueryFlags != nil {
+		query.QueryFlags = make([]lnwire.QueryFlag, len(queryChunk))
+		for i, chanID := range queryChunk {
+			query.QueryFlags[i] = g.newChanQueryFlags[chanID]
+			delete(g.newChanQueryFlags, chanID)
+		}
+	}
+
+	err := g.cfg.sendToPeer(query)
 
 	return false, err
 }
@@ -672,6 +829,31 @@ func (g *GossipSyncer) processChanRangeReply(msg *lnwire.ReplyChannelRange) erro
 		g.bufferedChanRangeReplies, msg.ShortChanIDs...,
 	)
 
+	// If the remote peer included the timestamps of its channel updates,
+	// we'll buffer them, along with their checksums if present, to later
+	// determine whether it has newer updates than us.
+	if len(msg.Timestamps) == len(msg.ShortChanIDs) &&
+		len(msg.Timestamps) > 0 {
+
+		if g.bufferedChanUpdateInfo == nil {
+			g.bufferedChanUpdateInfo = make(
+				map[lnwire.ShortChannelID]ChanUpdateInfo,
+			)
+		}
+
+		hasChecksums := len(msg.Checksums) == len(msg.ShortChanIDs)
+		for i, chanID := range msg.ShortChanIDs {
+			info := ChanUpdateInfo{
+				ChanUpdateTimestamps: msg.Timestamps[i],
+			}
+			if hasChecksums {
+				info.ChanUpdateChecksums = msg.Checksums[i]
+			}
+
+			g.bufferedChanUpdateInfo[chanID] = info
+		}
+	}
+
 	log.Infof("GossipSyncer(%x): buffering chan range reply of size=%v",
 		g.cfg.peerPub[:], len(msg.ShortChanIDs))
 
@@ -693,14 +875,23 @@ func (g *GossipSyncer) processChanRangeReply(msg *lnwire.ReplyChannelRange) erro
 		return fmt.Errorf("unable to filter chan ids: %v", err)
 	}
 
+	// If the remote peer told us about the state of its channel updates,
+	// we'll also determine which of the channels we already know of have
+	// newer updates on its end.
+	updatedChans, err := g.filterUpdatedChans(newChans)
+	if err != nil {
+		return fmt.Errorf("unable to filter updated chans: %v", err)
+	}
+
 	// As we've received the entirety of the reply, we no longer need to
 	// hold on to the set of buffered replies, so we'll let that be garbage
 	// collected now.
 	g.bufferedChanRangeReplies = nil
+	g.bufferedChanUpdateInfo = nil
 
 	// If there aren't any channels that we don't know of, then we can
 	// switch straight to our terminal state.
-	if len(newChans) == 0 {
+	if len(newChans) == 0 && len(updatedChans) == 0 {
 		log.Infof("GossipSyncer(%x): remote peer has no new chans",
 			g.cfg.peerPub[:])
 
@@ -711,14 +902,141 @@ func (g *GossipSyncer) processChanRangeReply(msg *lnwire.ReplyChannelRange) erro
 	// Otherwise, we'll set the set of channels that we need to query for
 	// the next state, and also transition our state.
 	g.newChansToQuery = newChans
+	if g.cfg.extendedQueries {
+		g.setExtendedChansToQuery(newChans, updatedChans)
+	}
 	g.setSyncState(queryNewChannels)
 
-	log.Infof("GossipSyncer(%x): starting query for %v new chans",
-		g.cfg.peerPub[:], len(newChans))
+	log.Infof("GossipSyncer(%x): starting query for %v new chans and %v "+
+		"chans with newer updates", g.cfg.peerPub[:], len(newChans),
+		len(updatedChans))
 
 	return nil
 }
 
+// filterUpdatedChans returns the query flags of the channels within the
+// buffered channel range replies that we already know of, but for which the
+// remote peer has newer channel updates. The passed set of new channels, which
+// we don't know of at all, is excluded.
+func (g *GossipSyncer) filterUpdatedChans(
+	newChans []lnwire.ShortChannelID) (map[lnwire.ShortChannelID]lnwire.QueryFlag,
+	error) {
+
+	if !g.cfg.extendedQueries || len(g.bufferedChanUpdateInfo) == 0 {
+		return nil, nil
+	}
+
+	isNewChan := make(map[lnwire.ShortChannelID]struct{}, len(newChans))
+	for _, chanID := range newChans {
+		isNewChan[chanID] = struct{}{}
+	}
+
+	knownChans := make(
+		[]lnwire.ShortChannelID, 0, len(g.bufferedChanUpdateInfo),
+	)
+	for chanID := range g.bufferedChanUpdateInfo {
+		if _, ok := isNewChan[chanID]; !ok {
+			knownChans = append(knownChans, chanID)
+		}
+	}
+
+	localInfo, err := g.cfg.channelSeries.FetchChanUpdateInfo(
+		g.cfg.chainHash, knownChans,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedChans := make(map[lnwire.ShortChannelID]lnwire.QueryFlag)
+	for i, chanID := range knownChans {
+		local := localInfo[i]
+		remote := g.bufferedChanUpdateInfo[chanID]
+
+		// Channels that we consider zombies are known to us, but their
+		// edges are no longer part of our graph. We'll skip them, just
+		// as we'd do without the extended queries.
+		if local.Timestamp1 == 0 && local.Timestamp2 == 0 {
+			continue
+		}
+
+		var flags lnwire.QueryFlag
+		if shouldQueryUpdate(
+			local.Timestamp1, remote.Timestamp1,
+			local.Checksum1, remote.Checksum1,
+		) {
+			flags |= lnwire.QueryFlagChanUpdate1
+		}
+		if shouldQueryUpdate(
+			local.Timestamp2, remote.Timestamp2,
+			local.Checksum2, remote.Checksum2,
+		) {
+			flags |= lnwire.QueryFlagChanUpdate2
+		}
+
+		if flags != 0 {
+			updatedChans[chanID] = flags
+		}
+	}
+
+	return updatedChans, nil
+}
+
+// shouldQueryUpdate determines whether we should query the remote peer for
+// its channel update of a channel's direction, based on the timestamps and
+// checksums of our update and the remote peer's update.
+func shouldQueryUpdate(localTimestamp, remoteTimestamp, localChecksum,
+	remoteChecksum uint32) bool {
+
+	// There's nothing to gain if the remote update isn't newer than ours.
+	if remoteTimestamp <= localTimestamp {
+		return false
+	}
+
+	// If the checksums match, the remote update merely refreshes the
+	// timestamp of ours. We'll only query it if ours is at risk of being
+	// considered a zombie.
+	if localChecksum != 0 && localChecksum == remoteChecksum {
+		lastUpdate := time.Unix(int64(localTimestamp), 0)
+		return time.Since(lastUpdate) >= chanUpdateRefreshAge
+	}
+
+	return true
+}
+
+// setExtendedChansToQuery sets the set of channels we should query for within
+// the queryNewChannels state, along with their query flags. We'll request all
+// announcements of the channels we don't know of, and only the newer channel
+// updates of those we do.
+func (g *GossipSyncer) setExtendedChansToQuery(newChans []lnwire.ShortChannelID,
+	updatedChans map[lnwire.ShortChannelID]lnwire.QueryFlag) {
+
+	g.newChanQueryFlags = make(
+		map[lnwire.ShortChannelID]lnwire.QueryFlag,
+		len(newChans)+len(updatedChans),
+	)
+	for chanID, flags := range updatedChans {
+		g.newChanQueryFlags[chanID] = flags
+	}
+	for _, chanID := range newChans {
+		g.newChanQueryFlags[chanID] = lnwire.QueryFlagAll
+	}
+
+	// As the query flags must line up with the short chan IDs once they're
+	// sorted on the wire, we'll sort them upfront, which also removes any
+	// duplicates the remote peer may have sent.
+	chansToQuery := make(
+		[]lnwire.ShortChannelID, 0, len(g.newChanQueryFlags),
+	)
+	for chanID := range g.newChanQueryFlags {
+		chansToQuery = append(chansToQuery, chanID)
+	}
+	sort.Slice(chansToQuery, func(i, j int) bool {
+		return chansToQuery[i].ToUint64() < chansToQuery[j].ToUint64()
+	})
+
+	g.newChansToQuery = chansToQuery
+}
+
 // genChanRangeQuery generates the initial message we'll send to the remote
 // party when we're kicking off the channel graph synchronization upon
 // connection. The historicalQuery boolean can be used to generate a query from
@@ -755,11 +1073,21 @@ func (g *GossipSyncer) genChanRangeQuery(
 	// Finally, we'll craft the channel range query, using our starting
 	// height, then asking for all known channels to the foreseeable end of
 	// the main chain.
-	return &lnwire.QueryChannelRange{
+	query := &lnwire.QueryChannelRange{
 		ChainHash:        g.cfg.chainHash,
 		FirstBlockHeight: startHeight,
 		NumBlocks:        math.MaxUint32 - startHeight,
-	}, nil
+	}
+
+	// If the remote peer understands the extended gossip queries, we'll
+	// also request the state of its channel updates, allowing us to only
+	// query for those that changed since we last synced.
+	if g.cfg.extendedQueries {
+		query.QueryOptions = lnwire.QueryOptionTimestamps |
+			lnwire.QueryOptionChecksums
+	}
+
+	return query, nil
 }
 
 // replyPeerQueries is called in response to any query by the remote peer.
@@ -782,21 +1110,29 @@ func (g *GossipSyncer) replyPeerQueries(msg lnwire.Message) error {
 		}
 	}
 
+	var err error
 	switch msg := msg.(type) {
 
 	// In this state, we'll also handle any incoming channel range queries
 	// from the remote peer as they're trying to sync their state as well.
 	case *lnwire.QueryChannelRange:
-		return g.replyChanRangeQuery(msg)
+		err = g.replyChanRangeQuery(msg)
 
 	// If the remote peer skips straight to requesting new channels that
 	// they don't know of, then we'll ensure that we also handle this case.
 	case *lnwire.QueryShortChanIDs:
-		return g.replyShortChanIDs(msg)
+		err = g.replyShortChanIDs(msg)
 
 	default:
 		return fmt.Errorf("unknown message: %T", msg)
 	}
+	if err != nil {
+		return err
+	}
+
+	g.stats.recordQueryServed()
+
+	return nil
 }
 
 // replyChanRangeQuery will be dispatched in response to a channel range query
@@ -822,6 +1158,26 @@ func (g *GossipSyncer) replyChanRangeQuery(query *lnwire.QueryChannelRange) erro
 	// TODO(roasbeef): means can't send max uint above?
 	//  * or make internal 64
 
+	// If the remote peer requested the state of our channel updates, we'll
+	// need to reduce the size of each chunk to make room for it. As the
+	// extra data needs to line up with the short chan IDs once they're
+	// sorted on the wire, we'll also make sure they're sorted upfront.
+	wantTimestamps := query.QueryOptions.Has(lnwire.QueryOptionTimestamps)
+	wantChecksums := query.QueryOptions.Has(lnwire.QueryOptionChecksums)
+	extended := wantTimestamps || wantChecksums
+
+	chunkSize := g.cfg.chunkSize
+	if extended {
+		if chunkSize > extendedChunkSize {
+			chunkSize = extendedChunkSize
+		}
+
+		sort.Slice(channelRange, func(i, j int) bool {
+			return channelRange[i].ToUint64() <
+				channelRange[j].ToUint64()
+		})
+	}
+
 	numChannels := int32(len(channelRange))
 	numChansSent := int32(0)
 	for {
@@ -833,7 +1189,7 @@ func (g *GossipSyncer) replyChanRangeQuery(query *lnwire.QueryChannelRange) erro
 		// We know this is the final chunk, if the difference between
 		// the total number of channels, and the number of channels
 		// we've sent is less-than-or-equal to the chunk size.
-		isFinalChunk := (numChannels - numChansSent) <= g.cfg.chunkSize
+		isFinalChunk := (numChannels - numChansSent) <= chunkSize
 
 		// If this is indeed the last chunk, then we'll send the
 		// remainder of the channels.
@@ -846,7 +1202,7 @@ func (g *GossipSyncer) replyChanRangeQuery(query *lnwire.QueryChannelRange) erro
 		} else {
 			// Otherwise, we'll only send off a fragment exactly
 			// sized to the proper chunk size.
-			channelChunk = channelRange[numChansSent : numChansSent+g.cfg.chunkSize]
+			channelChunk = channelRange[numChansSent : numChansSent+chunkSize]
 
 			log.Infof("GossipSyncer(%x): sending range chunk of "+
 				"size=%v", g.cfg.peerPub[:], len(channelChunk))
@@ -863,6 +1219,35 @@ func (g *GossipSyncer) replyChanRangeQuery(query *lnwire.QueryChannelRange) erro
 		if isFinalChunk {
 			replyChunk.Complete = 1
 		}
+		if extended {
+			updateInfo, err := g.cfg.channelSeries.FetchChanUpdateInfo(
+				query.ChainHash, channelChunk,
+			)
+			if err != nil {
+				return err
+			}
+
+			if wantTimestamps {
+				replyChunk.Timestamps = make(
+					[]lnwire.ChanUpdateTimestamps,
+					len(updateInfo),
+				)
+			}
+			if wantChecksums {
+				replyChunk.Checksums = make(
+					[]lnwire.ChanUpdateChecksums,
+					len(updateInfo),
+				)
+			}
+			for i, info := range updateInfo {
+				if wantTimestamps {
+					replyChunk.Timestamps[i] = info.ChanUpdateTimestamps
+				}
+				if wantChecksums {
+					replyChunk.Checksums[i] = info.ChanUpdateChecksums
+				}
+			}
+		}
 		if err := g.cfg.sendToPeerSync(&replyChunk); err != nil {
 			return err
 		}
@@ -918,6 +1303,12 @@ func (g *GossipSyncer) replyShortChanIDs(query *lnwire.QueryShortChanIDs) error
 			query.ShortChanIDs[0].ToUint64(), err)
 	}
 
+	// If the remote peer signaled which announcements of each channel it
+	// is interested in, we'll filter out the rest.
+	if len(query.QueryFlags) == len(query.ShortChanIDs) {
+		replyMsgs = filterQueriedAnns(replyMsgs, query)
+	}
+
 	// Reply with any messages related to those channel ID's, we'll write
 	// each one individually and synchronously to throttle the sends and
 	// perform buffering of responses in the syncer as opposed to the peer.
@@ -936,6 +1327,70 @@ func (g *GossipSyncer) replyShortChanIDs(query *lnwire.QueryShortChanIDs) error
 	})
 }
 
+// filterQueriedAnns filters the set of announcements related to the channels
+// of a QueryShortChanIDs message, only keeping those requested through the
+// query flags of their channel.
+func filterQueriedAnns(msgs []lnwire.Message,
+	query *lnwire.QueryShortChanIDs) []lnwire.Message {
+
+	queryFlags := make(
+		map[lnwire.ShortChannelID]lnwire.QueryFlag,
+		len(query.ShortChanIDs),
+	)
+	for i, chanID := range query.ShortChanIDs {
+		queryFlags[chanID] = query.QueryFlags[i]
+	}
+
+	// As each node announcement is only included once, regardless of the
+	// number of channels the node takes part in, we'll first determine
+	// the set of nodes whose announcement was requested for any channel.
+	wantedNodes := make(map[[33]byte]struct{})
+	for _, msg := range msgs {
+		chanAnn, ok := msg.(*lnwire.ChannelAnnouncement)
+		if !ok {
+			continue
+		}
+
+		flags := queryFlags[chanAnn.ShortChannelID]
+		if flags.Has(lnwire.QueryFlagNodeAnn1) {
+			wantedNodes[chanAnn.NodeID1] = struct{}{}
+		}
+		if flags.Has(lnwire.QueryFlagNodeAnn2) {
+			wantedNodes[chanAnn.NodeID2] = struct{}{}
+		}
+	}
+
+	filtered := make([]lnwire.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		var wanted bool
+		switch msg := msg.(type) {
+		case *lnwire.ChannelAnnouncement:
+			flags := queryFlags[msg.ShortChannelID]
+			wanted = flags.Has(lnwire.QueryFlagChanAnn)
+
+		case *lnwire.ChannelUpdate:
+			flags := queryFlags[msg.ShortChannelID]
+			if msg.ChannelFlags&lnwire.ChanUpdateDirection == 0 {
+				wanted = flags.Has(lnwire.QueryFlagChanUpdate1)
+			} else {
+				wanted = flags.Has(lnwire.QueryFlagChanUpdate2)
+			}
+
+		case *lnwire.NodeAnnouncement:
+			_, wanted = wantedNodes[msg.NodeID]
+
+		default:
+			wanted = true
+		}
+
+		if wanted {
+			filtered = append(filtered, msg)
+		}
+	}
+
+	return filtered
+}
+
 // ApplyGossipFilter applies a gossiper filter sent by the remote node to the
 // state machine. Once applied, we'll ensure that we don't forward any messages
 // to the peer that aren't within the time range of the filter.
@@ -1211,7 +1666,7 @@ func (g *GossipSyncer) handleSyncTransition(req *syncTransitionReq) error {
 	// If an active sync has been requested, then we should resume receiving
 	// new graph updates from the remote peer.
 	case ActiveSync:
-		firstTimestamp = time.Now()
+		firstTimestamp = g.horizonStart()
 		timestampRange = math.MaxUint32
 
 	// If a PassiveSync transition has been requested, then we should no
@@ -1239,6 +1694,12 @@ func (g *GossipSyncer) handleSyncTransition(req *syncTransitionReq) error {
 
 // setSyncType sets the gossip syncer's sync type to the given type.
 func (g *GossipSyncer) setSyncType(syncType SyncerType) {
+	// Newly active syncers are given a full window to deliver graph
+	// updates before they're considered stale.
+	if syncType == ActiveSync {
+		g.recordGraphUpdate()
+	}
+
 	atomic.StoreUint32(&g.syncType, uint32(syncType))
 }
 
@@ -1247,6 +1708,24 @@ func (g *GossipSyncer) SyncType() SyncerType {
 	return SyncerType(atomic.LoadUint32(&g.syncType))
 }
 
+// recordGraphUpdate marks that a graph update has just been received from the
+// remote peer.
+func (g *GossipSyncer) recordGraphUpdate() {
+	atomic.StoreInt64(&g.lastGraphUpdate, time.Now().UnixNano())
+}
+
+// LastGraphUpdate returns the time at which the last graph update was received
+// from the remote peer.
+func (g *GossipSyncer) LastGraphUpdate() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&g.lastGraphUpdate))
+}
+
+// Stats returns a snapshot of the gossip traffic exchanged with the remote
+// peer.
+func (g *GossipSyncer) Stats() GossipSyncerStats {
+	return g.stats.snapshot()
+}
+
 // historicalSync sends a request to the gossip syncer to perofmr a historical
 // sync.
 //
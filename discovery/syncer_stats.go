@@ -0,0 +1,112 @@
+package discovery
+
+import (
+	"io/ioutil"
+	"sync/atomic"
+
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// GossipSyncerStats is a snapshot of the gossip traffic exchanged with a peer
+// over the lifetime of its GossipSyncer.
+type GossipSyncerStats struct {
+	// AnnouncementsReceived is the number of channel announcements,
+	// channel updates and node announcements received from the peer.
+	AnnouncementsReceived uint64
+
+	// UpdatesApplied is the number of announcements received from the
+	// peer that were new to us, and were applied to our graph.
+	UpdatesApplied uint64
+
+	// StaleUpdates is the number of announcements received from the peer
+	// that we already knew of, or a more recent version thereof.
+	StaleUpdates uint64
+
+	// QueriesServed is the number of gossip queries of the peer we've
+	// replied to.
+	QueriesServed uint64
+
+	// BytesSent is the number of bytes of gossip messages we've sent to
+	// the peer.
+	BytesSent uint64
+
+	// BytesReceived is the number of bytes of gossip messages we've
+	// received from the peer.
+	BytesReceived uint64
+}
+
+// syncerStats holds the counters from which a GossipSyncerStats snapshot is
+// created.
+//
+// NOTE: All fields MUST be used atomically.
+type syncerStats struct {
+	announcementsReceived uint64
+	updatesApplied        uint64
+	staleUpdates          uint64
+	queriesServed         uint64
+	bytesSent             uint64
+	bytesReceived         uint64
+}
+
+// recordSent records the given messages as sent to the peer.
+func (s *syncerStats) recordSent(msgs ...lnwire.Message) {
+	var numBytes uint64
+	for _, msg := range msgs {
+		numBytes += msgSize(msg)
+	}
+
+	atomic.AddUint64(&s.bytesSent, numBytes)
+}
+
+// recordReceived records the given message as received from the peer.
+func (s *syncerStats) recordReceived(msg lnwire.Message) {
+	switch msg.(type) {
+	case *lnwire.ChannelAnnouncement,
+		*lnwire.ChannelUpdate,
+		*lnwire.NodeAnnouncement:
+
+		atomic.AddUint64(&s.announcementsReceived, 1)
+	}
+
+	atomic.AddUint64(&s.bytesReceived, msgSize(msg))
+}
+
+// recordUpdate records the outcome of processing an announcement received
+// from the peer.
+func (s *syncerStats) recordUpdate(applied bool) {
+	if applied {
+		atomic.AddUint64(&s.updatesApplied, 1)
+	} else {
+		atomic.AddUint64(&s.staleUpdates, 1)
+	}
+}
+
+// recordQueryServed records that we've replied to a gossip query of the peer.
+func (s *syncerStats) recordQueryServed() {
+	atomic.AddUint64(&s.queriesServed, 1)
+}
+
+// snapshot returns the current value of all counters.
+func (s *syncerStats) snapshot() GossipSyncerStats {
+	return GossipSyncerStats{
+		AnnouncementsReceived: atomic.LoadUint64(
+			&s.announcementsReceived,
+		),
+		UpdatesApplied: atomic.LoadUint64(&s.updatesApplied),
+		StaleUpdates:   atomic.LoadUint64(&s.staleUpdates),
+		QueriesServed:  atomic.LoadUint64(&s.queriesServed),
+		BytesSent:      atomic.LoadUint64(&s.bytesSent),
+		BytesReceived:  atomic.LoadUint64(&s.bytesReceived),
+	}
+}
+
+// msgSize returns the size of the message on the wire, excluding the
+// transport's encryption overhead.
+func msgSize(msg lnwire.Message) uint64 {
+	n, err := lnwire.WriteMessage(ioutil.Discard, msg, 0)
+	if err != nil {
+		return 0
+	}
+
+	return uint64(n)
+}
@@ -0,0 +1,70 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/chaincfg"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestGossipSyncerStats asserts that the gossip traffic exchanged with a peer
+// is properly accounted for within the stats of its GossipSyncer.
+func TestGossipSyncerStats(t *testing.T) {
+	t.Parallel()
+
+	msgChan, syncer, _ := newTestSyncer(
+		lnwire.NewShortChanIDFromInt(10), defaultEncoding,
+		defaultChunkSize,
+	)
+
+	// We'll have the syncer reply to a query for an unknown chain, which
+	// results in a single ReplyShortChanIDsEnd message being sent.
+	err := syncer.replyPeerQueries(&lnwire.QueryShortChanIDs{
+		ChainHash: *chaincfg.SimNetParams.GenesisHash,
+	})
+	if err != nil {
+		t.Fatalf("unable to reply to query: %v", err)
+	}
+
+	var sent []lnwire.Message
+	select {
+	case sent = <-msgChan:
+	case <-time.After(time.Second):
+		t.Fatalf("no msgs received")
+	}
+
+	// Next, we'll account for a few messages received from the peer, only
+	// one of which is an announcement.
+	chanUpdate := &lnwire.ChannelUpdate{
+		ShortChannelID: lnwire.NewShortChanIDFromInt(20),
+		Timestamp:      unixStamp(999999),
+	}
+	horizon := &lnwire.GossipTimestampRange{
+		FirstTimestamp: unixStamp(25000),
+		TimestampRange: 1000,
+	}
+	syncer.stats.recordReceived(chanUpdate)
+	syncer.stats.recordReceived(horizon)
+
+	// Finally, we'll record the results of processing a few announcements.
+	syncer.stats.recordUpdate(true)
+	syncer.stats.recordUpdate(false)
+	syncer.stats.recordUpdate(false)
+
+	expected := GossipSyncerStats{
+		AnnouncementsReceived: 1,
+		UpdatesApplied:        1,
+		StaleUpdates:          2,
+		QueriesServed:         1,
+		BytesSent:             msgSize(sent[0]),
+		BytesReceived:         msgSize(chanUpdate) + msgSize(horizon),
+	}
+	if expected.BytesSent == 0 || expected.BytesReceived == 0 {
+		t.Fatalf("unable to determine message sizes")
+	}
+
+	if stats := syncer.Stats(); stats != expected {
+		t.Fatalf("expected stats %+v, got %+v", expected, stats)
+	}
+}
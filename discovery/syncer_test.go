@@ -1,8 +1,10 @@
 package discovery
 
 import (
+	"io"
 	"math"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -48,6 +50,9 @@ type mockChannelGraphTimeSeries struct {
 
 	updateReq  chan lnwire.ShortChannelID
 	updateResp chan []*lnwire.ChannelUpdate
+
+	updateInfoReq  chan []lnwire.ShortChannelID
+	updateInfoResp chan []ChanUpdateInfo
 }
 
 func newMockChannelGraphTimeSeries(
@@ -70,6 +75,9 @@ func newMockChannelGraphTimeSeries(
 
 		updateReq:  make(chan lnwire.ShortChannelID, 1),
 		updateResp: make(chan []*lnwire.ChannelUpdate, 1),
+
+		updateInfoReq:  make(chan []lnwire.ShortChannelID, 1),
+		updateInfoResp: make(chan []ChanUpdateInfo, 1),
 	}
 }
 
@@ -113,6 +121,25 @@ func (m *mockChannelGraphTimeSeries) FetchChanUpdates(chain chainhash.Hash,
 
 	return <-m.updateResp, nil
 }
+func (m *mockChannelGraphTimeSeries) FetchChanUpdateInfo(chain chainhash.Hash,
+	shortChanIDs []lnwire.ShortChannelID) ([]ChanUpdateInfo, error) {
+
+	m.updateInfoReq <- shortChanIDs
+
+	return <-m.updateInfoResp, nil
+}
+
+func (m *mockChannelGraphTimeSeries) ExportGraphSnapshot(chain chainhash.Hash,
+	w io.Writer) error {
+
+	return nil
+}
+
+func (m *mockChannelGraphTimeSeries) ImportGraphSnapshot(chain chainhash.Hash,
+	r io.Reader) ([]lnwire.Message, error) {
+
+	return nil, nil
+}
 
 var _ ChannelGraphTimeSeries = (*mockChannelGraphTimeSeries)(nil)
 
@@ -1803,6 +1830,93 @@ func TestGossipSyncerSyncTransitions(t *testing.T) {
 	}
 }
 
+// TestGossipSyncerGossipHorizon asserts that an active gossip syncer requests
+// graph updates reaching back as far as the configured gossip horizon, and that
+// it resends its update horizon whenever the gossip horizon changes.
+func TestGossipSyncerGossipHorizon(t *testing.T) {
+	t.Parallel()
+
+	msgChan, syncer, _ := newTestSyncer(
+		lnwire.ShortChannelID{BlockHeight: latestKnownHeight},
+		defaultEncoding, defaultChunkSize,
+	)
+
+	horizon := int64(24 * time.Hour)
+	syncer.cfg.gossipHorizon = func() time.Duration {
+		return time.Duration(atomic.LoadInt64(&horizon))
+	}
+
+	assertHorizonSent := func(firstTimestamp time.Time) {
+		t.Helper()
+
+		var msgs []lnwire.Message
+		select {
+		case msgs = <-msgChan:
+		case <-time.After(time.Second):
+			t.Fatalf("expected update horizon to be sent")
+		}
+
+		filter, ok := msgs[0].(*lnwire.GossipTimestampRange)
+		if !ok {
+			t.Fatalf("expected GossipTimestampRange, got %T",
+				msgs[0])
+		}
+		if filter.TimestampRange != math.MaxUint32 {
+			t.Fatalf("expected timestamp range %d, got %d",
+				uint32(math.MaxUint32), filter.TimestampRange)
+		}
+
+		// As time passes while sending the horizon, we'll allow for a
+		// small difference in the first timestamp.
+		diff := int64(filter.FirstTimestamp) - firstTimestamp.Unix()
+		if diff < -5 || diff > 5 {
+			t.Fatalf("expected first timestamp %v, got %v",
+				firstTimestamp.Unix(), filter.FirstTimestamp)
+		}
+	}
+
+	// Once started as an active syncer, the syncer should request all
+	// graph updates within the configured gossip horizon.
+	syncer.setSyncState(chansSynced)
+	syncer.setSyncType(ActiveSync)
+	syncer.Start()
+	defer syncer.Stop()
+
+	assertHorizonSent(time.Now().Add(-24 * time.Hour))
+
+	// Extending the gossip horizon beyond the unix epoch should have the
+	// syncer request the full history of the remote peer's graph.
+	atomic.StoreInt64(&horizon, math.MaxInt64)
+	syncer.UpdateGossipHorizon()
+	assertHorizonSent(time.Unix(0, 0))
+
+	// Passive syncers don't receive any graph updates, so they shouldn't
+	// resend their horizon once the gossip horizon changes.
+	if err := syncer.ProcessSyncTransition(PassiveSync); err != nil {
+		t.Fatalf("unable to transition syncer: %v", err)
+	}
+	select {
+	case <-msgChan:
+	case <-time.After(time.Second):
+		t.Fatalf("expected update horizon to be sent")
+	}
+
+	atomic.StoreInt64(&horizon, 0)
+	syncer.UpdateGossipHorizon()
+	select {
+	case msgs := <-msgChan:
+		t.Fatalf("expected no msgs, got %v", spew.Sdump(msgs))
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Once active again, the syncer should only request new graph
+	// updates.
+	if err := syncer.ProcessSyncTransition(ActiveSync); err != nil {
+		t.Fatalf("unable to transition syncer: %v", err)
+	}
+	assertHorizonSent(time.Now())
+}
+
 // TestGossipSyncerHistoricalSync tests that a gossip syncer can perform a
 // historical sync with the remote peer.
 func TestGossipSyncerHistoricalSync(t *testing.T) {
@@ -1895,3 +2009,315 @@ func TestGossipSyncerSyncedSignal(t *testing.T) {
 		t.Fatal("expected to receive chansSynced signal")
 	}
 }
+
+// TestGossipSyncerExtendedChanRangeQuery tests that if the remote peer requests
+// the state of our channel updates within its channel range query, we include
+// their timestamps and checksums within our replies.
+func TestGossipSyncerExtendedChanRangeQuery(t *testing.T) {
+	t.Parallel()
+
+	msgChan, syncer, chanSeries := newTestSyncer(
+		lnwire.NewShortChanIDFromInt(10), defaultEncoding,
+		defaultChunkSize,
+	)
+
+	query := &lnwire.QueryChannelRange{
+		FirstBlockHeight: 100,
+		NumBlocks:        50,
+		QueryOptions: lnwire.QueryOptionTimestamps |
+			lnwire.QueryOptionChecksums,
+	}
+
+	// We'll reply to the range filter with an unsorted set of channels, as
+	// the syncer should sort them in order for the state of their updates
+	// to line up with them on the wire.
+	chanIDs := []lnwire.ShortChannelID{
+		lnwire.NewShortChanIDFromInt(2),
+		lnwire.NewShortChanIDFromInt(1),
+	}
+	sortedChanIDs := []lnwire.ShortChannelID{
+		lnwire.NewShortChanIDFromInt(1),
+		lnwire.NewShortChanIDFromInt(2),
+	}
+	updateInfo := []ChanUpdateInfo{
+		{
+			ChanUpdateTimestamps: lnwire.ChanUpdateTimestamps{
+				Timestamp1: 100, Timestamp2: 200,
+			},
+			ChanUpdateChecksums: lnwire.ChanUpdateChecksums{
+				Checksum1: 1, Checksum2: 2,
+			},
+		},
+		{
+			ChanUpdateTimestamps: lnwire.ChanUpdateTimestamps{
+				Timestamp1: 300,
+			},
+			ChanUpdateChecksums: lnwire.ChanUpdateChecksums{
+				Checksum1: 3,
+			},
+		},
+	}
+	go func() {
+		select {
+		case <-time.After(time.Second * 15):
+			t.Fatalf("no query recvd")
+		case <-chanSeries.filterRangeReqs:
+			chanSeries.filterRangeResp <- chanIDs
+		}
+
+		select {
+		case <-time.After(time.Second * 15):
+			t.Fatalf("no update info query recvd")
+		case req := <-chanSeries.updateInfoReq:
+			if !reflect.DeepEqual(req, sortedChanIDs) {
+				t.Fatalf("wrong request: expected %v, got %v",
+					sortedChanIDs, req)
+			}
+			chanSeries.updateInfoResp <- updateInfo
+		}
+	}()
+
+	if err := syncer.replyChanRangeQuery(query); err != nil {
+		t.Fatalf("unable to issue query: %v", err)
+	}
+
+	select {
+	case <-time.After(time.Second * 15):
+		t.Fatalf("no msgs received")
+
+	case msg := <-msgChan:
+		rangeResp, ok := msg[0].(*lnwire.ReplyChannelRange)
+		if !ok {
+			t.Fatalf("expected ReplyChannelRange instead got %T",
+				msg[0])
+		}
+
+		if !reflect.DeepEqual(rangeResp.ShortChanIDs, sortedChanIDs) {
+			t.Fatalf("wrong chan ids: expected %v, got %v",
+				sortedChanIDs, rangeResp.ShortChanIDs)
+		}
+		for i, info := range updateInfo {
+			if rangeResp.Timestamps[i] != info.ChanUpdateTimestamps {
+				t.Fatalf("wrong timestamps: expected %v, got %v",
+					info.ChanUpdateTimestamps,
+					rangeResp.Timestamps[i])
+			}
+			if rangeResp.Checksums[i] != info.ChanUpdateChecksums {
+				t.Fatalf("wrong checksums: expected %v, got %v",
+					info.ChanUpdateChecksums,
+					rangeResp.Checksums[i])
+			}
+		}
+	}
+}
+
+// TestGossipSyncerProcessExtendedChanRangeReply tests that if the remote peer
+// includes the state of its channel updates within its channel range replies,
+// we'll query it for the channels we don't know of, along with the newer
+// channel updates of the channels we do know of.
+func TestGossipSyncerProcessExtendedChanRangeReply(t *testing.T) {
+	t.Parallel()
+
+	msgChan, syncer, chanSeries := newTestSyncer(
+		lnwire.NewShortChanIDFromInt(10), defaultEncoding,
+		defaultChunkSize,
+	)
+	syncer.cfg.extendedQueries = true
+
+	now := uint32(time.Now().Unix())
+	recent := now - 100
+	stale := now - uint32((chanUpdateRefreshAge+time.Hour)/time.Second)
+
+	var (
+		newChan     = lnwire.NewShortChanIDFromInt(1)
+		changedChan = lnwire.NewShortChanIDFromInt(2)
+		refreshChan = lnwire.NewShortChanIDFromInt(3)
+		staleChan   = lnwire.NewShortChanIDFromInt(4)
+	)
+
+	// Our view of the channels we already know of. The remote peer has a
+	// different, newer update for the first direction of changedChan, and
+	// newer updates that only refresh our own for both directions of
+	// refreshChan and the second direction of staleChan.
+	localInfo := map[lnwire.ShortChannelID]ChanUpdateInfo{
+		changedChan: {
+			ChanUpdateTimestamps: lnwire.ChanUpdateTimestamps{
+				Timestamp1: recent, Timestamp2: recent,
+			},
+			ChanUpdateChecksums: lnwire.ChanUpdateChecksums{
+				Checksum1: 1, Checksum2: 2,
+			},
+		},
+		refreshChan: {
+			ChanUpdateTimestamps: lnwire.ChanUpdateTimestamps{
+				Timestamp1: recent, Timestamp2: recent,
+			},
+			ChanUpdateChecksums: lnwire.ChanUpdateChecksums{
+				Checksum1: 3, Checksum2: 4,
+			},
+		},
+		staleChan: {
+			ChanUpdateTimestamps: lnwire.ChanUpdateTimestamps{
+				Timestamp1: recent, Timestamp2: stale,
+			},
+			ChanUpdateChecksums: lnwire.ChanUpdateChecksums{
+				Checksum1: 5, Checksum2: 6,
+			},
+		},
+	}
+
+	reply := &lnwire.ReplyChannelRange{
+		Complete: 1,
+		ShortChanIDs: []lnwire.ShortChannelID{
+			newChan, changedChan, refreshChan, staleChan,
+		},
+		Timestamps: []lnwire.ChanUpdateTimestamps{
+			{Timestamp1: now, Timestamp2: now},
+			{Timestamp1: now, Timestamp2: recent},
+			{Timestamp1: now, Timestamp2: now},
+			{Timestamp1: recent - 1, Timestamp2: now},
+		},
+		Checksums: []lnwire.ChanUpdateChecksums{
+			{Checksum1: 7, Checksum2: 8},
+			{Checksum1: 9, Checksum2: 2},
+			{Checksum1: 3, Checksum2: 4},
+			{Checksum1: 10, Checksum2: 6},
+		},
+	}
+
+	go func() {
+		select {
+		case <-time.After(time.Second * 15):
+			t.Fatalf("no query recvd")
+		case <-chanSeries.filterReq:
+			chanSeries.filterResp <- []lnwire.ShortChannelID{
+				newChan,
+			}
+		}
+
+		select {
+		case <-time.After(time.Second * 15):
+			t.Fatalf("no update info query recvd")
+		case req := <-chanSeries.updateInfoReq:
+			if len(req) != len(localInfo) {
+				t.Fatalf("expected query for %d chans, got %d",
+					len(localInfo), len(req))
+			}
+
+			resp := make([]ChanUpdateInfo, 0, len(req))
+			for _, chanID := range req {
+				info, ok := localInfo[chanID]
+				if !ok {
+					t.Fatalf("unexpected query for %v",
+						chanID)
+				}
+				resp = append(resp, info)
+			}
+			chanSeries.updateInfoResp <- resp
+		}
+	}()
+
+	if err := syncer.processChanRangeReply(reply); err != nil {
+		t.Fatalf("unable to process reply: %v", err)
+	}
+
+	if syncer.syncState() != queryNewChannels {
+		t.Fatalf("wrong state: expected %v instead got %v",
+			queryNewChannels, syncer.syncState())
+	}
+
+	// We should only query for the new channel, along with the newer
+	// updates of the changed and stale channel.
+	if _, err := syncer.synchronizeChanIDs(); err != nil {
+		t.Fatalf("unable to sync chan IDs: %v", err)
+	}
+
+	expectedChans := []lnwire.ShortChannelID{
+		newChan, changedChan, staleChan,
+	}
+	expectedFlags := []lnwire.QueryFlag{
+		lnwire.QueryFlagAll, lnwire.QueryFlagChanUpdate1,
+		lnwire.QueryFlagChanUpdate2,
+	}
+	select {
+	case <-time.After(time.Second * 15):
+		t.Fatalf("no msgs received")
+
+	case msg := <-msgChan:
+		queryMsg, ok := msg[0].(*lnwire.QueryShortChanIDs)
+		if !ok {
+			t.Fatalf("expected QueryShortChanIDs instead got %T",
+				msg[0])
+		}
+
+		if !reflect.DeepEqual(queryMsg.ShortChanIDs, expectedChans) {
+			t.Fatalf("wrong query: expected %v, got %v",
+				expectedChans, queryMsg.ShortChanIDs)
+		}
+		if !reflect.DeepEqual(queryMsg.QueryFlags, expectedFlags) {
+			t.Fatalf("wrong query flags: expected %v, got %v",
+				expectedFlags, queryMsg.QueryFlags)
+		}
+	}
+}
+
+// TestGossipSyncerFilterQueriedAnns tests that we only reply with the
+// announcements of each channel requested through the query flags of a
+// QueryShortChanIDs message.
+func TestGossipSyncerFilterQueriedAnns(t *testing.T) {
+	t.Parallel()
+
+	var nodeA, nodeB, nodeC [33]byte
+	nodeA[0], nodeB[0], nodeC[0] = 1, 2, 3
+
+	chanID1 := lnwire.NewShortChanIDFromInt(1)
+	chanID2 := lnwire.NewShortChanIDFromInt(2)
+
+	chanAnn1 := &lnwire.ChannelAnnouncement{
+		ShortChannelID: chanID1,
+		NodeID1:        nodeA,
+		NodeID2:        nodeB,
+	}
+	chanUpd1a := &lnwire.ChannelUpdate{
+		ShortChannelID: chanID1,
+	}
+	chanUpd1b := &lnwire.ChannelUpdate{
+		ShortChannelID: chanID1,
+		ChannelFlags:   lnwire.ChanUpdateDirection,
+	}
+	nodeAnnA := &lnwire.NodeAnnouncement{NodeID: nodeA}
+	nodeAnnB := &lnwire.NodeAnnouncement{NodeID: nodeB}
+	chanAnn2 := &lnwire.ChannelAnnouncement{
+		ShortChannelID: chanID2,
+		NodeID1:        nodeB,
+		NodeID2:        nodeC,
+	}
+	chanUpd2a := &lnwire.ChannelUpdate{
+		ShortChannelID: chanID2,
+	}
+	nodeAnnC := &lnwire.NodeAnnouncement{NodeID: nodeC}
+
+	msgs := []lnwire.Message{
+		chanAnn1, chanUpd1a, nodeAnnA, chanUpd1b, nodeAnnB,
+		chanAnn2, chanUpd2a, nodeAnnC,
+	}
+
+	// For the first channel, we're only interested in the update of the
+	// second direction, while we'll request the announcements of both
+	// nodes of the second channel. As the announcement of nodeB is only
+	// included alongside the first channel, it should still be sent.
+	query := &lnwire.QueryShortChanIDs{
+		ShortChanIDs: []lnwire.ShortChannelID{chanID1, chanID2},
+		QueryFlags: []lnwire.QueryFlag{
+			lnwire.QueryFlagChanUpdate2,
+			lnwire.QueryFlagNodeAnn1 | lnwire.QueryFlagNodeAnn2,
+		},
+	}
+
+	expected := []lnwire.Message{chanUpd1b, nodeAnnB, nodeAnnC}
+	filtered := filterQueriedAnns(msgs, query)
+	if !reflect.DeepEqual(filtered, expected) {
+		t.Fatalf("wrong filtered msgs: expected %v, got %v",
+			spew.Sdump(expected), spew.Sdump(filtered))
+	}
+}
@@ -0,0 +1,86 @@
+package lnd
+
+import (
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcutil"
+)
+
+const (
+	// dualFundAcceptTimeout is the time we'll wait for a client of the
+	// DualFundAcceptor RPC to pick up, and respond to, a request to
+	// contribute funds to a channel, before declining it.
+	dualFundAcceptTimeout = time.Minute
+)
+
+// dualFundRequest is a request of a peer for us to contribute funds to a new
+// dual funded channel.
+type dualFundRequest struct {
+	// nodePubKey is the identity key of the peer.
+	nodePubKey *btcec.PublicKey
+
+	// pendingChanID is the pending channel ID of the funding flow.
+	pendingChanID [32]byte
+
+	// capacity is the total capacity of the proposed channel.
+	capacity btcutil.Amount
+
+	// localFundingAmt is the amount we're requested to contribute.
+	localFundingAmt btcutil.Amount
+
+	// remoteFundingAmt is the amount the peer contributes.
+	remoteFundingAmt btcutil.Amount
+
+	// resp is the channel over which it's signalled whether the request
+	// was accepted.
+	//
+	// NOTE: This channel MUST be buffered.
+	resp chan bool
+}
+
+// dualFundAcceptor hands inbound dual funding requests to the clients of the
+// DualFundAcceptor RPC, who decide whether we contribute to the channel. As
+// committing our funds is never done without consent, requests are declined
+// if no client picks them up, or responds, in time.
+type dualFundAcceptor struct {
+	requests chan *dualFundRequest
+	timeout  time.Duration
+	quit     <-chan struct{}
+}
+
+// newDualFundAcceptor creates a new dualFundAcceptor which gives its clients
+// the passed timeout to decide on each request.
+func newDualFundAcceptor(timeout time.Duration,
+	quit <-chan struct{}) *dualFundAcceptor {
+
+	return &dualFundAcceptor{
+		requests: make(chan *dualFundRequest),
+		timeout:  timeout,
+		quit:     quit,
+	}
+}
+
+// Accept blocks until a client decided on the given request, returning
+// whether it was accepted.
+func (a *dualFundAcceptor) Accept(req *dualFundRequest) bool {
+	req.resp = make(chan bool, 1)
+	timeout := time.After(a.timeout)
+
+	select {
+	case a.requests <- req:
+	case <-timeout:
+		return false
+	case <-a.quit:
+		return false
+	}
+
+	select {
+	case accept := <-req.resp:
+		return accept
+	case <-timeout:
+		return false
+	case <-a.quit:
+		return false
+	}
+}
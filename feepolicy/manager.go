@@ -0,0 +1,250 @@
+package feepolicy
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/ticker"
+)
+
+// DefaultInterval is the default interval at which the schedules of our
+// channels are evaluated.
+const DefaultInterval = 10 * time.Minute
+
+// ChannelBalance describes the balance of one of our channels.
+type ChannelBalance struct {
+	// ChanID is the short channel id of the channel.
+	ChanID uint64
+
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// Capacity is the total capacity of the channel.
+	Capacity btcutil.Amount
+
+	// LocalBalance is the part of the capacity held on our side.
+	LocalBalance btcutil.Amount
+}
+
+// Config houses the dependencies of the Manager.
+type Config struct {
+	// Store persists the fee schedules of our channels.
+	Store Store
+
+	// FetchChannels returns the balances of all of our open channels.
+	FetchChannels func() ([]*ChannelBalance, error)
+
+	// UpdatePolicy applies the given policy to the channel with the given
+	// funding outpoint, and announces it to the network.
+	UpdatePolicy func(chanPoint wire.OutPoint, policy *Policy) error
+
+	// Ticker is used to periodically evaluate the schedules of our
+	// channels.
+	Ticker ticker.Ticker
+
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+// Manager enforces the fee schedules of our channels. Each time its ticker
+// fires, the schedule of each channel is evaluated against the channel's
+// current local balance and the time of day, and a new policy is announced
+// for any channel whose scheduled policy changed.
+type Manager struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *Config
+
+	// applied maps the short channel ids of the channels with a schedule
+	// to the policy last applied to them.
+	applied map[uint64]Policy
+
+	// mu guards applied, and serializes evaluations of the schedules.
+	mu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new fee policy manager backed by the given config.
+func New(cfg *Config) *Manager {
+	return &Manager{
+		cfg:     cfg,
+		applied: make(map[uint64]Policy),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine responsible for evaluating the schedules.
+func (m *Manager) Start() error {
+	if !atomic.CompareAndSwapUint32(&m.started, 0, 1) {
+		return nil
+	}
+
+	log.Info("Fee policy manager starting")
+
+	m.cfg.Ticker.Resume()
+
+	m.wg.Add(1)
+	go m.scheduleHandler()
+
+	return nil
+}
+
+// Stop signals the manager for a graceful shutdown.
+func (m *Manager) Stop() error {
+	if !atomic.CompareAndSwapUint32(&m.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Info("Fee policy manager shutting down")
+
+	close(m.quit)
+	m.cfg.Ticker.Stop()
+	m.wg.Wait()
+
+	return nil
+}
+
+// SetSchedule adds or replaces the schedule of a channel. The policy selected
+// by the schedule is applied right away.
+func (m *Manager) SetSchedule(schedule *Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.cfg.Store.SetSchedule(schedule); err != nil {
+		return err
+	}
+
+	log.Infof("Set fee schedule of channel %v with %d rules",
+		lnwire.NewShortChanIDFromInt(schedule.ChanID),
+		len(schedule.Rules))
+
+	// The policy of the channel may have been changed manually since we
+	// last applied one, so we'll apply the new schedule's policy even if
+	// it's unchanged.
+	delete(m.applied, schedule.ChanID)
+	m.enforce()
+
+	return nil
+}
+
+// RemoveSchedule removes the schedule of the channel with the given short
+// channel id. The policy last applied to the channel remains in effect.
+func (m *Manager) RemoveSchedule(chanID uint64) error {
+	if err := m.cfg.Store.RemoveSchedule(chanID); err != nil {
+		return err
+	}
+
+	log.Infof("Removed fee schedule of channel %v",
+		lnwire.NewShortChanIDFromInt(chanID))
+
+	m.mu.Lock()
+	delete(m.applied, chanID)
+	m.mu.Unlock()
+
+	return nil
+}
+
+// ListSchedules returns the schedules of all channels that have one.
+func (m *Manager) ListSchedules() ([]*Schedule, error) {
+	return m.cfg.Store.FetchSchedules()
+}
+
+// scheduleHandler is the main event loop of the manager. Each time the ticker
+// fires, the schedules of our channels are evaluated.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) scheduleHandler() {
+	defer m.wg.Done()
+
+	// The policies of our channels may have been changed while we were
+	// offline, so we'll evaluate the schedules right away.
+	m.enforceSchedules()
+
+	for {
+		select {
+		case <-m.cfg.Ticker.Ticks():
+			m.enforceSchedules()
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// enforceSchedules applies the policy selected by the schedule of each of our
+// channels, unless it's already in effect.
+func (m *Manager) enforceSchedules() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.enforce()
+}
+
+// enforce applies the policy selected by the schedule of each of our channels,
+// unless it's already in effect.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *Manager) enforce() {
+	schedules, err := m.cfg.Store.FetchSchedules()
+	if err != nil {
+		log.Errorf("Unable to fetch fee schedules: %v", err)
+		return
+	}
+	if len(schedules) == 0 {
+		return
+	}
+
+	channels, err := m.cfg.FetchChannels()
+	if err != nil {
+		log.Errorf("Unable to fetch channels: %v", err)
+		return
+	}
+
+	balances := make(map[uint64]*ChannelBalance, len(channels))
+	for _, channel := range channels {
+		balances[channel.ChanID] = channel
+	}
+
+	now := m.cfg.Now()
+	for _, schedule := range schedules {
+		channel, ok := balances[schedule.ChanID]
+		if !ok || channel.Capacity == 0 {
+			continue
+		}
+
+		localRatio := float64(channel.LocalBalance) /
+			float64(channel.Capacity)
+		policy := schedule.PolicyAt(localRatio, now)
+
+		applied, ok := m.applied[schedule.ChanID]
+		if ok && applied == policy {
+			continue
+		}
+
+		chanID := lnwire.NewShortChanIDFromInt(schedule.ChanID)
+		log.Infof("Updating policy of channel %v with local ratio "+
+			"%.2f: base_fee=%v, fee_rate=%v, time_lock_delta=%v",
+			chanID, localRatio, policy.BaseFee, policy.FeeRate,
+			policy.TimeLockDelta)
+
+		err := m.cfg.UpdatePolicy(channel.ChanPoint, &policy)
+		if err != nil {
+			log.Errorf("Unable to update policy of channel %v: %v",
+				chanID, err)
+			continue
+		}
+
+		m.applied[schedule.ChanID] = policy
+	}
+}
@@ -0,0 +1,243 @@
+package feepolicy
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/ticker"
+)
+
+var (
+	defaultPolicy = Policy{
+		BaseFee:       1000,
+		FeeRate:       1,
+		TimeLockDelta: 40,
+	}
+	depletedPolicy = Policy{
+		BaseFee:       1000,
+		FeeRate:       1000,
+		TimeLockDelta: 40,
+	}
+	nightPolicy = Policy{
+		FeeRate:       10,
+		TimeLockDelta: 40,
+	}
+)
+
+// policyUpdate is a policy applied to one of our channels.
+type policyUpdate struct {
+	chanPoint wire.OutPoint
+	policy    Policy
+}
+
+type managerTestContext struct {
+	t *testing.T
+
+	manager *Manager
+	ticker  *ticker.Force
+
+	mu       sync.Mutex
+	channels []*ChannelBalance
+	now      time.Time
+
+	updates chan *policyUpdate
+
+	cleanUp func()
+}
+
+func newManagerTestContext(t *testing.T) *managerTestContext {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to open channel db: %v", err)
+	}
+
+	store, err := NewStore(cdb)
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to create store: %v", err)
+	}
+
+	ctx := &managerTestContext{
+		t:      t,
+		ticker: ticker.NewForce(DefaultInterval),
+		channels: []*ChannelBalance{{
+			ChanID:       1,
+			ChanPoint:    wire.OutPoint{Index: 1},
+			Capacity:     100000,
+			LocalBalance: 50000,
+		}},
+		now:     time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC),
+		updates: make(chan *policyUpdate, 10),
+	}
+
+	ctx.manager = New(&Config{
+		Store: store,
+		FetchChannels: func() ([]*ChannelBalance, error) {
+			ctx.mu.Lock()
+			defer ctx.mu.Unlock()
+
+			return ctx.channels, nil
+		},
+		UpdatePolicy: func(chanPoint wire.OutPoint,
+			policy *Policy) error {
+
+			ctx.updates <- &policyUpdate{
+				chanPoint: chanPoint,
+				policy:    *policy,
+			}
+			return nil
+		},
+		Ticker: ctx.ticker,
+		Now: func() time.Time {
+			ctx.mu.Lock()
+			defer ctx.mu.Unlock()
+
+			return ctx.now
+		},
+	})
+	if err := ctx.manager.Start(); err != nil {
+		cleanUp()
+		t.Fatalf("unable to start manager: %v", err)
+	}
+
+	ctx.cleanUp = func() {
+		ctx.manager.Stop()
+		cleanUp()
+	}
+
+	return ctx
+}
+
+// setLocalBalance sets the local balance of the test channel.
+func (c *managerTestContext) setLocalBalance(balance btcutil.Amount) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.channels[0].LocalBalance = balance
+}
+
+// setHour sets the hour of the day reported to the manager.
+func (c *managerTestContext) setHour(hour int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = time.Date(2019, 1, 1, hour, 0, 0, 0, time.UTC)
+}
+
+// tick forces the manager to evaluate the schedules. As the ticker is
+// unbuffered, the tick is only accepted once the previous one has been fully
+// processed.
+func (c *managerTestContext) tick() {
+	select {
+	case c.ticker.Force <- time.Now():
+	case <-time.After(5 * time.Second):
+		c.t.Fatalf("manager didn't accept tick")
+	}
+}
+
+// assertUpdate asserts that the given policy is applied to the test channel.
+func (c *managerTestContext) assertUpdate(expected Policy) {
+	c.t.Helper()
+
+	select {
+	case update := <-c.updates:
+		if update.chanPoint != c.channels[0].ChanPoint {
+			c.t.Fatalf("expected update of %v, got %v",
+				c.channels[0].ChanPoint, update.chanPoint)
+		}
+		if update.policy != expected {
+			c.t.Fatalf("expected policy %v, got %v", expected,
+				update.policy)
+		}
+
+	case <-time.After(5 * time.Second):
+		c.t.Fatalf("expected policy update")
+	}
+}
+
+// assertNoUpdate asserts that no policy has been applied.
+func (c *managerTestContext) assertNoUpdate() {
+	c.t.Helper()
+
+	select {
+	case update := <-c.updates:
+		c.t.Fatalf("unexpected policy update: %v", update.policy)
+	default:
+	}
+}
+
+// TestManagerEnforceSchedule asserts that the policy selected by a schedule is
+// applied whenever it changes due to the local balance of the channel or the
+// time of day.
+func TestManagerEnforceSchedule(t *testing.T) {
+	t.Parallel()
+
+	ctx := newManagerTestContext(t)
+	defer ctx.cleanUp()
+
+	err := ctx.manager.SetSchedule(&Schedule{
+		ChanID: 1,
+		Rules:  []Rule{{BelowLocalRatio: 2}},
+	})
+	if err == nil {
+		t.Fatalf("expected invalid ratio to be rejected")
+	}
+
+	// Fees are raised while less than 20% of the capacity is on our side,
+	// and lowered at night.
+	err = ctx.manager.SetSchedule(&Schedule{
+		ChanID:  1,
+		Default: defaultPolicy,
+		Rules: []Rule{
+			{
+				BelowLocalRatio: 0.2,
+				Policy:          depletedPolicy,
+			},
+			{
+				StartHour: 22,
+				EndHour:   6,
+				Policy:    nightPolicy,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to set schedule: %v", err)
+	}
+
+	// The scheduled policy should be applied right away, but not again as
+	// long as it doesn't change.
+	ctx.assertUpdate(defaultPolicy)
+	ctx.tick()
+	ctx.tick()
+	ctx.assertNoUpdate()
+
+	ctx.setLocalBalance(10000)
+	ctx.tick()
+	ctx.assertUpdate(depletedPolicy)
+
+	// The balance rule takes precedence over the night rule.
+	ctx.setHour(23)
+	ctx.tick()
+	ctx.tick()
+	ctx.assertNoUpdate()
+
+	ctx.setLocalBalance(50000)
+	ctx.tick()
+	ctx.assertUpdate(nightPolicy)
+
+	ctx.setHour(6)
+	ctx.tick()
+	ctx.assertUpdate(defaultPolicy)
+
+	// Once the schedule is removed, the channel keeps its last policy.
+	if err := ctx.manager.RemoveSchedule(1); err != nil {
+		t.Fatalf("unable to remove schedule: %v", err)
+	}
+	ctx.setLocalBalance(10000)
+	ctx.tick()
+	ctx.tick()
+	ctx.assertNoUpdate()
+}
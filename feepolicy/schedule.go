@@ -0,0 +1,209 @@
+package feepolicy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// ErrTooManyRules is returned when a schedule with more than MaxRules rules is
+// set.
+var ErrTooManyRules = errors.New("too many rules in fee schedule")
+
+// MaxRules is the maximum number of rules a schedule may contain.
+const MaxRules = 32
+
+// Policy is the routing policy we advertise for one of our channels.
+type Policy struct {
+	// BaseFee is the base fee charged for every HTLC forwarded through
+	// the channel.
+	BaseFee lnwire.MilliSatoshi
+
+	// FeeRate is the fee charged in proportion to the amount forwarded,
+	// expressed in parts per million.
+	FeeRate uint32
+
+	// TimeLockDelta is the difference we require between the time locks
+	// of incoming HTLCs and the HTLCs we forward through the channel.
+	TimeLockDelta uint32
+}
+
+// Rule selects a policy for a channel while all of its conditions hold. A rule
+// without any conditions always applies.
+type Rule struct {
+	// BelowLocalRatio, if non-zero, restricts the rule to the times the
+	// fraction of the channel's capacity held on our side is below it.
+	BelowLocalRatio float64
+
+	// AboveLocalRatio, if non-zero, restricts the rule to the times the
+	// fraction of the channel's capacity held on our side is above it.
+	AboveLocalRatio float64
+
+	// StartHour and EndHour restrict the rule to the hours of the day,
+	// in UTC, from StartHour up to but excluding EndHour. If StartHour is
+	// greater than EndHour, the period wraps around midnight. If both are
+	// equal, the rule applies at any time of the day.
+	StartHour uint32
+	EndHour   uint32
+
+	// Policy is the policy applied to the channel while the rule holds.
+	Policy Policy
+}
+
+// matches returns whether the conditions of the rule hold for a channel with
+// the given local balance ratio at the given time.
+func (r *Rule) matches(localRatio float64, now time.Time) bool {
+	if r.BelowLocalRatio != 0 && localRatio >= r.BelowLocalRatio {
+		return false
+	}
+	if r.AboveLocalRatio != 0 && localRatio <= r.AboveLocalRatio {
+		return false
+	}
+
+	hour := uint32(now.UTC().Hour())
+	switch {
+	case r.StartHour < r.EndHour:
+		return hour >= r.StartHour && hour < r.EndHour
+
+	case r.StartHour > r.EndHour:
+		return hour >= r.StartHour || hour < r.EndHour
+
+	default:
+		return true
+	}
+}
+
+// Schedule determines the policy of a channel based on its local balance and
+// the time of day.
+type Schedule struct {
+	// ChanID is the short channel id of the channel.
+	ChanID uint64
+
+	// Default is the policy applied while none of the rules hold.
+	Default Policy
+
+	// Rules are the rules of the schedule. If several rules hold at once,
+	// the first one is applied.
+	Rules []Rule
+}
+
+// Validate checks that the ratios and hours of all rules are within range.
+func (s *Schedule) Validate() error {
+	if len(s.Rules) > MaxRules {
+		return ErrTooManyRules
+	}
+
+	for i, rule := range s.Rules {
+		switch {
+		case rule.BelowLocalRatio < 0 || rule.BelowLocalRatio > 1:
+			return fmt.Errorf("rule %d: local ratio bound %v must "+
+				"be between 0 and 1", i, rule.BelowLocalRatio)
+
+		case rule.AboveLocalRatio < 0 || rule.AboveLocalRatio > 1:
+			return fmt.Errorf("rule %d: local ratio bound %v must "+
+				"be between 0 and 1", i, rule.AboveLocalRatio)
+
+		case rule.StartHour > 23 || rule.EndHour > 23:
+			return fmt.Errorf("rule %d: hours must be between 0 "+
+				"and 23", i)
+		}
+	}
+
+	return nil
+}
+
+// PolicyAt returns the policy of the channel when the given fraction of its
+// capacity is held on our side at the given time.
+func (s *Schedule) PolicyAt(localRatio float64, now time.Time) Policy {
+	for _, rule := range s.Rules {
+		if rule.matches(localRatio, now) {
+			return rule.Policy
+		}
+	}
+
+	return s.Default
+}
+
+// serializePolicy writes the serialized policy to the passed writer.
+func serializePolicy(w io.Writer, p *Policy) error {
+	return channeldb.WriteElements(w, p.BaseFee, p.FeeRate, p.TimeLockDelta)
+}
+
+// deserializePolicy reads a policy serialized by serializePolicy from the
+// passed reader.
+func deserializePolicy(r io.Reader, p *Policy) error {
+	return channeldb.ReadElements(r, &p.BaseFee, &p.FeeRate,
+		&p.TimeLockDelta)
+}
+
+// serializeSchedule writes the serialized schedule, excluding its channel id,
+// to the passed writer.
+func serializeSchedule(w io.Writer, s *Schedule) error {
+	if err := serializePolicy(w, &s.Default); err != nil {
+		return err
+	}
+
+	numRules := uint16(len(s.Rules))
+	if err := channeldb.WriteElement(w, numRules); err != nil {
+		return err
+	}
+
+	for _, rule := range s.Rules {
+		err := channeldb.WriteElements(w,
+			math.Float64bits(rule.BelowLocalRatio),
+			math.Float64bits(rule.AboveLocalRatio),
+			rule.StartHour, rule.EndHour,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := serializePolicy(w, &rule.Policy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeSchedule reads a schedule serialized by serializeSchedule from
+// the passed reader.
+func deserializeSchedule(r io.Reader) (*Schedule, error) {
+	var s Schedule
+	if err := deserializePolicy(r, &s.Default); err != nil {
+		return nil, err
+	}
+
+	var numRules uint16
+	if err := channeldb.ReadElement(r, &numRules); err != nil {
+		return nil, err
+	}
+
+	for i := uint16(0); i < numRules; i++ {
+		var (
+			rule         Rule
+			below, above uint64
+		)
+		err := channeldb.ReadElements(r,
+			&below, &above, &rule.StartHour, &rule.EndHour,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rule.BelowLocalRatio = math.Float64frombits(below)
+		rule.AboveLocalRatio = math.Float64frombits(above)
+
+		if err := deserializePolicy(r, &rule.Policy); err != nil {
+			return nil, err
+		}
+
+		s.Rules = append(s.Rules, rule)
+	}
+
+	return &s, nil
+}
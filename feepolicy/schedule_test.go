@@ -0,0 +1,49 @@
+package feepolicy
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSchedulePolicyAt asserts that the policy of the first rule that holds is
+// selected, and the default policy if none does.
+func TestSchedulePolicyAt(t *testing.T) {
+	t.Parallel()
+
+	schedule := &Schedule{
+		Default: defaultPolicy,
+		Rules: []Rule{
+			{
+				BelowLocalRatio: 0.2,
+				Policy:          depletedPolicy,
+			},
+			{
+				StartHour: 22,
+				EndHour:   6,
+				Policy:    nightPolicy,
+			},
+		},
+	}
+
+	testCases := []struct {
+		localRatio float64
+		hour       int
+		expected   Policy
+	}{
+		{localRatio: 0.5, hour: 12, expected: defaultPolicy},
+		{localRatio: 0.1, hour: 12, expected: depletedPolicy},
+		{localRatio: 0.2, hour: 12, expected: defaultPolicy},
+		{localRatio: 0.5, hour: 22, expected: nightPolicy},
+		{localRatio: 0.5, hour: 3, expected: nightPolicy},
+		{localRatio: 0.5, hour: 6, expected: defaultPolicy},
+		{localRatio: 0.1, hour: 23, expected: depletedPolicy},
+	}
+	for i, test := range testCases {
+		now := time.Date(2019, 1, 1, test.hour, 0, 0, 0, time.UTC)
+		policy := schedule.PolicyAt(test.localRatio, now)
+		if policy != test.expected {
+			t.Fatalf("test %d: expected policy %v, got %v", i,
+				test.expected, policy)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package feepolicy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+)
+
+var (
+	// schedulesBucketKey is the key of the top-level bucket that stores
+	// the fee schedules of our channels.
+	//
+	// maps: chanID -> serialized schedule
+	schedulesBucketKey = []byte("fee-schedules")
+
+	// ErrScheduleNotFound is returned when no schedule is set for a
+	// channel.
+	ErrScheduleNotFound = errors.New("fee schedule not found")
+
+	byteOrder = binary.BigEndian
+)
+
+// Store persists the fee schedules of our channels.
+type Store interface {
+	// SetSchedule adds or replaces the schedule of a channel.
+	SetSchedule(s *Schedule) error
+
+	// RemoveSchedule removes the schedule of the channel with the given
+	// short channel id. ErrScheduleNotFound is returned if no schedule is
+	// set.
+	RemoveSchedule(chanID uint64) error
+
+	// FetchSchedules returns all schedules, ordered by their channel id.
+	FetchSchedules() ([]*Schedule, error)
+}
+
+// scheduleStore is an implementation of the Store interface backed by the
+// channel database.
+type scheduleStore struct {
+	db *channeldb.DB
+}
+
+// A compile-time check to ensure scheduleStore implements the Store
+// interface.
+var _ Store = (*scheduleStore)(nil)
+
+// NewStore returns a new Store backed by the given channel database.
+func NewStore(db *channeldb.DB) (Store, error) {
+	err := db.Update(func(tx kvdb.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(schedulesBucketKey)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &scheduleStore{
+		db: db,
+	}, nil
+}
+
+// SetSchedule adds or replaces the schedule of a channel.
+//
+// NOTE: Part of the Store interface.
+func (s *scheduleStore) SetSchedule(schedule *Schedule) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		var b bytes.Buffer
+		if err := serializeSchedule(&b, schedule); err != nil {
+			return err
+		}
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], schedule.ChanID)
+
+		return tx.Bucket(schedulesBucketKey).Put(k[:], b.Bytes())
+	})
+}
+
+// RemoveSchedule removes the schedule of the channel with the given short
+// channel id.
+//
+// NOTE: Part of the Store interface.
+func (s *scheduleStore) RemoveSchedule(chanID uint64) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		schedules := tx.Bucket(schedulesBucketKey)
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], chanID)
+		if schedules.Get(k[:]) == nil {
+			return ErrScheduleNotFound
+		}
+
+		return schedules.Delete(k[:])
+	})
+}
+
+// FetchSchedules returns all schedules, ordered by their channel id.
+//
+// NOTE: Part of the Store interface.
+func (s *scheduleStore) FetchSchedules() ([]*Schedule, error) {
+	var schedules []*Schedule
+	err := s.db.View(func(tx kvdb.Tx) error {
+		bucket := tx.Bucket(schedulesBucketKey)
+		return bucket.ForEach(func(k, v []byte) error {
+			schedule, err := deserializeSchedule(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			schedule.ChanID = byteOrder.Uint64(k)
+
+			schedules = append(schedules, schedule)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
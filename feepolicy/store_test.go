@@ -0,0 +1,121 @@
+package feepolicy
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/lnd/channeldb"
+)
+
+// makeTestDB creates a new instance of the ChannelDB for testing purposes. A
+// callback which cleans up the created temporary directories is also returned
+// and intended to be executed after the test completes.
+func makeTestDB() (*channeldb.DB, func(), error) {
+	tempDirName, err := ioutil.TempDir("", "feepolicy")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cdb, err := channeldb.Open(tempDirName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanUp := func() {
+		cdb.Close()
+		os.RemoveAll(tempDirName)
+	}
+
+	return cdb, cleanUp, nil
+}
+
+// TestStore asserts that schedules can be set, replaced and removed.
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to open channel db: %v", err)
+	}
+	defer cleanUp()
+
+	store, err := NewStore(cdb)
+	if err != nil {
+		t.Fatalf("unable to create store: %v", err)
+	}
+
+	assertSchedules := func(expected ...*Schedule) {
+		t.Helper()
+
+		schedules, err := store.FetchSchedules()
+		if err != nil {
+			t.Fatalf("unable to fetch schedules: %v", err)
+		}
+		if !reflect.DeepEqual(schedules, expected) {
+			t.Fatalf("expected schedules %v, got %v",
+				spew.Sdump(expected), spew.Sdump(schedules))
+		}
+	}
+
+	schedule1 := &Schedule{
+		ChanID: 2,
+		Default: Policy{
+			BaseFee:       1000,
+			FeeRate:       100,
+			TimeLockDelta: 40,
+		},
+		Rules: []Rule{
+			{
+				BelowLocalRatio: 0.2,
+				Policy: Policy{
+					BaseFee:       2000,
+					FeeRate:       500,
+					TimeLockDelta: 40,
+				},
+			},
+			{
+				StartHour: 22,
+				EndHour:   6,
+				Policy: Policy{
+					FeeRate:       50,
+					TimeLockDelta: 144,
+				},
+			},
+		},
+	}
+	schedule2 := &Schedule{
+		ChanID: 1,
+		Default: Policy{
+			BaseFee:       1,
+			FeeRate:       1,
+			TimeLockDelta: 144,
+		},
+	}
+	if err := store.SetSchedule(schedule1); err != nil {
+		t.Fatalf("unable to set schedule: %v", err)
+	}
+	if err := store.SetSchedule(schedule2); err != nil {
+		t.Fatalf("unable to set schedule: %v", err)
+	}
+	assertSchedules(schedule2, schedule1)
+
+	// Setting the schedule of a channel again should replace it.
+	schedule1.Rules = schedule1.Rules[:1]
+	if err := store.SetSchedule(schedule1); err != nil {
+		t.Fatalf("unable to set schedule: %v", err)
+	}
+	assertSchedules(schedule2, schedule1)
+
+	if err := store.RemoveSchedule(schedule2.ChanID); err != nil {
+		t.Fatalf("unable to remove schedule: %v", err)
+	}
+	assertSchedules(schedule1)
+
+	err = store.RemoveSchedule(schedule2.ChanID)
+	if err != ErrScheduleNotFound {
+		t.Fatalf("expected ErrScheduleNotFound, got %v", err)
+	}
+}
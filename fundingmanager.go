@@ -2,6 +2,7 @@ package lnd
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
 	"sync"
@@ -12,11 +13,11 @@ import (
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/discovery"
 	"github.com/litecoinfinance/lnd/htlcswitch"
 	"github.com/litecoinfinance/lnd/input"
@@ -104,9 +105,35 @@ type reservationWithCtx struct {
 
 	chanAmt btcutil.Amount
 
-	// Constraints we require for the remote.
-	remoteCsvDelay uint16
-	remoteMinHtlc  lnwire.MilliSatoshi
+	// Constraints we require for the remote. If remoteChanReserve is
+	// zero, the reserve is derived from the capacity of the channel.
+	remoteCsvDelay    uint16
+	remoteMinHtlc     lnwire.MilliSatoshi
+	remoteChanReserve btcutil.Amount
+
+	// remoteFundingAmt is the amount the remote party contributes to the
+	// channel. It's only set for dual funded channels.
+	remoteFundingAmt btcutil.Amount
+
+	// remoteContribution and remoteInputScripts are the remote party's
+	// contribution to a dual funded channel we initiated, and the scripts
+	// spending its inputs. Each of them is received ahead of the
+	// AcceptChannel and FundingSigned message respectively.
+	remoteContribution *lnwire.FundingContribution
+	remoteInputScripts []lnwire.FundingInputScript
+
+	// psbtFunding indicates that the funding transaction is funded and
+	// signed outside of the wallet. Once the remote party accepted the
+	// channel, its contribution is kept in psbtContribution until the
+	// funding transaction, which must pay to psbtFundingOutput, is
+	// handed to us.
+	psbtFunding       bool
+	psbtContribution  *lnwallet.ChannelContribution
+	psbtFundingOutput *wire.TxOut
+
+	// zeroConf indicates that the channel is usable before its funding
+	// transaction confirms, which counts towards our zero-conf exposure.
+	zeroConf bool
 
 	updateMtx   sync.RWMutex
 	lastUpdated time.Time
@@ -157,6 +184,15 @@ type initFundingMsg struct {
 type fundingOpenMsg struct {
 	msg  *lnwire.OpenChannel
 	peer lnpeer.Peer
+
+	// contribution is the initiator's contribution to a dual funded
+	// channel, which is only set once we agreed to contribute to the
+	// channel as well.
+	contribution *lnwire.FundingContribution
+
+	// acceptance is the decision of the channel acceptor on the request,
+	// which is only set once it accepted the channel.
+	acceptance *channelAcceptResponse
 }
 
 // fundingAcceptMsg couples an lnwire.AcceptChannel message with the peer who
@@ -183,6 +219,24 @@ type fundingSignedMsg struct {
 	peer lnpeer.Peer
 }
 
+// fundingContributionMsg couples an lnwire.FundingContribution message with
+// the peer who sent the message. This allows the funding manager to pair the
+// contribution with the funding workflow of the dual funded channel it
+// belongs to.
+type fundingContributionMsg struct {
+	msg  *lnwire.FundingContribution
+	peer lnpeer.Peer
+}
+
+// fundingWitnessesMsg couples an lnwire.FundingWitnesses message with the
+// peer who sent the message. This allows the funding manager to pair the
+// witnesses with the funding workflow of the dual funded channel they belong
+// to.
+type fundingWitnessesMsg struct {
+	msg  *lnwire.FundingWitnesses
+	peer lnpeer.Peer
+}
+
 // fundingLockedMsg couples an lnwire.FundingLocked message with the peer who
 // sent the message. This allows the funding manager to finalize the funding
 // process and announce the existence of the new channel.
@@ -236,6 +290,10 @@ type fundingConfig struct {
 	// transaction information.
 	FeeEstimator lnwallet.FeeEstimator
 
+	// CommitFeeBounds restricts the commitment fee rate we propose for
+	// the channels we open.
+	CommitFeeBounds lnwallet.CommitFeeBounds
+
 	// Notifier is used by the FundingManager to determine when the
 	// channel's funding transaction has been confirmed on the blockchain
 	// so that the channel creation process can be completed.
@@ -324,6 +382,17 @@ type fundingConfig struct {
 	// sub-systems.
 	ReportShortChanID func(wire.OutPoint) error
 
+	// WatchSplicedChannel is called once the splice transaction of a
+	// channel confirmed, in order to move the watch over the channel from
+	// its old funding outpoint to its new one.
+	WatchSplicedChannel func(oldChanPoint wire.OutPoint,
+		newChan *channeldb.OpenChannel) error
+
+	// DisconnectPeer disconnects the peer with the given identity key.
+	// It's used to abort a splice, after which both parties resume the
+	// channel from its persisted state once reconnected.
+	DisconnectPeer func(*btcec.PublicKey) error
+
 	// ZombieSweeperInterval is the periodic time interval in which the
 	// zombie sweeper is run.
 	ZombieSweeperInterval time.Duration
@@ -341,6 +410,17 @@ type fundingConfig struct {
 	// NotifyOpenChannelEvent informs the ChannelNotifier when channels
 	// transition from pending open to open.
 	NotifyOpenChannelEvent func(wire.OutPoint)
+
+	// DualFundAcceptor decides whether we contribute the requested amount
+	// of funds to a dual funded channel proposed by a peer. It may block
+	// until the decision is made.
+	DualFundAcceptor func(*dualFundRequest) bool
+
+	// ChannelAcceptor decides whether we accept a channel proposed by a
+	// peer, along with any constraints we require of the peer that
+	// override our defaults. It may block until the decision is made. If
+	// nil, all channels are accepted.
+	ChannelAcceptor func(*channelAcceptRequest) *channelAcceptResponse
 }
 
 // fundingManager acts as an orchestrator/bridge between the wallet's
@@ -381,7 +461,16 @@ type fundingManager struct {
 	// signed by both parties.
 	signedReservations map[lnwire.ChannelID][32]byte
 
-	// resMtx guards both of the maps above to ensure that all access is
+	// pendingContributions houses the latest FundingContribution each peer
+	// sent for a dual funded channel it's about to propose. It's kept
+	// until the OpenChannel message following it arrives.
+	pendingContributions map[serializedPubKey]*lnwire.FundingContribution
+
+	// activeSplices houses the splices we're negotiating, keyed by the
+	// channel ID of the channel being spliced.
+	activeSplices map[lnwire.ChannelID]*spliceCtx
+
+	// resMtx guards all of the maps above to ensure that all access is
 	// goroutine safe.
 	resMtx sync.RWMutex
 
@@ -435,6 +524,13 @@ const (
 	// immediately after the fundingLocked message has been sent, but
 	// we still haven't announced the channel to the network.
 	addedToRouterGraph
+
+	// zeroConfOpen is the opening state of a zero-conf channel that was
+	// marked open under its alias short channel ID, and for which the
+	// fundingLocked message is sent, while its funding transaction has
+	// yet to confirm. Once it does, the channel moves on to markedOpen
+	// under its real short channel ID.
+	zeroConfOpen
 )
 
 var (
@@ -458,6 +554,8 @@ func newFundingManager(cfg fundingConfig) (*fundingManager, error) {
 		chanIDKey:                   cfg.TempChanIDSeed,
 		activeReservations:          make(map[serializedPubKey]pendingChannels),
 		signedReservations:          make(map[lnwire.ChannelID][32]byte),
+		pendingContributions:        make(map[serializedPubKey]*lnwire.FundingContribution),
+		activeSplices:               make(map[lnwire.ChannelID]*spliceCtx),
 		newChanBarriers:             make(map[lnwire.ChannelID]chan struct{}),
 		fundingMsgs:                 make(chan interface{}, msgBufferSize),
 		fundingRequests:             make(chan *initFundingMsg, msgBufferSize),
@@ -507,10 +605,9 @@ func (f *fundingManager) Start() error {
 		// double spend, we treat this as an indicator that we have
 		// already broadcast this transaction. Otherwise, we simply log
 		// the error as there isn't anything we can currently do to
-		// recover.
-		if channel.ChanType == channeldb.SingleFunder &&
-			channel.IsInitiator {
-
+		// recover. Channels whose funding transaction is published
+		// on their behalf, don't have it stored.
+		if channel.IsInitiator && channel.ChanType.HasFundingTx() {
 			err := f.cfg.PublishTransaction(channel.FundingTxn)
 			if err != nil {
 				fndgLog.Errorf("Unable to rebroadcast funding "+
@@ -593,6 +690,10 @@ func (f *fundingManager) Start() error {
 		return err
 	}
 
+	// Channels that were being spliced resume waiting for their splice
+	// transaction to confirm.
+	f.resumeSplices(openChannels)
+
 	for _, channel := range openChannels {
 		channelState, shortChanID, err := f.getChannelOpeningState(
 			&channel.FundingOutpoint)
@@ -695,6 +796,37 @@ func (f *fundingManager) Start() error {
 				}
 			}(channel)
 
+		case zeroConfOpen:
+			// The zero-conf channel is in use under its alias, but
+			// its funding transaction had yet to confirm. We'll
+			// make sure the peer received fundingLocked, before
+			// waiting for the confirmation once again.
+			f.wg.Add(1)
+			go func(dbChan *channeldb.OpenChannel) {
+				defer f.wg.Done()
+
+				peerChan := make(chan lnpeer.Peer, 1)
+				f.cfg.NotifyWhenOnline(
+					dbChan.IdentityPub, peerChan,
+				)
+
+				var peer lnpeer.Peer
+				select {
+				case peer = <-peerChan:
+				case <-f.quit:
+					return
+				}
+
+				err := f.openZeroConfChannel(peer, dbChan)
+				if err != nil {
+					fndgLog.Errorf("Failed to open "+
+						"zero-conf channel: %v", err)
+					return
+				}
+
+				f.waitForZeroConfConfirmation(peer, dbChan)
+			}(channel)
+
 		default:
 			fndgLog.Errorf("undefined channelState: %v",
 				channelState)
@@ -757,6 +889,22 @@ type pendingChansReq struct {
 	err  chan error
 }
 
+// psbtFinalizeReq hands the funding manager the signed funding transaction of
+// an externally funded channel, allowing the funding workflow to proceed.
+type psbtFinalizeReq struct {
+	pendingChanID [32]byte
+	fundingTx     *wire.MsgTx
+	err           chan error
+}
+
+// cancelReservationReq requests the funding manager to fail the funding flow
+// of the reservation with the given pending channel ID.
+type cancelReservationReq struct {
+	pendingChanID [32]byte
+	reason        error
+	err           chan error
+}
+
 // PendingChannels returns a slice describing all the channels which are
 // currently pending at the last state of the funding workflow.
 func (f *fundingManager) PendingChannels() ([]*pendingChannel, error) {
@@ -794,6 +942,10 @@ func (f *fundingManager) CancelPeerReservations(nodePub [33]byte) {
 	f.resMtx.Lock()
 	defer f.resMtx.Unlock()
 
+	// Any contribution the node sent ahead of a channel proposal won't be
+	// needed anymore.
+	delete(f.pendingContributions, nodePub)
+
 	// We'll attempt to look up this node in the set of active
 	// reservations.  If they don't have any, then there's no further work
 	// to be done.
@@ -897,11 +1049,27 @@ func (f *fundingManager) reservationCoordinator() {
 				f.handleFundingCreated(fmsg)
 			case *fundingSignedMsg:
 				f.handleFundingSigned(fmsg)
+			case *fundingContributionMsg:
+				f.handleFundingContribution(fmsg)
+			case *fundingWitnessesMsg:
+				f.handleFundingWitnesses(fmsg)
 			case *fundingLockedMsg:
 				f.wg.Add(1)
 				go f.handleFundingLocked(fmsg)
 			case *fundingErrorMsg:
 				f.handleErrorMsg(fmsg)
+			case *spliceInitMsg:
+				f.handleSpliceInit(fmsg)
+			case *spliceAckMsg:
+				f.handleSpliceAck(fmsg)
+			case *spliceCommitMsg:
+				f.handleSpliceCommit(fmsg)
+			case *spliceSignedMsg:
+				f.handleSpliceSigned(fmsg)
+			case *spliceErrorMsg:
+				f.handleSpliceError(fmsg)
+			case *spliceResumeMsg:
+				f.handleSpliceResume(fmsg)
 			}
 		case req := <-f.fundingRequests:
 			f.handleInitFundingMsg(req)
@@ -913,6 +1081,12 @@ func (f *fundingManager) reservationCoordinator() {
 			switch msg := req.(type) {
 			case *pendingChansReq:
 				f.handlePendingChannels(msg)
+			case *psbtFinalizeReq:
+				f.handlePsbtFinalize(msg)
+			case *cancelReservationReq:
+				f.handleCancelReservation(msg)
+			case *initSpliceMsg:
+				f.handleInitSpliceMsg(msg)
 			}
 		case <-f.quit:
 			return
@@ -953,7 +1127,7 @@ func (f *fundingManager) processFundingOpen(msg *lnwire.OpenChannel,
 	peer lnpeer.Peer) {
 
 	select {
-	case f.fundingMsgs <- &fundingOpenMsg{msg, peer}:
+	case f.fundingMsgs <- &fundingOpenMsg{msg: msg, peer: peer}:
 	case <-f.quit:
 		return
 	}
@@ -1050,28 +1224,76 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		return
 	}
 
+	// If the initiator requests a zero-conf channel, we'll make sure we
+	// can afford to trust its funding transaction.
+	zeroConf := msg.ChannelFlags&lnwire.FFZeroConf == lnwire.FFZeroConf
+	if zeroConf {
+		if err := f.validateZeroConfOpen(msg); err != nil {
+			f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
+			return
+		}
+	}
+
+	// Before committing to anything, the channel acceptor gets to decide
+	// whether we accept the channel at all. Once it did, the request is
+	// handed back to us along with the decision.
+	if f.cfg.ChannelAcceptor != nil && fmsg.acceptance == nil {
+		f.handleChannelAccept(fmsg)
+		return
+	}
+
+	// If the initiator requests us to contribute funds to the channel,
+	// we'll first have to decide whether to do so. Once we did, the
+	// request is handed back to us along with the initiator's
+	// contribution.
+	dualFund := msg.ChannelFlags&lnwire.FFDualFund == lnwire.FFDualFund
+	if dualFund && fmsg.contribution == nil {
+		f.handleDualFundOpen(fmsg)
+		return
+	}
+
 	fndgLog.Infof("Recv'd fundingRequest(amt=%v, push=%v, delay=%v, "+
 		"pendingId=%x) from peer(%x)", amt, msg.PushAmount,
 		msg.CsvDelay, msg.PendingChannelID,
 		fmsg.peer.IdentityKey().SerializeCompressed())
 
+	// If we're on the responding side of a single funder workflow, we
+	// don't commit any funds to the channel ourselves. Otherwise, we'll
+	// contribute whatever the initiator doesn't, paying the fees for our
+	// share of the funding transaction.
+	var (
+		localAmt, remoteAmt btcutil.Amount
+		fundingFeePerKw     lnwallet.SatPerKWeight
+	)
+	if dualFund {
+		remoteAmt = fmsg.contribution.FundingAmount
+		localAmt = amt - remoteAmt
+
+		fundingFeePerKw, err = f.cfg.FeeEstimator.EstimateFeePerKW(6)
+		if err != nil {
+			fndgLog.Errorf("Unable to query fee estimator: %v", err)
+			f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
+			return
+		}
+	}
+
 	// Attempt to initialize a reservation within the wallet. If the wallet
 	// has insufficient resources to create the channel, then the
-	// reservation attempt may be rejected. Note that since we're on the
-	// responding side of a single funder workflow, we don't commit any
-	// funds to the channel ourselves.
+	// reservation attempt may be rejected.
 	chainHash := chainhash.Hash(msg.ChainHash)
 	req := &lnwallet.InitFundingReserveMsg{
 		ChainHash:       &chainHash,
 		NodeID:          fmsg.peer.IdentityKey(),
 		NodeAddr:        fmsg.peer.Address(),
-		FundingAmount:   0,
+		FundingAmount:   localAmt,
 		Capacity:        amt,
 		CommitFeePerKw:  lnwallet.SatPerKWeight(msg.FeePerKiloWeight),
-		FundingFeePerKw: 0,
+		FundingFeePerKw: fundingFeePerKw,
 		PushMSat:        msg.PushAmount,
 		Flags:           msg.ChannelFlags,
 		MinConfs:        1,
+		Anchors:         useAnchors(fmsg.peer),
+		RemoteInitiated: dualFund,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -1087,6 +1309,16 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 	// the amount of the channel, and also if any funds are being pushed to
 	// us.
 	numConfsReq := f.cfg.NumRequiredConfs(msg.FundingAmount, msg.PushAmount)
+	acceptance := fmsg.acceptance
+	if acceptance != nil && acceptance.minAcceptDepth != 0 {
+		numConfsReq = acceptance.minAcceptDepth
+	}
+
+	// A zero-conf channel is usable before its funding transaction
+	// confirms, which we signal by not requiring any confirmations.
+	if zeroConf {
+		numConfsReq = 0
+	}
 	reservation.SetNumConfsRequired(numConfsReq)
 
 	// We'll also validate and apply all the constraints the initiating
@@ -1110,12 +1342,30 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		"amt=%v, push_amt=%v", numConfsReq, fmsg.msg.PendingChannelID,
 		amt, msg.PushAmount)
 
-	// Generate our required constraints for the remote party.
+	// Generate our required constraints for the remote party, unless the
+	// channel acceptor constrained them.
 	remoteCsvDelay := f.cfg.RequiredRemoteDelay(amt)
 	chanReserve := f.cfg.RequiredRemoteChanReserve(amt, msg.DustLimit)
 	maxValue := f.cfg.RequiredRemoteMaxValue(amt)
 	maxHtlcs := f.cfg.RequiredRemoteMaxHTLCs(amt)
 	minHtlc := f.cfg.DefaultRoutingPolicy.MinHTLC
+	if acceptance != nil {
+		if acceptance.remoteCsvDelay != 0 {
+			remoteCsvDelay = acceptance.remoteCsvDelay
+		}
+		if acceptance.remoteChanReserve != 0 {
+			chanReserve = acceptance.remoteChanReserve
+		}
+		if acceptance.remoteMaxValue != 0 {
+			maxValue = acceptance.remoteMaxValue
+		}
+		if acceptance.remoteMaxHtlcs != 0 {
+			maxHtlcs = acceptance.remoteMaxHtlcs
+		}
+		if acceptance.remoteMinHtlc != 0 {
+			minHtlc = acceptance.remoteMinHtlc
+		}
+	}
 
 	// Once the reservation has been created successfully, we add it to
 	// this peer's map of pending reservations to track this particular
@@ -1125,12 +1375,14 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		f.activeReservations[peerIDKey] = make(pendingChannels)
 	}
 	resCtx := &reservationWithCtx{
-		reservation:    reservation,
-		chanAmt:        amt,
-		remoteCsvDelay: remoteCsvDelay,
-		remoteMinHtlc:  minHtlc,
-		err:            make(chan error, 1),
-		peer:           fmsg.peer,
+		reservation:      reservation,
+		chanAmt:          amt,
+		remoteFundingAmt: remoteAmt,
+		remoteCsvDelay:   remoteCsvDelay,
+		remoteMinHtlc:    minHtlc,
+		zeroConf:         zeroConf,
+		err:              make(chan error, 1),
+		peer:             fmsg.peer,
 	}
 	f.activeReservations[peerIDKey][msg.PendingChannelID] = resCtx
 	f.resMtx.Unlock()
@@ -1141,7 +1393,7 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 	// With our parameters set, we'll now process their contribution so we
 	// can move the funding workflow ahead.
 	remoteContribution := &lnwallet.ChannelContribution{
-		FundingAmount:        amt,
+		FundingAmount:        amt - localAmt,
 		FirstCommitmentPoint: msg.FirstCommitmentPoint,
 		ChannelConfig: &channeldb.ChannelConfig{
 			ChannelConstraints: channeldb.ChannelConstraints{
@@ -1169,7 +1421,19 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 			},
 		},
 	}
-	err = reservation.ProcessSingleContribution(remoteContribution)
+	if dualFund {
+		// As both of us contribute inputs, the wallet is now able to
+		// construct the funding transaction, after verifying the
+		// initiator's inputs.
+		err = addFundingContribution(
+			remoteContribution, fmsg.contribution,
+		)
+		if err == nil {
+			err = reservation.ProcessContribution(remoteContribution)
+		}
+	} else {
+		err = reservation.ProcessSingleContribution(remoteContribution)
+	}
 	if err != nil {
 		fndgLog.Errorf("unable to add contribution reservation: %v", err)
 		f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
@@ -1200,13 +1464,200 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		HtlcPoint:            ourContribution.HtlcBasePoint.PubKey,
 		FirstCommitmentPoint: ourContribution.FirstCommitmentPoint,
 	}
-	if err := fmsg.peer.SendMessage(false, &fundingAccept); err != nil {
+
+	// Within a dual funder workflow, our own contribution precedes the
+	// AcceptChannel message, such that the initiator is able to construct
+	// the funding transaction once it receives the latter.
+	var msgs []lnwire.Message
+	if dualFund {
+		msgs = append(msgs, newFundingContribution(
+			msg.PendingChannelID, localAmt, ourContribution,
+		))
+	}
+	msgs = append(msgs, &fundingAccept)
+
+	if err := fmsg.peer.SendMessage(false, msgs...); err != nil {
 		fndgLog.Errorf("unable to send funding response to peer: %v", err)
 		f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
 		return
 	}
 }
 
+// handleDualFundOpen handles a request of a remote peer for us to contribute
+// funds to the channel it opens. After validating the peer's contribution,
+// which must have arrived ahead of the OpenChannel message, the request is
+// handed to the DualFundAcceptor. If it's accepted, the OpenChannel message is
+// requeued along with the contribution, so the workflow proceeds with us
+// contributing the remainder of the channel's capacity.
+func (f *fundingManager) handleDualFundOpen(fmsg *fundingOpenMsg) {
+	msg := fmsg.msg
+	peerKey := fmsg.peer.IdentityKey()
+
+	if !cfg.Protocol.DualFunding {
+		f.failFundingFlow(
+			fmsg.peer, msg.PendingChannelID,
+			lnwallet.ErrDualFundingDisabled(),
+		)
+		return
+	}
+
+	f.resMtx.Lock()
+	contribution := f.pendingContributions[newSerializedKey(peerKey)]
+	delete(f.pendingContributions, newSerializedKey(peerKey))
+	f.resMtx.Unlock()
+
+	var err error
+	switch {
+	case contribution == nil ||
+		contribution.PendingChannelID != msg.PendingChannelID:
+
+		err = lnwallet.ErrInvalidContribution("not received")
+
+	case msg.PushAmount != 0:
+		err = lnwallet.ErrInvalidContribution("non-zero push amount")
+
+	case contribution.FundingAmount <= 0 ||
+		contribution.FundingAmount >= msg.FundingAmount:
+
+		err = lnwallet.ErrInvalidContribution(fmt.Sprintf("amount of "+
+			"%v for capacity of %v", contribution.FundingAmount,
+			msg.FundingAmount))
+
+	// Ensure the contribution is sane before bothering anyone with it.
+	// Its inputs are verified against the UTXO set once we accepted it.
+	default:
+		err = addFundingContribution(
+			&lnwallet.ChannelContribution{}, contribution,
+		)
+	}
+	if err != nil {
+		fndgLog.Errorf("Invalid funding contribution of peer(%x): %v",
+			peerKey.SerializeCompressed(), err)
+		f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
+		return
+	}
+
+	localAmt := msg.FundingAmount - contribution.FundingAmount
+
+	fndgLog.Infof("Peer(%x) requests us to contribute %v to a channel "+
+		"of %v, pendingId=%x", peerKey.SerializeCompressed(), localAmt,
+		msg.FundingAmount, msg.PendingChannelID)
+
+	// As deciding on the request may take a while, we'll wait for the
+	// decision in a goroutine, allowing other funding flows to proceed in
+	// the meantime.
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		accepted := f.cfg.DualFundAcceptor(&dualFundRequest{
+			nodePubKey:       peerKey,
+			pendingChanID:    msg.PendingChannelID,
+			capacity:         msg.FundingAmount,
+			localFundingAmt:  localAmt,
+			remoteFundingAmt: contribution.FundingAmount,
+		})
+		if !accepted {
+			f.failFundingFlow(
+				fmsg.peer, msg.PendingChannelID,
+				lnwallet.ErrDualFundDeclined(localAmt),
+			)
+			return
+		}
+
+		select {
+		case f.fundingMsgs <- &fundingOpenMsg{
+			msg:          msg,
+			peer:         fmsg.peer,
+			contribution: contribution,
+			acceptance:   fmsg.acceptance,
+		}:
+		case <-f.quit:
+		}
+	}()
+}
+
+// handleChannelAccept hands a channel proposed by a peer to the channel
+// acceptor. If the channel is accepted, and any constraints the acceptor
+// requires of the peer are sane, the OpenChannel message is requeued along
+// with the decision, so the workflow proceeds with it.
+func (f *fundingManager) handleChannelAccept(fmsg *fundingOpenMsg) {
+	msg := fmsg.msg
+	peerKey := fmsg.peer.IdentityKey()
+
+	// As the decision may take a while, we'll wait for it in a goroutine,
+	// allowing other funding flows to proceed in the meantime.
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		acceptance := f.cfg.ChannelAcceptor(&channelAcceptRequest{
+			nodePubKey: peerKey,
+			openChan:   msg,
+			anchors:    useAnchors(fmsg.peer),
+		})
+		if !acceptance.accept {
+			fndgLog.Infof("Rejected pendingId=%x of peer(%x): %v",
+				msg.PendingChannelID,
+				peerKey.SerializeCompressed(), acceptance.reason)
+
+			f.failFundingFlow(
+				fmsg.peer, msg.PendingChannelID,
+				lnwallet.ErrChannelRejected(acceptance.reason),
+			)
+			return
+		}
+
+		err := validateAcceptance(msg, acceptance)
+		if err != nil {
+			fndgLog.Errorf("Invalid channel acceptor constraints "+
+				"for pendingId=%x: %v", msg.PendingChannelID,
+				err)
+			f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
+			return
+		}
+
+		select {
+		case f.fundingMsgs <- &fundingOpenMsg{
+			msg:        msg,
+			peer:       fmsg.peer,
+			acceptance: acceptance,
+		}:
+		case <-f.quit:
+		}
+	}()
+}
+
+// validateAcceptance ensures that the constraints the channel acceptor
+// requires of the peer are acceptable for the proposed channel. Any that
+// aren't set are replaced by our defaults, which are sane.
+func validateAcceptance(msg *lnwire.OpenChannel,
+	acceptance *channelAcceptResponse) error {
+
+	reserve := acceptance.remoteChanReserve
+	maxReserve := msg.FundingAmount / 5
+	switch {
+	case acceptance.minAcceptDepth > chainntnfs.MaxNumConfs:
+		return lnwallet.ErrNumConfsTooLarge(
+			uint32(acceptance.minAcceptDepth),
+			chainntnfs.MaxNumConfs,
+		)
+
+	case reserve != 0 && reserve < msg.DustLimit:
+		return lnwallet.ErrChanReserveTooSmall(reserve, msg.DustLimit)
+
+	case reserve > maxReserve:
+		return lnwallet.ErrChanReserveTooLarge(reserve, maxReserve)
+
+	case acceptance.remoteMaxHtlcs > input.MaxHTLCNumber/2:
+		return lnwallet.ErrMaxHtlcNumTooLarge(
+			acceptance.remoteMaxHtlcs, input.MaxHTLCNumber/2,
+		)
+	}
+
+	return nil
+}
+
 // processFundingAccept sends a message to the fundingManager allowing it to
 // continue the second phase of a funding workflow with the target peer.
 func (f *fundingManager) processFundingAccept(msg *lnwire.AcceptChannel,
@@ -1251,10 +1702,27 @@ func (f *fundingManager) handleFundingAccept(fmsg *fundingAcceptMsg) {
 		return
 	}
 
+	// A zero-conf channel is only usable before its funding transaction
+	// confirms if the responder doesn't require any confirmations. Vice
+	// versa, we'll require a single confirmation at least if we didn't
+	// request a zero-conf channel.
+	numConfs := uint16(msg.MinAcceptDepth)
+	switch {
+	case resCtx.zeroConf && numConfs != 0:
+		err := fmt.Errorf("peer requires %v confirmations for "+
+			"zero-conf channel", numConfs)
+		fndgLog.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(fmsg.peer, fmsg.msg.PendingChannelID, err)
+		return
+
+	case !resCtx.zeroConf && numConfs == 0:
+		numConfs = 1
+	}
+
 	// We'll also specify the responder's preference for the number of
 	// required confirmations, and also the set of channel constraints
 	// they've specified for commitment states we can create.
-	resCtx.reservation.SetNumConfsRequired(uint16(msg.MinAcceptDepth))
+	resCtx.reservation.SetNumConfsRequired(numConfs)
 	channelConstraints := &channeldb.ChannelConstraints{
 		DustLimit:        msg.DustLimit,
 		ChanReserve:      msg.ChannelReserve,
@@ -1273,15 +1741,33 @@ func (f *fundingManager) handleFundingAccept(fmsg *fundingAcceptMsg) {
 	// As they've accepted our channel constraints, we'll regenerate them
 	// here so we can properly commit their accepted constraints to the
 	// reservation.
-	chanReserve := f.cfg.RequiredRemoteChanReserve(resCtx.chanAmt, msg.DustLimit)
+	chanReserve := resCtx.remoteChanReserve
+	if chanReserve == 0 {
+		chanReserve = f.cfg.RequiredRemoteChanReserve(
+			resCtx.chanAmt, msg.DustLimit,
+		)
+	}
 	maxValue := f.cfg.RequiredRemoteMaxValue(resCtx.chanAmt)
 	maxHtlcs := f.cfg.RequiredRemoteMaxHTLCs(resCtx.chanAmt)
 
+	// A reserve requested for the channel must cover the dust limit the
+	// remote party picked for its commitment transactions, as the remote
+	// output could otherwise be trimmed while still holding the reserve.
+	if chanReserve < msg.DustLimit {
+		err := lnwallet.ErrChanReserveTooSmall(
+			chanReserve, msg.DustLimit,
+		)
+		fndgLog.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(fmsg.peer, fmsg.msg.PendingChannelID, err)
+		return
+	}
+
 	// The remote node has responded with their portion of the channel
 	// contribution. At this point, we can process their contribution which
 	// allows us to construct and sign both the commitment transaction, and
 	// the funding transaction.
 	remoteContribution := &lnwallet.ChannelContribution{
+		FundingAmount:        resCtx.remoteFundingAmt,
 		FirstCommitmentPoint: msg.FirstCommitmentPoint,
 		ChannelConfig: &channeldb.ChannelConfig{
 			ChannelConstraints: channeldb.ChannelConstraints{
@@ -1309,6 +1795,56 @@ func (f *fundingManager) handleFundingAccept(fmsg *fundingAcceptMsg) {
 			},
 		},
 	}
+
+	// If we requested the remote node to contribute funds to the channel,
+	// its inputs must have arrived ahead of the AcceptChannel message.
+	if resCtx.remoteFundingAmt != 0 {
+		contribution := resCtx.remoteContribution
+		switch {
+		case contribution == nil:
+			err = lnwallet.ErrInvalidContribution("not received")
+
+		case contribution.FundingAmount != resCtx.remoteFundingAmt:
+			err = lnwallet.ErrInvalidContribution(fmt.Sprintf(
+				"expected amount of %v, got %v",
+				resCtx.remoteFundingAmt,
+				contribution.FundingAmount,
+			))
+
+		default:
+			err = addFundingContribution(
+				remoteContribution, contribution,
+			)
+		}
+		if err != nil {
+			fndgLog.Errorf("Invalid contribution from %v: %v",
+				peerKey, err)
+			f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
+			return
+		}
+	}
+
+	fndgLog.Infof("pendingChan(%x): remote party proposes num_confs=%v, "+
+		"csv_delay=%v", pendingChanID[:], msg.MinAcceptDepth, msg.CsvDelay)
+	fndgLog.Debugf("Remote party accepted commitment constraints: %v",
+		spew.Sdump(remoteContribution.ChannelConfig.ChannelConstraints))
+
+	// If the channel is funded externally, the funding transaction can
+	// only be created now that both multi-sig keys are known. We'll hand
+	// the caller a template of it, and proceed once it's been funded.
+	if resCtx.psbtFunding {
+		err := f.sendPsbtTemplate(resCtx, pendingChanID, msg.FundingKey)
+		if err != nil {
+			fndgLog.Errorf("Unable to create PSBT funding "+
+				"template: %v", err)
+			f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
+			return
+		}
+
+		resCtx.psbtContribution = remoteContribution
+		return
+	}
+
 	err = resCtx.reservation.ProcessContribution(remoteContribution)
 	if err != nil {
 		fndgLog.Errorf("Unable to process contribution from %v: %v",
@@ -1317,10 +1853,15 @@ func (f *fundingManager) handleFundingAccept(fmsg *fundingAcceptMsg) {
 		return
 	}
 
-	fndgLog.Infof("pendingChan(%x): remote party proposes num_confs=%v, "+
-		"csv_delay=%v", pendingChanID[:], msg.MinAcceptDepth, msg.CsvDelay)
-	fndgLog.Debugf("Remote party accepted commitment constraints: %v",
-		spew.Sdump(remoteContribution.ChannelConfig.ChannelConstraints))
+	f.sendFundingCreated(resCtx, pendingChanID)
+}
+
+// sendFundingCreated sends the funding outpoint of the funding transaction of
+// the reservation, along with our signature for the remote party's version of
+// the commitment transaction, to the remote party. This requires the remote
+// party's contribution to have been processed.
+func (f *fundingManager) sendFundingCreated(resCtx *reservationWithCtx,
+	pendingChanID [32]byte) {
 
 	// Now that we have their contribution, we can extract, then send over
 	// both the funding out point and our signature for their version of
@@ -1353,17 +1894,205 @@ func (f *fundingManager) handleFundingAccept(fmsg *fundingAcceptMsg) {
 		PendingChannelID: pendingChanID,
 		FundingPoint:     *outPoint,
 	}
-	fundingCreated.CommitSig, err = lnwire.NewSigFromRawSignature(sig)
-	if err != nil {
-		fndgLog.Errorf("Unable to parse signature: %v", err)
-		f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
-		return
+	commitSig, err := lnwire.NewSigFromRawSignature(sig)
+	if err != nil {
+		fndgLog.Errorf("Unable to parse signature: %v", err)
+		f.failFundingFlow(resCtx.peer, pendingChanID, err)
+		return
+	}
+	fundingCreated.CommitSig = commitSig
+
+	if err := resCtx.peer.SendMessage(false, fundingCreated); err != nil {
+		fndgLog.Errorf("Unable to send funding complete message: %v", err)
+		f.failFundingFlow(resCtx.peer, pendingChanID, err)
+		return
+	}
+}
+
+// sendPsbtTemplate hands the caller that initiated the externally funded
+// channel of the reservation a PSBT funding template, which pays the channel's
+// capacity to the multi-sig output formed by our and the remote party's
+// funding key.
+func (f *fundingManager) sendPsbtTemplate(resCtx *reservationWithCtx,
+	pendingChanID [32]byte, remoteFundingKey *btcec.PublicKey) error {
+
+	ourKey := resCtx.reservation.OurContribution().MultiSigKey.PubKey
+	witnessScript, fundingOutput, err := input.GenFundingPkScript(
+		ourKey.SerializeCompressed(),
+		remoteFundingKey.SerializeCompressed(), int64(resCtx.chanAmt),
+	)
+	if err != nil {
+		return err
+	}
+
+	scriptHash := sha256.Sum256(witnessScript)
+	fundingAddr, err := btcutil.NewAddressWitnessScriptHash(
+		scriptHash[:], &f.cfg.Wallet.Cfg.NetParams,
+	)
+	if err != nil {
+		return err
+	}
+
+	fundingTemplate := wire.NewMsgTx(2)
+	fundingTemplate.AddTxOut(fundingOutput)
+	packet, err := lnwallet.NewUnsignedPsbt(fundingTemplate)
+	if err != nil {
+		return err
+	}
+
+	resCtx.psbtFundingOutput = fundingOutput
+
+	fndgLog.Infof("Waiting for funding tx paying %v to %v for "+
+		"pendingID(%x)", resCtx.chanAmt, fundingAddr, pendingChanID[:])
+
+	upd := &lnrpc.OpenStatusUpdate{
+		Update: &lnrpc.OpenStatusUpdate_PsbtFund{
+			PsbtFund: &lnrpc.ReadyForPsbtFunding{
+				FundingAddress: fundingAddr.String(),
+				FundingAmount:  int64(resCtx.chanAmt),
+				Psbt:           packet,
+				PendingChanId:  pendingChanID[:],
+			},
+		},
+	}
+
+	select {
+	case resCtx.updates <- upd:
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
+	}
+
+	return nil
+}
+
+// ProcessPsbtFinalize hands the funding manager the signed funding transaction
+// of the externally funded channel with the given pending channel ID. The
+// funding workflow then proceeds as usual, with the transaction being
+// broadcast once the remote party signed our commitment transaction.
+func (f *fundingManager) ProcessPsbtFinalize(pendingChanID [32]byte,
+	fundingTx *wire.MsgTx) error {
+
+	errChan := make(chan error, 1)
+	req := &psbtFinalizeReq{
+		pendingChanID: pendingChanID,
+		fundingTx:     fundingTx,
+		err:           errChan,
+	}
+
+	select {
+	case f.queries <- req:
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
+	}
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
+	}
+}
+
+// handlePsbtFinalize verifies that the funding transaction handed to us pays
+// the channel's capacity to its multi-sig output, and can't be malleated,
+// after which the funding workflow of the externally funded channel
+// proceeds.
+func (f *fundingManager) handlePsbtFinalize(req *psbtFinalizeReq) {
+	pendingChanID := req.pendingChanID
+
+	resCtx := f.findReservationCtx(pendingChanID)
+	if resCtx == nil || resCtx.psbtContribution == nil {
+		req.err <- fmt.Errorf("no channel awaiting its funding tx "+
+			"with pendingID(%x)", pendingChanID[:])
+		return
+	}
+
+	// Update the timestamp once the psbtFinalizeReq has been handled.
+	defer resCtx.updateTimestamp()
+
+	// The funding outpoint is committed to by the commitment transactions
+	// before the funding transaction is broadcast, so its txid must not
+	// change. We'll therefore require all of its inputs to be segwit
+	// inputs, which are fully signed.
+	fundingTx := req.fundingTx
+	if len(fundingTx.TxIn) == 0 {
+		req.err <- errors.New("funding tx has no inputs")
+		return
+	}
+	for _, txIn := range fundingTx.TxIn {
+		if len(txIn.Witness) == 0 {
+			req.err <- fmt.Errorf("input %v of funding tx is not "+
+				"a signed segwit input", txIn.PreviousOutPoint)
+			return
+		}
+	}
+
+	fundingOutput := resCtx.psbtFundingOutput
+	found, index := input.FindScriptOutputIndex(
+		fundingTx, fundingOutput.PkScript,
+	)
+	if !found || fundingTx.TxOut[index].Value != fundingOutput.Value {
+		req.err <- fmt.Errorf("funding tx doesn't pay %v to the "+
+			"funding output", btcutil.Amount(fundingOutput.Value))
+		return
+	}
+
+	// With the funding transaction verified, the wallet is now able to
+	// create both commitment transactions.
+	err := resCtx.reservation.ProcessExternalContribution(
+		resCtx.psbtContribution, fundingTx,
+	)
+	if err != nil {
+		req.err <- err
+		return
+	}
+	resCtx.psbtContribution = nil
+
+	req.err <- nil
+
+	f.sendFundingCreated(resCtx, pendingChanID)
+}
+
+// CancelReservation fails the funding flow of the reservation with the given
+// pending channel ID, sending the reason to the remote party. An error is
+// returned if there's no such reservation, which is the case once the channel
+// has been committed to disk.
+func (f *fundingManager) CancelReservation(pendingChanID [32]byte,
+	reason error) error {
+
+	errChan := make(chan error, 1)
+	req := &cancelReservationReq{
+		pendingChanID: pendingChanID,
+		reason:        reason,
+		err:           errChan,
+	}
+
+	select {
+	case f.queries <- req:
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
 	}
-	if err := fmsg.peer.SendMessage(false, fundingCreated); err != nil {
-		fndgLog.Errorf("Unable to send funding complete message: %v", err)
-		f.failFundingFlow(fmsg.peer, msg.PendingChannelID, err)
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-f.quit:
+		return ErrFundingManagerShuttingDown
+	}
+}
+
+// handleCancelReservation fails the funding flow of the reservation the
+// cancelReservationReq refers to.
+func (f *fundingManager) handleCancelReservation(req *cancelReservationReq) {
+	resCtx := f.findReservationCtx(req.pendingChanID)
+	if resCtx == nil {
+		req.err <- fmt.Errorf("no reservation with pendingID(%x)",
+			req.pendingChanID[:])
 		return
 	}
+
+	f.failFundingFlow(resCtx.peer, req.pendingChanID, req.reason)
+	req.err <- nil
 }
 
 // processFundingCreated queues a funding complete message coupled with the
@@ -1407,9 +2136,29 @@ func (f *fundingManager) handleFundingCreated(fmsg *fundingCreatedMsg) {
 	// funding transaction will broadcast after our next message.
 	// CompleteReservationSingle will also mark the channel as 'IsPending'
 	// in the database.
+	//
+	// Within a dual funder workflow we've constructed the funding
+	// transaction ourselves, so the initiator must have arrived at the
+	// same outpoint. We'll then complete the reservation without the
+	// initiator's input scripts, which we have no need for, as it's the
+	// initiator that broadcasts the funding transaction.
 	commitSig := fmsg.msg.CommitSig.ToSignatureBytes()
-	completeChan, err := resCtx.reservation.CompleteReservationSingle(
-		&fundingOut, commitSig)
+	dualFund := resCtx.remoteFundingAmt != 0
+	var completeChan *channeldb.OpenChannel
+	switch {
+	case dualFund && fundingOut != *resCtx.reservation.FundingOutpoint():
+		err = fmt.Errorf("expected ChannelPoint(%v), got %v",
+			resCtx.reservation.FundingOutpoint(), fundingOut)
+
+	case dualFund:
+		completeChan, err = resCtx.reservation.CompleteReservation(
+			nil, commitSig,
+		)
+
+	default:
+		completeChan, err = resCtx.reservation.CompleteReservationSingle(
+			&fundingOut, commitSig)
+	}
 	if err != nil {
 		// TODO(roasbeef): better error logging: peerID, channelID, etc.
 		fndgLog.Errorf("unable to complete single reservation: %v", err)
@@ -1459,7 +2208,7 @@ func (f *fundingManager) handleFundingCreated(fmsg *fundingCreatedMsg) {
 
 	// With their signature for our version of the commitment transaction
 	// verified, we can now send over our signature to the remote peer.
-	_, sig := resCtx.reservation.OurSignatures()
+	inputScripts, sig := resCtx.reservation.OurSignatures()
 	ourCommitSig, err := lnwire.NewSigFromRawSignature(sig)
 	if err != nil {
 		fndgLog.Errorf("unable to parse signature: %v", err)
@@ -1472,7 +2221,29 @@ func (f *fundingManager) handleFundingCreated(fmsg *fundingCreatedMsg) {
 		ChanID:    channelID,
 		CommitSig: ourCommitSig,
 	}
-	if err := fmsg.peer.SendMessage(false, fundingSigned); err != nil {
+
+	// If we contributed to the funding transaction, the initiator needs
+	// the scripts spending our inputs in order to complete it, so we'll
+	// send them ahead of our signature.
+	var msgs []lnwire.Message
+	if dualFund {
+		fundingWitnesses := &lnwire.FundingWitnesses{
+			ChanID: channelID,
+		}
+		for _, inputScript := range inputScripts {
+			fundingWitnesses.InputScripts = append(
+				fundingWitnesses.InputScripts,
+				lnwire.FundingInputScript{
+					SigScript: inputScript.SigScript,
+					Witness:   inputScript.Witness,
+				},
+			)
+		}
+		msgs = append(msgs, fundingWitnesses)
+	}
+	msgs = append(msgs, fundingSigned)
+
+	if err := fmsg.peer.SendMessage(false, msgs...); err != nil {
 		fndgLog.Errorf("unable to send FundingSigned message: %v", err)
 		f.failFundingFlow(fmsg.peer, pendingChanID, err)
 		deleteFromDatabase()
@@ -1513,6 +2284,18 @@ func (f *fundingManager) handleFundingCreated(fmsg *fundingCreatedMsg) {
 	f.wg.Add(1)
 	go func() {
 		defer f.wg.Done()
+
+		// A zero-conf channel is usable right away, under its alias.
+		// Should this fail, the channel is only opened once its
+		// funding transaction confirms.
+		if isZeroConf(completeChan) {
+			err := f.openZeroConfChannel(fmsg.peer, completeChan)
+			if err != nil {
+				fndgLog.Errorf("Failed to open zero-conf "+
+					"channel: %v", err)
+			}
+		}
+
 		confChan := make(chan *lnwire.ShortChannelID)
 		timeoutChan := make(chan struct{})
 		go f.waitForFundingWithTimeout(completeChan, confChan,
@@ -1607,12 +2390,33 @@ func (f *fundingManager) handleFundingSigned(fmsg *fundingSignedMsg) {
 	f.localDiscoverySignals[permChanID] = make(chan struct{})
 	f.localDiscoveryMtx.Unlock()
 
+	// If the remote peer contributed to the funding transaction, the
+	// scripts spending its inputs must have arrived ahead of its
+	// signature, as we can't complete the funding transaction otherwise.
+	var inputScripts []*input.Script
+	if resCtx.remoteFundingAmt != 0 {
+		if len(resCtx.remoteInputScripts) == 0 {
+			err := fmt.Errorf("missing funding input scripts of "+
+				"peer(%x)", peerKey.SerializeCompressed())
+			fndgLog.Errorf(err.Error())
+			f.failFundingFlow(fmsg.peer, pendingChanID, err)
+			return
+		}
+
+		for _, inputScript := range resCtx.remoteInputScripts {
+			inputScripts = append(inputScripts, &input.Script{
+				SigScript: inputScript.SigScript,
+				Witness:   inputScript.Witness,
+			})
+		}
+	}
+
 	// The remote peer has responded with a signature for our commitment
 	// transaction. We'll verify the signature for validity, then commit
 	// the state to disk as we can now open the channel.
 	commitSig := fmsg.msg.CommitSig.ToSignatureBytes()
 	completeChan, err := resCtx.reservation.CompleteReservation(
-		nil, commitSig,
+		inputScripts, commitSig,
 	)
 	if err != nil {
 		fndgLog.Errorf("Unable to complete reservation sign "+
@@ -1625,21 +2429,26 @@ func (f *fundingManager) handleFundingSigned(fmsg *fundingSignedMsg) {
 	// delete it from our set of active reservations.
 	f.deleteReservationCtx(peerKey, pendingChanID)
 
-	// Broadcast the finalized funding transaction to the network.
-	fundingTx := completeChan.FundingTxn
-	fndgLog.Infof("Broadcasting funding tx for ChannelPoint(%v): %v",
-		completeChan.FundingOutpoint, spew.Sdump(fundingTx))
+	// Broadcast the finalized funding transaction to the network, unless
+	// it's published on the channel's behalf.
+	if completeChan.ChanType.HasFundingTx() {
+		fundingTx := completeChan.FundingTxn
+		fndgLog.Infof("Broadcasting funding tx for ChannelPoint(%v): "+
+			"%v", completeChan.FundingOutpoint,
+			spew.Sdump(fundingTx))
 
-	err = f.cfg.PublishTransaction(fundingTx)
-	if err != nil {
-		fndgLog.Errorf("Unable to broadcast funding tx for "+
-			"ChannelPoint(%v): %v", completeChan.FundingOutpoint,
-			err)
-		// We failed to broadcast the funding transaction, but watch
-		// the channel regardless, in case the transaction made it to
-		// the network. We will retry broadcast at startup.
-		// TODO(halseth): retry more often? Handle with CPFP? Just
-		// delete from the DB?
+		err = f.cfg.PublishTransaction(fundingTx)
+		if err != nil {
+			fndgLog.Errorf("Unable to broadcast funding tx for "+
+				"ChannelPoint(%v): %v",
+				completeChan.FundingOutpoint, err)
+			// We failed to broadcast the funding transaction, but
+			// watch the channel regardless, in case the
+			// transaction made it to the network. We will retry
+			// broadcast at startup.
+			// TODO(halseth): retry more often? Handle with CPFP?
+			// Just delete from the DB?
+		}
 	}
 
 	// Now that we have a finalized reservation for this funding flow,
@@ -1679,6 +2488,17 @@ func (f *fundingManager) handleFundingSigned(fmsg *fundingSignedMsg) {
 	f.wg.Add(1)
 	go func() {
 		defer f.wg.Done()
+
+		// Likewise, we'll use a zero-conf channel under its alias
+		// until its funding transaction confirms.
+		if isZeroConf(completeChan) {
+			err := f.openZeroConfChannel(fmsg.peer, completeChan)
+			if err != nil {
+				fndgLog.Errorf("Failed to open zero-conf "+
+					"channel: %v", err)
+			}
+		}
+
 		confChan := make(chan *lnwire.ShortChannelID)
 		cancelChan := make(chan struct{})
 
@@ -1768,6 +2588,84 @@ func (f *fundingManager) handleFundingSigned(fmsg *fundingSignedMsg) {
 	}()
 }
 
+// processFundingContribution sends a message to the fundingManager allowing it
+// to record the remote party's contribution to a dual funded channel.
+func (f *fundingManager) processFundingContribution(
+	msg *lnwire.FundingContribution, peer lnpeer.Peer) {
+
+	select {
+	case f.fundingMsgs <- &fundingContributionMsg{msg, peer}:
+	case <-f.quit:
+		return
+	}
+}
+
+// handleFundingContribution records the remote party's contribution to a dual
+// funded channel. If we initiated the channel, the contribution is attached to
+// the reservation, to be processed along with the AcceptChannel message that
+// follows it. Otherwise, it's kept until the OpenChannel message that follows
+// it arrives.
+func (f *fundingManager) handleFundingContribution(
+	fmsg *fundingContributionMsg) {
+
+	peerKey := fmsg.peer.IdentityKey()
+	pendingChanID := fmsg.msg.PendingChannelID
+
+	fndgLog.Debugf("Recv'd funding contribution of %v for pendingID(%x) "+
+		"from peer(%x)", fmsg.msg.FundingAmount, pendingChanID[:],
+		peerKey.SerializeCompressed())
+
+	resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+	if err == nil {
+		resCtx.remoteContribution = fmsg.msg
+		return
+	}
+
+	f.resMtx.Lock()
+	f.pendingContributions[newSerializedKey(peerKey)] = fmsg.msg
+	f.resMtx.Unlock()
+}
+
+// processFundingWitnesses sends a message to the fundingManager allowing it to
+// record the scripts spending the remote party's inputs to the funding
+// transaction of a dual funded channel.
+func (f *fundingManager) processFundingWitnesses(msg *lnwire.FundingWitnesses,
+	peer lnpeer.Peer) {
+
+	select {
+	case f.fundingMsgs <- &fundingWitnessesMsg{msg, peer}:
+	case <-f.quit:
+		return
+	}
+}
+
+// handleFundingWitnesses attaches the scripts spending the responder's inputs
+// to the funding transaction of a dual funded channel we initiated to its
+// reservation, such that the funding transaction can be completed once the
+// FundingSigned message following them arrives.
+func (f *fundingManager) handleFundingWitnesses(fmsg *fundingWitnessesMsg) {
+	// Just like the FundingSigned message, the witnesses reference the
+	// reservation by its permanent channel ID.
+	f.resMtx.RLock()
+	pendingChanID, ok := f.signedReservations[fmsg.msg.ChanID]
+	f.resMtx.RUnlock()
+	if !ok {
+		fndgLog.Warnf("Unable to find signed reservation for "+
+			"chan_id=%x", fmsg.msg.ChanID)
+		return
+	}
+
+	peerKey := fmsg.peer.IdentityKey()
+	resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+	if err != nil {
+		fndgLog.Warnf("Unable to find reservation (peerID:%v, "+
+			"chanID:%x)", peerKey, pendingChanID[:])
+		return
+	}
+
+	resCtx.remoteInputScripts = fmsg.msg.InputScripts
+}
+
 // waitForFundingWithTimeout is a wrapper around waitForFundingConfirmation that
 // will cancel the wait for confirmation if we are not the channel initiator and
 // the maxWaitNumBlocksFundingConf has passed from bestHeight.
@@ -1886,6 +2784,13 @@ func (f *fundingManager) waitForFundingConfirmation(completeChan *channeldb.Open
 		return
 	}
 	numConfs := uint32(completeChan.NumConfsRequired)
+
+	// A zero-conf channel is in use already, but the first confirmation
+	// of its funding transaction determines its real short channel ID.
+	if numConfs == 0 {
+		numConfs = 1
+	}
+
 	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(
 		&txid, fundingScript, numConfs, completeChan.FundingBroadcastHeight,
 	)
@@ -1941,6 +2846,7 @@ func (f *fundingManager) waitForFundingConfirmation(completeChan *channeldb.Open
 
 	// Now that the channel has been fully confirmed, we'll mark it as open
 	// within the database.
+	wasPending := completeChan.IsPending
 	if err := completeChan.MarkAsOpen(shortChanID); err != nil {
 		fndgLog.Errorf("error setting channel pending flag to false: "+
 			"%v", err)
@@ -1948,8 +2854,10 @@ func (f *fundingManager) waitForFundingConfirmation(completeChan *channeldb.Open
 	}
 
 	// Inform the ChannelNotifier that the channel has transitioned from
-	// pending open to open.
-	f.cfg.NotifyOpenChannelEvent(completeChan.FundingOutpoint)
+	// pending open to open, unless it did so under its alias already.
+	if wasPending {
+		f.cfg.NotifyOpenChannelEvent(completeChan.FundingOutpoint)
+	}
 
 	// TODO(roasbeef): ideally persistent state update for chan above
 	// should be abstracted
@@ -2032,6 +2940,93 @@ func (f *fundingManager) handleFundingConfirmation(peer lnpeer.Peer,
 	return nil
 }
 
+// openZeroConfChannel marks a zero-conf channel open under its alias short
+// channel ID, and sends the fundingLocked message to the peer, such that the
+// channel is usable before its funding transaction confirms.
+func (f *fundingManager) openZeroConfChannel(peer lnpeer.Peer,
+	completeChan *channeldb.OpenChannel) error {
+
+	chanID := lnwire.NewChanIDFromOutPoint(&completeChan.FundingOutpoint)
+	alias := lnwire.NewAliasShortChanID(chanID)
+
+	// The opening state is saved first, so that we resume opening the
+	// channel under its alias in case we restart before it's marked
+	// open.
+	err := f.saveChannelOpeningState(
+		&completeChan.FundingOutpoint, zeroConfOpen, &alias,
+	)
+	if err != nil {
+		return fmt.Errorf("error setting channel state to "+
+			"zeroConfOpen: %v", err)
+	}
+
+	if completeChan.IsPending {
+		if err := completeChan.MarkAsOpen(alias); err != nil {
+			return fmt.Errorf("error setting channel pending flag "+
+				"to false: %v", err)
+		}
+
+		fndgLog.Infof("Zero-conf ChannelPoint(%v) is now active under "+
+			"alias short_chan_id=%v", completeChan.FundingOutpoint,
+			alias)
+
+		f.cfg.NotifyOpenChannelEvent(completeChan.FundingOutpoint)
+	}
+
+	// With the channel marked open, the fundingLocked message of the peer
+	// may be processed.
+	f.localDiscoveryMtx.Lock()
+	if discoverySignal, ok := f.localDiscoverySignals[chanID]; ok {
+		close(discoverySignal)
+		delete(f.localDiscoverySignals, chanID)
+	}
+	f.localDiscoveryMtx.Unlock()
+
+	lnChannel, err := lnwallet.NewLightningChannel(
+		nil, nil, completeChan, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return f.sendFundingLocked(peer, completeChan, lnChannel, &alias)
+}
+
+// waitForZeroConfConfirmation waits for the funding transaction of a zero-conf
+// channel that is in use under its alias to confirm, after which the channel
+// proceeds under its real short channel ID.
+func (f *fundingManager) waitForZeroConfConfirmation(peer lnpeer.Peer,
+	completeChan *channeldb.OpenChannel) {
+
+	confChan := make(chan *lnwire.ShortChannelID)
+	cancelChan := make(chan struct{})
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.waitForFundingConfirmation(completeChan, cancelChan, confChan)
+	}()
+
+	var shortChanID *lnwire.ShortChannelID
+	var ok bool
+	select {
+	case <-f.quit:
+		return
+	case shortChanID, ok = <-confChan:
+		if !ok {
+			fndgLog.Errorf("waiting for funding confirmation" +
+				" failed")
+			return
+		}
+	}
+
+	err := f.handleFundingConfirmation(peer, completeChan, shortChanID)
+	if err != nil {
+		fndgLog.Errorf("Failed to handle funding confirmation: %v",
+			err)
+	}
+}
+
 // sendFundingLocked creates and sends the fundingLocked message.
 // This should be called after the funding transaction has been confirmed,
 // and the channelState is 'markedOpen'.
@@ -2092,6 +3087,13 @@ func (f *fundingManager) sendFundingLocked(peer lnpeer.Peer,
 		}
 	}
 
+	// A zero-conf channel remains in the zeroConfOpen state until its
+	// funding transaction confirms, after which fundingLocked is sent
+	// once more.
+	if shortChanID.IsAlias() {
+		return nil
+	}
+
 	// As the fundingLocked message is now sent to the peer, the channel is
 	// moved to the next state of the state machine. It will be moved to the
 	// last state (actually deleted from the database) after the channel is
@@ -2730,16 +3732,94 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		ourDustLimit = defaultLitecoinfinanceDustLimit
 	}
 
+	// A dust limit requested for this channel may only raise the default
+	// of the chain, as the outputs of our commitment transactions
+	// wouldn't be relayed otherwise. Similar to the channel reserve, it
+	// mustn't exceed 20% of the capacity of the channel.
+	maxConstraint := capacity / 5
+	if msg.dustLimit != 0 {
+		switch {
+		case msg.dustLimit < ourDustLimit:
+			msg.err <- lnwallet.ErrDustLimitTooSmall(
+				msg.dustLimit, ourDustLimit,
+			)
+			return
+
+		case msg.dustLimit > maxConstraint:
+			msg.err <- lnwallet.ErrDustLimitTooLarge(
+				msg.dustLimit, maxConstraint,
+			)
+			return
+		}
+
+		ourDustLimit = msg.dustLimit
+	}
+
+	// The channel reserve we require of the remote party is capped the
+	// same way the remote party would cap it. Whether it covers the dust
+	// limit of the remote party can only be checked once it accepted the
+	// channel.
+	if msg.remoteChanReserve > maxConstraint {
+		msg.err <- lnwallet.ErrChanReserveTooLarge(
+			msg.remoteChanReserve, maxConstraint,
+		)
+		return
+	}
+
 	fndgLog.Infof("Initiating fundingRequest(localAmt=%v, remoteAmt=%v, "+
 		"capacity=%v, chainhash=%v, peer=%x, dustLimit=%v, min_confs=%v)",
 		localAmt, msg.pushAmt, capacity, msg.chainHash,
 		peerKey.SerializeCompressed(), ourDustLimit, msg.minConfs)
 
+	// If we request the peer to contribute funds to the channel, it must
+	// support dual funding. As the peer's funds only become part of the
+	// channel, there's nothing for us to push to it either.
+	dualFund := remoteAmt != 0
+	if dualFund {
+		remoteFeatures := msg.peer.RemoteLocalFeatures()
+		switch {
+		case !remoteFeatures.HasFeature(lnwire.DualFundingOptional):
+			msg.err <- fmt.Errorf("peer(%x) does not support dual "+
+				"funding", peerKey.SerializeCompressed())
+			return
+
+		case msg.pushAmt != 0:
+			msg.err <- fmt.Errorf("non-zero push amount of %v for "+
+				"dual funded channel", msg.pushAmt)
+			return
+
+		case msg.psbtFunding:
+			msg.err <- errors.New("dual funded channels can't be " +
+				"funded externally")
+			return
+		}
+	}
+
+	// A zero-conf channel counts towards our exposure to unconfirmed
+	// funding transactions, which is limited.
+	if msg.zeroConf {
+		if dualFund {
+			msg.err <- errors.New("zero-conf channels can't be " +
+				"dual funded")
+			return
+		}
+
+		if err := f.checkZeroConfExposure(capacity); err != nil {
+			msg.err <- err
+			return
+		}
+	}
+
 	// First, we'll query the fee estimator for a fee that should get the
 	// commitment transaction confirmed by the next few blocks (conf target
 	// of 3). We target the near blocks here to ensure that we'll be able
-	// to execute a timely unilateral channel closure if needed.
-	commitFeePerKw, err := f.cfg.FeeEstimator.EstimateFeePerKW(3)
+	// to execute a timely unilateral channel closure if needed. The
+	// estimate is kept within our commitment fee bounds, just like the
+	// fee updates of the channel later on.
+	commitFees := lnwallet.NewCommitFeeManager(
+		f.cfg.FeeEstimator, f.cfg.CommitFeeBounds,
+	)
+	commitFeePerKw, err := commitFees.EstimateFeeRate(3)
 	if err != nil {
 		msg.err <- err
 		return
@@ -2752,6 +3832,12 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		// This channel will be announced.
 		channelFlags = lnwire.FFAnnounceChannel
 	}
+	if dualFund {
+		channelFlags |= lnwire.FFDualFund
+	}
+	if msg.zeroConf {
+		channelFlags |= lnwire.FFZeroConf
+	}
 
 	// Initialize a funding reservation with the local wallet. If the
 	// wallet doesn't have enough funds to commit to this channel, then the
@@ -2767,6 +3853,12 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		PushMSat:        msg.pushAmt,
 		Flags:           channelFlags,
 		MinConfs:        msg.minConfs,
+		Anchors:         useAnchors(msg.peer),
+		ExternalFunding: msg.psbtFunding,
+		NoFundingTx:     msg.noFundingTx,
+		DustLimit:       ourDustLimit,
+
+		CoinSelectionStrategy: msg.coinSelectionStrategy,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -2804,13 +3896,17 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 	}
 
 	resCtx := &reservationWithCtx{
-		chanAmt:        capacity,
-		remoteCsvDelay: remoteCsvDelay,
-		remoteMinHtlc:  minHtlc,
-		reservation:    reservation,
-		peer:           msg.peer,
-		updates:        msg.updates,
-		err:            msg.err,
+		chanAmt:           capacity,
+		remoteFundingAmt:  remoteAmt,
+		remoteCsvDelay:    remoteCsvDelay,
+		remoteMinHtlc:     minHtlc,
+		remoteChanReserve: msg.remoteChanReserve,
+		psbtFunding:       msg.psbtFunding,
+		zeroConf:          msg.zeroConf,
+		reservation:       reservation,
+		peer:              msg.peer,
+		updates:           msg.updates,
+		err:               msg.err,
 	}
 	f.activeReservations[peerIDKey][chanID] = resCtx
 	f.resMtx.Unlock()
@@ -2825,7 +3921,12 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 	// Finally, we'll use the current value of the channels and our default
 	// policy to determine of required commitment constraints for the
 	// remote party.
-	chanReserve := f.cfg.RequiredRemoteChanReserve(capacity, ourDustLimit)
+	chanReserve := msg.remoteChanReserve
+	if chanReserve == 0 {
+		chanReserve = f.cfg.RequiredRemoteChanReserve(
+			capacity, ourDustLimit,
+		)
+	}
 	maxValue := f.cfg.RequiredRemoteMaxValue(capacity)
 	maxHtlcs := f.cfg.RequiredRemoteMaxHTLCs(capacity)
 
@@ -2852,7 +3953,19 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		FirstCommitmentPoint: ourContribution.FirstCommitmentPoint,
 		ChannelFlags:         channelFlags,
 	}
-	if err := msg.peer.SendMessage(false, &fundingOpen); err != nil {
+
+	// Within a dual funder workflow, our contribution precedes the
+	// OpenChannel message, such that the peer is able to decide on
+	// contributing to the channel once it receives the latter.
+	var msgs []lnwire.Message
+	if dualFund {
+		msgs = append(msgs, newFundingContribution(
+			chanID, localAmt, ourContribution,
+		))
+	}
+	msgs = append(msgs, &fundingOpen)
+
+	if err := msg.peer.SendMessage(false, msgs...); err != nil {
 		e := fmt.Errorf("Unable to send funding request message: %v",
 			err)
 		fndgLog.Errorf(e.Error())
@@ -3043,6 +4156,24 @@ func (f *fundingManager) deleteReservationCtx(peerKey *btcec.PublicKey,
 	}
 }
 
+// findReservationCtx returns the reservation context with the given pending
+// channel ID, regardless of the peer it's negotiated with, or nil if there's
+// no such reservation.
+func (f *fundingManager) findReservationCtx(
+	pendingChanID [32]byte) *reservationWithCtx {
+
+	f.resMtx.RLock()
+	defer f.resMtx.RUnlock()
+
+	for _, pendingChans := range f.activeReservations {
+		if resCtx, ok := pendingChans[pendingChanID]; ok {
+			return resCtx
+		}
+	}
+
+	return nil
+}
+
 // getReservationCtx returns the reservation context for a particular pending
 // channel ID for a target peer.
 func (f *fundingManager) getReservationCtx(peerKey *btcec.PublicKey,
@@ -3077,6 +4208,157 @@ func (f *fundingManager) IsPendingChannel(pendingChanID [32]byte,
 	return ok
 }
 
+// isZeroConf returns true if the channel is usable before its funding
+// transaction confirms, as the responder didn't require any confirmations.
+func isZeroConf(channel *channeldb.OpenChannel) bool {
+	return channel.NumConfsRequired == 0
+}
+
+// validateZeroConfOpen returns an error if we don't accept the zero-conf
+// channel the remote party requests to open.
+func (f *fundingManager) validateZeroConfOpen(msg *lnwire.OpenChannel) error {
+	switch {
+	case !cfg.Protocol.ZeroConf:
+		return lnwallet.ErrZeroConfDisabled()
+
+	// If we contributed funds to the channel, we'd risk losing them.
+	case msg.ChannelFlags&lnwire.FFDualFund == lnwire.FFDualFund:
+		return lnwallet.ErrChannelRejected(
+			"zero-conf channels can't be dual funded",
+		)
+	}
+
+	return f.checkZeroConfExposure(msg.FundingAmount)
+}
+
+// checkZeroConfExposure returns an error if a zero-conf channel of the given
+// capacity would raise our exposure to zero-conf channels beyond the maximum.
+func (f *fundingManager) checkZeroConfExposure(capacity btcutil.Amount) error {
+	exposure, err := f.zeroConfExposure()
+	if err != nil {
+		return err
+	}
+
+	maxExposure := btcutil.Amount(cfg.Protocol.MaxZeroConfExposure)
+	if exposure+capacity > maxExposure {
+		return lnwallet.ErrZeroConfExposureTooLarge(
+			capacity, exposure, maxExposure,
+		)
+	}
+
+	return nil
+}
+
+// zeroConfExposure returns the total capacity of our zero-conf channels whose
+// funding transaction has yet to confirm, including those of which the
+// funding flow is still in progress.
+func (f *fundingManager) zeroConfExposure() (btcutil.Amount, error) {
+	var exposure btcutil.Amount
+
+	f.resMtx.RLock()
+	for _, pendingChans := range f.activeReservations {
+		for _, resCtx := range pendingChans {
+			if resCtx.zeroConf {
+				exposure += resCtx.chanAmt
+			}
+		}
+	}
+	f.resMtx.RUnlock()
+
+	channels, err := f.cfg.Wallet.Cfg.Database.FetchAllChannels()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, channel := range channels {
+		if !isZeroConf(channel) {
+			continue
+		}
+
+		if channel.IsPending || channel.ShortChanID().IsAlias() {
+			exposure += channel.Capacity
+		}
+	}
+
+	return exposure, nil
+}
+
+// useAnchors returns true if new channels with the given peer should use the
+// commitment format with anchor outputs, which requires both us and the peer
+// to signal support for it.
+func useAnchors(peer lnpeer.Peer) bool {
+	return cfg.Protocol.Anchors &&
+		peer.RemoteLocalFeatures().HasFeature(lnwire.AnchorsOptional)
+}
+
+// newFundingContribution creates the FundingContribution message announcing
+// the inputs and change outputs of the given contribution to the funding
+// transaction of a dual funded channel, to which we contribute amt.
+func newFundingContribution(pendingChanID [32]byte, amt btcutil.Amount,
+	c *lnwallet.ChannelContribution) *lnwire.FundingContribution {
+
+	msg := &lnwire.FundingContribution{
+		PendingChannelID: pendingChanID,
+		FundingAmount:    amt,
+	}
+	for i, txIn := range c.Inputs {
+		msg.Inputs = append(msg.Inputs, lnwire.FundingInput{
+			OutPoint: txIn.PreviousOutPoint,
+			Value:    btcutil.Amount(c.PrevOutputs[i].Value),
+			PkScript: c.PrevOutputs[i].PkScript,
+		})
+	}
+	for _, txOut := range c.ChangeOutputs {
+		output := lnwire.FundingOutput{
+			Value:    btcutil.Amount(txOut.Value),
+			PkScript: txOut.PkScript,
+		}
+		msg.ChangeOutputs = append(msg.ChangeOutputs, output)
+	}
+
+	return msg
+}
+
+// addFundingContribution adds the inputs and change outputs of the remote
+// party's FundingContribution message to its contribution. The message is
+// sanity checked on the way, while verifying the inputs against the UTXO set
+// is left to the wallet.
+func addFundingContribution(c *lnwallet.ChannelContribution,
+	msg *lnwire.FundingContribution) error {
+
+	if len(msg.Inputs) == 0 {
+		return lnwallet.ErrInvalidContribution("no inputs")
+	}
+
+	var inputsValue, changeValue btcutil.Amount
+	for _, fundingInput := range msg.Inputs {
+		outPoint := fundingInput.OutPoint
+		c.Inputs = append(c.Inputs, wire.NewTxIn(&outPoint, nil, nil))
+		c.PrevOutputs = append(c.PrevOutputs, &wire.TxOut{
+			Value:    int64(fundingInput.Value),
+			PkScript: fundingInput.PkScript,
+		})
+
+		inputsValue += fundingInput.Value
+	}
+	for _, output := range msg.ChangeOutputs {
+		c.ChangeOutputs = append(c.ChangeOutputs, &wire.TxOut{
+			Value:    int64(output.Value),
+			PkScript: output.PkScript,
+		})
+
+		changeValue += output.Value
+	}
+
+	if inputsValue-changeValue < msg.FundingAmount {
+		return lnwallet.ErrInvalidContribution(fmt.Sprintf("inputs "+
+			"of %v minus change of %v don't cover amount of %v",
+			inputsValue, changeValue, msg.FundingAmount))
+	}
+
+	return nil
+}
+
 func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
 	return &btcec.PublicKey{
 		Curve: btcec.S256(),
@@ -3089,7 +4371,7 @@ func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
 // chanPoint to the channelOpeningStateBucket.
 func (f *fundingManager) saveChannelOpeningState(chanPoint *wire.OutPoint,
 	state channelOpeningState, shortChanID *lnwire.ShortChannelID) error {
-	return f.cfg.Wallet.Cfg.Database.Update(func(tx *bbolt.Tx) error {
+	return f.cfg.Wallet.Cfg.Database.Update(func(tx kvdb.Tx) error {
 
 		bucket, err := tx.CreateBucketIfNotExists(channelOpeningStateBucket)
 		if err != nil {
@@ -3119,7 +4401,7 @@ func (f *fundingManager) getChannelOpeningState(chanPoint *wire.OutPoint) (
 
 	var state channelOpeningState
 	var shortChanID lnwire.ShortChannelID
-	err := f.cfg.Wallet.Cfg.Database.View(func(tx *bbolt.Tx) error {
+	err := f.cfg.Wallet.Cfg.Database.View(func(tx kvdb.Tx) error {
 
 		bucket := tx.Bucket(channelOpeningStateBucket)
 		if bucket == nil {
@@ -3151,7 +4433,7 @@ func (f *fundingManager) getChannelOpeningState(chanPoint *wire.OutPoint) (
 
 // deleteChannelOpeningState removes any state for chanPoint from the database.
 func (f *fundingManager) deleteChannelOpeningState(chanPoint *wire.OutPoint) error {
-	return f.cfg.Wallet.Cfg.Database.Update(func(tx *bbolt.Tx) error {
+	return f.cfg.Wallet.Cfg.Database.Update(func(tx kvdb.Tx) error {
 		bucket := tx.Bucket(channelOpeningStateBucket)
 		if bucket == nil {
 			return fmt.Errorf("Bucket not found")
@@ -27,6 +27,7 @@ import (
 	"github.com/litecoinfinance/lnd/htlcswitch"
 	"github.com/litecoinfinance/lnd/input"
 	"github.com/litecoinfinance/lnd/keychain"
+	"github.com/litecoinfinance/lnd/lncfg"
 	"github.com/litecoinfinance/lnd/lnpeer"
 	"github.com/litecoinfinance/lnd/lnrpc"
 	"github.com/litecoinfinance/lnd/lnwallet"
@@ -183,6 +184,10 @@ func (n *testNode) QuitSignal() <-chan struct{} {
 	return n.shutdownChannel
 }
 
+func (n *testNode) RemoteLocalFeatures() *lnwire.FeatureVector {
+	return lnwire.NewFeatureVector(nil, lnwire.LocalFeatures)
+}
+
 func (n *testNode) AddNewChannel(channel *channeldb.OpenChannel,
 	quit <-chan struct{}) error {
 
@@ -472,6 +477,7 @@ func setupFundingManagers(t *testing.T, maxPendingChannels int) (*testNode, *tes
 	// MaxPendingChannels, and it is usually set in lndMain().
 	cfg = &config{
 		MaxPendingChannels: maxPendingChannels,
+		Protocol:           &lncfg.Protocol{},
 	}
 
 	aliceTestDir, err := ioutil.TempDir("", "alicelnwallet")
@@ -2379,6 +2385,132 @@ func TestFundingManagerCustomChannelParameters(t *testing.T) {
 	waitForOpenUpdate(t, updateChan)
 }
 
+// TestFundingManagerCustomDustLimitAndReserve checks that a custom dust limit
+// and remote channel reserve requested for a channel are sent to the remote
+// party and committed to the reservation, and that insane values are
+// rejected before the funding flow starts.
+func TestFundingManagerCustomDustLimitAndReserve(t *testing.T) {
+	alice, bob := setupFundingManagers(t, defaultMaxPendingChannels)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	const (
+		localAmt          = btcutil.Amount(5000000)
+		dustLimit         = btcutil.Amount(10000)
+		remoteChanReserve = btcutil.Amount(200000)
+	)
+
+	// newInitReq creates a funding request with the given dust limit and
+	// remote channel reserve.
+	newInitReq := func(dustLimit,
+		remoteChanReserve btcutil.Amount) *openChanReq {
+
+		return &openChanReq{
+			targetPubkey:      bob.privKey.PubKey(),
+			chainHash:         *activeNetParams.GenesisHash,
+			localFundingAmt:   localAmt,
+			pushAmt:           lnwire.NewMSatFromSatoshis(0),
+			dustLimit:         dustLimit,
+			remoteChanReserve: remoteChanReserve,
+			updates:           make(chan *lnrpc.OpenStatusUpdate, 1),
+			err:               make(chan error, 1),
+		}
+	}
+
+	// A dust limit below the default of the chain, as well as a reserve
+	// exceeding 20% of the capacity, must be rejected.
+	insaneReqs := []*openChanReq{
+		newInitReq(lnwallet.DefaultDustLimit()-1, 0),
+		newInitReq(localAmt/5+1, 0),
+		newInitReq(0, localAmt/5+1),
+	}
+	for _, initReq := range insaneReqs {
+		alice.fundingMgr.initFundingWorkflow(bob, initReq)
+
+		select {
+		case err := <-initReq.err:
+			if _, ok := err.(lnwallet.ReservationError); !ok {
+				t.Fatalf("expected ReservationError, got %v",
+					err)
+			}
+		case msg := <-alice.msgChan:
+			t.Fatalf("expected funding request to fail, "+
+				"instead got %T", msg)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("funding request didn't fail")
+		}
+	}
+
+	initReq := newInitReq(dustLimit, remoteChanReserve)
+	alice.fundingMgr.initFundingWorkflow(bob, initReq)
+
+	// Alice should have sent the OpenChannel message to Bob, which
+	// carries the custom constraints.
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-initReq.err:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel message")
+	}
+
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+	if !ok {
+		t.Fatalf("expected OpenChannel to be sent from alice, "+
+			"instead got %T", aliceMsg)
+	}
+	if openChannelReq.DustLimit != dustLimit {
+		t.Fatalf("expected OpenChannel to have dust limit %v, got %v",
+			dustLimit, openChannelReq.DustLimit)
+	}
+	if openChannelReq.ChannelReserve != remoteChanReserve {
+		t.Fatalf("expected OpenChannel to have reserve %v, got %v",
+			remoteChanReserve, openChannelReq.ChannelReserve)
+	}
+
+	chanID := openChannelReq.PendingChannelID
+
+	// Let Bob accept the channel, and forward his response to Alice.
+	bob.fundingMgr.processFundingOpen(openChannelReq, alice)
+	acceptChannelResponse := assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+
+	alice.fundingMgr.processFundingAccept(acceptChannelResponse, bob)
+	assertFundingMsgSent(t, alice.msgChan, "FundingCreated")
+
+	// Both reservations should now reflect the custom constraints.
+	resCtx, err := alice.fundingMgr.getReservationCtx(bobPubKey, chanID)
+	if err != nil {
+		t.Fatalf("unable to find ctx: %v", err)
+	}
+	ourDustLimit := resCtx.reservation.OurContribution().DustLimit
+	if ourDustLimit != dustLimit {
+		t.Fatalf("expected alice's dust limit to be %v, was %v",
+			dustLimit, ourDustLimit)
+	}
+	theirReserve := resCtx.reservation.TheirContribution().ChanReserve
+	if theirReserve != remoteChanReserve {
+		t.Fatalf("expected bob's reserve to be %v, was %v",
+			remoteChanReserve, theirReserve)
+	}
+
+	resCtx, err = bob.fundingMgr.getReservationCtx(alicePubKey, chanID)
+	if err != nil {
+		t.Fatalf("unable to find ctx: %v", err)
+	}
+	theirDustLimit := resCtx.reservation.TheirContribution().DustLimit
+	if theirDustLimit != dustLimit {
+		t.Fatalf("expected alice's dust limit to be %v, was %v",
+			dustLimit, theirDustLimit)
+	}
+	ourReserve := resCtx.reservation.OurContribution().ChanReserve
+	if ourReserve != remoteChanReserve {
+		t.Fatalf("expected bob's reserve to be %v, was %v",
+			remoteChanReserve, ourReserve)
+	}
+}
+
 // TestFundingManagerMaxPendingChannels checks that trying to open another
 // channel with the same peer when MaxPending channels are pending fails.
 func TestFundingManagerMaxPendingChannels(t *testing.T) {
@@ -2600,6 +2732,114 @@ func TestFundingManagerRejectPush(t *testing.T) {
 	}
 }
 
+// TestFundingManagerChannelAcceptor checks that channels proposed by a peer
+// are subject to the decision of the channel acceptor, and that the
+// constraints it requires of the peer are sent along with AcceptChannel.
+func TestFundingManagerChannelAcceptor(t *testing.T) {
+	alice, bob := setupFundingManagers(t, defaultMaxPendingChannels)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	requests := make(chan *channelAcceptRequest)
+	decisions := make(chan *channelAcceptResponse)
+	bob.fundingMgr.cfg.ChannelAcceptor = func(
+		req *channelAcceptRequest) *channelAcceptResponse {
+
+		requests <- req
+		return <-decisions
+	}
+
+	// decide starts a funding workflow from Alice to Bob, and responds to
+	// the request Bob's channel acceptor receives with the given decision.
+	decide := func(decision *channelAcceptResponse) {
+		initReq := &openChanReq{
+			targetPubkey:    bob.privKey.PubKey(),
+			chainHash:       *activeNetParams.GenesisHash,
+			localFundingAmt: 500000,
+			pushAmt:         lnwire.NewMSatFromSatoshis(0),
+			updates:         make(chan *lnrpc.OpenStatusUpdate, 1),
+			err:             make(chan error, 1),
+		}
+		alice.fundingMgr.initFundingWorkflow(bob, initReq)
+
+		var openChannelReq *lnwire.OpenChannel
+		select {
+		case msg := <-alice.msgChan:
+			openChannelReq = msg.(*lnwire.OpenChannel)
+		case err := <-initReq.err:
+			t.Fatalf("error init funding workflow: %v", err)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("alice did not send OpenChannel message")
+		}
+
+		bob.fundingMgr.processFundingOpen(openChannelReq, alice)
+
+		select {
+		case req := <-requests:
+			if req.openChan != openChannelReq {
+				t.Fatalf("acceptor received unexpected request")
+			}
+			if !req.nodePubKey.IsEqual(alicePubKey) {
+				t.Fatalf("acceptor received request of "+
+					"unexpected node %x",
+					req.nodePubKey.SerializeCompressed())
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("acceptor did not receive request")
+		}
+
+		decisions <- decision
+	}
+
+	// A rejected channel should fail the funding flow, sending the reason
+	// to Alice.
+	decide(&channelAcceptResponse{reason: "no thanks"})
+
+	errMsg := assertFundingMsgSent(t, bob.msgChan, "Error").(*lnwire.Error)
+	if string(errMsg.Data) != "channel rejected: no thanks" {
+		t.Fatalf("unexpected error: %v", string(errMsg.Data))
+	}
+
+	// An accepted channel should proceed, with the constraints of the
+	// acceptor replacing our defaults.
+	decide(&channelAcceptResponse{
+		accept:            true,
+		remoteCsvDelay:    144,
+		remoteChanReserve: 20000,
+		remoteMaxHtlcs:    30,
+		remoteMinHtlc:     2000,
+		minAcceptDepth:    6,
+	})
+
+	acceptChannel := assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+	switch {
+	case acceptChannel.CsvDelay != 144:
+		t.Fatalf("expected CSV delay 144, got %v",
+			acceptChannel.CsvDelay)
+	case acceptChannel.ChannelReserve != 20000:
+		t.Fatalf("expected reserve 20000, got %v",
+			acceptChannel.ChannelReserve)
+	case acceptChannel.MaxAcceptedHTLCs != 30:
+		t.Fatalf("expected max HTLCs 30, got %v",
+			acceptChannel.MaxAcceptedHTLCs)
+	case acceptChannel.HtlcMinimum != 2000:
+		t.Fatalf("expected min HTLC 2000, got %v",
+			acceptChannel.HtlcMinimum)
+	case acceptChannel.MinAcceptDepth != 6:
+		t.Fatalf("expected min accept depth 6, got %v",
+			acceptChannel.MinAcceptDepth)
+	}
+
+	// Constraints that aren't sane for the channel should fail the
+	// funding flow as well.
+	decide(&channelAcceptResponse{
+		accept:            true,
+		remoteChanReserve: 500000,
+	})
+	assertFundingMsgSent(t, bob.msgChan, "Error")
+}
+
 // TestFundingManagerMaxConfs ensures that we don't accept a funding proposal
 // that proposes a MinAcceptDepth greater than the maximum number of
 // confirmations we're willing to accept.
@@ -2668,3 +2908,181 @@ func TestFundingManagerMaxConfs(t *testing.T) {
 			string(err.Data))
 	}
 }
+
+// TestFundingManagerZeroConf checks that a zero-conf channel is usable under
+// its alias before its funding transaction confirms, after which it proceeds
+// like any other channel, and that our exposure to such channels is limited.
+func TestFundingManagerZeroConf(t *testing.T) {
+	alice, bob := setupFundingManagers(t, defaultMaxPendingChannels)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	cfg.Protocol.MaxZeroConfExposure = 600000
+
+	// initZeroConf starts a funding workflow of a zero-conf channel from
+	// Alice to Bob, returning the OpenChannel message Alice sent.
+	initZeroConf := func(updateChan chan *lnrpc.OpenStatusUpdate,
+		errChan chan error) *lnwire.OpenChannel {
+
+		initReq := &openChanReq{
+			targetPubkey:    bob.privKey.PubKey(),
+			chainHash:       *activeNetParams.GenesisHash,
+			localFundingAmt: 500000,
+			pushAmt:         lnwire.NewMSatFromSatoshis(0),
+			zeroConf:        true,
+			updates:         updateChan,
+			err:             errChan,
+		}
+		alice.fundingMgr.initFundingWorkflow(bob, initReq)
+
+		select {
+		case msg := <-alice.msgChan:
+			return msg.(*lnwire.OpenChannel)
+		case err := <-errChan:
+			t.Fatalf("error init funding workflow: %v", err)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("alice did not send OpenChannel message")
+		}
+
+		return nil
+	}
+
+	// As long as Bob doesn't accept zero-conf channels, he should reject
+	// Alice's request.
+	errChan := make(chan error, 1)
+	openChannelReq := initZeroConf(
+		make(chan *lnrpc.OpenStatusUpdate, 1), errChan,
+	)
+	if openChannelReq.ChannelFlags&lnwire.FFZeroConf == 0 {
+		t.Fatalf("expected zero-conf flag to be set")
+	}
+	bob.fundingMgr.processFundingOpen(openChannelReq, alice)
+
+	errMsg := assertFundingMsgSent(t, bob.msgChan, "Error").(*lnwire.Error)
+	if string(errMsg.Data) != "zero-conf channels are disabled" {
+		t.Fatalf("unexpected error: %v", string(errMsg.Data))
+	}
+
+	// Alice's reservation no longer counts towards her exposure once she
+	// handled the error.
+	alice.fundingMgr.processFundingError(errMsg, bobPubKey)
+	select {
+	case <-errChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not fail funding workflow")
+	}
+
+	// Once he does, he should accept it without requiring any
+	// confirmations.
+	cfg.Protocol.ZeroConf = true
+
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	openChannelReq = initZeroConf(updateChan, make(chan error, 1))
+	bob.fundingMgr.processFundingOpen(openChannelReq, alice)
+
+	acceptChannel := assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+	if acceptChannel.MinAcceptDepth != 0 {
+		t.Fatalf("expected min accept depth 0, got %v",
+			acceptChannel.MinAcceptDepth)
+	}
+
+	alice.fundingMgr.processFundingAccept(acceptChannel, bob)
+	fundingCreated := assertFundingMsgSent(
+		t, alice.msgChan, "FundingCreated",
+	).(*lnwire.FundingCreated)
+
+	bob.fundingMgr.processFundingCreated(fundingCreated, alice)
+	fundingSigned := assertFundingMsgSent(
+		t, bob.msgChan, "FundingSigned",
+	).(*lnwire.FundingSigned)
+
+	alice.fundingMgr.processFundingSigned(fundingSigned, bob)
+
+	select {
+	case <-updateChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenStatusUpdate_ChanPending")
+	}
+
+	var publ *wire.MsgTx
+	select {
+	case publ = <-alice.publTxChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not publish funding tx")
+	}
+	fundingOutPoint := &wire.OutPoint{
+		Hash:  publ.TxHash(),
+		Index: 0,
+	}
+
+	// Without the funding transaction confirming, both should send
+	// fundingLocked, having marked the channel open under its alias.
+	fundingLockedAlice := assertFundingMsgSent(
+		t, alice.msgChan, "FundingLocked",
+	).(*lnwire.FundingLocked)
+	fundingLockedBob := assertFundingMsgSent(
+		t, bob.msgChan, "FundingLocked",
+	).(*lnwire.FundingLocked)
+
+	assertDatabaseState(t, alice, fundingOutPoint, zeroConfOpen)
+	assertDatabaseState(t, bob, fundingOutPoint, zeroConfOpen)
+
+	for _, node := range []*testNode{alice, bob} {
+		db := node.fundingMgr.cfg.Wallet.Cfg.Database
+		dbChan, err := db.FetchChannel(*fundingOutPoint)
+		if err != nil {
+			t.Fatalf("unable to fetch channel: %v", err)
+		}
+		if dbChan.IsPending || !dbChan.ShortChanID().IsAlias() {
+			t.Fatalf("expected channel to be open under alias, "+
+				"got short_chan_id=%v", dbChan.ShortChanID())
+		}
+	}
+
+	alice.fundingMgr.processFundingLocked(fundingLockedBob, bob)
+	bob.fundingMgr.processFundingLocked(fundingLockedAlice, alice)
+	assertHandleFundingLocked(t, alice, bob)
+
+	// Another zero-conf channel would exceed the max exposure, as long as
+	// the funding transaction of the first one is unconfirmed.
+	errChan = make(chan error, 1)
+	alice.fundingMgr.initFundingWorkflow(bob, &openChanReq{
+		targetPubkey:    bob.privKey.PubKey(),
+		chainHash:       *activeNetParams.GenesisHash,
+		localFundingAmt: 500000,
+		pushAmt:         lnwire.NewMSatFromSatoshis(0),
+		zeroConf:        true,
+		updates:         make(chan *lnrpc.OpenStatusUpdate, 1),
+		err:             errChan,
+	})
+
+	select {
+	case err := <-errChan:
+		if !strings.Contains(err.Error(), "max zero-conf exposure") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not fail funding workflow")
+	}
+
+	// Once the funding transaction confirms, the channel proceeds under
+	// its real short channel ID, sending fundingLocked once more.
+	alice.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{}
+	bob.mockNotifier.oneConfChannel <- &chainntnfs.TxConfirmation{}
+
+	assertMarkedOpen(t, alice, bob, fundingOutPoint)
+	assertFundingMsgSent(t, alice.msgChan, "FundingLocked")
+	assertFundingMsgSent(t, bob.msgChan, "FundingLocked")
+	assertFundingLockedSent(t, alice, bob, fundingOutPoint)
+
+	assertChannelAnnouncements(t, alice, bob, 500000)
+	assertAddedToRouterGraph(t, alice, bob, fundingOutPoint)
+	waitForOpenUpdate(t, updateChan)
+
+	alice.mockNotifier.sixConfChannel <- &chainntnfs.TxConfirmation{}
+	bob.mockNotifier.sixConfChannel <- &chainntnfs.TxConfirmation{}
+
+	assertAnnouncementSignatures(t, alice, bob)
+	assertNoChannelState(t, alice, bob, fundingOutPoint)
+}
@@ -27,12 +27,14 @@ require (
 	github.com/juju/utils v0.0.0-20180820210520-bf9cc5bdd62d // indirect
 	github.com/juju/version v0.0.0-20180108022336-b64dbd566305 // indirect
 	github.com/kkdai/bstream v0.0.0-20181106074824-b3251f7901ec
+	github.com/lib/pq v1.1.1
 	github.com/litecoinfinance/neutrino v1.0.0
 	github.com/litecoinfinance/lightning-onion v1.0.0
 	github.com/litecoinfinance/lnd/queue v1.0.0
 	github.com/litecoinfinance/lnd/ticker v1.0.0
 	github.com/litecoinfinance/ltfnd v1.0.0
 	github.com/miekg/dns v0.0.0-20171125082028-79bfde677fa8
+	github.com/prometheus/client_golang v0.9.3
 	github.com/rogpeppe/fastuuid v0.0.0-20150106093220-6724a57986af // indirect
 	github.com/tv42/zbase32 v0.0.0-20160707012821-501572607d02
 	github.com/urfave/cli v1.19.1
@@ -22,8 +22,11 @@ type InvoiceDatabase interface {
 	// invoice is a debug invoice, then this method is a noop as debug
 	// invoices are never fully settled. The return value describes how the
 	// htlc should be resolved. If the htlc cannot be resolved immediately,
-	// the resolution is sent on the passed in hodlChan later.
+	// the resolution is sent on the passed in hodlChan later. The expiry
+	// is the height at which the htlc expires. If circuitKey is non-nil,
+	// the htlc is recorded along with the invoice.
 	NotifyExitHopHtlc(payHash lntypes.Hash, paidAmount lnwire.MilliSatoshi,
+		expiry, currentHeight uint32, circuitKey *channeldb.CircuitKey,
 		hodlChan chan<- interface{}) (*invoices.HodlEvent, error)
 
 	// CancelInvoice attempts to cancel the invoice corresponding to the
@@ -0,0 +1,163 @@
+package htlcswitch
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+const (
+	// maxLatencySamples is the maximum number of settlement latency
+	// samples we'll retain for each channel and peer. Once reached, the
+	// oldest samples are discarded, such that the reported percentiles
+	// reflect recent behavior.
+	maxLatencySamples = 1000
+)
+
+// HtlcLatencyStats summarizes the time it took for outgoing HTLCs to be
+// resolved, i.e. the time between us sending an HTLC add and receiving its
+// settle or fail.
+type HtlcLatencyStats struct {
+	// NumSettled is the total number of outgoing HTLCs that were settled.
+	NumSettled uint64
+
+	// NumFailed is the total number of outgoing HTLCs that were failed.
+	NumFailed uint64
+
+	// P50, P90 and P99 are the respective percentiles of the most recent
+	// resolution latencies.
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// latencyWindow retains a bounded window of the most recent latency samples,
+// along with the total number of resolutions.
+type latencyWindow struct {
+	samples    []time.Duration
+	next       int
+	numSettled uint64
+	numFailed  uint64
+}
+
+// add records a new latency sample, overwriting the oldest one if the window
+// is full.
+func (w *latencyWindow) add(latency time.Duration, settled bool) {
+	if settled {
+		w.numSettled++
+	} else {
+		w.numFailed++
+	}
+
+	if len(w.samples) < maxLatencySamples {
+		w.samples = append(w.samples, latency)
+		return
+	}
+
+	w.samples[w.next] = latency
+	w.next = (w.next + 1) % maxLatencySamples
+}
+
+// stats computes the summary of the samples within the window.
+func (w *latencyWindow) stats() HtlcLatencyStats {
+	sorted := make([]time.Duration, len(w.samples))
+	copy(sorted, w.samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return HtlcLatencyStats{
+		NumSettled: w.numSettled,
+		NumFailed:  w.numFailed,
+		P50:        percentile(sorted, 50),
+		P90:        percentile(sorted, 90),
+		P99:        percentile(sorted, 99),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the given sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// LatencyTracker aggregates the resolution latencies of outgoing HTLCs per
+// channel and per peer. This allows operators to identify peers that are slow
+// to settle or fail HTLCs, which degrades the experience of payments routed
+// through us.
+type LatencyTracker struct {
+	mu       sync.Mutex
+	channels map[lnwire.ShortChannelID]*latencyWindow
+	peers    map[[33]byte]*latencyWindow
+}
+
+// NewLatencyTracker creates a new, empty LatencyTracker.
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{
+		channels: make(map[lnwire.ShortChannelID]*latencyWindow),
+		peers:    make(map[[33]byte]*latencyWindow),
+	}
+}
+
+// RecordResolution records the time it took for an outgoing HTLC over the
+// given channel with the given peer to be either settled or failed.
+func (t *LatencyTracker) RecordResolution(chanID lnwire.ShortChannelID,
+	peer [33]byte, latency time.Duration, settled bool) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	chanWindow, ok := t.channels[chanID]
+	if !ok {
+		chanWindow = &latencyWindow{}
+		t.channels[chanID] = chanWindow
+	}
+	chanWindow.add(latency, settled)
+
+	peerWindow, ok := t.peers[peer]
+	if !ok {
+		peerWindow = &latencyWindow{}
+		t.peers[peer] = peerWindow
+	}
+	peerWindow.add(latency, settled)
+}
+
+// ChannelStats returns the latency summary of each channel for which we've
+// resolved at least one outgoing HTLC.
+func (t *LatencyTracker) ChannelStats() map[lnwire.ShortChannelID]HtlcLatencyStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make(
+		map[lnwire.ShortChannelID]HtlcLatencyStats, len(t.channels),
+	)
+	for chanID, window := range t.channels {
+		stats[chanID] = window.stats()
+	}
+
+	return stats
+}
+
+// PeerStats returns the latency summary of each peer for which we've resolved
+// at least one outgoing HTLC.
+func (t *LatencyTracker) PeerStats() map[[33]byte]HtlcLatencyStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make(map[[33]byte]HtlcLatencyStats, len(t.peers))
+	for peer, window := range t.peers {
+		stats[peer] = window.stats()
+	}
+
+	return stats
+}
@@ -0,0 +1,72 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestLatencyTracker asserts that resolution latencies are aggregated per
+// channel and peer, and that only the most recent samples are retained.
+func TestLatencyTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewLatencyTracker()
+
+	chanID1 := lnwire.NewShortChanIDFromInt(1)
+	chanID2 := lnwire.NewShortChanIDFromInt(2)
+	peer := [33]byte{0x02}
+
+	// Record latencies of 1 to 100 milliseconds over the first channel,
+	// failing every tenth HTLC, and a single slow one over the second.
+	for i := 1; i <= 100; i++ {
+		tracker.RecordResolution(
+			chanID1, peer, time.Duration(i)*time.Millisecond,
+			i%10 != 0,
+		)
+	}
+	tracker.RecordResolution(chanID2, peer, time.Second, true)
+
+	chanStats := tracker.ChannelStats()
+	expected := HtlcLatencyStats{
+		NumSettled: 90,
+		NumFailed:  10,
+		P50:        50 * time.Millisecond,
+		P90:        90 * time.Millisecond,
+		P99:        99 * time.Millisecond,
+	}
+	if chanStats[chanID1] != expected {
+		t.Fatalf("expected stats %v, got %v", expected,
+			chanStats[chanID1])
+	}
+	if chanStats[chanID2].P50 != time.Second {
+		t.Fatalf("expected median of 1s, got %v", chanStats[chanID2].P50)
+	}
+
+	// Both channels are with the same peer, so its stats should include
+	// all samples.
+	peerStats := tracker.PeerStats()
+	if peerStats[peer].NumSettled != 91 || peerStats[peer].NumFailed != 10 {
+		t.Fatalf("unexpected peer resolution counts: %v",
+			peerStats[peer])
+	}
+	if peerStats[peer].P99 != 100*time.Millisecond {
+		t.Fatalf("expected p99 of 100ms, got %v", peerStats[peer].P99)
+	}
+
+	// Once the sample window is full, the oldest samples should be
+	// replaced, while the resolution counts keep growing.
+	for i := 0; i < maxLatencySamples; i++ {
+		tracker.RecordResolution(chanID1, peer, time.Minute, true)
+	}
+
+	chanStats = tracker.ChannelStats()
+	if chanStats[chanID1].P50 != time.Minute {
+		t.Fatalf("expected median of 1m, got %v", chanStats[chanID1].P50)
+	}
+	if chanStats[chanID1].NumSettled != 90+maxLatencySamples {
+		t.Fatalf("expected %d settled HTLCs, got %d",
+			90+maxLatencySamples, chanStats[chanID1].NumSettled)
+	}
+}
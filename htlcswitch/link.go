@@ -48,6 +48,11 @@ const (
 	// DefaultMaxLinkFeeUpdateTimeout represents the maximum interval in
 	// which a link should propose to update its commitment fee rate.
 	DefaultMaxLinkFeeUpdateTimeout = 60 * time.Minute
+
+	// DefaultMaxRemoteFeeRateMultiplier is the default multiple of our own
+	// fee estimate up to which we'll accept commitment fee rates proposed
+	// by the remote party.
+	DefaultMaxRemoteFeeRateMultiplier = 10
 )
 
 // ForwardingPolicy describes the set of constraints that a given ChannelLink
@@ -235,6 +240,19 @@ type ChannelLinkConfig struct {
 	MinFeeUpdateTimeout time.Duration
 	MaxFeeUpdateTimeout time.Duration
 
+	// MaxRemoteFeeRateMultiplier bounds the commitment fee rates the
+	// remote party may propose to this multiple of our own fee estimate.
+	// Fee updates above it, or below the minimum fee rate our backend
+	// relays, fail the link without force closing the channel, so that
+	// the remote party can't drain the channel to fees. A zero value
+	// disables the check.
+	MaxRemoteFeeRateMultiplier uint32
+
+	// CommitFeeBounds restricts the commitment fee rates we propose to
+	// the remote party as the initiator of the channel, as well as those
+	// we accept from it otherwise.
+	CommitFeeBounds lnwallet.CommitFeeBounds
+
 	// FinalCltvRejectDelta defines the number of blocks before the expiry
 	// of the htlc where we no longer settle it as an exit hop and instead
 	// cancel it back. Normally this value should be lower than the cltv
@@ -247,6 +265,19 @@ type ChannelLinkConfig struct {
 	// the outgoing broadcast delta, because in any case we don't want to
 	// risk offering an htlc that triggers channel closure.
 	OutgoingCltvRejectDelta uint32
+
+	// LatencyTracker, if non-nil, is used to record the time it takes for
+	// the remote peer to settle or fail each HTLC we offer them.
+	LatencyTracker *LatencyTracker
+
+	// ReportForwardHoldTime, if non-nil, is called with the time it took
+	// the remote peer to settle or fail each HTLC we forwarded to them on
+	// behalf of another channel.
+	ReportForwardHoldTime func(peer [33]byte, holdTime time.Duration)
+
+	// AuditLog, if non-nil, records the state update messages exchanged
+	// with the remote peer within the audit log of the channel.
+	AuditLog *channeldb.ChannelAuditLog
 }
 
 // channelLink is the service which drives a channel's commitment update
@@ -302,6 +333,10 @@ type channelLink struct {
 	// which may affect behaviour of the service.
 	cfg ChannelLinkConfig
 
+	// commitFees decides on the commitment fee rates we propose, and
+	// accept, within the bounds of our config.
+	commitFees *lnwallet.CommitFeeManager
+
 	// overflowQueue is used to store the htlc add updates which haven't
 	// been processed because of the commitment transaction overflow.
 	overflowQueue *packetQueue
@@ -357,6 +392,16 @@ type channelLink struct {
 	// resolving those htlcs when we receive a message on hodlQueue.
 	hodlMap map[lntypes.Hash][]hodlHtlc
 
+	// htlcAddTimes maps the index of each outgoing HTLC we've offered to
+	// the remote peer to the time it was offered. This is used to record
+	// the HTLC's resolution latency once the remote peer settles or fails
+	// it.
+	htlcAddTimes map[uint64]time.Time
+
+	// forwardedHtlcs is the set of indexes of the outgoing HTLCs within
+	// htlcAddTimes that we forwarded, rather than initiated ourselves.
+	forwardedHtlcs map[uint64]struct{}
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -376,12 +421,17 @@ func NewChannelLink(cfg ChannelLinkConfig,
 		cfg:         cfg,
 		channel:     channel,
 		shortChanID: channel.ShortChanID(),
+		commitFees: lnwallet.NewCommitFeeManager(
+			cfg.FeeEstimator, cfg.CommitFeeBounds,
+		),
 		// TODO(roasbeef): just do reserve here?
 		logCommitTimer: time.NewTimer(300 * time.Millisecond),
 		overflowQueue:  newPacketQueue(input.MaxHTLCNumber / 2),
 		htlcUpdates:    make(chan []channeldb.HTLC),
 		hodlMap:        make(map[lntypes.Hash][]hodlHtlc),
 		hodlQueue:      queue.NewConcurrentQueue(10),
+		htlcAddTimes:   make(map[uint64]time.Time),
+		forwardedHtlcs: make(map[uint64]struct{}),
 		quit:           make(chan struct{}),
 	}
 }
@@ -533,6 +583,21 @@ func (l *channelLink) sampleNetworkFee() (lnwallet.SatPerKWeight, error) {
 	return feePerKw, nil
 }
 
+// sampleCommitFee returns the commitment fee rate we should propose to get
+// into the chain within 3 blocks, which is the network fee bounded by our
+// commitment fee manager.
+func (l *channelLink) sampleCommitFee() (lnwallet.SatPerKWeight, error) {
+	feePerKw, err := l.commitFees.EstimateFeeRate(3)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Debugf("ChannelLink(%v): bounded commitment fee rate for 3 "+
+		"block conf: %v sat/kw", l, int64(feePerKw))
+
+	return feePerKw, nil
+}
+
 // shouldAdjustCommitFee returns true if we should update our commitment fee to
 // match that of the network fee. We'll only update our commitment fee if the
 // network fee is +/- 10% to our network fee.
@@ -554,6 +619,93 @@ func shouldAdjustCommitFee(netFee, chanFee lnwallet.SatPerKWeight) bool {
 	}
 }
 
+// validateRemoteFeeRate checks that a commitment fee rate proposed by the
+// remote party is within our configured commitment fee bounds, as well as
+// within sane bounds relative to our own fee estimate. If we're unable to
+// estimate the fee rate, we'll give the remote party the benefit of the doubt.
+func (l *channelLink) validateRemoteFeeRate(feePerKw lnwallet.SatPerKWeight) error {
+	if err := l.commitFees.CheckRemoteFeeRate(feePerKw); err != nil {
+		return err
+	}
+
+	if l.cfg.MaxRemoteFeeRateMultiplier == 0 {
+		return nil
+	}
+
+	estimate, err := l.sampleNetworkFee()
+	if err != nil {
+		log.Warnf("ChannelLink(%v): unable to sample network fee to "+
+			"validate fee update: %v", l, err)
+		return nil
+	}
+
+	return checkRemoteFeeRate(
+		feePerKw, estimate, lnwallet.MinMempoolFeePerKW(l.cfg.FeeEstimator),
+		l.cfg.MaxRemoteFeeRateMultiplier,
+	)
+}
+
+// checkRemoteFeeRate returns an error if the fee rate proposed by the remote
+// party exceeds the given multiple of our fee estimate, or falls below the
+// minimum fee rate that would be relayed.
+func checkRemoteFeeRate(feePerKw, estimate, minFee lnwallet.SatPerKWeight,
+	multiplier uint32) error {
+
+	maxFee := estimate * lnwallet.SatPerKWeight(multiplier)
+	switch {
+	case feePerKw > maxFee:
+		return fmt.Errorf("fee rate of %v sat/kw exceeds %dx our "+
+			"estimate of %v sat/kw", int64(feePerKw), multiplier,
+			int64(estimate))
+
+	case feePerKw < minFee:
+		return fmt.Errorf("fee rate of %v sat/kw is below the minimum "+
+			"relay fee rate of %v sat/kw", int64(feePerKw),
+			int64(minFee))
+	}
+
+	return nil
+}
+
+// maybeUpdateCommitFee samples the current network fee, and sends an
+// UpdateFee message to the remote party if our commitment fee should be
+// adjusted to it. This is only done if we're the initiator of the channel.
+func (l *channelLink) maybeUpdateCommitFee() {
+	// If we're not the initiator of the channel, don't we don't control
+	// the fees, so we can ignore this.
+	if !l.channel.IsInitiator() {
+		return
+	}
+
+	// If we are the initiator, then we'll sample the current fee rate to
+	// get into the chain within 3 blocks, kept within our commitment fee
+	// bounds. During low fee periods, this lowers our commitment fee down
+	// to the minimum of the bounds, which reduces the cost of a force
+	// close.
+	feePerKw, err := l.sampleCommitFee()
+	if err != nil {
+		log.Errorf("unable to sample network fee: %v", err)
+		return
+	}
+
+	// We'll check to see if we should update the fee rate based on our
+	// current set fee rate. If our commitment fee has left the bounds,
+	// e.g. as it has fallen below the minimum fee rate the backend
+	// currently accepts, we'll always adjust it, as our commitment
+	// transaction might not propagate otherwise.
+	commitFee := l.channel.CommitFeeRate()
+	outOfRange := !l.commitFees.InRange(commitFee) && feePerKw != commitFee
+	if !outOfRange && !shouldAdjustCommitFee(feePerKw, commitFee) {
+		return
+	}
+
+	// If we do, then we'll send a new UpdateFee message to the remote
+	// party, to be locked in with a new update.
+	if err := l.updateChannelFee(feePerKw); err != nil {
+		log.Errorf("unable to update fee rate: %v", err)
+	}
+}
+
 // syncChanState attempts to synchronize channel states with the remote party.
 // This method is to be called upon reconnection after the initial funding
 // flow. We'll compare out commitment chains with the remote party, and re-send
@@ -679,7 +831,7 @@ func (l *channelLink) syncChanStates() error {
 		// immediately so we return to a synchronized state as soon as
 		// possible.
 		for _, msg := range msgsToReSend {
-			l.cfg.Peer.SendMessage(false, msg)
+			l.sendStateUpdate(msg)
 		}
 
 	case <-l.quit:
@@ -952,6 +1104,21 @@ func (l *channelLink) htlcManager() {
 		go l.fwdPkgGarbager()
 	}
 
+	// If our fee estimator tracks the backend's fee filter, we'll
+	// re-evaluate our commitment fee as soon as it changes significantly,
+	// rather than waiting for our next scheduled fee update.
+	var feeFilterUpdates <-chan interface{}
+	if source, ok := l.cfg.FeeEstimator.(lnwallet.FeeFilterSource); ok {
+		feeFilterClient, err := source.SubscribeFeeFilter()
+		if err != nil {
+			log.Warnf("ChannelLink(%v): unable to subscribe to fee "+
+				"filter updates: %v", l, err)
+		} else {
+			defer feeFilterClient.Cancel()
+			feeFilterUpdates = feeFilterClient.Updates()
+		}
+	}
+
 out:
 	for {
 		// We must always check if we failed at some point processing
@@ -973,35 +1140,12 @@ out:
 		// fee to see if we should adjust our commitment fee.
 		case <-l.updateFeeTimer.C:
 			l.updateFeeTimer.Reset(l.randomFeeUpdateTimeout())
+			l.maybeUpdateCommitFee()
 
-			// If we're not the initiator of the channel, don't we
-			// don't control the fees, so we can ignore this.
-			if !l.channel.IsInitiator() {
-				continue
-			}
-
-			// If we are the initiator, then we'll sample the
-			// current fee rate to get into the chain within 3
-			// blocks.
-			feePerKw, err := l.sampleNetworkFee()
-			if err != nil {
-				log.Errorf("unable to sample network fee: %v", err)
-				continue
-			}
-
-			// We'll check to see if we should update the fee rate
-			// based on our current set fee rate.
-			commitFee := l.channel.CommitFeeRate()
-			if !shouldAdjustCommitFee(feePerKw, commitFee) {
-				continue
-			}
-
-			// If we do, then we'll send a new UpdateFee message to
-			// the remote party, to be locked in with a new update.
-			if err := l.updateChannelFee(feePerKw); err != nil {
-				log.Errorf("unable to update fee rate: %v", err)
-				continue
-			}
+		// The backend's fee filter has changed significantly, so we'll
+		// check whether our commitment fee needs to follow it.
+		case <-feeFilterUpdates:
+			l.maybeUpdateCommitFee()
 
 		// The underlying channel has notified us of a unilateral close
 		// carried out by the remote peer. In the case of such an
@@ -1350,7 +1494,12 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket, isReProcess bool) {
 		l.openedCircuits = append(l.openedCircuits, pkt.inKey())
 		l.keystoneBatch = append(l.keystoneBatch, pkt.keystone())
 
-		l.cfg.Peer.SendMessage(false, htlc)
+		l.htlcAddTimes[index] = time.Now()
+		if pkt.incomingChanID != sourceHop {
+			l.forwardedHtlcs[index] = struct{}{}
+		}
+
+		l.sendStateUpdate(htlc)
 
 	case *lnwire.UpdateFulfillHTLC:
 		// If hodl.SettleOutgoing mode is active, we exit early to
@@ -1407,7 +1556,7 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket, isReProcess bool) {
 
 		// Then we send the HTLC settle message to the connected peer
 		// so we can continue the propagation of the settle message.
-		l.cfg.Peer.SendMessage(false, htlc)
+		l.sendStateUpdate(htlc)
 		isSettle = true
 
 	case *lnwire.UpdateFailHTLC:
@@ -1465,7 +1614,7 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket, isReProcess bool) {
 
 		// Finally, we send the HTLC message to the peer which
 		// initially created the HTLC.
-		l.cfg.Peer.SendMessage(false, htlc)
+		l.sendStateUpdate(htlc)
 		isSettle = true
 	}
 
@@ -1546,10 +1695,68 @@ func (l *channelLink) cleanupSpuriousResponse(pkt *htlcPacket) {
 	}
 }
 
+// recordHtlcResolution records the resolution latency of the outgoing HTLC
+// with the given index, which has just been settled or failed by the remote
+// peer. HTLCs that were offered before the link was last started are ignored,
+// as we don't know when they were added.
+func (l *channelLink) recordHtlcResolution(htlcIndex uint64, settled bool) {
+	addTime, ok := l.htlcAddTimes[htlcIndex]
+	if !ok {
+		return
+	}
+	delete(l.htlcAddTimes, htlcIndex)
+
+	_, forwarded := l.forwardedHtlcs[htlcIndex]
+	delete(l.forwardedHtlcs, htlcIndex)
+
+	latency := time.Since(addTime)
+	if l.cfg.LatencyTracker != nil {
+		l.cfg.LatencyTracker.RecordResolution(
+			l.ShortChanID(), l.cfg.Peer.PubKey(), latency,
+			settled,
+		)
+	}
+
+	// The hold times of HTLCs we initiated ourselves are reported by the
+	// router, which knows the full route they took.
+	if forwarded && l.cfg.ReportForwardHoldTime != nil {
+		l.cfg.ReportForwardHoldTime(l.cfg.Peer.PubKey(), latency)
+	}
+}
+
+// recordAuditEvent records the state update message within the audit log of
+// the channel, if enabled. Failing to do so is logged rather than failing the
+// link, as the audit log isn't needed to operate the channel.
+func (l *channelLink) recordAuditEvent(msg lnwire.Message, outgoing bool) {
+	if l.cfg.AuditLog == nil || !channeldb.IsAuditedMsg(msg) {
+		return
+	}
+
+	err := l.cfg.AuditLog.AddEvent(
+		l.channel.ChannelPoint(), &channeldb.ChannelAuditEvent{
+			Timestamp: time.Now(),
+			Outgoing:  outgoing,
+			Msg:       msg,
+		},
+	)
+	if err != nil {
+		l.errorf("unable to record audit event: %v", err)
+	}
+}
+
+// sendStateUpdate sends the channel state update message to the remote peer,
+// recording it within the audit log of the channel beforehand.
+func (l *channelLink) sendStateUpdate(msg lnwire.Message) error {
+	l.recordAuditEvent(msg, true)
+	return l.cfg.Peer.SendMessage(false, msg)
+}
+
 // handleUpstreamMsg processes wire messages related to commitment state
 // updates from the upstream peer. The upstream peer is the peer whom we have a
 // direct channel with, updating our respective commitment chains.
 func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
+	l.recordAuditEvent(msg, false)
+
 	switch msg := msg.(type) {
 
 	case *lnwire.UpdateAddHTLC:
@@ -1580,6 +1787,8 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			return
 		}
 
+		l.recordHtlcResolution(idx, true)
+
 		// TODO(roasbeef): pipeline to switch
 
 		// Add the newly discovered preimage to our growing list of
@@ -1629,6 +1838,8 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			return
 		}
 
+		l.recordHtlcResolution(msg.ID, false)
+
 	case *lnwire.UpdateFailHTLC:
 		idx := msg.ID
 		err := l.channel.ReceiveFailHTLC(idx, msg.Reason[:])
@@ -1638,6 +1849,8 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			return
 		}
 
+		l.recordHtlcResolution(idx, false)
+
 	case *lnwire.CommitSig:
 		// Since we may have learned new preimages for the first time,
 		// we'll add them to our preimage cache. By doing this, we
@@ -1705,7 +1918,7 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			log.Errorf("unable to revoke commitment: %v", err)
 			return
 		}
-		l.cfg.Peer.SendMessage(false, nextRevocation)
+		l.sendStateUpdate(nextRevocation)
 
 		// Since we just revoked our commitment, we may have a new set
 		// of HTLC's on our commitment, so we'll send them over our
@@ -1779,8 +1992,19 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 
 	case *lnwire.UpdateFee:
 		// We received fee update from peer. If we are the initiator we
-		// will fail the channel, if not we will apply the update.
+		// will fail the channel, if not we will apply the update as
+		// long as the fee rate is sane.
 		fee := lnwallet.SatPerKWeight(msg.FeePerKw)
+		if err := l.validateRemoteFeeRate(fee); err != nil {
+			l.fail(
+				LinkFailureError{
+					code:     ErrInvalidUpdate,
+					SendData: []byte(err.Error()),
+				},
+				"rejecting fee update: %v", err,
+			)
+			return
+		}
 		if err := l.channel.ReceiveUpdateFee(fee); err != nil {
 			l.fail(LinkFailureError{code: ErrInvalidUpdate},
 				"error receiving fee update: %v", err)
@@ -1909,7 +2133,7 @@ func (l *channelLink) updateCommitTx() error {
 		CommitSig: theirCommitSig,
 		HtlcSigs:  htlcSigs,
 	}
-	l.cfg.Peer.SendMessage(false, commitSig)
+	l.sendStateUpdate(commitSig)
 
 	// We've just initiated a state transition, attempt to stop the
 	// logCommitTimer. If the timer already ticked, then we'll consume the
@@ -1963,6 +2187,7 @@ func (l *channelLink) ShortChanID() lnwire.ShortChannelID {
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) UpdateShortChanID() (lnwire.ShortChannelID, error) {
 	chanID := l.ChanID()
+	oldSid := l.ShortChanID()
 
 	// Refresh the channel state's short channel ID by loading it from disk.
 	// This ensures that the channel state accurately reflects the updated
@@ -1994,9 +2219,12 @@ func (l *channelLink) UpdateShortChanID() (lnwire.ShortChannelID, error) {
 	}()
 
 	// Now that the short channel ID has been properly updated, we can begin
-	// garbage collecting any forwarding packages we create.
-	l.wg.Add(1)
-	go l.fwdPkgGarbager()
+	// garbage collecting any forwarding packages we create, unless we did
+	// so under an alias already.
+	if oldSid == sourceHop {
+		l.wg.Add(1)
+		go l.fwdPkgGarbager()
+	}
 
 	return sid, nil
 }
@@ -2322,7 +2550,7 @@ func (l *channelLink) updateChannelFee(feePerKw lnwallet.SatPerKWeight) error {
 	// We'll then attempt to send a new UpdateFee message, and also lock it
 	// in immediately by triggering a commitment update.
 	msg := lnwire.NewUpdateFee(l.ChanID(), uint32(feePerKw))
-	if err := l.cfg.Peer.SendMessage(false, msg); err != nil {
+	if err := l.sendStateUpdate(msg); err != nil {
 		return err
 	}
 	return l.updateCommitTx()
@@ -2868,8 +3096,13 @@ func (l *channelLink) processExitHop(pd *lnwallet.PaymentDescriptor,
 	// Notify the invoiceRegistry of the exit hop htlc. If we crash right
 	// after this, this code will be re-executed after restart. We will
 	// receive back a resolution event.
+	circuitKey := &CircuitKey{
+		ChanID: l.ShortChanID(),
+		HtlcID: pd.HtlcIndex,
+	}
 	event, err := l.cfg.Registry.NotifyExitHopHtlc(
-		invoiceHash, pd.Amount, l.hodlQueue.ChanIn(),
+		invoiceHash, pd.Amount, pd.Timeout, heightNow, circuitKey,
+		l.hodlQueue.ChanIn(),
 	)
 	if err != nil {
 		return false, err
@@ -2922,7 +3155,7 @@ func (l *channelLink) settleHTLC(preimage lntypes.Preimage, htlcIndex uint64,
 
 	// HTLC was successfully settled locally send notification about it
 	// remote peer.
-	l.cfg.Peer.SendMessage(false, &lnwire.UpdateFulfillHTLC{
+	l.sendStateUpdate(&lnwire.UpdateFulfillHTLC{
 		ChanID:          l.ChanID(),
 		ID:              htlcIndex,
 		PaymentPreimage: preimage,
@@ -2988,7 +3221,7 @@ func (l *channelLink) sendHTLCError(htlcIndex uint64, failure lnwire.FailureMess
 		return
 	}
 
-	l.cfg.Peer.SendMessage(false, &lnwire.UpdateFailHTLC{
+	l.sendStateUpdate(&lnwire.UpdateFailHTLC{
 		ChanID: l.ChanID(),
 		ID:     htlcIndex,
 		Reason: reason,
@@ -3007,7 +3240,7 @@ func (l *channelLink) sendMalformedHTLCError(htlcIndex uint64,
 		return
 	}
 
-	l.cfg.Peer.SendMessage(false, &lnwire.UpdateFailMalformedHTLC{
+	l.sendStateUpdate(&lnwire.UpdateFailMalformedHTLC{
 		ChanID:       l.ChanID(),
 		ID:           htlcIndex,
 		ShaOnionBlob: shaOnionBlob,
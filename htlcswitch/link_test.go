@@ -20,11 +20,11 @@ import (
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 	"github.com/litecoinfinance/lnd/build"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/contractcourt"
 	"github.com/litecoinfinance/lnd/htlcswitch/hodl"
 	"github.com/litecoinfinance/lnd/input"
@@ -1555,6 +1555,9 @@ type mockPeer struct {
 func (m *mockPeer) QuitSignal() <-chan struct{} {
 	return m.quit
 }
+func (m *mockPeer) RemoteLocalFeatures() *lnwire.FeatureVector {
+	return lnwire.NewFeatureVector(nil, lnwire.LocalFeatures)
+}
 
 var _ lnpeer.Peer = (*mockPeer)(nil)
 
@@ -3802,6 +3805,62 @@ func TestChannelLinkUpdateCommitFee(t *testing.T) {
 	}
 }
 
+// TestCheckRemoteFeeRate asserts that fee rates proposed by the remote party
+// are only accepted within the bounds derived from our own fee estimate.
+func TestCheckRemoteFeeRate(t *testing.T) {
+	t.Parallel()
+
+	const (
+		estimate = lnwallet.SatPerKWeight(1000)
+		minFee   = lnwallet.SatPerKWeight(253)
+	)
+
+	tests := []struct {
+		name       string
+		feePerKw   lnwallet.SatPerKWeight
+		multiplier uint32
+		valid      bool
+	}{
+		{
+			name:       "within bounds",
+			feePerKw:   5000,
+			multiplier: 10,
+			valid:      true,
+		},
+		{
+			name:       "at upper bound",
+			feePerKw:   10000,
+			multiplier: 10,
+			valid:      true,
+		},
+		{
+			name:       "above upper bound",
+			feePerKw:   10001,
+			multiplier: 10,
+			valid:      false,
+		},
+		{
+			name:       "below min relay fee",
+			feePerKw:   252,
+			multiplier: 10,
+			valid:      false,
+		},
+	}
+	for _, test := range tests {
+		err := checkRemoteFeeRate(
+			test.feePerKw, estimate, minFee, test.multiplier,
+		)
+		if test.valid && err != nil {
+			t.Fatalf("%s: expected fee rate to be accepted: %v",
+				test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("%s: expected fee rate to be rejected",
+				test.name)
+		}
+	}
+}
+
 // TestChannelLinkAcceptDuplicatePayment tests that if a link receives an
 // incoming HTLC for a payment we have already settled, then it accepts the
 // HTLC. We do this to simplify the processing of settles after restarts or
@@ -5161,32 +5220,32 @@ type mockPackager struct {
 	failLoadFwdPkgs bool
 }
 
-func (*mockPackager) AddFwdPkg(tx *bbolt.Tx, fwdPkg *channeldb.FwdPkg) error {
+func (*mockPackager) AddFwdPkg(tx kvdb.Tx, fwdPkg *channeldb.FwdPkg) error {
 	return nil
 }
 
-func (*mockPackager) SetFwdFilter(tx *bbolt.Tx, height uint64,
+func (*mockPackager) SetFwdFilter(tx kvdb.Tx, height uint64,
 	fwdFilter *channeldb.PkgFilter) error {
 	return nil
 }
 
-func (*mockPackager) AckAddHtlcs(tx *bbolt.Tx,
+func (*mockPackager) AckAddHtlcs(tx kvdb.Tx,
 	addRefs ...channeldb.AddRef) error {
 	return nil
 }
 
-func (m *mockPackager) LoadFwdPkgs(tx *bbolt.Tx) ([]*channeldb.FwdPkg, error) {
+func (m *mockPackager) LoadFwdPkgs(tx kvdb.Tx) ([]*channeldb.FwdPkg, error) {
 	if m.failLoadFwdPkgs {
 		return nil, fmt.Errorf("failing LoadFwdPkgs")
 	}
 	return nil, nil
 }
 
-func (*mockPackager) RemovePkg(tx *bbolt.Tx, height uint64) error {
+func (*mockPackager) RemovePkg(tx kvdb.Tx, height uint64) error {
 	return nil
 }
 
-func (*mockPackager) AckSettleFails(tx *bbolt.Tx,
+func (*mockPackager) AckSettleFails(tx kvdb.Tx,
 	settleFailRefs ...channeldb.SettleFailRef) error {
 	return nil
 }
@@ -264,6 +264,10 @@ func (s *mockServer) QuitSignal() <-chan struct{} {
 	return s.quit
 }
 
+func (s *mockServer) RemoteLocalFeatures() *lnwire.FeatureVector {
+	return lnwire.NewFeatureVector(nil, lnwire.LocalFeatures)
+}
+
 // mockHopIterator represents the test version of hop iterator which instead
 // of encrypting the path in onion blob just stores the path as a list of hops.
 type mockHopIterator struct {
@@ -766,7 +770,7 @@ func newMockRegistry(minDelta uint32) *mockInvoiceRegistry {
 		return testInvoiceCltvExpiry, nil
 	}
 
-	registry := invoices.NewRegistry(cdb, decodeExpiry)
+	registry := invoices.NewRegistry(cdb, decodeExpiry, nil, nil, nil, 0)
 	registry.Start()
 
 	return &mockInvoiceRegistry{
@@ -784,10 +788,13 @@ func (i *mockInvoiceRegistry) SettleHodlInvoice(preimage lntypes.Preimage) error
 }
 
 func (i *mockInvoiceRegistry) NotifyExitHopHtlc(rhash lntypes.Hash,
-	amt lnwire.MilliSatoshi, hodlChan chan<- interface{}) (
+	amt lnwire.MilliSatoshi, expiry, currentHeight uint32,
+	circuitKey *channeldb.CircuitKey, hodlChan chan<- interface{}) (
 	*invoices.HodlEvent, error) {
 
-	event, err := i.registry.NotifyExitHopHtlc(rhash, amt, hodlChan)
+	event, err := i.registry.NotifyExitHopHtlc(
+		rhash, amt, expiry, currentHeight, circuitKey, hodlChan,
+	)
 	if err != nil {
 		return nil, err
 	}
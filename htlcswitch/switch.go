@@ -12,10 +12,10 @@ import (
 	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/contractcourt"
 	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/lnwire"
@@ -116,6 +116,19 @@ type ChanClose struct {
 	// process for the cooperative closure transaction kicks off.
 	TargetFeePerKw lnwallet.SatPerKWeight
 
+	// MinFeePerKw and MaxFeePerKw bound the fee rates the caller is
+	// willing to accept for the closing transaction. Any offer of the
+	// remote party within this range will be accepted. A zero value leaves
+	// the respective side of the range unbounded. These values are only
+	// utilized if the closure type is CloseRegular.
+	MinFeePerKw lnwallet.SatPerKWeight
+	MaxFeePerKw lnwallet.SatPerKWeight
+
+	// DeliveryScript is the script our settled funds should be sent to.
+	// If empty, a fresh address of the wallet will be used instead. This
+	// value is only utilized if the closure type is CloseRegular.
+	DeliveryScript []byte
+
 	// Updates is used by request creator to receive the notifications about
 	// execution of the close channel request.
 	Updates chan interface{}
@@ -218,6 +231,16 @@ type Switch struct {
 	// control provides verification of sending htlc mesages
 	control ControlTower
 
+	// inflightShards counts the HTLCs in flight for each payment hash that
+	// were sent as shards of a multi-path payment. The control tower only
+	// tracks a single status per payment hash, so the payment is only
+	// grounded once the last of its shards has failed.
+	//
+	// NOTE: The counts aren't persisted, so after a restart the first
+	// shard to fail grounds the payment.
+	inflightShards map[[32]byte]int
+	shardsMtx      sync.Mutex
+
 	// circuits is storage for payment circuits which are used to
 	// forward the settle/fail htlc updates back to the add htlc initiator.
 	circuits CircuitMap
@@ -248,6 +271,12 @@ type Switch struct {
 	// ChannelLink
 	forwardingIndex map[lnwire.ShortChannelID]ChannelLink
 
+	// aliasIndex holds the alias short_chan_id of each live link that was
+	// added under its alias, before its funding transaction confirmed.
+	// Such a link remains in the forwardingIndex under its alias, so that
+	// HTLCs forwarded under it are still resolved.
+	aliasIndex map[lnwire.ChannelID]lnwire.ShortChannelID
+
 	// interfaceIndex maps the compressed public key of a peer to all the
 	// channels that the switch maintains with that peer.
 	interfaceIndex map[[33]byte]map[lnwire.ChannelID]ChannelLink
@@ -277,6 +306,10 @@ type Switch struct {
 	fwdEventMtx         sync.Mutex
 	pendingFwdingEvents []channeldb.ForwardingEvent
 
+	// latencyTracker aggregates the resolution latencies of the outgoing
+	// HTLCs of all links.
+	latencyTracker *LatencyTracker
+
 	// blockEpochStream is an active block epoch event stream backed by an
 	// active ChainNotifier instance. This will be used to retrieve the
 	// lastest height of the chain.
@@ -304,15 +337,18 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 		circuits:          circuitMap,
 		paymentSequencer:  sequencer,
 		control:           NewPaymentControl(false, cfg.DB),
+		inflightShards:    make(map[[32]byte]int),
 		linkIndex:         make(map[lnwire.ChannelID]ChannelLink),
 		mailOrchestrator:  newMailOrchestrator(),
 		forwardingIndex:   make(map[lnwire.ShortChannelID]ChannelLink),
+		aliasIndex:        make(map[lnwire.ChannelID]lnwire.ShortChannelID),
 		interfaceIndex:    make(map[[33]byte]map[lnwire.ChannelID]ChannelLink),
 		pendingLinkIndex:  make(map[lnwire.ChannelID]ChannelLink),
 		pendingPayments:   make(map[uint64]*pendingPayment),
 		htlcPlex:          make(chan *plexPacket),
 		chanCloseRequests: make(chan *ChanClose),
 		resolutionMsgs:    make(chan *resolutionMsg),
+		latencyTracker:    NewLatencyTracker(),
 		quit:              make(chan struct{}),
 	}, nil
 }
@@ -359,10 +395,31 @@ func (s *Switch) SendHTLC(firstHop lnwire.ShortChannelID,
 	htlc *lnwire.UpdateAddHTLC,
 	deobfuscator ErrorDecrypter) ([sha256.Size]byte, error) {
 
+	return s.sendHTLC(firstHop, htlc, deobfuscator, false)
+}
+
+// SendHTLCShard sends an htlc carrying one shard of a multi-path payment.
+// Unlike SendHTLC, it may be called while other shards of the same payment
+// hash are still in flight, though not once the payment has completed or while
+// a single-path payment to the hash is in flight.
+func (s *Switch) SendHTLCShard(firstHop lnwire.ShortChannelID,
+	htlc *lnwire.UpdateAddHTLC,
+	deobfuscator ErrorDecrypter) ([sha256.Size]byte, error) {
+
+	return s.sendHTLC(firstHop, htlc, deobfuscator, true)
+}
+
+// sendHTLC sends the htlc update on behalf of a local payment, and blocks
+// until its outcome is known. The shard flag indicates whether the htlc is one
+// of several shards of the same payment.
+func (s *Switch) sendHTLC(firstHop lnwire.ShortChannelID,
+	htlc *lnwire.UpdateAddHTLC, deobfuscator ErrorDecrypter,
+	shard bool) ([sha256.Size]byte, error) {
+
 	// Before sending, double check that we don't already have 1) an
 	// in-flight payment to this payment hash, or 2) a complete payment for
 	// the same hash.
-	if err := s.control.ClearForTakeoff(htlc); err != nil {
+	if err := s.clearForTakeoff(htlc, shard); err != nil {
 		return zeroPreimage, err
 	}
 
@@ -397,7 +454,7 @@ func (s *Switch) SendHTLC(firstHop lnwire.ShortChannelID,
 
 	if err := s.forward(packet); err != nil {
 		s.removePendingPayment(paymentID)
-		if err := s.control.Fail(htlc.PaymentHash); err != nil {
+		if err := s.failPayment(htlc.PaymentHash); err != nil {
 			return zeroPreimage, err
 		}
 
@@ -425,6 +482,76 @@ func (s *Switch) SendHTLC(firstHop lnwire.ShortChannelID,
 	return preimage, err
 }
 
+// clearForTakeoff checks with the control tower that the htlc may be sent,
+// marking its payment as in flight. Shards of a multi-path payment that
+// already has shards in flight are only counted, as the control tower would
+// otherwise reject them as duplicate payments.
+func (s *Switch) clearForTakeoff(htlc *lnwire.UpdateAddHTLC,
+	shard bool) error {
+
+	s.shardsMtx.Lock()
+	defer s.shardsMtx.Unlock()
+
+	if shard && s.inflightShards[htlc.PaymentHash] > 0 {
+		s.inflightShards[htlc.PaymentHash]++
+		return nil
+	}
+
+	if err := s.control.ClearForTakeoff(htlc); err != nil {
+		return err
+	}
+
+	if shard {
+		s.inflightShards[htlc.PaymentHash] = 1
+	}
+
+	return nil
+}
+
+// releaseShard removes an htlc sent to the given payment hash from the set of
+// in-flight shards, returning the number of shards that remain in flight.
+//
+// NOTE: This method must be called with the shardsMtx held.
+func (s *Switch) releaseShard(paymentHash [32]byte) int {
+	numShards, ok := s.inflightShards[paymentHash]
+	if !ok {
+		return 0
+	}
+
+	if numShards <= 1 {
+		delete(s.inflightShards, paymentHash)
+		return 0
+	}
+
+	s.inflightShards[paymentHash] = numShards - 1
+
+	return numShards - 1
+}
+
+// settlePayment marks the payment to the given payment hash as completed, as
+// one of its htlcs has been settled.
+func (s *Switch) settlePayment(paymentHash [32]byte) error {
+	s.shardsMtx.Lock()
+	defer s.shardsMtx.Unlock()
+
+	s.releaseShard(paymentHash)
+
+	return s.control.Success(paymentHash)
+}
+
+// failPayment grounds the payment to the given payment hash after one of its
+// htlcs failed, unless other shards of the payment remain in flight.
+func (s *Switch) failPayment(paymentHash [32]byte) error {
+	s.shardsMtx.Lock()
+	defer s.shardsMtx.Unlock()
+
+	if s.releaseShard(paymentHash) > 0 {
+		return nil
+	}
+
+	return s.control.Fail(paymentHash)
+}
+
 // UpdateForwardingPolicies sends a message to the switch to update the
 // forwarding policies for the set of target channels. If the set of targeted
 // channels is nil, then the forwarding policies for all active channels with
@@ -902,7 +1029,7 @@ func (s *Switch) handleLocalResponse(pkt *htlcPacket) {
 		// Persistently mark that a payment to this payment hash
 		// succeeded. This will prevent us from ever making another
 		// payment to this hash.
-		err := s.control.Success(pkt.circuit.PaymentHash)
+		err := s.settlePayment(pkt.circuit.PaymentHash)
 		if err != nil && err != ErrPaymentAlreadyCompleted {
 			log.Warnf("Unable to mark completed payment %x: %v",
 				pkt.circuit.PaymentHash, err)
@@ -917,7 +1044,7 @@ func (s *Switch) handleLocalResponse(pkt *htlcPacket) {
 		// Persistently mark that a payment to this payment hash failed.
 		// This will permit us to make another attempt at a successful
 		// payment.
-		err := s.control.Fail(pkt.circuit.PaymentHash)
+		err := s.failPayment(pkt.circuit.PaymentHash)
 		if err != nil && err != ErrPaymentAlreadyCompleted {
 			log.Warnf("Unable to ground payment %x: %v",
 				pkt.circuit.PaymentHash, err)
@@ -1390,7 +1517,7 @@ func (s *Switch) closeCircuit(pkt *htlcPacket) (*PaymentCircuit, error) {
 // we're the originator of the payment, so the link stops attempting to
 // re-broadcast.
 func (s *Switch) ackSettleFail(settleFailRef channeldb.SettleFailRef) error {
-	return s.cfg.DB.Batch(func(tx *bbolt.Tx) error {
+	return s.cfg.DB.Batch(func(tx kvdb.Tx) error {
 		return s.cfg.SwitchPackager.AckSettleFails(tx, settleFailRef)
 	})
 }
@@ -1452,11 +1579,13 @@ func (s *Switch) teardownCircuit(pkt *htlcPacket) error {
 
 // CloseLink creates and sends the close channel command to the target link
 // directing the specified closure type. If the closure type if CloseRegular,
-// then the last parameter should be the ideal fee-per-kw that will be used as
-// a starting point for close negotiation.
+// then targetFeePerKw should be the ideal fee-per-kw that will be used as a
+// starting point for close negotiation, optionally bounded by minFeePerKw and
+// maxFeePerKw. A non-empty deliveryScript overrides the address our funds are
+// sent to.
 func (s *Switch) CloseLink(chanPoint *wire.OutPoint, closeType ChannelCloseType,
-	targetFeePerKw lnwallet.SatPerKWeight) (chan interface{},
-	chan error) {
+	targetFeePerKw, minFeePerKw, maxFeePerKw lnwallet.SatPerKWeight,
+	deliveryScript []byte) (chan interface{}, chan error) {
 
 	// TODO(roasbeef) abstract out the close updates.
 	updateChan := make(chan interface{}, 2)
@@ -1467,6 +1596,9 @@ func (s *Switch) CloseLink(chanPoint *wire.OutPoint, closeType ChannelCloseType,
 		ChanPoint:      chanPoint,
 		Updates:        updateChan,
 		TargetFeePerKw: targetFeePerKw,
+		MinFeePerKw:    minFeePerKw,
+		MaxFeePerKw:    maxFeePerKw,
+		DeliveryScript: deliveryScript,
 		Err:            errChan,
 	}
 
@@ -1800,7 +1932,7 @@ func (s *Switch) reforwardResponses() error {
 func (s *Switch) loadChannelFwdPkgs(source lnwire.ShortChannelID) ([]*channeldb.FwdPkg, error) {
 
 	var fwdPkgs []*channeldb.FwdPkg
-	if err := s.cfg.DB.Update(func(tx *bbolt.Tx) error {
+	if err := s.cfg.DB.Update(func(tx kvdb.Tx) error {
 		var err error
 		fwdPkgs, err = s.cfg.SwitchPackager.LoadChannelFwdPkgs(
 			tx, source,
@@ -2084,6 +2216,10 @@ func (s *Switch) removeLink(chanID lnwire.ChannelID) ChannelLink {
 	delete(s.pendingLinkIndex, link.ChanID())
 	delete(s.linkIndex, link.ChanID())
 	delete(s.forwardingIndex, link.ShortChanID())
+	if alias, ok := s.aliasIndex[link.ChanID()]; ok {
+		delete(s.forwardingIndex, alias)
+		delete(s.aliasIndex, link.ChanID())
+	}
 
 	// If the link has been added to the peer index, then we'll move to
 	// delete the entry within the index.
@@ -2108,11 +2244,15 @@ func (s *Switch) UpdateShortChanID(chanID lnwire.ChannelID) error {
 	s.indexMtx.Lock()
 	defer s.indexMtx.Unlock()
 
-	// Locate the target link in the pending link index. If no such link
-	// exists, then we will ignore the request.
+	// Locate the target link in the pending link index. A live link may
+	// only be updated if it was added under an alias short chan ID. If no
+	// such link exists, then we will ignore the request.
 	link, ok := s.pendingLinkIndex[chanID]
 	if !ok {
-		return fmt.Errorf("link %v not found", chanID)
+		link, ok = s.linkIndex[chanID]
+		if !ok || !link.ShortChanID().IsAlias() {
+			return fmt.Errorf("link %v not found", chanID)
+		}
 	}
 
 	oldShortChanID := link.ShortChanID()
@@ -2133,11 +2273,18 @@ func (s *Switch) UpdateShortChanID(chanID lnwire.ChannelID) error {
 	log.Infof("Updated short_chan_id for ChannelLink(%v): old=%v, new=%v",
 		chanID, oldShortChanID, shortChanID)
 
-	// Since the link was in the pending state before, we will remove it
-	// from the pending link index and add it to the live link index so that
-	// it can be available in forwarding.
-	delete(s.pendingLinkIndex, chanID)
-	s.addLiveLink(link)
+	// If the link was live under its alias before, it'll be available in
+	// forwarding under both short chan IDs from now on.
+	if oldShortChanID.IsAlias() {
+		s.aliasIndex[chanID] = oldShortChanID
+		s.forwardingIndex[shortChanID] = link
+	} else {
+		// Since the link was in the pending state before, we will
+		// remove it from the pending link index and add it to the live
+		// link index so that it can be available in forwarding.
+		delete(s.pendingLinkIndex, chanID)
+		s.addLiveLink(link)
+	}
 
 	// Finally, alert the mail orchestrator to the change of short channel
 	// ID, and deliver any unclaimed packets to the link.
@@ -2206,6 +2353,12 @@ func (s *Switch) CircuitModifier() CircuitModifier {
 	return s.circuits
 }
 
+// LatencyTracker returns the tracker that aggregates the resolution latencies
+// of the outgoing HTLCs of all links.
+func (s *Switch) LatencyTracker() *LatencyTracker {
+	return s.latencyTracker
+}
+
 // numPendingPayments is helper function which returns the overall number of
 // pending user payments.
 func (s *Switch) numPendingPayments() int {
@@ -1830,6 +1830,134 @@ func TestSwitchSendPayment(t *testing.T) {
 	}
 }
 
+// TestSwitchSendPaymentShards tests that the switch allows several shards of
+// the same payment to be in flight at once, and only grounds the payment once
+// all of its shards have failed.
+func TestSwitchSendPaymentShards(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(t, "alice", testStartingHeight, nil, 6)
+	if err != nil {
+		t.Fatalf("unable to create alice server: %v", err)
+	}
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	if err != nil {
+		t.Fatalf("unable to init switch: %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	chanID1, _, aliceChanID, _ := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add link: %v", err)
+	}
+
+	preimage, err := genPreimage()
+	if err != nil {
+		t.Fatalf("unable to generate preimage: %v", err)
+	}
+	rhash := fastsha256.Sum256(preimage[:])
+	update := &lnwire.UpdateAddHTLC{
+		PaymentHash: rhash,
+		Amount:      1,
+	}
+
+	// Send two shards of the same payment, both of which should be
+	// forwarded to alice's link.
+	errChan := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		shard := *update
+		go func() {
+			_, err := s.SendHTLCShard(
+				aliceChannelLink.ShortChanID(), &shard,
+				newMockDeobfuscator(),
+			)
+			errChan <- err
+		}()
+
+		select {
+		case packet := <-aliceChannelLink.packets:
+			err := aliceChannelLink.completeCircuit(packet)
+			if err != nil {
+				t.Fatalf("unable to complete payment "+
+					"circuit: %v", err)
+			}
+
+		case err := <-errChan:
+			t.Fatalf("unable to send shard: %v", err)
+
+		case <-time.After(time.Second):
+			t.Fatal("shard was not propagated to destination")
+		}
+	}
+
+	if s.numPendingPayments() != 2 {
+		t.Fatal("wrong amount of pending payments")
+	}
+
+	// A single-path payment to the same hash should be rejected while the
+	// shards are in flight.
+	_, err = s.SendHTLC(
+		aliceChannelLink.ShortChanID(), update, newMockDeobfuscator(),
+	)
+	if err != ErrPaymentInFlight {
+		t.Fatalf("expected ErrPaymentInFlight, got: %v", err)
+	}
+
+	// failShard fails the shard with the given outgoing htlc id, and
+	// waits for its sender to be notified.
+	obfuscator := NewMockObfuscator()
+	failShard := func(htlcID uint64) {
+		failure := lnwire.NewFailUnknownPaymentHash(update.Amount)
+		reason, err := obfuscator.EncryptFirstHop(failure)
+		if err != nil {
+			t.Fatalf("unable obfuscate failure: %v", err)
+		}
+
+		packet := &htlcPacket{
+			outgoingChanID: aliceChannelLink.ShortChanID(),
+			outgoingHTLCID: htlcID,
+			amount:         1,
+			htlc: &lnwire.UpdateFailHTLC{
+				Reason: reason,
+			},
+		}
+		if err := s.forward(packet); err != nil {
+			t.Fatalf("can't forward htlc packet: %v", err)
+		}
+
+		select {
+		case err := <-errChan:
+			if err == nil {
+				t.Fatalf("expected shard to fail")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("err wasn't received")
+		}
+	}
+
+	// After the first shard fails, the payment should remain in flight
+	// because of the second one.
+	failShard(0)
+	if err := s.control.ClearForTakeoff(update); err != ErrPaymentInFlight {
+		t.Fatalf("expected ErrPaymentInFlight, got: %v", err)
+	}
+
+	// Once the second shard fails as well, the payment should be grounded,
+	// allowing it to be attempted again.
+	failShard(1)
+	if err := s.control.ClearForTakeoff(update); err != nil {
+		t.Fatalf("expected payment to be grounded, got: %v", err)
+	}
+}
+
 // TestLocalPaymentNoForwardingEvents tests that if we send a series of locally
 // initiated payments, then they aren't reflected in the forwarding log.
 func TestLocalPaymentNoForwardingEvents(t *testing.T) {
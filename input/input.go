@@ -128,6 +128,35 @@ func (bi *BaseInput) BlocksToMaturity() uint32 {
 	return 0
 }
 
+// CsvInput is an input that can only be spent once a relative time lock has
+// passed since the confirmation of its output.
+type CsvInput struct {
+	BaseInput
+
+	blocksToMaturity uint32
+}
+
+// MakeCsvInput assembles a new CsvInput that can be used to construct a sweep
+// transaction.
+func MakeCsvInput(outpoint *wire.OutPoint, witnessType WitnessType,
+	signDescriptor *SignDescriptor, heightHint,
+	blocksToMaturity uint32) CsvInput {
+
+	return CsvInput{
+		BaseInput: MakeBaseInput(
+			outpoint, witnessType, signDescriptor, heightHint,
+		),
+		blocksToMaturity: blocksToMaturity,
+	}
+}
+
+// BlocksToMaturity returns the relative timelock, as a number of blocks, that
+// must be built on top of the confirmation height before the output can be
+// spent.
+func (c *CsvInput) BlocksToMaturity() uint32 {
+	return c.blocksToMaturity
+}
+
 // HtlcSucceedInput constitutes a sweep input that needs a pre-image. The input
 // is expected to reside on the commitment tx of the remote party and should
 // not be a second level tx output.
@@ -184,7 +213,62 @@ func (h *HtlcSucceedInput) BlocksToMaturity() uint32 {
 	return 0
 }
 
+// SwapHTLCSuccessInput constitutes a sweep input that claims the on-chain HTLC
+// of a submarine swap using the payment preimage.
+type SwapHTLCSuccessInput struct {
+	inputKit
+
+	preimage []byte
+}
+
+// MakeSwapHTLCSuccessInput assembles a new input claiming a submarine swap HTLC
+// that can be used to construct a sweep transaction.
+func MakeSwapHTLCSuccessInput(outpoint *wire.OutPoint,
+	signDescriptor *SignDescriptor, preimage []byte,
+	heightHint uint32) SwapHTLCSuccessInput {
+
+	return SwapHTLCSuccessInput{
+		inputKit: inputKit{
+			outpoint:    *outpoint,
+			witnessType: SwapHTLCSuccess,
+			signDesc:    *signDescriptor,
+			heightHint:  heightHint,
+		},
+		preimage: preimage,
+	}
+}
+
+// CraftInputScript returns a valid set of input scripts allowing this output
+// to be spent. The returns input scripts should target the input at location
+// txIndex within the passed transaction.
+func (s *SwapHTLCSuccessInput) CraftInputScript(signer Signer,
+	txn *wire.MsgTx, hashCache *txscript.TxSigHashes,
+	txinIdx int) (*Script, error) {
+
+	desc := s.signDesc
+	desc.SigHashes = hashCache
+	desc.InputIndex = txinIdx
+
+	witness, err := SwapHTLCSpendSuccess(signer, &desc, txn, s.preimage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Script{
+		Witness: witness,
+	}, nil
+}
+
+// BlocksToMaturity returns the relative timelock, as a number of blocks, that
+// must be built on top of the confirmation height before the output can be
+// spent.
+func (s *SwapHTLCSuccessInput) BlocksToMaturity() uint32 {
+	return 0
+}
+
 // Compile-time constraints to ensure each input struct implement the Input
 // interface.
 var _ Input = (*BaseInput)(nil)
+var _ Input = (*CsvInput)(nil)
 var _ Input = (*HtlcSucceedInput)(nil)
+var _ Input = (*SwapHTLCSuccessInput)(nil)
@@ -599,6 +599,103 @@ func ReceiverHtlcSpendTimeout(signer Signer, signDesc *SignDescriptor,
 	return witnessStack, nil
 }
 
+// SwapHTLCScript constructs the public key script of the on-chain HTLC used
+// within a submarine swap. The receiver of the HTLC is able to claim the
+// output once they learn the payment preimage, while the sender is able to
+// reclaim it after the absolute CLTV expiry. As opposed to the HTLCs found on
+// commitment transactions, there's no revocation clause as the output is
+// never revoked.
+//
+// Possible Input Scripts:
+//    RECVR: <recvr sig> <preimage>
+//    SENDR: <sendr sig> 0
+//
+// OP_SIZE 32 OP_EQUAL
+// OP_IF
+//     OP_HASH160 <ripemd160(payment hash)> OP_EQUALVERIFY
+//     <recvr key>
+// OP_ELSE
+//     OP_DROP
+//     <cltv expiry> OP_CHECKLOCKTIMEVERIFY OP_DROP
+//     <sendr key>
+// OP_ENDIF
+// OP_CHECKSIG
+func SwapHTLCScript(cltvExpiry uint32, senderKey,
+	receiverKey *btcec.PublicKey, paymentHash []byte) ([]byte, error) {
+
+	builder := txscript.NewScriptBuilder()
+
+	// The size of the top stack item determines which clause is taken, a
+	// 32 byte item is treated as the payment preimage.
+	builder.AddOp(txscript.OP_SIZE)
+	builder.AddInt64(32)
+	builder.AddOp(txscript.OP_EQUAL)
+
+	// If it is the preimage, then it must hash to the payment hash, in
+	// which case the receiver's key is pushed onto the stack.
+	builder.AddOp(txscript.OP_IF)
+	builder.AddOp(txscript.OP_HASH160)
+	builder.AddData(Ripemd160H(paymentHash))
+	builder.AddOp(txscript.OP_EQUALVERIFY)
+	builder.AddData(receiverKey.SerializeCompressed())
+
+	// Otherwise, we'll drop the item and push the sender's key, as long as
+	// the spending transaction has a lock time beyond the CLTV expiry.
+	builder.AddOp(txscript.OP_ELSE)
+	builder.AddOp(txscript.OP_DROP)
+	builder.AddInt64(int64(cltvExpiry))
+	builder.AddOp(txscript.OP_CHECKLOCKTIMEVERIFY)
+	builder.AddOp(txscript.OP_DROP)
+	builder.AddData(senderKey.SerializeCompressed())
+	builder.AddOp(txscript.OP_ENDIF)
+
+	// In both cases, a valid signature under the key on top of the stack
+	// is required.
+	builder.AddOp(txscript.OP_CHECKSIG)
+
+	return builder.Script()
+}
+
+// SwapHTLCSpendSuccess constructs a valid witness allowing the receiver of a
+// submarine swap HTLC to claim the output using the payment preimage.
+func SwapHTLCSpendSuccess(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx, paymentPreimage []byte) (wire.TxWitness, error) {
+
+	sweepSig, err := signer.SignOutputRaw(sweepTx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessStack := wire.TxWitness(make([][]byte, 3))
+	witnessStack[0] = append(sweepSig, byte(signDesc.HashType))
+	witnessStack[1] = paymentPreimage
+	witnessStack[2] = signDesc.WitnessScript
+
+	return witnessStack, nil
+}
+
+// SwapHTLCSpendTimeout constructs a valid witness allowing the sender of a
+// submarine swap HTLC to reclaim the output after its CLTV expiry.
+//
+// NOTE: The lock time of the passed transaction MUST be set to at least the
+// CLTV expiry of the HTLC, and the target input MUST NOT have a final sequence
+// number. Otherwise, the OP_CHECKLOCKTIMEVERIFY check will fail.
+func SwapHTLCSpendTimeout(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
+
+	sweepSig, err := signer.SignOutputRaw(sweepTx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessStack := wire.TxWitness(make([][]byte, 3))
+	witnessStack[0] = append(sweepSig, byte(signDesc.HashType))
+	witnessStack[1] = nil
+	witnessStack[2] = signDesc.WitnessScript
+
+	return witnessStack, nil
+}
+
 // SecondLevelHtlcScript is the uniform script that's used as the output for
 // the second-level HTLC transactions. The second level transaction act as a
 // sort of covenant, ensuring that a 2-of-2 multi-sig output can only be
@@ -840,6 +937,125 @@ func CommitScriptUnencumbered(key *btcec.PublicKey) ([]byte, error) {
 	return builder.Script()
 }
 
+// CommitScriptToRemoteConfirmed constructs the witness script for the output on
+// the commitment transaction paying to the "other" party within channels using
+// anchor outputs. Unlike the regular p2wkh to-remote output, it can only be
+// spent after a confirmation, which prevents it from being used to carve out
+// of the package limits when CPFP'ing the commitment transaction.
+//
+// Possible Input Scripts:
+//     <remote sig>
+//
+// <remote key> OP_CHECKSIGVERIFY 1 OP_CHECKSEQUENCEVERIFY
+func CommitScriptToRemoteConfirmed(key *btcec.PublicKey) ([]byte, error) {
+	builder := txscript.NewScriptBuilder()
+
+	// Only the given key can spend the output.
+	builder.AddData(key.SerializeCompressed())
+	builder.AddOp(txscript.OP_CHECKSIGVERIFY)
+
+	// Check that it has one confirmation.
+	builder.AddOp(txscript.OP_1)
+	builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+
+	return builder.Script()
+}
+
+// CommitScriptAnchor constructs the witness script of an anchor output on
+// the commitment transaction of a channel using anchor outputs. Each party
+// has an anchor output spendable with its funding key, which allows it to
+// bump the fee of the commitment using CPFP. Once the commitment has been
+// confirmed for 16 blocks, anyone may sweep the anchor, so that the outputs
+// don't linger within the UTXO set.
+//
+// Possible Input Scripts:
+//     OWNER:  <sig>
+//     ANYONE: <emptyvector> (after 16 confirmations)
+//
+// <funding key> OP_CHECKSIG OP_IFDUP
+// OP_NOTIF
+//     OP_16 OP_CHECKSEQUENCEVERIFY
+// OP_ENDIF
+func CommitScriptAnchor(key *btcec.PublicKey) ([]byte, error) {
+	builder := txscript.NewScriptBuilder()
+
+	// The owner of the anchor can spend it at any time with a signature
+	// of its funding key, in which case the result of the signature check
+	// is left on the stack.
+	builder.AddData(key.SerializeCompressed())
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddOp(txscript.OP_IFDUP)
+
+	// Otherwise, anyone can spend it once it has 16 confirmations.
+	builder.AddOp(txscript.OP_NOTIF)
+	builder.AddOp(txscript.OP_16)
+	builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+	builder.AddOp(txscript.OP_ENDIF)
+
+	return builder.Script()
+}
+
+// CommitSpendAnchor constructs a valid witness allowing the owner of an anchor
+// output to spend it, e.g. within a transaction bumping the fee of the
+// commitment transaction using CPFP.
+func CommitSpendAnchor(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
+
+	sweepSig, err := signer.SignOutputRaw(sweepTx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessStack := wire.TxWitness(make([][]byte, 2))
+	witnessStack[0] = append(sweepSig, byte(signDesc.HashType))
+	witnessStack[1] = signDesc.WitnessScript
+
+	return witnessStack, nil
+}
+
+// CommitSpendAnchorAnyone constructs a witness allowing anyone to spend an
+// anchor output once it has 16 confirmations. The input spending it must
+// signal the relative lock time accordingly.
+func CommitSpendAnchorAnyone(script []byte) (wire.TxWitness, error) {
+	// An empty signature fails the check of the funding key, leading
+	// script execution to the CSV clause.
+	witnessStack := wire.TxWitness(make([][]byte, 2))
+	witnessStack[0] = nil
+	witnessStack[1] = script
+
+	return witnessStack, nil
+}
+
+// CommitSpendToRemoteConfirmed constructs a valid witness allowing a node to
+// spend its settled output on the counterparty's commitment transaction of a
+// channel using anchor outputs. The input spending it must signal a relative
+// lock time of one block.
+//
+// NOTE: The passed SignDescriptor should include the raw (untweaked) public
+// key of the receiver and also the proper single tweak value based on the
+// current commitment point.
+func CommitSpendToRemoteConfirmed(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
+
+	// Ensure the transaction version supports the validation of sequence
+	// locks and CSV semantics.
+	if sweepTx.Version < 2 {
+		return nil, fmt.Errorf("version of passed transaction MUST "+
+			"be >= 2, not %v", sweepTx.Version)
+	}
+
+	sweepSig, err := signer.SignOutputRaw(sweepTx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessStack := wire.TxWitness(make([][]byte, 2))
+	witnessStack[0] = append(sweepSig, byte(signDesc.HashType))
+	witnessStack[1] = signDesc.WitnessScript
+
+	return witnessStack, nil
+}
+
 // CommitSpendTimeout constructs a valid witness allowing the owner of a
 // particular commitment transaction to spend the output returning settled
 // funds back to themselves after a relative block timeout.  In order to
@@ -847,6 +847,328 @@ func TestSecondLevelHtlcSpends(t *testing.T) {
 	}
 }
 
+// TestSwapHTLCSpendValidation tests all possible valid+invalid redemption
+// paths of the on-chain HTLC used within submarine swaps.
+func TestSwapHTLCSpendValidation(t *testing.T) {
+	t.Parallel()
+
+	// Generate a payment preimage, along with a fake preimage that is of
+	// the proper size, but doesn't match the payment hash.
+	paymentPreimage := testHdSeed.CloneBytes()
+	paymentHash := sha256.Sum256(paymentPreimage)
+	fakePreimage := testHdSeed.CloneBytes()
+	fakePreimage[0] ^= 1
+
+	// Alice will be the sender of the HTLC, and Bob its receiver.
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		testWalletPrivKey)
+	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		bobsPrivKey)
+	paymentAmt := btcutil.Amount(1 * 10e8)
+	cltvExpiry := uint32(500)
+
+	htlcWitnessScript, err := SwapHTLCScript(
+		cltvExpiry, aliceKeyPub, bobKeyPub, paymentHash[:],
+	)
+	if err != nil {
+		t.Fatalf("unable to create swap htlc script: %v", err)
+	}
+	if len(htlcWitnessScript) > SwapHTLCScriptSize {
+		t.Fatalf("swap htlc script of size %d exceeds estimate of %d",
+			len(htlcWitnessScript), SwapHTLCScriptSize)
+	}
+	htlcPkScript, err := WitnessScriptHash(htlcWitnessScript)
+	if err != nil {
+		t.Fatalf("unable to create p2wsh htlc script: %v", err)
+	}
+	htlcOutput := &wire.TxOut{
+		Value:    int64(paymentAmt),
+		PkScript: htlcPkScript,
+	}
+
+	txid, err := chainhash.NewHash(testHdSeed.CloneBytes())
+	if err != nil {
+		t.Fatalf("unable to create txid: %v", err)
+	}
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  *txid,
+			Index: 0,
+		},
+	})
+	sweepTx.AddTxOut(
+		&wire.TxOut{
+			PkScript: []byte("doesn't matter"),
+			Value:    1 * 10e8,
+		},
+	)
+
+	aliceSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{aliceKeyPriv}}
+	bobSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{bobKeyPriv}}
+
+	// signDesc returns a sign descriptor for the given key, after setting
+	// the lock time of the sweep transaction, as it's covered by the
+	// signature.
+	signDesc := func(pubKey *btcec.PublicKey,
+		lockTime uint32) *SignDescriptor {
+
+		sweepTx.LockTime = lockTime
+
+		return &SignDescriptor{
+			KeyDesc: keychain.KeyDescriptor{
+				PubKey: pubKey,
+			},
+			WitnessScript: htlcWitnessScript,
+			Output:        htlcOutput,
+			HashType:      txscript.SigHashAll,
+			SigHashes:     txscript.NewTxSigHashes(sweepTx),
+			InputIndex:    0,
+		}
+	}
+
+	testCases := []struct {
+		witness func() wire.TxWitness
+		valid   bool
+	}{
+		{
+			// claim w/ valid preimage
+			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
+				return SwapHTLCSpendSuccess(
+					bobSigner, signDesc(bobKeyPub, 0),
+					sweepTx, paymentPreimage,
+				)
+			}),
+			true,
+		},
+		{
+			// claim w/ invalid preimage
+			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
+				return SwapHTLCSpendSuccess(
+					bobSigner, signDesc(bobKeyPub, 0),
+					sweepTx, fakePreimage,
+				)
+			}),
+			false,
+		},
+		{
+			// claim w/ valid preimage, but sender's sig
+			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
+				return SwapHTLCSpendSuccess(
+					aliceSigner, signDesc(aliceKeyPub, 0),
+					sweepTx, paymentPreimage,
+				)
+			}),
+			false,
+		},
+		{
+			// refund w/ invalid lock time
+			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
+				return SwapHTLCSpendTimeout(
+					aliceSigner,
+					signDesc(aliceKeyPub, cltvExpiry-1),
+					sweepTx,
+				)
+			}),
+			false,
+		},
+		{
+			// refund w/ valid lock time, but receiver's sig
+			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
+				return SwapHTLCSpendTimeout(
+					bobSigner,
+					signDesc(bobKeyPub, cltvExpiry),
+					sweepTx,
+				)
+			}),
+			false,
+		},
+		{
+			// refund w/ valid lock time
+			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
+				return SwapHTLCSpendTimeout(
+					aliceSigner,
+					signDesc(aliceKeyPub, cltvExpiry),
+					sweepTx,
+				)
+			}),
+			true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		sweepTx.TxIn[0].Witness = testCase.witness()
+
+		vm, err := txscript.NewEngine(htlcPkScript,
+			sweepTx, 0, txscript.StandardVerifyFlags, nil,
+			nil, int64(paymentAmt))
+		if err != nil {
+			t.Fatalf("unable to create engine: %v", err)
+		}
+
+		// This buffer will trace execution of the Script, only dumping
+		// out to stdout in the case that a test fails.
+		var debugBuf bytes.Buffer
+
+		done := false
+		for !done {
+			dis, err := vm.DisasmPC()
+			if err != nil {
+				t.Fatalf("stepping (%v)\n", err)
+			}
+			debugBuf.WriteString(fmt.Sprintf("stepping %v\n", dis))
+
+			done, err = vm.Step()
+			if err != nil && testCase.valid {
+				fmt.Println(debugBuf.String())
+				t.Fatalf("spend test case #%v failed, spend should be valid: %v", i, err)
+			} else if err == nil && !testCase.valid && done {
+				fmt.Println(debugBuf.String())
+				t.Fatalf("spend test case #%v succeed, spend should be invalid: %v", i, err)
+			}
+
+			debugBuf.WriteString(fmt.Sprintf("Stack: %v", vm.GetStack()))
+			debugBuf.WriteString(fmt.Sprintf("AltStack: %v", vm.GetAltStack()))
+		}
+	}
+}
+
+// TestCommitSpendAnchor checks that the anchor output of a commitment can be
+// spent by its owner at any time, and by anyone else only after 16
+// confirmations.
+func TestCommitSpendAnchor(t *testing.T) {
+	t.Parallel()
+
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		testWalletPrivKey)
+	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		bobsPrivKey)
+	anchorAmt := btcutil.Amount(330)
+
+	anchorScript, err := CommitScriptAnchor(aliceKeyPub)
+	if err != nil {
+		t.Fatalf("unable to create anchor script: %v", err)
+	}
+	if len(anchorScript) != AnchorScriptSize {
+		t.Fatalf("anchor script of size %d doesn't match estimate of "+
+			"%d", len(anchorScript), AnchorScriptSize)
+	}
+	anchorPkScript, err := WitnessScriptHash(anchorScript)
+	if err != nil {
+		t.Fatalf("unable to create p2wsh anchor script: %v", err)
+	}
+	anchorOutput := &wire.TxOut{
+		Value:    int64(anchorAmt),
+		PkScript: anchorPkScript,
+	}
+
+	txid, err := chainhash.NewHash(testHdSeed.CloneBytes())
+	if err != nil {
+		t.Fatalf("unable to create txid: %v", err)
+	}
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  *txid,
+			Index: 0,
+		},
+	})
+	sweepTx.AddTxOut(
+		&wire.TxOut{
+			PkScript: []byte("doesn't matter"),
+			Value:    1 * 10e8,
+		},
+	)
+
+	aliceSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{aliceKeyPriv}}
+	bobSigner := &MockSigner{Privkeys: []*btcec.PrivateKey{bobKeyPriv}}
+
+	// signDesc returns a sign descriptor for the given key, after setting
+	// the sequence of the sweeping input, as it's covered by the
+	// signature.
+	signDesc := func(pubKey *btcec.PublicKey,
+		sequence uint32) *SignDescriptor {
+
+		sweepTx.TxIn[0].Sequence = sequence
+
+		return &SignDescriptor{
+			KeyDesc: keychain.KeyDescriptor{
+				PubKey: pubKey,
+			},
+			WitnessScript: anchorScript,
+			Output:        anchorOutput,
+			HashType:      txscript.SigHashAll,
+			SigHashes:     txscript.NewTxSigHashes(sweepTx),
+			InputIndex:    0,
+		}
+	}
+
+	// anyone returns the witness spending the anchor without a signature,
+	// after setting the sequence of the sweeping input.
+	anyone := func(sequence uint32) func() (wire.TxWitness, error) {
+		return func() (wire.TxWitness, error) {
+			sweepTx.TxIn[0].Sequence = sequence
+			return CommitSpendAnchorAnyone(anchorScript)
+		}
+	}
+
+	testCases := []struct {
+		witness func() wire.TxWitness
+		valid   bool
+	}{
+		{
+			// spend by owner w/o delay
+			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
+				return CommitSpendAnchor(
+					aliceSigner, signDesc(aliceKeyPub, 0),
+					sweepTx,
+				)
+			}),
+			true,
+		},
+		{
+			// spend by the other party w/o delay
+			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
+				return CommitSpendAnchor(
+					bobSigner, signDesc(bobKeyPub, 0),
+					sweepTx,
+				)
+			}),
+			false,
+		},
+		{
+			// spend by anyone before 16 confirmations
+			makeWitnessTestCase(t, anyone(15)),
+			false,
+		},
+		{
+			// spend by anyone after 16 confirmations
+			makeWitnessTestCase(t, anyone(16)),
+			true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		sweepTx.TxIn[0].Witness = testCase.witness()
+
+		vm, err := txscript.NewEngine(anchorPkScript,
+			sweepTx, 0, txscript.StandardVerifyFlags, nil,
+			nil, int64(anchorAmt))
+		if err != nil {
+			t.Fatalf("unable to create engine: %v", err)
+		}
+
+		err = vm.Execute()
+		if err != nil && testCase.valid {
+			t.Fatalf("spend test case #%v failed, spend should be "+
+				"valid: %v", i, err)
+		} else if err == nil && !testCase.valid {
+			t.Fatalf("spend test case #%v succeeded, spend should "+
+				"be invalid", i)
+		}
+	}
+}
+
 // TestSpecificationKeyDerivation implements the test vectors provided in
 // BOLT-03, Appendix E.
 func TestSpecificationKeyDerivation(t *testing.T) {
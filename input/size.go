@@ -10,6 +10,13 @@ const (
 	// includes: one p2wsh input, out p2wkh output, and one p2wsh output.
 	CommitWeight int64 = 724
 
+	// AnchorCommitWeight is the weight of the base commitment transaction
+	// of channels using anchor outputs, which includes: one p2wsh input,
+	// two p2wsh outputs paying to either party, and two p2wsh anchor
+	// outputs.
+	AnchorCommitWeight int64 = BaseAnchorCommitmentTxWeight +
+		WitnessCommitmentTxWeight
+
 	// HtlcWeight is the weight of an HTLC output.
 	HtlcWeight int64 = 172
 )
@@ -40,6 +47,12 @@ const (
 	//	- WitnessScriptSHA256: 32 bytes
 	P2WSHSize = 1 + 1 + 32
 
+	// P2TRSize 34 bytes
+	//	- OP_1: 1 byte
+	//	- OP_DATA: 1 byte (x-only output key length)
+	//	- OutputKey: 32 bytes
+	P2TRSize = 1 + 1 + 32
+
 	// P2PKHOutputSize 34 bytes
 	//      - value: 8 bytes
 	//      - var_int: 1 byte (pkscript_length)
@@ -58,6 +71,12 @@ const (
 	//      - pkscript (p2wsh): 34 bytes
 	P2WSHOutputSize = 8 + 1 + P2WSHSize
 
+	// P2TROutputSize 43 bytes
+	//      - value: 8 bytes
+	//      - var_int: 1 byte (pkscript_length)
+	//      - pkscript (p2tr): 34 bytes
+	P2TROutputSize = 8 + 1 + P2TRSize
+
 	// P2SHOutputSize 32 bytes
 	//      - value: 8 bytes
 	//      - var_int: 1 byte (pkscript_length)
@@ -79,6 +98,13 @@ const (
 	//      - pubkey
 	P2WKHWitnessSize = 1 + 1 + 73 + 1 + 33
 
+	// TaprootKeySpendWitnessSize 67 bytes
+	//      - number_of_witness_elements: 1 byte
+	//      - signature_length: 1 byte
+	//      - signature: 65 bytes (schnorr signature, with an explicit
+	//        sighash flag unless SIGHASH_DEFAULT is used)
+	TaprootKeySpendWitnessSize = 1 + 1 + 65
+
 	// MultiSigSize 71 bytes
 	//	- OP_2: 1 byte
 	//	- OP_DATA: 1 byte (pubKeyAlice length)
@@ -131,6 +157,12 @@ const (
 	//	- PkScript (P2WPKH)
 	CommitmentKeyHashOutput = 8 + 1 + P2WPKHSize
 
+	// CommitmentAnchorOutput 43 bytes
+	//	- Value: 8 bytes
+	//	- VarInt: 1 byte (PkScript length)
+	//	- PkScript (P2WSH)
+	CommitmentAnchorOutput = 8 + 1 + P2WSHSize
+
 	// HTLCSize 43 bytes
 	//	- Value: 8 bytes
 	//	- VarInt: 1 byte (PkScript length)
@@ -165,6 +197,27 @@ const (
 	// BaseCommitmentTxWeight 500 weight
 	BaseCommitmentTxWeight = witnessScaleFactor * BaseCommitmentTxSize
 
+	// BaseAnchorCommitmentTxSize 223 + 43 * num-htlc-outputs bytes
+	//	- Version: 4 bytes
+	//	- WitnessHeader <---- part of the witness data
+	//	- CountTxIn: 1 byte
+	//	- TxIn: 41 bytes
+	//		FundingInput
+	//	- CountTxOut: 1 byte
+	//	- TxOut: 172 + 43 * num-htlc-outputs bytes
+	//		OutputPayingToThem,
+	//		OutputPayingToUs,
+	//		AnchorPayingToThem,
+	//		AnchorPayingToUs,
+	//		....HTLCOutputs...
+	//	- LockTime: 4 bytes
+	BaseAnchorCommitmentTxSize = 4 + 1 + FundingInputSize + 1 +
+		2*CommitmentDelayOutput + 2*CommitmentAnchorOutput + 4
+
+	// BaseAnchorCommitmentTxWeight 892 weight
+	BaseAnchorCommitmentTxWeight = witnessScaleFactor *
+		BaseAnchorCommitmentTxSize
+
 	// WitnessCommitmentTxWeight 224 weight
 	WitnessCommitmentTxWeight = WitnessHeaderSize + WitnessSize
 
@@ -219,6 +272,42 @@ const (
 	//      - witness_script (to_local_script)
 	ToLocalPenaltyWitnessSize = 1 + 1 + 73 + 1 + 1 + ToLocalScriptSize
 
+	// ToRemoteConfirmedScriptSize 37 bytes
+	//      - OP_DATA: 1 byte
+	//      - to_remote_key: 33 bytes
+	//      - OP_CHECKSIGVERIFY: 1 byte
+	//      - OP_1: 1 byte
+	//      - OP_CHECKSEQUENCEVERIFY: 1 byte
+	ToRemoteConfirmedScriptSize = 1 + 33 + 1 + 1 + 1
+
+	// ToRemoteConfirmedWitnessSize 113 bytes
+	//      - number_of_witness_elements: 1 byte
+	//      - sig_length: 1 byte
+	//      - sig: 73 bytes
+	//      - witness_script_length: 1 byte
+	//      - witness_script (to_remote_confirmed_script)
+	ToRemoteConfirmedWitnessSize = 1 + 1 + 73 + 1 +
+		ToRemoteConfirmedScriptSize
+
+	// AnchorScriptSize 40 bytes
+	//      - OP_DATA: 1 byte
+	//      - funding_key: 33 bytes
+	//      - OP_CHECKSIG: 1 byte
+	//      - OP_IFDUP: 1 byte
+	//      - OP_NOTIF: 1 byte
+	//              - OP_16: 1 byte
+	//              - OP_CHECKSEQUENCEVERIFY: 1 byte
+	//      - OP_ENDIF: 1 byte
+	AnchorScriptSize = 1 + 33 + 1 + 1 + 1 + 1 + 1 + 1
+
+	// AnchorWitnessSize 116 bytes
+	//      - number_of_witness_elements: 1 byte
+	//      - sig_length: 1 byte
+	//      - sig: 73 bytes
+	//      - witness_script_length: 1 byte
+	//      - witness_script (anchor_script)
+	AnchorWitnessSize = 1 + 1 + 73 + 1 + AnchorScriptSize
+
 	// AcceptedHtlcScriptSize 139 bytes
 	//      - OP_DUP: 1 byte
 	//      - OP_HASH160: 1 byte
@@ -357,6 +446,49 @@ const (
 	//      - witness_script_length: 1 byte
 	//      - witness_script (offered_htlc_script)
 	OfferedHtlcPenaltyWitnessSize = 1 + 1 + 73 + 1 + 33 + 1 + OfferedHtlcScriptSize
+
+	// SwapHTLCScriptSize 107 bytes
+	//      - OP_SIZE: 1 byte
+	//      - OP_DATA: 1 byte (preimage length)
+	//      - 32: 1 byte
+	//      - OP_EQUAL: 1 byte
+	//      - OP_IF: 1 byte
+	//              - OP_HASH160: 1 byte
+	//              - OP_DATA: 1 byte (ripemd160(payment_hash) length)
+	//              - ripemd160(payment_hash): 20 bytes
+	//              - OP_EQUALVERIFY: 1 byte
+	//              - OP_DATA: 1 byte (receiver_key length)
+	//              - receiver_key: 33 bytes
+	//      - OP_ELSE: 1 byte
+	//              - OP_DROP: 1 byte
+	//              - OP_DATA: 1 byte (cltv_expiry length)
+	//              - cltv_expiry: 4 bytes
+	//              - OP_CHECKLOCKTIMEVERIFY: 1 byte
+	//              - OP_DROP: 1 byte
+	//              - OP_DATA: 1 byte (sender_key length)
+	//              - sender_key: 33 bytes
+	//      - OP_ENDIF: 1 byte
+	//      - OP_CHECKSIG: 1 byte
+	SwapHTLCScriptSize = 8*1 + 20 + 2*1 + 33 + 3*1 + 4 + 3*1 + 33 + 2*1
+
+	// SwapHTLCSuccessWitnessSize 216 bytes
+	//      - number_of_witness_elements: 1 byte
+	//      - receiver_sig_length: 1 byte
+	//      - receiver_sig: 73 bytes
+	//      - payment_preimage_length: 1 byte
+	//      - payment_preimage: 32 bytes
+	//      - witness_script_length: 1 byte
+	//      - witness_script (swap_htlc_script)
+	SwapHTLCSuccessWitnessSize = 1 + 1 + 73 + 1 + 32 + 1 + SwapHTLCScriptSize
+
+	// SwapHTLCTimeoutWitnessSize 184 bytes
+	//      - number_of_witness_elements: 1 byte
+	//      - sender_sig_length: 1 byte
+	//      - sender_sig: 73 bytes
+	//      - nil_length: 1 byte
+	//      - witness_script_length: 1 byte
+	//      - witness_script (swap_htlc_script)
+	SwapHTLCTimeoutWitnessSize = 1 + 1 + 73 + 1 + 1 + SwapHTLCScriptSize
 )
 
 // EstimateCommitTxWeight estimate commitment transaction weight depending on
@@ -420,6 +552,14 @@ func (twe *TxWeightEstimator) AddWitnessInput(witnessSize int) *TxWeightEstimato
 	return twe
 }
 
+// AddTaprootKeySpendInput updates the weight estimate to account for an
+// additional input spending a P2TR output through the key path.
+func (twe *TxWeightEstimator) AddTaprootKeySpendInput() *TxWeightEstimator {
+	twe.AddWitnessInput(TaprootKeySpendWitnessSize)
+
+	return twe
+}
+
 // AddNestedP2WKHInput updates the weight estimate to account for an additional
 // input spending a P2SH output with a nested P2WKH redeem script.
 func (twe *TxWeightEstimator) AddNestedP2WKHInput() *TxWeightEstimator {
@@ -469,6 +609,15 @@ func (twe *TxWeightEstimator) AddP2WSHOutput() *TxWeightEstimator {
 	return twe
 }
 
+// AddP2TROutput updates the weight estimate to account for an additional
+// native P2TR output.
+func (twe *TxWeightEstimator) AddP2TROutput() *TxWeightEstimator {
+	twe.outputSize += P2TROutputSize
+	twe.outputCount++
+
+	return twe
+}
+
 // AddP2SHOutput updates the weight estimate to account for an additional P2SH
 // output.
 func (twe *TxWeightEstimator) AddP2SHOutput() *TxWeightEstimator {
@@ -478,6 +627,15 @@ func (twe *TxWeightEstimator) AddP2SHOutput() *TxWeightEstimator {
 	return twe
 }
 
+// AddTxOutput updates the weight estimate to account for an additional output
+// of arbitrary type, based on the size of its script.
+func (twe *TxWeightEstimator) AddTxOutput(txOut *wire.TxOut) *TxWeightEstimator {
+	twe.outputSize += txOut.SerializeSize()
+	twe.outputCount++
+
+	return twe
+}
+
 // Weight gets the estimated weight of the transaction.
 func (twe *TxWeightEstimator) Weight() int {
 	txSizeStripped := BaseTxSize +
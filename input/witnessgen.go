@@ -79,6 +79,27 @@ const (
 	// output that sends to a nested P2SH script that pays to a key solely
 	// under our control. The witness generated needs to include the
 	NestedWitnessKeyHash WitnessType = 11
+
+	// SwapHTLCSuccess is a witness that allows us to claim the on-chain
+	// HTLC of a submarine swap using the payment preimage. This witness
+	// type can only be generated by a SwapHTLCSuccessInput, as it requires
+	// knowledge of the preimage.
+	SwapHTLCSuccess WitnessType = 12
+
+	// SwapHTLCTimeout is a witness that allows us to reclaim the funds
+	// locked in the on-chain HTLC of a submarine swap once its absolute
+	// CLTV timeout has passed.
+	SwapHTLCTimeout WitnessType = 13
+
+	// CommitmentToRemoteConfirmed is a witness that allows us to spend our
+	// settled output on the counterparty's commitment transaction of a
+	// channel using anchor outputs, once the commitment has confirmed.
+	CommitmentToRemoteConfirmed WitnessType = 14
+
+	// CommitmentAnchor is a witness that allows us to spend our anchor
+	// output on a commitment transaction of a channel using anchor
+	// outputs, e.g. to bump the fee of the commitment using CPFP.
+	CommitmentAnchor WitnessType = 15
 )
 
 // Stirng returns a human readable version of the target WitnessType.
@@ -114,6 +135,18 @@ func (wt WitnessType) String() string {
 	case HtlcSecondLevelRevoke:
 		return "HtlcSecondLevelRevoke"
 
+	case SwapHTLCSuccess:
+		return "SwapHTLCSuccess"
+
+	case SwapHTLCTimeout:
+		return "SwapHTLCTimeout"
+
+	case CommitmentToRemoteConfirmed:
+		return "CommitmentToRemoteConfirmed"
+
+	case CommitmentAnchor:
+		return "CommitmentAnchor"
+
 	default:
 		return fmt.Sprintf("Unknown WitnessType: %v", uint32(wt))
 	}
@@ -162,6 +195,28 @@ func (wt WitnessType) GenWitnessFunc(signer Signer,
 				Witness: witness,
 			}, nil
 
+		case CommitmentToRemoteConfirmed:
+			witness, err := CommitSpendToRemoteConfirmed(
+				signer, desc, tx,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Script{
+				Witness: witness,
+			}, nil
+
+		case CommitmentAnchor:
+			witness, err := CommitSpendAnchor(signer, desc, tx)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Script{
+				Witness: witness,
+			}, nil
+
 		case CommitmentRevoke:
 			witness, err := CommitSpendRevoke(signer, desc, tx)
 			if err != nil {
@@ -235,6 +290,16 @@ func (wt WitnessType) GenWitnessFunc(signer Signer,
 				Witness: witness,
 			}, nil
 
+		case SwapHTLCTimeout:
+			witness, err := SwapHTLCSpendTimeout(signer, desc, tx)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Script{
+				Witness: witness,
+			}, nil
+
 		case WitnessKeyHash:
 			fallthrough
 		case NestedWitnessKeyHash:
@@ -0,0 +1,196 @@
+package invoices
+
+import (
+	"container/heap"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/lnd/lntypes"
+)
+
+// invoiceExpiry is an invoice that is to be canceled once it expires.
+type invoiceExpiry struct {
+	hash   lntypes.Hash
+	expiry time.Time
+}
+
+// expiryHeap is a min-heap of invoices ordered by their expiry.
+type expiryHeap []*invoiceExpiry
+
+// Len returns the number of invoices within the heap.
+//
+// NOTE: Part of the heap.Interface interface.
+func (h expiryHeap) Len() int { return len(h) }
+
+// Less returns whether the invoice at index i expires before the one at
+// index j.
+//
+// NOTE: Part of the heap.Interface interface.
+func (h expiryHeap) Less(i, j int) bool {
+	return h[i].expiry.Before(h[j].expiry)
+}
+
+// Swap swaps the invoices at indexes i and j.
+//
+// NOTE: Part of the heap.Interface interface.
+func (h expiryHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+// Push adds an invoice to the end of the heap.
+//
+// NOTE: Part of the heap.Interface interface.
+func (h *expiryHeap) Push(x interface{}) {
+	*h = append(*h, x.(*invoiceExpiry))
+}
+
+// Pop removes the invoice at the end of the heap.
+//
+// NOTE: Part of the heap.Interface interface.
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return x
+}
+
+// invoiceExpiryWatcher cancels invoices as soon as they expire, instead of
+// leaving them open until they're looked up.
+type invoiceExpiryWatcher struct {
+	started uint32
+	stopped uint32
+
+	// cancelInvoice is called for each invoice once it expires. Invoices
+	// that have been settled in the meantime are passed as well.
+	cancelInvoice func(lntypes.Hash)
+
+	// mu protects expiries, such that invoices can be added without
+	// waiting for the watcher to cancel the ones that expired.
+	mu       sync.Mutex
+	expiries expiryHeap
+
+	// newExpiry is signaled whenever an invoice is added, such that the
+	// watcher can reconsider the time at which the next invoice expires.
+	newExpiry chan struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newInvoiceExpiryWatcher creates a new invoiceExpiryWatcher that calls
+// cancelInvoice for each invoice that expires.
+func newInvoiceExpiryWatcher(
+	cancelInvoice func(lntypes.Hash)) *invoiceExpiryWatcher {
+
+	return &invoiceExpiryWatcher{
+		cancelInvoice: cancelInvoice,
+		newExpiry:     make(chan struct{}, 1),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine canceling the invoices as they expire.
+func (w *invoiceExpiryWatcher) Start() {
+	if !atomic.CompareAndSwapUint32(&w.started, 0, 1) {
+		return
+	}
+
+	w.wg.Add(1)
+	go w.watchExpiries()
+}
+
+// Stop signals the watcher to stop canceling invoices, and waits for it to
+// exit.
+func (w *invoiceExpiryWatcher) Stop() {
+	if !atomic.CompareAndSwapUint32(&w.stopped, 0, 1) {
+		return
+	}
+
+	close(w.quit)
+	w.wg.Wait()
+}
+
+// AddInvoice adds the invoice with the given hash to the set of invoices to
+// cancel once they expire. An invoice that has already expired is canceled
+// right away.
+func (w *invoiceExpiryWatcher) AddInvoice(hash lntypes.Hash,
+	expiry time.Time) {
+
+	w.mu.Lock()
+	heap.Push(&w.expiries, &invoiceExpiry{
+		hash:   hash,
+		expiry: expiry,
+	})
+	w.mu.Unlock()
+
+	select {
+	case w.newExpiry <- struct{}{}:
+	default:
+	}
+}
+
+// nextExpiry returns the time at which the next invoice expires, and false if
+// there are no invoices to watch.
+func (w *invoiceExpiryWatcher) nextExpiry() (time.Time, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.expiries) == 0 {
+		return time.Time{}, false
+	}
+
+	return w.expiries[0].expiry, true
+}
+
+// popExpired removes and returns the invoices that have expired by now.
+func (w *invoiceExpiryWatcher) popExpired(now time.Time) []lntypes.Hash {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var expired []lntypes.Hash
+	for len(w.expiries) > 0 && !w.expiries[0].expiry.After(now) {
+		invoice := heap.Pop(&w.expiries).(*invoiceExpiry)
+		expired = append(expired, invoice.hash)
+	}
+
+	return expired
+}
+
+// watchExpiries waits for the next invoice to expire, and cancels all
+// invoices that have expired by then.
+//
+// NOTE: This MUST be run as a goroutine.
+func (w *invoiceExpiryWatcher) watchExpiries() {
+	defer w.wg.Done()
+
+	for {
+		var (
+			timer   *time.Timer
+			expired <-chan time.Time
+		)
+		if expiry, ok := w.nextExpiry(); ok {
+			timer = time.NewTimer(time.Until(expiry))
+			expired = timer.C
+		}
+
+		select {
+		case <-expired:
+			for _, hash := range w.popExpired(time.Now()) {
+				w.cancelInvoice(hash)
+			}
+
+		case <-w.newExpiry:
+
+		case <-w.quit:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
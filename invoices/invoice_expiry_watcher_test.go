@@ -0,0 +1,54 @@
+package invoices
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/lnd/lntypes"
+)
+
+// TestInvoiceExpiryWatcher tests that invoices are canceled in the order in
+// which they expire, and that invoices which already expired are canceled
+// right away.
+func TestInvoiceExpiryWatcher(t *testing.T) {
+	t.Parallel()
+
+	canceled := make(chan lntypes.Hash, 3)
+	watcher := newInvoiceExpiryWatcher(func(hash lntypes.Hash) {
+		canceled <- hash
+	})
+	watcher.Start()
+	defer watcher.Stop()
+
+	now := time.Now()
+	expiries := []time.Time{
+		now.Add(200 * time.Millisecond),
+		now.Add(-time.Hour),
+		now.Add(100 * time.Millisecond),
+		now.Add(time.Hour),
+	}
+	for i, expiry := range expiries {
+		watcher.AddInvoice(lntypes.Hash{byte(i)}, expiry)
+	}
+
+	// The invoices should be canceled in the order of their expiry, and
+	// the one that only expires in an hour shouldn't be canceled at all.
+	for _, i := range []byte{1, 2, 0} {
+		select {
+		case hash := <-canceled:
+			if hash != (lntypes.Hash{i}) {
+				t.Fatalf("expected invoice %v to be canceled, "+
+					"got %v", i, hash[0])
+			}
+
+		case <-time.After(testTimeout):
+			t.Fatalf("invoice %v not canceled", i)
+		}
+	}
+
+	select {
+	case hash := <-canceled:
+		t.Fatalf("invoice %v canceled before it expired", hash[0])
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -9,10 +9,12 @@ import (
 
 	"github.com/litecoinfinance/btcutil"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/lntypes"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/queue"
+	"github.com/litecoinfinance/lnd/zpay32"
 )
 
 var (
@@ -35,6 +37,16 @@ type HodlEvent struct {
 	Hash     lntypes.Hash
 }
 
+// BlockNotifier notifies the registry of new blocks, such that accepted hold
+// invoices can be canceled before their htlcs expire. It is satisfied by
+// chainntnfs.ChainNotifier.
+type BlockNotifier interface {
+	// RegisterBlockEpochNtfn registers an intent to be notified of each
+	// new block connected to the tip of the main chain.
+	RegisterBlockEpochNtfn(*chainntnfs.BlockEpoch) (
+		*chainntnfs.BlockEpochEvent, error)
+}
+
 // InvoiceRegistry is a central registry of all the outstanding invoices
 // created by the daemon. The registry is a thin wrapper around a map in order
 // to ensure that all updates/reads are thread safe.
@@ -62,6 +74,18 @@ type InvoiceRegistry struct {
 	// value from the payment request.
 	decodeFinalCltvExpiry func(invoice string) (uint32, error)
 
+	// decodePayReq is a function used to decode the payment request of
+	// an invoice, from which we learn when the invoice expires.
+	decodePayReq func(payReq string) (*zpay32.Invoice, error)
+
+	// expiryWatcher, if non-nil, cancels invoices that are still open or
+	// accepted once they expire.
+	expiryWatcher *invoiceExpiryWatcher
+
+	// fiatRates, if non-nil, provides the exchange rates used to snapshot
+	// the fiat value of invoices denominated in a fiat currency.
+	fiatRates FiatRateProvider
+
 	// subscriptions is a map from a payment hash to a list of subscribers.
 	// It is used for efficient notification of links.
 	hodlSubscriptions map[lntypes.Hash]map[chan<- interface{}]struct{}
@@ -70,6 +94,20 @@ type InvoiceRegistry struct {
 	// is used to unsubscribe from all hashes efficiently.
 	hodlReverseSubscriptions map[chan<- interface{}]map[lntypes.Hash]struct{}
 
+	// blockNotifier, if non-nil, is used to cancel accepted hold invoices
+	// whose htlcs are about to expire.
+	blockNotifier BlockNotifier
+
+	// finalCltvRejectDelta is the number of blocks before the expiry of
+	// the earliest held htlc of an accepted hold invoice at which the
+	// invoice is canceled. Otherwise the channel of the htlc would be
+	// force closed by our peer once it expires.
+	finalCltvRejectDelta uint32
+
+	// heldHtlcExpiries maps the hash of each accepted invoice to the
+	// lowest expiry height of the htlcs held for it.
+	heldHtlcExpiries map[lntypes.Hash]uint32
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -77,11 +115,16 @@ type InvoiceRegistry struct {
 // NewRegistry creates a new invoice registry. The invoice registry
 // wraps the persistent on-disk invoice storage with an additional in-memory
 // layer. The in-memory layer is in place such that debug invoices can be added
-// which are volatile yet available system wide within the daemon.
+// which are volatile yet available system wide within the daemon. If
+// decodePayReq is nil, invoices aren't canceled once they expire. If
+// fiatRates is nil, no fiat rate snapshots are taken. If blockNotifier is nil,
+// accepted hold invoices aren't canceled before their htlcs expire.
 func NewRegistry(cdb *channeldb.DB, decodeFinalCltvExpiry func(invoice string) (
-	uint32, error)) *InvoiceRegistry {
+	uint32, error), decodePayReq func(payReq string) (*zpay32.Invoice,
+	error), fiatRates FiatRateProvider, blockNotifier BlockNotifier,
+	finalCltvRejectDelta uint32) *InvoiceRegistry {
 
-	return &InvoiceRegistry{
+	registry := &InvoiceRegistry{
 		cdb:                       cdb,
 		debugInvoices:             make(map[lntypes.Hash]*channeldb.Invoice),
 		notificationClients:       make(map[uint32]*InvoiceSubscription),
@@ -93,8 +136,21 @@ func NewRegistry(cdb *channeldb.DB, decodeFinalCltvExpiry func(invoice string) (
 		hodlSubscriptions:         make(map[lntypes.Hash]map[chan<- interface{}]struct{}),
 		hodlReverseSubscriptions:  make(map[chan<- interface{}]map[lntypes.Hash]struct{}),
 		decodeFinalCltvExpiry:     decodeFinalCltvExpiry,
+		decodePayReq:              decodePayReq,
+		fiatRates:                 fiatRates,
+		blockNotifier:             blockNotifier,
+		finalCltvRejectDelta:      finalCltvRejectDelta,
+		heldHtlcExpiries:          make(map[lntypes.Hash]uint32),
 		quit:                      make(chan struct{}),
 	}
+
+	if decodePayReq != nil {
+		registry.expiryWatcher = newInvoiceExpiryWatcher(
+			registry.cancelExpiredInvoice,
+		)
+	}
+
+	return registry
 }
 
 // Start starts the registry and all goroutines it needs to carry out its task.
@@ -103,11 +159,34 @@ func (i *InvoiceRegistry) Start() error {
 
 	go i.invoiceEventNotifier()
 
+	if i.expiryWatcher != nil {
+		if err := i.watchPendingInvoices(); err != nil {
+			return err
+		}
+		i.expiryWatcher.Start()
+	}
+
+	if i.blockNotifier == nil {
+		return nil
+	}
+
+	blockEpochs, err := i.blockNotifier.RegisterBlockEpochNtfn(nil)
+	if err != nil {
+		return err
+	}
+
+	i.wg.Add(1)
+	go i.heldHtlcExpiryWatcher(blockEpochs)
+
 	return nil
 }
 
 // Stop signals the registry for a graceful shutdown.
 func (i *InvoiceRegistry) Stop() {
+	if i.expiryWatcher != nil {
+		i.expiryWatcher.Stop()
+	}
+
 	close(i.quit)
 
 	i.wg.Wait()
@@ -195,6 +274,120 @@ func (i *InvoiceRegistry) invoiceEventNotifier() {
 	}
 }
 
+// watchPendingInvoices hands the invoices that are still open or accepted to
+// the expiry watcher.
+func (i *InvoiceRegistry) watchPendingInvoices() error {
+	pendingInvoices, err := i.cdb.FetchAllInvoices(true)
+	switch {
+	case err == channeldb.ErrNoInvoicesCreated:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for _, invoice := range pendingInvoices {
+		if invoice.Terms.State == channeldb.ContractCanceled {
+			continue
+		}
+
+		i.watchInvoiceExpiry(&invoice)
+	}
+
+	return nil
+}
+
+// watchInvoiceExpiry adds the invoice to the expiry watcher. Invoices without
+// a payment request, whose expiry is unknown, aren't watched.
+func (i *InvoiceRegistry) watchInvoiceExpiry(invoice *channeldb.Invoice) {
+	if len(invoice.PaymentRequest) == 0 {
+		return
+	}
+
+	payReq, err := i.decodePayReq(string(invoice.PaymentRequest))
+	if err != nil {
+		log.Errorf("Unable to decode payment request of invoice with "+
+			"add index %v: %v", invoice.AddIndex, err)
+		return
+	}
+
+	// We take the hash from the payment request, as the preimage of a
+	// hold invoice isn't known yet.
+	if payReq.PaymentHash == nil {
+		return
+	}
+
+	i.expiryWatcher.AddInvoice(
+		*payReq.PaymentHash, payReq.Timestamp.Add(payReq.Expiry()),
+	)
+}
+
+// cancelExpiredInvoice cancels the invoice with the given hash once it has
+// expired, unless it has been settled in the meantime.
+func (i *InvoiceRegistry) cancelExpiredInvoice(hash lntypes.Hash) {
+	err := i.CancelInvoice(hash)
+	switch err {
+	case nil:
+		log.Infof("Invoice(%v): canceled after expiring", hash)
+
+	// The invoice was either paid in time or deleted in the meantime.
+	case channeldb.ErrInvoiceAlreadySettled, channeldb.ErrInvoiceNotFound:
+
+	default:
+		log.Errorf("Invoice(%v): unable to cancel expired invoice: %v",
+			hash, err)
+	}
+}
+
+// heldHtlcExpiryWatcher cancels each accepted hold invoice once the earliest
+// of its held htlcs is about to expire. The htlcs are then canceled back
+// before our peer would force close the channel to claim them on-chain.
+//
+// NOTE: This MUST be run as a goroutine.
+func (i *InvoiceRegistry) heldHtlcExpiryWatcher(
+	blockEpochs *chainntnfs.BlockEpochEvent) {
+
+	defer i.wg.Done()
+	defer blockEpochs.Cancel()
+
+	for {
+		select {
+		case epoch, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return
+			}
+
+			i.cancelExpiringInvoices(uint32(epoch.Height))
+
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// cancelExpiringInvoices cancels the accepted hold invoices whose earliest
+// held htlc expires within finalCltvRejectDelta blocks of the given height.
+func (i *InvoiceRegistry) cancelExpiringInvoices(height uint32) {
+	i.Lock()
+	defer i.Unlock()
+
+	for hash, expiry := range i.heldHtlcExpiries {
+		if expiry > height+i.finalCltvRejectDelta {
+			continue
+		}
+
+		log.Infof("Invoice(%v): canceling hold invoice at height %v, "+
+			"its htlcs expire at height %v", hash, height, expiry)
+
+		// The invoice is only canceled once, as a failure to do so
+		// is unlikely to be resolved by the next block.
+		delete(i.heldHtlcExpiries, hash)
+		if err := i.cancelInvoice(hash); err != nil {
+			log.Errorf("Invoice(%v): unable to cancel hold "+
+				"invoice: %v", hash, err)
+		}
+	}
+}
+
 // dispatchToSingleClients passes the supplied event to all notification clients
 // that subscribed to all the invoice this event applies to.
 func (i *InvoiceRegistry) dispatchToSingleClients(event *invoiceEvent) {
@@ -408,6 +601,14 @@ func (i *InvoiceRegistry) AddInvoice(invoice *channeldb.Invoice,
 	i.Lock()
 	defer i.Unlock()
 
+	// Snapshot the exchange rate of the invoice's fiat currency, unless
+	// the caller already provided one.
+	if invoice.FiatCurrency != "" && invoice.CreationFiatRate == nil {
+		invoice.CreationFiatRate = i.fetchFiatRate(
+			paymentHash, invoice.FiatCurrency,
+		)
+	}
+
 	log.Debugf("Invoice(%v): added %v", paymentHash,
 		newLogClosure(func() string {
 			return spew.Sdump(invoice)
@@ -423,6 +624,10 @@ func (i *InvoiceRegistry) AddInvoice(invoice *channeldb.Invoice,
 	// notify the clients of this new invoice.
 	i.notifyClients(paymentHash, invoice, channeldb.ContractOpen)
 
+	if i.expiryWatcher != nil {
+		i.watchInvoiceExpiry(invoice)
+	}
+
 	return addIndex, nil
 }
 
@@ -471,8 +676,16 @@ func (i *InvoiceRegistry) LookupInvoice(rHash lntypes.Hash) (channeldb.Invoice,
 // to be taken on the htlc (settle or cancel). The caller needs to ensure that
 // the channel is either buffered or received on from another goroutine to
 // prevent deadlock.
+//
+// The expiry is the height at which the htlc expires. If the invoice is
+// still accepted once the earliest expiry of its htlcs draws near, it is
+// canceled. An expiry of zero means the htlc isn't watched.
+//
+// If circuitKey is non-nil, the htlc is recorded along with the invoice,
+// using the current height as its accept height.
 func (i *InvoiceRegistry) NotifyExitHopHtlc(rHash lntypes.Hash,
-	amtPaid lnwire.MilliSatoshi, hodlChan chan<- interface{}) (
+	amtPaid lnwire.MilliSatoshi, expiry, currentHeight uint32,
+	circuitKey *channeldb.CircuitKey, hodlChan chan<- interface{}) (
 	*HodlEvent, error) {
 
 	i.Lock()
@@ -497,7 +710,16 @@ func (i *InvoiceRegistry) NotifyExitHopHtlc(rHash lntypes.Hash,
 
 	// If this isn't a debug invoice, then we'll attempt to settle an
 	// invoice matching this rHash on disk (if one exists).
-	invoice, err := i.cdb.AcceptOrSettleInvoice(rHash, amtPaid)
+	var htlc *channeldb.HtlcAcceptDesc
+	if circuitKey != nil {
+		htlc = &channeldb.HtlcAcceptDesc{
+			CircuitKey:   *circuitKey,
+			AcceptHeight: currentHeight,
+			Expiry:       expiry,
+		}
+	}
+
+	invoice, err := i.cdb.AcceptOrSettleInvoice(rHash, amtPaid, htlc)
 	switch err {
 
 	// If invoice is already settled, settle htlc. This means we accept more
@@ -513,11 +735,18 @@ func (i *InvoiceRegistry) NotifyExitHopHtlc(rHash lntypes.Hash,
 	// subscribers.
 	case channeldb.ErrInvoiceAlreadyAccepted:
 		i.hodlSubscribe(hodlChan, rHash)
+		i.watchHeldHtlc(rHash, expiry)
 		return nil, nil
 
 	// If this call settled the invoice, settle the htlc. Otherwise
-	// subscribe for a future hodl event.
+	// subscribe for a future hodl event. This is also the case if the
+	// invoice was already accepted, but this htlc was recorded for the
+	// first time, such that its subscribers learn about the htlc.
 	case nil:
+		if invoice.Terms.State == channeldb.ContractSettled {
+			invoice = i.snapshotSettleFiatRate(rHash, invoice)
+		}
+
 		i.notifyClients(rHash, invoice, invoice.Terms.State)
 		switch invoice.Terms.State {
 		case channeldb.ContractSettled:
@@ -527,6 +756,7 @@ func (i *InvoiceRegistry) NotifyExitHopHtlc(rHash lntypes.Hash,
 		case channeldb.ContractAccepted:
 			// Subscribe to updates to this invoice.
 			i.hodlSubscribe(hodlChan, rHash)
+			i.watchHeldHtlc(rHash, expiry)
 			return nil, nil
 		default:
 			return nil, fmt.Errorf("unexpected invoice state %v",
@@ -553,6 +783,10 @@ func (i *InvoiceRegistry) SettleHodlInvoice(preimage lntypes.Preimage) error {
 	log.Debugf("Invoice(%v): settled with preimage %v", hash,
 		invoice.Terms.PaymentPreimage)
 
+	delete(i.heldHtlcExpiries, hash)
+
+	invoice = i.snapshotSettleFiatRate(hash, invoice)
+
 	i.notifyHodlSubscribers(HodlEvent{
 		Hash:     hash,
 		Preimage: &preimage,
@@ -568,6 +802,12 @@ func (i *InvoiceRegistry) CancelInvoice(payHash lntypes.Hash) error {
 	i.Lock()
 	defer i.Unlock()
 
+	return i.cancelInvoice(payHash)
+}
+
+// cancelInvoice cancels the invoice corresponding to the passed payment hash.
+// The caller must hold the registry lock.
+func (i *InvoiceRegistry) cancelInvoice(payHash lntypes.Hash) error {
 	log.Debugf("Invoice(%v): canceling invoice", payHash)
 
 	invoice, err := i.cdb.CancelInvoice(payHash)
@@ -576,6 +816,7 @@ func (i *InvoiceRegistry) CancelInvoice(payHash lntypes.Hash) error {
 	// canceled.
 	if err == channeldb.ErrInvoiceAlreadyCanceled {
 		log.Debugf("Invoice(%v): already canceled", payHash)
+		delete(i.heldHtlcExpiries, payHash)
 		return nil
 	}
 	if err != nil {
@@ -583,6 +824,7 @@ func (i *InvoiceRegistry) CancelInvoice(payHash lntypes.Hash) error {
 	}
 
 	log.Debugf("Invoice(%v): canceled", payHash)
+	delete(i.heldHtlcExpiries, payHash)
 	i.notifyHodlSubscribers(HodlEvent{
 		Hash: payHash,
 	})
@@ -591,6 +833,64 @@ func (i *InvoiceRegistry) CancelInvoice(payHash lntypes.Hash) error {
 	return nil
 }
 
+// watchHeldHtlc records the expiry of an htlc held for the accepted invoice
+// with the given hash, keeping track of the earliest expiry per invoice. The
+// caller must hold the registry lock.
+func (i *InvoiceRegistry) watchHeldHtlc(hash lntypes.Hash, expiry uint32) {
+	if expiry == 0 {
+		return
+	}
+
+	if current, ok := i.heldHtlcExpiries[hash]; ok && current <= expiry {
+		return
+	}
+	i.heldHtlcExpiries[hash] = expiry
+}
+
+// fetchFiatRate returns the current exchange rate of the given fiat currency,
+// or nil if it isn't available. As the rate is merely informational, failing
+// to obtain it doesn't prevent the invoice from being created or settled.
+func (i *InvoiceRegistry) fetchFiatRate(hash lntypes.Hash,
+	currency string) *channeldb.FiatRate {
+
+	if i.fiatRates == nil {
+		return nil
+	}
+
+	rate, err := i.fiatRates.FiatRate(currency)
+	if err != nil {
+		log.Warnf("Invoice(%v): unable to fetch %v exchange rate: %v",
+			hash, currency, err)
+		return nil
+	}
+
+	return rate
+}
+
+// snapshotSettleFiatRate records the exchange rate of the fiat currency of a
+// newly settled invoice, returning the updated invoice.
+func (i *InvoiceRegistry) snapshotSettleFiatRate(hash lntypes.Hash,
+	invoice *channeldb.Invoice) *channeldb.Invoice {
+
+	if invoice.FiatCurrency == "" {
+		return invoice
+	}
+
+	rate := i.fetchFiatRate(hash, invoice.FiatCurrency)
+	if rate == nil {
+		return invoice
+	}
+
+	updatedInvoice, err := i.cdb.SetInvoiceSettleFiatRate(hash, rate)
+	if err != nil {
+		log.Errorf("Invoice(%v): unable to record settle exchange "+
+			"rate: %v", hash, err)
+		return invoice
+	}
+
+	return updatedInvoice
+}
+
 // notifyClients notifies all currently registered invoice notification clients
 // of a newly added/settled invoice.
 func (i *InvoiceRegistry) notifyClients(hash lntypes.Hash,
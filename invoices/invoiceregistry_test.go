@@ -1,12 +1,14 @@
 package invoices
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/litecoinfinance/btcd/chaincfg"
+	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/lntypes"
 	"github.com/litecoinfinance/lnd/lnwire"
@@ -16,6 +18,14 @@ import (
 var (
 	testTimeout = 5 * time.Second
 
+	// testHtlcExpiry is the expiry height of the htlcs paying to the
+	// test invoices.
+	testHtlcExpiry = uint32(100)
+
+	// testCurrentHeight is the block height at which the htlcs paying to
+	// the test invoices arrive.
+	testCurrentHeight = uint32(50)
+
 	preimage = lntypes.Preimage{
 		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
@@ -54,7 +64,7 @@ func newTestContext(t *testing.T) (*InvoiceRegistry, func()) {
 	}
 
 	// Instantiate and start the invoice registry.
-	registry := NewRegistry(cdb, decodeExpiry)
+	registry := NewRegistry(cdb, decodeExpiry, nil, nil, nil, 0)
 
 	err = registry.Start()
 	if err != nil {
@@ -121,7 +131,10 @@ func TestSettleInvoice(t *testing.T) {
 
 	// Settle invoice with a slightly higher amount.
 	amtPaid := lnwire.MilliSatoshi(100500)
-	_, err = registry.NotifyExitHopHtlc(hash, amtPaid, hodlChan)
+	_, err = registry.NotifyExitHopHtlc(
+		hash, amtPaid, testHtlcExpiry, testCurrentHeight, nil,
+		hodlChan,
+	)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -153,13 +166,19 @@ func TestSettleInvoice(t *testing.T) {
 	}
 
 	// Try to settle again.
-	_, err = registry.NotifyExitHopHtlc(hash, amtPaid, hodlChan)
+	_, err = registry.NotifyExitHopHtlc(
+		hash, amtPaid, testHtlcExpiry, testCurrentHeight, nil,
+		hodlChan,
+	)
 	if err != nil {
 		t.Fatal("expected duplicate settle to succeed")
 	}
 
 	// Try to settle again with a different amount.
-	_, err = registry.NotifyExitHopHtlc(hash, amtPaid+600, hodlChan)
+	_, err = registry.NotifyExitHopHtlc(
+		hash, amtPaid+600, testHtlcExpiry, testCurrentHeight, nil,
+		hodlChan,
+	)
 	if err != nil {
 		t.Fatal("expected duplicate settle to succeed")
 	}
@@ -274,7 +293,10 @@ func TestCancelInvoice(t *testing.T) {
 	// Notify arrival of a new htlc paying to this invoice. This should
 	// succeed.
 	hodlChan := make(chan interface{})
-	event, err := registry.NotifyExitHopHtlc(hash, amt, hodlChan)
+	event, err := registry.NotifyExitHopHtlc(
+		hash, amt, testHtlcExpiry, testCurrentHeight, nil,
+		hodlChan,
+	)
 	if err != nil {
 		t.Fatal("expected settlement of a canceled invoice to succeed")
 	}
@@ -292,7 +314,7 @@ func TestHoldInvoice(t *testing.T) {
 	defer cleanup()
 
 	// Instantiate and start the invoice registry.
-	registry := NewRegistry(cdb, decodeExpiry)
+	registry := NewRegistry(cdb, decodeExpiry, nil, nil, nil, 0)
 
 	err = registry.Start()
 	if err != nil {
@@ -342,10 +364,17 @@ func TestHoldInvoice(t *testing.T) {
 	amtPaid := lnwire.MilliSatoshi(100500)
 
 	hodlChan := make(chan interface{}, 1)
+	circuitKey := channeldb.CircuitKey{
+		ChanID: lnwire.NewShortChanIDFromInt(1),
+		HtlcID: 2,
+	}
 
 	// NotifyExitHopHtlc without a preimage present in the invoice registry
 	// should be possible.
-	event, err := registry.NotifyExitHopHtlc(hash, amtPaid, hodlChan)
+	event, err := registry.NotifyExitHopHtlc(
+		hash, amtPaid, testHtlcExpiry, testCurrentHeight, &circuitKey,
+		hodlChan,
+	)
 	if err != nil {
 		t.Fatalf("expected settle to succeed but got %v", err)
 	}
@@ -354,7 +383,10 @@ func TestHoldInvoice(t *testing.T) {
 	}
 
 	// Test idempotency.
-	event, err = registry.NotifyExitHopHtlc(hash, amtPaid, hodlChan)
+	event, err = registry.NotifyExitHopHtlc(
+		hash, amtPaid, testHtlcExpiry, testCurrentHeight, &circuitKey,
+		hodlChan,
+	)
 	if err != nil {
 		t.Fatalf("expected settle to succeed but got %v", err)
 	}
@@ -373,6 +405,9 @@ func TestHoldInvoice(t *testing.T) {
 	if update.AmtPaid != amtPaid {
 		t.Fatal("invoice AmtPaid incorrect")
 	}
+	assertInvoiceHtlc(
+		t, update, circuitKey, amtPaid, channeldb.HtlcStateAccepted,
+	)
 
 	// Settling with preimage should succeed.
 	err = registry.SettleHodlInvoice(preimage)
@@ -398,6 +433,9 @@ func TestHoldInvoice(t *testing.T) {
 		t.Fatalf("expected state ContractSettled, but got %v",
 			update.Terms.State)
 	}
+	assertInvoiceHtlc(
+		t, update, circuitKey, amtPaid, channeldb.HtlcStateSettled,
+	)
 
 	// Idempotency.
 	err = registry.SettleHodlInvoice(preimage)
@@ -412,6 +450,309 @@ func TestHoldInvoice(t *testing.T) {
 	}
 }
 
+// assertInvoiceHtlc asserts that the invoice holds a single htlc, received
+// with the given circuit key, amount and state.
+func assertInvoiceHtlc(t *testing.T, invoice *channeldb.Invoice,
+	circuitKey channeldb.CircuitKey, amt lnwire.MilliSatoshi,
+	state channeldb.HtlcState) {
+
+	t.Helper()
+
+	if len(invoice.Htlcs) != 1 {
+		t.Fatalf("expected 1 htlc, got %d", len(invoice.Htlcs))
+	}
+
+	htlc, ok := invoice.Htlcs[circuitKey]
+	switch {
+	case !ok:
+		t.Fatalf("htlc %v not recorded", circuitKey)
+
+	case htlc.Amt != amt:
+		t.Fatalf("expected htlc amount %v, got %v", amt, htlc.Amt)
+
+	case htlc.AcceptHeight != testCurrentHeight:
+		t.Fatalf("expected accept height %v, got %v",
+			testCurrentHeight, htlc.AcceptHeight)
+
+	case htlc.Expiry != testHtlcExpiry:
+		t.Fatalf("expected expiry %v, got %v", testHtlcExpiry,
+			htlc.Expiry)
+
+	case htlc.State != state:
+		t.Fatalf("expected htlc state %v, got %v", state, htlc.State)
+
+	case (state == channeldb.HtlcStateAccepted) !=
+		htlc.ResolveTime.IsZero():
+
+		t.Fatalf("unexpected resolve time %v for htlc state %v",
+			htlc.ResolveTime, state)
+	}
+}
+
+// mockBlockNotifier is a BlockNotifier whose blocks are sent by the test.
+type mockBlockNotifier struct {
+	epochs chan *chainntnfs.BlockEpoch
+}
+
+func (m *mockBlockNotifier) RegisterBlockEpochNtfn(
+	*chainntnfs.BlockEpoch) (*chainntnfs.BlockEpochEvent, error) {
+
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: m.epochs,
+		Cancel: func() {},
+	}, nil
+}
+
+// TestHoldInvoiceExpiry tests that an accepted hold invoice is canceled once
+// the earliest of its htlcs is about to expire.
+func TestHoldInvoiceExpiry(t *testing.T) {
+	defer timeout(t)()
+
+	cdb, cleanup, err := newDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	const rejectDelta = 10
+	notifier := &mockBlockNotifier{
+		epochs: make(chan *chainntnfs.BlockEpoch),
+	}
+	registry := NewRegistry(
+		cdb, decodeExpiry, nil, nil, notifier, rejectDelta,
+	)
+
+	err = registry.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer registry.Stop()
+
+	subscription := registry.SubscribeSingleInvoice(hash)
+	defer subscription.Cancel()
+
+	invoice := &channeldb.Invoice{
+		Terms: channeldb.ContractTerm{
+			PaymentPreimage: channeldb.UnknownPreimage,
+			Value:           lnwire.MilliSatoshi(100000),
+		},
+	}
+	_, err = registry.AddInvoice(invoice, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := <-subscription.Updates
+	if update.Terms.State != channeldb.ContractOpen {
+		t.Fatalf("expected state ContractOpen, but got %v",
+			update.Terms.State)
+	}
+
+	// Accept the invoice with two htlcs, of which the second one expires
+	// first.
+	hodlChan := make(chan interface{}, 2)
+	for _, expiry := range []uint32{testHtlcExpiry, testHtlcExpiry - 5} {
+		event, err := registry.NotifyExitHopHtlc(
+			hash, invoice.Terms.Value, expiry, testCurrentHeight,
+			nil, hodlChan,
+		)
+		if err != nil {
+			t.Fatalf("unable to accept htlc: %v", err)
+		}
+		if event != nil {
+			t.Fatalf("unexpected direct resolution")
+		}
+	}
+
+	update = <-subscription.Updates
+	if update.Terms.State != channeldb.ContractAccepted {
+		t.Fatalf("expected state ContractAccepted, but got %v",
+			update.Terms.State)
+	}
+
+	// A block outside of the reject delta of the earliest expiry shouldn't
+	// cancel the invoice.
+	notifier.epochs <- &chainntnfs.BlockEpoch{
+		Height: int32(testHtlcExpiry - 5 - rejectDelta - 1),
+	}
+
+	select {
+	case <-hodlChan:
+		t.Fatalf("invoice canceled before reaching the reject delta")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Once the earliest expiry is within the reject delta, the invoice
+	// should be canceled.
+	notifier.epochs <- &chainntnfs.BlockEpoch{
+		Height: int32(testHtlcExpiry - 5 - rejectDelta),
+	}
+
+	hodlEvent := (<-hodlChan).(HodlEvent)
+	if hodlEvent.Preimage != nil {
+		t.Fatalf("expected cancel hodl event")
+	}
+
+	update = <-subscription.Updates
+	if update.Terms.State != channeldb.ContractCanceled {
+		t.Fatalf("expected state ContractCanceled, but got %v",
+			update.Terms.State)
+	}
+}
+
+// TestInvoiceExpiry tests that open and accepted invoices are canceled once
+// they expire, releasing the htlcs held for them.
+func TestInvoiceExpiry(t *testing.T) {
+	defer timeout(t)()
+
+	cdb, cleanup, err := newDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	// The payment request of each test invoice is the key of its decoded
+	// form within payReqs.
+	payReqs := make(map[string]*zpay32.Invoice)
+	decodePayReq := func(payReq string) (*zpay32.Invoice, error) {
+		decoded, ok := payReqs[payReq]
+		if !ok {
+			return nil, fmt.Errorf("unknown payment request")
+		}
+		return decoded, nil
+	}
+
+	registry := NewRegistry(
+		cdb, decodeExpiry, decodePayReq, nil, nil, 0,
+	)
+	err = registry.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer registry.Stop()
+
+	// addInvoice adds an invoice expiring after the given duration,
+	// returning a subscription to its updates.
+	addInvoice := func(payReq string, preimage lntypes.Preimage,
+		hash lntypes.Hash,
+		expiry time.Duration) *SingleInvoiceSubscription {
+
+		decoded, err := zpay32.NewInvoice(
+			&chaincfg.MainNetParams, hash, time.Now(),
+			zpay32.Description(payReq), zpay32.Expiry(expiry),
+		)
+		if err != nil {
+			t.Fatalf("unable to create payment request: %v", err)
+		}
+		payReqs[payReq] = decoded
+
+		subscription := registry.SubscribeSingleInvoice(hash)
+
+		invoice := &channeldb.Invoice{
+			PaymentRequest: []byte(payReq),
+			Terms: channeldb.ContractTerm{
+				PaymentPreimage: preimage,
+				Value:           lnwire.MilliSatoshi(100000),
+			},
+		}
+		if _, err := registry.AddInvoice(invoice, hash); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		update := <-subscription.Updates
+		if update.Terms.State != channeldb.ContractOpen {
+			t.Fatalf("expected state ContractOpen, but got %v",
+				update.Terms.State)
+		}
+
+		return subscription
+	}
+
+	// assertCanceled asserts that the invoice of the subscription is
+	// canceled.
+	assertCanceled := func(subscription *SingleInvoiceSubscription) {
+		t.Helper()
+
+		update := <-subscription.Updates
+		if update.Terms.State != channeldb.ContractCanceled {
+			t.Fatalf("expected state ContractCanceled, but got %v",
+				update.Terms.State)
+		}
+	}
+
+	// An open invoice should be canceled once it expires.
+	openPreimage := lntypes.Preimage{2}
+	openSubscription := addInvoice(
+		"open", openPreimage, openPreimage.Hash(),
+		100*time.Millisecond,
+	)
+	defer openSubscription.Cancel()
+
+	assertCanceled(openSubscription)
+
+	// The same goes for an accepted hold invoice, in which case its htlc
+	// should be canceled back.
+	holdHash := lntypes.Hash{3}
+	holdSubscription := addInvoice(
+		"hold", channeldb.UnknownPreimage, holdHash,
+		200*time.Millisecond,
+	)
+	defer holdSubscription.Cancel()
+
+	hodlChan := make(chan interface{}, 1)
+	event, err := registry.NotifyExitHopHtlc(
+		holdHash, lnwire.MilliSatoshi(100000), testHtlcExpiry,
+		testCurrentHeight, nil, hodlChan,
+	)
+	if err != nil {
+		t.Fatalf("unable to accept htlc: %v", err)
+	}
+	if event != nil {
+		t.Fatalf("unexpected direct resolution")
+	}
+
+	update := <-holdSubscription.Updates
+	if update.Terms.State != channeldb.ContractAccepted {
+		t.Fatalf("expected state ContractAccepted, but got %v",
+			update.Terms.State)
+	}
+
+	hodlEvent := (<-hodlChan).(HodlEvent)
+	if hodlEvent.Preimage != nil {
+		t.Fatalf("expected cancel hodl event")
+	}
+	assertCanceled(holdSubscription)
+
+	// An invoice that is settled before it expires should remain settled.
+	settledPreimage := lntypes.Preimage{4}
+	settledSubscription := addInvoice(
+		"settled", settledPreimage, settledPreimage.Hash(),
+		100*time.Millisecond,
+	)
+	defer settledSubscription.Cancel()
+
+	_, err = registry.NotifyExitHopHtlc(
+		settledPreimage.Hash(), lnwire.MilliSatoshi(100000),
+		testHtlcExpiry, testCurrentHeight, nil, hodlChan,
+	)
+	if err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+
+	update = <-settledSubscription.Updates
+	if update.Terms.State != channeldb.ContractSettled {
+		t.Fatalf("expected state ContractSettled, but got %v",
+			update.Terms.State)
+	}
+
+	select {
+	case update := <-settledSubscription.Updates:
+		t.Fatalf("unexpected update of settled invoice: %v",
+			update.Terms.State)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
 func newDB() (*channeldb.DB, func(), error) {
 	// First, create a temporary directory to be used for the duration of
 	// this test.
@@ -434,3 +775,72 @@ func newDB() (*channeldb.DB, func(), error) {
 
 	return cdb, cleanUp, nil
 }
+
+// TestFiatRateSnapshots asserts that the exchange rate of an invoice's fiat
+// currency is recorded both when the invoice is created and when it's settled.
+func TestFiatRateSnapshots(t *testing.T) {
+	defer timeout(t)()
+
+	cdb, cleanup, err := newDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	rates := StaticRateProvider{"USD": 100}
+	registry := NewRegistry(cdb, decodeExpiry, nil, rates, nil, 0)
+
+	err = registry.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer registry.Stop()
+
+	invoice := *testInvoice
+	invoice.FiatCurrency = "USD"
+	if _, err := registry.AddInvoice(&invoice, hash); err != nil {
+		t.Fatal(err)
+	}
+
+	dbInvoice, _, err := registry.LookupInvoice(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dbInvoice.CreationFiatRate == nil ||
+		dbInvoice.CreationFiatRate.Rate != 100 {
+
+		t.Fatalf("expected creation rate of 100, got %v",
+			dbInvoice.CreationFiatRate)
+	}
+	if dbInvoice.SettleFiatRate != nil {
+		t.Fatalf("expected no settle rate for open invoice")
+	}
+
+	// Change the rate before settling the invoice, such that we can tell
+	// both snapshots apart.
+	rates["USD"] = 200
+
+	hodlChan := make(chan interface{}, 1)
+	_, err = registry.NotifyExitHopHtlc(
+		hash, invoice.Terms.Value, testHtlcExpiry, testCurrentHeight,
+		nil, hodlChan,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbInvoice, _, err = registry.LookupInvoice(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dbInvoice.SettleFiatRate == nil ||
+		dbInvoice.SettleFiatRate.Rate != 200 {
+
+		t.Fatalf("expected settle rate of 200, got %v",
+			dbInvoice.SettleFiatRate)
+	}
+	if dbInvoice.CreationFiatRate.Rate != 100 {
+		t.Fatalf("expected creation rate to remain 100, got %v",
+			dbInvoice.CreationFiatRate.Rate)
+	}
+}
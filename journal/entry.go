@@ -0,0 +1,179 @@
+package journal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+)
+
+var (
+	// ErrBrokenChain is returned when an entry of the journal doesn't
+	// commit to the entry preceding it, or its hash doesn't match its
+	// contents, indicating that the journal has been tampered with.
+	ErrBrokenChain = errors.New("journal hash chain is broken")
+)
+
+// EventType identifies the kind of event recorded by a journal entry.
+type EventType uint16
+
+const (
+	// EventChannelOpened records that a channel has been opened.
+	EventChannelOpened EventType = 1
+
+	// EventChannelClosed records that a channel has been closed.
+	EventChannelClosed EventType = 2
+
+	// EventPaymentSent records an outgoing payment whose amount is at
+	// least the configured threshold.
+	EventPaymentSent EventType = 3
+
+	// EventPaymentReceived records a settled invoice whose paid amount is
+	// at least the configured threshold.
+	EventPaymentReceived EventType = 4
+
+	// EventRPCAction records an administrative RPC call, along with the
+	// identity of the macaroon it was authorized with.
+	EventRPCAction EventType = 5
+
+	// EventAnchored records that the hash of a journal entry has been
+	// committed to on-chain.
+	EventAnchored EventType = 6
+)
+
+// String returns a human readable name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventChannelOpened:
+		return "ChannelOpened"
+	case EventChannelClosed:
+		return "ChannelClosed"
+	case EventPaymentSent:
+		return "PaymentSent"
+	case EventPaymentReceived:
+		return "PaymentReceived"
+	case EventRPCAction:
+		return "RPCAction"
+	case EventAnchored:
+		return "Anchored"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint16(t))
+	}
+}
+
+// Entry is a single event recorded in the journal. Each entry commits to the
+// hash of the entry preceding it, such that modifying or removing any entry
+// invalidates the hashes of all entries that follow it.
+type Entry struct {
+	// Seq is the sequence number of the entry. The first entry of the
+	// journal has sequence number 1.
+	Seq uint64
+
+	// Timestamp is the time at which the event was recorded.
+	Timestamp time.Time
+
+	// Type is the kind of event recorded.
+	Type EventType
+
+	// Identity identifies the party that caused the event, e.g. the
+	// macaroon an RPC call was authorized with. It is empty for events
+	// that aren't caused by an RPC client.
+	Identity string
+
+	// Details is a human readable description of the event.
+	Details string
+
+	// PrevHash is the hash of the preceding entry, or all zeroes for the
+	// first entry of the journal.
+	PrevHash [32]byte
+
+	// Hash is the hash of all of the above fields.
+	Hash [32]byte
+}
+
+// computeHash returns the hash committing to all fields of the entry besides
+// the hash itself.
+func (e *Entry) computeHash() ([32]byte, error) {
+	var b bytes.Buffer
+	if err := serializeContents(&b, e); err != nil {
+		return [32]byte{}, err
+	}
+
+	return sha256.Sum256(b.Bytes()), nil
+}
+
+// VerifyChain checks that each of the given entries, ordered by sequence
+// number, commits to the entry preceding it, starting with prevHash, and that
+// their hashes match their contents. ErrBrokenChain is returned otherwise.
+func VerifyChain(prevHash [32]byte, entries []*Entry) error {
+	for i, entry := range entries {
+		if i > 0 && entry.Seq != entries[i-1].Seq+1 {
+			return fmt.Errorf("%v: entry %d follows entry %d",
+				ErrBrokenChain, entry.Seq, entries[i-1].Seq)
+		}
+
+		if entry.PrevHash != prevHash {
+			return fmt.Errorf("%v: entry %d doesn't commit to its "+
+				"predecessor", ErrBrokenChain, entry.Seq)
+		}
+
+		hash, err := entry.computeHash()
+		if err != nil {
+			return err
+		}
+		if hash != entry.Hash {
+			return fmt.Errorf("%v: hash of entry %d doesn't match "+
+				"its contents", ErrBrokenChain, entry.Seq)
+		}
+
+		prevHash = entry.Hash
+	}
+
+	return nil
+}
+
+// serializeContents writes all fields of the entry besides its hash to w.
+func serializeContents(w io.Writer, e *Entry) error {
+	return channeldb.WriteElements(w,
+		e.Seq, uint64(e.Timestamp.UnixNano()), uint16(e.Type),
+		[]byte(e.Identity), []byte(e.Details), e.PrevHash,
+	)
+}
+
+// serializeEntry writes the entry, including its hash, to w.
+func serializeEntry(w io.Writer, e *Entry) error {
+	if err := serializeContents(w, e); err != nil {
+		return err
+	}
+
+	return channeldb.WriteElement(w, e.Hash)
+}
+
+// deserializeEntry reads an entry serialized by serializeEntry from r.
+func deserializeEntry(r io.Reader) (*Entry, error) {
+	var (
+		e         Entry
+		timestamp uint64
+		eventType uint16
+		identity  []byte
+		details   []byte
+	)
+	err := channeldb.ReadElements(r,
+		&e.Seq, &timestamp, &eventType, &identity, &details,
+		&e.PrevHash, &e.Hash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Timestamp = time.Unix(0, int64(timestamp))
+	e.Type = EventType(eventType)
+	e.Identity = string(identity)
+	e.Details = string(details)
+
+	return &e, nil
+}
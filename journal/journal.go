@@ -0,0 +1,369 @@
+package journal
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/txscript"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channelnotifier"
+	"github.com/litecoinfinance/lnd/invoices"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/subscribe"
+)
+
+var (
+	// ErrEmptyJournal is returned when anchoring a journal without any
+	// entries.
+	ErrEmptyJournal = errors.New("journal has no entries")
+
+	// ErrAnchorUnsupported is returned when anchoring the journal while
+	// no means of publishing the anchor has been configured.
+	ErrAnchorUnsupported = errors.New("journal anchoring not supported")
+
+	// anchorPrefix precedes the hash committed to by the OP_RETURN output
+	// of an anchor transaction, so that anchors can be told apart from
+	// other OP_RETURN commitments.
+	anchorPrefix = []byte("lnj")
+)
+
+// Config houses the resources required by the Journal.
+type Config struct {
+	// Store persists the entries of the journal.
+	Store Store
+
+	// PaymentThreshold is the minimum amount of the payments that are
+	// recorded in the journal, whether they're sent or received.
+	PaymentThreshold btcutil.Amount
+
+	// SubscribeChannelEvents, if non-nil, is used to subscribe to the
+	// opening and closing of channels, which are recorded in the journal.
+	SubscribeChannelEvents func() (*subscribe.Client, error)
+
+	// SubscribeInvoices, if non-nil, is used to subscribe to the
+	// settlement of invoices, which are recorded in the journal as
+	// received payments.
+	SubscribeInvoices func() *invoices.InvoiceSubscription
+
+	// PublishAnchor broadcasts a transaction with an OP_RETURN output
+	// paying to the given script at the given fee rate, returning the
+	// transaction's hash. If nil, the journal can't be anchored.
+	PublishAnchor func(pkScript []byte,
+		feeRate lnwallet.SatPerKWeight) (*chainhash.Hash, error)
+}
+
+// Journal is an append-only, hash-chained record of the events relevant to
+// auditing the node's operation: channels being opened and closed, payments
+// above a threshold, and administrative RPC calls along with the identity
+// they were authorized with. Since each entry commits to its predecessor, the
+// hash of the last entry commits to the entire journal. Committing that hash
+// on-chain allows a third party to verify that the journal hasn't been
+// rewritten after the fact.
+type Journal struct {
+	started uint32
+	stopped uint32
+
+	cfg *Config
+
+	// mu serializes appending entries, and guards last.
+	mu   sync.Mutex
+	last *Entry
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new Journal from the given config, resuming after the last
+// entry of its store.
+func New(cfg *Config) (*Journal, error) {
+	last, err := cfg.Store.LastEntry()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Journal{
+		cfg:  cfg,
+		last: last,
+		quit: make(chan struct{}),
+	}, nil
+}
+
+// Start subscribes to the channel and invoice events recorded by the journal.
+func (j *Journal) Start() error {
+	if !atomic.CompareAndSwapUint32(&j.started, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Starting event journal")
+
+	var chanEvents *subscribe.Client
+	if j.cfg.SubscribeChannelEvents != nil {
+		var err error
+		chanEvents, err = j.cfg.SubscribeChannelEvents()
+		if err != nil {
+			return err
+		}
+	}
+
+	var invoiceEvents *invoices.InvoiceSubscription
+	if j.cfg.SubscribeInvoices != nil {
+		invoiceEvents = j.cfg.SubscribeInvoices()
+	}
+
+	j.wg.Add(1)
+	go j.recordEvents(chanEvents, invoiceEvents)
+
+	return nil
+}
+
+// Stop signals the Journal to stop recording channel and invoice events.
+func (j *Journal) Stop() error {
+	if !atomic.CompareAndSwapUint32(&j.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Stopping event journal")
+
+	close(j.quit)
+	j.wg.Wait()
+
+	return nil
+}
+
+// recordEvents records the channel and invoice events received from the
+// given subscriptions, either of which may be nil.
+//
+// NOTE: This MUST be run as a goroutine.
+func (j *Journal) recordEvents(chanEvents *subscribe.Client,
+	invoiceEvents *invoices.InvoiceSubscription) {
+
+	defer j.wg.Done()
+
+	var (
+		chanUpdates     <-chan interface{}
+		settledInvoices <-chan *channeldb.Invoice
+	)
+	if chanEvents != nil {
+		defer chanEvents.Cancel()
+		chanUpdates = chanEvents.Updates()
+	}
+	if invoiceEvents != nil {
+		defer invoiceEvents.Cancel()
+		settledInvoices = invoiceEvents.SettledInvoices
+	}
+
+	for {
+		select {
+		case update, ok := <-chanUpdates:
+			if !ok {
+				chanUpdates = nil
+				continue
+			}
+			j.recordChannelEvent(update)
+
+		case invoice, ok := <-settledInvoices:
+			if !ok {
+				settledInvoices = nil
+				continue
+			}
+			j.recordSettledInvoice(invoice)
+
+		case <-j.quit:
+			return
+		}
+	}
+}
+
+// recordChannelEvent records the opening or closing of a channel.
+func (j *Journal) recordChannelEvent(update interface{}) {
+	var err error
+	switch event := update.(type) {
+	// The notifier sends events without the channel if it failed to look
+	// it up, in which case there's nothing meaningful to record.
+	case channelnotifier.OpenChannelEvent:
+		channel := event.Channel
+		if channel == nil {
+			return
+		}
+		_, err = j.Record(EventChannelOpened, "", fmt.Sprintf(
+			"ChannelPoint(%v) with peer %x opened, capacity=%v",
+			channel.FundingOutpoint,
+			channel.IdentityPub.SerializeCompressed(),
+			channel.Capacity,
+		))
+
+	case channelnotifier.ClosedChannelEvent:
+		summary := event.CloseSummary
+		if summary == nil {
+			return
+		}
+		_, err = j.Record(EventChannelClosed, "", fmt.Sprintf(
+			"ChannelPoint(%v) with peer %x closed at height %d, "+
+				"settled_balance=%v", summary.ChanPoint,
+			summary.RemotePub.SerializeCompressed(),
+			summary.CloseHeight, summary.SettledBalance,
+		))
+
+	default:
+		return
+	}
+	if err != nil {
+		log.Errorf("Unable to record channel event: %v", err)
+	}
+}
+
+// recordSettledInvoice records the payment of the given invoice, if its paid
+// amount reaches the payment threshold.
+func (j *Journal) recordSettledInvoice(invoice *channeldb.Invoice) {
+	if invoice.AmtPaid.ToSatoshis() < j.cfg.PaymentThreshold {
+		return
+	}
+
+	hash := sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+	_, err := j.Record(EventPaymentReceived, "", fmt.Sprintf(
+		"Received payment of %v for invoice %x", invoice.AmtPaid, hash,
+	))
+	if err != nil {
+		log.Errorf("Unable to record received payment: %v", err)
+	}
+}
+
+// RecordPaymentSent records an outgoing payment to the given destination, if
+// its amount reaches the payment threshold.
+func (j *Journal) RecordPaymentSent(paymentHash [32]byte, dest [33]byte,
+	amt, fee lnwire.MilliSatoshi) error {
+
+	if amt.ToSatoshis() < j.cfg.PaymentThreshold {
+		return nil
+	}
+
+	_, err := j.Record(EventPaymentSent, "", fmt.Sprintf(
+		"Sent payment %x of %v to %x, fee=%v", paymentHash, amt, dest,
+		fee,
+	))
+	return err
+}
+
+// Record appends a new entry for an event of the given type to the journal.
+// The identity identifies the party that caused the event, if any.
+func (j *Journal) Record(eventType EventType, identity,
+	details string) (*Entry, error) {
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry := &Entry{
+		Seq:       1,
+		Timestamp: time.Now(),
+		Type:      eventType,
+		Identity:  identity,
+		Details:   details,
+	}
+	if j.last != nil {
+		entry.Seq = j.last.Seq + 1
+		entry.PrevHash = j.last.Hash
+	}
+
+	hash, err := entry.computeHash()
+	if err != nil {
+		return nil, err
+	}
+	entry.Hash = hash
+
+	if err := j.cfg.Store.AppendEntry(entry); err != nil {
+		return nil, err
+	}
+	j.last = entry
+
+	log.Debugf("Recorded journal entry %d: %v %v", entry.Seq, eventType,
+		details)
+
+	return entry, nil
+}
+
+// Export returns the entries with sequence numbers within the inclusive range
+// [start, end]. An end of zero exports all entries from start onwards.
+func (j *Journal) Export(start, end uint64) ([]*Entry, error) {
+	if end == 0 {
+		end = math.MaxUint64
+	}
+
+	return j.cfg.Store.FetchEntries(start, end)
+}
+
+// Verify checks the integrity of the entire journal, returning an error
+// wrapping ErrBrokenChain if any entry has been modified or removed.
+func (j *Journal) Verify() error {
+	entries, err := j.Export(1, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) > 0 && entries[0].Seq != 1 {
+		return fmt.Errorf("%v: journal starts at entry %d",
+			ErrBrokenChain, entries[0].Seq)
+	}
+
+	return VerifyChain([32]byte{}, entries)
+}
+
+// Anchor commits the hash of the journal's last entry on-chain, within an
+// OP_RETURN output of a transaction paying the given fee rate. The anchor is
+// itself recorded in the journal. The entry committed to and the hash of the
+// anchor transaction are returned.
+func (j *Journal) Anchor(feeRate lnwallet.SatPerKWeight) (*Entry,
+	*chainhash.Hash, error) {
+
+	if j.cfg.PublishAnchor == nil {
+		return nil, nil, ErrAnchorUnsupported
+	}
+
+	j.mu.Lock()
+	last := j.last
+	j.mu.Unlock()
+
+	if last == nil {
+		return nil, nil, ErrEmptyJournal
+	}
+
+	pkScript, err := AnchorScript(last.Hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txid, err := j.cfg.PublishAnchor(pkScript, feeRate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Infof("Anchored journal entry %d with hash %x in tx %v", last.Seq,
+		last.Hash, txid)
+
+	_, err = j.Record(EventAnchored, "", fmt.Sprintf(
+		"Committed to entry %d with hash %x in transaction %v",
+		last.Seq, last.Hash, txid,
+	))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return last, txid, nil
+}
+
+// AnchorScript returns the OP_RETURN script committing to the given journal
+// entry hash.
+func AnchorScript(hash [32]byte) ([]byte, error) {
+	data := make([]byte, 0, len(anchorPrefix)+len(hash))
+	data = append(data, anchorPrefix...)
+	data = append(data, hash[:]...)
+
+	return txscript.NullDataScript(data)
+}
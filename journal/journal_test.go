@@ -0,0 +1,183 @@
+package journal
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/txscript"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// mockStore is an in-memory implementation of the Store interface.
+type mockStore struct {
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+func (m *mockStore) AppendEntry(e *Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e.Seq != uint64(len(m.entries))+1 {
+		return ErrOutOfOrder
+	}
+
+	entry := *e
+	m.entries = append(m.entries, &entry)
+
+	return nil
+}
+
+func (m *mockStore) LastEntry() (*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.entries) == 0 {
+		return nil, nil
+	}
+
+	entry := *m.entries[len(m.entries)-1]
+	return &entry, nil
+}
+
+func (m *mockStore) FetchEntries(start, end uint64) ([]*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var entries []*Entry
+	for _, e := range m.entries {
+		if e.Seq >= start && e.Seq <= end {
+			entry := *e
+			entries = append(entries, &entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// TestJournalChain asserts that the entries recorded in the journal form a
+// hash chain, and that tampering with any entry is detected.
+func TestJournalChain(t *testing.T) {
+	t.Parallel()
+
+	store := &mockStore{}
+	j, err := New(&Config{
+		Store:            store,
+		PaymentThreshold: 1000,
+	})
+	if err != nil {
+		t.Fatalf("unable to create journal: %v", err)
+	}
+
+	if _, err := j.Record(EventRPCAction, "id1", "call 1"); err != nil {
+		t.Fatalf("unable to record event: %v", err)
+	}
+
+	// A payment below the threshold shouldn't be recorded, while one
+	// reaching it should.
+	var paymentHash [32]byte
+	err = j.RecordPaymentSent(paymentHash, [33]byte{}, 999000, 0)
+	if err != nil {
+		t.Fatalf("unable to record payment: %v", err)
+	}
+	err = j.RecordPaymentSent(
+		paymentHash, [33]byte{}, lnwire.MilliSatoshi(1000000), 0,
+	)
+	if err != nil {
+		t.Fatalf("unable to record payment: %v", err)
+	}
+
+	entries, err := j.Export(1, 0)
+	if err != nil {
+		t.Fatalf("unable to export journal: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[1].Type != EventPaymentSent {
+		t.Fatalf("expected payment entry, got %v", entries[1].Type)
+	}
+	if err := j.Verify(); err != nil {
+		t.Fatalf("unable to verify journal: %v", err)
+	}
+
+	// A journal resumed from the same store should continue the chain.
+	j, err = New(&Config{Store: store})
+	if err != nil {
+		t.Fatalf("unable to create journal: %v", err)
+	}
+	if _, err := j.Record(EventRPCAction, "id2", "call 2"); err != nil {
+		t.Fatalf("unable to record event: %v", err)
+	}
+	if err := j.Verify(); err != nil {
+		t.Fatalf("unable to verify resumed journal: %v", err)
+	}
+
+	// Now, tamper with the details of the first entry, which should break
+	// the chain.
+	store.entries[0].Details = "call 3"
+	if err := j.Verify(); err == nil {
+		t.Fatalf("expected tampered journal to fail verification")
+	}
+}
+
+// TestJournalAnchor asserts that anchoring the journal publishes a commitment
+// to its last entry, and records the anchor.
+func TestJournalAnchor(t *testing.T) {
+	t.Parallel()
+
+	var published []byte
+	txid := chainhash.Hash{0x01}
+	j, err := New(&Config{
+		Store: &mockStore{},
+		PublishAnchor: func(pkScript []byte,
+			_ lnwallet.SatPerKWeight) (*chainhash.Hash, error) {
+
+			published = pkScript
+			return &txid, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create journal: %v", err)
+	}
+
+	const feeRate = lnwallet.SatPerKWeight(253)
+	if _, _, err := j.Anchor(feeRate); err != ErrEmptyJournal {
+		t.Fatalf("expected ErrEmptyJournal, got: %v", err)
+	}
+
+	entry, err := j.Record(EventRPCAction, "id", "call")
+	if err != nil {
+		t.Fatalf("unable to record event: %v", err)
+	}
+
+	anchored, anchorTxid, err := j.Anchor(feeRate)
+	if err != nil {
+		t.Fatalf("unable to anchor journal: %v", err)
+	}
+	if anchored.Hash != entry.Hash || *anchorTxid != txid {
+		t.Fatalf("anchored wrong entry or tx")
+	}
+
+	// The published script should push the prefixed hash of the entry.
+	pushes, err := txscript.PushedData(published)
+	if err != nil {
+		t.Fatalf("unable to parse anchor script: %v", err)
+	}
+	expData := append([]byte("lnj"), entry.Hash[:]...)
+	if len(pushes) != 1 || !bytes.Equal(pushes[0], expData) {
+		t.Fatalf("anchor script doesn't commit to entry hash")
+	}
+
+	// The anchor itself should be recorded as the next entry.
+	entries, err := j.Export(2, 2)
+	if err != nil {
+		t.Fatalf("unable to export journal: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Type != EventAnchored {
+		t.Fatalf("expected anchor to be recorded")
+	}
+}
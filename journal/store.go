@@ -0,0 +1,148 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+)
+
+var (
+	// journalBucketKey is the key of the top-level bucket that stores the
+	// entries of the journal.
+	//
+	// maps: seq -> serialized entry
+	journalBucketKey = []byte("event-journal")
+
+	// ErrOutOfOrder is returned when an entry is appended whose sequence
+	// number doesn't directly follow that of the journal's last entry.
+	ErrOutOfOrder = errors.New("journal entry out of order")
+
+	byteOrder = binary.BigEndian
+)
+
+// Store persists the entries of the journal. Entries can only be appended,
+// never modified or removed.
+type Store interface {
+	// AppendEntry adds the entry to the end of the journal. ErrOutOfOrder
+	// is returned if its sequence number doesn't directly follow that of
+	// the last entry.
+	AppendEntry(e *Entry) error
+
+	// LastEntry returns the last entry of the journal, or nil if the
+	// journal is empty.
+	LastEntry() (*Entry, error)
+
+	// FetchEntries returns the entries with sequence numbers within the
+	// inclusive range [start, end], ordered by sequence number.
+	FetchEntries(start, end uint64) ([]*Entry, error)
+}
+
+// journalStore is an implementation of the Store interface backed by the
+// channel database.
+type journalStore struct {
+	db *channeldb.DB
+}
+
+// A compile-time check to ensure journalStore implements the Store interface.
+var _ Store = (*journalStore)(nil)
+
+// NewStore returns a new Store backed by the given channel database.
+func NewStore(db *channeldb.DB) (Store, error) {
+	err := db.Update(func(tx kvdb.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(journalBucketKey)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &journalStore{
+		db: db,
+	}, nil
+}
+
+// AppendEntry adds the entry to the end of the journal.
+//
+// NOTE: Part of the Store interface.
+func (s *journalStore) AppendEntry(e *Entry) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		entries := tx.Bucket(journalBucketKey)
+
+		var lastSeq uint64
+		if k, _ := entries.Cursor().Last(); k != nil {
+			lastSeq = byteOrder.Uint64(k)
+		}
+		if e.Seq != lastSeq+1 {
+			return ErrOutOfOrder
+		}
+
+		var b bytes.Buffer
+		if err := serializeEntry(&b, e); err != nil {
+			return err
+		}
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], e.Seq)
+
+		return entries.Put(k[:], b.Bytes())
+	})
+}
+
+// LastEntry returns the last entry of the journal, or nil if the journal is
+// empty.
+//
+// NOTE: Part of the Store interface.
+func (s *journalStore) LastEntry() (*Entry, error) {
+	var entry *Entry
+	err := s.db.View(func(tx kvdb.Tx) error {
+		_, v := tx.Bucket(journalBucketKey).Cursor().Last()
+		if v == nil {
+			return nil
+		}
+
+		var err error
+		entry, err = deserializeEntry(bytes.NewReader(v))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// FetchEntries returns the entries with sequence numbers within the inclusive
+// range [start, end], ordered by sequence number.
+//
+// NOTE: Part of the Store interface.
+func (s *journalStore) FetchEntries(start, end uint64) ([]*Entry, error) {
+	var entries []*Entry
+	err := s.db.View(func(tx kvdb.Tx) error {
+		var startKey [8]byte
+		byteOrder.PutUint64(startKey[:], start)
+
+		c := tx.Bucket(journalBucketKey).Cursor()
+		for k, v := c.Seek(startKey[:]); k != nil; k, v = c.Next() {
+			if byteOrder.Uint64(k) > end {
+				break
+			}
+
+			entry, err := deserializeEntry(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, entry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
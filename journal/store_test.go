@@ -0,0 +1,101 @@
+package journal
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/lnd/channeldb"
+)
+
+// makeTestDB creates a new instance of the ChannelDB for testing purposes. A
+// callback which cleans up the created temporary directories is also returned
+// and intended to be executed after the test completes.
+func makeTestDB() (*channeldb.DB, func(), error) {
+	tempDirName, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cdb, err := channeldb.Open(tempDirName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanUp := func() {
+		cdb.Close()
+		os.RemoveAll(tempDirName)
+	}
+
+	return cdb, cleanUp, nil
+}
+
+// TestStore asserts that entries can only be appended to the store in order,
+// and are returned unmodified.
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to open channel db: %v", err)
+	}
+	defer cleanUp()
+
+	store, err := NewStore(cdb)
+	if err != nil {
+		t.Fatalf("unable to create store: %v", err)
+	}
+
+	last, err := store.LastEntry()
+	if err != nil {
+		t.Fatalf("unable to fetch last entry: %v", err)
+	}
+	if last != nil {
+		t.Fatalf("expected empty store, got entry %d", last.Seq)
+	}
+
+	var entries []*Entry
+	for seq := uint64(1); seq <= 3; seq++ {
+		entry := &Entry{
+			Seq:       seq,
+			Timestamp: time.Unix(0, int64(seq)),
+			Type:      EventRPCAction,
+			Identity:  "identity",
+			Details:   "details",
+			PrevHash:  [32]byte{byte(seq - 1)},
+			Hash:      [32]byte{byte(seq)},
+		}
+		if err := store.AppendEntry(entry); err != nil {
+			t.Fatalf("unable to append entry: %v", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	// Appending an entry that doesn't follow the last one should fail.
+	err = store.AppendEntry(&Entry{Seq: 5})
+	if err != ErrOutOfOrder {
+		t.Fatalf("expected ErrOutOfOrder, got: %v", err)
+	}
+
+	last, err = store.LastEntry()
+	if err != nil {
+		t.Fatalf("unable to fetch last entry: %v", err)
+	}
+	if !reflect.DeepEqual(last, entries[2]) {
+		t.Fatalf("unexpected last entry: want %v, got %v",
+			spew.Sdump(entries[2]), spew.Sdump(last))
+	}
+
+	fetched, err := store.FetchEntries(2, 3)
+	if err != nil {
+		t.Fatalf("unable to fetch entries: %v", err)
+	}
+	if !reflect.DeepEqual(fetched, entries[1:]) {
+		t.Fatalf("unexpected entries: want %v, got %v",
+			spew.Sdump(entries[1:]), spew.Sdump(fetched))
+	}
+}
@@ -96,6 +96,12 @@ const (
 	// session keys are limited to the lifetime of the session and are used
 	// to increase privacy in the watchtower protocol.
 	KeyFamilyTowerSession KeyFamily = 8
+
+	// KeyFamilySwap is the family of keys that will be used to derive the
+	// keys we use within the on-chain HTLCs of submarine swaps. A fresh key
+	// is derived for each swap, allowing the swap to be resolved by lnd
+	// without the swap client having to manage any keys of its own.
+	KeyFamilySwap KeyFamily = 9
 )
 
 // KeyLocator is a two-tuple that can be used to derive *any* key that has ever
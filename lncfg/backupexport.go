@@ -0,0 +1,54 @@
+package lncfg
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultBackupExportTimeout is the default timeout of each upload of
+	// the channel backup to an HTTP endpoint.
+	DefaultBackupExportTimeout = 30 * time.Second
+)
+
+// BackupExport holds the destinations the static channel backup is copied to
+// each time it's updated, in addition to the main backup file.
+type BackupExport struct {
+	// Paths are the local paths the backup is copied to.
+	Paths []string `long:"path" description:"A path, typically on another disk, to which the channel backup file is copied each time a channel is opened or closed. Can be specified multiple times."`
+
+	// URLs are the HTTP endpoints the backup is uploaded to.
+	URLs []string `long:"url" description:"An http(s) URL to which the channel backup file is uploaded with a PUT request each time a channel is opened or closed. The hex encoded sha256 of the backup is passed in the X-Backup-Sha256 header. Can be specified multiple times."`
+
+	// Timeout is the timeout of each upload to an HTTP endpoint.
+	Timeout time.Duration `long:"timeout" description:"The timeout of each upload of the channel backup to a URL."`
+}
+
+// Validate checks that the URLs of the backup destinations are valid, and that
+// the upload timeout is positive.
+func (b *BackupExport) Validate() error {
+	for _, rawURL := range b.URLs {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid backupexport.url %v: %v",
+				rawURL, err)
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("backupexport.url %v must be an "+
+				"http or https URL", rawURL)
+		}
+	}
+
+	if b.Timeout <= 0 {
+		return fmt.Errorf("backupexport.timeout %v must be positive",
+			b.Timeout)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure BackupExport implements the Validator
+// interface.
+var _ Validator = (*BackupExport)(nil)
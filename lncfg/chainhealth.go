@@ -0,0 +1,40 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChainHealth holds the configuration of the chain health monitor, which
+// alerts when the chain backend becomes unreachable or falls behind.
+type ChainHealth struct {
+	// Interval is the interval at which the chain backend is polled.
+	Interval time.Duration `long:"interval" description:"The interval at which the chain backend is polled for its best block and the tip of the network."`
+
+	// MaxLag is the number of blocks the chain backend may lag behind the
+	// tip of the network before an alert is logged.
+	MaxLag uint32 `long:"maxlag" description:"The number of blocks the chain backend may lag behind the tip of the network, as learned from its peers, before it's considered out of sync."`
+
+	// MaxTipAge is the age the best block of the chain backend may reach
+	// before an alert is logged.
+	MaxTipAge time.Duration `long:"maxtipage" description:"The age the best block of the chain backend may reach before the backend is considered stalled."`
+}
+
+// Validate checks that the interval and maximum tip age of the ChainHealth
+// configuration are positive.
+func (c *ChainHealth) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("chain health interval %v must be positive",
+			c.Interval)
+	}
+	if c.MaxTipAge <= 0 {
+		return fmt.Errorf("chain health max tip age %v must be "+
+			"positive", c.MaxTipAge)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure ChainHealth implements the Validator
+// interface.
+var _ Validator = (*ChainHealth)(nil)
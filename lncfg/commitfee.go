@@ -0,0 +1,32 @@
+package lncfg
+
+import "fmt"
+
+// CommitFee holds the bounds of the commitment fee rates of our channels,
+// which restrict both the fee updates we propose as the initiator of a
+// channel, and those we accept from the remote party.
+type CommitFee struct {
+	// MinFeeRate is the lowest commitment fee rate, in sat/kw, of our
+	// channels.
+	MinFeeRate uint64 `long:"minfeerate" description:"The lowest commitment fee rate in sat/kw we propose for channels we opened, and accept from the remote party of channels it opened. During low fee periods, the commitment fee of our channels is lowered down to it, which reduces the cost of a force close. The minimum relay fee rate of the backend always applies. Set to 0 to only apply the minimum relay fee rate."`
+
+	// MaxFeeRate is the highest commitment fee rate, in sat/kw, of our
+	// channels.
+	MaxFeeRate uint64 `long:"maxfeerate" description:"The highest commitment fee rate in sat/kw we propose for channels we opened, and accept from the remote party of channels it opened. Set to 0 to leave the commitment fee rate unbounded."`
+}
+
+// Validate checks that the maximum commitment fee rate of the CommitFee
+// configuration isn't below its minimum.
+func (c *CommitFee) Validate() error {
+	if c.MaxFeeRate != 0 && c.MaxFeeRate < c.MinFeeRate {
+		return fmt.Errorf("maximum commitment fee rate %v sat/kw is "+
+			"below the minimum of %v sat/kw", c.MaxFeeRate,
+			c.MinFeeRate)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure CommitFee implements the Validator
+// interface.
+var _ Validator = (*CommitFee)(nil)
@@ -0,0 +1,194 @@
+package lncfg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// includeDirective is the option name used within a config file to
+	// include the contents of another config file. The path may either be
+	// absolute or relative to the directory of the including file, and may
+	// contain glob patterns in order to include several files at once.
+	includeDirective = "include"
+
+	// maxIncludeDepth is the maximum depth of nested includes we'll
+	// follow before giving up.
+	maxIncludeDepth = 10
+
+	// defaultSection is the name of the section options belong to when
+	// they aren't preceded by any section header.
+	defaultSection = "Application Options"
+)
+
+var (
+	// envVarPattern matches references to environment variables of the
+	// form ${NAME} within a config file.
+	envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// ReadConfigFile reads the config file at the given path and returns its fully
+// resolved contents. References to environment variables of the form ${NAME}
+// are substituted with their value, and any include directives are replaced
+// with the resolved contents of the files they reference. An error is returned
+// if a referenced environment variable isn't set, or an included file can't
+// be read.
+//
+// If the config file itself doesn't exist, the returned error satisfies
+// os.IsNotExist.
+func ReadConfigFile(path string) ([]byte, error) {
+	var resolved bytes.Buffer
+	err := readConfigFile(&resolved, path, make(map[string]struct{}), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved.Bytes(), nil
+}
+
+// readConfigFile writes the resolved contents of the config file at the given
+// path to the passed buffer. The set of files currently being included is
+// tracked in order to detect include cycles.
+func readConfigFile(w *bytes.Buffer, path string,
+	including map[string]struct{}, depth int) error {
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := including[absPath]; ok {
+		return fmt.Errorf("config file %v includes itself", path)
+	}
+	if depth > maxIncludeDepth {
+		return fmt.Errorf("config file %v exceeds the maximum include "+
+			"depth of %d", path, maxIncludeDepth)
+	}
+
+	contents, err := ioutil.ReadFile(absPath)
+	if err != nil {
+		return err
+	}
+
+	including[absPath] = struct{}{}
+	defer delete(including, absPath)
+
+	section := defaultSection
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		// Comments are passed through as is, such that they may still
+		// reference unset environment variables.
+		if strings.HasPrefix(trimmed, ";") ||
+			strings.HasPrefix(trimmed, "#") {
+
+			w.WriteString(line + "\n")
+			continue
+		}
+
+		line, err = expandEnvVars(line)
+		if err != nil {
+			return fmt.Errorf("%v:%d: %v", path, lineNum, err)
+		}
+		trimmed = strings.TrimSpace(line)
+
+		// Keep track of the current section, as we'll need to restore
+		// it after including another file.
+		if strings.HasPrefix(trimmed, "[") &&
+			strings.HasSuffix(trimmed, "]") {
+
+			section = trimmed[1 : len(trimmed)-1]
+			w.WriteString(line + "\n")
+			continue
+		}
+
+		name, value, ok := splitOption(trimmed)
+		if !ok || name != includeDirective {
+			w.WriteString(line + "\n")
+			continue
+		}
+
+		includePaths, err := resolveIncludePaths(path, value)
+		if err != nil {
+			return fmt.Errorf("%v:%d: %v", path, lineNum, err)
+		}
+
+		for _, includePath := range includePaths {
+			err := readConfigFile(w, includePath, including, depth+1)
+			if err != nil {
+				return fmt.Errorf("%v:%d: unable to include "+
+					"%v: %v", path, lineNum, includePath, err)
+			}
+
+			// The included file may have switched sections, so
+			// we'll restore the section we were in before.
+			w.WriteString("[" + section + "]\n")
+		}
+	}
+
+	return scanner.Err()
+}
+
+// expandEnvVars substitutes all references to environment variables of the
+// form ${NAME} within the given line with their value.
+func expandEnvVars(line string) (string, error) {
+	var err error
+	expanded := envVarPattern.ReplaceAllStringFunc(line, func(ref string) string {
+		name := envVarPattern.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("environment variable %v is not set", name)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return expanded, nil
+}
+
+// splitOption splits a config file line of the form name=value into its name
+// and value.
+func splitOption(line string) (string, string, bool) {
+	idx := strings.Index(line, "=")
+	if idx == -1 {
+		return "", "", false
+	}
+
+	name := strings.TrimSpace(line[:idx])
+	value := strings.TrimSpace(line[idx+1:])
+
+	return name, value, true
+}
+
+// resolveIncludePaths returns the list of files referenced by an include
+// directive within the given config file. Relative paths are resolved against
+// the directory of the including file. If the path contains a glob pattern,
+// all matching files are returned in lexical order, otherwise the file must
+// exist.
+func resolveIncludePaths(configPath, includePath string) ([]string, error) {
+	if includePath == "" {
+		return nil, fmt.Errorf("empty include path")
+	}
+
+	if !filepath.IsAbs(includePath) {
+		includePath = filepath.Join(
+			filepath.Dir(configPath), includePath,
+		)
+	}
+
+	if !strings.ContainsAny(includePath, "*?[") {
+		return []string{includePath}, nil
+	}
+
+	// The matches returned by Glob are already sorted lexically.
+	return filepath.Glob(includePath)
+}
@@ -0,0 +1,111 @@
+package lncfg
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfigFiles writes the given config files to a new temporary directory
+// and returns its path.
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "lncfg")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("unable to create dir: %v", err)
+		}
+		err := ioutil.WriteFile(path, []byte(contents), 0600)
+		if err != nil {
+			t.Fatalf("unable to write %v: %v", name, err)
+		}
+	}
+
+	return dir
+}
+
+// TestReadConfigFile asserts that environment variables and include
+// directives within a config file are properly resolved.
+func TestReadConfigFile(t *testing.T) {
+	const envVar = "LNCFG_TEST_ALIAS"
+	os.Setenv(envVar, "mynode")
+	defer os.Unsetenv(envVar)
+
+	dir := writeConfigFiles(t, map[string]string{
+		"lnd.conf": strings.Join([]string{
+			"alias=${LNCFG_TEST_ALIAS}",
+			"; ${LNCFG_TEST_UNSET} is ignored within comments",
+			"include=conf.d/*.conf",
+			"debuglevel=info",
+			"[Bitcoin]",
+			"include = " + filepath.Join("bitcoin", "node.conf"),
+			"bitcoin.active=1",
+		}, "\n"),
+		"conf.d/01-rpc.conf":      "rpclisten=localhost:10009",
+		"conf.d/02-tor.conf":      "[Tor]\ntor.active=1",
+		"bitcoin/node.conf":       "bitcoin.node=bitcoind",
+		"conf.d/ignored.conf.bak": "alias=ignored",
+	})
+	defer os.RemoveAll(dir)
+
+	resolved, err := ReadConfigFile(filepath.Join(dir, "lnd.conf"))
+	if err != nil {
+		t.Fatalf("unable to read config file: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"alias=mynode",
+		"; ${LNCFG_TEST_UNSET} is ignored within comments",
+		"rpclisten=localhost:10009",
+		"[Application Options]",
+		"[Tor]",
+		"tor.active=1",
+		"[Application Options]",
+		"debuglevel=info",
+		"[Bitcoin]",
+		"bitcoin.node=bitcoind",
+		"[Bitcoin]",
+		"bitcoin.active=1",
+	}, "\n") + "\n"
+	if string(resolved) != expected {
+		t.Fatalf("expected resolved config:\n%v\ngot:\n%v", expected,
+			string(resolved))
+	}
+}
+
+// TestReadConfigFileErrors asserts that invalid config files are rejected.
+func TestReadConfigFileErrors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"unset.conf":   "alias=${LNCFG_TEST_UNSET}",
+		"missing.conf": "include=nonexistent.conf",
+		"cycle.conf":   "include=cycle2.conf",
+		"cycle2.conf":  "include=cycle.conf",
+	})
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"unset.conf", "missing.conf", "cycle.conf"} {
+		_, err := ReadConfigFile(filepath.Join(dir, name))
+		if err == nil {
+			t.Fatalf("expected %v to be rejected", name)
+		}
+		if os.IsNotExist(err) {
+			t.Fatalf("expected %v to be rejected due to its "+
+				"contents, got: %v", name, err)
+		}
+	}
+
+	// A missing config file itself should be reported as such, since it's
+	// not required to exist.
+	_, err := ReadConfigFile(filepath.Join(dir, "nonexistent.conf"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error, got: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultDBBackupInterval is the default interval at which snapshots
+	// of the channel database are backed up.
+	DefaultDBBackupInterval = 10 * time.Minute
+
+	// DefaultDBBackupNumSnapshots is the default number of most recent
+	// snapshots of the channel database that are kept.
+	DefaultDBBackupNumSnapshots = 144
+
+	// MinDBBackupInterval is the minimum interval at which snapshots of
+	// the channel database can be backed up, as each one reads the entire
+	// database.
+	MinDBBackupInterval = time.Minute
+)
+
+// DBBackup holds the configuration of the incremental, encrypted backups of
+// the channel database taken while lnd is running.
+type DBBackup struct {
+	// Dir is the directory the snapshots are written to.
+	Dir string `long:"dir" description:"The directory to back up encrypted snapshots of channel.db to, typically on another disk or a network share. Only the parts of the database that changed since the previous snapshot are written. Backups are disabled if not set. Only applies to the bolt backend."`
+
+	// KeyFile is the path of the file holding the key the snapshots are
+	// encrypted with.
+	KeyFile string `long:"keyfile" description:"The file holding the hex encoded 32-byte key the snapshots are encrypted with. A new random key is written to it if it doesn't exist. Keep a copy of it apart from the node, as the snapshots can't be restored without it."`
+
+	// Interval is the interval at which snapshots are taken.
+	Interval time.Duration `long:"interval" description:"The interval at which snapshots of channel.db are taken. Must be at least 1m."`
+
+	// NumSnapshots is the number of most recent snapshots that are kept.
+	NumSnapshots int `long:"numsnapshots" description:"The number of most recent snapshots to keep. Older snapshots are deleted, along with the parts of the database only they refer to."`
+
+	// Restore indicates that channel.db should be restored from the most
+	// recent snapshot before starting.
+	Restore bool `long:"restore" description:"Restore channel.db from the most recent snapshot within dbbackup.dir before starting. The existing channel.db must have been moved away beforehand, as it's never overwritten."`
+}
+
+// Enabled returns whether snapshots of the channel database are backed up.
+func (d *DBBackup) Enabled() bool {
+	return d.Dir != ""
+}
+
+// Validate checks that the key file is set if backups are enabled, and that
+// the interval and number of snapshots kept are sane.
+func (d *DBBackup) Validate() error {
+	if !d.Enabled() {
+		if d.Restore {
+			return fmt.Errorf("dbbackup.dir must be set to " +
+				"restore channel.db")
+		}
+
+		return nil
+	}
+
+	if d.KeyFile == "" {
+		return fmt.Errorf("dbbackup.keyfile must be set when " +
+			"dbbackup.dir is set")
+	}
+
+	if d.Interval < MinDBBackupInterval {
+		return fmt.Errorf("dbbackup.interval %v must be at least %v",
+			d.Interval, MinDBBackupInterval)
+	}
+
+	if d.NumSnapshots < 1 {
+		return fmt.Errorf("dbbackup.numsnapshots %v must be at least "+
+			"1", d.NumSnapshots)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure DBBackup implements the Validator
+// interface.
+var _ Validator = (*DBBackup)(nil)
@@ -0,0 +1,29 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+// FeePolicy holds the configuration of the fee policy manager, which applies
+// the scheduled routing policies of our channels.
+type FeePolicy struct {
+	// Interval is the interval at which the fee schedules of our channels
+	// are evaluated.
+	Interval time.Duration `long:"interval" description:"The interval at which the fee schedules of channels, as set with setfeeschedule, are evaluated against their local balance and the time of day."`
+}
+
+// Validate checks that the interval of the FeePolicy configuration is
+// positive.
+func (f *FeePolicy) Validate() error {
+	if f.Interval <= 0 {
+		return fmt.Errorf("fee policy interval %v must be positive",
+			f.Interval)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure FeePolicy implements the Validator
+// interface.
+var _ Validator = (*FeePolicy)(nil)
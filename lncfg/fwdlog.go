@@ -0,0 +1,31 @@
+package lncfg
+
+import "fmt"
+
+const (
+	// MinFwdLogRetentionDays is the minimum number of days forwarding
+	// events are retained for, as the fee report is computed from the
+	// events of the past month.
+	MinFwdLogRetentionDays = 30
+)
+
+// FwdLog holds the retention policy of the forwarding log.
+type FwdLog struct {
+	// RetentionDays is the number of days forwarding events are retained
+	// for within the forwarding log.
+	RetentionDays uint32 `long:"retentiondays" description:"The number of days forwarding events are kept in the forwarding log. Older events are rolled into daily per-channel summaries, which remain queryable through fwdingsummary, and then pruned from the log. Must be at least 30 if set. Set to 0 to keep all events."`
+}
+
+// Validate checks that the retention is long enough for the fee report to
+// remain accurate.
+func (f *FwdLog) Validate() error {
+	if f.RetentionDays != 0 && f.RetentionDays < MinFwdLogRetentionDays {
+		return fmt.Errorf("fwdlog.retentiondays %v must be at least "+
+			"%v", f.RetentionDays, MinFwdLogRetentionDays)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure FwdLog implements the Validator interface.
+var _ Validator = (*FwdLog)(nil)
@@ -0,0 +1,27 @@
+package lncfg
+
+// GC holds the policy under which old payments and invoices are automatically
+// deleted from the database.
+type GC struct {
+	// PaymentRetentionDays is the number of days completed payments are
+	// retained for.
+	PaymentRetentionDays uint32 `long:"paymentretentiondays" description:"The number of days completed payments are kept in the database, after which they're deleted. Their payment hashes are still known to have been paid. Set to 0 to keep all payments."`
+
+	// DeleteFailedPayments indicates whether the records of failed
+	// payments should be deleted.
+	DeleteFailedPayments bool `long:"deletefailedpayments" description:"Periodically delete the records of payments that failed."`
+
+	// InvoiceRetentionDays is the number of days settled invoices, and
+	// open invoices that haven't been paid, are retained for.
+	InvoiceRetentionDays uint32 `long:"invoiceretentiondays" description:"The number of days invoices are kept in the database after being settled, or after being created if they haven't been paid, after which they're deleted. It should exceed the expiry of the invoices created, otherwise unexpired invoices may be deleted. Set to 0 to keep all invoices."`
+
+	// DeleteCanceledInvoices indicates whether canceled invoices should
+	// be deleted.
+	DeleteCanceledInvoices bool `long:"deletecanceledinvoices" description:"Periodically delete canceled invoices."`
+}
+
+// Enabled returns whether any payments or invoices are to be deleted.
+func (g *GC) Enabled() bool {
+	return g.PaymentRetentionDays > 0 || g.DeleteFailedPayments ||
+		g.InvoiceRetentionDays > 0 || g.DeleteCanceledInvoices
+}
@@ -0,0 +1,29 @@
+package lncfg
+
+import "fmt"
+
+// Journal holds the configuration of the event journal, which records
+// auditable events in an append-only, hash-chained log.
+type Journal struct {
+	// Active enables the event journal.
+	Active bool `long:"active" description:"Record channel openings and closings, payments above the payment threshold, and administrative RPC calls along with the identity of their macaroon in an append-only, hash-chained journal."`
+
+	// PaymentThreshold is the minimum amount, in satoshis, of the sent
+	// and received payments recorded in the journal.
+	PaymentThreshold int64 `long:"paymentthreshold" description:"The minimum amount in satoshis of the sent and received payments that are recorded in the journal."`
+}
+
+// Validate checks that the payment threshold of the Journal configuration
+// isn't negative.
+func (j *Journal) Validate() error {
+	if j.PaymentThreshold < 0 {
+		return fmt.Errorf("journal payment threshold %v must not be "+
+			"negative", j.PaymentThreshold)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure Journal implements the Validator
+// interface.
+var _ Validator = (*Journal)(nil)
@@ -0,0 +1,49 @@
+package lncfg
+
+import "fmt"
+
+// PathFinding holds the weights of the cost function minimized by path
+// finding, which are used by payments and route queries that don't specify
+// their own, along with the bounds of the routes it may find.
+type PathFinding struct {
+	// FeeWeight scales the routing fee charged by an edge.
+	FeeWeight float64 `long:"feeweight" description:"The weight of the routing fee charged by each channel along a route. Increase it relative to the other weights to favour the cheapest routes."`
+
+	// RiskFactor controls the influence of the time lock delta of an edge,
+	// in billionths of msat per msat sent per block.
+	RiskFactor uint64 `long:"riskfactor" description:"The penalty for the time lock delta of each channel along a route, in billionths of a millisatoshi per millisatoshi sent per block. Increase it to favour routes that lock up funds the shortest in case a payment gets stuck."`
+
+	// ProbabilityWeight is the penalty, in msat, for each unit of the
+	// negative natural logarithm of the estimated success probability of
+	// an edge.
+	ProbabilityWeight uint64 `long:"probabilityweight" description:"The penalty in millisatoshis for the estimated probability of each channel along a route to fail, based on the outcome of past payment attempts. Increase it to favour the routes most likely to succeed. 0 ignores the history of past payments."`
+
+	// HoldTimeWeight is the penalty, in msat, for each second the node of
+	// an edge took on average to resolve HTLCs.
+	HoldTimeWeight uint64 `long:"holdtimeweight" description:"The penalty in millisatoshis for each second the node of each channel along a route took on average to resolve the HTLCs of past payments and forwards. Increase it to avoid slow nodes. 0 ignores the hold times of nodes."`
+
+	// MaxCltvExpiry is the maximum number of blocks for which the funds of
+	// a payment may be locked up along its route.
+	MaxCltvExpiry uint32 `long:"maxcltvexpiry" description:"The maximum number of blocks for which the funds of a payment may be locked up should it get stuck along its route, including the final CLTV delta. Routes with a larger total time lock are never used. 0 leaves the time lock of routes unbounded."`
+}
+
+// Validate checks that the weights of the PathFinding configuration aren't
+// negative, and don't leave path finding without any cost to minimize.
+func (p *PathFinding) Validate() error {
+	if p.FeeWeight < 0 {
+		return fmt.Errorf("path finding fee weight %v must not be "+
+			"negative", p.FeeWeight)
+	}
+	if p.FeeWeight == 0 && p.RiskFactor == 0 &&
+		p.ProbabilityWeight == 0 && p.HoldTimeWeight == 0 {
+
+		return fmt.Errorf("at least one path finding weight must be " +
+			"non-zero")
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure PathFinding implements the Validator
+// interface.
+var _ Validator = (*PathFinding)(nil)
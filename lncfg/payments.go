@@ -0,0 +1,76 @@
+package lncfg
+
+import (
+	"fmt"
+
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// DefaultFeeBudgetWarnPercent is the default percentage of the daily fee
+// budget that may be spent before a warning is emitted.
+const DefaultFeeBudgetWarnPercent = 80
+
+// Payments holds the configuration of the guardrails applied to the routing
+// fees of outgoing payments.
+type Payments struct {
+	// DailyFeeBudget is the maximum routing fee, in satoshis, that may be
+	// paid by all payments combined within any 24 hour window.
+	DailyFeeBudget uint64 `long:"dailyfeebudget" description:"The maximum routing fees in satoshis that may be paid by all outgoing payments combined within any 24 hour window. Routes that would exceed the remaining budget aren't used. 0 leaves the fees unbounded."`
+
+	// FeeBudgetWarnPercent is the percentage of the daily fee budget that
+	// may be spent before subscribers are warned.
+	FeeBudgetWarnPercent uint32 `long:"feebudgetwarnpercent" description:"The percentage of the daily fee budget that may be spent before a warning event is emitted."`
+
+	// DefaultFeeLimitPercent is the percentage of the payment amount used
+	// as the fee limit of payments that don't specify one.
+	DefaultFeeLimitPercent float64 `long:"defaultfeelimitpercent" description:"The percentage of the payment amount used as the fee limit of payments that don't specify one. If neither this nor defaultfeelimitfloormsat is set, the fee limit of such payments is the payment amount itself."`
+
+	// DefaultFeeLimitFloorMSat is the smallest fee limit, in
+	// millisatoshis, applied to payments that don't specify one.
+	DefaultFeeLimitFloorMSat uint64 `long:"defaultfeelimitfloormsat" description:"The smallest fee limit in millisatoshis of payments that don't specify one, such that small payments aren't left without a viable route by defaultfeelimitpercent."`
+
+	// MaxShards is the number of shards payments that don't specify
+	// otherwise may be split into.
+	MaxShards uint32 `long:"maxshards" description:"The maximum number of shards that payments not specifying their own limit may be split into, in order to send them along multiple routes at once. The payee must accept partial payments. 0 or 1 sends every payment along a single route."`
+}
+
+// Validate checks that the percentages of the Payments configuration are
+// within range.
+func (p *Payments) Validate() error {
+	if p.DefaultFeeLimitPercent < 0 || p.DefaultFeeLimitPercent > 100 {
+		return fmt.Errorf("default fee limit percent %v must be "+
+			"between 0 and 100", p.DefaultFeeLimitPercent)
+	}
+	if p.FeeBudgetWarnPercent == 0 || p.FeeBudgetWarnPercent > 100 {
+		return fmt.Errorf("fee budget warn percent %d must be between "+
+			"1 and 100", p.FeeBudgetWarnPercent)
+	}
+
+	return nil
+}
+
+// DefaultFeeLimit returns the fee limit of a payment of the given amount that
+// doesn't specify one. If no default fee limit is configured, the payment
+// amount itself is used, in order to avoid payment attempts from incurring
+// fees higher than the payment amount.
+func (p *Payments) DefaultFeeLimit(
+	amount lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	floor := lnwire.MilliSatoshi(p.DefaultFeeLimitFloorMSat)
+	if p.DefaultFeeLimitPercent == 0 && floor == 0 {
+		return amount
+	}
+
+	feeLimit := lnwire.MilliSatoshi(
+		float64(amount) * p.DefaultFeeLimitPercent / 100,
+	)
+	if feeLimit < floor {
+		return floor
+	}
+
+	return feeLimit
+}
+
+// Compile-time constraint to ensure Payments implements the Validator
+// interface.
+var _ Validator = (*Payments)(nil)
@@ -0,0 +1,134 @@
+package lncfg_test
+
+import (
+	"testing"
+
+	"github.com/litecoinfinance/lnd/lncfg"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestPaymentsDefaultFeeLimit asserts that the default fee limit is derived
+// from the configured percentage and floor, and falls back to the payment
+// amount if neither is set.
+func TestPaymentsDefaultFeeLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *lncfg.Payments
+		amount   lnwire.MilliSatoshi
+		expLimit lnwire.MilliSatoshi
+	}{
+		{
+			name:     "unset",
+			cfg:      &lncfg.Payments{},
+			amount:   100000,
+			expLimit: 100000,
+		},
+		{
+			name: "percent",
+			cfg: &lncfg.Payments{
+				DefaultFeeLimitPercent: 0.5,
+			},
+			amount:   100000,
+			expLimit: 500,
+		},
+		{
+			name: "percent above floor",
+			cfg: &lncfg.Payments{
+				DefaultFeeLimitPercent:   1,
+				DefaultFeeLimitFloorMSat: 500,
+			},
+			amount:   100000,
+			expLimit: 1000,
+		},
+		{
+			name: "percent below floor",
+			cfg: &lncfg.Payments{
+				DefaultFeeLimitPercent:   1,
+				DefaultFeeLimitFloorMSat: 5000,
+			},
+			amount:   100000,
+			expLimit: 5000,
+		},
+		{
+			name: "floor only",
+			cfg: &lncfg.Payments{
+				DefaultFeeLimitFloorMSat: 5000,
+			},
+			amount:   100000,
+			expLimit: 5000,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			limit := test.cfg.DefaultFeeLimit(test.amount)
+			if limit != test.expLimit {
+				t.Fatalf("expected fee limit %v, got %v",
+					test.expLimit, limit)
+			}
+		})
+	}
+}
+
+// TestValidatePayments asserts that validating the Payments config only
+// succeeds if its percentages are within range.
+func TestValidatePayments(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   *lncfg.Payments
+		valid bool
+	}{
+		{
+			name: "valid",
+			cfg: &lncfg.Payments{
+				DefaultFeeLimitPercent: 1,
+				FeeBudgetWarnPercent:   80,
+			},
+			valid: true,
+		},
+		{
+			name: "negative fee limit percent",
+			cfg: &lncfg.Payments{
+				DefaultFeeLimitPercent: -1,
+				FeeBudgetWarnPercent:   80,
+			},
+			valid: false,
+		},
+		{
+			name: "fee limit percent above 100",
+			cfg: &lncfg.Payments{
+				DefaultFeeLimitPercent: 101,
+				FeeBudgetWarnPercent:   80,
+			},
+			valid: false,
+		},
+		{
+			name: "zero warn percent",
+			cfg: &lncfg.Payments{
+				FeeBudgetWarnPercent: 0,
+			},
+			valid: false,
+		},
+		{
+			name: "warn percent above 100",
+			cfg: &lncfg.Payments{
+				FeeBudgetWarnPercent: 101,
+			},
+			valid: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := test.cfg.Validate()
+			switch {
+			case test.valid && err != nil:
+				t.Fatalf("valid config was invalid: %v", err)
+			case !test.valid && err == nil:
+				t.Fatalf("invalid config was valid")
+			}
+		})
+	}
+}
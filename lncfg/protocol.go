@@ -0,0 +1,21 @@
+package lncfg
+
+// Protocol holds the configuration of experimental protocol features, which
+// are only used with peers that signal support for them as well.
+type Protocol struct {
+	// Anchors enables the commitment format with anchor outputs.
+	Anchors bool `long:"anchors" description:"EXPERIMENTAL: Use the commitment format with anchor outputs, which allows the fee of force closing transactions to be bumped using CPFP, for new channels with peers that signal support for it. Such channels aren't compatible with the final specification of the format."`
+
+	// DualFunding allows peers to request us to contribute funds to the
+	// channels they open.
+	DualFunding bool `long:"dualfunding" description:"EXPERIMENTAL: Allow peers to request us to contribute funds to the channels they open. Each request must be accepted by a client of the DualFundAcceptor RPC, and is declined otherwise."`
+
+	// ZeroConf allows peers to open channels with us that are usable
+	// before their funding transaction confirms.
+	ZeroConf bool `long:"zeroconf" description:"EXPERIMENTAL: Accept requests of peers to open zero-conf channels, which are usable before their funding transaction confirms. Only enable this for peers that are trusted not to double spend the funding transaction."`
+
+	// MaxZeroConfExposure is the maximum total capacity of zero-conf
+	// channels, opened by us or our peers, whose funding transaction has
+	// yet to confirm.
+	MaxZeroConfExposure int64 `long:"maxzeroconfexposure" description:"The maximum total capacity (in satoshis) of zero-conf channels whose funding transaction has yet to confirm. Requests to open zero-conf channels beyond it are rejected."`
+}
@@ -0,0 +1,49 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+// Rebalance holds the configuration of the rebalancer, which shifts funds
+// between our channels through circular payments.
+type Rebalance struct {
+	// Active enables automatic rebalancing of channels that drift too far
+	// from their targets.
+	Active bool `long:"active" description:"Periodically rebalance channels whose local balance deviates from their target ratio by more than the tolerance. Rebalances can still be requested through the Rebalance RPC if unset."`
+
+	// Interval is the interval at which channels are checked against
+	// their targets.
+	Interval time.Duration `long:"interval" description:"The interval at which channels are checked against their target ratios."`
+
+	// MaxFeeRate is the maximum routing fee of a rebalance, in parts per
+	// million of the amount moved.
+	MaxFeeRate uint64 `long:"maxfeerate" description:"The maximum routing fee of a rebalance, in parts per million of the amount moved, unless the request specifies its own limit."`
+
+	// DailyFeeBudget is the maximum routing fee, in satoshis, that may be
+	// paid by all rebalances combined within any 24 hour window.
+	DailyFeeBudget uint64 `long:"dailyfeebudget" description:"The maximum routing fees in satoshis that may be paid by all rebalances combined within any 24 hour window. 0 leaves the fees unbounded."`
+
+	// Tolerance is the fraction of a channel's capacity its local balance
+	// may deviate from its target before it's rebalanced.
+	Tolerance float64 `long:"tolerance" description:"The fraction of a channel's capacity, between 0 and 1, that its local balance may deviate from its target ratio before it's rebalanced automatically."`
+}
+
+// Validate checks that the interval and tolerance of the Rebalance
+// configuration are within range.
+func (r *Rebalance) Validate() error {
+	if r.Interval <= 0 {
+		return fmt.Errorf("rebalance interval %v must be positive",
+			r.Interval)
+	}
+	if r.Tolerance < 0 || r.Tolerance > 1 {
+		return fmt.Errorf("rebalance tolerance %v must be between 0 "+
+			"and 1", r.Tolerance)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure Rebalance implements the Validator
+// interface.
+var _ Validator = (*Rebalance)(nil)
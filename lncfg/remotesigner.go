@@ -0,0 +1,63 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultRemoteSignerRPCTimeout is the default timeout used when
+	// connecting to and requesting signatures from the remote signer.
+	DefaultRemoteSignerRPCTimeout = 5 * time.Second
+)
+
+// RemoteSigner holds the configuration of the remote signer, an lnd instance
+// holding the keys of our channels, to which all derivation of and signing
+// with those keys is delegated.
+type RemoteSigner struct {
+	// Enable turns on the delegation of the keys of our channels to the
+	// remote signer.
+	Enable bool `long:"enable" description:"Derive the keys of channels, and sign with them, through a remote signer rather than the local wallet. The remote signer is an lnd instance with the signrpc and walletrpc sub-servers enabled, which must be used as the signer from the very first channel on."`
+
+	// RPCHost is the host:port of the RPC server of the remote signer.
+	RPCHost string `long:"rpchost" description:"The host:port of the RPC server of the remote signer."`
+
+	// MacaroonPath is the path of the macaroon used to authenticate with
+	// the remote signer.
+	MacaroonPath string `long:"macaroonpath" description:"The path of the macaroon to authenticate with the remote signer. It needs the permissions of the signer and address entities."`
+
+	// TLSCertPath is the path of the TLS certificate of the remote signer.
+	TLSCertPath string `long:"tlscertpath" description:"The path of the TLS certificate of the remote signer."`
+
+	// Timeout is the timeout of each request to the remote signer.
+	Timeout time.Duration `long:"timeout" description:"The timeout for connecting to the remote signer, and for each request made to it."`
+}
+
+// Validate checks that the connection details of the remote signer are set if
+// it's enabled.
+func (r *RemoteSigner) Validate() error {
+	if !r.Enable {
+		return nil
+	}
+
+	switch {
+	case r.RPCHost == "":
+		return fmt.Errorf("remotesigner.rpchost must be set")
+
+	case r.MacaroonPath == "":
+		return fmt.Errorf("remotesigner.macaroonpath must be set")
+
+	case r.TLSCertPath == "":
+		return fmt.Errorf("remotesigner.tlscertpath must be set")
+
+	case r.Timeout <= 0:
+		return fmt.Errorf("remotesigner.timeout %v must be positive",
+			r.Timeout)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure RemoteSigner implements the Validator
+// interface.
+var _ Validator = (*RemoteSigner)(nil)
@@ -0,0 +1,37 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+// Sweeper holds the configuration of the sweeper, which batches the on-chain
+// outputs we're entitled to into sweep transactions.
+type Sweeper struct {
+	// BatchWindowDuration is the time the sweeper waits for further inputs
+	// to be offered before it sweeps the pending ones.
+	BatchWindowDuration time.Duration `long:"batchwindowduration" description:"The time the sweeper waits for further inputs once an input is ready to be swept, so that inputs offered around the same time end up in a single sweep transaction."`
+
+	// FeeRateBucketSize is the size, in sat/vbyte, of the fee rate buckets
+	// that pending inputs are grouped into.
+	FeeRateBucketSize int `long:"feeratebucketsize" description:"The size in sat/vbyte of the fee rate buckets inputs are grouped into. Inputs whose fee rates fall into the same bucket are swept together at the highest of their fee rates."`
+}
+
+// Validate checks that the batch window duration and fee rate bucket size of
+// the Sweeper configuration are positive.
+func (s *Sweeper) Validate() error {
+	if s.BatchWindowDuration <= 0 {
+		return fmt.Errorf("sweeper batch window duration %v must be "+
+			"positive", s.BatchWindowDuration)
+	}
+	if s.FeeRateBucketSize <= 0 {
+		return fmt.Errorf("sweeper fee rate bucket size %v must be "+
+			"positive", s.FeeRateBucketSize)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure Sweeper implements the Validator
+// interface.
+var _ Validator = (*Sweeper)(nil)
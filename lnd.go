@@ -43,6 +43,8 @@ import (
 	"github.com/litecoinfinance/lnd/autopilot"
 	"github.com/litecoinfinance/lnd/build"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+	"github.com/litecoinfinance/lnd/dbbackup"
 	"github.com/litecoinfinance/lnd/keychain"
 	"github.com/litecoinfinance/lnd/lncfg"
 	"github.com/litecoinfinance/lnd/lnrpc"
@@ -160,19 +162,30 @@ func Main() error {
 		defaultGraphSubDirname,
 		normalizeNetwork(activeNetParams.Name))
 
+	// If requested, we'll restore the channeldb from its most recent
+	// backup before opening it.
+	if cfg.DBBackup.Restore {
+		if err := restoreChannelDB(graphDir); err != nil {
+			ltndLog.Errorf("unable to restore channeldb: %v", err)
+			return err
+		}
+	}
+
 	// Open the channeldb, which is dedicated to storing channel, and
 	// network related metadata.
-	chanDB, err := channeldb.Open(
-		graphDir,
-		channeldb.OptionSetRejectCacheSize(cfg.Caches.RejectCacheSize),
-		channeldb.OptionSetChannelCacheSize(cfg.Caches.ChannelCacheSize),
-	)
+	chanDB, err := openChannelDB(graphDir)
 	if err != nil {
 		ltndLog.Errorf("unable to open channeldb: %v", err)
 		return err
 	}
 	defer chanDB.Close()
 
+	// If requested, we'll only check the integrity of the channeldb,
+	// rather than starting the daemon on top of it.
+	if cfg.CheckDB || cfg.RepairDB {
+		return checkChannelDB(chanDB, cfg.RepairDB)
+	}
+
 	// Only process macaroons if --no-macaroons isn't set.
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
@@ -295,14 +308,12 @@ func Main() error {
 	primaryChain := registeredChains.PrimaryChain()
 	registeredChains.RegisterChain(primaryChain, activeChainControl)
 
-	// TODO(roasbeef): add rotation
-	idPrivKey, err := activeChainControl.wallet.DerivePrivKey(keychain.KeyDescriptor{
-		KeyLocator: keychain.KeyLocator{
-			Family: keychain.KeyFamilyNodeKey,
-			Index:  0,
-		},
-	})
+	// Before deriving our identity key, we'll activate any node key
+	// rotation requested during the last run, so that the new key is used
+	// from here on.
+	idPrivKey, err := deriveNodeKey(chanDB, activeChainControl.keyRing)
 	if err != nil {
+		ltndLog.Errorf("unable to derive node key: %v", err)
 		return err
 	}
 	idPrivKey.Curve = btcec.S256()
@@ -424,6 +435,190 @@ func Main() error {
 	return nil
 }
 
+// openChannelDB opens the channeldb within the backend selected by the
+// configuration. Regardless of the backend, the auxiliary files of the daemon
+// are stored within graphDir.
+func openChannelDB(graphDir string) (*channeldb.DB, error) {
+	dbOptions := []channeldb.OptionModifier{
+		channeldb.OptionSetRejectCacheSize(cfg.Caches.RejectCacheSize),
+		channeldb.OptionSetChannelCacheSize(cfg.Caches.ChannelCacheSize),
+		channeldb.OptionSetBatchCommitInterval(cfg.BatchCommitInterval),
+		channeldb.OptionSetAutoCompactRatio(cfg.DB.AutoCompactRatio),
+	}
+
+	if cfg.DB.Backend != lncfg.PostgresBackend {
+		return channeldb.Open(graphDir, dbOptions...)
+	}
+
+	if err := os.MkdirAll(graphDir, 0700); err != nil {
+		return nil, err
+	}
+
+	ltndLog.Infof("Opening channeldb within Postgres table %v",
+		cfg.DB.PostgresTable)
+
+	backend, err := kvdb.OpenPostgresBackend(
+		cfg.DB.PostgresDSN, cfg.DB.PostgresTable,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to postgres: %v", err)
+	}
+
+	return channeldb.OpenWithBackend(graphDir, backend, dbOptions...)
+}
+
+// restoreChannelDB restores channel.db within graphDir from the most recent
+// snapshot within the configured backup directory. As an existing database is
+// never overwritten, it must have been moved away beforehand.
+func restoreChannelDB(graphDir string) error {
+	key, err := dbbackup.LoadKey(cfg.DBBackup.KeyFile)
+	if err != nil {
+		return err
+	}
+	store, err := dbbackup.NewStore(cfg.DBBackup.Dir, key)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := store.LatestSnapshot()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(graphDir, 0700); err != nil {
+		return err
+	}
+
+	dbPath := filepath.Join(graphDir, "channel.db")
+	if err := dbbackup.Restore(store, manifest, dbPath); err != nil {
+		return err
+	}
+
+	ltndLog.Warnf("Restored channel.db from snapshot taken at %v. Any "+
+		"channel updates since then are lost, so channels should "+
+		"be closed by the remote party once reconnected",
+		manifest.Timestamp)
+
+	return nil
+}
+
+// checkChannelDB scans the channeldb for inconsistencies, logging each one
+// found. An error is returned if any of them remain after the scan, which is
+// always the case unless repair is set.
+func checkChannelDB(chanDB *channeldb.DB, repair bool) error {
+	ltndLog.Infof("Checking integrity of channeldb (repair=%v)", repair)
+
+	issues, err := chanDB.CheckIntegrity(repair)
+	if err != nil {
+		return fmt.Errorf("unable to check channeldb integrity: %v",
+			err)
+	}
+
+	var numUnrepaired int
+	for _, issue := range issues {
+		switch {
+		case issue.Repaired:
+			ltndLog.Infof("Repaired %v: %v", issue.Type,
+				issue.Description)
+
+		case issue.Repairable:
+			numUnrepaired++
+			ltndLog.Warnf("Found %v, repairable with "+
+				"--repair-db: %v", issue.Type,
+				issue.Description)
+
+		default:
+			numUnrepaired++
+			ltndLog.Errorf("Found %v, not repairable: %v",
+				issue.Type, issue.Description)
+		}
+	}
+
+	if numUnrepaired > 0 {
+		return fmt.Errorf("%d of %d channeldb inconsistencies remain",
+			numUnrepaired, len(issues))
+	}
+
+	ltndLog.Infof("Channeldb integrity check complete, %d "+
+		"inconsistencies repaired", len(issues))
+
+	return nil
+}
+
+// deriveNodeKey derives the node identity key from the node key family index
+// stored within the database. If a node key rotation is pending, it is
+// activated first, unless channels were opened since it was requested, in
+// which case it is aborted as those channels are bound to the current key.
+func deriveNodeKey(chanDB *channeldb.DB,
+	keyRing keychain.SecretKeyRing) (*btcec.PrivateKey, error) {
+
+	rotation, err := chanDB.PendingNodeKeyRotation()
+	if err != nil {
+		return nil, err
+	}
+
+	if rotation != nil {
+		channels, err := chanDB.FetchAllChannels()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case len(channels) != 0:
+			ltndLog.Warnf("Aborting node key rotation to %x, %d "+
+				"channels were opened since it was requested",
+				rotation.NewPubKey.SerializeCompressed(),
+				len(channels))
+
+			_, err := chanDB.ResolveNodeKeyRotation(false)
+			if err != nil {
+				return nil, err
+			}
+
+		default:
+			newKeyDesc := keychain.KeyDescriptor{
+				KeyLocator: keychain.KeyLocator{
+					Family: keychain.KeyFamilyNodeKey,
+					Index:  rotation.NewKeyIndex,
+				},
+			}
+			newPrivKey, err := keyRing.DerivePrivKey(newKeyDesc)
+			if err != nil {
+				return nil, err
+			}
+			newPubKey := newPrivKey.PubKey()
+			if !newPubKey.IsEqual(rotation.NewPubKey) {
+				return nil, fmt.Errorf("derived node key %x "+
+					"doesn't match rotation target %x",
+					newPubKey.SerializeCompressed(),
+					rotation.NewPubKey.SerializeCompressed())
+			}
+
+			_, err = chanDB.ResolveNodeKeyRotation(true)
+			if err != nil {
+				return nil, err
+			}
+
+			ltndLog.Infof("Activated node key rotation, identity "+
+				"key changed from %x to %x",
+				rotation.OldPubKey.SerializeCompressed(),
+				rotation.NewPubKey.SerializeCompressed())
+		}
+	}
+
+	index, err := chanDB.NodeKeyIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyRing.DerivePrivKey(keychain.KeyDescriptor{
+		KeyLocator: keychain.KeyLocator{
+			Family: keychain.KeyFamilyNodeKey,
+			Index:  index,
+		},
+	})
+}
+
 // getTLSConfig returns a TLS configuration for the gRPC server and credentials
 // and a proxy destination for the REST reverse proxy.
 func getTLSConfig(cfg *config) (*tls.Config, *credentials.TransportCredentials,
@@ -46,4 +46,8 @@ type Peer interface {
 	// using the interface to cancel any processing in the event the backing
 	// implementation exits.
 	QuitSignal() <-chan struct{}
+
+	// RemoteLocalFeatures returns the local feature vector advertised by
+	// the remote peer within its init message.
+	RemoteLocalFeatures() *lnwire.FeatureVector
 }
@@ -51,6 +51,22 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/chainrpc.ChainQuery/GetBestBlock": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/chainrpc.ChainQuery/GetTransaction": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/chainrpc.ChainQuery/GetUtxo": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/chainrpc.ChainQuery/EstimateFee": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 	}
 
 	// DefaultChainNotifierMacFilename is the default name of the chain
@@ -138,8 +154,10 @@ func New(cfg *Config) (*Server, lnrpc.MacaroonPerms, error) {
 }
 
 // Compile-time checks to ensure that Server fully implements the
-// ChainNotifierServer gRPC service and lnrpc.SubServer interface.
+// ChainNotifierServer and ChainQueryServer gRPC services and lnrpc.SubServer
+// interface.
 var _ ChainNotifierServer = (*Server)(nil)
+var _ ChainQueryServer = (*Server)(nil)
 var _ lnrpc.SubServer = (*Server)(nil)
 
 // Start launches any helper goroutines required for the server to function.
@@ -183,6 +201,7 @@ func (s *Server) RegisterWithRootServer(grpcServer *grpc.Server) error {
 	// We make sure that we register it with the main gRPC server to ensure
 	// all our methods are routed properly.
 	RegisterChainNotifierServer(grpcServer, s)
+	RegisterChainQueryServer(grpcServer, s)
 
 	log.Debug("ChainNotifier RPC server successfully register with root " +
 		"gRPC server")
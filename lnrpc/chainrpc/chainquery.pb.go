@@ -0,0 +1,633 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: chainrpc/chainquery.proto
+
+package chainrpc
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type GetBestBlockRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBestBlockRequest) Reset()         { *m = GetBestBlockRequest{} }
+func (m *GetBestBlockRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBestBlockRequest) ProtoMessage()    {}
+func (*GetBestBlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chainquery_5fad09515a5ccbe3, []int{0}
+}
+func (m *GetBestBlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBestBlockRequest.Unmarshal(m, b)
+}
+func (m *GetBestBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBestBlockRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetBestBlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBestBlockRequest.Merge(dst, src)
+}
+func (m *GetBestBlockRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBestBlockRequest.Size(m)
+}
+func (m *GetBestBlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBestBlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBestBlockRequest proto.InternalMessageInfo
+
+type GetBestBlockResponse struct {
+	// The hash of the current tip of the best chain known to the backend.
+	BlockHash []byte `protobuf:"bytes,1,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	// The height of the current tip of the best chain known to the backend.
+	BlockHeight          uint32   `protobuf:"varint,2,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBestBlockResponse) Reset()         { *m = GetBestBlockResponse{} }
+func (m *GetBestBlockResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBestBlockResponse) ProtoMessage()    {}
+func (*GetBestBlockResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chainquery_5fad09515a5ccbe3, []int{1}
+}
+func (m *GetBestBlockResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBestBlockResponse.Unmarshal(m, b)
+}
+func (m *GetBestBlockResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBestBlockResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetBestBlockResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBestBlockResponse.Merge(dst, src)
+}
+func (m *GetBestBlockResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBestBlockResponse.Size(m)
+}
+func (m *GetBestBlockResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBestBlockResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBestBlockResponse proto.InternalMessageInfo
+
+func (m *GetBestBlockResponse) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *GetBestBlockResponse) GetBlockHeight() uint32 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+type GetTransactionRequest struct {
+	// The hash of the transaction to look up.
+	Txid                 []byte   `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTransactionRequest) Reset()         { *m = GetTransactionRequest{} }
+func (m *GetTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionRequest) ProtoMessage()    {}
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chainquery_5fad09515a5ccbe3, []int{2}
+}
+func (m *GetTransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionRequest.Unmarshal(m, b)
+}
+func (m *GetTransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetTransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionRequest.Merge(dst, src)
+}
+func (m *GetTransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionRequest.Size(m)
+}
+func (m *GetTransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionRequest proto.InternalMessageInfo
+
+func (m *GetTransactionRequest) GetTxid() []byte {
+	if m != nil {
+		return m.Txid
+	}
+	return nil
+}
+
+type GetTransactionResponse struct {
+	// The raw bytes of the transaction.
+	RawTx                []byte   `protobuf:"bytes,1,opt,name=raw_tx,json=rawTx,proto3" json:"raw_tx,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTransactionResponse) Reset()         { *m = GetTransactionResponse{} }
+func (m *GetTransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionResponse) ProtoMessage()    {}
+func (*GetTransactionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chainquery_5fad09515a5ccbe3, []int{3}
+}
+func (m *GetTransactionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionResponse.Unmarshal(m, b)
+}
+func (m *GetTransactionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetTransactionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionResponse.Merge(dst, src)
+}
+func (m *GetTransactionResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionResponse.Size(m)
+}
+func (m *GetTransactionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionResponse proto.InternalMessageInfo
+
+func (m *GetTransactionResponse) GetRawTx() []byte {
+	if m != nil {
+		return m.RawTx
+	}
+	return nil
+}
+
+type GetUtxoRequest struct {
+	// The hash of the transaction that created the output.
+	Txid []byte `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	// The index of the output within the transaction.
+	OutputIndex uint32 `protobuf:"varint,2,opt,name=output_index,json=outputIndex,proto3" json:"output_index,omitempty"`
+	//
+	// The output script of the output. This is only required when lnd is backed
+	// by a light client, which needs it to match block filters.
+	PkScript []byte `protobuf:"bytes,3,opt,name=pk_script,json=pkScript,proto3" json:"pk_script,omitempty"`
+	//
+	// The earliest height in the chain at which the output could have been
+	// created. This is only used when lnd is backed by a light client, in order
+	// to limit the number of blocks scanned.
+	HeightHint           uint32   `protobuf:"varint,4,opt,name=height_hint,json=heightHint,proto3" json:"height_hint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetUtxoRequest) Reset()         { *m = GetUtxoRequest{} }
+func (m *GetUtxoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetUtxoRequest) ProtoMessage()    {}
+func (*GetUtxoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chainquery_5fad09515a5ccbe3, []int{4}
+}
+func (m *GetUtxoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUtxoRequest.Unmarshal(m, b)
+}
+func (m *GetUtxoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUtxoRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetUtxoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUtxoRequest.Merge(dst, src)
+}
+func (m *GetUtxoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetUtxoRequest.Size(m)
+}
+func (m *GetUtxoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUtxoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUtxoRequest proto.InternalMessageInfo
+
+func (m *GetUtxoRequest) GetTxid() []byte {
+	if m != nil {
+		return m.Txid
+	}
+	return nil
+}
+
+func (m *GetUtxoRequest) GetOutputIndex() uint32 {
+	if m != nil {
+		return m.OutputIndex
+	}
+	return 0
+}
+
+func (m *GetUtxoRequest) GetPkScript() []byte {
+	if m != nil {
+		return m.PkScript
+	}
+	return nil
+}
+
+func (m *GetUtxoRequest) GetHeightHint() uint32 {
+	if m != nil {
+		return m.HeightHint
+	}
+	return 0
+}
+
+type GetUtxoResponse struct {
+	// The value of the output in litoshis.
+	Value int64 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	// The output script of the output.
+	PkScript             []byte   `protobuf:"bytes,2,opt,name=pk_script,json=pkScript,proto3" json:"pk_script,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetUtxoResponse) Reset()         { *m = GetUtxoResponse{} }
+func (m *GetUtxoResponse) String() string { return proto.CompactTextString(m) }
+func (*GetUtxoResponse) ProtoMessage()    {}
+func (*GetUtxoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chainquery_5fad09515a5ccbe3, []int{5}
+}
+func (m *GetUtxoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUtxoResponse.Unmarshal(m, b)
+}
+func (m *GetUtxoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUtxoResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetUtxoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUtxoResponse.Merge(dst, src)
+}
+func (m *GetUtxoResponse) XXX_Size() int {
+	return xxx_messageInfo_GetUtxoResponse.Size(m)
+}
+func (m *GetUtxoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUtxoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUtxoResponse proto.InternalMessageInfo
+
+func (m *GetUtxoResponse) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *GetUtxoResponse) GetPkScript() []byte {
+	if m != nil {
+		return m.PkScript
+	}
+	return nil
+}
+
+type FeeEstimateRequest struct {
+	//
+	// The number of blocks within which the transaction should confirm. Must be
+	// greater than 1.
+	ConfTarget           uint32   `protobuf:"varint,1,opt,name=conf_target,json=confTarget,proto3" json:"conf_target,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeeEstimateRequest) Reset()         { *m = FeeEstimateRequest{} }
+func (m *FeeEstimateRequest) String() string { return proto.CompactTextString(m) }
+func (*FeeEstimateRequest) ProtoMessage()    {}
+func (*FeeEstimateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chainquery_5fad09515a5ccbe3, []int{6}
+}
+func (m *FeeEstimateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeeEstimateRequest.Unmarshal(m, b)
+}
+func (m *FeeEstimateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeeEstimateRequest.Marshal(b, m, deterministic)
+}
+func (dst *FeeEstimateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeeEstimateRequest.Merge(dst, src)
+}
+func (m *FeeEstimateRequest) XXX_Size() int {
+	return xxx_messageInfo_FeeEstimateRequest.Size(m)
+}
+func (m *FeeEstimateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeeEstimateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeeEstimateRequest proto.InternalMessageInfo
+
+func (m *FeeEstimateRequest) GetConfTarget() uint32 {
+	if m != nil {
+		return m.ConfTarget
+	}
+	return 0
+}
+
+type FeeEstimateResponse struct {
+	// The estimated fee rate in litoshis per kiloweight.
+	SatPerKw int64 `protobuf:"varint,1,opt,name=sat_per_kw,json=satPerKw,proto3" json:"sat_per_kw,omitempty"`
+	// The minimum fee rate in litoshis per kiloweight accepted for relay.
+	MinRelaySatPerKw     int64    `protobuf:"varint,2,opt,name=min_relay_sat_per_kw,json=minRelaySatPerKw,proto3" json:"min_relay_sat_per_kw,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeeEstimateResponse) Reset()         { *m = FeeEstimateResponse{} }
+func (m *FeeEstimateResponse) String() string { return proto.CompactTextString(m) }
+func (*FeeEstimateResponse) ProtoMessage()    {}
+func (*FeeEstimateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chainquery_5fad09515a5ccbe3, []int{7}
+}
+func (m *FeeEstimateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeeEstimateResponse.Unmarshal(m, b)
+}
+func (m *FeeEstimateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeeEstimateResponse.Marshal(b, m, deterministic)
+}
+func (dst *FeeEstimateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeeEstimateResponse.Merge(dst, src)
+}
+func (m *FeeEstimateResponse) XXX_Size() int {
+	return xxx_messageInfo_FeeEstimateResponse.Size(m)
+}
+func (m *FeeEstimateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeeEstimateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeeEstimateResponse proto.InternalMessageInfo
+
+func (m *FeeEstimateResponse) GetSatPerKw() int64 {
+	if m != nil {
+		return m.SatPerKw
+	}
+	return 0
+}
+
+func (m *FeeEstimateResponse) GetMinRelaySatPerKw() int64 {
+	if m != nil {
+		return m.MinRelaySatPerKw
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*GetBestBlockRequest)(nil), "chainrpc.GetBestBlockRequest")
+	proto.RegisterType((*GetBestBlockResponse)(nil), "chainrpc.GetBestBlockResponse")
+	proto.RegisterType((*GetTransactionRequest)(nil), "chainrpc.GetTransactionRequest")
+	proto.RegisterType((*GetTransactionResponse)(nil), "chainrpc.GetTransactionResponse")
+	proto.RegisterType((*GetUtxoRequest)(nil), "chainrpc.GetUtxoRequest")
+	proto.RegisterType((*GetUtxoResponse)(nil), "chainrpc.GetUtxoResponse")
+	proto.RegisterType((*FeeEstimateRequest)(nil), "chainrpc.FeeEstimateRequest")
+	proto.RegisterType((*FeeEstimateResponse)(nil), "chainrpc.FeeEstimateResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ChainQueryClient is the client API for ChainQuery service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ChainQueryClient interface {
+	//
+	// GetBestBlock returns the hash and height of the current tip of the best
+	// chain known to the chain backend.
+	GetBestBlock(ctx context.Context, in *GetBestBlockRequest, opts ...grpc.CallOption) (*GetBestBlockResponse, error)
+	//
+	// GetTransaction returns the raw transaction with the given hash. This is
+	// not supported when lnd is backed by a light client.
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error)
+	//
+	// GetUtxo returns the output referenced by the given outpoint if it's still
+	// unspent. An error is returned if the output has already been spent or
+	// can't be found.
+	GetUtxo(ctx context.Context, in *GetUtxoRequest, opts ...grpc.CallOption) (*GetUtxoResponse, error)
+	//
+	// EstimateFee returns the fee rate estimated by the chain backend for a
+	// transaction to confirm within the given number of blocks.
+	EstimateFee(ctx context.Context, in *FeeEstimateRequest, opts ...grpc.CallOption) (*FeeEstimateResponse, error)
+}
+
+type chainQueryClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChainQueryClient(cc *grpc.ClientConn) ChainQueryClient {
+	return &chainQueryClient{cc}
+}
+
+func (c *chainQueryClient) GetBestBlock(ctx context.Context, in *GetBestBlockRequest, opts ...grpc.CallOption) (*GetBestBlockResponse, error) {
+	out := new(GetBestBlockResponse)
+	err := c.cc.Invoke(ctx, "/chainrpc.ChainQuery/GetBestBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainQueryClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error) {
+	out := new(GetTransactionResponse)
+	err := c.cc.Invoke(ctx, "/chainrpc.ChainQuery/GetTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainQueryClient) GetUtxo(ctx context.Context, in *GetUtxoRequest, opts ...grpc.CallOption) (*GetUtxoResponse, error) {
+	out := new(GetUtxoResponse)
+	err := c.cc.Invoke(ctx, "/chainrpc.ChainQuery/GetUtxo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainQueryClient) EstimateFee(ctx context.Context, in *FeeEstimateRequest, opts ...grpc.CallOption) (*FeeEstimateResponse, error) {
+	out := new(FeeEstimateResponse)
+	err := c.cc.Invoke(ctx, "/chainrpc.ChainQuery/EstimateFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChainQueryServer is the server API for ChainQuery service.
+type ChainQueryServer interface {
+	//
+	// GetBestBlock returns the hash and height of the current tip of the best
+	// chain known to the chain backend.
+	GetBestBlock(context.Context, *GetBestBlockRequest) (*GetBestBlockResponse, error)
+	//
+	// GetTransaction returns the raw transaction with the given hash. This is
+	// not supported when lnd is backed by a light client.
+	GetTransaction(context.Context, *GetTransactionRequest) (*GetTransactionResponse, error)
+	//
+	// GetUtxo returns the output referenced by the given outpoint if it's still
+	// unspent. An error is returned if the output has already been spent or
+	// can't be found.
+	GetUtxo(context.Context, *GetUtxoRequest) (*GetUtxoResponse, error)
+	//
+	// EstimateFee returns the fee rate estimated by the chain backend for a
+	// transaction to confirm within the given number of blocks.
+	EstimateFee(context.Context, *FeeEstimateRequest) (*FeeEstimateResponse, error)
+}
+
+func RegisterChainQueryServer(s *grpc.Server, srv ChainQueryServer) {
+	s.RegisterService(&_ChainQuery_serviceDesc, srv)
+}
+
+func _ChainQuery_GetBestBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBestBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainQueryServer).GetBestBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chainrpc.ChainQuery/GetBestBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainQueryServer).GetBestBlock(ctx, req.(*GetBestBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainQuery_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainQueryServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chainrpc.ChainQuery/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainQueryServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainQuery_GetUtxo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUtxoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainQueryServer).GetUtxo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chainrpc.ChainQuery/GetUtxo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainQueryServer).GetUtxo(ctx, req.(*GetUtxoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainQuery_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeEstimateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainQueryServer).EstimateFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chainrpc.ChainQuery/EstimateFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainQueryServer).EstimateFee(ctx, req.(*FeeEstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ChainQuery_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "chainrpc.ChainQuery",
+	HandlerType: (*ChainQueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBestBlock",
+			Handler:    _ChainQuery_GetBestBlock_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _ChainQuery_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetUtxo",
+			Handler:    _ChainQuery_GetUtxo_Handler,
+		},
+		{
+			MethodName: "EstimateFee",
+			Handler:    _ChainQuery_EstimateFee_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "chainrpc/chainquery.proto",
+}
+
+func init() {
+	proto.RegisterFile("chainrpc/chainquery.proto", fileDescriptor_chainquery_5fad09515a5ccbe3)
+}
+
+var fileDescriptor_chainquery_5fad09515a5ccbe3 = []byte{
+	// 459 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x53, 0x5d, 0x6f, 0xd3, 0x30,
+	0x14, 0x55, 0xbb, 0x0f, 0xba, 0xdb, 0x6e, 0x20, 0xaf, 0x45, 0x59, 0x59, 0x69, 0xc9, 0xd3, 0x24,
+	0xa4, 0x4e, 0x02, 0xf1, 0xca, 0xc3, 0x80, 0x75, 0x80, 0x90, 0x20, 0x2d, 0x12, 0x6f, 0x96, 0x97,
+	0x5d, 0x16, 0xab, 0xad, 0xe3, 0xd9, 0x37, 0x34, 0xfb, 0x01, 0xfc, 0x2b, 0x7e, 0x1c, 0x8a, 0xe3,
+	0x40, 0xc3, 0x56, 0xde, 0xec, 0x73, 0xce, 0xbd, 0xf7, 0x38, 0xf7, 0x04, 0x8e, 0xe2, 0x44, 0x48,
+	0x65, 0x74, 0x7c, 0xea, 0x0e, 0x37, 0x19, 0x9a, 0xdb, 0xb1, 0x36, 0x29, 0xa5, 0xac, 0x55, 0x51,
+	0x61, 0x0f, 0x0e, 0x27, 0x48, 0x67, 0x68, 0xe9, 0x6c, 0x91, 0xc6, 0xf3, 0x08, 0x6f, 0x32, 0xb4,
+	0x14, 0x7e, 0x83, 0x6e, 0x1d, 0xb6, 0x3a, 0x55, 0x16, 0xd9, 0x00, 0xe0, 0xb2, 0x00, 0x78, 0x22,
+	0x6c, 0x12, 0x34, 0x46, 0x8d, 0x93, 0x4e, 0xb4, 0xe7, 0x90, 0x0b, 0x61, 0x13, 0xf6, 0x0c, 0x3a,
+	0x9e, 0x46, 0x79, 0x9d, 0x50, 0xd0, 0x1c, 0x35, 0x4e, 0xf6, 0xa3, 0x76, 0x29, 0x70, 0x50, 0xf8,
+	0x1c, 0x7a, 0x13, 0xa4, 0x99, 0x11, 0xca, 0x8a, 0x98, 0x64, 0xaa, 0xfc, 0x48, 0xc6, 0x60, 0x9b,
+	0x72, 0x79, 0xe5, 0x9b, 0xba, 0x73, 0x78, 0x0a, 0x8f, 0xff, 0x15, 0x7b, 0x23, 0x3d, 0xd8, 0x35,
+	0x62, 0xc5, 0x29, 0xf7, 0xfa, 0x1d, 0x23, 0x56, 0xb3, 0x3c, 0xfc, 0xd9, 0x80, 0x83, 0x09, 0xd2,
+	0x57, 0xca, 0xd3, 0xff, 0xf4, 0x2d, 0x7c, 0xa6, 0x19, 0xe9, 0x8c, 0xb8, 0x54, 0x57, 0x98, 0x57,
+	0x3e, 0x4b, 0xec, 0x7d, 0x01, 0xb1, 0x27, 0xb0, 0xa7, 0xe7, 0xdc, 0xc6, 0x46, 0x6a, 0x0a, 0xb6,
+	0x5c, 0x6d, 0x4b, 0xcf, 0xa7, 0xee, 0xce, 0x86, 0xd0, 0x2e, 0x5f, 0xc8, 0x13, 0xa9, 0x28, 0xd8,
+	0x76, 0xe5, 0x50, 0x42, 0x17, 0x52, 0x51, 0xf8, 0x16, 0x1e, 0xfe, 0xb1, 0xe1, 0x1d, 0x77, 0x61,
+	0xe7, 0x87, 0x58, 0x64, 0xe8, 0x8c, 0x6c, 0x45, 0xe5, 0xa5, 0x3e, 0xa6, 0x59, 0x1f, 0x13, 0xbe,
+	0x02, 0x76, 0x8e, 0xf8, 0xce, 0x92, 0x5c, 0x0a, 0xc2, 0xea, 0x41, 0x43, 0x68, 0xc7, 0xa9, 0xfa,
+	0xce, 0x49, 0x98, 0x6b, 0x24, 0xd7, 0x6e, 0x3f, 0x82, 0x02, 0x9a, 0x39, 0x24, 0x8c, 0xe1, 0xb0,
+	0x56, 0xe6, 0x0d, 0x1c, 0x03, 0x58, 0x41, 0x5c, 0xa3, 0xe1, 0xf3, 0x95, 0x77, 0xd1, 0xb2, 0x82,
+	0x3e, 0xa3, 0xf9, 0xb8, 0x62, 0x63, 0xe8, 0x2e, 0xa5, 0xe2, 0x06, 0x17, 0xe2, 0x96, 0xaf, 0xe9,
+	0x9a, 0x4e, 0xf7, 0x68, 0x29, 0x55, 0x54, 0x50, 0x53, 0xaf, 0x7f, 0xf1, 0xab, 0x09, 0xf0, 0xa6,
+	0x48, 0xd1, 0x97, 0x22, 0x57, 0xec, 0x13, 0x74, 0xd6, 0x03, 0xc3, 0x06, 0xe3, 0x2a, 0x62, 0xe3,
+	0x7b, 0xf2, 0xd5, 0x7f, 0xba, 0x89, 0xf6, 0x5e, 0xa7, 0x70, 0x50, 0x5f, 0x3c, 0x1b, 0xd6, 0x2a,
+	0xee, 0xe6, 0xa7, 0x3f, 0xda, 0x2c, 0xf0, 0x4d, 0x5f, 0xc3, 0x03, 0xbf, 0x14, 0x16, 0xd4, 0xc4,
+	0x6b, 0x71, 0xe9, 0x1f, 0xdd, 0xc3, 0xf8, 0xfa, 0x0f, 0xd0, 0xae, 0x3e, 0xea, 0x39, 0x22, 0x3b,
+	0xfe, 0xab, 0xbc, 0xbb, 0xa5, 0xfe, 0x60, 0x03, 0x5b, 0xf6, 0xba, 0xdc, 0x75, 0x3f, 0xe2, 0xcb,
+	0xdf, 0x03, 0x00, 0xac, 0x8a, 0x0d, 0xd8, 0xa5, 0x03, 0x00, 0x00,
+}
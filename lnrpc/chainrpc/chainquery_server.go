@@ -0,0 +1,127 @@
+// +build chainrpc
+
+package chainrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+)
+
+var (
+	// ErrTxLookupUnsupported is returned by GetTransaction if the chain
+	// backend is unable to look up arbitrary transactions.
+	ErrTxLookupUnsupported = errors.New("transaction lookup is not " +
+		"supported by the chain backend")
+)
+
+// txFetcher is implemented by chain backends that are able to look up
+// arbitrary transactions by their hash.
+type txFetcher interface {
+	// GetRawTransaction returns the transaction with the given hash.
+	GetRawTransaction(txid *chainhash.Hash) (*wire.MsgTx, error)
+}
+
+// GetBestBlock returns the hash and height of the current tip of the best
+// chain known to the chain backend.
+//
+// NOTE: This is part of the chainrpc.ChainQueryServer interface.
+func (s *Server) GetBestBlock(ctx context.Context,
+	in *GetBestBlockRequest) (*GetBestBlockResponse, error) {
+
+	blockHash, blockHeight, err := s.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetBestBlockResponse{
+		BlockHash:   blockHash[:],
+		BlockHeight: uint32(blockHeight),
+	}, nil
+}
+
+// GetTransaction returns the raw transaction with the given hash.
+//
+// NOTE: This is part of the chainrpc.ChainQueryServer interface.
+func (s *Server) GetTransaction(ctx context.Context,
+	in *GetTransactionRequest) (*GetTransactionResponse, error) {
+
+	txid, err := chainhash.NewHash(in.Txid)
+	if err != nil {
+		return nil, err
+	}
+
+	fetcher, ok := s.cfg.ChainIO.(txFetcher)
+	if !ok {
+		return nil, ErrTxLookupUnsupported
+	}
+
+	tx, err := fetcher.GetRawTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawTx bytes.Buffer
+	if err := tx.Serialize(&rawTx); err != nil {
+		return nil, err
+	}
+
+	return &GetTransactionResponse{
+		RawTx: rawTx.Bytes(),
+	}, nil
+}
+
+// GetUtxo returns the output referenced by the given outpoint if it's still
+// unspent.
+//
+// NOTE: This is part of the chainrpc.ChainQueryServer interface.
+func (s *Server) GetUtxo(ctx context.Context,
+	in *GetUtxoRequest) (*GetUtxoResponse, error) {
+
+	txid, err := chainhash.NewHash(in.Txid)
+	if err != nil {
+		return nil, err
+	}
+	outpoint := wire.NewOutPoint(txid, in.OutputIndex)
+
+	txOut, err := s.cfg.ChainIO.GetUtxo(
+		outpoint, in.PkScript, in.HeightHint,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetUtxoResponse{
+		Value:    txOut.Value,
+		PkScript: txOut.PkScript,
+	}, nil
+}
+
+// EstimateFee returns the fee rate estimated by the chain backend for a
+// transaction to confirm within the given number of blocks.
+//
+// NOTE: This is part of the chainrpc.ChainQueryServer interface.
+func (s *Server) EstimateFee(ctx context.Context,
+	in *FeeEstimateRequest) (*FeeEstimateResponse, error) {
+
+	// Similar to the WalletKit, we reject confirmation targets of 1 as
+	// they're unreasonable.
+	if in.ConfTarget <= 1 {
+		return nil, fmt.Errorf("confirmation target must be greater " +
+			"than 1")
+	}
+
+	satPerKw, err := s.cfg.FeeEstimator.EstimateFeePerKW(in.ConfTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FeeEstimateResponse{
+		SatPerKw:         int64(satPerKw),
+		MinRelaySatPerKw: int64(s.cfg.FeeEstimator.RelayFeePerKW()),
+	}, nil
+}
@@ -4,6 +4,7 @@ package chainrpc
 
 import (
 	"github.com/litecoinfinance/lnd/chainntnfs"
+	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/macaroons"
 )
 
@@ -31,4 +32,12 @@ type Config struct {
 	// notifier RPC server. The job of the chain notifier RPC server is
 	// simply to proxy valid requests to the active chain notifier instance.
 	ChainNotifier chainntnfs.ChainNotifier
+
+	// ChainIO is the chain backend that the ChainQuery service proxies its
+	// block, transaction and UTXO queries to.
+	ChainIO lnwallet.BlockChainIO
+
+	// FeeEstimator is the fee estimator backing the ChainQuery service's
+	// fee estimates.
+	FeeEstimator lnwallet.FeeEstimator
 }
@@ -47,6 +47,12 @@ func createNewSubServer(configRegistry lnrpc.SubServerConfigDispatcher) (
 	case config.ChainNotifier == nil:
 		return nil, nil, fmt.Errorf("ChainNotifier must be set to " +
 			"create chainrpc")
+	case config.ChainIO == nil:
+		return nil, nil, fmt.Errorf("ChainIO must be set to create " +
+			"chainrpc")
+	case config.FeeEstimator == nil:
+		return nil, nil, fmt.Errorf("FeeEstimator must be set to " +
+			"create chainrpc")
 	}
 
 	return New(config)
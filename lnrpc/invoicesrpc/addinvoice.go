@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/litecoinfinance/btcd/chaincfg"
@@ -91,6 +92,10 @@ type AddInvoiceData struct {
 	// Whether this invoice should include routing hints for private
 	// channels.
 	Private bool
+
+	// An optional ISO 4217 code of the fiat currency in which the invoice
+	// should be displayed and accounted for.
+	FiatCurrency string
 }
 
 // AddInvoice attempts to add a new invoice to the invoice database. Any
@@ -163,6 +168,13 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 			len(invoice.DescriptionHash), channeldb.MaxPaymentRequestSize)
 	}
 
+	// If specified, the fiat currency must be a valid ISO 4217 code.
+	fiatCurrency := strings.ToUpper(invoice.FiatCurrency)
+	if fiatCurrency != "" && !isCurrencyCode(fiatCurrency) {
+		return nil, nil, fmt.Errorf("invalid fiat currency %q, must "+
+			"be an ISO 4217 code", invoice.FiatCurrency)
+	}
+
 	// The value of the invoice must not be negative.
 	if invoice.Value < 0 {
 		return nil, nil, fmt.Errorf("payments of negative value "+
@@ -398,6 +410,7 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 			Value:           amtMSat,
 			PaymentPreimage: paymentPreimage,
 		},
+		FiatCurrency: fiatCurrency,
 	}
 
 	log.Tracef("[addinvoice] adding new invoice %v",
@@ -414,3 +427,19 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 
 	return &paymentHash, newInvoice, nil
 }
+
+// isCurrencyCode returns whether the given string has the format of an ISO
+// 4217 currency code, i.e. three upper case letters.
+func isCurrencyCode(currency string) bool {
+	if len(currency) != channeldb.FiatCurrencySize {
+		return false
+	}
+
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
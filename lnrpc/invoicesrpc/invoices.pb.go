@@ -37,7 +37,7 @@ func (m *CancelInvoiceMsg) Reset()         { *m = CancelInvoiceMsg{} }
 func (m *CancelInvoiceMsg) String() string { return proto.CompactTextString(m) }
 func (*CancelInvoiceMsg) ProtoMessage()    {}
 func (*CancelInvoiceMsg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_invoices_faecc7e411e82f9d, []int{0}
+	return fileDescriptor_invoices_4d2057a38612d231, []int{0}
 }
 func (m *CancelInvoiceMsg) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CancelInvoiceMsg.Unmarshal(m, b)
@@ -74,7 +74,7 @@ func (m *CancelInvoiceResp) Reset()         { *m = CancelInvoiceResp{} }
 func (m *CancelInvoiceResp) String() string { return proto.CompactTextString(m) }
 func (*CancelInvoiceResp) ProtoMessage()    {}
 func (*CancelInvoiceResp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_invoices_faecc7e411e82f9d, []int{1}
+	return fileDescriptor_invoices_4d2057a38612d231, []int{1}
 }
 func (m *CancelInvoiceResp) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CancelInvoiceResp.Unmarshal(m, b)
@@ -110,7 +110,10 @@ type AddHoldInvoiceRequest struct {
 	// payment (memo) is too long to naturally fit within the description field
 	// of an encoded payment request.
 	DescriptionHash []byte `protobuf:"bytes,4,opt,name=description_hash,proto3" json:"description_hash,omitempty"`
-	// / Payment request expiry time in seconds. Default is 3600 (1 hour).
+	// *
+	// Payment request expiry time in seconds. Default is 3600 (1 hour). Once
+	// expired, the invoice is canceled unless it has been settled, which
+	// cancels back any htlcs held for it.
 	Expiry int64 `protobuf:"varint,5,opt,name=expiry,proto3" json:"expiry,omitempty"`
 	// / Fallback on-chain address.
 	FallbackAddr string `protobuf:"bytes,6,opt,name=fallback_addr,proto3" json:"fallback_addr,omitempty"`
@@ -131,7 +134,7 @@ func (m *AddHoldInvoiceRequest) Reset()         { *m = AddHoldInvoiceRequest{} }
 func (m *AddHoldInvoiceRequest) String() string { return proto.CompactTextString(m) }
 func (*AddHoldInvoiceRequest) ProtoMessage()    {}
 func (*AddHoldInvoiceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_invoices_faecc7e411e82f9d, []int{2}
+	return fileDescriptor_invoices_4d2057a38612d231, []int{2}
 }
 func (m *AddHoldInvoiceRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AddHoldInvoiceRequest.Unmarshal(m, b)
@@ -229,7 +232,7 @@ func (m *AddHoldInvoiceResp) Reset()         { *m = AddHoldInvoiceResp{} }
 func (m *AddHoldInvoiceResp) String() string { return proto.CompactTextString(m) }
 func (*AddHoldInvoiceResp) ProtoMessage()    {}
 func (*AddHoldInvoiceResp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_invoices_faecc7e411e82f9d, []int{3}
+	return fileDescriptor_invoices_4d2057a38612d231, []int{3}
 }
 func (m *AddHoldInvoiceResp) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AddHoldInvoiceResp.Unmarshal(m, b)
@@ -268,7 +271,7 @@ func (m *SettleInvoiceMsg) Reset()         { *m = SettleInvoiceMsg{} }
 func (m *SettleInvoiceMsg) String() string { return proto.CompactTextString(m) }
 func (*SettleInvoiceMsg) ProtoMessage()    {}
 func (*SettleInvoiceMsg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_invoices_faecc7e411e82f9d, []int{4}
+	return fileDescriptor_invoices_4d2057a38612d231, []int{4}
 }
 func (m *SettleInvoiceMsg) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SettleInvoiceMsg.Unmarshal(m, b)
@@ -305,7 +308,7 @@ func (m *SettleInvoiceResp) Reset()         { *m = SettleInvoiceResp{} }
 func (m *SettleInvoiceResp) String() string { return proto.CompactTextString(m) }
 func (*SettleInvoiceResp) ProtoMessage()    {}
 func (*SettleInvoiceResp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_invoices_faecc7e411e82f9d, []int{5}
+	return fileDescriptor_invoices_4d2057a38612d231, []int{5}
 }
 func (m *SettleInvoiceResp) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SettleInvoiceResp.Unmarshal(m, b)
@@ -362,7 +365,9 @@ type InvoicesClient interface {
 	AddHoldInvoice(ctx context.Context, in *AddHoldInvoiceRequest, opts ...grpc.CallOption) (*AddHoldInvoiceResp, error)
 	// *
 	// SettleInvoice settles an accepted invoice. If the invoice is already
-	// settled, this call will succeed.
+	// settled, this call will succeed. An accepted invoice that isn't settled
+	// in time is canceled automatically once it expires, or shortly before its
+	// htlcs expire if that happens first.
 	SettleInvoice(ctx context.Context, in *SettleInvoiceMsg, opts ...grpc.CallOption) (*SettleInvoiceResp, error)
 }
 
@@ -451,7 +456,9 @@ type InvoicesServer interface {
 	AddHoldInvoice(context.Context, *AddHoldInvoiceRequest) (*AddHoldInvoiceResp, error)
 	// *
 	// SettleInvoice settles an accepted invoice. If the invoice is already
-	// settled, this call will succeed.
+	// settled, this call will succeed. An accepted invoice that isn't settled
+	// in time is canceled automatically once it expires, or shortly before its
+	// htlcs expire if that happens first.
 	SettleInvoice(context.Context, *SettleInvoiceMsg) (*SettleInvoiceResp, error)
 }
 
@@ -562,40 +569,40 @@ var _Invoices_serviceDesc = grpc.ServiceDesc{
 }
 
 func init() {
-	proto.RegisterFile("invoicesrpc/invoices.proto", fileDescriptor_invoices_faecc7e411e82f9d)
-}
-
-var fileDescriptor_invoices_faecc7e411e82f9d = []byte{
-	// 490 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0x4f, 0x8f, 0xd3, 0x3c,
-	0x10, 0xc6, 0x95, 0xb6, 0xdb, 0x6d, 0xa7, 0xbb, 0xfb, 0xf6, 0x35, 0xb0, 0x8a, 0x22, 0xfe, 0x84,
-	0x88, 0x43, 0xc4, 0x21, 0x81, 0xae, 0xb8, 0xae, 0x04, 0x5c, 0xca, 0x01, 0x84, 0x52, 0x71, 0xe1,
-	0x52, 0xb9, 0x89, 0x49, 0xac, 0x75, 0x6d, 0x63, 0xbb, 0x85, 0xfd, 0x54, 0x7c, 0x06, 0xbe, 0x19,
-	0x8a, 0xe3, 0x96, 0x24, 0x0b, 0xdc, 0x66, 0x1e, 0xcf, 0x3c, 0x19, 0xff, 0x3c, 0x81, 0x80, 0xf2,
-	0xbd, 0xa0, 0x39, 0xd1, 0x4a, 0xe6, 0xe9, 0x21, 0x4e, 0xa4, 0x12, 0x46, 0xa0, 0x59, 0xeb, 0x2c,
-	0x78, 0x58, 0x0a, 0x51, 0x32, 0x92, 0x62, 0x49, 0x53, 0xcc, 0xb9, 0x30, 0xd8, 0x50, 0xc1, 0x5d,
-	0x69, 0x30, 0x55, 0x32, 0x6f, 0xc2, 0xe8, 0x15, 0xcc, 0xdf, 0x62, 0x9e, 0x13, 0xf6, 0xae, 0xe9,
-	0x7e, 0xaf, 0x4b, 0xf4, 0x14, 0xce, 0x24, 0xbe, 0xdd, 0x12, 0x6e, 0xd6, 0x15, 0xd6, 0x95, 0xef,
-	0x85, 0x5e, 0x7c, 0x96, 0xcd, 0x9c, 0xb6, 0xc4, 0xba, 0x8a, 0xee, 0xc1, 0xff, 0x9d, 0xb6, 0x8c,
-	0x68, 0x19, 0xfd, 0x18, 0xc0, 0x83, 0xd7, 0x45, 0xb1, 0x14, 0xac, 0x38, 0xca, 0x5f, 0x77, 0x44,
-	0x1b, 0x84, 0x60, 0xb4, 0x25, 0x5b, 0x61, 0x9d, 0xa6, 0x99, 0x8d, 0x6b, 0xcd, 0xba, 0x0f, 0xac,
-	0xbb, 0x8d, 0xd1, 0x7d, 0x38, 0xd9, 0x63, 0xb6, 0x23, 0xfe, 0x30, 0xf4, 0xe2, 0x61, 0xd6, 0x24,
-	0xe8, 0x39, 0xcc, 0x0b, 0xa2, 0x73, 0x45, 0x65, 0x7d, 0x89, 0x66, 0xa6, 0x91, 0xed, 0xba, 0xa3,
-	0xa3, 0x4b, 0x18, 0x93, 0xef, 0x92, 0xaa, 0x5b, 0xff, 0xc4, 0x5a, 0xb8, 0x0c, 0x3d, 0x83, 0xf3,
-	0x2f, 0x98, 0xb1, 0x0d, 0xce, 0x6f, 0xd6, 0xb8, 0x28, 0x94, 0x3f, 0xb6, 0xa3, 0x74, 0x45, 0x14,
-	0xc2, 0x2c, 0x67, 0x66, 0xbf, 0x76, 0x16, 0xa7, 0xa1, 0x17, 0x8f, 0xb2, 0xb6, 0x84, 0x16, 0x30,
-	0x53, 0x62, 0x67, 0xc8, 0xba, 0xa2, 0xdc, 0x68, 0x7f, 0x12, 0x0e, 0xe3, 0xd9, 0x62, 0x9e, 0x30,
-	0x5e, 0x23, 0xcd, 0xea, 0x93, 0x25, 0xe5, 0x26, 0x6b, 0x17, 0x21, 0x1f, 0x4e, 0xa5, 0xa2, 0x7b,
-	0x6c, 0x88, 0x3f, 0x0d, 0xbd, 0x78, 0x92, 0x1d, 0xd2, 0xe8, 0x1a, 0x50, 0x1f, 0x98, 0x96, 0x28,
-	0x86, 0xff, 0x0e, 0xfc, 0x55, 0x03, 0xd0, 0x81, 0xeb, 0xcb, 0x51, 0x02, 0xf3, 0x15, 0x31, 0x86,
-	0x91, 0xd6, 0xeb, 0x05, 0x30, 0x91, 0x8a, 0xd0, 0x2d, 0x2e, 0x89, 0x7b, 0xb9, 0x63, 0x5e, 0x3f,
-	0x5b, 0xa7, 0xbe, 0xfe, 0xdc, 0xe2, 0xe7, 0x00, 0x26, 0x2e, 0xd7, 0xe8, 0x1a, 0x2e, 0x57, 0xbb,
-	0x4d, 0x0d, 0x75, 0x43, 0x56, 0x94, 0x97, 0xc7, 0x52, 0x84, 0xdc, 0x25, 0x3f, 0xfe, 0x5e, 0x83,
-	0xe0, 0xc2, 0x69, 0xae, 0xe6, 0x85, 0x87, 0x3e, 0xc0, 0x79, 0x67, 0x31, 0xd0, 0xa3, 0xa4, 0xb5,
-	0x97, 0x49, 0x7f, 0xd7, 0x82, 0xc7, 0x7f, 0x3f, 0xb6, 0x2c, 0x3e, 0xc1, 0x45, 0x97, 0x10, 0x8a,
-	0x3a, 0x1d, 0x7f, 0xdc, 0xb7, 0xe0, 0xc9, 0x3f, 0x6b, 0xb4, 0xac, 0xc7, 0xec, 0x80, 0xe8, 0x8d,
-	0xd9, 0x87, 0xda, 0x1b, 0xf3, 0x0e, 0xc3, 0x37, 0x57, 0x9f, 0x5f, 0x96, 0xd4, 0x54, 0xbb, 0x4d,
-	0x92, 0x8b, 0x6d, 0xca, 0x68, 0x59, 0x19, 0x4e, 0x79, 0xc9, 0x89, 0xf9, 0x26, 0xd4, 0x4d, 0xca,
-	0x78, 0x91, 0x5a, 0x52, 0x69, 0xcb, 0x66, 0x33, 0xb6, 0xbf, 0xe0, 0xd5, 0xaf, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x89, 0x60, 0x5e, 0x11, 0xd6, 0x03, 0x00, 0x00,
+	proto.RegisterFile("invoicesrpc/invoices.proto", fileDescriptor_invoices_4d2057a38612d231)
+}
+
+var fileDescriptor_invoices_4d2057a38612d231 = []byte{
+	// 485 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x53, 0xc1, 0x8e, 0xd3, 0x30,
+	0x10, 0x55, 0xda, 0x6e, 0xb7, 0x9d, 0xee, 0x2e, 0xc5, 0xc0, 0x2a, 0x8a, 0x60, 0x09, 0x11, 0x87,
+	0x88, 0x43, 0xb2, 0x2a, 0xe2, 0xba, 0x12, 0x70, 0x29, 0x07, 0x10, 0x4a, 0xc5, 0x85, 0x4b, 0xe5,
+	0x26, 0xde, 0xd4, 0xc2, 0xb5, 0x8d, 0xed, 0x56, 0xec, 0x57, 0xf1, 0x0d, 0xfc, 0x19, 0xb2, 0xeb,
+	0x96, 0x24, 0xb0, 0xdc, 0x66, 0xde, 0xbc, 0x79, 0x19, 0xbf, 0x99, 0x40, 0x44, 0xf9, 0x4e, 0xd0,
+	0x92, 0x68, 0x25, 0xcb, 0xfc, 0x10, 0x67, 0x52, 0x09, 0x23, 0xd0, 0xa4, 0x51, 0x8b, 0x9e, 0xd6,
+	0x42, 0xd4, 0x8c, 0xe4, 0x58, 0xd2, 0x1c, 0x73, 0x2e, 0x0c, 0x36, 0x54, 0x70, 0x4f, 0x8d, 0xc6,
+	0x4a, 0x96, 0xfb, 0x30, 0x79, 0x03, 0xd3, 0xf7, 0x98, 0x97, 0x84, 0x7d, 0xd8, 0x77, 0x7f, 0xd4,
+	0x35, 0x7a, 0x01, 0x67, 0x12, 0xdf, 0x6d, 0x08, 0x37, 0xcb, 0x35, 0xd6, 0xeb, 0x30, 0x88, 0x83,
+	0xf4, 0xac, 0x98, 0x78, 0x6c, 0x8e, 0xf5, 0x3a, 0x79, 0x04, 0x0f, 0x5b, 0x6d, 0x05, 0xd1, 0x32,
+	0xf9, 0xd9, 0x83, 0x27, 0x6f, 0xab, 0x6a, 0x2e, 0x58, 0x75, 0x84, 0xbf, 0x6f, 0x89, 0x36, 0x08,
+	0xc1, 0x60, 0x43, 0x36, 0xc2, 0x29, 0x8d, 0x0b, 0x17, 0x5b, 0xcc, 0xa9, 0xf7, 0x9c, 0xba, 0x8b,
+	0xd1, 0x63, 0x38, 0xd9, 0x61, 0xb6, 0x25, 0x61, 0x3f, 0x0e, 0xd2, 0x7e, 0xb1, 0x4f, 0xd0, 0x2b,
+	0x98, 0x56, 0x44, 0x97, 0x8a, 0x4a, 0xfb, 0x88, 0xfd, 0x4c, 0x03, 0xd7, 0xf5, 0x17, 0x8e, 0x2e,
+	0x61, 0x48, 0x7e, 0x48, 0xaa, 0xee, 0xc2, 0x13, 0x27, 0xe1, 0x33, 0xf4, 0x12, 0xce, 0x6f, 0x31,
+	0x63, 0x2b, 0x5c, 0x7e, 0x5b, 0xe2, 0xaa, 0x52, 0xe1, 0xd0, 0x8d, 0xd2, 0x06, 0x51, 0x0c, 0x93,
+	0x92, 0x99, 0xdd, 0xd2, 0x4b, 0x9c, 0xc6, 0x41, 0x3a, 0x28, 0x9a, 0x10, 0x9a, 0xc1, 0x44, 0x89,
+	0xad, 0x21, 0xcb, 0x35, 0xe5, 0x46, 0x87, 0xa3, 0xb8, 0x9f, 0x4e, 0x66, 0xd3, 0x8c, 0x71, 0x6b,
+	0x69, 0x61, 0x2b, 0x73, 0xca, 0x4d, 0xd1, 0x24, 0xa1, 0x10, 0x4e, 0xa5, 0xa2, 0x3b, 0x6c, 0x48,
+	0x38, 0x8e, 0x83, 0x74, 0x54, 0x1c, 0xd2, 0xe4, 0x06, 0x50, 0xd7, 0x30, 0x2d, 0x51, 0x0a, 0x0f,
+	0x0e, 0xfe, 0xab, 0xbd, 0x81, 0xde, 0xb8, 0x2e, 0x9c, 0x64, 0x30, 0x5d, 0x10, 0x63, 0x18, 0x69,
+	0x6c, 0x2f, 0x82, 0x91, 0x54, 0x84, 0x6e, 0x70, 0x4d, 0xfc, 0xe6, 0x8e, 0xb9, 0x5d, 0x5b, 0x8b,
+	0x6f, 0x3f, 0x37, 0xfb, 0xd5, 0x83, 0x91, 0xcf, 0x35, 0xba, 0x81, 0xcb, 0xc5, 0x76, 0x65, 0x4d,
+	0x5d, 0x91, 0x05, 0xe5, 0xf5, 0x91, 0x8a, 0x90, 0x7f, 0xe4, 0xe7, 0x3f, 0x67, 0x10, 0x5d, 0x78,
+	0xcc, 0x73, 0xae, 0x03, 0xf4, 0x09, 0xce, 0x5b, 0x87, 0x81, 0x9e, 0x65, 0x8d, 0xbb, 0xcc, 0xba,
+	0xb7, 0x16, 0x5d, 0xdd, 0x5f, 0x76, 0x5e, 0x7c, 0x81, 0x8b, 0xb6, 0x43, 0x28, 0x69, 0x75, 0xfc,
+	0xf3, 0xde, 0xa2, 0xe7, 0xff, 0xe5, 0x68, 0x69, 0xc7, 0x6c, 0x19, 0xd1, 0x19, 0xb3, 0x6b, 0x6a,
+	0x74, 0x75, 0x7f, 0xd9, 0xea, 0xbd, 0x9b, 0x7d, 0xbd, 0xae, 0xa9, 0x59, 0x6f, 0x57, 0x59, 0x29,
+	0x36, 0x39, 0xa3, 0x86, 0x94, 0x82, 0xf2, 0x5b, 0xca, 0xed, 0x83, 0x72, 0xc6, 0xab, 0x9c, 0xf1,
+	0xe6, 0x3f, 0xab, 0x64, 0xb9, 0x1a, 0xba, 0x3f, 0xf0, 0xf5, 0xef, 0x01, 0x00, 0xa9, 0x95, 0x44,
+	0x44, 0xd5, 0x03, 0x00, 0x00,
 }
@@ -3,10 +3,12 @@ package invoicesrpc
 import (
 	"encoding/hex"
 	"fmt"
+	"sort"
 
 	"github.com/litecoinfinance/btcd/chaincfg"
 	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/lnrpc"
+	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/zpay32"
 )
 
@@ -94,9 +96,102 @@ func CreateRPCInvoice(invoice *channeldb.Invoice,
 		rpcInvoice.RPreimage = preimage[:]
 	}
 
+	rpcInvoice.Htlcs, err = CreateRPCInvoiceHtlcs(invoice.Htlcs)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.FiatCurrency != "" {
+		rpcInvoice.FiatCurrency = invoice.FiatCurrency
+		rpcInvoice.CreationFiat = CreateRPCFiatSnapshot(
+			invoice.FiatCurrency, invoice.CreationFiatRate,
+			invoice.Terms.Value,
+		)
+		rpcInvoice.SettleFiat = CreateRPCFiatSnapshot(
+			invoice.FiatCurrency, invoice.SettleFiatRate,
+			invoice.AmtPaid,
+		)
+	}
+
 	return rpcInvoice, nil
 }
 
+// CreateRPCInvoiceHtlcs converts the htlcs of an invoice into the lnrpc type,
+// ordered by the time they were accepted.
+func CreateRPCInvoiceHtlcs(
+	htlcs map[channeldb.CircuitKey]*channeldb.InvoiceHTLC) (
+	[]*lnrpc.InvoiceHTLC, error) {
+
+	keys := make([]channeldb.CircuitKey, 0, len(htlcs))
+	for key := range htlcs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := htlcs[keys[i]].AcceptTime, htlcs[keys[j]].AcceptTime
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		if keys[i].ChanID != keys[j].ChanID {
+			return keys[i].ChanID.ToUint64() <
+				keys[j].ChanID.ToUint64()
+		}
+		return keys[i].HtlcID < keys[j].HtlcID
+	})
+
+	rpcHtlcs := make([]*lnrpc.InvoiceHTLC, 0, len(keys))
+	for _, key := range keys {
+		htlc := htlcs[key]
+
+		var state lnrpc.InvoiceHTLCState
+		switch htlc.State {
+		case channeldb.HtlcStateAccepted:
+			state = lnrpc.InvoiceHTLCState_ACCEPTED
+		case channeldb.HtlcStateSettled:
+			state = lnrpc.InvoiceHTLCState_SETTLED
+		case channeldb.HtlcStateCanceled:
+			state = lnrpc.InvoiceHTLCState_CANCELED
+		default:
+			return nil, fmt.Errorf("unknown htlc state %v",
+				htlc.State)
+		}
+
+		rpcHtlc := &lnrpc.InvoiceHTLC{
+			ChanId:       key.ChanID.ToUint64(),
+			HtlcIndex:    key.HtlcID,
+			AmtMsat:      uint64(htlc.Amt),
+			AcceptHeight: int32(htlc.AcceptHeight),
+			AcceptTime:   htlc.AcceptTime.Unix(),
+			ExpiryHeight: int32(htlc.Expiry),
+			State:        state,
+		}
+		if !htlc.ResolveTime.IsZero() {
+			rpcHtlc.ResolveTime = htlc.ResolveTime.Unix()
+		}
+
+		rpcHtlcs = append(rpcHtlcs, rpcHtlc)
+	}
+
+	return rpcHtlcs, nil
+}
+
+// CreateRPCFiatSnapshot converts the exchange rate of the given fiat currency
+// into the lnrpc type, valuing the given amount at that rate. If the rate is
+// nil, nil is returned.
+func CreateRPCFiatSnapshot(currency string, rate *channeldb.FiatRate,
+	amt lnwire.MilliSatoshi) *lnrpc.FiatSnapshot {
+
+	if rate == nil {
+		return nil
+	}
+
+	return &lnrpc.FiatSnapshot{
+		Currency:  currency,
+		Rate:      rate.Rate,
+		Amount:    rate.Amount(amt),
+		Timestamp: rate.Timestamp.Unix(),
+	}
+}
+
 // CreateRPCRouteHints takes in the decoded form of an invoice's route hints
 // and converts them into the lnrpc type.
 func CreateRPCRouteHints(routeHints [][]zpay32.HopHint) []*lnrpc.RouteHint {
@@ -6,6 +6,7 @@ package routerrpc
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import lnrpc "github.com/litecoinfinance/lnd/lnrpc"
 
 import (
 	context "golang.org/x/net/context"
@@ -33,23 +34,38 @@ type PaymentRequest struct {
 	// An absolute limit on the highest fee we should pay when looking for a route
 	// to the destination. Routes with fees higher than this will be ignored, if
 	// there are no routes with a fee below this amount, an error will be
-	// returned.
+	// returned. If the payment is split into shards, the limit applies to the
+	// fees of all shards combined.
 	FeeLimitSat int64 `protobuf:"varint,2,opt,name=fee_limit_sat,json=feeLimitSat,proto3" json:"fee_limit_sat,omitempty"`
 	// *
 	// An absolute limit on the cumulative CLTV value along the route for this
 	// payment. Routes with total CLTV values higher than this will be ignored,
 	// if there are no routes with a CLTV value below this amount, an error will
-	// be returned.
+	// be returned. The limit applies to the route of every retry and shard.
 	CltvLimit int32 `protobuf:"varint,3,opt,name=cltv_limit,json=cltvLimit,proto3" json:"cltv_limit,omitempty"`
 	// *
 	// An upper limit on the amount of time we should spend when attempting to
 	// fulfill the payment. This is expressed in seconds. If we cannot make a
 	// successful payment within this time frame, an error will be returned.
+	// Once the timeout expires, no further retries or shards are dispatched,
+	// though the attempts already in flight are awaited.
 	TimeoutSeconds int32 `protobuf:"varint,4,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
 	// *
 	// The channel id of the channel that must be taken to the first hop. If zero,
 	// any channel may be used.
-	OutgoingChannelId    int64    `protobuf:"varint,5,opt,name=outgoing_channel_id,json=outgoingChannelId,proto3" json:"outgoing_channel_id,omitempty"`
+	OutgoingChannelId int64 `protobuf:"varint,5,opt,name=outgoing_channel_id,json=outgoingChannelId,proto3" json:"outgoing_channel_id,omitempty"`
+	// *
+	// A limit on the highest fee we should pay, expressed in parts per million
+	// of the payment amount. If fee_limit_sat is set as well, the lower of both
+	// limits applies. Like fee_limit_sat, the limit applies to the fees of all
+	// shards combined.
+	FeeLimitPpm int64 `protobuf:"varint,6,opt,name=fee_limit_ppm,json=feeLimitPpm,proto3" json:"fee_limit_ppm,omitempty"`
+	// *
+	// The maximum number of shards the payment may be split into if no single
+	// route is able to carry the full amount. The payee must accept payments
+	// that are split into several HTLCs. If zero or one, the payment is sent
+	// along a single route.
+	MaxShards            uint32   `protobuf:"varint,7,opt,name=max_shards,json=maxShards,proto3" json:"max_shards,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -59,7 +75,7 @@ func (m *PaymentRequest) Reset()         { *m = PaymentRequest{} }
 func (m *PaymentRequest) String() string { return proto.CompactTextString(m) }
 func (*PaymentRequest) ProtoMessage()    {}
 func (*PaymentRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_router_e218e7c710fe8172, []int{0}
+	return fileDescriptor_router_1f71d2ca65402735, []int{0}
 }
 func (m *PaymentRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PaymentRequest.Unmarshal(m, b)
@@ -114,6 +130,68 @@ func (m *PaymentRequest) GetOutgoingChannelId() int64 {
 	return 0
 }
 
+func (m *PaymentRequest) GetFeeLimitPpm() int64 {
+	if m != nil {
+		return m.FeeLimitPpm
+	}
+	return 0
+}
+
+func (m *PaymentRequest) GetMaxShards() uint32 {
+	if m != nil {
+		return m.MaxShards
+	}
+	return 0
+}
+
+type FailedAttempt struct {
+	// / The route along which the payment, or one of its shards, was attempted.
+	Route *lnrpc.Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	// / A string representation of the reason the attempt failed.
+	Failure              string   `protobuf:"bytes,2,opt,name=failure,proto3" json:"failure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FailedAttempt) Reset()         { *m = FailedAttempt{} }
+func (m *FailedAttempt) String() string { return proto.CompactTextString(m) }
+func (*FailedAttempt) ProtoMessage()    {}
+func (*FailedAttempt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_router_1f71d2ca65402735, []int{1}
+}
+func (m *FailedAttempt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FailedAttempt.Unmarshal(m, b)
+}
+func (m *FailedAttempt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FailedAttempt.Marshal(b, m, deterministic)
+}
+func (dst *FailedAttempt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FailedAttempt.Merge(dst, src)
+}
+func (m *FailedAttempt) XXX_Size() int {
+	return xxx_messageInfo_FailedAttempt.Size(m)
+}
+func (m *FailedAttempt) XXX_DiscardUnknown() {
+	xxx_messageInfo_FailedAttempt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FailedAttempt proto.InternalMessageInfo
+
+func (m *FailedAttempt) GetRoute() *lnrpc.Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+func (m *FailedAttempt) GetFailure() string {
+	if m != nil {
+		return m.Failure
+	}
+	return ""
+}
+
 type PaymentResponse struct {
 	// *
 	// The payment hash that we paid to. Provided so callers are able to map
@@ -124,17 +202,21 @@ type PaymentResponse struct {
 	PreImage []byte `protobuf:"bytes,2,opt,name=pre_image,json=preImage,proto3" json:"pre_image,omitempty"`
 	// *
 	// If not an empty string, then a string representation of the payment error.
-	PaymentErr           string   `protobuf:"bytes,3,opt,name=payment_err,json=paymentErr,proto3" json:"payment_err,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	PaymentErr string `protobuf:"bytes,3,opt,name=payment_err,json=paymentErr,proto3" json:"payment_err,omitempty"`
+	// *
+	// The routes along which the payment was attempted without success, in the
+	// order in which they failed, along with the reason of each failure.
+	FailedAttempts       []*FailedAttempt `protobuf:"bytes,4,rep,name=failed_attempts,json=failedAttempts,proto3" json:"failed_attempts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *PaymentResponse) Reset()         { *m = PaymentResponse{} }
 func (m *PaymentResponse) String() string { return proto.CompactTextString(m) }
 func (*PaymentResponse) ProtoMessage()    {}
 func (*PaymentResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_router_e218e7c710fe8172, []int{1}
+	return fileDescriptor_router_1f71d2ca65402735, []int{2}
 }
 func (m *PaymentResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PaymentResponse.Unmarshal(m, b)
@@ -175,6 +257,13 @@ func (m *PaymentResponse) GetPaymentErr() string {
 	return ""
 }
 
+func (m *PaymentResponse) GetFailedAttempts() []*FailedAttempt {
+	if m != nil {
+		return m.FailedAttempts
+	}
+	return nil
+}
+
 type RouteFeeRequest struct {
 	// *
 	// The destination once wishes to obtain a routing fee quote to.
@@ -191,7 +280,7 @@ func (m *RouteFeeRequest) Reset()         { *m = RouteFeeRequest{} }
 func (m *RouteFeeRequest) String() string { return proto.CompactTextString(m) }
 func (*RouteFeeRequest) ProtoMessage()    {}
 func (*RouteFeeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_router_e218e7c710fe8172, []int{2}
+	return fileDescriptor_router_1f71d2ca65402735, []int{3}
 }
 func (m *RouteFeeRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RouteFeeRequest.Unmarshal(m, b)
@@ -244,7 +333,7 @@ func (m *RouteFeeResponse) Reset()         { *m = RouteFeeResponse{} }
 func (m *RouteFeeResponse) String() string { return proto.CompactTextString(m) }
 func (*RouteFeeResponse) ProtoMessage()    {}
 func (*RouteFeeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_router_e218e7c710fe8172, []int{3}
+	return fileDescriptor_router_1f71d2ca65402735, []int{4}
 }
 func (m *RouteFeeResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RouteFeeResponse.Unmarshal(m, b)
@@ -278,11 +367,280 @@ func (m *RouteFeeResponse) GetTimeLockDelay() int64 {
 	return 0
 }
 
+type BuildRouteRequest struct {
+	// *
+	// The amount to deliver to the last hop, expressed in msat.
+	AmtMsat int64 `protobuf:"varint,1,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
+	// *
+	// CLTV delta from the current height that should be used for the timelock
+	// of the final hop. If zero, the default final CLTV delta is used.
+	FinalCltvDelta int32 `protobuf:"varint,2,opt,name=final_cltv_delta,json=finalCltvDelta,proto3" json:"final_cltv_delta,omitempty"`
+	// *
+	// The channel id of the channel that must be taken to the first hop. If zero,
+	// any channel may be used.
+	OutgoingChanId uint64 `protobuf:"varint,3,opt,name=outgoing_chan_id,json=outgoingChanId,proto3" json:"outgoing_chan_id,omitempty"`
+	// *
+	// A list of hops that defines the route. This does not include the source
+	// hop pubkey.
+	HopPubkeys           [][]byte `protobuf:"bytes,4,rep,name=hop_pubkeys,json=hopPubkeys,proto3" json:"hop_pubkeys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuildRouteRequest) Reset()         { *m = BuildRouteRequest{} }
+func (m *BuildRouteRequest) String() string { return proto.CompactTextString(m) }
+func (*BuildRouteRequest) ProtoMessage()    {}
+func (*BuildRouteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_router_1f71d2ca65402735, []int{5}
+}
+func (m *BuildRouteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildRouteRequest.Unmarshal(m, b)
+}
+func (m *BuildRouteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildRouteRequest.Marshal(b, m, deterministic)
+}
+func (dst *BuildRouteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildRouteRequest.Merge(dst, src)
+}
+func (m *BuildRouteRequest) XXX_Size() int {
+	return xxx_messageInfo_BuildRouteRequest.Size(m)
+}
+func (m *BuildRouteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildRouteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildRouteRequest proto.InternalMessageInfo
+
+func (m *BuildRouteRequest) GetAmtMsat() int64 {
+	if m != nil {
+		return m.AmtMsat
+	}
+	return 0
+}
+
+func (m *BuildRouteRequest) GetFinalCltvDelta() int32 {
+	if m != nil {
+		return m.FinalCltvDelta
+	}
+	return 0
+}
+
+func (m *BuildRouteRequest) GetOutgoingChanId() uint64 {
+	if m != nil {
+		return m.OutgoingChanId
+	}
+	return 0
+}
+
+func (m *BuildRouteRequest) GetHopPubkeys() [][]byte {
+	if m != nil {
+		return m.HopPubkeys
+	}
+	return nil
+}
+
+type BuildRouteResponse struct {
+	// *
+	// Fully specified route that can be used to execute the payment.
+	Route                *lnrpc.Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BuildRouteResponse) Reset()         { *m = BuildRouteResponse{} }
+func (m *BuildRouteResponse) String() string { return proto.CompactTextString(m) }
+func (*BuildRouteResponse) ProtoMessage()    {}
+func (*BuildRouteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_router_1f71d2ca65402735, []int{6}
+}
+func (m *BuildRouteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildRouteResponse.Unmarshal(m, b)
+}
+func (m *BuildRouteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildRouteResponse.Marshal(b, m, deterministic)
+}
+func (dst *BuildRouteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildRouteResponse.Merge(dst, src)
+}
+func (m *BuildRouteResponse) XXX_Size() int {
+	return xxx_messageInfo_BuildRouteResponse.Size(m)
+}
+func (m *BuildRouteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildRouteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildRouteResponse proto.InternalMessageInfo
+
+func (m *BuildRouteResponse) GetRoute() *lnrpc.Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+type ProbeRequest struct {
+	// / The identity pubkey of the node to probe the route to.
+	Dest []byte `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
+	// / The amount to probe the route for, expressed in satoshis.
+	AmtSat int64 `protobuf:"varint,2,opt,name=amt_sat,json=amtSat,proto3" json:"amt_sat,omitempty"`
+	// *
+	// An absolute limit on the fees of the route that is probed. If zero, the
+	// amount is used as the limit.
+	FeeLimitSat int64 `protobuf:"varint,3,opt,name=fee_limit_sat,json=feeLimitSat,proto3" json:"fee_limit_sat,omitempty"`
+	// *
+	// The CLTV delta the destination requires for the final hop. If zero, the
+	// default final CLTV delta is used.
+	FinalCltvDelta int32 `protobuf:"varint,4,opt,name=final_cltv_delta,json=finalCltvDelta,proto3" json:"final_cltv_delta,omitempty"`
+	// *
+	// The channel id of the channel that must be taken to the first hop. If zero,
+	// any channel may be used.
+	OutgoingChanId       uint64   `protobuf:"varint,5,opt,name=outgoing_chan_id,json=outgoingChanId,proto3" json:"outgoing_chan_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProbeRequest) Reset()         { *m = ProbeRequest{} }
+func (m *ProbeRequest) String() string { return proto.CompactTextString(m) }
+func (*ProbeRequest) ProtoMessage()    {}
+func (*ProbeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_router_1f71d2ca65402735, []int{7}
+}
+func (m *ProbeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProbeRequest.Unmarshal(m, b)
+}
+func (m *ProbeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProbeRequest.Marshal(b, m, deterministic)
+}
+func (dst *ProbeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProbeRequest.Merge(dst, src)
+}
+func (m *ProbeRequest) XXX_Size() int {
+	return xxx_messageInfo_ProbeRequest.Size(m)
+}
+func (m *ProbeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProbeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProbeRequest proto.InternalMessageInfo
+
+func (m *ProbeRequest) GetDest() []byte {
+	if m != nil {
+		return m.Dest
+	}
+	return nil
+}
+
+func (m *ProbeRequest) GetAmtSat() int64 {
+	if m != nil {
+		return m.AmtSat
+	}
+	return 0
+}
+
+func (m *ProbeRequest) GetFeeLimitSat() int64 {
+	if m != nil {
+		return m.FeeLimitSat
+	}
+	return 0
+}
+
+func (m *ProbeRequest) GetFinalCltvDelta() int32 {
+	if m != nil {
+		return m.FinalCltvDelta
+	}
+	return 0
+}
+
+func (m *ProbeRequest) GetOutgoingChanId() uint64 {
+	if m != nil {
+		return m.OutgoingChanId
+	}
+	return 0
+}
+
+type ProbeResponse struct {
+	// *
+	// Whether the probe reached the destination, meaning that each channel along
+	// the route was able to carry the amount.
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// / The route along which the probe was sent.
+	Route *lnrpc.Route `protobuf:"bytes,2,opt,name=route,proto3" json:"route,omitempty"`
+	// *
+	// The identity pubkey of the node that failed the probe. If the probe
+	// succeeded, this is the destination.
+	FailureSourcePubkey []byte `protobuf:"bytes,3,opt,name=failure_source_pubkey,json=failureSourcePubkey,proto3" json:"failure_source_pubkey,omitempty"`
+	// / A string representation of the failure returned by the failure source.
+	Failure              string   `protobuf:"bytes,4,opt,name=failure,proto3" json:"failure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProbeResponse) Reset()         { *m = ProbeResponse{} }
+func (m *ProbeResponse) String() string { return proto.CompactTextString(m) }
+func (*ProbeResponse) ProtoMessage()    {}
+func (*ProbeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_router_1f71d2ca65402735, []int{8}
+}
+func (m *ProbeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProbeResponse.Unmarshal(m, b)
+}
+func (m *ProbeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProbeResponse.Marshal(b, m, deterministic)
+}
+func (dst *ProbeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProbeResponse.Merge(dst, src)
+}
+func (m *ProbeResponse) XXX_Size() int {
+	return xxx_messageInfo_ProbeResponse.Size(m)
+}
+func (m *ProbeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProbeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProbeResponse proto.InternalMessageInfo
+
+func (m *ProbeResponse) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *ProbeResponse) GetRoute() *lnrpc.Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+func (m *ProbeResponse) GetFailureSourcePubkey() []byte {
+	if m != nil {
+		return m.FailureSourcePubkey
+	}
+	return nil
+}
+
+func (m *ProbeResponse) GetFailure() string {
+	if m != nil {
+		return m.Failure
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*PaymentRequest)(nil), "routerrpc.PaymentRequest")
+	proto.RegisterType((*FailedAttempt)(nil), "routerrpc.FailedAttempt")
 	proto.RegisterType((*PaymentResponse)(nil), "routerrpc.PaymentResponse")
 	proto.RegisterType((*RouteFeeRequest)(nil), "routerrpc.RouteFeeRequest")
 	proto.RegisterType((*RouteFeeResponse)(nil), "routerrpc.RouteFeeResponse")
+	proto.RegisterType((*BuildRouteRequest)(nil), "routerrpc.BuildRouteRequest")
+	proto.RegisterType((*BuildRouteResponse)(nil), "routerrpc.BuildRouteResponse")
+	proto.RegisterType((*ProbeRequest)(nil), "routerrpc.ProbeRequest")
+	proto.RegisterType((*ProbeResponse)(nil), "routerrpc.ProbeResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -301,13 +659,28 @@ type RouterClient interface {
 	// SendPayment attempts to route a payment described by the passed
 	// PaymentRequest to the final destination. If we are unable to route the
 	// payment, or cannot find a route that satisfies the constraints in the
-	// PaymentRequest, then an error will be returned. Otherwise, the payment
-	// pre-image, along with the final route will be returned.
+	// PaymentRequest, then payment_err will be set. Otherwise, the payment
+	// pre-image will be returned. In either case, the routes that were attempted
+	// without success are returned along with the reason of each failure.
 	SendPayment(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*PaymentResponse, error)
 	// *
 	// EstimateRouteFee allows callers to obtain a lower bound w.r.t how much it
 	// may cost to send an HTLC to the target end destination.
 	EstimateRouteFee(ctx context.Context, in *RouteFeeRequest, opts ...grpc.CallOption) (*RouteFeeResponse, error)
+	// *
+	// BuildRoute builds a fully specified route based on a list of hop public
+	// keys. It retrieves the relevant channel policies from the graph in order
+	// to calculate the correct fees and time locks. The route can then be
+	// executed using SendToRoute.
+	BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error)
+	// *
+	// ProbePayment tests whether a payment of the given amount can be routed to
+	// the destination, without risking any funds. An HTLC carrying a random
+	// payment hash is sent along the route the payment would take, which is
+	// certain to be failed. If the destination fails it, the route has enough
+	// liquidity. Otherwise, the point of failure is returned and mission control
+	// is updated, such that a subsequent probe will take a different route.
+	ProbePayment(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (*ProbeResponse, error)
 }
 
 type routerClient struct {
@@ -336,19 +709,52 @@ func (c *routerClient) EstimateRouteFee(ctx context.Context, in *RouteFeeRequest
 	return out, nil
 }
 
+func (c *routerClient) BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error) {
+	out := new(BuildRouteResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/BuildRoute", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ProbePayment(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (*ProbeResponse, error) {
+	out := new(ProbeResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ProbePayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RouterServer is the server API for Router service.
 type RouterServer interface {
 	// *
 	// SendPayment attempts to route a payment described by the passed
 	// PaymentRequest to the final destination. If we are unable to route the
 	// payment, or cannot find a route that satisfies the constraints in the
-	// PaymentRequest, then an error will be returned. Otherwise, the payment
-	// pre-image, along with the final route will be returned.
+	// PaymentRequest, then payment_err will be set. Otherwise, the payment
+	// pre-image will be returned. In either case, the routes that were attempted
+	// without success are returned along with the reason of each failure.
 	SendPayment(context.Context, *PaymentRequest) (*PaymentResponse, error)
 	// *
 	// EstimateRouteFee allows callers to obtain a lower bound w.r.t how much it
 	// may cost to send an HTLC to the target end destination.
 	EstimateRouteFee(context.Context, *RouteFeeRequest) (*RouteFeeResponse, error)
+	// *
+	// BuildRoute builds a fully specified route based on a list of hop public
+	// keys. It retrieves the relevant channel policies from the graph in order
+	// to calculate the correct fees and time locks. The route can then be
+	// executed using SendToRoute.
+	BuildRoute(context.Context, *BuildRouteRequest) (*BuildRouteResponse, error)
+	// *
+	// ProbePayment tests whether a payment of the given amount can be routed to
+	// the destination, without risking any funds. An HTLC carrying a random
+	// payment hash is sent along the route the payment would take, which is
+	// certain to be failed. If the destination fails it, the route has enough
+	// liquidity. Otherwise, the point of failure is returned and mission control
+	// is updated, such that a subsequent probe will take a different route.
+	ProbePayment(context.Context, *ProbeRequest) (*ProbeResponse, error)
 }
 
 func RegisterRouterServer(s *grpc.Server, srv RouterServer) {
@@ -391,6 +797,42 @@ func _Router_EstimateRouteFee_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_BuildRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).BuildRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/BuildRoute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).BuildRoute(ctx, req.(*BuildRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ProbePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ProbePayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ProbePayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ProbePayment(ctx, req.(*ProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Router_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "routerrpc.Router",
 	HandlerType: (*RouterServer)(nil),
@@ -403,39 +845,67 @@ var _Router_serviceDesc = grpc.ServiceDesc{
 			MethodName: "EstimateRouteFee",
 			Handler:    _Router_EstimateRouteFee_Handler,
 		},
+		{
+			MethodName: "BuildRoute",
+			Handler:    _Router_BuildRoute_Handler,
+		},
+		{
+			MethodName: "ProbePayment",
+			Handler:    _Router_ProbePayment_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "routerrpc/router.proto",
 }
 
-func init() { proto.RegisterFile("routerrpc/router.proto", fileDescriptor_router_e218e7c710fe8172) }
-
-var fileDescriptor_router_e218e7c710fe8172 = []byte{
-	// 409 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xdf, 0x6e, 0xd3, 0x30,
-	0x14, 0xc6, 0x15, 0xb6, 0x85, 0xe5, 0x74, 0x6b, 0x8b, 0x91, 0x20, 0xeb, 0x84, 0xa8, 0x72, 0x01,
-	0xb9, 0x0a, 0x12, 0xdc, 0x73, 0xc3, 0x36, 0x31, 0x31, 0x24, 0xe4, 0x3e, 0x80, 0x65, 0x92, 0xb3,
-	0x26, 0x34, 0x8e, 0x5d, 0xdb, 0x41, 0xca, 0xb3, 0xf0, 0x3c, 0xbc, 0x17, 0xb2, 0xe3, 0x96, 0x82,
-	0x7a, 0xe7, 0x7c, 0xe7, 0xef, 0xf7, 0x3b, 0x81, 0x17, 0x5a, 0xf6, 0x16, 0xb5, 0x56, 0xe5, 0xbb,
-	0xf1, 0x55, 0x28, 0x2d, 0xad, 0x24, 0xc9, 0x5e, 0xcf, 0x7e, 0x47, 0x30, 0xfd, 0xc6, 0x07, 0x81,
-	0x9d, 0xa5, 0xb8, 0xed, 0xd1, 0x58, 0xf2, 0x12, 0x9e, 0x2a, 0x3e, 0x30, 0x8d, 0xdb, 0x34, 0x5a,
-	0x46, 0x79, 0x42, 0x63, 0xc5, 0x07, 0x8a, 0x5b, 0x92, 0xc1, 0xe5, 0x23, 0x22, 0x6b, 0x1b, 0xd1,
-	0x58, 0x66, 0xb8, 0x4d, 0x9f, 0x2c, 0xa3, 0xfc, 0x84, 0x4e, 0x1e, 0x11, 0x1f, 0x9c, 0xb6, 0xe2,
-	0x96, 0xbc, 0x02, 0x28, 0x5b, 0xfb, 0x73, 0x4c, 0x4a, 0x4f, 0x96, 0x51, 0x7e, 0x46, 0x13, 0xa7,
-	0xf8, 0x0c, 0xf2, 0x16, 0x66, 0xb6, 0x11, 0x28, 0x7b, 0xcb, 0x0c, 0x96, 0xb2, 0xab, 0x4c, 0x7a,
-	0xea, 0x73, 0xa6, 0x41, 0x5e, 0x8d, 0x2a, 0x29, 0xe0, 0xb9, 0xec, 0xed, 0x5a, 0x36, 0xdd, 0x9a,
-	0x95, 0x35, 0xef, 0x3a, 0x6c, 0x59, 0x53, 0xa5, 0x67, 0x7e, 0xe2, 0xb3, 0x5d, 0xe8, 0xd3, 0x18,
-	0xb9, 0xaf, 0xb2, 0x1f, 0x30, 0xdb, 0xdb, 0x30, 0x4a, 0x76, 0x06, 0xc9, 0x15, 0x9c, 0x3b, 0x1f,
-	0x35, 0x37, 0xb5, 0x37, 0x72, 0x41, 0x9d, 0xaf, 0xcf, 0xdc, 0xd4, 0xe4, 0x1a, 0x12, 0xa5, 0x91,
-	0x35, 0x82, 0xaf, 0xd1, 0xbb, 0xb8, 0xa0, 0xe7, 0x4a, 0xe3, 0xbd, 0xfb, 0x26, 0xaf, 0x61, 0xa2,
-	0xc6, 0x56, 0x0c, 0xb5, 0xf6, 0x1e, 0x12, 0x0a, 0x41, 0xba, 0xd5, 0x3a, 0xfb, 0x08, 0x33, 0xea,
-	0x00, 0xde, 0x21, 0xee, 0x98, 0x11, 0x38, 0xad, 0xd0, 0xd8, 0x30, 0xc7, 0xbf, 0x1d, 0x47, 0x2e,
-	0x0e, 0x41, 0xc5, 0x5c, 0x38, 0x46, 0x59, 0x05, 0xf3, 0xbf, 0xf5, 0x61, 0xd9, 0x1c, 0xe6, 0xee,
-	0x28, 0xce, 0xae, 0x63, 0x2c, 0x5c, 0x55, 0xe4, 0xab, 0xa6, 0x41, 0xbf, 0x43, 0xfc, 0x6a, 0xb8,
-	0x25, 0x6f, 0x46, 0x84, 0xac, 0x95, 0xe5, 0x86, 0x55, 0xd8, 0xf2, 0x21, 0xb4, 0xbf, 0x74, 0xf2,
-	0x83, 0x2c, 0x37, 0x37, 0x4e, 0x7c, 0xff, 0x2b, 0x82, 0xd8, 0x8f, 0xd1, 0xe4, 0x06, 0x26, 0x2b,
-	0xec, 0xaa, 0x00, 0x88, 0x5c, 0x15, 0xfb, 0xfb, 0x17, 0xff, 0xde, 0x7e, 0xb1, 0x38, 0x16, 0x0a,
-	0x2b, 0x7e, 0x81, 0xf9, 0xad, 0xb1, 0x8d, 0xe0, 0x16, 0x77, 0xeb, 0x93, 0xc3, 0xfc, 0xff, 0x98,
-	0x2c, 0xae, 0x8f, 0xc6, 0xc6, 0x66, 0xdf, 0x63, 0xff, 0x27, 0x7e, 0xf8, 0x13, 0x00, 0x00, 0xff,
-	0xff, 0x95, 0xc2, 0xf8, 0xec, 0xa3, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("routerrpc/router.proto", fileDescriptor_router_1f71d2ca65402735) }
+
+var fileDescriptor_router_1f71d2ca65402735 = []byte{
+	// 727 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xcb, 0x6e, 0xe3, 0x46,
+	0x10, 0x04, 0xf5, 0x66, 0xeb, 0xb9, 0xb3, 0x48, 0x96, 0xd6, 0x66, 0x11, 0x81, 0x87, 0x44, 0x27,
+	0x05, 0x50, 0x2e, 0x39, 0x05, 0x70, 0xd6, 0x6b, 0x44, 0xc8, 0x2e, 0x20, 0x8c, 0x3e, 0x80, 0x18,
+	0x93, 0x2d, 0x8b, 0x30, 0x1f, 0xe3, 0x99, 0xa1, 0x61, 0xfd, 0x41, 0xfe, 0x21, 0xc7, 0x9c, 0x72,
+	0xc8, 0x29, 0x3f, 0x18, 0xcc, 0x43, 0x36, 0x15, 0xc9, 0x80, 0x91, 0x9b, 0xa7, 0xba, 0x5d, 0xac,
+	0xae, 0xae, 0xb6, 0xe1, 0x6b, 0x51, 0x56, 0x0a, 0x85, 0xe0, 0xf1, 0x0f, 0xf6, 0xa7, 0x05, 0x17,
+	0xa5, 0x2a, 0x89, 0xff, 0x84, 0x4f, 0x7d, 0xc1, 0x63, 0x8b, 0x86, 0xbf, 0x37, 0x60, 0xb4, 0x66,
+	0xfb, 0x1c, 0x0b, 0x45, 0xf1, 0xbe, 0x42, 0xa9, 0xc8, 0x3b, 0xe8, 0x72, 0xb6, 0x8f, 0x04, 0xde,
+	0x07, 0xde, 0xcc, 0x9b, 0xfb, 0xb4, 0xc3, 0xd9, 0x9e, 0xe2, 0x3d, 0x09, 0x61, 0xb8, 0x45, 0x8c,
+	0xb2, 0x34, 0x4f, 0x55, 0x24, 0x99, 0x0a, 0x1a, 0x33, 0x6f, 0xde, 0xa4, 0xfd, 0x2d, 0xe2, 0x67,
+	0x8d, 0x6d, 0x98, 0x22, 0x1f, 0x00, 0xe2, 0x4c, 0x3d, 0xd8, 0xa6, 0xa0, 0x39, 0xf3, 0xe6, 0x6d,
+	0xea, 0x6b, 0xc4, 0x74, 0x90, 0xef, 0x61, 0xac, 0xd2, 0x1c, 0xcb, 0x4a, 0x45, 0x12, 0xe3, 0xb2,
+	0x48, 0x64, 0xd0, 0x32, 0x3d, 0x23, 0x07, 0x6f, 0x2c, 0x4a, 0x16, 0xf0, 0xb6, 0xac, 0xd4, 0x6d,
+	0x99, 0x16, 0xb7, 0x51, 0xbc, 0x63, 0x45, 0x81, 0x59, 0x94, 0x26, 0x41, 0xdb, 0x7c, 0xf1, 0xcd,
+	0xa1, 0xf4, 0xd1, 0x56, 0x56, 0xc9, 0xb1, 0x36, 0xce, 0xf3, 0xa0, 0x73, 0xac, 0x6d, 0xcd, 0x73,
+	0xad, 0x2d, 0x67, 0x8f, 0x91, 0xdc, 0x31, 0x91, 0xc8, 0xa0, 0x3b, 0xf3, 0xe6, 0x43, 0xea, 0xe7,
+	0xec, 0x71, 0x63, 0x80, 0xf0, 0x0b, 0x0c, 0xaf, 0x59, 0x9a, 0x61, 0x72, 0xa9, 0x14, 0xe6, 0x5c,
+	0x91, 0x10, 0xda, 0xc6, 0x33, 0x63, 0x43, 0x7f, 0x39, 0x58, 0x64, 0x85, 0x36, 0x8e, 0x6a, 0x8c,
+	0xda, 0x12, 0x09, 0xa0, 0xbb, 0x65, 0x69, 0x56, 0x09, 0x34, 0x6e, 0xf8, 0xf4, 0xf0, 0x0c, 0xff,
+	0xf6, 0x60, 0xfc, 0xe4, 0xac, 0xe4, 0x65, 0x21, 0x91, 0x5c, 0x40, 0x4f, 0x5b, 0xbb, 0x63, 0x72,
+	0x67, 0x48, 0x07, 0x54, 0x5b, 0xfd, 0x2b, 0x93, 0x3b, 0xf2, 0x1e, 0x7c, 0x2e, 0x30, 0x4a, 0x73,
+	0x76, 0x6b, 0xa9, 0x06, 0xb4, 0xc7, 0x05, 0xae, 0xf4, 0x9b, 0x7c, 0x0b, 0x7d, 0x6e, 0xa9, 0x22,
+	0x14, 0xc2, 0xd8, 0xea, 0x53, 0x70, 0xd0, 0x27, 0x21, 0xc8, 0x25, 0x8c, 0xb7, 0x46, 0x7b, 0xc4,
+	0xac, 0x78, 0xed, 0x6b, 0x73, 0xde, 0x5f, 0x06, 0x8b, 0xa7, 0xb5, 0x2f, 0x8e, 0xa6, 0xa3, 0xa3,
+	0x6d, 0xfd, 0x29, 0xc3, 0x9f, 0x61, 0x6c, 0x26, 0xbb, 0x46, 0x3c, 0x24, 0x81, 0x40, 0x2b, 0x41,
+	0xa9, 0x9c, 0xd4, 0x56, 0xe2, 0xd2, 0xc1, 0xf2, 0xfa, 0xfa, 0x3b, 0x2c, 0xd7, 0x9b, 0x0f, 0x13,
+	0x98, 0x3c, 0xff, 0xbe, 0x9b, 0x77, 0x0e, 0x13, 0xfd, 0x79, 0xbd, 0x44, 0xbd, 0x9d, 0x5c, 0x32,
+	0x4b, 0xd6, 0xa4, 0x23, 0x87, 0x5f, 0x23, 0x7e, 0x91, 0x4c, 0x91, 0xef, 0x6c, 0x30, 0xa2, 0xac,
+	0x8c, 0xef, 0xa2, 0x04, 0x33, 0xb6, 0x77, 0xf4, 0x43, 0x0d, 0x7f, 0x2e, 0xe3, 0xbb, 0x2b, 0x0d,
+	0x86, 0x7f, 0x7a, 0xf0, 0xe6, 0x97, 0x2a, 0xcd, 0x12, 0xbb, 0x05, 0x27, 0xf4, 0x02, 0x7a, 0x5a,
+	0x54, 0x8d, 0x5f, 0x8b, 0x34, 0xc4, 0x73, 0x98, 0x6c, 0xd3, 0x82, 0x65, 0x91, 0x89, 0x65, 0x82,
+	0x99, 0x62, 0x86, 0xb9, 0x4d, 0x47, 0x06, 0xff, 0x98, 0xa9, 0x87, 0x2b, 0x8d, 0xea, 0xce, 0xa3,
+	0xc8, 0xe9, 0xbc, 0x69, 0xa7, 0x5b, 0x74, 0x54, 0xcf, 0xdb, 0x2a, 0xd1, 0xeb, 0xd8, 0x95, 0x3c,
+	0xe2, 0xd5, 0xcd, 0x1d, 0xee, 0xad, 0xd3, 0x03, 0x0a, 0xbb, 0x92, 0xaf, 0x2d, 0x12, 0xfe, 0x04,
+	0xa4, 0x2e, 0xd2, 0xb9, 0xf1, 0x8a, 0x3c, 0x85, 0xff, 0x78, 0x30, 0x58, 0x8b, 0xf2, 0xe6, 0x7f,
+	0xed, 0xe0, 0xf4, 0x42, 0x9b, 0xa7, 0x17, 0x7a, 0xce, 0x90, 0xd6, 0xab, 0x0d, 0x69, 0x9f, 0x33,
+	0x24, 0xfc, 0xc3, 0x83, 0xa1, 0x53, 0xed, 0x66, 0x0d, 0xa0, 0x2b, 0xab, 0x38, 0x46, 0x29, 0x8d,
+	0xf2, 0x1e, 0x3d, 0x3c, 0x9f, 0x5d, 0x68, 0xbc, 0x7c, 0x55, 0x4b, 0xf8, 0xca, 0x9d, 0x51, 0x24,
+	0xcb, 0x4a, 0xc4, 0xe8, 0xbc, 0x36, 0xf3, 0x0c, 0xe8, 0x5b, 0x57, 0xdc, 0x98, 0x9a, 0x35, 0xbd,
+	0x7e, 0x89, 0xad, 0xa3, 0x4b, 0x5c, 0xfe, 0xd5, 0x80, 0x8e, 0xa1, 0x17, 0xe4, 0x0a, 0xfa, 0x1b,
+	0x2c, 0x12, 0x77, 0x97, 0xe4, 0xa2, 0x76, 0x1d, 0xc7, 0x7f, 0x05, 0xa7, 0xd3, 0x73, 0x25, 0x37,
+	0xdc, 0x6f, 0x30, 0xf9, 0x24, 0x55, 0x9a, 0x33, 0x85, 0x87, 0xc8, 0x93, 0x7a, 0xff, 0x7f, 0xee,
+	0x68, 0xfa, 0xfe, 0x6c, 0xcd, 0x91, 0xad, 0x00, 0x9e, 0xb3, 0x42, 0xbe, 0xa9, 0xb5, 0x9e, 0xe4,
+	0x7c, 0xfa, 0xe1, 0x85, 0xaa, 0xa3, 0xba, 0x74, 0xd9, 0x39, 0x8c, 0xf7, 0xae, 0x3e, 0x43, 0x2d,
+	0x54, 0xd3, 0xe0, 0xb4, 0x60, 0x29, 0x6e, 0x3a, 0xe6, 0xdf, 0xc2, 0x8f, 0xff, 0x0e, 0x00, 0x48,
+	0xd5, 0xc1, 0xff, 0x46, 0x06, 0x00, 0x00,
 }
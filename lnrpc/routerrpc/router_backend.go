@@ -5,14 +5,21 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/lnrpc"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/routing"
 	"github.com/litecoinfinance/lnd/routing/route"
+	"github.com/litecoinfinance/lnd/zpay32"
 	context "golang.org/x/net/context"
 )
 
+// defaultNumRejected is the number of rejected routes returned by QueryRoutes
+// in explain mode if not specified explicitly.
+const defaultNumRejected = 5
+
 // RouterBackend contains the backend implementation of the router rpc sub
 // server calls.
 type RouterBackend struct {
@@ -30,8 +37,19 @@ type RouterBackend struct {
 	// routes.
 	FindRoutes func(source, target route.Vertex,
 		amt lnwire.MilliSatoshi, restrictions *routing.RestrictParams,
-		numPaths uint32, finalExpiry ...uint16) (
-		[]*route.Route, error)
+		numPaths uint32,
+		routeHints map[route.Vertex][]*channeldb.ChannelEdgePolicy,
+		finalExpiry ...uint16) ([]*route.Route, error)
+
+	// ExplainRoutes is a closure that abstracts away how we locate routes,
+	// along with the most promising candidate routes that were rejected
+	// due to the passed restrictions.
+	ExplainRoutes func(source, target route.Vertex,
+		amt lnwire.MilliSatoshi, restrictions *routing.RestrictParams,
+		numPaths, numRejected uint32,
+		routeHints map[route.Vertex][]*channeldb.ChannelEdgePolicy,
+		finalExpiry ...uint16) ([]*route.Route,
+		[]*routing.RejectedRoute, error)
 }
 
 // QueryRoutes attempts to query the daemons' Channel Router for a possible
@@ -116,10 +134,45 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 		ignoredEdges[locator] = struct{}{}
 	}
 
+	// If no path finding weights are specified, those of the router's
+	// configuration are used.
+	pathWeights, err := UnmarshallPathWeights(in.PathWeights)
+	if err != nil {
+		return nil, err
+	}
+
 	restrictions := &routing.RestrictParams{
 		FeeLimit:     feeLimit,
 		IgnoredNodes: ignoredNodes,
 		IgnoredEdges: ignoredEdges,
+		Weights:      pathWeights,
+	}
+
+	// The CLTV limit used during path finding excludes the final CLTV
+	// delta, as it's independent of the path.
+	finalCltvDelta := uint16(zpay32.DefaultFinalCLTVDelta)
+	if in.FinalCltvDelta != 0 {
+		finalCltvDelta = uint16(in.FinalCltvDelta)
+	}
+	if in.CltvLimit != 0 {
+		if in.CltvLimit < uint32(finalCltvDelta) {
+			return nil, fmt.Errorf("cltv limit %v is below final "+
+				"cltv delta %v", in.CltvLimit, finalCltvDelta)
+		}
+
+		cltvLimit := in.CltvLimit - uint32(finalCltvDelta)
+		restrictions.CltvLimit = &cltvLimit
+	}
+
+	// Route hints allow the destination to be reached through private
+	// channels, in which case it may not be part of the graph at all.
+	hopHints, err := unmarshallRouteHints(in.RouteHints)
+	if err != nil {
+		return nil, err
+	}
+	routeHints, err := routing.RouteHintsToEdges(hopHints, targetPubKey)
+	if err != nil {
+		return nil, err
 	}
 
 	// numRoutes will default to 10 if not specified explicitly.
@@ -130,21 +183,28 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 
 	// Query the channel router for a possible path to the destination that
 	// can carry `in.Amt` satoshis _including_ the total fee required on
-	// the route.
+	// the route. If requested, we'll also obtain the candidate routes that
+	// were rejected due to our restrictions.
 	var (
-		routes  []*route.Route
-		findErr error
+		routes   []*route.Route
+		rejected []*routing.RejectedRoute
+		findErr  error
 	)
 
-	if in.FinalCltvDelta == 0 {
-		routes, findErr = r.FindRoutes(
+	if in.Explain {
+		numRejected := in.NumRejected
+		if numRejected == 0 {
+			numRejected = defaultNumRejected
+		}
+
+		routes, rejected, findErr = r.ExplainRoutes(
 			sourcePubKey, targetPubKey, amtMSat, restrictions,
-			numRoutesIn,
+			numRoutesIn, numRejected, routeHints, finalCltvDelta,
 		)
 	} else {
 		routes, findErr = r.FindRoutes(
 			sourcePubKey, targetPubKey, amtMSat, restrictions,
-			numRoutesIn, uint16(in.FinalCltvDelta),
+			numRoutesIn, routeHints, finalCltvDelta,
 		)
 	}
 	if findErr != nil {
@@ -165,15 +225,124 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 		Routes: make([]*lnrpc.Route, 0, in.NumRoutes),
 	}
 	for i := uint32(0); i < numRoutes; i++ {
-		routeResp.Routes = append(
-			routeResp.Routes,
-			r.MarshallRoute(routes[i]),
+		rpcRoute := r.MarshallRoute(routes[i])
+		rpcRoute.TotalTimeLockDelta = totalTimeLockDelta(
+			routes[i], finalCltvDelta,
+		)
+
+		routeResp.Routes = append(routeResp.Routes, rpcRoute)
+	}
+	for _, rejectedRoute := range rejected {
+		rpcRoute := r.MarshallRoute(rejectedRoute.Route)
+		rpcRoute.TotalTimeLockDelta = totalTimeLockDelta(
+			rejectedRoute.Route, finalCltvDelta,
+		)
+
+		routeResp.RejectedRoutes = append(
+			routeResp.RejectedRoutes, &lnrpc.RejectedRoute{
+				Route:  rpcRoute,
+				Reason: marshallRejectionReason(rejectedRoute.Reason),
+			},
 		)
 	}
 
 	return routeResp, nil
 }
 
+// totalTimeLockDelta returns the number of blocks for which the funds sent
+// along the route may be locked up, counted from the height at which the route
+// was found. As the time lock of the final hop exceeds that height by the final
+// CLTV delta, the height can be recovered from the route itself.
+func totalTimeLockDelta(rt *route.Route, finalCltvDelta uint16) uint32 {
+	finalHop := rt.Hops[len(rt.Hops)-1]
+	height := finalHop.OutgoingTimeLock - uint32(finalCltvDelta)
+
+	return rt.TotalTimeLock - height
+}
+
+// unmarshallRouteHints converts the route hints of an rpc request into their
+// zpay32 form.
+func unmarshallRouteHints(
+	rpcRouteHints []*lnrpc.RouteHint) ([][]zpay32.HopHint, error) {
+
+	routeHints := make([][]zpay32.HopHint, 0, len(rpcRouteHints))
+	for _, rpcRouteHint := range rpcRouteHints {
+		routeHint := make(
+			[]zpay32.HopHint, 0, len(rpcRouteHint.HopHints),
+		)
+		for _, rpcHint := range rpcRouteHint.HopHints {
+			pubBytes, err := hex.DecodeString(rpcHint.NodeId)
+			if err != nil {
+				return nil, err
+			}
+			nodeID, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+			if err != nil {
+				return nil, fmt.Errorf("invalid hop hint node "+
+					"id %v: %v", rpcHint.NodeId, err)
+			}
+
+			routeHint = append(routeHint, zpay32.HopHint{
+				NodeID:      nodeID,
+				ChannelID:   rpcHint.ChanId,
+				FeeBaseMSat: rpcHint.FeeBaseMsat,
+				FeeProportionalMillionths: rpcHint.
+					FeeProportionalMillionths,
+				CLTVExpiryDelta: uint16(rpcHint.CltvExpiryDelta),
+			})
+		}
+
+		routeHints = append(routeHints, routeHint)
+	}
+
+	return routeHints, nil
+}
+
+// UnmarshallPathWeights converts the path finding weights of an rpc request
+// into their routing form. If no weights are specified, nil is returned so
+// that the router's configured weights apply.
+func UnmarshallPathWeights(
+	rpcWeights *lnrpc.PathWeights) (*routing.PathWeights, error) {
+
+	if rpcWeights == nil {
+		return nil, nil
+	}
+
+	if rpcWeights.FeeWeight < 0 {
+		return nil, errors.New("fee weight must not be negative")
+	}
+	if rpcWeights.FeeWeight == 0 && rpcWeights.RiskFactor == 0 &&
+		rpcWeights.ProbabilityWeight == 0 &&
+		rpcWeights.HoldTimeWeight == 0 {
+
+		return nil, errors.New("at least one path weight must be " +
+			"non-zero")
+	}
+
+	return &routing.PathWeights{
+		FeeWeight:            rpcWeights.FeeWeight,
+		RiskFactorBillionths: rpcWeights.RiskFactor,
+		ProbabilityWeight:    rpcWeights.ProbabilityWeight,
+		HoldTimeWeight:       rpcWeights.HoldTimeWeight,
+	}, nil
+}
+
+// marshallRejectionReason translates the reason a route was rejected during
+// path finding to its rpc counterpart.
+func marshallRejectionReason(
+	reason routing.RouteRejection) lnrpc.RejectedRoute_RejectionReason {
+
+	switch reason {
+	case routing.RejectedIgnoredEdge:
+		return lnrpc.RejectedRoute_IGNORED_EDGE
+	case routing.RejectedFeeLimit:
+		return lnrpc.RejectedRoute_FEE_LIMIT
+	case routing.RejectedCltvLimit:
+		return lnrpc.RejectedRoute_CLTV_LIMIT
+	default:
+		return lnrpc.RejectedRoute_IGNORED_NODE
+	}
+}
+
 // calculateFeeLimit returns the fee limit in millisatoshis. If a percentage
 // based fee limit has been requested, we'll factor in the ratio provided with
 // the amount of the payment.
@@ -195,6 +364,34 @@ func calculateFeeLimit(feeLimit *lnrpc.FeeLimit,
 	}
 }
 
+// paymentFeeLimit returns the fee limit of a payment of the given amount, as
+// requested by a fixed limit in satoshis and a limit in parts per million of
+// the amount. If both limits are set, the lower one applies. Unset limits are
+// zero.
+func paymentFeeLimit(amount lnwire.MilliSatoshi, feeLimitSat,
+	feeLimitPPM int64) (lnwire.MilliSatoshi, error) {
+
+	switch {
+	case feeLimitSat < 0:
+		return 0, errors.New("fee limit must not be negative")
+
+	case feeLimitPPM < 0:
+		return 0, errors.New("fee limit ppm must not be negative")
+	}
+
+	feeLimit := lnwire.NewMSatFromSatoshis(btcutil.Amount(feeLimitSat))
+	if feeLimitPPM == 0 {
+		return feeLimit, nil
+	}
+
+	ppmLimit := amount * lnwire.MilliSatoshi(feeLimitPPM) / 1000000
+	if feeLimitSat == 0 || ppmLimit < feeLimit {
+		feeLimit = ppmLimit
+	}
+
+	return feeLimit, nil
+}
+
 // MarshallRoute marshalls an internal route to an rpc route struct.
 func (r *RouterBackend) MarshallRoute(route *route.Route) *lnrpc.Route {
 	resp := &lnrpc.Route{
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/routing"
 	"github.com/litecoinfinance/lnd/routing/route"
@@ -54,14 +55,27 @@ func TestQueryRoutes(t *testing.T) {
 			ChannelId:        555,
 			DirectionReverse: true,
 		}},
+		RouteHints: []*lnrpc.RouteHint{{
+			HopHints: []*lnrpc.HopHint{{
+				NodeId:          ignoreNodeKey,
+				ChanId:          777,
+				FeeBaseMsat:     1,
+				CltvExpiryDelta: 40,
+			}},
+		}},
+		PathWeights: &lnrpc.PathWeights{
+			FeeWeight:         0.5,
+			ProbabilityWeight: 1000,
+		},
 	}
 
 	rt := &route.Route{}
 
 	findRoutes := func(source, target route.Vertex,
 		amt lnwire.MilliSatoshi, restrictions *routing.RestrictParams,
-		numPaths uint32, finalExpiry ...uint16) (
-		[]*route.Route, error) {
+		numPaths uint32,
+		routeHints map[route.Vertex][]*channeldb.ChannelEdgePolicy,
+		finalExpiry ...uint16) ([]*route.Route, error) {
 
 		if int64(amt) != request.Amt*1000 {
 			t.Fatal("unexpected amount")
@@ -101,6 +115,25 @@ func TestQueryRoutes(t *testing.T) {
 			t.Fatal("unexpected ignored node")
 		}
 
+		hintEdges := routeHints[ignoreNodeVertex]
+		if len(routeHints) != 1 || len(hintEdges) != 1 {
+			t.Fatal("unexpected route hints")
+		}
+		if hintEdges[0].ChannelID != 777 ||
+			hintEdges[0].TimeLockDelta != 40 ||
+			hintEdges[0].Node.PubKeyBytes != target {
+
+			t.Fatal("unexpected route hint edge")
+		}
+
+		weights := restrictions.Weights
+		if weights == nil || weights.FeeWeight != 0.5 ||
+			weights.RiskFactorBillionths != 0 ||
+			weights.ProbabilityWeight != 1000 {
+
+			t.Fatal("unexpected path weights")
+		}
+
 		return []*route.Route{
 			rt,
 		}, nil
@@ -125,3 +158,129 @@ func TestQueryRoutes(t *testing.T) {
 		t.Fatal("expected a single route response")
 	}
 }
+
+// TestQueryRoutesExplain asserts that in explain mode, the rejected routes are
+// returned along with the reason of their rejection, and that the CLTV limit
+// is passed onto path finding excluding the final CLTV delta.
+func TestQueryRoutesExplain(t *testing.T) {
+	request := &lnrpc.QueryRoutesRequest{
+		PubKey:         destKey,
+		Amt:            100000,
+		FinalCltvDelta: 100,
+		CltvLimit:      500,
+		Explain:        true,
+	}
+
+	explainRoutes := func(source, target route.Vertex,
+		amt lnwire.MilliSatoshi, restrictions *routing.RestrictParams,
+		numPaths, numRejected uint32,
+		_ map[route.Vertex][]*channeldb.ChannelEdgePolicy,
+		finalExpiry ...uint16) (
+		[]*route.Route, []*routing.RejectedRoute, error) {
+
+		if numRejected != defaultNumRejected {
+			t.Fatal("unexpected number of rejected routes")
+		}
+
+		if restrictions.CltvLimit == nil ||
+			*restrictions.CltvLimit != 400 {
+
+			t.Fatal("unexpected cltv limit")
+		}
+
+		return nil, []*routing.RejectedRoute{{
+			Route:  &route.Route{},
+			Reason: routing.RejectedCltvLimit,
+		}}, nil
+	}
+
+	backend := &RouterBackend{
+		MaxPaymentMSat: lnwire.NewMSatFromSatoshis(1000000),
+		ExplainRoutes:  explainRoutes,
+		SelfNode:       route.Vertex{1, 2, 3},
+		FetchChannelCapacity: func(chanID uint64) (
+			btcutil.Amount, error) {
+
+			return 1, nil
+		},
+	}
+
+	resp, err := backend.QueryRoutes(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Routes) != 0 {
+		t.Fatal("expected no routes")
+	}
+	if len(resp.RejectedRoutes) != 1 {
+		t.Fatal("expected a single rejected route")
+	}
+	if resp.RejectedRoutes[0].Reason != lnrpc.RejectedRoute_CLTV_LIMIT {
+		t.Fatalf("unexpected rejection reason: %v",
+			resp.RejectedRoutes[0].Reason)
+	}
+}
+
+// TestPaymentFeeLimit asserts that the lower of the fixed and the relative fee
+// limit of a payment applies.
+func TestPaymentFeeLimit(t *testing.T) {
+	const amt = lnwire.MilliSatoshi(10000000)
+
+	tests := []struct {
+		name        string
+		feeLimitSat int64
+		feeLimitPPM int64
+		expected    lnwire.MilliSatoshi
+		expectErr   bool
+	}{
+		{
+			name:     "no limit",
+			expected: 0,
+		},
+		{
+			name:        "fixed",
+			feeLimitSat: 5,
+			expected:    5000,
+		},
+		{
+			name:        "ppm",
+			feeLimitPPM: 1000,
+			expected:    10000,
+		},
+		{
+			name:        "fixed lower",
+			feeLimitSat: 5,
+			feeLimitPPM: 1000,
+			expected:    5000,
+		},
+		{
+			name:        "ppm lower",
+			feeLimitSat: 20,
+			feeLimitPPM: 1000,
+			expected:    10000,
+		},
+		{
+			name:        "negative ppm",
+			feeLimitPPM: -1,
+			expectErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		feeLimit, err := paymentFeeLimit(
+			amt, test.feeLimitSat, test.feeLimitPPM,
+		)
+		switch {
+		case test.expectErr && err == nil:
+			t.Fatalf("%v: expected error", test.name)
+
+		case !test.expectErr && err != nil:
+			t.Fatalf("%v: unexpected error: %v", test.name, err)
+		}
+
+		if feeLimit != test.expected {
+			t.Fatalf("%v: expected fee limit %v, got %v", test.name,
+				test.expected, feeLimit)
+		}
+	}
+}
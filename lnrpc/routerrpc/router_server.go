@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcutil"
 	"github.com/litecoinfinance/lnd/lnrpc"
 	"github.com/litecoinfinance/lnd/lnwire"
@@ -48,6 +49,14 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/routerpc.Router/BuildRoute": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerpc.Router/ProbePayment": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 
 	// DefaultRouterMacFilename is the default name of the router macaroon
@@ -166,8 +175,10 @@ func (s *Server) RegisterWithRootServer(grpcServer *grpc.Server) error {
 // SendPayment attempts to route a payment described by the passed
 // PaymentRequest to the final destination. If we are unable to route the
 // payment, or cannot find a route that satisfies the constraints in the
-// PaymentRequest, then an error will be returned. Otherwise, the payment
-// pre-image, along with the final route will be returned.
+// PaymentRequest, then the payment error will be set in the response.
+// Otherwise, the payment pre-image will be returned. In either case, the
+// routes that were attempted without success are returned along with the
+// reason of each failure.
 func (s *Server) SendPayment(ctx context.Context,
 	req *PaymentRequest) (*PaymentResponse, error) {
 
@@ -194,6 +205,22 @@ func (s *Server) SendPayment(ctx context.Context,
 	var destination route.Vertex
 	copy(destination[:], payReq.Destination.SerializeCompressed())
 
+	// The fee limit covers the fees of all retries and shards of the
+	// payment.
+	feeLimit, err := paymentFeeLimit(
+		*payReq.MilliSat, req.FeeLimitSat, req.FeeLimitPpm,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CltvLimit < 0 {
+		return nil, errors.New("cltv limit must not be negative")
+	}
+	if req.TimeoutSeconds < 0 {
+		return nil, errors.New("timeout must not be negative")
+	}
+
 	// Now that all the information we need has been parsed, we'll map this
 	// proto request into a proper request that our backing router can
 	// understand.
@@ -201,11 +228,12 @@ func (s *Server) SendPayment(ctx context.Context,
 	payment := routing.LightningPayment{
 		Target:            destination,
 		Amount:            *payReq.MilliSat,
-		FeeLimit:          lnwire.MilliSatoshi(req.FeeLimitSat),
+		FeeLimit:          feeLimit,
 		PaymentHash:       *payReq.PaymentHash,
 		FinalCLTVDelta:    &finalDelta,
 		PayAttemptTimeout: time.Second * time.Duration(req.TimeoutSeconds),
 		RouteHints:        payReq.RouteHints,
+		MaxShards:         req.MaxShards,
 	}
 
 	// Pin to an outgoing channel if specified.
@@ -214,15 +242,35 @@ func (s *Server) SendPayment(ctx context.Context,
 		payment.OutgoingChannelID = &chanID
 	}
 
-	preImage, _, err := s.cfg.Router.SendPayment(&payment)
+	// Bound the total time lock of every route if specified.
+	if req.CltvLimit != 0 {
+		cltvLimit := uint32(req.CltvLimit)
+		payment.CltvLimit = &cltvLimit
+	}
+
+	// We'll collect the routes that fail along the way, such that the
+	// caller can tell why the payment failed, or took as long as it did.
+	resp := &PaymentResponse{
+		PayHash: (*payReq.PaymentHash)[:],
+	}
+	payment.AttemptFailed = func(rt *route.Route, err error) {
+		resp.FailedAttempts = append(
+			resp.FailedAttempts, &FailedAttempt{
+				Route:   s.cfg.RouterBackend.MarshallRoute(rt),
+				Failure: err.Error(),
+			},
+		)
+	}
+
+	preImage, _, err := s.cfg.Router.SendMultiPathPayment(&payment)
 	if err != nil {
-		return nil, err
+		resp.PaymentErr = err.Error()
+		return resp, nil
 	}
 
-	return &PaymentResponse{
-		PayHash:  (*payReq.PaymentHash)[:],
-		PreImage: preImage[:],
-	}, nil
+	resp.PreImage = preImage[:]
+
+	return resp, nil
 }
 
 // EstimateRouteFee allows callers to obtain a lower bound w.r.t how much it
@@ -251,7 +299,7 @@ func (s *Server) EstimateRouteFee(ctx context.Context,
 		s.cfg.RouterBackend.SelfNode, destNode, amtMsat,
 		&routing.RestrictParams{
 			FeeLimit: feeLimit,
-		}, 1,
+		}, 1, nil,
 	)
 	if err != nil {
 		return nil, err
@@ -266,3 +314,114 @@ func (s *Server) EstimateRouteFee(ctx context.Context,
 		TimeLockDelay:  int64(routes[0].TotalTimeLock),
 	}, nil
 }
+
+// BuildRoute builds a fully specified route based on a list of hop public keys.
+// It retrieves the relevant channel policies from the graph in order to
+// calculate the correct fees and time locks.
+func (s *Server) BuildRoute(ctx context.Context,
+	req *BuildRouteRequest) (*BuildRouteResponse, error) {
+
+	if req.AmtMsat <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if req.FinalCltvDelta < 0 {
+		return nil, errors.New("final cltv delta must not be negative")
+	}
+
+	hops := make([]route.Vertex, 0, len(req.HopPubkeys))
+	for _, pubKey := range req.HopPubkeys {
+		hop, err := btcec.ParsePubKey(pubKey, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("invalid hop pubkey %x: %v",
+				pubKey, err)
+		}
+		hops = append(hops, route.NewVertex(hop))
+	}
+
+	var outgoingChan *uint64
+	if req.OutgoingChanId != 0 {
+		outgoingChan = &req.OutgoingChanId
+	}
+
+	finalCLTVDelta := uint16(req.FinalCltvDelta)
+	if finalCLTVDelta == 0 {
+		finalCLTVDelta = zpay32.DefaultFinalCLTVDelta
+	}
+
+	rt, err := s.cfg.Router.BuildRoute(
+		lnwire.MilliSatoshi(req.AmtMsat), hops, outgoingChan,
+		finalCLTVDelta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BuildRouteResponse{
+		Route: s.cfg.RouterBackend.MarshallRoute(rt),
+	}, nil
+}
+
+// ProbePayment tests whether a payment of the given amount can be routed to the
+// destination, by sending an HTLC carrying a random payment hash along the
+// route the payment would take.
+func (s *Server) ProbePayment(ctx context.Context,
+	req *ProbeRequest) (*ProbeResponse, error) {
+
+	if len(req.Dest) != 33 {
+		return nil, errors.New("invalid length destination key")
+	}
+	var destination route.Vertex
+	copy(destination[:], req.Dest)
+
+	if req.AmtSat <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if req.FeeLimitSat < 0 {
+		return nil, errors.New("fee limit must not be negative")
+	}
+	if req.FinalCltvDelta < 0 {
+		return nil, errors.New("final cltv delta must not be negative")
+	}
+
+	amt := lnwire.NewMSatFromSatoshis(btcutil.Amount(req.AmtSat))
+
+	// As with payments lacking a fee limit, we won't probe routes that
+	// charge more than the amount itself.
+	feeLimit := amt
+	if req.FeeLimitSat != 0 {
+		feeLimit = lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(req.FeeLimitSat),
+		)
+	}
+
+	payment := &routing.LightningPayment{
+		Target:   destination,
+		Amount:   amt,
+		FeeLimit: feeLimit,
+	}
+	if req.FinalCltvDelta != 0 {
+		finalDelta := uint16(req.FinalCltvDelta)
+		payment.FinalCLTVDelta = &finalDelta
+	}
+	if req.OutgoingChanId != 0 {
+		payment.OutgoingChannelID = &req.OutgoingChanId
+	}
+
+	result, err := s.cfg.Router.ProbePayment(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ProbeResponse{
+		Success: result.Success,
+		Route: s.cfg.RouterBackend.MarshallRoute(
+			result.Route,
+		),
+		FailureSourcePubkey: result.FailureSource[:],
+	}
+	if result.Failure != nil {
+		resp.Failure = result.Failure.Error()
+	}
+
+	return resp, nil
+}
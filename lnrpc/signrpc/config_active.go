@@ -4,7 +4,9 @@ package signrpc
 
 import (
 	"github.com/litecoinfinance/lnd/input"
+	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/macaroons"
+	"github.com/litecoinfinance/lnd/netann"
 )
 
 // Config is the primary configuration struct for the signer RPC server. It
@@ -18,6 +20,22 @@ type Config struct {
 	// directory, named DefaultSignerMacFilename.
 	SignerMacPath string `long:"signermacaroonpath" description:"Path to the signer macaroon"`
 
+	// AllowChanUpdates permits callers holding the signer macaroon to
+	// request signed channel updates for our channels.
+	AllowChanUpdates bool `long:"allowchanupdates" description:"Allow holders of the signer macaroon to request signed channel updates for our channels"`
+
+	// MaxChanUpdateBaseFee is the maximum base fee, in millisatoshis, that
+	// channel updates signed on behalf of callers may advertise.
+	MaxChanUpdateBaseFee uint32 `long:"maxchanupdatebasefee" description:"The maximum base fee in millisatoshis that externally requested channel updates may advertise"`
+
+	// MaxChanUpdateFeeRate is the maximum fee rate, in parts per million,
+	// that channel updates signed on behalf of callers may advertise.
+	MaxChanUpdateFeeRate uint32 `long:"maxchanupdatefeerate" description:"The maximum fee rate in parts per million that externally requested channel updates may advertise"`
+
+	// MinChanUpdateTimeLockDelta is the minimum time lock delta that
+	// channel updates signed on behalf of callers may advertise.
+	MinChanUpdateTimeLockDelta uint16 `long:"minchanupdatetimelockdelta" description:"The minimum time lock delta that externally requested channel updates may advertise"`
+
 	// NetworkDir is the main network directory wherein the signer rpc
 	// server will find the macaroon named DefaultSignerMacFilename.
 	NetworkDir string
@@ -30,4 +48,10 @@ type Config struct {
 	// job of the signer RPC server is simply to proxy valid requests to
 	// the active signer instance.
 	Signer input.Signer
+
+	// SignChannelUpdate applies the given modifiers to our latest channel
+	// update for the target channel, and signs the result with our node
+	// key.
+	SignChannelUpdate func(lnwire.ShortChannelID,
+		...netann.ChannelUpdateModifier) (*lnwire.ChannelUpdate, error)
 }
@@ -37,7 +37,7 @@ func (m *KeyLocator) Reset()         { *m = KeyLocator{} }
 func (m *KeyLocator) String() string { return proto.CompactTextString(m) }
 func (*KeyLocator) ProtoMessage()    {}
 func (*KeyLocator) Descriptor() ([]byte, []int) {
-	return fileDescriptor_signer_8dc402df3ea9a6a7, []int{0}
+	return fileDescriptor_signer_85bc33044b201f37, []int{0}
 }
 func (m *KeyLocator) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KeyLocator.Unmarshal(m, b)
@@ -89,7 +89,7 @@ func (m *KeyDescriptor) Reset()         { *m = KeyDescriptor{} }
 func (m *KeyDescriptor) String() string { return proto.CompactTextString(m) }
 func (*KeyDescriptor) ProtoMessage()    {}
 func (*KeyDescriptor) Descriptor() ([]byte, []int) {
-	return fileDescriptor_signer_8dc402df3ea9a6a7, []int{1}
+	return fileDescriptor_signer_85bc33044b201f37, []int{1}
 }
 func (m *KeyDescriptor) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KeyDescriptor.Unmarshal(m, b)
@@ -137,7 +137,7 @@ func (m *TxOut) Reset()         { *m = TxOut{} }
 func (m *TxOut) String() string { return proto.CompactTextString(m) }
 func (*TxOut) ProtoMessage()    {}
 func (*TxOut) Descriptor() ([]byte, []int) {
-	return fileDescriptor_signer_8dc402df3ea9a6a7, []int{2}
+	return fileDescriptor_signer_85bc33044b201f37, []int{2}
 }
 func (m *TxOut) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxOut.Unmarshal(m, b)
@@ -218,7 +218,7 @@ func (m *SignDescriptor) Reset()         { *m = SignDescriptor{} }
 func (m *SignDescriptor) String() string { return proto.CompactTextString(m) }
 func (*SignDescriptor) ProtoMessage()    {}
 func (*SignDescriptor) Descriptor() ([]byte, []int) {
-	return fileDescriptor_signer_8dc402df3ea9a6a7, []int{3}
+	return fileDescriptor_signer_85bc33044b201f37, []int{3}
 }
 func (m *SignDescriptor) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignDescriptor.Unmarshal(m, b)
@@ -301,7 +301,7 @@ func (m *SignReq) Reset()         { *m = SignReq{} }
 func (m *SignReq) String() string { return proto.CompactTextString(m) }
 func (*SignReq) ProtoMessage()    {}
 func (*SignReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_signer_8dc402df3ea9a6a7, []int{4}
+	return fileDescriptor_signer_85bc33044b201f37, []int{4}
 }
 func (m *SignReq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignReq.Unmarshal(m, b)
@@ -349,7 +349,7 @@ func (m *SignResp) Reset()         { *m = SignResp{} }
 func (m *SignResp) String() string { return proto.CompactTextString(m) }
 func (*SignResp) ProtoMessage()    {}
 func (*SignResp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_signer_8dc402df3ea9a6a7, []int{5}
+	return fileDescriptor_signer_85bc33044b201f37, []int{5}
 }
 func (m *SignResp) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignResp.Unmarshal(m, b)
@@ -392,7 +392,7 @@ func (m *InputScript) Reset()         { *m = InputScript{} }
 func (m *InputScript) String() string { return proto.CompactTextString(m) }
 func (*InputScript) ProtoMessage()    {}
 func (*InputScript) Descriptor() ([]byte, []int) {
-	return fileDescriptor_signer_8dc402df3ea9a6a7, []int{6}
+	return fileDescriptor_signer_85bc33044b201f37, []int{6}
 }
 func (m *InputScript) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InputScript.Unmarshal(m, b)
@@ -438,7 +438,7 @@ func (m *InputScriptResp) Reset()         { *m = InputScriptResp{} }
 func (m *InputScriptResp) String() string { return proto.CompactTextString(m) }
 func (*InputScriptResp) ProtoMessage()    {}
 func (*InputScriptResp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_signer_8dc402df3ea9a6a7, []int{7}
+	return fileDescriptor_signer_85bc33044b201f37, []int{7}
 }
 func (m *InputScriptResp) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InputScriptResp.Unmarshal(m, b)
@@ -465,6 +465,113 @@ func (m *InputScriptResp) GetInputScripts() []*InputScript {
 	return nil
 }
 
+type SignChannelUpdateReq struct {
+	// / The short channel ID of the channel to sign an update for.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
+	// / The base fee in millisatoshis to advertise for the channel.
+	BaseFeeMsat uint32 `protobuf:"varint,2,opt,name=base_fee_msat,json=baseFeeMsat,proto3" json:"base_fee_msat,omitempty"`
+	// / The fee rate in parts per million to advertise for the channel.
+	FeeRatePpm uint32 `protobuf:"varint,3,opt,name=fee_rate_ppm,json=feeRatePpm,proto3" json:"fee_rate_ppm,omitempty"`
+	// / The time lock delta to advertise for the channel.
+	TimeLockDelta        uint32   `protobuf:"varint,4,opt,name=time_lock_delta,json=timeLockDelta,proto3" json:"time_lock_delta,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignChannelUpdateReq) Reset()         { *m = SignChannelUpdateReq{} }
+func (m *SignChannelUpdateReq) String() string { return proto.CompactTextString(m) }
+func (*SignChannelUpdateReq) ProtoMessage()    {}
+func (*SignChannelUpdateReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_signer_85bc33044b201f37, []int{8}
+}
+func (m *SignChannelUpdateReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignChannelUpdateReq.Unmarshal(m, b)
+}
+func (m *SignChannelUpdateReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignChannelUpdateReq.Marshal(b, m, deterministic)
+}
+func (dst *SignChannelUpdateReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignChannelUpdateReq.Merge(dst, src)
+}
+func (m *SignChannelUpdateReq) XXX_Size() int {
+	return xxx_messageInfo_SignChannelUpdateReq.Size(m)
+}
+func (m *SignChannelUpdateReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignChannelUpdateReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignChannelUpdateReq proto.InternalMessageInfo
+
+func (m *SignChannelUpdateReq) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *SignChannelUpdateReq) GetBaseFeeMsat() uint32 {
+	if m != nil {
+		return m.BaseFeeMsat
+	}
+	return 0
+}
+
+func (m *SignChannelUpdateReq) GetFeeRatePpm() uint32 {
+	if m != nil {
+		return m.FeeRatePpm
+	}
+	return 0
+}
+
+func (m *SignChannelUpdateReq) GetTimeLockDelta() uint32 {
+	if m != nil {
+		return m.TimeLockDelta
+	}
+	return 0
+}
+
+type SignChannelUpdateResp struct {
+	// *
+	// The signed channel_update message, serialized in its wire format without
+	// the message type prefix.
+	ChannelUpdate        []byte   `protobuf:"bytes,1,opt,name=channel_update,json=channelUpdate,proto3" json:"channel_update,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignChannelUpdateResp) Reset()         { *m = SignChannelUpdateResp{} }
+func (m *SignChannelUpdateResp) String() string { return proto.CompactTextString(m) }
+func (*SignChannelUpdateResp) ProtoMessage()    {}
+func (*SignChannelUpdateResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_signer_85bc33044b201f37, []int{9}
+}
+func (m *SignChannelUpdateResp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignChannelUpdateResp.Unmarshal(m, b)
+}
+func (m *SignChannelUpdateResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignChannelUpdateResp.Marshal(b, m, deterministic)
+}
+func (dst *SignChannelUpdateResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignChannelUpdateResp.Merge(dst, src)
+}
+func (m *SignChannelUpdateResp) XXX_Size() int {
+	return xxx_messageInfo_SignChannelUpdateResp.Size(m)
+}
+func (m *SignChannelUpdateResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignChannelUpdateResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignChannelUpdateResp proto.InternalMessageInfo
+
+func (m *SignChannelUpdateResp) GetChannelUpdate() []byte {
+	if m != nil {
+		return m.ChannelUpdate
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*KeyLocator)(nil), "signrpc.KeyLocator")
 	proto.RegisterType((*KeyDescriptor)(nil), "signrpc.KeyDescriptor")
@@ -474,6 +581,8 @@ func init() {
 	proto.RegisterType((*SignResp)(nil), "signrpc.SignResp")
 	proto.RegisterType((*InputScript)(nil), "signrpc.InputScript")
 	proto.RegisterType((*InputScriptResp)(nil), "signrpc.InputScriptResp")
+	proto.RegisterType((*SignChannelUpdateReq)(nil), "signrpc.SignChannelUpdateReq")
+	proto.RegisterType((*SignChannelUpdateResp)(nil), "signrpc.SignChannelUpdateResp")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -510,6 +619,16 @@ type SignerClient interface {
 	// in the TxOut field, the value in that same field, and finally the input
 	// index.
 	ComputeInputScript(ctx context.Context, in *SignReq, opts ...grpc.CallOption) (*InputScriptResp, error)
+	// *
+	// SignChannelUpdate produces a channel_update for one of our channels with
+	// the given forwarding policy, signed by our node key. This allows an
+	// external service to manage the forwarding policy of our channels, and
+	// distribute the resulting updates, without access to our node key.
+	//
+	// The request is rejected unless channel update signing has been enabled,
+	// and the policy is within the configured bounds. The signed update is
+	// neither applied to our own forwarding policy nor broadcast by lnd.
+	SignChannelUpdate(ctx context.Context, in *SignChannelUpdateReq, opts ...grpc.CallOption) (*SignChannelUpdateResp, error)
 }
 
 type signerClient struct {
@@ -538,6 +657,15 @@ func (c *signerClient) ComputeInputScript(ctx context.Context, in *SignReq, opts
 	return out, nil
 }
 
+func (c *signerClient) SignChannelUpdate(ctx context.Context, in *SignChannelUpdateReq, opts ...grpc.CallOption) (*SignChannelUpdateResp, error) {
+	out := new(SignChannelUpdateResp)
+	err := c.cc.Invoke(ctx, "/signrpc.Signer/SignChannelUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SignerServer is the server API for Signer service.
 type SignerServer interface {
 	// *
@@ -562,6 +690,16 @@ type SignerServer interface {
 	// in the TxOut field, the value in that same field, and finally the input
 	// index.
 	ComputeInputScript(context.Context, *SignReq) (*InputScriptResp, error)
+	// *
+	// SignChannelUpdate produces a channel_update for one of our channels with
+	// the given forwarding policy, signed by our node key. This allows an
+	// external service to manage the forwarding policy of our channels, and
+	// distribute the resulting updates, without access to our node key.
+	//
+	// The request is rejected unless channel update signing has been enabled,
+	// and the policy is within the configured bounds. The signed update is
+	// neither applied to our own forwarding policy nor broadcast by lnd.
+	SignChannelUpdate(context.Context, *SignChannelUpdateReq) (*SignChannelUpdateResp, error)
 }
 
 func RegisterSignerServer(s *grpc.Server, srv SignerServer) {
@@ -604,6 +742,24 @@ func _Signer_ComputeInputScript_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Signer_SignChannelUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignChannelUpdateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).SignChannelUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/signrpc.Signer/SignChannelUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).SignChannelUpdate(ctx, req.(*SignChannelUpdateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Signer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "signrpc.Signer",
 	HandlerType: (*SignerServer)(nil),
@@ -616,49 +772,61 @@ var _Signer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ComputeInputScript",
 			Handler:    _Signer_ComputeInputScript_Handler,
 		},
+		{
+			MethodName: "SignChannelUpdate",
+			Handler:    _Signer_SignChannelUpdate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "signrpc/signer.proto",
 }
 
-func init() { proto.RegisterFile("signrpc/signer.proto", fileDescriptor_signer_8dc402df3ea9a6a7) }
-
-var fileDescriptor_signer_8dc402df3ea9a6a7 = []byte{
-	// 562 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0x4f, 0x8f, 0xd3, 0x3e,
-	0x10, 0x55, 0xb7, 0xbf, 0x36, 0xdd, 0x49, 0xd2, 0x1f, 0x98, 0x0a, 0x02, 0x08, 0x51, 0x22, 0x2d,
-	0xea, 0x01, 0x5a, 0x51, 0x10, 0x12, 0x9c, 0xd0, 0x82, 0x56, 0xac, 0xba, 0xd2, 0x4a, 0x6e, 0x4f,
-	0x5c, 0xa2, 0x34, 0x35, 0xa9, 0x95, 0x34, 0xf1, 0xc6, 0x0e, 0x69, 0x6e, 0x7c, 0x07, 0xbe, 0x30,
-	0x1a, 0x3b, 0xfd, 0x07, 0x9c, 0x9a, 0xf7, 0x3c, 0x33, 0xef, 0x79, 0x5e, 0x0d, 0x03, 0xc9, 0xe3,
-	0xac, 0x10, 0xd1, 0x04, 0x7f, 0x59, 0x31, 0x16, 0x45, 0xae, 0x72, 0x62, 0x35, 0xac, 0xff, 0x15,
-	0x60, 0xc6, 0xea, 0x9b, 0x3c, 0x0a, 0x55, 0x5e, 0x90, 0x67, 0x00, 0x09, 0xab, 0x83, 0xef, 0xe1,
-	0x86, 0xa7, 0xb5, 0xd7, 0x1a, 0xb6, 0x46, 0x1d, 0x7a, 0x9e, 0xb0, 0xfa, 0x4a, 0x13, 0xe4, 0x29,
-	0x20, 0x08, 0x78, 0xb6, 0x62, 0x5b, 0xef, 0x4c, 0x9f, 0xf6, 0x12, 0x56, 0x5f, 0x23, 0xf6, 0x43,
-	0x70, 0x67, 0xac, 0xfe, 0xc2, 0x64, 0x54, 0x70, 0x81, 0xc3, 0x7c, 0x70, 0x8b, 0xb0, 0x0a, 0xb0,
-	0x63, 0x59, 0x2b, 0x26, 0xf5, 0x3c, 0x87, 0xda, 0x45, 0x58, 0xcd, 0x58, 0x7d, 0x89, 0x14, 0x79,
-	0x05, 0x16, 0x9e, 0xa7, 0x79, 0xa4, 0xe7, 0xd9, 0xd3, 0x07, 0xe3, 0xc6, 0xd9, 0xf8, 0x60, 0x8b,
-	0x76, 0x13, 0xfd, 0xed, 0x7f, 0x84, 0xce, 0x62, 0x7b, 0x5b, 0x2a, 0x32, 0x80, 0xce, 0x8f, 0x30,
-	0x2d, 0x99, 0x1e, 0xd9, 0xa6, 0x06, 0xa0, 0x3d, 0x91, 0x04, 0x46, 0x5f, 0x8f, 0x73, 0x68, 0x4f,
-	0x24, 0x73, 0x8d, 0xfd, 0x5f, 0x67, 0xd0, 0x9f, 0xf3, 0x38, 0x3b, 0x32, 0xf8, 0x06, 0xd0, 0x7d,
-	0xb0, 0x62, 0x32, 0xd2, 0x83, 0xec, 0xe9, 0xc3, 0x63, 0xf5, 0x43, 0x25, 0x45, 0x93, 0x08, 0xc9,
-	0x0b, 0x70, 0x24, 0xcf, 0xe2, 0x94, 0x05, 0xaa, 0x62, 0x61, 0xd2, 0xa8, 0xd8, 0x86, 0x5b, 0x20,
-	0x85, 0x25, 0xab, 0xbc, 0x5c, 0xee, 0x4b, 0xda, 0xa6, 0xc4, 0x70, 0xa6, 0xe4, 0x02, 0xfa, 0x15,
-	0x57, 0x19, 0x93, 0x72, 0xe7, 0xf6, 0x3f, 0x5d, 0xe4, 0x36, 0xac, 0xb1, 0x4c, 0x5e, 0x42, 0x37,
-	0x2f, 0x95, 0x28, 0x95, 0xd7, 0xd1, 0xee, 0xfa, 0x7b, 0x77, 0x7a, 0x0b, 0xb4, 0x39, 0x25, 0x1e,
-	0x60, 0x9c, 0xeb, 0x50, 0xae, 0x3d, 0x6b, 0xd8, 0x1a, 0xb9, 0x74, 0x07, 0xc9, 0x73, 0xb0, 0x79,
-	0x26, 0x4a, 0xd5, 0x44, 0xd6, 0xd3, 0x91, 0x81, 0xa6, 0x4c, 0x68, 0x11, 0x58, 0xb8, 0x14, 0xca,
-	0xee, 0xc8, 0x10, 0x1c, 0x8c, 0x4b, 0x6d, 0x4f, 0xd2, 0x82, 0x22, 0xac, 0x16, 0x5b, 0x13, 0xd6,
-	0x7b, 0x00, 0x34, 0xa0, 0x17, 0x26, 0xbd, 0xb3, 0x61, 0x7b, 0x64, 0x4f, 0x1f, 0xed, 0x3d, 0x9d,
-	0x2e, 0x97, 0x9e, 0xcb, 0x06, 0x4b, 0xff, 0x02, 0x7a, 0x46, 0x44, 0x0a, 0xf2, 0x18, 0x7a, 0xa8,
-	0x22, 0x79, 0x8c, 0x0a, 0xed, 0x91, 0x43, 0xad, 0x22, 0xac, 0xe6, 0x3c, 0x96, 0xfe, 0x15, 0xd8,
-	0xd7, 0xe8, 0xac, 0xb9, 0xbd, 0x07, 0x56, 0xb3, 0x8e, 0x5d, 0x61, 0x03, 0xf1, 0x5f, 0x2a, 0x79,
-	0x7c, 0x1a, 0x34, 0xca, 0x35, 0x49, 0xdf, 0xc0, 0xff, 0x47, 0x73, 0xb4, 0xea, 0x07, 0x70, 0xcd,
-	0x1e, 0x4c, 0x8f, 0x99, 0x68, 0x4f, 0x07, 0x7b, 0xf3, 0xc7, 0x0d, 0x0e, 0x3f, 0x00, 0x39, 0xfd,
-	0xd9, 0x82, 0xee, 0x5c, 0x3f, 0x1d, 0xf2, 0x0e, 0x5c, 0xfc, 0xba, 0xd5, 0x5b, 0xa7, 0x61, 0x45,
-	0xee, 0x9d, 0x5c, 0x9e, 0xb2, 0xbb, 0x27, 0xf7, 0xff, 0x60, 0xa4, 0x20, 0x9f, 0x80, 0x7c, 0xce,
-	0x37, 0xa2, 0x54, 0xec, 0xf8, 0x76, 0x7f, 0xb7, 0x7a, 0xff, 0x34, 0xc3, 0xa4, 0xb8, 0x9c, 0x7c,
-	0x7b, 0x1d, 0x73, 0xb5, 0x2e, 0x97, 0xe3, 0x28, 0xdf, 0x4c, 0x52, 0x1e, 0xaf, 0x55, 0xc6, 0xb3,
-	0x38, 0x63, 0xaa, 0xca, 0x8b, 0x64, 0x92, 0x66, 0xab, 0x49, 0xba, 0x7f, 0xe2, 0x85, 0x88, 0x96,
-	0x5d, 0xfd, 0xc8, 0xdf, 0xfe, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x80, 0x01, 0xce, 0xe1, 0xfc, 0x03,
-	0x00, 0x00,
+func init() { proto.RegisterFile("signrpc/signer.proto", fileDescriptor_signer_85bc33044b201f37) }
+
+var fileDescriptor_signer_85bc33044b201f37 = []byte{
+	// 703 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0xd1, 0x8e, 0xdb, 0x44,
+	0x14, 0x55, 0x36, 0x4d, 0x9c, 0x5e, 0xc7, 0x5b, 0x3a, 0x04, 0x6a, 0x8a, 0x0a, 0xc1, 0x52, 0xab,
+	0x3c, 0x54, 0x89, 0x08, 0x08, 0x09, 0x1e, 0x10, 0x6a, 0xab, 0x15, 0xab, 0x5d, 0x54, 0x34, 0x59,
+	0x5e, 0x78, 0xb1, 0x26, 0xf6, 0x4d, 0x32, 0xb2, 0x63, 0x4f, 0x3d, 0x63, 0x12, 0x7f, 0x07, 0xcf,
+	0xfc, 0x15, 0x1f, 0x84, 0xee, 0x8c, 0x37, 0x9b, 0x74, 0x57, 0x3c, 0xc5, 0xf7, 0xcc, 0x9d, 0x73,
+	0x8f, 0xcf, 0xb9, 0x31, 0x8c, 0xb4, 0x5c, 0x17, 0x95, 0x4a, 0x66, 0xf4, 0x8b, 0xd5, 0x54, 0x55,
+	0xa5, 0x29, 0x99, 0xd7, 0xa2, 0xd1, 0xaf, 0x00, 0x57, 0xd8, 0x5c, 0x97, 0x89, 0x30, 0x65, 0xc5,
+	0x5e, 0x00, 0x64, 0xd8, 0xc4, 0x2b, 0xb1, 0x95, 0x79, 0x13, 0x76, 0xc6, 0x9d, 0x49, 0x8f, 0x3f,
+	0xce, 0xb0, 0xb9, 0xb0, 0x00, 0xfb, 0x12, 0xa8, 0x88, 0x65, 0x91, 0xe2, 0x3e, 0x3c, 0xb3, 0xa7,
+	0x83, 0x0c, 0x9b, 0x4b, 0xaa, 0x23, 0x01, 0xc1, 0x15, 0x36, 0xef, 0x50, 0x27, 0x95, 0x54, 0x44,
+	0x16, 0x41, 0x50, 0x89, 0x5d, 0x4c, 0x37, 0x96, 0x8d, 0x41, 0x6d, 0xf9, 0x86, 0xdc, 0xaf, 0xc4,
+	0xee, 0x0a, 0x9b, 0x37, 0x04, 0xb1, 0xd7, 0xe0, 0xd1, 0x79, 0x5e, 0x26, 0x96, 0xcf, 0x9f, 0x7f,
+	0x3a, 0x6d, 0x95, 0x4d, 0xef, 0x64, 0xf1, 0x7e, 0x66, 0x9f, 0xa3, 0x9f, 0xa0, 0x77, 0xb3, 0x7f,
+	0x5f, 0x1b, 0x36, 0x82, 0xde, 0x5f, 0x22, 0xaf, 0xd1, 0x52, 0x76, 0xb9, 0x2b, 0x48, 0x9e, 0xca,
+	0x62, 0x37, 0xdf, 0xd2, 0x0d, 0xf9, 0x40, 0x65, 0x0b, 0x5b, 0x47, 0x7f, 0x9f, 0xc1, 0xf9, 0x42,
+	0xae, 0x8b, 0x23, 0x81, 0xdf, 0x02, 0xa9, 0x8f, 0x53, 0xd4, 0x89, 0x25, 0xf2, 0xe7, 0x9f, 0x1f,
+	0x4f, 0xbf, 0xeb, 0xe4, 0x5e, 0xe6, 0x4a, 0xf6, 0x0d, 0x0c, 0xb5, 0x2c, 0xd6, 0x39, 0xc6, 0x66,
+	0x87, 0x22, 0x6b, 0xa7, 0xf8, 0x0e, 0xbb, 0x21, 0x88, 0x5a, 0xd2, 0xb2, 0x5e, 0x1e, 0x5a, 0xba,
+	0xae, 0xc5, 0x61, 0xae, 0xe5, 0x25, 0x9c, 0xef, 0xa4, 0x29, 0x50, 0xeb, 0x5b, 0xb5, 0x8f, 0x6c,
+	0x53, 0xd0, 0xa2, 0x4e, 0x32, 0x7b, 0x05, 0xfd, 0xb2, 0x36, 0xaa, 0x36, 0x61, 0xcf, 0xaa, 0x3b,
+	0x3f, 0xa8, 0xb3, 0x2e, 0xf0, 0xf6, 0x94, 0x85, 0x40, 0x71, 0x6e, 0x84, 0xde, 0x84, 0xde, 0xb8,
+	0x33, 0x09, 0xf8, 0x6d, 0xc9, 0xbe, 0x06, 0x5f, 0x16, 0xaa, 0x36, 0x6d, 0x64, 0x03, 0x1b, 0x19,
+	0x58, 0xc8, 0x85, 0x96, 0x80, 0x47, 0xa6, 0x70, 0xfc, 0xc0, 0xc6, 0x30, 0xa4, 0xb8, 0xcc, 0xfe,
+	0x24, 0x2d, 0xa8, 0xc4, 0xee, 0x66, 0xef, 0xc2, 0xfa, 0x01, 0x80, 0x04, 0x58, 0xc3, 0x74, 0x78,
+	0x36, 0xee, 0x4e, 0xfc, 0xf9, 0xb3, 0x83, 0xa6, 0x53, 0x73, 0xf9, 0x63, 0xdd, 0xd6, 0x3a, 0x7a,
+	0x09, 0x03, 0x37, 0x44, 0x2b, 0xf6, 0x05, 0x0c, 0x68, 0x8a, 0x96, 0x6b, 0x9a, 0xd0, 0x9d, 0x0c,
+	0xb9, 0x57, 0x89, 0xdd, 0x42, 0xae, 0x75, 0x74, 0x01, 0xfe, 0x25, 0x29, 0x6b, 0xdf, 0x3e, 0x04,
+	0xaf, 0xb5, 0xe3, 0xb6, 0xb1, 0x2d, 0x69, 0x4b, 0xb5, 0x5c, 0x9f, 0x06, 0x4d, 0xe3, 0xda, 0xa4,
+	0xaf, 0xe1, 0xc9, 0x11, 0x8f, 0x9d, 0xfa, 0x23, 0x04, 0xce, 0x07, 0x77, 0xc7, 0x31, 0xfa, 0xf3,
+	0xd1, 0x41, 0xfc, 0xf1, 0x85, 0xa1, 0xbc, 0x2b, 0x74, 0xf4, 0x4f, 0x07, 0x46, 0xa4, 0xfe, 0xed,
+	0x46, 0x14, 0x05, 0xe6, 0x7f, 0xa8, 0x54, 0x18, 0x24, 0xbf, 0x9e, 0x81, 0x97, 0x6c, 0x44, 0x11,
+	0xcb, 0xd4, 0x5a, 0xf5, 0x88, 0xf7, 0xa9, 0xbc, 0x4c, 0x69, 0xef, 0x97, 0x42, 0x63, 0xbc, 0x42,
+	0x8c, 0xb7, 0x5a, 0x38, 0x85, 0x01, 0xf7, 0x09, 0xbc, 0x40, 0xfc, 0x4d, 0x0b, 0x43, 0x66, 0xd3,
+	0x71, 0x25, 0x0c, 0xc6, 0x4a, 0x6d, 0xed, 0x92, 0x04, 0x1c, 0x56, 0x88, 0x5c, 0x18, 0xfc, 0x5d,
+	0x6d, 0xd9, 0x2b, 0x78, 0x62, 0xe4, 0x16, 0xe9, 0xaf, 0x91, 0xc5, 0x29, 0xe6, 0x46, 0xd8, 0x25,
+	0x09, 0x78, 0x40, 0xf0, 0x75, 0x99, 0x64, 0xef, 0x08, 0x8c, 0x7e, 0x86, 0xcf, 0x1e, 0x90, 0xa7,
+	0x15, 0x2d, 0x59, 0xe2, 0xc0, 0xb8, 0xb6, 0x68, 0x9b, 0x68, 0x90, 0x1c, 0xb7, 0xce, 0xff, 0xed,
+	0x40, 0x7f, 0x61, 0x3f, 0x0d, 0xec, 0x7b, 0x08, 0xe8, 0xe9, 0xbd, 0xdd, 0x2a, 0x2e, 0x76, 0xec,
+	0x93, 0x93, 0x70, 0x39, 0x7e, 0x78, 0xfe, 0xf4, 0x23, 0x44, 0x2b, 0xf6, 0x0b, 0xb0, 0xb7, 0xe5,
+	0x56, 0xd5, 0x06, 0x8f, 0xd3, 0xbb, 0x7f, 0x35, 0x7c, 0xd0, 0x6c, 0x62, 0xe0, 0xf0, 0xf4, 0xde,
+	0x2b, 0xb0, 0x17, 0x27, 0x04, 0x1f, 0xbb, 0xff, 0xfc, 0xab, 0xff, 0x3b, 0xd6, 0xea, 0xcd, 0xf4,
+	0xcf, 0xd7, 0x6b, 0x69, 0x36, 0xf5, 0x72, 0x9a, 0x94, 0xdb, 0x59, 0x2e, 0x0d, 0x26, 0xa5, 0x2c,
+	0x56, 0xb2, 0x10, 0x45, 0x82, 0xb3, 0xbc, 0x48, 0x67, 0xf9, 0xe1, 0xab, 0x58, 0xa9, 0x64, 0xd9,
+	0xb7, 0xdf, 0xc5, 0xef, 0xfe, 0x1b, 0x00, 0x4f, 0xef, 0x20, 0xfe, 0x2f, 0x05, 0x00, 0x00,
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/litecoinfinance/lnd/input"
 	"github.com/litecoinfinance/lnd/keychain"
 	"github.com/litecoinfinance/lnd/lnrpc"
+	"github.com/litecoinfinance/lnd/lnwire"
 
 	"google.golang.org/grpc"
 	"gopkg.in/macaroon-bakery.v2/bakery"
@@ -49,6 +51,10 @@ var (
 			Entity: "signer",
 			Action: "generate",
 		}},
+		"/signrpc.Signer/SignChannelUpdate": {{
+			Entity: "signer",
+			Action: "generate",
+		}},
 	}
 
 	// DefaultSignerMacFilename is the default name of the signer macaroon
@@ -57,6 +63,20 @@ var (
 	DefaultSignerMacFilename = "signer.macaroon"
 )
 
+const (
+	// DefaultMaxChanUpdateBaseFee is the default maximum base fee, in
+	// millisatoshis, of channel updates signed on behalf of callers.
+	DefaultMaxChanUpdateBaseFee = 10000
+
+	// DefaultMaxChanUpdateFeeRate is the default maximum fee rate, in
+	// parts per million, of channel updates signed on behalf of callers.
+	DefaultMaxChanUpdateFeeRate = 10000
+
+	// DefaultMinChanUpdateTimeLockDelta is the default minimum time lock
+	// delta of channel updates signed on behalf of callers.
+	DefaultMinChanUpdateTimeLockDelta = 40
+)
+
 // Server is a sub-server of the main RPC server: the signer RPC. This sub RPC
 // server allows external callers to access the full signing capabilities of
 // lnd. This allows callers to create custom protocols, external to lnd, even
@@ -111,6 +131,16 @@ func New(cfg *Config) (*Server, lnrpc.MacaroonPerms, error) {
 		}
 	}
 
+	if cfg.MaxChanUpdateBaseFee == 0 {
+		cfg.MaxChanUpdateBaseFee = DefaultMaxChanUpdateBaseFee
+	}
+	if cfg.MaxChanUpdateFeeRate == 0 {
+		cfg.MaxChanUpdateFeeRate = DefaultMaxChanUpdateFeeRate
+	}
+	if cfg.MinChanUpdateTimeLockDelta == 0 {
+		cfg.MinChanUpdateTimeLockDelta = DefaultMinChanUpdateTimeLockDelta
+	}
+
 	signerServer := &Server{
 		cfg: cfg,
 	}
@@ -201,18 +231,18 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp, err
 	for _, signDesc := range in.SignDescs {
 		keyDesc := signDesc.KeyDesc
 
-		// The caller can either specify the key using the raw pubkey,
-		// or the description of the key. Below we'll feel out the
-		// oneof field to decide which one we will attempt to parse.
+		// The caller can specify the key using the raw pubkey, the
+		// description of the key, or both. Passing both allows us to
+		// derive the key directly, rather than scanning its family for
+		// the pubkey.
 		var (
 			targetPubKey *btcec.PublicKey
 			keyLoc       keychain.KeyLocator
 		)
-		switch {
 
 		// If this method doesn't return nil, then we know that user is
 		// attempting to include a raw serialized pub key.
-		case keyDesc.GetRawKeyBytes() != nil:
+		if keyDesc.GetRawKeyBytes() != nil {
 			rawKeyBytes := keyDesc.GetRawKeyBytes()
 
 			switch {
@@ -235,10 +265,11 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp, err
 						"parse pubkey: %v", err)
 				}
 			}
+		}
 
 		// Similarly, if they specified a key locator, then we'll use
-		// that instead.
-		case keyDesc.GetKeyLoc() != nil:
+		// that as well.
+		if keyDesc.GetKeyLoc() != nil {
 			protoLoc := keyDesc.GetKeyLoc()
 			keyLoc = keychain.KeyLocator{
 				Family: keychain.KeyFamily(
@@ -383,3 +414,62 @@ func (s *Server) ComputeInputScript(ctx context.Context,
 
 	return resp, nil
 }
+
+// SignChannelUpdate produces a channel update for one of our channels with the
+// requested forwarding policy, signed by our node key. The request is rejected
+// if channel update signing hasn't been enabled, or the policy lies outside of
+// the configured bounds.
+func (s *Server) SignChannelUpdate(ctx context.Context,
+	in *SignChannelUpdateReq) (*SignChannelUpdateResp, error) {
+
+	if !s.cfg.AllowChanUpdates {
+		return nil, fmt.Errorf("channel update signing is disabled, " +
+			"enable it with signrpc.allowchanupdates")
+	}
+
+	switch {
+	case in.BaseFeeMsat > s.cfg.MaxChanUpdateBaseFee:
+		return nil, fmt.Errorf("base fee of %d msat exceeds maximum of "+
+			"%d msat", in.BaseFeeMsat, s.cfg.MaxChanUpdateBaseFee)
+
+	case in.FeeRatePpm > s.cfg.MaxChanUpdateFeeRate:
+		return nil, fmt.Errorf("fee rate of %d ppm exceeds maximum of "+
+			"%d ppm", in.FeeRatePpm, s.cfg.MaxChanUpdateFeeRate)
+
+	case in.TimeLockDelta > math.MaxUint16:
+		return nil, fmt.Errorf("time lock delta of %d is too large",
+			in.TimeLockDelta)
+
+	case in.TimeLockDelta < uint32(s.cfg.MinChanUpdateTimeLockDelta):
+		return nil, fmt.Errorf("time lock delta of %d is below minimum "+
+			"of %d", in.TimeLockDelta,
+			s.cfg.MinChanUpdateTimeLockDelta)
+	}
+
+	chanID := lnwire.NewShortChanIDFromInt(in.ChanId)
+
+	log.Debugf("Signing channel update for ChannelID(%v): base_fee=%v, "+
+		"fee_rate=%v, time_lock_delta=%v", chanID, in.BaseFeeMsat,
+		in.FeeRatePpm, in.TimeLockDelta)
+
+	update, err := s.cfg.SignChannelUpdate(
+		chanID, func(update *lnwire.ChannelUpdate) {
+			update.BaseFee = in.BaseFeeMsat
+			update.FeeRate = in.FeeRatePpm
+			update.TimeLockDelta = uint16(in.TimeLockDelta)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign channel update for "+
+			"ChannelID(%v): %v", chanID, err)
+	}
+
+	var b bytes.Buffer
+	if err := update.Encode(&b, 0); err != nil {
+		return nil, err
+	}
+
+	return &SignChannelUpdateResp{
+		ChannelUpdate: b.Bytes(),
+	}, nil
+}
@@ -0,0 +1,85 @@
+// +build towerrpc
+
+package towerrpc
+
+import (
+	"net"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/lnd/macaroons"
+	"github.com/litecoinfinance/lnd/watchtower"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
+	"github.com/litecoinfinance/lnd/watchtower/wtserver"
+)
+
+// Backend is the interface of the watchtower administered by the tower RPC
+// server. It is satisfied by *watchtower.Standalone.
+type Backend interface {
+	// PubKey returns the public key clients use to authenticate the tower.
+	PubKey() *btcec.PublicKey
+
+	// ListenAddrs returns the addresses on which the tower accepts
+	// clients.
+	ListenAddrs() []net.Addr
+
+	// Sessions returns a summary of every session negotiated with a
+	// client.
+	Sessions() ([]*wtdb.SessionSummary, error)
+
+	// Stats returns a summary of the resources occupied by the tower's
+	// clients, and the breaches handled since the tower was started.
+	Stats() (*watchtower.Stats, error)
+
+	// BlacklistClient refuses any further connections from the client
+	// with the given session id.
+	BlacklistClient(wtdb.SessionID) error
+
+	// UnblacklistClient allows a previously blacklisted client to connect
+	// to the tower once again.
+	UnblacklistClient(wtdb.SessionID) error
+
+	// BlacklistedClients returns the session ids of all blacklisted
+	// clients.
+	BlacklistedClients() ([]wtdb.SessionID, error)
+
+	// PruneInactiveSessions deletes all sessions that have been inactive
+	// for longer than the given duration.
+	PruneInactiveSessions(time.Duration) ([]wtdb.SessionID, error)
+
+	// PolicyBounds returns the bounds currently restricting the policies
+	// of new sessions.
+	PolicyBounds() wtserver.PolicyBounds
+
+	// SetPolicyBounds replaces the bounds restricting the policies of new
+	// sessions.
+	SetPolicyBounds(wtserver.PolicyBounds) error
+
+	// SessionPolicies returns the distinct policies negotiated by the
+	// tower's clients.
+	SessionPolicies() ([]*watchtower.SessionPolicy, error)
+}
+
+// Config is the primary configuration struct for the tower RPC server. It
+// contains all the items required for the rpc server to carry out its
+// duties. The fields with struct tags are meant to be parsed as normal
+// configuration options, while if able to be populated, the latter fields MUST
+// also be specified.
+type Config struct {
+	// TowerMacPath is the path for the tower macaroon. If unspecified
+	// then we assume that the macaroon will be found under the network
+	// directory, named DefaultTowerMacFilename.
+	TowerMacPath string `long:"towermacaroonpath" description:"Path to the tower macaroon"`
+
+	// NetworkDir is the main network directory wherein the tower rpc
+	// server will find the macaroon named DefaultTowerMacFilename.
+	NetworkDir string
+
+	// MacService is the main macaroon service that we'll use to handle
+	// authentication for the tower rpc server.
+	MacService *macaroons.Service
+
+	// Tower is the watchtower administered by the tower RPC server. If
+	// nil, all calls fail with ErrTowerNotActive.
+	Tower Backend
+}
@@ -0,0 +1,6 @@
+// +build !towerrpc
+
+package towerrpc
+
+// Config is empty for non-towerrpc builds.
+type Config struct{}
@@ -0,0 +1,1500 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: towerrpc/tower.proto
+
+package towerrpc // import "github.com/litecoinfinance/lnd/lnrpc/towerrpc"
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type GetInfoRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetInfoRequest) Reset()         { *m = GetInfoRequest{} }
+func (m *GetInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetInfoRequest) ProtoMessage()    {}
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{0}
+}
+func (m *GetInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetInfoRequest.Unmarshal(m, b)
+}
+func (m *GetInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetInfoRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetInfoRequest.Merge(dst, src)
+}
+func (m *GetInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetInfoRequest.Size(m)
+}
+func (m *GetInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetInfoRequest proto.InternalMessageInfo
+
+type GetInfoResponse struct {
+	// / The public key clients use to authenticate the tower.
+	Pubkey []byte `protobuf:"bytes,1,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	// / The addresses the tower accepts clients on.
+	Listeners []string `protobuf:"bytes,2,rep,name=listeners,proto3" json:"listeners,omitempty"`
+	// / The number of sessions negotiated with clients.
+	NumSessions uint64 `protobuf:"varint,3,opt,name=num_sessions,json=numSessions,proto3" json:"num_sessions,omitempty"`
+	// / The number of encrypted blobs stored across all sessions.
+	NumUpdates uint64 `protobuf:"varint,4,opt,name=num_updates,json=numUpdates,proto3" json:"num_updates,omitempty"`
+	// / The total size of all stored encrypted blobs in bytes.
+	UpdateBytes uint64 `protobuf:"varint,5,opt,name=update_bytes,json=updateBytes,proto3" json:"update_bytes,omitempty"`
+	// / The size of the tower database in bytes.
+	DbSize uint64 `protobuf:"varint,6,opt,name=db_size,json=dbSize,proto3" json:"db_size,omitempty"`
+	// / The number of blacklisted clients.
+	NumBlacklisted uint64 `protobuf:"varint,7,opt,name=num_blacklisted,json=numBlacklisted,proto3" json:"num_blacklisted,omitempty"`
+	// *
+	// The number of breaches matched against stored encrypted blobs since the
+	// tower was started.
+	MatchedBreaches uint64 `protobuf:"varint,8,opt,name=matched_breaches,json=matchedBreaches,proto3" json:"matched_breaches,omitempty"`
+	// *
+	// The number of justice transactions published since the tower was
+	// started.
+	PunishedBreaches     uint64   `protobuf:"varint,9,opt,name=punished_breaches,json=punishedBreaches,proto3" json:"punished_breaches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetInfoResponse) Reset()         { *m = GetInfoResponse{} }
+func (m *GetInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*GetInfoResponse) ProtoMessage()    {}
+func (*GetInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{1}
+}
+func (m *GetInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetInfoResponse.Unmarshal(m, b)
+}
+func (m *GetInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetInfoResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetInfoResponse.Merge(dst, src)
+}
+func (m *GetInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_GetInfoResponse.Size(m)
+}
+func (m *GetInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetInfoResponse proto.InternalMessageInfo
+
+func (m *GetInfoResponse) GetPubkey() []byte {
+	if m != nil {
+		return m.Pubkey
+	}
+	return nil
+}
+
+func (m *GetInfoResponse) GetListeners() []string {
+	if m != nil {
+		return m.Listeners
+	}
+	return nil
+}
+
+func (m *GetInfoResponse) GetNumSessions() uint64 {
+	if m != nil {
+		return m.NumSessions
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetNumUpdates() uint64 {
+	if m != nil {
+		return m.NumUpdates
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetUpdateBytes() uint64 {
+	if m != nil {
+		return m.UpdateBytes
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetDbSize() uint64 {
+	if m != nil {
+		return m.DbSize
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetNumBlacklisted() uint64 {
+	if m != nil {
+		return m.NumBlacklisted
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetMatchedBreaches() uint64 {
+	if m != nil {
+		return m.MatchedBreaches
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetPunishedBreaches() uint64 {
+	if m != nil {
+		return m.PunishedBreaches
+	}
+	return 0
+}
+
+type ListSessionsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSessionsRequest) Reset()         { *m = ListSessionsRequest{} }
+func (m *ListSessionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsRequest) ProtoMessage()    {}
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{2}
+}
+func (m *ListSessionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionsRequest.Unmarshal(m, b)
+}
+func (m *ListSessionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListSessionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionsRequest.Merge(dst, src)
+}
+func (m *ListSessionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSessionsRequest.Size(m)
+}
+func (m *ListSessionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionsRequest proto.InternalMessageInfo
+
+type Session struct {
+	// / The session id, which is the session key of the client.
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// / The blob type negotiated for the session.
+	BlobType uint32 `protobuf:"varint,2,opt,name=blob_type,json=blobType,proto3" json:"blob_type,omitempty"`
+	// / The maximum number of updates the tower will accept for the session.
+	MaxUpdates uint32 `protobuf:"varint,3,opt,name=max_updates,json=maxUpdates,proto3" json:"max_updates,omitempty"`
+	// / The sequence number of the last update accepted by the tower.
+	LastApplied uint32 `protobuf:"varint,4,opt,name=last_applied,json=lastApplied,proto3" json:"last_applied,omitempty"`
+	// / The last applied sequence number echoed back by the client.
+	ClientLastApplied uint32 `protobuf:"varint,5,opt,name=client_last_applied,json=clientLastApplied,proto3" json:"client_last_applied,omitempty"`
+	// / The fee rate in sat/kw justice transactions are swept with.
+	SweepFeeRate int64 `protobuf:"varint,6,opt,name=sweep_fee_rate,json=sweepFeeRate,proto3" json:"sweep_fee_rate,omitempty"`
+	// / The fixed reward of the tower in satoshis.
+	RewardBase uint32 `protobuf:"varint,7,opt,name=reward_base,json=rewardBase,proto3" json:"reward_base,omitempty"`
+	// / The proportional reward of the tower in millionths.
+	RewardRate uint32 `protobuf:"varint,8,opt,name=reward_rate,json=rewardRate,proto3" json:"reward_rate,omitempty"`
+	// / The number of encrypted blobs stored for the session.
+	NumUpdates uint64 `protobuf:"varint,9,opt,name=num_updates,json=numUpdates,proto3" json:"num_updates,omitempty"`
+	// *
+	// The unix timestamp of the last time the session was created or updated by
+	// the client, or zero if unknown.
+	LastActivity         int64    `protobuf:"varint,10,opt,name=last_activity,json=lastActivity,proto3" json:"last_activity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Session) Reset()         { *m = Session{} }
+func (m *Session) String() string { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()    {}
+func (*Session) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{3}
+}
+func (m *Session) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Session.Unmarshal(m, b)
+}
+func (m *Session) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Session.Marshal(b, m, deterministic)
+}
+func (dst *Session) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Session.Merge(dst, src)
+}
+func (m *Session) XXX_Size() int {
+	return xxx_messageInfo_Session.Size(m)
+}
+func (m *Session) XXX_DiscardUnknown() {
+	xxx_messageInfo_Session.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Session proto.InternalMessageInfo
+
+func (m *Session) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Session) GetBlobType() uint32 {
+	if m != nil {
+		return m.BlobType
+	}
+	return 0
+}
+
+func (m *Session) GetMaxUpdates() uint32 {
+	if m != nil {
+		return m.MaxUpdates
+	}
+	return 0
+}
+
+func (m *Session) GetLastApplied() uint32 {
+	if m != nil {
+		return m.LastApplied
+	}
+	return 0
+}
+
+func (m *Session) GetClientLastApplied() uint32 {
+	if m != nil {
+		return m.ClientLastApplied
+	}
+	return 0
+}
+
+func (m *Session) GetSweepFeeRate() int64 {
+	if m != nil {
+		return m.SweepFeeRate
+	}
+	return 0
+}
+
+func (m *Session) GetRewardBase() uint32 {
+	if m != nil {
+		return m.RewardBase
+	}
+	return 0
+}
+
+func (m *Session) GetRewardRate() uint32 {
+	if m != nil {
+		return m.RewardRate
+	}
+	return 0
+}
+
+func (m *Session) GetNumUpdates() uint64 {
+	if m != nil {
+		return m.NumUpdates
+	}
+	return 0
+}
+
+func (m *Session) GetLastActivity() int64 {
+	if m != nil {
+		return m.LastActivity
+	}
+	return 0
+}
+
+type ListSessionsResponse struct {
+	// / The sessions negotiated with clients.
+	Sessions             []*Session `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListSessionsResponse) Reset()         { *m = ListSessionsResponse{} }
+func (m *ListSessionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsResponse) ProtoMessage()    {}
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{4}
+}
+func (m *ListSessionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionsResponse.Unmarshal(m, b)
+}
+func (m *ListSessionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListSessionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionsResponse.Merge(dst, src)
+}
+func (m *ListSessionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSessionsResponse.Size(m)
+}
+func (m *ListSessionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionsResponse proto.InternalMessageInfo
+
+func (m *ListSessionsResponse) GetSessions() []*Session {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+type BlacklistClientRequest struct {
+	// / The session id of the client to blacklist.
+	Id                   []byte   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlacklistClientRequest) Reset()         { *m = BlacklistClientRequest{} }
+func (m *BlacklistClientRequest) String() string { return proto.CompactTextString(m) }
+func (*BlacklistClientRequest) ProtoMessage()    {}
+func (*BlacklistClientRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{5}
+}
+func (m *BlacklistClientRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlacklistClientRequest.Unmarshal(m, b)
+}
+func (m *BlacklistClientRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlacklistClientRequest.Marshal(b, m, deterministic)
+}
+func (dst *BlacklistClientRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlacklistClientRequest.Merge(dst, src)
+}
+func (m *BlacklistClientRequest) XXX_Size() int {
+	return xxx_messageInfo_BlacklistClientRequest.Size(m)
+}
+func (m *BlacklistClientRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlacklistClientRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlacklistClientRequest proto.InternalMessageInfo
+
+func (m *BlacklistClientRequest) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+type BlacklistClientResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlacklistClientResponse) Reset()         { *m = BlacklistClientResponse{} }
+func (m *BlacklistClientResponse) String() string { return proto.CompactTextString(m) }
+func (*BlacklistClientResponse) ProtoMessage()    {}
+func (*BlacklistClientResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{6}
+}
+func (m *BlacklistClientResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlacklistClientResponse.Unmarshal(m, b)
+}
+func (m *BlacklistClientResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlacklistClientResponse.Marshal(b, m, deterministic)
+}
+func (dst *BlacklistClientResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlacklistClientResponse.Merge(dst, src)
+}
+func (m *BlacklistClientResponse) XXX_Size() int {
+	return xxx_messageInfo_BlacklistClientResponse.Size(m)
+}
+func (m *BlacklistClientResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlacklistClientResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlacklistClientResponse proto.InternalMessageInfo
+
+type UnblacklistClientRequest struct {
+	// / The session id of the client to remove from the blacklist.
+	Id                   []byte   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnblacklistClientRequest) Reset()         { *m = UnblacklistClientRequest{} }
+func (m *UnblacklistClientRequest) String() string { return proto.CompactTextString(m) }
+func (*UnblacklistClientRequest) ProtoMessage()    {}
+func (*UnblacklistClientRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{7}
+}
+func (m *UnblacklistClientRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnblacklistClientRequest.Unmarshal(m, b)
+}
+func (m *UnblacklistClientRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnblacklistClientRequest.Marshal(b, m, deterministic)
+}
+func (dst *UnblacklistClientRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnblacklistClientRequest.Merge(dst, src)
+}
+func (m *UnblacklistClientRequest) XXX_Size() int {
+	return xxx_messageInfo_UnblacklistClientRequest.Size(m)
+}
+func (m *UnblacklistClientRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnblacklistClientRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnblacklistClientRequest proto.InternalMessageInfo
+
+func (m *UnblacklistClientRequest) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+type UnblacklistClientResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnblacklistClientResponse) Reset()         { *m = UnblacklistClientResponse{} }
+func (m *UnblacklistClientResponse) String() string { return proto.CompactTextString(m) }
+func (*UnblacklistClientResponse) ProtoMessage()    {}
+func (*UnblacklistClientResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{8}
+}
+func (m *UnblacklistClientResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnblacklistClientResponse.Unmarshal(m, b)
+}
+func (m *UnblacklistClientResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnblacklistClientResponse.Marshal(b, m, deterministic)
+}
+func (dst *UnblacklistClientResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnblacklistClientResponse.Merge(dst, src)
+}
+func (m *UnblacklistClientResponse) XXX_Size() int {
+	return xxx_messageInfo_UnblacklistClientResponse.Size(m)
+}
+func (m *UnblacklistClientResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnblacklistClientResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnblacklistClientResponse proto.InternalMessageInfo
+
+type ListBlacklistedClientsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListBlacklistedClientsRequest) Reset()         { *m = ListBlacklistedClientsRequest{} }
+func (m *ListBlacklistedClientsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListBlacklistedClientsRequest) ProtoMessage()    {}
+func (*ListBlacklistedClientsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{9}
+}
+func (m *ListBlacklistedClientsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBlacklistedClientsRequest.Unmarshal(m, b)
+}
+func (m *ListBlacklistedClientsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBlacklistedClientsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListBlacklistedClientsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBlacklistedClientsRequest.Merge(dst, src)
+}
+func (m *ListBlacklistedClientsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListBlacklistedClientsRequest.Size(m)
+}
+func (m *ListBlacklistedClientsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBlacklistedClientsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBlacklistedClientsRequest proto.InternalMessageInfo
+
+type ListBlacklistedClientsResponse struct {
+	// / The session ids of all blacklisted clients.
+	Ids                  [][]byte `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListBlacklistedClientsResponse) Reset()         { *m = ListBlacklistedClientsResponse{} }
+func (m *ListBlacklistedClientsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListBlacklistedClientsResponse) ProtoMessage()    {}
+func (*ListBlacklistedClientsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{10}
+}
+func (m *ListBlacklistedClientsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBlacklistedClientsResponse.Unmarshal(m, b)
+}
+func (m *ListBlacklistedClientsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBlacklistedClientsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListBlacklistedClientsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBlacklistedClientsResponse.Merge(dst, src)
+}
+func (m *ListBlacklistedClientsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListBlacklistedClientsResponse.Size(m)
+}
+func (m *ListBlacklistedClientsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBlacklistedClientsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBlacklistedClientsResponse proto.InternalMessageInfo
+
+func (m *ListBlacklistedClientsResponse) GetIds() [][]byte {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+type PruneSessionsRequest struct {
+	// *
+	// Sessions that haven't been updated by their client for at least this
+	// number of seconds are deleted along with their encrypted blobs.
+	InactiveSecs         uint64   `protobuf:"varint,1,opt,name=inactive_secs,json=inactiveSecs,proto3" json:"inactive_secs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PruneSessionsRequest) Reset()         { *m = PruneSessionsRequest{} }
+func (m *PruneSessionsRequest) String() string { return proto.CompactTextString(m) }
+func (*PruneSessionsRequest) ProtoMessage()    {}
+func (*PruneSessionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{11}
+}
+func (m *PruneSessionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruneSessionsRequest.Unmarshal(m, b)
+}
+func (m *PruneSessionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruneSessionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *PruneSessionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruneSessionsRequest.Merge(dst, src)
+}
+func (m *PruneSessionsRequest) XXX_Size() int {
+	return xxx_messageInfo_PruneSessionsRequest.Size(m)
+}
+func (m *PruneSessionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruneSessionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PruneSessionsRequest proto.InternalMessageInfo
+
+func (m *PruneSessionsRequest) GetInactiveSecs() uint64 {
+	if m != nil {
+		return m.InactiveSecs
+	}
+	return 0
+}
+
+type PruneSessionsResponse struct {
+	// / The session ids of the deleted sessions.
+	Ids                  [][]byte `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PruneSessionsResponse) Reset()         { *m = PruneSessionsResponse{} }
+func (m *PruneSessionsResponse) String() string { return proto.CompactTextString(m) }
+func (*PruneSessionsResponse) ProtoMessage()    {}
+func (*PruneSessionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{12}
+}
+func (m *PruneSessionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruneSessionsResponse.Unmarshal(m, b)
+}
+func (m *PruneSessionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruneSessionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *PruneSessionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruneSessionsResponse.Merge(dst, src)
+}
+func (m *PruneSessionsResponse) XXX_Size() int {
+	return xxx_messageInfo_PruneSessionsResponse.Size(m)
+}
+func (m *PruneSessionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruneSessionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PruneSessionsResponse proto.InternalMessageInfo
+
+func (m *PruneSessionsResponse) GetIds() [][]byte {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+type PolicyBounds struct {
+	// *
+	// The largest number of updates a client may request for a single session,
+	// or zero if unbounded.
+	MaxUpdates uint32 `protobuf:"varint,1,opt,name=max_updates,json=maxUpdates,proto3" json:"max_updates,omitempty"`
+	// / The minimum fixed reward in satoshis of reward sessions.
+	MinRewardBase uint32 `protobuf:"varint,2,opt,name=min_reward_base,json=minRewardBase,proto3" json:"min_reward_base,omitempty"`
+	// *
+	// The maximum fixed reward in satoshis of reward sessions, or zero if
+	// unbounded.
+	MaxRewardBase uint32 `protobuf:"varint,3,opt,name=max_reward_base,json=maxRewardBase,proto3" json:"max_reward_base,omitempty"`
+	// / The minimum proportional reward in millionths of reward sessions.
+	MinRewardRate uint32 `protobuf:"varint,4,opt,name=min_reward_rate,json=minRewardRate,proto3" json:"min_reward_rate,omitempty"`
+	// *
+	// The maximum proportional reward in millionths of reward sessions, or zero
+	// if unbounded.
+	MaxRewardRate uint32 `protobuf:"varint,5,opt,name=max_reward_rate,json=maxRewardRate,proto3" json:"max_reward_rate,omitempty"`
+	// *
+	// The minimum fee rate in sat/kw of justice transactions, enforced in
+	// addition to the relay fee of the chain backend.
+	MinSweepFeeRate int64 `protobuf:"varint,6,opt,name=min_sweep_fee_rate,json=minSweepFeeRate,proto3" json:"min_sweep_fee_rate,omitempty"`
+	// *
+	// The maximum fee rate in sat/kw of justice transactions, or zero if
+	// unbounded.
+	MaxSweepFeeRate int64 `protobuf:"varint,7,opt,name=max_sweep_fee_rate,json=maxSweepFeeRate,proto3" json:"max_sweep_fee_rate,omitempty"`
+	// *
+	// The blob types clients may negotiate. If empty, all blob types supported
+	// by the tower are accepted.
+	BlobTypes            []uint32 `protobuf:"varint,8,rep,packed,name=blob_types,json=blobTypes,proto3" json:"blob_types,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyBounds) Reset()         { *m = PolicyBounds{} }
+func (m *PolicyBounds) String() string { return proto.CompactTextString(m) }
+func (*PolicyBounds) ProtoMessage()    {}
+func (*PolicyBounds) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{13}
+}
+func (m *PolicyBounds) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyBounds.Unmarshal(m, b)
+}
+func (m *PolicyBounds) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyBounds.Marshal(b, m, deterministic)
+}
+func (dst *PolicyBounds) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyBounds.Merge(dst, src)
+}
+func (m *PolicyBounds) XXX_Size() int {
+	return xxx_messageInfo_PolicyBounds.Size(m)
+}
+func (m *PolicyBounds) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyBounds.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyBounds proto.InternalMessageInfo
+
+func (m *PolicyBounds) GetMaxUpdates() uint32 {
+	if m != nil {
+		return m.MaxUpdates
+	}
+	return 0
+}
+
+func (m *PolicyBounds) GetMinRewardBase() uint32 {
+	if m != nil {
+		return m.MinRewardBase
+	}
+	return 0
+}
+
+func (m *PolicyBounds) GetMaxRewardBase() uint32 {
+	if m != nil {
+		return m.MaxRewardBase
+	}
+	return 0
+}
+
+func (m *PolicyBounds) GetMinRewardRate() uint32 {
+	if m != nil {
+		return m.MinRewardRate
+	}
+	return 0
+}
+
+func (m *PolicyBounds) GetMaxRewardRate() uint32 {
+	if m != nil {
+		return m.MaxRewardRate
+	}
+	return 0
+}
+
+func (m *PolicyBounds) GetMinSweepFeeRate() int64 {
+	if m != nil {
+		return m.MinSweepFeeRate
+	}
+	return 0
+}
+
+func (m *PolicyBounds) GetMaxSweepFeeRate() int64 {
+	if m != nil {
+		return m.MaxSweepFeeRate
+	}
+	return 0
+}
+
+func (m *PolicyBounds) GetBlobTypes() []uint32 {
+	if m != nil {
+		return m.BlobTypes
+	}
+	return nil
+}
+
+type GetPolicyBoundsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPolicyBoundsRequest) Reset()         { *m = GetPolicyBoundsRequest{} }
+func (m *GetPolicyBoundsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPolicyBoundsRequest) ProtoMessage()    {}
+func (*GetPolicyBoundsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{14}
+}
+func (m *GetPolicyBoundsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPolicyBoundsRequest.Unmarshal(m, b)
+}
+func (m *GetPolicyBoundsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPolicyBoundsRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetPolicyBoundsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPolicyBoundsRequest.Merge(dst, src)
+}
+func (m *GetPolicyBoundsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPolicyBoundsRequest.Size(m)
+}
+func (m *GetPolicyBoundsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPolicyBoundsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPolicyBoundsRequest proto.InternalMessageInfo
+
+type SetPolicyBoundsRequest struct {
+	// / The bounds replacing those currently enforced by the tower.
+	Bounds               *PolicyBounds `protobuf:"bytes,1,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *SetPolicyBoundsRequest) Reset()         { *m = SetPolicyBoundsRequest{} }
+func (m *SetPolicyBoundsRequest) String() string { return proto.CompactTextString(m) }
+func (*SetPolicyBoundsRequest) ProtoMessage()    {}
+func (*SetPolicyBoundsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{15}
+}
+func (m *SetPolicyBoundsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPolicyBoundsRequest.Unmarshal(m, b)
+}
+func (m *SetPolicyBoundsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPolicyBoundsRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetPolicyBoundsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPolicyBoundsRequest.Merge(dst, src)
+}
+func (m *SetPolicyBoundsRequest) XXX_Size() int {
+	return xxx_messageInfo_SetPolicyBoundsRequest.Size(m)
+}
+func (m *SetPolicyBoundsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPolicyBoundsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPolicyBoundsRequest proto.InternalMessageInfo
+
+func (m *SetPolicyBoundsRequest) GetBounds() *PolicyBounds {
+	if m != nil {
+		return m.Bounds
+	}
+	return nil
+}
+
+type SetPolicyBoundsResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetPolicyBoundsResponse) Reset()         { *m = SetPolicyBoundsResponse{} }
+func (m *SetPolicyBoundsResponse) String() string { return proto.CompactTextString(m) }
+func (*SetPolicyBoundsResponse) ProtoMessage()    {}
+func (*SetPolicyBoundsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{16}
+}
+func (m *SetPolicyBoundsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPolicyBoundsResponse.Unmarshal(m, b)
+}
+func (m *SetPolicyBoundsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPolicyBoundsResponse.Marshal(b, m, deterministic)
+}
+func (dst *SetPolicyBoundsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPolicyBoundsResponse.Merge(dst, src)
+}
+func (m *SetPolicyBoundsResponse) XXX_Size() int {
+	return xxx_messageInfo_SetPolicyBoundsResponse.Size(m)
+}
+func (m *SetPolicyBoundsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPolicyBoundsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPolicyBoundsResponse proto.InternalMessageInfo
+
+type ListSessionPoliciesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSessionPoliciesRequest) Reset()         { *m = ListSessionPoliciesRequest{} }
+func (m *ListSessionPoliciesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSessionPoliciesRequest) ProtoMessage()    {}
+func (*ListSessionPoliciesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{17}
+}
+func (m *ListSessionPoliciesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionPoliciesRequest.Unmarshal(m, b)
+}
+func (m *ListSessionPoliciesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionPoliciesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListSessionPoliciesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionPoliciesRequest.Merge(dst, src)
+}
+func (m *ListSessionPoliciesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSessionPoliciesRequest.Size(m)
+}
+func (m *ListSessionPoliciesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionPoliciesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionPoliciesRequest proto.InternalMessageInfo
+
+type SessionPolicy struct {
+	// / The blob type negotiated under the policy.
+	BlobType uint32 `protobuf:"varint,1,opt,name=blob_type,json=blobType,proto3" json:"blob_type,omitempty"`
+	// / The maximum number of updates of sessions negotiated under the policy.
+	MaxUpdates uint32 `protobuf:"varint,2,opt,name=max_updates,json=maxUpdates,proto3" json:"max_updates,omitempty"`
+	// / The fee rate in sat/kw justice transactions are swept with.
+	SweepFeeRate int64 `protobuf:"varint,3,opt,name=sweep_fee_rate,json=sweepFeeRate,proto3" json:"sweep_fee_rate,omitempty"`
+	// / The fixed reward of the tower in satoshis.
+	RewardBase uint32 `protobuf:"varint,4,opt,name=reward_base,json=rewardBase,proto3" json:"reward_base,omitempty"`
+	// / The proportional reward of the tower in millionths.
+	RewardRate uint32 `protobuf:"varint,5,opt,name=reward_rate,json=rewardRate,proto3" json:"reward_rate,omitempty"`
+	// / The number of sessions negotiated under the policy.
+	NumSessions          uint64   `protobuf:"varint,6,opt,name=num_sessions,json=numSessions,proto3" json:"num_sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionPolicy) Reset()         { *m = SessionPolicy{} }
+func (m *SessionPolicy) String() string { return proto.CompactTextString(m) }
+func (*SessionPolicy) ProtoMessage()    {}
+func (*SessionPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{18}
+}
+func (m *SessionPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionPolicy.Unmarshal(m, b)
+}
+func (m *SessionPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionPolicy.Marshal(b, m, deterministic)
+}
+func (dst *SessionPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionPolicy.Merge(dst, src)
+}
+func (m *SessionPolicy) XXX_Size() int {
+	return xxx_messageInfo_SessionPolicy.Size(m)
+}
+func (m *SessionPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionPolicy proto.InternalMessageInfo
+
+func (m *SessionPolicy) GetBlobType() uint32 {
+	if m != nil {
+		return m.BlobType
+	}
+	return 0
+}
+
+func (m *SessionPolicy) GetMaxUpdates() uint32 {
+	if m != nil {
+		return m.MaxUpdates
+	}
+	return 0
+}
+
+func (m *SessionPolicy) GetSweepFeeRate() int64 {
+	if m != nil {
+		return m.SweepFeeRate
+	}
+	return 0
+}
+
+func (m *SessionPolicy) GetRewardBase() uint32 {
+	if m != nil {
+		return m.RewardBase
+	}
+	return 0
+}
+
+func (m *SessionPolicy) GetRewardRate() uint32 {
+	if m != nil {
+		return m.RewardRate
+	}
+	return 0
+}
+
+func (m *SessionPolicy) GetNumSessions() uint64 {
+	if m != nil {
+		return m.NumSessions
+	}
+	return 0
+}
+
+type ListSessionPoliciesResponse struct {
+	// *
+	// The distinct policies negotiated by clients, ordered by descending number
+	// of sessions.
+	Policies             []*SessionPolicy `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListSessionPoliciesResponse) Reset()         { *m = ListSessionPoliciesResponse{} }
+func (m *ListSessionPoliciesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSessionPoliciesResponse) ProtoMessage()    {}
+func (*ListSessionPoliciesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tower_309b50de62ed38b0, []int{19}
+}
+func (m *ListSessionPoliciesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionPoliciesResponse.Unmarshal(m, b)
+}
+func (m *ListSessionPoliciesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionPoliciesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListSessionPoliciesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionPoliciesResponse.Merge(dst, src)
+}
+func (m *ListSessionPoliciesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSessionPoliciesResponse.Size(m)
+}
+func (m *ListSessionPoliciesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionPoliciesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionPoliciesResponse proto.InternalMessageInfo
+
+func (m *ListSessionPoliciesResponse) GetPolicies() []*SessionPolicy {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*GetInfoRequest)(nil), "towerrpc.GetInfoRequest")
+	proto.RegisterType((*GetInfoResponse)(nil), "towerrpc.GetInfoResponse")
+	proto.RegisterType((*ListSessionsRequest)(nil), "towerrpc.ListSessionsRequest")
+	proto.RegisterType((*Session)(nil), "towerrpc.Session")
+	proto.RegisterType((*ListSessionsResponse)(nil), "towerrpc.ListSessionsResponse")
+	proto.RegisterType((*BlacklistClientRequest)(nil), "towerrpc.BlacklistClientRequest")
+	proto.RegisterType((*BlacklistClientResponse)(nil), "towerrpc.BlacklistClientResponse")
+	proto.RegisterType((*UnblacklistClientRequest)(nil), "towerrpc.UnblacklistClientRequest")
+	proto.RegisterType((*UnblacklistClientResponse)(nil), "towerrpc.UnblacklistClientResponse")
+	proto.RegisterType((*ListBlacklistedClientsRequest)(nil), "towerrpc.ListBlacklistedClientsRequest")
+	proto.RegisterType((*ListBlacklistedClientsResponse)(nil), "towerrpc.ListBlacklistedClientsResponse")
+	proto.RegisterType((*PruneSessionsRequest)(nil), "towerrpc.PruneSessionsRequest")
+	proto.RegisterType((*PruneSessionsResponse)(nil), "towerrpc.PruneSessionsResponse")
+	proto.RegisterType((*PolicyBounds)(nil), "towerrpc.PolicyBounds")
+	proto.RegisterType((*GetPolicyBoundsRequest)(nil), "towerrpc.GetPolicyBoundsRequest")
+	proto.RegisterType((*SetPolicyBoundsRequest)(nil), "towerrpc.SetPolicyBoundsRequest")
+	proto.RegisterType((*SetPolicyBoundsResponse)(nil), "towerrpc.SetPolicyBoundsResponse")
+	proto.RegisterType((*ListSessionPoliciesRequest)(nil), "towerrpc.ListSessionPoliciesRequest")
+	proto.RegisterType((*SessionPolicy)(nil), "towerrpc.SessionPolicy")
+	proto.RegisterType((*ListSessionPoliciesResponse)(nil), "towerrpc.ListSessionPoliciesResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// TowerClient is the client API for Tower service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TowerClient interface {
+	// *
+	// GetInfo returns the identity of the tower, along with a summary of the
+	// resources occupied by its clients and the breaches it has handled.
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	// *
+	// ListSessions returns every session negotiated with a client, along with
+	// the number of encrypted blobs stored for it.
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// *
+	// BlacklistClient refuses any further connections from the given client,
+	// and drops its connection if it is currently connected. Sessions of the
+	// client continue to be watched.
+	BlacklistClient(ctx context.Context, in *BlacklistClientRequest, opts ...grpc.CallOption) (*BlacklistClientResponse, error)
+	// *
+	// UnblacklistClient allows a previously blacklisted client to connect to the
+	// tower once again.
+	UnblacklistClient(ctx context.Context, in *UnblacklistClientRequest, opts ...grpc.CallOption) (*UnblacklistClientResponse, error)
+	// *
+	// ListBlacklistedClients returns the session ids of all blacklisted clients.
+	ListBlacklistedClients(ctx context.Context, in *ListBlacklistedClientsRequest, opts ...grpc.CallOption) (*ListBlacklistedClientsResponse, error)
+	// *
+	// PruneSessions deletes all sessions that haven't been updated by their
+	// client within the given period, freeing the storage of their encrypted
+	// blobs.
+	PruneSessions(ctx context.Context, in *PruneSessionsRequest, opts ...grpc.CallOption) (*PruneSessionsResponse, error)
+	// *
+	// GetPolicyBounds returns the bounds currently restricting the policies
+	// clients may negotiate new sessions with.
+	GetPolicyBounds(ctx context.Context, in *GetPolicyBoundsRequest, opts ...grpc.CallOption) (*PolicyBounds, error)
+	// *
+	// SetPolicyBounds replaces the bounds restricting the policies clients may
+	// negotiate new sessions with, taking effect immediately. Sessions that have
+	// already been negotiated continue to be honored.
+	SetPolicyBounds(ctx context.Context, in *SetPolicyBoundsRequest, opts ...grpc.CallOption) (*SetPolicyBoundsResponse, error)
+	// *
+	// ListSessionPolicies returns the distinct policies negotiated by clients,
+	// along with the number of sessions using each.
+	ListSessionPolicies(ctx context.Context, in *ListSessionPoliciesRequest, opts ...grpc.CallOption) (*ListSessionPoliciesResponse, error)
+}
+
+type towerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTowerClient(cc *grpc.ClientConn) TowerClient {
+	return &towerClient{cc}
+}
+
+func (c *towerClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
+	out := new(GetInfoResponse)
+	err := c.cc.Invoke(ctx, "/towerrpc.Tower/GetInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *towerClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, "/towerrpc.Tower/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *towerClient) BlacklistClient(ctx context.Context, in *BlacklistClientRequest, opts ...grpc.CallOption) (*BlacklistClientResponse, error) {
+	out := new(BlacklistClientResponse)
+	err := c.cc.Invoke(ctx, "/towerrpc.Tower/BlacklistClient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *towerClient) UnblacklistClient(ctx context.Context, in *UnblacklistClientRequest, opts ...grpc.CallOption) (*UnblacklistClientResponse, error) {
+	out := new(UnblacklistClientResponse)
+	err := c.cc.Invoke(ctx, "/towerrpc.Tower/UnblacklistClient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *towerClient) ListBlacklistedClients(ctx context.Context, in *ListBlacklistedClientsRequest, opts ...grpc.CallOption) (*ListBlacklistedClientsResponse, error) {
+	out := new(ListBlacklistedClientsResponse)
+	err := c.cc.Invoke(ctx, "/towerrpc.Tower/ListBlacklistedClients", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *towerClient) PruneSessions(ctx context.Context, in *PruneSessionsRequest, opts ...grpc.CallOption) (*PruneSessionsResponse, error) {
+	out := new(PruneSessionsResponse)
+	err := c.cc.Invoke(ctx, "/towerrpc.Tower/PruneSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *towerClient) GetPolicyBounds(ctx context.Context, in *GetPolicyBoundsRequest, opts ...grpc.CallOption) (*PolicyBounds, error) {
+	out := new(PolicyBounds)
+	err := c.cc.Invoke(ctx, "/towerrpc.Tower/GetPolicyBounds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *towerClient) SetPolicyBounds(ctx context.Context, in *SetPolicyBoundsRequest, opts ...grpc.CallOption) (*SetPolicyBoundsResponse, error) {
+	out := new(SetPolicyBoundsResponse)
+	err := c.cc.Invoke(ctx, "/towerrpc.Tower/SetPolicyBounds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *towerClient) ListSessionPolicies(ctx context.Context, in *ListSessionPoliciesRequest, opts ...grpc.CallOption) (*ListSessionPoliciesResponse, error) {
+	out := new(ListSessionPoliciesResponse)
+	err := c.cc.Invoke(ctx, "/towerrpc.Tower/ListSessionPolicies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TowerServer is the server API for Tower service.
+type TowerServer interface {
+	// *
+	// GetInfo returns the identity of the tower, along with a summary of the
+	// resources occupied by its clients and the breaches it has handled.
+	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
+	// *
+	// ListSessions returns every session negotiated with a client, along with
+	// the number of encrypted blobs stored for it.
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// *
+	// BlacklistClient refuses any further connections from the given client,
+	// and drops its connection if it is currently connected. Sessions of the
+	// client continue to be watched.
+	BlacklistClient(context.Context, *BlacklistClientRequest) (*BlacklistClientResponse, error)
+	// *
+	// UnblacklistClient allows a previously blacklisted client to connect to the
+	// tower once again.
+	UnblacklistClient(context.Context, *UnblacklistClientRequest) (*UnblacklistClientResponse, error)
+	// *
+	// ListBlacklistedClients returns the session ids of all blacklisted clients.
+	ListBlacklistedClients(context.Context, *ListBlacklistedClientsRequest) (*ListBlacklistedClientsResponse, error)
+	// *
+	// PruneSessions deletes all sessions that haven't been updated by their
+	// client within the given period, freeing the storage of their encrypted
+	// blobs.
+	PruneSessions(context.Context, *PruneSessionsRequest) (*PruneSessionsResponse, error)
+	// *
+	// GetPolicyBounds returns the bounds currently restricting the policies
+	// clients may negotiate new sessions with.
+	GetPolicyBounds(context.Context, *GetPolicyBoundsRequest) (*PolicyBounds, error)
+	// *
+	// SetPolicyBounds replaces the bounds restricting the policies clients may
+	// negotiate new sessions with, taking effect immediately. Sessions that have
+	// already been negotiated continue to be honored.
+	SetPolicyBounds(context.Context, *SetPolicyBoundsRequest) (*SetPolicyBoundsResponse, error)
+	// *
+	// ListSessionPolicies returns the distinct policies negotiated by clients,
+	// along with the number of sessions using each.
+	ListSessionPolicies(context.Context, *ListSessionPoliciesRequest) (*ListSessionPoliciesResponse, error)
+}
+
+func RegisterTowerServer(s *grpc.Server, srv TowerServer) {
+	s.RegisterService(&_Tower_serviceDesc, srv)
+}
+
+func _Tower_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TowerServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/towerrpc.Tower/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TowerServer).GetInfo(ctx, req.(*GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tower_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TowerServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/towerrpc.Tower/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TowerServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tower_BlacklistClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlacklistClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TowerServer).BlacklistClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/towerrpc.Tower/BlacklistClient",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TowerServer).BlacklistClient(ctx, req.(*BlacklistClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tower_UnblacklistClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnblacklistClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TowerServer).UnblacklistClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/towerrpc.Tower/UnblacklistClient",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TowerServer).UnblacklistClient(ctx, req.(*UnblacklistClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tower_ListBlacklistedClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlacklistedClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TowerServer).ListBlacklistedClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/towerrpc.Tower/ListBlacklistedClients",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TowerServer).ListBlacklistedClients(ctx, req.(*ListBlacklistedClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tower_PruneSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TowerServer).PruneSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/towerrpc.Tower/PruneSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TowerServer).PruneSessions(ctx, req.(*PruneSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tower_GetPolicyBounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPolicyBoundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TowerServer).GetPolicyBounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/towerrpc.Tower/GetPolicyBounds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TowerServer).GetPolicyBounds(ctx, req.(*GetPolicyBoundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tower_SetPolicyBounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPolicyBoundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TowerServer).SetPolicyBounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/towerrpc.Tower/SetPolicyBounds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TowerServer).SetPolicyBounds(ctx, req.(*SetPolicyBoundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tower_ListSessionPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TowerServer).ListSessionPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/towerrpc.Tower/ListSessionPolicies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TowerServer).ListSessionPolicies(ctx, req.(*ListSessionPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Tower_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "towerrpc.Tower",
+	HandlerType: (*TowerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetInfo",
+			Handler:    _Tower_GetInfo_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _Tower_ListSessions_Handler,
+		},
+		{
+			MethodName: "BlacklistClient",
+			Handler:    _Tower_BlacklistClient_Handler,
+		},
+		{
+			MethodName: "UnblacklistClient",
+			Handler:    _Tower_UnblacklistClient_Handler,
+		},
+		{
+			MethodName: "ListBlacklistedClients",
+			Handler:    _Tower_ListBlacklistedClients_Handler,
+		},
+		{
+			MethodName: "PruneSessions",
+			Handler:    _Tower_PruneSessions_Handler,
+		},
+		{
+			MethodName: "GetPolicyBounds",
+			Handler:    _Tower_GetPolicyBounds_Handler,
+		},
+		{
+			MethodName: "SetPolicyBounds",
+			Handler:    _Tower_SetPolicyBounds_Handler,
+		},
+		{
+			MethodName: "ListSessionPolicies",
+			Handler:    _Tower_ListSessionPolicies_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "towerrpc/tower.proto",
+}
+
+func init() { proto.RegisterFile("towerrpc/tower.proto", fileDescriptor_tower_309b50de62ed38b0) }
+
+var fileDescriptor_tower_309b50de62ed38b0 = []byte{
+	// 978 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x96, 0xdd, 0x6e, 0xdb, 0x36,
+	0x18, 0x86, 0xe1, 0x7f, 0xfb, 0x8b, 0x1d, 0x27, 0x6c, 0xea, 0x28, 0x4a, 0xd3, 0x38, 0x6a, 0xb7,
+	0xba, 0x2b, 0x6a, 0x03, 0xe9, 0xe1, 0x80, 0x01, 0xf3, 0xb0, 0x75, 0xc3, 0x3a, 0x20, 0x90, 0xdb,
+	0x1d, 0x0c, 0x03, 0x04, 0xfd, 0x30, 0x0b, 0x11, 0x89, 0xd2, 0x44, 0x69, 0xb1, 0x7b, 0x1d, 0xbb,
+	0xa8, 0x5c, 0xc2, 0x2e, 0x67, 0x20, 0x45, 0x59, 0xbf, 0x4e, 0x7a, 0x66, 0xbf, 0x7c, 0xf4, 0x91,
+	0xfc, 0xf4, 0xf2, 0xa5, 0xe0, 0x28, 0xf2, 0xef, 0x70, 0x18, 0x06, 0xf6, 0x42, 0xfc, 0x98, 0x07,
+	0xa1, 0x1f, 0xf9, 0xa8, 0x9f, 0xaa, 0xda, 0x01, 0xec, 0xbf, 0xc7, 0xd1, 0x2f, 0xf4, 0xda, 0xd7,
+	0xf1, 0xdf, 0x31, 0x66, 0x91, 0x76, 0xdf, 0x84, 0xf1, 0x56, 0x62, 0x81, 0x4f, 0x19, 0x46, 0x13,
+	0xe8, 0x06, 0xb1, 0x75, 0x8b, 0x37, 0x4a, 0x63, 0xda, 0x98, 0x0d, 0x75, 0xf9, 0x0f, 0x3d, 0x83,
+	0x81, 0x4b, 0x58, 0x84, 0x29, 0x0e, 0x99, 0xd2, 0x9c, 0xb6, 0x66, 0x03, 0x3d, 0x13, 0xd0, 0x05,
+	0x0c, 0x69, 0xec, 0x19, 0x0c, 0x33, 0x46, 0x7c, 0xca, 0x94, 0xd6, 0xb4, 0x31, 0x6b, 0xeb, 0x7b,
+	0x34, 0xf6, 0x56, 0x52, 0x42, 0xe7, 0xc0, 0xff, 0x1a, 0x71, 0xe0, 0x98, 0x11, 0x66, 0x4a, 0x5b,
+	0x10, 0x40, 0x63, 0xef, 0x53, 0xa2, 0xf0, 0x1a, 0xc9, 0xa0, 0x61, 0x6d, 0x38, 0xd1, 0x49, 0x6a,
+	0x24, 0xda, 0x92, 0x4b, 0xe8, 0x18, 0x7a, 0x8e, 0x65, 0x30, 0xf2, 0x19, 0x2b, 0x5d, 0x31, 0xda,
+	0x75, 0xac, 0x15, 0xf9, 0x8c, 0xd1, 0x2b, 0x18, 0xf3, 0xe2, 0x96, 0x6b, 0xda, 0xb7, 0x62, 0x55,
+	0x8e, 0xd2, 0x13, 0xc0, 0x3e, 0x8d, 0xbd, 0x65, 0xa6, 0xa2, 0xd7, 0x70, 0xe0, 0x99, 0x91, 0x7d,
+	0x83, 0x1d, 0xc3, 0x0a, 0xb1, 0x69, 0xdf, 0x60, 0xa6, 0xf4, 0x05, 0x39, 0x96, 0xfa, 0x52, 0xca,
+	0xe8, 0x0d, 0x1c, 0x06, 0x31, 0x25, 0xac, 0xc0, 0x0e, 0x04, 0x7b, 0x90, 0x0e, 0xa4, 0xb0, 0xf6,
+	0x14, 0x9e, 0x7c, 0x20, 0x2c, 0x4a, 0x77, 0x9b, 0x76, 0xf8, 0xbf, 0x26, 0xf4, 0xa4, 0x86, 0xf6,
+	0xa1, 0x49, 0x1c, 0xd9, 0xd5, 0x26, 0x71, 0xd0, 0x29, 0x0c, 0x2c, 0xd7, 0xb7, 0x8c, 0x68, 0x13,
+	0x60, 0xa5, 0x39, 0x6d, 0xcc, 0x46, 0x7a, 0x9f, 0x0b, 0x1f, 0x37, 0x01, 0xe6, 0xdd, 0xf2, 0xcc,
+	0xf5, 0xb6, 0x5b, 0x2d, 0x31, 0x0c, 0x9e, 0xb9, 0xce, 0x75, 0xcb, 0x35, 0x59, 0x64, 0x98, 0x41,
+	0xe0, 0x12, 0xec, 0x88, 0x7e, 0x8e, 0xf4, 0x3d, 0xae, 0x7d, 0x9f, 0x48, 0x68, 0x0e, 0x4f, 0x6c,
+	0x97, 0x60, 0x1a, 0x19, 0x05, 0xb2, 0x23, 0xc8, 0xc3, 0x64, 0xe8, 0x43, 0x8e, 0x7f, 0x09, 0xfb,
+	0xec, 0x0e, 0xe3, 0xc0, 0xb8, 0xc6, 0xd8, 0x08, 0xcd, 0x28, 0x69, 0x72, 0x4b, 0x1f, 0x0a, 0xf5,
+	0x27, 0x8c, 0x75, 0x33, 0x12, 0x2b, 0x0b, 0xf1, 0x9d, 0x19, 0x3a, 0x86, 0x65, 0x32, 0x2c, 0xda,
+	0x3c, 0xd2, 0x21, 0x91, 0x96, 0x26, 0xcb, 0x03, 0xa2, 0x46, 0x3f, 0x0f, 0xa4, 0x15, 0xf2, 0x4e,
+	0x18, 0x54, 0x9c, 0xf0, 0x02, 0x46, 0xc9, 0x8a, 0xed, 0x88, 0xfc, 0x43, 0xa2, 0x8d, 0x02, 0xc9,
+	0x3a, 0xc4, 0xe6, 0xa4, 0xa6, 0xfd, 0x08, 0x47, 0xc5, 0x8e, 0x4b, 0x03, 0xbf, 0x85, 0xfe, 0xd6,
+	0x86, 0x8d, 0x69, 0x6b, 0xb6, 0x77, 0x79, 0x38, 0x4f, 0xcf, 0xc0, 0x5c, 0xd2, 0xfa, 0x16, 0xd1,
+	0x66, 0x30, 0xd9, 0xfa, 0xe3, 0x07, 0xd1, 0x12, 0xf9, 0xee, 0xca, 0xef, 0x4b, 0x3b, 0x81, 0xe3,
+	0x0a, 0x99, 0xcc, 0xa9, 0x7d, 0x03, 0xca, 0x27, 0x6a, 0x7d, 0x59, 0x99, 0x53, 0x38, 0xa9, 0x61,
+	0x65, 0xa1, 0x73, 0x38, 0xe3, 0x9b, 0xca, 0x39, 0x36, 0x01, 0xb6, 0x86, 0xba, 0x84, 0xe7, 0xbb,
+	0x00, 0xb9, 0xff, 0x03, 0x68, 0x11, 0x27, 0xd9, 0xfa, 0x50, 0xe7, 0x3f, 0xb5, 0x6f, 0xe1, 0xe8,
+	0x2a, 0x8c, 0x29, 0x2e, 0x99, 0x93, 0xb7, 0x99, 0x50, 0xd1, 0x63, 0x6c, 0x30, 0x6c, 0x33, 0xb1,
+	0xc8, 0xb6, 0x3e, 0x4c, 0xc5, 0x15, 0xb6, 0x99, 0xf6, 0x1a, 0x9e, 0x96, 0x1e, 0xde, 0x39, 0xcf,
+	0x7d, 0x13, 0x86, 0x57, 0xbe, 0x4b, 0xec, 0xcd, 0xd2, 0x8f, 0xa9, 0xc3, 0xca, 0x26, 0x6e, 0x54,
+	0x4c, 0xfc, 0x35, 0x8c, 0x3d, 0x42, 0x8d, 0xbc, 0x9f, 0x92, 0x83, 0x30, 0xf2, 0x08, 0xd5, 0x33,
+	0x4b, 0x71, 0xce, 0x5c, 0x17, 0xb8, 0x96, 0xe4, 0xcc, 0x75, 0x89, 0xcb, 0xea, 0x09, 0xfb, 0xb5,
+	0x4b, 0xf5, 0x84, 0x03, 0x8b, 0xf5, 0x04, 0xd7, 0x29, 0xd5, 0x13, 0xdc, 0x1b, 0x40, 0xbc, 0x5e,
+	0xed, 0xa9, 0xe0, 0x33, 0xad, 0xf2, 0x07, 0x83, 0xc3, 0xe6, 0xba, 0x0c, 0xf7, 0x24, 0x6c, 0xae,
+	0x0b, 0xf0, 0x19, 0xc0, 0xf6, 0xf0, 0xf3, 0x04, 0x6a, 0xcd, 0x46, 0xfa, 0x20, 0x3d, 0xfd, 0x4c,
+	0x53, 0x60, 0xf2, 0x1e, 0x47, 0xf9, 0x66, 0xa6, 0x06, 0xf8, 0x19, 0x26, 0xab, 0xda, 0x11, 0x34,
+	0x87, 0xae, 0x25, 0x04, 0xd1, 0xe8, 0xbd, 0xcb, 0x49, 0x66, 0xfb, 0x02, 0x2e, 0x29, 0xee, 0xe7,
+	0x4a, 0x25, 0x69, 0xc3, 0x67, 0xa0, 0xe6, 0xce, 0x96, 0x40, 0x08, 0xce, 0x42, 0xad, 0x01, 0xa3,
+	0xfc, 0xd0, 0xa6, 0x18, 0x65, 0x8d, 0x87, 0xa3, 0xac, 0x59, 0x71, 0x41, 0x35, 0x77, 0x5a, 0x8f,
+	0xe7, 0x4e, 0xfb, 0xb1, 0xdc, 0xe9, 0x54, 0x72, 0xa7, 0x7c, 0x49, 0x75, 0x2b, 0x97, 0x94, 0xa6,
+	0xc3, 0x69, 0xed, 0xc6, 0xa5, 0xe7, 0xdf, 0x41, 0x3f, 0x90, 0x9a, 0xcc, 0x96, 0xe3, 0x4a, 0xb6,
+	0x24, 0x2d, 0xd1, 0xb7, 0xe0, 0xe5, 0xbf, 0x5d, 0xe8, 0x7c, 0xe4, 0x10, 0xfa, 0x0e, 0x7a, 0xf2,
+	0xba, 0x45, 0x4a, 0xf6, 0x5c, 0xf1, 0x52, 0x56, 0x4f, 0x6a, 0x46, 0xe4, 0xf4, 0xbf, 0xc1, 0x30,
+	0x1f, 0x79, 0xe8, 0x2c, 0x43, 0x6b, 0x2e, 0x1f, 0xf5, 0xf9, 0xae, 0x61, 0x59, 0xee, 0x77, 0x18,
+	0x97, 0x02, 0x0d, 0x4d, 0xb3, 0x47, 0xea, 0x53, 0x51, 0xbd, 0x78, 0x80, 0x90, 0x75, 0xff, 0x84,
+	0xc3, 0x4a, 0xc2, 0x21, 0x2d, 0x7b, 0x6e, 0x57, 0x54, 0xaa, 0x2f, 0x1e, 0x64, 0x64, 0xf5, 0x5b,
+	0x98, 0xd4, 0x27, 0x20, 0x7a, 0x55, 0xdc, 0xef, 0xce, 0x10, 0x55, 0x67, 0x8f, 0x83, 0x72, 0xb2,
+	0x2b, 0x18, 0x15, 0xd2, 0x0f, 0xe5, 0x7a, 0x5a, 0x97, 0xa9, 0xea, 0xf9, 0xce, 0x71, 0x59, 0xf1,
+	0x57, 0xf1, 0xc9, 0x55, 0x88, 0xc9, 0x69, 0xe1, 0x8d, 0xd7, 0x1c, 0x6d, 0x75, 0xc7, 0x51, 0xe6,
+	0x6f, 0x70, 0xb5, 0xbb, 0x58, 0x7d, 0x4e, 0xa8, 0x17, 0x0f, 0x10, 0x72, 0x91, 0x56, 0xe1, 0x6b,
+	0x26, 0x3d, 0x06, 0xe8, 0x65, 0xad, 0xa1, 0x4a, 0xf1, 0xa0, 0x7e, 0xf5, 0x08, 0x95, 0xcc, 0xb1,
+	0x5c, 0xfc, 0xf1, 0xf6, 0x2f, 0x12, 0xdd, 0xc4, 0xd6, 0xdc, 0xf6, 0xbd, 0x85, 0x4b, 0x22, 0x6c,
+	0xfb, 0x84, 0x5e, 0x13, 0x6a, 0x52, 0x1b, 0x2f, 0x5c, 0xea, 0x2c, 0x5c, 0xba, 0xfd, 0x98, 0x0d,
+	0x03, 0xdb, 0xea, 0x8a, 0x0f, 0xda, 0x77, 0xff, 0x0f, 0x00, 0x9a, 0x39, 0x5e, 0xa4, 0xe8, 0x0a,
+	0x00, 0x00,
+}
@@ -0,0 +1,487 @@
+// +build towerrpc
+
+package towerrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/lnd/lnrpc"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/watchtower/blob"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
+	"github.com/litecoinfinance/lnd/watchtower/wtserver"
+
+	"google.golang.org/grpc"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+const (
+	// subServerName is the name of the sub rpc server. We'll use this name
+	// to register ourselves, and we also require that the main
+	// SubServerConfigDispatcher instance recognize this as the name of the
+	// config file that we need.
+	subServerName = "TowerRPC"
+)
+
+var (
+	// macaroonOps are the set of capabilities that our minted macaroon (if
+	// it doesn't already exist) will have.
+	macaroonOps = []bakery.Op{
+		{
+			Entity: "tower",
+			Action: "read",
+		},
+		{
+			Entity: "tower",
+			Action: "write",
+		},
+	}
+
+	// macPermissions maps RPC calls to the permissions they require.
+	macPermissions = map[string][]bakery.Op{
+		"/towerrpc.Tower/GetInfo": {{
+			Entity: "tower",
+			Action: "read",
+		}},
+		"/towerrpc.Tower/ListSessions": {{
+			Entity: "tower",
+			Action: "read",
+		}},
+		"/towerrpc.Tower/BlacklistClient": {{
+			Entity: "tower",
+			Action: "write",
+		}},
+		"/towerrpc.Tower/UnblacklistClient": {{
+			Entity: "tower",
+			Action: "write",
+		}},
+		"/towerrpc.Tower/ListBlacklistedClients": {{
+			Entity: "tower",
+			Action: "read",
+		}},
+		"/towerrpc.Tower/PruneSessions": {{
+			Entity: "tower",
+			Action: "write",
+		}},
+		"/towerrpc.Tower/GetPolicyBounds": {{
+			Entity: "tower",
+			Action: "read",
+		}},
+		"/towerrpc.Tower/SetPolicyBounds": {{
+			Entity: "tower",
+			Action: "write",
+		}},
+		"/towerrpc.Tower/ListSessionPolicies": {{
+			Entity: "tower",
+			Action: "read",
+		}},
+	}
+
+	// DefaultTowerMacFilename is the default name of the tower macaroon
+	// that we expect to find via a file handle within the main
+	// configuration file in this package.
+	DefaultTowerMacFilename = "tower.macaroon"
+
+	// ErrTowerNotActive is returned when the tower RPC server is queried
+	// while no watchtower is running.
+	ErrTowerNotActive = errors.New("watchtower not active")
+)
+
+// Server is a sub-server of the main RPC server: the tower RPC. This sub RPC
+// server allows the operator of a watchtower to inspect the state of the
+// tower, and to manage the clients it serves.
+type Server struct {
+	cfg *Config
+}
+
+// A compile time check to ensure that Server fully implements the TowerServer
+// gRPC service.
+var _ TowerServer = (*Server)(nil)
+
+// New returns a new instance of the towerrpc Tower sub-server. We also return
+// the set of permissions for the macaroons that we may create within this
+// method. If the macaroons we need aren't found in the filepath, then we'll
+// create them on start up. If we're unable to locate, or create the macaroons
+// we need, then we'll return with an error.
+func New(cfg *Config) (*Server, lnrpc.MacaroonPerms, error) {
+	// If the path of the tower macaroon wasn't generated, then we'll
+	// assume that it's found at the default network directory.
+	if cfg.TowerMacPath == "" {
+		cfg.TowerMacPath = filepath.Join(
+			cfg.NetworkDir, DefaultTowerMacFilename,
+		)
+	}
+
+	// Now that we know the full path of the tower macaroon, we can check
+	// to see if we need to create it or not.
+	macFilePath := cfg.TowerMacPath
+	if cfg.MacService != nil && !lnrpc.FileExists(macFilePath) {
+		log.Infof("Making macaroons for Tower RPC Server at: %v",
+			macFilePath)
+
+		towerMac, err := cfg.MacService.Oven.NewMacaroon(
+			context.Background(), bakery.LatestVersion, nil,
+			macaroonOps...,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		towerMacBytes, err := towerMac.M().MarshalBinary()
+		if err != nil {
+			return nil, nil, err
+		}
+		err = ioutil.WriteFile(macFilePath, towerMacBytes, 0644)
+		if err != nil {
+			os.Remove(macFilePath)
+			return nil, nil, err
+		}
+	}
+
+	return &Server{cfg: cfg}, macPermissions, nil
+}
+
+// Start launches any helper goroutines required for the rpcServer to function.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Start() error {
+	return nil
+}
+
+// Stop signals any active goroutines for a graceful closure.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Stop() error {
+	return nil
+}
+
+// Name returns a unique string representation of the sub-server. This can be
+// used to identify the sub-server and also de-duplicate them.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Name() string {
+	return subServerName
+}
+
+// RegisterWithRootServer will be called by the root gRPC server to direct a
+// sub RPC server to register itself with the main gRPC root server. Until this
+// is called, each sub-server won't be able to have requests routed towards it.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) RegisterWithRootServer(grpcServer *grpc.Server) error {
+	// We make sure that we register it with the main gRPC server to ensure
+	// all our methods are routed properly.
+	RegisterTowerServer(grpcServer, s)
+
+	log.Debugf("Tower RPC server successfully register with root gRPC " +
+		"server")
+
+	return nil
+}
+
+// GetInfo returns the identity of the tower, along with a summary of the
+// resources occupied by its clients and the breaches it has handled.
+func (s *Server) GetInfo(ctx context.Context,
+	in *GetInfoRequest) (*GetInfoResponse, error) {
+
+	if s.cfg.Tower == nil {
+		return nil, ErrTowerNotActive
+	}
+
+	stats, err := s.cfg.Tower.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	var listeners []string
+	for _, addr := range s.cfg.Tower.ListenAddrs() {
+		listeners = append(listeners, addr.String())
+	}
+
+	return &GetInfoResponse{
+		Pubkey:           s.cfg.Tower.PubKey().SerializeCompressed(),
+		Listeners:        listeners,
+		NumSessions:      stats.Storage.NumSessions,
+		NumUpdates:       stats.Storage.NumUpdates,
+		UpdateBytes:      stats.Storage.UpdateBytes,
+		DbSize:           stats.Storage.DBSize,
+		NumBlacklisted:   stats.Storage.NumBlacklisted,
+		MatchedBreaches:  stats.Breaches.MatchedBreaches,
+		PunishedBreaches: stats.Breaches.PunishedBreaches,
+	}, nil
+}
+
+// ListSessions returns every session negotiated with a client, along with the
+// number of encrypted blobs stored for it.
+func (s *Server) ListSessions(ctx context.Context,
+	in *ListSessionsRequest) (*ListSessionsResponse, error) {
+
+	if s.cfg.Tower == nil {
+		return nil, ErrTowerNotActive
+	}
+
+	summaries, err := s.cfg.Tower.Sessions()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListSessionsResponse{
+		Sessions: make([]*Session, 0, len(summaries)),
+	}
+	for _, summary := range summaries {
+		policy := summary.Policy
+
+		var lastActivity int64
+		if !summary.LastActivity.IsZero() {
+			lastActivity = summary.LastActivity.Unix()
+		}
+
+		resp.Sessions = append(resp.Sessions, &Session{
+			Id:                summary.ID[:],
+			BlobType:          uint32(policy.BlobType),
+			MaxUpdates:        uint32(policy.MaxUpdates),
+			LastApplied:       uint32(summary.LastApplied),
+			ClientLastApplied: uint32(summary.ClientLastApplied),
+			SweepFeeRate:      int64(policy.SweepFeeRate),
+			RewardBase:        policy.RewardBase,
+			RewardRate:        policy.RewardRate,
+			NumUpdates:        summary.NumUpdates,
+			LastActivity:      lastActivity,
+		})
+	}
+
+	return resp, nil
+}
+
+// BlacklistClient refuses any further connections from the given client, and
+// drops its connection if it is currently connected.
+func (s *Server) BlacklistClient(ctx context.Context,
+	in *BlacklistClientRequest) (*BlacklistClientResponse, error) {
+
+	if s.cfg.Tower == nil {
+		return nil, ErrTowerNotActive
+	}
+
+	id, err := parseSessionID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cfg.Tower.BlacklistClient(id); err != nil {
+		return nil, err
+	}
+
+	return &BlacklistClientResponse{}, nil
+}
+
+// UnblacklistClient allows a previously blacklisted client to connect to the
+// tower once again.
+func (s *Server) UnblacklistClient(ctx context.Context,
+	in *UnblacklistClientRequest) (*UnblacklistClientResponse, error) {
+
+	if s.cfg.Tower == nil {
+		return nil, ErrTowerNotActive
+	}
+
+	id, err := parseSessionID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cfg.Tower.UnblacklistClient(id); err != nil {
+		return nil, err
+	}
+
+	return &UnblacklistClientResponse{}, nil
+}
+
+// ListBlacklistedClients returns the session ids of all blacklisted clients.
+func (s *Server) ListBlacklistedClients(ctx context.Context,
+	in *ListBlacklistedClientsRequest) (*ListBlacklistedClientsResponse,
+	error) {
+
+	if s.cfg.Tower == nil {
+		return nil, ErrTowerNotActive
+	}
+
+	ids, err := s.cfg.Tower.BlacklistedClients()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListBlacklistedClientsResponse{
+		Ids: marshallSessionIDs(ids),
+	}, nil
+}
+
+// PruneSessions deletes all sessions that haven't been updated by their client
+// within the given period, freeing the storage of their encrypted blobs.
+func (s *Server) PruneSessions(ctx context.Context,
+	in *PruneSessionsRequest) (*PruneSessionsResponse, error) {
+
+	if s.cfg.Tower == nil {
+		return nil, ErrTowerNotActive
+	}
+
+	// Refuse to prune every session at once, as that is unlikely to be
+	// what the caller intended.
+	if in.InactiveSecs == 0 {
+		return nil, fmt.Errorf("inactive_secs must be positive")
+	}
+
+	pruned, err := s.cfg.Tower.PruneInactiveSessions(
+		time.Duration(in.InactiveSecs) * time.Second,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PruneSessionsResponse{
+		Ids: marshallSessionIDs(pruned),
+	}, nil
+}
+
+// GetPolicyBounds returns the bounds currently restricting the policies
+// clients may negotiate new sessions with.
+func (s *Server) GetPolicyBounds(ctx context.Context,
+	in *GetPolicyBoundsRequest) (*PolicyBounds, error) {
+
+	if s.cfg.Tower == nil {
+		return nil, ErrTowerNotActive
+	}
+
+	bounds := s.cfg.Tower.PolicyBounds()
+
+	blobTypes := make([]uint32, 0, len(bounds.BlobTypes))
+	for _, blobType := range bounds.BlobTypes {
+		blobTypes = append(blobTypes, uint32(blobType))
+	}
+
+	return &PolicyBounds{
+		MaxUpdates:      uint32(bounds.MaxUpdates),
+		MinRewardBase:   bounds.MinRewardBase,
+		MaxRewardBase:   bounds.MaxRewardBase,
+		MinRewardRate:   bounds.MinRewardRate,
+		MaxRewardRate:   bounds.MaxRewardRate,
+		MinSweepFeeRate: int64(bounds.MinSweepFeeRate),
+		MaxSweepFeeRate: int64(bounds.MaxSweepFeeRate),
+		BlobTypes:       blobTypes,
+	}, nil
+}
+
+// SetPolicyBounds replaces the bounds restricting the policies clients may
+// negotiate new sessions with.
+func (s *Server) SetPolicyBounds(ctx context.Context,
+	in *SetPolicyBoundsRequest) (*SetPolicyBoundsResponse, error) {
+
+	if s.cfg.Tower == nil {
+		return nil, ErrTowerNotActive
+	}
+
+	if in.Bounds == nil {
+		return nil, fmt.Errorf("bounds must be specified")
+	}
+
+	bounds, err := unmarshallPolicyBounds(in.Bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cfg.Tower.SetPolicyBounds(*bounds); err != nil {
+		return nil, err
+	}
+
+	return &SetPolicyBoundsResponse{}, nil
+}
+
+// ListSessionPolicies returns the distinct policies negotiated by clients,
+// along with the number of sessions using each.
+func (s *Server) ListSessionPolicies(ctx context.Context,
+	in *ListSessionPoliciesRequest) (*ListSessionPoliciesResponse, error) {
+
+	if s.cfg.Tower == nil {
+		return nil, ErrTowerNotActive
+	}
+
+	policies, err := s.cfg.Tower.SessionPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListSessionPoliciesResponse{
+		Policies: make([]*SessionPolicy, 0, len(policies)),
+	}
+	for _, policy := range policies {
+		resp.Policies = append(resp.Policies, &SessionPolicy{
+			BlobType:     uint32(policy.BlobType),
+			MaxUpdates:   uint32(policy.MaxUpdates),
+			SweepFeeRate: int64(policy.SweepFeeRate),
+			RewardBase:   policy.RewardBase,
+			RewardRate:   policy.RewardRate,
+			NumSessions:  policy.NumSessions,
+		})
+	}
+
+	return resp, nil
+}
+
+// unmarshallPolicyBounds converts policy bounds received over RPC, ensuring
+// each value fits within the range of its native type.
+func unmarshallPolicyBounds(in *PolicyBounds) (*wtserver.PolicyBounds, error) {
+	if in.MaxUpdates > math.MaxUint16 {
+		return nil, fmt.Errorf("max_updates must not exceed %d",
+			math.MaxUint16)
+	}
+	if in.MinSweepFeeRate < 0 || in.MaxSweepFeeRate < 0 {
+		return nil, fmt.Errorf("sweep fee rates must not be negative")
+	}
+
+	var blobTypes []blob.Type
+	for _, blobType := range in.BlobTypes {
+		if blobType > math.MaxUint16 {
+			return nil, fmt.Errorf("invalid blob type %d", blobType)
+		}
+		blobTypes = append(blobTypes, blob.Type(blobType))
+	}
+
+	return &wtserver.PolicyBounds{
+		MaxUpdates:      uint16(in.MaxUpdates),
+		MinRewardBase:   in.MinRewardBase,
+		MaxRewardBase:   in.MaxRewardBase,
+		MinRewardRate:   in.MinRewardRate,
+		MaxRewardRate:   in.MaxRewardRate,
+		MinSweepFeeRate: lnwallet.SatPerKWeight(in.MinSweepFeeRate),
+		MaxSweepFeeRate: lnwallet.SatPerKWeight(in.MaxSweepFeeRate),
+		BlobTypes:       blobTypes,
+	}, nil
+}
+
+// parseSessionID parses a session id received over RPC, ensuring it is a
+// valid public key.
+func parseSessionID(rawID []byte) (wtdb.SessionID, error) {
+	pubKey, err := btcec.ParsePubKey(rawID, btcec.S256())
+	if err != nil {
+		return wtdb.SessionID{}, fmt.Errorf("invalid session id: %v",
+			err)
+	}
+
+	return wtdb.NewSessionIDFromPubKey(pubKey), nil
+}
+
+// marshallSessionIDs converts the session ids to their RPC representation.
+func marshallSessionIDs(ids []wtdb.SessionID) [][]byte {
+	rpcIDs := make([][]byte, 0, len(ids))
+	for _, id := range ids {
+		id := id
+		rpcIDs = append(rpcIDs, id[:])
+	}
+
+	return rpcIDs
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/litecoinfinance/lnd/keychain"
 	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/macaroons"
+	"github.com/litecoinfinance/lnd/sweep"
 )
 
 // Config is the primary configuration struct for the WalletKit RPC server. It
@@ -32,10 +33,15 @@ type Config struct {
 	FeeEstimator lnwallet.FeeEstimator
 
 	// Wallet is the primary wallet that the WalletKit will use to proxy
-	// any relevant requests to.
-	Wallet lnwallet.WalletController
+	// any relevant requests to. Unlike the underlying WalletController, it
+	// also tracks the outputs leased to clients.
+	Wallet *lnwallet.LightningWallet
 
 	// KeyRing is an interface that the WalletKit will use to derive any
 	// keys due to incoming client requests.
 	KeyRing keychain.KeyRing
+
+	// Sweeper is the central batching engine of lnd. It is responsible for
+	// sweeping inputs in batches back into the wallet.
+	Sweeper *sweep.UtxoSweeper
 }
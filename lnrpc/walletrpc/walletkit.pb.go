@@ -43,7 +43,7 @@ func (m *KeyReq) Reset()         { *m = KeyReq{} }
 func (m *KeyReq) String() string { return proto.CompactTextString(m) }
 func (*KeyReq) ProtoMessage()    {}
 func (*KeyReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_walletkit_ca4e27c2068154e3, []int{0}
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{0}
 }
 func (m *KeyReq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KeyReq.Unmarshal(m, b)
@@ -87,7 +87,7 @@ func (m *AddrRequest) Reset()         { *m = AddrRequest{} }
 func (m *AddrRequest) String() string { return proto.CompactTextString(m) }
 func (*AddrRequest) ProtoMessage()    {}
 func (*AddrRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_walletkit_ca4e27c2068154e3, []int{1}
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{1}
 }
 func (m *AddrRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AddrRequest.Unmarshal(m, b)
@@ -120,7 +120,7 @@ func (m *AddrResponse) Reset()         { *m = AddrResponse{} }
 func (m *AddrResponse) String() string { return proto.CompactTextString(m) }
 func (*AddrResponse) ProtoMessage()    {}
 func (*AddrResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_walletkit_ca4e27c2068154e3, []int{2}
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{2}
 }
 func (m *AddrResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AddrResponse.Unmarshal(m, b)
@@ -160,7 +160,7 @@ func (m *Transaction) Reset()         { *m = Transaction{} }
 func (m *Transaction) String() string { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()    {}
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_walletkit_ca4e27c2068154e3, []int{3}
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{3}
 }
 func (m *Transaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Transaction.Unmarshal(m, b)
@@ -204,7 +204,7 @@ func (m *PublishResponse) Reset()         { *m = PublishResponse{} }
 func (m *PublishResponse) String() string { return proto.CompactTextString(m) }
 func (*PublishResponse) ProtoMessage()    {}
 func (*PublishResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_walletkit_ca4e27c2068154e3, []int{4}
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{4}
 }
 func (m *PublishResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PublishResponse.Unmarshal(m, b)
@@ -248,7 +248,7 @@ func (m *SendOutputsRequest) Reset()         { *m = SendOutputsRequest{} }
 func (m *SendOutputsRequest) String() string { return proto.CompactTextString(m) }
 func (*SendOutputsRequest) ProtoMessage()    {}
 func (*SendOutputsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_walletkit_ca4e27c2068154e3, []int{5}
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{5}
 }
 func (m *SendOutputsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SendOutputsRequest.Unmarshal(m, b)
@@ -295,7 +295,7 @@ func (m *SendOutputsResponse) Reset()         { *m = SendOutputsResponse{} }
 func (m *SendOutputsResponse) String() string { return proto.CompactTextString(m) }
 func (*SendOutputsResponse) ProtoMessage()    {}
 func (*SendOutputsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_walletkit_ca4e27c2068154e3, []int{6}
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{6}
 }
 func (m *SendOutputsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SendOutputsResponse.Unmarshal(m, b)
@@ -335,7 +335,7 @@ func (m *EstimateFeeRequest) Reset()         { *m = EstimateFeeRequest{} }
 func (m *EstimateFeeRequest) String() string { return proto.CompactTextString(m) }
 func (*EstimateFeeRequest) ProtoMessage()    {}
 func (*EstimateFeeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_walletkit_ca4e27c2068154e3, []int{7}
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{7}
 }
 func (m *EstimateFeeRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EstimateFeeRequest.Unmarshal(m, b)
@@ -376,7 +376,7 @@ func (m *EstimateFeeResponse) Reset()         { *m = EstimateFeeResponse{} }
 func (m *EstimateFeeResponse) String() string { return proto.CompactTextString(m) }
 func (*EstimateFeeResponse) ProtoMessage()    {}
 func (*EstimateFeeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_walletkit_ca4e27c2068154e3, []int{8}
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{8}
 }
 func (m *EstimateFeeResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EstimateFeeResponse.Unmarshal(m, b)
@@ -403,6 +403,791 @@ func (m *EstimateFeeResponse) GetSatPerKw() int64 {
 	return 0
 }
 
+type OutPoint struct {
+	// *
+	// The txid of the transaction the output is part of, in its raw byte order.
+	Txid []byte `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	// *
+	// The index of the output within the transaction.
+	OutputIndex          uint32   `protobuf:"varint,2,opt,name=output_index,json=outputIndex,proto3" json:"output_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OutPoint) Reset()         { *m = OutPoint{} }
+func (m *OutPoint) String() string { return proto.CompactTextString(m) }
+func (*OutPoint) ProtoMessage()    {}
+func (*OutPoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{9}
+}
+func (m *OutPoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OutPoint.Unmarshal(m, b)
+}
+func (m *OutPoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OutPoint.Marshal(b, m, deterministic)
+}
+func (dst *OutPoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutPoint.Merge(dst, src)
+}
+func (m *OutPoint) XXX_Size() int {
+	return xxx_messageInfo_OutPoint.Size(m)
+}
+func (m *OutPoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutPoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OutPoint proto.InternalMessageInfo
+
+func (m *OutPoint) GetTxid() []byte {
+	if m != nil {
+		return m.Txid
+	}
+	return nil
+}
+
+func (m *OutPoint) GetOutputIndex() uint32 {
+	if m != nil {
+		return m.OutputIndex
+	}
+	return 0
+}
+
+type LeaseOutputRequest struct {
+	// *
+	// The output of the wallet to lease.
+	Outpoint             *OutPoint `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *LeaseOutputRequest) Reset()         { *m = LeaseOutputRequest{} }
+func (m *LeaseOutputRequest) String() string { return proto.CompactTextString(m) }
+func (*LeaseOutputRequest) ProtoMessage()    {}
+func (*LeaseOutputRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{10}
+}
+func (m *LeaseOutputRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaseOutputRequest.Unmarshal(m, b)
+}
+func (m *LeaseOutputRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaseOutputRequest.Marshal(b, m, deterministic)
+}
+func (dst *LeaseOutputRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaseOutputRequest.Merge(dst, src)
+}
+func (m *LeaseOutputRequest) XXX_Size() int {
+	return xxx_messageInfo_LeaseOutputRequest.Size(m)
+}
+func (m *LeaseOutputRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaseOutputRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaseOutputRequest proto.InternalMessageInfo
+
+func (m *LeaseOutputRequest) GetOutpoint() *OutPoint {
+	if m != nil {
+		return m.Outpoint
+	}
+	return nil
+}
+
+type LeaseOutputResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LeaseOutputResponse) Reset()         { *m = LeaseOutputResponse{} }
+func (m *LeaseOutputResponse) String() string { return proto.CompactTextString(m) }
+func (*LeaseOutputResponse) ProtoMessage()    {}
+func (*LeaseOutputResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{11}
+}
+func (m *LeaseOutputResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaseOutputResponse.Unmarshal(m, b)
+}
+func (m *LeaseOutputResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaseOutputResponse.Marshal(b, m, deterministic)
+}
+func (dst *LeaseOutputResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaseOutputResponse.Merge(dst, src)
+}
+func (m *LeaseOutputResponse) XXX_Size() int {
+	return xxx_messageInfo_LeaseOutputResponse.Size(m)
+}
+func (m *LeaseOutputResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaseOutputResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaseOutputResponse proto.InternalMessageInfo
+
+type ReleaseOutputRequest struct {
+	// *
+	// The leased output to release.
+	Outpoint             *OutPoint `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ReleaseOutputRequest) Reset()         { *m = ReleaseOutputRequest{} }
+func (m *ReleaseOutputRequest) String() string { return proto.CompactTextString(m) }
+func (*ReleaseOutputRequest) ProtoMessage()    {}
+func (*ReleaseOutputRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{12}
+}
+func (m *ReleaseOutputRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseOutputRequest.Unmarshal(m, b)
+}
+func (m *ReleaseOutputRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseOutputRequest.Marshal(b, m, deterministic)
+}
+func (dst *ReleaseOutputRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseOutputRequest.Merge(dst, src)
+}
+func (m *ReleaseOutputRequest) XXX_Size() int {
+	return xxx_messageInfo_ReleaseOutputRequest.Size(m)
+}
+func (m *ReleaseOutputRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseOutputRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseOutputRequest proto.InternalMessageInfo
+
+func (m *ReleaseOutputRequest) GetOutpoint() *OutPoint {
+	if m != nil {
+		return m.Outpoint
+	}
+	return nil
+}
+
+type ReleaseOutputResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseOutputResponse) Reset()         { *m = ReleaseOutputResponse{} }
+func (m *ReleaseOutputResponse) String() string { return proto.CompactTextString(m) }
+func (*ReleaseOutputResponse) ProtoMessage()    {}
+func (*ReleaseOutputResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{13}
+}
+func (m *ReleaseOutputResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseOutputResponse.Unmarshal(m, b)
+}
+func (m *ReleaseOutputResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseOutputResponse.Marshal(b, m, deterministic)
+}
+func (dst *ReleaseOutputResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseOutputResponse.Merge(dst, src)
+}
+func (m *ReleaseOutputResponse) XXX_Size() int {
+	return xxx_messageInfo_ReleaseOutputResponse.Size(m)
+}
+func (m *ReleaseOutputResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseOutputResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseOutputResponse proto.InternalMessageInfo
+
+type ListLeasesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListLeasesRequest) Reset()         { *m = ListLeasesRequest{} }
+func (m *ListLeasesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListLeasesRequest) ProtoMessage()    {}
+func (*ListLeasesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{14}
+}
+func (m *ListLeasesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListLeasesRequest.Unmarshal(m, b)
+}
+func (m *ListLeasesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListLeasesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListLeasesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListLeasesRequest.Merge(dst, src)
+}
+func (m *ListLeasesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListLeasesRequest.Size(m)
+}
+func (m *ListLeasesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListLeasesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListLeasesRequest proto.InternalMessageInfo
+
+type ListLeasesResponse struct {
+	// *
+	// The outputs of the wallet that are currently leased.
+	Outpoints            []*OutPoint `protobuf:"bytes,1,rep,name=outpoints,proto3" json:"outpoints,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListLeasesResponse) Reset()         { *m = ListLeasesResponse{} }
+func (m *ListLeasesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListLeasesResponse) ProtoMessage()    {}
+func (*ListLeasesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{15}
+}
+func (m *ListLeasesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListLeasesResponse.Unmarshal(m, b)
+}
+func (m *ListLeasesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListLeasesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListLeasesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListLeasesResponse.Merge(dst, src)
+}
+func (m *ListLeasesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListLeasesResponse.Size(m)
+}
+func (m *ListLeasesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListLeasesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListLeasesResponse proto.InternalMessageInfo
+
+func (m *ListLeasesResponse) GetOutpoints() []*OutPoint {
+	if m != nil {
+		return m.Outpoints
+	}
+	return nil
+}
+
+type PendingSweep struct {
+	// *
+	// The outpoint of the output we're attempting to sweep.
+	Outpoint *OutPoint `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// *
+	// The witness type of the output we're attempting to sweep.
+	WitnessType string `protobuf:"bytes,2,opt,name=witness_type,json=witnessType,proto3" json:"witness_type,omitempty"`
+	// *
+	// The value of the output we're attempting to sweep.
+	AmountSat uint32 `protobuf:"varint,3,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
+	// *
+	// The fee rate we'll use to sweep the output. The fee rate is only
+	// determined once a sweeping transaction for the output is created, so it's
+	// possible for this to be 0 before this.
+	SatPerByte uint32 `protobuf:"varint,4,opt,name=sat_per_byte,json=satPerByte,proto3" json:"sat_per_byte,omitempty"`
+	// *
+	// The number of broadcast attempts we've made to sweep the output.
+	BroadcastAttempts uint32 `protobuf:"varint,5,opt,name=broadcast_attempts,json=broadcastAttempts,proto3" json:"broadcast_attempts,omitempty"`
+	// *
+	// The next height of the chain at which we'll attempt to broadcast the
+	// sweep transaction of the output.
+	NextBroadcastHeight uint32 `protobuf:"varint,6,opt,name=next_broadcast_height,json=nextBroadcastHeight,proto3" json:"next_broadcast_height,omitempty"`
+	// *
+	// The requested confirmation target for this output, if any.
+	RequestedConfTarget uint32 `protobuf:"varint,7,opt,name=requested_conf_target,json=requestedConfTarget,proto3" json:"requested_conf_target,omitempty"`
+	// *
+	// The requested fee rate, expressed in sat/byte, for this output, if any.
+	RequestedSatPerByte uint32 `protobuf:"varint,8,opt,name=requested_sat_per_byte,json=requestedSatPerByte,proto3" json:"requested_sat_per_byte,omitempty"`
+	// *
+	// Whether the output was requested to be swept right away.
+	Force                bool     `protobuf:"varint,9,opt,name=force,proto3" json:"force,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PendingSweep) Reset()         { *m = PendingSweep{} }
+func (m *PendingSweep) String() string { return proto.CompactTextString(m) }
+func (*PendingSweep) ProtoMessage()    {}
+func (*PendingSweep) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{16}
+}
+func (m *PendingSweep) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingSweep.Unmarshal(m, b)
+}
+func (m *PendingSweep) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingSweep.Marshal(b, m, deterministic)
+}
+func (dst *PendingSweep) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingSweep.Merge(dst, src)
+}
+func (m *PendingSweep) XXX_Size() int {
+	return xxx_messageInfo_PendingSweep.Size(m)
+}
+func (m *PendingSweep) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingSweep.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingSweep proto.InternalMessageInfo
+
+func (m *PendingSweep) GetOutpoint() *OutPoint {
+	if m != nil {
+		return m.Outpoint
+	}
+	return nil
+}
+
+func (m *PendingSweep) GetWitnessType() string {
+	if m != nil {
+		return m.WitnessType
+	}
+	return ""
+}
+
+func (m *PendingSweep) GetAmountSat() uint32 {
+	if m != nil {
+		return m.AmountSat
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetSatPerByte() uint32 {
+	if m != nil {
+		return m.SatPerByte
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetBroadcastAttempts() uint32 {
+	if m != nil {
+		return m.BroadcastAttempts
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetNextBroadcastHeight() uint32 {
+	if m != nil {
+		return m.NextBroadcastHeight
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetRequestedConfTarget() uint32 {
+	if m != nil {
+		return m.RequestedConfTarget
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetRequestedSatPerByte() uint32 {
+	if m != nil {
+		return m.RequestedSatPerByte
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+type PendingSweepsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PendingSweepsRequest) Reset()         { *m = PendingSweepsRequest{} }
+func (m *PendingSweepsRequest) String() string { return proto.CompactTextString(m) }
+func (*PendingSweepsRequest) ProtoMessage()    {}
+func (*PendingSweepsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{17}
+}
+func (m *PendingSweepsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingSweepsRequest.Unmarshal(m, b)
+}
+func (m *PendingSweepsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingSweepsRequest.Marshal(b, m, deterministic)
+}
+func (dst *PendingSweepsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingSweepsRequest.Merge(dst, src)
+}
+func (m *PendingSweepsRequest) XXX_Size() int {
+	return xxx_messageInfo_PendingSweepsRequest.Size(m)
+}
+func (m *PendingSweepsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingSweepsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingSweepsRequest proto.InternalMessageInfo
+
+type PendingSweepsResponse struct {
+	// *
+	// The set of outputs currently being swept by lnd's central batching engine.
+	PendingSweeps        []*PendingSweep `protobuf:"bytes,1,rep,name=pending_sweeps,json=pendingSweeps,proto3" json:"pending_sweeps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *PendingSweepsResponse) Reset()         { *m = PendingSweepsResponse{} }
+func (m *PendingSweepsResponse) String() string { return proto.CompactTextString(m) }
+func (*PendingSweepsResponse) ProtoMessage()    {}
+func (*PendingSweepsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{18}
+}
+func (m *PendingSweepsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingSweepsResponse.Unmarshal(m, b)
+}
+func (m *PendingSweepsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingSweepsResponse.Marshal(b, m, deterministic)
+}
+func (dst *PendingSweepsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingSweepsResponse.Merge(dst, src)
+}
+func (m *PendingSweepsResponse) XXX_Size() int {
+	return xxx_messageInfo_PendingSweepsResponse.Size(m)
+}
+func (m *PendingSweepsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingSweepsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingSweepsResponse proto.InternalMessageInfo
+
+func (m *PendingSweepsResponse) GetPendingSweeps() []*PendingSweep {
+	if m != nil {
+		return m.PendingSweeps
+	}
+	return nil
+}
+
+type BumpFeeRequest struct {
+	// *
+	// The input we're attempting to bump the fee of.
+	Outpoint *OutPoint `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// *
+	// The target number of blocks that the input should be spent within.
+	TargetConf uint32 `protobuf:"varint,2,opt,name=target_conf,json=targetConf,proto3" json:"target_conf,omitempty"`
+	// *
+	// The fee rate, expressed in sat/byte, that should be used to spend the
+	// input with.
+	SatPerByte uint32 `protobuf:"varint,3,opt,name=sat_per_byte,json=satPerByte,proto3" json:"sat_per_byte,omitempty"`
+	// *
+	// Whether the input should be swept right away, rather than waiting for the
+	// batch window of the sweeper to expire.
+	Force                bool     `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BumpFeeRequest) Reset()         { *m = BumpFeeRequest{} }
+func (m *BumpFeeRequest) String() string { return proto.CompactTextString(m) }
+func (*BumpFeeRequest) ProtoMessage()    {}
+func (*BumpFeeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{19}
+}
+func (m *BumpFeeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BumpFeeRequest.Unmarshal(m, b)
+}
+func (m *BumpFeeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BumpFeeRequest.Marshal(b, m, deterministic)
+}
+func (dst *BumpFeeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BumpFeeRequest.Merge(dst, src)
+}
+func (m *BumpFeeRequest) XXX_Size() int {
+	return xxx_messageInfo_BumpFeeRequest.Size(m)
+}
+func (m *BumpFeeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BumpFeeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BumpFeeRequest proto.InternalMessageInfo
+
+func (m *BumpFeeRequest) GetOutpoint() *OutPoint {
+	if m != nil {
+		return m.Outpoint
+	}
+	return nil
+}
+
+func (m *BumpFeeRequest) GetTargetConf() uint32 {
+	if m != nil {
+		return m.TargetConf
+	}
+	return 0
+}
+
+func (m *BumpFeeRequest) GetSatPerByte() uint32 {
+	if m != nil {
+		return m.SatPerByte
+	}
+	return 0
+}
+
+func (m *BumpFeeRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+type BumpFeeResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BumpFeeResponse) Reset()         { *m = BumpFeeResponse{} }
+func (m *BumpFeeResponse) String() string { return proto.CompactTextString(m) }
+func (*BumpFeeResponse) ProtoMessage()    {}
+func (*BumpFeeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{20}
+}
+func (m *BumpFeeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BumpFeeResponse.Unmarshal(m, b)
+}
+func (m *BumpFeeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BumpFeeResponse.Marshal(b, m, deterministic)
+}
+func (dst *BumpFeeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BumpFeeResponse.Merge(dst, src)
+}
+func (m *BumpFeeResponse) XXX_Size() int {
+	return xxx_messageInfo_BumpFeeResponse.Size(m)
+}
+func (m *BumpFeeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BumpFeeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BumpFeeResponse proto.InternalMessageInfo
+
+type LabelTransactionRequest struct {
+	// *
+	// The txid of the wallet transaction to label.
+	Txid []byte `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	// *
+	// The label to add to the transaction.
+	Label string `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	// *
+	// Whether to overwrite the existing label of the transaction, if any.
+	Overwrite            bool     `protobuf:"varint,3,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LabelTransactionRequest) Reset()         { *m = LabelTransactionRequest{} }
+func (m *LabelTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*LabelTransactionRequest) ProtoMessage()    {}
+func (*LabelTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{21}
+}
+func (m *LabelTransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LabelTransactionRequest.Unmarshal(m, b)
+}
+func (m *LabelTransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LabelTransactionRequest.Marshal(b, m, deterministic)
+}
+func (dst *LabelTransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LabelTransactionRequest.Merge(dst, src)
+}
+func (m *LabelTransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_LabelTransactionRequest.Size(m)
+}
+func (m *LabelTransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LabelTransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LabelTransactionRequest proto.InternalMessageInfo
+
+func (m *LabelTransactionRequest) GetTxid() []byte {
+	if m != nil {
+		return m.Txid
+	}
+	return nil
+}
+
+func (m *LabelTransactionRequest) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *LabelTransactionRequest) GetOverwrite() bool {
+	if m != nil {
+		return m.Overwrite
+	}
+	return false
+}
+
+type LabelTransactionResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LabelTransactionResponse) Reset()         { *m = LabelTransactionResponse{} }
+func (m *LabelTransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*LabelTransactionResponse) ProtoMessage()    {}
+func (*LabelTransactionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{22}
+}
+func (m *LabelTransactionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LabelTransactionResponse.Unmarshal(m, b)
+}
+func (m *LabelTransactionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LabelTransactionResponse.Marshal(b, m, deterministic)
+}
+func (dst *LabelTransactionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LabelTransactionResponse.Merge(dst, src)
+}
+func (m *LabelTransactionResponse) XXX_Size() int {
+	return xxx_messageInfo_LabelTransactionResponse.Size(m)
+}
+func (m *LabelTransactionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LabelTransactionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LabelTransactionResponse proto.InternalMessageInfo
+
+type ListAccountsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAccountsRequest) Reset()         { *m = ListAccountsRequest{} }
+func (m *ListAccountsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAccountsRequest) ProtoMessage()    {}
+func (*ListAccountsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{23}
+}
+func (m *ListAccountsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAccountsRequest.Unmarshal(m, b)
+}
+func (m *ListAccountsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAccountsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListAccountsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAccountsRequest.Merge(dst, src)
+}
+func (m *ListAccountsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAccountsRequest.Size(m)
+}
+func (m *ListAccountsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAccountsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAccountsRequest proto.InternalMessageInfo
+
+type ListAccountsResponse struct {
+	// *
+	// The names of all accounts of the wallet, including the default account.
+	Accounts             []string `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAccountsResponse) Reset()         { *m = ListAccountsResponse{} }
+func (m *ListAccountsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAccountsResponse) ProtoMessage()    {}
+func (*ListAccountsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{24}
+}
+func (m *ListAccountsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAccountsResponse.Unmarshal(m, b)
+}
+func (m *ListAccountsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAccountsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListAccountsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAccountsResponse.Merge(dst, src)
+}
+func (m *ListAccountsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAccountsResponse.Size(m)
+}
+func (m *ListAccountsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAccountsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAccountsResponse proto.InternalMessageInfo
+
+func (m *ListAccountsResponse) GetAccounts() []string {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+type CreateAccountRequest struct {
+	// *
+	// The name of the account to create.
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAccountRequest) Reset()         { *m = CreateAccountRequest{} }
+func (m *CreateAccountRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateAccountRequest) ProtoMessage()    {}
+func (*CreateAccountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{25}
+}
+func (m *CreateAccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAccountRequest.Unmarshal(m, b)
+}
+func (m *CreateAccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAccountRequest.Marshal(b, m, deterministic)
+}
+func (dst *CreateAccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAccountRequest.Merge(dst, src)
+}
+func (m *CreateAccountRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateAccountRequest.Size(m)
+}
+func (m *CreateAccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAccountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAccountRequest proto.InternalMessageInfo
+
+func (m *CreateAccountRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type CreateAccountResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAccountResponse) Reset()         { *m = CreateAccountResponse{} }
+func (m *CreateAccountResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateAccountResponse) ProtoMessage()    {}
+func (*CreateAccountResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_walletkit_097c37014bf95d06, []int{26}
+}
+func (m *CreateAccountResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAccountResponse.Unmarshal(m, b)
+}
+func (m *CreateAccountResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAccountResponse.Marshal(b, m, deterministic)
+}
+func (dst *CreateAccountResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAccountResponse.Merge(dst, src)
+}
+func (m *CreateAccountResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateAccountResponse.Size(m)
+}
+func (m *CreateAccountResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAccountResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAccountResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*KeyReq)(nil), "walletrpc.KeyReq")
 	proto.RegisterType((*AddrRequest)(nil), "walletrpc.AddrRequest")
@@ -413,6 +1198,24 @@ func init() {
 	proto.RegisterType((*SendOutputsResponse)(nil), "walletrpc.SendOutputsResponse")
 	proto.RegisterType((*EstimateFeeRequest)(nil), "walletrpc.EstimateFeeRequest")
 	proto.RegisterType((*EstimateFeeResponse)(nil), "walletrpc.EstimateFeeResponse")
+	proto.RegisterType((*OutPoint)(nil), "walletrpc.OutPoint")
+	proto.RegisterType((*LeaseOutputRequest)(nil), "walletrpc.LeaseOutputRequest")
+	proto.RegisterType((*LeaseOutputResponse)(nil), "walletrpc.LeaseOutputResponse")
+	proto.RegisterType((*ReleaseOutputRequest)(nil), "walletrpc.ReleaseOutputRequest")
+	proto.RegisterType((*ReleaseOutputResponse)(nil), "walletrpc.ReleaseOutputResponse")
+	proto.RegisterType((*ListLeasesRequest)(nil), "walletrpc.ListLeasesRequest")
+	proto.RegisterType((*ListLeasesResponse)(nil), "walletrpc.ListLeasesResponse")
+	proto.RegisterType((*PendingSweep)(nil), "walletrpc.PendingSweep")
+	proto.RegisterType((*PendingSweepsRequest)(nil), "walletrpc.PendingSweepsRequest")
+	proto.RegisterType((*PendingSweepsResponse)(nil), "walletrpc.PendingSweepsResponse")
+	proto.RegisterType((*BumpFeeRequest)(nil), "walletrpc.BumpFeeRequest")
+	proto.RegisterType((*BumpFeeResponse)(nil), "walletrpc.BumpFeeResponse")
+	proto.RegisterType((*LabelTransactionRequest)(nil), "walletrpc.LabelTransactionRequest")
+	proto.RegisterType((*LabelTransactionResponse)(nil), "walletrpc.LabelTransactionResponse")
+	proto.RegisterType((*ListAccountsRequest)(nil), "walletrpc.ListAccountsRequest")
+	proto.RegisterType((*ListAccountsResponse)(nil), "walletrpc.ListAccountsResponse")
+	proto.RegisterType((*CreateAccountRequest)(nil), "walletrpc.CreateAccountRequest")
+	proto.RegisterType((*CreateAccountResponse)(nil), "walletrpc.CreateAccountResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -455,6 +1258,44 @@ type WalletKitClient interface {
 	// determine the fee (in sat/kw) to attach to a transaction in order to
 	// achieve the confirmation target.
 	EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error)
+	// *
+	// LeaseOutput locks an output of the wallet, such that it isn't selected to
+	// fund any transaction of lnd until it's released, which gives the caller
+	// control over it. Leases don't survive a restart of lnd.
+	LeaseOutput(ctx context.Context, in *LeaseOutputRequest, opts ...grpc.CallOption) (*LeaseOutputResponse, error)
+	// *
+	// ReleaseOutput unlocks an output that was leased through LeaseOutput, making
+	// it available for coin selection again.
+	ReleaseOutput(ctx context.Context, in *ReleaseOutputRequest, opts ...grpc.CallOption) (*ReleaseOutputResponse, error)
+	// *
+	// ListLeases lists all outputs of the wallet that are currently leased.
+	ListLeases(ctx context.Context, in *ListLeasesRequest, opts ...grpc.CallOption) (*ListLeasesResponse, error)
+	// *
+	// PendingSweeps returns lists of on-chain outputs that lnd is currently
+	// attempting to sweep within its central batching engine. Outputs with
+	// similar fee rates are batched together in order to sweep them within a
+	// single transaction.
+	PendingSweeps(ctx context.Context, in *PendingSweepsRequest, opts ...grpc.CallOption) (*PendingSweepsResponse, error)
+	// *
+	// BumpFee bumps the fee of an output that lnd is currently attempting to
+	// sweep, by replacing its sweep transaction with one paying the new fee
+	// rate (RBF). The output can also be swept right away, rather than waiting
+	// for the batch window of the sweeper to expire.
+	BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error)
+	// *
+	// LabelTransaction adds a label to a transaction of the wallet, which is
+	// returned along with the transaction by GetTransactions. Existing labels
+	// are only replaced if overwrite is set.
+	LabelTransaction(ctx context.Context, in *LabelTransactionRequest, opts ...grpc.CallOption) (*LabelTransactionResponse, error)
+	// *
+	// ListAccounts lists the names of all accounts of the wallet.
+	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	// *
+	// CreateAccount creates a new account within the wallet. Funds received to
+	// the addresses of the account are kept apart from the default account,
+	// whose funds are the only ones used by lnd on its own, e.g. to fund
+	// channels.
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
 }
 
 type walletKitClient struct {
@@ -519,6 +1360,78 @@ func (c *walletKitClient) EstimateFee(ctx context.Context, in *EstimateFeeReques
 	return out, nil
 }
 
+func (c *walletKitClient) LeaseOutput(ctx context.Context, in *LeaseOutputRequest, opts ...grpc.CallOption) (*LeaseOutputResponse, error) {
+	out := new(LeaseOutputResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/LeaseOutput", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) ReleaseOutput(ctx context.Context, in *ReleaseOutputRequest, opts ...grpc.CallOption) (*ReleaseOutputResponse, error) {
+	out := new(ReleaseOutputResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ReleaseOutput", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) ListLeases(ctx context.Context, in *ListLeasesRequest, opts ...grpc.CallOption) (*ListLeasesResponse, error) {
+	out := new(ListLeasesResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ListLeases", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) PendingSweeps(ctx context.Context, in *PendingSweepsRequest, opts ...grpc.CallOption) (*PendingSweepsResponse, error) {
+	out := new(PendingSweepsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/PendingSweeps", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error) {
+	out := new(BumpFeeResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/BumpFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) LabelTransaction(ctx context.Context, in *LabelTransactionRequest, opts ...grpc.CallOption) (*LabelTransactionResponse, error) {
+	out := new(LabelTransactionResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/LabelTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	out := new(ListAccountsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ListAccounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error) {
+	out := new(CreateAccountResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/CreateAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WalletKitServer is the server API for WalletKit service.
 type WalletKitServer interface {
 	// *
@@ -549,6 +1462,44 @@ type WalletKitServer interface {
 	// determine the fee (in sat/kw) to attach to a transaction in order to
 	// achieve the confirmation target.
 	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
+	// *
+	// LeaseOutput locks an output of the wallet, such that it isn't selected to
+	// fund any transaction of lnd until it's released, which gives the caller
+	// control over it. Leases don't survive a restart of lnd.
+	LeaseOutput(context.Context, *LeaseOutputRequest) (*LeaseOutputResponse, error)
+	// *
+	// ReleaseOutput unlocks an output that was leased through LeaseOutput, making
+	// it available for coin selection again.
+	ReleaseOutput(context.Context, *ReleaseOutputRequest) (*ReleaseOutputResponse, error)
+	// *
+	// ListLeases lists all outputs of the wallet that are currently leased.
+	ListLeases(context.Context, *ListLeasesRequest) (*ListLeasesResponse, error)
+	// *
+	// PendingSweeps returns lists of on-chain outputs that lnd is currently
+	// attempting to sweep within its central batching engine. Outputs with
+	// similar fee rates are batched together in order to sweep them within a
+	// single transaction.
+	PendingSweeps(context.Context, *PendingSweepsRequest) (*PendingSweepsResponse, error)
+	// *
+	// BumpFee bumps the fee of an output that lnd is currently attempting to
+	// sweep, by replacing its sweep transaction with one paying the new fee
+	// rate (RBF). The output can also be swept right away, rather than waiting
+	// for the batch window of the sweeper to expire.
+	BumpFee(context.Context, *BumpFeeRequest) (*BumpFeeResponse, error)
+	// *
+	// LabelTransaction adds a label to a transaction of the wallet, which is
+	// returned along with the transaction by GetTransactions. Existing labels
+	// are only replaced if overwrite is set.
+	LabelTransaction(context.Context, *LabelTransactionRequest) (*LabelTransactionResponse, error)
+	// *
+	// ListAccounts lists the names of all accounts of the wallet.
+	ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
+	// *
+	// CreateAccount creates a new account within the wallet. Funds received to
+	// the addresses of the account are kept apart from the default account,
+	// whose funds are the only ones used by lnd on its own, e.g. to fund
+	// channels.
+	CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
 }
 
 func RegisterWalletKitServer(s *grpc.Server, srv WalletKitServer) {
@@ -663,6 +1614,150 @@ func _WalletKit_EstimateFee_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_LeaseOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseOutputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).LeaseOutput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/LeaseOutput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).LeaseOutput(ctx, req.(*LeaseOutputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_ReleaseOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseOutputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ReleaseOutput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ReleaseOutput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ReleaseOutput(ctx, req.(*ReleaseOutputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_ListLeases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLeasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ListLeases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ListLeases",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ListLeases(ctx, req.(*ListLeasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_PendingSweeps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingSweepsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).PendingSweeps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/PendingSweeps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).PendingSweeps(ctx, req.(*PendingSweepsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).BumpFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/BumpFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).BumpFee(ctx, req.(*BumpFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_LabelTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).LabelTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/LabelTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).LabelTransaction(ctx, req.(*LabelTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ListAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ListAccounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ListAccounts(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/CreateAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _WalletKit_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "walletrpc.WalletKit",
 	HandlerType: (*WalletKitServer)(nil),
@@ -691,48 +1786,117 @@ var _WalletKit_serviceDesc = grpc.ServiceDesc{
 			MethodName: "EstimateFee",
 			Handler:    _WalletKit_EstimateFee_Handler,
 		},
+		{
+			MethodName: "LeaseOutput",
+			Handler:    _WalletKit_LeaseOutput_Handler,
+		},
+		{
+			MethodName: "ReleaseOutput",
+			Handler:    _WalletKit_ReleaseOutput_Handler,
+		},
+		{
+			MethodName: "ListLeases",
+			Handler:    _WalletKit_ListLeases_Handler,
+		},
+		{
+			MethodName: "PendingSweeps",
+			Handler:    _WalletKit_PendingSweeps_Handler,
+		},
+		{
+			MethodName: "BumpFee",
+			Handler:    _WalletKit_BumpFee_Handler,
+		},
+		{
+			MethodName: "LabelTransaction",
+			Handler:    _WalletKit_LabelTransaction_Handler,
+		},
+		{
+			MethodName: "ListAccounts",
+			Handler:    _WalletKit_ListAccounts_Handler,
+		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _WalletKit_CreateAccount_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "walletrpc/walletkit.proto",
 }
 
 func init() {
-	proto.RegisterFile("walletrpc/walletkit.proto", fileDescriptor_walletkit_ca4e27c2068154e3)
-}
-
-var fileDescriptor_walletkit_ca4e27c2068154e3 = []byte{
-	// 524 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x53, 0x5d, 0x6f, 0xd3, 0x30,
-	0x14, 0xd5, 0x56, 0x56, 0xd6, 0xdb, 0x76, 0x80, 0xcb, 0x46, 0x89, 0x18, 0x4c, 0x81, 0x87, 0x3e,
-	0xa0, 0x54, 0x6c, 0x02, 0x21, 0x78, 0x02, 0x6d, 0xd3, 0xa4, 0x4e, 0xac, 0x84, 0x4a, 0x48, 0x08,
-	0x29, 0x72, 0x93, 0xbb, 0xd4, 0x6a, 0x6a, 0x67, 0xce, 0x0d, 0x49, 0xfe, 0x0f, 0x3f, 0x14, 0xe5,
-	0xa3, 0x5d, 0x4a, 0x19, 0x4f, 0x71, 0x8e, 0xcf, 0x3d, 0xbe, 0xd7, 0xe7, 0x18, 0x9e, 0x26, 0x3c,
-	0x08, 0x90, 0x74, 0xe8, 0x0e, 0xcb, 0xd5, 0x5c, 0x90, 0x15, 0x6a, 0x45, 0x8a, 0xb5, 0x56, 0x5b,
-	0xc6, 0xe3, 0x48, 0xf8, 0x32, 0xe7, 0xe4, 0x5f, 0xd4, 0x25, 0xc1, 0xfc, 0x0a, 0xcd, 0x11, 0x66,
-	0x36, 0xde, 0xb0, 0x01, 0x3c, 0x9c, 0x63, 0xe6, 0x5c, 0x0b, 0xe9, 0xa3, 0x76, 0x42, 0x2d, 0x24,
-	0xf5, 0xb7, 0x8e, 0xb6, 0x06, 0x3b, 0xf6, 0xde, 0x1c, 0xb3, 0xf3, 0x02, 0x1e, 0xe7, 0x28, 0x3b,
-	0x04, 0x28, 0x98, 0x7c, 0x21, 0x82, 0xac, 0xbf, 0x5d, 0x70, 0x5a, 0x39, 0xa7, 0x00, 0xcc, 0x2e,
-	0xb4, 0x3f, 0x79, 0x9e, 0xb6, 0xf1, 0x26, 0xc6, 0x88, 0x4c, 0x13, 0x3a, 0xe5, 0x6f, 0x14, 0x2a,
-	0x19, 0x21, 0x63, 0x70, 0x8f, 0x7b, 0x9e, 0x2e, 0xb4, 0x5b, 0x76, 0xb1, 0x36, 0x5f, 0x41, 0x7b,
-	0xa2, 0xb9, 0x8c, 0xb8, 0x4b, 0x42, 0x49, 0xb6, 0x0f, 0x4d, 0x4a, 0x9d, 0x19, 0xa6, 0x05, 0xa9,
-	0x63, 0xef, 0x50, 0x7a, 0x81, 0xa9, 0xf9, 0x0e, 0x1e, 0x8c, 0xe3, 0x69, 0x20, 0xa2, 0xd9, 0x4a,
-	0xec, 0x25, 0x74, 0xc3, 0x12, 0x72, 0x50, 0x6b, 0xb5, 0x54, 0xed, 0x54, 0xe0, 0x59, 0x8e, 0x99,
-	0x3f, 0x81, 0x7d, 0x43, 0xe9, 0x5d, 0xc5, 0x14, 0xc6, 0x14, 0x55, 0x7d, 0xb1, 0x67, 0x00, 0x11,
-	0x27, 0x27, 0x44, 0xed, 0xcc, 0x93, 0xa2, 0xae, 0x61, 0xef, 0x46, 0x9c, 0xc6, 0xa8, 0x47, 0x09,
-	0x1b, 0xc0, 0x7d, 0x55, 0xf2, 0xfb, 0xdb, 0x47, 0x8d, 0x41, 0xfb, 0x78, 0xcf, 0xaa, 0xee, 0xcf,
-	0x9a, 0xa4, 0x57, 0x31, 0xd9, 0xcb, 0x6d, 0xf3, 0x35, 0xf4, 0xd6, 0xd4, 0xab, 0xce, 0xf6, 0xa1,
-	0xa9, 0x79, 0xe2, 0xd0, 0x6a, 0x06, 0xcd, 0x93, 0x49, 0x6a, 0xbe, 0x05, 0x76, 0x16, 0x91, 0x58,
-	0x70, 0xc2, 0x73, 0xc4, 0x65, 0x2f, 0x2f, 0xa0, 0xed, 0x2a, 0x79, 0xed, 0x10, 0xd7, 0x3e, 0x2e,
-	0xaf, 0x1d, 0x72, 0x68, 0x52, 0x20, 0xe6, 0x09, 0xf4, 0xd6, 0xca, 0xaa, 0x43, 0xfe, 0x3b, 0xc3,
-	0xf1, 0xef, 0x06, 0xb4, 0xbe, 0x17, 0xfe, 0x8f, 0x04, 0xb1, 0x0f, 0xd0, 0x3d, 0x45, 0x2d, 0x7e,
-	0xe1, 0x17, 0x4c, 0x69, 0x84, 0x19, 0x7b, 0x64, 0xad, 0xc2, 0x61, 0x95, 0x19, 0x30, 0x0e, 0x56,
-	0x43, 0x8e, 0x30, 0x3b, 0xc5, 0xc8, 0xd5, 0x22, 0x24, 0xa5, 0xd9, 0x7b, 0x68, 0x95, 0xb5, 0x79,
-	0x5d, 0xaf, 0x4e, 0xba, 0x54, 0x2e, 0x27, 0xa5, 0xef, 0xac, 0xfc, 0x08, 0xbb, 0xf9, 0x79, 0x79,
-	0x02, 0xd8, 0x41, 0xed, 0xc0, 0x5a, 0x42, 0x8c, 0x27, 0x1b, 0x78, 0x35, 0xde, 0x05, 0xb0, 0xca,
-	0xf0, 0x7a, 0x3a, 0xea, 0x32, 0x35, 0xdc, 0x30, 0x6a, 0xf8, 0xdf, 0x39, 0xb9, 0x84, 0x76, 0xcd,
-	0x24, 0x76, 0x58, 0xa3, 0x6e, 0x46, 0xc3, 0x78, 0x7e, 0xd7, 0xf6, 0xad, 0x5a, 0xcd, 0x8d, 0x35,
-	0xb5, 0x4d, 0x73, 0xd7, 0xd4, 0xfe, 0x61, 0xe2, 0xe7, 0x37, 0x3f, 0x86, 0xbe, 0xa0, 0x59, 0x3c,
-	0xb5, 0x5c, 0xb5, 0x18, 0x06, 0xc2, 0x9f, 0x91, 0x14, 0xd2, 0x97, 0x48, 0x89, 0xd2, 0xf3, 0x61,
-	0x20, 0xbd, 0x61, 0x20, 0x6f, 0x1f, 0xb7, 0x0e, 0xdd, 0x69, 0xb3, 0x78, 0xbc, 0x27, 0x7f, 0x02,
-	0x00, 0x00, 0xff, 0xff, 0x2d, 0xbb, 0xcd, 0x97, 0xfa, 0x03, 0x00, 0x00,
+	proto.RegisterFile("walletrpc/walletkit.proto", fileDescriptor_walletkit_097c37014bf95d06)
+}
+
+var fileDescriptor_walletkit_097c37014bf95d06 = []byte{
+	// 1111 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xff, 0x4e, 0x1b, 0x47,
+	0x10, 0x16, 0x31, 0x10, 0x7b, 0x6c, 0x93, 0xb0, 0xb6, 0xc1, 0x39, 0x25, 0xe0, 0x5e, 0xfa, 0x07,
+	0xaa, 0x5a, 0xd3, 0x82, 0x5a, 0x55, 0xad, 0x54, 0x15, 0x12, 0x12, 0x22, 0x50, 0xa1, 0x07, 0x52,
+	0xa4, 0xb6, 0xd2, 0x69, 0x7d, 0x1e, 0xcc, 0x0a, 0xfb, 0xee, 0xb2, 0xb7, 0x8e, 0xed, 0x07, 0xe8,
+	0x4b, 0xf4, 0x0d, 0xfa, 0x96, 0xd5, 0xfe, 0xb8, 0xbb, 0x3d, 0xff, 0x88, 0x14, 0xa9, 0x7f, 0xf9,
+	0xf6, 0x9b, 0x6f, 0xbe, 0x9d, 0x9d, 0xd9, 0x9d, 0x31, 0x3c, 0x9b, 0xd0, 0xe1, 0x10, 0x05, 0x8f,
+	0x83, 0x43, 0xfd, 0xf5, 0xc0, 0x44, 0x37, 0xe6, 0x91, 0x88, 0x48, 0x25, 0x33, 0x39, 0xcd, 0x84,
+	0x0d, 0x42, 0xc9, 0x91, 0xbf, 0xc8, 0x35, 0xc1, 0xfd, 0x1d, 0x36, 0x2f, 0x70, 0xe6, 0xe1, 0x07,
+	0x72, 0x00, 0x4f, 0x1f, 0x70, 0xe6, 0xdf, 0xb1, 0x70, 0x80, 0xdc, 0x8f, 0x39, 0x0b, 0x45, 0x7b,
+	0xad, 0xb3, 0x76, 0xb0, 0xe1, 0x6d, 0x3d, 0xe0, 0xec, 0x8d, 0x82, 0xaf, 0x25, 0x4a, 0x5e, 0x00,
+	0x28, 0x26, 0x1d, 0xb1, 0xe1, 0xac, 0xfd, 0x48, 0x71, 0x2a, 0x92, 0xa3, 0x00, 0xb7, 0x0e, 0xd5,
+	0x93, 0x7e, 0x9f, 0x7b, 0xf8, 0x61, 0x8c, 0x89, 0x70, 0x5d, 0xa8, 0xe9, 0x65, 0x12, 0x47, 0x61,
+	0x82, 0x84, 0xc0, 0x3a, 0xed, 0xf7, 0xb9, 0xd2, 0xae, 0x78, 0xea, 0xdb, 0xfd, 0x12, 0xaa, 0xb7,
+	0x9c, 0x86, 0x09, 0x0d, 0x04, 0x8b, 0x42, 0xd2, 0x82, 0x4d, 0x31, 0xf5, 0xef, 0x71, 0xaa, 0x48,
+	0x35, 0x6f, 0x43, 0x4c, 0xcf, 0x71, 0xea, 0xfe, 0x00, 0x4f, 0xae, 0xc7, 0xbd, 0x21, 0x4b, 0xee,
+	0x33, 0xb1, 0x97, 0x50, 0x8f, 0x35, 0xe4, 0x23, 0xe7, 0x51, 0xaa, 0x5a, 0x33, 0xe0, 0x99, 0xc4,
+	0xdc, 0xbf, 0x80, 0xdc, 0x60, 0xd8, 0xbf, 0x1a, 0x8b, 0x78, 0x2c, 0x12, 0x13, 0x17, 0x79, 0x0e,
+	0x90, 0x50, 0xe1, 0xc7, 0xc8, 0xfd, 0x87, 0x89, 0xf2, 0x2b, 0x79, 0xe5, 0x84, 0x8a, 0x6b, 0xe4,
+	0x17, 0x13, 0x72, 0x00, 0x8f, 0x23, 0xcd, 0x6f, 0x3f, 0xea, 0x94, 0x0e, 0xaa, 0x47, 0x5b, 0x5d,
+	0x93, 0xbf, 0xee, 0xed, 0xf4, 0x6a, 0x2c, 0xbc, 0xd4, 0xec, 0x7e, 0x0d, 0x8d, 0x82, 0xba, 0x89,
+	0xac, 0x05, 0x9b, 0x9c, 0x4e, 0x7c, 0x91, 0x9d, 0x81, 0xd3, 0xc9, 0xed, 0xd4, 0xfd, 0x1e, 0xc8,
+	0x59, 0x22, 0xd8, 0x88, 0x0a, 0x7c, 0x83, 0x98, 0xc6, 0xb2, 0x0f, 0xd5, 0x20, 0x0a, 0xef, 0x7c,
+	0x41, 0xf9, 0x00, 0xd3, 0xb4, 0x83, 0x84, 0x6e, 0x15, 0xe2, 0x1e, 0x43, 0xa3, 0xe0, 0x66, 0x36,
+	0xf9, 0xe4, 0x19, 0xdc, 0x13, 0x28, 0x5f, 0x8d, 0xc5, 0x75, 0x24, 0x6b, 0x46, 0x60, 0x5d, 0x4c,
+	0x59, 0xdf, 0x04, 0xa3, 0xbe, 0xc9, 0x17, 0x50, 0xd3, 0x87, 0xf0, 0x59, 0xd8, 0xc7, 0xa9, 0xaa,
+	0x64, 0xdd, 0xab, 0x6a, 0xec, 0x9d, 0x84, 0xdc, 0x33, 0x20, 0x97, 0x48, 0x13, 0xd4, 0xa7, 0x4b,
+	0xc3, 0x3d, 0x84, 0xb2, 0x24, 0x45, 0xe9, 0x15, 0xa9, 0x1e, 0x35, 0xba, 0xd9, 0x45, 0xeb, 0xa6,
+	0x7b, 0x7a, 0x19, 0xc9, 0x6d, 0x41, 0xa3, 0x20, 0xa3, 0xc3, 0x77, 0xdf, 0x42, 0xd3, 0xc3, 0xe1,
+	0xff, 0xa0, 0xbf, 0x0b, 0xad, 0x39, 0x21, 0xb3, 0x43, 0x03, 0xb6, 0x2f, 0x59, 0x22, 0xd4, 0xe6,
+	0x69, 0xe5, 0xdd, 0xb7, 0x40, 0x6c, 0xd0, 0xe4, 0xf2, 0x3b, 0xa8, 0xa4, 0x7a, 0x49, 0x7b, 0xad,
+	0x53, 0x5a, 0xb5, 0x6b, 0xce, 0x72, 0xff, 0x2e, 0x41, 0xed, 0x1a, 0xc3, 0x3e, 0x0b, 0x07, 0x37,
+	0x13, 0xc4, 0xf8, 0xb3, 0x03, 0x97, 0x25, 0x98, 0x30, 0x11, 0x62, 0x92, 0xf8, 0x62, 0x16, 0xa3,
+	0x2a, 0x41, 0xc5, 0xab, 0x1a, 0xec, 0x76, 0x16, 0xa3, 0x7c, 0x6d, 0x74, 0x14, 0x8d, 0x43, 0xe1,
+	0x27, 0x54, 0xb4, 0x4b, 0xaa, 0x46, 0x15, 0x8d, 0xdc, 0x50, 0x41, 0x3a, 0x50, 0x4b, 0xaf, 0x40,
+	0x6f, 0x26, 0xb0, 0xbd, 0xae, 0x08, 0xa0, 0x2f, 0xc1, 0xe9, 0x4c, 0x20, 0xf9, 0x06, 0x48, 0x8f,
+	0x47, 0xb4, 0x1f, 0xd0, 0x44, 0xf8, 0x54, 0x08, 0x1c, 0xc5, 0x22, 0x69, 0x6f, 0x28, 0xde, 0x76,
+	0x66, 0x39, 0x31, 0x06, 0x72, 0x04, 0xad, 0x10, 0xa7, 0xc2, 0xcf, 0x7d, 0xee, 0x91, 0x0d, 0xee,
+	0x45, 0x7b, 0x53, 0x79, 0x34, 0xa4, 0xf1, 0x34, 0xb5, 0x9d, 0x2b, 0x93, 0xf4, 0xe1, 0x3a, 0xb9,
+	0xd8, 0xf7, 0xed, 0x9b, 0xfc, 0x58, 0xfb, 0x64, 0xc6, 0x57, 0xd9, 0x95, 0x26, 0xc7, 0xb0, 0x93,
+	0xfb, 0x14, 0x8e, 0x50, 0x9e, 0x73, 0xba, 0xc9, 0xcf, 0xd2, 0x84, 0x8d, 0xbb, 0x88, 0x07, 0xd8,
+	0xae, 0x74, 0xd6, 0x0e, 0xca, 0x9e, 0x5e, 0xb8, 0x3b, 0xd0, 0xb4, 0xcb, 0x90, 0x15, 0xfa, 0x3d,
+	0xb4, 0xe6, 0x70, 0x53, 0xeb, 0x5f, 0x60, 0x2b, 0xd6, 0x06, 0x3f, 0x51, 0x16, 0x53, 0xf0, 0x5d,
+	0xab, 0x5a, 0xb6, 0xa7, 0x57, 0x8f, 0x6d, 0x1d, 0xf7, 0x9f, 0x35, 0xd8, 0x3a, 0x1d, 0x8f, 0x62,
+	0xeb, 0x09, 0x7f, 0x76, 0xe9, 0xf7, 0xa1, 0xaa, 0x93, 0xa4, 0x12, 0x66, 0x1e, 0x1f, 0x68, 0x48,
+	0xa6, 0x69, 0xa1, 0xb2, 0xa5, 0x85, 0xca, 0x66, 0xd9, 0x58, 0xb7, 0xb3, 0xb1, 0x0d, 0x4f, 0xb2,
+	0xd8, 0xcc, 0x33, 0xa0, 0xb0, 0x7b, 0x49, 0x7b, 0x38, 0xb4, 0x9a, 0x6c, 0x1a, 0xf7, 0xb2, 0xc6,
+	0xd0, 0x84, 0x8d, 0xa1, 0xa4, 0x9b, 0xeb, 0xa8, 0x17, 0xe4, 0x39, 0x54, 0xa2, 0x8f, 0xc8, 0x27,
+	0x9c, 0x99, 0x60, 0xca, 0x5e, 0x0e, 0xb8, 0x0e, 0xb4, 0x17, 0xb7, 0x30, 0xdb, 0xcb, 0xe7, 0xcf,
+	0x12, 0x71, 0x12, 0x04, 0xf2, 0xd6, 0x66, 0xe5, 0x39, 0x82, 0x66, 0x11, 0x36, 0xd5, 0x71, 0xa0,
+	0x4c, 0x0d, 0xa6, 0xea, 0x52, 0xf1, 0xb2, 0xb5, 0xfb, 0x15, 0x34, 0x5f, 0x71, 0xa4, 0x02, 0x8d,
+	0x97, 0x75, 0x8c, 0x90, 0x8e, 0x30, 0x9d, 0x2a, 0xf2, 0x5b, 0x76, 0x85, 0x39, 0xae, 0xde, 0xe0,
+	0xe8, 0xdf, 0x32, 0x54, 0xde, 0xab, 0xda, 0x5c, 0x30, 0x41, 0x7e, 0x82, 0xfa, 0x6b, 0xe4, 0xec,
+	0x23, 0xfe, 0x86, 0x53, 0x71, 0x81, 0x33, 0xb2, 0x6d, 0x15, 0x4e, 0x0f, 0x47, 0x67, 0x27, 0xeb,
+	0xfe, 0x17, 0x38, 0x7b, 0x8d, 0x49, 0xc0, 0x59, 0x2c, 0x22, 0x4e, 0x7e, 0x84, 0x8a, 0xf6, 0x95,
+	0x7e, 0x0d, 0x9b, 0x74, 0x19, 0x05, 0x54, 0x44, 0x7c, 0xa5, 0xe7, 0xcf, 0x50, 0x96, 0xfb, 0xc9,
+	0xd1, 0x48, 0x76, 0xac, 0x0d, 0xad, 0xd1, 0xe9, 0xec, 0x2e, 0xe0, 0x26, 0x43, 0xe7, 0x40, 0xcc,
+	0x24, 0xb4, 0xc7, 0xa6, 0x2d, 0x63, 0xe1, 0x8e, 0x63, 0xdf, 0xea, 0xb9, 0x01, 0x7a, 0x09, 0x55,
+	0x6b, 0x7a, 0x91, 0x17, 0x16, 0x75, 0x71, 0x66, 0x3a, 0x7b, 0xab, 0xcc, 0xb9, 0x9a, 0x35, 0xa6,
+	0x0a, 0x6a, 0x8b, 0x53, 0xcf, 0xd9, 0x5b, 0x65, 0xce, 0xd5, 0xac, 0xa9, 0x51, 0x50, 0x5b, 0x1c,
+	0x4a, 0xce, 0xde, 0x2a, 0xb3, 0x51, 0xf3, 0xa0, 0x5e, 0x98, 0x11, 0x64, 0xdf, 0x72, 0x58, 0x36,
+	0x86, 0x9c, 0xce, 0x6a, 0x82, 0xd1, 0x7c, 0x07, 0x90, 0x4f, 0x12, 0xf2, 0xdc, 0x8e, 0x60, 0x7e,
+	0xea, 0x38, 0x2f, 0x56, 0x58, 0xf3, 0xf0, 0x0a, 0xbd, 0xaa, 0x10, 0xde, 0xb2, 0xee, 0xe6, 0x74,
+	0x56, 0x13, 0x8c, 0xe6, 0xaf, 0xf0, 0xd8, 0x74, 0x02, 0xf2, 0xcc, 0x22, 0x17, 0x3b, 0x97, 0xe3,
+	0x2c, 0x33, 0x19, 0x85, 0x3f, 0xe1, 0xe9, 0xfc, 0xab, 0x26, 0xae, 0x7d, 0x90, 0xe5, 0x5d, 0xc5,
+	0x79, 0xf9, 0x49, 0x8e, 0x11, 0xbf, 0x82, 0x9a, 0xfd, 0xfe, 0xc9, 0xde, 0x5c, 0x86, 0xe6, 0xfa,
+	0x85, 0xb3, 0xbf, 0xd2, 0x9e, 0xe7, 0xb0, 0xf0, 0xe0, 0x0b, 0x39, 0x5c, 0xd6, 0x36, 0x9c, 0xce,
+	0x6a, 0x82, 0xd6, 0x3c, 0xfd, 0xf6, 0x8f, 0xee, 0x80, 0x89, 0xfb, 0x71, 0xaf, 0x1b, 0x44, 0xa3,
+	0xc3, 0x21, 0x13, 0x18, 0x44, 0x2c, 0xbc, 0x63, 0x21, 0x0d, 0x03, 0x3c, 0x1c, 0x86, 0xfd, 0xc3,
+	0x61, 0x98, 0xff, 0xf3, 0xe6, 0x71, 0xd0, 0xdb, 0x54, 0xff, 0xac, 0x8f, 0xff, 0x1b, 0x00, 0x4b,
+	0xc9, 0xce, 0x48, 0x97, 0x0b, 0x00, 0x00,
 }
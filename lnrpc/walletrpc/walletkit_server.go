@@ -9,11 +9,13 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/lnd/keychain"
 	"github.com/litecoinfinance/lnd/lnrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/signrpc"
 	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/sweep"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"gopkg.in/macaroon-bakery.v2/bakery"
@@ -74,6 +76,38 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/walletrpc.WalletKit/LeaseOutput": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/ReleaseOutput": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/ListLeases": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/PendingSweeps": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/BumpFee": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/LabelTransaction": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/ListAccounts": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/CreateAccount": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
 	}
 
 	// DefaultWalletKitMacFilename is the default name of the wallet kit
@@ -331,3 +365,226 @@ func (w *WalletKit) EstimateFee(ctx context.Context,
 		SatPerKw: int64(satPerKw),
 	}, nil
 }
+
+// unmarshallOutPoint converts an OutPoint of the RPC into its wire format.
+func unmarshallOutPoint(op *OutPoint) (*wire.OutPoint, error) {
+	switch {
+	case op == nil:
+		return nil, fmt.Errorf("must provide an outpoint")
+
+	case len(op.Txid) != chainhash.HashSize:
+		return nil, fmt.Errorf("txid must be %v bytes, is %v bytes",
+			chainhash.HashSize, len(op.Txid))
+	}
+
+	var outPoint wire.OutPoint
+	copy(outPoint.Hash[:], op.Txid)
+	outPoint.Index = op.OutputIndex
+
+	return &outPoint, nil
+}
+
+// LeaseOutput locks an output of the wallet, such that it isn't selected to
+// fund any transaction of lnd until it's released.
+func (w *WalletKit) LeaseOutput(ctx context.Context,
+	req *LeaseOutputRequest) (*LeaseOutputResponse, error) {
+
+	outPoint, err := unmarshallOutPoint(req.Outpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.cfg.Wallet.LeaseOutput(*outPoint); err != nil {
+		return nil, err
+	}
+
+	return &LeaseOutputResponse{}, nil
+}
+
+// ReleaseOutput unlocks an output that was leased through LeaseOutput.
+func (w *WalletKit) ReleaseOutput(ctx context.Context,
+	req *ReleaseOutputRequest) (*ReleaseOutputResponse, error) {
+
+	outPoint, err := unmarshallOutPoint(req.Outpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.cfg.Wallet.ReleaseOutput(*outPoint); err != nil {
+		return nil, err
+	}
+
+	return &ReleaseOutputResponse{}, nil
+}
+
+// ListLeases lists all outputs of the wallet that are currently leased.
+func (w *WalletKit) ListLeases(ctx context.Context,
+	req *ListLeasesRequest) (*ListLeasesResponse, error) {
+
+	leased := w.cfg.Wallet.LeasedOutputs()
+
+	outPoints := make([]*OutPoint, 0, len(leased))
+	for _, outPoint := range leased {
+		outPoint := outPoint
+		outPoints = append(outPoints, &OutPoint{
+			Txid:        outPoint.Hash[:],
+			OutputIndex: outPoint.Index,
+		})
+	}
+
+	return &ListLeasesResponse{
+		Outpoints: outPoints,
+	}, nil
+}
+
+// PendingSweeps returns lists of on-chain outputs that lnd is currently
+// attempting to sweep within its central batching engine.
+func (w *WalletKit) PendingSweeps(ctx context.Context,
+	req *PendingSweepsRequest) (*PendingSweepsResponse, error) {
+
+	pendingInputs, err := w.cfg.Sweeper.PendingInputs()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcPendingSweeps := make([]*PendingSweep, 0, len(pendingInputs))
+	for _, pendingInput := range pendingInputs {
+		outPoint := pendingInput.OutPoint
+		satPerByte := uint32(
+			pendingInput.LastFeeRate.FeePerKVByte() / 1000,
+		)
+
+		params := pendingInput.Params
+		requestedSatPerByte := uint32(
+			params.Fee.FeeRate.FeePerKVByte() / 1000,
+		)
+
+		broadcastAttempts := uint32(pendingInput.BroadcastAttempts)
+
+		rpcPendingSweeps = append(rpcPendingSweeps, &PendingSweep{
+			Outpoint: &OutPoint{
+				Txid:        outPoint.Hash[:],
+				OutputIndex: outPoint.Index,
+			},
+			WitnessType:         pendingInput.WitnessType.String(),
+			AmountSat:           uint32(pendingInput.Amount),
+			SatPerByte:          satPerByte,
+			BroadcastAttempts:   broadcastAttempts,
+			NextBroadcastHeight: pendingInput.NextBroadcastHeight,
+			RequestedConfTarget: params.Fee.ConfTarget,
+			RequestedSatPerByte: requestedSatPerByte,
+			Force:               params.Force,
+		})
+	}
+
+	return &PendingSweepsResponse{
+		PendingSweeps: rpcPendingSweeps,
+	}, nil
+}
+
+// BumpFee bumps the fee of an output that lnd is currently attempting to
+// sweep, by replacing its sweep transaction with one paying the new fee rate.
+func (w *WalletKit) BumpFee(ctx context.Context,
+	req *BumpFeeRequest) (*BumpFeeResponse, error) {
+
+	outPoint, err := unmarshallOutPoint(req.Outpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	feePreference := sweep.FeePreference{
+		ConfTarget: req.TargetConf,
+		FeeRate: lnwallet.SatPerKVByte(
+			req.SatPerByte * 1000,
+		).FeePerKWeight(),
+	}
+
+	switch {
+	case req.TargetConf != 0 && req.SatPerByte != 0:
+		return nil, fmt.Errorf("either target_conf or sat_per_byte " +
+			"must be set, not both")
+
+	// If the caller only wants the output to be swept right away, we'll
+	// retain its current fee preference.
+	case req.TargetConf == 0 && req.SatPerByte == 0:
+		if !req.Force {
+			return nil, fmt.Errorf("either target_conf or " +
+				"sat_per_byte must be set")
+		}
+
+		pendingInputs, err := w.cfg.Sweeper.PendingInputs()
+		if err != nil {
+			return nil, err
+		}
+		pendingInput, ok := pendingInputs[*outPoint]
+		if !ok {
+			return nil, fmt.Errorf("output %v is not being swept",
+				outPoint)
+		}
+		feePreference = pendingInput.Params.Fee
+	}
+
+	params := sweep.Params{
+		Fee:   feePreference,
+		Force: req.Force,
+	}
+	_, err = w.cfg.Sweeper.UpdateParams(*outPoint, params)
+	switch {
+	case err == sweep.ErrUnknownInput:
+		return nil, fmt.Errorf("output %v is not being swept",
+			outPoint)
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &BumpFeeResponse{}, nil
+}
+
+// LabelTransaction adds a label to a transaction of the wallet. Existing
+// labels are only replaced if overwrite is set.
+func (w *WalletKit) LabelTransaction(ctx context.Context,
+	req *LabelTransactionRequest) (*LabelTransactionResponse, error) {
+
+	hash, err := chainhash.NewHash(req.Txid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.cfg.Wallet.LabelTransaction(*hash, req.Label, req.Overwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelTransactionResponse{}, nil
+}
+
+// ListAccounts lists the names of all accounts of the wallet.
+func (w *WalletKit) ListAccounts(ctx context.Context,
+	req *ListAccountsRequest) (*ListAccountsResponse, error) {
+
+	accounts, err := w.cfg.Wallet.ListAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListAccountsResponse{
+		Accounts: accounts,
+	}, nil
+}
+
+// CreateAccount creates a new account within the wallet, whose funds are kept
+// apart from those lnd uses on its own.
+func (w *WalletKit) CreateAccount(ctx context.Context,
+	req *CreateAccountRequest) (*CreateAccountResponse, error) {
+
+	if req.Name == "" {
+		return nil, fmt.Errorf("account name must be set")
+	}
+
+	if err := w.cfg.Wallet.CreateAccount(req.Name); err != nil {
+		return nil, err
+	}
+
+	return &CreateAccountResponse{}, nil
+}
@@ -0,0 +1,58 @@
+// +build wtclientrpc
+
+package wtclientrpc
+
+import (
+	"net"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/lnd/macaroons"
+	"github.com/litecoinfinance/lnd/watchtower/wtclient"
+)
+
+// Backend is the interface of the watchtower client inspected by the
+// watchtower client RPC server. It is satisfied by *wtclient.TowerClient.
+type Backend interface {
+	// ListSessions returns the status of all sessions negotiated by the
+	// client that have not yet been exhausted.
+	ListSessions() ([]*wtclient.SessionStatus, error)
+
+	// SessionStats returns the accounting of the backups made to each
+	// tower, across all sessions negotiated with it.
+	SessionStats() ([]*wtclient.TowerStats, error)
+
+	// UpdateTowerAddresses replaces the addresses of the tower with the
+	// given public key, in order of preference.
+	UpdateTowerAddresses(*btcec.PublicKey, []net.Addr) error
+}
+
+// Config is the primary configuration struct for the watchtower client RPC
+// server. It contains all the items required for the rpc server to carry out
+// its duties. The fields with struct tags are meant to be parsed as normal
+// configuration options, while if able to be populated, the latter fields MUST
+// also be specified.
+type Config struct {
+	// WatchtowerClientMacPath is the path for the watchtower client
+	// macaroon. If unspecified then we assume that the macaroon will be
+	// found under the network directory, named
+	// DefaultWatchtowerClientMacFilename.
+	WatchtowerClientMacPath string `long:"wtclientmacaroonpath" description:"Path to the watchtower client macaroon"`
+
+	// NetworkDir is the main network directory wherein the watchtower
+	// client rpc server will find the macaroon named
+	// DefaultWatchtowerClientMacFilename.
+	NetworkDir string
+
+	// MacService is the main macaroon service that we'll use to handle
+	// authentication for the watchtower client rpc server.
+	MacService *macaroons.Service
+
+	// Client is the watchtower client inspected by the RPC server. If nil,
+	// all calls fail with ErrClientNotActive.
+	Client Backend
+
+	// Resolver is used to resolve the tower addresses given to the RPC
+	// server, such that they're resolved over Tor if lnd is configured
+	// to use it.
+	Resolver func(network, address string) (*net.TCPAddr, error)
+}
@@ -0,0 +1,6 @@
+// +build !wtclientrpc
+
+package wtclientrpc
+
+// Config is empty for non-wtclientrpc builds.
+type Config struct{}
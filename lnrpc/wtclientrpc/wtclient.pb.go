@@ -0,0 +1,825 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: wtclientrpc/wtclient.proto
+
+package wtclientrpc // import "github.com/litecoinfinance/lnd/lnrpc/wtclientrpc"
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ListSessionsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSessionsRequest) Reset()         { *m = ListSessionsRequest{} }
+func (m *ListSessionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsRequest) ProtoMessage()    {}
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{0}
+}
+func (m *ListSessionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionsRequest.Unmarshal(m, b)
+}
+func (m *ListSessionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListSessionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionsRequest.Merge(dst, src)
+}
+func (m *ListSessionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSessionsRequest.Size(m)
+}
+func (m *ListSessionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionsRequest proto.InternalMessageInfo
+
+type Session struct {
+	// / The session id, which is the session key of the client.
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// / The public key of the tower the session was negotiated with.
+	TowerPubkey []byte `protobuf:"bytes,2,opt,name=tower_pubkey,json=towerPubkey,proto3" json:"tower_pubkey,omitempty"`
+	// / The blob type negotiated for the session.
+	BlobType uint32 `protobuf:"varint,3,opt,name=blob_type,json=blobType,proto3" json:"blob_type,omitempty"`
+	// / The maximum number of backups the tower will accept for the session.
+	MaxBackups uint32 `protobuf:"varint,4,opt,name=max_backups,json=maxBackups,proto3" json:"max_backups,omitempty"`
+	// / The number of backups committed to the session.
+	NumBackups uint32 `protobuf:"varint,5,opt,name=num_backups,json=numBackups,proto3" json:"num_backups,omitempty"`
+	// / The number of committed backups that have yet to be acked by the tower.
+	NumPendingBackups uint32 `protobuf:"varint,6,opt,name=num_pending_backups,json=numPendingBackups,proto3" json:"num_pending_backups,omitempty"`
+	// *
+	// The number of additional backups the session can hold before it is
+	// exhausted, after which a replacement session is negotiated.
+	RemainingBackups uint32 `protobuf:"varint,7,opt,name=remaining_backups,json=remainingBackups,proto3" json:"remaining_backups,omitempty"`
+	// / The fee rate in sat/kw justice transactions are swept with.
+	SweepFeeRate         int64    `protobuf:"varint,8,opt,name=sweep_fee_rate,json=sweepFeeRate,proto3" json:"sweep_fee_rate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Session) Reset()         { *m = Session{} }
+func (m *Session) String() string { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()    {}
+func (*Session) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{1}
+}
+func (m *Session) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Session.Unmarshal(m, b)
+}
+func (m *Session) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Session.Marshal(b, m, deterministic)
+}
+func (dst *Session) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Session.Merge(dst, src)
+}
+func (m *Session) XXX_Size() int {
+	return xxx_messageInfo_Session.Size(m)
+}
+func (m *Session) XXX_DiscardUnknown() {
+	xxx_messageInfo_Session.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Session proto.InternalMessageInfo
+
+func (m *Session) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Session) GetTowerPubkey() []byte {
+	if m != nil {
+		return m.TowerPubkey
+	}
+	return nil
+}
+
+func (m *Session) GetBlobType() uint32 {
+	if m != nil {
+		return m.BlobType
+	}
+	return 0
+}
+
+func (m *Session) GetMaxBackups() uint32 {
+	if m != nil {
+		return m.MaxBackups
+	}
+	return 0
+}
+
+func (m *Session) GetNumBackups() uint32 {
+	if m != nil {
+		return m.NumBackups
+	}
+	return 0
+}
+
+func (m *Session) GetNumPendingBackups() uint32 {
+	if m != nil {
+		return m.NumPendingBackups
+	}
+	return 0
+}
+
+func (m *Session) GetRemainingBackups() uint32 {
+	if m != nil {
+		return m.RemainingBackups
+	}
+	return 0
+}
+
+func (m *Session) GetSweepFeeRate() int64 {
+	if m != nil {
+		return m.SweepFeeRate
+	}
+	return 0
+}
+
+type ListSessionsResponse struct {
+	// / The sessions of the client that have not been exhausted.
+	Sessions             []*Session `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListSessionsResponse) Reset()         { *m = ListSessionsResponse{} }
+func (m *ListSessionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsResponse) ProtoMessage()    {}
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{2}
+}
+func (m *ListSessionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionsResponse.Unmarshal(m, b)
+}
+func (m *ListSessionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListSessionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionsResponse.Merge(dst, src)
+}
+func (m *ListSessionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSessionsResponse.Size(m)
+}
+func (m *ListSessionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionsResponse proto.InternalMessageInfo
+
+func (m *ListSessionsResponse) GetSessions() []*Session {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+type UpdateTowerAddressesRequest struct {
+	// / The public key of the tower whose addresses should be replaced.
+	Pubkey []byte `protobuf:"bytes,1,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	// *
+	// The addresses at which the tower can be reached, in order of preference.
+	// Each address is of the form host:port, where the host may be a v2 or v3
+	// onion address if lnd is configured to use Tor. If the port is omitted, the
+	// default watchtower port is used.
+	Addresses            []string `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateTowerAddressesRequest) Reset()         { *m = UpdateTowerAddressesRequest{} }
+func (m *UpdateTowerAddressesRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateTowerAddressesRequest) ProtoMessage()    {}
+func (*UpdateTowerAddressesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{3}
+}
+func (m *UpdateTowerAddressesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTowerAddressesRequest.Unmarshal(m, b)
+}
+func (m *UpdateTowerAddressesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTowerAddressesRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateTowerAddressesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTowerAddressesRequest.Merge(dst, src)
+}
+func (m *UpdateTowerAddressesRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateTowerAddressesRequest.Size(m)
+}
+func (m *UpdateTowerAddressesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTowerAddressesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTowerAddressesRequest proto.InternalMessageInfo
+
+func (m *UpdateTowerAddressesRequest) GetPubkey() []byte {
+	if m != nil {
+		return m.Pubkey
+	}
+	return nil
+}
+
+func (m *UpdateTowerAddressesRequest) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type UpdateTowerAddressesResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateTowerAddressesResponse) Reset()         { *m = UpdateTowerAddressesResponse{} }
+func (m *UpdateTowerAddressesResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateTowerAddressesResponse) ProtoMessage()    {}
+func (*UpdateTowerAddressesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{4}
+}
+func (m *UpdateTowerAddressesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTowerAddressesResponse.Unmarshal(m, b)
+}
+func (m *UpdateTowerAddressesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTowerAddressesResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdateTowerAddressesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTowerAddressesResponse.Merge(dst, src)
+}
+func (m *UpdateTowerAddressesResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateTowerAddressesResponse.Size(m)
+}
+func (m *UpdateTowerAddressesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTowerAddressesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTowerAddressesResponse proto.InternalMessageInfo
+
+type WtclientSessionStatsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WtclientSessionStatsRequest) Reset()         { *m = WtclientSessionStatsRequest{} }
+func (m *WtclientSessionStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*WtclientSessionStatsRequest) ProtoMessage()    {}
+func (*WtclientSessionStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{5}
+}
+func (m *WtclientSessionStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WtclientSessionStatsRequest.Unmarshal(m, b)
+}
+func (m *WtclientSessionStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WtclientSessionStatsRequest.Marshal(b, m, deterministic)
+}
+func (dst *WtclientSessionStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WtclientSessionStatsRequest.Merge(dst, src)
+}
+func (m *WtclientSessionStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_WtclientSessionStatsRequest.Size(m)
+}
+func (m *WtclientSessionStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WtclientSessionStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WtclientSessionStatsRequest proto.InternalMessageInfo
+
+type SessionPolicy struct {
+	// / The blob type negotiated for the session.
+	BlobType uint32 `protobuf:"varint,1,opt,name=blob_type,json=blobType,proto3" json:"blob_type,omitempty"`
+	// / The maximum number of backups the tower will accept for the session.
+	MaxBackups uint32 `protobuf:"varint,2,opt,name=max_backups,json=maxBackups,proto3" json:"max_backups,omitempty"`
+	// / The fixed reward in satoshis claimed by the tower for each breach.
+	RewardBase uint32 `protobuf:"varint,3,opt,name=reward_base,json=rewardBase,proto3" json:"reward_base,omitempty"`
+	// *
+	// The proportional reward claimed by the tower for each breach, in
+	// millionths of the swept value.
+	RewardRate uint32 `protobuf:"varint,4,opt,name=reward_rate,json=rewardRate,proto3" json:"reward_rate,omitempty"`
+	// / The fee rate in sat/kw justice transactions are swept with.
+	SweepFeeRate         int64    `protobuf:"varint,5,opt,name=sweep_fee_rate,json=sweepFeeRate,proto3" json:"sweep_fee_rate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionPolicy) Reset()         { *m = SessionPolicy{} }
+func (m *SessionPolicy) String() string { return proto.CompactTextString(m) }
+func (*SessionPolicy) ProtoMessage()    {}
+func (*SessionPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{6}
+}
+func (m *SessionPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionPolicy.Unmarshal(m, b)
+}
+func (m *SessionPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionPolicy.Marshal(b, m, deterministic)
+}
+func (dst *SessionPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionPolicy.Merge(dst, src)
+}
+func (m *SessionPolicy) XXX_Size() int {
+	return xxx_messageInfo_SessionPolicy.Size(m)
+}
+func (m *SessionPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionPolicy proto.InternalMessageInfo
+
+func (m *SessionPolicy) GetBlobType() uint32 {
+	if m != nil {
+		return m.BlobType
+	}
+	return 0
+}
+
+func (m *SessionPolicy) GetMaxBackups() uint32 {
+	if m != nil {
+		return m.MaxBackups
+	}
+	return 0
+}
+
+func (m *SessionPolicy) GetRewardBase() uint32 {
+	if m != nil {
+		return m.RewardBase
+	}
+	return 0
+}
+
+func (m *SessionPolicy) GetRewardRate() uint32 {
+	if m != nil {
+		return m.RewardRate
+	}
+	return 0
+}
+
+func (m *SessionPolicy) GetSweepFeeRate() int64 {
+	if m != nil {
+		return m.SweepFeeRate
+	}
+	return 0
+}
+
+type SessionStats struct {
+	// / The session id, which is the session key of the client.
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// / The parameters negotiated for the session.
+	Policy *SessionPolicy `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	// / The number of backups committed to the session to be sent.
+	NumBackupsSent uint32 `protobuf:"varint,3,opt,name=num_backups_sent,json=numBackupsSent,proto3" json:"num_backups_sent,omitempty"`
+	// / The number of backups acked by the tower.
+	NumBackupsAcked uint32 `protobuf:"varint,4,opt,name=num_backups_acked,json=numBackupsAcked,proto3" json:"num_backups_acked,omitempty"`
+	// / The number of sent backups that have yet to be acked by the tower.
+	NumBackupsPending uint32 `protobuf:"varint,5,opt,name=num_backups_pending,json=numBackupsPending,proto3" json:"num_backups_pending,omitempty"`
+	// *
+	// An estimate of the on-chain value in satoshis the tower could recover
+	// through the session's acked backups, counting the revoked outputs of the
+	// most recent acked state of each channel.
+	ValueProtectedSat    int64    `protobuf:"varint,6,opt,name=value_protected_sat,json=valueProtectedSat,proto3" json:"value_protected_sat,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionStats) Reset()         { *m = SessionStats{} }
+func (m *SessionStats) String() string { return proto.CompactTextString(m) }
+func (*SessionStats) ProtoMessage()    {}
+func (*SessionStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{7}
+}
+func (m *SessionStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionStats.Unmarshal(m, b)
+}
+func (m *SessionStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionStats.Marshal(b, m, deterministic)
+}
+func (dst *SessionStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionStats.Merge(dst, src)
+}
+func (m *SessionStats) XXX_Size() int {
+	return xxx_messageInfo_SessionStats.Size(m)
+}
+func (m *SessionStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionStats proto.InternalMessageInfo
+
+func (m *SessionStats) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *SessionStats) GetPolicy() *SessionPolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+func (m *SessionStats) GetNumBackupsSent() uint32 {
+	if m != nil {
+		return m.NumBackupsSent
+	}
+	return 0
+}
+
+func (m *SessionStats) GetNumBackupsAcked() uint32 {
+	if m != nil {
+		return m.NumBackupsAcked
+	}
+	return 0
+}
+
+func (m *SessionStats) GetNumBackupsPending() uint32 {
+	if m != nil {
+		return m.NumBackupsPending
+	}
+	return 0
+}
+
+func (m *SessionStats) GetValueProtectedSat() int64 {
+	if m != nil {
+		return m.ValueProtectedSat
+	}
+	return 0
+}
+
+type TowerSessionStats struct {
+	// / The identity public key of the tower.
+	Pubkey []byte `protobuf:"bytes,1,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	// / The stats of each session negotiated with the tower.
+	Sessions []*SessionStats `protobuf:"bytes,2,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	// / The number of backups sent to the tower across all sessions.
+	NumBackupsSent uint32 `protobuf:"varint,3,opt,name=num_backups_sent,json=numBackupsSent,proto3" json:"num_backups_sent,omitempty"`
+	// / The number of backups acked by the tower across all sessions.
+	NumBackupsAcked uint32 `protobuf:"varint,4,opt,name=num_backups_acked,json=numBackupsAcked,proto3" json:"num_backups_acked,omitempty"`
+	// / The number of backups yet to be acked by the tower.
+	NumBackupsPending uint32 `protobuf:"varint,5,opt,name=num_backups_pending,json=numBackupsPending,proto3" json:"num_backups_pending,omitempty"`
+	// *
+	// An estimate of the on-chain value in satoshis the tower could recover
+	// across all sessions, counting each channel once.
+	ValueProtectedSat    int64    `protobuf:"varint,6,opt,name=value_protected_sat,json=valueProtectedSat,proto3" json:"value_protected_sat,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TowerSessionStats) Reset()         { *m = TowerSessionStats{} }
+func (m *TowerSessionStats) String() string { return proto.CompactTextString(m) }
+func (*TowerSessionStats) ProtoMessage()    {}
+func (*TowerSessionStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{8}
+}
+func (m *TowerSessionStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TowerSessionStats.Unmarshal(m, b)
+}
+func (m *TowerSessionStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TowerSessionStats.Marshal(b, m, deterministic)
+}
+func (dst *TowerSessionStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TowerSessionStats.Merge(dst, src)
+}
+func (m *TowerSessionStats) XXX_Size() int {
+	return xxx_messageInfo_TowerSessionStats.Size(m)
+}
+func (m *TowerSessionStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_TowerSessionStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TowerSessionStats proto.InternalMessageInfo
+
+func (m *TowerSessionStats) GetPubkey() []byte {
+	if m != nil {
+		return m.Pubkey
+	}
+	return nil
+}
+
+func (m *TowerSessionStats) GetSessions() []*SessionStats {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+func (m *TowerSessionStats) GetNumBackupsSent() uint32 {
+	if m != nil {
+		return m.NumBackupsSent
+	}
+	return 0
+}
+
+func (m *TowerSessionStats) GetNumBackupsAcked() uint32 {
+	if m != nil {
+		return m.NumBackupsAcked
+	}
+	return 0
+}
+
+func (m *TowerSessionStats) GetNumBackupsPending() uint32 {
+	if m != nil {
+		return m.NumBackupsPending
+	}
+	return 0
+}
+
+func (m *TowerSessionStats) GetValueProtectedSat() int64 {
+	if m != nil {
+		return m.ValueProtectedSat
+	}
+	return 0
+}
+
+type WtclientSessionStatsResponse struct {
+	// / The stats of each tower the client has negotiated sessions with.
+	Towers               []*TowerSessionStats `protobuf:"bytes,1,rep,name=towers,proto3" json:"towers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *WtclientSessionStatsResponse) Reset()         { *m = WtclientSessionStatsResponse{} }
+func (m *WtclientSessionStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*WtclientSessionStatsResponse) ProtoMessage()    {}
+func (*WtclientSessionStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_wtclient_b049530cea7b0796, []int{9}
+}
+func (m *WtclientSessionStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WtclientSessionStatsResponse.Unmarshal(m, b)
+}
+func (m *WtclientSessionStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WtclientSessionStatsResponse.Marshal(b, m, deterministic)
+}
+func (dst *WtclientSessionStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WtclientSessionStatsResponse.Merge(dst, src)
+}
+func (m *WtclientSessionStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_WtclientSessionStatsResponse.Size(m)
+}
+func (m *WtclientSessionStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WtclientSessionStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WtclientSessionStatsResponse proto.InternalMessageInfo
+
+func (m *WtclientSessionStatsResponse) GetTowers() []*TowerSessionStats {
+	if m != nil {
+		return m.Towers
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ListSessionsRequest)(nil), "wtclientrpc.ListSessionsRequest")
+	proto.RegisterType((*Session)(nil), "wtclientrpc.Session")
+	proto.RegisterType((*ListSessionsResponse)(nil), "wtclientrpc.ListSessionsResponse")
+	proto.RegisterType((*UpdateTowerAddressesRequest)(nil), "wtclientrpc.UpdateTowerAddressesRequest")
+	proto.RegisterType((*UpdateTowerAddressesResponse)(nil), "wtclientrpc.UpdateTowerAddressesResponse")
+	proto.RegisterType((*WtclientSessionStatsRequest)(nil), "wtclientrpc.WtclientSessionStatsRequest")
+	proto.RegisterType((*SessionPolicy)(nil), "wtclientrpc.SessionPolicy")
+	proto.RegisterType((*SessionStats)(nil), "wtclientrpc.SessionStats")
+	proto.RegisterType((*TowerSessionStats)(nil), "wtclientrpc.TowerSessionStats")
+	proto.RegisterType((*WtclientSessionStatsResponse)(nil), "wtclientrpc.WtclientSessionStatsResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// WatchtowerClientClient is the client API for WatchtowerClient service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WatchtowerClientClient interface {
+	// *
+	// ListSessions returns every session of the watchtower client that has not
+	// been exhausted, along with the number of backups it can still hold.
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// *
+	// UpdateTowerAddresses replaces the addresses of a tower the client backs up
+	// to, without having to remove and re-add the tower. The tower's existing
+	// sessions are resumed at the new addresses, which are tried in order, with
+	// each unreachable address backed off separately.
+	UpdateTowerAddresses(ctx context.Context, in *UpdateTowerAddressesRequest, opts ...grpc.CallOption) (*UpdateTowerAddressesResponse, error)
+	// *
+	// WtclientSessionStats accounts for the backups made to each tower, across
+	// every session negotiated with it including exhausted ones: the number of
+	// backups sent, acked and pending, the negotiated policy, and an estimate of
+	// the on-chain value protected.
+	WtclientSessionStats(ctx context.Context, in *WtclientSessionStatsRequest, opts ...grpc.CallOption) (*WtclientSessionStatsResponse, error)
+}
+
+type watchtowerClientClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWatchtowerClientClient(cc *grpc.ClientConn) WatchtowerClientClient {
+	return &watchtowerClientClient{cc}
+}
+
+func (c *watchtowerClientClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) UpdateTowerAddresses(ctx context.Context, in *UpdateTowerAddressesRequest, opts ...grpc.CallOption) (*UpdateTowerAddressesResponse, error) {
+	out := new(UpdateTowerAddressesResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/UpdateTowerAddresses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) WtclientSessionStats(ctx context.Context, in *WtclientSessionStatsRequest, opts ...grpc.CallOption) (*WtclientSessionStatsResponse, error) {
+	out := new(WtclientSessionStatsResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/WtclientSessionStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WatchtowerClientServer is the server API for WatchtowerClient service.
+type WatchtowerClientServer interface {
+	// *
+	// ListSessions returns every session of the watchtower client that has not
+	// been exhausted, along with the number of backups it can still hold.
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// *
+	// UpdateTowerAddresses replaces the addresses of a tower the client backs up
+	// to, without having to remove and re-add the tower. The tower's existing
+	// sessions are resumed at the new addresses, which are tried in order, with
+	// each unreachable address backed off separately.
+	UpdateTowerAddresses(context.Context, *UpdateTowerAddressesRequest) (*UpdateTowerAddressesResponse, error)
+	// *
+	// WtclientSessionStats accounts for the backups made to each tower, across
+	// every session negotiated with it including exhausted ones: the number of
+	// backups sent, acked and pending, the negotiated policy, and an estimate of
+	// the on-chain value protected.
+	WtclientSessionStats(context.Context, *WtclientSessionStatsRequest) (*WtclientSessionStatsResponse, error)
+}
+
+func RegisterWatchtowerClientServer(s *grpc.Server, srv WatchtowerClientServer) {
+	s.RegisterService(&_WatchtowerClient_serviceDesc, srv)
+}
+
+func _WatchtowerClient_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_UpdateTowerAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTowerAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).UpdateTowerAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/UpdateTowerAddresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).UpdateTowerAddresses(ctx, req.(*UpdateTowerAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_WtclientSessionStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WtclientSessionStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).WtclientSessionStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/WtclientSessionStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).WtclientSessionStats(ctx, req.(*WtclientSessionStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WatchtowerClient_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "wtclientrpc.WatchtowerClient",
+	HandlerType: (*WatchtowerClientServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSessions",
+			Handler:    _WatchtowerClient_ListSessions_Handler,
+		},
+		{
+			MethodName: "UpdateTowerAddresses",
+			Handler:    _WatchtowerClient_UpdateTowerAddresses_Handler,
+		},
+		{
+			MethodName: "WtclientSessionStats",
+			Handler:    _WatchtowerClient_WtclientSessionStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "wtclientrpc/wtclient.proto",
+}
+
+func init() {
+	proto.RegisterFile("wtclientrpc/wtclient.proto", fileDescriptor_wtclient_b049530cea7b0796)
+}
+
+var fileDescriptor_wtclient_b049530cea7b0796 = []byte{
+	// 659 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x95, 0xc1, 0x4e, 0xdb, 0x4c,
+	0x14, 0x85, 0x65, 0x07, 0x02, 0xdc, 0x04, 0x7e, 0x32, 0xf0, 0x57, 0x6e, 0xa0, 0x34, 0x58, 0x5d,
+	0x98, 0x56, 0x4a, 0x50, 0xaa, 0x76, 0x0f, 0x95, 0xaa, 0x2e, 0xba, 0x88, 0x1c, 0x5a, 0xa4, 0x6e,
+	0xac, 0xb1, 0x7d, 0x01, 0x2b, 0xf6, 0xd8, 0xf5, 0x8c, 0x1b, 0x78, 0x83, 0x3e, 0x42, 0x57, 0x7d,
+	0x8b, 0x3e, 0x40, 0xdf, 0xac, 0xf2, 0x78, 0xec, 0x38, 0xe0, 0x46, 0x6c, 0xbb, 0xb3, 0xcf, 0xfd,
+	0xc6, 0x33, 0x73, 0xee, 0x99, 0x31, 0xf4, 0xe7, 0xc2, 0x0b, 0x03, 0x64, 0x22, 0x4d, 0xbc, 0x51,
+	0xf9, 0x3c, 0x4c, 0xd2, 0x58, 0xc4, 0xa4, 0x53, 0xab, 0x99, 0xff, 0xc3, 0xde, 0xc7, 0x80, 0x8b,
+	0x29, 0x72, 0x1e, 0xc4, 0x8c, 0xdb, 0xf8, 0x35, 0x43, 0x2e, 0xcc, 0x9f, 0x3a, 0x6c, 0x28, 0x8d,
+	0xec, 0x80, 0x1e, 0xf8, 0x86, 0x36, 0xd0, 0xac, 0xae, 0xad, 0x07, 0x3e, 0x39, 0x86, 0xae, 0x88,
+	0xe7, 0x98, 0x3a, 0x49, 0xe6, 0xce, 0xf0, 0xce, 0xd0, 0x65, 0xa5, 0x23, 0xb5, 0x89, 0x94, 0xc8,
+	0x01, 0x6c, 0xb9, 0x61, 0xec, 0x3a, 0xe2, 0x2e, 0x41, 0xa3, 0x35, 0xd0, 0xac, 0x6d, 0x7b, 0x33,
+	0x17, 0x2e, 0xee, 0x12, 0x24, 0xcf, 0xa1, 0x13, 0xd1, 0x5b, 0xc7, 0xa5, 0xde, 0x2c, 0x4b, 0xb8,
+	0xb1, 0x26, 0xcb, 0x10, 0xd1, 0xdb, 0xf3, 0x42, 0xc9, 0x01, 0x96, 0x45, 0x15, 0xb0, 0x5e, 0x00,
+	0x2c, 0x8b, 0x4a, 0x60, 0x08, 0x7b, 0x39, 0x90, 0x20, 0xf3, 0x03, 0x76, 0x5d, 0x81, 0x6d, 0x09,
+	0xf6, 0x58, 0x16, 0x4d, 0x8a, 0x4a, 0xc9, 0xbf, 0x82, 0x5e, 0x8a, 0x11, 0x0d, 0x58, 0x9d, 0xde,
+	0x90, 0xf4, 0x6e, 0x55, 0x28, 0xe1, 0x17, 0xb0, 0xc3, 0xe7, 0x88, 0x89, 0x73, 0x85, 0xe8, 0xa4,
+	0x54, 0xa0, 0xb1, 0x39, 0xd0, 0xac, 0x96, 0xdd, 0x95, 0xea, 0x7b, 0x44, 0x9b, 0x0a, 0x34, 0x3f,
+	0xc0, 0xfe, 0xb2, 0x6f, 0x3c, 0x89, 0x19, 0x47, 0x72, 0x0a, 0x9b, 0x5c, 0x69, 0x86, 0x36, 0x68,
+	0x59, 0x9d, 0xf1, 0xfe, 0xb0, 0xe6, 0xf7, 0x50, 0x0d, 0xb0, 0x2b, 0xca, 0x9c, 0xc2, 0xc1, 0xa7,
+	0xc4, 0xa7, 0x02, 0x2f, 0x72, 0x03, 0xcf, 0x7c, 0x3f, 0x45, 0xce, 0xb1, 0xec, 0x04, 0x79, 0x02,
+	0x6d, 0xe5, 0x73, 0xd1, 0x01, 0xf5, 0x46, 0x0e, 0x61, 0x8b, 0x96, 0xac, 0xa1, 0x0f, 0x5a, 0xd6,
+	0x96, 0xbd, 0x10, 0xcc, 0x23, 0x38, 0x6c, 0xfe, 0x68, 0xb1, 0x4c, 0xf3, 0x19, 0x1c, 0x5c, 0xaa,
+	0x55, 0xa9, 0x15, 0x4d, 0x05, 0x15, 0x55, 0xfb, 0x7f, 0x69, 0xb0, 0xad, 0xf4, 0x49, 0x1c, 0x06,
+	0xde, 0xbd, 0x8e, 0x6a, 0xab, 0x3b, 0xaa, 0x37, 0x75, 0x34, 0xc5, 0x39, 0x4d, 0x7d, 0xc7, 0xa5,
+	0xbc, 0x4c, 0x04, 0x14, 0xd2, 0x39, 0xe5, 0x58, 0x03, 0xa4, 0xe3, 0x6b, 0x75, 0x20, 0xf7, 0xbb,
+	0xa1, 0x2b, 0xeb, 0x0d, 0x5d, 0xf9, 0xae, 0x43, 0xb7, 0xbe, 0x9f, 0x07, 0xd9, 0x1d, 0x43, 0x3b,
+	0x91, 0x1b, 0x92, 0x8b, 0xec, 0x8c, 0xfb, 0x4d, 0xcd, 0x29, 0xb6, 0x6c, 0x2b, 0x92, 0x58, 0xb0,
+	0x5b, 0x8b, 0xa3, 0xc3, 0x91, 0x09, 0xb5, 0x83, 0x9d, 0x45, 0x26, 0xa7, 0xc8, 0x04, 0x79, 0x09,
+	0xbd, 0x3a, 0x49, 0xbd, 0x19, 0xfa, 0x6a, 0x2f, 0xff, 0x2d, 0xd0, 0xb3, 0x5c, 0x2e, 0x33, 0x5c,
+	0xb2, 0x2a, 0xcb, 0x2a, 0xec, 0xbd, 0x05, 0xad, 0xa2, 0x9c, 0xf3, 0xdf, 0x68, 0x98, 0xa1, 0x93,
+	0x1f, 0x62, 0xf4, 0x04, 0xfa, 0x0e, 0xa7, 0x42, 0x66, 0xbe, 0x65, 0xf7, 0x64, 0x69, 0x52, 0x56,
+	0xa6, 0x54, 0x98, 0x3f, 0x74, 0xe8, 0xc9, 0xe6, 0x2f, 0xf9, 0xf1, 0xb7, 0x34, 0xbd, 0xa9, 0xc5,
+	0x56, 0x97, 0xb1, 0x7d, 0xda, 0xe4, 0x4c, 0x11, 0x92, 0x0a, 0xfd, 0x47, 0xad, 0xf9, 0x0c, 0x87,
+	0xcd, 0xe1, 0x57, 0x67, 0xf8, 0x2d, 0xb4, 0xe5, 0x65, 0x56, 0x9e, 0xe0, 0xa3, 0x25, 0x2b, 0x1e,
+	0x98, 0x6a, 0x2b, 0x7a, 0xfc, 0x5b, 0x87, 0xdd, 0x4b, 0x2a, 0xbc, 0x1b, 0xf9, 0xfe, 0x4e, 0x8e,
+	0x20, 0x53, 0xe8, 0xd6, 0x2f, 0x0a, 0x32, 0x58, 0xfa, 0x58, 0xc3, 0xdd, 0xdb, 0x3f, 0x5e, 0x41,
+	0xa8, 0x15, 0xce, 0x60, 0xbf, 0xe9, 0x78, 0x13, 0x6b, 0x69, 0xe8, 0x8a, 0x6b, 0xa5, 0x7f, 0xf2,
+	0x08, 0x72, 0x31, 0x59, 0x93, 0x5d, 0xf7, 0x26, 0x5b, 0x71, 0x9d, 0xf4, 0x4f, 0x1e, 0x41, 0x16,
+	0x93, 0x9d, 0x8f, 0xbf, 0x9c, 0x5e, 0x07, 0xe2, 0x26, 0x73, 0x87, 0x5e, 0x1c, 0x8d, 0xc2, 0x40,
+	0xa0, 0x17, 0x07, 0xec, 0x2a, 0x60, 0x94, 0x79, 0x38, 0x0a, 0x99, 0x3f, 0x0a, 0x59, 0xfd, 0x9f,
+	0x96, 0x26, 0x9e, 0xdb, 0x96, 0xff, 0xb5, 0xd7, 0x7f, 0x06, 0x00, 0xff, 0xd0, 0xaa, 0x64, 0xf5,
+	0x06, 0x00, 0x00,
+}
@@ -0,0 +1,298 @@
+// +build wtclientrpc
+
+package wtclientrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/lnd/lncfg"
+	"github.com/litecoinfinance/lnd/lnrpc"
+	"github.com/litecoinfinance/lnd/watchtower"
+
+	"google.golang.org/grpc"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+const (
+	// subServerName is the name of the sub rpc server. We'll use this name
+	// to register ourselves, and we also require that the main
+	// SubServerConfigDispatcher instance recognize this as the name of the
+	// config file that we need.
+	subServerName = "WatchtowerClientRPC"
+)
+
+var (
+	// macaroonOps are the set of capabilities that our minted macaroon (if
+	// it doesn't already exist) will have.
+	macaroonOps = []bakery.Op{
+		{
+			Entity: "offchain",
+			Action: "read",
+		},
+		{
+			Entity: "offchain",
+			Action: "write",
+		},
+	}
+
+	// macPermissions maps RPC calls to the permissions they require.
+	macPermissions = map[string][]bakery.Op{
+		"/wtclientrpc.WatchtowerClient/ListSessions": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/wtclientrpc.WatchtowerClient/UpdateTowerAddresses": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/wtclientrpc.WatchtowerClient/WtclientSessionStats": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+	}
+
+	// DefaultWatchtowerClientMacFilename is the default name of the
+	// watchtower client macaroon that we expect to find via a file handle
+	// within the main configuration file in this package.
+	DefaultWatchtowerClientMacFilename = "wtclient.macaroon"
+
+	// ErrClientNotActive is returned when the watchtower client RPC server
+	// is queried while no watchtower client is running.
+	ErrClientNotActive = errors.New("watchtower client not active")
+)
+
+// Server is a sub-server of the main RPC server: the watchtower client RPC.
+// This sub RPC server allows the node operator to inspect the sessions their
+// watchtower client backs up revoked states to.
+type Server struct {
+	cfg *Config
+}
+
+// A compile time check to ensure that Server fully implements the
+// WatchtowerClientServer gRPC service.
+var _ WatchtowerClientServer = (*Server)(nil)
+
+// New returns a new instance of the wtclientrpc WatchtowerClient sub-server.
+// We also return the set of permissions for the macaroons that we may create
+// within this method. If the macaroons we need aren't found in the filepath,
+// then we'll create them on start up. If we're unable to locate, or create the
+// macaroons we need, then we'll return with an error.
+func New(cfg *Config) (*Server, lnrpc.MacaroonPerms, error) {
+	// If the path of the watchtower client macaroon wasn't generated, then
+	// we'll assume that it's found at the default network directory.
+	if cfg.WatchtowerClientMacPath == "" {
+		cfg.WatchtowerClientMacPath = filepath.Join(
+			cfg.NetworkDir, DefaultWatchtowerClientMacFilename,
+		)
+	}
+
+	// Now that we know the full path of the watchtower client macaroon, we
+	// can check to see if we need to create it or not.
+	macFilePath := cfg.WatchtowerClientMacPath
+	if cfg.MacService != nil && !lnrpc.FileExists(macFilePath) {
+		log.Infof("Making macaroons for Watchtower Client RPC Server "+
+			"at: %v", macFilePath)
+
+		clientMac, err := cfg.MacService.Oven.NewMacaroon(
+			context.Background(), bakery.LatestVersion, nil,
+			macaroonOps...,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		clientMacBytes, err := clientMac.M().MarshalBinary()
+		if err != nil {
+			return nil, nil, err
+		}
+		err = ioutil.WriteFile(macFilePath, clientMacBytes, 0644)
+		if err != nil {
+			os.Remove(macFilePath)
+			return nil, nil, err
+		}
+	}
+
+	return &Server{cfg: cfg}, macPermissions, nil
+}
+
+// Start launches any helper goroutines required for the rpcServer to function.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Start() error {
+	return nil
+}
+
+// Stop signals any active goroutines for a graceful closure.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Stop() error {
+	return nil
+}
+
+// Name returns a unique string representation of the sub-server. This can be
+// used to identify the sub-server and also de-duplicate them.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Name() string {
+	return subServerName
+}
+
+// RegisterWithRootServer will be called by the root gRPC server to direct a
+// sub RPC server to register itself with the main gRPC root server. Until this
+// is called, each sub-server won't be able to have requests routed towards it.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) RegisterWithRootServer(grpcServer *grpc.Server) error {
+	// We make sure that we register it with the main gRPC server to ensure
+	// all our methods are routed properly.
+	RegisterWatchtowerClientServer(grpcServer, s)
+
+	log.Debugf("Watchtower client RPC server successfully register with " +
+		"root gRPC server")
+
+	return nil
+}
+
+// ListSessions returns every session of the watchtower client that has not
+// been exhausted, along with the number of backups it can still hold.
+func (s *Server) ListSessions(ctx context.Context,
+	in *ListSessionsRequest) (*ListSessionsResponse, error) {
+
+	if s.cfg.Client == nil {
+		return nil, ErrClientNotActive
+	}
+
+	statuses, err := s.cfg.Client.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListSessionsResponse{
+		Sessions: make([]*Session, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		policy := status.Policy
+		towerPub := status.TowerPubKey.SerializeCompressed()
+
+		resp.Sessions = append(resp.Sessions, &Session{
+			Id:                status.ID[:],
+			TowerPubkey:       towerPub,
+			BlobType:          uint32(policy.BlobType),
+			MaxBackups:        uint32(policy.MaxUpdates),
+			NumBackups:        uint32(status.NumBackups),
+			NumPendingBackups: uint32(status.NumPendingBackups),
+			RemainingBackups:  uint32(status.RemainingBackups),
+			SweepFeeRate:      int64(policy.SweepFeeRate),
+		})
+	}
+
+	return resp, nil
+}
+
+// UpdateTowerAddresses replaces the addresses of a tower the client backs up
+// to, without having to remove and re-add the tower.
+func (s *Server) UpdateTowerAddresses(ctx context.Context,
+	in *UpdateTowerAddressesRequest) (*UpdateTowerAddressesResponse, error) {
+
+	if s.cfg.Client == nil {
+		return nil, ErrClientNotActive
+	}
+
+	pubKey, err := btcec.ParsePubKey(in.Pubkey, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("invalid tower pubkey: %v", err)
+	}
+
+	if len(in.Addresses) == 0 {
+		return nil, errors.New("at least one tower address must be " +
+			"specified")
+	}
+
+	resolver := s.cfg.Resolver
+	if resolver == nil {
+		resolver = net.ResolveTCPAddr
+	}
+
+	defaultPort := strconv.Itoa(watchtower.DefaultPeerPort)
+	addrs := make([]net.Addr, 0, len(in.Addresses))
+	for _, rawAddr := range in.Addresses {
+		addr, err := lncfg.ParseAddressString(
+			rawAddr, defaultPort, resolver,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tower address %v: %v",
+				rawAddr, err)
+		}
+
+		addrs = append(addrs, addr)
+	}
+
+	err = s.cfg.Client.UpdateTowerAddresses(pubKey, addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateTowerAddressesResponse{}, nil
+}
+
+// WtclientSessionStats accounts for the backups made to each tower, across
+// every session negotiated with it, so that the coverage of the client's
+// channels can be audited.
+func (s *Server) WtclientSessionStats(ctx context.Context,
+	in *WtclientSessionStatsRequest) (*WtclientSessionStatsResponse, error) {
+
+	if s.cfg.Client == nil {
+		return nil, ErrClientNotActive
+	}
+
+	towers, err := s.cfg.Client.SessionStats()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &WtclientSessionStatsResponse{
+		Towers: make([]*TowerSessionStats, 0, len(towers)),
+	}
+	for _, tower := range towers {
+		sessions := make([]*SessionStats, 0, len(tower.Sessions))
+		for _, session := range tower.Sessions {
+			policy := session.Policy
+			sessions = append(sessions, &SessionStats{
+				Id: session.ID[:],
+				Policy: &SessionPolicy{
+					BlobType:   uint32(policy.BlobType),
+					MaxBackups: uint32(policy.MaxUpdates),
+					RewardBase: policy.RewardBase,
+					RewardRate: policy.RewardRate,
+					SweepFeeRate: int64(
+						policy.SweepFeeRate,
+					),
+				},
+				NumBackupsSent:    uint32(session.NumSent),
+				NumBackupsAcked:   uint32(session.NumAcked),
+				NumBackupsPending: uint32(session.NumPending),
+				ValueProtectedSat: int64(
+					session.ValueProtected,
+				),
+			})
+		}
+
+		resp.Towers = append(resp.Towers, &TowerSessionStats{
+			Pubkey:            tower.PubKey.SerializeCompressed(),
+			Sessions:          sessions,
+			NumBackupsSent:    tower.NumSent,
+			NumBackupsAcked:   tower.NumAcked,
+			NumBackupsPending: tower.NumPending,
+			ValueProtectedSat: int64(tower.ValueProtected),
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,56 @@
+package lnwallet
+
+import (
+	"errors"
+
+	"github.com/litecoinfinance/btcd/wire"
+)
+
+// FundBatchTx crafts and signs a transaction funded by coins of the wallet,
+// selected according to the given strategy, which pays to each of the passed
+// channel funding outputs at the given fee rate. Any excess is returned to a
+// change output, unless it would be dust. The transaction isn't published,
+// and its coins stay locked until it either is, or ReleaseBatchTx is called
+// for it.
+func (l *LightningWallet) FundBatchTx(outputs []*wire.TxOut,
+	feeRate SatPerKWeight, minConfs int32,
+	strategy CoinSelectionStrategy) (*wire.MsgTx, error) {
+
+	if len(outputs) == 0 {
+		return nil, errors.New("batch tx must have at least one output")
+	}
+
+	// We hold the coin select mutex while selecting and locking the coins
+	// of the batch, so they can't be used to fund a channel concurrently.
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	batchTx, err := l.fundTx(outputs, feeRate, minConfs, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only once the transaction is complete we'll lock its coins, such
+	// that we don't need to unlock them on failure.
+	for _, txIn := range batchTx.TxIn {
+		l.lockedOutPoints[txIn.PreviousOutPoint] = struct{}{}
+		l.LockOutpoint(txIn.PreviousOutPoint)
+	}
+
+	walletLog.Infof("Created batch tx %v funding %v outputs",
+		batchTx.TxHash(), len(outputs))
+
+	return batchTx, nil
+}
+
+// ReleaseBatchTx unlocks the coins spent by a batch transaction created by
+// FundBatchTx, which won't be published.
+func (l *LightningWallet) ReleaseBatchTx(batchTx *wire.MsgTx) {
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	for _, txIn := range batchTx.TxIn {
+		delete(l.lockedOutPoints, txIn.PreviousOutPoint)
+		l.UnlockOutpoint(txIn.PreviousOutPoint)
+	}
+}
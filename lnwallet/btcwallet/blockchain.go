@@ -1,6 +1,7 @@
 package btcwallet
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -23,6 +24,11 @@ var (
 	// ErrOutputNotFound signals that the desired output could not be
 	// located.
 	ErrOutputNotFound = errors.New("target output was not found")
+
+	// ErrTxLookupUnsupported is returned by the GetRawTransaction method
+	// if the chain backend is unable to look up arbitrary transactions.
+	ErrTxLookupUnsupported = errors.New("transaction lookup is not " +
+		"supported by the chain backend")
 )
 
 // GetBestBlock returns the current height and hash of the best known block
@@ -141,6 +147,45 @@ func (b *BtcWallet) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
 	return b.chain.GetBlockHash(blockHeight)
 }
 
+// GetRawTransaction returns the transaction with the given hash. Light clients
+// don't maintain a transaction index, so ErrTxLookupUnsupported is returned
+// when backed by neutrino.
+func (b *BtcWallet) GetRawTransaction(txid *chainhash.Hash) (*wire.MsgTx,
+	error) {
+
+	switch backend := b.chain.(type) {
+
+	case *chain.RPCClient:
+		tx, err := backend.GetRawTransaction(txid)
+		if err != nil {
+			return nil, err
+		}
+
+		return tx.MsgTx(), nil
+
+	case *chain.BitcoindClient:
+		txResult, err := backend.GetRawTransactionVerbose(txid)
+		if err != nil {
+			return nil, err
+		}
+
+		rawTx, err := hex.DecodeString(txResult.Hex)
+		if err != nil {
+			return nil, err
+		}
+
+		tx := &wire.MsgTx{}
+		if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+			return nil, err
+		}
+
+		return tx, nil
+
+	default:
+		return nil, ErrTxLookupUnsupported
+	}
+}
+
 // A compile time check to ensure that BtcWallet implements the BlockChainIO
 // interface.
 var _ lnwallet.WalletController = (*BtcWallet)(nil)
@@ -33,6 +33,15 @@ var (
 	// stored within the top-level waleltdb buckets of btcwallet.
 	waddrmgrNamespaceKey = []byte("waddrmgr")
 
+	// wtxmgrNamespaceKey is the namespace key that the wtxmgr state is
+	// stored within the top-level walletdb buckets of btcwallet.
+	wtxmgrNamespaceKey = []byte("wtxmgr")
+
+	// txLabelsBucketKey is the key of the top-level bucket within the
+	// wallet database that maps the hashes of wallet transactions to their
+	// labels.
+	txLabelsBucketKey = []byte("lnd-tx-labels")
+
 	// lightningAddrSchema is the scope addr schema for all keys that we
 	// derive. We'll treat them all as p2wkh addresses, as atm we must
 	// specify a particular type.
@@ -238,15 +247,9 @@ func (b *BtcWallet) ConfirmedBalance(confs int32) (btcutil.Amount, error) {
 //
 // This is a part of the WalletController interface.
 func (b *BtcWallet) NewAddress(t lnwallet.AddressType, change bool) (btcutil.Address, error) {
-	var keyScope waddrmgr.KeyScope
-
-	switch t {
-	case lnwallet.WitnessPubKey:
-		keyScope = waddrmgr.KeyScopeBIP0084
-	case lnwallet.NestedWitnessPubKey:
-		keyScope = waddrmgr.KeyScopeBIP0049Plus
-	default:
-		return nil, fmt.Errorf("unknown address type")
+	keyScope, err := addrTypeKeyScope(t)
+	if err != nil {
+		return nil, err
 	}
 
 	if change {
@@ -265,18 +268,142 @@ func (b *BtcWallet) NewAddress(t lnwallet.AddressType, change bool) (btcutil.Add
 func (b *BtcWallet) LastUnusedAddress(addrType lnwallet.AddressType) (
 	btcutil.Address, error) {
 
-	var keyScope waddrmgr.KeyScope
+	keyScope, err := addrTypeKeyScope(addrType)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.wallet.CurrentAddress(defaultAccount, keyScope)
+}
+
+// addrTypeKeyScope returns the key scope the addresses of the given type are
+// derived from.
+func addrTypeKeyScope(addrType lnwallet.AddressType) (waddrmgr.KeyScope,
+	error) {
 
 	switch addrType {
 	case lnwallet.WitnessPubKey:
-		keyScope = waddrmgr.KeyScopeBIP0084
+		return waddrmgr.KeyScopeBIP0084, nil
 	case lnwallet.NestedWitnessPubKey:
-		keyScope = waddrmgr.KeyScopeBIP0049Plus
+		return waddrmgr.KeyScopeBIP0049Plus, nil
 	default:
-		return nil, fmt.Errorf("unknown address type")
+		return waddrmgr.KeyScope{}, fmt.Errorf("unknown address type")
 	}
+}
 
-	return b.wallet.CurrentAddress(defaultAccount, keyScope)
+// CreateAccount creates a new account with the given name within the key
+// scopes of all address types we support, such that the account numbers of
+// both scopes stay in step.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) CreateAccount(name string) error {
+	keyScopes := []waddrmgr.KeyScope{
+		waddrmgr.KeyScopeBIP0084, waddrmgr.KeyScopeBIP0049Plus,
+	}
+	for _, keyScope := range keyScopes {
+		if _, err := b.wallet.NextAccount(keyScope, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListAccounts returns the names of all accounts of the wallet, including the
+// default account.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) ListAccounts() ([]string, error) {
+	accounts, err := b.wallet.Accounts(waddrmgr.KeyScopeBIP0084)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(accounts.Accounts))
+	for _, account := range accounts.Accounts {
+		// The account holding imported addresses isn't backed by the
+		// key scope, so it isn't of interest to the caller.
+		if account.AccountNumber == waddrmgr.ImportedAddrAccount {
+			continue
+		}
+
+		names = append(names, account.AccountName)
+	}
+
+	return names, nil
+}
+
+// NewAccountAddress returns the next external address of the given type for
+// the named account.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) NewAccountAddress(account string,
+	addrType lnwallet.AddressType) (btcutil.Address, error) {
+
+	keyScope, err := addrTypeKeyScope(addrType)
+	if err != nil {
+		return nil, err
+	}
+
+	accountNum, err := b.wallet.AccountNumber(keyScope, account)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.wallet.NewAddress(accountNum, keyScope)
+}
+
+// LabelTransaction labels the wallet transaction with the given hash. If the
+// transaction already has a label, ErrTxLabelExists is returned unless
+// overwrite is set.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) LabelTransaction(hash chainhash.Hash, label string,
+	overwrite bool) error {
+
+	if label == "" {
+		return lnwallet.ErrEmptyLabel
+	}
+
+	return walletdb.Update(b.db, func(tx walletdb.ReadWriteTx) error {
+		wtxmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		details, err := b.wallet.TxStore.TxDetails(wtxmgrNs, &hash)
+		if err != nil {
+			return err
+		}
+		if details == nil {
+			return lnwallet.ErrUnknownTransaction
+		}
+
+		labels, err := tx.CreateTopLevelBucket(txLabelsBucketKey)
+		if err != nil {
+			return err
+		}
+
+		if labels.Get(hash[:]) != nil && !overwrite {
+			return lnwallet.ErrTxLabelExists
+		}
+
+		return labels.Put(hash[:], []byte(label))
+	})
+}
+
+// fetchTxLabels populates the labels of the passed transaction details.
+func (b *BtcWallet) fetchTxLabels(
+	txDetails []*lnwallet.TransactionDetail) error {
+
+	return walletdb.View(b.db, func(tx walletdb.ReadTx) error {
+		labels := tx.ReadBucket(txLabelsBucketKey)
+		if labels == nil {
+			return nil
+		}
+
+		for _, txDetail := range txDetails {
+			txDetail.Label = string(labels.Get(txDetail.Hash[:]))
+		}
+
+		return nil
+	})
 }
 
 // IsOurAddress checks if the passed address belongs to this wallet
@@ -411,6 +538,7 @@ func (b *BtcWallet) ListUnspentWitness(minConfs, maxConfs int32) (
 					Index: output.Vout,
 				},
 				Confirmations: output.Confirmations,
+				Account:       output.Account,
 			}
 			witnessOutputs = append(witnessOutputs, utxo)
 		}
@@ -612,6 +740,10 @@ func (b *BtcWallet) ListTransactionDetails() ([]*lnwallet.TransactionDetail, err
 		txDetails = append(txDetails, detail)
 	}
 
+	if err := b.fetchTxLabels(txDetails); err != nil {
+		return nil, err
+	}
+
 	return txDetails, nil
 }
 
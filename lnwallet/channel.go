@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/litecoinfinance/btcd/blockchain"
 	"github.com/litecoinfinance/btcd/btcec"
@@ -1344,6 +1345,10 @@ type LightningChannel struct {
 
 	remoteChanCfg *channeldb.ChannelConfig
 
+	// commitBuilder creates the commitment transactions of the channel in
+	// the format determined by its type.
+	commitBuilder *CommitmentBuilder
+
 	// [local|remote]Log is a (mostly) append-only log storing all the HTLC
 	// updates to this channel. The log is walked backwards as HTLC updates
 	// are applied in order to re-construct a commitment transaction from a
@@ -1384,6 +1389,10 @@ func NewLightningChannel(signer input.Signer, pCache PreimageCache,
 		localCommit.RemoteLogIndex, localCommit.RemoteHtlcIndex,
 	)
 
+	commitBuilder := NewCommitmentBuilder(
+		state.ChanType, &state.LocalChanCfg, &state.RemoteChanCfg,
+	)
+
 	lc := &LightningChannel{
 		Signer:            signer,
 		sigPool:           sigPool,
@@ -1394,6 +1403,7 @@ func NewLightningChannel(signer input.Signer, pCache PreimageCache,
 		channelState:      state,
 		localChanCfg:      &state.LocalChanCfg,
 		remoteChanCfg:     &state.RemoteChanCfg,
+		commitBuilder:     commitBuilder,
 		localUpdateLog:    localUpdateLog,
 		remoteUpdateLog:   remoteUpdateLog,
 		ChanPoint:         &state.FundingOutpoint,
@@ -1926,6 +1936,12 @@ type BreachRetribution struct {
 	// party) within the breach transaction.
 	LocalOutpoint wire.OutPoint
 
+	// LocalDelay is the relative time-lock, in blocks, of the output paying
+	// to us within the breach transaction. It's non-zero only for channels
+	// using anchor outputs, whose to-remote output can only be spent after
+	// a confirmation.
+	LocalDelay uint32
+
 	// RemoteOutputSignDesc is a SignDescriptor which is capable of
 	// generating the signature required to claim the funds as described
 	// within the revocation clause of the remote party's commitment
@@ -1998,10 +2014,16 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	if err != nil {
 		return nil, err
 	}
-	localPkScript, err := input.CommitScriptUnencumbered(keyRing.NoDelayKey)
+	localWitnessScript, localPkScript, err := CommitScriptToRemote(
+		chanState.ChanType, keyRing.NoDelayKey,
+	)
 	if err != nil {
 		return nil, err
 	}
+	var localDelay uint32
+	if chanState.ChanType.HasAnchors() {
+		localDelay = 1
+	}
 
 	// In order to fully populate the breach retribution struct, we'll need
 	// to find the exact index of the local+remote commitment outputs.
@@ -2038,7 +2060,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		localSignDesc = &input.SignDescriptor{
 			SingleTweak:   keyRing.LocalCommitKeyTweak,
 			KeyDesc:       chanState.LocalChanCfg.PaymentBasePoint,
-			WitnessScript: localPkScript,
+			WitnessScript: localWitnessScript,
 			Output: &wire.TxOut{
 				PkScript: localPkScript,
 				Value:    int64(localAmt),
@@ -2154,6 +2176,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		RevokedStateNum:      stateNum,
 		PendingHTLCs:         revokedSnapshot.Htlcs,
 		LocalOutpoint:        localOutpoint,
+		LocalDelay:           localDelay,
 		LocalOutputSignDesc:  localSignDesc,
 		RemoteOutpoint:       remoteOutpoint,
 		RemoteOutputSignDesc: remoteSignDesc,
@@ -2369,7 +2392,8 @@ func (lc *LightningChannel) createCommitmentTx(c *commitment,
 	// on its total weight. Once we have the total weight, we'll multiply
 	// by the current fee-per-kw, then divide by 1000 to get the proper
 	// fee.
-	totalCommitWeight := input.CommitWeight + (input.HtlcWeight * numHTLCs)
+	totalCommitWeight := CommitWeight(lc.channelState.ChanType) +
+		(input.HtlcWeight * numHTLCs)
 
 	// With the weight known, we can now calculate the commitment fee,
 	// ensuring that we account for any dust outputs trimmed above.
@@ -2394,24 +2418,21 @@ func (lc *LightningChannel) createCommitmentTx(c *commitment,
 		theirBalance -= commitFeeMSat
 	}
 
-	var (
-		delay                      uint32
-		delayBalance, p2wkhBalance btcutil.Amount
-	)
+	var delayBalance, toRemoteBalance btcutil.Amount
 	if c.isOurs {
-		delay = uint32(lc.localChanCfg.CsvDelay)
 		delayBalance = ourBalance.ToSatoshis()
-		p2wkhBalance = theirBalance.ToSatoshis()
+		toRemoteBalance = theirBalance.ToSatoshis()
 	} else {
-		delay = uint32(lc.remoteChanCfg.CsvDelay)
 		delayBalance = theirBalance.ToSatoshis()
-		p2wkhBalance = ourBalance.ToSatoshis()
+		toRemoteBalance = ourBalance.ToSatoshis()
 	}
 
 	// Generate a new commitment transaction with all the latest
 	// unsettled/un-timed out HTLCs.
-	commitTx, err := CreateCommitTx(lc.fundingTxIn(), keyRing, delay,
-		delayBalance, p2wkhBalance, c.dustLimit)
+	commitTx, err := lc.commitBuilder.CreateCommitTx(
+		lc.fundingTxIn(), keyRing, c.isOurs, delayBalance,
+		toRemoteBalance, numHTLCs,
+	)
 	if err != nil {
 		return err
 	}
@@ -3682,7 +3703,8 @@ func (lc *LightningChannel) computeView(view *htlcView, remoteChain bool,
 		totalHtlcWeight += input.HtlcWeight
 	}
 
-	totalCommitWeight := input.CommitWeight + totalHtlcWeight
+	totalCommitWeight := CommitWeight(lc.channelState.ChanType) +
+		totalHtlcWeight
 	return ourBalance, theirBalance, totalCommitWeight, filteredHTLCView
 }
 
@@ -5125,7 +5147,9 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	// Before we can generate the proper sign descriptor, we'll need to
 	// locate the output index of our non-delayed output on the commitment
 	// transaction.
-	selfP2WKH, err := input.CommitScriptUnencumbered(keyRing.NoDelayKey)
+	selfWitnessScript, selfPkScript, err := CommitScriptToRemote(
+		chanState.ChanType, keyRing.NoDelayKey,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create self commit "+
 			"script: %v", err)
@@ -5137,7 +5161,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	)
 
 	for outputIndex, txOut := range commitTxBroadcast.TxOut {
-		if bytes.Equal(txOut.PkScript, selfP2WKH) {
+		if bytes.Equal(txOut.PkScript, selfPkScript) {
 			selfPoint = &wire.OutPoint{
 				Hash:  *commitSpend.SpenderTxHash,
 				Index: uint32(outputIndex),
@@ -5150,22 +5174,30 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	// With the HTLC's taken care of, we'll generate the sign descriptor
 	// necessary to sweep our commitment output, but only if we had a
 	// non-trimmed balance.
+	//
+	// For channels using anchor outputs, our output can only be spent
+	// once the commitment has confirmed.
 	var commitResolution *CommitOutputResolution
 	if selfPoint != nil {
+		var maturityDelay uint32
+		if chanState.ChanType.HasAnchors() {
+			maturityDelay = 1
+		}
+
 		localPayBase := chanState.LocalChanCfg.PaymentBasePoint
 		commitResolution = &CommitOutputResolution{
 			SelfOutPoint: *selfPoint,
 			SelfOutputSignDesc: input.SignDescriptor{
 				KeyDesc:       localPayBase,
 				SingleTweak:   keyRing.LocalCommitKeyTweak,
-				WitnessScript: selfP2WKH,
+				WitnessScript: selfWitnessScript,
 				Output: &wire.TxOut{
 					Value:    localBalance,
-					PkScript: selfP2WKH,
+					PkScript: selfPkScript,
 				},
 				HashType: txscript.SigHashAll|txscript.SigHashForkID,
 			},
-			MaturityDelay: 0,
+			MaturityDelay: maturityDelay,
 		}
 	}
 
@@ -5696,6 +5728,14 @@ type LocalForceCloseSummary struct {
 	// HTLC's, we'll need to go to the second level to sweep them fully.
 	HtlcResolutions *HtlcResolutions
 
+	// AnchorResolution contains the data required to spend our anchor
+	// output on the commitment transaction, which allows us to bump its
+	// fee using CPFP.
+	//
+	// NOTE: This will be nil if the channel doesn't use anchor outputs, or
+	// we don't have an anchor on the commitment.
+	AnchorResolution *AnchorResolution
+
 	// ChanSnapshot is a snapshot of the final state of the channel at the
 	// time the summary was created.
 	ChanSnapshot channeldb.ChannelSnapshot
@@ -5835,11 +5875,17 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 		return nil, err
 	}
 
+	anchorResolution, err := NewAnchorResolution(chanState, commitTx)
+	if err != nil {
+		return nil, err
+	}
+
 	return &LocalForceCloseSummary{
 		ChanPoint:        chanState.FundingOutpoint,
 		CloseTx:          commitTx,
 		CommitResolution: commitResolution,
 		HtlcResolutions:  htlcResolutions,
+		AnchorResolution: anchorResolution,
 		ChanSnapshot:     *chanState.Snapshot(),
 	}, nil
 }
@@ -5861,6 +5907,85 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 	lc.Lock()
 	defer lc.Unlock()
 
+	closeTx, signDesc, ourBalance, err := lc.createCloseTx(
+		proposedFee, localDeliveryScript, remoteDeliveryScript,
+	)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// Finally, sign the completed cooperative closure transaction. As the
+	// initiator we'll simply send our signature over to the remote party,
+	// using the generated txid to be notified once the closure transaction
+	// has been confirmed.
+	sig, err := lc.Signer.SignOutputRaw(closeTx, signDesc)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// As everything checks out, indicate in the channel status that a
+	// channel closure has been initiated.
+	lc.status = channelClosing
+
+	closeTXID := closeTx.TxHash()
+	return sig, &closeTXID, ourBalance, nil
+}
+
+// CreateCloseProposalExternal is identical to CreateCloseProposal, except that
+// our signature for the closing transaction is produced by the passed external
+// CloseSigner. We'll wait up to the given timeout for the signature to be
+// delivered, or until the quit channel is closed. The channel state isn't
+// locked while waiting for the signature.
+//
+// NOTE: The returned signature isn't verified locally, an invalid signature
+// will be rejected by the remote party.
+func (lc *LightningChannel) CreateCloseProposalExternal(
+	proposedFee btcutil.Amount, localDeliveryScript,
+	remoteDeliveryScript []byte, signer CloseSigner, timeout time.Duration,
+	quit <-chan struct{}) ([]byte, *chainhash.Hash, btcutil.Amount, error) {
+
+	lc.Lock()
+	closeTx, signDesc, ourBalance, err := lc.createCloseTx(
+		proposedFee, localDeliveryScript, remoteDeliveryScript,
+	)
+	lc.Unlock()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	sig, err := awaitCloseSig(signer, &CloseSignRequest{
+		ChanPoint:   lc.channelState.FundingOutpoint,
+		CloseTx:     closeTx,
+		SignDesc:    signDesc,
+		ProposedFee: proposedFee,
+	}, timeout, quit)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	// The channel may have been closed while we were waiting for the
+	// signature, in which case it's of no use anymore.
+	if lc.status == channelClosed {
+		return nil, nil, 0, ErrChanClosing
+	}
+	lc.status = channelClosing
+
+	closeTXID := closeTx.TxHash()
+	return sig, &closeTXID, ourBalance, nil
+}
+
+// createCloseTx creates the unsigned cooperative close transaction paying the
+// proposed fee, along with the sign descriptor required to sign it and our
+// final settled balance.
+//
+// NOTE: This method MUST be called with the channel state lock held.
+func (lc *LightningChannel) createCloseTx(proposedFee btcutil.Amount,
+	localDeliveryScript, remoteDeliveryScript []byte) (*wire.MsgTx,
+	*input.SignDescriptor, btcutil.Amount, error) {
+
 	// If we've already closed the channel, then ignore this request.
 	if lc.status == channelClosed {
 		// TODO(roasbeef): check to ensure no pending payments
@@ -5875,8 +6000,10 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 	theirBalance := localCommit.RemoteBalance.ToSatoshis()
 
 	// We'll make sure we account for the complete balance by adding the
-	// current dangling commitment fee to the balance of the initiator.
-	commitFee := localCommit.CommitFee
+	// current dangling commitment fee, along with the value of the anchors
+	// the initiator paid for, to the balance of the initiator.
+	commitFee := localCommit.CommitFee +
+		anchorsValue(lc.channelState.ChanType)
 	if lc.channelState.IsInitiator {
 		ourBalance = ourBalance - proposedFee + commitFee
 	} else {
@@ -5896,22 +6023,12 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 		return nil, nil, 0, err
 	}
 
-	// Finally, sign the completed cooperative closure transaction. As the
-	// initiator we'll simply send our signature over to the remote party,
-	// using the generated txid to be notified once the closure transaction
-	// has been confirmed.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(closeTx)
-	sig, err := lc.Signer.SignOutputRaw(closeTx, lc.signDesc)
-	if err != nil {
-		return nil, nil, 0, err
-	}
+	// We'll hand out a copy of our sign descriptor, such that it may be
+	// used without holding the channel state lock.
+	signDesc := *lc.signDesc
+	signDesc.SigHashes = txscript.NewTxSigHashes(closeTx)
 
-	// As everything checks out, indicate in the channel status that a
-	// channel closure has been initiated.
-	lc.status = channelClosing
-
-	closeTXID := closeTx.TxHash()
-	return sig, &closeTXID, ourBalance, nil
+	return closeTx, &signDesc, ourBalance, nil
 }
 
 // CompleteCooperativeClose completes the cooperative closure of the target
@@ -5942,8 +6059,10 @@ func (lc *LightningChannel) CompleteCooperativeClose(localSig, remoteSig []byte,
 	theirBalance := localCommit.RemoteBalance.ToSatoshis()
 
 	// We'll make sure we account for the complete balance by adding the
-	// current dangling commitment fee to the balance of the initiator.
-	commitFee := localCommit.CommitFee
+	// current dangling commitment fee, along with the value of the anchors
+	// the initiator paid for, to the balance of the initiator.
+	commitFee := localCommit.CommitFee +
+		anchorsValue(lc.channelState.ChanType)
 	if lc.channelState.IsInitiator {
 		ourBalance = ourBalance - proposedFee + commitFee
 	} else {
@@ -6183,60 +6302,6 @@ func (lc *LightningChannel) generateRevocation(height uint64) (*lnwire.RevokeAnd
 	return revocationMsg, nil
 }
 
-// CreateCommitTx creates a commitment transaction, spending from specified
-// funding output. The commitment transaction contains two outputs: one paying
-// to the "owner" of the commitment transaction which can be spent after a
-// relative block delay or revocation event, and the other paying the
-// counterparty within the channel, which can be spent immediately.
-func CreateCommitTx(fundingOutput wire.TxIn,
-	keyRing *CommitmentKeyRing, csvTimeout uint32,
-	amountToSelf, amountToThem, dustLimit btcutil.Amount) (*wire.MsgTx, error) {
-
-	// First, we create the script for the delayed "pay-to-self" output.
-	// This output has 2 main redemption clauses: either we can redeem the
-	// output after a relative block delay, or the remote node can claim
-	// the funds with the revocation key if we broadcast a revoked
-	// commitment transaction.
-	ourRedeemScript, err := input.CommitScriptToSelf(csvTimeout, keyRing.DelayKey,
-		keyRing.RevocationKey)
-	if err != nil {
-		return nil, err
-	}
-	payToUsScriptHash, err := input.WitnessScriptHash(ourRedeemScript)
-	if err != nil {
-		return nil, err
-	}
-
-	// Next, we create the script paying to them. This is just a regular
-	// P2WPKH output, without any added CSV delay.
-	theirWitnessKeyHash, err := input.CommitScriptUnencumbered(keyRing.NoDelayKey)
-	if err != nil {
-		return nil, err
-	}
-
-	// Now that both output scripts have been created, we can finally create
-	// the transaction itself. We use a transaction version of 2 since CSV
-	// will fail unless the tx version is >= 2.
-	commitTx := wire.NewMsgTx(2)
-	commitTx.AddTxIn(&fundingOutput)
-
-	// Avoid creating dust outputs within the commitment transaction.
-	if amountToSelf >= dustLimit {
-		commitTx.AddTxOut(&wire.TxOut{
-			PkScript: payToUsScriptHash,
-			Value:    int64(amountToSelf),
-		})
-	}
-	if amountToThem >= dustLimit {
-		commitTx.AddTxOut(&wire.TxOut{
-			PkScript: theirWitnessKeyHash,
-			Value:    int64(amountToThem),
-		})
-	}
-
-	return commitTx, nil
-}
-
 // CreateCooperativeCloseTx creates a transaction which if signed by both
 // parties, then broadcast cooperatively closes an active channel. The creation
 // of the closure transaction is modified by a boolean indicating if the party
@@ -6278,7 +6343,7 @@ func CreateCooperativeCloseTx(fundingTxIn wire.TxIn,
 // CalcFee returns the commitment fee to use for the given
 // fee rate (fee-per-kw).
 func (lc *LightningChannel) CalcFee(feeRate SatPerKWeight) btcutil.Amount {
-	return feeRate.FeeForWeight(input.CommitWeight)
+	return feeRate.FeeForWeight(CommitWeight(lc.channelState.ChanType))
 }
 
 // RemoteNextRevocation returns the channelState's RemoteNextRevocation.
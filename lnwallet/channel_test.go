@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/litecoinfinance/btcd/blockchain"
 	"github.com/litecoinfinance/btcd/btcec"
@@ -493,6 +494,95 @@ func TestCooperativeChannelClosure(t *testing.T) {
 	}
 }
 
+// mockCloseSigner is a CloseSigner that asynchronously signs close
+// transactions using the wrapped signer after an optional delay.
+type mockCloseSigner struct {
+	signer input.Signer
+	delay  time.Duration
+}
+
+// SignCloseTx signs the close transaction in the background and delivers the
+// signature over the returned channel.
+func (m *mockCloseSigner) SignCloseTx(
+	req *CloseSignRequest) (<-chan *CloseSignResponse, error) {
+
+	respChan := make(chan *CloseSignResponse, 1)
+	go func() {
+		time.Sleep(m.delay)
+
+		sig, err := m.signer.SignOutputRaw(req.CloseTx, req.SignDesc)
+		respChan <- &CloseSignResponse{Sig: sig, Err: err}
+	}()
+
+	return respChan, nil
+}
+
+// TestCooperativeCloseExternalSigner tests that a cooperative close can be
+// completed with a signature delivered by an external CloseSigner, and that we
+// give up waiting for the signature after the timeout.
+func TestCooperativeCloseExternalSigner(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels()
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	aliceDeliveryScript := bobsPrivKey[:]
+	bobDeliveryScript := testHdSeed[:]
+
+	aliceFeeRate := SatPerKWeight(
+		aliceChannel.channelState.LocalCommitment.FeePerKw,
+	)
+	aliceFee := aliceChannel.CalcFee(aliceFeeRate)
+
+	quit := make(chan struct{})
+	defer close(quit)
+
+	// If the external signer doesn't deliver the signature in time, the
+	// proposal should fail.
+	slowSigner := &mockCloseSigner{
+		signer: aliceChannel.Signer,
+		delay:  time.Second,
+	}
+	_, _, _, err = aliceChannel.CreateCloseProposalExternal(
+		aliceFee, aliceDeliveryScript, bobDeliveryScript, slowSigner,
+		10*time.Millisecond, quit,
+	)
+	if err != ErrCloseSigTimeout {
+		t.Fatalf("expected ErrCloseSigTimeout, got %v", err)
+	}
+
+	// Otherwise, Alice's externally produced signature should be accepted
+	// by Bob in order to complete the close.
+	aliceSig, _, _, err := aliceChannel.CreateCloseProposalExternal(
+		aliceFee, aliceDeliveryScript, bobDeliveryScript,
+		&mockCloseSigner{signer: aliceChannel.Signer}, time.Second,
+		quit,
+	)
+	if err != nil {
+		t.Fatalf("unable to create alice coop close proposal: %v", err)
+	}
+	aliceCloseSig := append(aliceSig, byte(txscript.SigHashAll))
+
+	bobSig, _, _, err := bobChannel.CreateCloseProposal(
+		aliceFee, bobDeliveryScript, aliceDeliveryScript,
+	)
+	if err != nil {
+		t.Fatalf("unable to create bob coop close proposal: %v", err)
+	}
+	bobCloseSig := append(bobSig, byte(txscript.SigHashAll))
+
+	_, _, err = bobChannel.CompleteCooperativeClose(
+		bobCloseSig, aliceCloseSig, bobDeliveryScript,
+		aliceDeliveryScript, aliceFee,
+	)
+	if err != nil {
+		t.Fatalf("unable to complete cooperative close: %v", err)
+	}
+}
+
 // TestForceClose checks that the resulting ForceCloseSummary is correct when a
 // peer is ForceClosing the channel. Will check outputs both above and below
 // the dust limit. Additionally, we'll ensure that the node which executed the
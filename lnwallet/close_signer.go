@@ -0,0 +1,96 @@
+package lnwallet
+
+import (
+	"errors"
+	"time"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/input"
+)
+
+// DefaultCloseSigTimeout is the default duration we'll wait for an external
+// CloseSigner to deliver our signature for a cooperative close transaction.
+const DefaultCloseSigTimeout = time.Minute
+
+var (
+	// ErrCloseSigTimeout is returned when an external CloseSigner fails to
+	// deliver a signature for a cooperative close transaction in time.
+	ErrCloseSigTimeout = errors.New("timeout waiting for external close " +
+		"signature")
+
+	// ErrCloseSigAborted is returned when we stop waiting for an external
+	// CloseSigner to deliver its signature due to shutting down.
+	ErrCloseSigAborted = errors.New("external close signing aborted")
+)
+
+// CloseSignRequest describes a cooperative close transaction that should be
+// signed on behalf of our side of the channel.
+type CloseSignRequest struct {
+	// ChanPoint is the funding outpoint of the channel being closed.
+	ChanPoint wire.OutPoint
+
+	// CloseTx is the unsigned cooperative close transaction.
+	CloseTx *wire.MsgTx
+
+	// SignDesc describes the funding output spent by CloseTx, including
+	// our multi-sig key and the sighash midstate of CloseTx.
+	SignDesc *input.SignDescriptor
+
+	// ProposedFee is the fee paid by CloseTx.
+	ProposedFee btcutil.Amount
+}
+
+// CloseSignResponse is delivered by a CloseSigner once it has completed a
+// CloseSignRequest.
+type CloseSignResponse struct {
+	// Sig is our signature for the close transaction, void of a sighash
+	// byte. It is only set if Err is nil.
+	Sig []byte
+
+	// Err is set if the signature couldn't be produced.
+	Err error
+}
+
+// CloseSigner is an abstraction point that allows the final signature of a
+// cooperative close transaction to be produced by an external protocol, e.g.
+// a set of threshold signers that aggregate their partial signatures, rather
+// than by the wallet's Signer directly. As such protocols may take a while to
+// complete, the signature is delivered asynchronously.
+type CloseSigner interface {
+	// SignCloseTx requests a signature for the given cooperative close
+	// transaction. The response is delivered over the returned channel
+	// once available. Requests that are no longer awaited are abandoned
+	// by the caller, so implementations MUST NOT block on delivering
+	// their response.
+	SignCloseTx(req *CloseSignRequest) (<-chan *CloseSignResponse, error)
+}
+
+// awaitCloseSig dispatches the request to the passed CloseSigner, and then
+// waits up to the given timeout for its signature to be delivered.
+func awaitCloseSig(signer CloseSigner, req *CloseSignRequest,
+	timeout time.Duration, quit <-chan struct{}) ([]byte, error) {
+
+	respChan, err := signer.SignCloseTx(req)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp, ok := <-respChan:
+		if !ok {
+			return nil, ErrCloseSigAborted
+		}
+		if resp.Err != nil {
+			return nil, resp.Err
+		}
+
+		return resp.Sig, nil
+
+	case <-time.After(timeout):
+		return nil, ErrCloseSigTimeout
+
+	case <-quit:
+		return nil, ErrCloseSigAborted
+	}
+}
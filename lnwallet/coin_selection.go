@@ -0,0 +1,462 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/litecoinfinance/btcd/txscript"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/btcutil/txsort"
+	"github.com/litecoinfinance/lnd/input"
+)
+
+var (
+	// ErrOutputLeased is returned when leasing an output that's already
+	// locked.
+	ErrOutputLeased = errors.New("output is already leased")
+
+	// ErrOutputNotLeased is returned when releasing an output that isn't
+	// leased.
+	ErrOutputNotLeased = errors.New("output isn't leased")
+)
+
+// CoinSelectionStrategy determines the order in which the coins of the wallet
+// are considered when selecting the inputs of a transaction.
+type CoinSelectionStrategy uint8
+
+const (
+	// CoinSelectionDefault selects coins in the order the wallet lists
+	// them.
+	CoinSelectionDefault CoinSelectionStrategy = iota
+
+	// CoinSelectionLargest selects the largest coins first, which
+	// minimizes the number of inputs, and therefore the fee, of the
+	// transaction.
+	CoinSelectionLargest
+
+	// CoinSelectionSmallest selects the smallest coins first, which
+	// consolidates the small coins of the wallet over time, at the expense
+	// of higher fees.
+	CoinSelectionSmallest
+
+	// CoinSelectionRandom selects coins in random order, which makes it
+	// harder to link the transactions of the wallet.
+	CoinSelectionRandom
+
+	// CoinSelectionBranchAndBound searches for a set of coins that funds
+	// the transaction without a change output, wasting at most what
+	// creating and later spending the change output would cost. If there's
+	// no such set, the largest coins are selected first.
+	CoinSelectionBranchAndBound
+)
+
+// String returns a human readable version of the CoinSelectionStrategy.
+func (c CoinSelectionStrategy) String() string {
+	switch c {
+	case CoinSelectionDefault:
+		return "default"
+	case CoinSelectionLargest:
+		return "largest-first"
+	case CoinSelectionSmallest:
+		return "smallest-first"
+	case CoinSelectionRandom:
+		return "random"
+	case CoinSelectionBranchAndBound:
+		return "branch-and-bound"
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(c))
+	}
+}
+
+// maxBranchAndBoundTries is the maximum number of combinations of coins we'll
+// consider while searching for a changeless set.
+const maxBranchAndBoundTries = 100000
+
+// orderCoins returns the coins in the order they're considered by the given
+// strategy. The passed slice isn't modified.
+func orderCoins(strategy CoinSelectionStrategy, coins []*Utxo) []*Utxo {
+	ordered := make([]*Utxo, len(coins))
+	copy(ordered, coins)
+
+	switch strategy {
+	case CoinSelectionLargest, CoinSelectionBranchAndBound:
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].Value > ordered[j].Value
+		})
+
+	case CoinSelectionSmallest:
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].Value < ordered[j].Value
+		})
+
+	case CoinSelectionRandom:
+		rand.Shuffle(len(ordered), func(i, j int) {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		})
+	}
+
+	return ordered
+}
+
+// addCoinInput updates the weight estimate to account for an input spending
+// the given coin.
+func addCoinInput(weightEstimate *input.TxWeightEstimator, coin *Utxo) error {
+	switch coin.AddressType {
+	case WitnessPubKey:
+		weightEstimate.AddP2WKHInput()
+	case NestedWitnessPubKey:
+		weightEstimate.AddNestedP2WKHInput()
+	default:
+		return fmt.Errorf("Unsupported address type: %v",
+			coin.AddressType)
+	}
+
+	return nil
+}
+
+// selectChangeless performs a branch and bound search for a set of coins which
+// pays amt, along with the fee of the transaction described by the outputs
+// weight estimate, without a change output. The value of the set may exceed
+// what's needed by at most the cost of the change output it avoids, which is
+// paid as additional fee. False is returned if there's no such set.
+func selectChangeless(feeRate SatPerKWeight, amt btcutil.Amount,
+	outputsEstimate input.TxWeightEstimator, coins []*Utxo) ([]*Utxo,
+	bool) {
+
+	// We'll consider the coins by their effective value, which is what's
+	// left of them once the fee of spending them has been paid. Coins
+	// that don't pay for themselves are never worth selecting.
+	type candidate struct {
+		coin     *Utxo
+		effValue btcutil.Amount
+	}
+	var (
+		candidates []candidate
+		noInputs   input.TxWeightEstimator
+	)
+	for _, coin := range orderCoins(CoinSelectionLargest, coins) {
+		var inputEstimate input.TxWeightEstimator
+		if err := addCoinInput(&inputEstimate, coin); err != nil {
+			continue
+		}
+
+		// We'll subtract the weight of the transaction without any
+		// inputs, such that only the weight of the input remains.
+		inputWeight := int64(inputEstimate.Weight()-noInputs.Weight()) -
+			input.WitnessHeaderSize
+
+		effValue := coin.Value - feeRate.FeeForWeight(inputWeight)
+		if effValue <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{coin, effValue})
+	}
+
+	// The coins must pay for the outputs, and the parts of the
+	// transaction that don't depend on its inputs. Avoiding the change
+	// output is only worth it if no more than the cost of the change
+	// output is wasted.
+	target := amt + feeRate.FeeForWeight(
+		int64(outputsEstimate.Weight())+input.WitnessHeaderSize,
+	)
+	costOfChange := feeRate.FeeForWeight(
+		input.P2WKHOutputSize*4 + input.InputSize*4 +
+			input.P2WKHWitnessSize,
+	)
+	upperBound := target + costOfChange
+
+	// remaining[i] is the total effective value of all candidates
+	// starting at index i, which allows us to prune branches that can't
+	// reach the target anymore.
+	remaining := make([]btcutil.Amount, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + candidates[i].effValue
+	}
+
+	var (
+		tries    int
+		selected []*Utxo
+		search   func(i int, total btcutil.Amount) bool
+	)
+	search = func(i int, total btcutil.Amount) bool {
+		tries++
+		switch {
+		case tries > maxBranchAndBoundTries:
+			return false
+		case total > upperBound:
+			return false
+		case total >= target:
+			return true
+		case total+remaining[i] < target:
+			return false
+		}
+
+		// We'll first explore the branch including the candidate, as
+		// the candidates are sorted by descending value, followed by
+		// the one omitting it.
+		selected = append(selected, candidates[i].coin)
+		if search(i+1, total+candidates[i].effValue) {
+			return true
+		}
+		selected = selected[:len(selected)-1]
+
+		return search(i+1, total)
+	}
+	if !search(0, 0) {
+		return nil, false
+	}
+
+	// As the effective values are only an approximation of the weight of
+	// the transaction, we'll ensure the selected coins pay for it.
+	var totalSat btcutil.Amount
+	weightEstimate := outputsEstimate
+	for _, coin := range selected {
+		totalSat += coin.Value
+		if err := addCoinInput(&weightEstimate, coin); err != nil {
+			return nil, false
+		}
+	}
+	requiredFee := feeRate.FeeForWeight(int64(weightEstimate.Weight()))
+	if totalSat < amt+requiredFee {
+		return nil, false
+	}
+
+	return selected, true
+}
+
+// listSpendableCoins returns the unspent witness outputs of the default
+// account of the wallet with at least minConfs confirmations. Outputs of the
+// other accounts are left to the operator, and are never spent by lnd on its
+// own.
+func (l *LightningWallet) listSpendableCoins(minConfs int32) ([]*Utxo, error) {
+	coins, err := l.ListUnspentWitness(minConfs, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	return defaultAccountCoins(coins), nil
+}
+
+// defaultAccountCoins returns the coins belonging to the default account.
+func defaultAccountCoins(coins []*Utxo) []*Utxo {
+	spendable := make([]*Utxo, 0, len(coins))
+	for _, coin := range coins {
+		switch coin.Account {
+		case "", DefaultAccountName:
+			spendable = append(spendable, coin)
+		}
+	}
+
+	return spendable
+}
+
+// fundTx crafts and signs a transaction funded by coins of the wallet, which
+// pays to each of the passed outputs at the given fee rate. Any excess is
+// returned to a change output, unless it would be dust.
+//
+// NOTE: The caller MUST hold the coin select mutex.
+func (l *LightningWallet) fundTx(outputs []*wire.TxOut, feeRate SatPerKWeight,
+	minConfs int32, strategy CoinSelectionStrategy) (*wire.MsgTx, error) {
+
+	var (
+		totalOut        btcutil.Amount
+		outputsEstimate input.TxWeightEstimator
+	)
+	for _, txOut := range outputs {
+		totalOut += btcutil.Amount(txOut.Value)
+		outputsEstimate.AddTxOutput(txOut)
+	}
+
+	walletLog.Infof("Performing %v coin selection for %v outputs using "+
+		"%v sat/kw as fee rate", strategy, len(outputs), int64(feeRate))
+
+	coins, err := l.listSpendableCoins(minConfs)
+	if err != nil {
+		return nil, err
+	}
+
+	selectedCoins, changeAmt, err := coinSelect(
+		feeRate, totalOut, outputsEstimate, strategy, coins,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := wire.NewMsgTx(1)
+	for _, coin := range selectedCoins {
+		tx.AddTxIn(wire.NewTxIn(&coin.OutPoint, nil, nil))
+	}
+	for _, txOut := range outputs {
+		tx.AddTxOut(txOut)
+	}
+
+	if err := l.addChangeOutput(tx, changeAmt); err != nil {
+		return nil, err
+	}
+
+	txsort.InPlaceSort(tx)
+
+	// With the transaction assembled, we'll sign each of its inputs. As
+	// all of them are segwit inputs, the txid of the transaction is final
+	// from here on.
+	if err := l.signWalletInputs(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// addChangeOutput adds an output paying the given amount to a new change
+// address of the wallet to the transaction, unless the amount is dust.
+func (l *LightningWallet) addChangeOutput(tx *wire.MsgTx,
+	changeAmt btcutil.Amount) error {
+
+	if changeAmt <= DefaultDustLimit() {
+		return nil
+	}
+
+	changeAddr, err := l.NewAddress(WitnessPubKey, true)
+	if err != nil {
+		return err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return err
+	}
+
+	tx.AddTxOut(&wire.TxOut{
+		Value:    int64(changeAmt),
+		PkScript: changeScript,
+	})
+
+	return nil
+}
+
+// signWalletInputs signs each input of the transaction with the coin of the
+// wallet it spends, except for those spending any of the external outpoints,
+// which are signed by the caller.
+func (l *LightningWallet) signWalletInputs(tx *wire.MsgTx,
+	external ...wire.OutPoint) error {
+
+	skip := make(map[wire.OutPoint]struct{}, len(external))
+	for _, outPoint := range external {
+		skip[outPoint] = struct{}{}
+	}
+
+	signDesc := input.SignDescriptor{
+		HashType:  txscript.SigHashAll | txscript.SigHashForkID,
+		SigHashes: txscript.NewTxSigHashes(tx),
+	}
+	for i, txIn := range tx.TxIn {
+		if _, ok := skip[txIn.PreviousOutPoint]; ok {
+			continue
+		}
+
+		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err != nil {
+			return err
+		}
+
+		signDesc.Output = info
+		signDesc.InputIndex = i
+
+		inputScript, err := l.Cfg.Signer.ComputeInputScript(
+			tx, &signDesc,
+		)
+		if err != nil {
+			return err
+		}
+
+		txIn.SignatureScript = inputScript.SigScript
+		txIn.Witness = inputScript.Witness
+	}
+
+	return nil
+}
+
+// SendOutputsWithStrategy funds, signs, and broadcasts a transaction paying
+// out to the specified outputs, selecting coins with at least minConfs
+// confirmations according to the given strategy. In contrast to SendOutputs,
+// coin selection is performed by us, rather than the underlying wallet.
+//
+// NOTE: This MUST be called within WithCoinSelectLock, such that the coins of
+// the transaction can't be used to fund a channel concurrently.
+func (l *LightningWallet) SendOutputsWithStrategy(outputs []*wire.TxOut,
+	feeRate SatPerKWeight, minConfs int32,
+	strategy CoinSelectionStrategy) (*wire.MsgTx, error) {
+
+	tx, err := l.fundTx(outputs, feeRate, minConfs, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.PublishTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// LeaseOutput locks the given output of the wallet, such that it isn't
+// selected to fund any of our transactions until it's released, giving an
+// external caller control over it. Leases are kept in memory, so they don't
+// survive a restart.
+func (l *LightningWallet) LeaseOutput(outPoint wire.OutPoint) error {
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	if _, ok := l.lockedOutPoints[outPoint]; ok {
+		return ErrOutputLeased
+	}
+	if _, ok := l.leasedOutPoints[outPoint]; ok {
+		return ErrOutputLeased
+	}
+
+	// Only outputs of the wallet can be leased.
+	if _, err := l.FetchInputInfo(&outPoint); err != nil {
+		return err
+	}
+
+	walletLog.Infof("Leasing output %v", outPoint)
+
+	l.leasedOutPoints[outPoint] = struct{}{}
+	l.LockOutpoint(outPoint)
+
+	return nil
+}
+
+// ReleaseOutput unlocks an output previously leased by LeaseOutput, making it
+// available for coin selection again.
+func (l *LightningWallet) ReleaseOutput(outPoint wire.OutPoint) error {
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	if _, ok := l.leasedOutPoints[outPoint]; !ok {
+		return ErrOutputNotLeased
+	}
+
+	walletLog.Infof("Releasing output %v", outPoint)
+
+	delete(l.leasedOutPoints, outPoint)
+	l.UnlockOutpoint(outPoint)
+
+	return nil
+}
+
+// LeasedOutputs returns all outputs currently leased by LeaseOutput.
+func (l *LightningWallet) LeasedOutputs() []wire.OutPoint {
+	l.coinSelectMtx.RLock()
+	defer l.coinSelectMtx.RUnlock()
+
+	outPoints := make([]wire.OutPoint, 0, len(l.leasedOutPoints))
+	for outPoint := range l.leasedOutPoints {
+		outPoints = append(outPoints, outPoint)
+	}
+
+	return outPoints
+}
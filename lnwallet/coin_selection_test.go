@@ -0,0 +1,150 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/input"
+)
+
+// testCoins returns a set of P2WKH coins of the given values.
+func testCoins(values ...btcutil.Amount) []*Utxo {
+	coins := make([]*Utxo, len(values))
+	for i, value := range values {
+		coins[i] = &Utxo{
+			AddressType: WitnessPubKey,
+			Value:       value,
+		}
+		coins[i].OutPoint.Index = uint32(i)
+	}
+
+	return coins
+}
+
+// coinValues returns the values of the given coins.
+func coinValues(coins []*Utxo) []btcutil.Amount {
+	values := make([]btcutil.Amount, len(coins))
+	for i, coin := range coins {
+		values[i] = coin.Value
+	}
+
+	return values
+}
+
+// assertCoinValues asserts that the coins have the expected values, in order.
+func assertCoinValues(t *testing.T, coins []*Utxo,
+	expected ...btcutil.Amount) {
+
+	t.Helper()
+
+	values := coinValues(coins)
+	if len(values) != len(expected) {
+		t.Fatalf("expected coins %v, got %v", expected, values)
+	}
+	for i := range values {
+		if values[i] != expected[i] {
+			t.Fatalf("expected coins %v, got %v", expected, values)
+		}
+	}
+}
+
+// TestOrderCoins asserts that each strategy orders the coins as expected,
+// without modifying the passed slice.
+func TestOrderCoins(t *testing.T) {
+	t.Parallel()
+
+	coins := testCoins(200, 500, 100, 300)
+
+	assertCoinValues(t, orderCoins(CoinSelectionDefault, coins),
+		200, 500, 100, 300)
+	assertCoinValues(t, orderCoins(CoinSelectionLargest, coins),
+		500, 300, 200, 100)
+	assertCoinValues(t, orderCoins(CoinSelectionBranchAndBound, coins),
+		500, 300, 200, 100)
+	assertCoinValues(t, orderCoins(CoinSelectionSmallest, coins),
+		100, 200, 300, 500)
+
+	// A random order must still contain every coin exactly once.
+	random := orderCoins(CoinSelectionRandom, coins)
+	if len(random) != len(coins) {
+		t.Fatalf("expected %v coins, got %v", len(coins), len(random))
+	}
+	seen := make(map[uint32]struct{})
+	for _, coin := range random {
+		seen[coin.OutPoint.Index] = struct{}{}
+	}
+	if len(seen) != len(coins) {
+		t.Fatalf("expected %v distinct coins, got %v", len(coins),
+			len(seen))
+	}
+
+	assertCoinValues(t, coins, 200, 500, 100, 300)
+}
+
+// TestCoinSelectBranchAndBound asserts that the branch and bound strategy
+// selects a set of coins that doesn't need a change output if one exists, and
+// falls back to selecting the largest coins first otherwise.
+func TestCoinSelectBranchAndBound(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = SatPerKWeight(1000)
+
+	var outputsEstimate input.TxWeightEstimator
+	outputsEstimate.AddP2WKHOutput()
+
+	coins := testCoins(1000000, 300000, 200000)
+
+	// The two smaller coins pay for the amount, along with the fee, and
+	// waste less than a change output would cost.
+	const amt = btcutil.Amount(499100)
+	selected, changeAmt, err := coinSelect(
+		feeRate, amt, outputsEstimate, CoinSelectionBranchAndBound,
+		coins,
+	)
+	if err != nil {
+		t.Fatalf("unable to select coins: %v", err)
+	}
+	assertCoinValues(t, selected, 300000, 200000)
+	if changeAmt != 0 {
+		t.Fatalf("expected no change, got %v", changeAmt)
+	}
+
+	// Selecting the largest coins first leads to a change output for the
+	// same amount.
+	selected, changeAmt, err = coinSelect(
+		feeRate, amt, outputsEstimate, CoinSelectionLargest, coins,
+	)
+	if err != nil {
+		t.Fatalf("unable to select coins: %v", err)
+	}
+	assertCoinValues(t, selected, 1000000)
+	if changeAmt == 0 {
+		t.Fatalf("expected change")
+	}
+
+	// No combination of coins pays a small amount without change, so the
+	// largest coin is selected instead.
+	selected, changeAmt, err = coinSelect(
+		feeRate, 100000, outputsEstimate, CoinSelectionBranchAndBound,
+		coins,
+	)
+	if err != nil {
+		t.Fatalf("unable to select coins: %v", err)
+	}
+	assertCoinValues(t, selected, 1000000)
+	if changeAmt == 0 {
+		t.Fatalf("expected change")
+	}
+}
+
+// TestDefaultAccountCoins asserts that only the coins of the default account
+// are considered spendable by lnd.
+func TestDefaultAccountCoins(t *testing.T) {
+	t.Parallel()
+
+	coins := testCoins(100, 200, 300)
+	coins[1].Account = "savings"
+	coins[2].Account = DefaultAccountName
+
+	assertCoinValues(t, defaultAccountCoins(coins), 100, 300)
+}
@@ -0,0 +1,105 @@
+package lnwallet
+
+import (
+	"fmt"
+)
+
+// CommitFeeBounds restricts the commitment fee rates of our channels. A zero
+// value for either bound leaves that side unrestricted.
+type CommitFeeBounds struct {
+	// MinFeeRate is the lowest commitment fee rate we'll propose, and
+	// accept from the remote party. Keeping commitment fees low while
+	// the network is quiet reduces the cost of a force close.
+	MinFeeRate SatPerKWeight
+
+	// MaxFeeRate is the highest commitment fee rate we'll propose, and
+	// accept from the remote party.
+	MaxFeeRate SatPerKWeight
+}
+
+// CommitFeeManager decides on the commitment fee rates we propose as the
+// initiator of a channel. Rather than following the fee estimator blindly, its
+// estimates are kept within the configured bounds, and never below the minimum
+// fee rate the backend currently relays, as the commitment transaction
+// wouldn't propagate otherwise.
+type CommitFeeManager struct {
+	estimator FeeEstimator
+	bounds    CommitFeeBounds
+}
+
+// NewCommitFeeManager creates a new CommitFeeManager that bounds the estimates
+// of the passed fee estimator.
+func NewCommitFeeManager(estimator FeeEstimator,
+	bounds CommitFeeBounds) *CommitFeeManager {
+
+	return &CommitFeeManager{
+		estimator: estimator,
+		bounds:    bounds,
+	}
+}
+
+// FeeRateRange returns the range our commitment fee rate is currently kept
+// within. The minimum relay fee rate takes precedence over the configured
+// bounds, so the maximum is never below the returned minimum, unless it's
+// zero, which means the range is unbounded.
+func (m *CommitFeeManager) FeeRateRange() (SatPerKWeight, SatPerKWeight) {
+	minFee := MinMempoolFeePerKW(m.estimator)
+	if m.bounds.MinFeeRate > minFee {
+		minFee = m.bounds.MinFeeRate
+	}
+
+	maxFee := m.bounds.MaxFeeRate
+	if maxFee != 0 && maxFee < minFee {
+		maxFee = minFee
+	}
+
+	return minFee, maxFee
+}
+
+// InRange returns true if the given commitment fee rate lies within the
+// range returned by FeeRateRange.
+func (m *CommitFeeManager) InRange(feePerKw SatPerKWeight) bool {
+	minFee, maxFee := m.FeeRateRange()
+	return feePerKw >= minFee && (maxFee == 0 || feePerKw <= maxFee)
+}
+
+// EstimateFeeRate returns the commitment fee rate we should propose to get
+// into the chain within the given number of blocks, which is the estimate of
+// our fee estimator moved into the range returned by FeeRateRange.
+func (m *CommitFeeManager) EstimateFeeRate(
+	numBlocks uint32) (SatPerKWeight, error) {
+
+	feePerKw, err := m.estimator.EstimateFeePerKW(numBlocks)
+	if err != nil {
+		return 0, err
+	}
+
+	minFee, maxFee := m.FeeRateRange()
+	switch {
+	case feePerKw < minFee:
+		feePerKw = minFee
+
+	case maxFee != 0 && feePerKw > maxFee:
+		feePerKw = maxFee
+	}
+
+	return feePerKw, nil
+}
+
+// CheckRemoteFeeRate returns an error if a commitment fee rate proposed by the
+// remote party falls outside of the configured bounds.
+func (m *CommitFeeManager) CheckRemoteFeeRate(feePerKw SatPerKWeight) error {
+	switch {
+	case feePerKw < m.bounds.MinFeeRate:
+		return fmt.Errorf("fee rate of %v sat/kw is below our minimum "+
+			"commitment fee rate of %v sat/kw", int64(feePerKw),
+			int64(m.bounds.MinFeeRate))
+
+	case m.bounds.MaxFeeRate != 0 && feePerKw > m.bounds.MaxFeeRate:
+		return fmt.Errorf("fee rate of %v sat/kw exceeds our maximum "+
+			"commitment fee rate of %v sat/kw", int64(feePerKw),
+			int64(m.bounds.MaxFeeRate))
+	}
+
+	return nil
+}
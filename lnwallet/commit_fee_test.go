@@ -0,0 +1,118 @@
+package lnwallet
+
+import "testing"
+
+// TestCommitFeeManagerEstimateFeeRate asserts that the fee estimates of a
+// CommitFeeManager are kept within its bounds, and never below the relay fee
+// rate of the backend.
+func TestCommitFeeManagerEstimateFeeRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		estimate SatPerKWeight
+		relayFee SatPerKWeight
+		bounds   CommitFeeBounds
+		feePerKw SatPerKWeight
+	}{
+		{
+			name:     "unbounded",
+			estimate: 5000,
+			relayFee: 253,
+			feePerKw: 5000,
+		},
+		{
+			name:     "below relay fee",
+			estimate: 200,
+			relayFee: 253,
+			feePerKw: 253,
+		},
+		{
+			name:     "within bounds",
+			estimate: 5000,
+			relayFee: 253,
+			bounds:   CommitFeeBounds{MinFeeRate: 1000, MaxFeeRate: 10000},
+			feePerKw: 5000,
+		},
+		{
+			name:     "below min fee rate",
+			estimate: 500,
+			relayFee: 253,
+			bounds:   CommitFeeBounds{MinFeeRate: 1000},
+			feePerKw: 1000,
+		},
+		{
+			name:     "above max fee rate",
+			estimate: 50000,
+			relayFee: 253,
+			bounds:   CommitFeeBounds{MaxFeeRate: 10000},
+			feePerKw: 10000,
+		},
+		{
+			name:     "max fee rate below relay fee",
+			estimate: 5000,
+			relayFee: 2000,
+			bounds:   CommitFeeBounds{MaxFeeRate: 1000},
+			feePerKw: 2000,
+		},
+	}
+
+	for _, test := range tests {
+		estimator := NewStaticFeeEstimator(test.estimate, test.relayFee)
+		manager := NewCommitFeeManager(estimator, test.bounds)
+
+		feePerKw, err := manager.EstimateFeeRate(3)
+		if err != nil {
+			t.Fatalf("%v: unable to estimate fee rate: %v",
+				test.name, err)
+		}
+		if feePerKw != test.feePerKw {
+			t.Fatalf("%v: expected fee rate %v, got %v", test.name,
+				test.feePerKw, feePerKw)
+		}
+		if !manager.InRange(feePerKw) {
+			t.Fatalf("%v: estimated fee rate %v out of range",
+				test.name, feePerKw)
+		}
+	}
+}
+
+// TestCommitFeeManagerCheckRemoteFeeRate asserts that only commitment fee
+// rates within the configured bounds are accepted from the remote party.
+func TestCommitFeeManagerCheckRemoteFeeRate(t *testing.T) {
+	t.Parallel()
+
+	estimator := NewStaticFeeEstimator(5000, 253)
+	manager := NewCommitFeeManager(estimator, CommitFeeBounds{
+		MinFeeRate: 1000,
+		MaxFeeRate: 10000,
+	})
+
+	tests := []struct {
+		feePerKw SatPerKWeight
+		valid    bool
+	}{
+		{feePerKw: 999, valid: false},
+		{feePerKw: 1000, valid: true},
+		{feePerKw: 10000, valid: true},
+		{feePerKw: 10001, valid: false},
+	}
+
+	for _, test := range tests {
+		err := manager.CheckRemoteFeeRate(test.feePerKw)
+		if test.valid && err != nil {
+			t.Fatalf("expected fee rate %v to be accepted: %v",
+				test.feePerKw, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("expected fee rate %v to be rejected",
+				test.feePerKw)
+		}
+	}
+
+	// Without bounds, any fee rate is accepted.
+	manager = NewCommitFeeManager(estimator, CommitFeeBounds{})
+	if err := manager.CheckRemoteFeeRate(1); err != nil {
+		t.Fatalf("expected fee rate to be accepted: %v", err)
+	}
+}
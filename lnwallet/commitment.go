@@ -0,0 +1,287 @@
+package lnwallet
+
+import (
+	"bytes"
+
+	"github.com/litecoinfinance/btcd/blockchain"
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/txscript"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/input"
+)
+
+// CommitmentBuilder creates the commitment transactions of a channel, in the
+// format determined by the channel's type. Channels using anchor outputs
+// carry an anchor for each party on their commitments, which it can spend to
+// bump the fee of the commitment using CPFP once it's been broadcast.
+type CommitmentBuilder struct {
+	chanType channeldb.ChannelType
+
+	localChanCfg  *channeldb.ChannelConfig
+	remoteChanCfg *channeldb.ChannelConfig
+}
+
+// NewCommitmentBuilder creates a new CommitmentBuilder for a channel of the
+// given type between parties with the given channel configs.
+func NewCommitmentBuilder(chanType channeldb.ChannelType, localChanCfg,
+	remoteChanCfg *channeldb.ChannelConfig) *CommitmentBuilder {
+
+	return &CommitmentBuilder{
+		chanType:      chanType,
+		localChanCfg:  localChanCfg,
+		remoteChanCfg: remoteChanCfg,
+	}
+}
+
+// CreateCommitTx creates the commitment transaction of the local party if
+// isOurs is true, and of the remote party otherwise. The owner of the
+// commitment is paid amountToSelf after its CSV delay, and the other party
+// amountToThem. HTLC outputs aren't added, numHTLCs is the number of non-dust
+// HTLC outputs the caller will add to the transaction.
+func (cb *CommitmentBuilder) CreateCommitTx(fundingOutput wire.TxIn,
+	keyRing *CommitmentKeyRing, isOurs bool, amountToSelf,
+	amountToThem btcutil.Amount, numHTLCs int64) (*wire.MsgTx, error) {
+
+	ownerChanCfg, otherChanCfg := cb.localChanCfg, cb.remoteChanCfg
+	if !isOurs {
+		ownerChanCfg, otherChanCfg = cb.remoteChanCfg, cb.localChanCfg
+	}
+
+	commitTx, err := createCommitTx(
+		cb.chanType, fundingOutput, keyRing,
+		uint32(ownerChanCfg.CsvDelay), amountToSelf, amountToThem,
+		ownerChanCfg.DustLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cb.chanType.HasAnchors() {
+		return commitTx, nil
+	}
+
+	// Each party gets an anchor if it has an output on the commitment, or
+	// if there are any HTLCs on it, as it may then need to bump the fee
+	// of the commitment to claim them in time.
+	dustLimit := ownerChanCfg.DustLimit
+	if amountToSelf >= dustLimit || numHTLCs > 0 {
+		err := addAnchor(commitTx, ownerChanCfg.MultiSigKey.PubKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if amountToThem >= dustLimit || numHTLCs > 0 {
+		err := addAnchor(commitTx, otherChanCfg.MultiSigKey.PubKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return commitTx, nil
+}
+
+// CreateCommitTx creates a commitment transaction, spending from specified
+// funding output. The commitment transaction contains two outputs: one paying
+// to the "owner" of the commitment transaction which can be spent after a
+// relative block delay or revocation event, and the other paying the
+// counterparty within the channel, which can be spent immediately.
+//
+// NOTE: The transaction is created in the format of channels without anchor
+// outputs. The commitments of other channel types are created through a
+// CommitmentBuilder.
+func CreateCommitTx(fundingOutput wire.TxIn,
+	keyRing *CommitmentKeyRing, csvTimeout uint32,
+	amountToSelf, amountToThem,
+	dustLimit btcutil.Amount) (*wire.MsgTx, error) {
+
+	return createCommitTx(
+		channeldb.SingleFunder, fundingOutput, keyRing, csvTimeout,
+		amountToSelf, amountToThem, dustLimit,
+	)
+}
+
+// createCommitTx creates a commitment transaction in the format of the given
+// channel type, paying to the owner of the commitment and the counterparty,
+// without any anchor or HTLC outputs.
+func createCommitTx(chanType channeldb.ChannelType, fundingOutput wire.TxIn,
+	keyRing *CommitmentKeyRing, csvTimeout uint32,
+	amountToSelf, amountToThem,
+	dustLimit btcutil.Amount) (*wire.MsgTx, error) {
+
+	// First, we create the script for the delayed "pay-to-self" output.
+	// This output has 2 main redemption clauses: either we can redeem the
+	// output after a relative block delay, or the remote node can claim
+	// the funds with the revocation key if we broadcast a revoked
+	// commitment transaction.
+	ourRedeemScript, err := input.CommitScriptToSelf(
+		csvTimeout, keyRing.DelayKey, keyRing.RevocationKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	payToUsScriptHash, err := input.WitnessScriptHash(ourRedeemScript)
+	if err != nil {
+		return nil, err
+	}
+
+	// Next, we create the script paying to them. Depending on the channel
+	// type, this is either a regular P2WKH output without any added CSV
+	// delay, or a P2WSH output only spendable after a confirmation.
+	_, theirPkScript, err := CommitScriptToRemote(
+		chanType, keyRing.NoDelayKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Now that both output scripts have been created, we can finally create
+	// the transaction itself. We use a transaction version of 2 since CSV
+	// will fail unless the tx version is >= 2.
+	commitTx := wire.NewMsgTx(2)
+	commitTx.AddTxIn(&fundingOutput)
+
+	// Avoid creating dust outputs within the commitment transaction.
+	if amountToSelf >= dustLimit {
+		commitTx.AddTxOut(&wire.TxOut{
+			PkScript: payToUsScriptHash,
+			Value:    int64(amountToSelf),
+		})
+	}
+	if amountToThem >= dustLimit {
+		commitTx.AddTxOut(&wire.TxOut{
+			PkScript: theirPkScript,
+			Value:    int64(amountToThem),
+		})
+	}
+
+	return commitTx, nil
+}
+
+// CommitScriptToRemote returns the witness script and the public key script
+// of the output paying to the counterparty of the owner of a commitment, in
+// the format of the given channel type. For channels without anchor outputs,
+// this is a P2WKH output, whose witness script is the public key script
+// itself. For channels with anchor outputs, the output can only be spent
+// after a confirmation, so that it can't be used to pin the commitment.
+func CommitScriptToRemote(chanType channeldb.ChannelType,
+	key *btcec.PublicKey) ([]byte, []byte, error) {
+
+	if !chanType.HasAnchors() {
+		pkScript, err := input.CommitScriptUnencumbered(key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return pkScript, pkScript, nil
+	}
+
+	witnessScript, err := input.CommitScriptToRemoteConfirmed(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkScript, err := input.WitnessScriptHash(witnessScript)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return witnessScript, pkScript, nil
+}
+
+// addAnchor adds an anchor output spendable with the given funding key to
+// the commitment transaction.
+func addAnchor(commitTx *wire.MsgTx, fundingKey *btcec.PublicKey) error {
+	anchorScript, err := input.CommitScriptAnchor(fundingKey)
+	if err != nil {
+		return err
+	}
+	anchorPkScript, err := input.WitnessScriptHash(anchorScript)
+	if err != nil {
+		return err
+	}
+
+	commitTx.AddTxOut(&wire.TxOut{
+		PkScript: anchorPkScript,
+		Value:    int64(AnchorSize),
+	})
+
+	return nil
+}
+
+// AnchorResolution holds the information required to spend our anchor output
+// on a commitment transaction, which allows us to bump the fee of the
+// commitment using CPFP while it's unconfirmed.
+type AnchorResolution struct {
+	// AnchorSignDescriptor is the sign descriptor for our anchor output.
+	AnchorSignDescriptor input.SignDescriptor
+
+	// CommitAnchor is the outpoint of our anchor output on the commitment
+	// transaction.
+	CommitAnchor wire.OutPoint
+
+	// CommitFee is the fee paid by the commitment transaction.
+	CommitFee btcutil.Amount
+
+	// CommitWeight is the weight of the fully signed commitment
+	// transaction.
+	CommitWeight int64
+}
+
+// NewAnchorResolution returns the information required to spend our anchor
+// output on the given, fully signed commitment transaction of the channel. If
+// the channel doesn't use anchor outputs, or we don't have an anchor on the
+// commitment, nil is returned.
+func NewAnchorResolution(chanState *channeldb.OpenChannel,
+	commitTx *wire.MsgTx) (*AnchorResolution, error) {
+
+	if !chanState.ChanType.HasAnchors() {
+		return nil, nil
+	}
+
+	localFundingKey := chanState.LocalChanCfg.MultiSigKey
+	anchorScript, err := input.CommitScriptAnchor(localFundingKey.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	anchorPkScript, err := input.WitnessScriptHash(anchorScript)
+	if err != nil {
+		return nil, err
+	}
+
+	// Locate our anchor output, while summing up the value of all
+	// outputs to determine the fee paid by the commitment.
+	var (
+		anchorOutput *wire.TxOut
+		anchorIndex  uint32
+		totalOut     btcutil.Amount
+	)
+	for i, txOut := range commitTx.TxOut {
+		totalOut += btcutil.Amount(txOut.Value)
+
+		if bytes.Equal(txOut.PkScript, anchorPkScript) {
+			anchorOutput = txOut
+			anchorIndex = uint32(i)
+		}
+	}
+	if anchorOutput == nil {
+		return nil, nil
+	}
+
+	return &AnchorResolution{
+		AnchorSignDescriptor: input.SignDescriptor{
+			KeyDesc:       localFundingKey,
+			WitnessScript: anchorScript,
+			Output:        anchorOutput,
+			HashType:      txscript.SigHashAll | txscript.SigHashForkID,
+		},
+		CommitAnchor: wire.OutPoint{
+			Hash:  commitTx.TxHash(),
+			Index: anchorIndex,
+		},
+		CommitFee: chanState.Capacity - totalOut,
+		CommitWeight: blockchain.GetTransactionWeight(
+			btcutil.NewTx(commitTx),
+		),
+	}, nil
+}
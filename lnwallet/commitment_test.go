@@ -0,0 +1,141 @@
+package lnwallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/input"
+	"github.com/litecoinfinance/lnd/keychain"
+)
+
+// TestCommitmentAnchors ensures that the commitments of channels using anchor
+// outputs carry an anchor for each party with an output on them, and that our
+// anchor can be located once the commitment has been broadcast.
+func TestCommitmentAnchors(t *testing.T) {
+	t.Parallel()
+
+	newKey := func() *btcec.PublicKey {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		return priv.PubKey()
+	}
+
+	localCfg := &channeldb.ChannelConfig{
+		ChannelConstraints: channeldb.ChannelConstraints{
+			DustLimit: 573,
+			CsvDelay:  144,
+		},
+		MultiSigKey: keychain.KeyDescriptor{PubKey: newKey()},
+	}
+	remoteCfg := &channeldb.ChannelConfig{
+		ChannelConstraints: channeldb.ChannelConstraints{
+			DustLimit: 573,
+			CsvDelay:  144,
+		},
+		MultiSigKey: keychain.KeyDescriptor{PubKey: newKey()},
+	}
+	keyRing := &CommitmentKeyRing{
+		DelayKey:      newKey(),
+		NoDelayKey:    newKey(),
+		RevocationKey: newKey(),
+	}
+
+	chanType := channeldb.SingleFunder | channeldb.AnchorOutputsBit
+	builder := NewCommitmentBuilder(chanType, localCfg, remoteCfg)
+
+	const (
+		capacity     = btcutil.Amount(3000000)
+		amountToSelf = btcutil.Amount(1000000)
+		amountToThem = btcutil.Amount(1990000)
+	)
+	commitTx, err := builder.CreateCommitTx(
+		wire.TxIn{}, keyRing, true, amountToSelf, amountToThem, 0,
+	)
+	if err != nil {
+		t.Fatalf("unable to create commitment: %v", err)
+	}
+
+	// Along with the outputs of both parties, the commitment should carry
+	// an anchor for each of them.
+	if len(commitTx.TxOut) != 4 {
+		t.Fatalf("expected 4 outputs, got %d", len(commitTx.TxOut))
+	}
+	var numAnchors int
+	for _, txOut := range commitTx.TxOut {
+		if btcutil.Amount(txOut.Value) == AnchorSize {
+			numAnchors++
+		}
+	}
+	if numAnchors != 2 {
+		t.Fatalf("expected 2 anchors, got %d", numAnchors)
+	}
+
+	// The output paying to the remote party should only be spendable once
+	// the commitment has confirmed.
+	_, toRemotePkScript, err := CommitScriptToRemote(
+		chanType, keyRing.NoDelayKey,
+	)
+	if err != nil {
+		t.Fatalf("unable to create to-remote script: %v", err)
+	}
+	found, _ := input.FindScriptOutputIndex(commitTx, toRemotePkScript)
+	if !found {
+		t.Fatalf("to-remote output not found")
+	}
+
+	// Our anchor should be found on the commitment, along with the fee it
+	// pays.
+	chanState := &channeldb.OpenChannel{
+		ChanType:     chanType,
+		Capacity:     capacity,
+		LocalChanCfg: *localCfg,
+	}
+	anchorRes, err := NewAnchorResolution(chanState, commitTx)
+	if err != nil {
+		t.Fatalf("unable to create anchor resolution: %v", err)
+	}
+	if anchorRes == nil {
+		t.Fatalf("expected anchor resolution")
+	}
+	anchorOutput := commitTx.TxOut[anchorRes.CommitAnchor.Index]
+	if !bytes.Equal(anchorOutput.PkScript,
+		anchorRes.AnchorSignDescriptor.Output.PkScript) {
+
+		t.Fatalf("anchor resolution doesn't spend our anchor")
+	}
+	expectedFee := capacity - amountToSelf - amountToThem - 2*AnchorSize
+	if anchorRes.CommitFee != expectedFee {
+		t.Fatalf("expected commitment fee %v, got %v", expectedFee,
+			anchorRes.CommitFee)
+	}
+
+	// If the remote party doesn't have an output on the commitment, and
+	// there aren't any HTLCs, it doesn't get an anchor.
+	commitTx, err = builder.CreateCommitTx(
+		wire.TxIn{}, keyRing, true, amountToSelf, 0, 0,
+	)
+	if err != nil {
+		t.Fatalf("unable to create commitment: %v", err)
+	}
+	if len(commitTx.TxOut) != 2 {
+		t.Fatalf("expected 2 outputs, got %d", len(commitTx.TxOut))
+	}
+
+	// Commitments of channels without anchor outputs don't carry any.
+	anchorRes, err = NewAnchorResolution(
+		&channeldb.OpenChannel{ChanType: channeldb.SingleFunder},
+		commitTx,
+	)
+	if err != nil {
+		t.Fatalf("unable to create anchor resolution: %v", err)
+	}
+	if anchorRes != nil {
+		t.Fatalf("expected no anchor resolution")
+	}
+}
@@ -0,0 +1,155 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/litecoinfinance/btcd/txscript"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/input"
+)
+
+// ErrCommitFeeSufficient is returned when we're asked to bump the fee of a
+// commitment transaction which already pays the target fee rate.
+var ErrCommitFeeSufficient = errors.New("commitment transaction already " +
+	"pays target fee rate")
+
+// BumpCommitFee crafts and broadcasts a child transaction which spends our
+// anchor output on a commitment transaction, such that the package formed by
+// the commitment and the child pays the given fee rate. As the value of the
+// anchor alone isn't enough to pay for the child, coins of the wallet are
+// added to it, with any excess being returned to a change output.
+func (l *LightningWallet) BumpCommitFee(anchor *AnchorResolution,
+	feeRate SatPerKWeight) (*wire.MsgTx, error) {
+
+	// If the commitment pays the target fee rate on its own, there's
+	// nothing to bump.
+	if feeRate.FeeForWeight(anchor.CommitWeight) <= anchor.CommitFee {
+		return nil, ErrCommitFeeSufficient
+	}
+
+	// We hold the coin select mutex while selecting the coins for the
+	// child, and until it has been published, so they can't be used to
+	// fund a channel concurrently.
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	coins, err := l.listSpendableCoins(1)
+	if err != nil {
+		return nil, err
+	}
+
+	// We'll select coins until they're sufficient to pay the fee of the
+	// child, which is the fee of the whole package minus what the
+	// commitment already pays, while leaving a non-dust change output.
+	var (
+		amtNeeded     btcutil.Amount
+		selectedCoins []*Utxo
+		changeAmt     btcutil.Amount
+	)
+	for {
+		totalSat, selected, err := selectInputs(amtNeeded, coins)
+		if err != nil {
+			return nil, err
+		}
+
+		var weightEstimate input.TxWeightEstimator
+		weightEstimate.AddWitnessInput(input.AnchorWitnessSize)
+		for _, utxo := range selected {
+			switch utxo.AddressType {
+			case WitnessPubKey:
+				weightEstimate.AddP2WKHInput()
+			case NestedWitnessPubKey:
+				weightEstimate.AddNestedP2WKHInput()
+			default:
+				return nil, fmt.Errorf("unsupported address "+
+					"type: %v", utxo.AddressType)
+			}
+		}
+		weightEstimate.AddP2WKHOutput()
+
+		packageWeight := anchor.CommitWeight +
+			int64(weightEstimate.Weight())
+		childFee := feeRate.FeeForWeight(packageWeight) -
+			anchor.CommitFee
+
+		changeAmt = totalSat + AnchorSize - childFee
+		if changeAmt < DefaultDustLimit() {
+			amtNeeded = childFee + DefaultDustLimit() - AnchorSize
+			continue
+		}
+
+		selectedCoins = selected
+		break
+	}
+
+	changeAddr, err := l.NewAddress(WitnessPubKey, true)
+	if err != nil {
+		return nil, err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	// With the coins selected, we can assemble the child, spending our
+	// anchor as its first input.
+	childTx := wire.NewMsgTx(2)
+	childTx.AddTxIn(wire.NewTxIn(&anchor.CommitAnchor, nil, nil))
+	for _, coin := range selectedCoins {
+		childTx.AddTxIn(wire.NewTxIn(&coin.OutPoint, nil, nil))
+	}
+	childTx.AddTxOut(&wire.TxOut{
+		Value:    int64(changeAmt),
+		PkScript: changeScript,
+	})
+
+	sigHashes := txscript.NewTxSigHashes(childTx)
+
+	anchorSignDesc := anchor.AnchorSignDescriptor
+	anchorSignDesc.SigHashes = sigHashes
+	anchorSignDesc.InputIndex = 0
+	anchorWitness, err := input.CommitSpendAnchor(
+		l.Cfg.Signer, &anchorSignDesc, childTx,
+	)
+	if err != nil {
+		return nil, err
+	}
+	childTx.TxIn[0].Witness = anchorWitness
+
+	// Next, sign all the inputs from our wallet.
+	signDesc := input.SignDescriptor{
+		HashType:  txscript.SigHashAll | txscript.SigHashForkID,
+		SigHashes: sigHashes,
+	}
+	for i, txIn := range childTx.TxIn[1:] {
+		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		signDesc.Output = info
+		signDesc.InputIndex = i + 1
+
+		inputScript, err := l.Cfg.Signer.ComputeInputScript(
+			childTx, &signDesc,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		txIn.SignatureScript = inputScript.SigScript
+		txIn.Witness = inputScript.Witness
+	}
+
+	walletLog.Infof("Bumping fee of commitment %v to %v sat/kw with "+
+		"child %v", anchor.CommitAnchor.Hash, int64(feeRate),
+		childTx.TxHash())
+
+	if err := l.PublishTransaction(childTx); err != nil {
+		return nil, err
+	}
+
+	return childTx, nil
+}
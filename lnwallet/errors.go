@@ -21,6 +21,29 @@ type ReservationError struct {
 // interface.
 var _ error = (*ReservationError)(nil)
 
+// chanReserveError is wrapped by the ReservationErrors rejecting the channel
+// reserve required by the remote party, allowing IsChanReserveError to tell
+// them apart from other ReservationErrors.
+type chanReserveError struct {
+	error
+}
+
+// IsChanReserveError returns true if the given error signals that a channel
+// reserve is out of bounds, or that an HTLC would dip a party below it.
+func IsChanReserveError(err error) bool {
+	if err == ErrBelowChanReserve {
+		return true
+	}
+
+	resErr, ok := err.(ReservationError)
+	if !ok {
+		return false
+	}
+
+	_, ok = resErr.error.(chanReserveError)
+	return ok
+}
+
 // ErrZeroCapacity returns an error indicating the funder attempted to put zero
 // funds into the channel.
 func ErrZeroCapacity() ReservationError {
@@ -63,8 +86,33 @@ func ErrCsvDelayTooLarge(remoteDelay, maxDelay uint16) ReservationError {
 // the remote is requiring is too small to be accepted.
 func ErrChanReserveTooSmall(reserve, dustLimit btcutil.Amount) ReservationError {
 	return ReservationError{
-		fmt.Errorf("channel reserve of %v sat is too small, min is %v "+
-			"sat", int64(reserve), int64(dustLimit)),
+		chanReserveError{
+			fmt.Errorf("channel reserve of %v sat is too small, "+
+				"min is %v sat", int64(reserve),
+				int64(dustLimit)),
+		},
+	}
+}
+
+// ErrDustLimitTooSmall returns an error indicating that the dust limit of a
+// channel is too small to be accepted, along with the current min.
+func ErrDustLimitTooSmall(dustLimit,
+	minDustLimit btcutil.Amount) ReservationError {
+
+	return ReservationError{
+		fmt.Errorf("dust limit of %v sat is too small, min is %v sat",
+			int64(dustLimit), int64(minDustLimit)),
+	}
+}
+
+// ErrDustLimitTooLarge returns an error indicating that the dust limit of a
+// channel is too large to be accepted, along with the current max.
+func ErrDustLimitTooLarge(dustLimit,
+	maxDustLimit btcutil.Amount) ReservationError {
+
+	return ReservationError{
+		fmt.Errorf("dust limit of %v sat is too large, max is %v sat",
+			int64(dustLimit), int64(maxDustLimit)),
 	}
 }
 
@@ -73,8 +121,11 @@ func ErrChanReserveTooSmall(reserve, dustLimit btcutil.Amount) ReservationError
 func ErrChanReserveTooLarge(reserve,
 	maxReserve btcutil.Amount) ReservationError {
 	return ReservationError{
-		fmt.Errorf("Channel reserve is too large: %v sat, max "+
-			"is %v sat", int64(reserve), int64(maxReserve)),
+		chanReserveError{
+			fmt.Errorf("Channel reserve is too large: %v sat, "+
+				"max is %v sat", int64(reserve),
+				int64(maxReserve)),
+		},
 	}
 }
 
@@ -142,6 +193,59 @@ func ErrChanTooSmall(chanSize, minChanSize btcutil.Amount) ReservationError {
 	}
 }
 
+// ErrDualFundingDisabled is returned by a remote peer that receives a request
+// to contribute funds to a channel, while dual funding is disabled.
+func ErrDualFundingDisabled() ReservationError {
+	return ReservationError{errors.New("dual funding is disabled")}
+}
+
+// ErrDualFundDeclined is returned by a remote peer that declines to contribute
+// the requested amount of funds to a channel.
+func ErrDualFundDeclined(amt btcutil.Amount) ReservationError {
+	return ReservationError{
+		fmt.Errorf("declined to contribute %v to channel", amt),
+	}
+}
+
+// ErrInvalidContribution returns an error indicating that the contribution of
+// the remote party to a dual funded channel is invalid.
+func ErrInvalidContribution(reason string) ReservationError {
+	return ReservationError{
+		fmt.Errorf("invalid funding contribution: %v", reason),
+	}
+}
+
+// ErrChannelRejected returns an error indicating that the channel proposed by
+// the remote party was rejected for the given reason.
+func ErrChannelRejected(reason string) ReservationError {
+	if reason == "" {
+		return ReservationError{errors.New("channel rejected")}
+	}
+
+	return ReservationError{
+		fmt.Errorf("channel rejected: %v", reason),
+	}
+}
+
+// ErrZeroConfDisabled is returned by a remote peer that receives a request to
+// open a zero-conf channel, while it doesn't accept such channels.
+func ErrZeroConfDisabled() ReservationError {
+	return ReservationError{errors.New("zero-conf channels are disabled")}
+}
+
+// ErrZeroConfExposureTooLarge returns an error indicating that a zero-conf
+// channel of the given capacity would raise our total exposure to zero-conf
+// channels whose funding transaction has yet to confirm beyond the maximum.
+func ErrZeroConfExposureTooLarge(capacity, exposure,
+	maxExposure btcutil.Amount) ReservationError {
+
+	return ReservationError{
+		fmt.Errorf("zero-conf channel of %v exceeds max zero-conf "+
+			"exposure of %v, current exposure is %v", capacity,
+			maxExposure, exposure),
+	}
+}
+
 // ErrHtlcIndexAlreadyFailed is returned when the HTLC index has already been
 // failed, but has not been committed by our commitment state.
 type ErrHtlcIndexAlreadyFailed uint64
@@ -13,6 +13,7 @@ import (
 	"github.com/litecoinfinance/btcd/blockchain"
 	"github.com/litecoinfinance/btcd/rpcclient"
 	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/subscribe"
 )
 
 const (
@@ -160,11 +161,11 @@ type BtcdFeeEstimator struct {
 	// produce fee estimates.
 	fallbackFeePerKW SatPerKWeight
 
-	// minFeePerKW is the minimum fee, in sat/kw, that we should enforce.
-	// This will be used as the default fee rate for a transaction when the
-	// estimated fee rate is too low to allow the transaction to propagate
-	// through the network.
-	minFeePerKW SatPerKWeight
+	// feeFilter tracks the backend node's fee filter. Its minimum fee
+	// rate will be used as the default fee rate for a transaction when
+	// the estimated fee rate is too low to allow the transaction to
+	// propagate through the network.
+	feeFilter *feeFilterTracker
 
 	btcdConn *rpcclient.Client
 }
@@ -184,10 +185,15 @@ func NewBtcdFeeEstimator(rpcConfig rpcclient.ConnConfig,
 		return nil, err
 	}
 
-	return &BtcdFeeEstimator{
+	b := &BtcdFeeEstimator{
 		fallbackFeePerKW: fallBackFeeRate,
 		btcdConn:         chainConn,
-	}, nil
+	}
+	b.feeFilter = newFeeFilterTracker(
+		b.fetchFeeFilter, DefaultFeeFilterUpdateInterval,
+	)
+
+	return b, nil
 }
 
 // Start signals the FeeEstimator to start any processes or goroutines
@@ -200,31 +206,14 @@ func (b *BtcdFeeEstimator) Start() error {
 	}
 
 	// Once the connection to the backend node has been established, we'll
-	// query it for its minimum relay fee.
-	info, err := b.btcdConn.GetInfo()
-	if err != nil {
-		return err
-	}
-
-	relayFee, err := btcutil.NewAmount(info.RelayFee)
-	if err != nil {
+	// start tracking its fee filter. By default, we'll use its minimum
+	// relay fee as the minimum fee rate we'll propose for transactions.
+	if err := b.feeFilter.Start(); err != nil {
 		return err
 	}
 
-	// The fee rate is expressed in sat/kb, so we'll manually convert it to
-	// our desired sat/kw rate.
-	minRelayFeePerKw := SatPerKVByte(relayFee).FeePerKWeight()
-
-	// By default, we'll use the backend node's minimum relay fee as the
-	// minimum fee rate we'll propose for transacations. However, if this
-	// happens to be lower than our fee floor, we'll enforce that instead.
-	b.minFeePerKW = minRelayFeePerKw
-	if b.minFeePerKW < FeePerKwFloor {
-		b.minFeePerKW = FeePerKwFloor
-	}
-
 	walletLog.Debugf("Using minimum fee rate of %v sat/kw",
-		int64(b.minFeePerKW))
+		int64(b.feeFilter.FeeFilter().MinFeePerKW()))
 
 	return nil
 }
@@ -234,11 +223,51 @@ func (b *BtcdFeeEstimator) Start() error {
 //
 // NOTE: This method is part of the FeeEstimator interface.
 func (b *BtcdFeeEstimator) Stop() error {
+	b.feeFilter.Stop()
 	b.btcdConn.Shutdown()
 
 	return nil
 }
 
+// fetchFeeFilter queries btcd for its current fee filter. As btcd doesn't
+// evict transactions from its mempool based on their fee rate, its minimum
+// relay fee is also its mempool min fee.
+func (b *BtcdFeeEstimator) fetchFeeFilter() (FeeFilter, error) {
+	info, err := b.btcdConn.GetInfo()
+	if err != nil {
+		return FeeFilter{}, err
+	}
+
+	relayFee, err := btcutil.NewAmount(info.RelayFee)
+	if err != nil {
+		return FeeFilter{}, err
+	}
+
+	// The fee rate is expressed in sat/kb, so we'll manually convert it to
+	// our desired sat/kw rate.
+	relayFeePerKw := SatPerKVByte(relayFee).FeePerKWeight()
+
+	return FeeFilter{
+		RelayFee:      relayFeePerKw,
+		MempoolMinFee: relayFeePerKw,
+	}, nil
+}
+
+// FeeFilter returns the most recently known fee filter of the backend.
+//
+// NOTE: This method is part of the FeeFilterSource interface.
+func (b *BtcdFeeEstimator) FeeFilter() FeeFilter {
+	return b.feeFilter.FeeFilter()
+}
+
+// SubscribeFeeFilter returns a client that will receive a *FeeFilterUpdate
+// whenever the backend's fee filter changes significantly.
+//
+// NOTE: This method is part of the FeeFilterSource interface.
+func (b *BtcdFeeEstimator) SubscribeFeeFilter() (*subscribe.Client, error) {
+	return b.feeFilter.SubscribeFeeFilter()
+}
+
 // EstimateFeePerKW takes in a target for the number of blocks until an initial
 // confirmation and returns the estimated fee expressed in sat/kw.
 //
@@ -265,7 +294,12 @@ func (b *BtcdFeeEstimator) EstimateFeePerKW(numBlocks uint32) (SatPerKWeight, er
 //
 // NOTE: This method is part of the FeeEstimator interface.
 func (b *BtcdFeeEstimator) RelayFeePerKW() SatPerKWeight {
-	return b.minFeePerKW
+	relayFee := b.feeFilter.FeeFilter().RelayFee
+	if relayFee < FeePerKwFloor {
+		return FeePerKwFloor
+	}
+
+	return relayFee
 }
 
 // fetchEstimate returns a fee estimate for a transaction to be confirmed in
@@ -288,12 +322,14 @@ func (b *BtcdFeeEstimator) fetchEstimate(confTarget uint32) (SatPerKWeight, erro
 	// estimated fee rate from its sat/kb representation to sat/kw.
 	satPerKw := SatPerKVByte(satPerKB).FeePerKWeight()
 
-	// Finally, we'll enforce our fee floor.
-	if satPerKw < b.minFeePerKW {
+	// Finally, we'll enforce our fee floor, which is kept up to date with
+	// the backend's fee filter.
+	minFeePerKW := b.feeFilter.FeeFilter().MinFeePerKW()
+	if satPerKw < minFeePerKW {
 		walletLog.Debugf("Estimated fee rate of %v sat/kw is too low, "+
 			"using fee floor of %v sat/kw instead", satPerKw,
-			b.minFeePerKW)
-		satPerKw = b.minFeePerKW
+			minFeePerKW)
+		satPerKw = minFeePerKW
 	}
 
 	walletLog.Debugf("Returning %v sat/kw for conf target of %v",
@@ -306,6 +342,10 @@ func (b *BtcdFeeEstimator) fetchEstimate(confTarget uint32) (SatPerKWeight, erro
 // FeeEstimator interface.
 var _ FeeEstimator = (*BtcdFeeEstimator)(nil)
 
+// A compile-time assertion to ensure that BtcdFeeEstimator implements the
+// FeeFilterSource interface.
+var _ FeeFilterSource = (*BtcdFeeEstimator)(nil)
+
 // BitcoindFeeEstimator is an implementation of the FeeEstimator interface
 // backed by the RPC interface of an active bitcoind node. This implementation
 // will proxy any fee estimation requests to bitcoind's RPC interface.
@@ -315,11 +355,11 @@ type BitcoindFeeEstimator struct {
 	// produce fee estimates.
 	fallbackFeePerKW SatPerKWeight
 
-	// minFeePerKW is the minimum fee, in sat/kw, that we should enforce.
-	// This will be used as the default fee rate for a transaction when the
-	// estimated fee rate is too low to allow the transaction to propagate
-	// through the network.
-	minFeePerKW SatPerKWeight
+	// feeFilter tracks the backend node's fee filter. Its minimum fee
+	// rate will be used as the default fee rate for a transaction when
+	// the estimated fee rate is too low to allow the transaction to
+	// propagate through the network.
+	feeFilter *feeFilterTracker
 
 	bitcoindConn *rpcclient.Client
 }
@@ -341,10 +381,15 @@ func NewBitcoindFeeEstimator(rpcConfig rpcclient.ConnConfig,
 		return nil, err
 	}
 
-	return &BitcoindFeeEstimator{
+	b := &BitcoindFeeEstimator{
 		fallbackFeePerKW: fallBackFeeRate,
 		bitcoindConn:     chainConn,
-	}, nil
+	}
+	b.feeFilter = newFeeFilterTracker(
+		b.fetchFeeFilter, DefaultFeeFilterUpdateInterval,
+	)
+
+	return b, nil
 }
 
 // Start signals the FeeEstimator to start any processes or goroutines
@@ -352,13 +397,35 @@ func NewBitcoindFeeEstimator(rpcConfig rpcclient.ConnConfig,
 //
 // NOTE: This method is part of the FeeEstimator interface.
 func (b *BitcoindFeeEstimator) Start() error {
-	// Once the connection to the backend node has been established, we'll
-	// query it for its minimum relay fee. Since the `getinfo` RPC has been
-	// deprecated for `bitcoind`, we'll need to send a `getnetworkinfo`
-	// command as a raw request.
+	// We'll start tracking the backend node's fee filter. By default,
+	// we'll use the higher of its minimum relay fee and mempool min fee as
+	// the minimum fee rate we'll propose for transactions.
+	if err := b.feeFilter.Start(); err != nil {
+		return err
+	}
+
+	walletLog.Debugf("Using minimum fee rate of %v sat/kw",
+		int64(b.feeFilter.FeeFilter().MinFeePerKW()))
+
+	return nil
+}
+
+// Stop stops any spawned goroutines and cleans up the resources used
+// by the fee estimator.
+//
+// NOTE: This method is part of the FeeEstimator interface.
+func (b *BitcoindFeeEstimator) Stop() error {
+	return b.feeFilter.Stop()
+}
+
+// fetchFeeFilter queries bitcoind for its current fee filter.
+func (b *BitcoindFeeEstimator) fetchFeeFilter() (FeeFilter, error) {
+	// Since the `getinfo` RPC has been deprecated for `bitcoind`, we'll
+	// need to send a `getnetworkinfo` command as a raw request to retrieve
+	// its minimum relay fee.
 	resp, err := b.bitcoindConn.RawRequest("getnetworkinfo", nil)
 	if err != nil {
-		return err
+		return FeeFilter{}, err
 	}
 
 	// Parse the response to retrieve the relay fee in sat/KB.
@@ -366,38 +433,54 @@ func (b *BitcoindFeeEstimator) Start() error {
 		RelayFee float64 `json:"relayfee"`
 	}{}
 	if err := json.Unmarshal(resp, &info); err != nil {
-		return err
+		return FeeFilter{}, err
 	}
 
 	relayFee, err := btcutil.NewAmount(info.RelayFee)
 	if err != nil {
-		return err
+		return FeeFilter{}, err
 	}
 
-	// The fee rate is expressed in sat/kb, so we'll manually convert it to
-	// our desired sat/kw rate.
-	minRelayFeePerKw := SatPerKVByte(relayFee).FeePerKWeight()
+	// The mempool min fee changes dynamically as the mempool fills up, so
+	// we'll need to query for it separately.
+	resp, err = b.bitcoindConn.RawRequest("getmempoolinfo", nil)
+	if err != nil {
+		return FeeFilter{}, err
+	}
 
-	// By default, we'll use the backend node's minimum relay fee as the
-	// minimum fee rate we'll propose for transacations. However, if this
-	// happens to be lower than our fee floor, we'll enforce that instead.
-	b.minFeePerKW = minRelayFeePerKw
-	if b.minFeePerKW < FeePerKwFloor {
-		b.minFeePerKW = FeePerKwFloor
+	mempoolInfo := struct {
+		MempoolMinFee float64 `json:"mempoolminfee"`
+	}{}
+	if err := json.Unmarshal(resp, &mempoolInfo); err != nil {
+		return FeeFilter{}, err
 	}
 
-	walletLog.Debugf("Using minimum fee rate of %v sat/kw",
-		int64(b.minFeePerKW))
+	mempoolMinFee, err := btcutil.NewAmount(mempoolInfo.MempoolMinFee)
+	if err != nil {
+		return FeeFilter{}, err
+	}
 
-	return nil
+	// Both fee rates are expressed in sat/kb, so we'll manually convert
+	// them to our desired sat/kw rate.
+	return FeeFilter{
+		RelayFee:      SatPerKVByte(relayFee).FeePerKWeight(),
+		MempoolMinFee: SatPerKVByte(mempoolMinFee).FeePerKWeight(),
+	}, nil
 }
 
-// Stop stops any spawned goroutines and cleans up the resources used
-// by the fee estimator.
+// FeeFilter returns the most recently known fee filter of the backend.
 //
-// NOTE: This method is part of the FeeEstimator interface.
-func (b *BitcoindFeeEstimator) Stop() error {
-	return nil
+// NOTE: This method is part of the FeeFilterSource interface.
+func (b *BitcoindFeeEstimator) FeeFilter() FeeFilter {
+	return b.feeFilter.FeeFilter()
+}
+
+// SubscribeFeeFilter returns a client that will receive a *FeeFilterUpdate
+// whenever the backend's fee filter changes significantly.
+//
+// NOTE: This method is part of the FeeFilterSource interface.
+func (b *BitcoindFeeEstimator) SubscribeFeeFilter() (*subscribe.Client, error) {
+	return b.feeFilter.SubscribeFeeFilter()
 }
 
 // EstimateFeePerKW takes in a target for the number of blocks until an initial
@@ -426,7 +509,12 @@ func (b *BitcoindFeeEstimator) EstimateFeePerKW(numBlocks uint32) (SatPerKWeight
 //
 // NOTE: This method is part of the FeeEstimator interface.
 func (b *BitcoindFeeEstimator) RelayFeePerKW() SatPerKWeight {
-	return b.minFeePerKW
+	relayFee := b.feeFilter.FeeFilter().RelayFee
+	if relayFee < FeePerKwFloor {
+		return FeePerKwFloor
+	}
+
+	return relayFee
 }
 
 // fetchEstimate returns a fee estimate for a transaction to be confirmed in
@@ -466,13 +554,15 @@ func (b *BitcoindFeeEstimator) fetchEstimate(confTarget uint32) (SatPerKWeight,
 	// estimated fee rate from its sat/kb representation to sat/kw.
 	satPerKw := SatPerKVByte(satPerKB).FeePerKWeight()
 
-	// Finally, we'll enforce our fee floor.
-	if satPerKw < b.minFeePerKW {
+	// Finally, we'll enforce our fee floor, which is kept up to date with
+	// the backend's fee filter.
+	minFeePerKW := b.feeFilter.FeeFilter().MinFeePerKW()
+	if satPerKw < minFeePerKW {
 		walletLog.Debugf("Estimated fee rate of %v sat/kw is too low, "+
 			"using fee floor of %v sat/kw instead", satPerKw,
-			b.minFeePerKW)
+			minFeePerKW)
 
-		satPerKw = b.minFeePerKW
+		satPerKw = minFeePerKW
 	}
 
 	walletLog.Debugf("Returning %v sat/kw for conf target of %v",
@@ -485,6 +575,10 @@ func (b *BitcoindFeeEstimator) fetchEstimate(confTarget uint32) (SatPerKWeight,
 // FeeEstimator interface.
 var _ FeeEstimator = (*BitcoindFeeEstimator)(nil)
 
+// A compile-time assertion to ensure that BitcoindFeeEstimator implements the
+// FeeFilterSource interface.
+var _ FeeFilterSource = (*BitcoindFeeEstimator)(nil)
+
 // WebAPIFeeSource is an interface allows the WebAPIFeeEstimator to query an
 // arbitrary HTTP-based fee estimator. Each new set/network will gain an
 // implementation of this interface in order to allow the WebAPIFeeEstimator to
@@ -0,0 +1,268 @@
+package lnwallet
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/lnd/subscribe"
+)
+
+const (
+	// DefaultFeeFilterUpdateInterval is the default interval in which fee
+	// estimators backed by a full node will poll it for changes to its
+	// fee filter.
+	DefaultFeeFilterUpdateInterval = 5 * time.Minute
+
+	// significantFeeFilterChange is the percentage by which either
+	// component of a fee filter must change before subscribers are
+	// notified.
+	significantFeeFilterChange = 10
+)
+
+var (
+	// ErrFeeFilterTrackerNotActive is returned when attempting to
+	// subscribe to fee filter updates of a fee estimator that hasn't been
+	// started.
+	ErrFeeFilterTrackerNotActive = errors.New("fee filter tracker not " +
+		"active")
+)
+
+// FeeFilter describes the minimum fee rates a chain backend requires for
+// transactions to be accepted into its mempool and relayed.
+type FeeFilter struct {
+	// RelayFee is the backend's static minimum relay fee rate. This is
+	// also the basis for calculation of the dust limit.
+	RelayFee SatPerKWeight
+
+	// MempoolMinFee is the minimum fee rate currently required for
+	// transactions to be accepted into the backend's mempool. This may
+	// rise above the RelayFee while the mempool is full.
+	MempoolMinFee SatPerKWeight
+}
+
+// MinFeePerKW returns the lowest fee rate a transaction can currently pay to
+// be accepted by the backend, which is never below our fee floor.
+func (f FeeFilter) MinFeePerKW() SatPerKWeight {
+	minFee := f.RelayFee
+	if f.MempoolMinFee > minFee {
+		minFee = f.MempoolMinFee
+	}
+	if minFee < FeePerKwFloor {
+		minFee = FeePerKwFloor
+	}
+
+	return minFee
+}
+
+// FeeFilterUpdate is sent to subscribers of a FeeFilterSource whenever the
+// backend's fee filter changes significantly.
+type FeeFilterUpdate struct {
+	// Old is the fee filter before the change.
+	Old FeeFilter
+
+	// New is the fee filter after the change.
+	New FeeFilter
+}
+
+// FeeFilterSource is implemented by fee estimators that dynamically track the
+// fee filter of the chain backend they're connected to.
+type FeeFilterSource interface {
+	// FeeFilter returns the most recently known fee filter of the
+	// backend.
+	FeeFilter() FeeFilter
+
+	// SubscribeFeeFilter returns a client that will receive a
+	// *FeeFilterUpdate whenever the backend's fee filter changes
+	// significantly.
+	SubscribeFeeFilter() (*subscribe.Client, error)
+}
+
+// MinMempoolFeePerKW returns the lowest fee rate a transaction can currently
+// pay to be accepted into the mempool of the estimator's backend. If the
+// estimator doesn't track the backend's fee filter, its static relay fee is
+// used instead.
+func MinMempoolFeePerKW(estimator FeeEstimator) SatPerKWeight {
+	if source, ok := estimator.(FeeFilterSource); ok {
+		return source.FeeFilter().MinFeePerKW()
+	}
+
+	return estimator.RelayFeePerKW()
+}
+
+// isSignificantFeeChange returns true if the new fee rate differs from the
+// old one by at least significantFeeFilterChange percent.
+func isSignificantFeeChange(oldFee, newFee SatPerKWeight) bool {
+	delta := newFee - oldFee
+	switch {
+	case delta == 0:
+		return false
+
+	case delta < 0:
+		delta = -delta
+	}
+
+	return delta*100 >= oldFee*significantFeeFilterChange
+}
+
+// feeFilterTracker periodically polls a chain backend for its fee filter, and
+// notifies its subscribers of any significant changes.
+type feeFilterTracker struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	// fetchFeeFilter queries the backend for its current fee filter.
+	fetchFeeFilter func() (FeeFilter, error)
+
+	// updateInterval is the interval in which we'll poll the backend.
+	updateInterval time.Duration
+
+	mu     sync.RWMutex
+	filter FeeFilter
+
+	// notified is the fee filter subscribers were last notified of. Minor
+	// changes are measured against it rather than the latest filter, such
+	// that a fee filter slowly drifting over several updates is still
+	// detected as significant once it has moved far enough.
+	notified FeeFilter
+
+	ntfnServer *subscribe.Server
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// newFeeFilterTracker creates a new feeFilterTracker that uses the given
+// closure to query the backend for its fee filter.
+func newFeeFilterTracker(fetchFeeFilter func() (FeeFilter, error),
+	updateInterval time.Duration) *feeFilterTracker {
+
+	return &feeFilterTracker{
+		fetchFeeFilter: fetchFeeFilter,
+		updateInterval: updateInterval,
+		ntfnServer:     subscribe.NewServer(),
+		quit:           make(chan struct{}),
+	}
+}
+
+// Start queries the backend for its initial fee filter, and then begins to
+// poll it for any changes.
+func (t *feeFilterTracker) Start() error {
+	filter, err := t.fetchFeeFilter()
+	if err != nil {
+		return err
+	}
+
+	if !atomic.CompareAndSwapUint32(&t.started, 0, 1) {
+		return nil
+	}
+
+	t.mu.Lock()
+	t.filter = filter
+	t.notified = filter
+	t.mu.Unlock()
+
+	walletLog.Debugf("Backend relay fee is %v sat/kw, mempool min fee is "+
+		"%v sat/kw", int64(filter.RelayFee), int64(filter.MempoolMinFee))
+
+	if err := t.ntfnServer.Start(); err != nil {
+		return err
+	}
+
+	t.wg.Add(1)
+	go t.pollFeeFilter()
+
+	return nil
+}
+
+// Stop stops polling the backend and cancels all subscriptions.
+func (t *feeFilterTracker) Stop() error {
+	if atomic.LoadUint32(&t.started) == 0 ||
+		!atomic.CompareAndSwapUint32(&t.stopped, 0, 1) {
+
+		return nil
+	}
+
+	close(t.quit)
+	t.wg.Wait()
+
+	return t.ntfnServer.Stop()
+}
+
+// FeeFilter returns the most recently known fee filter of the backend.
+func (t *feeFilterTracker) FeeFilter() FeeFilter {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.filter
+}
+
+// SubscribeFeeFilter returns a client that will receive a *FeeFilterUpdate
+// whenever the backend's fee filter changes significantly.
+func (t *feeFilterTracker) SubscribeFeeFilter() (*subscribe.Client, error) {
+	if atomic.LoadUint32(&t.started) == 0 {
+		return nil, ErrFeeFilterTrackerNotActive
+	}
+
+	return t.ntfnServer.Subscribe()
+}
+
+// pollFeeFilter periodically queries the backend for its fee filter.
+//
+// NOTE: This MUST be run as a goroutine.
+func (t *feeFilterTracker) pollFeeFilter() {
+	defer t.wg.Done()
+
+	updateTicker := time.NewTicker(t.updateInterval)
+	defer updateTicker.Stop()
+
+	for {
+		select {
+		case <-updateTicker.C:
+			filter, err := t.fetchFeeFilter()
+			if err != nil {
+				walletLog.Errorf("Unable to query backend fee "+
+					"filter: %v", err)
+				continue
+			}
+
+			t.update(filter)
+
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// update records the backend's latest fee filter, and notifies subscribers if
+// it changed significantly from the last one they were notified of.
+func (t *feeFilterTracker) update(filter FeeFilter) {
+	t.mu.Lock()
+	t.filter = filter
+
+	old := t.notified
+	significant := isSignificantFeeChange(old.RelayFee, filter.RelayFee) ||
+		isSignificantFeeChange(old.MempoolMinFee, filter.MempoolMinFee)
+	if significant {
+		t.notified = filter
+	}
+	t.mu.Unlock()
+
+	if !significant {
+		return
+	}
+
+	walletLog.Infof("Backend fee filter changed: relay fee %v -> %v "+
+		"sat/kw, mempool min fee %v -> %v sat/kw", int64(old.RelayFee),
+		int64(filter.RelayFee), int64(old.MempoolMinFee),
+		int64(filter.MempoolMinFee))
+
+	err := t.ntfnServer.SendUpdate(&FeeFilterUpdate{
+		Old: old,
+		New: filter,
+	})
+	if err != nil {
+		walletLog.Errorf("Unable to send fee filter update: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package lnwallet
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFeeFilterMinFeePerKW asserts that the minimum fee rate of a fee filter
+// is the higher of its components, but never below our fee floor.
+func TestFeeFilterMinFeePerKW(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		filter FeeFilter
+		minFee SatPerKWeight
+	}{
+		{
+			filter: FeeFilter{RelayFee: 1000, MempoolMinFee: 500},
+			minFee: 1000,
+		},
+		{
+			filter: FeeFilter{RelayFee: 1000, MempoolMinFee: 5000},
+			minFee: 5000,
+		},
+		{
+			filter: FeeFilter{RelayFee: 100, MempoolMinFee: 0},
+			minFee: FeePerKwFloor,
+		},
+	}
+
+	for i, test := range tests {
+		minFee := test.filter.MinFeePerKW()
+		if minFee != test.minFee {
+			t.Fatalf("test #%d: expected min fee %v, got %v", i,
+				test.minFee, minFee)
+		}
+	}
+}
+
+// TestFeeFilterTracker asserts that subscribers of a feeFilterTracker are only
+// notified of significant changes to the backend's fee filter, while the
+// latest fee filter is always made available.
+func TestFeeFilterTracker(t *testing.T) {
+	t.Parallel()
+
+	initial := FeeFilter{RelayFee: 1000, MempoolMinFee: 1000}
+	tracker := newFeeFilterTracker(func() (FeeFilter, error) {
+		return initial, nil
+	}, time.Hour)
+
+	if _, err := tracker.SubscribeFeeFilter(); err == nil {
+		t.Fatalf("expected subscription to inactive tracker to fail")
+	}
+
+	if err := tracker.Start(); err != nil {
+		t.Fatalf("unable to start tracker: %v", err)
+	}
+	defer tracker.Stop()
+
+	client, err := tracker.SubscribeFeeFilter()
+	if err != nil {
+		t.Fatalf("unable to subscribe to fee filter: %v", err)
+	}
+	defer client.Cancel()
+
+	assertFeeFilter := func(expected FeeFilter) {
+		t.Helper()
+
+		if filter := tracker.FeeFilter(); filter != expected {
+			t.Fatalf("expected fee filter %v, got %v", expected,
+				filter)
+		}
+	}
+	assertNoUpdate := func() {
+		t.Helper()
+
+		select {
+		case update := <-client.Updates():
+			t.Fatalf("received unexpected update: %v", update)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	assertUpdate := func(expected FeeFilterUpdate) {
+		t.Helper()
+
+		select {
+		case update := <-client.Updates():
+			if *update.(*FeeFilterUpdate) != expected {
+				t.Fatalf("expected update %v, got %v",
+					expected, update)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected fee filter update")
+		}
+	}
+
+	assertFeeFilter(initial)
+
+	// A minor increase of the mempool min fee should be reflected by the
+	// tracker, but not be sent to subscribers.
+	minor := FeeFilter{RelayFee: 1000, MempoolMinFee: 1050}
+	tracker.update(minor)
+	assertFeeFilter(minor)
+	assertNoUpdate()
+
+	// Once the mempool min fee has drifted far enough from the one we've
+	// last notified of, the change is significant.
+	drifted := FeeFilter{RelayFee: 1000, MempoolMinFee: 1100}
+	tracker.update(drifted)
+	assertFeeFilter(drifted)
+	assertUpdate(FeeFilterUpdate{Old: initial, New: drifted})
+
+	// A significant decrease should be sent to subscribers as well.
+	decreased := FeeFilter{RelayFee: 1000, MempoolMinFee: 1000}
+	tracker.update(decreased)
+	assertUpdate(FeeFilterUpdate{Old: drifted, New: decreased})
+}
@@ -30,6 +30,11 @@ const (
 	UnknownAddressType
 )
 
+// DefaultAccountName is the name of the default account of the wallet. Only
+// the funds of the default account are used by lnd itself, e.g. to fund
+// channels, while those of any other account are reserved for the operator.
+const DefaultAccountName = "default"
+
 var (
 	// DefaultPublicPassphrase is the default public passphrase used for the
 	// wallet.
@@ -47,6 +52,18 @@ var (
 	// ErrNotMine is an error denoting that a WalletController instance is
 	// unable to spend a specified output.
 	ErrNotMine = errors.New("the passed output doesn't belong to the wallet")
+
+	// ErrTxLabelExists is returned when a transaction that already has a
+	// label is labelled without allowing the label to be overwritten.
+	ErrTxLabelExists = errors.New("transaction already labelled")
+
+	// ErrEmptyLabel is returned when a transaction is labelled with an
+	// empty label.
+	ErrEmptyLabel = errors.New("cannot label transaction with empty label")
+
+	// ErrUnknownTransaction is returned when a transaction that isn't
+	// relevant to the wallet is labelled.
+	ErrUnknownTransaction = errors.New("transaction not found in wallet")
 )
 
 // ErrNoOutputs is returned if we try to create a transaction with no outputs
@@ -54,7 +71,9 @@ var (
 var ErrNoOutputs = errors.New("no outputs")
 
 // Utxo is an unspent output denoted by its outpoint, and output value of the
-// original output.
+// original output. Account is the name of the wallet account the output
+// belongs to. Wallets without accounts leave it empty, which is treated as the
+// default account.
 type Utxo struct {
 	AddressType   AddressType
 	Value         btcutil.Amount
@@ -62,6 +81,7 @@ type Utxo struct {
 	PkScript      []byte
 	RedeemScript  []byte
 	WitnessScript []byte
+	Account       string
 	wire.OutPoint
 }
 
@@ -101,6 +121,9 @@ type TransactionDetail struct {
 
 	// DestAddresses are the destinations for a transaction
 	DestAddresses []btcutil.Address
+
+	// Label is the label the transaction was given, if any.
+	Label string
 }
 
 // TransactionSubscription is an interface which describes an object capable of
@@ -166,6 +189,27 @@ type WalletController interface {
 	// IsOurAddress checks if the passed address belongs to this wallet
 	IsOurAddress(a btcutil.Address) bool
 
+	// CreateAccount creates a new account with the given name. Each
+	// account is backed by its own branch within the key scopes of all
+	// supported address types, so its funds are kept apart from those of
+	// the other accounts.
+	CreateAccount(name string) error
+
+	// ListAccounts returns the names of all accounts of the wallet,
+	// including the default account.
+	ListAccounts() ([]string, error)
+
+	// NewAccountAddress returns the next external address of the given
+	// type for the named account.
+	NewAccountAddress(account string,
+		addrType AddressType) (btcutil.Address, error)
+
+	// LabelTransaction labels the wallet transaction with the given hash.
+	// If the transaction already has a label, ErrTxLabelExists is
+	// returned unless overwrite is set.
+	LabelTransaction(hash chainhash.Hash, label string,
+		overwrite bool) error
+
 	// SendOutputs funds, signs, and broadcasts a Bitcoin transaction paying
 	// out to the specified outputs. In the case the wallet has insufficient
 	// funds, or the outputs are non-standard, an error should be returned.
@@ -29,12 +29,12 @@ import (
 	"github.com/litecoinfinance/btcwallet/chain"
 	"github.com/litecoinfinance/btcwallet/walletdb"
 	_ "github.com/litecoinfinance/btcwallet/walletdb/bdb"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/litecoinfinance/neutrino"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/chainntnfs/btcdnotify"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/input"
 	"github.com/litecoinfinance/lnd/keychain"
 	"github.com/litecoinfinance/lnd/lnwallet"
@@ -456,7 +456,8 @@ func testDualFundingReservationWorkflow(miner *rpctest.Harness,
 
 	// Bob does the same, generating his own contribution. He then also
 	// receives' Alice's contribution, and consumes that so we can continue
-	// the funding process.
+	// the funding process. As Alice initiated the workflow, she'll be the
+	// one paying the commitment fee.
 	bobReq := &lnwallet.InitFundingReserveMsg{
 		ChainHash:       chainHash,
 		NodeID:          alicePub,
@@ -467,6 +468,7 @@ func testDualFundingReservationWorkflow(miner *rpctest.Harness,
 		FundingFeePerKw: feePerKw,
 		PushMSat:        0,
 		Flags:           lnwire.FFAnnounceChannel,
+		RemoteInitiated: true,
 	}
 	bobChanReservation, err := bob.InitChannelReservation(bobReq)
 	if err != nil {
@@ -737,7 +739,7 @@ func testCancelNonExistentReservation(miner *rpctest.Harness,
 	// Create our own reservation, give it some ID.
 	res, err := lnwallet.NewChannelReservation(
 		10000, 10000, feePerKw, alice, 22, 10, &testHdSeed,
-		lnwire.FFAnnounceChannel,
+		lnwire.FFAnnounceChannel, false, false,
 	)
 	if err != nil {
 		t.Fatalf("unable to create res: %v", err)
@@ -2889,7 +2891,7 @@ func runTests(t *testing.T, walletDriver *lnwallet.WalletDriver,
 		// node's chainstate to initial level, cleanly
 		// wipe buckets
 		if err := clearWalletStates(alice, bob); err !=
-			nil && err != bbolt.ErrBucketNotFound {
+			nil && err != kvdb.ErrBucketNotFound {
 			t.Fatalf("unable to wipe wallet state: %v", err)
 		}
 	}
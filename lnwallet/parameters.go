@@ -3,11 +3,45 @@ package lnwallet
 import (
 	"github.com/litecoinfinance/btcutil"
 	"github.com/litecoinfinance/btcwallet/wallet/txrules"
+	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/input"
 )
 
+const (
+	// AnchorSize is the value of each of the anchor outputs on the
+	// commitment transactions of channels using anchor outputs. Both
+	// anchors are paid for by the initiator of the channel.
+	AnchorSize = btcutil.Amount(330)
+
+	// MinDustLimit is the smallest dust limit we accept for the
+	// commitment transactions of a channel, as defined by BOLT #2. Below
+	// it, outputs of the commitment transactions wouldn't be relayed by
+	// the network.
+	MinDustLimit = btcutil.Amount(354)
+)
+
 // DefaultDustLimit is used to calculate the dust HTLC amount which will be
 // send to other node during funding process.
 func DefaultDustLimit() btcutil.Amount {
 	return txrules.GetDustThreshold(input.P2WSHSize, txrules.DefaultRelayFeePerKb)
 }
+
+// CommitWeight returns the base weight of the commitment transactions of a
+// channel of the given type, without any HTLC outputs.
+func CommitWeight(chanType channeldb.ChannelType) int64 {
+	if chanType.HasAnchors() {
+		return input.AnchorCommitWeight
+	}
+
+	return input.CommitWeight
+}
+
+// anchorsValue returns the total value of the anchor outputs the initiator of
+// a channel of the given type pays for on each commitment transaction.
+func anchorsValue(chanType channeldb.ChannelType) btcutil.Amount {
+	if chanType.HasAnchors() {
+		return 2 * AnchorSize
+	}
+
+	return 0
+}
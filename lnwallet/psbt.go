@@ -0,0 +1,65 @@
+package lnwallet
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/litecoinfinance/btcd/wire"
+)
+
+// psbtSeparator terminates each of the maps of a PSBT.
+const psbtSeparator = 0x00
+
+var (
+	// psbtMagic is the magic prefixing each serialized PSBT, as defined in
+	// BIP-174.
+	psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+	// psbtUnsignedTxKey is the key of the global unsigned transaction of
+	// a PSBT, which consists of its key type only.
+	psbtUnsignedTxKey = []byte{0x00}
+)
+
+// ErrPsbtTxSigned is returned when a PSBT is requested for a transaction
+// that already carries signature scripts or witnesses.
+var ErrPsbtTxSigned = errors.New("transaction of PSBT must be unsigned")
+
+// NewUnsignedPsbt serializes the given unsigned transaction as a BIP-174
+// partially signed transaction, which carries no information on its inputs
+// and outputs apart from the transaction itself. External wallets use it as
+// a template, which they're able to fund, sign and finalize.
+func NewUnsignedPsbt(tx *wire.MsgTx) ([]byte, error) {
+	for _, txIn := range tx.TxIn {
+		if len(txIn.SignatureScript) != 0 || len(txIn.Witness) != 0 {
+			return nil, ErrPsbtTxSigned
+		}
+	}
+
+	var rawTx bytes.Buffer
+	if err := tx.SerializeNoWitness(&rawTx); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.Write(psbtMagic)
+
+	// The global map only holds the unsigned transaction, keyed by its
+	// single byte key type.
+	if err := wire.WriteVarBytes(&b, 0, psbtUnsignedTxKey); err != nil {
+		return nil, err
+	}
+	if err := wire.WriteVarBytes(&b, 0, rawTx.Bytes()); err != nil {
+		return nil, err
+	}
+	b.WriteByte(psbtSeparator)
+
+	// Each of the inputs and outputs is followed by an empty map.
+	for range tx.TxIn {
+		b.WriteByte(psbtSeparator)
+	}
+	for range tx.TxOut {
+		b.WriteByte(psbtSeparator)
+	}
+
+	return b.Bytes(), nil
+}
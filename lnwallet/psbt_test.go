@@ -0,0 +1,43 @@
+package lnwallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/litecoinfinance/btcd/wire"
+)
+
+// TestNewUnsignedPsbt asserts that a PSBT funding template is serialized as
+// defined in BIP-174, and that signed transactions are rejected.
+func TestNewUnsignedPsbt(t *testing.T) {
+	t.Parallel()
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxOut(wire.NewTxOut(1000000, bytes.Repeat([]byte{0x01}, 34)))
+
+	var rawTx bytes.Buffer
+	if err := tx.SerializeNoWitness(&rawTx); err != nil {
+		t.Fatalf("unable to serialize tx: %v", err)
+	}
+
+	// The PSBT consists of the magic, the global map holding the unsigned
+	// transaction, and a single empty map for the output.
+	var expected []byte
+	expected = append(expected, 0x70, 0x73, 0x62, 0x74, 0xff)
+	expected = append(expected, 0x01, 0x00, byte(rawTx.Len()))
+	expected = append(expected, rawTx.Bytes()...)
+	expected = append(expected, 0x00, 0x00)
+
+	packet, err := NewUnsignedPsbt(tx)
+	if err != nil {
+		t.Fatalf("unable to create psbt: %v", err)
+	}
+	if !bytes.Equal(packet, expected) {
+		t.Fatalf("expected psbt %x, got %x", expected, packet)
+	}
+
+	tx.AddTxIn(&wire.TxIn{Witness: wire.TxWitness{[]byte{0x01}}})
+	if _, err := NewUnsignedPsbt(tx); err != ErrPsbtTxSigned {
+		t.Fatalf("expected ErrPsbtTxSigned, got %v", err)
+	}
+}
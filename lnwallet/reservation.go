@@ -27,6 +27,11 @@ type ChannelContribution struct {
 	// Inputs to the funding transaction.
 	Inputs []*wire.TxIn
 
+	// PrevOutputs are the outputs spent by each of the Inputs, in the same
+	// order. Within a dual funder workflow, these allow the counterparty
+	// to verify the inputs, and the signatures spending them.
+	PrevOutputs []*wire.TxOut
+
 	// ChangeOutputs are the Outputs to be used in the case that the total
 	// value of the funding inputs is greater than the total potential
 	// channel capacity.
@@ -126,11 +131,16 @@ type ChannelReservation struct {
 // NewChannelReservation creates a new channel reservation. This function is
 // used only internally by lnwallet. In order to concurrent safety, the
 // creation of all channel reservations should be carried out via the
-// lnwallet.InitChannelReservation interface.
+// lnwallet.InitChannelReservation interface. If anchors is set, the
+// commitment transactions of the channel will carry anchor outputs, the value
+// of which is paid for by the initiator along with the commitment fee. The
+// remoteInitiated flag indicates that we're the responder to a dual funder
+// workflow, in which both parties contribute funds to the channel.
 func NewChannelReservation(capacity, fundingAmt btcutil.Amount,
 	commitFeePerKw SatPerKWeight, wallet *LightningWallet,
 	id uint64, pushMSat lnwire.MilliSatoshi, chainHash *chainhash.Hash,
-	flags lnwire.FundingFlag) (*ChannelReservation, error) {
+	flags lnwire.FundingFlag, anchors,
+	remoteInitiated bool) (*ChannelReservation, error) {
 
 	var (
 		ourBalance   lnwire.MilliSatoshi
@@ -138,10 +148,19 @@ func NewChannelReservation(capacity, fundingAmt btcutil.Amount,
 		initiator    bool
 	)
 
-	commitFee := commitFeePerKw.FeeForWeight(input.CommitWeight)
+	// The commitment format determines both the weight of the commitment
+	// and whether the initiator has to pay for its anchors.
+	var formatType channeldb.ChannelType
+	if anchors {
+		formatType = channeldb.AnchorOutputsBit
+	}
+
+	commitFee := commitFeePerKw.FeeForWeight(CommitWeight(formatType))
 	fundingMSat := lnwire.NewMSatFromSatoshis(fundingAmt)
 	capacityMSat := lnwire.NewMSatFromSatoshis(capacity)
-	feeMSat := lnwire.NewMSatFromSatoshis(commitFee)
+	feeMSat := lnwire.NewMSatFromSatoshis(
+		commitFee + anchorsValue(formatType),
+	)
 
 	// If we're the responder to a single-funder reservation, then we have
 	// no initial balance in the channel unless the remote party is pushing
@@ -160,31 +179,40 @@ func NewChannelReservation(capacity, fundingAmt btcutil.Amount,
 			)
 		}
 	} else {
-		// TODO(roasbeef): need to rework fee structure in general and
-		// also when we "unlock" dual funder within the daemon
-
-		if capacity == fundingAmt {
-			// If we're initiating a single funder workflow, then
-			// we pay all the initial fees within the commitment
-			// transaction. We also deduct our balance by the
-			// amount pushed as part of the initial state.
+		switch {
+		// If we're initiating a single funder workflow, then we pay
+		// all the initial fees within the commitment transaction. We
+		// also deduct our balance by the amount pushed as part of the
+		// initial state.
+		case capacity == fundingAmt:
 			ourBalance = capacityMSat - feeMSat - pushMSat
 			theirBalance = pushMSat
-		} else {
-			// Otherwise, this is a dual funder workflow where both
-			// slides split the amount funded and the commitment
-			// fee.
-			ourBalance = fundingMSat - (feeMSat / 2)
-			theirBalance = capacityMSat - fundingMSat - (feeMSat / 2) + pushMSat
+
+		// Otherwise, this is a dual funder workflow where both sides
+		// contribute funds. Just as in a single funder workflow, the
+		// initiator pays the commitment fee, so both sides agree on
+		// the party responsible for it throughout the channel's
+		// lifetime.
+		case !remoteInitiated:
+			ourBalance = fundingMSat - feeMSat
+			theirBalance = capacityMSat - fundingMSat
+
+		default:
+			ourBalance = fundingMSat
+			theirBalance = capacityMSat - fundingMSat - feeMSat
 		}
 
-		initiator = true
+		initiator = !remoteInitiated
 
-		// If we, the initiator don't have enough funds to actually pay
+		// If the initiator doesn't have enough funds to actually pay
 		// the fees, then we'll exit with an error.
-		if int64(ourBalance) < 0 {
+		initiatorBalance := ourBalance
+		if !initiator {
+			initiatorBalance = theirBalance
+		}
+		if int64(initiatorBalance) < 0 {
 			return nil, ErrFunderBalanceDust(
-				int64(commitFee), int64(ourBalance),
+				int64(commitFee), int64(initiatorBalance),
 				int64(2*DefaultDustLimit()),
 			)
 		}
@@ -213,11 +241,10 @@ func NewChannelReservation(capacity, fundingAmt btcutil.Amount,
 	if ourBalance == 0 || theirBalance == 0 || pushMSat != 0 {
 		chanType = channeldb.SingleFunder
 	} else {
-		// Otherwise, this is a dual funder channel, and no side is
-		// technically the "initiator"
-		initiator = false
+		// Otherwise, this is a dual funder channel.
 		chanType = channeldb.DualFunder
 	}
+	chanType |= formatType
 
 	return &ChannelReservation{
 		ourContribution: &ChannelContribution{
@@ -286,6 +313,12 @@ func (r *ChannelReservation) CommitConstraints(c *channeldb.ChannelConstraints)
 		return ErrCsvDelayTooLarge(c.CsvDelay, maxDelay)
 	}
 
+	// Fail if the dust limit is so small that the commitment transactions
+	// could end up non-standard.
+	if c.DustLimit < MinDustLimit {
+		return ErrDustLimitTooSmall(c.DustLimit, MinDustLimit)
+	}
+
 	// The dust limit should always be greater or equal to the channel
 	// reserve. The reservation request should be denied if otherwise.
 	if c.DustLimit > c.ChanReserve {
@@ -377,6 +410,27 @@ func (r *ChannelReservation) ProcessContribution(theirContribution *ChannelContr
 	return <-errChan
 }
 
+// ProcessExternalContribution is the counterpart of ProcessContribution for
+// channels funded outside of the wallet. Rather than building the funding
+// transaction, the passed, fully signed funding transaction is used, which
+// must pay the channel's capacity to the multi-sig output. As the wallet
+// contributes no inputs, only the signature to the counterparty's version of
+// the commitment transaction is generated.
+func (r *ChannelReservation) ProcessExternalContribution(
+	theirContribution *ChannelContribution, fundingTx *wire.MsgTx) error {
+
+	errChan := make(chan error, 1)
+
+	r.wallet.msgChan <- &addContributionMsg{
+		pendingFundingID: r.reservationID,
+		contribution:     theirContribution,
+		fundingTx:        fundingTx,
+		err:              errChan,
+	}
+
+	return <-errChan
+}
+
 // ProcessSingleContribution verifies, and records the initiator's contribution
 // to this pending single funder channel. Internally, no further action is
 // taken other than recording the initiator's contribution to the single funder
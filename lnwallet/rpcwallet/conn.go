@@ -0,0 +1,53 @@
+package rpcwallet
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/litecoinfinance/lnd/macaroons"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	macaroon "gopkg.in/macaroon.v2"
+)
+
+// Connect establishes a connection to the RPC server of the remote signer at
+// rpcHost, authenticating it through its TLS certificate, and ourselves through
+// the macaroon at macaroonPath.
+func Connect(rpcHost, tlsCertPath, macaroonPath string,
+	timeout time.Duration) (*grpc.ClientConn, error) {
+
+	creds, err := credentials.NewClientTLSFromFile(tlsCertPath, "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read remote signer TLS "+
+			"certificate: %v", err)
+	}
+
+	macBytes, err := ioutil.ReadFile(macaroonPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read remote signer "+
+			"macaroon: %v", err)
+	}
+	mac := &macaroon.Macaroon{}
+	if err := mac.UnmarshalBinary(macBytes); err != nil {
+		return nil, fmt.Errorf("unable to decode remote signer "+
+			"macaroon: %v", err)
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithPerRPCCredentials(
+			macaroons.NewMacaroonCredential(mac),
+		),
+		grpc.WithBlock(),
+		grpc.WithTimeout(timeout),
+	}
+
+	conn, err := grpc.Dial(rpcHost, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to remote signer "+
+			"at %v: %v", rpcHost, err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,277 @@
+package rpcwallet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/input"
+	"github.com/litecoinfinance/lnd/keychain"
+	"github.com/litecoinfinance/lnd/lnrpc/signrpc"
+	"github.com/litecoinfinance/lnd/lnrpc/walletrpc"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+var (
+	// ErrRemoteSigningPrivKey is returned when the private key of a key
+	// held by the remote signer is requested.
+	ErrRemoteSigningPrivKey = errors.New("private key not available in " +
+		"remote signing mode")
+)
+
+// RPCKeyRing is an implementation of the keychain.SecretKeyRing and
+// input.Signer interfaces which delegates the keys of our channels to a remote
+// signer. The remote signer is an lnd instance exposing its signrpc and
+// walletrpc sub-servers, which derives the keys of channels and signs with
+// them, such that they never touch this machine.
+//
+// The keys of a few families are kept local, as we need to hold their private
+// keys regardless: the node key is required to process onion packets, the
+// revocation roots to hand out revocation secrets, while the static backup and
+// tower session keys aren't guarding any funds. Inputs spending outputs of the
+// on-chain wallet are signed by the local wallet as well.
+type RPCKeyRing struct {
+	// localKeyRing derives the keys of the families kept local.
+	localKeyRing keychain.SecretKeyRing
+
+	// localSigner signs the inputs spending outputs of the local wallet.
+	localSigner input.Signer
+
+	signerClient signrpc.SignerClient
+	walletClient walletrpc.WalletKitClient
+
+	// timeout is the timeout of each request to the remote signer.
+	timeout time.Duration
+}
+
+// A compile time check to ensure that RPCKeyRing implements the SecretKeyRing
+// and Signer interfaces.
+var _ keychain.SecretKeyRing = (*RPCKeyRing)(nil)
+var _ input.Signer = (*RPCKeyRing)(nil)
+
+// NewRPCKeyRing creates a new RPCKeyRing delegating the keys of our channels to
+// the remote signer behind the passed connection.
+func NewRPCKeyRing(localKeyRing keychain.SecretKeyRing,
+	localSigner input.Signer, conn *grpc.ClientConn,
+	timeout time.Duration) *RPCKeyRing {
+
+	return &RPCKeyRing{
+		localKeyRing: localKeyRing,
+		localSigner:  localSigner,
+		signerClient: signrpc.NewSignerClient(conn),
+		walletClient: walletrpc.NewWalletKitClient(conn),
+		timeout:      timeout,
+	}
+}
+
+// isLocalFamily returns true if the keys of the given family are held by the
+// local wallet rather than the remote signer.
+func isLocalFamily(keyFam keychain.KeyFamily) bool {
+	switch keyFam {
+	case keychain.KeyFamilyRevocationRoot, keychain.KeyFamilyNodeKey,
+		keychain.KeyFamilyStaticBackup, keychain.KeyFamilyTowerSession:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
+// DeriveNextKey attempts to derive the *next* key within the key family
+// (account in BIP43) specified.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (r *RPCKeyRing) DeriveNextKey(
+	keyFam keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	if isLocalFamily(keyFam) {
+		return r.localKeyRing.DeriveNextKey(keyFam)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	resp, err := r.walletClient.DeriveNextKey(ctx, &walletrpc.KeyReq{
+		KeyFamily: int32(keyFam),
+	})
+	if err != nil {
+		return keychain.KeyDescriptor{}, fmt.Errorf("remote signer "+
+			"unable to derive next key of family %d: %v", keyFam,
+			err)
+	}
+
+	return unmarshalKeyDesc(resp, keyFam)
+}
+
+// DeriveKey attempts to derive an arbitrary key specified by the passed
+// KeyLocator.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (r *RPCKeyRing) DeriveKey(
+	keyLoc keychain.KeyLocator) (keychain.KeyDescriptor, error) {
+
+	if isLocalFamily(keyLoc.Family) {
+		return r.localKeyRing.DeriveKey(keyLoc)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	resp, err := r.walletClient.DeriveKey(ctx, &signrpc.KeyLocator{
+		KeyFamily: int32(keyLoc.Family),
+		KeyIndex:  int32(keyLoc.Index),
+	})
+	if err != nil {
+		return keychain.KeyDescriptor{}, fmt.Errorf("remote signer "+
+			"unable to derive key %d/%d: %v", keyLoc.Family,
+			keyLoc.Index, err)
+	}
+
+	return unmarshalKeyDesc(resp, keyLoc.Family)
+}
+
+// DerivePrivKey attempts to derive the private key that corresponds to the
+// passed key descriptor. Only the private keys of the families kept local are
+// available.
+//
+// NOTE: This is part of the keychain.SecretKeyRing interface.
+func (r *RPCKeyRing) DerivePrivKey(
+	keyDesc keychain.KeyDescriptor) (*btcec.PrivateKey, error) {
+
+	if !isLocalFamily(keyDesc.Family) {
+		return nil, ErrRemoteSigningPrivKey
+	}
+
+	return r.localKeyRing.DerivePrivKey(keyDesc)
+}
+
+// ScalarMult performs a scalar multiplication (ECDH-like operation) between
+// the target key descriptor and remote public key. Only keys of the families
+// kept local are supported.
+//
+// NOTE: This is part of the keychain.SecretKeyRing interface.
+func (r *RPCKeyRing) ScalarMult(keyDesc keychain.KeyDescriptor,
+	pubKey *btcec.PublicKey) ([]byte, error) {
+
+	if !isLocalFamily(keyDesc.Family) {
+		return nil, ErrRemoteSigningPrivKey
+	}
+
+	return r.localKeyRing.ScalarMult(keyDesc, pubKey)
+}
+
+// SignOutputRaw generates a signature for the passed transaction according to
+// the data within the passed SignDescriptor, by requesting it from the remote
+// signer.
+//
+// NOTE: This is part of the input.Signer interface.
+func (r *RPCKeyRing) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) ([]byte, error) {
+
+	var rawTx bytes.Buffer
+	if err := tx.Serialize(&rawTx); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	resp, err := r.signerClient.SignOutputRaw(ctx, &signrpc.SignReq{
+		RawTxBytes: rawTx.Bytes(),
+		SignDescs: []*signrpc.SignDescriptor{
+			marshalSignDesc(signDesc),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("remote signer unable to sign input "+
+			"%d of %v: %v", signDesc.InputIndex, tx.TxHash(), err)
+	}
+	if len(resp.RawSigs) != 1 {
+		return nil, fmt.Errorf("remote signer returned %d signatures, "+
+			"expected 1", len(resp.RawSigs))
+	}
+
+	return resp.RawSigs[0], nil
+}
+
+// ComputeInputScript generates a complete InputIndex for the passed
+// transaction with the signature as defined within the passed SignDescriptor.
+// As this is only used for inputs spending outputs of the on-chain wallet, the
+// local wallet signs them.
+//
+// NOTE: This is part of the input.Signer interface.
+func (r *RPCKeyRing) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) (*input.Script, error) {
+
+	return r.localSigner.ComputeInputScript(tx, signDesc)
+}
+
+// unmarshalKeyDesc parses a key descriptor returned by the remote signer,
+// ensuring it belongs to the expected family.
+func unmarshalKeyDesc(rpcDesc *signrpc.KeyDescriptor,
+	keyFam keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	rpcLoc := rpcDesc.GetKeyLoc()
+	if rpcLoc == nil {
+		return keychain.KeyDescriptor{}, fmt.Errorf("remote signer " +
+			"returned key without locator")
+	}
+	if keychain.KeyFamily(rpcLoc.KeyFamily) != keyFam {
+		return keychain.KeyDescriptor{}, fmt.Errorf("remote signer "+
+			"returned key of family %d, expected %d",
+			rpcLoc.KeyFamily, keyFam)
+	}
+
+	pubKey, err := btcec.ParsePubKey(rpcDesc.RawKeyBytes, btcec.S256())
+	if err != nil {
+		return keychain.KeyDescriptor{}, fmt.Errorf("remote signer "+
+			"returned invalid key: %v", err)
+	}
+
+	return keychain.KeyDescriptor{
+		KeyLocator: keychain.KeyLocator{
+			Family: keyFam,
+			Index:  uint32(rpcLoc.KeyIndex),
+		},
+		PubKey: pubKey,
+	}, nil
+}
+
+// marshalSignDesc converts the sign descriptor into its RPC counterpart. Both
+// the locator and the public key of the signing key are passed, such that the
+// remote signer can derive it directly rather than scanning its family.
+func marshalSignDesc(signDesc *input.SignDescriptor) *signrpc.SignDescriptor {
+	signKey := signDesc.KeyDesc
+	keyDesc := &signrpc.KeyDescriptor{
+		KeyLoc: &signrpc.KeyLocator{
+			KeyFamily: int32(signKey.Family),
+			KeyIndex:  int32(signKey.Index),
+		},
+	}
+	if signKey.PubKey != nil {
+		keyDesc.RawKeyBytes = signKey.PubKey.SerializeCompressed()
+	}
+
+	var doubleTweak []byte
+	if signDesc.DoubleTweak != nil {
+		doubleTweak = signDesc.DoubleTweak.Serialize()
+	}
+
+	return &signrpc.SignDescriptor{
+		KeyDesc:       keyDesc,
+		SingleTweak:   signDesc.SingleTweak,
+		DoubleTweak:   doubleTweak,
+		WitnessScript: signDesc.WitnessScript,
+		Output: &signrpc.TxOut{
+			Value:    signDesc.Output.Value,
+			PkScript: signDesc.Output.PkScript,
+		},
+		Sighash:    uint32(signDesc.HashType),
+		InputIndex: int32(signDesc.InputIndex),
+	}
+}
@@ -0,0 +1,191 @@
+package rpcwallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/txscript"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/input"
+	"github.com/litecoinfinance/lnd/keychain"
+	"github.com/litecoinfinance/lnd/lnrpc/signrpc"
+	"github.com/litecoinfinance/lnd/lnrpc/walletrpc"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// mockSignerClient records the requests to sign and returns a fixed
+// signature.
+type mockSignerClient struct {
+	signrpc.SignerClient
+
+	req *signrpc.SignReq
+}
+
+func (m *mockSignerClient) SignOutputRaw(ctx context.Context,
+	in *signrpc.SignReq, opts ...grpc.CallOption) (*signrpc.SignResp,
+	error) {
+
+	m.req = in
+	return &signrpc.SignResp{RawSigs: [][]byte{{0x01}}}, nil
+}
+
+// mockWalletClient serves the same key at index 3 of every family.
+type mockWalletClient struct {
+	walletrpc.WalletKitClient
+
+	pubKey *btcec.PublicKey
+}
+
+func (m *mockWalletClient) DeriveNextKey(ctx context.Context,
+	in *walletrpc.KeyReq, opts ...grpc.CallOption) (*signrpc.KeyDescriptor,
+	error) {
+
+	return &signrpc.KeyDescriptor{
+		RawKeyBytes: m.pubKey.SerializeCompressed(),
+		KeyLoc: &signrpc.KeyLocator{
+			KeyFamily: in.KeyFamily,
+			KeyIndex:  3,
+		},
+	}, nil
+}
+
+// mockLocalKeyRing records the families it derived keys of.
+type mockLocalKeyRing struct {
+	keychain.SecretKeyRing
+
+	derived []keychain.KeyFamily
+}
+
+func (m *mockLocalKeyRing) DeriveNextKey(
+	keyFam keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	m.derived = append(m.derived, keyFam)
+	return keychain.KeyDescriptor{
+		KeyLocator: keychain.KeyLocator{Family: keyFam},
+	}, nil
+}
+
+func newTestKeyRing(t *testing.T) (*RPCKeyRing, *mockLocalKeyRing,
+	*mockSignerClient, *btcec.PrivateKey) {
+
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	localKeyRing := &mockLocalKeyRing{}
+	signerClient := &mockSignerClient{}
+	keyRing := &RPCKeyRing{
+		localKeyRing: localKeyRing,
+		signerClient: signerClient,
+		walletClient: &mockWalletClient{pubKey: privKey.PubKey()},
+	}
+
+	return keyRing, localKeyRing, signerClient, privKey
+}
+
+// TestRPCKeyRingFamilies asserts that the keys of channels are derived by the
+// remote signer, while the families kept local are derived by the local key
+// ring, and that only their private keys are available.
+func TestRPCKeyRingFamilies(t *testing.T) {
+	t.Parallel()
+
+	keyRing, localKeyRing, _, privKey := newTestKeyRing(t)
+
+	keyDesc, err := keyRing.DeriveNextKey(keychain.KeyFamilyMultiSig)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	if keyDesc.Family != keychain.KeyFamilyMultiSig || keyDesc.Index != 3 {
+		t.Fatalf("unexpected key locator: %v", keyDesc.KeyLocator)
+	}
+	if !keyDesc.PubKey.IsEqual(privKey.PubKey()) {
+		t.Fatalf("expected key of remote signer")
+	}
+	if len(localKeyRing.derived) != 0 {
+		t.Fatalf("expected key to be derived remotely")
+	}
+
+	_, err = keyRing.DeriveNextKey(keychain.KeyFamilyRevocationRoot)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	if len(localKeyRing.derived) != 1 ||
+		localKeyRing.derived[0] != keychain.KeyFamilyRevocationRoot {
+
+		t.Fatalf("expected revocation root to be derived locally")
+	}
+
+	_, err = keyRing.DerivePrivKey(keyDesc)
+	if err != ErrRemoteSigningPrivKey {
+		t.Fatalf("expected ErrRemoteSigningPrivKey, got %v", err)
+	}
+}
+
+// TestRPCKeyRingSignOutputRaw asserts that the sign descriptor is passed to
+// the remote signer along with both the locator and public key of the signing
+// key.
+func TestRPCKeyRingSignOutputRaw(t *testing.T) {
+	t.Parallel()
+
+	keyRing, _, signerClient, privKey := newTestKeyRing(t)
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{})
+	tx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x00}})
+
+	signDesc := &input.SignDescriptor{
+		KeyDesc: keychain.KeyDescriptor{
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamilyHtlcBase,
+				Index:  7,
+			},
+			PubKey: privKey.PubKey(),
+		},
+		DoubleTweak:   privKey,
+		WitnessScript: []byte{0x51},
+		Output:        &wire.TxOut{Value: 2000, PkScript: []byte{0x00}},
+		HashType:      txscript.SigHashAll,
+	}
+
+	sig, err := keyRing.SignOutputRaw(tx, signDesc)
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	if !bytes.Equal(sig, []byte{0x01}) {
+		t.Fatalf("expected signature of remote signer, got %x", sig)
+	}
+
+	req := signerClient.req
+	var rawTx bytes.Buffer
+	if err := tx.Serialize(&rawTx); err != nil {
+		t.Fatalf("unable to serialize tx: %v", err)
+	}
+	if !bytes.Equal(req.RawTxBytes, rawTx.Bytes()) {
+		t.Fatalf("transaction mismatch")
+	}
+
+	rpcDesc := req.SignDescs[0]
+	rpcLoc := rpcDesc.KeyDesc.KeyLoc
+	if rpcLoc.KeyFamily != int32(keychain.KeyFamilyHtlcBase) ||
+		rpcLoc.KeyIndex != 7 {
+
+		t.Fatalf("unexpected key locator: %v", rpcLoc)
+	}
+	if !bytes.Equal(
+		rpcDesc.KeyDesc.RawKeyBytes,
+		privKey.PubKey().SerializeCompressed(),
+	) {
+		t.Fatalf("public key mismatch")
+	}
+	if !bytes.Equal(rpcDesc.DoubleTweak, privKey.Serialize()) {
+		t.Fatalf("double tweak mismatch")
+	}
+	if rpcDesc.Output.Value != 2000 ||
+		rpcDesc.Sighash != uint32(txscript.SigHashAll) {
+
+		t.Fatalf("unexpected sign descriptor: %v", rpcDesc)
+	}
+}
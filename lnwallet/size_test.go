@@ -57,16 +57,22 @@ func TestTxWeightEstimator(t *testing.T) {
 		t.Fatalf("Failed to generate scriptPubKey: %v", err)
 	}
 
+	p2trScript := append(
+		[]byte{txscript.OP_1, txscript.OP_DATA_32}, make([]byte, 32)...,
+	)
+
 	testCases := []struct {
 		numP2PKHInputs       int
 		numP2WKHInputs       int
 		numP2WSHInputs       int
 		numNestedP2WKHInputs int
 		numNestedP2WSHInputs int
+		numTaprootInputs     int
 		numP2PKHOutputs      int
 		numP2WKHOutputs      int
 		numP2WSHOutputs      int
 		numP2SHOutputs       int
+		numP2TROutputs       int
 	}{
 		{
 			numP2PKHInputs:  1,
@@ -104,6 +110,16 @@ func TestTxWeightEstimator(t *testing.T) {
 			numNestedP2WSHInputs: 1,
 			numP2WKHOutputs:      1,
 		},
+		{
+			numTaprootInputs: 1,
+			numP2TROutputs:   1,
+		},
+		{
+			numP2WKHInputs:   1,
+			numTaprootInputs: 2,
+			numP2WKHOutputs:  1,
+			numP2TROutputs:   1,
+		},
 	}
 
 	for i, test := range testCases {
@@ -165,6 +181,13 @@ func TestTxWeightEstimator(t *testing.T) {
 
 			tx.AddTxIn(&wire.TxIn{SignatureScript: scriptSig, Witness: witness})
 		}
+		for j := 0; j < test.numTaprootInputs; j++ {
+			weightEstimate.AddTaprootKeySpendInput()
+
+			signature := make([]byte, 65)
+			witness := wire.TxWitness{signature}
+			tx.AddTxIn(&wire.TxIn{Witness: witness})
+		}
 		for j := 0; j < test.numP2PKHOutputs; j++ {
 			weightEstimate.AddP2PKHOutput()
 			tx.AddTxOut(&wire.TxOut{PkScript: p2pkhScript})
@@ -181,6 +204,10 @@ func TestTxWeightEstimator(t *testing.T) {
 			weightEstimate.AddP2SHOutput()
 			tx.AddTxOut(&wire.TxOut{PkScript: p2shScript})
 		}
+		for j := 0; j < test.numP2TROutputs; j++ {
+			weightEstimate.AddP2TROutput()
+			tx.AddTxOut(&wire.TxOut{PkScript: p2trScript})
+		}
 
 		expectedWeight := blockchain.GetTransactionWeight(btcutil.NewTx(tx))
 		if weightEstimate.Weight() != int(expectedWeight) {
@@ -0,0 +1,575 @@
+package lnwallet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/litecoinfinance/btcd/blockchain"
+	"github.com/litecoinfinance/btcd/txscript"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/btcutil/txsort"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/input"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+var (
+	// ErrChannelNotQuiescent is returned when attempting to splice a
+	// channel that has HTLCs, or updates that aren't yet committed to by
+	// both parties.
+	ErrChannelNotQuiescent = errors.New("channel has pending updates")
+
+	// ErrSpliceBelowReserve is returned when a splice would leave its
+	// initiator with a balance below its channel reserve.
+	ErrSpliceBelowReserve = errors.New("splice would dip balance below " +
+		"channel reserve")
+)
+
+// CanSplice returns an error if the channel can't be spliced in its current
+// state. A channel can only be spliced once it's open, and while it's
+// quiescent: neither commitment has any HTLCs, there's no commitment pending
+// the revocation of the remote party, and both commitments agree on the
+// balances of the channel. As both parties check the channel on their end,
+// neither has any outstanding updates once they agreed on a splice.
+func CanSplice(chanState *channeldb.OpenChannel) error {
+	switch {
+	case chanState.IsPending:
+		return errors.New("channel is still pending")
+
+	case chanState.ShortChanID().IsAlias():
+		return errors.New("channel funding transaction is unconfirmed")
+
+	case chanState.RemoteNextRevocation == nil:
+		return errors.New("channel isn't fully open")
+
+	case chanState.ChanStatus() != channeldb.ChanStatusDefault:
+		return fmt.Errorf("channel has status %v",
+			chanState.ChanStatus())
+	}
+
+	localCommit := chanState.LocalCommitment
+	remoteCommit := chanState.RemoteCommitment
+	switch {
+	case len(localCommit.Htlcs) != 0 || len(remoteCommit.Htlcs) != 0:
+		return ErrChannelNotQuiescent
+
+	case localCommit.LocalBalance != remoteCommit.LocalBalance,
+		localCommit.RemoteBalance != remoteCommit.RemoteBalance,
+		localCommit.FeePerKw != remoteCommit.FeePerKw:
+
+		return ErrChannelNotQuiescent
+	}
+
+	_, err := chanState.RemoteCommitChainTip()
+	switch err {
+	case channeldb.ErrNoPendingCommit:
+		return nil
+	case nil:
+		return ErrChannelNotQuiescent
+	default:
+		return err
+	}
+}
+
+// spliceFundingScript returns the witness script, and the output, of a
+// funding output of the channel holding the given capacity. The funding
+// outputs of a channel only differ in their value, as they're locked to the
+// same multi-sig keys.
+func spliceFundingScript(chanState *channeldb.OpenChannel,
+	capacity btcutil.Amount) ([]byte, *wire.TxOut, error) {
+
+	localKey := chanState.LocalChanCfg.MultiSigKey.PubKey
+	remoteKey := chanState.RemoteChanCfg.MultiSigKey.PubKey
+
+	return input.GenFundingPkScript(
+		localKey.SerializeCompressed(), remoteKey.SerializeCompressed(),
+		int64(capacity),
+	)
+}
+
+// spliceTxWeightEstimate returns the weight estimate of a splice transaction
+// spending the funding output of a channel, and paying to its new one,
+// without any other inputs or outputs.
+func spliceTxWeightEstimate() input.TxWeightEstimator {
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(input.WitnessSize)
+	weightEstimate.AddP2WSHOutput()
+
+	return weightEstimate
+}
+
+// FundSpliceIn crafts a splice transaction which adds amt to the capacity of
+// the channel, funded by coins of the wallet selected according to the given
+// strategy. The fee of the splice transaction is paid by the coins as well,
+// and any excess is returned to a change output, unless it would be dust. Each
+// input but the one spending the funding output is signed, and the coins stay
+// locked until the splice transaction is either published, or released by
+// ReleaseSpliceTx.
+func (l *LightningWallet) FundSpliceIn(chanState *channeldb.OpenChannel,
+	amt btcutil.Amount, feeRate SatPerKWeight, minConfs int32,
+	strategy CoinSelectionStrategy) (*wire.MsgTx, error) {
+
+	if amt <= 0 {
+		return nil, errors.New("splice amount must be positive")
+	}
+
+	_, fundingOutput, err := spliceFundingScript(
+		chanState, chanState.Capacity+amt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// We hold the coin select mutex while selecting and locking the coins
+	// of the splice, so they can't be used to fund a channel
+	// concurrently.
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	walletLog.Infof("Performing %v coin selection to splice %v into "+
+		"ChannelPoint(%v) using %v sat/kw as fee rate", strategy, amt,
+		chanState.FundingOutpoint, int64(feeRate))
+
+	coins, err := l.listSpendableCoins(minConfs)
+	if err != nil {
+		return nil, err
+	}
+
+	// As the current funding output pays for its share of the new one,
+	// the coins only need to pay for the spliced amount, along with the
+	// fee of the whole transaction.
+	selectedCoins, changeAmt, err := coinSelect(
+		feeRate, amt, spliceTxWeightEstimate(), strategy, coins,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	spliceTx := wire.NewMsgTx(1)
+	spliceTx.AddTxIn(wire.NewTxIn(&chanState.FundingOutpoint, nil, nil))
+	for _, coin := range selectedCoins {
+		spliceTx.AddTxIn(wire.NewTxIn(&coin.OutPoint, nil, nil))
+	}
+	spliceTx.AddTxOut(fundingOutput)
+
+	if err := l.addChangeOutput(spliceTx, changeAmt); err != nil {
+		return nil, err
+	}
+
+	txsort.InPlaceSort(spliceTx)
+
+	err = l.signWalletInputs(spliceTx, chanState.FundingOutpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, coin := range selectedCoins {
+		l.lockedOutPoints[coin.OutPoint] = struct{}{}
+		l.LockOutpoint(coin.OutPoint)
+	}
+
+	return spliceTx, nil
+}
+
+// ReleaseSpliceTx unlocks the coins spent by a splice transaction created by
+// FundSpliceIn, which won't be published.
+func (l *LightningWallet) ReleaseSpliceTx(spliceTx *wire.MsgTx) {
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	for _, txIn := range spliceTx.TxIn {
+		if _, ok := l.lockedOutPoints[txIn.PreviousOutPoint]; !ok {
+			continue
+		}
+
+		delete(l.lockedOutPoints, txIn.PreviousOutPoint)
+		l.UnlockOutpoint(txIn.PreviousOutPoint)
+	}
+}
+
+// CreateSpliceOut crafts a splice transaction which pays amt out of the
+// channel to the given script. The fee of the splice transaction is deducted
+// from the capacity of the channel as well.
+func CreateSpliceOut(chanState *channeldb.OpenChannel, amt btcutil.Amount,
+	pkScript []byte, feeRate SatPerKWeight) (*wire.MsgTx, error) {
+
+	payout := &wire.TxOut{
+		Value:    int64(amt),
+		PkScript: pkScript,
+	}
+	if amt < DefaultDustLimit() {
+		return nil, fmt.Errorf("splice amount of %v is dust", amt)
+	}
+
+	weightEstimate := spliceTxWeightEstimate()
+	weightEstimate.AddTxOutput(payout)
+	fee := feeRate.FeeForWeight(int64(weightEstimate.Weight()))
+
+	newCapacity := chanState.Capacity - amt - fee
+	if newCapacity <= 0 {
+		return nil, fmt.Errorf("splice amount of %v, along with the "+
+			"fee of %v, exceeds the capacity of the channel", amt,
+			fee)
+	}
+
+	_, fundingOutput, err := spliceFundingScript(chanState, newCapacity)
+	if err != nil {
+		return nil, err
+	}
+
+	spliceTx := wire.NewMsgTx(1)
+	spliceTx.AddTxIn(wire.NewTxIn(&chanState.FundingOutpoint, nil, nil))
+	spliceTx.AddTxOut(fundingOutput)
+	spliceTx.AddTxOut(payout)
+	txsort.InPlaceSort(spliceTx)
+
+	return spliceTx, nil
+}
+
+// Splice is a splice of a channel in progress. It validates the splice
+// transaction, and creates the commitment transactions spending its new
+// funding output, which are at the same heights, and have the same balances,
+// as the current commitments of the channel, except for the balance of the
+// initiator of the splice, which changes by the difference in capacity.
+type Splice struct {
+	chanState *channeldb.OpenChannel
+	signer    input.Signer
+
+	spliceTx     *wire.MsgTx
+	isInitiator  bool
+	fundingInput int
+
+	fundingOutpoint wire.OutPoint
+	capacity        btcutil.Amount
+	witnessScript   []byte
+	fundingOutput   *wire.TxOut
+
+	localCommit  channeldb.ChannelCommitment
+	remoteCommit channeldb.ChannelCommitment
+}
+
+// NewSplice validates the splice transaction of the channel, and creates the
+// commitment transactions spending its new funding output. isInitiator
+// indicates whether we initiated the splice, and are therefore the party whose
+// balance changes.
+func NewSplice(chanState *channeldb.OpenChannel, signer input.Signer,
+	spliceTx *wire.MsgTx, isInitiator bool) (*Splice, error) {
+
+	if err := CanSplice(chanState); err != nil {
+		return nil, err
+	}
+
+	err := blockchain.CheckTransactionSanity(btcutil.NewTx(spliceTx))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Splice{
+		chanState:    chanState,
+		signer:       signer,
+		spliceTx:     spliceTx,
+		isInitiator:  isInitiator,
+		fundingInput: -1,
+	}
+
+	// The splice transaction must spend the current funding output, which
+	// is only spent once, as the transaction is sane.
+	for i, txIn := range spliceTx.TxIn {
+		if txIn.PreviousOutPoint == chanState.FundingOutpoint {
+			s.fundingInput = i
+		}
+	}
+	if s.fundingInput == -1 {
+		return nil, errors.New("splice tx doesn't spend funding output")
+	}
+
+	// It must also pay to exactly one new funding output, which we'll
+	// recognize by its script.
+	_, fundingOutput, err := spliceFundingScript(
+		chanState, chanState.Capacity,
+	)
+	if err != nil {
+		return nil, err
+	}
+	numFundingOutputs := 0
+	for i, txOut := range spliceTx.TxOut {
+		if !bytes.Equal(txOut.PkScript, fundingOutput.PkScript) {
+			continue
+		}
+
+		numFundingOutputs++
+		s.fundingOutpoint = wire.OutPoint{
+			Hash:  spliceTx.TxHash(),
+			Index: uint32(i),
+		}
+		s.capacity = btcutil.Amount(txOut.Value)
+	}
+	if numFundingOutputs != 1 {
+		return nil, fmt.Errorf("splice tx must have exactly one "+
+			"funding output, found %v", numFundingOutputs)
+	}
+	if s.capacity == chanState.Capacity {
+		return nil, errors.New("splice doesn't change channel capacity")
+	}
+
+	s.witnessScript, s.fundingOutput, err = spliceFundingScript(
+		chanState, s.capacity,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The difference in capacity is credited to, or debited from, the
+	// initiator of the splice, who must keep its channel reserve.
+	delta := int64(s.capacity-chanState.Capacity) * 1000
+	ourBalance := int64(chanState.LocalCommitment.LocalBalance)
+	theirBalance := int64(chanState.LocalCommitment.RemoteBalance)
+	if isInitiator {
+		ourBalance += delta
+		reserve := chanState.LocalChanCfg.ChanReserve
+		if delta < 0 && ourBalance < int64(reserve)*1000 {
+			return nil, ErrSpliceBelowReserve
+		}
+	} else {
+		theirBalance += delta
+		reserve := chanState.RemoteChanCfg.ChanReserve
+		if delta < 0 && theirBalance < int64(reserve)*1000 {
+			return nil, ErrSpliceBelowReserve
+		}
+	}
+	if ourBalance < 0 || theirBalance < 0 {
+		return nil, ErrSpliceBelowReserve
+	}
+
+	s.localCommit = chanState.LocalCommitment
+	s.localCommit.LocalBalance = lnwire.MilliSatoshi(ourBalance)
+	s.localCommit.RemoteBalance = lnwire.MilliSatoshi(theirBalance)
+	s.localCommit.CommitSig = nil
+
+	s.remoteCommit = chanState.RemoteCommitment
+	s.remoteCommit.LocalBalance = lnwire.MilliSatoshi(ourBalance)
+	s.remoteCommit.RemoteBalance = lnwire.MilliSatoshi(theirBalance)
+	s.remoteCommit.CommitSig = nil
+
+	if err := s.createCommitments(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// createCommitments creates both commitment transactions spending the new
+// funding output, using the commitment points of the current commitments.
+func (s *Splice) createCommitments() error {
+	chanState := s.chanState
+
+	localSecret, err := chanState.RevocationProducer.AtIndex(
+		s.localCommit.CommitHeight,
+	)
+	if err != nil {
+		return err
+	}
+	localCommitPoint := input.ComputeCommitmentPoint(localSecret[:])
+
+	ourCommitTx, theirCommitTx, err := CreateCommitmentTxns(
+		s.localCommit.LocalBalance.ToSatoshis(),
+		s.localCommit.RemoteBalance.ToSatoshis(),
+		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+		localCommitPoint, chanState.RemoteCurrentRevocation,
+		*wire.NewTxIn(&s.fundingOutpoint, nil, nil), chanState.ChanType,
+	)
+	if err != nil {
+		return err
+	}
+
+	var obfuscator [StateHintSize]byte
+	if chanState.IsInitiator {
+		obfuscator = DeriveStateHintObfuscator(
+			chanState.LocalChanCfg.PaymentBasePoint.PubKey,
+			chanState.RemoteChanCfg.PaymentBasePoint.PubKey,
+		)
+	} else {
+		obfuscator = DeriveStateHintObfuscator(
+			chanState.RemoteChanCfg.PaymentBasePoint.PubKey,
+			chanState.LocalChanCfg.PaymentBasePoint.PubKey,
+		)
+	}
+	err = SetStateNumHint(
+		ourCommitTx, s.localCommit.CommitHeight, obfuscator,
+	)
+	if err != nil {
+		return err
+	}
+	err = SetStateNumHint(
+		theirCommitTx, s.remoteCommit.CommitHeight, obfuscator,
+	)
+	if err != nil {
+		return err
+	}
+
+	txsort.InPlaceSort(ourCommitTx)
+	txsort.InPlaceSort(theirCommitTx)
+	s.localCommit.CommitTx = ourCommitTx
+	s.remoteCommit.CommitTx = theirCommitTx
+
+	return nil
+}
+
+// SpliceTx returns the splice transaction.
+func (s *Splice) SpliceTx() *wire.MsgTx {
+	return s.spliceTx
+}
+
+// FundingOutpoint returns the new funding outpoint of the channel.
+func (s *Splice) FundingOutpoint() wire.OutPoint {
+	return s.fundingOutpoint
+}
+
+// Capacity returns the capacity of the channel once spliced.
+func (s *Splice) Capacity() btcutil.Amount {
+	return s.capacity
+}
+
+// SignRemoteCommit signs the commitment transaction of the remote party
+// spending the new funding output.
+func (s *Splice) SignRemoteCommit() (lnwire.Sig, error) {
+	signDesc := input.SignDescriptor{
+		WitnessScript: s.witnessScript,
+		KeyDesc:       s.chanState.LocalChanCfg.MultiSigKey,
+		Output:        s.fundingOutput,
+		HashType:      txscript.SigHashAll | txscript.SigHashForkID,
+		SigHashes:     txscript.NewTxSigHashes(s.remoteCommit.CommitTx),
+		InputIndex:    0,
+	}
+	sig, err := s.signer.SignOutputRaw(s.remoteCommit.CommitTx, &signDesc)
+	if err != nil {
+		return lnwire.Sig{}, err
+	}
+	s.remoteCommit.CommitSig = sig
+
+	return lnwire.NewSigFromRawSignature(sig)
+}
+
+// ReceiveCommitSig verifies the signature of the remote party for our
+// commitment transaction spending the new funding output.
+func (s *Splice) ReceiveCommitSig(commitSig lnwire.Sig) error {
+	ourCommitTx := s.localCommit.CommitTx
+	sigHash, err := txscript.CalcWitnessSigHash(
+		s.witnessScript, txscript.NewTxSigHashes(ourCommitTx),
+		txscript.SigHashAll|txscript.SigHashForkID, ourCommitTx, 0,
+		int64(s.capacity),
+	)
+	if err != nil {
+		return err
+	}
+
+	sig, err := commitSig.ToSignature()
+	if err != nil {
+		return err
+	}
+	theirKey := s.chanState.RemoteChanCfg.MultiSigKey.PubKey
+	if !sig.Verify(sigHash, theirKey) {
+		return errors.New("invalid splice commitment signature")
+	}
+	s.localCommit.CommitSig = commitSig.ToSignatureBytes()
+
+	return nil
+}
+
+// SignFundingInput signs the input of the splice transaction spending the
+// current funding output of the channel.
+func (s *Splice) SignFundingInput() ([]byte, error) {
+	chanState := s.chanState
+	witnessScript, fundingOutput, err := spliceFundingScript(
+		chanState, chanState.Capacity,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	signDesc := input.SignDescriptor{
+		WitnessScript: witnessScript,
+		KeyDesc:       chanState.LocalChanCfg.MultiSigKey,
+		Output:        fundingOutput,
+		HashType:      txscript.SigHashAll | txscript.SigHashForkID,
+		SigHashes:     txscript.NewTxSigHashes(s.spliceTx),
+		InputIndex:    s.fundingInput,
+	}
+
+	return s.signer.SignOutputRaw(s.spliceTx, &signDesc)
+}
+
+// ChannelSplice returns the splice to be persisted, once both commitment
+// signatures have been exchanged.
+func (s *Splice) ChannelSplice(fundingSig []byte,
+	broadcastHeight uint32) *channeldb.ChannelSplice {
+
+	return &channeldb.ChannelSplice{
+		SpliceTx:         s.spliceTx,
+		FundingOutpoint:  s.fundingOutpoint,
+		Capacity:         s.capacity,
+		LocalCommitment:  s.localCommit,
+		RemoteCommitment: s.remoteCommit,
+		IsInitiator:      s.isInitiator,
+		FundingSig:       fundingSig,
+		BroadcastHeight:  broadcastHeight,
+	}
+}
+
+// CompleteSpliceTx adds the witness spending the current funding output of
+// the channel to its splice transaction, using our signature of the splice and
+// the one of the remote party, which is verified along the way. The completed
+// splice transaction is returned.
+func CompleteSpliceTx(chanState *channeldb.OpenChannel,
+	splice *channeldb.ChannelSplice, theirSig lnwire.Sig) (*wire.MsgTx,
+	error) {
+
+	witnessScript, fundingOutput, err := spliceFundingScript(
+		chanState, chanState.Capacity,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	spliceTx := splice.SpliceTx.Copy()
+	fundingInput := -1
+	for i, txIn := range spliceTx.TxIn {
+		if txIn.PreviousOutPoint == chanState.FundingOutpoint {
+			fundingInput = i
+		}
+	}
+	if fundingInput == -1 {
+		return nil, errors.New("splice tx doesn't spend funding output")
+	}
+
+	sigHashType := byte(txscript.SigHashAll | txscript.SigHashForkID)
+	ourSig := append(
+		append([]byte(nil), splice.FundingSig...), sigHashType,
+	)
+	theirSigBytes := append(theirSig.ToSignatureBytes(), sigHashType)
+
+	ourKey := chanState.LocalChanCfg.MultiSigKey.PubKey
+	theirKey := chanState.RemoteChanCfg.MultiSigKey.PubKey
+	spliceTx.TxIn[fundingInput].Witness = input.SpendMultiSig(
+		witnessScript, ourKey.SerializeCompressed(), ourSig,
+		theirKey.SerializeCompressed(), theirSigBytes,
+	)
+
+	// Before handing out the splice transaction, we'll ensure the
+	// signature of the remote party is valid.
+	vm, err := txscript.NewEngine(
+		fundingOutput.PkScript, spliceTx, fundingInput,
+		txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(spliceTx), fundingOutput.Value,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := vm.Execute(); err != nil {
+		return nil, fmt.Errorf("invalid splice signature: %v", err)
+	}
+
+	return spliceTx, nil
+}
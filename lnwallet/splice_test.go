@@ -0,0 +1,155 @@
+package lnwallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestSpliceOut asserts that both parties of a channel arrive at the same
+// commitment transactions spending the new funding output of a splice, and
+// that the splice transaction can be completed with their signatures.
+func TestSpliceOut(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels()
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	aliceState := aliceChannel.channelState
+	bobState := bobChannel.channelState
+
+	pkScript := append([]byte{0x00, 0x14}, bytes.Repeat([]byte{1}, 20)...)
+	const spliceAmt = btcutil.SatoshiPerBitcoin
+	spliceTx, err := CreateSpliceOut(aliceState, spliceAmt, pkScript, 6000)
+	if err != nil {
+		t.Fatalf("unable to create splice tx: %v", err)
+	}
+
+	aliceSplice, err := NewSplice(
+		aliceState, aliceChannel.Signer, spliceTx, true,
+	)
+	if err != nil {
+		t.Fatalf("unable to create alice's splice: %v", err)
+	}
+	bobSplice, err := NewSplice(
+		bobState, bobChannel.Signer, spliceTx, false,
+	)
+	if err != nil {
+		t.Fatalf("unable to create bob's splice: %v", err)
+	}
+
+	if aliceSplice.FundingOutpoint() != bobSplice.FundingOutpoint() {
+		t.Fatalf("funding outpoint mismatch: %v vs %v",
+			aliceSplice.FundingOutpoint(),
+			bobSplice.FundingOutpoint())
+	}
+
+	// Alice's balance must have been debited by the splice amount, along
+	// with the fee of the splice transaction, while Bob's is unchanged.
+	debit := aliceState.Capacity - aliceSplice.Capacity()
+	if debit <= spliceAmt {
+		t.Fatalf("expected splice fee to be deducted, got debit of %v",
+			debit)
+	}
+	expectedBalance := aliceState.LocalCommitment.LocalBalance -
+		lnwire.NewMSatFromSatoshis(debit)
+	if aliceSplice.localCommit.LocalBalance != expectedBalance {
+		t.Fatalf("expected alice's balance to be %v, got %v",
+			expectedBalance, aliceSplice.localCommit.LocalBalance)
+	}
+	if bobSplice.localCommit.LocalBalance !=
+		bobState.LocalCommitment.LocalBalance {
+
+		t.Fatalf("expected bob's balance to be unchanged")
+	}
+
+	// Each party must have created the commitment transaction of the
+	// other.
+	aliceCommit := aliceSplice.localCommit.CommitTx.TxHash()
+	bobCommit := bobSplice.localCommit.CommitTx.TxHash()
+	if aliceCommit != bobSplice.remoteCommit.CommitTx.TxHash() {
+		t.Fatalf("alice's commitment mismatch")
+	}
+	if bobCommit != aliceSplice.remoteCommit.CommitTx.TxHash() {
+		t.Fatalf("bob's commitment mismatch")
+	}
+
+	bobCommitSig, err := bobSplice.SignRemoteCommit()
+	if err != nil {
+		t.Fatalf("unable to sign alice's commitment: %v", err)
+	}
+	if err := aliceSplice.ReceiveCommitSig(bobCommitSig); err != nil {
+		t.Fatalf("alice unable to verify commitment sig: %v", err)
+	}
+	aliceCommitSig, err := aliceSplice.SignRemoteCommit()
+	if err != nil {
+		t.Fatalf("unable to sign bob's commitment: %v", err)
+	}
+	if err := bobSplice.ReceiveCommitSig(aliceCommitSig); err != nil {
+		t.Fatalf("bob unable to verify commitment sig: %v", err)
+	}
+
+	// A signature for the wrong commitment must be rejected.
+	if err := aliceSplice.ReceiveCommitSig(aliceCommitSig); err == nil {
+		t.Fatalf("expected invalid commitment sig to be rejected")
+	}
+
+	aliceFundingSig, err := aliceSplice.SignFundingInput()
+	if err != nil {
+		t.Fatalf("unable to sign funding input: %v", err)
+	}
+	bobFundingSig, err := bobSplice.SignFundingInput()
+	if err != nil {
+		t.Fatalf("unable to sign funding input: %v", err)
+	}
+	bobSig, err := lnwire.NewSigFromRawSignature(bobFundingSig)
+	if err != nil {
+		t.Fatalf("unable to parse funding sig: %v", err)
+	}
+
+	_, err = CompleteSpliceTx(
+		aliceState, aliceSplice.ChannelSplice(aliceFundingSig, 0),
+		bobSig,
+	)
+	if err != nil {
+		t.Fatalf("unable to complete splice tx: %v", err)
+	}
+}
+
+// TestSpliceBelowReserve asserts that a splice debiting more than the balance
+// of its initiator is rejected by both parties.
+func TestSpliceBelowReserve(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels()
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Alice holds half of the capacity of the channel, so splicing out
+	// more than that must fail.
+	aliceState := aliceChannel.channelState
+	pkScript := append([]byte{0x00, 0x14}, bytes.Repeat([]byte{1}, 20)...)
+	spliceAmt := aliceState.Capacity/2 + 1
+	spliceTx, err := CreateSpliceOut(aliceState, spliceAmt, pkScript, 6000)
+	if err != nil {
+		t.Fatalf("unable to create splice tx: %v", err)
+	}
+
+	_, err = NewSplice(aliceState, aliceChannel.Signer, spliceTx, true)
+	if err != ErrSpliceBelowReserve {
+		t.Fatalf("expected ErrSpliceBelowReserve, got %v", err)
+	}
+	_, err = NewSplice(
+		bobChannel.channelState, bobChannel.Signer, spliceTx, false,
+	)
+	if err != ErrSpliceBelowReserve {
+		t.Fatalf("expected ErrSpliceBelowReserve, got %v", err)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
-	"math"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -98,6 +97,33 @@ type InitFundingReserveMsg struct {
 	// output selected to fund the channel should satisfy.
 	MinConfs int32
 
+	// Anchors indicates whether the commitment transactions of the channel
+	// should carry anchor outputs, as negotiated with the remote peer.
+	Anchors bool
+
+	// RemoteInitiated indicates that the remote peer initiated the dual
+	// funder workflow this reservation is for, and will therefore pay the
+	// commitment fee.
+	RemoteInitiated bool
+
+	// ExternalFunding indicates that the funding transaction is funded and
+	// signed outside of the wallet, so no coins are selected to fund the
+	// FundingAmount.
+	ExternalFunding bool
+
+	// NoFundingTx indicates that the funding transaction isn't stored
+	// with the channel, as it's published by the caller on the channel's
+	// behalf.
+	NoFundingTx bool
+
+	// CoinSelectionStrategy is the strategy used to select the coins
+	// funding our contribution to the channel.
+	CoinSelectionStrategy CoinSelectionStrategy
+
+	// DustLimit is the dust limit of our commitment transactions. If
+	// zero, the dust limit of the default constraints is used.
+	DustLimit btcutil.Amount
+
 	// err is a channel in which all errors will be sent across. Will be
 	// nil if this initial set is successful.
 	//
@@ -136,6 +162,10 @@ type addContributionMsg struct {
 	// TODO(roasbeef): Should also carry SPV proofs in we're in SPV mode
 	contribution *ChannelContribution
 
+	// fundingTx, if set, is the fully signed funding transaction, which
+	// was assembled outside of the wallet.
+	fundingTx *wire.MsgTx
+
 	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
 	err chan error
 }
@@ -271,6 +301,11 @@ type LightningWallet struct {
 	// the currently locked outpoints.
 	lockedOutPoints map[wire.OutPoint]struct{}
 
+	// leasedOutPoints is the set of outpoints locked on behalf of an
+	// external caller, which are kept apart from those locked by
+	// reservations, so they aren't unlocked along with them.
+	leasedOutPoints map[wire.OutPoint]struct{}
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -291,6 +326,7 @@ func NewLightningWallet(Cfg Config) (*LightningWallet, error) {
 		nextFundingID:    0,
 		fundingLimbo:     make(map[uint64]*ChannelReservation),
 		lockedOutPoints:  make(map[wire.OutPoint]struct{}),
+		leasedOutPoints:  make(map[wire.OutPoint]struct{}),
 		quit:             make(chan struct{}),
 	}, nil
 }
@@ -453,6 +489,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 	reservation, err := NewChannelReservation(
 		req.Capacity, req.FundingAmount, req.CommitFeePerKw, l, id,
 		req.PushMSat, l.Cfg.NetParams.GenesisHash, req.Flags,
+		req.Anchors, req.RemoteInitiated,
 	)
 	if err != nil {
 		req.err <- err
@@ -467,15 +504,22 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 	reservation.nodeAddr = req.NodeAddr
 	reservation.partialState.IdentityPub = req.NodeID
 
-	// If we're on the receiving end of a single funder channel then we
-	// don't need to perform any coin selection. Otherwise, attempt to
-	// obtain enough coins to meet the required funding amount.
-	if req.FundingAmount != 0 {
+	// If the funding transaction is published on behalf of the channel,
+	// it mustn't be stored with it, as we'd rebroadcast it otherwise.
+	if req.NoFundingTx {
+		reservation.partialState.ChanType |= channeldb.NoFundingTxBit
+	}
+
+	// If we're on the receiving end of a single funder channel, or the
+	// channel is funded externally, then we don't need to perform any coin
+	// selection. Otherwise, attempt to obtain enough coins to meet the
+	// required funding amount.
+	if req.FundingAmount != 0 && !req.ExternalFunding {
 		// Coin selection is done on the basis of sat/kw, so we'll use
 		// the fee rate passed in to perform coin selection.
 		err := l.selectCoinsAndChange(
 			req.FundingFeePerKw, req.FundingAmount, req.MinConfs,
-			reservation.ourContribution,
+			req.CoinSelectionStrategy, reservation.ourContribution,
 		)
 		if err != nil {
 			req.err <- err
@@ -569,6 +613,9 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 
 	reservation.partialState.RevocationProducer = producer
 	reservation.ourContribution.ChannelConstraints = l.Cfg.DefaultConstraints
+	if req.DustLimit != 0 {
+		reservation.ourContribution.DustLimit = req.DustLimit
+	}
 
 	// TODO(roasbeef): turn above into: initContribution()
 
@@ -626,20 +673,23 @@ func (l *LightningWallet) handleFundingCancelRequest(req *fundingReserveCancelMs
 // commitment transaction for both parties. This function is used during the
 // initial funding workflow as both sides must generate a signature for the
 // remote party's commitment transaction, and verify the signature for their
-// version of the commitment transaction.
+// version of the commitment transaction. The format of the commitment
+// transactions is determined by the given channel type.
 func CreateCommitmentTxns(localBalance, remoteBalance btcutil.Amount,
 	ourChanCfg, theirChanCfg *channeldb.ChannelConfig,
 	localCommitPoint, remoteCommitPoint *btcec.PublicKey,
-	fundingTxIn wire.TxIn) (*wire.MsgTx, *wire.MsgTx, error) {
+	fundingTxIn wire.TxIn, chanType channeldb.ChannelType) (*wire.MsgTx,
+	*wire.MsgTx, error) {
 
 	localCommitmentKeys := deriveCommitmentKeys(localCommitPoint, true,
 		ourChanCfg, theirChanCfg)
 	remoteCommitmentKeys := deriveCommitmentKeys(remoteCommitPoint, false,
 		ourChanCfg, theirChanCfg)
 
-	ourCommitTx, err := CreateCommitTx(fundingTxIn, localCommitmentKeys,
-		uint32(ourChanCfg.CsvDelay), localBalance, remoteBalance,
-		ourChanCfg.DustLimit)
+	builder := NewCommitmentBuilder(chanType, ourChanCfg, theirChanCfg)
+
+	ourCommitTx, err := builder.CreateCommitTx(fundingTxIn,
+		localCommitmentKeys, true, localBalance, remoteBalance, 0)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -649,9 +699,8 @@ func CreateCommitmentTxns(localBalance, remoteBalance btcutil.Amount,
 		return nil, nil, err
 	}
 
-	theirCommitTx, err := CreateCommitTx(fundingTxIn, remoteCommitmentKeys,
-		uint32(theirChanCfg.CsvDelay), remoteBalance, localBalance,
-		theirChanCfg.DustLimit)
+	theirCommitTx, err := builder.CreateCommitTx(fundingTxIn,
+		remoteCommitmentKeys, false, remoteBalance, localBalance, 0)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -683,35 +732,25 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	pendingReservation.Lock()
 	defer pendingReservation.Unlock()
 
-	// Create a blank, fresh transaction. Soon to be a complete funding
-	// transaction which will allow opening a lightning channel.
-	pendingReservation.fundingTx = wire.NewMsgTx(1)
-	fundingTx := pendingReservation.fundingTx
+	// Before signing anything that commits to the funding transaction,
+	// we'll make sure each of the inputs contributed by the remote party
+	// is actually spendable, and pays what the party claims it does.
+	err := l.verifyFundingInputs(req.contribution)
+	if err != nil {
+		req.err <- err
+		return
+	}
 
 	// Some temporary variables to cut down on the resolution verbosity.
 	pendingReservation.theirContribution = req.contribution
 	theirContribution := req.contribution
 	ourContribution := pendingReservation.ourContribution
 
-	// Add all multi-party inputs and outputs to the transaction.
-	for _, ourInput := range ourContribution.Inputs {
-		fundingTx.AddTxIn(ourInput)
-	}
-	for _, theirInput := range theirContribution.Inputs {
-		fundingTx.AddTxIn(theirInput)
-	}
-	for _, ourChangeOutput := range ourContribution.ChangeOutputs {
-		fundingTx.AddTxOut(ourChangeOutput)
-	}
-	for _, theirChangeOutput := range theirContribution.ChangeOutputs {
-		fundingTx.AddTxOut(theirChangeOutput)
-	}
-
 	ourKey := pendingReservation.ourContribution.MultiSigKey
 	theirKey := theirContribution.MultiSigKey
 
-	// Finally, add the 2-of-2 multi-sig output which will set up the lightning
-	// channel.
+	// Generate the 2-of-2 multi-sig output which will set up the
+	// lightning channel.
 	channelCapacity := int64(pendingReservation.partialState.Capacity)
 	witnessScript, multiSigOut, err := input.GenFundingPkScript(
 		ourKey.PubKey.SerializeCompressed(),
@@ -722,53 +761,32 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 		return
 	}
 
-	// Sort the transaction. Since both side agree to a canonical ordering,
-	// by sorting we no longer need to send the entire transaction. Only
-	// signatures will be exchanged.
-	fundingTx.AddTxOut(multiSigOut)
-	txsort.InPlaceSort(pendingReservation.fundingTx)
-
-	// Next, sign all inputs that are ours, collecting the signatures in
-	// order of the inputs.
-	pendingReservation.ourFundingInputScripts = make([]*input.Script, 0,
-		len(ourContribution.Inputs))
-	signDesc := input.SignDescriptor{
-		HashType:  txscript.SigHashAll | txscript.SigHashForkID,
-		SigHashes: txscript.NewTxSigHashes(fundingTx),
-	}
-	for i, txIn := range fundingTx.TxIn {
-		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
-		if err == ErrNotMine {
-			continue
-		} else if err != nil {
-			req.err <- err
-			return
-		}
-
-		signDesc.Output = info
-		signDesc.InputIndex = i
-
-		inputScript, err := l.Cfg.Signer.ComputeInputScript(
-			fundingTx, &signDesc,
-		)
+	// If the funding transaction was assembled outside of the wallet, it
+	// merely has to pay to the multi-sig output. Otherwise, we'll
+	// assemble it from the contributions of both parties.
+	if req.fundingTx != nil {
+		pendingReservation.fundingTx = req.fundingTx
+	} else {
+		err := l.assembleFundingTx(pendingReservation, multiSigOut)
 		if err != nil {
 			req.err <- err
 			return
 		}
-
-		txIn.SignatureScript = inputScript.SigScript
-		txIn.Witness = inputScript.Witness
-		pendingReservation.ourFundingInputScripts = append(
-			pendingReservation.ourFundingInputScripts,
-			inputScript,
-		)
 	}
+	fundingTx := pendingReservation.fundingTx
 
 	// Locate the index of the multi-sig outpoint in order to record it
 	// since the outputs are canonically sorted. If this is a single funder
 	// workflow, then we'll also need to send this to the remote node.
 	fundingTxID := fundingTx.TxHash()
-	_, multiSigIndex := input.FindScriptOutputIndex(fundingTx, multiSigOut.PkScript)
+	found, multiSigIndex := input.FindScriptOutputIndex(
+		fundingTx, multiSigOut.PkScript,
+	)
+	if !found || fundingTx.TxOut[multiSigIndex].Value != channelCapacity {
+		req.err <- fmt.Errorf("funding tx doesn't pay %v to the "+
+			"multi-sig output", btcutil.Amount(channelCapacity))
+		return
+	}
 	fundingOutpoint := wire.NewOutPoint(&fundingTxID, multiSigIndex)
 	pendingReservation.partialState.FundingOutpoint = *fundingOutpoint
 
@@ -804,6 +822,7 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 		theirContribution.ChannelConfig,
 		ourContribution.FirstCommitmentPoint,
 		theirContribution.FirstCommitmentPoint, fundingTxIn,
+		pendingReservation.partialState.ChanType,
 	)
 	if err != nil {
 		req.err <- err
@@ -814,7 +833,7 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	// obfuscator then use it to encode the current state number within
 	// both commitment transactions.
 	var stateObfuscator [StateHintSize]byte
-	if chanState.ChanType == channeldb.SingleFunder {
+	if chanState.ChanType.IsSingleFunder() {
 		stateObfuscator = DeriveStateHintObfuscator(
 			ourContribution.PaymentBasePoint.PubKey,
 			theirContribution.PaymentBasePoint.PubKey,
@@ -859,7 +878,7 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 
 	// Generate a signature for their version of the initial commitment
 	// transaction.
-	signDesc = input.SignDescriptor{
+	signDesc := input.SignDescriptor{
 		WitnessScript: witnessScript,
 		KeyDesc:       ourKey,
 		Output:        multiSigOut,
@@ -877,6 +896,130 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	req.err <- nil
 }
 
+// assembleFundingTx assembles the funding transaction of the reservation from
+// the inputs and change outputs contributed by both parties, along with the
+// passed multi-sig output, then signs all inputs that are ours.
+func (l *LightningWallet) assembleFundingTx(res *ChannelReservation,
+	multiSigOut *wire.TxOut) error {
+
+	// Create a blank, fresh transaction. Soon to be a complete funding
+	// transaction which will allow opening a lightning channel.
+	res.fundingTx = wire.NewMsgTx(1)
+	fundingTx := res.fundingTx
+
+	ourContribution := res.ourContribution
+	theirContribution := res.theirContribution
+
+	// Add all multi-party inputs and outputs to the transaction.
+	for _, ourInput := range ourContribution.Inputs {
+		fundingTx.AddTxIn(ourInput)
+	}
+	for _, theirInput := range theirContribution.Inputs {
+		fundingTx.AddTxIn(theirInput)
+	}
+	for _, ourChangeOutput := range ourContribution.ChangeOutputs {
+		fundingTx.AddTxOut(ourChangeOutput)
+	}
+	for _, theirChangeOutput := range theirContribution.ChangeOutputs {
+		fundingTx.AddTxOut(theirChangeOutput)
+	}
+
+	// Finally, add the 2-of-2 multi-sig output. Since both side agree to
+	// a canonical ordering, by sorting we no longer need to send the
+	// entire transaction. Only signatures will be exchanged.
+	fundingTx.AddTxOut(multiSigOut)
+	txsort.InPlaceSort(fundingTx)
+
+	// The inputs of both parties must be sufficient to pay for the
+	// outputs of the funding transaction, otherwise it could never
+	// confirm.
+	var totalIn, totalOut int64
+	for _, prevOut := range ourContribution.PrevOutputs {
+		totalIn += prevOut.Value
+	}
+	for _, prevOut := range theirContribution.PrevOutputs {
+		totalIn += prevOut.Value
+	}
+	for _, txOut := range fundingTx.TxOut {
+		totalOut += txOut.Value
+	}
+	if totalIn < totalOut {
+		return fmt.Errorf("funding tx inputs of %v don't cover its "+
+			"outputs of %v", btcutil.Amount(totalIn),
+			btcutil.Amount(totalOut))
+	}
+
+	// Next, sign all inputs that are ours, collecting the signatures in
+	// order of the inputs.
+	res.ourFundingInputScripts = make([]*input.Script, 0,
+		len(ourContribution.Inputs))
+	signDesc := input.SignDescriptor{
+		HashType:  txscript.SigHashAll | txscript.SigHashForkID,
+		SigHashes: txscript.NewTxSigHashes(fundingTx),
+	}
+	for i, txIn := range fundingTx.TxIn {
+		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err == ErrNotMine {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		signDesc.Output = info
+		signDesc.InputIndex = i
+
+		inputScript, err := l.Cfg.Signer.ComputeInputScript(
+			fundingTx, &signDesc,
+		)
+		if err != nil {
+			return err
+		}
+
+		txIn.SignatureScript = inputScript.SigScript
+		txIn.Witness = inputScript.Witness
+		res.ourFundingInputScripts = append(
+			res.ourFundingInputScripts, inputScript,
+		)
+	}
+
+	return nil
+}
+
+// verifyFundingInputs ensures that each of the inputs the remote party
+// contributes to the funding transaction spends an output that's still part of
+// the UTXO set, and that the output matches the value and script the party
+// claims it has. We'll sign the funding transaction, and commitments spending
+// from it, based on these claims, so they can't be taken at face value.
+func (l *LightningWallet) verifyFundingInputs(c *ChannelContribution) error {
+	if len(c.PrevOutputs) != len(c.Inputs) {
+		return fmt.Errorf("expected %v outputs spent by funding "+
+			"inputs, got %v", len(c.Inputs), len(c.PrevOutputs))
+	}
+
+	for i, txIn := range c.Inputs {
+		prevOut := c.PrevOutputs[i]
+
+		// TODO(roasbeef): pass actual height-hint
+		utxo, err := l.Cfg.ChainIO.GetUtxo(
+			&txIn.PreviousOutPoint, prevOut.PkScript, 0,
+		)
+		if err != nil {
+			return fmt.Errorf("input %v to funding tx does not "+
+				"exist: %v", txIn.PreviousOutPoint, err)
+		}
+
+		if utxo.Value != prevOut.Value ||
+			!bytes.Equal(utxo.PkScript, prevOut.PkScript) {
+
+			return fmt.Errorf("input %v to funding tx doesn't "+
+				"match the output it spends",
+				txIn.PreviousOutPoint)
+		}
+	}
+
+	return nil
+}
+
 // handleSingleContribution is called as the second step to a single funder
 // workflow to which we are the responder. It simply saves the remote peer's
 // contribution to the channel, as solely the remote peer will contribute any
@@ -950,39 +1093,39 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	defer res.Unlock()
 
 	// Now we can complete the funding transaction by adding their
-	// signatures to their inputs.
-	res.theirFundingInputScripts = msg.theirFundingInputScripts
+	// signatures to their inputs. The outputs spent by their inputs have
+	// already been verified against the UTXO set once we received their
+	// contribution, so we'll look them up in order to verify the scripts.
+	//
+	// The responder to a dual funder workflow completes the reservation
+	// without the initiator's input scripts, as it's the initiator that
+	// broadcasts the funding transaction.
+	theirPrevOutputs := make(map[wire.OutPoint]*wire.TxOut)
+	for i, txIn := range res.theirContribution.Inputs {
+		theirPrevOutputs[txIn.PreviousOutPoint] =
+			res.theirContribution.PrevOutputs[i]
+	}
 	inputScripts := msg.theirFundingInputScripts
+	if len(inputScripts) != 0 &&
+		len(inputScripts) != len(theirPrevOutputs) {
+
+		msg.err <- fmt.Errorf("expected %v funding input scripts, "+
+			"got %v", len(theirPrevOutputs), len(inputScripts))
+		msg.completeChan <- nil
+		return
+	}
+	res.theirFundingInputScripts = inputScripts
+
 	fundingTx := res.fundingTx
 	sigIndex := 0
 	fundingHashCache := txscript.NewTxSigHashes(fundingTx)
 	for i, txin := range fundingTx.TxIn {
-		if len(inputScripts) != 0 && len(txin.Witness) == 0 {
+		output, ok := theirPrevOutputs[txin.PreviousOutPoint]
+		if ok && len(inputScripts) != 0 {
 			// Attach the input scripts so we can verify it below.
 			txin.Witness = inputScripts[sigIndex].Witness
 			txin.SignatureScript = inputScripts[sigIndex].SigScript
 
-			// Fetch the alleged previous output along with the
-			// pkscript referenced by this input.
-			//
-			// TODO(roasbeef): when dual funder pass actual
-			// height-hint
-			pkScript, err := input.WitnessScriptHash(
-				txin.Witness[len(txin.Witness)-1],
-			)
-			if err != nil {
-			}
-			output, err := l.Cfg.ChainIO.GetUtxo(
-				&txin.PreviousOutPoint,
-				pkScript, 0,
-			)
-			if output == nil {
-				msg.err <- fmt.Errorf("input to funding tx "+
-					"does not exist: %v", err)
-				msg.completeChan <- nil
-				return
-			}
-
 			// Ensure that the witness+sigScript combo is valid.
 			vm, err := txscript.NewEngine(output.PkScript,
 				fundingTx, i, txscript.StandardVerifyFlags, nil,
@@ -1127,7 +1270,7 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 		pendingReservation.theirContribution.ChannelConfig,
 		pendingReservation.ourContribution.FirstCommitmentPoint,
 		pendingReservation.theirContribution.FirstCommitmentPoint,
-		*fundingTxIn,
+		*fundingTxIn, pendingReservation.partialState.ChanType,
 	)
 	if err != nil {
 		req.err <- err
@@ -1270,7 +1413,7 @@ func (l *LightningWallet) WithCoinSelectLock(f func() error) error {
 // within the passed contribution's inputs. If necessary, a change address will
 // also be generated.
 func (l *LightningWallet) selectCoinsAndChange(feeRate SatPerKWeight,
-	amt btcutil.Amount, minConfs int32,
+	amt btcutil.Amount, minConfs int32, strategy CoinSelectionStrategy,
 	contribution *ChannelContribution) error {
 
 	// We hold the coin select mutex while querying for outputs, and
@@ -1279,12 +1422,12 @@ func (l *LightningWallet) selectCoinsAndChange(feeRate SatPerKWeight,
 	l.coinSelectMtx.Lock()
 	defer l.coinSelectMtx.Unlock()
 
-	walletLog.Infof("Performing funding tx coin selection using %v "+
-		"sat/kw as fee rate", int64(feeRate))
+	walletLog.Infof("Performing funding tx %v coin selection using %v "+
+		"sat/kw as fee rate", strategy, int64(feeRate))
 
 	// Find all unlocked unspent witness outputs that satisfy the minimum
 	// number of confirmations required.
-	coins, err := l.ListUnspentWitness(minConfs, math.MaxInt32)
+	coins, err := l.listSpendableCoins(minConfs)
 	if err != nil {
 		return err
 	}
@@ -1292,7 +1435,13 @@ func (l *LightningWallet) selectCoinsAndChange(feeRate SatPerKWeight,
 	// Perform coin selection over our available, unlocked unspent outputs
 	// in order to find enough coins to meet the funding amount
 	// requirements.
-	selectedCoins, changeAmt, err := coinSelect(feeRate, amt, coins)
+	// The channel funding multisig output is P2WSH.
+	var outputsEstimate input.TxWeightEstimator
+	outputsEstimate.AddP2WSHOutput()
+
+	selectedCoins, changeAmt, err := coinSelect(
+		feeRate, amt, outputsEstimate, strategy, coins,
+	)
 	if err != nil {
 		return err
 	}
@@ -1301,6 +1450,7 @@ func (l *LightningWallet) selectCoinsAndChange(feeRate SatPerKWeight,
 	// prevents concurrent funding requests from referring to and this
 	// double-spending the same set of coins.
 	contribution.Inputs = make([]*wire.TxIn, len(selectedCoins))
+	contribution.PrevOutputs = make([]*wire.TxOut, len(selectedCoins))
 	for i, coin := range selectedCoins {
 		outpoint := &coin.OutPoint
 		l.lockedOutPoints[*outpoint] = struct{}{}
@@ -1309,6 +1459,10 @@ func (l *LightningWallet) selectCoinsAndChange(feeRate SatPerKWeight,
 		// Empty sig script, we'll actually sign if this reservation is
 		// queued up to be completed (the other side accepts).
 		contribution.Inputs[i] = wire.NewTxIn(outpoint, nil, nil)
+		contribution.PrevOutputs[i] = &wire.TxOut{
+			Value:    int64(coin.Value),
+			PkScript: coin.PkScript,
+		}
 	}
 
 	// Record any change output(s) generated as a result of the coin
@@ -1388,11 +1542,26 @@ func selectInputs(amt btcutil.Amount, coins []*Utxo) (btcutil.Amount, []*Utxo, e
 
 // coinSelect attempts to select a sufficient amount of coins, including a
 // change output to fund amt satoshis, adhering to the specified fee rate. The
+// amount is paid to the outputs accounted for by the outputs weight estimate,
+// and the coins are considered in the order of the given strategy. The
 // specified fee rate should be expressed in sat/kw for coin selection to
 // function properly.
 func coinSelect(feeRate SatPerKWeight, amt btcutil.Amount,
+	outputsEstimate input.TxWeightEstimator, strategy CoinSelectionStrategy,
 	coins []*Utxo) ([]*Utxo, btcutil.Amount, error) {
 
+	// If we find a set of coins that doesn't need a change output, we'll
+	// use it. Otherwise, we'll select the largest coins first.
+	if strategy == CoinSelectionBranchAndBound {
+		selectedUtxos, ok := selectChangeless(
+			feeRate, amt, outputsEstimate, coins,
+		)
+		if ok {
+			return selectedUtxos, 0, nil
+		}
+	}
+	coins = orderCoins(strategy, coins)
+
 	amtNeeded := amt
 	for {
 		// First perform an initial round of coin selection to estimate
@@ -1402,23 +1571,14 @@ func coinSelect(feeRate SatPerKWeight, amt btcutil.Amount,
 			return nil, 0, err
 		}
 
-		var weightEstimate input.TxWeightEstimator
-
+		weightEstimate := outputsEstimate
 		for _, utxo := range selectedUtxos {
-			switch utxo.AddressType {
-			case WitnessPubKey:
-				weightEstimate.AddP2WKHInput()
-			case NestedWitnessPubKey:
-				weightEstimate.AddNestedP2WKHInput()
-			default:
-				return nil, 0, fmt.Errorf("Unsupported address type: %v",
-					utxo.AddressType)
+			err := addCoinInput(&weightEstimate, utxo)
+			if err != nil {
+				return nil, 0, err
 			}
 		}
 
-		// Channel funding multisig output is P2WSH.
-		weightEstimate.AddP2WSHOutput()
-
 		// Assume that change output is a P2WKH output.
 		//
 		// TODO: Handle wallets that generate non-witness change
@@ -0,0 +1,381 @@
+package lnwire
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+)
+
+// QueryOption is a bitfield that is sent within a QueryChannelRange message to
+// request additional information about each short channel ID included in the
+// ReplyChannelRange messages sent in response.
+type QueryOption uint64
+
+const (
+	// QueryOptionTimestamps requests that the timestamps of the latest
+	// channel updates of both directions of each channel are included in
+	// the reply.
+	QueryOptionTimestamps QueryOption = 1 << 0
+
+	// QueryOptionChecksums requests that the checksums of the latest
+	// channel updates of both directions of each channel are included in
+	// the reply.
+	QueryOptionChecksums QueryOption = 1 << 1
+)
+
+// Has returns true if all of the passed options are set.
+func (q QueryOption) Has(opt QueryOption) bool {
+	return q&opt == opt
+}
+
+// QueryFlag is a bitfield that is sent for each short channel ID within a
+// QueryShortChanIDs message, signalling which of the announcements of the
+// channel the sender is interested in.
+type QueryFlag uint64
+
+const (
+	// QueryFlagChanAnn requests the channel announcement of the channel.
+	QueryFlagChanAnn QueryFlag = 1 << 0
+
+	// QueryFlagChanUpdate1 requests the channel update of the first node
+	// of the channel.
+	QueryFlagChanUpdate1 QueryFlag = 1 << 1
+
+	// QueryFlagChanUpdate2 requests the channel update of the second node
+	// of the channel.
+	QueryFlagChanUpdate2 QueryFlag = 1 << 2
+
+	// QueryFlagNodeAnn1 requests the node announcement of the first node
+	// of the channel.
+	QueryFlagNodeAnn1 QueryFlag = 1 << 3
+
+	// QueryFlagNodeAnn2 requests the node announcement of the second node
+	// of the channel.
+	QueryFlagNodeAnn2 QueryFlag = 1 << 4
+
+	// QueryFlagAll requests all announcements related to the channel.
+	QueryFlagAll = QueryFlagChanAnn | QueryFlagChanUpdate1 |
+		QueryFlagChanUpdate2 | QueryFlagNodeAnn1 | QueryFlagNodeAnn2
+)
+
+// Has returns true if all of the passed flags are set.
+func (q QueryFlag) Has(flag QueryFlag) bool {
+	return q&flag == flag
+}
+
+// ChanUpdateTimestamps holds the timestamps of the latest channel updates of
+// both directions of a channel. A zero timestamp signals that no channel
+// update is known for the direction.
+type ChanUpdateTimestamps struct {
+	// Timestamp1 is the timestamp of the channel update of the first node
+	// of the channel.
+	Timestamp1 uint32
+
+	// Timestamp2 is the timestamp of the channel update of the second
+	// node of the channel.
+	Timestamp2 uint32
+}
+
+// ChanUpdateChecksums holds the checksums of the latest channel updates of
+// both directions of a channel, as computed by ChannelUpdateChecksum. A zero
+// checksum signals that no channel update is known for the direction.
+type ChanUpdateChecksums struct {
+	// Checksum1 is the checksum of the channel update of the first node of
+	// the channel.
+	Checksum1 uint32
+
+	// Checksum2 is the checksum of the channel update of the second node
+	// of the channel.
+	Checksum2 uint32
+}
+
+// checksumTable is the CRC32C table used to compute channel update checksums.
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ChannelUpdateChecksum computes the CRC32C checksum of the channel update,
+// excluding its signature and timestamp. Two channel updates with the same
+// checksum therefore only differ in their timestamp, allowing a node to skip
+// requesting a newer update that merely refreshes the timestamp of one it
+// already knows of.
+func ChannelUpdateChecksum(upd *ChannelUpdate) (uint32, error) {
+	data, err := upd.DataToSign()
+	if err != nil {
+		return 0, err
+	}
+
+	// The timestamp directly follows the chain hash and short channel ID,
+	// so we'll snip it out before computing the checksum.
+	const tsOffset = 32 + 8
+	checksumData := make([]byte, 0, len(data)-4)
+	checksumData = append(checksumData, data[:tsOffset]...)
+	checksumData = append(checksumData, data[tsOffset+4:]...)
+
+	return crc32.Checksum(checksumData, checksumTable), nil
+}
+
+const (
+	// queryOptionsType is the TLV type of the query options within a
+	// QueryChannelRange message.
+	queryOptionsType = 1
+
+	// timestampsType is the TLV type of the channel update timestamps
+	// within a ReplyChannelRange message.
+	timestampsType = 1
+
+	// checksumsType is the TLV type of the channel update checksums within
+	// a ReplyChannelRange message.
+	checksumsType = 3
+
+	// queryFlagsType is the TLV type of the per channel query flags within
+	// a QueryShortChanIDs message.
+	queryFlagsType = 1
+)
+
+// ErrNonCanonicalBigSize is returned when a BigSize integer isn't encoded
+// using the minimum number of bytes.
+var ErrNonCanonicalBigSize = errors.New("decoded BigSize is not canonical")
+
+// writeBigSize writes the integer to the passed io.Writer using the BigSize
+// variable length encoding.
+func writeBigSize(w io.Writer, val uint64) error {
+	var b []byte
+	switch {
+	case val < 0xfd:
+		b = []byte{uint8(val)}
+
+	case val <= 0xffff:
+		b = make([]byte, 3)
+		b[0] = 0xfd
+		binary.BigEndian.PutUint16(b[1:], uint16(val))
+
+	case val <= 0xffffffff:
+		b = make([]byte, 5)
+		b[0] = 0xfe
+		binary.BigEndian.PutUint32(b[1:], uint32(val))
+
+	default:
+		b = make([]byte, 9)
+		b[0] = 0xff
+		binary.BigEndian.PutUint64(b[1:], val)
+	}
+
+	_, err := w.Write(b)
+	return err
+}
+
+// readBigSize reads a BigSize encoded integer from the passed io.Reader. An
+// io.EOF error is only returned if no bytes could be read at all.
+func readBigSize(r io.Reader) (uint64, error) {
+	var discriminant [1]byte
+	if _, err := io.ReadFull(r, discriminant[:]); err != nil {
+		return 0, err
+	}
+
+	var (
+		b   []byte
+		min uint64
+	)
+	switch discriminant[0] {
+	case 0xfd:
+		b, min = make([]byte, 2), 0xfd
+	case 0xfe:
+		b, min = make([]byte, 4), 0x10000
+	case 0xff:
+		b, min = make([]byte, 8), 0x100000000
+	default:
+		return uint64(discriminant[0]), nil
+	}
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+
+	var val uint64
+	for _, v := range b {
+		val = val<<8 | uint64(v)
+	}
+	if val < min {
+		return 0, ErrNonCanonicalBigSize
+	}
+
+	return val, nil
+}
+
+// tlvRecord is a single type-length-value record of the extension stream that
+// may follow the regular fields of a message.
+type tlvRecord struct {
+	typ   uint64
+	value []byte
+}
+
+// writeTLVStream writes the passed records to the io.Writer. The records MUST
+// be ordered by strictly increasing type.
+func writeTLVStream(w io.Writer, records ...tlvRecord) error {
+	for _, record := range records {
+		if err := writeBigSize(w, record.typ); err != nil {
+			return err
+		}
+		err := writeBigSize(w, uint64(len(record.value)))
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(record.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTLVStream reads all records from the passed io.Reader until EOF, and
+// returns the values of the known types. Unknown odd types are ignored, while
+// an unknown even type results in an error as we're required to understand
+// it.
+func readTLVStream(r io.Reader, knownTypes ...uint64) (map[uint64][]byte,
+	error) {
+
+	known := make(map[uint64]struct{}, len(knownTypes))
+	for _, typ := range knownTypes {
+		known[typ] = struct{}{}
+	}
+
+	var (
+		records  = make(map[uint64][]byte)
+		lastType uint64
+		first    = true
+	)
+	for {
+		typ, err := readBigSize(r)
+		switch {
+		case err == io.EOF:
+			return records, nil
+		case err != nil:
+			return nil, err
+		}
+
+		if !first && typ <= lastType {
+			return nil, fmt.Errorf("tlv type %d doesn't follow "+
+				"type %d in increasing order", typ, lastType)
+		}
+		first = false
+		lastType = typ
+
+		length, err := readBigSize(r)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		if length > MaxMessagePayload {
+			return nil, fmt.Errorf("tlv record of type %d with "+
+				"length %d exceeds max payload", typ, length)
+		}
+
+		value := make([]byte, length)
+		if _, err := io.ReadFull(r, value); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		if _, ok := known[typ]; !ok {
+			if typ%2 == 0 {
+				return nil, fmt.Errorf("unknown required tlv "+
+					"type %d", typ)
+			}
+			continue
+		}
+
+		records[typ] = value
+	}
+}
+
+// encodeExtendedData prefixes the passed data with the encoding type,
+// compressing it if needed.
+func encodeExtendedData(encodingType ShortChanIDEncoding,
+	data []byte) ([]byte, error) {
+
+	var buf bytes.Buffer
+	if err := WriteElements(&buf, encodingType); err != nil {
+		return nil, err
+	}
+
+	switch encodingType {
+	case EncodingSortedPlain:
+		buf.Write(data)
+
+	case EncodingSortedZlib:
+		zlibWriter := zlib.NewWriter(&buf)
+		if _, err := zlibWriter.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zlibWriter.Close(); err != nil {
+			return nil, fmt.Errorf("unable to finalize "+
+				"compression: %v", err)
+		}
+
+	default:
+		return nil, ErrUnknownShortChanIDEncoding(encodingType)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeExtendedData decodes data prefixed by its encoding type, as written by
+// encodeExtendedData.
+func decodeExtendedData(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no encoding type specified")
+	}
+
+	encodingType := ShortChanIDEncoding(data[0])
+	switch encodingType {
+	case EncodingSortedPlain:
+		return data[1:], nil
+
+	case EncodingSortedZlib:
+		zlibDecodeMtx.Lock()
+		defer zlibDecodeMtx.Unlock()
+
+		decompressor, err := zlib.NewReader(bytes.NewReader(data[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("unable to create zlib "+
+				"reader: %v", err)
+		}
+
+		// As with the short channel IDs themselves, we'll limit the
+		// amount of memory we're willing to allocate.
+		return ioutil.ReadAll(&io.LimitedReader{
+			R: decompressor,
+			N: maxZlibBufSize,
+		})
+
+	default:
+		return nil, ErrUnknownShortChanIDEncoding(encodingType)
+	}
+}
+
+// verifySortedChanIDs returns an error if the short channel IDs aren't sorted
+// in strictly increasing order. As encoding the short channel IDs sorts them
+// in place, this must hold for any message carrying additional data for each
+// short channel ID, as the data would otherwise no longer line up.
+func verifySortedChanIDs(shortChanIDs []ShortChannelID) error {
+	for i := 1; i < len(shortChanIDs); i++ {
+		if shortChanIDs[i].ToUint64() <= shortChanIDs[i-1].ToUint64() {
+			return fmt.Errorf("short chan ids must be sorted when " +
+				"sending extended query data")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestBigSizeEncoding asserts that BigSize integers are encoded using the
+// minimum number of bytes, and that non-canonical encodings are rejected.
+func TestBigSizeEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		val     uint64
+		encoded []byte
+	}{
+		{0, []byte{0x00}},
+		{0xfc, []byte{0xfc}},
+		{0xfd, []byte{0xfd, 0x00, 0xfd}},
+		{0xffff, []byte{0xfd, 0xff, 0xff}},
+		{0x10000, []byte{0xfe, 0x00, 0x01, 0x00, 0x00}},
+		{0xffffffff, []byte{0xfe, 0xff, 0xff, 0xff, 0xff}},
+		{
+			0x100000000,
+			[]byte{0xff, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+				0x00, 0x00},
+		},
+	}
+	for _, test := range tests {
+		var b bytes.Buffer
+		if err := writeBigSize(&b, test.val); err != nil {
+			t.Fatalf("unable to encode %d: %v", test.val, err)
+		}
+		if !bytes.Equal(b.Bytes(), test.encoded) {
+			t.Fatalf("expected %d to be encoded as %x, got %x",
+				test.val, test.encoded, b.Bytes())
+		}
+
+		val, err := readBigSize(&b)
+		if err != nil {
+			t.Fatalf("unable to decode %x: %v", test.encoded, err)
+		}
+		if val != test.val {
+			t.Fatalf("expected %d, got %d", test.val, val)
+		}
+	}
+
+	_, err := readBigSize(bytes.NewReader([]byte{0xfd, 0x00, 0xfc}))
+	if err != ErrNonCanonicalBigSize {
+		t.Fatalf("expected %v, got %v", ErrNonCanonicalBigSize, err)
+	}
+}
+
+// TestExtendedQueriesEncodeDecode asserts that the extended gossip query
+// fields survive an encoding round trip for both encoding types.
+func TestExtendedQueriesEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	shortChanIDs := []ShortChannelID{
+		NewShortChanIDFromInt(1),
+		NewShortChanIDFromInt(2),
+		NewShortChanIDFromInt(3),
+	}
+
+	for _, encoding := range []ShortChanIDEncoding{
+		EncodingSortedPlain, EncodingSortedZlib,
+	} {
+		msgs := []Message{
+			&QueryChannelRange{
+				FirstBlockHeight: 100,
+				NumBlocks:        200,
+				QueryOptions: QueryOptionTimestamps |
+					QueryOptionChecksums,
+			},
+			&ReplyChannelRange{
+				QueryChannelRange: QueryChannelRange{
+					FirstBlockHeight: 100,
+					NumBlocks:        200,
+				},
+				Complete:     1,
+				EncodingType: encoding,
+				ShortChanIDs: shortChanIDs,
+				Timestamps: []ChanUpdateTimestamps{
+					{1, 2}, {0, 4}, {5, 0},
+				},
+				Checksums: []ChanUpdateChecksums{
+					{6, 7}, {0, 9}, {10, 0},
+				},
+			},
+			&QueryShortChanIDs{
+				EncodingType: encoding,
+				ShortChanIDs: shortChanIDs,
+				QueryFlags: []QueryFlag{
+					QueryFlagAll, QueryFlagChanUpdate1,
+					1 << 20,
+				},
+			},
+		}
+
+		for _, msg := range msgs {
+			var b bytes.Buffer
+			if _, err := WriteMessage(&b, msg, 0); err != nil {
+				t.Fatalf("unable to encode %T: %v", msg, err)
+			}
+
+			newMsg, err := ReadMessage(&b, 0)
+			if err != nil {
+				t.Fatalf("unable to decode %T: %v", msg, err)
+			}
+
+			if !reflect.DeepEqual(msg, newMsg) {
+				t.Fatalf("message mismatch: expected %v, got %v",
+					spew.Sdump(msg), spew.Sdump(newMsg))
+			}
+		}
+	}
+}
+
+// TestExtendedQueriesUnsorted asserts that we refuse to encode extended query
+// data alongside unsorted short channel IDs, as the data would no longer line
+// up with the IDs once they're sorted.
+func TestExtendedQueriesUnsorted(t *testing.T) {
+	t.Parallel()
+
+	query := &QueryShortChanIDs{
+		ShortChanIDs: []ShortChannelID{
+			NewShortChanIDFromInt(2),
+			NewShortChanIDFromInt(1),
+		},
+		QueryFlags: []QueryFlag{QueryFlagAll, QueryFlagChanAnn},
+	}
+
+	var b bytes.Buffer
+	if err := query.Encode(&b, 0); err == nil {
+		t.Fatalf("expected encoding of unsorted query to fail")
+	}
+}
+
+// TestChannelUpdateChecksum asserts that the checksum of a channel update
+// doesn't depend on its timestamp and signature, but does cover the rest of
+// the update.
+func TestChannelUpdateChecksum(t *testing.T) {
+	t.Parallel()
+
+	upd := &ChannelUpdate{
+		ShortChannelID: NewShortChanIDFromInt(1),
+		Timestamp:      100,
+		TimeLockDelta:  144,
+		BaseFee:        1000,
+		FeeRate:        1,
+	}
+	checksum, err := ChannelUpdateChecksum(upd)
+	if err != nil {
+		t.Fatalf("unable to compute checksum: %v", err)
+	}
+
+	refreshed := *upd
+	refreshed.Timestamp = 200
+	refreshed.Signature = Sig{0x01}
+	refreshedChecksum, err := ChannelUpdateChecksum(&refreshed)
+	if err != nil {
+		t.Fatalf("unable to compute checksum: %v", err)
+	}
+	if refreshedChecksum != checksum {
+		t.Fatalf("checksum changed by timestamp or signature")
+	}
+
+	changed := *upd
+	changed.FeeRate = 2
+	changedChecksum, err := ChannelUpdateChecksum(&changed)
+	if err != nil {
+		t.Fatalf("unable to compute checksum: %v", err)
+	}
+	if changedChecksum == checksum {
+		t.Fatalf("checksum not changed by fee rate")
+	}
+}
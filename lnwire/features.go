@@ -46,6 +46,42 @@ const (
 	// efficient network view reconciliation.
 	GossipQueriesOptional FeatureBit = 7
 
+	// GossipQueriesExRequired is a feature bit that indicates that the
+	// receiving peer MUST know of the extended gossip queries, which allow
+	// nodes to request the timestamps and checksums of channel updates
+	// when querying channel ranges, and to only query the announcements
+	// of a channel they're interested in.
+	GossipQueriesExRequired FeatureBit = 10
+
+	// GossipQueriesExOptional is an optional feature bit that signals that
+	// the setting peer knows of the extended gossip queries.
+	GossipQueriesExOptional FeatureBit = 11
+
+	// AnchorsRequired is a feature bit that indicates that the receiving
+	// peer MUST know of the anchor output commitment format, in which
+	// each party has an anchor output on the commitment transaction it
+	// can spend to bump the transaction's fee using CPFP.
+	//
+	// NOTE: This bit lies within the experimental range, as the format
+	// still derives the to-remote key of each commitment from the
+	// per-commitment point.
+	AnchorsRequired FeatureBit = 1336
+
+	// AnchorsOptional is an optional feature bit that signals that the
+	// sending peer knows of the anchor output commitment format, and
+	// is willing to use it for new channels.
+	AnchorsOptional FeatureBit = 1337
+
+	// DualFundingRequired is a feature bit that indicates that the
+	// receiving peer MUST know of the dual funding protocol, in which both
+	// parties contribute inputs to the funding transaction of a channel.
+	DualFundingRequired FeatureBit = 1338
+
+	// DualFundingOptional is an optional feature bit that signals that the
+	// sending peer knows of the dual funding protocol, and is willing to
+	// consider inbound requests to contribute funds to a channel.
+	DualFundingOptional FeatureBit = 1339
+
 	// maxAllowedSize is a maximum allowed size of feature vector.
 	//
 	// NOTE: Within the protocol, the maximum allowed message size is 65535
@@ -70,6 +106,12 @@ var LocalFeatures = map[FeatureBit]string{
 	InitialRoutingSync:      "initial-routing-sync",
 	GossipQueriesRequired:   "gossip-queries",
 	GossipQueriesOptional:   "gossip-queries",
+	GossipQueriesExRequired: "gossip-queries-ex",
+	GossipQueriesExOptional: "gossip-queries-ex",
+	AnchorsRequired:         "anchor-commitments",
+	AnchorsOptional:         "anchor-commitments",
+	DualFundingRequired:     "dual-funding",
+	DualFundingOptional:     "dual-funding",
 }
 
 // GlobalFeatures is a mapping of known global feature bits to a descriptive
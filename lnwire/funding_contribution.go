@@ -0,0 +1,161 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+)
+
+// FundingInput is an input a party contributes to the funding transaction of
+// a dual funded channel, along with the output it spends. The output allows
+// the other party to verify the input against its view of the UTXO set before
+// signing anything that depends on the funding transaction.
+type FundingInput struct {
+	// OutPoint is the output spent by the input.
+	OutPoint wire.OutPoint
+
+	// Value is the value of the spent output.
+	Value btcutil.Amount
+
+	// PkScript is the script of the spent output.
+	PkScript PkScript
+}
+
+// FundingOutput is a change output a party adds to the funding transaction of
+// a dual funded channel.
+type FundingOutput struct {
+	// Value is the value of the output.
+	Value btcutil.Amount
+
+	// PkScript is the script the output pays to.
+	PkScript PkScript
+}
+
+// FundingContribution is sent by each party of a dual funded channel to
+// announce the inputs and change outputs it adds to the funding transaction.
+// Alice (the initiator) sends hers right before the OpenChannel message, and
+// Bob (the responder) sends his right before the AcceptChannel message, once
+// he chose to contribute to the channel.
+type FundingContribution struct {
+	// PendingChannelID is the pending channel ID of the funding flow the
+	// contribution belongs to.
+	PendingChannelID [32]byte
+
+	// FundingAmount is the amount the sender contributes to the capacity
+	// of the channel.
+	FundingAmount btcutil.Amount
+
+	// Inputs are the inputs of the sender to the funding transaction.
+	Inputs []FundingInput
+
+	// ChangeOutputs are the change outputs of the sender on the funding
+	// transaction.
+	ChangeOutputs []FundingOutput
+}
+
+// A compile time check to ensure FundingContribution implements the
+// lnwire.Message interface.
+var _ Message = (*FundingContribution)(nil)
+
+// Encode serializes the target FundingContribution into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (f *FundingContribution) Encode(w io.Writer, pver uint32) error {
+	err := WriteElements(w,
+		f.PendingChannelID[:],
+		f.FundingAmount,
+		uint16(len(f.Inputs)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, input := range f.Inputs {
+		err := WriteElements(w, input.OutPoint, input.Value,
+			input.PkScript)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := WriteElement(w, uint16(len(f.ChangeOutputs))); err != nil {
+		return err
+	}
+
+	for _, output := range f.ChangeOutputs {
+		err := WriteElements(w, output.Value, output.PkScript)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decode deserializes the serialized FundingContribution stored in the passed
+// io.Reader into the target FundingContribution using the deserialization
+// rules defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (f *FundingContribution) Decode(r io.Reader, pver uint32) error {
+	var numInputs uint16
+	err := ReadElements(r,
+		f.PendingChannelID[:],
+		&f.FundingAmount,
+		&numInputs,
+	)
+	if err != nil {
+		return err
+	}
+
+	f.Inputs = nil
+	if numInputs > 0 {
+		f.Inputs = make([]FundingInput, numInputs)
+	}
+	for i := range f.Inputs {
+		input := &f.Inputs[i]
+		err := ReadElements(r, &input.OutPoint, &input.Value,
+			&input.PkScript)
+		if err != nil {
+			return err
+		}
+	}
+
+	var numOutputs uint16
+	if err := ReadElement(r, &numOutputs); err != nil {
+		return err
+	}
+
+	f.ChangeOutputs = nil
+	if numOutputs > 0 {
+		f.ChangeOutputs = make([]FundingOutput, numOutputs)
+	}
+	for i := range f.ChangeOutputs {
+		output := &f.ChangeOutputs[i]
+		err := ReadElements(r, &output.Value, &output.PkScript)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MsgType returns the uint32 code which uniquely identifies this message as a
+// FundingContribution on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (f *FundingContribution) MsgType() MessageType {
+	return MsgFundingContribution
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// FundingContribution message.
+//
+// This is part of the lnwire.Message interface.
+func (f *FundingContribution) MaxPayloadLength(uint32) uint32 {
+	return MaxMessagePayload
+}
@@ -0,0 +1,144 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/litecoinfinance/btcd/wire"
+)
+
+const (
+	// maxFundingSigScriptSize is the largest signature script we'll accept
+	// for an input of a dual funded channel's funding transaction.
+	maxFundingSigScriptSize = 1650
+
+	// maxFundingWitnessItemSize is the largest witness item we'll accept
+	// for an input of a dual funded channel's funding transaction.
+	maxFundingWitnessItemSize = 10000
+)
+
+// FundingInputScript is the signature script and witness which spend an input
+// of the funding transaction of a dual funded channel.
+type FundingInputScript struct {
+	// SigScript is the signature script of the input, which is only set
+	// for nested witness inputs.
+	SigScript []byte
+
+	// Witness is the witness of the input.
+	Witness wire.TxWitness
+}
+
+// FundingWitnesses is sent by Bob (the responder) of a dual funded channel to
+// hand Alice (the initiator) the scripts spending his inputs to the funding
+// transaction. He sends it right before the FundingSigned message, after which
+// Alice is able to complete and broadcast the funding transaction.
+type FundingWitnesses struct {
+	// ChanID is the channel ID of the channel the funding transaction
+	// opens.
+	ChanID ChannelID
+
+	// InputScripts are the scripts spending each of the sender's inputs,
+	// in the order the inputs appear within the funding transaction, as
+	// sorted according to BIP-69.
+	InputScripts []FundingInputScript
+}
+
+// A compile time check to ensure FundingWitnesses implements the
+// lnwire.Message interface.
+var _ Message = (*FundingWitnesses)(nil)
+
+// Encode serializes the target FundingWitnesses into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (f *FundingWitnesses) Encode(w io.Writer, pver uint32) error {
+	err := WriteElements(w, f.ChanID, uint16(len(f.InputScripts)))
+	if err != nil {
+		return err
+	}
+
+	for _, inputScript := range f.InputScripts {
+		err := wire.WriteVarBytes(w, 0, inputScript.SigScript)
+		if err != nil {
+			return err
+		}
+
+		err = WriteElement(w, uint16(len(inputScript.Witness)))
+		if err != nil {
+			return err
+		}
+		for _, item := range inputScript.Witness {
+			if err := wire.WriteVarBytes(w, 0, item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Decode deserializes the serialized FundingWitnesses stored in the passed
+// io.Reader into the target FundingWitnesses using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (f *FundingWitnesses) Decode(r io.Reader, pver uint32) error {
+	var numInputs uint16
+	if err := ReadElements(r, &f.ChanID, &numInputs); err != nil {
+		return err
+	}
+
+	f.InputScripts = nil
+	if numInputs > 0 {
+		f.InputScripts = make([]FundingInputScript, numInputs)
+	}
+	for i := range f.InputScripts {
+		inputScript := &f.InputScripts[i]
+
+		sigScript, err := wire.ReadVarBytes(
+			r, 0, maxFundingSigScriptSize, "sigscript",
+		)
+		if err != nil {
+			return err
+		}
+		if len(sigScript) > 0 {
+			inputScript.SigScript = sigScript
+		}
+
+		var numItems uint16
+		if err := ReadElement(r, &numItems); err != nil {
+			return err
+		}
+		if numItems == 0 {
+			continue
+		}
+
+		inputScript.Witness = make(wire.TxWitness, numItems)
+		for j := range inputScript.Witness {
+			inputScript.Witness[j], err = wire.ReadVarBytes(
+				r, 0, maxFundingWitnessItemSize, "witness item",
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// MsgType returns the uint32 code which uniquely identifies this message as a
+// FundingWitnesses on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (f *FundingWitnesses) MsgType() MessageType {
+	return MsgFundingWitnesses
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// FundingWitnesses message.
+//
+// This is part of the lnwire.Message interface.
+func (f *FundingWitnesses) MaxPayloadLength(uint32) uint32 {
+	return MaxMessagePayload
+}
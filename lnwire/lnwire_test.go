@@ -810,6 +810,197 @@ func TestLightningWireProtocol(t *testing.T) {
 					NewShortChanIDFromInt(uint64(r.Int63())))
 			}
 
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgFundingContribution: func(v []reflect.Value, r *rand.Rand) {
+			req := FundingContribution{
+				FundingAmount: btcutil.Amount(r.Int63()),
+			}
+
+			if _, err := r.Read(req.PendingChannelID[:]); err != nil {
+				t.Fatalf("unable to generate pending chan id: %v", err)
+				return
+			}
+
+			numInputs := 1 + r.Int31n(10)
+			for i := int32(0); i < numInputs; i++ {
+				input := FundingInput{
+					Value:    btcutil.Amount(r.Int63()),
+					PkScript: make(PkScript, 22),
+				}
+				_, err := r.Read(input.OutPoint.Hash[:])
+				if err != nil {
+					t.Fatalf("unable to generate hash: %v", err)
+					return
+				}
+				input.OutPoint.Index = uint32(r.Int31n(
+					math.MaxUint16,
+				))
+				if _, err := r.Read(input.PkScript); err != nil {
+					t.Fatalf("unable to generate pkscript: %v",
+						err)
+					return
+				}
+
+				req.Inputs = append(req.Inputs, input)
+			}
+
+			// The change output is optional, so with a 50/50 chance
+			// we'll leave it out.
+			if r.Int31()%2 == 0 {
+				output := FundingOutput{
+					Value:    btcutil.Amount(r.Int63()),
+					PkScript: make(PkScript, 22),
+				}
+				if _, err := r.Read(output.PkScript); err != nil {
+					t.Fatalf("unable to generate pkscript: %v",
+						err)
+					return
+				}
+
+				req.ChangeOutputs = append(req.ChangeOutputs, output)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgFundingWitnesses: func(v []reflect.Value, r *rand.Rand) {
+			req := FundingWitnesses{}
+
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			numInputs := 1 + r.Int31n(10)
+			for i := int32(0); i < numInputs; i++ {
+				var inputScript FundingInputScript
+
+				// Only nested witness inputs carry a signature
+				// script, so with a 50/50 chance we'll leave it
+				// out.
+				if r.Int31()%2 == 0 {
+					inputScript.SigScript = make([]byte, 23)
+					_, err := r.Read(inputScript.SigScript)
+					if err != nil {
+						t.Fatalf("unable to generate "+
+							"sigscript: %v", err)
+						return
+					}
+				}
+
+				inputScript.Witness = make(wire.TxWitness, 2)
+				for j := range inputScript.Witness {
+					item := make([]byte, 1+r.Int31n(72))
+					if _, err := r.Read(item); err != nil {
+						t.Fatalf("unable to generate "+
+							"witness: %v", err)
+						return
+					}
+					inputScript.Witness[j] = item
+				}
+
+				req.InputScripts = append(
+					req.InputScripts, inputScript,
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceInit: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceInit{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			spliceTx := wire.NewMsgTx(2)
+			numInputs := 1 + r.Int31n(5)
+			for i := int32(0); i < numInputs; i++ {
+				var prevOut wire.OutPoint
+				_, err := r.Read(prevOut.Hash[:])
+				if err != nil {
+					t.Fatalf("unable to generate hash: %v",
+						err)
+					return
+				}
+				prevOut.Index = r.Uint32()
+				txIn := wire.NewTxIn(&prevOut, nil, nil)
+				spliceTx.AddTxIn(txIn)
+			}
+			numOutputs := 1 + r.Int31n(5)
+			for i := int32(0); i < numOutputs; i++ {
+				pkScript := make([]byte, 34)
+				if _, err := r.Read(pkScript); err != nil {
+					t.Fatalf("unable to generate "+
+						"pkscript: %v", err)
+					return
+				}
+				txOut := wire.NewTxOut(r.Int63(), pkScript)
+				spliceTx.AddTxOut(txOut)
+			}
+
+			// The transaction is normalized by a round trip, as
+			// the decoded scripts are never nil.
+			var b bytes.Buffer
+			if err := spliceTx.SerializeNoWitness(&b); err != nil {
+				t.Fatalf("unable to serialize tx: %v", err)
+				return
+			}
+			req.SpliceTx = &wire.MsgTx{}
+			err := req.SpliceTx.DeserializeNoWitness(&b)
+			if err != nil {
+				t.Fatalf("unable to deserialize tx: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceAck: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceAck{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.CommitSig, err = NewSigFromSignature(testSig)
+			if err != nil {
+				t.Fatalf("unable to parse sig: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceCommit: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceCommit{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.CommitSig, err = NewSigFromSignature(testSig)
+			if err != nil {
+				t.Fatalf("unable to parse sig: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceSigned: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceSigned{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.FundingSig, err = NewSigFromSignature(testSig)
+			if err != nil {
+				t.Fatalf("unable to parse sig: %v", err)
+				return
+			}
+
 			v[0] = reflect.ValueOf(req)
 		},
 	}
@@ -992,6 +1183,42 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgFundingContribution,
+			scenario: func(m FundingContribution) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgFundingWitnesses,
+			scenario: func(m FundingWitnesses) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceInit,
+			scenario: func(m SpliceInit) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceAck,
+			scenario: func(m SpliceAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceCommit,
+			scenario: func(m SpliceCommit) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceSigned,
+			scenario: func(m SpliceSigned) bool {
+				return mainScenario(&m)
+			},
+		},
 	}
 	for _, test := range tests {
 		var config *quick.Config
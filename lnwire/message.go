@@ -54,6 +54,12 @@ const (
 	MsgQueryChannelRange                   = 263
 	MsgReplyChannelRange                   = 264
 	MsgGossipTimestampRange                = 265
+	MsgFundingContribution                 = 32769
+	MsgFundingWitnesses                    = 32771
+	MsgSpliceInit                          = 32773
+	MsgSpliceAck                           = 32775
+	MsgSpliceCommit                        = 32777
+	MsgSpliceSigned                        = 32779
 )
 
 // String return the string representation of message type.
@@ -115,6 +121,18 @@ func (t MessageType) String() string {
 		return "ReplyChannelRange"
 	case MsgGossipTimestampRange:
 		return "GossipTimestampRange"
+	case MsgFundingContribution:
+		return "FundingContribution"
+	case MsgFundingWitnesses:
+		return "FundingWitnesses"
+	case MsgSpliceInit:
+		return "SpliceInit"
+	case MsgSpliceAck:
+		return "SpliceAck"
+	case MsgSpliceCommit:
+		return "SpliceCommit"
+	case MsgSpliceSigned:
+		return "SpliceSigned"
 	default:
 		return "<unknown>"
 	}
@@ -216,6 +234,18 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &ReplyChannelRange{}
 	case MsgGossipTimestampRange:
 		msg = &GossipTimestampRange{}
+	case MsgFundingContribution:
+		msg = &FundingContribution{}
+	case MsgFundingWitnesses:
+		msg = &FundingWitnesses{}
+	case MsgSpliceInit:
+		msg = &SpliceInit{}
+	case MsgSpliceAck:
+		msg = &SpliceAck{}
+	case MsgSpliceCommit:
+		msg = &SpliceCommit{}
+	case MsgSpliceSigned:
+		msg = &SpliceSigned{}
 	default:
 		return nil, &UnknownMessage{msgType}
 	}
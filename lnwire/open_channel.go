@@ -17,6 +17,19 @@ const (
 	// initiator of a funding flow wishes to announce the channel to the
 	// greater network.
 	FFAnnounceChannel FundingFlag = 1 << iota
+
+	// FFDualFund is a FundingFlag that when set, indicates the initiator
+	// of a funding flow requests the responder to contribute funds to the
+	// channel. The initiator's own contribution precedes the request in a
+	// FundingContribution message, and the FundingAmount of the request
+	// is the total capacity of the channel.
+	FFDualFund
+
+	// FFZeroConf is a FundingFlag that when set, indicates the initiator
+	// of a funding flow requests the channel to be usable before its
+	// funding transaction confirms. The responder agrees by requiring a
+	// MinAcceptDepth of zero.
+	FFZeroConf
 )
 
 // OpenChannel is the message Alice sends to Bob if we should like to create a
@@ -1,6 +1,7 @@
 package lnwire
 
 import (
+	"bytes"
 	"io"
 
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
@@ -24,6 +25,11 @@ type QueryChannelRange struct {
 	// NumBlocks is the number of blocks beyond the first block that short
 	// channel ID's should be sent for.
 	NumBlocks uint32
+
+	// QueryOptions requests additional information about each short
+	// channel ID to be included in the replies. This is only sent to peers
+	// that signal support for extended gossip queries.
+	QueryOptions QueryOption
 }
 
 // NewQueryChannelRange creates a new empty QueryChannelRange message.
@@ -40,6 +46,30 @@ var _ Message = (*QueryChannelRange)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (q *QueryChannelRange) Decode(r io.Reader, pver uint32) error {
+	if err := q.decodeRange(r); err != nil {
+		return err
+	}
+
+	records, err := readTLVStream(r, queryOptionsType)
+	if err != nil {
+		return err
+	}
+
+	q.QueryOptions = 0
+	if value, ok := records[queryOptionsType]; ok {
+		opts, err := readBigSize(bytes.NewReader(value))
+		if err != nil {
+			return err
+		}
+		q.QueryOptions = QueryOption(opts)
+	}
+
+	return nil
+}
+
+// decodeRange deserializes the queried range, which is shared with the
+// ReplyChannelRange message.
+func (q *QueryChannelRange) decodeRange(r io.Reader) error {
 	return ReadElements(r,
 		q.ChainHash[:],
 		&q.FirstBlockHeight,
@@ -52,6 +82,30 @@ func (q *QueryChannelRange) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (q *QueryChannelRange) Encode(w io.Writer, pver uint32) error {
+	if err := q.encodeRange(w); err != nil {
+		return err
+	}
+
+	// The query options are only written if set, as peers that don't
+	// understand extended queries don't expect them.
+	if q.QueryOptions == 0 {
+		return nil
+	}
+
+	var opts bytes.Buffer
+	if err := writeBigSize(&opts, uint64(q.QueryOptions)); err != nil {
+		return err
+	}
+
+	return writeTLVStream(w, tlvRecord{
+		typ:   queryOptionsType,
+		value: opts.Bytes(),
+	})
+}
+
+// encodeRange serializes the queried range, which is shared with the
+// ReplyChannelRange message.
+func (q *QueryChannelRange) encodeRange(w io.Writer) error {
 	return WriteElements(w,
 		q.ChainHash[:],
 		q.FirstBlockHeight,
@@ -72,6 +126,7 @@ func (q *QueryChannelRange) MsgType() MessageType {
 //
 // This is part of the lnwire.Message interface.
 func (q *QueryChannelRange) MaxPayloadLength(uint32) uint32 {
-	// 32 + 4 + 4
-	return 40
+	// 32 + 4 + 4, followed by the query options record of at most 1 + 1
+	// + 9 bytes.
+	return 51
 }
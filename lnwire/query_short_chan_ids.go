@@ -67,6 +67,11 @@ type QueryShortChanIDs struct {
 
 	// ShortChanIDs is a slice of decoded short channel ID's.
 	ShortChanIDs []ShortChannelID
+
+	// QueryFlags, if set, signals which announcements of each channel
+	// within ShortChanIDs, at the same index, the sender is interested in.
+	// If not set, all announcements of each channel are requested.
+	QueryFlags []QueryFlag
 }
 
 // NewQueryShortChanIDs creates a new QueryShortChanIDs message.
@@ -95,8 +100,41 @@ func (q *QueryShortChanIDs) Decode(r io.Reader, pver uint32) error {
 	}
 
 	q.EncodingType, q.ShortChanIDs, err = decodeShortChanIDs(r)
+	if err != nil {
+		return err
+	}
+
+	records, err := readTLVStream(r, queryFlagsType)
+	if err != nil {
+		return err
+	}
+
+	q.QueryFlags = nil
+	value, ok := records[queryFlagsType]
+	if !ok {
+		return nil
+	}
+
+	data, err := decodeExtendedData(value)
+	if err != nil {
+		return err
+	}
+
+	flagReader := bytes.NewReader(data)
+	for flagReader.Len() > 0 {
+		flag, err := readBigSize(flagReader)
+		if err != nil {
+			return fmt.Errorf("unable to parse query flag: %v", err)
+		}
+		q.QueryFlags = append(q.QueryFlags, QueryFlag(flag))
+	}
 
-	return err
+	if len(q.QueryFlags) != len(q.ShortChanIDs) {
+		return fmt.Errorf("expected %d query flags, got %d",
+			len(q.ShortChanIDs), len(q.QueryFlags))
+	}
+
+	return nil
 }
 
 // decodeShortChanIDs decodes a set of short channel ID's that have been
@@ -245,6 +283,19 @@ func decodeShortChanIDs(r io.Reader) (ShortChanIDEncoding, []ShortChannelID, err
 //
 // This is part of the lnwire.Message interface.
 func (q *QueryShortChanIDs) Encode(w io.Writer, pver uint32) error {
+	// If query flags are included, they need to line up with the short
+	// channel ID's, which will no longer be the case if they're sorted
+	// during encoding.
+	if q.QueryFlags != nil {
+		if len(q.QueryFlags) != len(q.ShortChanIDs) {
+			return fmt.Errorf("expected %d query flags, got %d",
+				len(q.ShortChanIDs), len(q.QueryFlags))
+		}
+		if err := verifySortedChanIDs(q.ShortChanIDs); err != nil {
+			return err
+		}
+	}
+
 	// First, we'll write out the chain hash.
 	err := WriteElements(w, q.ChainHash[:])
 	if err != nil {
@@ -253,7 +304,29 @@ func (q *QueryShortChanIDs) Encode(w io.Writer, pver uint32) error {
 
 	// Base on our encoding type, we'll write out the set of short channel
 	// ID's.
-	return encodeShortChanIDs(w, q.EncodingType, q.ShortChanIDs)
+	err = encodeShortChanIDs(w, q.EncodingType, q.ShortChanIDs)
+	if err != nil || q.QueryFlags == nil {
+		return err
+	}
+
+	// Finally, we'll write out the query flags, encoded in the same
+	// manner as the short channel ID's.
+	var flags bytes.Buffer
+	for _, flag := range q.QueryFlags {
+		if err := writeBigSize(&flags, uint64(flag)); err != nil {
+			return err
+		}
+	}
+
+	value, err := encodeExtendedData(q.EncodingType, flags.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return writeTLVStream(w, tlvRecord{
+		typ:   queryFlagsType,
+		value: value,
+	})
 }
 
 // encodeShortChanIDs encodes the passed short channel ID's into the passed
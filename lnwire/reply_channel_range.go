@@ -1,6 +1,11 @@
 package lnwire
 
-import "io"
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
 
 // ReplyChannelRange is the response to the QueryChannelRange message. It
 // includes the original query, and the next streaming chunk of encoded short
@@ -8,6 +13,7 @@ import "io"
 // this is the last query in the message.
 type ReplyChannelRange struct {
 	// QueryChannelRange is the corresponding query to this response.
+	// Only the queried range is sent back, its QueryOptions are omitted.
 	QueryChannelRange
 
 	// Complete denotes if this is the conclusion of the set of streaming
@@ -21,6 +27,16 @@ type ReplyChannelRange struct {
 
 	// ShortChanIDs is a slice of decoded short channel ID's.
 	ShortChanIDs []ShortChannelID
+
+	// Timestamps, if set, holds the timestamps of the latest channel
+	// updates of each channel within ShortChanIDs, at the same index. It
+	// is only sent if requested through QueryOptionTimestamps.
+	Timestamps []ChanUpdateTimestamps
+
+	// Checksums, if set, holds the checksums of the latest channel updates
+	// of each channel within ShortChanIDs, at the same index. It is only
+	// sent if requested through QueryOptionChecksums.
+	Checksums []ChanUpdateChecksums
 }
 
 // NewReplyChannelRange creates a new empty ReplyChannelRange message.
@@ -37,7 +53,7 @@ var _ Message = (*ReplyChannelRange)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *ReplyChannelRange) Decode(r io.Reader, pver uint32) error {
-	err := c.QueryChannelRange.Decode(r, pver)
+	err := c.QueryChannelRange.decodeRange(r)
 	if err != nil {
 		return err
 	}
@@ -47,8 +63,56 @@ func (c *ReplyChannelRange) Decode(r io.Reader, pver uint32) error {
 	}
 
 	c.EncodingType, c.ShortChanIDs, err = decodeShortChanIDs(r)
+	if err != nil {
+		return err
+	}
+
+	records, err := readTLVStream(r, timestampsType, checksumsType)
+	if err != nil {
+		return err
+	}
+
+	c.Timestamps = nil
+	if value, ok := records[timestampsType]; ok {
+		data, err := decodeExtendedData(value)
+		if err != nil {
+			return err
+		}
+		if len(data) != len(c.ShortChanIDs)*8 {
+			return fmt.Errorf("expected %d timestamps, got %d "+
+				"bytes", len(c.ShortChanIDs), len(data))
+		}
+
+		c.Timestamps = make([]ChanUpdateTimestamps, len(c.ShortChanIDs))
+		for i := range c.Timestamps {
+			c.Timestamps[i].Timestamp1 = binary.BigEndian.Uint32(
+				data[i*8:],
+			)
+			c.Timestamps[i].Timestamp2 = binary.BigEndian.Uint32(
+				data[i*8+4:],
+			)
+		}
+	}
 
-	return err
+	c.Checksums = nil
+	if value, ok := records[checksumsType]; ok {
+		if len(value) != len(c.ShortChanIDs)*8 {
+			return fmt.Errorf("expected %d checksums, got %d "+
+				"bytes", len(c.ShortChanIDs), len(value))
+		}
+
+		c.Checksums = make([]ChanUpdateChecksums, len(c.ShortChanIDs))
+		for i := range c.Checksums {
+			c.Checksums[i].Checksum1 = binary.BigEndian.Uint32(
+				value[i*8:],
+			)
+			c.Checksums[i].Checksum2 = binary.BigEndian.Uint32(
+				value[i*8+4:],
+			)
+		}
+	}
+
+	return nil
 }
 
 // Encode serializes the target ReplyChannelRange into the passed io.Writer
@@ -56,7 +120,14 @@ func (c *ReplyChannelRange) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *ReplyChannelRange) Encode(w io.Writer, pver uint32) error {
-	if err := c.QueryChannelRange.Encode(w, pver); err != nil {
+	extended := c.Timestamps != nil || c.Checksums != nil
+	if extended {
+		if err := verifySortedChanIDs(c.ShortChanIDs); err != nil {
+			return err
+		}
+	}
+
+	if err := c.QueryChannelRange.encodeRange(w); err != nil {
 		return err
 	}
 
@@ -64,7 +135,64 @@ func (c *ReplyChannelRange) Encode(w io.Writer, pver uint32) error {
 		return err
 	}
 
-	return encodeShortChanIDs(w, c.EncodingType, c.ShortChanIDs)
+	err := encodeShortChanIDs(w, c.EncodingType, c.ShortChanIDs)
+	if err != nil || !extended {
+		return err
+	}
+
+	var records []tlvRecord
+	if c.Timestamps != nil {
+		if len(c.Timestamps) != len(c.ShortChanIDs) {
+			return fmt.Errorf("expected %d timestamps, got %d",
+				len(c.ShortChanIDs), len(c.Timestamps))
+		}
+
+		var timestamps bytes.Buffer
+		for _, ts := range c.Timestamps {
+			err := WriteElements(
+				&timestamps, ts.Timestamp1, ts.Timestamp2,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		value, err := encodeExtendedData(
+			c.EncodingType, timestamps.Bytes(),
+		)
+		if err != nil {
+			return err
+		}
+
+		records = append(records, tlvRecord{
+			typ:   timestampsType,
+			value: value,
+		})
+	}
+
+	if c.Checksums != nil {
+		if len(c.Checksums) != len(c.ShortChanIDs) {
+			return fmt.Errorf("expected %d checksums, got %d",
+				len(c.ShortChanIDs), len(c.Checksums))
+		}
+
+		var checksums bytes.Buffer
+		for _, cs := range c.Checksums {
+			err := WriteElements(
+				&checksums, cs.Checksum1, cs.Checksum2,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		records = append(records, tlvRecord{
+			typ:   checksumsType,
+			value: checksums.Bytes(),
+		})
+	}
+
+	return writeTLVStream(w, records...)
 }
 
 // MsgType returns the integer uniquely identifying this message type on the
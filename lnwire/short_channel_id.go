@@ -1,9 +1,23 @@
 package lnwire
 
 import (
+	"encoding/binary"
 	"fmt"
 )
 
+const (
+	// AliasStartHeight is the block height at which the range of alias
+	// short channel IDs starts. An alias doesn't refer to a funding
+	// output, but identifies a zero-conf channel until its funding
+	// transaction confirms. No block of this height will be mined for
+	// centuries.
+	AliasStartHeight uint32 = 16000000
+
+	// aliasEndHeight is the largest block height a short channel ID is
+	// able to encode, which ends the range of alias short channel IDs.
+	aliasEndHeight uint32 = (1 << 24) - 1
+)
+
 // ShortChannelID represents the set of data which is needed to retrieve all
 // necessary data to validate the channel existence.
 type ShortChannelID struct {
@@ -34,6 +48,27 @@ func NewShortChanIDFromInt(chanID uint64) ShortChannelID {
 	}
 }
 
+// NewAliasShortChanID returns the alias short channel ID of the zero-conf
+// channel with the given channel ID. As the alias is derived from the funding
+// outpoint of the channel, it doesn't need to be stored separately, while
+// collisions with the alias of another channel are improbable.
+func NewAliasShortChanID(chanID ChannelID) ShortChannelID {
+	numHeights := aliasEndHeight - AliasStartHeight + 1
+	height := binary.BigEndian.Uint32(chanID[:4]) % numHeights
+
+	return ShortChannelID{
+		BlockHeight: AliasStartHeight + height,
+		TxIndex:     binary.BigEndian.Uint32(chanID[4:8]) & 0xFFFFFF,
+		TxPosition:  binary.BigEndian.Uint16(chanID[8:10]),
+	}
+}
+
+// IsAlias returns true if the ShortChannelID is the alias of a zero-conf
+// channel, rather than the location of its funding output.
+func (c ShortChannelID) IsAlias() bool {
+	return c.BlockHeight >= AliasStartHeight
+}
+
 // ToUint64 converts the ShortChannelID into a compact format encoded within a
 // uint64 (8 bytes).
 func (c ShortChannelID) ToUint64() uint64 {
@@ -39,3 +39,40 @@ func TestShortChannelIDEncoding(t *testing.T) {
 		}
 	}
 }
+
+// TestAliasShortChanID asserts that the alias short channel ID of a channel is
+// within the alias range, and is distinct from that of another channel.
+func TestAliasShortChanID(t *testing.T) {
+	t.Parallel()
+
+	var chanID1, chanID2 ChannelID
+	for i := range chanID1 {
+		chanID1[i] = 0xff
+		chanID2[i] = byte(i)
+	}
+
+	alias1 := NewAliasShortChanID(chanID1)
+	alias2 := NewAliasShortChanID(chanID2)
+	for _, alias := range []ShortChannelID{alias1, alias2} {
+		if !alias.IsAlias() {
+			t.Fatalf("expected %v to be an alias", alias)
+		}
+
+		// The alias must survive its compact encoding.
+		if NewShortChanIDFromInt(alias.ToUint64()) != alias {
+			t.Fatalf("alias %v doesn't match its encoding", alias)
+		}
+	}
+	if alias1 == alias2 {
+		t.Fatalf("expected distinct aliases, got %v", alias1)
+	}
+
+	confirmed := ShortChannelID{
+		BlockHeight: 1500000,
+		TxIndex:     2345,
+		TxPosition:  5,
+	}
+	if confirmed.IsAlias() {
+		t.Fatalf("expected %v not to be an alias", confirmed)
+	}
+}
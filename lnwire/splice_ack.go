@@ -0,0 +1,54 @@
+package lnwire
+
+import "io"
+
+// SpliceAck is sent by the responder of a splice once it accepted the splice
+// transaction proposed by SpliceInit. By then, the responder no longer updates
+// the channel off-chain until the splice either completes, or is aborted.
+type SpliceAck struct {
+	// ChanID is the channel ID of the channel being spliced.
+	ChanID ChannelID
+
+	// CommitSig is the signature of the responder for the commitment
+	// transaction of the initiator spending the new funding output.
+	CommitSig Sig
+}
+
+// A compile time check to ensure SpliceAck implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceAck)(nil)
+
+// Encode serializes the target SpliceAck into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Encode(w io.Writer, pver uint32) error {
+	return WriteElements(w, s.ChanID, s.CommitSig)
+}
+
+// Decode deserializes the serialized SpliceAck stored in the passed
+// io.Reader into the target SpliceAck using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &s.ChanID, &s.CommitSig)
+}
+
+// MsgType returns the uint32 code which uniquely identifies this message as a
+// SpliceAck on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) MsgType() MessageType {
+	return MsgSpliceAck
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// SpliceAck message.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) MaxPayloadLength(uint32) uint32 {
+	// 32 + 64
+	return 96
+}
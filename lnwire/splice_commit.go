@@ -0,0 +1,54 @@
+package lnwire
+
+import "io"
+
+// SpliceCommit is sent by the initiator of a splice in response to SpliceAck,
+// once it verified the signature for its commitment transaction spending the
+// new funding output, and stored the splice.
+type SpliceCommit struct {
+	// ChanID is the channel ID of the channel being spliced.
+	ChanID ChannelID
+
+	// CommitSig is the signature of the initiator for the commitment
+	// transaction of the responder spending the new funding output.
+	CommitSig Sig
+}
+
+// A compile time check to ensure SpliceCommit implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceCommit)(nil)
+
+// Encode serializes the target SpliceCommit into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceCommit) Encode(w io.Writer, pver uint32) error {
+	return WriteElements(w, s.ChanID, s.CommitSig)
+}
+
+// Decode deserializes the serialized SpliceCommit stored in the passed
+// io.Reader into the target SpliceCommit using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceCommit) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &s.ChanID, &s.CommitSig)
+}
+
+// MsgType returns the uint32 code which uniquely identifies this message as a
+// SpliceCommit on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceCommit) MsgType() MessageType {
+	return MsgSpliceCommit
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// SpliceCommit message.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceCommit) MaxPayloadLength(uint32) uint32 {
+	// 32 + 64
+	return 96
+}
@@ -0,0 +1,83 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/litecoinfinance/btcd/wire"
+)
+
+// SpliceInit is sent by the initiator of a splice to propose a new funding
+// transaction for an existing channel, which changes its capacity without
+// closing it. The splice transaction spends the current funding output, and
+// pays to a new funding output locked to the same multi-sig keys, along with
+// any inputs and outputs of the initiator. The difference in capacity is
+// credited to, or debited from, the balance of the initiator.
+type SpliceInit struct {
+	// ChanID is the channel ID of the channel to be spliced.
+	ChanID ChannelID
+
+	// SpliceTx is the splice transaction. It's sent without any witnesses,
+	// as those don't affect its txid, which the commitment transactions
+	// spending the new funding output depend on.
+	SpliceTx *wire.MsgTx
+}
+
+// A compile time check to ensure SpliceInit implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceInit)(nil)
+
+// Encode serializes the target SpliceInit into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Encode(w io.Writer, pver uint32) error {
+	if err := WriteElement(w, s.ChanID); err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := s.SpliceTx.SerializeNoWitness(&b); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, b.Bytes())
+}
+
+// Decode deserializes the serialized SpliceInit stored in the passed
+// io.Reader into the target SpliceInit using the deserialization rules defined
+// by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Decode(r io.Reader, pver uint32) error {
+	if err := ReadElement(r, &s.ChanID); err != nil {
+		return err
+	}
+
+	txBytes, err := wire.ReadVarBytes(
+		r, 0, MaxMessagePayload, "splice tx",
+	)
+	if err != nil {
+		return err
+	}
+
+	s.SpliceTx = &wire.MsgTx{}
+	return s.SpliceTx.DeserializeNoWitness(bytes.NewReader(txBytes))
+}
+
+// MsgType returns the uint32 code which uniquely identifies this message as a
+// SpliceInit on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) MsgType() MessageType {
+	return MsgSpliceInit
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// SpliceInit message.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) MaxPayloadLength(uint32) uint32 {
+	return MaxMessagePayload
+}
@@ -0,0 +1,55 @@
+package lnwire
+
+import "io"
+
+// SpliceSigned is the final message of a splice, sent by the responder in
+// response to SpliceCommit. It carries the signature of the responder for the
+// input of the splice transaction spending the current funding output, which
+// allows the initiator to complete and broadcast the splice transaction.
+type SpliceSigned struct {
+	// ChanID is the channel ID of the channel being spliced.
+	ChanID ChannelID
+
+	// FundingSig is the signature of the responder for the input of the
+	// splice transaction spending the current funding output.
+	FundingSig Sig
+}
+
+// A compile time check to ensure SpliceSigned implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceSigned)(nil)
+
+// Encode serializes the target SpliceSigned into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceSigned) Encode(w io.Writer, pver uint32) error {
+	return WriteElements(w, s.ChanID, s.FundingSig)
+}
+
+// Decode deserializes the serialized SpliceSigned stored in the passed
+// io.Reader into the target SpliceSigned using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceSigned) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &s.ChanID, &s.FundingSig)
+}
+
+// MsgType returns the uint32 code which uniquely identifies this message as a
+// SpliceSigned on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceSigned) MsgType() MessageType {
+	return MsgSpliceSigned
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// SpliceSigned message.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceSigned) MaxPayloadLength(uint32) uint32 {
+	// 32 + 64
+	return 96
+}
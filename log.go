@@ -12,25 +12,36 @@ import (
 	"github.com/litecoinfinance/neutrino"
 	sphinx "github.com/litecoinfinance/lightning-onion"
 	"github.com/litecoinfinance/lnd/autopilot"
+	"github.com/litecoinfinance/lnd/blockfetch"
 	"github.com/litecoinfinance/lnd/build"
+	"github.com/litecoinfinance/lnd/chainhealth"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/chanbackup"
 	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/channelnotifier"
 	"github.com/litecoinfinance/lnd/contractcourt"
+	"github.com/litecoinfinance/lnd/dbbackup"
 	"github.com/litecoinfinance/lnd/discovery"
+	"github.com/litecoinfinance/lnd/feepolicy"
 	"github.com/litecoinfinance/lnd/htlcswitch"
 	"github.com/litecoinfinance/lnd/invoices"
+	"github.com/litecoinfinance/lnd/journal"
 	"github.com/litecoinfinance/lnd/lnrpc/autopilotrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/chainrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/invoicesrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/routerrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/signrpc"
+	"github.com/litecoinfinance/lnd/lnrpc/towerrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/walletrpc"
+	"github.com/litecoinfinance/lnd/lnrpc/wtclientrpc"
 	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/netann"
+	"github.com/litecoinfinance/lnd/paysched"
+	"github.com/litecoinfinance/lnd/rebalance"
+	"github.com/litecoinfinance/lnd/resources"
 	"github.com/litecoinfinance/lnd/routing"
 	"github.com/litecoinfinance/lnd/signal"
+	"github.com/litecoinfinance/lnd/swap"
 	"github.com/litecoinfinance/lnd/sweep"
 	"github.com/litecoinfinance/lnd/watchtower"
 )
@@ -114,6 +125,17 @@ func init() {
 	chanbackup.UseLogger(chbuLog)
 
 	addSubLogger(routerrpc.Subsystem, routerrpc.UseLogger)
+	addSubLogger(paysched.Subsystem, paysched.UseLogger)
+	addSubLogger(rebalance.Subsystem, rebalance.UseLogger)
+	addSubLogger(feepolicy.Subsystem, feepolicy.UseLogger)
+	addSubLogger(chainhealth.Subsystem, chainhealth.UseLogger)
+	addSubLogger(blockfetch.Subsystem, blockfetch.UseLogger)
+	addSubLogger(journal.Subsystem, journal.UseLogger)
+	addSubLogger(swap.Subsystem, swap.UseLogger)
+	addSubLogger(towerrpc.Subsystem, towerrpc.UseLogger)
+	addSubLogger(wtclientrpc.Subsystem, wtclientrpc.UseLogger)
+	addSubLogger(resources.Subsystem, resources.UseLogger)
+	addSubLogger(dbbackup.Subsystem, dbbackup.UseLogger)
 }
 
 // addSubLogger is a helper method to conveniently register the logger of a sub
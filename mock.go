@@ -266,6 +266,26 @@ func (*mockWalletController) IsOurAddress(a btcutil.Address) bool {
 	return false
 }
 
+func (*mockWalletController) CreateAccount(name string) error {
+	return nil
+}
+
+func (*mockWalletController) ListAccounts() ([]string, error) {
+	return []string{lnwallet.DefaultAccountName}, nil
+}
+
+func (m *mockWalletController) NewAccountAddress(account string,
+	addrType lnwallet.AddressType) (btcutil.Address, error) {
+
+	return m.NewAddress(addrType, false)
+}
+
+func (*mockWalletController) LabelTransaction(hash chainhash.Hash,
+	label string, overwrite bool) error {
+
+	return nil
+}
+
 func (*mockWalletController) SendOutputs(outputs []*wire.TxOut,
 	_ lnwallet.SatPerKWeight) (*wire.MsgTx, error) {
 
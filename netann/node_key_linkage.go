@@ -0,0 +1,84 @@
+package netann
+
+import (
+	"errors"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// nodeKeyLinkageTag is prepended to the message signed when linking two node
+// identity keys, so that the signatures can't be mistaken for signatures of any
+// other message.
+var nodeKeyLinkageTag = []byte("lnd node key rotation")
+
+// ErrInvalidNodeKeyLinkage is returned when either signature of a node key
+// linkage proof is invalid.
+var ErrInvalidNodeKeyLinkage = errors.New("invalid node key linkage proof")
+
+// NodeKeyLinkageMsg returns the message signed by both the old and the new node
+// identity keys when rotating from one to the other.
+func NodeKeyLinkageMsg(oldKey, newKey *btcec.PublicKey) []byte {
+	var msg []byte
+	msg = append(msg, nodeKeyLinkageTag...)
+	msg = append(msg, oldKey.SerializeCompressed()...)
+	msg = append(msg, newKey.SerializeCompressed()...)
+
+	return msg
+}
+
+// SignNodeKeyLinkage produces a proof linking the new node identity key to the
+// old one, consisting of a signature of the linkage message under each key.
+func SignNodeKeyLinkage(oldSigner, newSigner lnwallet.MessageSigner,
+	oldKey, newKey *btcec.PublicKey) (lnwire.Sig, lnwire.Sig, error) {
+
+	msg := NodeKeyLinkageMsg(oldKey, newKey)
+
+	oldSig, err := oldSigner.SignMessage(oldKey, msg)
+	if err != nil {
+		return lnwire.Sig{}, lnwire.Sig{}, err
+	}
+	newSig, err := newSigner.SignMessage(newKey, msg)
+	if err != nil {
+		return lnwire.Sig{}, lnwire.Sig{}, err
+	}
+
+	oldWireSig, err := lnwire.NewSigFromSignature(oldSig)
+	if err != nil {
+		return lnwire.Sig{}, lnwire.Sig{}, err
+	}
+	newWireSig, err := lnwire.NewSigFromSignature(newSig)
+	if err != nil {
+		return lnwire.Sig{}, lnwire.Sig{}, err
+	}
+
+	return oldWireSig, newWireSig, nil
+}
+
+// VerifyNodeKeyLinkage checks that the given signatures form a valid proof
+// linking the new node identity key to the old one.
+func VerifyNodeKeyLinkage(oldKey, newKey *btcec.PublicKey, oldSig,
+	newSig lnwire.Sig) error {
+
+	digest := chainhash.DoubleHashB(NodeKeyLinkageMsg(oldKey, newKey))
+
+	for _, keySig := range []struct {
+		key *btcec.PublicKey
+		sig lnwire.Sig
+	}{
+		{oldKey, oldSig},
+		{newKey, newSig},
+	} {
+		sig, err := keySig.sig.ToSignature()
+		if err != nil {
+			return err
+		}
+		if !sig.Verify(digest, keySig.key) {
+			return ErrInvalidNodeKeyLinkage
+		}
+	}
+
+	return nil
+}
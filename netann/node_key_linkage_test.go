@@ -0,0 +1,53 @@
+package netann_test
+
+import (
+	"testing"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/lnd/netann"
+)
+
+// TestNodeKeyLinkage asserts that a node key linkage proof produced by both
+// keys verifies, and that it doesn't verify for any other pair of keys.
+func TestNodeKeyLinkage(t *testing.T) {
+	t.Parallel()
+
+	oldPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	newPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	oldKey, newKey := oldPriv.PubKey(), newPriv.PubKey()
+
+	oldSig, newSig, err := netann.SignNodeKeyLinkage(
+		netann.NewNodeSigner(oldPriv), netann.NewNodeSigner(newPriv),
+		oldKey, newKey,
+	)
+	if err != nil {
+		t.Fatalf("unable to sign linkage: %v", err)
+	}
+
+	err = netann.VerifyNodeKeyLinkage(oldKey, newKey, oldSig, newSig)
+	if err != nil {
+		t.Fatalf("unable to verify linkage: %v", err)
+	}
+
+	// The proof commits to the direction of the rotation, so it shouldn't
+	// verify with the keys swapped.
+	err = netann.VerifyNodeKeyLinkage(newKey, oldKey, newSig, oldSig)
+	if err != netann.ErrInvalidNodeKeyLinkage {
+		t.Fatalf("expected ErrInvalidNodeKeyLinkage, got %v", err)
+	}
+
+	// Signing with a key other than the one being linked should fail.
+	_, _, err = netann.SignNodeKeyLinkage(
+		netann.NewNodeSigner(oldPriv), netann.NewNodeSigner(oldPriv),
+		oldKey, newKey,
+	)
+	if err == nil {
+		t.Fatalf("expected linkage signed by wrong key to fail")
+	}
+}
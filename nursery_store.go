@@ -7,8 +7,8 @@ import (
 
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
-	"github.com/coreos/bbolt"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 )
 
 //	              Overview of Nursery Store Storage Hierarchy
@@ -263,7 +263,7 @@ func newNurseryStore(chainHash *chainhash.Hash,
 // CSV-delayed outputs (commitment and incoming HTLC's), commitment output and
 // a list of outgoing two-stage htlc outputs.
 func (ns *nurseryStore) Incubate(kids []kidOutput, babies []babyOutput) error {
-	return ns.db.Update(func(tx *bbolt.Tx) error {
+	return ns.db.Update(func(tx kvdb.Tx) error {
 		// If we have any kid outputs to incubate, then we'll attempt
 		// to add each of them to the nursery store. Any duplicate
 		// outputs will be ignored.
@@ -290,7 +290,7 @@ func (ns *nurseryStore) Incubate(kids []kidOutput, babies []babyOutput) error {
 // kindergarten bucket. The now mature kidOutput contained in the babyOutput
 // will be stored as it waits out the kidOutput's CSV delay.
 func (ns *nurseryStore) CribToKinder(bby *babyOutput) error {
-	return ns.db.Update(func(tx *bbolt.Tx) error {
+	return ns.db.Update(func(tx kvdb.Tx) error {
 
 		// First, retrieve or create the channel bucket corresponding to
 		// the baby output's origin channel point.
@@ -374,7 +374,7 @@ func (ns *nurseryStore) CribToKinder(bby *babyOutput) error {
 func (ns *nurseryStore) PreschoolToKinder(kid *kidOutput,
 	lastGradHeight uint32) error {
 
-	return ns.db.Update(func(tx *bbolt.Tx) error {
+	return ns.db.Update(func(tx kvdb.Tx) error {
 		// Create or retrieve the channel bucket corresponding to the
 		// kid output's origin channel point.
 		chanPoint := kid.OriginChanPoint()
@@ -471,7 +471,7 @@ func (ns *nurseryStore) PreschoolToKinder(kid *kidOutput,
 // the height and channel indexes. The height bucket will be opportunistically
 // pruned from the height index as outputs are removed.
 func (ns *nurseryStore) GraduateKinder(height uint32, kid *kidOutput) error {
-	return ns.db.Update(func(tx *bbolt.Tx) error {
+	return ns.db.Update(func(tx kvdb.Tx) error {
 
 		hghtBucket := ns.getHeightBucket(tx, height)
 		if hghtBucket == nil {
@@ -540,7 +540,7 @@ func (ns *nurseryStore) FetchClass(
 	// processed at the provided block height.
 	var kids []kidOutput
 	var babies []babyOutput
-	if err := ns.db.View(func(tx *bbolt.Tx) error {
+	if err := ns.db.View(func(tx kvdb.Tx) error {
 		// Append each crib output to our list of babyOutputs.
 		if err := ns.forEachHeightPrefix(tx, cribPrefix, height,
 			func(buf []byte) error {
@@ -594,7 +594,7 @@ func (ns *nurseryStore) FetchClass(
 // preschool bucket.
 func (ns *nurseryStore) FetchPreschools() ([]kidOutput, error) {
 	var kids []kidOutput
-	if err := ns.db.View(func(tx *bbolt.Tx) error {
+	if err := ns.db.View(func(tx kvdb.Tx) error {
 
 		// Retrieve the existing chain bucket for this nursery store.
 		chainBucket := tx.Bucket(ns.pfxChainKey)
@@ -667,7 +667,7 @@ func (ns *nurseryStore) FetchPreschools() ([]kidOutput, error) {
 // index at or below the provided upper bound.
 func (ns *nurseryStore) HeightsBelowOrEqual(height uint32) ([]uint32, error) {
 	var activeHeights []uint32
-	err := ns.db.View(func(tx *bbolt.Tx) error {
+	err := ns.db.View(func(tx kvdb.Tx) error {
 		// Ensure that the chain bucket for this nursery store exists.
 		chainBucket := tx.Bucket(ns.pfxChainKey)
 		if chainBucket == nil {
@@ -712,7 +712,7 @@ func (ns *nurseryStore) HeightsBelowOrEqual(height uint32) ([]uint32, error) {
 func (ns *nurseryStore) ForChanOutputs(chanPoint *wire.OutPoint,
 	callback func([]byte, []byte) error) error {
 
-	return ns.db.View(func(tx *bbolt.Tx) error {
+	return ns.db.View(func(tx kvdb.Tx) error {
 		return ns.forChanOutputs(tx, chanPoint, callback)
 	})
 }
@@ -720,7 +720,7 @@ func (ns *nurseryStore) ForChanOutputs(chanPoint *wire.OutPoint,
 // ListChannels returns all channels the nursery is currently tracking.
 func (ns *nurseryStore) ListChannels() ([]wire.OutPoint, error) {
 	var activeChannels []wire.OutPoint
-	if err := ns.db.View(func(tx *bbolt.Tx) error {
+	if err := ns.db.View(func(tx kvdb.Tx) error {
 		// Retrieve the existing chain bucket for this nursery store.
 		chainBucket := tx.Bucket(ns.pfxChainKey)
 		if chainBucket == nil {
@@ -754,7 +754,7 @@ func (ns *nurseryStore) ListChannels() ([]wire.OutPoint, error) {
 // IsMatureChannel determines the whether or not all of the outputs in a
 // particular channel bucket have been marked as graduated.
 func (ns *nurseryStore) IsMatureChannel(chanPoint *wire.OutPoint) (bool, error) {
-	err := ns.db.View(func(tx *bbolt.Tx) error {
+	err := ns.db.View(func(tx kvdb.Tx) error {
 		// Iterate over the contents of the channel bucket, computing
 		// both total number of outputs, and those that have the grad
 		// prefix.
@@ -783,7 +783,7 @@ var ErrImmatureChannel = errors.New("cannot remove immature channel, " +
 // provided channel point.
 // NOTE: The channel's entries in the height index are assumed to be removed.
 func (ns *nurseryStore) RemoveChannel(chanPoint *wire.OutPoint) error {
-	return ns.db.Update(func(tx *bbolt.Tx) error {
+	return ns.db.Update(func(tx kvdb.Tx) error {
 		// Retrieve the existing chain bucket for this nursery store.
 		chainBucket := tx.Bucket(ns.pfxChainKey)
 		if chainBucket == nil {
@@ -845,7 +845,7 @@ func (ns *nurseryStore) RemoveChannel(chanPoint *wire.OutPoint) error {
 // its two-stage process of sweeping funds back to the user's wallet. These
 // outputs are persisted in the nursery store in the crib state, and will be
 // revisited after the first-stage output's CLTV has expired.
-func (ns *nurseryStore) enterCrib(tx *bbolt.Tx, baby *babyOutput) error {
+func (ns *nurseryStore) enterCrib(tx kvdb.Tx, baby *babyOutput) error {
 	// First, retrieve or create the channel bucket corresponding to the
 	// baby output's origin channel point.
 	chanPoint := baby.OriginChanPoint()
@@ -902,7 +902,7 @@ func (ns *nurseryStore) enterCrib(tx *bbolt.Tx, baby *babyOutput) error {
 // through a single stage before sweeping. Outputs are stored in the preschool
 // bucket until the commitment transaction has been confirmed, at which point
 // they will be moved to the kindergarten bucket.
-func (ns *nurseryStore) enterPreschool(tx *bbolt.Tx, kid *kidOutput) error {
+func (ns *nurseryStore) enterPreschool(tx kvdb.Tx, kid *kidOutput) error {
 	// First, retrieve or create the channel bucket corresponding to the
 	// baby output's origin channel point.
 	chanPoint := kid.OriginChanPoint()
@@ -935,8 +935,8 @@ func (ns *nurseryStore) enterPreschool(tx *bbolt.Tx, kid *kidOutput) error {
 
 // createChannelBucket creates or retrieves a channel bucket for the provided
 // channel point.
-func (ns *nurseryStore) createChannelBucket(tx *bbolt.Tx,
-	chanPoint *wire.OutPoint) (*bbolt.Bucket, error) {
+func (ns *nurseryStore) createChannelBucket(tx kvdb.Tx,
+	chanPoint *wire.OutPoint) (kvdb.Bucket, error) {
 
 	// Ensure that the chain bucket for this nursery store exists.
 	chainBucket, err := tx.CreateBucketIfNotExists(ns.pfxChainKey)
@@ -966,8 +966,8 @@ func (ns *nurseryStore) createChannelBucket(tx *bbolt.Tx,
 // getChannelBucket retrieves an existing channel bucket from the nursery store,
 // using the given channel point.  If the bucket does not exist, or any bucket
 // along its path does not exist, a nil value is returned.
-func (ns *nurseryStore) getChannelBucket(tx *bbolt.Tx,
-	chanPoint *wire.OutPoint) *bbolt.Bucket {
+func (ns *nurseryStore) getChannelBucket(tx kvdb.Tx,
+	chanPoint *wire.OutPoint) kvdb.Bucket {
 
 	// Retrieve the existing chain bucket for this nursery store.
 	chainBucket := tx.Bucket(ns.pfxChainKey)
@@ -993,8 +993,8 @@ func (ns *nurseryStore) getChannelBucket(tx *bbolt.Tx,
 
 // createHeightBucket creates or retrieves an existing bucket from the height
 // index, corresponding to the provided height.
-func (ns *nurseryStore) createHeightBucket(tx *bbolt.Tx,
-	height uint32) (*bbolt.Bucket, error) {
+func (ns *nurseryStore) createHeightBucket(tx kvdb.Tx,
+	height uint32) (kvdb.Bucket, error) {
 
 	// Ensure that the chain bucket for this nursery store exists.
 	chainBucket, err := tx.CreateBucketIfNotExists(ns.pfxChainKey)
@@ -1021,8 +1021,8 @@ func (ns *nurseryStore) createHeightBucket(tx *bbolt.Tx,
 // getHeightBucketPath retrieves an existing height bucket from the nursery
 // store, using the provided block height. If the bucket does not exist, or any
 // bucket along its path does not exist, a nil value is returned.
-func (ns *nurseryStore) getHeightBucketPath(tx *bbolt.Tx,
-	height uint32) (*bbolt.Bucket, *bbolt.Bucket, *bbolt.Bucket) {
+func (ns *nurseryStore) getHeightBucketPath(tx kvdb.Tx,
+	height uint32) (kvdb.Bucket, kvdb.Bucket, kvdb.Bucket) {
 
 	// Retrieve the existing chain bucket for this nursery store.
 	chainBucket := tx.Bucket(ns.pfxChainKey)
@@ -1047,8 +1047,8 @@ func (ns *nurseryStore) getHeightBucketPath(tx *bbolt.Tx,
 // getHeightBucket retrieves an existing height bucket from the nursery store,
 // using the provided block height. If the bucket does not exist, or any bucket
 // along its path does not exist, a nil value is returned.
-func (ns *nurseryStore) getHeightBucket(tx *bbolt.Tx,
-	height uint32) *bbolt.Bucket {
+func (ns *nurseryStore) getHeightBucket(tx kvdb.Tx,
+	height uint32) kvdb.Bucket {
 	_, _, hghtBucket := ns.getHeightBucketPath(tx, height)
 
 	return hghtBucket
@@ -1057,8 +1057,8 @@ func (ns *nurseryStore) getHeightBucket(tx *bbolt.Tx,
 // createHeightChanBucket creates or retrieves an existing height-channel bucket
 // for the provided block height and channel point. This method will attempt to
 // instantiate all buckets along the path if required.
-func (ns *nurseryStore) createHeightChanBucket(tx *bbolt.Tx,
-	height uint32, chanPoint *wire.OutPoint) (*bbolt.Bucket, error) {
+func (ns *nurseryStore) createHeightChanBucket(tx kvdb.Tx,
+	height uint32, chanPoint *wire.OutPoint) (kvdb.Bucket, error) {
 
 	// Ensure that the height bucket for this nursery store exists.
 	hghtBucket, err := ns.createHeightBucket(tx, height)
@@ -1083,8 +1083,8 @@ func (ns *nurseryStore) createHeightChanBucket(tx *bbolt.Tx,
 // nursery store, using the provided block height and channel point. if the
 // bucket does not exist, or any bucket along its path does not exist, a nil
 // value is returned.
-func (ns *nurseryStore) getHeightChanBucket(tx *bbolt.Tx,
-	height uint32, chanPoint *wire.OutPoint) *bbolt.Bucket {
+func (ns *nurseryStore) getHeightChanBucket(tx kvdb.Tx,
+	height uint32, chanPoint *wire.OutPoint) kvdb.Bucket {
 
 	// Retrieve the existing height bucket from this nursery store.
 	hghtBucket := ns.getHeightBucket(tx, height)
@@ -1110,7 +1110,7 @@ func (ns *nurseryStore) getHeightChanBucket(tx *bbolt.Tx,
 // enumerate crib and kindergarten outputs at a particular height. The callback
 // is invoked with serialized bytes retrieved for each output of interest,
 // allowing the caller to deserialize them into the appropriate type.
-func (ns *nurseryStore) forEachHeightPrefix(tx *bbolt.Tx, prefix []byte,
+func (ns *nurseryStore) forEachHeightPrefix(tx kvdb.Tx, prefix []byte,
 	height uint32, callback func([]byte) error) error {
 
 	// Start by retrieving the height bucket corresponding to the provided
@@ -1198,7 +1198,7 @@ func (ns *nurseryStore) forEachHeightPrefix(tx *bbolt.Tx, prefix []byte,
 // provided callback. The callback accepts a key-value pair of byte slices
 // corresponding to the prefixed-output key and the serialized output,
 // respectively.
-func (ns *nurseryStore) forChanOutputs(tx *bbolt.Tx, chanPoint *wire.OutPoint,
+func (ns *nurseryStore) forChanOutputs(tx kvdb.Tx, chanPoint *wire.OutPoint,
 	callback func([]byte, []byte) error) error {
 
 	chanBucket := ns.getChannelBucket(tx, chanPoint)
@@ -1216,7 +1216,7 @@ var errBucketNotEmpty = errors.New("bucket is not empty, cannot be pruned")
 // removeOutputFromHeight will delete the given output from the specified
 // height-channel bucket, and attempt to prune the upstream directories if they
 // are empty.
-func (ns *nurseryStore) removeOutputFromHeight(tx *bbolt.Tx, height uint32,
+func (ns *nurseryStore) removeOutputFromHeight(tx kvdb.Tx, height uint32,
 	chanPoint *wire.OutPoint, pfxKey []byte) error {
 
 	// Retrieve the height-channel bucket and delete the prefixed output.
@@ -1268,7 +1268,7 @@ func (ns *nurseryStore) removeOutputFromHeight(tx *bbolt.Tx, height uint32,
 // all active outputs at this height have been removed from their respective
 // height-channel buckets. The returned boolean value indicated whether or not
 // this invocation successfully pruned the height bucket.
-func (ns *nurseryStore) pruneHeight(tx *bbolt.Tx, height uint32) (bool, error) {
+func (ns *nurseryStore) pruneHeight(tx kvdb.Tx, height uint32) (bool, error) {
 	// Fetch the existing height index and height bucket.
 	_, hghtIndex, hghtBucket := ns.getHeightBucketPath(tx, height)
 	if hghtBucket == nil {
@@ -1315,7 +1315,7 @@ func (ns *nurseryStore) pruneHeight(tx *bbolt.Tx, height uint32) (bool, error) {
 
 // removeBucketIfEmpty attempts to delete a bucket specified by name from the
 // provided parent bucket.
-func removeBucketIfEmpty(parent *bbolt.Bucket, bktName []byte) error {
+func removeBucketIfEmpty(parent kvdb.Bucket, bktName []byte) error {
 	// Attempt to fetch the named bucket from its parent.
 	bkt := parent.Bucket(bktName)
 	if bkt == nil {
@@ -1333,7 +1333,7 @@ func removeBucketIfEmpty(parent *bbolt.Bucket, bktName []byte) error {
 
 // removeBucketIfExists safely deletes the named bucket by first checking
 // that it exists in the parent bucket.
-func removeBucketIfExists(parent *bbolt.Bucket, bktName []byte) error {
+func removeBucketIfExists(parent kvdb.Bucket, bktName []byte) error {
 	// Attempt to fetch the named bucket from its parent.
 	bkt := parent.Bucket(bktName)
 	if bkt == nil {
@@ -1346,7 +1346,7 @@ func removeBucketIfExists(parent *bbolt.Bucket, bktName []byte) error {
 
 // isBucketEmpty returns errBucketNotEmpty if the bucket has a non-zero number
 // of children.
-func isBucketEmpty(parent *bbolt.Bucket) error {
+func isBucketEmpty(parent kvdb.Bucket) error {
 	return parent.ForEach(func(_, _ []byte) error {
 		return errBucketNotEmpty
 	})
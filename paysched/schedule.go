@@ -0,0 +1,192 @@
+package paysched
+
+import (
+	"io"
+	"time"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing/route"
+)
+
+// State describes whether a schedule is still eligible to have its payments
+// executed.
+type State uint8
+
+const (
+	// StateActive denotes a schedule whose payments will be executed once
+	// they're due.
+	StateActive State = 0
+
+	// StateBudgetExhausted denotes a schedule that can no longer make
+	// payments without going over its budget.
+	StateBudgetExhausted State = 1
+
+	// StateFailed denotes a schedule that was disabled after too many
+	// consecutive payment failures. It can be re-activated by adding new
+	// payment requests to it.
+	StateFailed State = 2
+)
+
+// String returns a human readable version of the schedule state.
+func (s State) String() string {
+	switch s {
+	case StateActive:
+		return "Active"
+
+	case StateBudgetExhausted:
+		return "BudgetExhausted"
+
+	case StateFailed:
+		return "Failed"
+
+	default:
+		return "Unknown"
+	}
+}
+
+// Schedule describes a recurring payment of a fixed amount towards a single
+// destination. Each payment is made by paying the next of a queue of payment
+// requests provided by the payee, allowing them to pull funds from us at a
+// fixed interval while the total spent is capped by a budget.
+type Schedule struct {
+	// ID is the unique identifier of the schedule. It's assigned by the
+	// Store once the schedule is first added.
+	ID uint64
+
+	// Dest is the node all payments of the schedule are sent to.
+	Dest route.Vertex
+
+	// Amt is the amount sent with each payment.
+	Amt lnwire.MilliSatoshi
+
+	// Interval is the time between two consecutive payments.
+	Interval time.Duration
+
+	// MaxFee is the maximum routing fee we're willing to pay for each
+	// individual payment.
+	MaxFee lnwire.MilliSatoshi
+
+	// Budget is the maximum total amount, including routing fees, that
+	// may be spent over the lifetime of the schedule.
+	Budget lnwire.MilliSatoshi
+
+	// Spent is the total amount, including routing fees, spent so far.
+	Spent lnwire.MilliSatoshi
+
+	// NextPayment is the time at which the next payment is due.
+	NextPayment time.Time
+
+	// PaymentRequests is the queue of payment requests that are yet to be
+	// paid, the first of which will be paid once the next payment is due.
+	PaymentRequests []string
+
+	// NumPayments is the number of successful payments made so far.
+	NumPayments uint32
+
+	// NumFailures is the number of payment attempts that have failed in a
+	// row. It's reset to zero after each successful payment.
+	NumFailures uint32
+
+	// LastError is the reason of the last failed payment attempt, if any.
+	LastError string
+
+	// State is the current state of the schedule.
+	State State
+}
+
+// Copy returns a deep copy of the schedule.
+func (s *Schedule) Copy() *Schedule {
+	c := *s
+	c.PaymentRequests = append([]string(nil), s.PaymentRequests...)
+	return &c
+}
+
+// nextPaymentCost returns the maximum amount the next payment can cost us,
+// taking the routing fee limit into account.
+func (s *Schedule) nextPaymentCost() lnwire.MilliSatoshi {
+	return s.Amt + s.MaxFee
+}
+
+// popPaymentRequest removes the first occurrence of the given payment request
+// from the queue.
+func (s *Schedule) popPaymentRequest(payReq string) {
+	for i, p := range s.PaymentRequests {
+		if p != payReq {
+			continue
+		}
+
+		s.PaymentRequests = append(
+			s.PaymentRequests[:i], s.PaymentRequests[i+1:]...,
+		)
+		return
+	}
+}
+
+// serializeSchedule writes the serialized schedule, excluding its ID, to the
+// passed writer.
+func serializeSchedule(w io.Writer, s *Schedule) error {
+	if _, err := w.Write(s.Dest[:]); err != nil {
+		return err
+	}
+
+	err := channeldb.WriteElements(w,
+		s.Amt, uint64(s.Interval), s.MaxFee, s.Budget, s.Spent,
+		uint64(s.NextPayment.UnixNano()), s.NumPayments, s.NumFailures,
+		[]byte(s.LastError), uint16(s.State),
+		uint32(len(s.PaymentRequests)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, payReq := range s.PaymentRequests {
+		if err := channeldb.WriteElement(w, []byte(payReq)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeSchedule reads a schedule serialized by serializeSchedule from
+// the passed reader.
+func deserializeSchedule(r io.Reader) (*Schedule, error) {
+	var (
+		s           Schedule
+		interval    uint64
+		nextPayment uint64
+		lastError   []byte
+		state       uint16
+		numPayReqs  uint32
+	)
+
+	if _, err := io.ReadFull(r, s.Dest[:]); err != nil {
+		return nil, err
+	}
+
+	err := channeldb.ReadElements(r,
+		&s.Amt, &interval, &s.MaxFee, &s.Budget, &s.Spent,
+		&nextPayment, &s.NumPayments, &s.NumFailures, &lastError,
+		&state, &numPayReqs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Interval = time.Duration(interval)
+	s.NextPayment = time.Unix(0, int64(nextPayment))
+	s.LastError = string(lastError)
+	s.State = State(state)
+
+	for i := uint32(0); i < numPayReqs; i++ {
+		var payReq []byte
+		if err := channeldb.ReadElement(r, &payReq); err != nil {
+			return nil, err
+		}
+
+		s.PaymentRequests = append(s.PaymentRequests, string(payReq))
+	}
+
+	return &s, nil
+}
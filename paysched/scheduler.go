@@ -0,0 +1,523 @@
+package paysched
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/lnd/htlcswitch"
+	"github.com/litecoinfinance/lnd/routing"
+	"github.com/litecoinfinance/lnd/routing/route"
+	"github.com/litecoinfinance/lnd/subscribe"
+	"github.com/litecoinfinance/lnd/ticker"
+	"github.com/litecoinfinance/lnd/zpay32"
+)
+
+const (
+	// DefaultTickInterval is the default interval at which the scheduler
+	// checks for payments that are due.
+	DefaultTickInterval = time.Minute
+
+	// DefaultMaxConsecutiveFailures is the default number of payment
+	// attempts of a schedule that may fail in a row before the schedule
+	// is disabled.
+	DefaultMaxConsecutiveFailures = 3
+
+	// DefaultRetryDelay is the default time we'll wait before retrying a
+	// failed payment.
+	DefaultRetryDelay = 10 * time.Minute
+
+	// MinInterval is the minimum interval allowed between two payments
+	// of the same schedule.
+	MinInterval = time.Minute
+)
+
+var (
+	// ErrBudgetExhausted is used to signal that a payment wasn't made as
+	// it would have caused the schedule to go over its budget.
+	ErrBudgetExhausted = errors.New("payment schedule budget exhausted")
+
+	// ErrNoPaymentRequests is used to signal that a payment wasn't made as
+	// the payee hasn't provided us with any payment requests to pay.
+	ErrNoPaymentRequests = errors.New("no payment requests left to pay")
+
+	// ErrPaymentRequestExpired is used to signal that the next payment
+	// request of a schedule expired before it could be paid.
+	ErrPaymentRequestExpired = errors.New("payment request expired")
+)
+
+// Config houses the dependencies of the Scheduler.
+type Config struct {
+	// Store persists the set of payment schedules.
+	Store Store
+
+	// DecodePayReq decodes a payment request for the chain we're
+	// currently operating on.
+	DecodePayReq func(payReq string) (*zpay32.Invoice, error)
+
+	// SendPayment attempts to route the payment through the network,
+	// blocking until it either succeeds or fails.
+	SendPayment func(*routing.LightningPayment) ([32]byte, *route.Route,
+		error)
+
+	// Ticker is used to periodically check for payments that are due.
+	Ticker ticker.Ticker
+
+	// MaxConsecutiveFailures is the number of payment attempts of a
+	// schedule that may fail in a row before the schedule is disabled.
+	MaxConsecutiveFailures uint32
+
+	// RetryDelay is the time we'll wait before retrying a failed payment.
+	RetryDelay time.Duration
+}
+
+// Alert is sent to all alert subscribers whenever a scheduled payment could
+// not be made.
+type Alert struct {
+	// Schedule is the state of the schedule after the failed payment
+	// attempt. If the schedule was disabled as a result, this is
+	// reflected in its State.
+	Schedule *Schedule
+
+	// Err is the reason the payment could not be made.
+	Err error
+}
+
+// Scheduler executes recurring payments on behalf of the user. Each payment
+// is subject to the routing fee limit and the total budget of its schedule.
+// Whenever a payment fails, an Alert is sent to all subscribers, and the
+// schedule is disabled once too many payments fail in a row.
+type Scheduler struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *Config
+
+	ntfnServer *subscribe.Server
+
+	// mu serializes all modifications of the schedules within the store.
+	mu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new payment scheduler backed by the given config.
+func New(cfg *Config) *Scheduler {
+	return &Scheduler{
+		cfg:        cfg,
+		ntfnServer: subscribe.NewServer(),
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine responsible for executing due payments.
+func (s *Scheduler) Start() error {
+	if !atomic.CompareAndSwapUint32(&s.started, 0, 1) {
+		return nil
+	}
+
+	log.Info("Payment scheduler starting")
+
+	if err := s.ntfnServer.Start(); err != nil {
+		return err
+	}
+
+	s.cfg.Ticker.Resume()
+
+	s.wg.Add(1)
+	go s.scheduleHandler()
+
+	return nil
+}
+
+// Stop signals the scheduler for a graceful shutdown, waiting for any
+// in-flight payment to complete.
+func (s *Scheduler) Stop() error {
+	if !atomic.CompareAndSwapUint32(&s.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Info("Payment scheduler shutting down")
+
+	close(s.quit)
+	s.cfg.Ticker.Stop()
+	s.wg.Wait()
+
+	return s.ntfnServer.Stop()
+}
+
+// SubscribeAlerts returns a subscribe.Client that will receive an Alert each
+// time a scheduled payment could not be made.
+func (s *Scheduler) SubscribeAlerts() (*subscribe.Client, error) {
+	return s.ntfnServer.Subscribe()
+}
+
+// AddSchedule validates and persists a new schedule. The destination and
+// amount of the schedule may be omitted, in which case they're taken from
+// its first payment request. The stored schedule, along with its newly
+// assigned ID, is returned.
+func (s *Scheduler) AddSchedule(schedule *Schedule) (*Schedule, error) {
+	schedule = schedule.Copy()
+
+	if len(schedule.PaymentRequests) == 0 {
+		return nil, errors.New("at least one payment request must be " +
+			"provided")
+	}
+
+	// If the destination or amount weren't set, then we'll populate them
+	// from the first payment request.
+	invoice, err := s.cfg.DecodePayReq(schedule.PaymentRequests[0])
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode payment request: %v",
+			err)
+	}
+	if schedule.Dest == (route.Vertex{}) && invoice.Destination != nil {
+		schedule.Dest = route.NewVertex(invoice.Destination)
+	}
+	if schedule.Amt == 0 && invoice.MilliSat != nil {
+		schedule.Amt = *invoice.MilliSat
+	}
+
+	switch {
+	case schedule.Amt == 0:
+		return nil, errors.New("payment amount must be specified")
+
+	case schedule.Interval < MinInterval:
+		return nil, fmt.Errorf("payment interval must be at least %v",
+			MinInterval)
+
+	case schedule.Budget < schedule.nextPaymentCost():
+		return nil, errors.New("budget must cover at least a single " +
+			"payment including fees")
+	}
+
+	for _, payReq := range schedule.PaymentRequests {
+		if err := s.validatePayReq(schedule, payReq); err != nil {
+			return nil, err
+		}
+	}
+
+	schedule.Spent = 0
+	schedule.NumPayments = 0
+	schedule.NumFailures = 0
+	schedule.LastError = ""
+	schedule.State = StateActive
+	if schedule.NextPayment.IsZero() {
+		schedule.NextPayment = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.cfg.Store.AddSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Added payment schedule %d paying %v to %x every %v",
+		schedule.ID, schedule.Amt, schedule.Dest[:], schedule.Interval)
+
+	return schedule.Copy(), nil
+}
+
+// AddPaymentRequests appends the given payment requests to the queue of the
+// target schedule. If the schedule was disabled due to too many consecutive
+// payment failures, it is re-activated.
+func (s *Scheduler) AddPaymentRequests(id uint64,
+	payReqs []string) (*Schedule, error) {
+
+	return s.updateSchedule(id, func(schedule *Schedule) error {
+		for _, payReq := range payReqs {
+			err := s.validatePayReq(schedule, payReq)
+			if err != nil {
+				return err
+			}
+		}
+
+		schedule.PaymentRequests = append(
+			schedule.PaymentRequests, payReqs...,
+		)
+
+		if schedule.State == StateFailed {
+			log.Infof("Re-activating payment schedule %d", id)
+
+			schedule.State = StateActive
+			schedule.NumFailures = 0
+		}
+
+		return nil
+	})
+}
+
+// RemoveSchedule removes the schedule with the given ID. Any payment of the
+// schedule that's currently in-flight won't be cancelled.
+func (s *Scheduler) RemoveSchedule(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.cfg.Store.RemoveSchedule(id); err != nil {
+		return err
+	}
+
+	log.Infof("Removed payment schedule %d", id)
+
+	return nil
+}
+
+// ListSchedules returns all known schedules.
+func (s *Scheduler) ListSchedules() ([]*Schedule, error) {
+	return s.cfg.Store.FetchSchedules()
+}
+
+// validatePayReq ensures the payment request pays the destination and amount
+// of the schedule, and that it hasn't expired yet.
+func (s *Scheduler) validatePayReq(schedule *Schedule, payReq string) error {
+	invoice, err := s.cfg.DecodePayReq(payReq)
+	if err != nil {
+		return fmt.Errorf("unable to decode payment request: %v", err)
+	}
+
+	switch {
+	case invoice.Destination == nil ||
+		route.NewVertex(invoice.Destination) != schedule.Dest:
+
+		return fmt.Errorf("payment request %v doesn't pay the "+
+			"destination of the schedule", payReq)
+
+	case invoice.MilliSat != nil && *invoice.MilliSat != schedule.Amt:
+		return fmt.Errorf("payment request %v has amount %v, expected "+
+			"%v", payReq, *invoice.MilliSat, schedule.Amt)
+
+	case invoiceExpired(invoice):
+		return fmt.Errorf("payment request %v has expired", payReq)
+	}
+
+	return nil
+}
+
+// invoiceExpired returns whether the invoice can no longer be paid.
+func invoiceExpired(invoice *zpay32.Invoice) bool {
+	return time.Now().After(invoice.Timestamp.Add(invoice.Expiry()))
+}
+
+// updateSchedule atomically applies the given modification to the schedule
+// with the given ID, and returns a copy of the updated schedule.
+func (s *Scheduler) updateSchedule(id uint64,
+	modify func(*Schedule) error) (*Schedule, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, err := s.cfg.Store.FetchSchedule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := modify(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.cfg.Store.UpdateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule.Copy(), nil
+}
+
+// scheduleHandler is the main event loop of the scheduler. Each time the
+// ticker fires, all payments that are due are executed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *Scheduler) scheduleHandler() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.cfg.Ticker.Ticks():
+			s.executeDuePayments()
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// executeDuePayments executes the next payment of all active schedules that
+// are due, one at a time.
+func (s *Scheduler) executeDuePayments() {
+	schedules, err := s.cfg.Store.FetchSchedules()
+	if err != nil {
+		log.Errorf("Unable to fetch payment schedules: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, schedule := range schedules {
+		if schedule.State != StateActive ||
+			now.Before(schedule.NextPayment) {
+
+			continue
+		}
+
+		select {
+		case <-s.quit:
+			return
+		default:
+		}
+
+		s.executePayment(schedule)
+	}
+}
+
+// executePayment attempts to make the next payment of the given schedule,
+// recording its outcome.
+func (s *Scheduler) executePayment(schedule *Schedule) {
+	// Before making the payment, we'll ensure that it can't take us over
+	// the budget of the schedule, even if we end up paying the maximum
+	// routing fee.
+	if schedule.Spent+schedule.nextPaymentCost() > schedule.Budget {
+		s.disableBudgetExhausted(schedule.ID)
+		return
+	}
+
+	if len(schedule.PaymentRequests) == 0 {
+		s.recordFailure(schedule.ID, "", ErrNoPaymentRequests)
+		return
+	}
+
+	payReq := schedule.PaymentRequests[0]
+	invoice, err := s.cfg.DecodePayReq(payReq)
+	if err != nil {
+		s.recordFailure(schedule.ID, payReq, err)
+		return
+	}
+	if invoiceExpired(invoice) {
+		s.recordFailure(schedule.ID, payReq, ErrPaymentRequestExpired)
+		return
+	}
+
+	log.Debugf("Executing payment %d of schedule %d", schedule.NumPayments+1,
+		schedule.ID)
+
+	finalCLTVDelta := uint16(invoice.MinFinalCLTVExpiry())
+	payment := &routing.LightningPayment{
+		Target:         schedule.Dest,
+		Amount:         schedule.Amt,
+		FeeLimit:       schedule.MaxFee,
+		PaymentHash:    *invoice.PaymentHash,
+		FinalCLTVDelta: &finalCLTVDelta,
+		RouteHints:     invoice.RouteHints,
+	}
+
+	_, rt, err := s.cfg.SendPayment(payment)
+	switch {
+
+	// If the payment request was paid already, then we'll drop it from
+	// the queue to avoid retrying it, but still count it as a failure as
+	// it's likely the payee handed it out twice.
+	case err == htlcswitch.ErrAlreadyPaid:
+		s.recordFailure(schedule.ID, payReq, err)
+		return
+
+	case err != nil:
+		s.recordFailure(schedule.ID, "", err)
+		return
+	}
+
+	id := schedule.ID
+	schedule, err = s.updateSchedule(id, func(sched *Schedule) error {
+		sched.Spent += sched.Amt + rt.TotalFees
+		sched.NumPayments++
+		sched.NumFailures = 0
+		sched.LastError = ""
+		sched.popPaymentRequest(payReq)
+
+		// We'll schedule the next payment at the original cadence,
+		// skipping over any payments we may have missed while offline.
+		now := time.Now()
+		for !sched.NextPayment.After(now) {
+			sched.NextPayment = sched.NextPayment.Add(sched.Interval)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Unable to record payment of schedule %d: %v", id,
+			err)
+		return
+	}
+
+	log.Infof("Payment %d of schedule %d succeeded, spent %v of %v budget",
+		schedule.NumPayments, schedule.ID, schedule.Spent,
+		schedule.Budget)
+}
+
+// disableBudgetExhausted disables the schedule with the given ID as its next
+// payment would take it over budget.
+func (s *Scheduler) disableBudgetExhausted(id uint64) {
+	schedule, err := s.updateSchedule(id, func(sched *Schedule) error {
+		sched.State = StateBudgetExhausted
+		sched.LastError = ErrBudgetExhausted.Error()
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Unable to disable payment schedule %d: %v", id, err)
+		return
+	}
+
+	log.Warnf("Payment schedule %d disabled, spent %v of %v budget", id,
+		schedule.Spent, schedule.Budget)
+
+	s.sendAlert(schedule, ErrBudgetExhausted)
+}
+
+// recordFailure records a failed payment attempt of the schedule with the
+// given ID, disabling it once it has failed too many times in a row. If a
+// payment request is given, it's removed from the queue of the schedule as it
+// can no longer be paid.
+func (s *Scheduler) recordFailure(id uint64, payReq string, payErr error) {
+	schedule, err := s.updateSchedule(id, func(sched *Schedule) error {
+		sched.NumFailures++
+		sched.LastError = payErr.Error()
+		if payReq != "" {
+			sched.popPaymentRequest(payReq)
+		}
+
+		if sched.NumFailures >= s.cfg.MaxConsecutiveFailures {
+			sched.State = StateFailed
+			return nil
+		}
+
+		sched.NextPayment = time.Now().Add(s.cfg.RetryDelay)
+
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Unable to record payment failure of schedule %d: "+
+			"%v", id, err)
+		return
+	}
+
+	if schedule.State == StateFailed {
+		log.Errorf("Payment schedule %d disabled after %d consecutive "+
+			"failures: %v", id, schedule.NumFailures, payErr)
+	} else {
+		log.Warnf("Payment of schedule %d failed, retrying at %v: %v",
+			id, schedule.NextPayment, payErr)
+	}
+
+	s.sendAlert(schedule, payErr)
+}
+
+// sendAlert notifies all alert subscribers of the failed payment.
+func (s *Scheduler) sendAlert(schedule *Schedule, err error) {
+	alert := &Alert{
+		Schedule: schedule,
+		Err:      err,
+	}
+	if err := s.ntfnServer.SendUpdate(alert); err != nil {
+		log.Warnf("Unable to send payment schedule alert: %v", err)
+	}
+}
@@ -0,0 +1,410 @@
+package paysched
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/chaincfg"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing"
+	"github.com/litecoinfinance/lnd/routing/route"
+	"github.com/litecoinfinance/lnd/ticker"
+	"github.com/litecoinfinance/lnd/zpay32"
+)
+
+const testAmt = lnwire.MilliSatoshi(10000)
+
+type paymentResult struct {
+	fee lnwire.MilliSatoshi
+	err error
+}
+
+type schedulerTestContext struct {
+	t *testing.T
+
+	scheduler *Scheduler
+	ticker    *ticker.Force
+
+	destKey  *btcec.PublicKey
+	invoices map[string]*zpay32.Invoice
+
+	payments chan *routing.LightningPayment
+	results  chan paymentResult
+
+	alerts <-chan interface{}
+
+	cleanUp func()
+}
+
+func newSchedulerTestContext(t *testing.T) *schedulerTestContext {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to open channel db: %v", err)
+	}
+
+	store, err := NewStore(cdb)
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to create store: %v", err)
+	}
+
+	destPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	ctx := &schedulerTestContext{
+		t:        t,
+		ticker:   ticker.NewForce(DefaultTickInterval),
+		destKey:  destPriv.PubKey(),
+		invoices: make(map[string]*zpay32.Invoice),
+		payments: make(chan *routing.LightningPayment),
+		results:  make(chan paymentResult),
+	}
+
+	ctx.scheduler = New(&Config{
+		Store: store,
+		DecodePayReq: func(payReq string) (*zpay32.Invoice, error) {
+			invoice, ok := ctx.invoices[payReq]
+			if !ok {
+				return nil, errors.New("unknown payment request")
+			}
+			return invoice, nil
+		},
+		SendPayment: func(payment *routing.LightningPayment) ([32]byte,
+			*route.Route, error) {
+
+			ctx.payments <- payment
+			result := <-ctx.results
+			if result.err != nil {
+				return [32]byte{}, nil, result.err
+			}
+
+			return [32]byte{}, &route.Route{TotalFees: result.fee}, nil
+		},
+		Ticker:                 ctx.ticker,
+		MaxConsecutiveFailures: 2,
+	})
+	if err := ctx.scheduler.Start(); err != nil {
+		cleanUp()
+		t.Fatalf("unable to start scheduler: %v", err)
+	}
+
+	alertClient, err := ctx.scheduler.SubscribeAlerts()
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to subscribe to alerts: %v", err)
+	}
+	ctx.alerts = alertClient.Updates()
+
+	ctx.cleanUp = func() {
+		alertClient.Cancel()
+		ctx.scheduler.Stop()
+		cleanUp()
+	}
+
+	return ctx
+}
+
+// newPayReq creates a new payment request towards the destination of the
+// test context.
+func (c *schedulerTestContext) newPayReq(amt lnwire.MilliSatoshi) string {
+	var hash [32]byte
+	payReq := fmt.Sprintf("lnreq%d", len(c.invoices))
+	copy(hash[:], payReq)
+
+	invoice, err := zpay32.NewInvoice(
+		&chaincfg.MainNetParams, hash, time.Now(),
+		zpay32.Amount(amt), zpay32.Destination(c.destKey),
+		zpay32.Description(payReq),
+	)
+	if err != nil {
+		c.t.Fatalf("unable to create invoice: %v", err)
+	}
+
+	c.invoices[payReq] = invoice
+
+	return payReq
+}
+
+// tick forces the scheduler to execute all due payments.
+func (c *schedulerTestContext) tick() {
+	select {
+	case c.ticker.Force <- time.Now():
+	case <-time.After(5 * time.Second):
+		c.t.Fatalf("scheduler didn't accept tick")
+	}
+}
+
+// assertPayment asserts that a payment for the given payment request is
+// attempted, and completes it with the given result.
+func (c *schedulerTestContext) assertPayment(payReq string,
+	result paymentResult) {
+
+	c.t.Helper()
+
+	select {
+	case payment := <-c.payments:
+		invoice := c.invoices[payReq]
+		if payment.PaymentHash != *invoice.PaymentHash {
+			c.t.Fatalf("expected payment of %v", payReq)
+		}
+		if payment.Amount != testAmt {
+			c.t.Fatalf("expected amount %v, got %v", testAmt,
+				payment.Amount)
+		}
+
+	case <-time.After(5 * time.Second):
+		c.t.Fatalf("expected payment of %v", payReq)
+	}
+
+	c.results <- result
+}
+
+// assertNoPayment asserts that no payment is attempted during the next tick.
+func (c *schedulerTestContext) assertNoPayment() {
+	c.t.Helper()
+
+	// The tick is only accepted once the previous one has been fully
+	// processed, so we'll tick twice to ensure no payment was made.
+	c.tick()
+	c.tick()
+
+	select {
+	case <-c.payments:
+		c.t.Fatalf("unexpected payment")
+	default:
+	}
+}
+
+// assertAlert asserts that an alert is sent for the schedule with the given
+// state and error.
+func (c *schedulerTestContext) assertAlert(state State, err error) {
+	c.t.Helper()
+
+	select {
+	case update := <-c.alerts:
+		alert := update.(*Alert)
+		if alert.Schedule.State != state {
+			c.t.Fatalf("expected state %v, got %v", state,
+				alert.Schedule.State)
+		}
+		if err != nil && alert.Err != err {
+			c.t.Fatalf("expected error %v, got %v", err, alert.Err)
+		}
+
+	case <-time.After(5 * time.Second):
+		c.t.Fatalf("expected alert")
+	}
+}
+
+// makeDue makes the next payment of the schedule due immediately.
+func (c *schedulerTestContext) makeDue(id uint64) {
+	c.t.Helper()
+
+	_, err := c.scheduler.updateSchedule(id, func(s *Schedule) error {
+		s.NextPayment = time.Now().Add(-time.Second)
+		return nil
+	})
+	if err != nil {
+		c.t.Fatalf("unable to update schedule: %v", err)
+	}
+}
+
+// TestSchedulerAddSchedule asserts that invalid schedules are rejected.
+func TestSchedulerAddSchedule(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSchedulerTestContext(t)
+	defer ctx.cleanUp()
+
+	otherPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+	}{
+		{
+			name: "no payment requests",
+			schedule: &Schedule{
+				Interval: time.Hour,
+				Budget:   testAmt,
+			},
+		},
+		{
+			name: "interval too short",
+			schedule: &Schedule{
+				Interval: time.Second,
+				Budget:   testAmt,
+				PaymentRequests: []string{
+					ctx.newPayReq(testAmt),
+				},
+			},
+		},
+		{
+			name: "budget too small",
+			schedule: &Schedule{
+				Interval: time.Hour,
+				MaxFee:   1,
+				Budget:   testAmt,
+				PaymentRequests: []string{
+					ctx.newPayReq(testAmt),
+				},
+			},
+		},
+		{
+			name: "amount mismatch",
+			schedule: &Schedule{
+				Interval: time.Hour,
+				Budget:   testAmt * 2,
+				PaymentRequests: []string{
+					ctx.newPayReq(testAmt),
+					ctx.newPayReq(testAmt * 2),
+				},
+			},
+		},
+		{
+			name: "destination mismatch",
+			schedule: &Schedule{
+				Dest:     route.NewVertex(otherPriv.PubKey()),
+				Interval: time.Hour,
+				Budget:   testAmt,
+				PaymentRequests: []string{
+					ctx.newPayReq(testAmt),
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		_, err := ctx.scheduler.AddSchedule(test.schedule)
+		if err == nil {
+			t.Fatalf("%v: expected schedule to be rejected",
+				test.name)
+		}
+	}
+
+	schedules, err := ctx.scheduler.ListSchedules()
+	if err != nil {
+		t.Fatalf("unable to list schedules: %v", err)
+	}
+	if len(schedules) != 0 {
+		t.Fatalf("expected no schedules, found %d", len(schedules))
+	}
+}
+
+// TestSchedulerPayments asserts that due payments are executed, and that a
+// schedule is disabled once its budget is exhausted.
+func TestSchedulerPayments(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSchedulerTestContext(t)
+	defer ctx.cleanUp()
+
+	payReq1 := ctx.newPayReq(testAmt)
+	payReq2 := ctx.newPayReq(testAmt)
+	schedule, err := ctx.scheduler.AddSchedule(&Schedule{
+		Interval:        time.Hour,
+		MaxFee:          100,
+		Budget:          2*testAmt + 100,
+		PaymentRequests: []string{payReq1, payReq2},
+	})
+	if err != nil {
+		t.Fatalf("unable to add schedule: %v", err)
+	}
+	if schedule.Amt != testAmt {
+		t.Fatalf("expected amount %v, got %v", testAmt, schedule.Amt)
+	}
+
+	// The first payment is due right away.
+	ctx.tick()
+	ctx.assertPayment(payReq1, paymentResult{fee: 50})
+
+	// The next payment is only due after the interval, so nothing should
+	// happen on the following ticks.
+	ctx.assertNoPayment()
+
+	schedules, err := ctx.scheduler.ListSchedules()
+	if err != nil {
+		t.Fatalf("unable to list schedules: %v", err)
+	}
+	schedule = schedules[0]
+	if schedule.Spent != testAmt+50 || schedule.NumPayments != 1 {
+		t.Fatalf("unexpected schedule after payment: spent=%v, "+
+			"payments=%d", schedule.Spent, schedule.NumPayments)
+	}
+	if !schedule.NextPayment.After(time.Now()) {
+		t.Fatalf("expected next payment in the future")
+	}
+
+	// The remaining budget doesn't cover another payment at the maximum
+	// fee, so the schedule should be disabled once the next one is due.
+	ctx.makeDue(schedule.ID)
+	ctx.tick()
+	ctx.assertAlert(StateBudgetExhausted, ErrBudgetExhausted)
+	ctx.assertNoPayment()
+}
+
+// TestSchedulerFailures asserts that failed payments are alerted, and that
+// a schedule is disabled after too many consecutive failures until new
+// payment requests are added.
+func TestSchedulerFailures(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSchedulerTestContext(t)
+	defer ctx.cleanUp()
+
+	payReq := ctx.newPayReq(testAmt)
+	schedule, err := ctx.scheduler.AddSchedule(&Schedule{
+		Interval:        time.Hour,
+		Budget:          10 * testAmt,
+		PaymentRequests: []string{payReq},
+	})
+	if err != nil {
+		t.Fatalf("unable to add schedule: %v", err)
+	}
+
+	// The first attempt fails, and should be retried.
+	routeErr := errors.New("unable to route payment")
+	ctx.tick()
+	ctx.assertPayment(payReq, paymentResult{err: routeErr})
+	ctx.assertAlert(StateActive, routeErr)
+
+	// The second failure reaches the failure limit, disabling the
+	// schedule.
+	ctx.makeDue(schedule.ID)
+	ctx.tick()
+	ctx.assertPayment(payReq, paymentResult{err: routeErr})
+	ctx.assertAlert(StateFailed, routeErr)
+
+	ctx.makeDue(schedule.ID)
+	ctx.assertNoPayment()
+
+	// Adding a new payment request should re-activate the schedule, which
+	// should then continue with the payment request that's still queued.
+	schedule, err = ctx.scheduler.AddPaymentRequests(
+		schedule.ID, []string{ctx.newPayReq(testAmt)},
+	)
+	if err != nil {
+		t.Fatalf("unable to add payment requests: %v", err)
+	}
+	if schedule.State != StateActive || len(schedule.PaymentRequests) != 2 {
+		t.Fatalf("expected active schedule with two payment requests")
+	}
+
+	ctx.tick()
+	ctx.assertPayment(payReq, paymentResult{})
+
+	// Finally, removing the schedule should prevent any further payments.
+	if err := ctx.scheduler.RemoveSchedule(schedule.ID); err != nil {
+		t.Fatalf("unable to remove schedule: %v", err)
+	}
+	ctx.assertNoPayment()
+}
@@ -0,0 +1,196 @@
+package paysched
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+)
+
+var (
+	// schedulesBucketKey is the key of the top-level bucket that stores
+	// all payment schedules.
+	//
+	// maps: scheduleID -> serialized schedule
+	schedulesBucketKey = []byte("payment-schedules")
+
+	// ErrScheduleNotFound is returned when a schedule with the target ID
+	// doesn't exist.
+	ErrScheduleNotFound = errors.New("payment schedule not found")
+
+	byteOrder = binary.BigEndian
+)
+
+// Store persists the set of payment schedules.
+type Store interface {
+	// AddSchedule adds a new schedule to the store, assigning it a unique
+	// ID.
+	AddSchedule(s *Schedule) error
+
+	// UpdateSchedule overwrites an existing schedule with the given one.
+	// ErrScheduleNotFound is returned if the schedule doesn't exist.
+	UpdateSchedule(s *Schedule) error
+
+	// RemoveSchedule removes the schedule with the given ID.
+	// ErrScheduleNotFound is returned if the schedule doesn't exist.
+	RemoveSchedule(id uint64) error
+
+	// FetchSchedule returns the schedule with the given ID.
+	FetchSchedule(id uint64) (*Schedule, error)
+
+	// FetchSchedules returns all schedules known to the store, ordered by
+	// their ID.
+	FetchSchedules() ([]*Schedule, error)
+}
+
+// scheduleStore is an implementation of the Store interface backed by the
+// channel database.
+type scheduleStore struct {
+	db *channeldb.DB
+}
+
+// A compile-time check to ensure scheduleStore implements the Store interface.
+var _ Store = (*scheduleStore)(nil)
+
+// NewStore returns a new Store backed by the given channel database.
+func NewStore(db *channeldb.DB) (Store, error) {
+	err := db.Update(func(tx kvdb.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(schedulesBucketKey)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &scheduleStore{
+		db: db,
+	}, nil
+}
+
+// AddSchedule adds a new schedule to the store, assigning it a unique ID.
+//
+// NOTE: Part of the Store interface.
+func (s *scheduleStore) AddSchedule(schedule *Schedule) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		schedules := tx.Bucket(schedulesBucketKey)
+
+		id, err := schedules.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		if err := putSchedule(schedules, id, schedule); err != nil {
+			return err
+		}
+
+		schedule.ID = id
+
+		return nil
+	})
+}
+
+// UpdateSchedule overwrites an existing schedule with the given one.
+//
+// NOTE: Part of the Store interface.
+func (s *scheduleStore) UpdateSchedule(schedule *Schedule) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		schedules := tx.Bucket(schedulesBucketKey)
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], schedule.ID)
+		if schedules.Get(k[:]) == nil {
+			return ErrScheduleNotFound
+		}
+
+		return putSchedule(schedules, schedule.ID, schedule)
+	})
+}
+
+// RemoveSchedule removes the schedule with the given ID.
+//
+// NOTE: Part of the Store interface.
+func (s *scheduleStore) RemoveSchedule(id uint64) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		schedules := tx.Bucket(schedulesBucketKey)
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], id)
+		if schedules.Get(k[:]) == nil {
+			return ErrScheduleNotFound
+		}
+
+		return schedules.Delete(k[:])
+	})
+}
+
+// FetchSchedule returns the schedule with the given ID.
+//
+// NOTE: Part of the Store interface.
+func (s *scheduleStore) FetchSchedule(id uint64) (*Schedule, error) {
+	var schedule *Schedule
+	err := s.db.View(func(tx kvdb.Tx) error {
+		schedules := tx.Bucket(schedulesBucketKey)
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], id)
+		v := schedules.Get(k[:])
+		if v == nil {
+			return ErrScheduleNotFound
+		}
+
+		var err error
+		schedule, err = deserializeSchedule(bytes.NewReader(v))
+		if err != nil {
+			return err
+		}
+		schedule.ID = id
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// FetchSchedules returns all schedules known to the store, ordered by their
+// ID.
+//
+// NOTE: Part of the Store interface.
+func (s *scheduleStore) FetchSchedules() ([]*Schedule, error) {
+	var schedules []*Schedule
+	err := s.db.View(func(tx kvdb.Tx) error {
+		return tx.Bucket(schedulesBucketKey).ForEach(func(k, v []byte) error {
+			schedule, err := deserializeSchedule(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			schedule.ID = byteOrder.Uint64(k)
+
+			schedules = append(schedules, schedule)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// putSchedule serializes the schedule and stores it under the given ID.
+func putSchedule(schedules kvdb.Bucket, id uint64, schedule *Schedule) error {
+	var b bytes.Buffer
+	if err := serializeSchedule(&b, schedule); err != nil {
+		return err
+	}
+
+	var k [8]byte
+	byteOrder.PutUint64(k[:], id)
+
+	return schedules.Put(k[:], b.Bytes())
+}
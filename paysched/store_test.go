@@ -0,0 +1,120 @@
+package paysched
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/lnd/channeldb"
+)
+
+// makeTestDB creates a new instance of the ChannelDB for testing purposes. A
+// callback which cleans up the created temporary directories is also returned
+// and intended to be executed after the test completes.
+func makeTestDB() (*channeldb.DB, func(), error) {
+	tempDirName, err := ioutil.TempDir("", "paysched")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cdb, err := channeldb.Open(tempDirName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanUp := func() {
+		cdb.Close()
+		os.RemoveAll(tempDirName)
+	}
+
+	return cdb, cleanUp, nil
+}
+
+// TestStore asserts that schedules can be added, updated and removed from the
+// store.
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to open channel db: %v", err)
+	}
+	defer cleanUp()
+
+	store, err := NewStore(cdb)
+	if err != nil {
+		t.Fatalf("unable to create store: %v", err)
+	}
+
+	schedule := &Schedule{
+		Dest:            [33]byte{0x02, 0x01},
+		Amt:             1000,
+		Interval:        time.Hour,
+		MaxFee:          10,
+		Budget:          100000,
+		NextPayment:     time.Unix(0, 1000),
+		PaymentRequests: []string{"lnreq1", "lnreq2"},
+	}
+	if err := store.AddSchedule(schedule); err != nil {
+		t.Fatalf("unable to add schedule: %v", err)
+	}
+	if schedule.ID == 0 {
+		t.Fatalf("expected schedule to be assigned an ID")
+	}
+
+	assertSchedules := func(expected ...*Schedule) {
+		t.Helper()
+
+		schedules, err := store.FetchSchedules()
+		if err != nil {
+			t.Fatalf("unable to fetch schedules: %v", err)
+		}
+		if !reflect.DeepEqual(schedules, expected) {
+			t.Fatalf("expected schedules %v, got %v",
+				spew.Sdump(expected), spew.Sdump(schedules))
+		}
+	}
+	assertSchedules(schedule)
+
+	// Update the schedule and ensure the changes are persisted.
+	schedule.Spent = 1010
+	schedule.NumPayments = 1
+	schedule.NumFailures = 1
+	schedule.LastError = "unable to route payment"
+	schedule.State = StateFailed
+	schedule.PaymentRequests = []string{"lnreq2"}
+	if err := store.UpdateSchedule(schedule); err != nil {
+		t.Fatalf("unable to update schedule: %v", err)
+	}
+	assertSchedules(schedule)
+
+	fetched, err := store.FetchSchedule(schedule.ID)
+	if err != nil {
+		t.Fatalf("unable to fetch schedule: %v", err)
+	}
+	if !reflect.DeepEqual(fetched, schedule) {
+		t.Fatalf("expected schedule %v, got %v", spew.Sdump(schedule),
+			spew.Sdump(fetched))
+	}
+
+	// Finally, remove the schedule. Any further operations on it should
+	// fail.
+	if err := store.RemoveSchedule(schedule.ID); err != nil {
+		t.Fatalf("unable to remove schedule: %v", err)
+	}
+	assertSchedules()
+
+	if err := store.RemoveSchedule(schedule.ID); err != ErrScheduleNotFound {
+		t.Fatalf("expected ErrScheduleNotFound, got %v", err)
+	}
+	if err := store.UpdateSchedule(schedule); err != ErrScheduleNotFound {
+		t.Fatalf("expected ErrScheduleNotFound, got %v", err)
+	}
+	_, err = store.FetchSchedule(schedule.ID)
+	if err != ErrScheduleNotFound {
+		t.Fatalf("expected ErrScheduleNotFound, got %v", err)
+	}
+}
@@ -10,12 +10,12 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/davecgh/go-spew/spew"
 	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/connmgr"
 	"github.com/litecoinfinance/btcd/txscript"
 	"github.com/litecoinfinance/btcd/wire"
-	"github.com/davecgh/go-spew/spew"
 
 	"github.com/litecoinfinance/lnd/brontide"
 	"github.com/litecoinfinance/lnd/buffer"
@@ -424,6 +424,14 @@ func (p *peer) QuitSignal() <-chan struct{} {
 	return p.quit
 }
 
+// RemoteLocalFeatures returns the local feature vector advertised by the
+// remote peer within its init message.
+//
+// NOTE: Part of the lnpeer.Peer interface.
+func (p *peer) RemoteLocalFeatures() *lnwire.FeatureVector {
+	return p.remoteLocalFeatures
+}
+
 // loadActiveChannels creates indexes within the peer for tracking all active
 // channels returned by the database.
 func (p *peer) loadActiveChannels(chans []*channeldb.OpenChannel) error {
@@ -454,6 +462,15 @@ func (p *peer) loadActiveChannels(chans []*channeldb.OpenChannel) error {
 			peerLog.Warnf("ChannelPoint(%v) has status %v, won't "+
 				"start.", chanPoint, dbChan.ChanStatus())
 			continue
+
+		// A channel being spliced is resumed once its splice
+		// transaction confirms, until then the funding manager
+		// resumes the splice itself.
+		case dbChan.HasChanStatus(channeldb.ChanStatusSplicing):
+			peerLog.Infof("ChannelPoint(%v) is being spliced, "+
+				"won't start.", chanPoint)
+			p.server.fundingMgr.processSpliceResume(dbChan, p)
+			continue
 		}
 
 		// Also skip adding any channel marked as `failed` for this
@@ -571,6 +588,11 @@ func (p *peer) addLink(chanPoint *wire.OutPoint,
 		}
 	}
 
+	var auditLog *channeldb.ChannelAuditLog
+	if cfg.ChanAuditLog {
+		auditLog = p.server.chanDB.ChannelAuditLog()
+	}
+
 	linkCfg := htlcswitch.ChannelLinkConfig{
 		Peer:                   p,
 		DecodeHopIterators:     p.server.sphinx.DecodeHopIterators,
@@ -591,16 +613,21 @@ func (p *peer) addLink(chanPoint *wire.OutPoint,
 				*chanPoint, signals,
 			)
 		},
-		OnChannelFailure:        onChannelFailure,
-		SyncStates:              syncStates,
-		BatchTicker:             ticker.New(50 * time.Millisecond),
-		FwdPkgGCTicker:          ticker.New(time.Minute),
-		BatchSize:               10,
-		UnsafeReplay:            cfg.UnsafeReplay,
-		MinFeeUpdateTimeout:     htlcswitch.DefaultMinLinkFeeUpdateTimeout,
-		MaxFeeUpdateTimeout:     htlcswitch.DefaultMaxLinkFeeUpdateTimeout,
-		FinalCltvRejectDelta:    p.finalCltvRejectDelta,
-		OutgoingCltvRejectDelta: p.outgoingCltvRejectDelta,
+		OnChannelFailure:           onChannelFailure,
+		SyncStates:                 syncStates,
+		BatchTicker:                ticker.New(50 * time.Millisecond),
+		FwdPkgGCTicker:             ticker.New(time.Minute),
+		BatchSize:                  10,
+		UnsafeReplay:               cfg.UnsafeReplay,
+		MinFeeUpdateTimeout:        htlcswitch.DefaultMinLinkFeeUpdateTimeout,
+		MaxFeeUpdateTimeout:        htlcswitch.DefaultMaxLinkFeeUpdateTimeout,
+		MaxRemoteFeeRateMultiplier: cfg.MaxRemoteFeeRateMultiplier,
+		CommitFeeBounds:            commitFeeBounds(cfg.CommitFee),
+		FinalCltvRejectDelta:       p.finalCltvRejectDelta,
+		OutgoingCltvRejectDelta:    p.outgoingCltvRejectDelta,
+		LatencyTracker:             p.server.htlcSwitch.LatencyTracker(),
+		ReportForwardHoldTime:      p.server.reportForwardHoldTime,
+		AuditLog:                   auditLog,
 	}
 
 	link := htlcswitch.NewChannelLink(linkCfg, lnChan)
@@ -1090,6 +1117,18 @@ out:
 			p.server.fundingMgr.processFundingSigned(msg, p)
 		case *lnwire.FundingLocked:
 			p.server.fundingMgr.processFundingLocked(msg, p)
+		case *lnwire.FundingContribution:
+			p.server.fundingMgr.processFundingContribution(msg, p)
+		case *lnwire.FundingWitnesses:
+			p.server.fundingMgr.processFundingWitnesses(msg, p)
+		case *lnwire.SpliceInit:
+			p.server.fundingMgr.processSpliceInit(msg, p)
+		case *lnwire.SpliceAck:
+			p.server.fundingMgr.processSpliceAck(msg, p)
+		case *lnwire.SpliceCommit:
+			p.server.fundingMgr.processSpliceCommit(msg, p)
+		case *lnwire.SpliceSigned:
+			p.server.fundingMgr.processSpliceSigned(msg, p)
 
 		case *lnwire.Shutdown:
 			select {
@@ -1126,6 +1165,11 @@ out:
 			case p.server.fundingMgr.IsPendingChannel(msg.ChanID, key):
 				p.server.fundingMgr.processFundingError(msg, key)
 
+			// An error for a channel we're splicing rejects the
+			// splice, rather than failing the channel.
+			case p.server.fundingMgr.IsSplicing(msg.ChanID, key):
+				p.server.fundingMgr.processSpliceError(msg, key)
+
 			// If not we hand the error to the channel link for
 			// this channel.
 			default:
@@ -1240,6 +1284,29 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chan_id=%v, next_point=%x",
 			msg.ChanID, msg.NextPerCommitmentPoint.SerializeCompressed())
 
+	case *lnwire.FundingContribution:
+		return fmt.Sprintf("temp_chan_id=%x, amt=%v, num_inputs=%v, "+
+			"num_change=%v", msg.PendingChannelID[:],
+			msg.FundingAmount, len(msg.Inputs),
+			len(msg.ChangeOutputs))
+
+	case *lnwire.FundingWitnesses:
+		return fmt.Sprintf("chan_id=%v, num_inputs=%v", msg.ChanID,
+			len(msg.InputScripts))
+
+	case *lnwire.SpliceInit:
+		return fmt.Sprintf("chan_id=%v, splice_txid=%v", msg.ChanID,
+			msg.SpliceTx.TxHash())
+
+	case *lnwire.SpliceAck:
+		return fmt.Sprintf("chan_id=%v", msg.ChanID)
+
+	case *lnwire.SpliceCommit:
+		return fmt.Sprintf("chan_id=%v", msg.ChanID)
+
+	case *lnwire.SpliceSigned:
+		return fmt.Sprintf("chan_id=%v", msg.ChanID)
+
 	case *lnwire.Shutdown:
 		return fmt.Sprintf("chan_id=%v, script=%x", msg.ChannelID,
 			msg.Address[:])
@@ -2082,6 +2149,8 @@ func (p *peer) fetchActiveChanCloser(chanID lnwire.ChannelID) (*channelCloser, e
 				unregisterChannel: p.server.htlcSwitch.RemoveLink,
 				broadcastTx:       p.server.cc.wallet.PublishTransaction,
 				disableChannel:    p.server.chanStatusMgr.RequestDisable,
+				closeSigner:       p.server.closeSigner,
+				closeSigTimeout:   cfg.CoopCloseSigTimeout,
 				quit:              p.quit,
 			},
 			deliveryAddr,
@@ -2119,12 +2188,16 @@ func (p *peer) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 	case htlcswitch.CloseRegular:
 		// First, we'll fetch a fresh delivery address that we'll use
 		// to send the funds to in the case of a successful
-		// negotiation.
-		deliveryAddr, err := p.genDeliveryScript()
-		if err != nil {
-			peerLog.Errorf(err.Error())
-			req.Err <- err
-			return
+		// negotiation, unless the caller specified one.
+		deliveryAddr := req.DeliveryScript
+		if len(deliveryAddr) == 0 {
+			var err error
+			deliveryAddr, err = p.genDeliveryScript()
+			if err != nil {
+				peerLog.Errorf(err.Error())
+				req.Err <- err
+				return
+			}
 		}
 
 		// Next, we'll create a new channel closer state machine to
@@ -2142,6 +2215,8 @@ func (p *peer) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 				unregisterChannel: p.server.htlcSwitch.RemoveLink,
 				broadcastTx:       p.server.cc.wallet.PublishTransaction,
 				disableChannel:    p.server.chanStatusMgr.RequestDisable,
+				closeSigner:       p.server.closeSigner,
+				closeSigTimeout:   cfg.CoopCloseSigTimeout,
 				quit:              p.quit,
 			},
 			deliveryAddr,
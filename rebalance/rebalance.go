@@ -0,0 +1,102 @@
+package rebalance
+
+import (
+	"io"
+	"math"
+	"time"
+
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// Target is the share of a channel's capacity that should be held on our side
+// of the channel. Channels that drift too far from their target are
+// rebalanced automatically.
+type Target struct {
+	// ChanID is the short channel id of the channel.
+	ChanID uint64
+
+	// Ratio is the fraction of the channel's capacity, between 0 and 1,
+	// that should make up our local balance.
+	Ratio float64
+}
+
+// Rebalance records a completed circular payment, which shifted funds from
+// one of our channels to another.
+type Rebalance struct {
+	// ID is the unique identifier of the rebalance.
+	ID uint64
+
+	// Timestamp is the time at which the rebalance completed.
+	Timestamp time.Time
+
+	// OutgoingChanID is the short channel id of the channel the funds
+	// were moved out of.
+	OutgoingChanID uint64
+
+	// IncomingChanID is the short channel id of the channel the funds
+	// were moved into.
+	IncomingChanID uint64
+
+	// Amount is the amount that was moved into the incoming channel.
+	Amount btcutil.Amount
+
+	// Fee is the routing fee paid for the rebalance, which was moved out
+	// of the outgoing channel on top of the amount.
+	Fee lnwire.MilliSatoshi
+
+	// Automatic is true if the rebalance was initiated to bring channels
+	// closer to their targets, rather than being requested manually.
+	Automatic bool
+}
+
+// serializeTarget writes the serialized target, excluding its channel id, to
+// the passed writer.
+func serializeTarget(w io.Writer, t *Target) error {
+	return channeldb.WriteElement(w, math.Float64bits(t.Ratio))
+}
+
+// deserializeTarget reads a target serialized by serializeTarget from the
+// passed reader.
+func deserializeTarget(r io.Reader) (*Target, error) {
+	var ratio uint64
+	if err := channeldb.ReadElement(r, &ratio); err != nil {
+		return nil, err
+	}
+
+	return &Target{
+		Ratio: math.Float64frombits(ratio),
+	}, nil
+}
+
+// serializeRebalance writes the serialized rebalance, excluding its ID, to the
+// passed writer.
+func serializeRebalance(w io.Writer, r *Rebalance) error {
+	return channeldb.WriteElements(w,
+		uint64(r.Timestamp.UnixNano()), r.OutgoingChanID,
+		r.IncomingChanID, r.Amount, r.Fee, r.Automatic,
+	)
+}
+
+// deserializeRebalance reads a rebalance serialized by serializeRebalance from
+// the passed reader.
+func deserializeRebalance(r io.Reader) (*Rebalance, error) {
+	var (
+		rebalance Rebalance
+		timestamp uint64
+	)
+
+	err := channeldb.ReadElements(r,
+		&timestamp, &rebalance.OutgoingChanID,
+		&rebalance.IncomingChanID, &rebalance.Amount, &rebalance.Fee,
+		&rebalance.Automatic,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rebalance.Timestamp = time.Unix(0, int64(timestamp))
+
+	return &rebalance, nil
+}
@@ -0,0 +1,495 @@
+package rebalance
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing"
+	"github.com/litecoinfinance/lnd/routing/route"
+	"github.com/litecoinfinance/lnd/ticker"
+	"github.com/litecoinfinance/lnd/zpay32"
+)
+
+const (
+	// DefaultInterval is the default interval at which channels are
+	// checked against their targets.
+	DefaultInterval = time.Hour
+
+	// DefaultMaxFeeRate is the default maximum routing fee of a rebalance,
+	// expressed in parts per million of the amount moved.
+	DefaultMaxFeeRate = 1000
+
+	// DefaultTolerance is the default fraction of a channel's capacity
+	// its local balance may deviate from its target before the channel is
+	// rebalanced automatically.
+	DefaultTolerance = 0.1
+
+	// MinAmount is the smallest amount moved by an automatic rebalance.
+	// Smaller imbalances aren't worth the fees paid to correct them.
+	MinAmount = btcutil.Amount(10000)
+
+	// feeBudgetWindow is the window the daily fee budget applies to.
+	feeBudgetWindow = 24 * time.Hour
+
+	// finalCLTVDelta is the CLTV delta of the invoices paid to ourselves.
+	finalCLTVDelta = zpay32.DefaultFinalCLTVDelta
+)
+
+var (
+	// ErrBudgetExhausted is returned when a rebalance isn't attempted as
+	// the fees paid for rebalances within the last 24 hours have
+	// exhausted the daily fee budget.
+	ErrBudgetExhausted = errors.New("rebalance fee budget exhausted")
+
+	// ErrInvalidRatio is returned when a target ratio outside of [0, 1]
+	// is set.
+	ErrInvalidRatio = errors.New("target ratio must be between 0 and 1")
+)
+
+// ChannelBalance describes the balance of one of our channels.
+type ChannelBalance struct {
+	// ChanID is the short channel id of the channel.
+	ChanID uint64
+
+	// Capacity is the total capacity of the channel.
+	Capacity btcutil.Amount
+
+	// LocalBalance is the part of the capacity held on our side.
+	LocalBalance btcutil.Amount
+
+	// Active indicates whether the channel is able to forward payments.
+	Active bool
+}
+
+// Request describes a rebalance between two of our channels.
+type Request struct {
+	// OutgoingChanID is the short channel id of the channel to move funds
+	// out of.
+	OutgoingChanID uint64
+
+	// IncomingChanID is the short channel id of the channel to move funds
+	// into.
+	IncomingChanID uint64
+
+	// Amount is the amount to move into the incoming channel.
+	Amount btcutil.Amount
+
+	// MaxFee is the maximum routing fee to pay. If zero, the fee is
+	// limited by the configured MaxFeeRate.
+	MaxFee lnwire.MilliSatoshi
+}
+
+// Config houses the dependencies of the Rebalancer.
+type Config struct {
+	// Store persists the targets of our channels and the history of
+	// rebalances.
+	Store Store
+
+	// FetchChannels returns the balances of all of our open channels.
+	FetchChannels func() ([]*ChannelBalance, error)
+
+	// FindCircularRoute finds a route leaving through the outgoing channel
+	// and returning to us through the incoming channel.
+	FindCircularRoute func(amt lnwire.MilliSatoshi, outgoingChanID,
+		incomingChanID uint64, restrictions *routing.RestrictParams,
+		finalCLTVDelta uint16) (*route.Route, error)
+
+	// AddInvoice adds an invoice to our invoice registry for the given
+	// amount, returning its payment hash.
+	AddInvoice func(amt btcutil.Amount, memo string,
+		finalCLTVDelta uint16) ([32]byte, error)
+
+	// SendToRoute sends a payment along the given routes, blocking until
+	// it either succeeds or fails.
+	SendToRoute func(routes []*route.Route,
+		payment *routing.LightningPayment) ([32]byte, *route.Route,
+		error)
+
+	// Ticker is used to periodically check our channels against their
+	// targets. If nil, channels are only rebalanced on request.
+	Ticker ticker.Ticker
+
+	// MaxFeeRate is the maximum routing fee of a rebalance, expressed in
+	// parts per million of the amount moved, unless a request specifies
+	// its own limit.
+	MaxFeeRate uint64
+
+	// DailyFeeBudget is the maximum total routing fee that may be paid
+	// for rebalances within any 24 hour window. Zero leaves the fees
+	// unbounded.
+	DailyFeeBudget btcutil.Amount
+
+	// Tolerance is the fraction of a channel's capacity its local balance
+	// may deviate from its target before it's rebalanced automatically.
+	Tolerance float64
+}
+
+// Rebalancer shifts funds between our channels by paying ourselves along
+// circular routes. Rebalances can either be requested directly, or happen
+// automatically for channels that drift too far from their target ratio of
+// local balance to capacity. The fees of all rebalances are bounded by a daily
+// budget.
+type Rebalancer struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *Config
+
+	// mu serializes rebalances, such that the fees spent within the
+	// budget window are accounted for correctly.
+	mu sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new rebalancer backed by the given config.
+func New(cfg *Config) *Rebalancer {
+	return &Rebalancer{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine responsible for automatic rebalances, if
+// enabled.
+func (r *Rebalancer) Start() error {
+	if !atomic.CompareAndSwapUint32(&r.started, 0, 1) {
+		return nil
+	}
+
+	log.Info("Rebalancer starting")
+
+	if r.cfg.Ticker != nil {
+		r.cfg.Ticker.Resume()
+
+		r.wg.Add(1)
+		go r.rebalanceHandler()
+	}
+
+	return nil
+}
+
+// Stop signals the rebalancer for a graceful shutdown, waiting for any
+// in-flight automatic rebalance to complete.
+func (r *Rebalancer) Stop() error {
+	if !atomic.CompareAndSwapUint32(&r.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Info("Rebalancer shutting down")
+
+	close(r.quit)
+	if r.cfg.Ticker != nil {
+		r.cfg.Ticker.Stop()
+	}
+	r.wg.Wait()
+
+	return nil
+}
+
+// SetTarget sets the target ratio of local balance to capacity of the channel
+// with the given short channel id.
+func (r *Rebalancer) SetTarget(chanID uint64, ratio float64) error {
+	if ratio < 0 || ratio > 1 {
+		return ErrInvalidRatio
+	}
+
+	err := r.cfg.Store.SetTarget(&Target{
+		ChanID: chanID,
+		Ratio:  ratio,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Set rebalance target of channel %v to %v",
+		lnwire.NewShortChanIDFromInt(chanID), ratio)
+
+	return nil
+}
+
+// RemoveTarget removes the target of the channel with the given short channel
+// id, such that it's no longer rebalanced automatically.
+func (r *Rebalancer) RemoveTarget(chanID uint64) error {
+	if err := r.cfg.Store.RemoveTarget(chanID); err != nil {
+		return err
+	}
+
+	log.Infof("Removed rebalance target of channel %v",
+		lnwire.NewShortChanIDFromInt(chanID))
+
+	return nil
+}
+
+// ListTargets returns the targets of all channels that have one.
+func (r *Rebalancer) ListTargets() ([]*Target, error) {
+	return r.cfg.Store.FetchTargets()
+}
+
+// ListRebalances returns all completed rebalances.
+func (r *Rebalancer) ListRebalances() ([]*Rebalance, error) {
+	return r.cfg.Store.FetchRebalances()
+}
+
+// FeesSpent returns the total routing fee paid for rebalances within the last
+// 24 hours.
+func (r *Rebalancer) FeesSpent() (lnwire.MilliSatoshi, error) {
+	rebalances, err := r.cfg.Store.FetchRebalances()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-feeBudgetWindow)
+
+	var spent lnwire.MilliSatoshi
+	for _, rebalance := range rebalances {
+		if rebalance.Timestamp.After(cutoff) {
+			spent += rebalance.Fee
+		}
+	}
+
+	return spent, nil
+}
+
+// Rebalance moves the requested amount from the outgoing channel to the
+// incoming channel, blocking until the circular payment either succeeds or
+// fails.
+func (r *Rebalancer) Rebalance(req *Request) (*Rebalance, error) {
+	return r.rebalance(req, false)
+}
+
+// rebalance executes the requested rebalance, and records it once completed.
+func (r *Rebalancer) rebalance(req *Request,
+	automatic bool) (*Rebalance, error) {
+
+	switch {
+	case req.Amount <= 0:
+		return nil, errors.New("rebalance amount must be positive")
+
+	case req.OutgoingChanID == req.IncomingChanID:
+		return nil, routing.ErrSameChannel
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	amt := lnwire.NewMSatFromSatoshis(req.Amount)
+	maxFee := req.MaxFee
+	if maxFee == 0 {
+		maxFee = amt * lnwire.MilliSatoshi(r.cfg.MaxFeeRate) / 1000000
+	}
+
+	// The fee of the rebalance may not exceed what remains of the daily
+	// budget.
+	if r.cfg.DailyFeeBudget != 0 {
+		spent, err := r.FeesSpent()
+		if err != nil {
+			return nil, err
+		}
+
+		budget := lnwire.NewMSatFromSatoshis(r.cfg.DailyFeeBudget)
+		if spent >= budget {
+			return nil, ErrBudgetExhausted
+		}
+		if maxFee > budget-spent {
+			maxFee = budget - spent
+		}
+	}
+
+	outgoing := lnwire.NewShortChanIDFromInt(req.OutgoingChanID)
+	incoming := lnwire.NewShortChanIDFromInt(req.IncomingChanID)
+
+	rt, err := r.cfg.FindCircularRoute(
+		amt, req.OutgoingChanID, req.IncomingChanID,
+		&routing.RestrictParams{
+			FeeLimit: maxFee,
+		}, finalCLTVDelta,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find route from %v to %v: %v",
+			outgoing, incoming, err)
+	}
+
+	memo := fmt.Sprintf("rebalance %v -> %v", outgoing, incoming)
+	paymentHash, err := r.cfg.AddInvoice(req.Amount, memo, finalCLTVDelta)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Rebalancing %v from %v to %v with fee %v", req.Amount,
+		outgoing, incoming, rt.TotalFees)
+
+	_, rt, err = r.cfg.SendToRoute(
+		[]*route.Route{rt}, &routing.LightningPayment{
+			PaymentHash: paymentHash,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rebalance := &Rebalance{
+		Timestamp:      time.Now(),
+		OutgoingChanID: req.OutgoingChanID,
+		IncomingChanID: req.IncomingChanID,
+		Amount:         req.Amount,
+		Fee:            rt.TotalFees,
+		Automatic:      automatic,
+	}
+	if err := r.cfg.Store.AddRebalance(rebalance); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Rebalanced %v from %v to %v, paying %v in fees",
+		req.Amount, outgoing, incoming, rt.TotalFees)
+
+	return rebalance, nil
+}
+
+// rebalanceHandler is the main event loop of the rebalancer. Each time the
+// ticker fires, channels are checked against their targets.
+//
+// NOTE: This MUST be run as a goroutine.
+func (r *Rebalancer) rebalanceHandler() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.cfg.Ticker.Ticks():
+			r.rebalanceTargets()
+
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// imbalance is the amount by which a channel's local balance deviates from
+// its target.
+type imbalance struct {
+	chanID uint64
+	amt    btcutil.Amount
+}
+
+// findImbalances returns the channels holding more than their target, and
+// those holding less, each ordered by the size of their imbalance. Channels
+// within the tolerance of their target, and those that are inactive, are
+// left out.
+func (r *Rebalancer) findImbalances(targets []*Target,
+	channels []*ChannelBalance) ([]*imbalance, []*imbalance) {
+
+	balances := make(map[uint64]*ChannelBalance, len(channels))
+	for _, channel := range channels {
+		balances[channel.ChanID] = channel
+	}
+
+	var excess, deficit []*imbalance
+	for _, target := range targets {
+		channel, ok := balances[target.ChanID]
+		if !ok || !channel.Active {
+			continue
+		}
+
+		capacity := float64(channel.Capacity)
+		desired := btcutil.Amount(capacity * target.Ratio)
+		tolerance := btcutil.Amount(capacity * r.cfg.Tolerance)
+
+		diff := channel.LocalBalance - desired
+		switch {
+		case diff > tolerance:
+			excess = append(excess, &imbalance{
+				chanID: target.ChanID,
+				amt:    diff,
+			})
+
+		case -diff > tolerance:
+			deficit = append(deficit, &imbalance{
+				chanID: target.ChanID,
+				amt:    -diff,
+			})
+		}
+	}
+
+	sortImbalances(excess)
+	sortImbalances(deficit)
+
+	return excess, deficit
+}
+
+// sortImbalances orders the imbalances from largest to smallest.
+func sortImbalances(imbalances []*imbalance) {
+	sort.Slice(imbalances, func(i, j int) bool {
+		return imbalances[i].amt > imbalances[j].amt
+	})
+}
+
+// rebalanceTargets moves funds from the channels holding more than their
+// target to those holding less. Each channel short of its target receives
+// funds from the channel with the largest excess at the time.
+func (r *Rebalancer) rebalanceTargets() {
+	targets, err := r.cfg.Store.FetchTargets()
+	if err != nil {
+		log.Errorf("Unable to fetch rebalance targets: %v", err)
+		return
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	channels, err := r.cfg.FetchChannels()
+	if err != nil {
+		log.Errorf("Unable to fetch channels: %v", err)
+		return
+	}
+
+	excess, deficit := r.findImbalances(targets, channels)
+	for _, sink := range deficit {
+		if len(excess) == 0 {
+			return
+		}
+
+		select {
+		case <-r.quit:
+			return
+		default:
+		}
+
+		source := excess[0]
+		amt := sink.amt
+		if amt > source.amt {
+			amt = source.amt
+		}
+		if amt < MinAmount {
+			continue
+		}
+
+		_, err := r.rebalance(&Request{
+			OutgoingChanID: source.chanID,
+			IncomingChanID: sink.chanID,
+			Amount:         amt,
+		}, true)
+		switch {
+		case err == ErrBudgetExhausted:
+			log.Warnf("Skipping automatic rebalances: %v", err)
+			return
+
+		case err != nil:
+			log.Warnf("Unable to rebalance channel %v: %v",
+				lnwire.NewShortChanIDFromInt(sink.chanID), err)
+			continue
+		}
+
+		source.amt -= amt
+		sortImbalances(excess)
+		if excess[0].amt < MinAmount {
+			return
+		}
+	}
+}
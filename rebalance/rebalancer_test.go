@@ -0,0 +1,343 @@
+package rebalance
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing"
+	"github.com/litecoinfinance/lnd/routing/route"
+	"github.com/litecoinfinance/lnd/ticker"
+)
+
+var (
+	errFeeLimit    = errors.New("fee limit exceeded")
+	errUnknownHash = errors.New("unknown payment hash")
+)
+
+type rebalancerTestContext struct {
+	t *testing.T
+
+	rebalancer *Rebalancer
+	ticker     *ticker.Force
+
+	channels []*ChannelBalance
+	routeFee lnwire.MilliSatoshi
+	invoices [][32]byte
+
+	payments chan *Request
+
+	cleanUp func()
+}
+
+func newRebalancerTestContext(t *testing.T,
+	budget btcutil.Amount) *rebalancerTestContext {
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to open channel db: %v", err)
+	}
+
+	store, err := NewStore(cdb)
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to create store: %v", err)
+	}
+
+	ctx := &rebalancerTestContext{
+		t:        t,
+		ticker:   ticker.NewForce(DefaultInterval),
+		routeFee: 1000,
+		payments: make(chan *Request, 10),
+	}
+
+	ctx.rebalancer = New(&Config{
+		Store: store,
+		FetchChannels: func() ([]*ChannelBalance, error) {
+			return ctx.channels, nil
+		},
+		FindCircularRoute: func(amt lnwire.MilliSatoshi, outgoingChanID,
+			incomingChanID uint64,
+			restrictions *routing.RestrictParams,
+			_ uint16) (*route.Route, error) {
+
+			if ctx.routeFee > restrictions.FeeLimit {
+				return nil, errFeeLimit
+			}
+
+			return &route.Route{
+				TotalAmount: amt + ctx.routeFee,
+				TotalFees:   ctx.routeFee,
+				Hops: []*route.Hop{
+					{ChannelID: outgoingChanID},
+					{ChannelID: incomingChanID},
+				},
+			}, nil
+		},
+		AddInvoice: func(amt btcutil.Amount, _ string,
+			_ uint16) ([32]byte, error) {
+
+			hash := [32]byte{byte(len(ctx.invoices) + 1)}
+			ctx.invoices = append(ctx.invoices, hash)
+			return hash, nil
+		},
+		SendToRoute: func(routes []*route.Route,
+			payment *routing.LightningPayment) ([32]byte,
+			*route.Route, error) {
+
+			rt := routes[0]
+			lastInvoice := ctx.invoices[len(ctx.invoices)-1]
+			if payment.PaymentHash != lastInvoice {
+				return [32]byte{}, nil, errUnknownHash
+			}
+
+			ctx.payments <- &Request{
+				OutgoingChanID: rt.Hops[0].ChannelID,
+				IncomingChanID: rt.Hops[1].ChannelID,
+				Amount: (rt.TotalAmount -
+					rt.TotalFees).ToSatoshis(),
+			}
+
+			return [32]byte{}, rt, nil
+		},
+		Ticker:         ctx.ticker,
+		MaxFeeRate:     DefaultMaxFeeRate,
+		DailyFeeBudget: budget,
+		Tolerance:      DefaultTolerance,
+	})
+	if err := ctx.rebalancer.Start(); err != nil {
+		cleanUp()
+		t.Fatalf("unable to start rebalancer: %v", err)
+	}
+
+	ctx.cleanUp = func() {
+		ctx.rebalancer.Stop()
+		cleanUp()
+	}
+
+	return ctx
+}
+
+// tick forces the rebalancer to check the channels against their targets. As
+// the ticker is unbuffered, the tick is only accepted once the previous one
+// has been fully processed.
+func (c *rebalancerTestContext) tick() {
+	select {
+	case c.ticker.Force <- time.Now():
+	case <-time.After(5 * time.Second):
+		c.t.Fatalf("rebalancer didn't accept tick")
+	}
+}
+
+// assertPayment asserts that a circular payment is sent for the given
+// rebalance.
+func (c *rebalancerTestContext) assertPayment(outgoing, incoming uint64,
+	amt btcutil.Amount) {
+
+	c.t.Helper()
+
+	select {
+	case req := <-c.payments:
+		if req.OutgoingChanID != outgoing ||
+			req.IncomingChanID != incoming || req.Amount != amt {
+
+			c.t.Fatalf("expected rebalance of %v from %v to %v, "+
+				"got %v from %v to %v", amt, outgoing, incoming,
+				req.Amount, req.OutgoingChanID,
+				req.IncomingChanID)
+		}
+
+	case <-time.After(5 * time.Second):
+		c.t.Fatalf("expected rebalance from %v to %v", outgoing,
+			incoming)
+	}
+}
+
+// assertNoPayment asserts that no circular payment has been sent.
+func (c *rebalancerTestContext) assertNoPayment() {
+	c.t.Helper()
+
+	select {
+	case req := <-c.payments:
+		c.t.Fatalf("unexpected rebalance from %v to %v",
+			req.OutgoingChanID, req.IncomingChanID)
+	default:
+	}
+}
+
+// TestRebalance asserts that requested rebalances are sent as circular
+// payments within their fee limit, and recorded once completed.
+func TestRebalance(t *testing.T) {
+	t.Parallel()
+
+	ctx := newRebalancerTestContext(t, 0)
+	defer ctx.cleanUp()
+
+	_, err := ctx.rebalancer.Rebalance(&Request{
+		OutgoingChanID: 1,
+		IncomingChanID: 1,
+		Amount:         100000,
+	})
+	if err != routing.ErrSameChannel {
+		t.Fatalf("expected ErrSameChannel, got %v", err)
+	}
+
+	rebalance, err := ctx.rebalancer.Rebalance(&Request{
+		OutgoingChanID: 1,
+		IncomingChanID: 2,
+		Amount:         100000,
+	})
+	if err != nil {
+		t.Fatalf("unable to rebalance: %v", err)
+	}
+	ctx.assertPayment(1, 2, 100000)
+
+	if rebalance.Fee != ctx.routeFee || rebalance.Automatic {
+		t.Fatalf("unexpected rebalance: %v", rebalance)
+	}
+
+	rebalances, err := ctx.rebalancer.ListRebalances()
+	if err != nil {
+		t.Fatalf("unable to list rebalances: %v", err)
+	}
+	if len(rebalances) != 1 || rebalances[0].ID != rebalance.ID {
+		t.Fatalf("expected rebalance to be recorded")
+	}
+
+	// With the default fee rate of 1000 ppm, the fee of a rebalance of
+	// 100000 sat is limited to 100 sat, unless the request specifies a
+	// higher limit.
+	ctx.routeFee = 150000
+	_, err = ctx.rebalancer.Rebalance(&Request{
+		OutgoingChanID: 1,
+		IncomingChanID: 2,
+		Amount:         100000,
+	})
+	if err == nil {
+		t.Fatalf("expected rebalance exceeding fee limit to fail")
+	}
+	ctx.assertNoPayment()
+
+	_, err = ctx.rebalancer.Rebalance(&Request{
+		OutgoingChanID: 1,
+		IncomingChanID: 2,
+		Amount:         100000,
+		MaxFee:         200000,
+	})
+	if err != nil {
+		t.Fatalf("unable to rebalance: %v", err)
+	}
+	ctx.assertPayment(1, 2, 100000)
+}
+
+// TestRebalanceBudget asserts that the fees of rebalances are bounded by the
+// daily fee budget.
+func TestRebalanceBudget(t *testing.T) {
+	t.Parallel()
+
+	ctx := newRebalancerTestContext(t, 2)
+	defer ctx.cleanUp()
+
+	req := &Request{
+		OutgoingChanID: 1,
+		IncomingChanID: 2,
+		Amount:         100000,
+	}
+
+	// The first rebalance uses up half of the budget, leaving room for a
+	// second one.
+	for i := 0; i < 2; i++ {
+		if _, err := ctx.rebalancer.Rebalance(req); err != nil {
+			t.Fatalf("unable to rebalance: %v", err)
+		}
+		ctx.assertPayment(1, 2, 100000)
+	}
+
+	spent, err := ctx.rebalancer.FeesSpent()
+	if err != nil {
+		t.Fatalf("unable to fetch fees spent: %v", err)
+	}
+	if spent != 2000 {
+		t.Fatalf("expected 2000 msat spent, got %v", spent)
+	}
+
+	if _, err := ctx.rebalancer.Rebalance(req); err != ErrBudgetExhausted {
+		t.Fatalf("expected ErrBudgetExhausted, got %v", err)
+	}
+	ctx.assertNoPayment()
+}
+
+// TestRebalanceTargets asserts that channels drifting too far from their
+// targets are rebalanced automatically.
+func TestRebalanceTargets(t *testing.T) {
+	t.Parallel()
+
+	ctx := newRebalancerTestContext(t, 0)
+	defer ctx.cleanUp()
+
+	ctx.channels = []*ChannelBalance{
+		{ChanID: 1, Capacity: 1000000, LocalBalance: 900000,
+			Active: true},
+		{ChanID: 2, Capacity: 1000000, LocalBalance: 150000,
+			Active: true},
+		{ChanID: 3, Capacity: 1000000, LocalBalance: 450000,
+			Active: true},
+		{ChanID: 4, Capacity: 1000000, LocalBalance: 0},
+	}
+
+	// Without any targets, no channel is rebalanced.
+	ctx.tick()
+	ctx.tick()
+	ctx.assertNoPayment()
+
+	if err := ctx.rebalancer.SetTarget(1, 1.5); err != ErrInvalidRatio {
+		t.Fatalf("expected ErrInvalidRatio, got %v", err)
+	}
+
+	// Channel 1 holds 400000 sat more than its target, while channel 2
+	// lacks 350000 sat. Channel 3 is within the tolerance of its target,
+	// and channel 4 is inactive, so neither is rebalanced.
+	for chanID := uint64(1); chanID <= 4; chanID++ {
+		if err := ctx.rebalancer.SetTarget(chanID, 0.5); err != nil {
+			t.Fatalf("unable to set target: %v", err)
+		}
+	}
+
+	ctx.tick()
+	ctx.assertPayment(1, 2, 350000)
+
+	// Once the funds have been moved, both channels are within the
+	// tolerance of their targets.
+	ctx.channels = []*ChannelBalance{
+		{ChanID: 1, Capacity: 1000000, LocalBalance: 549000,
+			Active: true},
+		{ChanID: 2, Capacity: 1000000, LocalBalance: 500000,
+			Active: true},
+		{ChanID: 3, Capacity: 1000000, LocalBalance: 450000,
+			Active: true},
+		{ChanID: 4, Capacity: 1000000, LocalBalance: 0},
+	}
+	ctx.tick()
+	ctx.tick()
+	ctx.assertNoPayment()
+
+	rebalances, err := ctx.rebalancer.ListRebalances()
+	if err != nil {
+		t.Fatalf("unable to list rebalances: %v", err)
+	}
+	if len(rebalances) != 1 || !rebalances[0].Automatic {
+		t.Fatalf("expected automatic rebalance, got %v", rebalances)
+	}
+
+	// Once the targets are removed, the channels are left alone.
+	for chanID := uint64(1); chanID <= 4; chanID++ {
+		if err := ctx.rebalancer.RemoveTarget(chanID); err != nil {
+			t.Fatalf("unable to remove target: %v", err)
+		}
+	}
+	ctx.tick()
+	ctx.tick()
+	ctx.assertNoPayment()
+}
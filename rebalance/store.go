@@ -0,0 +1,199 @@
+package rebalance
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+)
+
+var (
+	// targetsBucketKey is the key of the top-level bucket that stores the
+	// balance targets of our channels.
+	//
+	// maps: chanID -> serialized target
+	targetsBucketKey = []byte("rebalance-targets")
+
+	// rebalancesBucketKey is the key of the top-level bucket that stores
+	// the history of completed rebalances.
+	//
+	// maps: rebalanceID -> serialized rebalance
+	rebalancesBucketKey = []byte("rebalances")
+
+	// ErrTargetNotFound is returned when no target is set for a channel.
+	ErrTargetNotFound = errors.New("rebalance target not found")
+
+	byteOrder = binary.BigEndian
+)
+
+// Store persists the balance targets of our channels, along with the history
+// of rebalances.
+type Store interface {
+	// SetTarget adds or replaces the target of a channel.
+	SetTarget(t *Target) error
+
+	// RemoveTarget removes the target of the channel with the given short
+	// channel id. ErrTargetNotFound is returned if no target is set.
+	RemoveTarget(chanID uint64) error
+
+	// FetchTargets returns all targets, ordered by their channel id.
+	FetchTargets() ([]*Target, error)
+
+	// AddRebalance records a completed rebalance, assigning it a unique
+	// ID.
+	AddRebalance(r *Rebalance) error
+
+	// FetchRebalances returns all recorded rebalances, ordered by their
+	// ID.
+	FetchRebalances() ([]*Rebalance, error)
+}
+
+// rebalanceStore is an implementation of the Store interface backed by the
+// channel database.
+type rebalanceStore struct {
+	db *channeldb.DB
+}
+
+// A compile-time check to ensure rebalanceStore implements the Store
+// interface.
+var _ Store = (*rebalanceStore)(nil)
+
+// NewStore returns a new Store backed by the given channel database.
+func NewStore(db *channeldb.DB) (Store, error) {
+	err := db.Update(func(tx kvdb.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(targetsBucketKey)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateBucketIfNotExists(rebalancesBucketKey)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rebalanceStore{
+		db: db,
+	}, nil
+}
+
+// SetTarget adds or replaces the target of a channel.
+//
+// NOTE: Part of the Store interface.
+func (s *rebalanceStore) SetTarget(t *Target) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		var b bytes.Buffer
+		if err := serializeTarget(&b, t); err != nil {
+			return err
+		}
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], t.ChanID)
+
+		return tx.Bucket(targetsBucketKey).Put(k[:], b.Bytes())
+	})
+}
+
+// RemoveTarget removes the target of the channel with the given short channel
+// id.
+//
+// NOTE: Part of the Store interface.
+func (s *rebalanceStore) RemoveTarget(chanID uint64) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		targets := tx.Bucket(targetsBucketKey)
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], chanID)
+		if targets.Get(k[:]) == nil {
+			return ErrTargetNotFound
+		}
+
+		return targets.Delete(k[:])
+	})
+}
+
+// FetchTargets returns all targets, ordered by their channel id.
+//
+// NOTE: Part of the Store interface.
+func (s *rebalanceStore) FetchTargets() ([]*Target, error) {
+	var targets []*Target
+	err := s.db.View(func(tx kvdb.Tx) error {
+		bucket := tx.Bucket(targetsBucketKey)
+		return bucket.ForEach(func(k, v []byte) error {
+			target, err := deserializeTarget(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			target.ChanID = byteOrder.Uint64(k)
+
+			targets = append(targets, target)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// AddRebalance records a completed rebalance, assigning it a unique ID.
+//
+// NOTE: Part of the Store interface.
+func (s *rebalanceStore) AddRebalance(r *Rebalance) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		rebalances := tx.Bucket(rebalancesBucketKey)
+
+		id, err := rebalances.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := serializeRebalance(&b, r); err != nil {
+			return err
+		}
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], id)
+		if err := rebalances.Put(k[:], b.Bytes()); err != nil {
+			return err
+		}
+
+		r.ID = id
+
+		return nil
+	})
+}
+
+// FetchRebalances returns all recorded rebalances, ordered by their ID.
+//
+// NOTE: Part of the Store interface.
+func (s *rebalanceStore) FetchRebalances() ([]*Rebalance, error) {
+	var rebalances []*Rebalance
+	err := s.db.View(func(tx kvdb.Tx) error {
+		bucket := tx.Bucket(rebalancesBucketKey)
+		return bucket.ForEach(func(k, v []byte) error {
+			rebalance, err := deserializeRebalance(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			rebalance.ID = byteOrder.Uint64(k)
+
+			rebalances = append(rebalances, rebalance)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rebalances, nil
+}
@@ -0,0 +1,127 @@
+package rebalance
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/lnd/channeldb"
+)
+
+// makeTestDB creates a new instance of the ChannelDB for testing purposes. A
+// callback which cleans up the created temporary directories is also returned
+// and intended to be executed after the test completes.
+func makeTestDB() (*channeldb.DB, func(), error) {
+	tempDirName, err := ioutil.TempDir("", "rebalance")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cdb, err := channeldb.Open(tempDirName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanUp := func() {
+		cdb.Close()
+		os.RemoveAll(tempDirName)
+	}
+
+	return cdb, cleanUp, nil
+}
+
+// TestStore asserts that targets can be set and removed, and that rebalances
+// are recorded by the store.
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to open channel db: %v", err)
+	}
+	defer cleanUp()
+
+	store, err := NewStore(cdb)
+	if err != nil {
+		t.Fatalf("unable to create store: %v", err)
+	}
+
+	assertTargets := func(expected ...*Target) {
+		t.Helper()
+
+		targets, err := store.FetchTargets()
+		if err != nil {
+			t.Fatalf("unable to fetch targets: %v", err)
+		}
+		if !reflect.DeepEqual(targets, expected) {
+			t.Fatalf("expected targets %v, got %v",
+				spew.Sdump(expected), spew.Sdump(targets))
+		}
+	}
+
+	target1 := &Target{ChanID: 2, Ratio: 0.5}
+	target2 := &Target{ChanID: 1, Ratio: 0.25}
+	if err := store.SetTarget(target1); err != nil {
+		t.Fatalf("unable to set target: %v", err)
+	}
+	if err := store.SetTarget(target2); err != nil {
+		t.Fatalf("unable to set target: %v", err)
+	}
+	assertTargets(target2, target1)
+
+	// Setting the target of a channel again should replace it.
+	target1.Ratio = 0.75
+	if err := store.SetTarget(target1); err != nil {
+		t.Fatalf("unable to set target: %v", err)
+	}
+	assertTargets(target2, target1)
+
+	if err := store.RemoveTarget(target2.ChanID); err != nil {
+		t.Fatalf("unable to remove target: %v", err)
+	}
+	assertTargets(target1)
+
+	err = store.RemoveTarget(target2.ChanID)
+	if err != ErrTargetNotFound {
+		t.Fatalf("expected ErrTargetNotFound, got %v", err)
+	}
+
+	// Rebalances should be assigned increasing IDs as they're recorded.
+	rebalance1 := &Rebalance{
+		Timestamp:      time.Unix(0, 1000),
+		OutgoingChanID: 1,
+		IncomingChanID: 2,
+		Amount:         50000,
+		Fee:            1500,
+	}
+	rebalance2 := &Rebalance{
+		Timestamp:      time.Unix(0, 2000),
+		OutgoingChanID: 2,
+		IncomingChanID: 3,
+		Amount:         20000,
+		Fee:            10,
+		Automatic:      true,
+	}
+	for _, rebalance := range []*Rebalance{rebalance1, rebalance2} {
+		if err := store.AddRebalance(rebalance); err != nil {
+			t.Fatalf("unable to add rebalance: %v", err)
+		}
+	}
+	if rebalance1.ID == 0 || rebalance2.ID <= rebalance1.ID {
+		t.Fatalf("expected increasing IDs, got %v and %v",
+			rebalance1.ID, rebalance2.ID)
+	}
+
+	rebalances, err := store.FetchRebalances()
+	if err != nil {
+		t.Fatalf("unable to fetch rebalances: %v", err)
+	}
+	expected := []*Rebalance{rebalance1, rebalance2}
+	if !reflect.DeepEqual(rebalances, expected) {
+		t.Fatalf("expected rebalances %v, got %v",
+			spew.Sdump(expected), spew.Sdump(rebalances))
+	}
+}
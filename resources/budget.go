@@ -0,0 +1,142 @@
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/discovery"
+	"github.com/litecoinfinance/lnd/lncfg"
+)
+
+// Profile is the name of a predefined resource budget, tailored to a class of
+// devices.
+type Profile string
+
+const (
+	// ProfileDefault is the budget lnd uses unless configured otherwise.
+	// It suits most desktop machines and small servers.
+	ProfileDefault Profile = "default"
+
+	// ProfileRaspberryPi is a budget for single board computers with
+	// little memory and slow SD card or USB storage. It trades off
+	// throughput for a small memory footprint, and batches database
+	// writes more aggressively to spare the storage.
+	ProfileRaspberryPi Profile = "raspberry-pi"
+
+	// ProfileServer is a budget for dedicated servers with plenty of
+	// memory and cores, serving many peers.
+	ProfileServer Profile = "server"
+)
+
+// Budget bounds the resources lnd's subsystems may use.
+type Budget struct {
+	// ReadWorkers is the maximum number of concurrent read pool workers.
+	ReadWorkers int
+
+	// WriteWorkers is the maximum number of concurrent write pool workers.
+	WriteWorkers int
+
+	// SigWorkers is the number of sig pool workers.
+	SigWorkers int
+
+	// RejectCacheSize is the maximum number of entries in the channel
+	// graph's reject cache.
+	RejectCacheSize int
+
+	// ChannelCacheSize is the maximum number of entries in the channel
+	// graph's channel cache.
+	ChannelCacheSize int
+
+	// GossipBatchSize is the maximum number of channels queried from a
+	// peer in a single gossip query.
+	GossipBatchSize int32
+
+	// BatchCommitInterval is the maximum duration graph updates are held
+	// back to be written to the database in a single transaction.
+	BatchCommitInterval time.Duration
+}
+
+// profiles maps each known profile to its budget.
+var profiles = map[Profile]Budget{
+	ProfileDefault: {
+		ReadWorkers:         lncfg.DefaultReadWorkers,
+		WriteWorkers:        lncfg.DefaultWriteWorkers,
+		SigWorkers:          lncfg.DefaultSigWorkers,
+		RejectCacheSize:     channeldb.DefaultRejectCacheSize,
+		ChannelCacheSize:    channeldb.DefaultChannelCacheSize,
+		GossipBatchSize:     discovery.DefaultRequestBatchSize,
+		BatchCommitInterval: channeldb.DefaultBatchCommitInterval,
+	},
+	ProfileRaspberryPi: {
+		ReadWorkers:         20,
+		WriteWorkers:        2,
+		SigWorkers:          2,
+		RejectCacheSize:     10000,
+		ChannelCacheSize:    2000,
+		GossipBatchSize:     100,
+		BatchCommitInterval: 2 * time.Second,
+	},
+	ProfileServer: {
+		ReadWorkers:         200,
+		WriteWorkers:        16,
+		SigWorkers:          16,
+		RejectCacheSize:     200000,
+		ChannelCacheSize:    100000,
+		GossipBatchSize:     discovery.DefaultRequestBatchSize,
+		BatchCommitInterval: channeldb.DefaultBatchCommitInterval,
+	},
+}
+
+// ProfileBudget returns the budget of the given profile. An empty profile
+// refers to ProfileDefault.
+func ProfileBudget(profile Profile) (Budget, error) {
+	if profile == "" {
+		profile = ProfileDefault
+	}
+
+	budget, ok := profiles[profile]
+	if !ok {
+		return Budget{}, fmt.Errorf("unknown resource profile %q, "+
+			"must be one of: %v, %v, %v", profile, ProfileDefault,
+			ProfileRaspberryPi, ProfileServer)
+	}
+
+	return budget, nil
+}
+
+// Validate checks that the budget leaves every subsystem enough resources to
+// function.
+func (b *Budget) Validate() error {
+	workers := &lncfg.Workers{
+		Read:  b.ReadWorkers,
+		Write: b.WriteWorkers,
+		Sig:   b.SigWorkers,
+	}
+	caches := &lncfg.Caches{
+		RejectCacheSize:  b.RejectCacheSize,
+		ChannelCacheSize: b.ChannelCacheSize,
+	}
+	if err := lncfg.Validate(workers, caches); err != nil {
+		return err
+	}
+
+	if b.GossipBatchSize <= 0 {
+		return fmt.Errorf("gossip batch size (%d) must be positive",
+			b.GossipBatchSize)
+	}
+	if b.BatchCommitInterval < 0 {
+		return fmt.Errorf("batch commit interval (%v) must not be "+
+			"negative", b.BatchCommitInterval)
+	}
+
+	return nil
+}
+
+// String returns a human readable summary of the budget.
+func (b Budget) String() string {
+	return fmt.Sprintf("(workers read=%d write=%d sig=%d, caches "+
+		"reject=%d channel=%d, gossip_batch=%d, batch_commit=%v)",
+		b.ReadWorkers, b.WriteWorkers, b.SigWorkers, b.RejectCacheSize,
+		b.ChannelCacheSize, b.GossipBatchSize, b.BatchCommitInterval)
+}
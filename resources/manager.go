@@ -0,0 +1,110 @@
+package resources
+
+import (
+	"sync"
+	"time"
+)
+
+// Config houses the hooks through which the Manager applies a budget to the
+// subsystems whose resource usage can be adjusted at runtime.
+type Config struct {
+	// Profile is the profile the initial budget was derived from.
+	Profile Profile
+
+	// Budget is the budget lnd was started with. Its worker pool sizes
+	// remain in effect until lnd is restarted.
+	Budget Budget
+
+	// SetCacheSizes resizes the channel graph's reject and channel
+	// caches.
+	SetCacheSizes func(rejectCacheSize, chanCacheSize int)
+
+	// SetBatchCommitInterval changes the maximum duration graph updates
+	// are held back to be written in a single database transaction.
+	SetBatchCommitInterval func(time.Duration)
+
+	// SetGossipBatchSize changes the maximum number of channels queried
+	// from a peer in a single gossip query.
+	SetGossipBatchSize func(int32)
+}
+
+// Manager is the central authority over the resources lnd's subsystems may
+// use. Rather than tuning each subsystem individually, the operator selects a
+// profile, and the Manager applies its budget coherently across subsystems.
+type Manager struct {
+	cfg *Config
+
+	mu      sync.Mutex
+	profile Profile
+	budget  Budget
+}
+
+// NewManager creates a new Manager with the budget lnd was started with.
+func NewManager(cfg *Config) *Manager {
+	profile := cfg.Profile
+	if profile == "" {
+		profile = ProfileDefault
+	}
+
+	return &Manager{
+		cfg:     cfg,
+		profile: profile,
+		budget:  cfg.Budget,
+	}
+}
+
+// Budget returns the currently selected profile, along with the budget in
+// effect.
+func (m *Manager) Budget() (Profile, Budget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.profile, m.budget
+}
+
+// SetProfile applies the budget of the given profile to all subsystems that
+// can be adjusted at runtime, and returns the budget now in effect. Worker
+// pools are sized once at startup, so the returned boolean indicates whether
+// lnd must be restarted with the profile configured for them to adopt it as
+// well.
+func (m *Manager) SetProfile(profile Profile) (*Budget, bool, error) {
+	target, err := ProfileBudget(profile)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := target.Validate(); err != nil {
+		return nil, false, err
+	}
+	if profile == "" {
+		profile = ProfileDefault
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cfg.SetCacheSizes(target.RejectCacheSize, target.ChannelCacheSize)
+	m.cfg.SetBatchCommitInterval(target.BatchCommitInterval)
+	m.cfg.SetGossipBatchSize(target.GossipBatchSize)
+
+	restartRequired := target.ReadWorkers != m.cfg.Budget.ReadWorkers ||
+		target.WriteWorkers != m.cfg.Budget.WriteWorkers ||
+		target.SigWorkers != m.cfg.Budget.SigWorkers
+
+	// The worker pools keep running with the sizes they were started
+	// with, so our budget must continue to reflect those.
+	target.ReadWorkers = m.cfg.Budget.ReadWorkers
+	target.WriteWorkers = m.cfg.Budget.WriteWorkers
+	target.SigWorkers = m.cfg.Budget.SigWorkers
+
+	m.profile = profile
+	m.budget = target
+
+	log.Infof("Applied resource profile %v: %v", profile, target)
+	if restartRequired {
+		log.Infof("Worker pools will adopt resource profile %v once "+
+			"restarted with it configured", profile)
+	}
+
+	budget := target
+	return &budget, restartRequired, nil
+}
@@ -0,0 +1,108 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+// TestProfileBudgets asserts that the budgets of all known profiles are valid.
+func TestProfileBudgets(t *testing.T) {
+	t.Parallel()
+
+	for profile := range profiles {
+		budget, err := ProfileBudget(profile)
+		if err != nil {
+			t.Fatalf("unable to fetch budget of %v: %v", profile,
+				err)
+		}
+		if err := budget.Validate(); err != nil {
+			t.Fatalf("invalid budget for %v: %v", profile, err)
+		}
+	}
+
+	if _, err := ProfileBudget("toaster"); err == nil {
+		t.Fatalf("expected unknown profile to be rejected")
+	}
+}
+
+// TestManagerSetProfile asserts that selecting a profile applies its budget to
+// all subsystems that can be adjusted at runtime, while worker pools keep
+// their initial size.
+func TestManagerSetProfile(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rejectCacheSize     int
+		chanCacheSize       int
+		batchCommitInterval time.Duration
+		gossipBatchSize     int32
+	)
+
+	initial, err := ProfileBudget(ProfileDefault)
+	if err != nil {
+		t.Fatalf("unable to fetch default budget: %v", err)
+	}
+	m := NewManager(&Config{
+		Budget: initial,
+		SetCacheSizes: func(reject, channel int) {
+			rejectCacheSize = reject
+			chanCacheSize = channel
+		},
+		SetBatchCommitInterval: func(interval time.Duration) {
+			batchCommitInterval = interval
+		},
+		SetGossipBatchSize: func(batchSize int32) {
+			gossipBatchSize = batchSize
+		},
+	})
+
+	profile, budget := m.Budget()
+	if profile != ProfileDefault || budget != initial {
+		t.Fatalf("expected initial budget %v of profile %v, got %v "+
+			"of profile %v", initial, ProfileDefault, budget,
+			profile)
+	}
+
+	pi := profiles[ProfileRaspberryPi]
+	applied, restartRequired, err := m.SetProfile(ProfileRaspberryPi)
+	if err != nil {
+		t.Fatalf("unable to set profile: %v", err)
+	}
+	if !restartRequired {
+		t.Fatalf("expected worker pools to require a restart")
+	}
+
+	// The runtime adjustable parts of the budget should've been applied.
+	if rejectCacheSize != pi.RejectCacheSize ||
+		chanCacheSize != pi.ChannelCacheSize ||
+		batchCommitInterval != pi.BatchCommitInterval ||
+		gossipBatchSize != pi.GossipBatchSize {
+
+		t.Fatalf("budget %v not applied", pi)
+	}
+
+	// Though the worker pools should keep their initial size.
+	expected := pi
+	expected.ReadWorkers = initial.ReadWorkers
+	expected.WriteWorkers = initial.WriteWorkers
+	expected.SigWorkers = initial.SigWorkers
+	if *applied != expected {
+		t.Fatalf("expected budget %v, got %v", expected, *applied)
+	}
+
+	profile, budget = m.Budget()
+	if profile != ProfileRaspberryPi || budget != expected {
+		t.Fatalf("expected budget %v of profile %v, got %v of "+
+			"profile %v", expected, ProfileRaspberryPi, budget,
+			profile)
+	}
+
+	// Switching back to the initial profile shouldn't require a restart.
+	_, restartRequired, err = m.SetProfile(ProfileDefault)
+	if err != nil {
+		t.Fatalf("unable to set profile: %v", err)
+	}
+	if restartRequired {
+		t.Fatalf("expected no restart to be required")
+	}
+}
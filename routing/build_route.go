@@ -0,0 +1,178 @@
+package routing
+
+import (
+	"errors"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing/route"
+)
+
+// ErrNoHops is returned when a route is requested along an empty list of hops.
+var ErrNoHops = errors.New("route must contain at least one hop")
+
+// BuildRoute constructs a route that delivers amt to the last of the given
+// hops, after passing through all preceding hops in the given order. Between
+// each pair of consecutive hops, the cheapest channel that is able to carry
+// the amount it needs to forward is selected from the graph, and the fees and
+// time locks of the route are derived from the policies of those channels.
+// Unlike FindRoutes, no path finding takes place, which allows routes to be
+// explored manually.
+//
+// If outgoingChanID is set, the route leaves through that channel of ours.
+func (r *ChannelRouter) BuildRoute(amt lnwire.MilliSatoshi,
+	hops []route.Vertex, outgoingChanID *uint64,
+	finalCLTVDelta uint16) (*route.Route, error) {
+
+	if len(hops) == 0 {
+		return nil, ErrNoHops
+	}
+	if len(hops) > HopLimit {
+		return nil, newErr(ErrMaxHopsExceeded, "route has too many "+
+			"hops")
+	}
+
+	self := route.Vertex(r.selfNode.PubKeyBytes)
+
+	bandwidthHints, err := generateBandwidthHints(
+		r.selfNode, r.cfg.QueryBandwidth,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// We'll select the channels starting at the destination, as the amount
+	// a channel needs to carry depends on the fees charged by the hops
+	// following it.
+	path := make([]*channeldb.ChannelEdgePolicy, len(hops))
+	amtToSend := amt
+	for i := len(hops) - 1; i >= 0; i-- {
+		from := self
+		if i > 0 {
+			from = hops[i-1]
+		}
+
+		edge, err := r.selectHopChannel(
+			from, hops[i], amtToSend, bandwidthHints,
+			outgoingChanID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		path[i] = edge
+
+		// We don't charge ourselves a fee for the first hop.
+		if from != self {
+			amtToSend += computeFee(amtToSend, edge)
+		}
+	}
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	rt, err := newRoute(
+		amt, self, path, uint32(currentHeight), finalCLTVDelta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.checkRouteCltv(rt, uint32(currentHeight)); err != nil {
+		return nil, err
+	}
+
+	return rt, nil
+}
+
+// selectHopChannel returns the policy of the cheapest channel from one node to
+// the next that is able to carry amt. The Node of the returned policy is the
+// node the channel leads to. If the channel is one of ours, its bandwidth must
+// suffice, and it must match outgoingChanID if set.
+func (r *ChannelRouter) selectHopChannel(from, to route.Vertex,
+	amt lnwire.MilliSatoshi, bandwidthHints map[uint64]lnwire.MilliSatoshi,
+	outgoingChanID *uint64) (*channeldb.ChannelEdgePolicy, error) {
+
+	toNode, err := r.FetchLightningNode(to)
+	switch {
+	case err == channeldb.ErrGraphNodeNotFound:
+		return nil, newErrf(ErrTargetNotInNetwork, "hop %x not found "+
+			"in graph", to[:])
+
+	case err != nil:
+		return nil, err
+	}
+
+	isSourceChan := from == route.Vertex(r.selfNode.PubKeyBytes)
+
+	var (
+		bestEdge *channeldb.ChannelEdgePolicy
+		bestFee  lnwire.MilliSatoshi
+	)
+	err = toNode.ForEachChannel(nil, func(_ kvdb.Tx,
+		edgeInfo *channeldb.ChannelEdgeInfo,
+		_, inEdge *channeldb.ChannelEdgePolicy) error {
+
+		// We're only interested in the channels with the previous hop
+		// for which it has announced a policy.
+		if inEdge == nil {
+			return nil
+		}
+		if route.Vertex(edgeInfo.NodeKey1Bytes) != from &&
+			route.Vertex(edgeInfo.NodeKey2Bytes) != from {
+
+			return nil
+		}
+
+		var fee lnwire.MilliSatoshi
+		if isSourceChan {
+			if outgoingChanID != nil &&
+				*outgoingChanID != inEdge.ChannelID {
+
+				return nil
+			}
+
+			bandwidth, ok := bandwidthHints[inEdge.ChannelID]
+			if ok && bandwidth < amt {
+				return nil
+			}
+		} else {
+			isDisabled := inEdge.ChannelFlags&
+				lnwire.ChanUpdateDisabled != 0
+			if isDisabled {
+				return nil
+			}
+
+			fee = computeFee(amt, inEdge)
+		}
+
+		if amt < inEdge.MinHTLC {
+			return nil
+		}
+		if inEdge.MaxHTLC != 0 && inEdge.MaxHTLC < amt {
+			return nil
+		}
+
+		if bestEdge == nil || fee < bestFee ||
+			(fee == bestFee &&
+				inEdge.TimeLockDelta < bestEdge.TimeLockDelta) {
+
+			bestEdge = inEdge
+			bestFee = fee
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if bestEdge == nil {
+		return nil, newErrf(ErrNoPathFound, "no channel from %x to %x "+
+			"able to carry %v", from[:], to[:], amt)
+	}
+
+	return bestEdge, nil
+}
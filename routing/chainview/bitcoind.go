@@ -32,6 +32,10 @@ type BitcoindFilteredChainView struct {
 	// NodeFilteredView interface.
 	chainClient *chain.BitcoindClient
 
+	// blockCache holds the blocks recently fetched from bitcoind, and may
+	// be shared with other chain views.
+	blockCache *BlockCache
+
 	// blockEventQueue is the ordered queue used to keep the order
 	// of connected and disconnected blocks sent to the reader of the
 	// chainView.
@@ -60,10 +64,12 @@ var _ FilteredChainView = (*BitcoindFilteredChainView)(nil)
 
 // NewBitcoindFilteredChainView creates a new instance of a FilteredChainView
 // from RPC credentials and a ZMQ socket address for a bitcoind instance.
-func NewBitcoindFilteredChainView(
-	chainConn *chain.BitcoindConn) *BitcoindFilteredChainView {
+// Blocks are fetched through the passed cache, which may be nil.
+func NewBitcoindFilteredChainView(chainConn *chain.BitcoindConn,
+	blockCache *BlockCache) *BitcoindFilteredChainView {
 
 	chainView := &BitcoindFilteredChainView{
+		blockCache:      blockCache,
 		chainFilter:     make(map[wire.OutPoint]struct{}),
 		filterUpdates:   make(chan filterUpdate),
 		filterBlockReqs: make(chan *filterBlockReq),
@@ -390,7 +396,9 @@ func (b *BitcoindFilteredChainView) chainFilterer() {
 		case req := <-b.filterBlockReqs:
 			// First we'll fetch the block itself as well as some
 			// additional information including its height.
-			block, err := b.chainClient.GetBlock(req.blockHash)
+			block, err := b.blockCache.FetchBlock(
+				req.blockHash, b.chainClient.GetBlock,
+			)
 			if err != nil {
 				req.err <- err
 				req.resp <- nil
@@ -0,0 +1,465 @@
+package chainview
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/rpcclient"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil/gcs"
+	"github.com/litecoinfinance/btcutil/gcs/builder"
+	"github.com/litecoinfinance/btcwallet/chain"
+	"github.com/litecoinfinance/lnd/channeldb"
+)
+
+// BitcoindCfFilteredChainView is an implementation of the FilteredChainView
+// interface which is backed by a bitcoind node that serves BIP 158 compact
+// block filters, i.e. one running with blockfilterindex=1. Rather than
+// scanning every block for spends of our watched outputs, the filter of each
+// block is fetched over RPC and matched against the funding scripts of the
+// outputs first. Only the few blocks whose filter matches are then fetched in
+// full, which keeps rescans cheap even when the node is remote or pruned.
+type BitcoindCfFilteredChainView struct {
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	// bestHeight is the height of the latest block added to the
+	// blockQueue. It is used to determine up to what height we would need
+	// to rescan in case of a filter update.
+	bestHeightMtx sync.Mutex
+	bestHeight    uint32
+
+	// chainClient delivers the notifications of connected and
+	// disconnected blocks, and fetches the blocks that match our filter.
+	chainClient *chain.BitcoindClient
+
+	// rpcClient is used to fetch the compact filters of blocks, for which
+	// the chain client has no dedicated call.
+	rpcClient *rpcclient.Client
+
+	// blockCache holds the blocks and filters recently fetched from
+	// bitcoind, and may be shared with other chain views.
+	blockCache *BlockCache
+
+	// blockEventQueue is the ordered queue used to keep the order
+	// of connected and disconnected blocks sent to the reader of the
+	// chainView.
+	blockQueue *blockEventQueue
+
+	// filterUpdates is a channel in which updates to the utxo filter
+	// attached to this instance are sent over.
+	filterUpdates chan filterUpdate
+
+	// chainFilter maps the utxo's that we're currently watching spends
+	// for within the chain to their funding scripts, which are the items
+	// matched against the compact filters.
+	filterMtx   sync.RWMutex
+	chainFilter map[wire.OutPoint][]byte
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// A compile time check to ensure BitcoindCfFilteredChainView implements the
+// chainview.FilteredChainView.
+var _ FilteredChainView = (*BitcoindCfFilteredChainView)(nil)
+
+// NewBitcoindCfFilteredChainView creates a new instance of a FilteredChainView
+// that receives block notifications through the passed bitcoind connection,
+// and fetches compact block filters using the RPC credentials of the same
+// bitcoind instance. Blocks and filters are fetched through the passed cache,
+// which may be nil.
+func NewBitcoindCfFilteredChainView(chainConn *chain.BitcoindConn,
+	rpcConfig rpcclient.ConnConfig,
+	blockCache *BlockCache) (*BitcoindCfFilteredChainView, error) {
+
+	rpcConfig.DisableConnectOnNew = true
+	rpcConfig.DisableAutoReconnect = false
+	rpcConfig.DisableTLS = true
+	rpcConfig.HTTPPostMode = true
+	rpcClient, err := rpcclient.New(&rpcConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BitcoindCfFilteredChainView{
+		chainClient:   chainConn.NewBitcoindClient(),
+		rpcClient:     rpcClient,
+		blockCache:    blockCache,
+		blockQueue:    newBlockEventQueue(),
+		filterUpdates: make(chan filterUpdate),
+		chainFilter:   make(map[wire.OutPoint][]byte),
+		quit:          make(chan struct{}),
+	}, nil
+}
+
+// Start starts all goroutines necessary for normal operation.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (b *BitcoindCfFilteredChainView) Start() error {
+	// Already started?
+	if atomic.AddInt32(&b.started, 1) != 1 {
+		return nil
+	}
+
+	log.Infof("FilteredChainView starting")
+
+	err := b.chainClient.Start()
+	if err != nil {
+		return err
+	}
+
+	err = b.chainClient.NotifyBlocks()
+	if err != nil {
+		return err
+	}
+
+	bestHash, bestHeight, err := b.chainClient.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	// We'll fail early if bitcoind doesn't serve compact filters, rather
+	// than on the first block that's connected.
+	if _, err := b.fetchFilter(bestHash); err != nil {
+		return fmt.Errorf("unable to fetch compact filter, make sure "+
+			"bitcoind runs with blockfilterindex=1: %v", err)
+	}
+
+	b.bestHeightMtx.Lock()
+	b.bestHeight = uint32(bestHeight)
+	b.bestHeightMtx.Unlock()
+
+	b.blockQueue.Start()
+
+	b.wg.Add(1)
+	go b.chainFilterer()
+
+	return nil
+}
+
+// Stop stops all goroutines which we launched by the prior call to the Start
+// method.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (b *BitcoindCfFilteredChainView) Stop() error {
+	// Already shutting down?
+	if atomic.AddInt32(&b.stopped, 1) != 1 {
+		return nil
+	}
+
+	b.chainClient.Stop()
+	b.rpcClient.Shutdown()
+
+	b.blockQueue.Stop()
+
+	log.Infof("FilteredChainView stopping")
+
+	close(b.quit)
+	b.wg.Wait()
+
+	return nil
+}
+
+// fetchFilter fetches the basic compact filter of the block with the given
+// hash from bitcoind.
+func (b *BitcoindCfFilteredChainView) fetchFilter(
+	blockHash *chainhash.Hash) (*gcs.Filter, error) {
+
+	hashParam, err := json.Marshal(blockHash.String())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.rpcClient.RawRequest(
+		"getblockfilter", []json.RawMessage{hashParam},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Filter string `json:"filter"`
+	}{}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, err
+	}
+
+	filterBytes, err := hex.DecodeString(result.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcs.FromNBytes(builder.DefaultP, builder.DefaultM, filterBytes)
+}
+
+// filterBlock returns the FilteredBlock of the block with the given hash and
+// height. The block itself is only fetched if its compact filter matches the
+// funding script of any watched output. Any watched outputs spent within the
+// block are removed from the chain filter.
+func (b *BitcoindCfFilteredChainView) filterBlock(blockHash *chainhash.Hash,
+	height uint32) (*FilteredBlock, error) {
+
+	filteredBlock := &FilteredBlock{
+		Hash:   *blockHash,
+		Height: height,
+	}
+
+	// If we don't have any items within our current chain filter, then we
+	// can exit early as we don't need to fetch the filter.
+	b.filterMtx.RLock()
+	relevantScripts := make([][]byte, 0, len(b.chainFilter))
+	for _, pkScript := range b.chainFilter {
+		relevantScripts = append(relevantScripts, pkScript)
+	}
+	b.filterMtx.RUnlock()
+
+	if len(relevantScripts) == 0 {
+		return filteredBlock, nil
+	}
+
+	filter, err := b.blockCache.FetchFilter(blockHash, b.fetchFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := filter.MatchAny(
+		builder.DeriveKey(blockHash), relevantScripts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !matched {
+		return filteredBlock, nil
+	}
+
+	// As the filter has a false positive rate, we'll need to fetch the
+	// block itself to find the transactions that actually spend any of
+	// our watched outputs.
+	block, err := b.blockCache.FetchBlock(
+		blockHash, b.chainClient.GetBlock,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	b.filterMtx.Lock()
+	defer b.filterMtx.Unlock()
+
+	for _, tx := range block.Transactions {
+		var txAlreadyFiltered bool
+		for _, txIn := range tx.TxIn {
+			prevOp := txIn.PreviousOutPoint
+			if _, ok := b.chainFilter[prevOp]; !ok {
+				continue
+			}
+
+			delete(b.chainFilter, prevOp)
+
+			// Only add this txn to our list of filtered txns if it
+			// is the first previous outpoint to cause a match.
+			if txAlreadyFiltered {
+				continue
+			}
+
+			filteredBlock.Transactions = append(
+				filteredBlock.Transactions, tx,
+			)
+			txAlreadyFiltered = true
+		}
+	}
+
+	return filteredBlock, nil
+}
+
+// onBlockConnected is called for each block that's connected to the end of
+// the main chain. The block is matched against our current chain filter
+// before being dispatched.
+func (b *BitcoindCfFilteredChainView) onBlockConnected(height int32,
+	hash chainhash.Hash) {
+
+	block, err := b.filterBlock(&hash, uint32(height))
+	if err != nil {
+		// We'll still dispatch the block, as the reader relies on
+		// receiving every block in order.
+		log.Errorf("Unable to filter block %v at height %d: %v", hash,
+			height, err)
+
+		block = &FilteredBlock{
+			Hash:   hash,
+			Height: uint32(height),
+		}
+	}
+
+	// We record the height of the last connected block added to the
+	// blockQueue such that we can scan up to this height in case of
+	// a rescan.
+	b.bestHeightMtx.Lock()
+	b.bestHeight = uint32(height)
+	b.bestHeightMtx.Unlock()
+
+	b.blockQueue.Add(&blockEvent{
+		eventType: connected,
+		block:     block,
+	})
+}
+
+// onBlockDisconnected is a callback which is executed once a block is
+// disconnected from the end of the main chain.
+func (b *BitcoindCfFilteredChainView) onBlockDisconnected(height int32,
+	hash chainhash.Hash) {
+
+	log.Debugf("got disconnected block at height %d: %v", height,
+		hash)
+
+	filteredBlock := &FilteredBlock{
+		Hash:   hash,
+		Height: uint32(height),
+	}
+
+	b.blockQueue.Add(&blockEvent{
+		eventType: disconnected,
+		block:     filteredBlock,
+	})
+}
+
+// FilterBlock takes a block hash, and returns a FilteredBlocks which is the
+// result of applying the current registered UTXO sub-set on the block
+// corresponding to that block hash. If any watched UTXO's are spent by the
+// selected block, then the internal chainFilter will also be updated.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (b *BitcoindCfFilteredChainView) FilterBlock(
+	blockHash *chainhash.Hash) (*FilteredBlock, error) {
+
+	header, err := b.chainClient.GetBlockHeaderVerbose(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.filterBlock(blockHash, uint32(header.Height))
+}
+
+// chainFilterer is the primary goroutine which: listens for new blocks coming
+// and dispatches the relevant FilteredBlock notifications, and updates the
+// filter due to requests by callers, rescanning past blocks if needed.
+func (b *BitcoindCfFilteredChainView) chainFilterer() {
+	defer b.wg.Done()
+
+	for {
+		select {
+		// The caller has just sent an update to the current chain
+		// filter, so we'll apply the update, possibly rewinding our
+		// state partially.
+		case update := <-b.filterUpdates:
+			b.bestHeightMtx.Lock()
+			bestHeight := b.bestHeight
+			b.bestHeightMtx.Unlock()
+
+			// Starting from the height _after_ the update height,
+			// we'll walk forwards, matching the filter of one block
+			// at a time against the newly added outputs.
+			for i := update.updateHeight + 1; i < bestHeight+1; i++ {
+				blockHash, err := b.chainClient.GetBlockHash(
+					int64(i),
+				)
+				if err != nil {
+					log.Warnf("Unable to get block hash "+
+						"for block at height %d: %v",
+						i, err)
+					continue
+				}
+
+				filtered, err := b.filterBlock(blockHash, i)
+				if err != nil {
+					log.Warnf("Unable to rescan block "+
+						"with hash %v at height %d: %v",
+						blockHash, i, err)
+					continue
+				}
+
+				if len(filtered.Transactions) == 0 {
+					continue
+				}
+
+				b.blockQueue.Add(&blockEvent{
+					eventType: connected,
+					block:     filtered,
+				})
+			}
+
+		// We've received a new event from the chain client.
+		case event := <-b.chainClient.Notifications():
+			switch e := event.(type) {
+
+			case chain.BlockConnected:
+				b.onBlockConnected(e.Height, e.Hash)
+
+			case chain.BlockDisconnected:
+				b.onBlockDisconnected(e.Height, e.Hash)
+			}
+
+		case <-b.quit:
+			return
+		}
+	}
+}
+
+// UpdateFilter updates the UTXO filter which is to be consulted when creating
+// FilteredBlocks to be sent to subscribed clients. This method is cumulative
+// meaning repeated calls to this method should _expand_ the size of the UTXO
+// sub-set currently being watched.  If the set updateHeight is _lower_ than
+// the best known height of the implementation, then the state should be
+// rewound to ensure all relevant notifications are dispatched.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (b *BitcoindCfFilteredChainView) UpdateFilter(ops []channeldb.EdgePoint,
+	updateHeight uint32) error {
+
+	log.Tracef("Updating chain filter with new UTXO's: %v", ops)
+
+	// We'll add the new outputs to the chain filter right away, such that
+	// they're accounted for by the blocks connected from now on.
+	newUtxos := make([]wire.OutPoint, len(ops))
+	b.filterMtx.Lock()
+	for i, op := range ops {
+		b.chainFilter[op.OutPoint] = op.FundingPkScript
+		newUtxos[i] = op.OutPoint
+	}
+	b.filterMtx.Unlock()
+
+	select {
+
+	case b.filterUpdates <- filterUpdate{
+		newUtxos:     newUtxos,
+		updateHeight: updateHeight,
+	}:
+		return nil
+
+	case <-b.quit:
+		return fmt.Errorf("chain filter shutting down")
+	}
+}
+
+// FilteredBlocks returns the channel that filtered blocks are to be sent over.
+// Each time a block is connected to the end of a main chain, and appropriate
+// FilteredBlock which contains the transactions which mutate our watched UTXO
+// set is to be returned.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (b *BitcoindCfFilteredChainView) FilteredBlocks() <-chan *FilteredBlock {
+	return b.blockQueue.newBlocks
+}
+
+// DisconnectedBlocks returns a receive only channel which will be sent upon
+// with the empty filtered blocks of blocks which are disconnected from the
+// main chain in the case of a re-org.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (b *BitcoindCfFilteredChainView) DisconnectedBlocks() <-chan *FilteredBlock {
+	return b.blockQueue.staleBlocks
+}
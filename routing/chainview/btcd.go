@@ -30,6 +30,10 @@ type BtcdFilteredChainView struct {
 
 	btcdConn *rpcclient.Client
 
+	// blockCache holds the blocks recently fetched from the backend, and
+	// may be shared with other chain views.
+	blockCache *BlockCache
+
 	// blockEventQueue is the ordered queue used to keep the order
 	// of connected and disconnected blocks sent to the reader of the
 	// chainView.
@@ -57,9 +61,13 @@ type BtcdFilteredChainView struct {
 var _ FilteredChainView = (*BtcdFilteredChainView)(nil)
 
 // NewBtcdFilteredChainView creates a new instance of a FilteredChainView from
-// RPC credentials for an active btcd instance.
-func NewBtcdFilteredChainView(config rpcclient.ConnConfig) (*BtcdFilteredChainView, error) {
+// RPC credentials for an active btcd instance. Blocks are fetched through the
+// passed cache, which may be nil.
+func NewBtcdFilteredChainView(config rpcclient.ConnConfig,
+	blockCache *BlockCache) (*BtcdFilteredChainView, error) {
+
 	chainView := &BtcdFilteredChainView{
+		blockCache:      blockCache,
 		chainFilter:     make(map[wire.OutPoint]struct{}),
 		filterUpdates:   make(chan filterUpdate),
 		filterBlockReqs: make(chan *filterBlockReq),
@@ -404,7 +412,9 @@ func (b *BtcdFilteredChainView) chainFilterer() {
 		case req := <-b.filterBlockReqs:
 			// First we'll fetch the block itself as well as some
 			// additional information including its height.
-			block, err := b.btcdConn.GetBlock(req.blockHash)
+			block, err := b.blockCache.FetchBlock(
+				req.blockHash, b.btcdConn.GetBlock,
+			)
 			if err != nil {
 				req.err <- err
 				req.resp <- nil
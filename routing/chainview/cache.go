@@ -0,0 +1,200 @@
+package chainview
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil/gcs"
+)
+
+// DefaultBlockCacheSize is the default number of bytes worth of blocks and
+// compact filters retained by a BlockCache.
+const DefaultBlockCacheSize = 20 * 1024 * 1024
+
+// cacheKind distinguishes the different kinds of items cached per block.
+type cacheKind uint8
+
+const (
+	// cachedBlock denotes a full block.
+	cachedBlock cacheKind = iota
+
+	// cachedFilter denotes the basic compact filter of a block.
+	cachedFilter
+)
+
+// cacheKey identifies an item within the BlockCache.
+type cacheKey struct {
+	hash chainhash.Hash
+	kind cacheKind
+}
+
+// cacheEntry is an item held within the BlockCache, along with the number of
+// bytes it's accounted for.
+type cacheEntry struct {
+	key   cacheKey
+	value interface{}
+	size  int
+}
+
+// cacheFetch is a fetch of an item from the backend that is in progress.
+// Requests for the same item arriving in the meantime wait for it to complete
+// rather than fetching the item themselves.
+type cacheFetch struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// BlockCache is a size-bounded cache of blocks and compact filters, which can
+// be shared among several FilteredChainView instances. Once the total size of
+// its items exceeds the capacity, the least recently used ones are evicted.
+// Concurrent requests for an item that isn't cached yet result in a single
+// fetch from the chain backend.
+//
+// Items returned by the cache are shared among all its users, and must not be
+// modified. A nil BlockCache is valid, and simply fetches each item from the
+// backend.
+type BlockCache struct {
+	capacity int
+
+	mtx      sync.Mutex
+	size     int
+	entries  map[cacheKey]*list.Element
+	lru      *list.List
+	inflight map[cacheKey]*cacheFetch
+}
+
+// NewBlockCache creates a new BlockCache that retains up to capacity bytes of
+// blocks and filters.
+func NewBlockCache(capacity int) *BlockCache {
+	return &BlockCache{
+		capacity: capacity,
+		entries:  make(map[cacheKey]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[cacheKey]*cacheFetch),
+	}
+}
+
+// FetchBlock returns the block with the given hash, using fetchBlock to
+// retrieve it from the backend if it isn't cached.
+func (c *BlockCache) FetchBlock(hash *chainhash.Hash,
+	fetchBlock func(*chainhash.Hash) (*wire.MsgBlock, error)) (
+	*wire.MsgBlock, error) {
+
+	key := cacheKey{hash: *hash, kind: cachedBlock}
+	value, err := c.fetch(key, func() (interface{}, int, error) {
+		block, err := fetchBlock(hash)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return block, block.SerializeSize(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*wire.MsgBlock), nil
+}
+
+// FetchFilter returns the basic compact filter of the block with the given
+// hash, using fetchFilter to retrieve it from the backend if it isn't cached.
+func (c *BlockCache) FetchFilter(hash *chainhash.Hash,
+	fetchFilter func(*chainhash.Hash) (*gcs.Filter, error)) (
+	*gcs.Filter, error) {
+
+	key := cacheKey{hash: *hash, kind: cachedFilter}
+	value, err := c.fetch(key, func() (interface{}, int, error) {
+		filter, err := fetchFilter(hash)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		filterBytes, err := filter.NBytes()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return filter, len(filterBytes), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*gcs.Filter), nil
+}
+
+// fetch returns the cached item with the given key. If it isn't cached, it's
+// retrieved using fetchItem, which also returns its size, unless a fetch of
+// the same item is already in progress, in which case we'll wait for its
+// result instead.
+func (c *BlockCache) fetch(key cacheKey,
+	fetchItem func() (interface{}, int, error)) (interface{}, error) {
+
+	if c == nil {
+		value, _, err := fetchItem()
+		return value, err
+	}
+
+	c.mtx.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		c.mtx.Unlock()
+
+		return elem.Value.(*cacheEntry).value, nil
+	}
+
+	if inflight, ok := c.inflight[key]; ok {
+		c.mtx.Unlock()
+
+		<-inflight.done
+		return inflight.value, inflight.err
+	}
+
+	inflight := &cacheFetch{
+		done: make(chan struct{}),
+	}
+	c.inflight[key] = inflight
+	c.mtx.Unlock()
+
+	value, size, err := fetchItem()
+	inflight.value = value
+	inflight.err = err
+
+	c.mtx.Lock()
+	delete(c.inflight, key)
+	if err == nil {
+		c.add(key, value, size)
+	}
+	c.mtx.Unlock()
+
+	close(inflight.done)
+
+	return value, err
+}
+
+// add inserts an item into the cache, evicting the least recently used items
+// until it fits. Items larger than the capacity of the cache aren't added.
+//
+// NOTE: This method MUST be called with the cache's mutex held.
+func (c *BlockCache) add(key cacheKey, value interface{}, size int) {
+	if size > c.capacity {
+		return
+	}
+
+	for c.size+size > c.capacity {
+		oldest := c.lru.Back()
+		entry := c.lru.Remove(oldest).(*cacheEntry)
+		delete(c.entries, entry.key)
+		c.size -= entry.size
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{
+		key:   key,
+		value: value,
+		size:  size,
+	})
+	c.size += size
+}
@@ -0,0 +1,139 @@
+package chainview
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+)
+
+// TestBlockCacheEviction ensures that the BlockCache evicts its least
+// recently used blocks once its capacity is exceeded, and doesn't cache
+// blocks that couldn't be fetched.
+func TestBlockCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	blocks := make(map[chainhash.Hash]*wire.MsgBlock)
+	hashes := make([]chainhash.Hash, 4)
+	for i := range hashes {
+		block := &wire.MsgBlock{
+			Header: wire.BlockHeader{Nonce: uint32(i)},
+		}
+		hashes[i] = block.BlockHash()
+		blocks[hashes[i]] = block
+	}
+	blockSize := blocks[hashes[0]].SerializeSize()
+	cache := NewBlockCache(3 * blockSize)
+
+	var (
+		fetches  int
+		fetchErr error
+	)
+	fetchBlock := func(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+		fetches++
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return blocks[*hash], nil
+	}
+
+	assertFetch := func(i int, fetched bool) {
+		t.Helper()
+
+		numFetches := fetches
+		block, err := cache.FetchBlock(&hashes[i], fetchBlock)
+		if err != nil {
+			t.Fatalf("unable to fetch block %d: %v", i, err)
+		}
+		if block != blocks[hashes[i]] {
+			t.Fatalf("wrong block returned for block %d", i)
+		}
+		if (fetches != numFetches) != fetched {
+			t.Fatalf("expected block %d to be fetched: %v", i,
+				fetched)
+		}
+	}
+
+	// The first three blocks fit into the cache, so they should only be
+	// fetched from the backend once.
+	for i := 0; i < 3; i++ {
+		assertFetch(i, true)
+	}
+	for i := 0; i < 3; i++ {
+		assertFetch(i, false)
+	}
+
+	// Adding the fourth block evicts the least recently used one, which is
+	// the first block.
+	assertFetch(3, true)
+	assertFetch(1, false)
+	assertFetch(0, true)
+
+	// The second block was used more recently than the third, so the
+	// latter should have made room for the first block.
+	assertFetch(1, false)
+	assertFetch(2, true)
+
+	// Errors should be returned as is, without caching anything.
+	fetchErr = errors.New("unavailable")
+	if _, err := cache.FetchBlock(&hashes[3], fetchBlock); err != fetchErr {
+		t.Fatalf("expected error %v, got %v", fetchErr, err)
+	}
+	fetchErr = nil
+	assertFetch(1, true)
+}
+
+// TestBlockCacheConcurrentFetch ensures that concurrent requests for a block
+// that isn't cached yet only result in a single fetch from the backend.
+func TestBlockCacheConcurrentFetch(t *testing.T) {
+	t.Parallel()
+
+	block := &wire.MsgBlock{}
+	hash := block.BlockHash()
+
+	var fetches int32
+	release := make(chan struct{})
+	fetchBlock := func(*chainhash.Hash) (*wire.MsgBlock, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return block, nil
+	}
+
+	cache := NewBlockCache(DefaultBlockCacheSize)
+
+	const numRequests = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, numRequests)
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			fetched, err := cache.FetchBlock(&hash, fetchBlock)
+			if err == nil && fetched != block {
+				err = errors.New("wrong block returned")
+			}
+			errs <- err
+		}()
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unable to fetch block: %v", err)
+		}
+	}
+
+	// As the first fetch may complete before all requests have been made,
+	// the block might be served from the cache rather than the fetch in
+	// progress, but either way it should only be fetched once.
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("expected block to be fetched once, got %d", n)
+	}
+}
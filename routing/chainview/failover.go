@@ -0,0 +1,614 @@
+package chainview
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/ticker"
+)
+
+// DefaultFailoverCheckInterval is the default interval at which the
+// availability of the backends of a FailoverFilteredChainView is checked.
+const DefaultFailoverCheckInterval = 30 * time.Second
+
+// maxFailoverReorgDepth is the number of most recently dispatched blocks the
+// FailoverFilteredChainView keeps track of, in order to detect a reorg that a
+// backend went through while another one was active.
+const maxFailoverReorgDepth = 100
+
+// ErrNoBackendAvailable is returned when none of the backends of a
+// FailoverFilteredChainView is available.
+var ErrNoBackendAvailable = errors.New("no chain backend available")
+
+// ChainSource provides the chain queries used to check the availability of a
+// backend of a FailoverFilteredChainView, and to fetch the blocks that were
+// missed while failing over to it.
+type ChainSource interface {
+	// GetBestBlock returns the hash and height of the best block known to
+	// the backend.
+	GetBestBlock() (*chainhash.Hash, int32, error)
+
+	// GetBlockHash returns the hash of the block at the given height
+	// within the main chain of the backend.
+	GetBlockHash(height int64) (*chainhash.Hash, error)
+}
+
+// FailoverBackend is one of the backends of a FailoverFilteredChainView.
+type FailoverBackend struct {
+	// Name identifies the backend within log messages.
+	Name string
+
+	// ChainView is the chain view backed by the backend.
+	ChainView FilteredChainView
+
+	// Chain queries the chain through the same backend.
+	Chain ChainSource
+}
+
+// backendEvent is a block event received from one of the backends of a
+// FailoverFilteredChainView.
+type backendEvent struct {
+	backend int
+	event   *blockEvent
+}
+
+// FailoverFilteredChainView is an implementation of the FilteredChainView
+// interface that wraps a primary backend and one or more fallback backends.
+// All backends watch the same UTXO filter, but only the notifications of the
+// active backend are dispatched. The availability of the backends is checked
+// periodically, and if the active backend can no longer be reached, we fail
+// over to the first available backend in the order they were passed in. Once
+// a preferred backend becomes available again, we switch back to it.
+//
+// Whenever we switch backends, any reorg of the new backend's chain relative
+// to the blocks we've dispatched is reflected as disconnected blocks, and the
+// blocks we missed are filtered through the new backend, such that the reader
+// keeps receiving an uninterrupted sequence of blocks.
+type FailoverFilteredChainView struct {
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	backends []*FailoverBackend
+
+	// running marks the backends that were started successfully. Only
+	// these are ever made active.
+	running []bool
+
+	// active is the index of the backend whose notifications are
+	// dispatched.
+	activeMtx sync.RWMutex
+	active    int
+
+	// checkTicker is used to periodically check the availability of the
+	// backends.
+	checkTicker ticker.Ticker
+
+	// bestHeight is the height of the latest connected block dispatched
+	// to the reader, and hashes holds the hashes of the latest
+	// maxFailoverReorgDepth dispatched blocks, indexed by height. Both are
+	// only accessed by the dispatcher goroutine once started.
+	bestHeight uint32
+	hashes     map[uint32]chainhash.Hash
+
+	// chainFilter holds every output we're watching spends of, such that
+	// a backend that was unavailable while outputs were added can be
+	// brought up to date once we fail over to it.
+	filterMtx   sync.Mutex
+	chainFilter map[wire.OutPoint]channeldb.EdgePoint
+
+	// blockQueue is the ordered queue used to keep the order of connected
+	// and disconnected blocks sent to the reader of the chainView.
+	blockQueue *blockEventQueue
+
+	// events receives the block events of all backends.
+	events chan *backendEvent
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// A compile time check to ensure FailoverFilteredChainView implements the
+// chainview.FilteredChainView.
+var _ FilteredChainView = (*FailoverFilteredChainView)(nil)
+
+// NewFailoverFilteredChainView creates a new FailoverFilteredChainView from
+// the given backends, ordered by preference. The availability of the backends
+// is checked each time the passed ticker fires.
+func NewFailoverFilteredChainView(backends []*FailoverBackend,
+	checkTicker ticker.Ticker) (*FailoverFilteredChainView, error) {
+
+	if len(backends) == 0 {
+		return nil, errors.New("at least one chain backend must be " +
+			"specified")
+	}
+
+	return &FailoverFilteredChainView{
+		backends:    backends,
+		running:     make([]bool, len(backends)),
+		checkTicker: checkTicker,
+		hashes:      make(map[uint32]chainhash.Hash),
+		chainFilter: make(map[wire.OutPoint]channeldb.EdgePoint),
+		blockQueue:  newBlockEventQueue(),
+		events:      make(chan *backendEvent),
+		quit:        make(chan struct{}),
+	}, nil
+}
+
+// Start starts all backends that are currently reachable, and the goroutines
+// necessary for normal operation. The first available backend is made active.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (f *FailoverFilteredChainView) Start() error {
+	// Already started?
+	if atomic.AddInt32(&f.started, 1) != 1 {
+		return nil
+	}
+
+	log.Infof("FilteredChainView starting")
+
+	// A backend that can't be started is unavailable for the lifetime of
+	// the chain view, but we'll still be able to operate as long as one
+	// of them can.
+	active := -1
+	var (
+		bestHash   *chainhash.Hash
+		bestHeight int32
+	)
+	for i, backend := range f.backends {
+		if err := backend.ChainView.Start(); err != nil {
+			log.Errorf("Unable to start chain backend %v: %v",
+				backend.Name, err)
+			continue
+		}
+		f.running[i] = true
+
+		if active != -1 {
+			continue
+		}
+
+		hash, height, err := backend.Chain.GetBestBlock()
+		if err != nil {
+			log.Warnf("Chain backend %v unavailable: %v",
+				backend.Name, err)
+			continue
+		}
+
+		active = i
+		bestHash, bestHeight = hash, height
+	}
+	if active == -1 {
+		f.stopBackends()
+		return ErrNoBackendAvailable
+	}
+
+	log.Infof("Using chain backend %v", f.backends[active].Name)
+
+	f.active = active
+	f.bestHeight = uint32(bestHeight)
+	f.hashes[f.bestHeight] = *bestHash
+
+	f.blockQueue.Start()
+
+	for i := range f.backends {
+		if !f.running[i] {
+			continue
+		}
+
+		f.wg.Add(1)
+		go f.forwardEvents(i)
+	}
+
+	f.checkTicker.Resume()
+
+	f.wg.Add(1)
+	go f.dispatcher()
+
+	return nil
+}
+
+// Stop stops all backends and goroutines which we launched by the prior call
+// to the Start method.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (f *FailoverFilteredChainView) Stop() error {
+	// Already shutting down?
+	if atomic.AddInt32(&f.stopped, 1) != 1 {
+		return nil
+	}
+
+	log.Infof("FilteredChainView stopping")
+
+	close(f.quit)
+	f.checkTicker.Stop()
+	f.wg.Wait()
+
+	f.stopBackends()
+	f.blockQueue.Stop()
+
+	return nil
+}
+
+// stopBackends stops all backends that were started.
+func (f *FailoverFilteredChainView) stopBackends() {
+	for i, backend := range f.backends {
+		if !f.running[i] {
+			continue
+		}
+
+		if err := backend.ChainView.Stop(); err != nil {
+			log.Errorf("Unable to stop chain backend %v: %v",
+				backend.Name, err)
+		}
+	}
+}
+
+// activeBackend returns the index of the active backend.
+func (f *FailoverFilteredChainView) activeBackend() int {
+	f.activeMtx.RLock()
+	defer f.activeMtx.RUnlock()
+
+	return f.active
+}
+
+// forwardEvents hands the block events of the backend with the given index
+// off to the dispatcher. The events of all backends are received, even
+// though only those of the active backend are dispatched, as otherwise the
+// inactive backends would stall.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *FailoverFilteredChainView) forwardEvents(backend int) {
+	defer f.wg.Done()
+
+	chainView := f.backends[backend].ChainView
+	for {
+		var event *blockEvent
+		select {
+		case block := <-chainView.FilteredBlocks():
+			event = &blockEvent{
+				eventType: connected,
+				block:     block,
+			}
+
+		case block := <-chainView.DisconnectedBlocks():
+			event = &blockEvent{
+				eventType: disconnected,
+				block:     block,
+			}
+
+		case <-f.quit:
+			return
+		}
+
+		select {
+		case f.events <- &backendEvent{backend, event}:
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// dispatcher is the main goroutine of the FailoverFilteredChainView. It
+// dispatches the block events of the active backend, and periodically checks
+// the availability of the backends.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *FailoverFilteredChainView) dispatcher() {
+	defer f.wg.Done()
+
+	for {
+		select {
+		case e := <-f.events:
+			if e.backend != f.activeBackend() {
+				continue
+			}
+
+			f.handleEvent(e.backend, e.event)
+
+		case <-f.checkTicker.Ticks():
+			f.checkBackends()
+
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// handleEvent dispatches a block event of the active backend, unless it
+// conflicts with the blocks dispatched so far. Such events may be received
+// shortly after switching backends, as they may have been queued before.
+func (f *FailoverFilteredChainView) handleEvent(backend int,
+	event *blockEvent) {
+
+	block := event.block
+
+	switch {
+	case event.eventType == disconnected:
+		hash, ok := f.hashes[block.Height]
+		if block.Height != f.bestHeight || !ok || hash != block.Hash {
+			return
+		}
+
+		f.dispatchDisconnected(block.Height)
+
+	// Blocks at or below our best height are either resent after a filter
+	// update, or were already dispatched while catching up. We'll only
+	// pass on the former, which the reader expects as updates to known
+	// blocks that contain newly matched transactions.
+	case block.Height <= f.bestHeight:
+		hash, ok := f.hashes[block.Height]
+		if !ok || hash != block.Hash || len(block.Transactions) == 0 {
+			return
+		}
+
+		f.blockQueue.Add(event)
+
+	default:
+		// If the backend skipped any blocks, we'll fetch them
+		// ourselves, as the reader requires blocks to be connected in
+		// order.
+		if block.Height > f.bestHeight+1 {
+			err := f.catchUp(backend, block.Height-1)
+			if err != nil {
+				log.Errorf("Unable to fetch blocks missed by "+
+					"chain backend %v: %v",
+					f.backends[backend].Name, err)
+			}
+		}
+
+		f.dispatchConnected(block)
+	}
+}
+
+// dispatchConnected dispatches a connected block to the reader, and records
+// it as our best block.
+func (f *FailoverFilteredChainView) dispatchConnected(block *FilteredBlock) {
+	f.filterMtx.Lock()
+	for _, tx := range block.Transactions {
+		for _, txIn := range tx.TxIn {
+			delete(f.chainFilter, txIn.PreviousOutPoint)
+		}
+	}
+	f.filterMtx.Unlock()
+
+	f.bestHeight = block.Height
+	f.hashes[block.Height] = block.Hash
+	delete(f.hashes, block.Height-maxFailoverReorgDepth)
+
+	f.blockQueue.Add(&blockEvent{
+		eventType: connected,
+		block:     block,
+	})
+}
+
+// dispatchDisconnected dispatches the disconnection of our best block at the
+// given height to the reader.
+func (f *FailoverFilteredChainView) dispatchDisconnected(height uint32) {
+	hash := f.hashes[height]
+	delete(f.hashes, height)
+	f.bestHeight = height - 1
+
+	f.blockQueue.Add(&blockEvent{
+		eventType: disconnected,
+		block: &FilteredBlock{
+			Hash:   hash,
+			Height: height,
+		},
+	})
+}
+
+// catchUp filters the blocks after our best block up to the given height
+// through the given backend, and dispatches them.
+func (f *FailoverFilteredChainView) catchUp(backend int, height uint32) error {
+	b := f.backends[backend]
+	for f.bestHeight < height {
+		hash, err := b.Chain.GetBlockHash(int64(f.bestHeight + 1))
+		if err != nil {
+			return err
+		}
+
+		block, err := b.ChainView.FilterBlock(hash)
+		if err != nil {
+			return err
+		}
+
+		f.dispatchConnected(block)
+	}
+
+	return nil
+}
+
+// checkBackends checks the availability of the backends in order of
+// preference, and switches to the first one available if it isn't the
+// active one already.
+func (f *FailoverFilteredChainView) checkBackends() {
+	active := f.activeBackend()
+	for i, backend := range f.backends {
+		if !f.running[i] {
+			continue
+		}
+
+		_, _, err := backend.Chain.GetBestBlock()
+		if err != nil {
+			log.Warnf("Chain backend %v unavailable: %v",
+				backend.Name, err)
+			continue
+		}
+
+		if i == active {
+			return
+		}
+
+		if err := f.switchBackend(i); err != nil {
+			log.Errorf("Unable to switch to chain backend %v: %v",
+				backend.Name, err)
+			continue
+		}
+
+		return
+	}
+
+	log.Errorf("Unable to check for closed channels: %v",
+		ErrNoBackendAvailable)
+}
+
+// switchBackend makes the backend with the given index the active one. The
+// backend is made to watch our entire filter, after which the blocks we
+// dispatched that are no longer part of its chain are disconnected, and the
+// blocks we missed are filtered through it.
+func (f *FailoverFilteredChainView) switchBackend(backend int) error {
+	b := f.backends[backend]
+
+	_, bestHeight, err := b.Chain.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	// If the backend hasn't caught up with the blocks we've dispatched,
+	// it's still syncing, so we can't rely on it yet.
+	if uint32(bestHeight) < f.bestHeight {
+		return fmt.Errorf("backend at height %d is behind our best "+
+			"height %d", bestHeight, f.bestHeight)
+	}
+
+	// As the backend may have been unavailable while outputs were added
+	// to our filter, we'll make sure it's watching all of them.
+	f.filterMtx.Lock()
+	ops := make([]channeldb.EdgePoint, 0, len(f.chainFilter))
+	for _, op := range f.chainFilter {
+		ops = append(ops, op)
+	}
+	f.filterMtx.Unlock()
+
+	if len(ops) > 0 {
+		err := b.ChainView.UpdateFilter(ops, uint32(bestHeight))
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Switching chain backend from %v to %v",
+		f.backends[f.activeBackend()].Name, b.Name)
+
+	f.activeMtx.Lock()
+	f.active = backend
+	f.activeMtx.Unlock()
+
+	// If the backend's chain forked off from the blocks we dispatched,
+	// we'll disconnect those that are no longer part of it.
+	for {
+		hash, ok := f.hashes[f.bestHeight]
+		if !ok {
+			break
+		}
+
+		backendHash, err := b.Chain.GetBlockHash(int64(f.bestHeight))
+		if err != nil {
+			return err
+		}
+		if *backendHash == hash {
+			break
+		}
+
+		f.dispatchDisconnected(f.bestHeight)
+	}
+
+	return f.catchUp(backend, uint32(bestHeight))
+}
+
+// FilterBlock takes a block hash, and returns a FilteredBlocks which is the
+// result of applying the current registered UTXO sub-set on the block
+// corresponding to that block hash. The block is filtered by the active
+// backend, or if that fails, by the other backends in order of preference.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (f *FailoverFilteredChainView) FilterBlock(
+	blockHash *chainhash.Hash) (*FilteredBlock, error) {
+
+	active := f.activeBackend()
+	block, err := f.backends[active].ChainView.FilterBlock(blockHash)
+	if err == nil {
+		return block, nil
+	}
+
+	for i, backend := range f.backends {
+		if i == active || !f.running[i] {
+			continue
+		}
+
+		block, err := backend.ChainView.FilterBlock(blockHash)
+		if err != nil {
+			continue
+		}
+
+		return block, nil
+	}
+
+	return nil, err
+}
+
+// UpdateFilter updates the UTXO filter which is to be consulted when creating
+// FilteredBlocks to be sent to subscribed clients. This method is cumulative
+// meaning repeated calls to this method should _expand_ the size of the UTXO
+// sub-set currently being watched.  If the set updateHeight is _lower_ than
+// the best known height of the implementation, then the state should be
+// rewound to ensure all relevant notifications are dispatched.
+//
+// The filter of each backend is updated, but only a failure of the active
+// backend is returned.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (f *FailoverFilteredChainView) UpdateFilter(ops []channeldb.EdgePoint,
+	updateHeight uint32) error {
+
+	f.filterMtx.Lock()
+	for _, op := range ops {
+		f.chainFilter[op.OutPoint] = op
+	}
+	f.filterMtx.Unlock()
+
+	active := f.activeBackend()
+
+	var activeErr error
+	for i, backend := range f.backends {
+		if !f.running[i] {
+			continue
+		}
+
+		err := backend.ChainView.UpdateFilter(ops, updateHeight)
+		switch {
+		case err != nil && i == active:
+			activeErr = err
+
+		case err != nil:
+			log.Warnf("Unable to update filter of chain backend "+
+				"%v: %v", backend.Name, err)
+		}
+	}
+
+	return activeErr
+}
+
+// FilteredBlocks returns the channel that filtered blocks are to be sent over.
+// Each time a block is connected to the end of a main chain, and appropriate
+// FilteredBlock which contains the transactions which mutate our watched UTXO
+// set is to be returned.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (f *FailoverFilteredChainView) FilteredBlocks() <-chan *FilteredBlock {
+	return f.blockQueue.newBlocks
+}
+
+// DisconnectedBlocks returns a receive only channel which will be sent upon
+// with the empty filtered blocks of blocks which are disconnected from the
+// main chain in the case of a re-org.
+//
+// NOTE: This is part of the FilteredChainView interface.
+func (f *FailoverFilteredChainView) DisconnectedBlocks() <-chan *FilteredBlock {
+	return f.blockQueue.staleBlocks
+}
@@ -0,0 +1,284 @@
+package chainview
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/ticker"
+)
+
+// mockChain is a ChainSource whose chain consists of the blocks with the
+// given hashes, indexed by height.
+type mockChain struct {
+	sync.Mutex
+	hashes []chainhash.Hash
+	err    error
+}
+
+// testBlockHash returns the hash of the test block at the given height on the
+// branch identified by fork.
+func testBlockHash(height uint32, fork byte) chainhash.Hash {
+	return chainhash.Hash{byte(height), byte(height >> 8), fork}
+}
+
+func newMockChain(height uint32) *mockChain {
+	c := &mockChain{}
+	c.extend(height, 0)
+	return c
+}
+
+// fork replaces the blocks from the first given height onwards with blocks of
+// the given fork, up to the second given height.
+func (c *mockChain) fork(from, to uint32, fork byte) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.hashes = c.hashes[:from]
+	for h := from; h <= to; h++ {
+		c.hashes = append(c.hashes, testBlockHash(h, fork))
+	}
+}
+
+// extend extends the chain up to the given height with blocks of the given
+// fork.
+func (c *mockChain) extend(to uint32, fork byte) {
+	c.Lock()
+	from := uint32(len(c.hashes))
+	c.Unlock()
+
+	c.fork(from, to, fork)
+}
+
+func (c *mockChain) setErr(err error) {
+	c.Lock()
+	c.err = err
+	c.Unlock()
+}
+
+func (c *mockChain) GetBestBlock() (*chainhash.Hash, int32, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.err != nil {
+		return nil, 0, c.err
+	}
+
+	height := len(c.hashes) - 1
+	return &c.hashes[height], int32(height), nil
+}
+
+func (c *mockChain) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	if height >= int64(len(c.hashes)) {
+		return nil, errors.New("block not found")
+	}
+
+	return &c.hashes[height], nil
+}
+
+// mockChainView is a FilteredChainView whose blocks are connected manually.
+type mockChainView struct {
+	chain      *mockChain
+	blockQueue *blockEventQueue
+
+	mu      sync.Mutex
+	updates [][]channeldb.EdgePoint
+}
+
+func newMockChainView(chain *mockChain) *mockChainView {
+	return &mockChainView{
+		chain:      chain,
+		blockQueue: newBlockEventQueue(),
+	}
+}
+
+// connect notifies the block of the chain at the given height.
+func (m *mockChainView) connect(height uint32) {
+	m.chain.Lock()
+	hash := m.chain.hashes[height]
+	m.chain.Unlock()
+
+	m.blockQueue.Add(&blockEvent{
+		eventType: connected,
+		block: &FilteredBlock{
+			Hash:   hash,
+			Height: height,
+		},
+	})
+}
+
+func (m *mockChainView) numUpdates() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.updates)
+}
+
+func (m *mockChainView) FilteredBlocks() <-chan *FilteredBlock {
+	return m.blockQueue.newBlocks
+}
+
+func (m *mockChainView) DisconnectedBlocks() <-chan *FilteredBlock {
+	return m.blockQueue.staleBlocks
+}
+
+func (m *mockChainView) UpdateFilter(ops []channeldb.EdgePoint,
+	_ uint32) error {
+
+	m.mu.Lock()
+	m.updates = append(m.updates, ops)
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *mockChainView) FilterBlock(
+	blockHash *chainhash.Hash) (*FilteredBlock, error) {
+
+	m.chain.Lock()
+	defer m.chain.Unlock()
+
+	for height, hash := range m.chain.hashes {
+		if hash == *blockHash {
+			return &FilteredBlock{
+				Hash:   hash,
+				Height: uint32(height),
+			}, nil
+		}
+	}
+
+	return nil, errors.New("block not found")
+}
+
+func (m *mockChainView) Start() error {
+	m.blockQueue.Start()
+	return nil
+}
+
+func (m *mockChainView) Stop() error {
+	m.blockQueue.Stop()
+	return nil
+}
+
+// assertBlock asserts that the block at the given height of the given fork is
+// received on the passed channel.
+func assertBlock(t *testing.T, blocks <-chan *FilteredBlock, height uint32,
+	fork byte) {
+
+	t.Helper()
+
+	select {
+	case block := <-blocks:
+		if block.Height != height ||
+			block.Hash != testBlockHash(height, fork) {
+
+			t.Fatalf("expected block %d of fork %d, got block "+
+				"%d: %v", height, fork, block.Height,
+				block.Hash)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("block %d of fork %d not received", height, fork)
+	}
+}
+
+// TestFailoverChainView ensures that the FailoverFilteredChainView fails over
+// to its fallback backend when the primary one becomes unavailable, catching
+// up on the blocks it missed and the reorgs it didn't see, and that it
+// switches back once the primary backend is available again.
+func TestFailoverChainView(t *testing.T) {
+	t.Parallel()
+
+	primaryChain := newMockChain(100)
+	fallbackChain := newMockChain(100)
+	primary := newMockChainView(primaryChain)
+	fallback := newMockChainView(fallbackChain)
+
+	checkTicker := ticker.NewForce(DefaultFailoverCheckInterval)
+	chainView, err := NewFailoverFilteredChainView([]*FailoverBackend{
+		{
+			Name:      "primary",
+			ChainView: primary,
+			Chain:     primaryChain,
+		},
+		{
+			Name:      "fallback",
+			ChainView: fallback,
+			Chain:     fallbackChain,
+		},
+	}, checkTicker)
+	if err != nil {
+		t.Fatalf("unable to create chain view: %v", err)
+	}
+	if err := chainView.Start(); err != nil {
+		t.Fatalf("unable to start chain view: %v", err)
+	}
+	defer chainView.Stop()
+
+	// The filter of both backends should be updated.
+	ops := []channeldb.EdgePoint{{
+		OutPoint: wire.OutPoint{Index: 1},
+	}}
+	if err := chainView.UpdateFilter(ops, 100); err != nil {
+		t.Fatalf("unable to update filter: %v", err)
+	}
+	if primary.numUpdates() != 1 || fallback.numUpdates() != 1 {
+		t.Fatalf("expected filter of both backends to be updated")
+	}
+
+	// A block connected by the primary backend should be dispatched.
+	primaryChain.extend(101, 0)
+	fallbackChain.extend(101, 0)
+	primary.connect(101)
+	assertBlock(t, chainView.FilteredBlocks(), 101, 0)
+
+	// Now, we'll make the primary backend unavailable, while the fallback
+	// reorgs out block 101 and extends its chain to height 102.
+	primaryChain.setErr(errors.New("unavailable"))
+	fallbackChain.fork(101, 102, 1)
+	checkTicker.Force <- time.Time{}
+
+	// Once we fail over, block 101 should be disconnected, and the blocks
+	// of the fallback's chain should be connected.
+	assertBlock(t, chainView.DisconnectedBlocks(), 101, 0)
+	assertBlock(t, chainView.FilteredBlocks(), 101, 1)
+	assertBlock(t, chainView.FilteredBlocks(), 102, 1)
+
+	// The fallback should have been made to watch our entire filter.
+	if fallback.numUpdates() != 2 {
+		t.Fatalf("expected filter of fallback to be updated")
+	}
+
+	// The blocks connected by the fallback are now dispatched.
+	fallbackChain.extend(103, 1)
+	fallback.connect(103)
+	assertBlock(t, chainView.FilteredBlocks(), 103, 1)
+
+	// Once the primary is available again with the same chain, we should
+	// switch back to it without any blocks being dispatched.
+	primaryChain.fork(101, 103, 1)
+	primaryChain.setErr(nil)
+	checkTicker.Force <- time.Time{}
+
+	primaryChain.extend(104, 1)
+	primary.connect(104)
+	assertBlock(t, chainView.FilteredBlocks(), 104, 1)
+
+	select {
+	case block := <-chainView.FilteredBlocks():
+		t.Fatalf("unexpected block %d", block.Height)
+	case block := <-chainView.DisconnectedBlocks():
+		t.Fatalf("unexpected disconnected block %d", block.Height)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
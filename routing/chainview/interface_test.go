@@ -765,6 +765,73 @@ var chainViewTests = []testCase{
 	},
 }
 
+// startBitcoind launches a regtest bitcoind instance connected to the given
+// p2p address, and returns a connection to it along with its RPC host. Any
+// extra arguments are passed to bitcoind.
+func startBitcoind(p2pAddr string, extraArgs ...string) (func(),
+	*chain.BitcoindConn, string, error) {
+
+	// Start a bitcoind instance.
+	tempBitcoindDir, err := ioutil.TempDir("", "bitcoind")
+	if err != nil {
+		return nil, nil, "", err
+	}
+	zmqBlockHost := "ipc:///" + tempBitcoindDir + "/blocks.socket"
+	zmqTxHost := "ipc:///" + tempBitcoindDir + "/tx.socket"
+	cleanUp1 := func() {
+		os.RemoveAll(tempBitcoindDir)
+	}
+	rpcPort := rand.Int()%(65536-1024) + 1024
+	args := []string{
+		"-datadir=" + tempBitcoindDir,
+		"-regtest",
+		"-connect=" + p2pAddr,
+		"-txindex",
+		"-rpcauth=weks:469e9bb14ab2360f8e226efed5ca6f" +
+			"d$507c670e800a95284294edb5773b05544b" +
+			"220110063096c221be9933c82d38e1",
+		fmt.Sprintf("-rpcport=%d", rpcPort),
+		"-disablewallet",
+		"-zmqpubrawblock=" + zmqBlockHost,
+		"-zmqpubrawtx=" + zmqTxHost,
+	}
+	bitcoind := exec.Command("bitcoind", append(args, extraArgs...)...)
+	err = bitcoind.Start()
+	if err != nil {
+		cleanUp1()
+		return nil, nil, "", err
+	}
+	cleanUp2 := func() {
+		bitcoind.Process.Kill()
+		bitcoind.Wait()
+		cleanUp1()
+	}
+
+	// Wait for the bitcoind instance to start up.
+	time.Sleep(time.Second)
+
+	host := fmt.Sprintf("127.0.0.1:%d", rpcPort)
+	chainConn, err := chain.NewBitcoindConn(
+		&chaincfg.RegressionNetParams, host, "weks",
+		"weks", zmqBlockHost, zmqTxHost,
+		100*time.Millisecond,
+	)
+	if err != nil {
+		return cleanUp2, nil, "", fmt.Errorf("unable to establish "+
+			"connection to bitcoind: %v", err)
+	}
+	if err := chainConn.Start(); err != nil {
+		return cleanUp2, nil, "", fmt.Errorf("unable to establish "+
+			"connection to bitcoind: %v", err)
+	}
+	cleanUp3 := func() {
+		chainConn.Stop()
+		cleanUp2()
+	}
+
+	return cleanUp3, chainConn, host, nil
+}
+
 var interfaceImpls = []struct {
 	name          string
 	chainViewInit chainViewInitFunc
@@ -772,69 +839,40 @@ var interfaceImpls = []struct {
 	{
 		name: "bitcoind_zmq",
 		chainViewInit: func(_ rpcclient.ConnConfig, p2pAddr string) (func(), FilteredChainView, error) {
-			// Start a bitcoind instance.
-			tempBitcoindDir, err := ioutil.TempDir("", "bitcoind")
+			cleanUp, chainConn, _, err := startBitcoind(p2pAddr)
 			if err != nil {
-				return nil, nil, err
+				return cleanUp, nil, err
 			}
-			zmqBlockHost := "ipc:///" + tempBitcoindDir + "/blocks.socket"
-			zmqTxHost := "ipc:///" + tempBitcoindDir + "/tx.socket"
-			cleanUp1 := func() {
-				os.RemoveAll(tempBitcoindDir)
-			}
-			rpcPort := rand.Int()%(65536-1024) + 1024
-			bitcoind := exec.Command(
-				"bitcoind",
-				"-datadir="+tempBitcoindDir,
-				"-regtest",
-				"-connect="+p2pAddr,
-				"-txindex",
-				"-rpcauth=weks:469e9bb14ab2360f8e226efed5ca6f"+
-					"d$507c670e800a95284294edb5773b05544b"+
-					"220110063096c221be9933c82d38e1",
-				fmt.Sprintf("-rpcport=%d", rpcPort),
-				"-disablewallet",
-				"-zmqpubrawblock="+zmqBlockHost,
-				"-zmqpubrawtx="+zmqTxHost,
+
+			chainView := NewBitcoindFilteredChainView(
+				chainConn, NewBlockCache(DefaultBlockCacheSize),
+			)
+
+			return cleanUp, chainView, nil
+		},
+	},
+	{
+		name: "bitcoind_cf",
+		chainViewInit: func(_ rpcclient.ConnConfig, p2pAddr string) (func(), FilteredChainView, error) {
+			cleanUp, chainConn, host, err := startBitcoind(
+				p2pAddr, "-blockfilterindex",
 			)
-			err = bitcoind.Start()
 			if err != nil {
-				cleanUp1()
-				return nil, nil, err
-			}
-			cleanUp2 := func() {
-				bitcoind.Process.Kill()
-				bitcoind.Wait()
-				cleanUp1()
+				return cleanUp, nil, err
 			}
 
-			// Wait for the bitcoind instance to start up.
-			time.Sleep(time.Second)
-
-			host := fmt.Sprintf("127.0.0.1:%d", rpcPort)
-			chainConn, err := chain.NewBitcoindConn(
-				&chaincfg.RegressionNetParams, host, "weks",
-				"weks", zmqBlockHost, zmqTxHost,
-				100*time.Millisecond,
+			chainView, err := NewBitcoindCfFilteredChainView(
+				chainConn, rpcclient.ConnConfig{
+					Host: host,
+					User: "weks",
+					Pass: "weks",
+				}, NewBlockCache(DefaultBlockCacheSize),
 			)
 			if err != nil {
-				return cleanUp2, nil, fmt.Errorf("unable to "+
-					"establish connection to bitcoind: %v",
-					err)
-			}
-			if err := chainConn.Start(); err != nil {
-				return cleanUp2, nil, fmt.Errorf("unable to "+
-					"establish connection to bitcoind: %v",
-					err)
+				return cleanUp, nil, err
 			}
-			cleanUp3 := func() {
-				chainConn.Stop()
-				cleanUp2()
-			}
-
-			chainView := NewBitcoindFilteredChainView(chainConn)
 
-			return cleanUp3, chainView, nil
+			return cleanUp, chainView, nil
 		},
 	},
 	{
@@ -876,7 +914,9 @@ var interfaceImpls = []struct {
 				os.RemoveAll(spvDir)
 			}
 
-			chainView, err := NewCfFilteredChainView(spvNode)
+			chainView, err := NewCfFilteredChainView(
+				spvNode, NewBlockCache(DefaultBlockCacheSize),
+			)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -887,7 +927,9 @@ var interfaceImpls = []struct {
 	{
 		name: "btcd_websockets",
 		chainViewInit: func(config rpcclient.ConnConfig, _ string) (func(), FilteredChainView, error) {
-			chainView, err := NewBtcdFilteredChainView(config)
+			chainView, err := NewBtcdFilteredChainView(
+				config, NewBlockCache(DefaultBlockCacheSize),
+			)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -35,6 +35,10 @@ type CfFilteredChainView struct {
 	// rescan will be sent over.
 	rescanErrChan <-chan error
 
+	// blockCache holds the blocks recently downloaded by the light
+	// client, and may be shared with other chain views.
+	blockCache *BlockCache
+
 	// blockEventQueue is the ordered queue used to keep the order
 	// of connected and disconnected blocks sent to the reader of the
 	// chainView.
@@ -53,12 +57,17 @@ type CfFilteredChainView struct {
 var _ FilteredChainView = (*CfFilteredChainView)(nil)
 
 // NewCfFilteredChainView creates a new instance of the CfFilteredChainView
-// which is connected to an active neutrino node.
+// which is connected to an active neutrino node. Blocks that match our filter
+// are fetched through the passed cache, which may be nil, so that they're
+// downloaded from the network only once.
 //
 // NOTE: The node should already be running and syncing before being passed into
 // this function.
-func NewCfFilteredChainView(node *neutrino.ChainService) (*CfFilteredChainView, error) {
+func NewCfFilteredChainView(node *neutrino.ChainService,
+	blockCache *BlockCache) (*CfFilteredChainView, error) {
+
 	return &CfFilteredChainView{
+		blockCache:    blockCache,
 		blockQueue:    newBlockEventQueue(),
 		quit:          make(chan struct{}),
 		rescanErrChan: make(chan error),
@@ -273,7 +282,7 @@ func (c *CfFilteredChainView) FilterBlock(blockHash *chainhash.Hash) (*FilteredB
 	// If we reach this point, then there was a match, so we'll need to
 	// fetch the block itself so we can scan it for any actual matches (as
 	// there's a fp rate).
-	block, err := c.p2pNode.GetBlock(*blockHash)
+	block, err := c.blockCache.FetchBlock(blockHash, c.fetchBlock)
 	if err != nil {
 		return nil, err
 	}
@@ -281,8 +290,8 @@ func (c *CfFilteredChainView) FilterBlock(blockHash *chainhash.Hash) (*FilteredB
 	// Finally, we'll step through the block, input by input, to see if any
 	// transactions spend any outputs from our watched sub-set of the UTXO
 	// set.
-	for _, tx := range block.Transactions() {
-		for _, txIn := range tx.MsgTx().TxIn {
+	for _, tx := range block.Transactions {
+		for _, txIn := range tx.TxIn {
 			prevOp := txIn.PreviousOutPoint
 
 			c.filterMtx.RLock()
@@ -291,8 +300,7 @@ func (c *CfFilteredChainView) FilterBlock(blockHash *chainhash.Hash) (*FilteredB
 
 			if ok {
 				filteredBlock.Transactions = append(
-					filteredBlock.Transactions,
-					tx.MsgTx(),
+					filteredBlock.Transactions, tx,
 				)
 
 				c.filterMtx.Lock()
@@ -307,6 +315,18 @@ func (c *CfFilteredChainView) FilterBlock(blockHash *chainhash.Hash) (*FilteredB
 	return filteredBlock, nil
 }
 
+// fetchBlock downloads the block with the given hash through the light client.
+func (c *CfFilteredChainView) fetchBlock(
+	blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+
+	block, err := c.p2pNode.GetBlock(*blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return block.MsgBlock(), nil
+}
+
 // UpdateFilter updates the UTXO filter which is to be consulted when creating
 // FilteredBlocks to be sent to subscribed clients. This method is cumulative
 // meaning repeated calls to this method should _expand_ the size of the UTXO
@@ -0,0 +1,140 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing/route"
+)
+
+// ErrSameChannel is returned when a circular route is requested that leaves
+// and returns through the same channel.
+var ErrSameChannel = errors.New("outgoing and incoming channel must differ")
+
+// FindCircularRoute attempts to find a route that leaves through our channel
+// with the given outgoing channel ID, and returns to us through our channel
+// with the given incoming channel ID, delivering amt back to us. Paying
+// ourselves along such a route shifts funds from the outgoing channel to the
+// incoming one, which allows the balances of our channels to be rebalanced.
+//
+// The path leading to the peer of the incoming channel adheres to the passed
+// restrictions, while the FeeLimit applies to the fees of the entire route.
+func (r *ChannelRouter) FindCircularRoute(amt lnwire.MilliSatoshi,
+	outgoingChanID, incomingChanID uint64, restrictions *RestrictParams,
+	finalCLTVDelta uint16) (*route.Route, error) {
+
+	if outgoingChanID == incomingChanID {
+		return nil, ErrSameChannel
+	}
+
+	self := route.Vertex(r.selfNode.PubKeyBytes)
+
+	// The route ends with the incoming channel, so we'll need the policy
+	// its peer applies when forwarding towards us.
+	info, policy1, policy2, err := r.cfg.Graph.FetchChannelEdgesByID(
+		incomingChanID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		peer           route.Vertex
+		incomingPolicy *channeldb.ChannelEdgePolicy
+	)
+	switch self {
+	case route.Vertex(info.NodeKey1Bytes):
+		peer = info.NodeKey2Bytes
+		incomingPolicy = policy2
+
+	case route.Vertex(info.NodeKey2Bytes):
+		peer = info.NodeKey1Bytes
+		incomingPolicy = policy1
+
+	default:
+		return nil, fmt.Errorf("channel %v is not one of our channels",
+			incomingChanID)
+	}
+	if incomingPolicy == nil {
+		return nil, newErrf(ErrNoPathFound, "no policy known for "+
+			"incoming channel %v", incomingChanID)
+	}
+
+	lastEdge := *incomingPolicy
+	lastEdge.Node = r.selfNode
+
+	// The peer needs to receive enough to forward amt to us after taking
+	// its fee.
+	amtToPeer := amt + computeFee(amt, &lastEdge)
+
+	// While looking for the path to the peer, we'll force the outgoing
+	// channel to be used, and ignore the incoming channel so that it isn't
+	// traversed twice.
+	pathRestrictions := *restrictions
+	pathRestrictions.OutgoingChannelID = &outgoingChanID
+	if pathRestrictions.Weights == nil {
+		pathRestrictions.Weights = r.cfg.PathWeights
+	}
+	pathRestrictions.IgnoredEdges = make(map[EdgeLocator]struct{})
+	for edge := range restrictions.IgnoredEdges {
+		pathRestrictions.IgnoredEdges[edge] = struct{}{}
+	}
+	for _, direction := range []uint8{0, 1} {
+		pathRestrictions.IgnoredEdges[EdgeLocator{
+			ChannelID: incomingChanID,
+			Direction: direction,
+		}] = struct{}{}
+	}
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	bandwidthHints, err := generateBandwidthHints(
+		r.selfNode, r.cfg.QueryBandwidth,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := findPath(
+		&graphParams{
+			graph:           r.cfg.Graph,
+			bandwidthHints:  bandwidthHints,
+			edgeProbability: r.missionControl.edgeProbability,
+			nodeHoldTime:    r.missionControl.nodeHoldTime,
+		},
+		&pathRestrictions, self, peer, amtToPeer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	path = append(path, &lastEdge)
+	if len(path) > HopLimit {
+		return nil, newErr(ErrMaxHopsExceeded, "circular route has "+
+			"too many hops")
+	}
+
+	rt, err := newRoute(
+		amt, self, path, uint32(currentHeight), finalCLTVDelta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if rt.TotalFees > restrictions.FeeLimit {
+		return nil, newErrf(ErrFeeLimitExceeded, "circular route fee "+
+			"of %v exceeds limit of %v", rt.TotalFees,
+			restrictions.FeeLimit)
+	}
+
+	if err := r.checkRouteCltv(rt, uint32(currentHeight)); err != nil {
+		return nil, err
+	}
+
+	return rt, nil
+}
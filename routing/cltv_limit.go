@@ -0,0 +1,85 @@
+package routing
+
+import (
+	"github.com/litecoinfinance/lnd/routing/route"
+)
+
+// DefaultMaxCltvExpiry is the default maximum number of blocks for which the
+// funds of a payment may be locked up, should the payment get stuck along its
+// route. It includes the final CLTV delta of the payment.
+const DefaultMaxCltvExpiry = 2016
+
+// limitPaymentCltv returns the payment with its CLTV limit tightened to the
+// maximum of our configuration, if the payment doesn't specify a lower limit
+// of its own. The passed payment isn't modified.
+func (r *ChannelRouter) limitPaymentCltv(
+	payment *LightningPayment) (*LightningPayment, error) {
+
+	maxCltv := r.cfg.MaxCltvExpiry
+	if maxCltv == 0 {
+		return payment, nil
+	}
+	if payment.CltvLimit != nil && *payment.CltvLimit <= maxCltv {
+		return payment, nil
+	}
+
+	// As path finding excludes the final CLTV delta from the limit, it
+	// must leave room for it.
+	finalCLTVDelta := uint32(payment.finalCLTVDelta())
+	if maxCltv < finalCLTVDelta {
+		return nil, newErrf(ErrCltvLimitExceeded, "final cltv delta "+
+			"of %v exceeds maximum of %v", finalCLTVDelta, maxCltv)
+	}
+
+	limited := *payment
+	limited.CltvLimit = &maxCltv
+
+	return &limited, nil
+}
+
+// limitRestrictionsCltv returns the restrictions with their CLTV limit
+// tightened to the maximum of our configuration, if they don't specify a lower
+// limit of their own. Like the limit of the restrictions, the maximum is
+// reduced by the final CLTV delta. The passed restrictions aren't modified.
+func (r *ChannelRouter) limitRestrictionsCltv(restrictions *RestrictParams,
+	finalCLTVDelta uint16) (*RestrictParams, error) {
+
+	maxCltv := r.cfg.MaxCltvExpiry
+	if maxCltv == 0 {
+		return restrictions, nil
+	}
+	if maxCltv < uint32(finalCLTVDelta) {
+		return nil, newErrf(ErrCltvLimitExceeded, "final cltv delta "+
+			"of %v exceeds maximum of %v", finalCLTVDelta, maxCltv)
+	}
+
+	limit := maxCltv - uint32(finalCLTVDelta)
+	if restrictions.CltvLimit != nil && *restrictions.CltvLimit <= limit {
+		return restrictions, nil
+	}
+
+	limited := *restrictions
+	limited.CltvLimit = &limit
+
+	return &limited, nil
+}
+
+// checkRouteCltv returns an error if the funds sent along the route may be
+// locked up for more blocks than allowed by our configuration.
+func (r *ChannelRouter) checkRouteCltv(rt *route.Route,
+	currentHeight uint32) error {
+
+	maxCltv := r.cfg.MaxCltvExpiry
+	if maxCltv == 0 || rt.TotalTimeLock <= currentHeight {
+		return nil
+	}
+
+	delta := rt.TotalTimeLock - currentHeight
+	if delta > maxCltv {
+		return newErrf(ErrCltvLimitExceeded, "total time lock of "+
+			"route is %v blocks, exceeding maximum of %v", delta,
+			maxCltv)
+	}
+
+	return nil
+}
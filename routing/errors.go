@@ -51,6 +51,10 @@ const (
 	// ErrFeeLimitExceeded is returned when the total fees of a route exceed
 	// the user-specified fee limit.
 	ErrFeeLimitExceeded
+
+	// ErrCltvLimitExceeded is returned when the total time lock of a route
+	// exceeds the maximum of the router's configuration.
+	ErrCltvLimitExceeded
 )
 
 // routerError is a structure that represent the error inside the routing package,
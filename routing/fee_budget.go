@@ -0,0 +1,391 @@
+package routing
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/subscribe"
+)
+
+// FeeBudgetWindow is the rolling window over which routing fees are accounted
+// against the daily fee budget.
+const FeeBudgetWindow = 24 * time.Hour
+
+// ErrFeeBudgetExhausted is returned when the fees of a route would exceed the
+// remaining daily fee budget.
+var ErrFeeBudgetExhausted = errors.New("daily routing fee budget exhausted")
+
+// FeeSpend records the routing fees paid by a single payment.
+type FeeSpend struct {
+	// Time is the time at which the payment succeeded.
+	Time time.Time
+
+	// Fee is the total routing fee paid by the payment.
+	Fee lnwire.MilliSatoshi
+}
+
+// FeeBudgetConfig houses the parameters of the FeeBudget.
+type FeeBudgetConfig struct {
+	// DailyBudget is the maximum routing fee that may be paid by all
+	// payments combined within any FeeBudgetWindow. A zero value leaves
+	// the fees unbounded, though they're still accounted for.
+	DailyBudget lnwire.MilliSatoshi
+
+	// WarnPercent is the percentage of the daily budget that may be
+	// spent before a FeeBudgetNearingLimit event is sent.
+	WarnPercent uint32
+
+	// InitialSpends are the fees paid before the FeeBudget was created,
+	// e.g. by payments made before a restart. Spends that have left the
+	// window are ignored.
+	InitialSpends []FeeSpend
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// FeeBudgetStatus is a snapshot of the state of the FeeBudget.
+type FeeBudgetStatus struct {
+	// DailyBudget is the maximum routing fee that may be paid within the
+	// window, or zero if unbounded.
+	DailyBudget lnwire.MilliSatoshi
+
+	// WarnPercent is the percentage of the daily budget that may be
+	// spent before subscribers are warned.
+	WarnPercent uint32
+
+	// Spent is the routing fee paid by payments that succeeded within
+	// the window.
+	Spent lnwire.MilliSatoshi
+
+	// Reserved is the routing fee of payment attempts still in flight.
+	Reserved lnwire.MilliSatoshi
+
+	// Remaining is the routing fee that may still be paid within the
+	// window. It's zero if the budget is unbounded.
+	Remaining lnwire.MilliSatoshi
+
+	// NextRelease is the time at which the oldest spend leaves the
+	// window, freeing up its share of the budget. It's the zero time if
+	// nothing was spent within the window.
+	NextRelease time.Time
+}
+
+// FeeBudgetEventType distinguishes the events sent by the FeeBudget.
+type FeeBudgetEventType uint8
+
+const (
+	// FeeBudgetNearingLimit signals that the fees spent within the window
+	// have reached the warning threshold of the daily budget.
+	FeeBudgetNearingLimit FeeBudgetEventType = iota
+
+	// FeeBudgetExhausted signals that the fees spent within the window
+	// have reached the daily budget.
+	FeeBudgetExhausted
+)
+
+// String returns a human readable name of the event type.
+func (t FeeBudgetEventType) String() string {
+	switch t {
+	case FeeBudgetNearingLimit:
+		return "NearingLimit"
+	case FeeBudgetExhausted:
+		return "Exhausted"
+	default:
+		return "Unknown"
+	}
+}
+
+// FeeBudgetEvent is sent to all subscribers of the FeeBudget whenever the fees
+// spent within the window cross one of its thresholds.
+type FeeBudgetEvent struct {
+	// Type is the threshold that was crossed.
+	Type FeeBudgetEventType
+
+	// Status is the state of the budget right after it was crossed.
+	Status FeeBudgetStatus
+}
+
+// FeeBudget enforces a budget on the routing fees paid by all outgoing
+// payments combined within a rolling window of a day. The fees of each payment
+// attempt are reserved before the attempt is dispatched, such that concurrent
+// payments can't jointly overspend, and are only accounted as spent once the
+// payment succeeds.
+type FeeBudget struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *FeeBudgetConfig
+
+	ntfnServer *subscribe.Server
+
+	mu sync.Mutex
+
+	// spends holds the fees paid within the window, in the order they
+	// were paid.
+	spends []FeeSpend
+
+	// reserved is the sum of the fees of all attempts in flight.
+	reserved lnwire.MilliSatoshi
+
+	// warned and exhausted track whether the respective events have been
+	// sent since the spent fees last fell below their thresholds, such
+	// that each crossing is only notified once.
+	warned    bool
+	exhausted bool
+}
+
+// NewFeeBudget creates a new FeeBudget backed by the given config.
+func NewFeeBudget(cfg *FeeBudgetConfig) *FeeBudget {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	b := &FeeBudget{
+		cfg:        cfg,
+		ntfnServer: subscribe.NewServer(),
+	}
+
+	cutoff := cfg.Now().Add(-FeeBudgetWindow)
+	for _, spend := range cfg.InitialSpends {
+		if spend.Time.After(cutoff) {
+			b.spends = append(b.spends, spend)
+		}
+	}
+
+	// The initial spends aren't necessarily sorted, so we'll sort them to
+	// ensure that pruning always removes the oldest first.
+	sort.Slice(b.spends, func(i, j int) bool {
+		return b.spends[i].Time.Before(b.spends[j].Time)
+	})
+
+	// We don't want to flood new subscribers with events for thresholds
+	// that were already crossed before a restart.
+	b.warned, b.exhausted = b.thresholdsReached()
+
+	return b
+}
+
+// Start launches the notification server of the FeeBudget.
+func (b *FeeBudget) Start() error {
+	if !atomic.CompareAndSwapUint32(&b.started, 0, 1) {
+		return nil
+	}
+
+	return b.ntfnServer.Start()
+}
+
+// Stop shuts down the notification server of the FeeBudget.
+func (b *FeeBudget) Stop() error {
+	if !atomic.CompareAndSwapUint32(&b.stopped, 0, 1) {
+		return nil
+	}
+
+	return b.ntfnServer.Stop()
+}
+
+// SubscribeEvents returns a subscribe.Client that will receive a
+// FeeBudgetEvent each time the fees spent within the window cross one of the
+// budget's thresholds.
+func (b *FeeBudget) SubscribeEvents() (*subscribe.Client, error) {
+	return b.ntfnServer.Subscribe()
+}
+
+// Status returns a snapshot of the state of the budget.
+func (b *FeeBudget) Status() FeeBudgetStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.prune()
+
+	return b.status()
+}
+
+// FeeLimit caps the given fee limit of a payment at the remaining budget.
+func (b *FeeBudget) FeeLimit(
+	feeLimit lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cfg.DailyBudget == 0 {
+		return feeLimit
+	}
+
+	b.prune()
+
+	if remaining := b.remaining(); remaining < feeLimit {
+		return remaining
+	}
+
+	return feeLimit
+}
+
+// Reserve sets aside the given fee for a payment attempt that is about to be
+// dispatched. If the fee exceeds the remaining budget, ErrFeeBudgetExhausted
+// is returned. Each successful reservation must be followed by a call to
+// either Settle or Release once the outcome of the attempt is known.
+func (b *FeeBudget) Reserve(fee lnwire.MilliSatoshi) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.prune()
+
+	if b.cfg.DailyBudget != 0 && fee > b.remaining() {
+		return ErrFeeBudgetExhausted
+	}
+
+	b.reserved += fee
+
+	return nil
+}
+
+// Release returns the reserved fee of a failed payment attempt to the budget.
+func (b *FeeBudget) Release(fee lnwire.MilliSatoshi) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.release(fee)
+}
+
+// Settle accounts the reserved fee of a successful payment attempt as spent,
+// notifying subscribers if a threshold of the budget has been crossed as a
+// result.
+func (b *FeeBudget) Settle(fee lnwire.MilliSatoshi) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.release(fee)
+	b.spends = append(b.spends, FeeSpend{
+		Time: b.cfg.Now(),
+		Fee:  fee,
+	})
+
+	b.prune()
+
+	warn, exhausted := b.thresholdsReached()
+	switch {
+	case exhausted && !b.exhausted:
+		b.notify(FeeBudgetExhausted)
+
+	case warn && !b.warned && !exhausted:
+		b.notify(FeeBudgetNearingLimit)
+	}
+
+	b.warned = b.warned || warn
+	b.exhausted = b.exhausted || exhausted
+}
+
+// release deducts the given fee from the reserved fees.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (b *FeeBudget) release(fee lnwire.MilliSatoshi) {
+	if fee > b.reserved {
+		b.reserved = 0
+		return
+	}
+
+	b.reserved -= fee
+}
+
+// prune removes all spends that have left the window, re-arming the events of
+// any thresholds the spent fees have fallen below.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (b *FeeBudget) prune() {
+	cutoff := b.cfg.Now().Add(-FeeBudgetWindow)
+
+	var numExpired int
+	for numExpired < len(b.spends) &&
+		!b.spends[numExpired].Time.After(cutoff) {
+
+		numExpired++
+	}
+	b.spends = b.spends[numExpired:]
+
+	warn, exhausted := b.thresholdsReached()
+	b.warned = b.warned && warn
+	b.exhausted = b.exhausted && exhausted
+}
+
+// spent returns the fees paid within the window.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (b *FeeBudget) spent() lnwire.MilliSatoshi {
+	var spent lnwire.MilliSatoshi
+	for _, spend := range b.spends {
+		spent += spend.Fee
+	}
+
+	return spent
+}
+
+// remaining returns the fees that may still be paid within the window.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (b *FeeBudget) remaining() lnwire.MilliSatoshi {
+	used := b.spent() + b.reserved
+	if used >= b.cfg.DailyBudget {
+		return 0
+	}
+
+	return b.cfg.DailyBudget - used
+}
+
+// thresholdsReached returns whether the fees spent within the window have
+// reached the warning threshold and the daily budget respectively.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (b *FeeBudget) thresholdsReached() (bool, bool) {
+	if b.cfg.DailyBudget == 0 {
+		return false, false
+	}
+
+	spent := b.spent()
+	warn := uint64(spent)*100 >=
+		uint64(b.cfg.DailyBudget)*uint64(b.cfg.WarnPercent)
+
+	return warn, spent >= b.cfg.DailyBudget
+}
+
+// status returns a snapshot of the state of the budget.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (b *FeeBudget) status() FeeBudgetStatus {
+	status := FeeBudgetStatus{
+		DailyBudget: b.cfg.DailyBudget,
+		WarnPercent: b.cfg.WarnPercent,
+		Spent:       b.spent(),
+		Reserved:    b.reserved,
+	}
+	if b.cfg.DailyBudget != 0 {
+		status.Remaining = b.remaining()
+	}
+	if len(b.spends) > 0 {
+		status.NextRelease = b.spends[0].Time.Add(FeeBudgetWindow)
+	}
+
+	return status
+}
+
+// notify sends an event of the given type to all subscribers.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (b *FeeBudget) notify(eventType FeeBudgetEventType) {
+	status := b.status()
+
+	log.Warnf("Routing fee budget %v: spent %v of %v within the last %v",
+		eventType, status.Spent, status.DailyBudget, FeeBudgetWindow)
+
+	err := b.ntfnServer.SendUpdate(&FeeBudgetEvent{
+		Type:   eventType,
+		Status: status,
+	})
+	if err != nil {
+		log.Errorf("Unable to send fee budget event: %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// TestFeeBudgetReservations asserts that reserved fees count against the
+// budget until they're either settled or released, and that only settled fees
+// are accounted as spent.
+func TestFeeBudgetReservations(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000000, 0)
+	budget := NewFeeBudget(&FeeBudgetConfig{
+		DailyBudget: 1000,
+		WarnPercent: 80,
+		Now:         func() time.Time { return now },
+	})
+
+	if err := budget.Reserve(600); err != nil {
+		t.Fatalf("unable to reserve fee: %v", err)
+	}
+
+	// While the first reservation is in flight, the remaining budget
+	// should only allow for smaller ones.
+	if limit := budget.FeeLimit(1000); limit != 400 {
+		t.Fatalf("expected fee limit 400, got %v", limit)
+	}
+	if err := budget.Reserve(500); err != ErrFeeBudgetExhausted {
+		t.Fatalf("expected ErrFeeBudgetExhausted, got %v", err)
+	}
+
+	// Releasing the reservation should return its fee to the budget.
+	budget.Release(600)
+	if limit := budget.FeeLimit(1000); limit != 1000 {
+		t.Fatalf("expected fee limit 1000, got %v", limit)
+	}
+
+	// Settling it should account it as spent instead.
+	if err := budget.Reserve(600); err != nil {
+		t.Fatalf("unable to reserve fee: %v", err)
+	}
+	budget.Settle(600)
+
+	status := budget.Status()
+	if status.Spent != 600 || status.Reserved != 0 ||
+		status.Remaining != 400 {
+
+		t.Fatalf("unexpected budget status: %+v", status)
+	}
+	if !status.NextRelease.Equal(now.Add(FeeBudgetWindow)) {
+		t.Fatalf("expected next release at %v, got %v",
+			now.Add(FeeBudgetWindow), status.NextRelease)
+	}
+
+	// Once the spend leaves the window, the whole budget should be
+	// available again.
+	now = now.Add(FeeBudgetWindow)
+	status = budget.Status()
+	if status.Spent != 0 || status.Remaining != 1000 {
+		t.Fatalf("unexpected budget status: %+v", status)
+	}
+}
+
+// TestFeeBudgetUnbounded asserts that a zero daily budget doesn't restrict the
+// fees of payments, while still accounting for them.
+func TestFeeBudgetUnbounded(t *testing.T) {
+	t.Parallel()
+
+	budget := NewFeeBudget(&FeeBudgetConfig{})
+
+	if limit := budget.FeeLimit(5000); limit != 5000 {
+		t.Fatalf("expected fee limit 5000, got %v", limit)
+	}
+	if err := budget.Reserve(5000); err != nil {
+		t.Fatalf("unable to reserve fee: %v", err)
+	}
+	budget.Settle(5000)
+
+	status := budget.Status()
+	if status.Spent != 5000 || status.Remaining != 0 {
+		t.Fatalf("unexpected budget status: %+v", status)
+	}
+}
+
+// TestFeeBudgetInitialSpends asserts that fees paid before the budget was
+// created are accounted for, as long as they're within the window.
+func TestFeeBudgetInitialSpends(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000000, 0)
+	budget := NewFeeBudget(&FeeBudgetConfig{
+		DailyBudget: 1000,
+		WarnPercent: 80,
+		InitialSpends: []FeeSpend{
+			{Time: now.Add(-time.Hour), Fee: 300},
+			{Time: now.Add(-2 * FeeBudgetWindow), Fee: 500},
+			{Time: now.Add(-2 * time.Hour), Fee: 200},
+		},
+		Now: func() time.Time { return now },
+	})
+
+	status := budget.Status()
+	if status.Spent != 500 || status.Remaining != 500 {
+		t.Fatalf("unexpected budget status: %+v", status)
+	}
+
+	// The oldest spend within the window determines the next release.
+	expRelease := now.Add(-2 * time.Hour).Add(FeeBudgetWindow)
+	if !status.NextRelease.Equal(expRelease) {
+		t.Fatalf("expected next release at %v, got %v", expRelease,
+			status.NextRelease)
+	}
+}
+
+// TestFeeBudgetEvents asserts that subscribers are notified once when the
+// spent fees reach the warning threshold and the budget respectively, and
+// again once they do so after the window has rolled over.
+func TestFeeBudgetEvents(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000000, 0)
+	budget := NewFeeBudget(&FeeBudgetConfig{
+		DailyBudget: 1000,
+		WarnPercent: 80,
+		Now:         func() time.Time { return now },
+	})
+	if err := budget.Start(); err != nil {
+		t.Fatalf("unable to start fee budget: %v", err)
+	}
+	defer budget.Stop()
+
+	sub, err := budget.SubscribeEvents()
+	if err != nil {
+		t.Fatalf("unable to subscribe to events: %v", err)
+	}
+	defer sub.Cancel()
+
+	spend := func(fee lnwire.MilliSatoshi) {
+		t.Helper()
+
+		if err := budget.Reserve(fee); err != nil {
+			t.Fatalf("unable to reserve fee: %v", err)
+		}
+		budget.Settle(fee)
+	}
+
+	assertEvent := func(expType FeeBudgetEventType,
+		expSpent lnwire.MilliSatoshi) {
+
+		t.Helper()
+
+		select {
+		case update := <-sub.Updates():
+			event := update.(*FeeBudgetEvent)
+			if event.Type != expType {
+				t.Fatalf("expected %v event, got %v", expType,
+					event.Type)
+			}
+			if event.Status.Spent != expSpent {
+				t.Fatalf("expected %v spent, got %v", expSpent,
+					event.Status.Spent)
+			}
+
+		case <-time.After(time.Second):
+			t.Fatalf("expected %v event", expType)
+		}
+	}
+
+	assertNoEvent := func() {
+		t.Helper()
+
+		select {
+		case update := <-sub.Updates():
+			t.Fatalf("unexpected event: %v", update)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	// Spending below the warning threshold shouldn't trigger any events.
+	spend(700)
+	assertNoEvent()
+
+	// Reaching it should, but only once.
+	spend(100)
+	assertEvent(FeeBudgetNearingLimit, 800)
+	spend(100)
+	assertNoEvent()
+
+	// Spending the remainder of the budget should signal its exhaustion.
+	spend(100)
+	assertEvent(FeeBudgetExhausted, 1000)
+
+	// Once the window has rolled over, the events should be re-armed.
+	now = now.Add(FeeBudgetWindow)
+	spend(1000)
+	assertEvent(FeeBudgetExhausted, 1000)
+}
@@ -0,0 +1,159 @@
+package routing
+
+import (
+	"sort"
+	"time"
+
+	"github.com/litecoinfinance/lnd/htlcswitch"
+	"github.com/litecoinfinance/lnd/routing/route"
+)
+
+const (
+	// holdTimeSmoothing is the weight of each new sample in the moving
+	// average of a node's hold time. A small weight keeps a single slow
+	// resolution from marking a node as slow, while chronically slow
+	// nodes quickly stand out.
+	holdTimeSmoothing = 0.2
+
+	// holdTimeDecay is the period after which the hold time of a node is
+	// forgotten, if no samples were added since.
+	holdTimeDecay = 24 * time.Hour
+)
+
+// NodeHoldTime summarizes the time a node took to resolve the HTLCs of our
+// payments and forwards.
+type NodeHoldTime struct {
+	// Node is the public key of the node.
+	Node route.Vertex
+
+	// HoldTime is the exponentially weighted moving average of the time
+	// the node held on to an HTLC before it was resolved.
+	HoldTime time.Duration
+
+	// NumSamples is the number of resolutions the average is based on.
+	NumSamples uint64
+
+	// LastUpdate is the time the most recent sample was added.
+	LastUpdate time.Time
+}
+
+// recordHoldTime adds a sample of the time the given node held on to an HTLC
+// to its moving average.
+func (m *missionControl) recordHoldTime(node route.Vertex,
+	holdTime time.Duration) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	now := time.Now()
+
+	h, ok := m.holdTimes[node]
+	if !ok || now.Sub(h.LastUpdate) >= holdTimeDecay {
+		m.holdTimes[node] = &NodeHoldTime{
+			Node:       node,
+			HoldTime:   holdTime,
+			NumSamples: 1,
+			LastUpdate: now,
+		}
+		return
+	}
+
+	h.HoldTime += time.Duration(
+		holdTimeSmoothing * float64(holdTime-h.HoldTime),
+	)
+	h.NumSamples++
+	h.LastUpdate = now
+}
+
+// nodeHoldTime returns the average time the given node held on to HTLCs, or
+// zero if no recent samples are known.
+func (m *missionControl) nodeHoldTime(node route.Vertex) time.Duration {
+	m.Lock()
+	defer m.Unlock()
+
+	h, ok := m.holdTimes[node]
+	if !ok {
+		return 0
+	}
+
+	// Once the hold time of a node has decayed, it's garbage collected.
+	if time.Since(h.LastUpdate) >= holdTimeDecay {
+		delete(m.holdTimes, node)
+		return 0
+	}
+
+	return h.HoldTime
+}
+
+// reportRouteHoldTime attributes the time it took for an HTLC sent along the
+// given route to be resolved to the nodes that held on to it. As we can't tell
+// how long each node held the HTLC, the time is split evenly among them. If the
+// HTLC failed, only the nodes up to and including the one that reported the
+// failure held it.
+func (r *ChannelRouter) reportRouteHoldTime(rt *route.Route,
+	elapsed time.Duration, sendErr error) {
+
+	nodes := make([]route.Vertex, 0, len(rt.Hops))
+	for _, hop := range rt.Hops {
+		nodes = append(nodes, hop.PubKeyBytes)
+	}
+
+	if sendErr != nil {
+		fErr, ok := sendErr.(*htlcswitch.ForwardingError)
+		if !ok {
+			return
+		}
+
+		errSource := route.NewVertex(fErr.ErrorSource)
+		i := 0
+		for i < len(nodes) && nodes[i] != errSource {
+			i++
+		}
+
+		// Failures that originate from ourselves, or from an unknown
+		// source, tell us nothing about the nodes of the route.
+		if i == len(nodes) {
+			return
+		}
+		nodes = nodes[:i+1]
+	}
+
+	holdTime := elapsed / time.Duration(len(nodes))
+	for _, node := range nodes {
+		r.missionControl.recordHoldTime(node, holdTime)
+	}
+}
+
+// ReportPeerHoldTime records the time it took the given peer to resolve an
+// HTLC we forwarded to it. As we don't know how the HTLC was routed beyond the
+// peer, the entire time is attributed to the peer.
+func (r *ChannelRouter) ReportPeerHoldTime(peer route.Vertex,
+	holdTime time.Duration) {
+
+	r.missionControl.recordHoldTime(peer, holdTime)
+}
+
+// NodeHoldTimes returns the hold times of all nodes for which recent samples
+// are known, ordered such that the slowest nodes are listed first.
+func (r *ChannelRouter) NodeHoldTimes() []NodeHoldTime {
+	m := r.missionControl
+
+	m.Lock()
+	defer m.Unlock()
+
+	holdTimes := make([]NodeHoldTime, 0, len(m.holdTimes))
+	for node, h := range m.holdTimes {
+		if time.Since(h.LastUpdate) >= holdTimeDecay {
+			delete(m.holdTimes, node)
+			continue
+		}
+
+		holdTimes = append(holdTimes, *h)
+	}
+
+	sort.Slice(holdTimes, func(i, j int) bool {
+		return holdTimes[i].HoldTime > holdTimes[j].HoldTime
+	})
+
+	return holdTimes
+}
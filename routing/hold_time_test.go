@@ -0,0 +1,90 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/lnd/htlcswitch"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing/route"
+)
+
+// TestReportRouteHoldTime asserts that the time it took to resolve an HTLC is
+// split among the nodes that held on to it, and that nodes beyond the source
+// of a failure aren't affected.
+func TestReportRouteHoldTime(t *testing.T) {
+	t.Parallel()
+
+	var (
+		keys []*btcec.PublicKey
+		rt   = &route.Route{}
+	)
+	for i := 0; i < 3; i++ {
+		privKey, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		keys = append(keys, privKey.PubKey())
+
+		rt.Hops = append(rt.Hops, &route.Hop{
+			PubKeyBytes: route.NewVertex(privKey.PubKey()),
+			ChannelID:   uint64(i),
+		})
+	}
+
+	router := &ChannelRouter{
+		missionControl: newMissionControl(nil, nil, nil, nil),
+	}
+
+	assertHoldTimes := func(expected ...time.Duration) {
+		t.Helper()
+
+		for i, hop := range rt.Hops {
+			holdTime := router.missionControl.nodeHoldTime(
+				hop.PubKeyBytes,
+			)
+			if holdTime != expected[i] {
+				t.Fatalf("expected hold time %v for hop %d, "+
+					"got %v", expected[i], i, holdTime)
+			}
+		}
+	}
+
+	// A failure reported by the second hop should only be attributed to
+	// the first two hops.
+	fErr := &htlcswitch.ForwardingError{
+		ErrorSource:    keys[1],
+		FailureMessage: &lnwire.FailTemporaryNodeFailure{},
+	}
+	router.reportRouteHoldTime(rt, 10*time.Second, fErr)
+	assertHoldTimes(5*time.Second, 5*time.Second, 0)
+
+	// A success should be attributed to all hops, moving their average
+	// hold times towards the new sample.
+	router.reportRouteHoldTime(rt, 30*time.Second, nil)
+	assertHoldTimes(
+		5*time.Second+time.Duration(holdTimeSmoothing*float64(
+			5*time.Second,
+		)),
+		5*time.Second+time.Duration(holdTimeSmoothing*float64(
+			5*time.Second,
+		)),
+		10*time.Second,
+	)
+
+	// Errors that don't originate from the route shouldn't affect the
+	// hold times at all.
+	holdTimes := router.NodeHoldTimes()
+	router.reportRouteHoldTime(rt, time.Minute, ErrRouterShuttingDown)
+	if len(router.NodeHoldTimes()) != len(holdTimes) ||
+		router.NodeHoldTimes()[0] != holdTimes[0] {
+
+		t.Fatalf("hold times changed after local failure")
+	}
+
+	// The slowest node should be listed first.
+	if holdTimes[0].Node != rt.Hops[2].PubKeyBytes {
+		t.Fatalf("expected slowest node to be listed first")
+	}
+}
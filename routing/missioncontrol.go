@@ -5,8 +5,8 @@ import (
 	"time"
 
 	"github.com/litecoinfinance/btcd/btcec"
-	"github.com/coreos/bbolt"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/routing/route"
 	"github.com/litecoinfinance/lnd/zpay32"
@@ -29,8 +29,21 @@ const (
 	//
 	// TODO(roasbeef): instead use random delay on each?
 	edgeDecay = time.Duration(time.Second * 5)
+
+	// historyDecay is the period after which the outcomes of past payment
+	// attempts through an edge are forgotten, if none were added since.
+	// Unlike the prune view, the history only informs the estimated
+	// success probability of edges, so it's kept much longer.
+	historyDecay = time.Hour
 )
 
+// edgeHistory records the outcomes of past payment attempts through an edge.
+type edgeHistory struct {
+	successes  uint32
+	failures   uint32
+	lastUpdate time.Time
+}
+
 // missionControl contains state which summarizes the past attempts of HTLC
 // routing by external callers when sending payments throughout the network.
 // missionControl remembers the outcome of these past routing attempts (success
@@ -56,6 +69,18 @@ type missionControl struct {
 	// to that particular vertex.
 	failedVertexes map[route.Vertex]time.Time
 
+	// history records the outcomes of past payment attempts through each
+	// edge, from which the success probability of edges is estimated.
+	history map[EdgeLocator]*edgeHistory
+
+	// holdTimes tracks the time nodes took to resolve the HTLCs of our
+	// payments and forwards, such that slow nodes can be avoided.
+	holdTimes map[route.Vertex]*NodeHoldTime
+
+	// pathWeights are the weights used by path finding for payments that
+	// don't specify their own.
+	pathWeights *PathWeights
+
 	graph *channeldb.ChannelGraph
 
 	selfNode *channeldb.LightningNode
@@ -74,11 +99,15 @@ type missionControl struct {
 //
 // TODO(roasbeef): persist memory
 func newMissionControl(g *channeldb.ChannelGraph, selfNode *channeldb.LightningNode,
-	qb func(*channeldb.ChannelEdgeInfo) lnwire.MilliSatoshi) *missionControl {
+	qb func(*channeldb.ChannelEdgeInfo) lnwire.MilliSatoshi,
+	pathWeights *PathWeights) *missionControl {
 
 	return &missionControl{
 		failedEdges:    make(map[EdgeLocator]time.Time),
 		failedVertexes: make(map[route.Vertex]time.Time),
+		history:        make(map[EdgeLocator]*edgeHistory),
+		holdTimes:      make(map[route.Vertex]*NodeHoldTime),
+		pathWeights:    pathWeights,
 		selfNode:       selfNode,
 		queryBandwidth: qb,
 		graph:          g,
@@ -159,6 +188,45 @@ func (m *missionControl) NewPaymentSession(routeHints [][]zpay32.HopHint,
 
 	viewSnapshot := m.GraphPruneView()
 
+	edges, err := RouteHintsToEdges(routeHints, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// We'll also obtain a set of bandwidthHints from the lower layer for
+	// each of our outbound channels. This will allow the path finding to
+	// skip any links that aren't active or just don't have enough
+	// bandwidth to carry the payment.
+	sourceNode, err := m.graph.SourceNode()
+	if err != nil {
+		return nil, err
+	}
+	bandwidthHints, err := generateBandwidthHints(
+		sourceNode, m.queryBandwidth,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentSession{
+		pruneViewSnapshot:    viewSnapshot,
+		additionalEdges:      edges,
+		bandwidthHints:       bandwidthHints,
+		errFailedPolicyChans: make(map[EdgeLocator]struct{}),
+		mc:                   m,
+		pathFinder:           findPath,
+		candidateFinder:      findPaths,
+	}, nil
+}
+
+// RouteHintsToEdges converts a set of BOLT-11 route hints leading to the
+// given target into the additional edges that should be explored during path
+// finding, indexed by the public key of each channel's starting node. This
+// allows routes to be found to targets that can only be reached through
+// private channels.
+func RouteHintsToEdges(routeHints [][]zpay32.HopHint, target route.Vertex) (
+	map[route.Vertex][]*channeldb.ChannelEdgePolicy, error) {
+
 	edges := make(map[route.Vertex][]*channeldb.ChannelEdgePolicy)
 
 	// Traverse through all of the available hop hints and include them in
@@ -206,29 +274,7 @@ func (m *missionControl) NewPaymentSession(routeHints [][]zpay32.HopHint,
 		}
 	}
 
-	// We'll also obtain a set of bandwidthHints from the lower layer for
-	// each of our outbound channels. This will allow the path finding to
-	// skip any links that aren't active or just don't have enough
-	// bandwidth to carry the payment.
-	sourceNode, err := m.graph.SourceNode()
-	if err != nil {
-		return nil, err
-	}
-	bandwidthHints, err := generateBandwidthHints(
-		sourceNode, m.queryBandwidth,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &paymentSession{
-		pruneViewSnapshot:    viewSnapshot,
-		additionalEdges:      edges,
-		bandwidthHints:       bandwidthHints,
-		errFailedPolicyChans: make(map[EdgeLocator]struct{}),
-		mc:                   m,
-		pathFinder:           findPath,
-	}, nil
+	return edges, nil
 }
 
 // NewPaymentSessionFromRoutes creates a new paymentSession instance that will
@@ -259,7 +305,7 @@ func generateBandwidthHints(sourceNode *channeldb.LightningNode,
 	// First, we'll collect the set of outbound edges from the target
 	// source node.
 	var localChans []*channeldb.ChannelEdgeInfo
-	err := sourceNode.ForEachChannel(nil, func(tx *bbolt.Tx,
+	err := sourceNode.ForEachChannel(nil, func(tx kvdb.Tx,
 		edgeInfo *channeldb.ChannelEdgeInfo,
 		_, _ *channeldb.ChannelEdgePolicy) error {
 
@@ -287,5 +333,71 @@ func (m *missionControl) ResetHistory() {
 	m.Lock()
 	m.failedEdges = make(map[EdgeLocator]time.Time)
 	m.failedVertexes = make(map[route.Vertex]time.Time)
+	m.history = make(map[EdgeLocator]*edgeHistory)
+	m.holdTimes = make(map[route.Vertex]*NodeHoldTime)
 	m.Unlock()
 }
+
+// recordOutcome adds the outcome of a payment attempt through the given edge
+// to its history.
+//
+// NOTE: The mission control lock must be held.
+func (m *missionControl) recordOutcome(e EdgeLocator, success bool) {
+	now := time.Now()
+
+	h, ok := m.history[e]
+	if !ok || now.Sub(h.lastUpdate) >= historyDecay {
+		h = &edgeHistory{}
+		m.history[e] = h
+	}
+
+	if success {
+		h.successes++
+	} else {
+		h.failures++
+	}
+	h.lastUpdate = now
+}
+
+// reportRouteSuccess records that a payment attempt along the given route
+// succeeded, and therefore each of its edges forwarded the HTLC.
+func (m *missionControl) reportRouteSuccess(rt *route.Route) {
+	m.Lock()
+	defer m.Unlock()
+
+	fromNode := rt.SourcePubKey
+	for _, hop := range rt.Hops {
+		toNode := hop.PubKeyBytes
+		locator := newEdgeLocatorByPubkeys(
+			hop.ChannelID, &fromNode, &toNode,
+		)
+		m.recordOutcome(*locator, true)
+
+		fromNode = toNode
+	}
+}
+
+// edgeProbability estimates the probability that an HTLC forwarded through the
+// given edge succeeds, based on the outcomes of past payment attempts through
+// it. Following Laplace's rule of succession, the estimate is always within
+// (0, 1), with edges lacking any history being assigned a probability of 1/2.
+func (m *missionControl) edgeProbability(e EdgeLocator) float64 {
+	m.Lock()
+	defer m.Unlock()
+
+	h, ok := m.history[e]
+	if !ok {
+		return 0.5
+	}
+
+	// Once the history of an edge has decayed, it's garbage collected.
+	if time.Since(h.lastUpdate) >= historyDecay {
+		delete(m.history, e)
+		return 0.5
+	}
+
+	successes := float64(h.successes)
+	failures := float64(h.failures)
+
+	return (successes + 1) / (successes + failures + 2)
+}
@@ -0,0 +1,283 @@
+package routing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing/route"
+)
+
+// MinShardAmount is the smallest amount a payment shard may carry. Splitting
+// payments further would mostly waste the HTLC slots of the channels along
+// their routes, while the base fees paid by each shard make up an increasing
+// share of their amount.
+const MinShardAmount = lnwire.MilliSatoshi(10000000)
+
+// ErrMultiPathUnsupported is returned when a payment may be split into several
+// shards, but the router has no means to send them.
+var ErrMultiPathUnsupported = fmt.Errorf("multi-path payments not supported")
+
+// shardResult is the outcome of a single shard of a multi-path payment.
+type shardResult struct {
+	route    *route.Route
+	preimage [32]byte
+	err      error
+}
+
+// shardAmount returns the amount delivered to the destination by the shard
+// sent along the given route.
+func shardAmount(rt *route.Route) lnwire.MilliSatoshi {
+	return rt.TotalAmount - rt.TotalFees
+}
+
+// SendMultiPathPayment attempts to send a payment as described within the
+// passed LightningPayment, splitting it into up to MaxShards shards that are
+// sent along different routes at once if no single route is able to carry the
+// full amount. Whenever a route can't be found for the remaining amount, the
+// amount of the next shard is halved, down to MinShardAmount. The amount of
+// shards that fail is returned to the remaining amount, and sent again along
+// new routes, until either a shard succeeds or the payment can't be completed.
+// The fees of all shards combined are bounded by the payment's FeeLimit.
+//
+// The function blocks until all shards it dispatched have been resolved. If
+// the payment succeeds, the preimage is returned along with the routes of the
+// shards that were settled.
+//
+// NOTE: The destination must be willing to accept HTLCs that each only carry
+// part of the payment's amount.
+func (r *ChannelRouter) SendMultiPathPayment(
+	payment *LightningPayment) ([32]byte, []*route.Route, error) {
+
+	// Payments that may not be split are sent along a single route.
+	if payment.MaxShards <= 1 {
+		preimage, rt, err := r.SendPayment(payment)
+		if err != nil {
+			return [32]byte{}, nil, err
+		}
+
+		return preimage, []*route.Route{rt}, nil
+	}
+
+	if r.cfg.SendShardToSwitch == nil {
+		return [32]byte{}, nil, ErrMultiPathUnsupported
+	}
+
+	paySession, err := r.missionControl.NewPaymentSession(
+		payment.RouteHints, payment.Target,
+	)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+
+	var (
+		finalCLTVDelta    = payment.finalCLTVDelta()
+		payAttemptTimeout = payment.payAttemptTimeout()
+		timeoutChan       = time.After(payAttemptTimeout)
+
+		// results is buffered such that shards can always deliver
+		// their result, even if we're exiting.
+		results = make(chan *shardResult, payment.MaxShards)
+
+		remaining = payment.Amount
+		shardAmt  = payment.Amount
+		feesUsed  lnwire.MilliSatoshi
+		inFlight  uint32
+
+		preimage  [32]byte
+		settled   []*route.Route
+		lastError error
+		finalErr  error
+	)
+
+	for {
+		// As long as part of the payment remains to be sent, and it
+		// has neither succeeded nor failed terminally, we'll dispatch
+		// new shards until we run out of shard slots.
+		for remaining > 0 && len(settled) == 0 && finalErr == nil &&
+			inFlight < payment.MaxShards {
+
+			select {
+			case <-timeoutChan:
+				finalErr = newErrf(
+					ErrPaymentAttemptTimeout, "payment "+
+						"attempt not completed before "+
+						"timeout of %v",
+					payAttemptTimeout,
+				)
+				continue
+
+			case <-r.quit:
+				return [32]byte{}, nil, ErrRouterShuttingDown
+
+			default:
+			}
+
+			amt := shardAmt
+			if amt > remaining {
+				amt = remaining
+			}
+
+			// The shard may only pay the part of the fee limit
+			// that hasn't been used by the other shards yet. If our
+			// routing fees are bounded by a budget, it's capped by
+			// what remains of it as well.
+			shard := *payment
+			shard.Amount = amt
+			shard.FeeLimit = payment.FeeLimit - feesUsed
+			if r.cfg.FeeBudget != nil {
+				shard.FeeLimit = r.cfg.FeeBudget.FeeLimit(
+					shard.FeeLimit,
+				)
+			}
+
+			rt, err := paySession.RequestRoute(
+				&shard, uint32(currentHeight), finalCLTVDelta,
+			)
+			if err != nil {
+				// If no route is able to carry the shard, we'll
+				// split it further, as long as the smaller
+				// shards can still cover the remaining amount
+				// within our shard slots.
+				slots := lnwire.MilliSatoshi(
+					payment.MaxShards - inFlight,
+				)
+				half := amt / 2
+				if half >= MinShardAmount &&
+					remaining <= half*slots {
+
+					log.Debugf("Splitting shard of %v "+
+						"for payment %x: %v", amt,
+						payment.PaymentHash, err)
+
+					shardAmt = half
+					continue
+				}
+
+				// Otherwise, we'll wait for the shards in
+				// flight, which may free up capacity if they
+				// fail. If none are left, the payment fails.
+				switch {
+				case inFlight > 0:
+				case lastError != nil:
+					finalErr = newErrf(
+						ErrNoRouteFound, "unable to "+
+							"route payment to "+
+							"destination: %v",
+						lastError,
+					)
+				default:
+					finalErr = err
+				}
+				break
+			}
+
+			// Before dispatching the shard, we'll reserve its fees
+			// from the budget, if any. Should concurrent payments
+			// have consumed it, we'll request another route.
+			if r.cfg.FeeBudget != nil {
+				err := r.cfg.FeeBudget.Reserve(rt.TotalFees)
+				if err != nil {
+					log.Warnf("Unable to send shard of "+
+						"payment %x with fee %v: %v",
+						payment.PaymentHash,
+						rt.TotalFees, err)
+
+					lastError = err
+					continue
+				}
+			}
+
+			// The shard will use up part of the bandwidth of our
+			// outgoing channel, which subsequent shards must take
+			// into account.
+			paySession.reserveBandwidth(rt)
+
+			remaining -= amt
+			feesUsed += rt.TotalFees
+			inFlight++
+
+			log.Debugf("Dispatching shard %d of %v for payment %x",
+				inFlight, amt, payment.PaymentHash)
+
+			go func(rt *route.Route) {
+				preimage, err := r.sendToSwitch(
+					rt, payment.PaymentHash, true,
+				)
+				results <- &shardResult{
+					route:    rt,
+					preimage: preimage,
+					err:      err,
+				}
+			}(rt)
+		}
+
+		// If no shards are left in flight, the outcome of the payment
+		// is known.
+		if inFlight == 0 {
+			if len(settled) > 0 {
+				return preimage, settled, nil
+			}
+			if finalErr != nil {
+				return [32]byte{}, nil, finalErr
+			}
+		}
+
+		// Otherwise, we'll wait for the next shard to be resolved.
+		var result *shardResult
+		select {
+		case result = <-results:
+		case <-r.quit:
+			return [32]byte{}, nil, ErrRouterShuttingDown
+		}
+
+		inFlight--
+		rt := result.route
+
+		if r.cfg.FeeBudget != nil {
+			if result.err == nil {
+				r.cfg.FeeBudget.Settle(rt.TotalFees)
+			} else {
+				r.cfg.FeeBudget.Release(rt.TotalFees)
+			}
+		}
+
+		// A settled shard means the destination released the
+		// preimage, so we won't dispatch any more shards.
+		if result.err == nil {
+			r.missionControl.reportRouteSuccess(rt)
+
+			preimage = result.preimage
+			settled = append(settled, rt)
+			continue
+		}
+
+		log.Errorf("Shard of %v for payment %x failed: %v",
+			shardAmount(rt), payment.PaymentHash, result.err)
+
+		if payment.AttemptFailed != nil {
+			payment.AttemptFailed(rt, result.err)
+		}
+
+		paySession.releaseBandwidth(rt)
+
+		// Terminal failures abort the payment, though we'll still wait
+		// for the remaining shards to be resolved. Otherwise, the
+		// amount and fees of the shard are freed up to be sent again.
+		if r.processSendError(paySession, rt, result.err) {
+			if finalErr == nil {
+				finalErr = result.err
+			}
+			continue
+		}
+
+		remaining += shardAmount(rt)
+		feesUsed -= rt.TotalFees
+		lastError = result.err
+	}
+}
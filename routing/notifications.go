@@ -168,6 +168,11 @@ type TopologyChange struct {
 	// manner.
 	NodeUpdates []*NetworkNodeUpdate
 
+	// NewChannels is a slice of channels which have been newly announced
+	// and authenticated. The routing policies of their edges are sent out
+	// as ChannelEdgeUpdates once they're known.
+	NewChannels []*NewChanSummary
+
 	// ChanelEdgeUpdates is a slice of channel edges which are either newly
 	// opened and authenticated, or have had their routing policies
 	// updated.
@@ -176,14 +181,36 @@ type TopologyChange struct {
 	// ClosedChannels contains a slice of close channel summaries which
 	// described which block a channel was closed at, and also carry
 	// supplemental information such as the capacity of the former channel.
+	// Channels that were pruned from the graph as zombies are included as
+	// well.
 	ClosedChannels []*ClosedChanSummary
 }
 
 // isEmpty returns true if the TopologyChange is empty. A TopologyChange is
 // considered empty, if it contains no *new* updates of any type.
 func (t *TopologyChange) isEmpty() bool {
-	return len(t.NodeUpdates) == 0 && len(t.ChannelEdgeUpdates) == 0 &&
-		len(t.ClosedChannels) == 0
+	return len(t.NodeUpdates) == 0 && len(t.NewChannels) == 0 &&
+		len(t.ChannelEdgeUpdates) == 0 && len(t.ClosedChannels) == 0
+}
+
+// NewChanSummary is a summary of a channel that was newly added to the channel
+// graph.
+type NewChanSummary struct {
+	// ChanID is the short-channel ID which uniquely identifies the
+	// channel.
+	ChanID uint64
+
+	// ChanPoint is the outpoint which represents the multi-sig funding
+	// output for the channel.
+	ChanPoint wire.OutPoint
+
+	// Capacity is the capacity of the channel.
+	Capacity btcutil.Amount
+
+	// Node1 and Node2 are the nodes connected by the channel, in the order
+	// of their announcement.
+	Node1 *btcec.PublicKey
+	Node2 *btcec.PublicKey
 }
 
 // ClosedChanSummary is a summary of a channel that was detected as being
@@ -201,7 +228,8 @@ type ClosedChanSummary struct {
 	Capacity btcutil.Amount
 
 	// ClosedHeight is the height in the chain that the channel was closed
-	// at.
+	// at. It's zero if the channel was pruned from the graph as a zombie
+	// instead.
 	ClosedHeight uint32
 
 	// ChanPoint is the funding point, or the multi-sig utxo which
@@ -327,9 +355,36 @@ func addToTopologyChange(graph *channeldb.ChannelGraph, update *TopologyChange,
 		update.NodeUpdates = append(update.NodeUpdates, nodeUpdate)
 		return nil
 
-	// We ignore initial channel announcements as we'll only send out
-	// updates once the individual edges themselves have been updated.
+	// New channel announcements only identify the channel and the nodes
+	// it connects. Their policies are sent out once the individual edges
+	// themselves have been updated.
 	case *channeldb.ChannelEdgeInfo:
+		// We parse the keys rather than using the ones cached within
+		// the announcement, as we'll strip their curves below.
+		node1, err := btcec.ParsePubKey(
+			m.NodeKey1Bytes[:], btcec.S256(),
+		)
+		if err != nil {
+			return err
+		}
+		node2, err := btcec.ParsePubKey(
+			m.NodeKey2Bytes[:], btcec.S256(),
+		)
+		if err != nil {
+			return err
+		}
+
+		newChan := &NewChanSummary{
+			ChanID:    m.ChannelID,
+			ChanPoint: m.ChannelPoint,
+			Capacity:  m.Capacity,
+			Node1:     node1,
+			Node2:     node2,
+		}
+		newChan.Node1.Curve = nil
+		newChan.Node2.Curve = nil
+
+		update.NewChannels = append(update.NewChannels, newChan)
 		return nil
 
 	// Any new ChannelUpdateAnnouncements will generate a corresponding
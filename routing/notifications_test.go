@@ -337,6 +337,98 @@ func (m *mockChainView) Stop() error {
 	return nil
 }
 
+// TestNewChannelNotification tests that clients are notified of channels that
+// are newly added to the graph, before any of their edges are known.
+func TestNewChannelNotification(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtxSingleNode(startingBlockHeight)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	ntfnClient, err := ctx.router.SubscribeTopology()
+	if err != nil {
+		t.Fatalf("unable to subscribe for channel notifications: %v", err)
+	}
+	defer ntfnClient.Cancel()
+
+	const chanValue = 10000
+	fundingTx, chanPoint, chanID, err := createChannelEdge(ctx,
+		bitcoinKey1.SerializeCompressed(),
+		bitcoinKey2.SerializeCompressed(),
+		chanValue, startingBlockHeight)
+	if err != nil {
+		t.Fatalf("unable create channel edge: %v", err)
+	}
+
+	fundingBlock := &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{fundingTx},
+	}
+	ctx.chain.addBlock(fundingBlock, chanID.BlockHeight, chanID.BlockHeight)
+
+	node1, err := createTestNode()
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	node2, err := createTestNode()
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+
+	edge := &channeldb.ChannelEdgeInfo{
+		ChannelID:     chanID.ToUint64(),
+		NodeKey1Bytes: node1.PubKeyBytes,
+		NodeKey2Bytes: node2.PubKeyBytes,
+		AuthProof: &channeldb.ChannelAuthProof{
+			NodeSig1Bytes:    testSig.Serialize(),
+			NodeSig2Bytes:    testSig.Serialize(),
+			BitcoinSig1Bytes: testSig.Serialize(),
+			BitcoinSig2Bytes: testSig.Serialize(),
+		},
+	}
+	copy(edge.BitcoinKey1Bytes[:], bitcoinKey1.SerializeCompressed())
+	copy(edge.BitcoinKey2Bytes[:], bitcoinKey2.SerializeCompressed())
+	if err := ctx.router.AddEdge(edge); err != nil {
+		t.Fatalf("unable to add edge: %v", err)
+	}
+
+	select {
+	case ntfn := <-ntfnClient.TopologyChanges:
+		if len(ntfn.NewChannels) != 1 {
+			t.Fatalf("expected one new channel, got %v",
+				len(ntfn.NewChannels))
+		}
+
+		newChan := ntfn.NewChannels[0]
+		if newChan.ChanID != chanID.ToUint64() {
+			t.Fatalf("channel ID of new channel doesn't match: "+
+				"expected %v, got %v", chanID.ToUint64(),
+				newChan.ChanID)
+		}
+		if newChan.ChanPoint != *chanPoint {
+			t.Fatalf("chan point of new channel doesn't match: "+
+				"expected %v, got %v", chanPoint,
+				newChan.ChanPoint)
+		}
+		if newChan.Capacity != chanValue {
+			t.Fatalf("capacity of new channel doesn't match: "+
+				"expected %v, got %v", chanValue,
+				newChan.Capacity)
+		}
+		if route.NewVertex(newChan.Node1) != node1.PubKeyBytes ||
+			route.NewVertex(newChan.Node2) != node2.PubKeyBytes {
+
+			t.Fatalf("nodes of new channel don't match")
+		}
+
+	case <-time.After(time.Second * 5):
+		t.Fatal("notification not sent")
+	}
+}
+
 // TestEdgeUpdateNotification tests that when edges are updated or added,
 // a proper notification is sent of to all registered clients.
 func TestEdgeUpdateNotification(t *testing.T) {
@@ -3,10 +3,12 @@ package routing
 import (
 	"container/heap"
 	"math"
-
-	"github.com/coreos/bbolt"
+	"runtime"
+	"sync"
+	"time"
 
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/routing/route"
 )
@@ -30,16 +32,69 @@ const (
 	// diminishes the time lock penalty for all but the smallest amounts.
 	// To not change the behaviour of path finding too drastically, a
 	// relatively small value is chosen which is still big enough to give
-	// some effect with smaller time lock values. The value may need
-	// tweaking and/or be made configurable in the future.
+	// some effect with smaller time lock values. It can be overridden
+	// through the RiskFactorBillionths of PathWeights.
 	RiskFactorBillionths = 15
+
+	// DefaultHoldTimeWeight is the default penalty in msat for each second
+	// a node took on average to resolve HTLCs. It's chosen such that
+	// healthy nodes, which resolve HTLCs within a few seconds, are barely
+	// affected, while nodes holding on to HTLCs for minutes are only used
+	// if they are considerably cheaper than the alternatives.
+	DefaultHoldTimeWeight = 1000
 )
 
+// PathWeights configures the cost function minimized by path finding. By
+// trading off the fees, time locks and estimated success probabilities of
+// edges against each other, routes can be selected to be the cheapest, the
+// ones locking up funds the shortest, or the ones most likely to succeed.
+type PathWeights struct {
+	// FeeWeight scales the routing fee charged by an edge.
+	FeeWeight float64
+
+	// RiskFactorBillionths controls the influence of the time lock delta
+	// of an edge. It is expressed as billionths of msat per msat sent
+	// through the edge per time lock delta block.
+	RiskFactorBillionths uint64
+
+	// ProbabilityWeight is the penalty in msat applied to an edge for
+	// each unit of the negative natural logarithm of its estimated success
+	// probability. As the logarithms of the probabilities of the edges of
+	// a path add up to that of the path itself, a high weight favours the
+	// paths most likely to succeed.
+	ProbabilityWeight uint64
+
+	// HoldTimeWeight is the penalty in msat applied to an edge for each
+	// second its node took on average to resolve the HTLCs of our payments
+	// and forwards. A high weight avoids chronically slow nodes.
+	HoldTimeWeight uint64
+}
+
+// DefaultPathWeights are the weights used by path finding if none are
+// specified, which account for the fees and time locks of edges, and the hold
+// times of their nodes.
+var DefaultPathWeights = PathWeights{
+	FeeWeight:            1,
+	RiskFactorBillionths: RiskFactorBillionths,
+	HoldTimeWeight:       DefaultHoldTimeWeight,
+}
+
+// spurSearchWorkers is the maximum number of spur path searches findPaths runs
+// concurrently.
+var spurSearchWorkers = runtime.NumCPU()
+
 // pathFinder defines the interface of a path finding algorithm.
 type pathFinder = func(g *graphParams, r *RestrictParams,
 	source, target route.Vertex, amt lnwire.MilliSatoshi) (
 	[]*channeldb.ChannelEdgePolicy, error)
 
+// candidatePathFinder defines the interface of a k-shortest paths algorithm.
+// The returned paths are ranked from best to worst, and each starts with a self
+// edge of the source.
+type candidatePathFinder = func(g *graphParams, source, target route.Vertex,
+	amt lnwire.MilliSatoshi, r *RestrictParams, numPaths uint32) (
+	[][]*channeldb.ChannelEdgePolicy, error)
+
 // edgePolicyWithSource is a helper struct to keep track of the source node
 // of a channel edge. ChannelEdgePolicy only contains to destination node
 // of the edge.
@@ -185,27 +240,40 @@ func newRoute(amtToSend lnwire.MilliSatoshi, sourceVertex route.Vertex,
 
 // edgeWeight computes the weight of an edge. This value is used when searching
 // for the shortest path within the channel graph between two nodes. Weight is
-// is the fee itself plus a time lock penalty added to it. This benefits
-// channels with shorter time lock deltas and shorter (hops) routes in general.
-// RiskFactor controls the influence of time lock on route selection. This is
-// currently a fixed value, but might be configurable in the future.
-func edgeWeight(lockedAmt lnwire.MilliSatoshi, fee lnwire.MilliSatoshi,
-	timeLockDelta uint16) int64 {
+// the weighted fee plus a time lock penalty and a penalty for the probability
+// of the edge failing to forward the payment. This benefits channels with
+// shorter time lock deltas and shorter (hops) routes in general. The passed
+// weights control the influence of each of these on route selection.
+func edgeWeight(weights *PathWeights, lockedAmt lnwire.MilliSatoshi,
+	fee lnwire.MilliSatoshi, timeLockDelta uint16, probability float64,
+	holdTime time.Duration) int64 {
+
 	// timeLockPenalty is the penalty for the time lock delta of this channel.
 	// It is controlled by RiskFactorBillionths and scales proportional
 	// to the amount that will pass through channel. Rationale is that it if
 	// a twice as large amount gets locked up, it is twice as bad.
-	timeLockPenalty := int64(lockedAmt) * int64(timeLockDelta) *
-		RiskFactorBillionths / 1000000000
+	timeLockPenalty := float64(lockedAmt) * float64(timeLockDelta) *
+		float64(weights.RiskFactorBillionths) / 1000000000
+
+	// probabilityPenalty is the penalty for the chance that the payment
+	// fails at this edge, which grows without bound as the probability of
+	// success approaches zero.
+	probabilityPenalty := -math.Log(probability) *
+		float64(weights.ProbabilityWeight)
 
-	return int64(fee) + timeLockPenalty
+	// holdTimePenalty is the penalty for the time the node of this
+	// channel is expected to hold on to the HTLC.
+	holdTimePenalty := holdTime.Seconds() * float64(weights.HoldTimeWeight)
+
+	return int64(weights.FeeWeight*float64(fee) + timeLockPenalty +
+		probabilityPenalty + holdTimePenalty)
 }
 
 // graphParams wraps the set of graph parameters passed to findPath.
 type graphParams struct {
 	// tx can be set to an existing db transaction. If not set, a new
 	// transaction will be started.
-	tx *bbolt.Tx
+	tx kvdb.Tx
 
 	// graph is the ChannelGraph to be used during path finding.
 	graph *channeldb.ChannelGraph
@@ -223,6 +291,16 @@ type graphParams struct {
 	// set to the current available sending bandwidth for active local
 	// channels, and 0 for inactive channels.
 	bandwidthHints map[uint64]lnwire.MilliSatoshi
+
+	// edgeProbability is an optional estimate of the probability that an
+	// HTLC forwarded through an edge succeeds. If not set, all edges are
+	// assumed to succeed.
+	edgeProbability func(EdgeLocator) float64
+
+	// nodeHoldTime is an optional estimate of the time a node takes to
+	// resolve the HTLCs it's offered. If not set, nodes are assumed to
+	// resolve HTLCs instantly.
+	nodeHoldTime func(route.Vertex) time.Duration
 }
 
 // RestrictParams wraps the set of restrictions passed to findPath that the
@@ -248,6 +326,10 @@ type RestrictParams struct {
 	// ctlv. After path finding is complete, the caller needs to increase
 	// all cltv expiry heights with the required final cltv delta.
 	CltvLimit *uint32
+
+	// Weights configures the cost function used to choose among the paths
+	// adhering to the restrictions. If nil, DefaultPathWeights are used.
+	Weights *PathWeights
 }
 
 // findPath attempts to find a path from the source node within the
@@ -264,16 +346,23 @@ type RestrictParams struct {
 func findPath(g *graphParams, r *RestrictParams, source, target route.Vertex,
 	amt lnwire.MilliSatoshi) ([]*channeldb.ChannelEdgePolicy, error) {
 
-	var err error
-	tx := g.tx
-	if tx == nil {
-		tx, err = g.graph.Database().Begin(false)
-		if err != nil {
-			return nil, err
-		}
-		defer tx.Rollback()
+	// Without a transaction to use, we'll search for the path within a
+	// read-only transaction of our own.
+	if g.tx == nil {
+		var path []*channeldb.ChannelEdgePolicy
+		err := g.graph.Database().View(func(tx kvdb.Tx) error {
+			withTx := *g
+			withTx.tx = tx
+
+			var err error
+			path, err = findPath(&withTx, r, source, target, amt)
+			return err
+		})
+		return path, err
 	}
 
+	tx := g.tx
+
 	// First we'll initialize an empty heap which'll help us to quickly
 	// locate the next edge we should visit next during our graph
 	// traversal.
@@ -284,7 +373,7 @@ func findPath(g *graphParams, r *RestrictParams, source, target route.Vertex,
 	// also returns the source node, so there is no need to add the source
 	// node explicitly.
 	distance := make(map[route.Vertex]nodeWithDist)
-	if err := g.graph.ForEachNode(tx, func(_ *bbolt.Tx,
+	if err := g.graph.ForEachNode(tx, func(_ kvdb.Tx,
 		node *channeldb.LightningNode) error {
 		// TODO(roasbeef): with larger graph can just use disk seeks
 		// with a visited map
@@ -350,6 +439,10 @@ func findPath(g *graphParams, r *RestrictParams, source, target route.Vertex,
 	if ignoredNodes == nil {
 		ignoredNodes = make(map[route.Vertex]struct{})
 	}
+	weights := r.Weights
+	if weights == nil {
+		weights = &DefaultPathWeights
+	}
 
 	// processEdge is a helper closure that will be used to make sure edges
 	// satisfy our specific requirements.
@@ -453,11 +546,29 @@ func findPath(g *graphParams, r *RestrictParams, source, target route.Vertex,
 			return
 		}
 
+		// Our own channels are known to be able to carry the amount,
+		// so only the success of the edges of other nodes is
+		// uncertain.
+		probability := 1.0
+		if !isSourceChan && g.edgeProbability != nil {
+			probability = g.edgeProbability(*locator)
+		}
+
+		var holdTime time.Duration
+		if !isSourceChan && g.nodeHoldTime != nil {
+			holdTime = g.nodeHoldTime(fromVertex)
+		}
+
 		// By adding fromNode in the route, there will be an extra
-		// weight composed of the fee that this node will charge and
-		// the amount that will be locked for timeLockDelta blocks in
-		// the HTLC that is handed out to fromNode.
-		weight := edgeWeight(amountToReceive, fee, timeLockDelta)
+		// weight composed of the fee that this node will charge, the
+		// amount that will be locked for timeLockDelta blocks in the
+		// HTLC that is handed out to fromNode, the chance of the HTLC
+		// failing to be forwarded, and the time fromNode is expected
+		// to hold on to it.
+		weight := edgeWeight(
+			weights, amountToReceive, fee, timeLockDelta,
+			probability, holdTime,
+		)
 
 		// Compute the tentative distance to this new channel/edge
 		// which is the distance from our toNode to the target node
@@ -520,7 +631,7 @@ func findPath(g *graphParams, r *RestrictParams, source, target route.Vertex,
 		// examine all the incoming edges (channels) from this node to
 		// further our graph traversal.
 		pivot := route.Vertex(bestNode.PubKeyBytes)
-		err := bestNode.ForEachChannel(tx, func(tx *bbolt.Tx,
+		err := bestNode.ForEachChannel(tx, func(tx kvdb.Tx,
 			edgeInfo *channeldb.ChannelEdgeInfo,
 			_, inEdge *channeldb.ChannelEdgePolicy) error {
 
@@ -627,12 +738,14 @@ func findPath(g *graphParams, r *RestrictParams, source, target route.Vertex,
 // will be ignored by our modified Dijkstra's algorithm. With this approach, we
 // make our inner path finding algorithm aware of our k-shortest paths
 // algorithm, rather than attempting to use an unmodified path finding
-// algorithm in a block box manner.
-func findPaths(tx *bbolt.Tx, graph *channeldb.ChannelGraph,
-	source, target route.Vertex, amt lnwire.MilliSatoshi,
-	restrictions *RestrictParams, numPaths uint32,
-	bandwidthHints map[uint64]lnwire.MilliSatoshi) (
-	[][]*channeldb.ChannelEdgePolicy, error) {
+// algorithm in a block box manner. All path finding attempts are made with the
+// passed graph parameters, such that the optional additional edges, such as
+// those derived from route hints, are explored alongside the edges of the
+// graph. The spur paths deviating from the previous shortest path are searched
+// for concurrently.
+func findPaths(g *graphParams, source, target route.Vertex,
+	amt lnwire.MilliSatoshi, restrictions *RestrictParams,
+	numPaths uint32) ([][]*channeldb.ChannelEdgePolicy, error) {
 
 	// TODO(roasbeef): modifying ordering within heap to eliminate final
 	// sorting step?
@@ -644,14 +757,7 @@ func findPaths(tx *bbolt.Tx, graph *channeldb.ChannelGraph,
 	// First we'll find a single shortest path from the source (our
 	// selfNode) to the target destination that's capable of carrying amt
 	// satoshis along the path before fees are calculated.
-	startingPath, err := findPath(
-		&graphParams{
-			tx:             tx,
-			graph:          graph,
-			bandwidthHints: bandwidthHints,
-		},
-		restrictions, source, target, amt,
-	)
+	startingPath, err := findPath(g, restrictions, source, target, amt)
 	if err != nil {
 		log.Errorf("Unable to find path: %v", err)
 		return nil, err
@@ -676,6 +782,7 @@ func findPaths(tx *bbolt.Tx, graph *channeldb.ChannelGraph,
 		// We'll examine each edge in the previous iteration's shortest
 		// path in order to find path deviations from each node in the
 		// path.
+		searches := make([]*spurSearch, 0, len(prevShortest)-1)
 		for i := 0; i < len(prevShortest)-1; i++ {
 			// These two maps will mark the edges and Vertexes
 			// we'll exclude from the next path finding attempt.
@@ -737,32 +844,35 @@ func findPaths(tx *bbolt.Tx, graph *channeldb.ChannelGraph,
 			//
 			// TODO: Outgoing channel restriction isn't obeyed for
 			// spur paths.
-			spurRestrictions := &RestrictParams{
-				IgnoredEdges: ignoredEdges,
-				IgnoredNodes: ignoredVertexes,
-				FeeLimit:     restrictions.FeeLimit,
-			}
-
-			spurPath, err := findPath(
-				&graphParams{
-					tx:             tx,
-					graph:          graph,
-					bandwidthHints: bandwidthHints,
+			searches = append(searches, &spurSearch{
+				spurNode: spurNode.PubKeyBytes,
+				rootPath: rootPath,
+				restrictions: &RestrictParams{
+					IgnoredEdges: ignoredEdges,
+					IgnoredNodes: ignoredVertexes,
+					FeeLimit:     restrictions.FeeLimit,
+					Weights:      restrictions.Weights,
 				},
-				spurRestrictions, spurNode.PubKeyBytes,
-				target, amt,
-			)
+			})
+		}
+
+		// The spur paths deviating from each node are independent of
+		// each other, so we'll search for them concurrently.
+		spurPaths, err := findSpurPaths(g, searches, target, amt)
+		if err != nil {
+			return nil, err
+		}
 
-			// If we weren't able to find a path, we'll continue to
-			// the next round.
-			if IsError(err, ErrNoPathFound) {
+		for i, spurPath := range spurPaths {
+			// If we weren't able to find a path from this spur
+			// node, we'll continue to the next one.
+			if spurPath == nil {
 				continue
-			} else if err != nil {
-				return nil, err
 			}
 
 			// Create the new combined path by concatenating the
 			// rootPath to the spurPath.
+			rootPath := searches[i].rootPath
 			newPathLen := len(rootPath) + len(spurPath)
 			newPath := path{
 				hops: make([]*channeldb.ChannelEdgePolicy, 0, newPathLen),
@@ -793,3 +903,71 @@ func findPaths(tx *bbolt.Tx, graph *channeldb.ChannelGraph,
 
 	return shortestPaths, nil
 }
+
+// spurSearch describes the search for a path deviating from the previous
+// shortest path at its spur node, as part of our k-shortest paths algorithm.
+type spurSearch struct {
+	// spurNode is the node the path deviates from.
+	spurNode route.Vertex
+
+	// rootPath is the part of the previous shortest path leading up to
+	// and including the spur node.
+	rootPath []*channeldb.ChannelEdgePolicy
+
+	// restrictions exclude the edges and nodes that would cause the path
+	// to repeat a known path or contain loops.
+	restrictions *RestrictParams
+}
+
+// findSpurPaths runs the passed spur path searches concurrently, bounded by
+// spurSearchWorkers, and returns the path found by each search at its index.
+// Searches that are unable to find a path yield a nil path. Any other error
+// encountered aborts the k-shortest paths algorithm, in which case the error
+// of the first failed search is returned.
+func findSpurPaths(g *graphParams, searches []*spurSearch,
+	target route.Vertex, amt lnwire.MilliSatoshi) (
+	[][]*channeldb.ChannelEdgePolicy, error) {
+
+	// A database transaction must not be used by multiple goroutines at
+	// once, so each search will open a transaction of its own.
+	spurGraph := *g
+	spurGraph.tx = nil
+
+	var (
+		spurPaths = make([][]*channeldb.ChannelEdgePolicy, len(searches))
+		errs      = make([]error, len(searches))
+		workers   = make(chan struct{}, spurSearchWorkers)
+		wg        sync.WaitGroup
+	)
+
+	for i, search := range searches {
+		workers <- struct{}{}
+		wg.Add(1)
+
+		go func(i int, search *spurSearch) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			spurPaths[i], errs[i] = findPath(
+				&spurGraph, search.restrictions,
+				search.spurNode, target, amt,
+			)
+		}(i, search)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		switch {
+		case IsError(err, ErrNoPathFound):
+			spurPaths[i] = nil
+
+		case err != nil:
+			return nil, err
+		}
+	}
+
+	return spurPaths, nil
+}
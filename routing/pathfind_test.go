@@ -976,8 +976,10 @@ func TestKShortestPathFinding(t *testing.T) {
 		FeeLimit: noFeeLimit,
 	}
 	paths, err := findPaths(
-		nil, graph.graph, sourceNode.PubKeyBytes, target, paymentAmt,
-		restrictions, 100, nil,
+		&graphParams{
+			graph: graph.graph,
+		},
+		sourceNode.PubKeyBytes, target, paymentAmt, restrictions, 100,
 	)
 	if err != nil {
 		t.Fatalf("unable to find paths between roasbeef and "+
@@ -1733,7 +1735,7 @@ func TestPathFindSpecExample(t *testing.T) {
 	carol := ctx.aliases["C"]
 	const amt lnwire.MilliSatoshi = 4999999
 	routes, err := ctx.router.FindRoutes(
-		bobNode.PubKeyBytes, carol, amt, noRestrictions, 100,
+		bobNode.PubKeyBytes, carol, amt, noRestrictions, 100, nil,
 	)
 	if err != nil {
 		t.Fatalf("unable to find route: %v", err)
@@ -1799,7 +1801,7 @@ func TestPathFindSpecExample(t *testing.T) {
 
 	// We'll now request a route from A -> B -> C.
 	routes, err = ctx.router.FindRoutes(
-		source.PubKeyBytes, carol, amt, noRestrictions, 100,
+		source.PubKeyBytes, carol, amt, noRestrictions, 100, nil,
 	)
 	if err != nil {
 		t.Fatalf("unable to find routes: %v", err)
@@ -2148,3 +2150,134 @@ func testCltvLimit(t *testing.T, limit uint32, expectedChannel uint64) {
 			route.Hops[0].ChannelID)
 	}
 }
+
+// TestPathWeights asserts that the weights of the cost function steer path
+// finding towards the cheapest path, the one with the lowest time lock, or the
+// one most likely to succeed.
+func TestPathWeights(t *testing.T) {
+	t.Parallel()
+
+	// Set up a test graph with three possible paths to the target. The path
+	// through a has the lowest fee, but a high time lock (144) and a low
+	// probability of success, and a is somewhat slow to resolve HTLCs. The
+	// path through b has a higher fee, but is likely to succeed. The path
+	// through c and d has the same fee as the path through b, but the
+	// lowest total time lock (60).
+	testChannels := []*testChannel{
+		symmetricTestChannel("roasbeef", "a", 100000, &testChannelPolicy{}, 1),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: 10000,
+			MinHTLC:     1,
+		}, 4),
+		symmetricTestChannel("roasbeef", "b", 100000, &testChannelPolicy{}, 2),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:      100,
+			FeeBaseMsat: 20000,
+			MinHTLC:     1,
+		}, 5),
+		symmetricTestChannel("roasbeef", "c", 100000, &testChannelPolicy{}, 3),
+		symmetricTestChannel("c", "d", 100000, &testChannelPolicy{
+			Expiry:      30,
+			FeeBaseMsat: 10000,
+			MinHTLC:     1,
+		}, 6),
+		symmetricTestChannel("d", "target", 100000, &testChannelPolicy{
+			Expiry:      30,
+			FeeBaseMsat: 10000,
+			MinHTLC:     1,
+		}, 7),
+	}
+
+	testGraphInstance, err := createTestGraphFromChannels(testChannels)
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	defer testGraphInstance.cleanUp()
+
+	sourceNode, err := testGraphInstance.graph.SourceNode()
+	if err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+	sourceVertex := route.Vertex(sourceNode.PubKeyBytes)
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	target := testGraphInstance.aliasMap["target"]
+
+	probabilities := map[uint64]float64{
+		4: 0.1,
+		5: 0.9,
+		6: 0.5,
+		7: 0.5,
+	}
+	edgeProbability := func(e EdgeLocator) float64 {
+		return probabilities[e.ChannelID]
+	}
+
+	holdTimes := map[route.Vertex]time.Duration{
+		testGraphInstance.aliasMap["a"]: 5 * time.Second,
+		testGraphInstance.aliasMap["c"]: 2 * time.Second,
+	}
+	nodeHoldTime := func(v route.Vertex) time.Duration {
+		return holdTimes[v]
+	}
+
+	tests := []struct {
+		name            string
+		weights         *PathWeights
+		expectedChannel uint64
+	}{
+		{
+			name:            "default",
+			expectedChannel: 1,
+		},
+		{
+			name: "low time lock",
+			weights: &PathWeights{
+				FeeWeight:            1,
+				RiskFactorBillionths: 10000000,
+			},
+			expectedChannel: 3,
+		},
+		{
+			name: "high probability",
+			weights: &PathWeights{
+				FeeWeight:         1,
+				ProbabilityWeight: 100000,
+			},
+			expectedChannel: 2,
+		},
+		{
+			name: "slow nodes",
+			weights: &PathWeights{
+				FeeWeight:      1,
+				HoldTimeWeight: 10000,
+			},
+			expectedChannel: 2,
+		},
+	}
+
+	for _, test := range tests {
+		path, err := findPath(
+			&graphParams{
+				graph:           testGraphInstance.graph,
+				edgeProbability: edgeProbability,
+				nodeHoldTime:    nodeHoldTime,
+			},
+			&RestrictParams{
+				FeeLimit: noFeeLimit,
+				Weights:  test.weights,
+			},
+			sourceVertex, target, paymentAmt,
+		)
+		if err != nil {
+			t.Fatalf("%v: unable to find path: %v", test.name, err)
+		}
+
+		if path[0].ChannelID != test.expectedChannel {
+			t.Fatalf("%v: expected path through channel %v, got %v",
+				test.name, test.expectedChannel,
+				path[0].ChannelID)
+		}
+	}
+}
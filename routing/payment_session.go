@@ -36,8 +36,23 @@ type paymentSession struct {
 	preBuiltRoutes []*route.Route
 
 	pathFinder pathFinder
+
+	// candidateFinder, if set, is used instead of pathFinder to find
+	// numCandidatePaths candidate paths at once. The best path is used
+	// right away, while the others are kept in candidatePaths to serve
+	// the retries of the payment without searching the graph again.
+	candidateFinder candidatePathFinder
+
+	// candidatePaths are the remaining candidate paths found for
+	// candidateAmt, excluding the self edge of the source.
+	candidatePaths [][]*channeldb.ChannelEdgePolicy
+	candidateAmt   lnwire.MilliSatoshi
 }
 
+// numCandidatePaths is the number of candidate paths a payment session looks
+// for whenever it needs to search the graph for a new route.
+const numCandidatePaths = 5
+
 // ReportVertexFailure adds a vertex to the graph prune view after a client
 // reports a routing failure localized to the vertex. The time the vertex was
 // added is noted, as it'll be pruned from the shared view after a period of
@@ -75,6 +90,7 @@ func (p *paymentSession) ReportEdgeFailure(e *EdgeLocator) {
 	// payment sessions.
 	p.mc.Lock()
 	p.mc.failedEdges[*e] = time.Now()
+	p.mc.recordOutcome(*e, false)
 	p.mc.Unlock()
 }
 
@@ -87,6 +103,10 @@ func (p *paymentSession) ReportEdgeFailure(e *EdgeLocator) {
 func (p *paymentSession) ReportEdgePolicyFailure(
 	errSource route.Vertex, failedEdge *EdgeLocator) {
 
+	// The candidate paths carry the policies known at the time they were
+	// found, which may have been outdated by the failure.
+	p.candidatePaths = nil
+
 	// Check to see if we've already reported a policy related failure for
 	// this channel. If so, then we'll prune out the vertex.
 	_, ok := p.errFailedPolicyChans[*failedEdge]
@@ -103,6 +123,35 @@ func (p *paymentSession) ReportEdgePolicyFailure(
 	p.errFailedPolicyChans[*failedEdge] = struct{}{}
 }
 
+// reserveBandwidth deducts the amount sent along the given route from the
+// bandwidth hint of its outgoing channel, such that routes requested for other
+// shards of the payment in flight don't exceed the channel's bandwidth.
+func (p *paymentSession) reserveBandwidth(rt *route.Route) {
+	chanID := rt.Hops[0].ChannelID
+	bandwidth, ok := p.bandwidthHints[chanID]
+	if !ok {
+		return
+	}
+
+	if bandwidth < rt.TotalAmount {
+		p.bandwidthHints[chanID] = 0
+		return
+	}
+
+	p.bandwidthHints[chanID] = bandwidth - rt.TotalAmount
+}
+
+// releaseBandwidth returns the amount sent along the given route to the
+// bandwidth hint of its outgoing channel, once the shard sent along it failed.
+func (p *paymentSession) releaseBandwidth(rt *route.Route) {
+	chanID := rt.Hops[0].ChannelID
+	if _, ok := p.bandwidthHints[chanID]; !ok {
+		return
+	}
+
+	p.bandwidthHints[chanID] += rt.TotalAmount
+}
+
 // RequestRoute returns a route which is likely to be capable for successfully
 // routing the specified HTLC payment to the target node. Initially the first
 // set of paths returned from this method may encounter routing failure along
@@ -149,34 +198,77 @@ func (p *paymentSession) RequestRoute(payment *LightningPayment,
 		cltvLimit = &limit
 	}
 
+	// Unless the payment specifies its own weights, paths are selected
+	// according to those of our configuration.
+	weights := payment.PathWeights
+	if weights == nil {
+		weights = p.mc.pathWeights
+	}
+
+	// If candidate paths are left over from a previous search, the first
+	// one that remains viable is used without searching the graph again.
+	sourceVertex := route.Vertex(p.mc.selfNode.PubKeyBytes)
+	if rt := p.nextCandidate(
+		payment, height, finalCltvDelta, cltvLimit,
+	); rt != nil {
+		return rt, nil
+	}
+
 	// TODO(roasbeef): sync logic amongst dist sys
 
 	// Taking into account this prune view, we'll attempt to locate a path
 	// to our destination, respecting the recommendations from
 	// missionControl.
-	path, err := p.pathFinder(
-		&graphParams{
-			graph:           p.mc.graph,
-			additionalEdges: p.additionalEdges,
-			bandwidthHints:  p.bandwidthHints,
-		},
-		&RestrictParams{
-			IgnoredNodes:      pruneView.vertexes,
-			IgnoredEdges:      pruneView.edges,
-			FeeLimit:          payment.FeeLimit,
-			OutgoingChannelID: payment.OutgoingChannelID,
-			CltvLimit:         cltvLimit,
-		},
-		p.mc.selfNode.PubKeyBytes, payment.Target,
-		payment.Amount,
-	)
-	if err != nil {
-		return nil, err
+	g := &graphParams{
+		graph:           p.mc.graph,
+		additionalEdges: p.additionalEdges,
+		bandwidthHints:  p.bandwidthHints,
+		edgeProbability: p.mc.edgeProbability,
+		nodeHoldTime:    p.mc.nodeHoldTime,
+	}
+	restrictions := &RestrictParams{
+		IgnoredNodes:      pruneView.vertexes,
+		IgnoredEdges:      pruneView.edges,
+		FeeLimit:          payment.FeeLimit,
+		OutgoingChannelID: payment.OutgoingChannelID,
+		CltvLimit:         cltvLimit,
+		Weights:           weights,
+	}
+
+	var path []*channeldb.ChannelEdgePolicy
+	if p.candidateFinder != nil {
+		paths, err := p.candidateFinder(
+			g, sourceVertex, payment.Target, payment.Amount,
+			restrictions, numCandidatePaths,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Each candidate path starts with a self edge that isn't part
+		// of the route.
+		path = paths[0][1:]
+
+		p.candidatePaths = p.candidatePaths[:0]
+		for _, candidate := range paths[1:] {
+			p.candidatePaths = append(
+				p.candidatePaths, candidate[1:],
+			)
+		}
+		p.candidateAmt = payment.Amount
+	} else {
+		var err error
+		path, err = p.pathFinder(
+			g, restrictions, sourceVertex, payment.Target,
+			payment.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// With the next candidate path found, we'll attempt to turn this into
 	// a route by applying the time-lock and fee requirements.
-	sourceVertex := route.Vertex(p.mc.selfNode.PubKeyBytes)
 	route, err := newRoute(
 		payment.Amount, sourceVertex, path, height, finalCltvDelta,
 	)
@@ -188,3 +280,85 @@ func (p *paymentSession) RequestRoute(payment *LightningPayment,
 
 	return route, err
 }
+
+// nextCandidate pops candidate paths found by a previous search until one is
+// found that is still able to carry the payment, and returns it as a route. As
+// the restrictions aren't fully obeyed while searching for the candidates, and
+// the prune view and bandwidth hints may have changed since, they are checked
+// against the route. If none of the candidates are viable, nil is returned.
+func (p *paymentSession) nextCandidate(payment *LightningPayment,
+	height uint32, finalCltvDelta uint16, cltvLimit *uint32) *route.Route {
+
+	// The candidates are only suitable for the amount they were found
+	// for.
+	if payment.Amount != p.candidateAmt {
+		p.candidatePaths = nil
+		return nil
+	}
+
+	sourceVertex := route.Vertex(p.mc.selfNode.PubKeyBytes)
+	for len(p.candidatePaths) > 0 {
+		path := p.candidatePaths[0]
+		p.candidatePaths[0] = nil // Set to nil to avoid GC leak.
+		p.candidatePaths = p.candidatePaths[1:]
+
+		rt, err := newRoute(
+			payment.Amount, sourceVertex, path, height,
+			finalCltvDelta,
+		)
+		if err != nil {
+			continue
+		}
+
+		if p.isViable(rt, payment, height, finalCltvDelta, cltvLimit) {
+			return rt
+		}
+	}
+
+	return nil
+}
+
+// isViable returns whether the given route adheres to the restrictions of the
+// payment, and avoids the edges and vertexes pruned during this session.
+func (p *paymentSession) isViable(rt *route.Route, payment *LightningPayment,
+	height uint32, finalCltvDelta uint16, cltvLimit *uint32) bool {
+
+	firstHop := rt.Hops[0].ChannelID
+	if payment.OutgoingChannelID != nil &&
+		firstHop != *payment.OutgoingChannelID {
+
+		return false
+	}
+
+	bandwidth, ok := p.bandwidthHints[firstHop]
+	if ok && bandwidth < rt.TotalAmount {
+		return false
+	}
+
+	if rt.TotalFees > payment.FeeLimit {
+		return false
+	}
+
+	delay := rt.TotalTimeLock - height - uint32(finalCltvDelta)
+	if cltvLimit != nil && delay > *cltvLimit {
+		return false
+	}
+
+	pruneView := p.pruneViewSnapshot
+	from := rt.SourcePubKey
+	for _, hop := range rt.Hops {
+		to := hop.PubKeyBytes
+		if _, ok := pruneView.vertexes[to]; ok {
+			return false
+		}
+
+		edge := newEdgeLocatorByPubkeys(hop.ChannelID, &from, &to)
+		if _, ok := pruneView.edges[*edge]; ok {
+			return false
+		}
+
+		from = to
+	}
+
+	return true
+}
@@ -59,3 +59,91 @@ func TestRequestRoute(t *testing.T) {
 			route.TotalTimeLock)
 	}
 }
+
+// TestRequestRouteCandidates asserts that the candidate paths found alongside a
+// route are used for subsequent route requests, as long as they avoid the
+// pruned edges, before the graph is searched again.
+func TestRequestRouteCandidates(t *testing.T) {
+	const (
+		height         = 10
+		finalCltvDelta = 8
+	)
+
+	var searches int
+	findPaths := func(g *graphParams, source, target route.Vertex,
+		amt lnwire.MilliSatoshi, r *RestrictParams, numPaths uint32) (
+		[][]*channeldb.ChannelEdgePolicy, error) {
+
+		searches++
+
+		var paths [][]*channeldb.ChannelEdgePolicy
+		for i := byte(1); i <= 3; i++ {
+			paths = append(paths, []*channeldb.ChannelEdgePolicy{
+				{
+					Node: &channeldb.LightningNode{
+						PubKeyBytes: source,
+					},
+				},
+				{
+					Node: &channeldb.LightningNode{
+						PubKeyBytes: route.Vertex{i},
+					},
+					ChannelID: uint64(i),
+				},
+			})
+		}
+
+		return paths, nil
+	}
+
+	session := &paymentSession{
+		mc: &missionControl{
+			selfNode: &channeldb.LightningNode{},
+		},
+		pruneViewSnapshot: graphPruneView{
+			edges:    make(map[EdgeLocator]struct{}),
+			vertexes: make(map[route.Vertex]struct{}),
+		},
+		candidateFinder: findPaths,
+	}
+
+	payment := &LightningPayment{
+		Amount:   1000,
+		FeeLimit: 1000,
+	}
+
+	assertRoute := func(chanID uint64, expectedSearches int) {
+		t.Helper()
+
+		rt, err := session.RequestRoute(
+			payment, height, finalCltvDelta,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rt.Hops[0].ChannelID != chanID {
+			t.Fatalf("expected route through channel %v, got %v",
+				chanID, rt.Hops[0].ChannelID)
+		}
+		if searches != expectedSearches {
+			t.Fatalf("expected %v searches, got %v",
+				expectedSearches, searches)
+		}
+	}
+
+	// The first route is taken from a new search.
+	assertRoute(1, 1)
+
+	// After the edge of the second candidate is pruned, the third
+	// candidate should be used without searching again.
+	self := route.Vertex{}
+	second := route.Vertex{2}
+	edge := newEdgeLocatorByPubkeys(2, &self, &second)
+	session.pruneViewSnapshot.edges[*edge] = struct{}{}
+
+	assertRoute(3, 1)
+
+	// With all candidates used up, the graph should be searched again.
+	assertRoute(1, 2)
+}
@@ -0,0 +1,117 @@
+package routing
+
+import (
+	"crypto/rand"
+
+	"github.com/litecoinfinance/lnd/htlcswitch"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing/route"
+)
+
+// ProbeResult describes the outcome of a payment probe.
+type ProbeResult struct {
+	// Route is the route along which the probe was sent.
+	Route *route.Route
+
+	// Success is true if the probe reached the destination, which means
+	// that every channel along the route was able to carry the amount.
+	Success bool
+
+	// FailureSource is the node that failed the probe. If the probe
+	// succeeded, this is the destination.
+	FailureSource route.Vertex
+
+	// Failure is the failure message returned by FailureSource.
+	Failure lnwire.FailureMessage
+}
+
+// ProbePayment tests whether the payment described by the passed
+// LightningPayment can be routed, without risking any funds. An HTLC is sent
+// along the route that would be used for the payment, but with a random
+// payment hash the destination doesn't know the preimage of, so that it's
+// certain to be failed. If the destination itself fails the HTLC, each channel
+// along the route was able to carry it. Otherwise, the failure is applied to
+// mission control just like failures of actual payments are, such that another
+// probe will avoid the failed part of the route.
+//
+// The PaymentHash of the passed payment is ignored.
+func (r *ChannelRouter) ProbePayment(
+	payment *LightningPayment) (*ProbeResult, error) {
+
+	payment, err := r.limitPaymentCltv(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	paySession, err := r.missionControl.NewPaymentSession(
+		payment.RouteHints, payment.Target,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	rt, err := paySession.RequestRoute(
+		payment, uint32(currentHeight), payment.finalCLTVDelta(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var probeHash [32]byte
+	if _, err := rand.Read(probeHash[:]); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Probing route to %x for %v with hash %x",
+		payment.Target, payment.Amount, probeHash)
+
+	_, err = r.sendToSwitch(rt, probeHash, false)
+	if err == nil {
+		// This should never happen, as no one knows the preimage.
+		r.missionControl.reportRouteSuccess(rt)
+		return &ProbeResult{
+			Route:   rt,
+			Success: true,
+		}, nil
+	}
+
+	// Failures that occurred before the HTLC left our node tell us nothing
+	// about the route.
+	fErr, ok := err.(*htlcswitch.ForwardingError)
+	if !ok {
+		return nil, err
+	}
+
+	result := &ProbeResult{
+		Route:         rt,
+		FailureSource: route.NewVertex(fErr.ErrorSource),
+		Failure:       fErr.FailureMessage,
+	}
+
+	// If the destination rejected the payment hash, the HTLC made it all
+	// the way, so each of the edges was able to forward it.
+	finalHop := rt.Hops[len(rt.Hops)-1].PubKeyBytes
+	if result.FailureSource == finalHop {
+		switch fErr.FailureMessage.(type) {
+		case *lnwire.FailUnknownPaymentHash,
+			*lnwire.FailIncorrectPaymentAmount:
+
+			r.missionControl.reportRouteSuccess(rt)
+			result.Success = true
+
+			return result, nil
+		}
+	}
+
+	log.Debugf("Probe with hash %x failed at %x: %v", probeHash,
+		result.FailureSource, fErr.FailureMessage)
+
+	r.processSendError(paySession, rt, err)
+
+	return result, nil
+}
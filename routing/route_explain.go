@@ -0,0 +1,202 @@
+package routing
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing/route"
+	"github.com/litecoinfinance/lnd/zpay32"
+)
+
+// RouteRejection describes why a candidate route was rejected during path
+// finding.
+type RouteRejection uint8
+
+const (
+	// RejectedIgnoredNode signals that the route passes through a node
+	// that was to be ignored.
+	RejectedIgnoredNode RouteRejection = iota
+
+	// RejectedIgnoredEdge signals that the route traverses an edge that
+	// was to be ignored.
+	RejectedIgnoredEdge
+
+	// RejectedFeeLimit signals that the total fees of the route exceed the
+	// fee limit.
+	RejectedFeeLimit
+
+	// RejectedCltvLimit signals that the total time lock of the route
+	// exceeds the CLTV limit.
+	RejectedCltvLimit
+)
+
+// String returns a human readable version of the rejection reason.
+func (r RouteRejection) String() string {
+	switch r {
+	case RejectedIgnoredNode:
+		return "IgnoredNode"
+	case RejectedIgnoredEdge:
+		return "IgnoredEdge"
+	case RejectedFeeLimit:
+		return "FeeLimit"
+	case RejectedCltvLimit:
+		return "CltvLimit"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(r))
+	}
+}
+
+// RejectedRoute is a candidate route that was rejected during path finding,
+// along with the reason it was rejected.
+type RejectedRoute struct {
+	// Route is the rejected route.
+	Route *route.Route
+
+	// Reason is the first restriction the route was found to violate.
+	Reason RouteRejection
+}
+
+// ExplainRoutes behaves like FindRoutes, but in addition to the selected
+// routes, it also returns up to numRejected of the most promising candidate
+// routes that were rejected due to the passed restrictions, along with the
+// reason each of them was rejected. The candidates are found by repeating path
+// finding with all restrictions lifted, so unlike FindRoutes, not finding any
+// route that adheres to the restrictions isn't treated as an error.
+func (r *ChannelRouter) ExplainRoutes(source, target route.Vertex,
+	amt lnwire.MilliSatoshi, restrictions *RestrictParams, numPaths,
+	numRejected uint32,
+	routeHints map[route.Vertex][]*channeldb.ChannelEdgePolicy,
+	finalExpiry ...uint16) ([]*route.Route, []*RejectedRoute, error) {
+
+	finalCLTVDelta := uint16(zpay32.DefaultFinalCLTVDelta)
+	if len(finalExpiry) > 0 {
+		finalCLTVDelta = finalExpiry[0]
+	}
+
+	routes, err := r.FindRoutes(
+		source, target, amt, restrictions, numPaths, routeHints,
+		finalCLTVDelta,
+	)
+	if err != nil && !IsError(err, ErrNoPathFound) {
+		return nil, nil, err
+	}
+
+	if numRejected == 0 {
+		return routes, nil, nil
+	}
+
+	// To find the candidates that were rejected, we'll look for routes
+	// without any of the restrictions that may cause a route to be
+	// rejected. As the selected routes will be found once again, we'll
+	// need to look for enough routes to make up for them.
+	relaxed := &RestrictParams{
+		FeeLimit:          lnwire.MilliSatoshi(math.MaxUint64),
+		OutgoingChannelID: restrictions.OutgoingChannelID,
+		Weights:           restrictions.Weights,
+	}
+	candidates, err := r.FindRoutes(
+		source, target, amt, relaxed, numPaths+numRejected,
+		routeHints, finalCLTVDelta,
+	)
+	switch {
+	case IsError(err, ErrNoPathFound):
+		return routes, nil, nil
+	case err != nil:
+		return nil, nil, err
+	}
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selected := make(map[string]struct{}, len(routes))
+	for _, rt := range routes {
+		selected[routeKey(rt)] = struct{}{}
+	}
+
+	var rejected []*RejectedRoute
+	for _, candidate := range candidates {
+		if uint32(len(rejected)) == numRejected {
+			break
+		}
+
+		if _, ok := selected[routeKey(candidate)]; ok {
+			continue
+		}
+
+		// Candidates that don't violate any of the restrictions were
+		// merely outranked by the selected routes, so they weren't
+		// rejected.
+		reason, ok := rejectionReason(
+			candidate, restrictions, uint32(currentHeight),
+			finalCLTVDelta,
+		)
+		if !ok {
+			continue
+		}
+
+		rejected = append(rejected, &RejectedRoute{
+			Route:  candidate,
+			Reason: reason,
+		})
+	}
+
+	return routes, rejected, nil
+}
+
+// rejectionReason returns the first of the passed restrictions that the route
+// violates. False is returned if the route adheres to all of them.
+func rejectionReason(rt *route.Route, restrictions *RestrictParams,
+	currentHeight uint32, finalCLTVDelta uint16) (RouteRejection, bool) {
+
+	prevNode := rt.SourcePubKey
+	for _, hop := range rt.Hops {
+		_, ok := restrictions.IgnoredNodes[hop.PubKeyBytes]
+		if ok {
+			return RejectedIgnoredNode, true
+		}
+
+		// The direction of the edge is determined by whether we're
+		// coming from the node with the lexicographically smaller
+		// key.
+		locator := EdgeLocator{ChannelID: hop.ChannelID}
+		if bytes.Compare(prevNode[:], hop.PubKeyBytes[:]) > 0 {
+			locator.Direction = 1
+		}
+		if _, ok := restrictions.IgnoredEdges[locator]; ok {
+			return RejectedIgnoredEdge, true
+		}
+
+		prevNode = hop.PubKeyBytes
+	}
+
+	if rt.TotalFees > restrictions.FeeLimit {
+		return RejectedFeeLimit, true
+	}
+
+	// The CLTV limit excludes the final CLTV delta, as it's independent
+	// of the path.
+	if restrictions.CltvLimit != nil {
+		timeLock := rt.TotalTimeLock - currentHeight -
+			uint32(finalCLTVDelta)
+		if timeLock > *restrictions.CltvLimit {
+			return RejectedCltvLimit, true
+		}
+	}
+
+	return 0, false
+}
+
+// routeKey returns a key uniquely identifying the path of the route.
+func routeKey(rt *route.Route) string {
+	var b bytes.Buffer
+	for _, hop := range rt.Hops {
+		fmt.Fprintf(&b, "%d:", hop.ChannelID)
+	}
+
+	return b.String()
+}
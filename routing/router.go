@@ -13,12 +13,13 @@ import (
 	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 
 	sphinx "github.com/litecoinfinance/lightning-onion"
+	"github.com/litecoinfinance/lnd/batch"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/htlcswitch"
 	"github.com/litecoinfinance/lnd/input"
 	"github.com/litecoinfinance/lnd/lnwallet"
@@ -59,12 +60,14 @@ type ChannelGraphSource interface {
 	// AddNode is used to add information about a node to the router
 	// database. If the node with this pubkey is not present in an existing
 	// channel, it will be ignored.
-	AddNode(node *channeldb.LightningNode) error
+	AddNode(node *channeldb.LightningNode,
+		op ...batch.SchedulerOption) error
 
 	// AddEdge is used to add edge/channel to the topology of the router,
 	// after all information about channel will be gathered this
 	// edge/channel might be used in construction of payment path.
-	AddEdge(edge *channeldb.ChannelEdgeInfo) error
+	AddEdge(edge *channeldb.ChannelEdgeInfo,
+		op ...batch.SchedulerOption) error
 
 	// AddProof updates the channel edge info with proof which is needed to
 	// properly announce the edge to the rest of the network.
@@ -72,7 +75,8 @@ type ChannelGraphSource interface {
 
 	// UpdateEdge is used to update edge information, without this message
 	// edge considered as not fully constructed.
-	UpdateEdge(policy *channeldb.ChannelEdgePolicy) error
+	UpdateEdge(policy *channeldb.ChannelEdgePolicy,
+		op ...batch.SchedulerOption) error
 
 	// IsStaleNode returns true if the graph source has a node announcement
 	// for the target node with a more recent timestamp. This method will
@@ -98,6 +102,12 @@ type ChannelGraphSource interface {
 	// live.
 	MarkEdgeLive(chanID lnwire.ShortChannelID) error
 
+	// ResurrectEdge clears an edge from our zombie index, and adds it back
+	// to the graph if its info was retained when it was marked as a
+	// zombie. False is returned if the edge's announcement needs to be
+	// received again before it's part of the graph.
+	ResurrectEdge(chanID lnwire.ShortChannelID) (bool, error)
+
 	// ForAllOutgoingChannels is used to iterate over all channels
 	// emanating from the "source" node which is the center of the
 	// star-graph.
@@ -181,6 +191,13 @@ type Config struct {
 		htlcAdd *lnwire.UpdateAddHTLC,
 		circuit *sphinx.Circuit) ([sha256.Size]byte, error)
 
+	// SendShardToSwitch is like SendToSwitch, but forwards a payment that
+	// is one of several shards of a multi-path payment, which may be in
+	// flight alongside the other shards. If nil, payments are never split.
+	SendShardToSwitch func(firstHop lnwire.ShortChannelID,
+		htlcAdd *lnwire.UpdateAddHTLC,
+		circuit *sphinx.Circuit) ([sha256.Size]byte, error)
+
 	// ChannelPruneExpiry is the duration used to determine if a channel
 	// should be pruned or not. If the delta between now and when the
 	// channel was last updated is greater than ChannelPruneExpiry, then
@@ -203,6 +220,23 @@ type Config struct {
 	// spentness of channel outpoints. For neutrino, this saves long rescans
 	// from blocking initial usage of the daemon.
 	AssumeChannelValid bool
+
+	// FeeBudget, if non-nil, bounds the routing fees paid by all payments
+	// combined. The fee limit of each payment is capped at the remaining
+	// budget, and the fees of each attempt are reserved before it's
+	// dispatched.
+	FeeBudget *FeeBudget
+
+	// PathWeights are the weights of the cost function used by path
+	// finding for payments and queries that don't specify their own. If
+	// nil, DefaultPathWeights are used.
+	PathWeights *PathWeights
+
+	// MaxCltvExpiry is the maximum number of blocks for which the funds of
+	// a payment may be locked up along its route, including the final
+	// CLTV delta. Routes with a larger total time lock are never found or
+	// attempted. If zero, the total time lock of routes is unbounded.
+	MaxCltvExpiry uint32
 }
 
 // routeTuple is an entry within the ChannelRouter's route cache. We cache
@@ -365,7 +399,7 @@ func New(cfg Config) (*ChannelRouter, error) {
 	}
 
 	r.missionControl = newMissionControl(
-		cfg.Graph, selfNode, cfg.QueryBandwidth,
+		cfg.Graph, selfNode, cfg.QueryBandwidth, cfg.PathWeights,
 	)
 
 	return r, nil
@@ -644,7 +678,10 @@ func (r *ChannelRouter) syncGraphWithChain() error {
 // usually signals that a channel has been closed on-chain. We do this
 // periodically to keep a healthy, lively routing table.
 func (r *ChannelRouter) pruneZombieChans() error {
-	var chansToPrune []uint64
+	var (
+		chansToPrune []uint64
+		prunedChans  []*channeldb.ChannelEdgeInfo
+	)
 	chanExpiry := r.cfg.ChannelPruneExpiry
 
 	log.Infof("Examining channel graph for zombie channels")
@@ -720,6 +757,7 @@ func (r *ChannelRouter) pruneZombieChans() error {
 
 		// TODO(roasbeef): add ability to delete single directional edge
 		chansToPrune = append(chansToPrune, info.ChannelID)
+		prunedChans = append(prunedChans, info)
 
 		// As we're detecting this as a zombie channel, we'll add this
 		// to the set of recently rejected items so we don't re-accept
@@ -749,6 +787,15 @@ func (r *ChannelRouter) pruneZombieChans() error {
 		return fmt.Errorf("unable to delete zombie channels: %v", err)
 	}
 
+	// Topology clients mirroring the graph need to learn of the pruned
+	// channels as well. As they weren't closed on-chain, their summaries
+	// carry no close height.
+	if len(prunedChans) > 0 {
+		r.notifyTopologyChange(&TopologyChange{
+			ClosedChannels: createCloseSummaries(0, prunedChans...),
+		})
+	}
+
 	// With the channels pruned, we'll also attempt to prune any nodes that
 	// were a part of them.
 	err = r.cfg.Graph.PruneGraphNodes()
@@ -811,7 +858,9 @@ func (r *ChannelRouter) networkHandler() {
 				// this is either a new update from our PoV or
 				// an update to a prior vertex/edge we
 				// previously accepted.
-				err = r.processUpdate(update.msg)
+				err = r.processUpdate(
+					update.msg, update.op...,
+				)
 				update.err <- err
 
 				// If this message had any dependencies, then
@@ -1017,8 +1066,11 @@ func (r *ChannelRouter) assertNodeAnnFreshness(node route.Vertex,
 // processUpdate processes a new relate authenticated channel/edge, node or
 // channel/edge update network update. If the update didn't affect the internal
 // state of the draft due to either being out of date, invalid, or redundant,
-// then error is returned.
-func (r *ChannelRouter) processUpdate(msg interface{}) error {
+// then error is returned. The passed scheduler options are applied to the
+// resulting write to the graph.
+func (r *ChannelRouter) processUpdate(msg interface{},
+	op ...batch.SchedulerOption) error {
+
 	switch msg := msg.(type) {
 	case *channeldb.LightningNode:
 		// Before we add the node to the database, we'll check to see
@@ -1029,7 +1081,7 @@ func (r *ChannelRouter) processUpdate(msg interface{}) error {
 			return err
 		}
 
-		if err := r.cfg.Graph.AddLightningNode(msg); err != nil {
+		if err := r.cfg.Graph.AddLightningNode(msg, op...); err != nil {
 			return errors.Errorf("unable to add node %v to the "+
 				"graph: %v", msg.PubKeyBytes, err)
 		}
@@ -1070,7 +1122,8 @@ func (r *ChannelRouter) processUpdate(msg interface{}) error {
 		// short-circuit our path straight to adding the edge to our
 		// graph.
 		if r.cfg.AssumeChannelValid {
-			if err := r.cfg.Graph.AddChannelEdge(msg); err != nil {
+			err := r.cfg.Graph.AddChannelEdge(msg, op...)
+			if err != nil {
 				return fmt.Errorf("unable to add edge: %v", err)
 			}
 			log.Infof("New channel discovered! Link "+
@@ -1138,7 +1191,7 @@ func (r *ChannelRouter) processUpdate(msg interface{}) error {
 		// after commitment fees are dynamic.
 		msg.Capacity = btcutil.Amount(chanUtxo.Value)
 		msg.ChannelPoint = *fundingPoint
-		if err := r.cfg.Graph.AddChannelEdge(msg); err != nil {
+		if err := r.cfg.Graph.AddChannelEdge(msg, op...); err != nil {
 			return errors.Errorf("unable to add edge: %v", err)
 		}
 
@@ -1246,7 +1299,7 @@ func (r *ChannelRouter) processUpdate(msg interface{}) error {
 		// Now that we know this isn't a stale update, we'll apply the
 		// new edge policy to the proper directional edge within the
 		// channel graph.
-		if err = r.cfg.Graph.UpdateEdgePolicy(msg); err != nil {
+		if err = r.cfg.Graph.UpdateEdgePolicy(msg, op...); err != nil {
 			err := errors.Errorf("unable to add channel: %v", err)
 			log.Error(err)
 			return err
@@ -1309,6 +1362,7 @@ func (r *ChannelRouter) fetchChanPoint(
 // error channel.
 type routingMsg struct {
 	msg interface{}
+	op  []batch.SchedulerOption
 	err chan error
 }
 
@@ -1374,9 +1428,12 @@ func pathsToFeeSortedRoutes(source route.Vertex, paths [][]*channeldb.ChannelEdg
 // within its inner loop.  Once we have a set of candidate routes, we calculate
 // the required fee and time lock values running backwards along the route. The
 // route that will be ranked the highest is the one with the lowest cumulative
-// fee along the route.
+// fee along the route. The optional routeHints are additional edges, as
+// returned by RouteHintsToEdges, through which targets that aren't part of the
+// public graph may be reached.
 func (r *ChannelRouter) FindRoutes(source, target route.Vertex,
 	amt lnwire.MilliSatoshi, restrictions *RestrictParams, numPaths uint32,
+	routeHints map[route.Vertex][]*channeldb.ChannelEdgePolicy,
 	finalExpiry ...uint16) ([]*route.Route, error) {
 
 	var finalCLTVDelta uint16
@@ -1394,10 +1451,15 @@ func (r *ChannelRouter) FindRoutes(source, target route.Vertex,
 	// returned.
 
 	// We can short circuit the routing by opportunistically checking to
-	// see if the target vertex event exists in the current graph.
-	if _, exists, err := r.cfg.Graph.HasLightningNode(target); err != nil {
+	// see if the target vertex event exists in the current graph. If route
+	// hints were provided, the target may be a private node only reachable
+	// through them, so we'll leave it to path finding instead.
+	_, exists, err := r.cfg.Graph.HasLightningNode(target)
+	switch {
+	case err != nil:
 		return nil, err
-	} else if !exists {
+
+	case !exists && len(routeHints) == 0:
 		log.Debugf("Target %x is not in known graph", target)
 		return nil, newErrf(ErrTargetNotInNetwork, "target not found")
 	}
@@ -1419,25 +1481,47 @@ func (r *ChannelRouter) FindRoutes(source, target route.Vertex,
 		return nil, err
 	}
 
-	tx, err := r.cfg.Graph.Database().Begin(false)
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
-
 	// Now that we know the destination is reachable within the graph,
 	// we'll execute our KSP algorithm to find the k-shortest paths from
 	// our source to the destination.
-	shortestPaths, err := findPaths(
-		tx, r.cfg.Graph, source, target, amt, restrictions,
-		numPaths, bandwidthHints,
-	)
+	//
+	// Unless the restrictions specify their own, the paths are selected
+	// according to the weights of our configuration.
+	if restrictions.Weights == nil {
+		withWeights := *restrictions
+		withWeights.Weights = r.cfg.PathWeights
+		restrictions = &withWeights
+	}
+
+	// Paths that would lock up our funds for longer than allowed by our
+	// configuration aren't considered either.
+	restrictions, err = r.limitRestrictionsCltv(restrictions, finalCLTVDelta)
 	if err != nil {
-		tx.Rollback()
 		return nil, err
 	}
 
-	tx.Rollback()
+	// All paths are searched for within a single read-only transaction,
+	// such that they're found within a consistent view of the graph.
+	mc := r.missionControl
+	var shortestPaths [][]*channeldb.ChannelEdgePolicy
+	err = r.cfg.Graph.Database().View(func(tx kvdb.Tx) error {
+		var err error
+		shortestPaths, err = findPaths(
+			&graphParams{
+				tx:              tx,
+				graph:           r.cfg.Graph,
+				additionalEdges: routeHints,
+				bandwidthHints:  bandwidthHints,
+				edgeProbability: mc.edgeProbability,
+				nodeHoldTime:    mc.nodeHoldTime,
+			},
+			source, target, amt, restrictions, numPaths,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// Now that we have a set of paths, we'll need to turn them into
 	// *routes* by computing the required time-lock and fee information for
@@ -1581,9 +1665,45 @@ type LightningPayment struct {
 	// hop. If nil, any channel may be used.
 	OutgoingChannelID *uint64
 
+	// MaxShards is the maximum number of shards SendMultiPathPayment may
+	// split the payment into, which are in flight at the same time. If it
+	// is at most one, the payment is sent along a single route.
+	MaxShards uint32
+
+	// PathWeights are the weights of the cost function used to find the
+	// routes of the payment. If nil, the weights of the router's
+	// configuration are used.
+	PathWeights *PathWeights
+
+	// AttemptFailed, if set, is called with each route along which the
+	// payment, or one of its shards, was sent without success, along with
+	// the error the attempt failed with. It's never called concurrently.
+	AttemptFailed func(rt *route.Route, err error)
+
 	// TODO(roasbeef): add e2e message?
 }
 
+// finalCLTVDelta returns the CLTV delta to use for the final hop of the
+// payment, falling back to the default if none was specified.
+func (p *LightningPayment) finalCLTVDelta() uint16 {
+	if p.FinalCLTVDelta == nil {
+		return zpay32.DefaultFinalCLTVDelta
+	}
+
+	return *p.FinalCLTVDelta
+}
+
+// payAttemptTimeout returns the duration after which no further attempts are
+// made to complete the payment, falling back to the default if none was
+// specified.
+func (p *LightningPayment) payAttemptTimeout() time.Duration {
+	if p.PayAttemptTimeout == time.Duration(0) {
+		return defaultPayAttemptTimeout
+	}
+
+	return p.PayAttemptTimeout
+}
+
 // SendPayment attempts to send a payment as described within the passed
 // LightningPayment. This function is blocking and will return either: when the
 // payment is successful, or all candidates routes have been attempted and
@@ -1650,19 +1770,15 @@ func (r *ChannelRouter) sendPayment(payment *LightningPayment,
 		return [32]byte{}, nil, err
 	}
 
-	var finalCLTVDelta uint16
-	if payment.FinalCLTVDelta == nil {
-		finalCLTVDelta = zpay32.DefaultFinalCLTVDelta
-	} else {
-		finalCLTVDelta = *payment.FinalCLTVDelta
+	// Routes that would lock up our funds for longer than allowed by our
+	// configuration are never requested.
+	payment, err = r.limitPaymentCltv(payment)
+	if err != nil {
+		return [32]byte{}, nil, err
 	}
 
-	var payAttemptTimeout time.Duration
-	if payment.PayAttemptTimeout == time.Duration(0) {
-		payAttemptTimeout = defaultPayAttemptTimeout
-	} else {
-		payAttemptTimeout = payment.PayAttemptTimeout
-	}
+	finalCLTVDelta := payment.finalCLTVDelta()
+	payAttemptTimeout := payment.payAttemptTimeout()
 
 	timeoutChan := time.After(payAttemptTimeout)
 
@@ -1691,26 +1807,80 @@ func (r *ChannelRouter) sendPayment(payment *LightningPayment,
 			// are expiring.
 		}
 
+		// If our routing fees are bounded by a budget, we won't look
+		// for routes that pay more than what remains of it.
+		attempt := payment
+		if r.cfg.FeeBudget != nil {
+			budgeted := *payment
+			budgeted.FeeLimit = r.cfg.FeeBudget.FeeLimit(
+				payment.FeeLimit,
+			)
+			attempt = &budgeted
+		}
+
 		route, err := paySession.RequestRoute(
-			payment, uint32(currentHeight), finalCLTVDelta,
+			attempt, uint32(currentHeight), finalCLTVDelta,
 		)
 		if err != nil {
 			// If we're unable to successfully make a payment using
 			// any of the routes we've found, then return an error.
 			if lastError != nil {
-				return [32]byte{}, nil, fmt.Errorf("unable to "+
-					"route payment to destination: %v",
-					lastError)
+				return [32]byte{}, nil, newErrf(
+					ErrNoRouteFound, "unable to route "+
+						"payment to destination: %v",
+					lastError,
+				)
 			}
 
 			return [32]byte{}, nil, err
 		}
 
+		// Routes provided by the caller haven't been found by path
+		// finding, so their time lock may still exceed our maximum.
+		err = r.checkRouteCltv(route, uint32(currentHeight))
+		if err != nil {
+			log.Warnf("Unable to send payment %x: %v",
+				payment.PaymentHash, err)
+
+			lastError = err
+			continue
+		}
+
+		// Before dispatching the attempt, we'll reserve its fees. This
+		// may fail if concurrent payments have consumed the budget
+		// since we requested the route, in which case we'll move on to
+		// the next one.
+		if r.cfg.FeeBudget != nil {
+			err := r.cfg.FeeBudget.Reserve(route.TotalFees)
+			if err != nil {
+				log.Warnf("Unable to send payment %x with fee "+
+					"%v: %v", payment.PaymentHash,
+					route.TotalFees, err)
+
+				lastError = err
+				continue
+			}
+		}
+
 		// Send payment attempt. It will return a final boolean
 		// indicating if more attempts are needed.
 		preimage, final, err := r.sendPaymentAttempt(
 			paySession, route, payment.PaymentHash,
 		)
+		if err != nil && payment.AttemptFailed != nil {
+			payment.AttemptFailed(route, err)
+		}
+
+		// Only the fees of a successful attempt are accounted as
+		// spent.
+		if r.cfg.FeeBudget != nil {
+			if err == nil {
+				r.cfg.FeeBudget.Settle(route.TotalFees)
+			} else {
+				r.cfg.FeeBudget.Release(route.TotalFees)
+			}
+		}
+
 		if final {
 			return preimage, route, err
 		}
@@ -1732,8 +1902,9 @@ func (r *ChannelRouter) sendPaymentAttempt(paySession *paymentSession,
 		}),
 	)
 
-	preimage, err := r.sendToSwitch(route, paymentHash)
+	preimage, err := r.sendToSwitch(route, paymentHash, false)
 	if err == nil {
+		r.missionControl.reportRouteSuccess(route)
 		return preimage, true, nil
 	}
 
@@ -1746,9 +1917,10 @@ func (r *ChannelRouter) sendPaymentAttempt(paySession *paymentSession,
 }
 
 // sendToSwitch sends a payment along the specified route and returns the
-// obtained preimage.
-func (r *ChannelRouter) sendToSwitch(route *route.Route, paymentHash [32]byte) (
-	[32]byte, error) {
+// obtained preimage. The shard flag indicates whether the payment is one of
+// several shards of a multi-path payment.
+func (r *ChannelRouter) sendToSwitch(route *route.Route, paymentHash [32]byte,
+	shard bool) ([32]byte, error) {
 
 	// Generate the raw encoded sphinx packet to be included along
 	// with the htlcAdd message that we send directly to the
@@ -1776,9 +1948,24 @@ func (r *ChannelRouter) sendToSwitch(route *route.Route, paymentHash [32]byte) (
 	firstHop := lnwire.NewShortChanIDFromInt(
 		route.Hops[0].ChannelID,
 	)
-	return r.cfg.SendToSwitch(
-		firstHop, htlcAdd, circuit,
-	)
+	start := time.Now()
+
+	var preimage [32]byte
+	if shard {
+		preimage, err = r.cfg.SendShardToSwitch(
+			firstHop, htlcAdd, circuit,
+		)
+	} else {
+		preimage, err = r.cfg.SendToSwitch(
+			firstHop, htlcAdd, circuit,
+		)
+	}
+
+	// The time it took for the HTLC to be resolved tells us how long the
+	// nodes along the route held on to it.
+	r.reportRouteHoldTime(route, time.Since(start), err)
+
+	return preimage, err
 }
 
 // processSendError analyzes the error for the payment attempt received from the
@@ -2094,9 +2281,12 @@ func (r *ChannelRouter) applyChannelUpdate(msg *lnwire.ChannelUpdate,
 // be ignored.
 //
 // NOTE: This method is part of the ChannelGraphSource interface.
-func (r *ChannelRouter) AddNode(node *channeldb.LightningNode) error {
+func (r *ChannelRouter) AddNode(node *channeldb.LightningNode,
+	op ...batch.SchedulerOption) error {
+
 	rMsg := &routingMsg{
 		msg: node,
+		op:  op,
 		err: make(chan error, 1),
 	}
 
@@ -2118,9 +2308,12 @@ func (r *ChannelRouter) AddNode(node *channeldb.LightningNode) error {
 // in construction of payment path.
 //
 // NOTE: This method is part of the ChannelGraphSource interface.
-func (r *ChannelRouter) AddEdge(edge *channeldb.ChannelEdgeInfo) error {
+func (r *ChannelRouter) AddEdge(edge *channeldb.ChannelEdgeInfo,
+	op ...batch.SchedulerOption) error {
+
 	rMsg := &routingMsg{
 		msg: edge,
+		op:  op,
 		err: make(chan error, 1),
 	}
 
@@ -2141,9 +2334,12 @@ func (r *ChannelRouter) AddEdge(edge *channeldb.ChannelEdgeInfo) error {
 // considered as not fully constructed.
 //
 // NOTE: This method is part of the ChannelGraphSource interface.
-func (r *ChannelRouter) UpdateEdge(update *channeldb.ChannelEdgePolicy) error {
+func (r *ChannelRouter) UpdateEdge(update *channeldb.ChannelEdgePolicy,
+	op ...batch.SchedulerOption) error {
+
 	rMsg := &routingMsg{
 		msg: update,
+		op:  op,
 		err: make(chan error, 1),
 	}
 
@@ -2196,7 +2392,7 @@ func (r *ChannelRouter) FetchLightningNode(node route.Vertex) (*channeldb.Lightn
 //
 // NOTE: This method is part of the ChannelGraphSource interface.
 func (r *ChannelRouter) ForEachNode(cb func(*channeldb.LightningNode) error) error {
-	return r.cfg.Graph.ForEachNode(nil, func(_ *bbolt.Tx, n *channeldb.LightningNode) error {
+	return r.cfg.Graph.ForEachNode(nil, func(_ kvdb.Tx, n *channeldb.LightningNode) error {
 		return cb(n)
 	})
 }
@@ -2208,7 +2404,7 @@ func (r *ChannelRouter) ForEachNode(cb func(*channeldb.LightningNode) error) err
 func (r *ChannelRouter) ForAllOutgoingChannels(cb func(*channeldb.ChannelEdgeInfo,
 	*channeldb.ChannelEdgePolicy) error) error {
 
-	return r.selfNode.ForEachChannel(nil, func(_ *bbolt.Tx, c *channeldb.ChannelEdgeInfo,
+	return r.selfNode.ForEachChannel(nil, func(_ kvdb.Tx, c *channeldb.ChannelEdgeInfo,
 		e, _ *channeldb.ChannelEdgePolicy) error {
 
 		if e == nil {
@@ -2335,3 +2531,44 @@ func (r *ChannelRouter) IsStaleEdgePolicy(chanID lnwire.ShortChannelID,
 func (r *ChannelRouter) MarkEdgeLive(chanID lnwire.ShortChannelID) error {
 	return r.cfg.Graph.MarkEdgeLive(chanID.ToUint64())
 }
+
+// ResurrectEdge clears an edge from our zombie index, and adds it back to the
+// graph if its info was retained when it was marked as a zombie. The edge is
+// validated like any newly announced edge, so it's only restored if its
+// funding output remains unspent. False is returned if the edge's announcement
+// needs to be received again before it's part of the graph.
+//
+// NOTE: This method is part of the ChannelGraphSource interface.
+func (r *ChannelRouter) ResurrectEdge(chanID lnwire.ShortChannelID) (bool,
+	error) {
+
+	// The info is fetched first, as it's removed along with the edge's
+	// entry in the zombie index.
+	edge, err := r.cfg.Graph.FetchZombieEdge(chanID.ToUint64())
+	switch {
+	// Edges that were marked as zombies by older versions, or through
+	// other means than being pruned from the graph, have no info.
+	case err == channeldb.ErrEdgeNotFound:
+		edge = nil
+
+	case err != nil:
+		return false, err
+	}
+
+	if err := r.cfg.Graph.MarkEdgeLive(chanID.ToUint64()); err != nil {
+		return false, err
+	}
+
+	if edge == nil {
+		return false, nil
+	}
+
+	if err := r.AddEdge(edge); err != nil {
+		return false, err
+	}
+
+	log.Debugf("Restored zombie channel with ChannelID(%v) to graph",
+		chanID.ToUint64())
+
+	return true, nil
+}
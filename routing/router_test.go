@@ -6,6 +6,7 @@ import (
 	"image/color"
 	"math/rand"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -187,7 +188,7 @@ func TestFindRoutesFeeSorting(t *testing.T) {
 	target := ctx.aliases["luoji"]
 	routes, err := ctx.router.FindRoutes(
 		ctx.router.selfNode.PubKeyBytes,
-		target, paymentAmt, noRestrictions, defaultNumRoutes,
+		target, paymentAmt, noRestrictions, defaultNumRoutes, nil,
 		zpay32.DefaultFinalCLTVDelta,
 	)
 	if err != nil {
@@ -249,7 +250,7 @@ func TestFindRoutesWithFeeLimit(t *testing.T) {
 
 	routes, err := ctx.router.FindRoutes(
 		ctx.router.selfNode.PubKeyBytes,
-		target, paymentAmt, restrictions, defaultNumRoutes,
+		target, paymentAmt, restrictions, defaultNumRoutes, nil,
 		zpay32.DefaultFinalCLTVDelta,
 	)
 	if err != nil {
@@ -277,6 +278,453 @@ func TestFindRoutesWithFeeLimit(t *testing.T) {
 	}
 }
 
+// TestMaxCltvExpiry asserts that routes whose total time lock exceeds the
+// maximum of the router's configuration are neither found nor built.
+func TestMaxCltvExpiry(t *testing.T) {
+	t.Parallel()
+
+	const chanCapSat = btcutil.Amount(100000)
+	newPolicy := func(baseFee lnwire.MilliSatoshi,
+		expiry uint16) *testChannelPolicy {
+
+		return &testChannelPolicy{
+			Expiry:      expiry,
+			FeeBaseMsat: baseFee,
+			MinHTLC:     1,
+		}
+	}
+
+	// The route through a is the cheapest, but has a much larger time
+	// lock than the one through b.
+	testChannels := []*testChannel{
+		symmetricTestChannel(
+			"roasbeef", "a", chanCapSat, newPolicy(0, 144), 1,
+		),
+		symmetricTestChannel(
+			"a", "c", chanCapSat, newPolicy(0, 144), 2,
+		),
+		symmetricTestChannel(
+			"roasbeef", "b", chanCapSat, newPolicy(1000, 40), 3,
+		),
+		symmetricTestChannel(
+			"b", "c", chanCapSat, newPolicy(1000, 40), 4,
+		),
+	}
+
+	testGraph, err := createTestGraphFromChannels(testChannels)
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	defer testGraph.cleanUp()
+
+	const startingBlockHeight = 101
+
+	ctx, cleanUp, err := createTestCtxFromGraphInstance(
+		startingBlockHeight, testGraph,
+	)
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	defer cleanUp()
+
+	// With a final CLTV delta of 40, the route through a locks up funds
+	// for 184 blocks, and the one through b for 80 blocks.
+	ctx.router.cfg.MaxCltvExpiry = 100
+
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	routes, err := ctx.router.FindRoutes(
+		ctx.router.selfNode.PubKeyBytes, ctx.aliases["c"], amt,
+		noRestrictions, defaultNumRoutes, nil, 40,
+	)
+	if err != nil {
+		t.Fatalf("unable to find routes: %v", err)
+	}
+	if len(routes) != 1 {
+		t.Fatalf("expected 1 route, got %v", len(routes))
+	}
+	if routes[0].Hops[0].PubKeyBytes != ctx.aliases["b"] {
+		t.Fatalf("expected route through b, got: %v",
+			spew.Sdump(routes[0]))
+	}
+	if routes[0].TotalTimeLock != startingBlockHeight+80 {
+		t.Fatalf("expected total time lock of %v, got %v",
+			startingBlockHeight+80, routes[0].TotalTimeLock)
+	}
+
+	// A final CLTV delta above the maximum leaves no room for any route.
+	_, err = ctx.router.FindRoutes(
+		ctx.router.selfNode.PubKeyBytes, ctx.aliases["c"], amt,
+		noRestrictions, defaultNumRoutes, nil, 101,
+	)
+	if !IsError(err, ErrCltvLimitExceeded) {
+		t.Fatalf("expected ErrCltvLimitExceeded, got: %v", err)
+	}
+
+	// Routes that are built manually are subject to the maximum as well.
+	_, err = ctx.router.BuildRoute(
+		amt, []route.Vertex{ctx.aliases["a"], ctx.aliases["c"]}, nil,
+		40,
+	)
+	if !IsError(err, ErrCltvLimitExceeded) {
+		t.Fatalf("expected ErrCltvLimitExceeded, got: %v", err)
+	}
+
+	_, err = ctx.router.BuildRoute(
+		amt, []route.Vertex{ctx.aliases["b"], ctx.aliases["c"]}, nil,
+		40,
+	)
+	if err != nil {
+		t.Fatalf("unable to build route: %v", err)
+	}
+}
+
+// TestFindRoutesWithRouteHints asserts that routes can be found to a target
+// that isn't part of the graph, as long as route hints lead to it.
+func TestFindRoutesWithRouteHints(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtxFromFile(
+		startingBlockHeight, basicGraphFilePath,
+	)
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	defer cleanUp()
+
+	// The target, doge, is only reachable through a private channel with
+	// songoku.
+	dogeKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	doge := route.NewVertex(dogeKey.PubKey())
+
+	songoku := ctx.aliases["songoku"]
+	songokuKey, err := btcec.ParsePubKey(songoku[:], btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to parse key: %v", err)
+	}
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+
+	// Without any route hints, the target can't be found.
+	_, err = ctx.router.FindRoutes(
+		ctx.router.selfNode.PubKeyBytes, doge, paymentAmt,
+		noRestrictions, defaultNumRoutes, nil,
+	)
+	if !IsError(err, ErrTargetNotInNetwork) {
+		t.Fatalf("expected ErrTargetNotInNetwork, got: %v", err)
+	}
+
+	routeHints, err := RouteHintsToEdges([][]zpay32.HopHint{{{
+		NodeID:                    songokuKey,
+		ChannelID:                 1337,
+		FeeBaseMSat:               1,
+		FeeProportionalMillionths: 1000,
+		CLTVExpiryDelta:           9,
+	}}}, doge)
+	if err != nil {
+		t.Fatalf("unable to convert route hints: %v", err)
+	}
+
+	routes, err := ctx.router.FindRoutes(
+		ctx.router.selfNode.PubKeyBytes, doge, paymentAmt,
+		noRestrictions, defaultNumRoutes, routeHints,
+	)
+	if err != nil {
+		t.Fatalf("unable to find routes: %v", err)
+	}
+	if len(routes) == 0 {
+		t.Fatalf("expected routes to doge")
+	}
+
+	// Every route should end with the hinted channel.
+	for _, rt := range routes {
+		lastHop := rt.Hops[len(rt.Hops)-1]
+		if lastHop.PubKeyBytes != doge || lastHop.ChannelID != 1337 {
+			t.Fatalf("route doesn't end with hinted channel: %v",
+				spew.Sdump(rt))
+		}
+	}
+}
+
+// TestFindCircularRoute asserts that a route leaving through one of our
+// channels and returning through another can be found, and that the amount
+// returned to us excludes all fees.
+func TestFindCircularRoute(t *testing.T) {
+	t.Parallel()
+
+	const chanCapSat = btcutil.Amount(100000)
+	policy := &testChannelPolicy{
+		Expiry:      144,
+		FeeBaseMsat: 1000,
+		MinHTLC:     1,
+	}
+	testChannels := []*testChannel{
+		symmetricTestChannel("roasbeef", "a", chanCapSat, policy, 1),
+		symmetricTestChannel("a", "b", chanCapSat, policy, 2),
+		symmetricTestChannel("b", "roasbeef", chanCapSat, policy, 3),
+	}
+
+	testGraph, err := createTestGraphFromChannels(testChannels)
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	defer testGraph.cleanUp()
+
+	const startingBlockHeight = 101
+
+	ctx, cleanUp, err := createTestCtxFromGraphInstance(
+		startingBlockHeight, testGraph,
+	)
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	defer cleanUp()
+
+	amt := lnwire.NewMSatFromSatoshis(10000)
+	restrictions := &RestrictParams{
+		FeeLimit: noFeeLimit,
+	}
+
+	_, err = ctx.router.FindCircularRoute(amt, 1, 1, restrictions, 40)
+	if err != ErrSameChannel {
+		t.Fatalf("expected ErrSameChannel, got: %v", err)
+	}
+
+	rt, err := ctx.router.FindCircularRoute(amt, 1, 3, restrictions, 40)
+	if err != nil {
+		t.Fatalf("unable to find circular route: %v", err)
+	}
+
+	expHops := []struct {
+		alias  string
+		chanID uint64
+	}{
+		{"a", 1}, {"b", 2}, {"roasbeef", 3},
+	}
+	if len(rt.Hops) != len(expHops) {
+		t.Fatalf("expected %v hops, got %v", len(expHops),
+			len(rt.Hops))
+	}
+	for i, exp := range expHops {
+		hop := rt.Hops[i]
+		if hop.PubKeyBytes != ctx.aliases[exp.alias] ||
+			hop.ChannelID != exp.chanID {
+
+			t.Fatalf("unexpected hop %d: %v", i, spew.Sdump(hop))
+		}
+	}
+
+	// Both a and b charge their base fee.
+	if rt.TotalFees != 2000 {
+		t.Fatalf("expected fees of 2000 msat, got %v", rt.TotalFees)
+	}
+	if rt.TotalAmount-rt.TotalFees != amt {
+		t.Fatalf("expected %v to be returned, got %v", amt,
+			rt.TotalAmount-rt.TotalFees)
+	}
+
+	// A fee limit below the fees of the route is enforced.
+	restrictions.FeeLimit = 1500
+	_, err = ctx.router.FindCircularRoute(amt, 1, 3, restrictions, 40)
+	if err == nil {
+		t.Fatalf("expected fee limit to be enforced")
+	}
+}
+
+// TestBuildRoute asserts that a route along manually specified hops uses the
+// cheapest usable channel between each pair of hops.
+func TestBuildRoute(t *testing.T) {
+	t.Parallel()
+
+	const chanCapSat = btcutil.Amount(100000)
+	newPolicy := func(baseFee lnwire.MilliSatoshi,
+		disabled bool) *testChannelPolicy {
+
+		return &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: baseFee,
+			MinHTLC:     1,
+			Disabled:    disabled,
+		}
+	}
+	testChannels := []*testChannel{
+		symmetricTestChannel(
+			"roasbeef", "a", chanCapSat, newPolicy(1000, false), 1,
+		),
+		symmetricTestChannel(
+			"a", "b", chanCapSat, newPolicy(1000, false), 2,
+		),
+		symmetricTestChannel(
+			"a", "b", chanCapSat, newPolicy(500, false), 3,
+		),
+		symmetricTestChannel(
+			"a", "b", chanCapSat, newPolicy(100, true), 4,
+		),
+		symmetricTestChannel(
+			"b", "c", chanCapSat, newPolicy(1000, false), 5,
+		),
+	}
+
+	testGraph, err := createTestGraphFromChannels(testChannels)
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	defer testGraph.cleanUp()
+
+	const startingBlockHeight = 101
+
+	ctx, cleanUp, err := createTestCtxFromGraphInstance(
+		startingBlockHeight, testGraph,
+	)
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	defer cleanUp()
+
+	amt := lnwire.NewMSatFromSatoshis(10000)
+
+	_, err = ctx.router.BuildRoute(amt, nil, nil, 40)
+	if err != ErrNoHops {
+		t.Fatalf("expected ErrNoHops, got: %v", err)
+	}
+
+	// There's no channel between us and b, so the hops must be adjacent.
+	_, err = ctx.router.BuildRoute(
+		amt, []route.Vertex{ctx.aliases["b"]}, nil, 40,
+	)
+	if err == nil {
+		t.Fatalf("expected route along non-adjacent hops to fail")
+	}
+
+	hops := []route.Vertex{
+		ctx.aliases["a"], ctx.aliases["b"], ctx.aliases["c"],
+	}
+	rt, err := ctx.router.BuildRoute(amt, hops, nil, 40)
+	if err != nil {
+		t.Fatalf("unable to build route: %v", err)
+	}
+
+	// Between a and b, the cheapest channel that isn't disabled should be
+	// selected.
+	expChans := []uint64{1, 3, 5}
+	if len(rt.Hops) != len(expChans) {
+		t.Fatalf("expected %v hops, got %v", len(expChans),
+			len(rt.Hops))
+	}
+	for i, chanID := range expChans {
+		hop := rt.Hops[i]
+		if hop.PubKeyBytes != hops[i] || hop.ChannelID != chanID {
+			t.Fatalf("unexpected hop %d: %v", i, spew.Sdump(hop))
+		}
+	}
+
+	// a charges 500 msat to forward over channel 3, and b 1000 msat to
+	// forward over channel 5. Both add their time lock delta.
+	if rt.TotalFees != 1500 {
+		t.Fatalf("expected fees of 1500 msat, got %v", rt.TotalFees)
+	}
+	if rt.TotalAmount-rt.TotalFees != amt {
+		t.Fatalf("expected %v to be delivered, got %v", amt,
+			rt.TotalAmount-rt.TotalFees)
+	}
+	expTimeLock := uint32(startingBlockHeight + 40 + 2*144)
+	if rt.TotalTimeLock != expTimeLock {
+		t.Fatalf("expected time lock %v, got %v", expTimeLock,
+			rt.TotalTimeLock)
+	}
+}
+
+// TestExplainRoutes asserts that the candidate routes rejected due to the path
+// finding restrictions are returned along with the reason of their rejection.
+func TestExplainRoutes(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtxFromFile(
+		startingBlockHeight, basicGraphFilePath,
+	)
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	defer cleanUp()
+
+	// As in TestFindRoutesWithFeeLimit, there are two routes from roasbeef
+	// to sophon, of which the one through phamnuwen violates our fee
+	// limit.
+	target := ctx.aliases["sophon"]
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	restrictions := &RestrictParams{
+		FeeLimit: lnwire.NewMSatFromSatoshis(10),
+	}
+
+	routes, rejected, err := ctx.router.ExplainRoutes(
+		ctx.router.selfNode.PubKeyBytes, target, paymentAmt,
+		restrictions, defaultNumRoutes, 5, nil,
+		zpay32.DefaultFinalCLTVDelta,
+	)
+	if err != nil {
+		t.Fatalf("unable to explain routes: %v", err)
+	}
+
+	if len(routes) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(routes))
+	}
+	if len(rejected) != 1 {
+		t.Fatalf("expected 1 rejected route, got %d", len(rejected))
+	}
+	if rejected[0].Reason != RejectedFeeLimit {
+		t.Fatalf("expected rejection due to %v, got %v",
+			RejectedFeeLimit, rejected[0].Reason)
+	}
+	if rejected[0].Route.Hops[0].PubKeyBytes != ctx.aliases["phamnuwen"] {
+		t.Fatalf("expected rejected route through phamnuwen, got %s",
+			getAliasFromPubKey(
+				rejected[0].Route.Hops[0].PubKeyBytes,
+				ctx.aliases,
+			))
+	}
+
+	// If we also ignore songoku, no route adheres to our restrictions. We
+	// should still be able to learn why both routes were rejected.
+	restrictions.IgnoredNodes = map[route.Vertex]struct{}{
+		ctx.aliases["songoku"]: {},
+	}
+	routes, rejected, err = ctx.router.ExplainRoutes(
+		ctx.router.selfNode.PubKeyBytes, target, paymentAmt,
+		restrictions, defaultNumRoutes, 5, nil,
+		zpay32.DefaultFinalCLTVDelta,
+	)
+	if err != nil {
+		t.Fatalf("unable to explain routes: %v", err)
+	}
+
+	if len(routes) != 0 {
+		t.Fatalf("expected no routes, got %d", len(routes))
+	}
+	if len(rejected) != 2 {
+		t.Fatalf("expected 2 rejected routes, got %d", len(rejected))
+	}
+
+	reasons := make(map[route.Vertex]RouteRejection)
+	for _, rejectedRoute := range rejected {
+		reasons[rejectedRoute.Route.Hops[0].PubKeyBytes] =
+			rejectedRoute.Reason
+	}
+	if reasons[ctx.aliases["songoku"]] != RejectedIgnoredNode {
+		t.Fatalf("expected route through songoku to be rejected "+
+			"due to %v, got %v", RejectedIgnoredNode,
+			reasons[ctx.aliases["songoku"]])
+	}
+	if reasons[ctx.aliases["phamnuwen"]] != RejectedFeeLimit {
+		t.Fatalf("expected route through phamnuwen to be rejected "+
+			"due to %v, got %v", RejectedFeeLimit,
+			reasons[ctx.aliases["phamnuwen"]])
+	}
+}
+
 // TestSendPaymentRouteFailureFallback tests that when sending a payment, if
 // one of the target routes is seen as unavailable, then the next route in the
 // queue is used instead. This process should continue until either a payment
@@ -742,6 +1190,12 @@ func TestSendPaymentErrorNonFinalTimeLockErrors(t *testing.T) {
 		}
 	}
 
+	// The attempt through Son Goku should be reported as failed.
+	var failedRoutes []*route.Route
+	payment.AttemptFailed = func(rt *route.Route, err error) {
+		failedRoutes = append(failedRoutes, rt)
+	}
+
 	// Send off the payment request to the router, this payment should
 	// succeed as we should actually go through Pham Nuwen in order to get
 	// to Sophon, even though he has higher fees.
@@ -752,6 +1206,13 @@ func TestSendPaymentErrorNonFinalTimeLockErrors(t *testing.T) {
 
 	assertExpectedPath(paymentPreImage, rt)
 
+	if len(failedRoutes) != 1 ||
+		failedRoutes[0].Hops[0].ChannelID != chanID {
+
+		t.Fatalf("expected failed attempt through channel %v, got %v",
+			chanID, spew.Sdump(failedRoutes))
+	}
+
 	// We'll now modify the error return an IncorrectCltvExpiry error
 	// instead, this should result in the same behavior of roasbeef routing
 	// around the faulty Son Goku node.
@@ -787,6 +1248,93 @@ func TestSendPaymentErrorNonFinalTimeLockErrors(t *testing.T) {
 	assertExpectedPath(paymentPreImage, rt)
 }
 
+// TestProbePayment asserts that probes are sent with a random payment hash,
+// and that a failed probe is applied to mission control such that the next
+// probe takes a different route.
+func TestProbePayment(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtxFromFile(
+		startingBlockHeight, basicGraphFilePath,
+	)
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	defer cleanUp()
+
+	payment := LightningPayment{
+		Target:   ctx.aliases["sophon"],
+		Amount:   lnwire.NewMSatFromSatoshis(1000),
+		FeeLimit: noFeeLimit,
+	}
+
+	parseKey := func(alias string) *btcec.PublicKey {
+		pubKey := ctx.aliases[alias]
+		key, err := btcec.ParsePubKey(pubKey[:], btcec.S256())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+
+	// Son Goku lacks the liquidity to forward to Sophon, while the route
+	// through Pham Nuwen makes it all the way to Sophon, who doesn't know
+	// the payment hash.
+	roasbeefSongoku := lnwire.NewShortChanIDFromInt(12345)
+	var probeHashes [][32]byte
+	ctx.router.cfg.SendToSwitch = func(firstHop lnwire.ShortChannelID,
+		htlcAdd *lnwire.UpdateAddHTLC,
+		_ *sphinx.Circuit) ([32]byte, error) {
+
+		probeHashes = append(probeHashes, htlcAdd.PaymentHash)
+
+		if firstHop == roasbeefSongoku {
+			fErr := &lnwire.FailTemporaryChannelFailure{}
+			return [32]byte{}, &htlcswitch.ForwardingError{
+				ErrorSource:    parseKey("songoku"),
+				FailureMessage: fErr,
+			}
+		}
+
+		return [32]byte{}, &htlcswitch.ForwardingError{
+			ErrorSource:    parseKey("sophon"),
+			FailureMessage: &lnwire.FailUnknownPaymentHash{},
+		}
+	}
+
+	result, err := ctx.router.ProbePayment(&payment)
+	if err != nil {
+		t.Fatalf("unable to probe: %v", err)
+	}
+	if result.Success {
+		t.Fatalf("expected first probe to fail")
+	}
+	if result.FailureSource != ctx.aliases["songoku"] {
+		t.Fatalf("expected failure at songoku, got %v",
+			getAliasFromPubKey(result.FailureSource, ctx.aliases))
+	}
+
+	result, err = ctx.router.ProbePayment(&payment)
+	if err != nil {
+		t.Fatalf("unable to probe: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected second probe to succeed, failed at %v: %v",
+			getAliasFromPubKey(result.FailureSource, ctx.aliases),
+			result.Failure)
+	}
+	if result.Route.Hops[0].PubKeyBytes != ctx.aliases["phamnuwen"] {
+		t.Fatalf("expected second probe to pass through phamnuwen")
+	}
+
+	if len(probeHashes) != 2 || probeHashes[0] == probeHashes[1] ||
+		probeHashes[0] == payment.PaymentHash {
+
+		t.Fatalf("expected probes to use distinct random hashes")
+	}
+}
+
 // TestSendPaymentErrorPathPruning tests that the send of candidate routes
 // properly gets pruned in response to ForwardingError response from the
 // underlying SendToSwitch function.
@@ -973,6 +1521,119 @@ func TestSendPaymentErrorPathPruning(t *testing.T) {
 	}
 }
 
+// TestSendMultiPathPayment tests that a payment too large for any single route
+// is split into shards that are sent along different routes, such that the
+// amounts they deliver add up to the full payment.
+func TestSendMultiPathPayment(t *testing.T) {
+	t.Parallel()
+
+	// Set up a network in which roasbeef can reach the target through
+	// either a or b, with every channel being too small to carry the
+	// payment by itself.
+	const chanCapSat = btcutil.Amount(100000)
+	policy := &testChannelPolicy{
+		Expiry:  144,
+		FeeRate: 400,
+		MinHTLC: 1,
+		MaxHTLC: lnwire.NewMSatFromSatoshis(chanCapSat),
+	}
+	testChannels := []*testChannel{
+		symmetricTestChannel("roasbeef", "a", chanCapSat, policy, 1),
+		symmetricTestChannel("roasbeef", "b", chanCapSat, policy, 2),
+		symmetricTestChannel("a", "target", chanCapSat, policy, 3),
+		symmetricTestChannel("b", "target", chanCapSat, policy, 4),
+	}
+
+	testGraph, err := createTestGraphFromChannels(testChannels)
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	defer testGraph.cleanUp()
+
+	const startingBlockHeight = 101
+
+	ctx, cleanUp, err := createTestCtxFromGraphInstance(
+		startingBlockHeight, testGraph,
+	)
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	defer cleanUp()
+
+	var preImage [32]byte
+	copy(preImage[:], bytes.Repeat([]byte{9}, 32))
+
+	// Every shard handed to the switch will be settled, while we keep
+	// track of the channels they were sent over.
+	var (
+		mtx       sync.Mutex
+		firstHops = make(map[lnwire.ShortChannelID]int)
+	)
+	ctx.router.cfg.SendShardToSwitch = func(firstHop lnwire.ShortChannelID,
+		_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+		mtx.Lock()
+		firstHops[firstHop]++
+		mtx.Unlock()
+
+		return preImage, nil
+	}
+
+	var payHash [32]byte
+	payment := LightningPayment{
+		Target:      ctx.aliases["target"],
+		Amount:      lnwire.NewMSatFromSatoshis(150000),
+		FeeLimit:    noFeeLimit,
+		PaymentHash: payHash,
+	}
+
+	// Without allowing the payment to be split, no route can be found.
+	_, _, err = ctx.router.SendMultiPathPayment(&payment)
+	if err == nil {
+		t.Fatalf("expected payment to fail without splitting")
+	}
+
+	// Once it may be split, the payment should succeed using both of
+	// roasbeef's channels.
+	payment.MaxShards = 4
+	paymentPreImage, routes, err := ctx.router.SendMultiPathPayment(
+		&payment,
+	)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	if paymentPreImage != preImage {
+		t.Fatalf("incorrect preimage used: expected %x got %x",
+			preImage[:], paymentPreImage[:])
+	}
+
+	if len(routes) < 2 {
+		t.Fatalf("expected payment to be split, got %v routes",
+			len(routes))
+	}
+
+	var delivered lnwire.MilliSatoshi
+	for _, rt := range routes {
+		if shardAmount(rt) < MinShardAmount {
+			t.Fatalf("shard amount %v below minimum",
+				shardAmount(rt))
+		}
+		delivered += shardAmount(rt)
+	}
+	if delivered != payment.Amount {
+		t.Fatalf("expected shards to deliver %v, got %v",
+			payment.Amount, delivered)
+	}
+
+	for _, chanID := range []uint64{1, 2} {
+		firstHop := lnwire.NewShortChanIDFromInt(chanID)
+		if firstHops[firstHop] == 0 {
+			t.Fatalf("expected shard over channel %v", chanID)
+		}
+	}
+}
+
 // TestAddProof checks that we can update the channel proof after channel
 // info was added to the database.
 func TestAddProof(t *testing.T) {
@@ -1347,7 +2008,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 	routes, err := ctx.router.FindRoutes(
 		ctx.router.selfNode.PubKeyBytes,
 		targetPubKeyBytes, paymentAmt, noRestrictions, defaultNumRoutes,
-		zpay32.DefaultFinalCLTVDelta,
+		nil, zpay32.DefaultFinalCLTVDelta,
 	)
 	if err != nil {
 		t.Fatalf("unable to find any routes: %v", err)
@@ -1393,7 +2054,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 	routes, err = ctx.router.FindRoutes(
 		ctx.router.selfNode.PubKeyBytes,
 		targetPubKeyBytes, paymentAmt, noRestrictions, defaultNumRoutes,
-		zpay32.DefaultFinalCLTVDelta,
+		nil, zpay32.DefaultFinalCLTVDelta,
 	)
 	if err != nil {
 		t.Fatalf("unable to find any routes: %v", err)
@@ -0,0 +1,173 @@
+package lnd
+
+import (
+	"fmt"
+
+	"github.com/litecoinfinance/lnd/lnrpc"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/routing"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codedError is an error annotated with the lnrpc.ErrorCode classifying it.
+// It's used for failures that can't be classified by the type or identity of
+// the underlying error, such that RPC clients don't need to match against the
+// error message instead.
+type codedError struct {
+	error
+	code lnrpc.ErrorCode
+}
+
+// newCodedErrorf creates a codedError of the given code from the formatted
+// description.
+func newCodedErrorf(code lnrpc.ErrorCode, format string,
+	a ...interface{}) error {
+
+	return &codedError{
+		error: fmt.Errorf(format, a...),
+		code:  code,
+	}
+}
+
+// rpcErrorCode classifies the given error, returning
+// lnrpc.ErrorCode_UNKNOWN_ERROR if it isn't among the common failures.
+func rpcErrorCode(err error) lnrpc.ErrorCode {
+	switch e := err.(type) {
+	case *codedError:
+		return e.code
+
+	case *lnwallet.ErrInsufficientFunds:
+		return lnrpc.ErrorCode_INSUFFICIENT_BALANCE
+	}
+
+	switch {
+	case err == ErrPeerNotConnected:
+		return lnrpc.ErrorCode_PEER_OFFLINE
+
+	case lnwallet.IsChanReserveError(err):
+		return lnrpc.ErrorCode_CHANNEL_RESERVE_VIOLATION
+
+	case routing.IsError(
+		err, routing.ErrNoPathFound, routing.ErrNoRouteFound,
+		routing.ErrTargetNotInNetwork, routing.ErrMaxHopsExceeded,
+		routing.ErrInsufficientCapacity, routing.ErrFeeLimitExceeded,
+	):
+		return lnrpc.ErrorCode_ROUTE_NOT_FOUND
+
+	default:
+		return lnrpc.ErrorCode_UNKNOWN_ERROR
+	}
+}
+
+// paymentErrorResponse returns the SendResponse reporting the failure of the
+// payment with the given hash, along with the failure's classification.
+func paymentErrorResponse(err error, paymentHash []byte) *lnrpc.SendResponse {
+	return &lnrpc.SendResponse{
+		PaymentError:     err.Error(),
+		PaymentErrorCode: rpcErrorCode(err),
+		PaymentHash:      paymentHash,
+	}
+}
+
+// rpcStatusCodes maps each lnrpc.ErrorCode to the gRPC status code of the
+// calls failing with it.
+var rpcStatusCodes = map[lnrpc.ErrorCode]codes.Code{
+	lnrpc.ErrorCode_INSUFFICIENT_BALANCE:      codes.FailedPrecondition,
+	lnrpc.ErrorCode_PEER_OFFLINE:              codes.Unavailable,
+	lnrpc.ErrorCode_INVOICE_EXPIRED:           codes.FailedPrecondition,
+	lnrpc.ErrorCode_ROUTE_NOT_FOUND:           codes.NotFound,
+	lnrpc.ErrorCode_CHANNEL_RESERVE_VIOLATION: codes.FailedPrecondition,
+}
+
+// rpcStatusError converts the given error into a gRPC status error carrying an
+// lnrpc.ErrorDetail with its classification. Errors that can't be classified,
+// or that already are status errors, are returned unmodified.
+func rpcStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := rpcErrorCode(err)
+	if code == lnrpc.ErrorCode_UNKNOWN_ERROR {
+		return err
+	}
+
+	st, detailErr := status.New(rpcStatusCodes[code], err.Error()).
+		WithDetails(&lnrpc.ErrorDetail{
+			Code:    code,
+			Message: err.Error(),
+		})
+	if detailErr != nil {
+		rpcsLog.Errorf("Unable to attach error detail to %v: %v", err,
+			detailErr)
+		return err
+	}
+
+	return st.Err()
+}
+
+// errorCodeUnaryInterceptor is a gRPC interceptor that classifies the errors
+// returned by unary calls using rpcStatusError.
+func errorCodeUnaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	resp, err := handler(ctx, req)
+	return resp, rpcStatusError(err)
+}
+
+// errorCodeStreamInterceptor is a gRPC interceptor that classifies the errors
+// returned by streaming calls using rpcStatusError.
+func errorCodeStreamInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	return rpcStatusError(handler(srv, ss))
+}
+
+// chainUnaryInterceptors combines the given chain of interceptors into one,
+// such that the first interceptor is the outermost one.
+func chainUnaryInterceptors(
+	chain []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		chained := handler
+		for i := len(chain) - 1; i >= 0; i-- {
+			interceptor, next := chain[i], chained
+			chained = func(ctx context.Context,
+				req interface{}) (interface{}, error) {
+
+				return interceptor(ctx, req, info, next)
+			}
+		}
+
+		return chained(ctx, req)
+	}
+}
+
+// chainStreamInterceptors combines the given chain of interceptors into one,
+// such that the first interceptor is the outermost one.
+func chainStreamInterceptors(
+	chain []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+
+	return func(srv interface{}, ss grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		chained := handler
+		for i := len(chain) - 1; i >= 0; i-- {
+			interceptor, next := chain[i], chained
+			chained = func(srv interface{},
+				ss grpc.ServerStream) error {
+
+				return interceptor(srv, ss, info, next)
+			}
+		}
+
+		return chained(srv, ss)
+	}
+}
@@ -0,0 +1,127 @@
+package lnd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/litecoinfinance/lnd/lnrpc"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestRPCStatusError asserts that classified errors are converted into gRPC
+// status errors carrying their classification, while all others are left
+// untouched.
+func TestRPCStatusError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		err        error
+		expCode    lnrpc.ErrorCode
+		expStatus  codes.Code
+		classified bool
+	}{
+		{
+			name:       "peer offline",
+			err:        ErrPeerNotConnected,
+			expCode:    lnrpc.ErrorCode_PEER_OFFLINE,
+			expStatus:  codes.Unavailable,
+			classified: true,
+		},
+		{
+			name:       "insufficient funds",
+			err:        &lnwallet.ErrInsufficientFunds{},
+			expCode:    lnrpc.ErrorCode_INSUFFICIENT_BALANCE,
+			expStatus:  codes.FailedPrecondition,
+			classified: true,
+		},
+		{
+			name:       "below channel reserve",
+			err:        lnwallet.ErrBelowChanReserve,
+			expCode:    lnrpc.ErrorCode_CHANNEL_RESERVE_VIOLATION,
+			expStatus:  codes.FailedPrecondition,
+			classified: true,
+		},
+		{
+			name:       "channel reserve too large",
+			err:        lnwallet.ErrChanReserveTooLarge(1000, 100),
+			expCode:    lnrpc.ErrorCode_CHANNEL_RESERVE_VIOLATION,
+			expStatus:  codes.FailedPrecondition,
+			classified: true,
+		},
+		{
+			name: "coded error",
+			err: newCodedErrorf(
+				lnrpc.ErrorCode_INVOICE_EXPIRED, "expired",
+			),
+			expCode:    lnrpc.ErrorCode_INVOICE_EXPIRED,
+			expStatus:  codes.FailedPrecondition,
+			classified: true,
+		},
+		{
+			name:    "other reservation error",
+			err:     lnwallet.ErrCsvDelayTooLarge(2000, 1000),
+			expCode: lnrpc.ErrorCode_UNKNOWN_ERROR,
+		},
+		{
+			name:    "unclassified",
+			err:     errors.New("unclassified"),
+			expCode: lnrpc.ErrorCode_UNKNOWN_ERROR,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			code := rpcErrorCode(test.err)
+			if code != test.expCode {
+				t.Fatalf("expected code %v, got %v",
+					test.expCode, code)
+			}
+
+			err := rpcStatusError(test.err)
+			if !test.classified {
+				if err != test.err {
+					t.Fatalf("expected unmodified error, "+
+						"got %v", err)
+				}
+				return
+			}
+
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("expected status error, got %v", err)
+			}
+			if st.Code() != test.expStatus {
+				t.Fatalf("expected status %v, got %v",
+					test.expStatus, st.Code())
+			}
+			if st.Message() != test.err.Error() {
+				t.Fatalf("expected message %q, got %q",
+					test.err.Error(), st.Message())
+			}
+
+			details := st.Details()
+			if len(details) != 1 {
+				t.Fatalf("expected one detail, got %d",
+					len(details))
+			}
+			detail, ok := details[0].(*lnrpc.ErrorDetail)
+			if !ok {
+				t.Fatalf("unexpected detail %T", details[0])
+			}
+			if detail.Code != test.expCode {
+				t.Fatalf("expected detail code %v, got %v",
+					test.expCode, detail.Code)
+			}
+
+			// Errors that already are status errors shouldn't be
+			// classified again.
+			if rpcStatusError(err) != err {
+				t.Fatalf("status error was modified")
+			}
+		})
+	}
+}
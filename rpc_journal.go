@@ -0,0 +1,140 @@
+package lnd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/litecoinfinance/lnd/journal"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/routing/route"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+	macaroon "gopkg.in/macaroon.v2"
+)
+
+// errJournalInactive is returned by the journal RPCs if the journal hasn't
+// been activated.
+var errJournalInactive = errors.New("event journal not active, enable it " +
+	"with journal.active")
+
+// isAdminCall returns true if the given RPC method requires any write
+// permission, in which case its calls are recorded in the journal.
+func isAdminCall(permissions map[string][]bakery.Op, method string) bool {
+	for _, op := range permissions[method] {
+		if op.Action == "write" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// macaroonIdentity returns the hex encoded id of the macaroon the call of the
+// given context was made with, identifying the party that made it. An empty
+// string is returned if no valid macaroon is attached, for example if
+// macaroons are disabled.
+func macaroonIdentity(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md["macaroon"]) != 1 {
+		return ""
+	}
+
+	macBytes, err := hex.DecodeString(md["macaroon"][0])
+	if err != nil {
+		return ""
+	}
+
+	mac := &macaroon.Macaroon{}
+	if err := mac.UnmarshalBinary(macBytes); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(mac.Id())
+}
+
+// journalUnaryInterceptor returns a gRPC interceptor that records the calls
+// of administrative unary methods, along with their outcome, in the journal.
+func journalUnaryInterceptor(j *journal.Journal,
+	permissions map[string][]bakery.Op) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		resp, err := handler(ctx, req)
+		if !isAdminCall(permissions, info.FullMethod) {
+			return resp, err
+		}
+
+		details := fmt.Sprintf("%v succeeded", info.FullMethod)
+		if err != nil {
+			details = fmt.Sprintf("%v failed: %v", info.FullMethod,
+				err)
+		}
+
+		_, recordErr := j.Record(
+			journal.EventRPCAction, macaroonIdentity(ctx), details,
+		)
+		if recordErr != nil {
+			rpcsLog.Errorf("Unable to record call of %v in "+
+				"journal: %v", info.FullMethod, recordErr)
+		}
+
+		return resp, err
+	}
+}
+
+// journalStreamInterceptor returns a gRPC interceptor that records the calls
+// of administrative streaming methods in the journal. As streams may be long
+// lived, they're recorded as soon as they're opened.
+func journalStreamInterceptor(j *journal.Journal,
+	permissions map[string][]bakery.Op) grpc.StreamServerInterceptor {
+
+	return func(srv interface{}, ss grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		if isAdminCall(permissions, info.FullMethod) {
+			_, err := j.Record(
+				journal.EventRPCAction,
+				macaroonIdentity(ss.Context()),
+				fmt.Sprintf("%v opened", info.FullMethod),
+			)
+			if err != nil {
+				rpcsLog.Errorf("Unable to record call of "+
+					"%v in journal: %v", info.FullMethod,
+					err)
+			}
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// recordPaymentSent records the payment with the given hash, completed over
+// the given routes, in the journal if it's active. A payment split across
+// several routes is recorded once, with the amounts and fees of its shards
+// combined.
+func (s *server) recordPaymentSent(paymentHash [32]byte,
+	routes ...*route.Route) {
+
+	if s.journal == nil || len(routes) == 0 || len(routes[0].Hops) == 0 {
+		return
+	}
+
+	var amt, fees lnwire.MilliSatoshi
+	for _, rt := range routes {
+		amt += rt.TotalAmount - rt.TotalFees
+		fees += rt.TotalFees
+	}
+
+	hops := routes[0].Hops
+	dest := hops[len(hops)-1].PubKeyBytes
+	err := s.journal.RecordPaymentSent(paymentHash, dest, amt, fees)
+	if err != nil {
+		srvrLog.Errorf("Unable to record payment %x in journal: %v",
+			paymentHash, err)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/lnrpc/routerrpc"
 	"github.com/litecoinfinance/lnd/routing/route"
 
@@ -27,7 +28,6 @@ import (
 	"github.com/litecoinfinance/btcutil"
 	"github.com/litecoinfinance/btcwallet/waddrmgr"
 	"github.com/litecoinfinance/btcwallet/wallet/txauthor"
-	"github.com/coreos/bbolt"
 	"github.com/davecgh/go-spew/spew"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/litecoinfinance/lnd/autopilot"
@@ -35,10 +35,13 @@ import (
 	"github.com/litecoinfinance/lnd/chanbackup"
 	"github.com/litecoinfinance/lnd/channeldb"
 	"github.com/litecoinfinance/lnd/channelnotifier"
+	"github.com/litecoinfinance/lnd/contractcourt"
 	"github.com/litecoinfinance/lnd/discovery"
+	"github.com/litecoinfinance/lnd/feepolicy"
 	"github.com/litecoinfinance/lnd/htlcswitch"
 	"github.com/litecoinfinance/lnd/input"
 	"github.com/litecoinfinance/lnd/invoices"
+	"github.com/litecoinfinance/lnd/keychain"
 	"github.com/litecoinfinance/lnd/lncfg"
 	"github.com/litecoinfinance/lnd/lnrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/invoicesrpc"
@@ -46,8 +49,13 @@ import (
 	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/macaroons"
+	"github.com/litecoinfinance/lnd/netann"
+	"github.com/litecoinfinance/lnd/paysched"
+	"github.com/litecoinfinance/lnd/rebalance"
+	"github.com/litecoinfinance/lnd/resources"
 	"github.com/litecoinfinance/lnd/routing"
 	"github.com/litecoinfinance/lnd/signal"
+	"github.com/litecoinfinance/lnd/swap"
 	"github.com/litecoinfinance/lnd/sweep"
 	"github.com/litecoinfinance/lnd/zpay32"
 	"github.com/tv42/zbase32"
@@ -217,6 +225,48 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/DualFundAcceptor": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ChannelAcceptor": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/FundingStateStep": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/BatchOpenChannel": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SpliceIn": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SpliceOut": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/CloseChannel": {{
 			Entity: "onchain",
 			Action: "write",
@@ -296,6 +346,10 @@ var (
 			Entity: "invoices",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/DeleteInvoices": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/SubscribeTransactions": {{
 			Entity: "onchain",
 			Action: "read",
@@ -324,10 +378,61 @@ var (
 			Entity: "info",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/GossipStats": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/UpdateGossipHorizon": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ExportGraphSnapshot": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ImportGraphSnapshot": {{
+			Entity: "info",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ExportGraphDump": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ImportGraphDump": {{
+			Entity: "info",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/StopDaemon": {{
 			Entity: "info",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/RotateNodeKey": {{
+			Entity: "info",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListNodeKeyRotations": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ListSubsystems": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ModifySubsystem": {{
+			Entity: "info",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/GetResourceBudget": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SetResourceProfile": {{
+			Entity: "info",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/SubscribeChannelGraph": {{
 			Entity: "info",
 			Action: "read",
@@ -340,10 +445,68 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/DeletePayments": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/GetFeeBudget": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SubscribeFeeBudgetEvents": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AddPaymentSchedule": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListPaymentSchedules": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ExtendPaymentSchedule": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/RemovePaymentSchedule": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribePaymentScheduleAlerts": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/DeriveSwapKey": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/RegisterSwap": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListSwaps": {{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/DebugLevel": {{
 			Entity: "info",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/CompactDatabase": {{
+			Entity: "info",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/CheckDatabase": {{
+			Entity: "info",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/DecodePayReq": {{
 			Entity: "offchain",
 			Action: "read",
@@ -360,6 +523,14 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ForwardingSummary": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/HtlcLatencyReport": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/RestoreChannelBackups": {{
 			Entity: "offchain",
 			Action: "write",
@@ -372,6 +543,14 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ChanBackupRecoverability": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ListBackupFiles": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ExportAllChannelBackups": {{
 			Entity: "offchain",
 			Action: "read",
@@ -380,6 +559,54 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ExportJournal": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AnchorJournal": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ExportChannelAuditLog": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/Rebalance": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SetRebalanceTarget": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/RemoveRebalanceTarget": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListRebalanceTargets": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ListRebalances": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SetFeeSchedule": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/RemoveFeeSchedule": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListFeeSchedules": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/GetChainStatus": {{
+			Entity: "info",
+			Action: "read",
+		}},
 	}
 )
 
@@ -425,6 +652,14 @@ type rpcServer struct {
 	// rpc sub server.
 	routerBackend *routerrpc.RouterBackend
 
+	// atpl is the autopilot manager, used to stop and start the autopilot
+	// agent through ModifySubsystem.
+	atpl *autopilot.Manager
+
+	// subsystemMtx serializes modifications of the running subsystems, so
+	// that their dependency checks can't race with each other.
+	subsystemMtx sync.Mutex
+
 	quit chan struct{}
 }
 
@@ -462,7 +697,8 @@ func newRPCServer(s *server, macService *macaroons.Service,
 			}
 			return info.Capacity, nil
 		},
-		FindRoutes: s.chanRouter.FindRoutes,
+		FindRoutes:    s.chanRouter.FindRoutes,
+		ExplainRoutes: s.chanRouter.ExplainRoutes,
 	}
 
 	var (
@@ -476,7 +712,8 @@ func newRPCServer(s *server, macService *macaroons.Service,
 	err = subServerCgs.PopulateDependencies(
 		s.cc, networkDir, macService, atpl, invoiceRegistry,
 		s.htlcSwitch, activeNetParams.Params, s.chanRouter,
-		routerBackend, s.nodeSigner, s.chanDB,
+		routerBackend, s.nodeSigner, s.chanDB, s.sweeper,
+		s.signChanUpdate,
 	)
 	if err != nil {
 		return nil, err
@@ -515,22 +752,47 @@ func newRPCServer(s *server, macService *macaroons.Service,
 		}
 	}
 
+	// The errors returned by all calls, including those of the
+	// sub-servers, are classified by the outermost interceptors.
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		errorCodeUnaryInterceptor,
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		errorCodeStreamInterceptor,
+	}
+
 	// If macaroons aren't disabled (a non-nil service), then we'll set up
 	// our set of interceptors which will allow us handle the macaroon
 	// authentication in a single location .
 	if macService != nil {
-		unaryInterceptor := grpc.UnaryInterceptor(
+		unaryInterceptors = append(unaryInterceptors,
 			macService.UnaryServerInterceptor(permissions),
 		)
-		streamInterceptor := grpc.StreamInterceptor(
+		streamInterceptors = append(streamInterceptors,
 			macService.StreamServerInterceptor(permissions),
 		)
+	}
 
-		serverOpts = append(serverOpts,
-			unaryInterceptor, streamInterceptor,
+	// If the journal is active, administrative calls are recorded once
+	// they've been authenticated.
+	if s.journal != nil {
+		unaryInterceptors = append(unaryInterceptors,
+			journalUnaryInterceptor(s.journal, permissions),
+		)
+		streamInterceptors = append(streamInterceptors,
+			journalStreamInterceptor(s.journal, permissions),
 		)
 	}
 
+	serverOpts = append(serverOpts,
+		grpc.UnaryInterceptor(
+			chainUnaryInterceptors(unaryInterceptors),
+		),
+		grpc.StreamInterceptor(
+			chainStreamInterceptors(streamInterceptors),
+		),
+	)
+
 	// Finally, with all the pre-set up complete,  we can create the main
 	// gRPC server, and register the main lnrpc server along side.
 	grpcServer := grpc.NewServer(serverOpts...)
@@ -542,6 +804,7 @@ func newRPCServer(s *server, macService *macaroons.Service,
 		grpcServer:    grpcServer,
 		server:        s,
 		routerBackend: routerBackend,
+		atpl:          atpl,
 		quit:          make(chan struct{}, 1),
 	}
 	lnrpc.RegisterLightningServer(grpcServer, rootRPCServer)
@@ -700,14 +963,25 @@ func addrPairsToOutputs(addrPairs map[string]int64) ([]*wire.TxOut, error) {
 // more addresses specified in the passed payment map. The payment map maps an
 // address to a specified output value to be sent to that address.
 func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64,
-	feeRate lnwallet.SatPerKWeight) (*chainhash.Hash, error) {
+	feeRate lnwallet.SatPerKWeight,
+	strategy lnwallet.CoinSelectionStrategy) (*chainhash.Hash, error) {
 
 	outputs, err := addrPairsToOutputs(paymentMap)
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := r.server.cc.wallet.SendOutputs(outputs, feeRate)
+	// Unless a coin selection strategy was requested, we'll leave coin
+	// selection to the underlying wallet. Either way, only confirmed coins
+	// are spent.
+	var tx *wire.MsgTx
+	if strategy == lnwallet.CoinSelectionDefault {
+		tx, err = r.server.cc.wallet.SendOutputs(outputs, feeRate)
+	} else {
+		tx, err = r.server.cc.wallet.SendOutputsWithStrategy(
+			outputs, feeRate, 1, strategy,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -716,6 +990,29 @@ func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64,
 	return &txHash, nil
 }
 
+// parseCoinSelectionStrategy maps the passed RPC coin selection strategy to
+// the one of the wallet.
+func parseCoinSelectionStrategy(
+	strategy lnrpc.CoinSelectionStrategy) (lnwallet.CoinSelectionStrategy,
+	error) {
+
+	switch strategy {
+	case lnrpc.CoinSelectionStrategy_STRATEGY_DEFAULT:
+		return lnwallet.CoinSelectionDefault, nil
+	case lnrpc.CoinSelectionStrategy_STRATEGY_LARGEST_FIRST:
+		return lnwallet.CoinSelectionLargest, nil
+	case lnrpc.CoinSelectionStrategy_STRATEGY_SMALLEST_FIRST:
+		return lnwallet.CoinSelectionSmallest, nil
+	case lnrpc.CoinSelectionStrategy_STRATEGY_RANDOM:
+		return lnwallet.CoinSelectionRandom, nil
+	case lnrpc.CoinSelectionStrategy_STRATEGY_BRANCH_AND_BOUND:
+		return lnwallet.CoinSelectionBranchAndBound, nil
+	default:
+		return 0, fmt.Errorf("unknown coin selection strategy: %v",
+			strategy)
+	}
+}
+
 // ListUnspent returns useful information about each unspent output owned by
 // the wallet, as reported by the underlying `ListUnspentWitness`; the
 // information returned is: outpoint, amount in satoshis, address, address
@@ -896,9 +1193,14 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 		return nil, err
 	}
 
-	rpcsLog.Infof("[sendcoins] addr=%v, amt=%v, sat/kw=%v, sweep_all=%v",
-		in.Addr, btcutil.Amount(in.Amount), int64(feePerKw),
-		in.SendAll)
+	strategy, err := parseCoinSelectionStrategy(in.CoinSelectionStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[sendcoins] addr=%v, amt=%v, sat/kw=%v, sweep_all=%v, "+
+		"coin_selection=%v", in.Addr, btcutil.Amount(in.Amount),
+		int64(feePerKw), in.SendAll, strategy)
 
 	// Decode the address receiving the coins, we need to check whether the
 	// address is valid for this network.
@@ -939,6 +1241,12 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 				"active")
 		}
 
+		// As all coins are swept, there's nothing to select.
+		if strategy != lnwallet.CoinSelectionDefault {
+			return nil, fmt.Errorf("coin selection strategy set " +
+				"while SendAll is active")
+		}
+
 		_, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
 		if err != nil {
 			return nil, err
@@ -981,7 +1289,9 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 		// while we instruct the wallet to send this transaction.
 		paymentMap := map[string]int64{targetAddr.String(): in.Amount}
 		err := wallet.WithCoinSelectLock(func() error {
-			newTXID, err := r.sendCoinsOnChain(paymentMap, feePerKw)
+			newTXID, err := r.sendCoinsOnChain(
+				paymentMap, feePerKw, strategy,
+			)
 			if err != nil {
 				return err
 			}
@@ -1018,8 +1328,13 @@ func (r *rpcServer) SendMany(ctx context.Context,
 		return nil, err
 	}
 
-	rpcsLog.Infof("[sendmany] outputs=%v, sat/kw=%v",
-		spew.Sdump(in.AddrToAmount), int64(feePerKw))
+	strategy, err := parseCoinSelectionStrategy(in.CoinSelectionStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[sendmany] outputs=%v, sat/kw=%v, coin_selection=%v",
+		spew.Sdump(in.AddrToAmount), int64(feePerKw), strategy)
 
 	var txid *chainhash.Hash
 
@@ -1029,7 +1344,7 @@ func (r *rpcServer) SendMany(ctx context.Context,
 	wallet := r.server.cc.wallet
 	err = wallet.WithCoinSelectLock(func() error {
 		sendManyTXID, err := r.sendCoinsOnChain(
-			in.AddrToAmount, feePerKw,
+			in.AddrToAmount, feePerKw, strategy,
 		)
 		if err != nil {
 			return err
@@ -1052,6 +1367,34 @@ func (r *rpcServer) SendMany(ctx context.Context,
 func (r *rpcServer) NewAddress(ctx context.Context,
 	in *lnrpc.NewAddressRequest) (*lnrpc.NewAddressResponse, error) {
 
+	// Addresses of accounts other than the default one are always fresh
+	// ones, so the unused address types aren't supported for them.
+	if in.Account != "" && in.Account != lnwallet.DefaultAccountName {
+		var addrType lnwallet.AddressType
+		switch in.Type {
+		case lnrpc.AddressType_WITNESS_PUBKEY_HASH:
+			addrType = lnwallet.WitnessPubKey
+
+		case lnrpc.AddressType_NESTED_PUBKEY_HASH:
+			addrType = lnwallet.NestedWitnessPubKey
+
+		default:
+			return nil, fmt.Errorf("address type %v not supported "+
+				"for account %v", in.Type, in.Account)
+		}
+
+		addr, err := r.server.cc.wallet.NewAccountAddress(
+			in.Account, addrType,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcsLog.Debugf("[newaddress] type=%v account=%v addr=%v",
+			in.Type, in.Account, addr.String())
+		return &lnrpc.NewAddressResponse{Address: addr.String()}, nil
+	}
+
 	// Translate the gRPC proto address type to the wallet controller's
 	// available address types.
 	var (
@@ -1325,9 +1668,12 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	}
 
 	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
+	remoteFundingAmt := btcutil.Amount(in.RemoteFundingAmount)
 	remoteInitialBalance := btcutil.Amount(in.PushSat)
 	minHtlc := lnwire.MilliSatoshi(in.MinHtlcMsat)
 	remoteCsvDelay := uint16(in.RemoteCsvDelay)
+	dustLimit := btcutil.Amount(in.DustLimitSat)
+	remoteChanReserve := btcutil.Amount(in.RemoteChanReserveSat)
 
 	// Ensure that the initial balance of the remote party (if pushing
 	// satoshis) does not exceed the amount the local party has requested
@@ -1339,10 +1685,19 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 			"state must be below the local funding amount")
 	}
 
+	// If the remote peer is requested to contribute to the channel, its
+	// funds already make up its initial balance.
+	if remoteFundingAmt < 0 ||
+		(remoteFundingAmt != 0 && remoteInitialBalance != 0) {
+
+		return fmt.Errorf("remote funding amount must be positive, " +
+			"and can't be combined with pushing satoshis")
+	}
+
 	// Ensure that the user doesn't exceed the current soft-limit for
 	// channel size. If the funding amount is above the soft-limit, then
 	// we'll reject the request.
-	if localFundingAmt > maxFundingAmount {
+	if localFundingAmt+remoteFundingAmt > maxFundingAmount {
 		return fmt.Errorf("funding amount is too large, the max "+
 			"channel size is: %v", maxFundingAmount)
 	}
@@ -1355,6 +1710,13 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 			"size is: %v SAT", int64(minChanFundingSize))
 	}
 
+	// Any custom constraints are checked against the channel capacity
+	// by the funding manager, but they can't be negative.
+	if dustLimit < 0 || remoteChanReserve < 0 {
+		return errors.New("dust limit and remote channel reserve " +
+			"must not be negative")
+	}
+
 	// Then, we'll extract the minimum number of confirmations that each
 	// output we use to fund the channel's funding transaction should
 	// satisfy.
@@ -1363,6 +1725,11 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 		return err
 	}
 
+	strategy, err := parseCoinSelectionStrategy(in.CoinSelectionStrategy)
+	if err != nil {
+		return err
+	}
+
 	var (
 		nodePubKey      *btcec.PublicKey
 		nodePubKeyBytes []byte
@@ -1410,15 +1777,22 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	// open a new channel. A stream is returned in place, this stream will
 	// be used to consume updates of the state of the pending channel.
 	req := &openChanReq{
-		targetPubkey:    nodePubKey,
-		chainHash:       *activeNetParams.GenesisHash,
-		localFundingAmt: localFundingAmt,
-		pushAmt:         lnwire.NewMSatFromSatoshis(remoteInitialBalance),
-		minHtlc:         minHtlc,
-		fundingFeePerKw: feeRate,
-		private:         in.Private,
-		remoteCsvDelay:  remoteCsvDelay,
-		minConfs:        minConfs,
+		targetPubkey:     nodePubKey,
+		chainHash:        *activeNetParams.GenesisHash,
+		localFundingAmt:  localFundingAmt,
+		remoteFundingAmt: remoteFundingAmt,
+		pushAmt:          lnwire.NewMSatFromSatoshis(remoteInitialBalance),
+		minHtlc:          minHtlc,
+		fundingFeePerKw:  feeRate,
+		private:          in.Private,
+		remoteCsvDelay:   remoteCsvDelay,
+		minConfs:         minConfs,
+		psbtFunding:      in.PsbtFunding,
+
+		coinSelectionStrategy: strategy,
+		dustLimit:             dustLimit,
+		remoteChanReserve:     remoteChanReserve,
+		zeroConf:              in.ZeroConf,
 	}
 
 	updateChan, errChan := r.server.OpenChannel(req)
@@ -1465,79 +1839,343 @@ out:
 	return nil
 }
 
-// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
-// call is meant to be consumed by clients to the REST proxy. As with all other
-// sync calls, all byte slices are instead to be populated as hex encoded
-// strings.
-func (r *rpcServer) OpenChannelSync(ctx context.Context,
-	in *lnrpc.OpenChannelRequest) (*lnrpc.ChannelPoint, error) {
-
-	rpcsLog.Tracef("[openchannel] request to NodeKey(%v) "+
-		"allocation(us=%v, them=%v)", in.NodePubkeyString,
-		in.LocalFundingAmount, in.PushSat)
+// DualFundAcceptor dispatches a bi-directional streaming RPC in which requests
+// of peers for us to contribute funds to the channels they open are sent to
+// the client, which responds with whether each request is accepted.
+func (r *rpcServer) DualFundAcceptor(
+	stream lnrpc.Lightning_DualFundAcceptorServer) error {
 
-	// We don't allow new channels to be open while the server is still
-	// syncing, as otherwise we may not be able to obtain the relevant
-	// notifications.
-	if !r.server.Started() {
-		return nil, fmt.Errorf("chain backend is still syncing, server " +
-			"not active yet")
+	if !cfg.Protocol.DualFunding {
+		return fmt.Errorf("dual funding is disabled, set " +
+			"protocol.dualfunding to enable it")
 	}
 
-	// Creation of channels before the wallet syncs up is currently
-	// disallowed.
-	isSynced, _, err := r.server.cc.wallet.IsSynced()
-	if err != nil {
-		return nil, err
-	}
-	if !isSynced {
-		return nil, errors.New("channels cannot be created before the " +
-			"wallet is fully synced")
-	}
+	// We'll read the responses of the client in a separate goroutine, so
+	// we're able to hand it new requests while waiting for them.
+	responses := make(chan *lnrpc.DualFundAcceptResponse)
+	errChan := make(chan error, 1)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				errChan <- err
+				return
+			}
 
-	// Decode the provided target node's public key, parsing it into a pub
-	// key object. For all sync call, byte slices are expected to be
-	// encoded as hex strings.
-	keyBytes, err := hex.DecodeString(in.NodePubkeyString)
+			select {
+			case responses <- resp:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	// Any requests the client hasn't responded to by the time it goes
+	// away are declined.
+	pending := make(map[[32]byte]*dualFundRequest)
+	defer func() {
+		for _, req := range pending {
+			req.resp <- false
+		}
+	}()
+
+	for {
+		select {
+		case req := <-r.server.dualFundAcceptor.requests:
+			nodePubKey := req.nodePubKey.SerializeCompressed()
+			err := stream.Send(&lnrpc.DualFundAcceptRequest{
+				NodePubkey:          nodePubKey,
+				PendingChanId:       req.pendingChanID[:],
+				Capacity:            int64(req.capacity),
+				LocalFundingAmount:  int64(req.localFundingAmt),
+				RemoteFundingAmount: int64(req.remoteFundingAmt),
+			})
+			if err != nil {
+				req.resp <- false
+				return err
+			}
+
+			pending[req.pendingChanID] = req
+
+		case resp := <-responses:
+			var pendingChanID [32]byte
+			copy(pendingChanID[:], resp.PendingChanId)
+
+			req, ok := pending[pendingChanID]
+			if !ok {
+				rpcsLog.Warnf("Received response for unknown "+
+					"dual fund request %x", pendingChanID[:])
+				continue
+			}
+			delete(pending, pendingChanID)
+
+			req.resp <- resp.Accept
+
+		case err := <-errChan:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// ChannelAcceptor dispatches a bi-directional streaming RPC in which requests
+// of peers to open a channel with us are sent to the client, which responds
+// with whether each channel is accepted, and any constraints we require of
+// the peer.
+func (r *rpcServer) ChannelAcceptor(
+	stream lnrpc.Lightning_ChannelAcceptorServer) error {
+
+	// As long as the client is connected, channels are no longer accepted
+	// without its consent.
+	r.server.channelAcceptor.addClient()
+	defer r.server.channelAcceptor.removeClient()
+
+	// We'll read the responses of the client in a separate goroutine, so
+	// we're able to hand it new requests while waiting for them.
+	responses := make(chan *lnrpc.ChannelAcceptResponse)
+	errChan := make(chan error, 1)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			select {
+			case responses <- resp:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	// Any requests the client hasn't responded to by the time it goes
+	// away are rejected.
+	pending := make(map[[32]byte]*channelAcceptRequest)
+	defer func() {
+		for _, req := range pending {
+			req.resp <- &channelAcceptResponse{
+				reason: "channel acceptor unavailable",
+			}
+		}
+	}()
+
+	for {
+		select {
+		case req := <-r.server.channelAcceptor.requests:
+			err := stream.Send(marshallChannelAcceptRequest(req))
+			if err != nil {
+				req.resp <- &channelAcceptResponse{
+					reason: "channel acceptor unavailable",
+				}
+				return err
+			}
+
+			pending[req.openChan.PendingChannelID] = req
+
+		case resp := <-responses:
+			var pendingChanID [32]byte
+			copy(pendingChanID[:], resp.PendingChanId)
+
+			req, ok := pending[pendingChanID]
+			if !ok {
+				rpcsLog.Warnf("Received response for unknown "+
+					"channel accept request %x",
+					pendingChanID[:])
+				continue
+			}
+			delete(pending, pendingChanID)
+
+			req.resp <- unmarshallChannelAcceptResponse(resp)
+
+		case err := <-errChan:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// marshallChannelAcceptRequest converts a request of a peer to open a channel
+// into its RPC format.
+func marshallChannelAcceptRequest(
+	req *channelAcceptRequest) *lnrpc.ChannelAcceptRequest {
+
+	openChan := req.openChan
+
+	commitType := lnrpc.CommitmentType_LEGACY
+	if req.anchors {
+		commitType = lnrpc.CommitmentType_ANCHORS
+	}
+
+	return &lnrpc.ChannelAcceptRequest{
+		NodePubkey:       req.nodePubKey.SerializeCompressed(),
+		ChainHash:        openChan.ChainHash[:],
+		PendingChanId:    openChan.PendingChannelID[:],
+		FundingAmt:       int64(openChan.FundingAmount),
+		PushAmt:          uint64(openChan.PushAmount),
+		DustLimit:        uint64(openChan.DustLimit),
+		MaxValueInFlight: uint64(openChan.MaxValueInFlight),
+		ChannelReserve:   uint64(openChan.ChannelReserve),
+		MinHtlc:          uint64(openChan.HtlcMinimum),
+		FeePerKw:         uint64(openChan.FeePerKiloWeight),
+		CsvDelay:         uint32(openChan.CsvDelay),
+		MaxAcceptedHtlcs: uint32(openChan.MaxAcceptedHTLCs),
+		ChannelFlags:     uint32(openChan.ChannelFlags),
+		CommitmentType:   commitType,
+	}
+}
+
+// unmarshallChannelAcceptResponse converts the response of a ChannelAcceptor
+// client into the decision on a channel. Constraints that don't fit the wire
+// format can't be required of the peer, so the channel is rejected instead.
+func unmarshallChannelAcceptResponse(
+	resp *lnrpc.ChannelAcceptResponse) *channelAcceptResponse {
+
+	if resp.CsvDelay > math.MaxUint16 ||
+		resp.MaxHtlcCount > math.MaxUint16 ||
+		resp.MinAcceptDepth > math.MaxUint16 {
+
+		rpcsLog.Errorf("Invalid channel acceptor constraints for %x",
+			resp.PendingChanId)
+
+		return &channelAcceptResponse{
+			reason: "invalid channel constraints",
+		}
+	}
+
+	return &channelAcceptResponse{
+		accept:            resp.Accept,
+		reason:            resp.Error,
+		remoteCsvDelay:    uint16(resp.CsvDelay),
+		remoteChanReserve: btcutil.Amount(resp.ReserveSat),
+		remoteMaxValue:    lnwire.MilliSatoshi(resp.InFlightMaxMsat),
+		remoteMaxHtlcs:    uint16(resp.MaxHtlcCount),
+		remoteMinHtlc:     lnwire.MilliSatoshi(resp.MinHtlcIn),
+		minAcceptDepth:    uint16(resp.MinAcceptDepth),
+	}
+}
+
+// FundingStateStep advances the funding workflow of a channel opened with
+// psbt_funding set, by handing lnd the final funding transaction, which was
+// funded and signed based on the PSBT funding template.
+func (r *rpcServer) FundingStateStep(ctx context.Context,
+	in *lnrpc.FundingStateStepRequest) (*lnrpc.FundingStateStepResponse,
+	error) {
+
+	var pendingChanID [32]byte
+	if len(in.PendingChanId) != len(pendingChanID) {
+		return nil, fmt.Errorf("pending channel ID must be %v bytes",
+			len(pendingChanID))
+	}
+	copy(pendingChanID[:], in.PendingChanId)
+
+	fundingTx := &wire.MsgTx{}
+	err := fundingTx.Deserialize(bytes.NewReader(in.SignedTx))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to decode funding tx: %v", err)
 	}
-	nodepubKey, err := btcec.ParsePubKey(keyBytes, btcec.S256())
+
+	rpcsLog.Debugf("[fundingstatestep] funding tx for pendingID(%x): %v",
+		pendingChanID[:], newLogClosure(func() string {
+			return spew.Sdump(fundingTx)
+		}))
+
+	err = r.server.fundingMgr.ProcessPsbtFinalize(pendingChanID, fundingTx)
 	if err != nil {
 		return nil, err
 	}
 
-	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
-	remoteInitialBalance := btcutil.Amount(in.PushSat)
-	minHtlc := lnwire.MilliSatoshi(in.MinHtlcMsat)
-	remoteCsvDelay := uint16(in.RemoteCsvDelay)
+	return &lnrpc.FundingStateStepResponse{}, nil
+}
 
-	// Ensure that the initial balance of the remote party (if pushing
-	// satoshis) does not exceed the amount the local party has requested
-	// for funding.
-	if remoteInitialBalance >= localFundingAmt {
-		return nil, fmt.Errorf("amount pushed to remote peer for " +
-			"initial state must be below the local funding amount")
+// BatchOpenChannel opens a channel to each of the requested nodes, funding all
+// of them with a single transaction, which is only broadcast once every
+// channel is pending.
+func (r *rpcServer) BatchOpenChannel(ctx context.Context,
+	in *lnrpc.BatchOpenChannelRequest) (*lnrpc.BatchOpenChannelResponse,
+	error) {
+
+	if !r.server.Started() {
+		return nil, fmt.Errorf("chain backend is still syncing, " +
+			"server not active yet")
 	}
 
-	// Restrict the size of the channel we'll actually open. At a later
-	// level, we'll ensure that the output we create after accounting for
-	// fees that a dust output isn't created.
-	if localFundingAmt < minChanFundingSize {
-		return nil, fmt.Errorf("channel is too small, the minimum channel "+
-			"size is: %v SAT", int64(minChanFundingSize))
+	if len(in.Channels) == 0 {
+		return nil, errors.New("no channels to open")
 	}
 
-	// Then, we'll extract the minimum number of confirmations that each
-	// output we use to fund the channel's funding transaction should
-	// satisfy.
-	minConfs, err := extractOpenChannelMinConfs(in)
+	reqs := make([]*openChanReq, len(in.Channels))
+	for i, channel := range in.Channels {
+		localFundingAmt := btcutil.Amount(channel.LocalFundingAmount)
+		remoteInitialBalance := btcutil.Amount(channel.PushSat)
+		pushAmt := lnwire.NewMSatFromSatoshis(remoteInitialBalance)
+		minHtlc := lnwire.MilliSatoshi(channel.MinHtlcMsat)
+
+		// Each channel of the batch is subject to the same limits as
+		// a channel opened on its own.
+		switch {
+		case remoteInitialBalance >= localFundingAmt:
+			return nil, fmt.Errorf("amount pushed to remote peer " +
+				"for initial state must be below the local " +
+				"funding amount")
+
+		case localFundingAmt > maxFundingAmount:
+			return nil, fmt.Errorf("funding amount is too large, "+
+				"the max channel size is: %v", maxFundingAmount)
+
+		case localFundingAmt < minChanFundingSize:
+			return nil, fmt.Errorf("channel is too small, the "+
+				"minimum channel size is: %v SAT",
+				int64(minChanFundingSize))
+		}
+
+		nodePubKey, err := btcec.ParsePubKey(
+			channel.NodePubkey, btcec.S256(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if nodePubKey.IsEqual(r.server.identityPriv.PubKey()) {
+			return nil, fmt.Errorf("cannot open channel to self")
+		}
+
+		reqs[i] = &openChanReq{
+			targetPubkey:    nodePubKey,
+			chainHash:       *activeNetParams.GenesisHash,
+			localFundingAmt: localFundingAmt,
+			pushAmt:         pushAmt,
+			minHtlc:         minHtlc,
+			private:         channel.Private,
+			remoteCsvDelay:  uint16(channel.RemoteCsvDelay),
+		}
+	}
+
+	minConfs, err := extractOpenChannelMinConfs(&lnrpc.OpenChannelRequest{
+		MinConfs:         in.MinConfs,
+		SpendUnconfirmed: in.SpendUnconfirmed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	strategy, err := parseCoinSelectionStrategy(in.CoinSelectionStrategy)
 	if err != nil {
 		return nil, err
 	}
 
 	// Based on the passed fee related parameters, we'll determine an
-	// appropriate fee rate for the funding transaction.
+	// appropriate fee rate for the batch transaction.
 	satPerKw := lnwallet.SatPerKVByte(in.SatPerByte * 1000).FeePerKWeight()
 	feeRate, err := sweep.DetermineFeePerKw(
 		r.server.cc.feeEstimator, sweep.FeePreference{
@@ -1549,2753 +2187,4258 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 		return nil, err
 	}
 
-	rpcsLog.Tracef("[openchannel] target sat/kw for funding tx: %v",
-		int64(feeRate))
+	rpcsLog.Debugf("[batchopenchannel]: opening %v channels using fee "+
+		"of %v sat/kw for batch tx", len(reqs), int64(feeRate))
 
-	req := &openChanReq{
-		targetPubkey:    nodepubKey,
-		chainHash:       *activeNetParams.GenesisHash,
-		localFundingAmt: localFundingAmt,
-		pushAmt:         lnwire.NewMSatFromSatoshis(remoteInitialBalance),
-		minHtlc:         minHtlc,
-		fundingFeePerKw: feeRate,
-		private:         in.Private,
-		remoteCsvDelay:  remoteCsvDelay,
-		minConfs:        minConfs,
+	chanPoints, err := r.server.BatchOpenChannels(
+		reqs, feeRate, minConfs, strategy,
+	)
+	if err != nil {
+		rpcsLog.Errorf("unable to open channel batch: %v", err)
+		return nil, err
 	}
 
-	updateChan, errChan := r.server.OpenChannel(req)
-	select {
-	// If an error occurs them immediately return the error to the client.
-	case err := <-errChan:
-		rpcsLog.Errorf("unable to open channel to NodeKey(%x): %v",
-			nodepubKey, err)
-		return nil, err
+	resp := &lnrpc.BatchOpenChannelResponse{
+		PendingChannels: make([]*lnrpc.PendingUpdate, len(chanPoints)),
+	}
+	for i, chanPoint := range chanPoints {
+		resp.PendingChannels[i] = &lnrpc.PendingUpdate{
+			Txid:        chanPoint.Hash[:],
+			OutputIndex: chanPoint.Index,
+		}
+	}
 
-	// Otherwise, wait for the first channel update. The first update sent
-	// is when the funding transaction is broadcast to the network.
-	case fundingUpdate := <-updateChan:
-		rpcsLog.Tracef("[openchannel] sending update: %v",
-			fundingUpdate)
+	return resp, nil
+}
 
-		// Parse out the txid of the pending funding transaction. The
-		// sync client can use this to poll against the list of
-		// PendingChannels.
-		openUpdate := fundingUpdate.Update.(*lnrpc.OpenStatusUpdate_ChanPending)
-		chanUpdate := openUpdate.ChanPending
+// SpliceIn adds funds of the wallet to the capacity of an active channel. The
+// new funding outpoint of the channel is returned once the splice transaction
+// is published.
+func (r *rpcServer) SpliceIn(ctx context.Context,
+	in *lnrpc.SpliceInRequest) (*lnrpc.ChannelPoint, error) {
 
-		return &lnrpc.ChannelPoint{
-			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
-				FundingTxidBytes: chanUpdate.Txid,
-			},
-			OutputIndex: chanUpdate.OutputIndex,
-		}, nil
-	case <-r.quit:
-		return nil, nil
+	if !r.server.Started() {
+		return nil, fmt.Errorf("chain backend is still syncing, " +
+			"server not active yet")
 	}
-}
-
-// getChanPointFundingTxid returns the given channel point's funding txid in
-// raw bytes.
-func getChanPointFundingTxid(chanPoint *lnrpc.ChannelPoint) (*chainhash.Hash, error) {
-	var txid []byte
 
-	// A channel point's funding txid can be get/set as a byte slice or a
-	// string. In the case it is a string, decode it.
-	switch chanPoint.GetFundingTxid().(type) {
-	case *lnrpc.ChannelPoint_FundingTxidBytes:
-		txid = chanPoint.GetFundingTxidBytes()
-	case *lnrpc.ChannelPoint_FundingTxidStr:
-		s := chanPoint.GetFundingTxidStr()
-		h, err := chainhash.NewHashFromStr(s)
-		if err != nil {
-			return nil, err
-		}
+	chanPoint, err := parseSpliceChanPoint(in.ChannelPoint)
+	if err != nil {
+		return nil, err
+	}
 
-		txid = h[:]
+	amt := btcutil.Amount(in.Amount)
+	if amt <= 0 {
+		return nil, errors.New("splice amount must be positive")
 	}
 
-	return chainhash.NewHash(txid)
-}
+	minConfs, err := extractOpenChannelMinConfs(&lnrpc.OpenChannelRequest{
+		MinConfs:         in.MinConfs,
+		SpendUnconfirmed: in.SpendUnconfirmed,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-// CloseChannel attempts to close an active channel identified by its channel
-// point. The actions of this method can additionally be augmented to attempt
-// a force close after a timeout period in the case of an inactive peer.
-func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
-	updateStream lnrpc.Lightning_CloseChannelServer) error {
+	strategy, err := parseCoinSelectionStrategy(in.CoinSelectionStrategy)
+	if err != nil {
+		return nil, err
+	}
 
-	// If the user didn't specify a channel point, then we'll reject this
-	// request all together.
-	if in.GetChannelPoint() == nil {
-		return fmt.Errorf("must specify channel point in close channel")
+	feeRate, err := r.spliceFeeRate(in.TargetConf, in.SatPerByte)
+	if err != nil {
+		return nil, err
 	}
 
-	force := in.Force
-	index := in.ChannelPoint.OutputIndex
-	txid, err := getChanPointFundingTxid(in.GetChannelPoint())
+	rpcsLog.Debugf("[splicein]: splicing %v into ChannelPoint(%v) using "+
+		"fee of %v sat/kw", amt, chanPoint, int64(feeRate))
+
+	newChanPoint, err := r.server.SpliceChannel(&spliceReq{
+		chanPoint: *chanPoint,
+		spliceIn:  true,
+		amt:       amt,
+		feeRate:   feeRate,
+		minConfs:  minConfs,
+		strategy:  strategy,
+	})
 	if err != nil {
-		rpcsLog.Errorf("[closechannel] unable to get funding txid: %v", err)
-		return err
+		rpcsLog.Errorf("unable to splice into ChannelPoint(%v): %v",
+			chanPoint, err)
+		return nil, err
 	}
-	chanPoint := wire.NewOutPoint(txid, index)
 
-	rpcsLog.Tracef("[closechannel] request for ChannelPoint(%v), force=%v",
-		chanPoint, force)
+	return &lnrpc.ChannelPoint{
+		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: newChanPoint.Hash[:],
+		},
+		OutputIndex: newChanPoint.Index,
+	}, nil
+}
 
-	var (
-		updateChan chan interface{}
-		errChan    chan error
-	)
+// SpliceOut pays funds out of an active channel to an on-chain address. The
+// new funding outpoint of the channel is returned once the splice transaction
+// is published.
+func (r *rpcServer) SpliceOut(ctx context.Context,
+	in *lnrpc.SpliceOutRequest) (*lnrpc.ChannelPoint, error) {
 
-	// TODO(roasbeef): if force and peer online then don't force?
+	if !r.server.Started() {
+		return nil, fmt.Errorf("chain backend is still syncing, " +
+			"server not active yet")
+	}
 
-	// First, we'll fetch the channel as is, as we'll need to examine it
-	// regardless of if this is a force close or not.
-	channel, err := r.fetchActiveChannel(*chanPoint)
+	chanPoint, err := parseSpliceChanPoint(in.ChannelPoint)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// If a force closure was requested, then we'll handle all the details
-	// around the creation and broadcast of the unilateral closure
-	// transaction here rather than going to the switch as we don't require
-	// interaction from the peer.
-	if force {
-		_, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
-		if err != nil {
-			return err
-		}
-
-		// As we're force closing this channel, as a precaution, we'll
-		// ensure that the switch doesn't continue to see this channel
-		// as eligible for forwarding HTLC's. If the peer is online,
-		// then we'll also purge all of its indexes.
-		remotePub := &channel.StateSnapshot().RemoteIdentity
-		if peer, err := r.server.FindPeer(remotePub); err == nil {
-			// TODO(roasbeef): actually get the active channel
-			// instead too?
-			//  * so only need to grab from database
-			peer.WipeChannel(channel.ChannelPoint())
-		} else {
-			chanID := lnwire.NewChanIDFromOutPoint(channel.ChannelPoint())
-			r.server.htlcSwitch.RemoveLink(chanID)
-		}
+	amt := btcutil.Amount(in.Amount)
+	if amt <= 0 {
+		return nil, errors.New("splice amount must be positive")
+	}
 
-		// With the necessary indexes cleaned up, we'll now force close
-		// the channel.
-		chainArbitrator := r.server.chainArb
-		closingTx, err := chainArbitrator.ForceCloseContract(
-			*chanPoint,
+	// If no address is given, the funds are paid to a new address of the
+	// wallet.
+	var addr btcutil.Address
+	if in.Address != "" {
+		addr, err = btcutil.DecodeAddress(
+			in.Address, activeNetParams.Params,
 		)
 		if err != nil {
-			rpcsLog.Errorf("unable to force close transaction: %v", err)
-			return err
+			return nil, err
 		}
-
-		closingTxid := closingTx.TxHash()
-
-		// With the transaction broadcast, we send our first update to
-		// the client.
-		updateChan = make(chan interface{}, 2)
-		updateChan <- &pendingUpdate{
-			Txid: closingTxid[:],
+		if !addr.IsForNet(activeNetParams.Params) {
+			return nil, fmt.Errorf("address: %v is not valid for "+
+				"this network: %v", addr.String(),
+				activeNetParams.Params.Name)
 		}
-
-		errChan = make(chan error, 1)
-		notifier := r.server.cc.chainNotifier
-		go waitForChanToClose(uint32(bestHeight), notifier, errChan, chanPoint,
-			&closingTxid, closingTx.TxOut[0].PkScript, func() {
-				// Respond to the local subsystem which
-				// requested the channel closure.
-				updateChan <- &channelCloseUpdate{
-					ClosingTxid: closingTxid[:],
-					Success:     true,
-				}
-			})
 	} else {
-		// If the link is not known by the switch, we cannot gracefully close
-		// the channel.
-		channelID := lnwire.NewChanIDFromOutPoint(chanPoint)
-		if _, err := r.server.htlcSwitch.GetLink(channelID); err != nil {
-			rpcsLog.Debugf("Trying to non-force close offline channel with "+
-				"chan_point=%v", chanPoint)
-			return fmt.Errorf("unable to gracefully close channel while peer "+
-				"is offline (try force closing it instead): %v", err)
-		}
-
-		// Based on the passed fee related parameters, we'll determine
-		// an appropriate fee rate for the cooperative closure
-		// transaction.
-		satPerKw := lnwallet.SatPerKVByte(
-			in.SatPerByte * 1000,
-		).FeePerKWeight()
-		feeRate, err := sweep.DetermineFeePerKw(
-			r.server.cc.feeEstimator, sweep.FeePreference{
-				ConfTarget: uint32(in.TargetConf),
-				FeeRate:    satPerKw,
-			},
+		addr, err = r.server.cc.wallet.NewAddress(
+			lnwallet.WitnessPubKey, false,
 		)
 		if err != nil {
-			return err
-		}
-
-		rpcsLog.Debugf("Target sat/kw for closing transaction: %v",
-			int64(feeRate))
-
-		// Before we attempt the cooperative channel closure, we'll
-		// examine the channel to ensure that it doesn't have a
-		// lingering HTLC.
-		if len(channel.ActiveHtlcs()) != 0 {
-			return fmt.Errorf("cannot co-op close channel " +
-				"with active htlcs")
+			return nil, err
 		}
+	}
 
-		// Otherwise, the caller has requested a regular interactive
-		// cooperative channel closure. So we'll forward the request to
-		// the htlc switch which will handle the negotiation and
-		// broadcast details.
-		updateChan, errChan = r.server.htlcSwitch.CloseLink(
-			chanPoint, htlcswitch.CloseRegular, feeRate,
-		)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
 	}
-out:
-	for {
-		select {
-		case err := <-errChan:
-			rpcsLog.Errorf("[closechannel] unable to close "+
-				"ChannelPoint(%v): %v", chanPoint, err)
-			return err
-		case closingUpdate := <-updateChan:
-			rpcClosingUpdate, err := createRPCCloseUpdate(
-				closingUpdate,
-			)
-			if err != nil {
-				return err
-			}
 
-			rpcsLog.Tracef("[closechannel] sending update: %v",
-				rpcClosingUpdate)
+	feeRate, err := r.spliceFeeRate(in.TargetConf, in.SatPerByte)
+	if err != nil {
+		return nil, err
+	}
 
-			if err := updateStream.Send(rpcClosingUpdate); err != nil {
-				return err
-			}
+	rpcsLog.Debugf("[spliceout]: splicing %v out of ChannelPoint(%v) to "+
+		"%v using fee of %v sat/kw", amt, chanPoint, addr,
+		int64(feeRate))
 
-			// If a final channel closing updates is being sent,
-			// then we can break out of our dispatch loop as we no
-			// longer need to process any further updates.
-			switch closeUpdate := closingUpdate.(type) {
-			case *channelCloseUpdate:
-				h, _ := chainhash.NewHash(closeUpdate.ClosingTxid)
-				rpcsLog.Infof("[closechannel] close completed: "+
-					"txid(%v)", h)
-				break out
-			}
-		case <-r.quit:
-			return nil
-		}
+	newChanPoint, err := r.server.SpliceChannel(&spliceReq{
+		chanPoint: *chanPoint,
+		amt:       amt,
+		pkScript:  pkScript,
+		feeRate:   feeRate,
+	})
+	if err != nil {
+		rpcsLog.Errorf("unable to splice out of ChannelPoint(%v): %v",
+			chanPoint, err)
+		return nil, err
 	}
 
-	return nil
+	return &lnrpc.ChannelPoint{
+		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: newChanPoint.Hash[:],
+		},
+		OutputIndex: newChanPoint.Index,
+	}, nil
 }
 
-func createRPCCloseUpdate(update interface{}) (
-	*lnrpc.CloseStatusUpdate, error) {
+// parseSpliceChanPoint returns the funding outpoint of the channel to splice.
+func parseSpliceChanPoint(
+	rpcChanPoint *lnrpc.ChannelPoint) (*wire.OutPoint, error) {
 
-	switch u := update.(type) {
-	case *channelCloseUpdate:
-		return &lnrpc.CloseStatusUpdate{
-			Update: &lnrpc.CloseStatusUpdate_ChanClose{
-				ChanClose: &lnrpc.ChannelCloseUpdate{
-					ClosingTxid: u.ClosingTxid,
-				},
-			},
-		}, nil
-	case *pendingUpdate:
-		return &lnrpc.CloseStatusUpdate{
-			Update: &lnrpc.CloseStatusUpdate_ClosePending{
-				ClosePending: &lnrpc.PendingUpdate{
-					Txid:        u.Txid,
-					OutputIndex: u.OutputIndex,
-				},
-			},
-		}, nil
+	if rpcChanPoint == nil {
+		return nil, errors.New("channel point must be specified")
 	}
 
-	return nil, errors.New("unknown close status update")
+	txid, err := getChanPointFundingTxid(rpcChanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return wire.NewOutPoint(txid, rpcChanPoint.OutputIndex), nil
 }
 
-// AbandonChannel removes all channel state from the database except for a
-// close summary. This method can be used to get rid of permanently unusable
-// channels due to bugs fixed in newer versions of lnd.
-func (r *rpcServer) AbandonChannel(ctx context.Context,
-	in *lnrpc.AbandonChannelRequest) (*lnrpc.AbandonChannelResponse, error) {
+// spliceFeeRate determines the fee rate of a splice transaction based on the
+// passed fee related parameters.
+func (r *rpcServer) spliceFeeRate(targetConf int32,
+	satPerByte int64) (lnwallet.SatPerKWeight, error) {
 
-	// If this isn't the dev build, then we won't allow the RPC to be
-	// executed, as it's an advanced feature and won't be activated in
-	// regular production/release builds.
-	if !build.IsDevBuild() {
-		return nil, fmt.Errorf("AbandonChannel RPC call only " +
-			"available in dev builds")
+	satPerKw := lnwallet.SatPerKVByte(satPerByte * 1000).FeePerKWeight()
+	return sweep.DetermineFeePerKw(
+		r.server.cc.feeEstimator, sweep.FeePreference{
+			ConfTarget: uint32(targetConf),
+			FeeRate:    satPerKw,
+		},
+	)
+}
+
+// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
+// call is meant to be consumed by clients to the REST proxy. As with all other
+// sync calls, all byte slices are instead to be populated as hex encoded
+// strings.
+func (r *rpcServer) OpenChannelSync(ctx context.Context,
+	in *lnrpc.OpenChannelRequest) (*lnrpc.ChannelPoint, error) {
+
+	rpcsLog.Tracef("[openchannel] request to NodeKey(%v) "+
+		"allocation(us=%v, them=%v)", in.NodePubkeyString,
+		in.LocalFundingAmount, in.PushSat)
+
+	// We don't allow new channels to be open while the server is still
+	// syncing, as otherwise we may not be able to obtain the relevant
+	// notifications.
+	if !r.server.Started() {
+		return nil, fmt.Errorf("chain backend is still syncing, server " +
+			"not active yet")
 	}
 
-	// We'll parse out the arguments to we can obtain the chanPoint of the
-	// target channel.
-	txid, err := getChanPointFundingTxid(in.GetChannelPoint())
+	// Creation of channels before the wallet syncs up is currently
+	// disallowed.
+	isSynced, _, err := r.server.cc.wallet.IsSynced()
 	if err != nil {
 		return nil, err
 	}
-	index := in.ChannelPoint.OutputIndex
-	chanPoint := wire.NewOutPoint(txid, index)
+	if !isSynced {
+		return nil, errors.New("channels cannot be created before the " +
+			"wallet is fully synced")
+	}
 
-	// With the chanPoint constructed, we'll attempt to find the target
-	// channel in the database. If we can't find the channel, then we'll
-	// return the error back to the caller.
-	dbChan, err := r.server.chanDB.FetchChannel(*chanPoint)
+	// Decode the provided target node's public key, parsing it into a pub
+	// key object. For all sync call, byte slices are expected to be
+	// encoded as hex strings.
+	keyBytes, err := hex.DecodeString(in.NodePubkeyString)
 	if err != nil {
 		return nil, err
 	}
-
-	// Now that we've found the channel, we'll populate a close summary for
-	// the channel, so we can store as much information for this abounded
-	// channel as possible. We also ensure that we set Pending to false, to
-	// indicate that this channel has been "fully" closed.
-	_, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
+	nodepubKey, err := btcec.ParsePubKey(keyBytes, btcec.S256())
 	if err != nil {
 		return nil, err
 	}
-	summary := &channeldb.ChannelCloseSummary{
-		CloseType:               channeldb.Abandoned,
-		ChanPoint:               *chanPoint,
-		ChainHash:               dbChan.ChainHash,
-		CloseHeight:             uint32(bestHeight),
-		RemotePub:               dbChan.IdentityPub,
-		Capacity:                dbChan.Capacity,
-		SettledBalance:          dbChan.LocalCommitment.LocalBalance.ToSatoshis(),
-		ShortChanID:             dbChan.ShortChanID(),
-		RemoteCurrentRevocation: dbChan.RemoteCurrentRevocation,
-		RemoteNextRevocation:    dbChan.RemoteNextRevocation,
-		LocalChanConfig:         dbChan.LocalChanCfg,
+
+	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
+	remoteFundingAmt := btcutil.Amount(in.RemoteFundingAmount)
+	remoteInitialBalance := btcutil.Amount(in.PushSat)
+	minHtlc := lnwire.MilliSatoshi(in.MinHtlcMsat)
+	remoteCsvDelay := uint16(in.RemoteCsvDelay)
+	dustLimit := btcutil.Amount(in.DustLimitSat)
+	remoteChanReserve := btcutil.Amount(in.RemoteChanReserveSat)
+
+	// Ensure that the initial balance of the remote party (if pushing
+	// satoshis) does not exceed the amount the local party has requested
+	// for funding.
+	if remoteInitialBalance >= localFundingAmt {
+		return nil, fmt.Errorf("amount pushed to remote peer for " +
+			"initial state must be below the local funding amount")
 	}
 
-	// Finally, we'll close the channel in the DB, and return back to the
-	// caller.
-	err = dbChan.CloseChannel(summary)
-	if err != nil {
-		return nil, err
+	// If the remote peer is requested to contribute to the channel, its
+	// funds already make up its initial balance.
+	if remoteFundingAmt < 0 ||
+		(remoteFundingAmt != 0 && remoteInitialBalance != 0) {
+
+		return nil, fmt.Errorf("remote funding amount must be " +
+			"positive, and can't be combined with pushing satoshis")
 	}
 
-	return &lnrpc.AbandonChannelResponse{}, nil
-}
+	// As the caller has to fund the channel based on an update of the
+	// funding workflow, external funding requires the streaming call.
+	if in.PsbtFunding {
+		return nil, errors.New("psbt funding is only supported by " +
+			"the OpenChannel call")
+	}
 
-// fetchActiveChannel attempts to locate a channel identified by its channel
-// point from the database's set of all currently opened channels and
-// return it as a fully populated state machine
-func (r *rpcServer) fetchActiveChannel(chanPoint wire.OutPoint) (
-	*lnwallet.LightningChannel, error) {
-
-	dbChan, err := r.server.chanDB.FetchChannel(chanPoint)
-	if err != nil {
-		return nil, err
+	// Ensure that the user doesn't exceed the current soft-limit for
+	// channel size.
+	if localFundingAmt+remoteFundingAmt > maxFundingAmount {
+		return nil, fmt.Errorf("funding amount is too large, the max "+
+			"channel size is: %v", maxFundingAmount)
 	}
 
-	// If the channel is successfully fetched from the database,
-	// we create a fully populated channel state machine which
-	// uses the db channel as backing storage.
-	return lnwallet.NewLightningChannel(
-		r.server.cc.wallet.Cfg.Signer, nil, dbChan, nil,
-	)
-}
-
-// GetInfo returns general information concerning the lightning node including
-// its identity pubkey, alias, the chains it is connected to, and information
-// concerning the number of open+pending channels.
-func (r *rpcServer) GetInfo(ctx context.Context,
-	in *lnrpc.GetInfoRequest) (*lnrpc.GetInfoResponse, error) {
-
-	serverPeers := r.server.Peers()
-
-	openChannels, err := r.server.chanDB.FetchAllOpenChannels()
-	if err != nil {
-		return nil, err
+	// Restrict the size of the channel we'll actually open. At a later
+	// level, we'll ensure that the output we create after accounting for
+	// fees that a dust output isn't created.
+	if localFundingAmt < minChanFundingSize {
+		return nil, fmt.Errorf("channel is too small, the minimum channel "+
+			"size is: %v SAT", int64(minChanFundingSize))
 	}
 
-	var activeChannels uint32
-	for _, channel := range openChannels {
-		chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
-		if r.server.htlcSwitch.HasActiveLink(chanID) {
-			activeChannels++
-		}
+	// Any custom constraints are checked against the channel capacity
+	// by the funding manager, but they can't be negative.
+	if dustLimit < 0 || remoteChanReserve < 0 {
+		return nil, errors.New("dust limit and remote channel reserve " +
+			"must not be negative")
 	}
 
-	inactiveChannels := uint32(len(openChannels)) - activeChannels
-
-	pendingChannels, err := r.server.chanDB.FetchPendingChannels()
+	// Then, we'll extract the minimum number of confirmations that each
+	// output we use to fund the channel's funding transaction should
+	// satisfy.
+	minConfs, err := extractOpenChannelMinConfs(in)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get retrieve pending "+
-			"channels: %v", err)
+		return nil, err
 	}
-	nPendingChannels := uint32(len(pendingChannels))
-
-	idPub := r.server.identityPriv.PubKey().SerializeCompressed()
-	encodedIDPub := hex.EncodeToString(idPub)
 
-	bestHash, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
+	strategy, err := parseCoinSelectionStrategy(in.CoinSelectionStrategy)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get best block info: %v", err)
+		return nil, err
 	}
 
-	isSynced, bestHeaderTimestamp, err := r.server.cc.wallet.IsSynced()
+	// Based on the passed fee related parameters, we'll determine an
+	// appropriate fee rate for the funding transaction.
+	satPerKw := lnwallet.SatPerKVByte(in.SatPerByte * 1000).FeePerKWeight()
+	feeRate, err := sweep.DetermineFeePerKw(
+		r.server.cc.feeEstimator, sweep.FeePreference{
+			ConfTarget: uint32(in.TargetConf),
+			FeeRate:    satPerKw,
+		},
+	)
 	if err != nil {
-		return nil, fmt.Errorf("unable to sync PoV of the wallet "+
-			"with current best block in the main chain: %v", err)
+		return nil, err
 	}
 
-	network := normalizeNetwork(activeNetParams.Name)
-	activeChains := make([]*lnrpc.Chain, registeredChains.NumActiveChains())
-	for i, chain := range registeredChains.ActiveChains() {
-		activeChains[i] = &lnrpc.Chain{
-			Chain:   chain.String(),
-			Network: network,
-		}
-
-	}
+	rpcsLog.Tracef("[openchannel] target sat/kw for funding tx: %v",
+		int64(feeRate))
 
-	// Check if external IP addresses were provided to lnd and use them
-	// to set the URIs.
-	nodeAnn, err := r.server.genNodeAnnouncement(false)
-	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve current fully signed "+
-			"node announcement: %v", err)
-	}
-	addrs := nodeAnn.Addresses
-	uris := make([]string, len(addrs))
-	for i, addr := range addrs {
-		uris[i] = fmt.Sprintf("%s@%s", encodedIDPub, addr.String())
+	req := &openChanReq{
+		targetPubkey:     nodepubKey,
+		chainHash:        *activeNetParams.GenesisHash,
+		localFundingAmt:  localFundingAmt,
+		remoteFundingAmt: remoteFundingAmt,
+		pushAmt:          lnwire.NewMSatFromSatoshis(remoteInitialBalance),
+		minHtlc:          minHtlc,
+		fundingFeePerKw:  feeRate,
+		private:          in.Private,
+		remoteCsvDelay:   remoteCsvDelay,
+		minConfs:         minConfs,
+
+		coinSelectionStrategy: strategy,
+		dustLimit:             dustLimit,
+		remoteChanReserve:     remoteChanReserve,
+		zeroConf:              in.ZeroConf,
 	}
 
-	// TODO(roasbeef): add synced height n stuff
-	return &lnrpc.GetInfoResponse{
-		IdentityPubkey:      encodedIDPub,
-		NumPendingChannels:  nPendingChannels,
-		NumActiveChannels:   activeChannels,
-		NumInactiveChannels: inactiveChannels,
-		NumPeers:            uint32(len(serverPeers)),
-		BlockHeight:         uint32(bestHeight),
-		BlockHash:           bestHash.String(),
-		SyncedToChain:       isSynced,
-		Testnet:             isTestnet(&activeNetParams),
-		Chains:              activeChains,
-		Uris:                uris,
-		Alias:               nodeAnn.Alias.String(),
-		BestHeaderTimestamp: int64(bestHeaderTimestamp),
-		Version:             build.Version(),
-	}, nil
-}
+	updateChan, errChan := r.server.OpenChannel(req)
+	select {
+	// If an error occurs them immediately return the error to the client.
+	case err := <-errChan:
+		rpcsLog.Errorf("unable to open channel to NodeKey(%x): %v",
+			nodepubKey, err)
+		return nil, err
 
-// ListPeers returns a verbose listing of all currently active peers.
-func (r *rpcServer) ListPeers(ctx context.Context,
-	in *lnrpc.ListPeersRequest) (*lnrpc.ListPeersResponse, error) {
+	// Otherwise, wait for the first channel update. The first update sent
+	// is when the funding transaction is broadcast to the network.
+	case fundingUpdate := <-updateChan:
+		rpcsLog.Tracef("[openchannel] sending update: %v",
+			fundingUpdate)
 
-	rpcsLog.Tracef("[listpeers] request")
+		// Parse out the txid of the pending funding transaction. The
+		// sync client can use this to poll against the list of
+		// PendingChannels.
+		openUpdate := fundingUpdate.Update.(*lnrpc.OpenStatusUpdate_ChanPending)
+		chanUpdate := openUpdate.ChanPending
 
-	serverPeers := r.server.Peers()
-	resp := &lnrpc.ListPeersResponse{
-		Peers: make([]*lnrpc.Peer, 0, len(serverPeers)),
+		return &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: chanUpdate.Txid,
+			},
+			OutputIndex: chanUpdate.OutputIndex,
+		}, nil
+	case <-r.quit:
+		return nil, nil
 	}
+}
 
-	for _, serverPeer := range serverPeers {
-		var (
-			satSent int64
-			satRecv int64
-		)
-
-		// In order to display the total number of satoshis of outbound
-		// (sent) and inbound (recv'd) satoshis that have been
-		// transported through this peer, we'll sum up the sent/recv'd
-		// values for each of the active channels we have with the
-		// peer.
-		chans := serverPeer.ChannelSnapshots()
-		for _, c := range chans {
-			satSent += int64(c.TotalMSatSent.ToSatoshis())
-			satRecv += int64(c.TotalMSatReceived.ToSatoshis())
-		}
-
-		nodePub := serverPeer.PubKey()
-
-		// Retrieve the peer's sync type. If we don't currently have a
-		// syncer for the peer, then we'll default to a passive sync.
-		// This can happen if the RPC is called while a peer is
-		// initializing.
-		syncer, ok := r.server.authGossiper.SyncManager().GossipSyncer(
-			nodePub,
-		)
-
-		var lnrpcSyncType lnrpc.Peer_SyncType
-		if !ok {
-			rpcsLog.Warnf("Gossip syncer for peer=%x not found",
-				nodePub)
-			lnrpcSyncType = lnrpc.Peer_UNKNOWN_SYNC
-		} else {
-			syncType := syncer.SyncType()
-			switch syncType {
-			case discovery.ActiveSync:
-				lnrpcSyncType = lnrpc.Peer_ACTIVE_SYNC
-			case discovery.PassiveSync:
-				lnrpcSyncType = lnrpc.Peer_PASSIVE_SYNC
-			default:
-				return nil, fmt.Errorf("unhandled sync type %v",
-					syncType)
-			}
-		}
+// getChanPointFundingTxid returns the given channel point's funding txid in
+// raw bytes.
+func getChanPointFundingTxid(chanPoint *lnrpc.ChannelPoint) (*chainhash.Hash, error) {
+	var txid []byte
 
-		peer := &lnrpc.Peer{
-			PubKey:    hex.EncodeToString(nodePub[:]),
-			Address:   serverPeer.conn.RemoteAddr().String(),
-			Inbound:   serverPeer.inbound,
-			BytesRecv: atomic.LoadUint64(&serverPeer.bytesReceived),
-			BytesSent: atomic.LoadUint64(&serverPeer.bytesSent),
-			SatSent:   satSent,
-			SatRecv:   satRecv,
-			PingTime:  serverPeer.PingTime(),
-			SyncType:  lnrpcSyncType,
+	// A channel point's funding txid can be get/set as a byte slice or a
+	// string. In the case it is a string, decode it.
+	switch chanPoint.GetFundingTxid().(type) {
+	case *lnrpc.ChannelPoint_FundingTxidBytes:
+		txid = chanPoint.GetFundingTxidBytes()
+	case *lnrpc.ChannelPoint_FundingTxidStr:
+		s := chanPoint.GetFundingTxidStr()
+		h, err := chainhash.NewHashFromStr(s)
+		if err != nil {
+			return nil, err
 		}
 
-		resp.Peers = append(resp.Peers, peer)
+		txid = h[:]
 	}
 
-	rpcsLog.Debugf("[listpeers] yielded %v peers", serverPeers)
-
-	return resp, nil
+	return chainhash.NewHash(txid)
 }
 
-// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
-// confirmed unspent outputs and all unconfirmed unspent outputs under control
-// by the wallet. This method can be modified by having the request specify
-// only witness outputs should be factored into the final output sum.
-// TODO(roasbeef): add async hooks into wallet balance changes
-func (r *rpcServer) WalletBalance(ctx context.Context,
-	in *lnrpc.WalletBalanceRequest) (*lnrpc.WalletBalanceResponse, error) {
+// CloseChannel attempts to close an active channel identified by its channel
+// point. The actions of this method can additionally be augmented to attempt
+// a force close after a timeout period in the case of an inactive peer.
+func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
+	updateStream lnrpc.Lightning_CloseChannelServer) error {
 
-	// Get total balance, from txs that have >= 0 confirmations.
-	totalBal, err := r.server.cc.wallet.ConfirmedBalance(0)
-	if err != nil {
-		return nil, err
+	// If the user didn't specify a channel point, then we'll reject this
+	// request all together.
+	if in.GetChannelPoint() == nil {
+		return fmt.Errorf("must specify channel point in close channel")
 	}
 
-	// Get confirmed balance, from txs that have >= 1 confirmations.
-	confirmedBal, err := r.server.cc.wallet.ConfirmedBalance(1)
+	force := in.Force
+	index := in.ChannelPoint.OutputIndex
+	txid, err := getChanPointFundingTxid(in.GetChannelPoint())
 	if err != nil {
-		return nil, err
+		rpcsLog.Errorf("[closechannel] unable to get funding txid: %v", err)
+		return err
 	}
+	chanPoint := wire.NewOutPoint(txid, index)
 
-	// Get unconfirmed balance, from txs with 0 confirmations.
-	unconfirmedBal := totalBal - confirmedBal
-
-	rpcsLog.Debugf("[walletbalance] Total balance=%v", totalBal)
+	rpcsLog.Tracef("[closechannel] request for ChannelPoint(%v), force=%v",
+		chanPoint, force)
 
-	return &lnrpc.WalletBalanceResponse{
-		TotalBalance:       int64(totalBal),
-		ConfirmedBalance:   int64(confirmedBal),
-		UnconfirmedBalance: int64(unconfirmedBal),
-	}, nil
-}
+	var (
+		updateChan chan interface{}
+		errChan    chan error
+	)
 
-// ChannelBalance returns the total available channel flow across all open
-// channels in satoshis.
-func (r *rpcServer) ChannelBalance(ctx context.Context,
-	in *lnrpc.ChannelBalanceRequest) (*lnrpc.ChannelBalanceResponse, error) {
+	// TODO(roasbeef): if force and peer online then don't force?
 
-	openChannels, err := r.server.chanDB.FetchAllOpenChannels()
-	if err != nil {
-		return nil, err
-	}
-
-	var balance btcutil.Amount
-	for _, channel := range openChannels {
-		balance += channel.LocalCommitment.LocalBalance.ToSatoshis()
-	}
-
-	pendingChannels, err := r.server.chanDB.FetchPendingChannels()
+	// First, we'll fetch the channel as is, as we'll need to examine it
+	// regardless of if this is a force close or not.
+	channel, err := r.fetchActiveChannel(*chanPoint)
 	if err != nil {
-		return nil, err
-	}
-
-	var pendingOpenBalance btcutil.Amount
-	for _, channel := range pendingChannels {
-		pendingOpenBalance += channel.LocalCommitment.LocalBalance.ToSatoshis()
+		return err
 	}
 
-	return &lnrpc.ChannelBalanceResponse{
-		Balance:            int64(balance),
-		PendingOpenBalance: int64(pendingOpenBalance),
-	}, nil
-}
+	// If a force closure was requested, then we'll handle all the details
+	// around the creation and broadcast of the unilateral closure
+	// transaction here rather than going to the switch as we don't require
+	// interaction from the peer.
+	if force {
+		_, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
+		if err != nil {
+			return err
+		}
 
-// PendingChannels returns a list of all the channels that are currently
-// considered "pending". A channel is pending if it has finished the funding
-// workflow and is waiting for confirmations for the funding txn, or is in the
-// process of closure, either initiated cooperatively or non-cooperatively.
-func (r *rpcServer) PendingChannels(ctx context.Context,
-	in *lnrpc.PendingChannelsRequest) (*lnrpc.PendingChannelsResponse, error) {
+		// As we're force closing this channel, as a precaution, we'll
+		// ensure that the switch doesn't continue to see this channel
+		// as eligible for forwarding HTLC's. If the peer is online,
+		// then we'll also purge all of its indexes.
+		remotePub := &channel.StateSnapshot().RemoteIdentity
+		if peer, err := r.server.FindPeer(remotePub); err == nil {
+			// TODO(roasbeef): actually get the active channel
+			// instead too?
+			//  * so only need to grab from database
+			peer.WipeChannel(channel.ChannelPoint())
+		} else {
+			chanID := lnwire.NewChanIDFromOutPoint(channel.ChannelPoint())
+			r.server.htlcSwitch.RemoveLink(chanID)
+		}
 
-	rpcsLog.Debugf("[pendingchannels]")
+		// With the necessary indexes cleaned up, we'll now force close
+		// the channel.
+		chainArbitrator := r.server.chainArb
+		closingTx, err := chainArbitrator.ForceCloseContract(
+			*chanPoint,
+		)
+		if err != nil {
+			rpcsLog.Errorf("unable to force close transaction: %v", err)
+			return err
+		}
 
-	resp := &lnrpc.PendingChannelsResponse{}
+		closingTxid := closingTx.TxHash()
 
-	// First, we'll populate the response with all the channels that are
-	// soon to be opened. We can easily fetch this data from the database
-	// and map the db struct to the proto response.
-	pendingOpenChannels, err := r.server.chanDB.FetchPendingChannels()
-	if err != nil {
-		rpcsLog.Errorf("unable to fetch pending channels: %v", err)
-		return nil, err
-	}
-	resp.PendingOpenChannels = make([]*lnrpc.PendingChannelsResponse_PendingOpenChannel,
-		len(pendingOpenChannels))
-	for i, pendingChan := range pendingOpenChannels {
-		pub := pendingChan.IdentityPub.SerializeCompressed()
+		// With the transaction broadcast, we send our first update to
+		// the client.
+		updateChan = make(chan interface{}, 2)
+		updateChan <- &pendingUpdate{
+			Txid: closingTxid[:],
+		}
 
-		// As this is required for display purposes, we'll calculate
-		// the weight of the commitment transaction. We also add on the
-		// estimated weight of the witness to calculate the weight of
-		// the transaction if it were to be immediately unilaterally
-		// broadcast.
-		// TODO(roasbeef): query for funding tx from wallet, display
-		// that also?
-		localCommitment := pendingChan.LocalCommitment
-		utx := btcutil.NewTx(localCommitment.CommitTx)
-		commitBaseWeight := blockchain.GetTransactionWeight(utx)
-		commitWeight := commitBaseWeight + input.WitnessCommitmentTxWeight
+		errChan = make(chan error, 1)
+		notifier := r.server.cc.chainNotifier
+		go waitForChanToClose(uint32(bestHeight), notifier, errChan, chanPoint,
+			&closingTxid, closingTx.TxOut[0].PkScript, func() {
+				// Respond to the local subsystem which
+				// requested the channel closure.
+				updateChan <- &channelCloseUpdate{
+					ClosingTxid: closingTxid[:],
+					Success:     true,
+				}
+			})
+	} else {
+		// If the link is not known by the switch, we cannot gracefully close
+		// the channel.
+		channelID := lnwire.NewChanIDFromOutPoint(chanPoint)
+		if _, err := r.server.htlcSwitch.GetLink(channelID); err != nil {
+			rpcsLog.Debugf("Trying to non-force close offline channel with "+
+				"chan_point=%v", chanPoint)
+			return newCodedErrorf(lnrpc.ErrorCode_PEER_OFFLINE,
+				"unable to gracefully close channel while "+
+					"peer is offline (try force closing it "+
+					"instead): %v", err)
+		}
 
-		resp.PendingOpenChannels[i] = &lnrpc.PendingChannelsResponse_PendingOpenChannel{
-			Channel: &lnrpc.PendingChannelsResponse_PendingChannel{
-				RemoteNodePub: hex.EncodeToString(pub),
-				ChannelPoint:  pendingChan.FundingOutpoint.String(),
-				Capacity:      int64(pendingChan.Capacity),
-				LocalBalance:  int64(localCommitment.LocalBalance.ToSatoshis()),
-				RemoteBalance: int64(localCommitment.RemoteBalance.ToSatoshis()),
+		// Based on the passed fee related parameters, we'll determine
+		// an appropriate fee rate for the cooperative closure
+		// transaction.
+		satPerKw := lnwallet.SatPerKVByte(
+			in.SatPerByte * 1000,
+		).FeePerKWeight()
+		feeRate, err := sweep.DetermineFeePerKw(
+			r.server.cc.feeEstimator, sweep.FeePreference{
+				ConfTarget: uint32(in.TargetConf),
+				FeeRate:    satPerKw,
 			},
-			CommitWeight: commitWeight,
-			CommitFee:    int64(localCommitment.CommitFee),
-			FeePerKw:     int64(localCommitment.FeePerKw),
-			// TODO(roasbeef): need to track confirmation height
+		)
+		if err != nil {
+			return err
 		}
-	}
 
-	_, currentHeight, err := r.server.cc.chainIO.GetBestBlock()
-	if err != nil {
-		return nil, err
-	}
+		rpcsLog.Debugf("Target sat/kw for closing transaction: %v",
+			int64(feeRate))
 
-	// Next, we'll examine the channels that are soon to be closed so we
-	// can populate these fields within the response.
-	pendingCloseChannels, err := r.server.chanDB.FetchClosedChannels(true)
-	if err != nil {
-		rpcsLog.Errorf("unable to fetch closed channels: %v", err)
-		return nil, err
-	}
-	for _, pendingClose := range pendingCloseChannels {
-		// First construct the channel struct itself, this will be
-		// needed regardless of how this channel was closed.
-		pub := pendingClose.RemotePub.SerializeCompressed()
-		chanPoint := pendingClose.ChanPoint
-		channel := &lnrpc.PendingChannelsResponse_PendingChannel{
-			RemoteNodePub: hex.EncodeToString(pub),
-			ChannelPoint:  chanPoint.String(),
-			Capacity:      int64(pendingClose.Capacity),
-			LocalBalance:  int64(pendingClose.SettledBalance),
+		// If the caller bounded the fee rates it's willing to accept
+		// for the closing transaction, we'll ensure the range is sane.
+		if in.MinSatPerByte < 0 || in.MaxSatPerByte < 0 {
+			return fmt.Errorf("closing fee rates must not be " +
+				"negative")
+		}
+		minFeeRate := lnwallet.SatPerKVByte(
+			in.MinSatPerByte * 1000,
+		).FeePerKWeight()
+		maxFeeRate := lnwallet.SatPerKVByte(
+			in.MaxSatPerByte * 1000,
+		).FeePerKWeight()
+		if maxFeeRate != 0 && minFeeRate > maxFeeRate {
+			return fmt.Errorf("min closing fee rate %v sat/kw "+
+				"exceeds max closing fee rate %v sat/kw",
+				int64(minFeeRate), int64(maxFeeRate))
 		}
 
-		closeTXID := pendingClose.ClosingTXID.String()
-
-		switch pendingClose.CloseType {
-
-		// If the channel was closed cooperatively, then we'll only
-		// need to tack on the closing txid.
-		// TODO(halseth): remove. After recent changes, a coop closed
-		// channel should never be in the "pending close" state.
-		// Keeping for now to let someone that upgraded in the middle
-		// of a close let their closing tx confirm.
-		case channeldb.CooperativeClose:
-			resp.PendingClosingChannels = append(
-				resp.PendingClosingChannels,
-				&lnrpc.PendingChannelsResponse_ClosedChannel{
-					Channel:     channel,
-					ClosingTxid: closeTXID,
-				},
+		// Similarly, if the caller specified the address our funds
+		// should be sent to, we'll convert it into its delivery
+		// script.
+		var deliveryScript []byte
+		if in.DeliveryAddress != "" {
+			addr, err := btcutil.DecodeAddress(
+				in.DeliveryAddress, activeNetParams.Params,
 			)
-
-			resp.TotalLimboBalance += channel.LocalBalance
-
-		// If the channel was force closed, then we'll need to query
-		// the utxoNursery for additional information.
-		// TODO(halseth): distinguish remote and local case?
-		case channeldb.LocalForceClose, channeldb.RemoteForceClose:
-			forceClose := &lnrpc.PendingChannelsResponse_ForceClosedChannel{
-				Channel:     channel,
-				ClosingTxid: closeTXID,
+			if err != nil {
+				return fmt.Errorf("invalid delivery address: "+
+					"%v", err)
+			}
+			if !addr.IsForNet(activeNetParams.Params) {
+				return fmt.Errorf("delivery address %v is "+
+					"not valid for this network", addr)
 			}
 
-			// Fetch reports from both nursery and resolvers. At the
-			// moment this is not an atomic snapshot. This is
-			// planned to be resolved when the nursery is removed
-			// and channel arbitrator will be the single source for
-			// these kind of reports.
-			err := r.nurseryPopulateForceCloseResp(
-				&chanPoint, currentHeight, forceClose,
-			)
+			deliveryScript, err = txscript.PayToAddrScript(addr)
 			if err != nil {
-				return nil, err
+				return err
 			}
+			if !isStandardDeliveryScript(deliveryScript) {
+				return fmt.Errorf("delivery address %v is of "+
+					"an unsupported type", addr)
+			}
+		}
 
-			err = r.arbitratorPopulateForceCloseResp(
-				&chanPoint, currentHeight, forceClose,
+		// Before we attempt the cooperative channel closure, we'll
+		// examine the channel to ensure that it doesn't have a
+		// lingering HTLC.
+		if len(channel.ActiveHtlcs()) != 0 {
+			return fmt.Errorf("cannot co-op close channel " +
+				"with active htlcs")
+		}
+
+		// Otherwise, the caller has requested a regular interactive
+		// cooperative channel closure. So we'll forward the request to
+		// the htlc switch which will handle the negotiation and
+		// broadcast details.
+		updateChan, errChan = r.server.htlcSwitch.CloseLink(
+			chanPoint, htlcswitch.CloseRegular, feeRate,
+			minFeeRate, maxFeeRate, deliveryScript,
+		)
+	}
+out:
+	for {
+		select {
+		case err := <-errChan:
+			rpcsLog.Errorf("[closechannel] unable to close "+
+				"ChannelPoint(%v): %v", chanPoint, err)
+			return err
+		case closingUpdate := <-updateChan:
+			rpcClosingUpdate, err := createRPCCloseUpdate(
+				closingUpdate,
 			)
 			if err != nil {
-				return nil, err
+				return err
 			}
 
-			resp.TotalLimboBalance += int64(forceClose.LimboBalance)
+			rpcsLog.Tracef("[closechannel] sending update: %v",
+				rpcClosingUpdate)
 
-			resp.PendingForceClosingChannels = append(
-				resp.PendingForceClosingChannels,
-				forceClose,
-			)
+			if err := updateStream.Send(rpcClosingUpdate); err != nil {
+				return err
+			}
+
+			// If a final channel closing updates is being sent,
+			// then we can break out of our dispatch loop as we no
+			// longer need to process any further updates.
+			switch closeUpdate := closingUpdate.(type) {
+			case *channelCloseUpdate:
+				h, _ := chainhash.NewHash(closeUpdate.ClosingTxid)
+				rpcsLog.Infof("[closechannel] close completed: "+
+					"txid(%v)", h)
+				break out
+			}
+		case <-r.quit:
+			return nil
 		}
 	}
 
-	// We'll also fetch all channels that are open, but have had their
-	// commitment broadcasted, meaning they are waiting for the closing
-	// transaction to confirm.
-	waitingCloseChans, err := r.server.chanDB.FetchWaitingCloseChannels()
+	return nil
+}
+
+func createRPCCloseUpdate(update interface{}) (
+	*lnrpc.CloseStatusUpdate, error) {
+
+	switch u := update.(type) {
+	case *channelCloseUpdate:
+		return &lnrpc.CloseStatusUpdate{
+			Update: &lnrpc.CloseStatusUpdate_ChanClose{
+				ChanClose: &lnrpc.ChannelCloseUpdate{
+					ClosingTxid: u.ClosingTxid,
+				},
+			},
+		}, nil
+	case *pendingUpdate:
+		return &lnrpc.CloseStatusUpdate{
+			Update: &lnrpc.CloseStatusUpdate_ClosePending{
+				ClosePending: &lnrpc.PendingUpdate{
+					Txid:        u.Txid,
+					OutputIndex: u.OutputIndex,
+				},
+			},
+		}, nil
+	}
+
+	return nil, errors.New("unknown close status update")
+}
+
+// AbandonChannel removes all channel state from the database except for a
+// close summary. This method can be used to get rid of permanently unusable
+// channels due to bugs fixed in newer versions of lnd.
+func (r *rpcServer) AbandonChannel(ctx context.Context,
+	in *lnrpc.AbandonChannelRequest) (*lnrpc.AbandonChannelResponse, error) {
+
+	// If this isn't the dev build, then we won't allow the RPC to be
+	// executed, as it's an advanced feature and won't be activated in
+	// regular production/release builds.
+	if !build.IsDevBuild() {
+		return nil, fmt.Errorf("AbandonChannel RPC call only " +
+			"available in dev builds")
+	}
+
+	// We'll parse out the arguments to we can obtain the chanPoint of the
+	// target channel.
+	txid, err := getChanPointFundingTxid(in.GetChannelPoint())
 	if err != nil {
-		rpcsLog.Errorf("unable to fetch channels waiting close: %v",
-			err)
 		return nil, err
 	}
+	index := in.ChannelPoint.OutputIndex
+	chanPoint := wire.NewOutPoint(txid, index)
 
-	for _, waitingClose := range waitingCloseChans {
-		pub := waitingClose.IdentityPub.SerializeCompressed()
-		chanPoint := waitingClose.FundingOutpoint
-		channel := &lnrpc.PendingChannelsResponse_PendingChannel{
-			RemoteNodePub: hex.EncodeToString(pub),
-			ChannelPoint:  chanPoint.String(),
-			Capacity:      int64(waitingClose.Capacity),
-			LocalBalance:  int64(waitingClose.LocalCommitment.LocalBalance.ToSatoshis()),
-		}
+	// With the chanPoint constructed, we'll attempt to find the target
+	// channel in the database. If we can't find the channel, then we'll
+	// return the error back to the caller.
+	dbChan, err := r.server.chanDB.FetchChannel(*chanPoint)
+	if err != nil {
+		return nil, err
+	}
 
-		// A close tx has been broadcasted, all our balance will be in
-		// limbo until it confirms.
-		resp.WaitingCloseChannels = append(
-			resp.WaitingCloseChannels,
-			&lnrpc.PendingChannelsResponse_WaitingCloseChannel{
-				Channel:      channel,
-				LimboBalance: channel.LocalBalance,
-			},
-		)
+	// Now that we've found the channel, we'll populate a close summary for
+	// the channel, so we can store as much information for this abounded
+	// channel as possible. We also ensure that we set Pending to false, to
+	// indicate that this channel has been "fully" closed.
+	_, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	summary := &channeldb.ChannelCloseSummary{
+		CloseType:               channeldb.Abandoned,
+		ChanPoint:               *chanPoint,
+		ChainHash:               dbChan.ChainHash,
+		CloseHeight:             uint32(bestHeight),
+		RemotePub:               dbChan.IdentityPub,
+		Capacity:                dbChan.Capacity,
+		SettledBalance:          dbChan.LocalCommitment.LocalBalance.ToSatoshis(),
+		ShortChanID:             dbChan.ShortChanID(),
+		RemoteCurrentRevocation: dbChan.RemoteCurrentRevocation,
+		RemoteNextRevocation:    dbChan.RemoteNextRevocation,
+		LocalChanConfig:         dbChan.LocalChanCfg,
+	}
 
-		resp.TotalLimboBalance += channel.LocalBalance
+	// Finally, we'll close the channel in the DB, and return back to the
+	// caller.
+	err = dbChan.CloseChannel(summary)
+	if err != nil {
+		return nil, err
 	}
 
-	return resp, nil
+	return &lnrpc.AbandonChannelResponse{}, nil
 }
 
-// arbitratorPopulateForceCloseResp populates the pending channels response
-// message with channel resolution information from the contract resolvers.
-func (r *rpcServer) arbitratorPopulateForceCloseResp(chanPoint *wire.OutPoint,
-	currentHeight int32,
-	forceClose *lnrpc.PendingChannelsResponse_ForceClosedChannel) error {
+// fetchActiveChannel attempts to locate a channel identified by its channel
+// point from the database's set of all currently opened channels and
+// return it as a fully populated state machine
+func (r *rpcServer) fetchActiveChannel(chanPoint wire.OutPoint) (
+	*lnwallet.LightningChannel, error) {
 
-	// Query for contract resolvers state.
-	arbitrator, err := r.server.chainArb.GetChannelArbitrator(*chanPoint)
+	dbChan, err := r.server.chanDB.FetchChannel(chanPoint)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	reports := arbitrator.Report()
 
-	for _, report := range reports {
-		htlc := &lnrpc.PendingHTLC{
-			Incoming:       report.Incoming,
-			Amount:         int64(report.Amount),
-			Outpoint:       report.Outpoint.String(),
-			MaturityHeight: report.MaturityHeight,
-			Stage:          report.Stage,
+	// If the channel is successfully fetched from the database,
+	// we create a fully populated channel state machine which
+	// uses the db channel as backing storage.
+	return lnwallet.NewLightningChannel(
+		r.server.cc.wallet.Cfg.Signer, nil, dbChan, nil,
+	)
+}
+
+// GetInfo returns general information concerning the lightning node including
+// its identity pubkey, alias, the chains it is connected to, and information
+// concerning the number of open+pending channels.
+func (r *rpcServer) GetInfo(ctx context.Context,
+	in *lnrpc.GetInfoRequest) (*lnrpc.GetInfoResponse, error) {
+
+	serverPeers := r.server.Peers()
+
+	openChannels, err := r.server.chanDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var activeChannels uint32
+	for _, channel := range openChannels {
+		chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+		if r.server.htlcSwitch.HasActiveLink(chanID) {
+			activeChannels++
 		}
+	}
 
-		if htlc.MaturityHeight != 0 {
-			htlc.BlocksTilMaturity =
-				int32(htlc.MaturityHeight) - currentHeight
+	inactiveChannels := uint32(len(openChannels)) - activeChannels
+
+	pendingChannels, err := r.server.chanDB.FetchPendingChannels()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get retrieve pending "+
+			"channels: %v", err)
+	}
+	nPendingChannels := uint32(len(pendingChannels))
+
+	idPub := r.server.identityPriv.PubKey().SerializeCompressed()
+	encodedIDPub := hex.EncodeToString(idPub)
+
+	bestHash, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get best block info: %v", err)
+	}
+
+	isSynced, bestHeaderTimestamp, err := r.server.cc.wallet.IsSynced()
+	if err != nil {
+		return nil, fmt.Errorf("unable to sync PoV of the wallet "+
+			"with current best block in the main chain: %v", err)
+	}
+
+	network := normalizeNetwork(activeNetParams.Name)
+	activeChains := make([]*lnrpc.Chain, registeredChains.NumActiveChains())
+	for i, chain := range registeredChains.ActiveChains() {
+		activeChains[i] = &lnrpc.Chain{
+			Chain:   chain.String(),
+			Network: network,
+		}
+
+	}
+
+	// Check if external IP addresses were provided to lnd and use them
+	// to set the URIs.
+	nodeAnn, err := r.server.genNodeAnnouncement(false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve current fully signed "+
+			"node announcement: %v", err)
+	}
+	addrs := nodeAnn.Addresses
+	uris := make([]string, len(addrs))
+	for i, addr := range addrs {
+		uris[i] = fmt.Sprintf("%s@%s", encodedIDPub, addr.String())
+	}
+
+	// TODO(roasbeef): add synced height n stuff
+	return &lnrpc.GetInfoResponse{
+		IdentityPubkey:      encodedIDPub,
+		NumPendingChannels:  nPendingChannels,
+		NumActiveChannels:   activeChannels,
+		NumInactiveChannels: inactiveChannels,
+		NumPeers:            uint32(len(serverPeers)),
+		BlockHeight:         uint32(bestHeight),
+		BlockHash:           bestHash.String(),
+		SyncedToChain:       isSynced,
+		Testnet:             isTestnet(&activeNetParams),
+		Chains:              activeChains,
+		Uris:                uris,
+		Alias:               nodeAnn.Alias.String(),
+		BestHeaderTimestamp: int64(bestHeaderTimestamp),
+		Version:             build.Version(),
+	}, nil
+}
+
+// ListPeers returns a verbose listing of all currently active peers.
+func (r *rpcServer) ListPeers(ctx context.Context,
+	in *lnrpc.ListPeersRequest) (*lnrpc.ListPeersResponse, error) {
+
+	rpcsLog.Tracef("[listpeers] request")
+
+	serverPeers := r.server.Peers()
+	resp := &lnrpc.ListPeersResponse{
+		Peers: make([]*lnrpc.Peer, 0, len(serverPeers)),
+	}
+
+	for _, serverPeer := range serverPeers {
+		var (
+			satSent int64
+			satRecv int64
+		)
+
+		// In order to display the total number of satoshis of outbound
+		// (sent) and inbound (recv'd) satoshis that have been
+		// transported through this peer, we'll sum up the sent/recv'd
+		// values for each of the active channels we have with the
+		// peer.
+		chans := serverPeer.ChannelSnapshots()
+		for _, c := range chans {
+			satSent += int64(c.TotalMSatSent.ToSatoshis())
+			satRecv += int64(c.TotalMSatReceived.ToSatoshis())
 		}
 
-		forceClose.LimboBalance += int64(report.LimboBalance)
-		forceClose.RecoveredBalance += int64(report.RecoveredBalance)
+		nodePub := serverPeer.PubKey()
+
+		// Retrieve the peer's sync type. If we don't currently have a
+		// syncer for the peer, then we'll default to a passive sync.
+		// This can happen if the RPC is called while a peer is
+		// initializing.
+		syncer, ok := r.server.authGossiper.SyncManager().GossipSyncer(
+			nodePub,
+		)
+
+		lnrpcSyncType := lnrpc.Peer_UNKNOWN_SYNC
+		if !ok {
+			rpcsLog.Warnf("Gossip syncer for peer=%x not found",
+				nodePub)
+		} else {
+			var err error
+			lnrpcSyncType, err = marshallSyncType(syncer.SyncType())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		peer := &lnrpc.Peer{
+			PubKey:    hex.EncodeToString(nodePub[:]),
+			Address:   serverPeer.conn.RemoteAddr().String(),
+			Inbound:   serverPeer.inbound,
+			BytesRecv: atomic.LoadUint64(&serverPeer.bytesReceived),
+			BytesSent: atomic.LoadUint64(&serverPeer.bytesSent),
+			SatSent:   satSent,
+			SatRecv:   satRecv,
+			PingTime:  serverPeer.PingTime(),
+			SyncType:  lnrpcSyncType,
+		}
+
+		resp.Peers = append(resp.Peers, peer)
+	}
+
+	rpcsLog.Debugf("[listpeers] yielded %v peers", serverPeers)
+
+	return resp, nil
+}
+
+// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
+// confirmed unspent outputs and all unconfirmed unspent outputs under control
+// by the wallet. This method can be modified by having the request specify
+// only witness outputs should be factored into the final output sum.
+// TODO(roasbeef): add async hooks into wallet balance changes
+func (r *rpcServer) WalletBalance(ctx context.Context,
+	in *lnrpc.WalletBalanceRequest) (*lnrpc.WalletBalanceResponse, error) {
+
+	// Get total balance, from txs that have >= 0 confirmations.
+	totalBal, err := r.server.cc.wallet.ConfirmedBalance(0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get confirmed balance, from txs that have >= 1 confirmations.
+	confirmedBal, err := r.server.cc.wallet.ConfirmedBalance(1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get unconfirmed balance, from txs with 0 confirmations.
+	unconfirmedBal := totalBal - confirmedBal
+
+	// Break the balance down by the account each output belongs to, such
+	// that every account is listed, even those without any funds.
+	accounts, err := r.server.cc.wallet.ListAccounts()
+	if err != nil {
+		return nil, err
+	}
+	accountBalance := make(map[string]*lnrpc.WalletAccountBalance)
+	for _, account := range accounts {
+		accountBalance[account] = &lnrpc.WalletAccountBalance{}
+	}
+
+	utxos, err := r.server.cc.wallet.ListUnspentWitness(0, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+	for _, utxo := range utxos {
+		account := utxo.Account
+		if account == "" {
+			account = lnwallet.DefaultAccountName
+		}
+
+		balance, ok := accountBalance[account]
+		if !ok {
+			balance = &lnrpc.WalletAccountBalance{}
+			accountBalance[account] = balance
+		}
+
+		if utxo.Confirmations > 0 {
+			balance.ConfirmedBalance += int64(utxo.Value)
+		} else {
+			balance.UnconfirmedBalance += int64(utxo.Value)
+		}
+	}
+
+	rpcsLog.Debugf("[walletbalance] Total balance=%v", totalBal)
+
+	return &lnrpc.WalletBalanceResponse{
+		TotalBalance:       int64(totalBal),
+		ConfirmedBalance:   int64(confirmedBal),
+		UnconfirmedBalance: int64(unconfirmedBal),
+		AccountBalance:     accountBalance,
+	}, nil
+}
+
+// ChannelBalance returns the total available channel flow across all open
+// channels in satoshis and milli-satoshis.
+func (r *rpcServer) ChannelBalance(ctx context.Context,
+	in *lnrpc.ChannelBalanceRequest) (*lnrpc.ChannelBalanceResponse, error) {
+
+	openChannels, err := r.server.chanDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	// The satoshi balances are summed per channel, such that they match
+	// the sum of the balances reported by ListChannels.
+	var (
+		balance     btcutil.Amount
+		balanceMsat lnwire.MilliSatoshi
+	)
+	for _, channel := range openChannels {
+		localBalance := channel.LocalCommitment.LocalBalance
+		balance += localBalance.ToSatoshis()
+		balanceMsat += localBalance
+	}
+
+	pendingChannels, err := r.server.chanDB.FetchPendingChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pendingOpenBalance     btcutil.Amount
+		pendingOpenBalanceMsat lnwire.MilliSatoshi
+	)
+	for _, channel := range pendingChannels {
+		localBalance := channel.LocalCommitment.LocalBalance
+		pendingOpenBalance += localBalance.ToSatoshis()
+		pendingOpenBalanceMsat += localBalance
+	}
+
+	return &lnrpc.ChannelBalanceResponse{
+		Balance:                int64(balance),
+		PendingOpenBalance:     int64(pendingOpenBalance),
+		BalanceMsat:            int64(balanceMsat),
+		PendingOpenBalanceMsat: int64(pendingOpenBalanceMsat),
+	}, nil
+}
+
+// PendingChannels returns a list of all the channels that are currently
+// considered "pending". A channel is pending if it has finished the funding
+// workflow and is waiting for confirmations for the funding txn, or is in the
+// process of closure, either initiated cooperatively or non-cooperatively.
+func (r *rpcServer) PendingChannels(ctx context.Context,
+	in *lnrpc.PendingChannelsRequest) (*lnrpc.PendingChannelsResponse, error) {
+
+	rpcsLog.Debugf("[pendingchannels]")
+
+	resp := &lnrpc.PendingChannelsResponse{}
+
+	// First, we'll populate the response with all the channels that are
+	// soon to be opened. We can easily fetch this data from the database
+	// and map the db struct to the proto response.
+	pendingOpenChannels, err := r.server.chanDB.FetchPendingChannels()
+	if err != nil {
+		rpcsLog.Errorf("unable to fetch pending channels: %v", err)
+		return nil, err
+	}
+	resp.PendingOpenChannels = make([]*lnrpc.PendingChannelsResponse_PendingOpenChannel,
+		len(pendingOpenChannels))
+	for i, pendingChan := range pendingOpenChannels {
+		pub := pendingChan.IdentityPub.SerializeCompressed()
+
+		// As this is required for display purposes, we'll calculate
+		// the weight of the commitment transaction. We also add on the
+		// estimated weight of the witness to calculate the weight of
+		// the transaction if it were to be immediately unilaterally
+		// broadcast.
+		// TODO(roasbeef): query for funding tx from wallet, display
+		// that also?
+		localCommitment := pendingChan.LocalCommitment
+		utx := btcutil.NewTx(localCommitment.CommitTx)
+		commitBaseWeight := blockchain.GetTransactionWeight(utx)
+		commitWeight := commitBaseWeight + input.WitnessCommitmentTxWeight
+
+		resp.PendingOpenChannels[i] = &lnrpc.PendingChannelsResponse_PendingOpenChannel{
+			Channel: &lnrpc.PendingChannelsResponse_PendingChannel{
+				RemoteNodePub: hex.EncodeToString(pub),
+				ChannelPoint:  pendingChan.FundingOutpoint.String(),
+				Capacity:      int64(pendingChan.Capacity),
+				LocalBalance:  int64(localCommitment.LocalBalance.ToSatoshis()),
+				RemoteBalance: int64(localCommitment.RemoteBalance.ToSatoshis()),
+			},
+			CommitWeight: commitWeight,
+			CommitFee:    int64(localCommitment.CommitFee),
+			FeePerKw:     int64(localCommitment.FeePerKw),
+			// TODO(roasbeef): need to track confirmation height
+		}
+	}
+
+	_, currentHeight, err := r.server.cc.chainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Next, we'll examine the channels that are soon to be closed so we
+	// can populate these fields within the response.
+	pendingCloseChannels, err := r.server.chanDB.FetchClosedChannels(true)
+	if err != nil {
+		rpcsLog.Errorf("unable to fetch closed channels: %v", err)
+		return nil, err
+	}
+	for _, pendingClose := range pendingCloseChannels {
+		// First construct the channel struct itself, this will be
+		// needed regardless of how this channel was closed.
+		pub := pendingClose.RemotePub.SerializeCompressed()
+		chanPoint := pendingClose.ChanPoint
+		channel := &lnrpc.PendingChannelsResponse_PendingChannel{
+			RemoteNodePub: hex.EncodeToString(pub),
+			ChannelPoint:  chanPoint.String(),
+			Capacity:      int64(pendingClose.Capacity),
+			LocalBalance:  int64(pendingClose.SettledBalance),
+		}
+
+		closeTXID := pendingClose.ClosingTXID.String()
+
+		switch pendingClose.CloseType {
+
+		// If the channel was closed cooperatively, then we'll only
+		// need to tack on the closing txid.
+		// TODO(halseth): remove. After recent changes, a coop closed
+		// channel should never be in the "pending close" state.
+		// Keeping for now to let someone that upgraded in the middle
+		// of a close let their closing tx confirm.
+		case channeldb.CooperativeClose:
+			resp.PendingClosingChannels = append(
+				resp.PendingClosingChannels,
+				&lnrpc.PendingChannelsResponse_ClosedChannel{
+					Channel:     channel,
+					ClosingTxid: closeTXID,
+				},
+			)
+
+			resp.TotalLimboBalance += channel.LocalBalance
+
+		// If the channel was force closed, then we'll need to query
+		// the utxoNursery for additional information.
+		// TODO(halseth): distinguish remote and local case?
+		case channeldb.LocalForceClose, channeldb.RemoteForceClose:
+			forceClose := &lnrpc.PendingChannelsResponse_ForceClosedChannel{
+				Channel:     channel,
+				ClosingTxid: closeTXID,
+			}
+
+			// Fetch reports from both nursery and resolvers. At the
+			// moment this is not an atomic snapshot. This is
+			// planned to be resolved when the nursery is removed
+			// and channel arbitrator will be the single source for
+			// these kind of reports.
+			err := r.nurseryPopulateForceCloseResp(
+				&chanPoint, currentHeight, forceClose,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			err = r.arbitratorPopulateForceCloseResp(
+				&chanPoint, currentHeight, forceClose,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			resp.TotalLimboBalance += int64(forceClose.LimboBalance)
+
+			resp.PendingForceClosingChannels = append(
+				resp.PendingForceClosingChannels,
+				forceClose,
+			)
+		}
+	}
+
+	// We'll also fetch all channels that are open, but have had their
+	// commitment broadcasted, meaning they are waiting for the closing
+	// transaction to confirm.
+	waitingCloseChans, err := r.server.chanDB.FetchWaitingCloseChannels()
+	if err != nil {
+		rpcsLog.Errorf("unable to fetch channels waiting close: %v",
+			err)
+		return nil, err
+	}
+
+	for _, waitingClose := range waitingCloseChans {
+		pub := waitingClose.IdentityPub.SerializeCompressed()
+		chanPoint := waitingClose.FundingOutpoint
+		channel := &lnrpc.PendingChannelsResponse_PendingChannel{
+			RemoteNodePub: hex.EncodeToString(pub),
+			ChannelPoint:  chanPoint.String(),
+			Capacity:      int64(waitingClose.Capacity),
+			LocalBalance:  int64(waitingClose.LocalCommitment.LocalBalance.ToSatoshis()),
+		}
+
+		// A close tx has been broadcasted, all our balance will be in
+		// limbo until it confirms.
+		resp.WaitingCloseChannels = append(
+			resp.WaitingCloseChannels,
+			&lnrpc.PendingChannelsResponse_WaitingCloseChannel{
+				Channel:      channel,
+				LimboBalance: channel.LocalBalance,
+			},
+		)
+
+		resp.TotalLimboBalance += channel.LocalBalance
+	}
+
+	return resp, nil
+}
+
+// arbitratorPopulateForceCloseResp populates the pending channels response
+// message with channel resolution information from the contract resolvers.
+func (r *rpcServer) arbitratorPopulateForceCloseResp(chanPoint *wire.OutPoint,
+	currentHeight int32,
+	forceClose *lnrpc.PendingChannelsResponse_ForceClosedChannel) error {
+
+	// Query for contract resolvers state.
+	arbitrator, err := r.server.chainArb.GetChannelArbitrator(*chanPoint)
+	if err != nil {
+		return err
+	}
+	reports := arbitrator.Report()
+
+	for _, report := range reports {
+		htlc := &lnrpc.PendingHTLC{
+			Incoming:       report.Incoming,
+			Amount:         int64(report.Amount),
+			Outpoint:       report.Outpoint.String(),
+			MaturityHeight: report.MaturityHeight,
+			Stage:          report.Stage,
+		}
+
+		if htlc.MaturityHeight != 0 {
+			htlc.BlocksTilMaturity =
+				int32(htlc.MaturityHeight) - currentHeight
+		}
+
+		forceClose.LimboBalance += int64(report.LimboBalance)
+		forceClose.RecoveredBalance += int64(report.RecoveredBalance)
+
+		forceClose.PendingHtlcs = append(forceClose.PendingHtlcs, htlc)
+	}
+
+	return nil
+}
+
+// nurseryPopulateForceCloseResp populates the pending channels response
+// message with contract resolution information from utxonursery.
+func (r *rpcServer) nurseryPopulateForceCloseResp(chanPoint *wire.OutPoint,
+	currentHeight int32,
+	forceClose *lnrpc.PendingChannelsResponse_ForceClosedChannel) error {
+
+	// Query for the maturity state for this force closed channel. If we
+	// didn't have any time-locked outputs, then the nursery may not know of
+	// the contract.
+	nurseryInfo, err := r.server.utxoNursery.NurseryReport(chanPoint)
+	if err == ErrContractNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to obtain "+
+			"nursery report for ChannelPoint(%v): %v",
+			chanPoint, err)
+	}
+
+	// If the nursery knows of this channel, then we can populate
+	// information detailing exactly how much funds are time locked and also
+	// the height in which we can ultimately sweep the funds into the
+	// wallet.
+	forceClose.LimboBalance = int64(nurseryInfo.limboBalance)
+	forceClose.RecoveredBalance = int64(nurseryInfo.recoveredBalance)
+	forceClose.MaturityHeight = nurseryInfo.maturityHeight
+
+	// If the transaction has been confirmed, then we can compute how many
+	// blocks it has left.
+	if forceClose.MaturityHeight != 0 {
+		forceClose.BlocksTilMaturity =
+			int32(forceClose.MaturityHeight) -
+				currentHeight
+	}
+
+	for _, htlcReport := range nurseryInfo.htlcs {
+		// TODO(conner) set incoming flag appropriately after handling
+		// incoming incubation
+		htlc := &lnrpc.PendingHTLC{
+			Incoming:       false,
+			Amount:         int64(htlcReport.amount),
+			Outpoint:       htlcReport.outpoint.String(),
+			MaturityHeight: htlcReport.maturityHeight,
+			Stage:          htlcReport.stage,
+		}
+
+		if htlc.MaturityHeight != 0 {
+			htlc.BlocksTilMaturity =
+				int32(htlc.MaturityHeight) -
+					currentHeight
+		}
+
+		forceClose.PendingHtlcs = append(forceClose.PendingHtlcs,
+			htlc)
+	}
+
+	return nil
+}
+
+// ClosedChannels returns a list of all the channels have been closed.
+// This does not include channels that are still in the process of closing.
+func (r *rpcServer) ClosedChannels(ctx context.Context,
+	in *lnrpc.ClosedChannelsRequest) (*lnrpc.ClosedChannelsResponse,
+	error) {
+
+	// Show all channels when no filter flags are set.
+	filterResults := in.Cooperative || in.LocalForce ||
+		in.RemoteForce || in.Breach || in.FundingCanceled ||
+		in.Abandoned
+
+	resp := &lnrpc.ClosedChannelsResponse{}
+
+	dbChannels, err := r.server.chanDB.FetchClosedChannels(false)
+	if err != nil {
+		return nil, err
+	}
+
+	// In order to make the response easier to parse for clients, we'll
+	// sort the set of closed channels by their closing height before
+	// serializing the proto response.
+	sort.Slice(dbChannels, func(i, j int) bool {
+		return dbChannels[i].CloseHeight < dbChannels[j].CloseHeight
+	})
+
+	for _, dbChannel := range dbChannels {
+		if dbChannel.IsPending {
+			continue
+		}
+
+		switch dbChannel.CloseType {
+		case channeldb.CooperativeClose:
+			if filterResults && !in.Cooperative {
+				continue
+			}
+		case channeldb.LocalForceClose:
+			if filterResults && !in.LocalForce {
+				continue
+			}
+		case channeldb.RemoteForceClose:
+			if filterResults && !in.RemoteForce {
+				continue
+			}
+		case channeldb.BreachClose:
+			if filterResults && !in.Breach {
+				continue
+			}
+		case channeldb.FundingCanceled:
+			if filterResults && !in.FundingCanceled {
+				continue
+			}
+		case channeldb.Abandoned:
+			if filterResults && !in.Abandoned {
+				continue
+			}
+		}
+
+		channel := createRPCClosedChannel(dbChannel)
+		resp.Channels = append(resp.Channels, channel)
+	}
+
+	return resp, nil
+}
+
+// ListChannels returns a description of all the open channels that this node
+// is a participant in.
+func (r *rpcServer) ListChannels(ctx context.Context,
+	in *lnrpc.ListChannelsRequest) (*lnrpc.ListChannelsResponse, error) {
+
+	if in.ActiveOnly && in.InactiveOnly {
+		return nil, fmt.Errorf("either `active_only` or " +
+			"`inactive_only` can be set, but not both")
+	}
+
+	if in.PublicOnly && in.PrivateOnly {
+		return nil, fmt.Errorf("either `public_only` or " +
+			"`private_only` can be set, but not both")
+	}
+
+	resp := &lnrpc.ListChannelsResponse{}
+
+	graph := r.server.chanDB.ChannelGraph()
+
+	dbChannels, err := r.server.chanDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[listchannels] fetched %v channels from DB",
+		len(dbChannels))
+
+	for _, dbChannel := range dbChannels {
+		nodePub := dbChannel.IdentityPub
+		chanPoint := dbChannel.FundingOutpoint
+
+		var peerOnline bool
+		if _, err := r.server.FindPeer(nodePub); err == nil {
+			peerOnline = true
+		}
+
+		channelID := lnwire.NewChanIDFromOutPoint(&chanPoint)
+		var linkActive bool
+		if link, err := r.server.htlcSwitch.GetLink(channelID); err == nil {
+			// A channel is only considered active if it is known
+			// by the switch *and* able to forward
+			// incoming/outgoing payments.
+			linkActive = link.EligibleToForward()
+		}
+
+		// Next, we'll determine whether we should add this channel to
+		// our list depending on the type of channels requested to us.
+		isActive := peerOnline && linkActive
+		channel := createRPCOpenChannel(r, graph, dbChannel, isActive)
+
+		// We'll only skip returning this channel if we were requested
+		// for a specific kind and this channel doesn't satisfy it.
+		switch {
+		case in.ActiveOnly && !isActive:
+			continue
+		case in.InactiveOnly && isActive:
+			continue
+		case in.PublicOnly && channel.Private:
+			continue
+		case in.PrivateOnly && !channel.Private:
+			continue
+		}
+
+		resp.Channels = append(resp.Channels, channel)
+	}
+
+	return resp, nil
+}
+
+// createRPCOpenChannel creates an *lnrpc.Channel from the *channeldb.Channel.
+func createRPCOpenChannel(r *rpcServer, graph *channeldb.ChannelGraph,
+	dbChannel *channeldb.OpenChannel, isActive bool) *lnrpc.Channel {
+
+	nodePub := dbChannel.IdentityPub
+	nodeID := hex.EncodeToString(nodePub.SerializeCompressed())
+	chanPoint := dbChannel.FundingOutpoint
+
+	// Next, we'll determine whether the channel is public or not.
+	isPublic := dbChannel.ChannelFlags&lnwire.FFAnnounceChannel != 0
+
+	// As this is required for display purposes, we'll calculate
+	// the weight of the commitment transaction. We also add on the
+	// estimated weight of the witness to calculate the weight of
+	// the transaction if it were to be immediately unilaterally
+	// broadcast.
+	localCommit := dbChannel.LocalCommitment
+	utx := btcutil.NewTx(localCommit.CommitTx)
+	commitBaseWeight := blockchain.GetTransactionWeight(utx)
+	commitWeight := commitBaseWeight + input.WitnessCommitmentTxWeight
+
+	localBalance := localCommit.LocalBalance
+	remoteBalance := localCommit.RemoteBalance
+
+	// As an artifact of our usage of mSAT internally, either party
+	// may end up in a state where they're holding a fractional
+	// amount of satoshis which can't be expressed within the
+	// actual commitment output. Since we round down when going
+	// from mSAT -> SAT, we may at any point be adding an
+	// additional SAT to miners fees. As a result, we display a
+	// commitment fee that accounts for this externally.
+	var sumOutputs btcutil.Amount
+	for _, txOut := range localCommit.CommitTx.TxOut {
+		sumOutputs += btcutil.Amount(txOut.Value)
+	}
+	externalCommitFee := dbChannel.Capacity - sumOutputs
+
+	channel := &lnrpc.Channel{
+		Active:                isActive,
+		Private:               !isPublic,
+		RemotePubkey:          nodeID,
+		ChannelPoint:          chanPoint.String(),
+		ChanId:                dbChannel.ShortChannelID.ToUint64(),
+		Capacity:              int64(dbChannel.Capacity),
+		LocalBalance:          int64(localBalance.ToSatoshis()),
+		RemoteBalance:         int64(remoteBalance.ToSatoshis()),
+		CommitFee:             int64(externalCommitFee),
+		CommitWeight:          commitWeight,
+		FeePerKw:              int64(localCommit.FeePerKw),
+		TotalSatoshisSent:     int64(dbChannel.TotalMSatSent.ToSatoshis()),
+		TotalSatoshisReceived: int64(dbChannel.TotalMSatReceived.ToSatoshis()),
+		NumUpdates:            localCommit.CommitHeight,
+		PendingHtlcs:          make([]*lnrpc.HTLC, len(localCommit.Htlcs)),
+		CsvDelay:              uint32(dbChannel.LocalChanCfg.CsvDelay),
+		Initiator:             dbChannel.IsInitiator,
+		ChanStatusFlags:       dbChannel.ChanStatus().String(),
+		LocalDustLimitSat:     int64(dbChannel.LocalChanCfg.DustLimit),
+		RemoteDustLimitSat:    int64(dbChannel.RemoteChanCfg.DustLimit),
+		LocalChanReserveSat:   int64(dbChannel.LocalChanCfg.ChanReserve),
+		RemoteChanReserveSat:  int64(dbChannel.RemoteChanCfg.ChanReserve),
+		ZeroConf:              isZeroConf(dbChannel),
+	}
+
+	for i, htlc := range localCommit.Htlcs {
+		var rHash [32]byte
+		copy(rHash[:], htlc.RHash[:])
+		channel.PendingHtlcs[i] = &lnrpc.HTLC{
+			Incoming:         htlc.Incoming,
+			Amount:           int64(htlc.Amt.ToSatoshis()),
+			HashLock:         rHash[:],
+			ExpirationHeight: htlc.RefundTimeout,
+		}
+
+		// Add the Pending Htlc Amount to UnsettledBalance field.
+		channel.UnsettledBalance += channel.PendingHtlcs[i].Amount
+	}
+
+	return channel
+}
+
+// createRPCClosedChannel creates an *lnrpc.ClosedChannelSummary from a
+// *channeldb.ChannelCloseSummary.
+func createRPCClosedChannel(
+	dbChannel *channeldb.ChannelCloseSummary) *lnrpc.ChannelCloseSummary {
+
+	nodePub := dbChannel.RemotePub
+	nodeID := hex.EncodeToString(nodePub.SerializeCompressed())
+
+	var closeType lnrpc.ChannelCloseSummary_ClosureType
+	switch dbChannel.CloseType {
+	case channeldb.CooperativeClose:
+		closeType = lnrpc.ChannelCloseSummary_COOPERATIVE_CLOSE
+	case channeldb.LocalForceClose:
+		closeType = lnrpc.ChannelCloseSummary_LOCAL_FORCE_CLOSE
+	case channeldb.RemoteForceClose:
+		closeType = lnrpc.ChannelCloseSummary_REMOTE_FORCE_CLOSE
+	case channeldb.BreachClose:
+		closeType = lnrpc.ChannelCloseSummary_BREACH_CLOSE
+	case channeldb.FundingCanceled:
+		closeType = lnrpc.ChannelCloseSummary_FUNDING_CANCELED
+	case channeldb.Abandoned:
+		closeType = lnrpc.ChannelCloseSummary_ABANDONED
+	}
+
+	return &lnrpc.ChannelCloseSummary{
+		Capacity:          int64(dbChannel.Capacity),
+		RemotePubkey:      nodeID,
+		CloseHeight:       dbChannel.CloseHeight,
+		CloseType:         closeType,
+		ChannelPoint:      dbChannel.ChanPoint.String(),
+		ChanId:            dbChannel.ShortChanID.ToUint64(),
+		SettledBalance:    int64(dbChannel.SettledBalance),
+		TimeLockedBalance: int64(dbChannel.TimeLockedBalance),
+		ChainHash:         dbChannel.ChainHash.String(),
+		ClosingTxHash:     dbChannel.ClosingTXID.String(),
+	}
+}
+
+// SubscribeChannelEvents returns a uni-directional stream (server -> client)
+// for notifying the client of newly active, inactive or closed channels.
+func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
+	updateStream lnrpc.Lightning_SubscribeChannelEventsServer) error {
+
+	channelEventSub, err := r.server.channelNotifier.SubscribeChannelEvents()
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the resources for the client is cleaned up once either
+	// the server, or client exits.
+	defer channelEventSub.Cancel()
+
+	graph := r.server.chanDB.ChannelGraph()
+
+	for {
+		select {
+		// A new update has been sent by the channel router, we'll
+		// marshal it into the form expected by the gRPC client, then
+		// send it off to the client(s).
+		case e := <-channelEventSub.Updates():
+			var update *lnrpc.ChannelEventUpdate
+			switch event := e.(type) {
+			case channelnotifier.OpenChannelEvent:
+				channel := createRPCOpenChannel(r, graph,
+					event.Channel, true)
+				update = &lnrpc.ChannelEventUpdate{
+					Type: lnrpc.ChannelEventUpdate_OPEN_CHANNEL,
+					Channel: &lnrpc.ChannelEventUpdate_OpenChannel{
+						OpenChannel: channel,
+					},
+				}
+			case channelnotifier.ClosedChannelEvent:
+				closedChannel := createRPCClosedChannel(event.CloseSummary)
+				update = &lnrpc.ChannelEventUpdate{
+					Type: lnrpc.ChannelEventUpdate_CLOSED_CHANNEL,
+					Channel: &lnrpc.ChannelEventUpdate_ClosedChannel{
+						ClosedChannel: closedChannel,
+					},
+				}
+			case channelnotifier.ActiveChannelEvent:
+				update = &lnrpc.ChannelEventUpdate{
+					Type: lnrpc.ChannelEventUpdate_ACTIVE_CHANNEL,
+					Channel: &lnrpc.ChannelEventUpdate_ActiveChannel{
+						ActiveChannel: &lnrpc.ChannelPoint{
+							FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+								FundingTxidBytes: event.ChannelPoint.Hash[:],
+							},
+							OutputIndex: event.ChannelPoint.Index,
+						},
+					},
+				}
+			case channelnotifier.InactiveChannelEvent:
+				update = &lnrpc.ChannelEventUpdate{
+					Type: lnrpc.ChannelEventUpdate_INACTIVE_CHANNEL,
+					Channel: &lnrpc.ChannelEventUpdate_InactiveChannel{
+						InactiveChannel: &lnrpc.ChannelPoint{
+							FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+								FundingTxidBytes: event.ChannelPoint.Hash[:],
+							},
+							OutputIndex: event.ChannelPoint.Index,
+						},
+					},
+				}
+			default:
+				return fmt.Errorf("unexpected channel event update: %v", event)
+			}
+
+			if err := updateStream.Send(update); err != nil {
+				return err
+			}
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// savePayment saves a successfully completed payment to the database for
+// historical record keeping. If the payment was split across several routes,
+// the path of the first is recorded, along with the fees of all routes
+// combined and the longest of their time locks.
+func savePayment(db *channeldb.DB, amount lnwire.MilliSatoshi,
+	preImage []byte, routes ...*route.Route) error {
+
+	route := routes[0]
+	paymentPath := make([][33]byte, len(route.Hops))
+	for i, hop := range route.Hops {
+		hopPub := hop.PubKeyBytes
+		copy(paymentPath[i][:], hopPub[:])
+	}
+
+	var (
+		fee      lnwire.MilliSatoshi
+		timeLock uint32
+	)
+	for _, rt := range routes {
+		fee += rt.TotalFees
+		if rt.TotalTimeLock > timeLock {
+			timeLock = rt.TotalTimeLock
+		}
+	}
+
+	payment := &channeldb.OutgoingPayment{
+		Invoice: channeldb.Invoice{
+			Terms: channeldb.ContractTerm{
+				Value: amount,
+			},
+			CreationDate: time.Now(),
+		},
+		Path:           paymentPath,
+		Fee:            fee,
+		TimeLockLength: timeLock,
+	}
+	copy(payment.PaymentPreimage[:], preImage)
+
+	return db.AddPayment(payment)
+}
+
+// validatePayReqExpiry checks if the passed payment request has expired. In
+// the case it has expired, an error will be returned.
+func validatePayReqExpiry(payReq *zpay32.Invoice) error {
+	expiry := payReq.Expiry()
+	validUntil := payReq.Timestamp.Add(expiry)
+	if time.Now().After(validUntil) {
+		return newCodedErrorf(lnrpc.ErrorCode_INVOICE_EXPIRED,
+			"invoice expired. Valid until %v", validUntil)
+	}
+
+	return nil
+}
+
+// paymentStream enables different types of payment streams, such as:
+// lnrpc.Lightning_SendPaymentServer and lnrpc.Lightning_SendToRouteServer to
+// execute sendPayment. We use this struct as a sort of bridge to enable code
+// re-use between SendPayment and SendToRoute.
+type paymentStream struct {
+	recv func() (*rpcPaymentRequest, error)
+	send func(*lnrpc.SendResponse) error
+}
+
+// rpcPaymentRequest wraps lnrpc.SendRequest so that routes from
+// lnrpc.SendToRouteRequest can be passed to sendPayment.
+type rpcPaymentRequest struct {
+	*lnrpc.SendRequest
+	routes []*route.Route
+}
+
+// calculateFeeLimit returns the fee limit in millisatoshis. If a percentage
+// based fee limit has been requested, we'll factor in the ratio provided with
+// the amount of the payment. If no fee limit has been requested, the default
+// fee limit of the given payments config is used.
+func calculateFeeLimit(feeLimit *lnrpc.FeeLimit, amount lnwire.MilliSatoshi,
+	payments *lncfg.Payments) lnwire.MilliSatoshi {
+
+	switch feeLimit.GetLimit().(type) {
+	case *lnrpc.FeeLimit_Fixed:
+		return lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(feeLimit.GetFixed()),
+		)
+	case *lnrpc.FeeLimit_Percent:
+		return amount * lnwire.MilliSatoshi(feeLimit.GetPercent()) / 100
+	default:
+		// If a fee limit was not specified, we'll use the configured
+		// default, which is the payment's amount unless set.
+		return payments.DefaultFeeLimit(amount)
+	}
+}
+
+// SendPayment dispatches a bi-directional streaming RPC for sending payments
+// through the Lightning Network. A single RPC invocation creates a persistent
+// bi-directional stream allowing clients to rapidly send payments through the
+// Lightning Network with a single persistent connection.
+func (r *rpcServer) SendPayment(stream lnrpc.Lightning_SendPaymentServer) error {
+	var lock sync.Mutex
+
+	return r.sendPayment(&paymentStream{
+		recv: func() (*rpcPaymentRequest, error) {
+			req, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+
+			return &rpcPaymentRequest{
+				SendRequest: req,
+			}, nil
+		},
+		send: func(r *lnrpc.SendResponse) error {
+			// Calling stream.Send concurrently is not safe.
+			lock.Lock()
+			defer lock.Unlock()
+			return stream.Send(r)
+		},
+	})
+}
+
+// SendToRoute dispatches a bi-directional streaming RPC for sending payments
+// through the Lightning Network via predefined routes passed in. A single RPC
+// invocation creates a persistent bi-directional stream allowing clients to
+// rapidly send payments through the Lightning Network with a single persistent
+// connection.
+func (r *rpcServer) SendToRoute(stream lnrpc.Lightning_SendToRouteServer) error {
+	var lock sync.Mutex
+
+	return r.sendPayment(&paymentStream{
+		recv: func() (*rpcPaymentRequest, error) {
+			req, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+
+			graph := r.server.chanDB.ChannelGraph()
+
+			return unmarshallSendToRouteRequest(req, graph)
+		},
+		send: func(r *lnrpc.SendResponse) error {
+			// Calling stream.Send concurrently is not safe.
+			lock.Lock()
+			defer lock.Unlock()
+			return stream.Send(r)
+		},
+	})
+}
+
+// unmarshallSendToRouteRequest unmarshalls an rpc sendtoroute request
+func unmarshallSendToRouteRequest(req *lnrpc.SendToRouteRequest,
+	graph *channeldb.ChannelGraph) (*rpcPaymentRequest, error) {
+
+	switch {
+	case len(req.Routes) == 0 && req.Route == nil:
+		return nil, fmt.Errorf("unable to send, no routes provided")
+	case len(req.Routes) > 0 && req.Route != nil:
+		return nil, fmt.Errorf("cannot use both route and routes field")
+	}
+
+	var routes []*route.Route
+	if len(req.Routes) > 0 {
+		routes = make([]*route.Route, len(req.Routes))
+		for i, rpcroute := range req.Routes {
+			route, err := unmarshallRoute(rpcroute, graph)
+			if err != nil {
+				return nil, err
+			}
+			routes[i] = route
+		}
+	} else {
+		rt, err := unmarshallRoute(req.Route, graph)
+		if err != nil {
+			return nil, err
+		}
+		routes = []*route.Route{rt}
+	}
+
+	return &rpcPaymentRequest{
+		SendRequest: &lnrpc.SendRequest{
+			PaymentHash:       req.PaymentHash,
+			PaymentHashString: req.PaymentHashString,
+		},
+		routes: routes,
+	}, nil
+}
+
+// rpcPaymentIntent is a small wrapper struct around the of values we can
+// receive from a client over RPC if they wish to send a payment. We'll either
+// extract these fields from a payment request (which may include routing
+// hints), or we'll get a fully populated route from the user that we'll pass
+// directly to the channel router for dispatching.
+type rpcPaymentIntent struct {
+	msat              lnwire.MilliSatoshi
+	feeLimit          lnwire.MilliSatoshi
+	cltvLimit         *uint32
+	dest              route.Vertex
+	rHash             [32]byte
+	cltvDelta         uint16
+	routeHints        [][]zpay32.HopHint
+	outgoingChannelID *uint64
+	maxShards         uint32
+	pathWeights       *routing.PathWeights
+
+	routes []*route.Route
+}
+
+// extractPaymentIntent attempts to parse the complete details required to
+// dispatch a client from the information presented by an RPC client. There are
+// three ways a client can specify their payment details: a payment request,
+// via manual details, or via a complete route.
+func extractPaymentIntent(rpcPayReq *rpcPaymentRequest) (rpcPaymentIntent, error) {
+	payIntent := rpcPaymentIntent{}
+
+	// If a route was specified, then we can use that directly.
+	if len(rpcPayReq.routes) != 0 {
+		// If the user is using the REST interface, then they'll be
+		// passing the payment hash as a hex encoded string.
+		if rpcPayReq.PaymentHashString != "" {
+			paymentHash, err := hex.DecodeString(
+				rpcPayReq.PaymentHashString,
+			)
+			if err != nil {
+				return payIntent, err
+			}
+
+			copy(payIntent.rHash[:], paymentHash)
+		} else {
+			copy(payIntent.rHash[:], rpcPayReq.PaymentHash)
+		}
+
+		payIntent.routes = rpcPayReq.routes
+		return payIntent, nil
+	}
+
+	// If there are no routes specified, pass along a outgoing channel
+	// restriction if specified.
+	if rpcPayReq.OutgoingChanId != 0 {
+		payIntent.outgoingChannelID = &rpcPayReq.OutgoingChanId
+	}
+
+	// Take cltv limit from request if set.
+	if rpcPayReq.CltvLimit != 0 {
+		payIntent.cltvLimit = &rpcPayReq.CltvLimit
+	}
+
+	// Payments that don't limit the number of shards they may be split
+	// into use the configured default.
+	payIntent.maxShards = rpcPayReq.MaxShards
+	if payIntent.maxShards == 0 {
+		payIntent.maxShards = cfg.Payments.MaxShards
+	}
+
+	// Payments that don't specify their own path finding weights use
+	// those of the router's configuration.
+	pathWeights, err := routerrpc.UnmarshallPathWeights(
+		rpcPayReq.PathWeights,
+	)
+	if err != nil {
+		return payIntent, err
+	}
+	payIntent.pathWeights = pathWeights
+
+	// If the payment request field isn't blank, then the details of the
+	// invoice are encoded entirely within the encoded payReq.  So we'll
+	// attempt to decode it, populating the payment accordingly.
+	if rpcPayReq.PaymentRequest != "" {
+		payReq, err := zpay32.Decode(
+			rpcPayReq.PaymentRequest, activeNetParams.Params,
+		)
+		if err != nil {
+			return payIntent, err
+		}
+
+		// Next, we'll ensure that this payreq hasn't already expired.
+		err = validatePayReqExpiry(payReq)
+		if err != nil {
+			return payIntent, err
+		}
+
+		// If the amount was not included in the invoice, then we let
+		// the payee specify the amount of satoshis they wish to send.
+		// We override the amount to pay with the amount provided from
+		// the payment request.
+		if payReq.MilliSat == nil {
+			if rpcPayReq.Amt == 0 {
+				return payIntent, errors.New("amount must be " +
+					"specified when paying a zero amount " +
+					"invoice")
+			}
+
+			payIntent.msat = lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(rpcPayReq.Amt),
+			)
+		} else {
+			payIntent.msat = *payReq.MilliSat
+		}
+
+		// Calculate the fee limit that should be used for this payment.
+		payIntent.feeLimit = calculateFeeLimit(
+			rpcPayReq.FeeLimit, payIntent.msat, cfg.Payments,
+		)
+
+		copy(payIntent.rHash[:], payReq.PaymentHash[:])
+		destKey := payReq.Destination.SerializeCompressed()
+		copy(payIntent.dest[:], destKey)
+		payIntent.cltvDelta = uint16(payReq.MinFinalCLTVExpiry())
+		payIntent.routeHints = payReq.RouteHints
+
+		return payIntent, nil
+	}
+
+	// At this point, a destination MUST be specified, so we'll convert it
+	// into the proper representation now. The destination will either be
+	// encoded as raw bytes, or via a hex string.
+	var pubBytes []byte
+	if len(rpcPayReq.Dest) != 0 {
+		pubBytes = rpcPayReq.Dest
+	} else {
+		var err error
+		pubBytes, err = hex.DecodeString(rpcPayReq.DestString)
+		if err != nil {
+			return payIntent, err
+		}
+	}
+	if len(pubBytes) != 33 {
+		return payIntent, errors.New("invalid key length")
+	}
+	copy(payIntent.dest[:], pubBytes)
+
+	// Otherwise, If the payment request field was not specified
+	// (and a custom route wasn't specified), construct the payment
+	// from the other fields.
+	payIntent.msat = lnwire.NewMSatFromSatoshis(
+		btcutil.Amount(rpcPayReq.Amt),
+	)
+
+	// Calculate the fee limit that should be used for this payment.
+	payIntent.feeLimit = calculateFeeLimit(
+		rpcPayReq.FeeLimit, payIntent.msat, cfg.Payments,
+	)
+
+	payIntent.cltvDelta = uint16(rpcPayReq.FinalCltvDelta)
+
+	// If the user is manually specifying payment details, then the payment
+	// hash may be encoded as a string.
+	switch {
+	case rpcPayReq.PaymentHashString != "":
+		paymentHash, err := hex.DecodeString(
+			rpcPayReq.PaymentHashString,
+		)
+		if err != nil {
+			return payIntent, err
+		}
+
+		copy(payIntent.rHash[:], paymentHash)
+
+	// If we're in debug HTLC mode, then all outgoing HTLCs will pay to the
+	// same debug rHash. Otherwise, we pay to the rHash specified within
+	// the RPC request.
+	case cfg.DebugHTLC && bytes.Equal(payIntent.rHash[:], zeroHash[:]):
+		copy(payIntent.rHash[:], invoices.DebugHash[:])
+
+	default:
+		copy(payIntent.rHash[:], rpcPayReq.PaymentHash)
+	}
+
+	// Currently, within the bootstrap phase of the network, we limit the
+	// largest payment size allotted to (2^32) - 1 mSAT or 4.29 million
+	// satoshis.
+	if payIntent.msat > maxPaymentMSat {
+		// In this case, we'll send an error to the caller, but
+		// continue our loop for the next payment.
+		return payIntent, fmt.Errorf("payment of %v is too large, "+
+			"max payment allowed is %v", payIntent.msat,
+			maxPaymentMSat)
+
+	}
+
+	return payIntent, nil
+}
+
+type paymentIntentResponse struct {
+	Route       *route.Route
+	ShardRoutes []*route.Route
+	Preimage    [32]byte
+	Err         error
+}
+
+// dispatchPaymentIntent attempts to fully dispatch an RPC payment intent.
+// We'll either pass the payment as a whole to the channel router, or give it a
+// pre-built route. The first error this method returns denotes if we were
+// unable to save the payment. The second error returned denotes if the payment
+// didn't succeed.
+func (r *rpcServer) dispatchPaymentIntent(
+	payIntent *rpcPaymentIntent) (*paymentIntentResponse, error) {
+
+	// Construct a payment request to send to the channel router. If the
+	// payment is successful, the route chosen will be returned. Otherwise,
+	// we'll get a non-nil error.
+	var (
+		preImage  [32]byte
+		routes    []*route.Route
+		routerErr error
+	)
+
+	// If a route was specified, then we'll pass the route directly to the
+	// router, otherwise we'll create a payment session to execute it.
+	if len(payIntent.routes) == 0 {
+		payment := &routing.LightningPayment{
+			Target:            payIntent.dest,
+			Amount:            payIntent.msat,
+			FeeLimit:          payIntent.feeLimit,
+			CltvLimit:         payIntent.cltvLimit,
+			PaymentHash:       payIntent.rHash,
+			RouteHints:        payIntent.routeHints,
+			OutgoingChannelID: payIntent.outgoingChannelID,
+		}
+
+		// If the final CLTV value was specified, then we'll use that
+		// rather than the default.
+		if payIntent.cltvDelta != 0 {
+			payment.FinalCLTVDelta = &payIntent.cltvDelta
+		}
+
+		// The payment may be split into shards, in which case a route
+		// is returned for each of the shards that were settled.
+		payment.MaxShards = payIntent.maxShards
+		payment.PathWeights = payIntent.pathWeights
+
+		preImage, routes, routerErr = r.server.chanRouter.
+			SendMultiPathPayment(payment)
+	} else {
+		payment := &routing.LightningPayment{
+			PaymentHash: payIntent.rHash,
+		}
+
+		var rt *route.Route
+		preImage, rt, routerErr = r.server.chanRouter.SendToRoute(
+			payIntent.routes, payment,
+		)
+		routes = []*route.Route{rt}
+	}
+
+	// If the route failed, then we'll return a nil save err, but a non-nil
+	// routing err.
+	if routerErr != nil {
+		return &paymentIntentResponse{
+			Err: routerErr,
+		}, nil
+	}
+
+	// If a route was used to complete this payment, then we'll need to
+	// compute the final amount sent
+	var amt lnwire.MilliSatoshi
+	if len(payIntent.routes) > 0 {
+		amt = routes[0].TotalAmount - routes[0].TotalFees
+	} else {
+		amt = payIntent.msat
+	}
+
+	// Save the completed payment to the database for record keeping
+	// purposes.
+	err := savePayment(r.server.chanDB, amt, preImage[:], routes...)
+	if err != nil {
+		// We weren't able to save the payment, so we return the save
+		// err, but a nil routing err.
+		return nil, err
+	}
+	r.server.recordPaymentSent(payIntent.rHash, routes...)
+
+	resp := &paymentIntentResponse{
+		Route:    routes[0],
+		Preimage: preImage,
+	}
+	if len(routes) > 1 {
+		resp.ShardRoutes = routes
+	}
+
+	return resp, nil
+}
+
+// marshallShardRoutes converts the routes of the settled shards of a split
+// payment into their RPC representation.
+func (r *rpcServer) marshallShardRoutes(
+	routes []*route.Route) []*lnrpc.Route {
+
+	var rpcRoutes []*lnrpc.Route
+	for _, rt := range routes {
+		rpcRoutes = append(rpcRoutes, r.routerBackend.MarshallRoute(rt))
+	}
+
+	return rpcRoutes
+}
+
+// sendPayment takes a paymentStream (a source of pre-built routes or payment
+// requests) and continually attempt to dispatch payment requests written to
+// the write end of the stream. Responses will also be streamed back to the
+// client via the write end of the stream. This method is by both SendToRoute
+// and SendPayment as the logic is virtually identical.
+func (r *rpcServer) sendPayment(stream *paymentStream) error {
+	payChan := make(chan *rpcPaymentIntent)
+	errChan := make(chan error, 1)
+
+	// We don't allow payments to be sent while the daemon itself is still
+	// syncing as we may be trying to sent a payment over a "stale"
+	// channel.
+	if !r.server.Started() {
+		return fmt.Errorf("chain backend is still syncing, server " +
+			"not active yet")
+	}
+
+	// TODO(roasbeef): check payment filter to see if already used?
+
+	// In order to limit the level of concurrency and prevent a client from
+	// attempting to OOM the server, we'll set up a semaphore to create an
+	// upper ceiling on the number of outstanding payments.
+	const numOutstandingPayments = 2000
+	htlcSema := make(chan struct{}, numOutstandingPayments)
+	for i := 0; i < numOutstandingPayments; i++ {
+		htlcSema <- struct{}{}
+	}
+
+	// Launch a new goroutine to handle reading new payment requests from
+	// the client. This way we can handle errors independently of blocking
+	// and waiting for the next payment request to come through.
+	reqQuit := make(chan struct{})
+	defer func() {
+		close(reqQuit)
+	}()
+
+	// TODO(joostjager): Callers expect result to come in in the same order
+	// as the request were sent, but this is far from guarantueed in the
+	// code below.
+	go func() {
+		for {
+			select {
+			case <-reqQuit:
+				return
+			case <-r.quit:
+				errChan <- nil
+				return
+			default:
+				// Receive the next pending payment within the
+				// stream sent by the client. If we read the
+				// EOF sentinel, then the client has closed the
+				// stream, and we can exit normally.
+				nextPayment, err := stream.recv()
+				if err == io.EOF {
+					errChan <- nil
+					return
+				} else if err != nil {
+					select {
+					case errChan <- err:
+					case <-reqQuit:
+						return
+					}
+					return
+				}
+
+				// Populate the next payment, either from the
+				// payment request, or from the explicitly set
+				// fields. If the payment proto wasn't well
+				// formed, then we'll send an error reply and
+				// wait for the next payment.
+				payIntent, err := extractPaymentIntent(nextPayment)
+				if err != nil {
+					err := stream.send(paymentErrorResponse(
+						err, payIntent.rHash[:],
+					))
+					if err != nil {
+						select {
+						case errChan <- err:
+						case <-reqQuit:
+							return
+						}
+					}
+					continue
+				}
+
+				// If the payment was well formed, then we'll
+				// send to the dispatch goroutine, or exit,
+				// which ever comes first
+				select {
+				case payChan <- &payIntent:
+				case <-reqQuit:
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		select {
+		case err := <-errChan:
+			return err
+
+		case payIntent := <-payChan:
+			// We launch a new goroutine to execute the current
+			// payment so we can continue to serve requests while
+			// this payment is being dispatched.
+			go func() {
+				// Attempt to grab a free semaphore slot, using
+				// a defer to eventually release the slot
+				// regardless of payment success.
+				<-htlcSema
+				defer func() {
+					htlcSema <- struct{}{}
+				}()
+
+				resp, saveErr := r.dispatchPaymentIntent(
+					payIntent,
+				)
+
+				switch {
+				// If we were unable to save the state of the
+				// payment, then we'll return the error to the
+				// user, and terminate.
+				case saveErr != nil:
+					errChan <- saveErr
+					return
+
+				// If we receive payment error than, instead of
+				// terminating the stream, send error response
+				// to the user.
+				case resp.Err != nil:
+					err := stream.send(paymentErrorResponse(
+						resp.Err, payIntent.rHash[:],
+					))
+					if err != nil {
+						errChan <- err
+					}
+					return
+				}
+
+				marshalledRouted := r.routerBackend.
+					MarshallRoute(resp.Route)
+
+				err := stream.send(&lnrpc.SendResponse{
+					PaymentHash:     payIntent.rHash[:],
+					PaymentPreimage: resp.Preimage[:],
+					PaymentRoute:    marshalledRouted,
+					ShardRoutes: r.marshallShardRoutes(
+						resp.ShardRoutes,
+					),
+				})
+				if err != nil {
+					errChan <- err
+					return
+				}
+			}()
+		}
+	}
+}
+
+// SendPaymentSync is the synchronous non-streaming version of SendPayment.
+// This RPC is intended to be consumed by clients of the REST proxy.
+// Additionally, this RPC expects the destination's public key and the payment
+// hash (if any) to be encoded as hex strings.
+func (r *rpcServer) SendPaymentSync(ctx context.Context,
+	nextPayment *lnrpc.SendRequest) (*lnrpc.SendResponse, error) {
+
+	return r.sendPaymentSync(ctx, &rpcPaymentRequest{
+		SendRequest: nextPayment,
+	})
+}
+
+// SendToRouteSync is the synchronous non-streaming version of SendToRoute.
+// This RPC is intended to be consumed by clients of the REST proxy.
+// Additionally, this RPC expects the payment hash (if any) to be encoded as
+// hex strings.
+func (r *rpcServer) SendToRouteSync(ctx context.Context,
+	req *lnrpc.SendToRouteRequest) (*lnrpc.SendResponse, error) {
+
+	if len(req.Routes) == 0 {
+		return nil, fmt.Errorf("unable to send, no routes provided")
+	}
+
+	graph := r.server.chanDB.ChannelGraph()
 
-		forceClose.PendingHtlcs = append(forceClose.PendingHtlcs, htlc)
+	paymentRequest, err := unmarshallSendToRouteRequest(req, graph)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return r.sendPaymentSync(ctx, paymentRequest)
 }
 
-// nurseryPopulateForceCloseResp populates the pending channels response
-// message with contract resolution information from utxonursery.
-func (r *rpcServer) nurseryPopulateForceCloseResp(chanPoint *wire.OutPoint,
-	currentHeight int32,
-	forceClose *lnrpc.PendingChannelsResponse_ForceClosedChannel) error {
+// sendPaymentSync is the synchronous variant of sendPayment. It will block and
+// wait until the payment has been fully completed.
+func (r *rpcServer) sendPaymentSync(ctx context.Context,
+	nextPayment *rpcPaymentRequest) (*lnrpc.SendResponse, error) {
 
-	// Query for the maturity state for this force closed channel. If we
-	// didn't have any time-locked outputs, then the nursery may not know of
-	// the contract.
-	nurseryInfo, err := r.server.utxoNursery.NurseryReport(chanPoint)
-	if err == ErrContractNotFound {
-		return nil
+	// We don't allow payments to be sent while the daemon itself is still
+	// syncing as we may be trying to sent a payment over a "stale"
+	// channel.
+	if !r.server.Started() {
+		return nil, fmt.Errorf("chain backend is still syncing, server " +
+			"not active yet")
 	}
+
+	// First we'll attempt to map the proto describing the next payment to
+	// an intent that we can pass to local sub-systems.
+	payIntent, err := extractPaymentIntent(nextPayment)
 	if err != nil {
-		return fmt.Errorf("unable to obtain "+
-			"nursery report for ChannelPoint(%v): %v",
-			chanPoint, err)
+		return nil, err
 	}
 
-	// If the nursery knows of this channel, then we can populate
-	// information detailing exactly how much funds are time locked and also
-	// the height in which we can ultimately sweep the funds into the
-	// wallet.
-	forceClose.LimboBalance = int64(nurseryInfo.limboBalance)
-	forceClose.RecoveredBalance = int64(nurseryInfo.recoveredBalance)
-	forceClose.MaturityHeight = nurseryInfo.maturityHeight
+	// With the payment validated, we'll now attempt to dispatch the
+	// payment.
+	resp, saveErr := r.dispatchPaymentIntent(&payIntent)
+	switch {
+	case saveErr != nil:
+		return nil, saveErr
 
-	// If the transaction has been confirmed, then we can compute how many
-	// blocks it has left.
-	if forceClose.MaturityHeight != 0 {
-		forceClose.BlocksTilMaturity =
-			int32(forceClose.MaturityHeight) -
-				currentHeight
+	case resp.Err != nil:
+		return paymentErrorResponse(resp.Err, payIntent.rHash[:]), nil
 	}
 
-	for _, htlcReport := range nurseryInfo.htlcs {
-		// TODO(conner) set incoming flag appropriately after handling
-		// incoming incubation
-		htlc := &lnrpc.PendingHTLC{
-			Incoming:       false,
-			Amount:         int64(htlcReport.amount),
-			Outpoint:       htlcReport.outpoint.String(),
-			MaturityHeight: htlcReport.maturityHeight,
-			Stage:          htlcReport.stage,
-		}
+	return &lnrpc.SendResponse{
+		PaymentHash:     payIntent.rHash[:],
+		PaymentPreimage: resp.Preimage[:],
+		PaymentRoute:    r.routerBackend.MarshallRoute(resp.Route),
+		ShardRoutes:     r.marshallShardRoutes(resp.ShardRoutes),
+	}, nil
+}
 
-		if htlc.MaturityHeight != 0 {
-			htlc.BlocksTilMaturity =
-				int32(htlc.MaturityHeight) -
-					currentHeight
-		}
+// AddInvoice attempts to add a new invoice to the invoice database. Any
+// duplicated invoices are rejected, therefore all invoices *must* have a
+// unique payment preimage.
+func (r *rpcServer) AddInvoice(ctx context.Context,
+	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
 
-		forceClose.PendingHtlcs = append(forceClose.PendingHtlcs,
-			htlc)
+	defaultDelta := cfg.Bitcoin.TimeLockDelta
+	if registeredChains.PrimaryChain() == litecoinfinanceChain {
+		defaultDelta = cfg.Litecoinfinance.TimeLockDelta
 	}
 
-	return nil
-}
-
-// ClosedChannels returns a list of all the channels have been closed.
-// This does not include channels that are still in the process of closing.
-func (r *rpcServer) ClosedChannels(ctx context.Context,
-	in *lnrpc.ClosedChannelsRequest) (*lnrpc.ClosedChannelsResponse,
-	error) {
+	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
+		AddInvoice:        r.server.invoices.AddInvoice,
+		IsChannelActive:   r.server.htlcSwitch.HasActiveLink,
+		ChainParams:       activeNetParams.Params,
+		NodeSigner:        r.server.nodeSigner,
+		MaxPaymentMSat:    maxPaymentMSat,
+		DefaultCLTVExpiry: defaultDelta,
+		ChanDB:            r.server.chanDB,
+	}
 
-	// Show all channels when no filter flags are set.
-	filterResults := in.Cooperative || in.LocalForce ||
-		in.RemoteForce || in.Breach || in.FundingCanceled ||
-		in.Abandoned
+	addInvoiceData := &invoicesrpc.AddInvoiceData{
+		Memo:            invoice.Memo,
+		Receipt:         invoice.Receipt,
+		Value:           btcutil.Amount(invoice.Value),
+		DescriptionHash: invoice.DescriptionHash,
+		Expiry:          invoice.Expiry,
+		FallbackAddr:    invoice.FallbackAddr,
+		CltvExpiry:      invoice.CltvExpiry,
+		Private:         invoice.Private,
+		FiatCurrency:    invoice.FiatCurrency,
+	}
 
-	resp := &lnrpc.ClosedChannelsResponse{}
+	if invoice.RPreimage != nil {
+		preimage, err := lntypes.MakePreimage(invoice.RPreimage)
+		if err != nil {
+			return nil, err
+		}
+		addInvoiceData.Preimage = &preimage
+	}
 
-	dbChannels, err := r.server.chanDB.FetchClosedChannels(false)
+	hash, dbInvoice, err := invoicesrpc.AddInvoice(
+		ctx, addInvoiceCfg, addInvoiceData,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	// In order to make the response easier to parse for clients, we'll
-	// sort the set of closed channels by their closing height before
-	// serializing the proto response.
-	sort.Slice(dbChannels, func(i, j int) bool {
-		return dbChannels[i].CloseHeight < dbChannels[j].CloseHeight
-	})
+	return &lnrpc.AddInvoiceResponse{
+		AddIndex:       dbInvoice.AddIndex,
+		PaymentRequest: string(dbInvoice.PaymentRequest),
+		RHash:          hash[:],
+		CreationFiat: invoicesrpc.CreateRPCFiatSnapshot(
+			dbInvoice.FiatCurrency, dbInvoice.CreationFiatRate,
+			dbInvoice.Terms.Value,
+		),
+	}, nil
+}
 
-	for _, dbChannel := range dbChannels {
-		if dbChannel.IsPending {
-			continue
-		}
+// LookupInvoice attempts to look up an invoice according to its payment hash.
+// The passed payment hash *must* be exactly 32 bytes, if not an error is
+// returned.
+func (r *rpcServer) LookupInvoice(ctx context.Context,
+	req *lnrpc.PaymentHash) (*lnrpc.Invoice, error) {
 
-		switch dbChannel.CloseType {
-		case channeldb.CooperativeClose:
-			if filterResults && !in.Cooperative {
-				continue
-			}
-		case channeldb.LocalForceClose:
-			if filterResults && !in.LocalForce {
-				continue
-			}
-		case channeldb.RemoteForceClose:
-			if filterResults && !in.RemoteForce {
-				continue
-			}
-		case channeldb.BreachClose:
-			if filterResults && !in.Breach {
-				continue
-			}
-		case channeldb.FundingCanceled:
-			if filterResults && !in.FundingCanceled {
-				continue
-			}
-		case channeldb.Abandoned:
-			if filterResults && !in.Abandoned {
-				continue
-			}
-		}
+	var (
+		payHash [32]byte
+		rHash   []byte
+		err     error
+	)
 
-		channel := createRPCClosedChannel(dbChannel)
-		resp.Channels = append(resp.Channels, channel)
+	// If the RHash as a raw string was provided, then decode that and use
+	// that directly. Otherwise, we use the raw bytes provided.
+	if req.RHashStr != "" {
+		rHash, err = hex.DecodeString(req.RHashStr)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		rHash = req.RHash
 	}
 
-	return resp, nil
-}
-
-// ListChannels returns a description of all the open channels that this node
-// is a participant in.
-func (r *rpcServer) ListChannels(ctx context.Context,
-	in *lnrpc.ListChannelsRequest) (*lnrpc.ListChannelsResponse, error) {
-
-	if in.ActiveOnly && in.InactiveOnly {
-		return nil, fmt.Errorf("either `active_only` or " +
-			"`inactive_only` can be set, but not both")
+	// Ensure that the payment hash is *exactly* 32-bytes.
+	if len(rHash) != 0 && len(rHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be exactly "+
+			"32 bytes, is instead %v", len(rHash))
 	}
+	copy(payHash[:], rHash)
 
-	if in.PublicOnly && in.PrivateOnly {
-		return nil, fmt.Errorf("either `public_only` or " +
-			"`private_only` can be set, but not both")
-	}
+	rpcsLog.Tracef("[lookupinvoice] searching for invoice %x", payHash[:])
 
-	resp := &lnrpc.ListChannelsResponse{}
+	invoice, _, err := r.server.invoices.LookupInvoice(payHash)
+	if err != nil {
+		return nil, err
+	}
 
-	graph := r.server.chanDB.ChannelGraph()
+	rpcsLog.Tracef("[lookupinvoice] located invoice %v",
+		newLogClosure(func() string {
+			return spew.Sdump(invoice)
+		}))
 
-	dbChannels, err := r.server.chanDB.FetchAllOpenChannels()
+	rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
+		&invoice, activeNetParams.Params,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	rpcsLog.Debugf("[listchannels] fetched %v channels from DB",
-		len(dbChannels))
-
-	for _, dbChannel := range dbChannels {
-		nodePub := dbChannel.IdentityPub
-		chanPoint := dbChannel.FundingOutpoint
+	return rpcInvoice, nil
+}
 
-		var peerOnline bool
-		if _, err := r.server.FindPeer(nodePub); err == nil {
-			peerOnline = true
-		}
+// ListInvoices returns a list of all the invoices currently stored within the
+// database. Any active debug invoices are ignored.
+func (r *rpcServer) ListInvoices(ctx context.Context,
+	req *lnrpc.ListInvoiceRequest) (*lnrpc.ListInvoiceResponse, error) {
 
-		channelID := lnwire.NewChanIDFromOutPoint(&chanPoint)
-		var linkActive bool
-		if link, err := r.server.htlcSwitch.GetLink(channelID); err == nil {
-			// A channel is only considered active if it is known
-			// by the switch *and* able to forward
-			// incoming/outgoing payments.
-			linkActive = link.EligibleToForward()
+	// If the number of invoices was not specified, then we'll default to
+	// returning the latest 100 invoices.
+	if req.NumMaxInvoices == 0 {
+		req.NumMaxInvoices = 100
+	}
+
+	// Next, we'll map the proto request into a format that is understood by
+	// the database.
+	q := channeldb.InvoiceQuery{
+		IndexOffset:    req.IndexOffset,
+		NumMaxInvoices: req.NumMaxInvoices,
+		PendingOnly:    req.PendingOnly,
+		Reversed:       req.Reversed,
+		MemoSubstring:  req.MemoSubstring,
+	}
+	for _, state := range req.States {
+		switch state {
+		case lnrpc.Invoice_OPEN:
+			q.States = append(q.States, channeldb.ContractOpen)
+		case lnrpc.Invoice_SETTLED:
+			q.States = append(q.States, channeldb.ContractSettled)
+		case lnrpc.Invoice_CANCELED:
+			q.States = append(q.States, channeldb.ContractCanceled)
+		case lnrpc.Invoice_ACCEPTED:
+			q.States = append(q.States, channeldb.ContractAccepted)
+		default:
+			return nil, fmt.Errorf("unknown invoice state %v", state)
 		}
+	}
+	if req.CreationDateStart != 0 {
+		q.CreatedAfter = time.Unix(req.CreationDateStart, 0)
+	}
+	if req.CreationDateEnd != 0 {
+		q.CreatedBefore = time.Unix(req.CreationDateEnd, 0)
+	}
 
-		// Next, we'll determine whether we should add this channel to
-		// our list depending on the type of channels requested to us.
-		isActive := peerOnline && linkActive
-		channel := createRPCOpenChannel(r, graph, dbChannel, isActive)
+	invoiceSlice, err := r.server.chanDB.QueryInvoices(q)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query invoices: %v", err)
+	}
 
-		// We'll only skip returning this channel if we were requested
-		// for a specific kind and this channel doesn't satisfy it.
-		switch {
-		case in.ActiveOnly && !isActive:
-			continue
-		case in.InactiveOnly && isActive:
-			continue
-		case in.PublicOnly && channel.Private:
-			continue
-		case in.PrivateOnly && !channel.Private:
-			continue
+	// Before returning the response, we'll need to convert each invoice
+	// into it's proto representation.
+	resp := &lnrpc.ListInvoiceResponse{
+		Invoices:         make([]*lnrpc.Invoice, len(invoiceSlice.Invoices)),
+		FirstIndexOffset: invoiceSlice.FirstIndexOffset,
+		LastIndexOffset:  invoiceSlice.LastIndexOffset,
+	}
+	for i, invoice := range invoiceSlice.Invoices {
+		resp.Invoices[i], err = invoicesrpc.CreateRPCInvoice(
+			&invoice, activeNetParams.Params,
+		)
+		if err != nil {
+			return nil, err
 		}
-
-		resp.Channels = append(resp.Channels, channel)
 	}
 
 	return resp, nil
 }
 
-// createRPCOpenChannel creates an *lnrpc.Channel from the *channeldb.Channel.
-func createRPCOpenChannel(r *rpcServer, graph *channeldb.ChannelGraph,
-	dbChannel *channeldb.OpenChannel, isActive bool) *lnrpc.Channel {
-
-	nodePub := dbChannel.IdentityPub
-	nodeID := hex.EncodeToString(nodePub.SerializeCompressed())
-	chanPoint := dbChannel.FundingOutpoint
+// SubscribeInvoices returns a uni-directional stream (server -> client) for
+// notifying the client of newly added/settled invoices.
+func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
+	updateStream lnrpc.Lightning_SubscribeInvoicesServer) error {
 
-	// Next, we'll determine whether the channel is public or not.
-	isPublic := dbChannel.ChannelFlags&lnwire.FFAnnounceChannel != 0
+	invoiceClient := r.server.invoices.SubscribeNotifications(
+		req.AddIndex, req.SettleIndex,
+	)
+	defer invoiceClient.Cancel()
 
-	// As this is required for display purposes, we'll calculate
-	// the weight of the commitment transaction. We also add on the
-	// estimated weight of the witness to calculate the weight of
-	// the transaction if it were to be immediately unilaterally
-	// broadcast.
-	localCommit := dbChannel.LocalCommitment
-	utx := btcutil.NewTx(localCommit.CommitTx)
-	commitBaseWeight := blockchain.GetTransactionWeight(utx)
-	commitWeight := commitBaseWeight + input.WitnessCommitmentTxWeight
+	for {
+		select {
+		case newInvoice := <-invoiceClient.NewInvoices:
+			rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
+				newInvoice, activeNetParams.Params,
+			)
+			if err != nil {
+				return err
+			}
 
-	localBalance := localCommit.LocalBalance
-	remoteBalance := localCommit.RemoteBalance
+			if err := updateStream.Send(rpcInvoice); err != nil {
+				return err
+			}
 
-	// As an artifact of our usage of mSAT internally, either party
-	// may end up in a state where they're holding a fractional
-	// amount of satoshis which can't be expressed within the
-	// actual commitment output. Since we round down when going
-	// from mSAT -> SAT, we may at any point be adding an
-	// additional SAT to miners fees. As a result, we display a
-	// commitment fee that accounts for this externally.
-	var sumOutputs btcutil.Amount
-	for _, txOut := range localCommit.CommitTx.TxOut {
-		sumOutputs += btcutil.Amount(txOut.Value)
-	}
-	externalCommitFee := dbChannel.Capacity - sumOutputs
+		case settledInvoice := <-invoiceClient.SettledInvoices:
+			rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
+				settledInvoice, activeNetParams.Params,
+			)
+			if err != nil {
+				return err
+			}
 
-	channel := &lnrpc.Channel{
-		Active:                isActive,
-		Private:               !isPublic,
-		RemotePubkey:          nodeID,
-		ChannelPoint:          chanPoint.String(),
-		ChanId:                dbChannel.ShortChannelID.ToUint64(),
-		Capacity:              int64(dbChannel.Capacity),
-		LocalBalance:          int64(localBalance.ToSatoshis()),
-		RemoteBalance:         int64(remoteBalance.ToSatoshis()),
-		CommitFee:             int64(externalCommitFee),
-		CommitWeight:          commitWeight,
-		FeePerKw:              int64(localCommit.FeePerKw),
-		TotalSatoshisSent:     int64(dbChannel.TotalMSatSent.ToSatoshis()),
-		TotalSatoshisReceived: int64(dbChannel.TotalMSatReceived.ToSatoshis()),
-		NumUpdates:            localCommit.CommitHeight,
-		PendingHtlcs:          make([]*lnrpc.HTLC, len(localCommit.Htlcs)),
-		CsvDelay:              uint32(dbChannel.LocalChanCfg.CsvDelay),
-		Initiator:             dbChannel.IsInitiator,
-		ChanStatusFlags:       dbChannel.ChanStatus().String(),
-	}
+			if err := updateStream.Send(rpcInvoice); err != nil {
+				return err
+			}
 
-	for i, htlc := range localCommit.Htlcs {
-		var rHash [32]byte
-		copy(rHash[:], htlc.RHash[:])
-		channel.PendingHtlcs[i] = &lnrpc.HTLC{
-			Incoming:         htlc.Incoming,
-			Amount:           int64(htlc.Amt.ToSatoshis()),
-			HashLock:         rHash[:],
-			ExpirationHeight: htlc.RefundTimeout,
+		case <-r.quit:
+			return nil
 		}
-
-		// Add the Pending Htlc Amount to UnsettledBalance field.
-		channel.UnsettledBalance += channel.PendingHtlcs[i].Amount
 	}
-
-	return channel
 }
 
-// createRPCClosedChannel creates an *lnrpc.ClosedChannelSummary from a
-// *channeldb.ChannelCloseSummary.
-func createRPCClosedChannel(
-	dbChannel *channeldb.ChannelCloseSummary) *lnrpc.ChannelCloseSummary {
+// DeleteInvoices deletes the invoices selected by the request from the
+// database. Accepted invoices are never deleted, as their HTLCs are still
+// being held.
+func (r *rpcServer) DeleteInvoices(ctx context.Context,
+	req *lnrpc.DeleteInvoicesRequest) (*lnrpc.DeleteInvoicesResponse,
+	error) {
 
-	nodePub := dbChannel.RemotePub
-	nodeID := hex.EncodeToString(nodePub.SerializeCompressed())
+	rpcsLog.Debugf("[DeleteInvoices]")
 
-	var closeType lnrpc.ChannelCloseSummary_ClosureType
-	switch dbChannel.CloseType {
-	case channeldb.CooperativeClose:
-		closeType = lnrpc.ChannelCloseSummary_COOPERATIVE_CLOSE
-	case channeldb.LocalForceClose:
-		closeType = lnrpc.ChannelCloseSummary_LOCAL_FORCE_CLOSE
-	case channeldb.RemoteForceClose:
-		closeType = lnrpc.ChannelCloseSummary_REMOTE_FORCE_CLOSE
-	case channeldb.BreachClose:
-		closeType = lnrpc.ChannelCloseSummary_BREACH_CLOSE
-	case channeldb.FundingCanceled:
-		closeType = lnrpc.ChannelCloseSummary_FUNDING_CANCELED
-	case channeldb.Abandoned:
-		closeType = lnrpc.ChannelCloseSummary_ABANDONED
+	if req.SettledBefore == 0 && !req.Canceled && req.OpenBefore == 0 {
+		return nil, errors.New("no invoices selected for deletion")
 	}
 
-	return &lnrpc.ChannelCloseSummary{
-		Capacity:          int64(dbChannel.Capacity),
-		RemotePubkey:      nodeID,
-		CloseHeight:       dbChannel.CloseHeight,
-		CloseType:         closeType,
-		ChannelPoint:      dbChannel.ChanPoint.String(),
-		ChanId:            dbChannel.ShortChanID.ToUint64(),
-		SettledBalance:    int64(dbChannel.SettledBalance),
-		TimeLockedBalance: int64(dbChannel.TimeLockedBalance),
-		ChainHash:         dbChannel.ChainHash.String(),
-		ClosingTxHash:     dbChannel.ClosingTXID.String(),
+	query := channeldb.InvoiceDeletionQuery{
+		Canceled: req.Canceled,
+	}
+	if req.SettledBefore != 0 {
+		query.SettledBefore = time.Unix(req.SettledBefore, 0)
+	}
+	if req.OpenBefore != 0 {
+		query.OpenBefore = time.Unix(req.OpenBefore, 0)
 	}
-}
-
-// SubscribeChannelEvents returns a uni-directional stream (server -> client)
-// for notifying the client of newly active, inactive or closed channels.
-func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
-	updateStream lnrpc.Lightning_SubscribeChannelEventsServer) error {
 
-	channelEventSub, err := r.server.channelNotifier.SubscribeChannelEvents()
+	numDeleted, err := r.server.chanDB.DeleteInvoices(query)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Ensure that the resources for the client is cleaned up once either
-	// the server, or client exits.
-	defer channelEventSub.Cancel()
+	rpcsLog.Infof("Deleted %v invoices", numDeleted)
 
-	graph := r.server.chanDB.ChannelGraph()
+	return &lnrpc.DeleteInvoicesResponse{
+		NumDeleted: uint64(numDeleted),
+	}, nil
+}
 
-	for {
-		select {
-		// A new update has been sent by the channel router, we'll
-		// marshal it into the form expected by the gRPC client, then
-		// send it off to the client(s).
-		case e := <-channelEventSub.Updates():
-			var update *lnrpc.ChannelEventUpdate
-			switch event := e.(type) {
-			case channelnotifier.OpenChannelEvent:
-				channel := createRPCOpenChannel(r, graph,
-					event.Channel, true)
-				update = &lnrpc.ChannelEventUpdate{
-					Type: lnrpc.ChannelEventUpdate_OPEN_CHANNEL,
-					Channel: &lnrpc.ChannelEventUpdate_OpenChannel{
-						OpenChannel: channel,
-					},
-				}
-			case channelnotifier.ClosedChannelEvent:
-				closedChannel := createRPCClosedChannel(event.CloseSummary)
-				update = &lnrpc.ChannelEventUpdate{
-					Type: lnrpc.ChannelEventUpdate_CLOSED_CHANNEL,
-					Channel: &lnrpc.ChannelEventUpdate_ClosedChannel{
-						ClosedChannel: closedChannel,
-					},
-				}
-			case channelnotifier.ActiveChannelEvent:
-				update = &lnrpc.ChannelEventUpdate{
-					Type: lnrpc.ChannelEventUpdate_ACTIVE_CHANNEL,
-					Channel: &lnrpc.ChannelEventUpdate_ActiveChannel{
-						ActiveChannel: &lnrpc.ChannelPoint{
-							FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
-								FundingTxidBytes: event.ChannelPoint.Hash[:],
-							},
-							OutputIndex: event.ChannelPoint.Index,
-						},
-					},
-				}
-			case channelnotifier.InactiveChannelEvent:
-				update = &lnrpc.ChannelEventUpdate{
-					Type: lnrpc.ChannelEventUpdate_INACTIVE_CHANNEL,
-					Channel: &lnrpc.ChannelEventUpdate_InactiveChannel{
-						InactiveChannel: &lnrpc.ChannelPoint{
-							FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
-								FundingTxidBytes: event.ChannelPoint.Hash[:],
-							},
-							OutputIndex: event.ChannelPoint.Index,
-						},
-					},
-				}
-			default:
-				return fmt.Errorf("unexpected channel event update: %v", event)
+// SubscribeTransactions creates a uni-directional stream (server -> client) in
+// which any newly discovered transactions relevant to the wallet are sent
+// over.
+func (r *rpcServer) SubscribeTransactions(req *lnrpc.GetTransactionsRequest,
+	updateStream lnrpc.Lightning_SubscribeTransactionsServer) error {
+
+	txClient, err := r.server.cc.wallet.SubscribeTransactions()
+	if err != nil {
+		return err
+	}
+	defer txClient.Cancel()
+
+	for {
+		select {
+		case tx := <-txClient.ConfirmedTransactions():
+			detail := &lnrpc.Transaction{
+				TxHash:           tx.Hash.String(),
+				Amount:           int64(tx.Value),
+				NumConfirmations: tx.NumConfirmations,
+				BlockHash:        tx.BlockHash.String(),
+				TimeStamp:        tx.Timestamp,
+				TotalFees:        tx.TotalFees,
+			}
+			if err := updateStream.Send(detail); err != nil {
+				return err
 			}
 
-			if err := updateStream.Send(update); err != nil {
+		case tx := <-txClient.UnconfirmedTransactions():
+			detail := &lnrpc.Transaction{
+				TxHash:    tx.Hash.String(),
+				Amount:    int64(tx.Value),
+				TimeStamp: tx.Timestamp,
+				TotalFees: tx.TotalFees,
+			}
+			if err := updateStream.Send(detail); err != nil {
 				return err
 			}
+
 		case <-r.quit:
 			return nil
 		}
 	}
 }
 
-// savePayment saves a successfully completed payment to the database for
-// historical record keeping.
-func (r *rpcServer) savePayment(route *route.Route,
-	amount lnwire.MilliSatoshi, preImage []byte) error {
+// GetTransactions returns a list of describing all the known transactions
+// relevant to the wallet.
+func (r *rpcServer) GetTransactions(ctx context.Context,
+	_ *lnrpc.GetTransactionsRequest) (*lnrpc.TransactionDetails, error) {
 
-	paymentPath := make([][33]byte, len(route.Hops))
-	for i, hop := range route.Hops {
-		hopPub := hop.PubKeyBytes
-		copy(paymentPath[i][:], hopPub[:])
+	// TODO(roasbeef): add pagination support
+	transactions, err := r.server.cc.wallet.ListTransactionDetails()
+	if err != nil {
+		return nil, err
 	}
 
-	payment := &channeldb.OutgoingPayment{
-		Invoice: channeldb.Invoice{
-			Terms: channeldb.ContractTerm{
-				Value: amount,
-			},
-			CreationDate: time.Now(),
-		},
-		Path:           paymentPath,
-		Fee:            route.TotalFees,
-		TimeLockLength: route.TotalTimeLock,
+	txDetails := &lnrpc.TransactionDetails{
+		Transactions: make([]*lnrpc.Transaction, len(transactions)),
 	}
-	copy(payment.PaymentPreimage[:], preImage)
+	for i, tx := range transactions {
+		var destAddresses []string
+		for _, destAddress := range tx.DestAddresses {
+			destAddresses = append(destAddresses, destAddress.EncodeAddress())
+		}
 
-	return r.server.chanDB.AddPayment(payment)
-}
+		// We also get unconfirmed transactions, so BlockHash can be
+		// nil.
+		blockHash := ""
+		if tx.BlockHash != nil {
+			blockHash = tx.BlockHash.String()
+		}
 
-// validatePayReqExpiry checks if the passed payment request has expired. In
-// the case it has expired, an error will be returned.
-func validatePayReqExpiry(payReq *zpay32.Invoice) error {
-	expiry := payReq.Expiry()
-	validUntil := payReq.Timestamp.Add(expiry)
-	if time.Now().After(validUntil) {
-		return fmt.Errorf("invoice expired. Valid until %v", validUntil)
+		txDetails.Transactions[i] = &lnrpc.Transaction{
+			TxHash:           tx.Hash.String(),
+			Amount:           int64(tx.Value),
+			NumConfirmations: tx.NumConfirmations,
+			BlockHash:        blockHash,
+			BlockHeight:      tx.BlockHeight,
+			TimeStamp:        tx.Timestamp,
+			TotalFees:        tx.TotalFees,
+			DestAddresses:    destAddresses,
+			Label:            tx.Label,
+		}
 	}
 
-	return nil
+	return txDetails, nil
 }
 
-// paymentStream enables different types of payment streams, such as:
-// lnrpc.Lightning_SendPaymentServer and lnrpc.Lightning_SendToRouteServer to
-// execute sendPayment. We use this struct as a sort of bridge to enable code
-// re-use between SendPayment and SendToRoute.
-type paymentStream struct {
-	recv func() (*rpcPaymentRequest, error)
-	send func(*lnrpc.SendResponse) error
-}
+// DescribeGraph returns a description of the latest graph state from the PoV
+// of the node. The graph information is partitioned into two components: all
+// the nodes/vertexes, and all the edges that connect the vertexes themselves.
+// As this is a directed graph, the edges also contain the node directional
+// specific routing policy which includes: the time lock delta, fee
+// information, etc.
+func (r *rpcServer) DescribeGraph(ctx context.Context,
+	req *lnrpc.ChannelGraphRequest) (*lnrpc.ChannelGraph, error) {
 
-// rpcPaymentRequest wraps lnrpc.SendRequest so that routes from
-// lnrpc.SendToRouteRequest can be passed to sendPayment.
-type rpcPaymentRequest struct {
-	*lnrpc.SendRequest
-	routes []*route.Route
+	resp := &lnrpc.ChannelGraph{}
+	includeUnannounced := req.IncludeUnannounced
+
+	// Obtain the pointer to the global singleton channel graph, this will
+	// provide a consistent view of the graph due to bolt db's
+	// transactional model.
+	graph := r.server.chanDB.ChannelGraph()
+
+	// First iterate through all the known nodes (connected or unconnected
+	// within the graph), collating their current state into the RPC
+	// response.
+	err := graph.ForEachNode(nil, func(_ kvdb.Tx, node *channeldb.LightningNode) error {
+		nodeAddrs := make([]*lnrpc.NodeAddress, 0)
+		for _, addr := range node.Addresses {
+			nodeAddr := &lnrpc.NodeAddress{
+				Network: addr.Network(),
+				Addr:    addr.String(),
+			}
+			nodeAddrs = append(nodeAddrs, nodeAddr)
+		}
+
+		nodeColor := fmt.Sprintf("#%02x%02x%02x", node.Color.R, node.Color.G, node.Color.B)
+		resp.Nodes = append(resp.Nodes, &lnrpc.LightningNode{
+			LastUpdate: uint32(node.LastUpdate.Unix()),
+			PubKey:     hex.EncodeToString(node.PubKeyBytes[:]),
+			Addresses:  nodeAddrs,
+			Alias:      node.Alias,
+			Color:      nodeColor,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Next, for each active channel we know of within the graph, create a
+	// similar response which details both the edge information as well as
+	// the routing policies of th nodes connecting the two edges.
+	err = graph.ForEachChannel(func(edgeInfo *channeldb.ChannelEdgeInfo,
+		c1, c2 *channeldb.ChannelEdgePolicy) error {
+
+		// Do not include unannounced channels unless specifically
+		// requested. Unannounced channels include both private channels as
+		// well as public channels whose authentication proof were not
+		// confirmed yet, hence were not announced.
+		if !includeUnannounced && edgeInfo.AuthProof == nil {
+			return nil
+		}
+
+		edge := marshalDbEdge(edgeInfo, c1, c2)
+		resp.Edges = append(resp.Edges, edge)
+
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+		return nil, err
+	}
+
+	return resp, nil
 }
 
-// calculateFeeLimit returns the fee limit in millisatoshis. If a percentage
-// based fee limit has been requested, we'll factor in the ratio provided with
-// the amount of the payment.
-func calculateFeeLimit(feeLimit *lnrpc.FeeLimit,
-	amount lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+func marshalDbEdge(edgeInfo *channeldb.ChannelEdgeInfo,
+	c1, c2 *channeldb.ChannelEdgePolicy) *lnrpc.ChannelEdge {
 
-	switch feeLimit.GetLimit().(type) {
-	case *lnrpc.FeeLimit_Fixed:
-		return lnwire.NewMSatFromSatoshis(
-			btcutil.Amount(feeLimit.GetFixed()),
-		)
-	case *lnrpc.FeeLimit_Percent:
-		return amount * lnwire.MilliSatoshi(feeLimit.GetPercent()) / 100
-	default:
-		// If a fee limit was not specified, we'll use the payment's
-		// amount as an upper bound in order to avoid payment attempts
-		// from incurring fees higher than the payment amount itself.
-		return amount
+	var (
+		lastUpdate int64
+	)
+
+	if c2 != nil {
+		lastUpdate = c2.LastUpdate.Unix()
+	}
+	if c1 != nil {
+		lastUpdate = c1.LastUpdate.Unix()
+	}
+
+	edge := &lnrpc.ChannelEdge{
+		ChannelId: edgeInfo.ChannelID,
+		ChanPoint: edgeInfo.ChannelPoint.String(),
+		// TODO(roasbeef): update should be on edge info itself
+		LastUpdate: uint32(lastUpdate),
+		Node1Pub:   hex.EncodeToString(edgeInfo.NodeKey1Bytes[:]),
+		Node2Pub:   hex.EncodeToString(edgeInfo.NodeKey2Bytes[:]),
+		Capacity:   int64(edgeInfo.Capacity),
+	}
+
+	if c1 != nil {
+		edge.Node1Policy = &lnrpc.RoutingPolicy{
+			TimeLockDelta:    uint32(c1.TimeLockDelta),
+			MinHtlc:          int64(c1.MinHTLC),
+			MaxHtlcMsat:      uint64(c1.MaxHTLC),
+			FeeBaseMsat:      int64(c1.FeeBaseMSat),
+			FeeRateMilliMsat: int64(c1.FeeProportionalMillionths),
+			Disabled:         c1.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
+		}
+	}
+
+	if c2 != nil {
+		edge.Node2Policy = &lnrpc.RoutingPolicy{
+			TimeLockDelta:    uint32(c2.TimeLockDelta),
+			MinHtlc:          int64(c2.MinHTLC),
+			MaxHtlcMsat:      uint64(c2.MaxHTLC),
+			FeeBaseMsat:      int64(c2.FeeBaseMSat),
+			FeeRateMilliMsat: int64(c2.FeeProportionalMillionths),
+			Disabled:         c2.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
+		}
 	}
+
+	return edge
 }
 
-// SendPayment dispatches a bi-directional streaming RPC for sending payments
-// through the Lightning Network. A single RPC invocation creates a persistent
-// bi-directional stream allowing clients to rapidly send payments through the
-// Lightning Network with a single persistent connection.
-func (r *rpcServer) SendPayment(stream lnrpc.Lightning_SendPaymentServer) error {
-	var lock sync.Mutex
+// GetChanInfo returns the latest authenticated network announcement for the
+// given channel identified by its channel ID: an 8-byte integer which uniquely
+// identifies the location of transaction's funding output within the block
+// chain.
+func (r *rpcServer) GetChanInfo(ctx context.Context,
+	in *lnrpc.ChanInfoRequest) (*lnrpc.ChannelEdge, error) {
 
-	return r.sendPayment(&paymentStream{
-		recv: func() (*rpcPaymentRequest, error) {
-			req, err := stream.Recv()
-			if err != nil {
-				return nil, err
-			}
+	graph := r.server.chanDB.ChannelGraph()
 
-			return &rpcPaymentRequest{
-				SendRequest: req,
-			}, nil
-		},
-		send: func(r *lnrpc.SendResponse) error {
-			// Calling stream.Send concurrently is not safe.
-			lock.Lock()
-			defer lock.Unlock()
-			return stream.Send(r)
-		},
-	})
+	edgeInfo, edge1, edge2, err := graph.FetchChannelEdgesByID(in.ChanId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the database's edge format into the network/RPC edge format
+	// which couples the edge itself along with the directional node
+	// routing policies of each node involved within the channel.
+	channelEdge := marshalDbEdge(edgeInfo, edge1, edge2)
+
+	return channelEdge, nil
 }
 
-// SendToRoute dispatches a bi-directional streaming RPC for sending payments
-// through the Lightning Network via predefined routes passed in. A single RPC
-// invocation creates a persistent bi-directional stream allowing clients to
-// rapidly send payments through the Lightning Network with a single persistent
-// connection.
-func (r *rpcServer) SendToRoute(stream lnrpc.Lightning_SendToRouteServer) error {
-	var lock sync.Mutex
+// GetNodeInfo returns the latest advertised and aggregate authenticated
+// channel information for the specified node identified by its public key.
+func (r *rpcServer) GetNodeInfo(ctx context.Context,
+	in *lnrpc.NodeInfoRequest) (*lnrpc.NodeInfo, error) {
 
-	return r.sendPayment(&paymentStream{
-		recv: func() (*rpcPaymentRequest, error) {
-			req, err := stream.Recv()
-			if err != nil {
-				return nil, err
-			}
+	graph := r.server.chanDB.ChannelGraph()
 
-			graph := r.server.chanDB.ChannelGraph()
+	// First, parse the hex-encoded public key into a full in-memory public
+	// key object we can work with for querying.
+	pubKeyBytes, err := hex.DecodeString(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
 
-			return unmarshallSendToRouteRequest(req, graph)
-		},
-		send: func(r *lnrpc.SendResponse) error {
-			// Calling stream.Send concurrently is not safe.
-			lock.Lock()
-			defer lock.Unlock()
-			return stream.Send(r)
-		},
-	})
-}
+	// With the public key decoded, attempt to fetch the node corresponding
+	// to this public key. If the node cannot be found, then an error will
+	// be returned.
+	node, err := graph.FetchLightningNode(pubKey)
+	if err != nil {
+		return nil, err
+	}
 
-// unmarshallSendToRouteRequest unmarshalls an rpc sendtoroute request
-func unmarshallSendToRouteRequest(req *lnrpc.SendToRouteRequest,
-	graph *channeldb.ChannelGraph) (*rpcPaymentRequest, error) {
+	// With the node obtained, we'll now iterate through all its out going
+	// edges to gather some basic statistics about its out going channels.
+	var (
+		numChannels   uint32
+		totalCapacity btcutil.Amount
+	)
+	if err := node.ForEachChannel(nil, func(_ kvdb.Tx, edge *channeldb.ChannelEdgeInfo,
+		_, _ *channeldb.ChannelEdgePolicy) error {
 
-	switch {
-	case len(req.Routes) == 0 && req.Route == nil:
-		return nil, fmt.Errorf("unable to send, no routes provided")
-	case len(req.Routes) > 0 && req.Route != nil:
-		return nil, fmt.Errorf("cannot use both route and routes field")
+		numChannels++
+		totalCapacity += edge.Capacity
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
-	var routes []*route.Route
-	if len(req.Routes) > 0 {
-		routes = make([]*route.Route, len(req.Routes))
-		for i, rpcroute := range req.Routes {
-			route, err := unmarshallRoute(rpcroute, graph)
-			if err != nil {
-				return nil, err
-			}
-			routes[i] = route
-		}
-	} else {
-		rt, err := unmarshallRoute(req.Route, graph)
-		if err != nil {
-			return nil, err
+	nodeAddrs := make([]*lnrpc.NodeAddress, 0)
+	for _, addr := range node.Addresses {
+		nodeAddr := &lnrpc.NodeAddress{
+			Network: addr.Network(),
+			Addr:    addr.String(),
 		}
-		routes = []*route.Route{rt}
+		nodeAddrs = append(nodeAddrs, nodeAddr)
 	}
+	// TODO(roasbeef): list channels as well?
 
-	return &rpcPaymentRequest{
-		SendRequest: &lnrpc.SendRequest{
-			PaymentHash:       req.PaymentHash,
-			PaymentHashString: req.PaymentHashString,
+	nodeColor := fmt.Sprintf("#%02x%02x%02x", node.Color.R, node.Color.G, node.Color.B)
+	return &lnrpc.NodeInfo{
+		Node: &lnrpc.LightningNode{
+			LastUpdate: uint32(node.LastUpdate.Unix()),
+			PubKey:     in.PubKey,
+			Addresses:  nodeAddrs,
+			Alias:      node.Alias,
+			Color:      nodeColor,
 		},
-		routes: routes,
+		NumChannels:   numChannels,
+		TotalCapacity: int64(totalCapacity),
 	}, nil
 }
 
-// rpcPaymentIntent is a small wrapper struct around the of values we can
-// receive from a client over RPC if they wish to send a payment. We'll either
-// extract these fields from a payment request (which may include routing
-// hints), or we'll get a fully populated route from the user that we'll pass
-// directly to the channel router for dispatching.
-type rpcPaymentIntent struct {
-	msat              lnwire.MilliSatoshi
-	feeLimit          lnwire.MilliSatoshi
-	cltvLimit         *uint32
-	dest              route.Vertex
-	rHash             [32]byte
-	cltvDelta         uint16
-	routeHints        [][]zpay32.HopHint
-	outgoingChannelID *uint64
+// QueryRoutes attempts to query the daemons' Channel Router for a possible
+// route to a target destination capable of carrying a specific amount of
+// satoshis within the route's flow. The retuned route contains the full
+// details required to craft and send an HTLC, also including the necessary
+// information that should be present within the Sphinx packet encapsulated
+// within the HTLC.
+//
+// TODO(roasbeef): should return a slice of routes in reality
+//  * create separate PR to send based on well formatted route
+func (r *rpcServer) QueryRoutes(ctx context.Context,
+	in *lnrpc.QueryRoutesRequest) (*lnrpc.QueryRoutesResponse, error) {
 
-	routes []*route.Route
+	return r.routerBackend.QueryRoutes(ctx, in)
 }
 
-// extractPaymentIntent attempts to parse the complete details required to
-// dispatch a client from the information presented by an RPC client. There are
-// three ways a client can specify their payment details: a payment request,
-// via manual details, or via a complete route.
-func extractPaymentIntent(rpcPayReq *rpcPaymentRequest) (rpcPaymentIntent, error) {
-	payIntent := rpcPaymentIntent{}
-
-	// If a route was specified, then we can use that directly.
-	if len(rpcPayReq.routes) != 0 {
-		// If the user is using the REST interface, then they'll be
-		// passing the payment hash as a hex encoded string.
-		if rpcPayReq.PaymentHashString != "" {
-			paymentHash, err := hex.DecodeString(
-				rpcPayReq.PaymentHashString,
-			)
-			if err != nil {
-				return payIntent, err
-			}
-
-			copy(payIntent.rHash[:], paymentHash)
-		} else {
-			copy(payIntent.rHash[:], rpcPayReq.PaymentHash)
-		}
+// unmarshallHopByChannelLookup unmarshalls an rpc hop for which the pub key is
+// not known. This function will query the channel graph with channel id to
+// retrieve both endpoints and determine the hop pubkey using the previous hop
+// pubkey. If the channel is unknown, an error is returned.
+func unmarshallHopByChannelLookup(graph *channeldb.ChannelGraph, hop *lnrpc.Hop,
+	prevPubKeyBytes [33]byte) (*route.Hop, error) {
 
-		payIntent.routes = rpcPayReq.routes
-		return payIntent, nil
+	// Discard edge policies, because they may be nil.
+	edgeInfo, _, _, err := graph.FetchChannelEdgesByID(hop.ChanId)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channel edges by "+
+			"channel ID %d: %v", hop.ChanId, err)
 	}
 
-	// If there are no routes specified, pass along a outgoing channel
-	// restriction if specified.
-	if rpcPayReq.OutgoingChanId != 0 {
-		payIntent.outgoingChannelID = &rpcPayReq.OutgoingChanId
+	var pubKeyBytes [33]byte
+	switch {
+	case prevPubKeyBytes == edgeInfo.NodeKey1Bytes:
+		pubKeyBytes = edgeInfo.NodeKey2Bytes
+	case prevPubKeyBytes == edgeInfo.NodeKey2Bytes:
+		pubKeyBytes = edgeInfo.NodeKey1Bytes
+	default:
+		return nil, fmt.Errorf("channel edge does not match expected node")
 	}
 
-	// Take cltv limit from request if set.
-	if rpcPayReq.CltvLimit != 0 {
-		payIntent.cltvLimit = &rpcPayReq.CltvLimit
+	return &route.Hop{
+		OutgoingTimeLock: hop.Expiry,
+		AmtToForward:     lnwire.MilliSatoshi(hop.AmtToForwardMsat),
+		PubKeyBytes:      pubKeyBytes,
+		ChannelID:        edgeInfo.ChannelID,
+	}, nil
+}
+
+// unmarshallKnownPubkeyHop unmarshalls an rpc hop that contains the hop pubkey.
+// The channel graph doesn't need to be queried because all information required
+// for sending the payment is present.
+func unmarshallKnownPubkeyHop(hop *lnrpc.Hop) (*route.Hop, error) {
+	pubKey, err := hex.DecodeString(hop.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode pubkey %s", hop.PubKey)
 	}
 
-	// If the payment request field isn't blank, then the details of the
-	// invoice are encoded entirely within the encoded payReq.  So we'll
-	// attempt to decode it, populating the payment accordingly.
-	if rpcPayReq.PaymentRequest != "" {
-		payReq, err := zpay32.Decode(
-			rpcPayReq.PaymentRequest, activeNetParams.Params,
-		)
-		if err != nil {
-			return payIntent, err
-		}
+	var pubKeyBytes [33]byte
+	copy(pubKeyBytes[:], pubKey)
 
-		// Next, we'll ensure that this payreq hasn't already expired.
-		err = validatePayReqExpiry(payReq)
-		if err != nil {
-			return payIntent, err
-		}
+	return &route.Hop{
+		OutgoingTimeLock: hop.Expiry,
+		AmtToForward:     lnwire.MilliSatoshi(hop.AmtToForwardMsat),
+		PubKeyBytes:      pubKeyBytes,
+		ChannelID:        hop.ChanId,
+	}, nil
+}
 
-		// If the amount was not included in the invoice, then we let
-		// the payee specify the amount of satoshis they wish to send.
-		// We override the amount to pay with the amount provided from
-		// the payment request.
-		if payReq.MilliSat == nil {
-			if rpcPayReq.Amt == 0 {
-				return payIntent, errors.New("amount must be " +
-					"specified when paying a zero amount " +
-					"invoice")
-			}
+// unmarshallHop unmarshalls an rpc hop that may or may not contain a node
+// pubkey.
+func unmarshallHop(graph *channeldb.ChannelGraph, hop *lnrpc.Hop,
+	prevNodePubKey [33]byte) (*route.Hop, error) {
 
-			payIntent.msat = lnwire.NewMSatFromSatoshis(
-				btcutil.Amount(rpcPayReq.Amt),
-			)
-		} else {
-			payIntent.msat = *payReq.MilliSat
-		}
+	if hop.PubKey == "" {
+		// If no pub key is given of the hop, the local channel
+		// graph needs to be queried to complete the information
+		// necessary for routing.
+		return unmarshallHopByChannelLookup(graph, hop, prevNodePubKey)
+	}
 
-		// Calculate the fee limit that should be used for this payment.
-		payIntent.feeLimit = calculateFeeLimit(
-			rpcPayReq.FeeLimit, payIntent.msat,
-		)
+	return unmarshallKnownPubkeyHop(hop)
+}
 
-		copy(payIntent.rHash[:], payReq.PaymentHash[:])
-		destKey := payReq.Destination.SerializeCompressed()
-		copy(payIntent.dest[:], destKey)
-		payIntent.cltvDelta = uint16(payReq.MinFinalCLTVExpiry())
-		payIntent.routeHints = payReq.RouteHints
+// unmarshallRoute unmarshalls an rpc route. For hops that don't specify a
+// pubkey, the channel graph is queried.
+func unmarshallRoute(rpcroute *lnrpc.Route,
+	graph *channeldb.ChannelGraph) (*route.Route, error) {
 
-		return payIntent, nil
+	sourceNode, err := graph.SourceNode()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch source node from graph "+
+			"while unmarshaling route. %v", err)
 	}
 
-	// At this point, a destination MUST be specified, so we'll convert it
-	// into the proper representation now. The destination will either be
-	// encoded as raw bytes, or via a hex string.
-	var pubBytes []byte
-	if len(rpcPayReq.Dest) != 0 {
-		pubBytes = rpcPayReq.Dest
-	} else {
-		var err error
-		pubBytes, err = hex.DecodeString(rpcPayReq.DestString)
+	prevNodePubKey := sourceNode.PubKeyBytes
+
+	hops := make([]*route.Hop, len(rpcroute.Hops))
+	for i, hop := range rpcroute.Hops {
+		routeHop, err := unmarshallHop(graph,
+			hop, prevNodePubKey)
 		if err != nil {
-			return payIntent, err
+			return nil, err
 		}
+
+		hops[i] = routeHop
+
+		prevNodePubKey = routeHop.PubKeyBytes
 	}
-	if len(pubBytes) != 33 {
-		return payIntent, errors.New("invalid key length")
-	}
-	copy(payIntent.dest[:], pubBytes)
 
-	// Otherwise, If the payment request field was not specified
-	// (and a custom route wasn't specified), construct the payment
-	// from the other fields.
-	payIntent.msat = lnwire.NewMSatFromSatoshis(
-		btcutil.Amount(rpcPayReq.Amt),
+	route, err := route.NewRouteFromHops(
+		lnwire.MilliSatoshi(rpcroute.TotalAmtMsat),
+		rpcroute.TotalTimeLock,
+		sourceNode.PubKeyBytes,
+		hops,
 	)
+	if err != nil {
+		return nil, err
+	}
 
-	// Calculate the fee limit that should be used for this payment.
-	payIntent.feeLimit = calculateFeeLimit(
-		rpcPayReq.FeeLimit, payIntent.msat,
-	)
+	return route, nil
+}
 
-	payIntent.cltvDelta = uint16(rpcPayReq.FinalCltvDelta)
+// GetNetworkInfo returns some basic stats about the known channel graph from
+// the PoV of the node.
+func (r *rpcServer) GetNetworkInfo(ctx context.Context,
+	_ *lnrpc.NetworkInfoRequest) (*lnrpc.NetworkInfo, error) {
 
-	// If the user is manually specifying payment details, then the payment
-	// hash may be encoded as a string.
-	switch {
-	case rpcPayReq.PaymentHashString != "":
-		paymentHash, err := hex.DecodeString(
-			rpcPayReq.PaymentHashString,
-		)
-		if err != nil {
-			return payIntent, err
-		}
+	graph := r.server.chanDB.ChannelGraph()
 
-		copy(payIntent.rHash[:], paymentHash)
+	var (
+		numNodes             uint32
+		numChannels          uint32
+		maxChanOut           uint32
+		totalNetworkCapacity btcutil.Amount
+		minChannelSize       btcutil.Amount = math.MaxInt64
+		maxChannelSize       btcutil.Amount
+		medianChanSize       btcutil.Amount
+	)
 
-	// If we're in debug HTLC mode, then all outgoing HTLCs will pay to the
-	// same debug rHash. Otherwise, we pay to the rHash specified within
-	// the RPC request.
-	case cfg.DebugHTLC && bytes.Equal(payIntent.rHash[:], zeroHash[:]):
-		copy(payIntent.rHash[:], invoices.DebugHash[:])
+	// We'll use this map to de-duplicate channels during our traversal.
+	// This is needed since channels are directional, so there will be two
+	// edges for each channel within the graph.
+	seenChans := make(map[uint64]struct{})
 
-	default:
-		copy(payIntent.rHash[:], rpcPayReq.PaymentHash)
-	}
+	// We also keep a list of all encountered capacities, in order to
+	// calculate the median channel size.
+	var allChans []btcutil.Amount
 
-	// Currently, within the bootstrap phase of the network, we limit the
-	// largest payment size allotted to (2^32) - 1 mSAT or 4.29 million
-	// satoshis.
-	if payIntent.msat > maxPaymentMSat {
-		// In this case, we'll send an error to the caller, but
-		// continue our loop for the next payment.
-		return payIntent, fmt.Errorf("payment of %v is too large, "+
-			"max payment allowed is %v", payIntent.msat,
-			maxPaymentMSat)
+	// We'll run through all the known nodes in the within our view of the
+	// network, tallying up the total number of nodes, and also gathering
+	// each node so we can measure the graph diameter and degree stats
+	// below.
+	if err := graph.ForEachNode(nil, func(tx kvdb.Tx, node *channeldb.LightningNode) error {
+		// Increment the total number of nodes with each iteration.
+		numNodes++
 
-	}
+		// For each channel we'll compute the out degree of each node,
+		// and also update our running tallies of the min/max channel
+		// capacity, as well as the total channel capacity. We pass
+		// through the db transaction from the outer view so we can
+		// re-use it within this inner view.
+		var outDegree uint32
+		if err := node.ForEachChannel(tx, func(_ kvdb.Tx,
+			edge *channeldb.ChannelEdgeInfo, _, _ *channeldb.ChannelEdgePolicy) error {
 
-	return payIntent, nil
-}
+			// Bump up the out degree for this node for each
+			// channel encountered.
+			outDegree++
 
-type paymentIntentResponse struct {
-	Route    *route.Route
-	Preimage [32]byte
-	Err      error
-}
+			// If we've already seen this channel, then we'll
+			// return early to ensure that we don't double-count
+			// stats.
+			if _, ok := seenChans[edge.ChannelID]; ok {
+				return nil
+			}
 
-// dispatchPaymentIntent attempts to fully dispatch an RPC payment intent.
-// We'll either pass the payment as a whole to the channel router, or give it a
-// pre-built route. The first error this method returns denotes if we were
-// unable to save the payment. The second error returned denotes if the payment
-// didn't succeed.
-func (r *rpcServer) dispatchPaymentIntent(
-	payIntent *rpcPaymentIntent) (*paymentIntentResponse, error) {
+			// Compare the capacity of this channel against the
+			// running min/max to see if we should update the
+			// extrema.
+			chanCapacity := edge.Capacity
+			if chanCapacity < minChannelSize {
+				minChannelSize = chanCapacity
+			}
+			if chanCapacity > maxChannelSize {
+				maxChannelSize = chanCapacity
+			}
 
-	// Construct a payment request to send to the channel router. If the
-	// payment is successful, the route chosen will be returned. Otherwise,
-	// we'll get a non-nil error.
-	var (
-		preImage  [32]byte
-		route     *route.Route
-		routerErr error
-	)
+			// Accumulate the total capacity of this channel to the
+			// network wide-capacity.
+			totalNetworkCapacity += chanCapacity
 
-	// If a route was specified, then we'll pass the route directly to the
-	// router, otherwise we'll create a payment session to execute it.
-	if len(payIntent.routes) == 0 {
-		payment := &routing.LightningPayment{
-			Target:            payIntent.dest,
-			Amount:            payIntent.msat,
-			FeeLimit:          payIntent.feeLimit,
-			CltvLimit:         payIntent.cltvLimit,
-			PaymentHash:       payIntent.rHash,
-			RouteHints:        payIntent.routeHints,
-			OutgoingChannelID: payIntent.outgoingChannelID,
-		}
+			numChannels++
 
-		// If the final CLTV value was specified, then we'll use that
-		// rather than the default.
-		if payIntent.cltvDelta != 0 {
-			payment.FinalCLTVDelta = &payIntent.cltvDelta
+			seenChans[edge.ChannelID] = struct{}{}
+			allChans = append(allChans, edge.Capacity)
+			return nil
+		}); err != nil {
+			return err
 		}
 
-		preImage, route, routerErr = r.server.chanRouter.SendPayment(
-			payment,
-		)
-	} else {
-		payment := &routing.LightningPayment{
-			PaymentHash: payIntent.rHash,
+		// Finally, if the out degree of this node is greater than what
+		// we've seen so far, update the maxChanOut variable.
+		if outDegree > maxChanOut {
+			maxChanOut = outDegree
 		}
 
-		preImage, route, routerErr = r.server.chanRouter.SendToRoute(
-			payIntent.routes, payment,
-		)
-	}
-
-	// If the route failed, then we'll return a nil save err, but a non-nil
-	// routing err.
-	if routerErr != nil {
-		return &paymentIntentResponse{
-			Err: routerErr,
-		}, nil
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
-	// If a route was used to complete this payment, then we'll need to
-	// compute the final amount sent
-	var amt lnwire.MilliSatoshi
-	if len(payIntent.routes) > 0 {
-		amt = route.TotalAmount - route.TotalFees
-	} else {
-		amt = payIntent.msat
-	}
+	// Find the median.
+	medianChanSize = autopilot.Median(allChans)
 
-	// Save the completed payment to the database for record keeping
-	// purposes.
-	err := r.savePayment(route, amt, preImage[:])
-	if err != nil {
-		// We weren't able to save the payment, so we return the save
-		// err, but a nil routing err.
-		return nil, err
+	// If we don't have any channels, then reset the minChannelSize to zero
+	// to avoid outputting NaN in encoded JSON.
+	if numChannels == 0 {
+		minChannelSize = 0
 	}
 
-	return &paymentIntentResponse{
-		Route:    route,
-		Preimage: preImage,
-	}, nil
-}
+	// TODO(roasbeef): graph diameter
 
-// sendPayment takes a paymentStream (a source of pre-built routes or payment
-// requests) and continually attempt to dispatch payment requests written to
-// the write end of the stream. Responses will also be streamed back to the
-// client via the write end of the stream. This method is by both SendToRoute
-// and SendPayment as the logic is virtually identical.
-func (r *rpcServer) sendPayment(stream *paymentStream) error {
-	payChan := make(chan *rpcPaymentIntent)
-	errChan := make(chan error, 1)
+	// TODO(roasbeef): also add oldest channel?
+	netInfo := &lnrpc.NetworkInfo{
+		MaxOutDegree:         maxChanOut,
+		AvgOutDegree:         float64(2*numChannels) / float64(numNodes),
+		NumNodes:             numNodes,
+		NumChannels:          numChannels,
+		TotalNetworkCapacity: int64(totalNetworkCapacity),
+		AvgChannelSize:       float64(totalNetworkCapacity) / float64(numChannels),
 
-	// We don't allow payments to be sent while the daemon itself is still
-	// syncing as we may be trying to sent a payment over a "stale"
-	// channel.
-	if !r.server.Started() {
-		return fmt.Errorf("chain backend is still syncing, server " +
-			"not active yet")
+		MinChannelSize:       int64(minChannelSize),
+		MaxChannelSize:       int64(maxChannelSize),
+		MedianChannelSizeSat: int64(medianChanSize),
 	}
 
-	// TODO(roasbeef): check payment filter to see if already used?
-
-	// In order to limit the level of concurrency and prevent a client from
-	// attempting to OOM the server, we'll set up a semaphore to create an
-	// upper ceiling on the number of outstanding payments.
-	const numOutstandingPayments = 2000
-	htlcSema := make(chan struct{}, numOutstandingPayments)
-	for i := 0; i < numOutstandingPayments; i++ {
-		htlcSema <- struct{}{}
+	// Similarly, if we don't have any channels, then we'll also set the
+	// average channel size to zero in order to avoid weird JSON encoding
+	// outputs.
+	if numChannels == 0 {
+		netInfo.AvgChannelSize = 0
 	}
 
-	// Launch a new goroutine to handle reading new payment requests from
-	// the client. This way we can handle errors independently of blocking
-	// and waiting for the next payment request to come through.
-	reqQuit := make(chan struct{})
-	defer func() {
-		close(reqQuit)
-	}()
+	return netInfo, nil
+}
 
-	// TODO(joostjager): Callers expect result to come in in the same order
-	// as the request were sent, but this is far from guarantueed in the
-	// code below.
-	go func() {
-		for {
-			select {
-			case <-reqQuit:
-				return
-			case <-r.quit:
-				errChan <- nil
-				return
-			default:
-				// Receive the next pending payment within the
-				// stream sent by the client. If we read the
-				// EOF sentinel, then the client has closed the
-				// stream, and we can exit normally.
-				nextPayment, err := stream.recv()
-				if err == io.EOF {
-					errChan <- nil
-					return
-				} else if err != nil {
-					select {
-					case errChan <- err:
-					case <-reqQuit:
-						return
-					}
-					return
-				}
+// GossipStats returns the gossip traffic exchanged with each of our peers over
+// the lifetime of their gossip syncers.
+func (r *rpcServer) GossipStats(ctx context.Context,
+	_ *lnrpc.GossipStatsRequest) (*lnrpc.GossipStatsResponse, error) {
 
-				// Populate the next payment, either from the
-				// payment request, or from the explicitly set
-				// fields. If the payment proto wasn't well
-				// formed, then we'll send an error reply and
-				// wait for the next payment.
-				payIntent, err := extractPaymentIntent(nextPayment)
-				if err != nil {
-					if err := stream.send(&lnrpc.SendResponse{
-						PaymentError: err.Error(),
-						PaymentHash:  payIntent.rHash[:],
-					}); err != nil {
-						select {
-						case errChan <- err:
-						case <-reqQuit:
-							return
-						}
-					}
-					continue
-				}
+	rpcsLog.Debugf("[gossipstats]")
 
-				// If the payment was well formed, then we'll
-				// send to the dispatch goroutine, or exit,
-				// which ever comes first
-				select {
-				case payChan <- &payIntent:
-				case <-reqQuit:
-					return
-				}
-			}
-		}
-	}()
+	syncMgr := r.server.authGossiper.SyncManager()
+	syncers := syncMgr.GossipSyncers()
 
-	for {
-		select {
-		case err := <-errChan:
-			return err
+	resp := &lnrpc.GossipStatsResponse{
+		Peers: make([]*lnrpc.PeerGossipStats, 0, len(syncers)),
+		GossipHorizonSeconds: uint64(
+			syncMgr.GossipHorizon() / time.Second,
+		),
+	}
+	for peer, syncer := range syncers {
+		syncType, err := marshallSyncType(syncer.SyncType())
+		if err != nil {
+			return nil, err
+		}
 
-		case payIntent := <-payChan:
-			// We launch a new goroutine to execute the current
-			// payment so we can continue to serve requests while
-			// this payment is being dispatched.
-			go func() {
-				// Attempt to grab a free semaphore slot, using
-				// a defer to eventually release the slot
-				// regardless of payment success.
-				<-htlcSema
-				defer func() {
-					htlcSema <- struct{}{}
-				}()
+		stats := syncer.Stats()
+		resp.Peers = append(resp.Peers, &lnrpc.PeerGossipStats{
+			PubKey:                hex.EncodeToString(peer[:]),
+			SyncType:              syncType,
+			AnnouncementsReceived: stats.AnnouncementsReceived,
+			UpdatesApplied:        stats.UpdatesApplied,
+			StaleUpdates:          stats.StaleUpdates,
+			QueriesServed:         stats.QueriesServed,
+			BytesSent:             stats.BytesSent,
+			BytesReceived:         stats.BytesReceived,
+		})
+	}
 
-				resp, saveErr := r.dispatchPaymentIntent(
-					payIntent,
-				)
+	// Order the peers such that those contributing the most to our graph
+	// are listed first.
+	sort.Slice(resp.Peers, func(i, j int) bool {
+		return resp.Peers[i].UpdatesApplied > resp.Peers[j].UpdatesApplied
+	})
 
-				switch {
-				// If we were unable to save the state of the
-				// payment, then we'll return the error to the
-				// user, and terminate.
-				case saveErr != nil:
-					errChan <- saveErr
-					return
+	return resp, nil
+}
 
-				// If we receive payment error than, instead of
-				// terminating the stream, send error response
-				// to the user.
-				case resp.Err != nil:
-					err := stream.send(&lnrpc.SendResponse{
-						PaymentError: resp.Err.Error(),
-						PaymentHash:  payIntent.rHash[:],
-					})
-					if err != nil {
-						errChan <- err
-					}
-					return
-				}
+// UpdateGossipHorizon changes how far back in time the graph updates we request
+// from the peers we receive new graph updates from should reach.
+func (r *rpcServer) UpdateGossipHorizon(ctx context.Context,
+	req *lnrpc.UpdateGossipHorizonRequest) (
+	*lnrpc.UpdateGossipHorizonResponse, error) {
 
-				marshalledRouted := r.routerBackend.
-					MarshallRoute(resp.Route)
+	rpcsLog.Debugf("[updategossiphorizon] horizon=%vs", req.HorizonSeconds)
 
-				err := stream.send(&lnrpc.SendResponse{
-					PaymentHash:     payIntent.rHash[:],
-					PaymentPreimage: resp.Preimage[:],
-					PaymentRoute:    marshalledRouted,
-				})
-				if err != nil {
-					errChan <- err
-					return
-				}
-			}()
-		}
+	// Any horizon reaching back beyond the unix epoch requests the full
+	// history, so we'll cap it to avoid overflowing the duration.
+	horizonSecs := req.HorizonSeconds
+	if horizonSecs > math.MaxInt64/uint64(time.Second) {
+		horizonSecs = math.MaxInt64 / uint64(time.Second)
 	}
-}
 
-// SendPaymentSync is the synchronous non-streaming version of SendPayment.
-// This RPC is intended to be consumed by clients of the REST proxy.
-// Additionally, this RPC expects the destination's public key and the payment
-// hash (if any) to be encoded as hex strings.
-func (r *rpcServer) SendPaymentSync(ctx context.Context,
-	nextPayment *lnrpc.SendRequest) (*lnrpc.SendResponse, error) {
+	r.server.authGossiper.SyncManager().SetGossipHorizon(
+		time.Duration(horizonSecs) * time.Second,
+	)
 
-	return r.sendPaymentSync(ctx, &rpcPaymentRequest{
-		SendRequest: nextPayment,
-	})
+	return &lnrpc.UpdateGossipHorizonResponse{}, nil
 }
 
-// SendToRouteSync is the synchronous non-streaming version of SendToRoute.
-// This RPC is intended to be consumed by clients of the REST proxy.
-// Additionally, this RPC expects the payment hash (if any) to be encoded as
-// hex strings.
-func (r *rpcServer) SendToRouteSync(ctx context.Context,
-	req *lnrpc.SendToRouteRequest) (*lnrpc.SendResponse, error) {
+// marshallSyncType converts the sync type of a gossip syncer into its RPC
+// representation.
+func marshallSyncType(syncType discovery.SyncerType) (lnrpc.Peer_SyncType,
+	error) {
 
-	if len(req.Routes) == 0 {
-		return nil, fmt.Errorf("unable to send, no routes provided")
+	switch syncType {
+	case discovery.ActiveSync:
+		return lnrpc.Peer_ACTIVE_SYNC, nil
+
+	case discovery.PassiveSync:
+		return lnrpc.Peer_PASSIVE_SYNC, nil
+
+	default:
+		return 0, fmt.Errorf("unhandled sync type %v", syncType)
 	}
+}
 
-	graph := r.server.chanDB.ChannelGraph()
+// ExportGraphSnapshot returns a compact binary snapshot of the node's entire
+// view of the public channel graph.
+func (r *rpcServer) ExportGraphSnapshot(ctx context.Context,
+	_ *lnrpc.ExportGraphSnapshotRequest) (*lnrpc.GraphSnapshot, error) {
 
-	paymentRequest, err := unmarshallSendToRouteRequest(req, graph)
-	if err != nil {
-		return nil, err
+	var b bytes.Buffer
+	if err := r.server.authGossiper.ExportGraphSnapshot(&b); err != nil {
+		return nil, fmt.Errorf("unable to export graph snapshot: %v",
+			err)
 	}
 
-	return r.sendPaymentSync(ctx, paymentRequest)
+	return &lnrpc.GraphSnapshot{
+		Snapshot: b.Bytes(),
+	}, nil
 }
 
-// sendPaymentSync is the synchronous variant of sendPayment. It will block and
-// wait until the payment has been fully completed.
-func (r *rpcServer) sendPaymentSync(ctx context.Context,
-	nextPayment *rpcPaymentRequest) (*lnrpc.SendResponse, error) {
+// ImportGraphSnapshot imports a graph snapshot previously created by
+// ExportGraphSnapshot. Every message within the snapshot is validated before
+// being added to the channel graph, and the initial historical gossip sync is
+// skipped.
+func (r *rpcServer) ImportGraphSnapshot(ctx context.Context,
+	in *lnrpc.GraphSnapshot) (*lnrpc.ImportGraphSnapshotResponse, error) {
 
-	// We don't allow payments to be sent while the daemon itself is still
-	// syncing as we may be trying to sent a payment over a "stale"
-	// channel.
-	if !r.server.Started() {
-		return nil, fmt.Errorf("chain backend is still syncing, server " +
-			"not active yet")
+	if len(in.Snapshot) == 0 {
+		return nil, fmt.Errorf("graph snapshot must be specified")
 	}
 
-	// First we'll attempt to map the proto describing the next payment to
-	// an intent that we can pass to local sub-systems.
-	payIntent, err := extractPaymentIntent(nextPayment)
+	numMsgs, err := r.server.authGossiper.ImportGraphSnapshot(
+		bytes.NewReader(in.Snapshot),
+	)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to import graph snapshot: %v",
+			err)
 	}
 
-	// With the payment validated, we'll now attempt to dispatch the
-	// payment.
-	resp, saveErr := r.dispatchPaymentIntent(&payIntent)
-	switch {
-	case saveErr != nil:
-		return nil, saveErr
-
-	case resp.Err != nil:
-		return &lnrpc.SendResponse{
-			PaymentError: resp.Err.Error(),
-			PaymentHash:  payIntent.rHash[:],
-		}, nil
-	}
+	rpcsLog.Infof("[importgraph] queued %v messages from graph snapshot",
+		numMsgs)
 
-	return &lnrpc.SendResponse{
-		PaymentHash:     payIntent.rHash[:],
-		PaymentPreimage: resp.Preimage[:],
-		PaymentRoute:    r.routerBackend.MarshallRoute(resp.Route),
+	return &lnrpc.ImportGraphSnapshotResponse{
+		NumMessages: uint32(numMsgs),
 	}, nil
 }
 
-// AddInvoice attempts to add a new invoice to the invoice database. Any
-// duplicated invoices are rejected, therefore all invoices *must* have a
-// unique payment preimage.
-func (r *rpcServer) AddInvoice(ctx context.Context,
-	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
+// ExportGraphDump returns the node's view of the public channel graph as a
+// GraphDump.
+func (r *rpcServer) ExportGraphDump(ctx context.Context,
+	_ *lnrpc.ExportGraphDumpRequest) (*lnrpc.GraphDump, error) {
 
-	defaultDelta := cfg.Bitcoin.TimeLockDelta
-	if registeredChains.PrimaryChain() == litecoinfinanceChain {
-		defaultDelta = cfg.Litecoinfinance.TimeLockDelta
+	dump, err := r.server.authGossiper.ExportGraphDump()
+	if err != nil {
+		return nil, fmt.Errorf("unable to export graph dump: %v", err)
 	}
 
-	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
-		AddInvoice:        r.server.invoices.AddInvoice,
-		IsChannelActive:   r.server.htlcSwitch.HasActiveLink,
-		ChainParams:       activeNetParams.Params,
-		NodeSigner:        r.server.nodeSigner,
-		MaxPaymentMSat:    maxPaymentMSat,
-		DefaultCLTVExpiry: defaultDelta,
-		ChanDB:            r.server.chanDB,
-	}
+	return &lnrpc.GraphDump{
+		ChannelAnnouncements: dump.ChannelAnnouncements,
+		ChannelUpdates:       dump.ChannelUpdates,
+		NodeAnnouncements:    dump.NodeAnnouncements,
+	}, nil
+}
 
-	addInvoiceData := &invoicesrpc.AddInvoiceData{
-		Memo:            invoice.Memo,
-		Receipt:         invoice.Receipt,
-		Value:           btcutil.Amount(invoice.Value),
-		DescriptionHash: invoice.DescriptionHash,
-		Expiry:          invoice.Expiry,
-		FallbackAddr:    invoice.FallbackAddr,
-		CltvExpiry:      invoice.CltvExpiry,
-		Private:         invoice.Private,
-	}
+// ImportGraphDump gathers the parts of a graph dump sent over the stream, and
+// imports the dump once the client closes its side of the stream. Every
+// message is validated before being added to the channel graph.
+func (r *rpcServer) ImportGraphDump(
+	stream lnrpc.Lightning_ImportGraphDumpServer) error {
 
-	if invoice.RPreimage != nil {
-		preimage, err := lntypes.MakePreimage(invoice.RPreimage)
+	var dump discovery.GraphDump
+	for {
+		part, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			return nil, err
+			return err
 		}
-		addInvoiceData.Preimage = &preimage
+
+		dump.ChannelAnnouncements = append(
+			dump.ChannelAnnouncements, part.ChannelAnnouncements...,
+		)
+		dump.ChannelUpdates = append(
+			dump.ChannelUpdates, part.ChannelUpdates...,
+		)
+		dump.NodeAnnouncements = append(
+			dump.NodeAnnouncements, part.NodeAnnouncements...,
+		)
 	}
 
-	hash, dbInvoice, err := invoicesrpc.AddInvoice(
-		ctx, addInvoiceCfg, addInvoiceData,
-	)
+	result, err := r.server.authGossiper.ImportGraphDump(&dump)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("unable to import graph dump: %v", err)
 	}
 
-	return &lnrpc.AddInvoiceResponse{
-		AddIndex:       dbInvoice.AddIndex,
-		PaymentRequest: string(dbInvoice.PaymentRequest),
-		RHash:          hash[:],
-	}, nil
+	rpcsLog.Infof("[importgraphdump] added %v channels, %v updates and "+
+		"%v nodes, rejected %v messages", result.NumChannels,
+		result.NumUpdates, result.NumNodes, result.NumRejected)
+
+	return stream.SendAndClose(&lnrpc.ImportGraphDumpResponse{
+		NumChannels: result.NumChannels,
+		NumUpdates:  result.NumUpdates,
+		NumNodes:    result.NumNodes,
+		NumKnown:    result.NumKnown,
+		NumRejected: result.NumRejected,
+	})
 }
 
-// LookupInvoice attempts to look up an invoice according to its payment hash.
-// The passed payment hash *must* be exactly 32 bytes, if not an error is
-// returned.
-func (r *rpcServer) LookupInvoice(ctx context.Context,
-	req *lnrpc.PaymentHash) (*lnrpc.Invoice, error) {
+// StopDaemon will send a shutdown request to the interrupt handler, triggering
+// a graceful shutdown of the daemon.
+func (r *rpcServer) StopDaemon(ctx context.Context,
+	_ *lnrpc.StopRequest) (*lnrpc.StopResponse, error) {
 
-	var (
-		payHash [32]byte
-		rHash   []byte
-		err     error
-	)
+	signal.RequestShutdown()
+	return &lnrpc.StopResponse{}, nil
+}
 
-	// If the RHash as a raw string was provided, then decode that and use
-	// that directly. Otherwise, we use the raw bytes provided.
-	if req.RHashStr != "" {
-		rHash, err = hex.DecodeString(req.RHashStr)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		rHash = req.RHash
+// RotateNodeKey requests the rotation of the node's identity key. The new key
+// is derived from the next index of the node key family, and only replaces the
+// current identity key once lnd is restarted.
+//
+// Channels can't be carried over to the new identity: our channel peers know
+// us by the old key, and the announcements of our public channels commit to
+// it. For this reason, the rotation is refused while any channels exist. The
+// intended migration path for an operator suspecting their key has been
+// exposed is therefore to close all channels, request the rotation, restart,
+// and publish the returned linkage proof so that others can verify the new
+// identity belongs to the same operator before re-opening channels.
+func (r *rpcServer) RotateNodeKey(ctx context.Context,
+	req *lnrpc.RotateNodeKeyRequest) (*lnrpc.RotateNodeKeyResponse, error) {
+
+	oldKey := r.server.identityPriv.PubKey()
+	currentPubKey, err := hex.DecodeString(req.CurrentPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode current pub key: %v",
+			err)
 	}
-
-	// Ensure that the payment hash is *exactly* 32-bytes.
-	if len(rHash) != 0 && len(rHash) != 32 {
-		return nil, fmt.Errorf("payment hash must be exactly "+
-			"32 bytes, is instead %v", len(rHash))
+	if !bytes.Equal(currentPubKey, oldKey.SerializeCompressed()) {
+		return nil, fmt.Errorf("current_pub_key doesn't match the " +
+			"identity key of this node")
 	}
-	copy(payHash[:], rHash)
-
-	rpcsLog.Tracef("[lookupinvoice] searching for invoice %x", payHash[:])
 
-	invoice, _, err := r.server.invoices.LookupInvoice(payHash)
+	channels, err := r.server.chanDB.FetchAllChannels()
 	if err != nil {
 		return nil, err
 	}
+	if len(channels) != 0 {
+		return nil, fmt.Errorf("unable to rotate node key with %d "+
+			"channels, all channels must be closed first",
+			len(channels))
+	}
 
-	rpcsLog.Tracef("[lookupinvoice] located invoice %v",
-		newLogClosure(func() string {
-			return spew.Sdump(invoice)
-		}))
+	oldIndex, err := r.server.chanDB.NodeKeyIndex()
+	if err != nil {
+		return nil, err
+	}
+	newKeyDesc := keychain.KeyDescriptor{
+		KeyLocator: keychain.KeyLocator{
+			Family: keychain.KeyFamilyNodeKey,
+			Index:  oldIndex + 1,
+		},
+	}
+	newPrivKey, err := r.server.cc.keyRing.DerivePrivKey(newKeyDesc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive new node key: %v",
+			err)
+	}
+	newKey := newPrivKey.PubKey()
 
-	rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
-		&invoice, activeNetParams.Params,
+	oldSig, newSig, err := netann.SignNodeKeyLinkage(
+		r.server.nodeSigner, netann.NewNodeSigner(newPrivKey),
+		oldKey, newKey,
 	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign node key linkage: %v",
+			err)
+	}
+
+	err = r.server.chanDB.AddNodeKeyRotation(&channeldb.NodeKeyRotation{
+		OldKeyIndex: oldIndex,
+		NewKeyIndex: oldIndex + 1,
+		OldPubKey:   oldKey,
+		NewPubKey:   newKey,
+		OldKeySig:   oldSig,
+		NewKeySig:   newSig,
+		RequestTime: time.Now(),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return rpcInvoice, nil
-}
+	rpcsLog.Warnf("Node key rotation requested, identity key will change "+
+		"from %x to %x on restart", oldKey.SerializeCompressed(),
+		newKey.SerializeCompressed())
 
-// ListInvoices returns a list of all the invoices currently stored within the
-// database. Any active debug invoices are ignored.
-func (r *rpcServer) ListInvoices(ctx context.Context,
-	req *lnrpc.ListInvoiceRequest) (*lnrpc.ListInvoiceResponse, error) {
+	return &lnrpc.RotateNodeKeyResponse{
+		NewPubKey: hex.EncodeToString(newKey.SerializeCompressed()),
+		OldKeySig: oldSig[:],
+		NewKeySig: newSig[:],
+	}, nil
+}
 
-	// If the number of invoices was not specified, then we'll default to
-	// returning the latest 100 invoices.
-	if req.NumMaxInvoices == 0 {
-		req.NumMaxInvoices = 100
-	}
+// ListNodeKeyRotations returns the audit log of all requested node identity
+// key rotations.
+func (r *rpcServer) ListNodeKeyRotations(ctx context.Context,
+	req *lnrpc.ListNodeKeyRotationsRequest) (
+	*lnrpc.ListNodeKeyRotationsResponse, error) {
 
-	// Next, we'll map the proto request into a format that is understood by
-	// the database.
-	q := channeldb.InvoiceQuery{
-		IndexOffset:    req.IndexOffset,
-		NumMaxInvoices: req.NumMaxInvoices,
-		PendingOnly:    req.PendingOnly,
-		Reversed:       req.Reversed,
-	}
-	invoiceSlice, err := r.server.chanDB.QueryInvoices(q)
+	rotations, err := r.server.chanDB.FetchNodeKeyRotations()
 	if err != nil {
-		return nil, fmt.Errorf("unable to query invoices: %v", err)
+		return nil, err
 	}
 
-	// Before returning the response, we'll need to convert each invoice
-	// into it's proto representation.
-	resp := &lnrpc.ListInvoiceResponse{
-		Invoices:         make([]*lnrpc.Invoice, len(invoiceSlice.Invoices)),
-		FirstIndexOffset: invoiceSlice.FirstIndexOffset,
-		LastIndexOffset:  invoiceSlice.LastIndexOffset,
+	resp := &lnrpc.ListNodeKeyRotationsResponse{
+		Rotations: make([]*lnrpc.NodeKeyRotation, 0, len(rotations)),
 	}
-	for i, invoice := range invoiceSlice.Invoices {
-		resp.Invoices[i], err = invoicesrpc.CreateRPCInvoice(
-			&invoice, activeNetParams.Params,
-		)
-		if err != nil {
-			return nil, err
+	for _, rotation := range rotations {
+		var status lnrpc.NodeKeyRotation_Status
+		switch rotation.Status {
+		case channeldb.NodeKeyRotationPending:
+			status = lnrpc.NodeKeyRotation_PENDING
+		case channeldb.NodeKeyRotationActivated:
+			status = lnrpc.NodeKeyRotation_ACTIVATED
+		case channeldb.NodeKeyRotationAborted:
+			status = lnrpc.NodeKeyRotation_ABORTED
+		default:
+			return nil, fmt.Errorf("unknown node key rotation "+
+				"status: %v", rotation.Status)
+		}
+
+		var resolveTime int64
+		if !rotation.ResolveTime.IsZero() {
+			resolveTime = rotation.ResolveTime.Unix()
 		}
+
+		resp.Rotations = append(resp.Rotations, &lnrpc.NodeKeyRotation{
+			Status: status,
+			OldPubKey: hex.EncodeToString(
+				rotation.OldPubKey.SerializeCompressed(),
+			),
+			NewPubKey: hex.EncodeToString(
+				rotation.NewPubKey.SerializeCompressed(),
+			),
+			OldKeyIndex: rotation.OldKeyIndex,
+			NewKeyIndex: rotation.NewKeyIndex,
+			OldKeySig:   rotation.OldKeySig[:],
+			NewKeySig:   rotation.NewKeySig[:],
+			RequestTime: rotation.RequestTime.Unix(),
+			ResolveTime: resolveTime,
+		})
 	}
 
 	return resp, nil
 }
 
-// SubscribeInvoices returns a uni-directional stream (server -> client) for
-// notifying the client of newly added/settled invoices.
-func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
-	updateStream lnrpc.Lightning_SubscribeInvoicesServer) error {
+// subsystemStatus returns the current status of the given subsystem.
+func (r *rpcServer) subsystemStatus(
+	subsystem lnrpc.Subsystem) (*lnrpc.SubsystemStatus, error) {
 
-	invoiceClient := r.server.invoices.SubscribeNotifications(
-		req.AddIndex, req.SettleIndex,
-	)
-	defer invoiceClient.Cancel()
+	status := &lnrpc.SubsystemStatus{
+		Subsystem: subsystem,
+	}
 
-	for {
-		select {
-		case newInvoice := <-invoiceClient.NewInvoices:
-			rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
-				newInvoice, activeNetParams.Params,
-			)
-			if err != nil {
-				return err
-			}
+	syncMgr := r.server.authGossiper.SyncManager()
+	switch subsystem {
+	case lnrpc.Subsystem_AUTOPILOT:
+		status.Available = true
+		status.Active = r.atpl.IsActive()
 
-			if err := updateStream.Send(rpcInvoice); err != nil {
-				return err
-			}
+	case lnrpc.Subsystem_GOSSIP_ACTIVE_SYNC:
+		status.Available = true
+		status.Active = !syncMgr.ActiveSyncPaused()
 
-		case settledInvoice := <-invoiceClient.SettledInvoices:
-			rpcInvoice, err := invoicesrpc.CreateRPCInvoice(
-				settledInvoice, activeNetParams.Params,
-			)
-			if err != nil {
-				return err
-			}
+	// Neither the watchtower server nor its client are run as part of lnd
+	// yet, so there's nothing we can control.
+	case lnrpc.Subsystem_WATCHTOWER, lnrpc.Subsystem_WATCHTOWER_CLIENT:
 
-			if err := updateStream.Send(rpcInvoice); err != nil {
-				return err
-			}
+	default:
+		return nil, fmt.Errorf("unknown subsystem: %v", subsystem)
+	}
 
-		case <-r.quit:
-			return nil
+	return status, nil
+}
+
+// ListSubsystems returns the runtime status of all subsystems that can be
+// individually stopped and started through ModifySubsystem.
+func (r *rpcServer) ListSubsystems(ctx context.Context,
+	req *lnrpc.ListSubsystemsRequest) (*lnrpc.ListSubsystemsResponse,
+	error) {
+
+	r.subsystemMtx.Lock()
+	defer r.subsystemMtx.Unlock()
+
+	resp := &lnrpc.ListSubsystemsResponse{}
+	for i := 0; i < len(lnrpc.Subsystem_name); i++ {
+		status, err := r.subsystemStatus(lnrpc.Subsystem(i))
+		if err != nil {
+			return nil, err
 		}
+		resp.Subsystems = append(resp.Subsystems, status)
 	}
+
+	return resp, nil
 }
 
-// SubscribeTransactions creates a uni-directional stream (server -> client) in
-// which any newly discovered transactions relevant to the wallet are sent
-// over.
-func (r *rpcServer) SubscribeTransactions(req *lnrpc.GetTransactionsRequest,
-	updateStream lnrpc.Lightning_SubscribeTransactionsServer) error {
+// ModifySubsystem stops or starts a single subsystem at runtime. Requests that
+// would result in an unsafe combination of running subsystems are rejected:
+// the autopilot agent picks channel candidates from our view of the graph, so
+// it can only run while we receive new graph updates from active syncers.
+func (r *rpcServer) ModifySubsystem(ctx context.Context,
+	req *lnrpc.ModifySubsystemRequest) (*lnrpc.ModifySubsystemResponse,
+	error) {
 
-	txClient, err := r.server.cc.wallet.SubscribeTransactions()
+	r.subsystemMtx.Lock()
+	defer r.subsystemMtx.Unlock()
+
+	status, err := r.subsystemStatus(req.Subsystem)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer txClient.Cancel()
 
-	for {
-		select {
-		case tx := <-txClient.ConfirmedTransactions():
-			detail := &lnrpc.Transaction{
-				TxHash:           tx.Hash.String(),
-				Amount:           int64(tx.Value),
-				NumConfirmations: tx.NumConfirmations,
-				BlockHash:        tx.BlockHash.String(),
-				TimeStamp:        tx.Timestamp,
-				TotalFees:        tx.TotalFees,
-			}
-			if err := updateStream.Send(detail); err != nil {
-				return err
-			}
+	switch {
+	case !status.Available:
+		return nil, fmt.Errorf("subsystem %v is not available",
+			req.Subsystem)
 
-		case tx := <-txClient.UnconfirmedTransactions():
-			detail := &lnrpc.Transaction{
-				TxHash:    tx.Hash.String(),
-				Amount:    int64(tx.Value),
-				TimeStamp: tx.Timestamp,
-				TotalFees: tx.TotalFees,
-			}
-			if err := updateStream.Send(detail); err != nil {
-				return err
-			}
+	// If the subsystem is already in the requested state, there's nothing
+	// left to do.
+	case status.Active == req.Enable:
+		return &lnrpc.ModifySubsystemResponse{Status: status}, nil
+	}
 
-		case <-r.quit:
-			return nil
+	rpcsLog.Warnf("[modifysubsystem] subsystem=%v, enable=%v",
+		req.Subsystem, req.Enable)
+
+	syncMgr := r.server.authGossiper.SyncManager()
+	switch req.Subsystem {
+	case lnrpc.Subsystem_AUTOPILOT:
+		if !req.Enable {
+			err = r.atpl.StopAgent()
+			break
 		}
-	}
-}
 
-// GetTransactions returns a list of describing all the known transactions
-// relevant to the wallet.
-func (r *rpcServer) GetTransactions(ctx context.Context,
-	_ *lnrpc.GetTransactionsRequest) (*lnrpc.TransactionDetails, error) {
+		if syncMgr.ActiveSyncPaused() {
+			return nil, fmt.Errorf("unable to start %v while %v is "+
+				"stopped, as it relies on an up to date graph",
+				lnrpc.Subsystem_AUTOPILOT,
+				lnrpc.Subsystem_GOSSIP_ACTIVE_SYNC)
+		}
+		err = r.atpl.StartAgent()
 
-	// TODO(roasbeef): add pagination support
-	transactions, err := r.server.cc.wallet.ListTransactionDetails()
+	case lnrpc.Subsystem_GOSSIP_ACTIVE_SYNC:
+		if req.Enable {
+			syncMgr.ResumeActiveSync()
+			break
+		}
+
+		if r.atpl.IsActive() {
+			return nil, fmt.Errorf("unable to stop %v while %v is "+
+				"running, as it relies on an up to date graph",
+				lnrpc.Subsystem_GOSSIP_ACTIVE_SYNC,
+				lnrpc.Subsystem_AUTOPILOT)
+		}
+		syncMgr.PauseActiveSync()
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	txDetails := &lnrpc.TransactionDetails{
-		Transactions: make([]*lnrpc.Transaction, len(transactions)),
+	status, err = r.subsystemStatus(req.Subsystem)
+	if err != nil {
+		return nil, err
 	}
-	for i, tx := range transactions {
-		var destAddresses []string
-		for _, destAddress := range tx.DestAddresses {
-			destAddresses = append(destAddresses, destAddress.EncodeAddress())
-		}
 
-		// We also get unconfirmed transactions, so BlockHash can be
-		// nil.
-		blockHash := ""
-		if tx.BlockHash != nil {
-			blockHash = tx.BlockHash.String()
-		}
+	return &lnrpc.ModifySubsystemResponse{Status: status}, nil
+}
 
-		txDetails.Transactions[i] = &lnrpc.Transaction{
-			TxHash:           tx.Hash.String(),
-			Amount:           int64(tx.Value),
-			NumConfirmations: tx.NumConfirmations,
-			BlockHash:        blockHash,
-			BlockHeight:      tx.BlockHeight,
-			TimeStamp:        tx.Timestamp,
-			TotalFees:        tx.TotalFees,
-			DestAddresses:    destAddresses,
-		}
+// marshallResourceBudget converts a resource budget to its RPC representation.
+func marshallResourceBudget(budget *resources.Budget) *lnrpc.ResourceBudget {
+	return &lnrpc.ResourceBudget{
+		ReadWorkers:      uint32(budget.ReadWorkers),
+		WriteWorkers:     uint32(budget.WriteWorkers),
+		SigWorkers:       uint32(budget.SigWorkers),
+		RejectCacheSize:  uint32(budget.RejectCacheSize),
+		ChannelCacheSize: uint32(budget.ChannelCacheSize),
+		GossipBatchSize:  uint32(budget.GossipBatchSize),
+		BatchCommitIntervalMs: int64(
+			budget.BatchCommitInterval / time.Millisecond,
+		),
 	}
+}
 
-	return txDetails, nil
+// GetResourceBudget returns the resource profile currently selected, along
+// with the bounds it places on worker pools, caches, gossip batch sizes and
+// database batch intervals.
+func (r *rpcServer) GetResourceBudget(ctx context.Context,
+	req *lnrpc.GetResourceBudgetRequest) (*lnrpc.GetResourceBudgetResponse,
+	error) {
+
+	profile, budget := r.server.resourceMgr.Budget()
+
+	return &lnrpc.GetResourceBudgetResponse{
+		Profile: string(profile),
+		Budget:  marshallResourceBudget(&budget),
+	}, nil
 }
 
-// DescribeGraph returns a description of the latest graph state from the PoV
-// of the node. The graph information is partitioned into two components: all
-// the nodes/vertexes, and all the edges that connect the vertexes themselves.
-// As this is a directed graph, the edges also contain the node directional
-// specific routing policy which includes: the time lock delta, fee
-// information, etc.
-func (r *rpcServer) DescribeGraph(ctx context.Context,
-	req *lnrpc.ChannelGraphRequest) (*lnrpc.ChannelGraph, error) {
+// SetResourceProfile selects a different resource profile at runtime. Worker
+// pools are sized at startup, so they only adopt the profile once lnd is
+// restarted with it configured.
+func (r *rpcServer) SetResourceProfile(ctx context.Context,
+	req *lnrpc.SetResourceProfileRequest) (
+	*lnrpc.SetResourceProfileResponse, error) {
 
-	resp := &lnrpc.ChannelGraph{}
-	includeUnannounced := req.IncludeUnannounced
+	rpcsLog.Infof("[setresourceprofile] profile=%v", req.Profile)
 
-	// Obtain the pointer to the global singleton channel graph, this will
-	// provide a consistent view of the graph due to bolt db's
-	// transactional model.
-	graph := r.server.chanDB.ChannelGraph()
+	budget, restartRequired, err := r.server.resourceMgr.SetProfile(
+		resources.Profile(req.Profile),
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	// First iterate through all the known nodes (connected or unconnected
-	// within the graph), collating their current state into the RPC
-	// response.
-	err := graph.ForEachNode(nil, func(_ *bbolt.Tx, node *channeldb.LightningNode) error {
-		nodeAddrs := make([]*lnrpc.NodeAddress, 0)
-		for _, addr := range node.Addresses {
-			nodeAddr := &lnrpc.NodeAddress{
-				Network: addr.Network(),
-				Addr:    addr.String(),
-			}
-			nodeAddrs = append(nodeAddrs, nodeAddr)
-		}
+	return &lnrpc.SetResourceProfileResponse{
+		Budget:          marshallResourceBudget(budget),
+		RestartRequired: restartRequired,
+	}, nil
+}
 
-		nodeColor := fmt.Sprintf("#%02x%02x%02x", node.Color.R, node.Color.G, node.Color.B)
-		resp.Nodes = append(resp.Nodes, &lnrpc.LightningNode{
-			LastUpdate: uint32(node.LastUpdate.Unix()),
-			PubKey:     hex.EncodeToString(node.PubKeyBytes[:]),
-			Addresses:  nodeAddrs,
-			Alias:      node.Alias,
-			Color:      nodeColor,
-		})
+// SubscribeChannelGraph launches a streaming RPC that allows the caller to
+// receive notifications upon any changes the channel graph topology from the
+// review of the responding node. Events notified include: new nodes coming
+// online, nodes updating their authenticated attributes, new channels being
+// advertised, updates in the routing policy for a directional channel edge,
+// and finally when prior channels are closed on-chain or pruned as zombies.
+func (r *rpcServer) SubscribeChannelGraph(req *lnrpc.GraphTopologySubscription,
+	updateStream lnrpc.Lightning_SubscribeChannelGraphServer) error {
 
-		return nil
-	})
+	// First, we start by subscribing to a new intent to receive
+	// notifications from the channel router.
+	client, err := r.server.chanRouter.SubscribeTopology()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Next, for each active channel we know of within the graph, create a
-	// similar response which details both the edge information as well as
-	// the routing policies of th nodes connecting the two edges.
-	err = graph.ForEachChannel(func(edgeInfo *channeldb.ChannelEdgeInfo,
-		c1, c2 *channeldb.ChannelEdgePolicy) error {
+	// Ensure that the resources for the topology update client is cleaned
+	// up once either the server, or client exists.
+	defer client.Cancel()
 
-		// Do not include unannounced channels unless specifically
-		// requested. Unannounced channels include both private channels as
-		// well as public channels whose authentication proof were not
-		// confirmed yet, hence were not announced.
-		if !includeUnannounced && edgeInfo.AuthProof == nil {
-			return nil
-		}
+	for {
+		select {
 
-		edge := marshalDbEdge(edgeInfo, c1, c2)
-		resp.Edges = append(resp.Edges, edge)
+		// A new update has been sent by the channel router, we'll
+		// marshal it into the form expected by the gRPC client, then
+		// send it off.
+		case topChange, ok := <-client.TopologyChanges:
+			// If the second value from the channel read is nil,
+			// then this means that the channel router is exiting
+			// or the notification client was cancelled. So we'll
+			// exit early.
+			if !ok {
+				return errors.New("server shutting down")
+			}
 
-		return nil
-	})
-	if err != nil && err != channeldb.ErrGraphNoEdgesFound {
-		return nil, err
-	}
+			// Convert the struct from the channel router into the
+			// form expected by the gRPC service then send it off
+			// to the client.
+			graphUpdate := marshallTopologyChange(topChange)
+			if err := updateStream.Send(graphUpdate); err != nil {
+				return err
+			}
 
-	return resp, nil
+		// The server is quitting, so we'll exit immediately. Returning
+		// nil will close the clients read end of the stream.
+		case <-r.quit:
+			return nil
+		}
+	}
 }
 
-func marshalDbEdge(edgeInfo *channeldb.ChannelEdgeInfo,
-	c1, c2 *channeldb.ChannelEdgePolicy) *lnrpc.ChannelEdge {
-
-	var (
-		lastUpdate int64
-	)
+// marshallTopologyChange performs a mapping from the topology change struct
+// returned by the router to the form of notifications expected by the current
+// gRPC service.
+func marshallTopologyChange(topChange *routing.TopologyChange) *lnrpc.GraphTopologyUpdate {
 
-	if c2 != nil {
-		lastUpdate = c2.LastUpdate.Unix()
-	}
-	if c1 != nil {
-		lastUpdate = c1.LastUpdate.Unix()
+	// encodeKey is a simple helper function that converts a live public
+	// key into a hex-encoded version of the compressed serialization for
+	// the public key.
+	encodeKey := func(k *btcec.PublicKey) string {
+		return hex.EncodeToString(k.SerializeCompressed())
 	}
 
-	edge := &lnrpc.ChannelEdge{
-		ChannelId: edgeInfo.ChannelID,
-		ChanPoint: edgeInfo.ChannelPoint.String(),
-		// TODO(roasbeef): update should be on edge info itself
-		LastUpdate: uint32(lastUpdate),
-		Node1Pub:   hex.EncodeToString(edgeInfo.NodeKey1Bytes[:]),
-		Node2Pub:   hex.EncodeToString(edgeInfo.NodeKey2Bytes[:]),
-		Capacity:   int64(edgeInfo.Capacity),
-	}
+	nodeUpdates := make([]*lnrpc.NodeUpdate, len(topChange.NodeUpdates))
+	for i, nodeUpdate := range topChange.NodeUpdates {
+		addrs := make([]string, len(nodeUpdate.Addresses))
+		for i, addr := range nodeUpdate.Addresses {
+			addrs[i] = addr.String()
+		}
 
-	if c1 != nil {
-		edge.Node1Policy = &lnrpc.RoutingPolicy{
-			TimeLockDelta:    uint32(c1.TimeLockDelta),
-			MinHtlc:          int64(c1.MinHTLC),
-			MaxHtlcMsat:      uint64(c1.MaxHTLC),
-			FeeBaseMsat:      int64(c1.FeeBaseMSat),
-			FeeRateMilliMsat: int64(c1.FeeProportionalMillionths),
-			Disabled:         c1.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
+		nodeUpdates[i] = &lnrpc.NodeUpdate{
+			Addresses:      addrs,
+			IdentityKey:    encodeKey(nodeUpdate.IdentityKey),
+			GlobalFeatures: nodeUpdate.GlobalFeatures,
+			Alias:          nodeUpdate.Alias,
 		}
 	}
 
-	if c2 != nil {
-		edge.Node2Policy = &lnrpc.RoutingPolicy{
-			TimeLockDelta:    uint32(c2.TimeLockDelta),
-			MinHtlc:          int64(c2.MinHTLC),
-			MaxHtlcMsat:      uint64(c2.MaxHTLC),
-			FeeBaseMsat:      int64(c2.FeeBaseMSat),
-			FeeRateMilliMsat: int64(c2.FeeProportionalMillionths),
-			Disabled:         c2.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
+	newChans := make([]*lnrpc.NewChannelUpdate, len(topChange.NewChannels))
+	for i, newChan := range topChange.NewChannels {
+		newChans[i] = &lnrpc.NewChannelUpdate{
+			ChanId: newChan.ChanID,
+			ChanPoint: &lnrpc.ChannelPoint{
+				FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+					FundingTxidBytes: newChan.ChanPoint.Hash[:],
+				},
+				OutputIndex: newChan.ChanPoint.Index,
+			},
+			Capacity: int64(newChan.Capacity),
+			Node1Pub: encodeKey(newChan.Node1),
+			Node2Pub: encodeKey(newChan.Node2),
 		}
 	}
 
-	return edge
-}
-
-// GetChanInfo returns the latest authenticated network announcement for the
-// given channel identified by its channel ID: an 8-byte integer which uniquely
-// identifies the location of transaction's funding output within the block
-// chain.
-func (r *rpcServer) GetChanInfo(ctx context.Context,
-	in *lnrpc.ChanInfoRequest) (*lnrpc.ChannelEdge, error) {
-
-	graph := r.server.chanDB.ChannelGraph()
-
-	edgeInfo, edge1, edge2, err := graph.FetchChannelEdgesByID(in.ChanId)
-	if err != nil {
-		return nil, err
+	channelUpdates := make([]*lnrpc.ChannelEdgeUpdate, len(topChange.ChannelEdgeUpdates))
+	for i, channelUpdate := range topChange.ChannelEdgeUpdates {
+		channelUpdates[i] = &lnrpc.ChannelEdgeUpdate{
+			ChanId: channelUpdate.ChanID,
+			ChanPoint: &lnrpc.ChannelPoint{
+				FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+					FundingTxidBytes: channelUpdate.ChanPoint.Hash[:],
+				},
+				OutputIndex: channelUpdate.ChanPoint.Index,
+			},
+			Capacity: int64(channelUpdate.Capacity),
+			RoutingPolicy: &lnrpc.RoutingPolicy{
+				TimeLockDelta:    uint32(channelUpdate.TimeLockDelta),
+				MinHtlc:          int64(channelUpdate.MinHTLC),
+				MaxHtlcMsat:      uint64(channelUpdate.MaxHTLC),
+				FeeBaseMsat:      int64(channelUpdate.BaseFee),
+				FeeRateMilliMsat: int64(channelUpdate.FeeRate),
+				Disabled:         channelUpdate.Disabled,
+			},
+			AdvertisingNode: encodeKey(channelUpdate.AdvertisingNode),
+			ConnectingNode:  encodeKey(channelUpdate.ConnectingNode),
+		}
 	}
 
-	// Convert the database's edge format into the network/RPC edge format
-	// which couples the edge itself along with the directional node
-	// routing policies of each node involved within the channel.
-	channelEdge := marshalDbEdge(edgeInfo, edge1, edge2)
+	closedChans := make([]*lnrpc.ClosedChannelUpdate, len(topChange.ClosedChannels))
+	for i, closedChan := range topChange.ClosedChannels {
+		closedChans[i] = &lnrpc.ClosedChannelUpdate{
+			ChanId:       closedChan.ChanID,
+			Capacity:     int64(closedChan.Capacity),
+			ClosedHeight: closedChan.ClosedHeight,
+			ChanPoint: &lnrpc.ChannelPoint{
+				FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+					FundingTxidBytes: closedChan.ChanPoint.Hash[:],
+				},
+				OutputIndex: closedChan.ChanPoint.Index,
+			},
+		}
+	}
 
-	return channelEdge, nil
+	return &lnrpc.GraphTopologyUpdate{
+		NodeUpdates:    nodeUpdates,
+		ChannelUpdates: channelUpdates,
+		ClosedChans:    closedChans,
+		NewChans:       newChans,
+	}
 }
 
-// GetNodeInfo returns the latest advertised and aggregate authenticated
-// channel information for the specified node identified by its public key.
-func (r *rpcServer) GetNodeInfo(ctx context.Context,
-	in *lnrpc.NodeInfoRequest) (*lnrpc.NodeInfo, error) {
+// ListPayments returns a list of all outgoing payments.
+func (r *rpcServer) ListPayments(ctx context.Context,
+	_ *lnrpc.ListPaymentsRequest) (*lnrpc.ListPaymentsResponse, error) {
 
-	graph := r.server.chanDB.ChannelGraph()
+	rpcsLog.Debugf("[ListPayments]")
 
-	// First, parse the hex-encoded public key into a full in-memory public
-	// key object we can work with for querying.
-	pubKeyBytes, err := hex.DecodeString(in.PubKey)
-	if err != nil {
-		return nil, err
-	}
-	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
-	if err != nil {
+	payments, err := r.server.chanDB.FetchAllPayments()
+	if err != nil && err != channeldb.ErrNoPaymentsCreated {
 		return nil, err
 	}
 
-	// With the public key decoded, attempt to fetch the node corresponding
-	// to this public key. If the node cannot be found, then an error will
-	// be returned.
-	node, err := graph.FetchLightningNode(pubKey)
-	if err != nil {
-		return nil, err
+	paymentsResp := &lnrpc.ListPaymentsResponse{
+		Payments: make([]*lnrpc.Payment, len(payments)),
 	}
+	for i, payment := range payments {
+		path := make([]string, len(payment.Path))
+		for i, hop := range payment.Path {
+			path[i] = hex.EncodeToString(hop[:])
+		}
 
-	// With the node obtained, we'll now iterate through all its out going
-	// edges to gather some basic statistics about its out going channels.
-	var (
-		numChannels   uint32
-		totalCapacity btcutil.Amount
-	)
-	if err := node.ForEachChannel(nil, func(_ *bbolt.Tx, edge *channeldb.ChannelEdgeInfo,
-		_, _ *channeldb.ChannelEdgePolicy) error {
-
-		numChannels++
-		totalCapacity += edge.Capacity
-		return nil
-	}); err != nil {
-		return nil, err
-	}
+		msatValue := int64(payment.Terms.Value)
+		satValue := int64(payment.Terms.Value.ToSatoshis())
 
-	nodeAddrs := make([]*lnrpc.NodeAddress, 0)
-	for _, addr := range node.Addresses {
-		nodeAddr := &lnrpc.NodeAddress{
-			Network: addr.Network(),
-			Addr:    addr.String(),
+		paymentHash := sha256.Sum256(payment.PaymentPreimage[:])
+		paymentsResp.Payments[i] = &lnrpc.Payment{
+			PaymentHash:     hex.EncodeToString(paymentHash[:]),
+			Value:           satValue,
+			ValueMsat:       msatValue,
+			ValueSat:        satValue,
+			CreationDate:    payment.CreationDate.Unix(),
+			Path:            path,
+			Fee:             int64(payment.Fee.ToSatoshis()),
+			PaymentPreimage: hex.EncodeToString(payment.PaymentPreimage[:]),
 		}
-		nodeAddrs = append(nodeAddrs, nodeAddr)
 	}
-	// TODO(roasbeef): list channels as well?
 
-	nodeColor := fmt.Sprintf("#%02x%02x%02x", node.Color.R, node.Color.G, node.Color.B)
-	return &lnrpc.NodeInfo{
-		Node: &lnrpc.LightningNode{
-			LastUpdate: uint32(node.LastUpdate.Unix()),
-			PubKey:     in.PubKey,
-			Addresses:  nodeAddrs,
-			Alias:      node.Alias,
-			Color:      nodeColor,
-		},
-		NumChannels:   numChannels,
-		TotalCapacity: int64(totalCapacity),
-	}, nil
+	return paymentsResp, nil
 }
 
-// QueryRoutes attempts to query the daemons' Channel Router for a possible
-// route to a target destination capable of carrying a specific amount of
-// satoshis within the route's flow. The retuned route contains the full
-// details required to craft and send an HTLC, also including the necessary
-// information that should be present within the Sphinx packet encapsulated
-// within the HTLC.
-//
-// TODO(roasbeef): should return a slice of routes in reality
-//  * create separate PR to send based on well formatted route
-func (r *rpcServer) QueryRoutes(ctx context.Context,
-	in *lnrpc.QueryRoutesRequest) (*lnrpc.QueryRoutesResponse, error) {
+// DeleteAllPayments deletes all outgoing payments from DB.
+func (r *rpcServer) DeleteAllPayments(ctx context.Context,
+	_ *lnrpc.DeleteAllPaymentsRequest) (*lnrpc.DeleteAllPaymentsResponse, error) {
 
-	return r.routerBackend.QueryRoutes(ctx, in)
+	rpcsLog.Debugf("[DeleteAllPayments]")
+
+	if err := r.server.chanDB.DeleteAllPayments(); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DeleteAllPaymentsResponse{}, nil
 }
 
-// unmarshallHopByChannelLookup unmarshalls an rpc hop for which the pub key is
-// not known. This function will query the channel graph with channel id to
-// retrieve both endpoints and determine the hop pubkey using the previous hop
-// pubkey. If the channel is unknown, an error is returned.
-func unmarshallHopByChannelLookup(graph *channeldb.ChannelGraph, hop *lnrpc.Hop,
-	prevPubKeyBytes [33]byte) (*route.Hop, error) {
+// DeletePayments deletes the payment records selected by the request from the
+// database. Payments that are still in flight are never deleted.
+func (r *rpcServer) DeletePayments(ctx context.Context,
+	req *lnrpc.DeletePaymentsRequest) (*lnrpc.DeletePaymentsResponse,
+	error) {
 
-	// Discard edge policies, because they may be nil.
-	edgeInfo, _, _, err := graph.FetchChannelEdgesByID(hop.ChanId)
-	if err != nil {
-		return nil, fmt.Errorf("unable to fetch channel edges by "+
-			"channel ID %d: %v", hop.ChanId, err)
-	}
+	rpcsLog.Debugf("[DeletePayments]")
 
-	var pubKeyBytes [33]byte
-	switch {
-	case prevPubKeyBytes == edgeInfo.NodeKey1Bytes:
-		pubKeyBytes = edgeInfo.NodeKey2Bytes
-	case prevPubKeyBytes == edgeInfo.NodeKey2Bytes:
-		pubKeyBytes = edgeInfo.NodeKey1Bytes
-	default:
-		return nil, fmt.Errorf("channel edge does not match expected node")
+	if !req.Failed && req.CompletedBefore == 0 {
+		return nil, errors.New("no payments selected for deletion")
 	}
 
-	return &route.Hop{
-		OutgoingTimeLock: hop.Expiry,
-		AmtToForward:     lnwire.MilliSatoshi(hop.AmtToForwardMsat),
-		PubKeyBytes:      pubKeyBytes,
-		ChannelID:        edgeInfo.ChannelID,
-	}, nil
-}
+	query := channeldb.PaymentDeletionQuery{
+		Failed: req.Failed,
+	}
+	if req.CompletedBefore != 0 {
+		query.CompletedBefore = time.Unix(req.CompletedBefore, 0)
+	}
 
-// unmarshallKnownPubkeyHop unmarshalls an rpc hop that contains the hop pubkey.
-// The channel graph doesn't need to be queried because all information required
-// for sending the payment is present.
-func unmarshallKnownPubkeyHop(hop *lnrpc.Hop) (*route.Hop, error) {
-	pubKey, err := hex.DecodeString(hop.PubKey)
+	numDeleted, err := r.server.chanDB.DeletePayments(query)
 	if err != nil {
-		return nil, fmt.Errorf("cannot decode pubkey %s", hop.PubKey)
+		return nil, err
 	}
 
-	var pubKeyBytes [33]byte
-	copy(pubKeyBytes[:], pubKey)
+	rpcsLog.Infof("Deleted %v payment records", numDeleted)
 
-	return &route.Hop{
-		OutgoingTimeLock: hop.Expiry,
-		AmtToForward:     lnwire.MilliSatoshi(hop.AmtToForwardMsat),
-		PubKeyBytes:      pubKeyBytes,
-		ChannelID:        hop.ChanId,
+	return &lnrpc.DeletePaymentsResponse{
+		NumDeleted: uint64(numDeleted),
 	}, nil
 }
 
-// unmarshallHop unmarshalls an rpc hop that may or may not contain a node
-// pubkey.
-func unmarshallHop(graph *channeldb.ChannelGraph, hop *lnrpc.Hop,
-	prevNodePubKey [33]byte) (*route.Hop, error) {
+// GetFeeBudget returns the daily routing fee budget, along with the routing
+// fees paid within its window and the part of the budget that remains.
+func (r *rpcServer) GetFeeBudget(ctx context.Context,
+	_ *lnrpc.GetFeeBudgetRequest) (*lnrpc.FeeBudget, error) {
 
-	if hop.PubKey == "" {
-		// If no pub key is given of the hop, the local channel
-		// graph needs to be queried to complete the information
-		// necessary for routing.
-		return unmarshallHopByChannelLookup(graph, hop, prevNodePubKey)
-	}
+	rpcsLog.Debugf("[GetFeeBudget]")
 
-	return unmarshallKnownPubkeyHop(hop)
+	return marshallFeeBudget(r.server.feeBudget.Status()), nil
 }
 
-// unmarshallRoute unmarshalls an rpc route. For hops that don't specify a
-// pubkey, the channel graph is queried.
-func unmarshallRoute(rpcroute *lnrpc.Route,
-	graph *channeldb.ChannelGraph) (*route.Route, error) {
+// SubscribeFeeBudgetEvents returns a uni-directional stream (server -> client)
+// of events sent each time the routing fees paid within the window of the daily
+// fee budget reach one of its thresholds.
+func (r *rpcServer) SubscribeFeeBudgetEvents(
+	req *lnrpc.FeeBudgetEventSubscription,
+	updateStream lnrpc.Lightning_SubscribeFeeBudgetEventsServer) error {
 
-	sourceNode, err := graph.SourceNode()
+	eventSub, err := r.server.feeBudget.SubscribeEvents()
 	if err != nil {
-		return nil, fmt.Errorf("unable to fetch source node from graph "+
-			"while unmarshaling route. %v", err)
+		return err
 	}
 
-	prevNodePubKey := sourceNode.PubKeyBytes
+	// Ensure that the resources for the client is cleaned up once either
+	// the server, or client exits.
+	defer eventSub.Cancel()
 
-	hops := make([]*route.Hop, len(rpcroute.Hops))
-	for i, hop := range rpcroute.Hops {
-		routeHop, err := unmarshallHop(graph,
-			hop, prevNodePubKey)
-		if err != nil {
-			return nil, err
-		}
+	for {
+		select {
+		case update := <-eventSub.Updates():
+			event, ok := update.(*routing.FeeBudgetEvent)
+			if !ok {
+				return fmt.Errorf("unexpected fee budget "+
+					"event: %v", update)
+			}
 
-		hops[i] = routeHop
+			var eventType lnrpc.FeeBudgetEvent_EventType
+			switch event.Type {
+			case routing.FeeBudgetNearingLimit:
+				eventType = lnrpc.FeeBudgetEvent_NEARING_LIMIT
+			case routing.FeeBudgetExhausted:
+				eventType = lnrpc.FeeBudgetEvent_EXHAUSTED
+			default:
+				return fmt.Errorf("unknown fee budget event "+
+					"type: %v", event.Type)
+			}
 
-		prevNodePubKey = routeHop.PubKeyBytes
-	}
+			err := updateStream.Send(&lnrpc.FeeBudgetEvent{
+				Type:   eventType,
+				Budget: marshallFeeBudget(event.Status),
+			})
+			if err != nil {
+				return err
+			}
 
-	route, err := route.NewRouteFromHops(
-		lnwire.MilliSatoshi(rpcroute.TotalAmtMsat),
-		rpcroute.TotalTimeLock,
-		sourceNode.PubKeyBytes,
-		hops,
-	)
-	if err != nil {
-		return nil, err
+		case <-r.quit:
+			return nil
+		}
 	}
-
-	return route, nil
 }
 
-// GetNetworkInfo returns some basic stats about the known channel graph from
-// the PoV of the node.
-func (r *rpcServer) GetNetworkInfo(ctx context.Context,
-	_ *lnrpc.NetworkInfoRequest) (*lnrpc.NetworkInfo, error) {
-
-	graph := r.server.chanDB.ChannelGraph()
-
-	var (
-		numNodes             uint32
-		numChannels          uint32
-		maxChanOut           uint32
-		totalNetworkCapacity btcutil.Amount
-		minChannelSize       btcutil.Amount = math.MaxInt64
-		maxChannelSize       btcutil.Amount
-		medianChanSize       btcutil.Amount
-	)
-
-	// We'll use this map to de-duplicate channels during our traversal.
-	// This is needed since channels are directional, so there will be two
-	// edges for each channel within the graph.
-	seenChans := make(map[uint64]struct{})
-
-	// We also keep a list of all encountered capacities, in order to
-	// calculate the median channel size.
-	var allChans []btcutil.Amount
+// marshallFeeBudget converts the status of the fee budget into its RPC
+// representation, along with the default fee limit of payments.
+func marshallFeeBudget(status routing.FeeBudgetStatus) *lnrpc.FeeBudget {
+	budget := &lnrpc.FeeBudget{
+		DailyBudgetMsat:          uint64(status.DailyBudget),
+		SpentMsat:                uint64(status.Spent),
+		ReservedMsat:             uint64(status.Reserved),
+		RemainingMsat:            uint64(status.Remaining),
+		WarnPercent:              status.WarnPercent,
+		DefaultFeeLimitPercent:   cfg.Payments.DefaultFeeLimitPercent,
+		DefaultFeeLimitFloorMsat: cfg.Payments.DefaultFeeLimitFloorMSat,
+	}
+	if !status.NextRelease.IsZero() {
+		budget.NextReleaseTime = status.NextRelease.Unix()
+	}
 
-	// We'll run through all the known nodes in the within our view of the
-	// network, tallying up the total number of nodes, and also gathering
-	// each node so we can measure the graph diameter and degree stats
-	// below.
-	if err := graph.ForEachNode(nil, func(tx *bbolt.Tx, node *channeldb.LightningNode) error {
-		// Increment the total number of nodes with each iteration.
-		numNodes++
+	return budget
+}
 
-		// For each channel we'll compute the out degree of each node,
-		// and also update our running tallies of the min/max channel
-		// capacity, as well as the total channel capacity. We pass
-		// through the db transaction from the outer view so we can
-		// re-use it within this inner view.
-		var outDegree uint32
-		if err := node.ForEachChannel(tx, func(_ *bbolt.Tx,
-			edge *channeldb.ChannelEdgeInfo, _, _ *channeldb.ChannelEdgePolicy) error {
+// AddPaymentSchedule adds a new schedule of recurring payments towards a
+// single destination, paying the next of the given payment requests each time
+// a payment is due.
+func (r *rpcServer) AddPaymentSchedule(ctx context.Context,
+	req *lnrpc.AddPaymentScheduleRequest) (*lnrpc.PaymentSchedule, error) {
 
-			// Bump up the out degree for this node for each
-			// channel encountered.
-			outDegree++
+	rpcsLog.Debugf("[addschedule] amt=%v, interval=%v, max_fee=%v, "+
+		"budget=%v", req.Amt, req.Interval, req.MaxFee, req.Budget)
 
-			// If we've already seen this channel, then we'll
-			// return early to ensure that we don't double-count
-			// stats.
-			if _, ok := seenChans[edge.ChannelID]; ok {
-				return nil
-			}
+	if req.Amt < 0 || req.MaxFee < 0 || req.Budget < 0 {
+		return nil, fmt.Errorf("amounts must not be negative")
+	}
 
-			// Compare the capacity of this channel against the
-			// running min/max to see if we should update the
-			// extrema.
-			chanCapacity := edge.Capacity
-			if chanCapacity < minChannelSize {
-				minChannelSize = chanCapacity
-			}
-			if chanCapacity > maxChannelSize {
-				maxChannelSize = chanCapacity
-			}
+	schedule := &paysched.Schedule{
+		Amt:      lnwire.NewMSatFromSatoshis(btcutil.Amount(req.Amt)),
+		Interval: time.Duration(req.Interval) * time.Second,
+		MaxFee: lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(req.MaxFee),
+		),
+		Budget: lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(req.Budget),
+		),
+		PaymentRequests: req.PaymentRequests,
+	}
+	if req.StartTime != 0 {
+		schedule.NextPayment = time.Unix(req.StartTime, 0)
+	}
 
-			// Accumulate the total capacity of this channel to the
-			// network wide-capacity.
-			totalNetworkCapacity += chanCapacity
+	schedule, err := r.server.paymentScheduler.AddSchedule(schedule)
+	if err != nil {
+		return nil, err
+	}
 
-			numChannels++
+	return marshallPaymentSchedule(schedule), nil
+}
 
-			seenChans[edge.ChannelID] = struct{}{}
-			allChans = append(allChans, edge.Capacity)
-			return nil
-		}); err != nil {
-			return err
-		}
+// ListPaymentSchedules returns all recurring payment schedules.
+func (r *rpcServer) ListPaymentSchedules(ctx context.Context,
+	_ *lnrpc.ListPaymentSchedulesRequest) (
+	*lnrpc.ListPaymentSchedulesResponse, error) {
 
-		// Finally, if the out degree of this node is greater than what
-		// we've seen so far, update the maxChanOut variable.
-		if outDegree > maxChanOut {
-			maxChanOut = outDegree
-		}
+	rpcsLog.Debugf("[listschedules]")
 
-		return nil
-	}); err != nil {
+	schedules, err := r.server.paymentScheduler.ListSchedules()
+	if err != nil {
 		return nil, err
 	}
 
-	// Find the median.
-	medianChanSize = autopilot.Median(allChans)
-
-	// If we don't have any channels, then reset the minChannelSize to zero
-	// to avoid outputting NaN in encoded JSON.
-	if numChannels == 0 {
-		minChannelSize = 0
+	resp := &lnrpc.ListPaymentSchedulesResponse{
+		Schedules: make([]*lnrpc.PaymentSchedule, 0, len(schedules)),
+	}
+	for _, schedule := range schedules {
+		resp.Schedules = append(
+			resp.Schedules, marshallPaymentSchedule(schedule),
+		)
 	}
 
-	// TODO(roasbeef): graph diameter
+	return resp, nil
+}
 
-	// TODO(roasbeef): also add oldest channel?
-	netInfo := &lnrpc.NetworkInfo{
-		MaxOutDegree:         maxChanOut,
-		AvgOutDegree:         float64(2*numChannels) / float64(numNodes),
-		NumNodes:             numNodes,
-		NumChannels:          numChannels,
-		TotalNetworkCapacity: int64(totalNetworkCapacity),
-		AvgChannelSize:       float64(totalNetworkCapacity) / float64(numChannels),
+// ExtendPaymentSchedule adds further payment requests to an existing payment
+// schedule.
+func (r *rpcServer) ExtendPaymentSchedule(ctx context.Context,
+	req *lnrpc.ExtendPaymentScheduleRequest) (*lnrpc.PaymentSchedule,
+	error) {
 
-		MinChannelSize:       int64(minChannelSize),
-		MaxChannelSize:       int64(maxChannelSize),
-		MedianChannelSizeSat: int64(medianChanSize),
+	rpcsLog.Debugf("[extendschedule] id=%v, num_payment_requests=%v",
+		req.Id, len(req.PaymentRequests))
+
+	if len(req.PaymentRequests) == 0 {
+		return nil, fmt.Errorf("no payment requests provided")
 	}
 
-	// Similarly, if we don't have any channels, then we'll also set the
-	// average channel size to zero in order to avoid weird JSON encoding
-	// outputs.
-	if numChannels == 0 {
-		netInfo.AvgChannelSize = 0
+	schedule, err := r.server.paymentScheduler.AddPaymentRequests(
+		req.Id, req.PaymentRequests,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	return netInfo, nil
+	return marshallPaymentSchedule(schedule), nil
 }
 
-// StopDaemon will send a shutdown request to the interrupt handler, triggering
-// a graceful shutdown of the daemon.
-func (r *rpcServer) StopDaemon(ctx context.Context,
-	_ *lnrpc.StopRequest) (*lnrpc.StopResponse, error) {
+// RemovePaymentSchedule removes a payment schedule, preventing any further
+// payments from being made.
+func (r *rpcServer) RemovePaymentSchedule(ctx context.Context,
+	req *lnrpc.RemovePaymentScheduleRequest) (
+	*lnrpc.RemovePaymentScheduleResponse, error) {
 
-	signal.RequestShutdown()
-	return &lnrpc.StopResponse{}, nil
-}
+	rpcsLog.Debugf("[removeschedule] id=%v", req.Id)
 
-// SubscribeChannelGraph launches a streaming RPC that allows the caller to
-// receive notifications upon any changes the channel graph topology from the
-// review of the responding node. Events notified include: new nodes coming
-// online, nodes updating their authenticated attributes, new channels being
-// advertised, updates in the routing policy for a directional channel edge,
-// and finally when prior channels are closed on-chain.
-func (r *rpcServer) SubscribeChannelGraph(req *lnrpc.GraphTopologySubscription,
-	updateStream lnrpc.Lightning_SubscribeChannelGraphServer) error {
+	if err := r.server.paymentScheduler.RemoveSchedule(req.Id); err != nil {
+		return nil, err
+	}
 
-	// First, we start by subscribing to a new intent to receive
-	// notifications from the channel router.
-	client, err := r.server.chanRouter.SubscribeTopology()
+	return &lnrpc.RemovePaymentScheduleResponse{}, nil
+}
+
+// SubscribePaymentScheduleAlerts returns a uni-directional stream (server ->
+// client) of alerts sent each time a scheduled payment could not be made.
+func (r *rpcServer) SubscribePaymentScheduleAlerts(
+	req *lnrpc.PaymentScheduleAlertSubscription,
+	updateStream lnrpc.Lightning_SubscribePaymentScheduleAlertsServer) error {
+
+	alertSub, err := r.server.paymentScheduler.SubscribeAlerts()
 	if err != nil {
 		return err
 	}
 
-	// Ensure that the resources for the topology update client is cleaned
-	// up once either the server, or client exists.
-	defer client.Cancel()
+	// Ensure that the resources for the client is cleaned up once either
+	// the server, or client exits.
+	defer alertSub.Cancel()
 
 	for {
 		select {
-
-		// A new update has been sent by the channel router, we'll
-		// marshal it into the form expected by the gRPC client, then
-		// send it off.
-		case topChange, ok := <-client.TopologyChanges:
-			// If the second value from the channel read is nil,
-			// then this means that the channel router is exiting
-			// or the notification client was cancelled. So we'll
-			// exit early.
+		case update := <-alertSub.Updates():
+			alert, ok := update.(*paysched.Alert)
 			if !ok {
-				return errors.New("server shutting down")
+				return fmt.Errorf("unexpected payment schedule "+
+					"alert: %v", update)
 			}
 
-			// Convert the struct from the channel router into the
-			// form expected by the gRPC service then send it off
-			// to the client.
-			graphUpdate := marshallTopologyChange(topChange)
-			if err := updateStream.Send(graphUpdate); err != nil {
+			err := updateStream.Send(&lnrpc.PaymentScheduleAlert{
+				Schedule: marshallPaymentSchedule(alert.Schedule),
+				Error:    alert.Err.Error(),
+			})
+			if err != nil {
 				return err
 			}
 
-		// The server is quitting, so we'll exit immediately. Returning
-		// nil will close the clients read end of the stream.
 		case <-r.quit:
 			return nil
 		}
 	}
 }
 
-// marshallTopologyChange performs a mapping from the topology change struct
-// returned by the router to the form of notifications expected by the current
-// gRPC service.
-func marshallTopologyChange(topChange *routing.TopologyChange) *lnrpc.GraphTopologyUpdate {
+// marshallPaymentSchedule converts a payment schedule into its RPC
+// representation.
+func marshallPaymentSchedule(s *paysched.Schedule) *lnrpc.PaymentSchedule {
+	var state lnrpc.PaymentSchedule_State
+	switch s.State {
+	case paysched.StateActive:
+		state = lnrpc.PaymentSchedule_ACTIVE
+
+	case paysched.StateBudgetExhausted:
+		state = lnrpc.PaymentSchedule_BUDGET_EXHAUSTED
+
+	case paysched.StateFailed:
+		state = lnrpc.PaymentSchedule_FAILED
+	}
+
+	return &lnrpc.PaymentSchedule{
+		Id:                 s.ID,
+		Dest:               hex.EncodeToString(s.Dest[:]),
+		Amt:                int64(s.Amt.ToSatoshis()),
+		Interval:           uint64(s.Interval / time.Second),
+		MaxFee:             int64(s.MaxFee.ToSatoshis()),
+		Budget:             int64(s.Budget.ToSatoshis()),
+		SpentMsat:          int64(s.Spent),
+		NextPayment:        s.NextPayment.Unix(),
+		NumPaymentRequests: uint32(len(s.PaymentRequests)),
+		NumPayments:        s.NumPayments,
+		NumFailures:        s.NumFailures,
+		LastError:          s.LastError,
+		State:              state,
+	}
+}
 
-	// encodeKey is a simple helper function that converts a live public
-	// key into a hex-encoded version of the compressed serialization for
-	// the public key.
-	encodeKey := func(k *btcec.PublicKey) string {
-		return hex.EncodeToString(k.SerializeCompressed())
+// DeriveSwapKey derives a fresh key to be used within the on-chain HTLC of a
+// new submarine swap.
+func (r *rpcServer) DeriveSwapKey(ctx context.Context,
+	req *lnrpc.DeriveSwapKeyRequest) (*lnrpc.DeriveSwapKeyResponse, error) {
+
+	keyDesc, err := r.server.swapManager.DeriveKey()
+	if err != nil {
+		return nil, err
 	}
 
-	nodeUpdates := make([]*lnrpc.NodeUpdate, len(topChange.NodeUpdates))
-	for i, nodeUpdate := range topChange.NodeUpdates {
-		addrs := make([]string, len(nodeUpdate.Addresses))
-		for i, addr := range nodeUpdate.Addresses {
-			addrs[i] = addr.String()
-		}
+	return &lnrpc.DeriveSwapKeyResponse{
+		PubKey:   keyDesc.PubKey.SerializeCompressed(),
+		KeyIndex: keyDesc.Index,
+	}, nil
+}
 
-		nodeUpdates[i] = &lnrpc.NodeUpdate{
-			Addresses:      addrs,
-			IdentityKey:    encodeKey(nodeUpdate.IdentityKey),
-			GlobalFeatures: nodeUpdate.GlobalFeatures,
-			Alias:          nodeUpdate.Alias,
-		}
+// RegisterSwap hands the on-chain side of a submarine swap over to the swap
+// manager, which claims or reclaims its HTLC once confirmed.
+func (r *rpcServer) RegisterSwap(ctx context.Context,
+	req *lnrpc.RegisterSwapRequest) (*lnrpc.RegisterSwapResponse, error) {
+
+	paymentHash, err := lntypes.MakeHash(req.PaymentHash)
+	if err != nil {
+		return nil, err
+	}
+	remoteKey, err := btcec.ParsePubKey(req.RemotePubKey, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse remote pubkey: %v", err)
 	}
 
-	channelUpdates := make([]*lnrpc.ChannelEdgeUpdate, len(topChange.ChannelEdgeUpdates))
-	for i, channelUpdate := range topChange.ChannelEdgeUpdates {
-		channelUpdates[i] = &lnrpc.ChannelEdgeUpdate{
-			ChanId: channelUpdate.ChanID,
-			ChanPoint: &lnrpc.ChannelPoint{
-				FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
-					FundingTxidBytes: channelUpdate.ChanPoint.Hash[:],
+	s := &swap.Swap{
+		HTLC: contractcourt.SwapHTLC{
+			PaymentHash: paymentHash,
+			LocalKey: keychain.KeyDescriptor{
+				KeyLocator: keychain.KeyLocator{
+					Family: keychain.KeyFamilySwap,
+					Index:  req.KeyIndex,
 				},
-				OutputIndex: channelUpdate.ChanPoint.Index,
-			},
-			Capacity: int64(channelUpdate.Capacity),
-			RoutingPolicy: &lnrpc.RoutingPolicy{
-				TimeLockDelta:    uint32(channelUpdate.TimeLockDelta),
-				MinHtlc:          int64(channelUpdate.MinHTLC),
-				MaxHtlcMsat:      uint64(channelUpdate.MaxHTLC),
-				FeeBaseMsat:      int64(channelUpdate.BaseFee),
-				FeeRateMilliMsat: int64(channelUpdate.FeeRate),
-				Disabled:         channelUpdate.Disabled,
 			},
-			AdvertisingNode: encodeKey(channelUpdate.AdvertisingNode),
-			ConnectingNode:  encodeKey(channelUpdate.ConnectingNode),
-		}
+			RemoteKey:  remoteKey,
+			CltvExpiry: req.CltvExpiry,
+			HeightHint: req.HeightHint,
+		},
 	}
 
-	closedChans := make([]*lnrpc.ClosedChannelUpdate, len(topChange.ClosedChannels))
-	for i, closedChan := range topChange.ClosedChannels {
-		closedChans[i] = &lnrpc.ClosedChannelUpdate{
-			ChanId:       closedChan.ChanID,
-			Capacity:     int64(closedChan.Capacity),
-			ClosedHeight: closedChan.ClosedHeight,
-			ChanPoint: &lnrpc.ChannelPoint{
-				FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
-					FundingTxidBytes: closedChan.ChanPoint.Hash[:],
-				},
-				OutputIndex: closedChan.ChanPoint.Index,
-			},
+	switch req.Type {
+	case lnrpc.Swap_LOOP_OUT:
+		s.Type = swap.TypeLoopOut
+
+	case lnrpc.Swap_LOOP_IN:
+		s.Type = swap.TypeLoopIn
+
+	default:
+		return nil, fmt.Errorf("unknown swap type: %v", req.Type)
+	}
+
+	if len(req.Preimage) != 0 {
+		preimage, err := lntypes.MakePreimage(req.Preimage)
+		if err != nil {
+			return nil, err
 		}
+		s.Preimage = &preimage
 	}
 
-	return &lnrpc.GraphTopologyUpdate{
-		NodeUpdates:    nodeUpdates,
-		ChannelUpdates: channelUpdates,
-		ClosedChans:    closedChans,
+	if err := r.server.swapManager.AddSwap(s); err != nil {
+		return nil, err
 	}
-}
 
-// ListPayments returns a list of all outgoing payments.
-func (r *rpcServer) ListPayments(ctx context.Context,
-	_ *lnrpc.ListPaymentsRequest) (*lnrpc.ListPaymentsResponse, error) {
+	htlcAddr, err := swapHTLCAddress(&s.HTLC)
+	if err != nil {
+		return nil, err
+	}
 
-	rpcsLog.Debugf("[ListPayments]")
+	return &lnrpc.RegisterSwapResponse{
+		HtlcAddress: htlcAddr,
+	}, nil
+}
 
-	payments, err := r.server.chanDB.FetchAllPayments()
-	if err != nil && err != channeldb.ErrNoPaymentsCreated {
+// ListSwaps returns all submarine swaps registered with the swap manager.
+func (r *rpcServer) ListSwaps(ctx context.Context,
+	req *lnrpc.ListSwapsRequest) (*lnrpc.ListSwapsResponse, error) {
+
+	swaps, err := r.server.swapManager.Swaps()
+	if err != nil {
 		return nil, err
 	}
 
-	paymentsResp := &lnrpc.ListPaymentsResponse{
-		Payments: make([]*lnrpc.Payment, len(payments)),
+	resp := &lnrpc.ListSwapsResponse{
+		Swaps: make([]*lnrpc.Swap, 0, len(swaps)),
 	}
-	for i, payment := range payments {
-		path := make([]string, len(payment.Path))
-		for i, hop := range payment.Path {
-			path[i] = hex.EncodeToString(hop[:])
+	for _, s := range swaps {
+		rpcSwap, err := marshallSwap(s)
+		if err != nil {
+			return nil, err
 		}
+		resp.Swaps = append(resp.Swaps, rpcSwap)
+	}
 
-		msatValue := int64(payment.Terms.Value)
-		satValue := int64(payment.Terms.Value.ToSatoshis())
+	return resp, nil
+}
 
-		paymentHash := sha256.Sum256(payment.PaymentPreimage[:])
-		paymentsResp.Payments[i] = &lnrpc.Payment{
-			PaymentHash:     hex.EncodeToString(paymentHash[:]),
-			Value:           satValue,
-			ValueMsat:       msatValue,
-			ValueSat:        satValue,
-			CreationDate:    payment.CreationDate.Unix(),
-			Path:            path,
-			Fee:             int64(payment.Fee.ToSatoshis()),
-			PaymentPreimage: hex.EncodeToString(payment.PaymentPreimage[:]),
-		}
+// swapHTLCAddress returns the address of the on-chain HTLC of a swap.
+func swapHTLCAddress(htlc *contractcourt.SwapHTLC) (string, error) {
+	witnessScript, err := htlc.WitnessScript()
+	if err != nil {
+		return "", err
 	}
 
-	return paymentsResp, nil
+	addr, err := btcutil.NewAddressWitnessScriptHash(
+		chainhash.HashB(witnessScript), activeNetParams.Params,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return addr.String(), nil
 }
 
-// DeleteAllPayments deletes all outgoing payments from DB.
-func (r *rpcServer) DeleteAllPayments(ctx context.Context,
-	_ *lnrpc.DeleteAllPaymentsRequest) (*lnrpc.DeleteAllPaymentsResponse, error) {
+// marshallSwap converts a swap into its RPC representation.
+func marshallSwap(s *swap.Swap) (*lnrpc.Swap, error) {
+	htlcAddr, err := swapHTLCAddress(&s.HTLC)
+	if err != nil {
+		return nil, err
+	}
 
-	rpcsLog.Debugf("[DeleteAllPayments]")
+	rpcSwap := &lnrpc.Swap{
+		PaymentHash:    s.HTLC.PaymentHash[:],
+		HtlcAddress:    htlcAddr,
+		HtlcAmount:     int64(s.HTLC.Amount),
+		CltvExpiry:     s.HTLC.CltvExpiry,
+		InitiationTime: s.InitiationTime.Unix(),
+		LastUpdate:     s.LastUpdate.Unix(),
+	}
 
-	if err := r.server.chanDB.DeleteAllPayments(); err != nil {
-		return nil, err
+	switch s.Type {
+	case swap.TypeLoopOut:
+		rpcSwap.Type = lnrpc.Swap_LOOP_OUT
+
+	case swap.TypeLoopIn:
+		rpcSwap.Type = lnrpc.Swap_LOOP_IN
 	}
 
-	return &lnrpc.DeleteAllPaymentsResponse{}, nil
+	switch s.State {
+	case swap.StateInitiated:
+		rpcSwap.State = lnrpc.Swap_INITIATED
+
+	case swap.StateHtlcConfirmed:
+		rpcSwap.State = lnrpc.Swap_HTLC_CONFIRMED
+
+	case swap.StateSuccess:
+		rpcSwap.State = lnrpc.Swap_SUCCESS
+
+	case swap.StateRefunded:
+		rpcSwap.State = lnrpc.Swap_REFUNDED
+	}
+
+	if s.HTLC.OutPoint != nil {
+		rpcSwap.HtlcOutpoint = s.HTLC.OutPoint.String()
+	}
+	if s.SpendTxid != nil {
+		rpcSwap.SpendTxid = s.SpendTxid.String()
+	}
+
+	return rpcSwap, nil
 }
 
 // DebugLevel allows a caller to programmatically set the logging verbosity of
@@ -4324,6 +6467,77 @@ func (r *rpcServer) DebugLevel(ctx context.Context,
 	return &lnrpc.DebugLevelResponse{}, nil
 }
 
+// CompactDatabase compacts the channel database, unless a dry run is
+// requested, in which case only its size and free page ratio are reported.
+func (r *rpcServer) CompactDatabase(ctx context.Context,
+	req *lnrpc.CompactDatabaseRequest) (*lnrpc.CompactDatabaseResponse,
+	error) {
+
+	chanDB := r.server.chanDB
+
+	if req.DryRun {
+		ratio, err := chanDB.FreePageRatio()
+		if err != nil {
+			return nil, err
+		}
+
+		size, err := chanDB.FileSize()
+		if err != nil {
+			return nil, err
+		}
+
+		return &lnrpc.CompactDatabaseResponse{
+			FreePageRatio: ratio,
+			SizeBefore:    size,
+			SizeAfter:     size,
+		}, nil
+	}
+
+	rpcsLog.Infof("[compactdb] compacting channel database")
+
+	report, err := chanDB.Compact()
+	if err != nil {
+		return nil, fmt.Errorf("unable to compact database: %v", err)
+	}
+
+	return &lnrpc.CompactDatabaseResponse{
+		FreePageRatio:  report.FreePageRatio,
+		SizeBefore:     report.SizeBefore,
+		SizeAfter:      report.SizeAfter,
+		ReclaimedBytes: report.Reclaimed(),
+		DurationMs:     int64(report.Duration / time.Millisecond),
+	}, nil
+}
+
+// CheckDatabase scans the channel database for inconsistencies, repairing
+// those that can be repaired if requested.
+func (r *rpcServer) CheckDatabase(ctx context.Context,
+	req *lnrpc.CheckDatabaseRequest) (*lnrpc.CheckDatabaseResponse,
+	error) {
+
+	rpcsLog.Infof("[checkdb] checking channel database (repair=%v)",
+		req.Repair)
+
+	issues, err := r.server.chanDB.CheckIntegrity(req.Repair)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check database: %v", err)
+	}
+
+	resp := &lnrpc.CheckDatabaseResponse{
+		Issues: make([]*lnrpc.DatabaseIssue, 0, len(issues)),
+	}
+	for _, issue := range issues {
+		resp.Issues = append(resp.Issues, &lnrpc.DatabaseIssue{
+			Type:        issue.Type.String(),
+			Description: issue.Description,
+			Repairable:  issue.Repairable,
+			Repaired:    issue.Repaired,
+		})
+	}
+
+	return resp, nil
+}
+
 // DecodePayReq takes an encoded payment request string and attempts to decode
 // it, returning a full description of the conditions encoded within the
 // payment request.
@@ -4405,7 +6619,7 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 	}
 
 	var feeReports []*lnrpc.ChannelFeeReport
-	err = selfNode.ForEachChannel(nil, func(_ *bbolt.Tx, chanInfo *channeldb.ChannelEdgeInfo,
+	err = selfNode.ForEachChannel(nil, func(_ kvdb.Tx, chanInfo *channeldb.ChannelEdgeInfo,
 		edgePolicy, _ *channeldb.ChannelEdgePolicy) error {
 
 		// Self node should always have policies for its channels.
@@ -4520,10 +6734,13 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 	}
 
 	return &lnrpc.FeeReportResponse{
-		ChannelFees: feeReports,
-		DayFeeSum:   uint64(dayFees.ToSatoshis()),
-		WeekFeeSum:  uint64(weekFees.ToSatoshis()),
-		MonthFeeSum: uint64(monthFees.ToSatoshis()),
+		ChannelFees:     feeReports,
+		DayFeeSum:       uint64(dayFees.ToSatoshis()),
+		WeekFeeSum:      uint64(weekFees.ToSatoshis()),
+		MonthFeeSum:     uint64(monthFees.ToSatoshis()),
+		DayFeeSumMsat:   uint64(dayFees),
+		WeekFeeSumMsat:  uint64(weekFees),
+		MonthFeeSumMsat: uint64(monthFees),
 	}, nil
 }
 
@@ -4580,49 +6797,21 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 	// protocol.
 	feeRateFixed := uint32(req.FeeRate * feeBase)
 	baseFeeMsat := lnwire.MilliSatoshi(req.BaseFeeMsat)
-	feeSchema := routing.FeeSchema{
-		BaseFee: baseFeeMsat,
-		FeeRate: feeRateFixed,
-	}
-
-	chanPolicy := routing.ChannelPolicy{
-		FeeSchema:     feeSchema,
-		TimeLockDelta: req.TimeLockDelta,
-	}
 
 	rpcsLog.Debugf("[updatechanpolicy] updating channel policy base_fee=%v, "+
 		"rate_float=%v, rate_fixed=%v, time_lock_delta: %v, targets=%v",
 		req.BaseFeeMsat, req.FeeRate, feeRateFixed, req.TimeLockDelta,
 		spew.Sdump(targetChans))
 
-	// With the scope resolved, we'll now send this to the
-	// AuthenticatedGossiper so it can propagate the new policy for our
-	// target channel(s).
-	err := r.server.authGossiper.PropagateChanPolicyUpdate(
-		chanPolicy, targetChans...,
+	// With the scope resolved, we'll now propagate the new policy for our
+	// target channel(s), and apply it to their active links.
+	err := r.server.updateChannelPolicy(
+		baseFeeMsat, feeRateFixed, req.TimeLockDelta, targetChans...,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// Finally, we'll apply the set of active links amongst the target
-	// channels.
-	//
-	// We create a partially policy as the logic won't overwrite a valid
-	// sub-policy with a "nil" one.
-	p := htlcswitch.ForwardingPolicy{
-		BaseFee:       baseFeeMsat,
-		FeeRate:       lnwire.MilliSatoshi(feeRateFixed),
-		TimeLockDelta: req.TimeLockDelta,
-	}
-	err = r.server.htlcSwitch.UpdateForwardingPolicies(p, targetChans...)
-	if err != nil {
-		// If we're unable update the fees due to the links not being
-		// online, then we don't need to fail the call. We'll simply
-		// log the failure.
-		rpcsLog.Warnf("Unable to update link fees: %v", err)
-	}
-
 	return &lnrpc.PolicyUpdateResponse{}, nil
 }
 
@@ -4698,25 +6887,157 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 		ForwardingEvents: make([]*lnrpc.ForwardingEvent, len(timeSlice.ForwardingEvents)),
 		LastOffsetIndex:  timeSlice.LastIndexOffset,
 	}
-	for i, event := range timeSlice.ForwardingEvents {
-		amtInSat := event.AmtIn.ToSatoshis()
-		amtOutSat := event.AmtOut.ToSatoshis()
-		feeMsat := event.AmtIn - event.AmtOut
+	for i, event := range timeSlice.ForwardingEvents {
+		amtInSat := event.AmtIn.ToSatoshis()
+		amtOutSat := event.AmtOut.ToSatoshis()
+		feeMsat := event.AmtIn - event.AmtOut
+
+		resp.ForwardingEvents[i] = &lnrpc.ForwardingEvent{
+			Timestamp:  uint64(event.Timestamp.Unix()),
+			ChanIdIn:   event.IncomingChanID.ToUint64(),
+			ChanIdOut:  event.OutgoingChanID.ToUint64(),
+			AmtIn:      uint64(amtInSat),
+			AmtOut:     uint64(amtOutSat),
+			Fee:        uint64(feeMsat.ToSatoshis()),
+			FeeMsat:    uint64(feeMsat),
+			AmtInMsat:  uint64(event.AmtIn),
+			AmtOutMsat: uint64(event.AmtOut),
+		}
+	}
+
+	return resp, nil
+}
+
+// ForwardingSummary returns the daily summaries of the HTLCs forwarded through
+// each of our channels within the target time range, including those of the
+// events that have been pruned from the forwarding log. If no time range is
+// specified, the summaries of the past 30 days are returned.
+func (r *rpcServer) ForwardingSummary(ctx context.Context,
+	req *lnrpc.ForwardingSummaryRequest) (*lnrpc.ForwardingSummaryResponse,
+	error) {
+
+	rpcsLog.Debugf("[forwardingsummary]")
+
+	// As with the forwarding history, we'll flush any pending events to
+	// disk first so they're accounted for.
+	if err := r.server.htlcSwitch.FlushForwardingEvents(); err != nil {
+		return nil, fmt.Errorf("unable to flush forwarding "+
+			"events: %v", err)
+	}
+
+	var startTime, endTime time.Time
+	if req.StartTime == 0 && req.EndTime == 0 {
+		endTime = time.Now()
+		startTime = endTime.Add(-time.Hour * 24 * 30)
+	} else {
+		startTime = time.Unix(int64(req.StartTime), 0)
+		endTime = time.Unix(int64(req.EndTime), 0)
+	}
+
+	if endTime.Before(startTime) {
+		return nil, fmt.Errorf("end time %v is before start time %v",
+			req.EndTime, req.StartTime)
+	}
+
+	summaries, err := r.server.chanDB.ForwardingLog().QuerySummaries(
+		startTime, endTime,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query forwarding "+
+			"summaries: %v", err)
+	}
+
+	resp := &lnrpc.ForwardingSummaryResponse{
+		Summaries: make(
+			[]*lnrpc.ChannelForwardingSummary, 0, len(summaries),
+		),
+	}
+	for _, summary := range summaries {
+		resp.Summaries = append(
+			resp.Summaries, &lnrpc.ChannelForwardingSummary{
+				Day:         uint64(summary.Day.Unix()),
+				ChanId:      summary.ChanID.ToUint64(),
+				NumIncoming: summary.NumIncoming,
+				NumOutgoing: summary.NumOutgoing,
+				AmtInMsat:   uint64(summary.AmtIn),
+				AmtOutMsat:  uint64(summary.AmtOut),
+				FeeMsat:     uint64(summary.Fees),
+			},
+		)
+	}
+
+	return resp, nil
+}
+
+// HtlcLatencyReport returns the percentiles of the time it took for the HTLCs
+// we've offered to each of our channels and peers to be settled or failed,
+// since lnd was last started.
+func (r *rpcServer) HtlcLatencyReport(ctx context.Context,
+	req *lnrpc.HtlcLatencyReportRequest) (*lnrpc.HtlcLatencyReportResponse,
+	error) {
+
+	rpcsLog.Debugf("[htlclatencyreport]")
+
+	tracker := r.server.htlcSwitch.LatencyTracker()
+
+	resp := &lnrpc.HtlcLatencyReportResponse{}
+	for chanID, stats := range tracker.ChannelStats() {
+		resp.Channels = append(resp.Channels, &lnrpc.ChannelHtlcLatency{
+			ChanId:  chanID.ToUint64(),
+			Latency: marshallHtlcLatencyStats(stats),
+		})
+	}
+	for peer, stats := range tracker.PeerStats() {
+		resp.Peers = append(resp.Peers, &lnrpc.PeerHtlcLatency{
+			PubKey:  hex.EncodeToString(peer[:]),
+			Latency: marshallHtlcLatencyStats(stats),
+		})
+	}
 
-		resp.ForwardingEvents[i] = &lnrpc.ForwardingEvent{
-			Timestamp: uint64(event.Timestamp.Unix()),
-			ChanIdIn:  event.IncomingChanID.ToUint64(),
-			ChanIdOut: event.OutgoingChanID.ToUint64(),
-			AmtIn:     uint64(amtInSat),
-			AmtOut:    uint64(amtOutSat),
-			Fee:       uint64(feeMsat.ToSatoshis()),
-			FeeMsat:   uint64(feeMsat),
-		}
+	// Order the results such that the slowest channels and peers are
+	// listed first.
+	sort.Slice(resp.Channels, func(i, j int) bool {
+		return resp.Channels[i].Latency.P90Ms >
+			resp.Channels[j].Latency.P90Ms
+	})
+	sort.Slice(resp.Peers, func(i, j int) bool {
+		return resp.Peers[i].Latency.P90Ms > resp.Peers[j].Latency.P90Ms
+	})
+
+	// The hold times of the nodes along our routes are already ordered by
+	// the router.
+	for _, holdTime := range r.server.chanRouter.NodeHoldTimes() {
+		resp.Nodes = append(resp.Nodes, &lnrpc.NodeHoldTime{
+			PubKey: hex.EncodeToString(holdTime.Node[:]),
+			HoldTimeMs: uint64(
+				holdTime.HoldTime / time.Millisecond,
+			),
+			NumSamples: holdTime.NumSamples,
+			LastUpdate: holdTime.LastUpdate.Unix(),
+		})
 	}
 
 	return resp, nil
 }
 
+// marshallHtlcLatencyStats converts the latency statistics of a channel or
+// peer into their RPC representation.
+func marshallHtlcLatencyStats(
+	stats htlcswitch.HtlcLatencyStats) *lnrpc.HtlcLatencyStats {
+
+	toMillis := func(d time.Duration) uint64 {
+		return uint64(d / time.Millisecond)
+	}
+
+	return &lnrpc.HtlcLatencyStats{
+		NumSettled: stats.NumSettled,
+		NumFailed:  stats.NumFailed,
+		P50Ms:      toMillis(stats.P50),
+		P90Ms:      toMillis(stats.P90),
+		P99Ms:      toMillis(stats.P99),
+	}
+}
+
 // ExportChannelBackup attempts to return an encrypted static channel backup
 // for the target channel identified by it channel point. The backup is
 // encrypted with a key generated from the aezeed seed of the user. The
@@ -4833,6 +7154,554 @@ func (r *rpcServer) VerifyChanBackup(ctx context.Context,
 	return &lnrpc.VerifyChanBackupResponse{}, nil
 }
 
+// ChanBackupRecoverability reports whether the channels in the latest static
+// channel backup file could be recovered, by verifying that the peer of each
+// channel can be reached and that its recent revoked states are backed up to a
+// watchtower. Unless a refresh is requested, the report of the most recent
+// verification is returned.
+func (r *rpcServer) ChanBackupRecoverability(ctx context.Context,
+	in *lnrpc.RecoverabilityRequest) (*lnrpc.RecoverabilityReport, error) {
+
+	verifier := r.server.backupVerifier
+
+	report := verifier.LatestReport()
+	if in.Refresh || report == nil {
+		var err error
+		report, err = verifier.Verify()
+		if err != nil {
+			return nil, fmt.Errorf("unable to verify channel "+
+				"backup: %v", err)
+		}
+	}
+
+	resp := &lnrpc.RecoverabilityReport{
+		Timestamp: report.Timestamp.Unix(),
+		Channels: make(
+			[]*lnrpc.ChannelRecoverability, 0, len(report.Channels),
+		),
+	}
+	for _, c := range report.Channels {
+		var peerErr string
+		if c.PeerErr != nil {
+			peerErr = c.PeerErr.Error()
+		}
+
+		remotePub := c.RemoteNodePub.SerializeCompressed()
+
+		chanResp := &lnrpc.ChannelRecoverability{
+			ChannelPoint:  c.ChanPoint.String(),
+			RemotePubkey:  hex.EncodeToString(remotePub),
+			ChannelOpen:   c.ChannelOpen,
+			PeerReachable: c.PeerReachable,
+			PeerError:     peerErr,
+			TowerChecked:  c.TowerChecked,
+			RevokedStates: c.RevokedStates,
+			StatesBehind:  c.StatesBehind,
+			TowerCovered:  c.TowerCovered(),
+		}
+		resp.Channels = append(resp.Channels, chanResp)
+	}
+
+	return resp, nil
+}
+
+// ListBackupFiles inspects the static channel backup file and the copies
+// exported to local paths, and returns the outcome of the most recent export to
+// each destination.
+func (r *rpcServer) ListBackupFiles(ctx context.Context,
+	in *lnrpc.ListBackupFilesRequest) (*lnrpc.ListBackupFilesResponse,
+	error) {
+
+	paths := append([]string{cfg.BackupFilePath}, cfg.BackupExport.Paths...)
+
+	resp := &lnrpc.ListBackupFilesResponse{
+		Files: make([]*lnrpc.BackupFile, 0, len(paths)),
+	}
+	for _, path := range paths {
+		// A file that can't be read, e.g. as it's yet to be
+		// exported, is reported as invalid rather than failing the
+		// entire call.
+		info, err := chanbackup.InspectBackupFile(
+			path, r.server.cc.keyRing,
+		)
+		if err != nil {
+			resp.Files = append(resp.Files, &lnrpc.BackupFile{
+				Path:  path,
+				Error: err.Error(),
+			})
+			continue
+		}
+
+		backupFile := &lnrpc.BackupFile{
+			Path:    info.Path,
+			Size:    info.Size,
+			ModTime: info.ModTime.Unix(),
+			Sha256:  hex.EncodeToString(info.Sha256[:]),
+			Valid:   info.Err == nil,
+		}
+		if info.Err != nil {
+			backupFile.Error = info.Err.Error()
+		}
+		for _, chanPoint := range info.ChanPoints {
+			backupFile.ChannelPoints = append(
+				backupFile.ChannelPoints, chanPoint.String(),
+			)
+		}
+		resp.Files = append(resp.Files, backupFile)
+	}
+
+	for _, status := range r.server.backupExporter.Status() {
+		export := &lnrpc.BackupExport{
+			Destination: status.Destination,
+		}
+		if !status.LastAttempt.IsZero() {
+			export.LastAttempt = status.LastAttempt.Unix()
+		}
+		if !status.LastSuccess.IsZero() {
+			export.LastSuccess = status.LastSuccess.Unix()
+		}
+		if status.LastErr != nil {
+			export.LastError = status.LastErr.Error()
+		}
+		resp.Exports = append(resp.Exports, export)
+	}
+
+	return resp, nil
+}
+
+// ExportJournal returns a range of entries of the event journal, after
+// verifying the integrity of the entire journal.
+func (r *rpcServer) ExportJournal(ctx context.Context,
+	in *lnrpc.ExportJournalRequest) (*lnrpc.ExportJournalResponse, error) {
+
+	j := r.server.journal
+	if j == nil {
+		return nil, errJournalInactive
+	}
+
+	if err := j.Verify(); err != nil {
+		return nil, fmt.Errorf("unable to verify journal: %v", err)
+	}
+
+	entries, err := j.Export(in.StartSeq, in.EndSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ExportJournalResponse{
+		Entries: make([]*lnrpc.JournalEntry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &lnrpc.JournalEntry{
+			Seq:         e.Seq,
+			TimestampNs: e.Timestamp.UnixNano(),
+			EventType:   e.Type.String(),
+			Identity:    e.Identity,
+			Details:     e.Details,
+			PrevHash:    e.PrevHash[:],
+			Hash:        e.Hash[:],
+		})
+	}
+
+	return resp, nil
+}
+
+// AnchorJournal commits the hash of the last entry of the event journal
+// on-chain.
+func (r *rpcServer) AnchorJournal(ctx context.Context,
+	in *lnrpc.AnchorJournalRequest) (*lnrpc.AnchorJournalResponse, error) {
+
+	j := r.server.journal
+	if j == nil {
+		return nil, errJournalInactive
+	}
+
+	// Based on the passed fee related parameters, we'll determine an
+	// appropriate fee rate for the anchor transaction.
+	satPerKw := lnwallet.SatPerKVByte(in.SatPerByte * 1000).FeePerKWeight()
+	feePerKw, err := sweep.DetermineFeePerKw(
+		r.server.cc.feeEstimator, sweep.FeePreference{
+			ConfTarget: uint32(in.TargetConf),
+			FeeRate:    satPerKw,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[anchorjournal] sat/kw=%v", int64(feePerKw))
+
+	entry, txid, err := j.Anchor(feePerKw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to anchor journal: %v", err)
+	}
+
+	return &lnrpc.AnchorJournalResponse{
+		Seq:  entry.Seq,
+		Hash: entry.Hash[:],
+		Txid: txid.String(),
+	}, nil
+}
+
+// defaultAuditLogEvents is the number of events of a channel's audit log that
+// are exported if the request doesn't specify a maximum.
+const defaultAuditLogEvents = 1000
+
+// ExportChannelAuditLog returns a range of the audit log of a channel, which
+// records the state update messages exchanged with its peer.
+func (r *rpcServer) ExportChannelAuditLog(ctx context.Context,
+	in *lnrpc.ExportChannelAuditLogRequest) (
+	*lnrpc.ExportChannelAuditLogResponse, error) {
+
+	rpcsLog.Debugf("[exportchanauditlog]")
+
+	if in.ChannelPoint == nil {
+		return nil, errors.New("channel point must be specified")
+	}
+	txid, err := getChanPointFundingTxid(in.ChannelPoint)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	maxEvents := in.MaxEvents
+	if maxEvents == 0 {
+		maxEvents = defaultAuditLogEvents
+	}
+
+	auditLog := r.server.chanDB.ChannelAuditLog()
+	events, err := auditLog.FetchEvents(chanPoint, in.StartSeq, maxEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ExportChannelAuditLogResponse{
+		Events: make([]*lnrpc.ChannelAuditEvent, 0, len(events)),
+	}
+	for _, e := range events {
+		var rawMsg bytes.Buffer
+		if _, err := lnwire.WriteMessage(&rawMsg, e.Msg, 0); err != nil {
+			return nil, err
+		}
+
+		event := &lnrpc.ChannelAuditEvent{
+			Seq:         e.Seq,
+			TimestampNs: e.Timestamp.UnixNano(),
+			Outgoing:    e.Outgoing,
+			MsgType:     e.Msg.MsgType().String(),
+			RawMsg:      rawMsg.Bytes(),
+		}
+
+		switch msg := e.Msg.(type) {
+		case *lnwire.UpdateAddHTLC:
+			event.HtlcId = msg.ID
+			event.AmtMsat = uint64(msg.Amount)
+			event.PaymentHash = msg.PaymentHash[:]
+
+		case *lnwire.UpdateFulfillHTLC:
+			event.HtlcId = msg.ID
+
+		case *lnwire.UpdateFailHTLC:
+			event.HtlcId = msg.ID
+
+		case *lnwire.UpdateFailMalformedHTLC:
+			event.HtlcId = msg.ID
+		}
+
+		resp.Events = append(resp.Events, event)
+	}
+
+	return resp, nil
+}
+
+// Rebalance moves funds from one of our channels to another by paying
+// ourselves along a circular route.
+func (r *rpcServer) Rebalance(ctx context.Context,
+	req *lnrpc.RebalanceRequest) (*lnrpc.RebalanceRecord, error) {
+
+	rpcsLog.Debugf("[rebalance] outgoing_chan_id=%v, incoming_chan_id=%v, "+
+		"amt=%v, max_fee_msat=%v", req.OutgoingChanId,
+		req.IncomingChanId, req.Amt, req.MaxFeeMsat)
+
+	if req.MaxFeeMsat < 0 {
+		return nil, fmt.Errorf("max fee must not be negative")
+	}
+
+	record, err := r.server.rebalancer.Rebalance(&rebalance.Request{
+		OutgoingChanID: req.OutgoingChanId,
+		IncomingChanID: req.IncomingChanId,
+		Amount:         btcutil.Amount(req.Amt),
+		MaxFee:         lnwire.MilliSatoshi(req.MaxFeeMsat),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return marshallRebalance(record), nil
+}
+
+// SetRebalanceTarget sets the target ratio of local balance to capacity of a
+// channel.
+func (r *rpcServer) SetRebalanceTarget(ctx context.Context,
+	req *lnrpc.RebalanceTarget) (*lnrpc.SetRebalanceTargetResponse,
+	error) {
+
+	rpcsLog.Debugf("[setrebalancetarget] chan_id=%v, ratio=%v",
+		req.ChanId, req.Ratio)
+
+	err := r.server.rebalancer.SetTarget(req.ChanId, req.Ratio)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SetRebalanceTargetResponse{}, nil
+}
+
+// RemoveRebalanceTarget removes the target of a channel, such that it's no
+// longer rebalanced automatically.
+func (r *rpcServer) RemoveRebalanceTarget(ctx context.Context,
+	req *lnrpc.RemoveRebalanceTargetRequest) (
+	*lnrpc.RemoveRebalanceTargetResponse, error) {
+
+	rpcsLog.Debugf("[removerebalancetarget] chan_id=%v", req.ChanId)
+
+	if err := r.server.rebalancer.RemoveTarget(req.ChanId); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.RemoveRebalanceTargetResponse{}, nil
+}
+
+// ListRebalanceTargets returns the targets of all channels that have one.
+func (r *rpcServer) ListRebalanceTargets(ctx context.Context,
+	_ *lnrpc.ListRebalanceTargetsRequest) (
+	*lnrpc.ListRebalanceTargetsResponse, error) {
+
+	rpcsLog.Debugf("[listrebalancetargets]")
+
+	targets, err := r.server.rebalancer.ListTargets()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListRebalanceTargetsResponse{
+		Targets: make([]*lnrpc.RebalanceTarget, 0, len(targets)),
+	}
+	for _, target := range targets {
+		resp.Targets = append(resp.Targets, &lnrpc.RebalanceTarget{
+			ChanId: target.ChanID,
+			Ratio:  target.Ratio,
+		})
+	}
+
+	return resp, nil
+}
+
+// ListRebalances returns all completed rebalances, along with the routing fees
+// spent on rebalances within the last 24 hours.
+func (r *rpcServer) ListRebalances(ctx context.Context,
+	_ *lnrpc.ListRebalancesRequest) (*lnrpc.ListRebalancesResponse,
+	error) {
+
+	rpcsLog.Debugf("[listrebalances]")
+
+	rebalances, err := r.server.rebalancer.ListRebalances()
+	if err != nil {
+		return nil, err
+	}
+	feesSpent, err := r.server.rebalancer.FeesSpent()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListRebalancesResponse{
+		Rebalances: make(
+			[]*lnrpc.RebalanceRecord, 0, len(rebalances),
+		),
+		FeesSpentMsat: int64(feesSpent),
+	}
+	for _, record := range rebalances {
+		resp.Rebalances = append(
+			resp.Rebalances, marshallRebalance(record),
+		)
+	}
+
+	return resp, nil
+}
+
+// marshallRebalance converts a completed rebalance into its RPC
+// representation.
+func marshallRebalance(record *rebalance.Rebalance) *lnrpc.RebalanceRecord {
+	return &lnrpc.RebalanceRecord{
+		Id:             record.ID,
+		Timestamp:      record.Timestamp.Unix(),
+		OutgoingChanId: record.OutgoingChanID,
+		IncomingChanId: record.IncomingChanID,
+		Amt:            int64(record.Amount),
+		FeeMsat:        int64(record.Fee),
+		Automatic:      record.Automatic,
+	}
+}
+
+// SetFeeSchedule adds or replaces the fee schedule of a channel.
+func (r *rpcServer) SetFeeSchedule(ctx context.Context,
+	req *lnrpc.FeeSchedule) (*lnrpc.SetFeeScheduleResponse, error) {
+
+	rpcsLog.Debugf("[setfeeschedule] chan_id=%v, rules=%d", req.ChanId,
+		len(req.Rules))
+
+	schedule := &feepolicy.Schedule{
+		ChanID: req.ChanId,
+		Rules:  make([]feepolicy.Rule, 0, len(req.Rules)),
+	}
+
+	var err error
+	schedule.Default, err = unmarshallFeePolicy(req.DefaultPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("default policy: %v", err)
+	}
+
+	for i, rpcRule := range req.Rules {
+		policy, err := unmarshallFeePolicy(rpcRule.Policy)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i, err)
+		}
+
+		schedule.Rules = append(schedule.Rules, feepolicy.Rule{
+			BelowLocalRatio: rpcRule.BelowLocalRatio,
+			AboveLocalRatio: rpcRule.AboveLocalRatio,
+			StartHour:       rpcRule.StartHour,
+			EndHour:         rpcRule.EndHour,
+			Policy:          policy,
+		})
+	}
+
+	if err := r.server.feePolicyMgr.SetSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SetFeeScheduleResponse{}, nil
+}
+
+// RemoveFeeSchedule removes the fee schedule of a channel.
+func (r *rpcServer) RemoveFeeSchedule(ctx context.Context,
+	req *lnrpc.RemoveFeeScheduleRequest) (*lnrpc.RemoveFeeScheduleResponse,
+	error) {
+
+	rpcsLog.Debugf("[removefeeschedule] chan_id=%v", req.ChanId)
+
+	if err := r.server.feePolicyMgr.RemoveSchedule(req.ChanId); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.RemoveFeeScheduleResponse{}, nil
+}
+
+// ListFeeSchedules returns the fee schedules of all channels that have one.
+func (r *rpcServer) ListFeeSchedules(ctx context.Context,
+	_ *lnrpc.ListFeeSchedulesRequest) (*lnrpc.ListFeeSchedulesResponse,
+	error) {
+
+	rpcsLog.Debugf("[listfeeschedules]")
+
+	schedules, err := r.server.feePolicyMgr.ListSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListFeeSchedulesResponse{
+		Schedules: make([]*lnrpc.FeeSchedule, 0, len(schedules)),
+	}
+	for _, schedule := range schedules {
+		rpcSchedule := &lnrpc.FeeSchedule{
+			ChanId:        schedule.ChanID,
+			DefaultPolicy: marshallFeePolicy(&schedule.Default),
+			Rules: make(
+				[]*lnrpc.FeeRule, 0, len(schedule.Rules),
+			),
+		}
+		for _, rule := range schedule.Rules {
+			rpcRule := &lnrpc.FeeRule{
+				BelowLocalRatio: rule.BelowLocalRatio,
+				AboveLocalRatio: rule.AboveLocalRatio,
+				StartHour:       rule.StartHour,
+				EndHour:         rule.EndHour,
+				Policy:          marshallFeePolicy(&rule.Policy),
+			}
+			rpcSchedule.Rules = append(rpcSchedule.Rules, rpcRule)
+		}
+
+		resp.Schedules = append(resp.Schedules, rpcSchedule)
+	}
+
+	return resp, nil
+}
+
+// unmarshallFeePolicy converts a fee policy received over RPC, ensuring its
+// time lock delta is large enough.
+func unmarshallFeePolicy(rpcPolicy *lnrpc.FeePolicy) (feepolicy.Policy,
+	error) {
+
+	if rpcPolicy == nil {
+		return feepolicy.Policy{}, fmt.Errorf("policy must be set")
+	}
+
+	if rpcPolicy.TimeLockDelta < minTimeLockDelta {
+		return feepolicy.Policy{}, fmt.Errorf("time lock delta of %v "+
+			"is too small, minimum supported is %v",
+			rpcPolicy.TimeLockDelta, minTimeLockDelta)
+	}
+
+	return feepolicy.Policy{
+		BaseFee:       lnwire.MilliSatoshi(rpcPolicy.BaseFeeMsat),
+		FeeRate:       rpcPolicy.FeeRatePpm,
+		TimeLockDelta: rpcPolicy.TimeLockDelta,
+	}, nil
+}
+
+// marshallFeePolicy converts a fee policy into its RPC representation.
+func marshallFeePolicy(policy *feepolicy.Policy) *lnrpc.FeePolicy {
+	return &lnrpc.FeePolicy{
+		BaseFeeMsat:   uint64(policy.BaseFee),
+		FeeRatePpm:    policy.FeeRate,
+		TimeLockDelta: policy.TimeLockDelta,
+	}
+}
+
+// GetChainStatus returns the health of the chain backend as of its last poll.
+func (r *rpcServer) GetChainStatus(ctx context.Context,
+	_ *lnrpc.GetChainStatusRequest) (*lnrpc.GetChainStatusResponse,
+	error) {
+
+	rpcsLog.Tracef("[getchainstatus]")
+
+	status := r.server.chainHealth.Status()
+
+	resp := &lnrpc.GetChainStatusResponse{
+		Backend:        status.Backend,
+		Reachable:      status.Reachable,
+		Synced:         status.Synced,
+		NetworkHeight:  uint32(status.NetworkHeight),
+		LatencyMs:      int64(status.Latency / time.Millisecond),
+		AvgLatencyMs:   int64(status.AvgLatency / time.Millisecond),
+		NumDisconnects: status.NumDisconnects,
+		NumAlerts:      status.NumAlerts,
+	}
+	if status.BestHeight > 0 {
+		resp.BestHash = status.BestHash.String()
+		resp.BestHeight = uint32(status.BestHeight)
+		resp.BestBlockTimestamp = status.BestBlockTime.Unix()
+	}
+	if !status.LastPoll.IsZero() {
+		resp.LastPollTimestamp = status.LastPoll.Unix()
+	}
+	if status.LastError != nil {
+		resp.LastError = status.LastError.Error()
+	}
+
+	return resp, nil
+}
+
 // createBackupSnapshot converts the passed Single backup into a snapshot which
 // contains individual packed single backups, as well as a single packed multi
 // backup.
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"image/color"
+	"io/ioutil"
 	"math/big"
 	prand "math/rand"
 	"net"
@@ -22,29 +23,40 @@ import (
 	"github.com/litecoinfinance/btcd/connmgr"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
-	"github.com/coreos/bbolt"
 	"github.com/go-errors/errors"
 	sphinx "github.com/litecoinfinance/lightning-onion"
 	"github.com/litecoinfinance/lnd/autopilot"
 	"github.com/litecoinfinance/lnd/brontide"
+	"github.com/litecoinfinance/lnd/chainhealth"
 	"github.com/litecoinfinance/lnd/chanbackup"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 	"github.com/litecoinfinance/lnd/channelnotifier"
 	"github.com/litecoinfinance/lnd/contractcourt"
+	"github.com/litecoinfinance/lnd/dbbackup"
 	"github.com/litecoinfinance/lnd/discovery"
+	"github.com/litecoinfinance/lnd/feepolicy"
 	"github.com/litecoinfinance/lnd/htlcswitch"
 	"github.com/litecoinfinance/lnd/input"
 	"github.com/litecoinfinance/lnd/invoices"
+	"github.com/litecoinfinance/lnd/journal"
 	"github.com/litecoinfinance/lnd/lncfg"
 	"github.com/litecoinfinance/lnd/lnpeer"
 	"github.com/litecoinfinance/lnd/lnrpc"
+	"github.com/litecoinfinance/lnd/lnrpc/invoicesrpc"
+	"github.com/litecoinfinance/lnd/lntypes"
 	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/nat"
 	"github.com/litecoinfinance/lnd/netann"
+	"github.com/litecoinfinance/lnd/paysched"
 	"github.com/litecoinfinance/lnd/pool"
+	"github.com/litecoinfinance/lnd/rebalance"
+	"github.com/litecoinfinance/lnd/resources"
 	"github.com/litecoinfinance/lnd/routing"
 	"github.com/litecoinfinance/lnd/routing/route"
+	"github.com/litecoinfinance/lnd/subscribe"
+	"github.com/litecoinfinance/lnd/swap"
 	"github.com/litecoinfinance/lnd/sweep"
 	"github.com/litecoinfinance/lnd/ticker"
 	"github.com/litecoinfinance/lnd/tor"
@@ -74,6 +86,15 @@ const (
 	// value used or a particular peer will be chosen between 0s and this
 	// value.
 	maxInitReconnectDelay = 30
+
+	// fwdLogPruneInterval is the interval at which the forwarding events
+	// that have outlived their retention are pruned from the forwarding
+	// log.
+	fwdLogPruneInterval = time.Hour
+
+	// gcInterval is the interval at which the payments and invoices
+	// selected by the garbage collection policy are deleted.
+	gcInterval = time.Hour
 )
 
 var (
@@ -124,6 +145,12 @@ type server struct {
 
 	chanStatusMgr *netann.ChanStatusManager
 
+	// closeSigner is an optional external signer that produces our
+	// signatures for cooperative close transactions, e.g. by running an
+	// aggregation protocol among a set of threshold signers. If nil, the
+	// wallet's signer is used directly.
+	closeSigner lnwallet.CloseSigner
+
 	// listenAddrs is the list of addresses the server is currently
 	// listening on.
 	listenAddrs []net.Addr
@@ -191,10 +218,32 @@ type server struct {
 
 	authGossiper *discovery.AuthenticatedGossiper
 
+	resourceMgr *resources.Manager
+
 	utxoNursery *utxoNursery
 
 	sweeper *sweep.UtxoSweeper
 
+	feeBudget *routing.FeeBudget
+
+	paymentScheduler *paysched.Scheduler
+
+	rebalancer *rebalance.Rebalancer
+
+	feePolicyMgr *feepolicy.Manager
+
+	chainHealth *chainhealth.Monitor
+
+	// journal records auditable events in a hash chain. It's nil unless
+	// the journal has been activated.
+	journal *journal.Journal
+
+	// dbBackuper backs up snapshots of the channel database. It's nil
+	// unless database backups are enabled.
+	dbBackuper *dbbackup.Backuper
+
+	swapManager *swap.Manager
+
 	chainArb *contractcourt.ChainArbitrator
 
 	sphinx *htlcswitch.OnionProcessor
@@ -225,6 +274,27 @@ type server struct {
 	// channelNotifier to be notified of newly opened and closed channels.
 	chanSubSwapper *chanbackup.SubSwapper
 
+	// backupVerifier periodically verifies that the channels of the
+	// on-disk channel backup could be recovered.
+	backupVerifier *chanbackup.Verifier
+
+	// backupExporter copies the on-disk channel backup to the configured
+	// destinations each time it's updated.
+	backupExporter *chanbackup.Exporter
+
+	// bootstrapRequests is used by the gossiper to signal the peer
+	// bootstrapper that it has been left without any gossip syncers, so
+	// fresh peers should be dialed.
+	bootstrapRequests chan struct{}
+
+	// dualFundAcceptor hands the requests of peers for us to contribute
+	// funds to their channels to the clients of the DualFundAcceptor RPC.
+	dualFundAcceptor *dualFundAcceptor
+
+	// channelAcceptor hands the requests of peers to open a channel with
+	// us to the clients of the ChannelAcceptor RPC.
+	channelAcceptor *channelAcceptor
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -333,6 +403,16 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 		}
 		return uint32(invoice.MinFinalCLTVExpiry()), nil
 	}
+	decodePayReq := func(payReq string) (*zpay32.Invoice, error) {
+		return zpay32.Decode(payReq, activeNetParams.Params)
+	}
+
+	// Only snapshot the exchange rates of invoices denominated in a fiat
+	// currency if any rates were configured.
+	var fiatRates invoices.FiatRateProvider
+	if len(cfg.fiatRates) > 0 {
+		fiatRates = cfg.fiatRates
+	}
 
 	s := &server{
 		chanDB:         chanDB,
@@ -342,7 +422,10 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 		readPool:       readPool,
 		chansToRestore: chansToRestore,
 
-		invoices: invoices.NewRegistry(chanDB, decodeFinalCltvExpiry),
+		invoices: invoices.NewRegistry(
+			chanDB, decodeFinalCltvExpiry, decodePayReq, fiatRates,
+			cc.chainNotifier, defaultFinalCltvRejectDelta,
+		),
 
 		channelNotifier: channelnotifier.New(chanDB),
 
@@ -370,9 +453,13 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 
 		globalFeatures: lnwire.NewFeatureVector(globalFeatures,
 			lnwire.GlobalFeatures),
-		quit: make(chan struct{}),
+		bootstrapRequests: make(chan struct{}, 1),
+		quit:              make(chan struct{}),
 	}
 
+	s.dualFundAcceptor = newDualFundAcceptor(dualFundAcceptTimeout, s.quit)
+	s.channelAcceptor = newChannelAcceptor(channelAcceptTimeout, s.quit)
+
 	s.witnessBeacon = &preimageBeacon{
 		invoices:    s.invoices,
 		wCache:      chanDB.NewWitnessCache(),
@@ -607,6 +694,28 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 	}
 	s.currentNodeAnn = nodeAnn
 
+	// The routing fees of all outgoing payments are bounded by the daily
+	// fee budget, which must also account for the payments made within
+	// its window before we were restarted.
+	payments, err := chanDB.FetchAllPayments()
+	if err != nil && err != channeldb.ErrNoPaymentsCreated {
+		return nil, err
+	}
+	feeSpends := make([]routing.FeeSpend, 0, len(payments))
+	for _, payment := range payments {
+		feeSpends = append(feeSpends, routing.FeeSpend{
+			Time: payment.CreationDate,
+			Fee:  payment.Fee,
+		})
+	}
+	s.feeBudget = routing.NewFeeBudget(&routing.FeeBudgetConfig{
+		DailyBudget: lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(cfg.Payments.DailyFeeBudget),
+		),
+		WarnPercent:   cfg.Payments.FeeBudgetWarnPercent,
+		InitialSpends: feeSpends,
+	})
+
 	s.chanRouter, err = routing.New(routing.Config{
 		Graph:     chanGraph,
 		Chain:     cc.chainIO,
@@ -626,6 +735,18 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 				firstHop, htlcAdd, errorDecryptor,
 			)
 		},
+		SendShardToSwitch: func(firstHop lnwire.ShortChannelID,
+			htlcAdd *lnwire.UpdateAddHTLC,
+			circuit *sphinx.Circuit) ([32]byte, error) {
+
+			errorDecryptor := &htlcswitch.SphinxErrorDecrypter{
+				OnionErrorDecrypter: sphinx.NewOnionErrorDecrypter(circuit),
+			}
+
+			return s.htlcSwitch.SendHTLCShard(
+				firstHop, htlcAdd, errorDecryptor,
+			)
+		},
 		ChannelPruneExpiry: routing.DefaultChannelPruneExpiry,
 		GraphPruneInterval: time.Duration(time.Hour),
 		QueryBandwidth: func(edge *channeldb.ChannelEdgeInfo) lnwire.MilliSatoshi {
@@ -658,11 +779,187 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 			return link.Bandwidth()
 		},
 		AssumeChannelValid: cfg.Routing.UseAssumeChannelValid(),
+		FeeBudget:          s.feeBudget,
+		PathWeights: &routing.PathWeights{
+			FeeWeight:            cfg.PathFinding.FeeWeight,
+			RiskFactorBillionths: cfg.PathFinding.RiskFactor,
+			ProbabilityWeight:    cfg.PathFinding.ProbabilityWeight,
+			HoldTimeWeight:       cfg.PathFinding.HoldTimeWeight,
+		},
+		MaxCltvExpiry: cfg.PathFinding.MaxCltvExpiry,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %v", err)
 	}
 
+	scheduleStore, err := paysched.NewStore(chanDB)
+	if err != nil {
+		return nil, err
+	}
+	s.paymentScheduler = paysched.New(&paysched.Config{
+		Store: scheduleStore,
+		DecodePayReq: func(payReq string) (*zpay32.Invoice, error) {
+			return zpay32.Decode(payReq, activeNetParams.Params)
+		},
+		SendPayment: func(payment *routing.LightningPayment) ([32]byte,
+			*route.Route, error) {
+
+			preimage, rt, err := s.chanRouter.SendPayment(payment)
+			if err != nil {
+				return preimage, nil, err
+			}
+
+			// Save the completed payment to the database, so that
+			// scheduled payments show up alongside all others.
+			err = savePayment(
+				chanDB, payment.Amount, preimage[:], rt,
+			)
+			if err != nil {
+				srvrLog.Errorf("Unable to save scheduled payment: "+
+					"%v", err)
+			}
+			s.recordPaymentSent(payment.PaymentHash, rt)
+
+			return preimage, rt, nil
+		},
+		Ticker:                 ticker.New(paysched.DefaultTickInterval),
+		MaxConsecutiveFailures: paysched.DefaultMaxConsecutiveFailures,
+		RetryDelay:             paysched.DefaultRetryDelay,
+	})
+
+	rebalanceStore, err := rebalance.NewStore(chanDB)
+	if err != nil {
+		return nil, err
+	}
+	rebalanceCfg := &rebalance.Config{
+		Store:             rebalanceStore,
+		FetchChannels:     s.fetchChannelBalances,
+		FindCircularRoute: s.chanRouter.FindCircularRoute,
+		AddInvoice:        s.addRebalanceInvoice,
+		SendToRoute: func(routes []*route.Route,
+			payment *routing.LightningPayment) ([32]byte,
+			*route.Route, error) {
+
+			preimage, rt, err := s.chanRouter.SendToRoute(
+				routes, payment,
+			)
+			if err != nil {
+				return preimage, nil, err
+			}
+
+			// Rebalances are saved alongside all other payments,
+			// such that their fees are accounted for.
+			err = savePayment(
+				chanDB, rt.TotalAmount-rt.TotalFees,
+				preimage[:], rt,
+			)
+			if err != nil {
+				srvrLog.Errorf("Unable to save rebalance "+
+					"payment: %v", err)
+			}
+			s.recordPaymentSent(payment.PaymentHash, rt)
+
+			return preimage, rt, nil
+		},
+		MaxFeeRate: cfg.Rebalance.MaxFeeRate,
+		DailyFeeBudget: btcutil.Amount(
+			cfg.Rebalance.DailyFeeBudget,
+		),
+		Tolerance: cfg.Rebalance.Tolerance,
+	}
+	if cfg.Rebalance.Active {
+		rebalanceCfg.Ticker = ticker.New(cfg.Rebalance.Interval)
+	}
+	s.rebalancer = rebalance.New(rebalanceCfg)
+
+	feePolicyStore, err := feepolicy.NewStore(chanDB)
+	if err != nil {
+		return nil, err
+	}
+	s.feePolicyMgr = feepolicy.New(&feepolicy.Config{
+		Store:         feePolicyStore,
+		FetchChannels: s.fetchFeePolicyBalances,
+		UpdatePolicy: func(chanPoint wire.OutPoint,
+			policy *feepolicy.Policy) error {
+
+			return s.updateChannelPolicy(
+				policy.BaseFee, policy.FeeRate,
+				policy.TimeLockDelta, chanPoint,
+			)
+		},
+		Ticker: ticker.New(cfg.FeePolicy.Interval),
+		Now:    time.Now,
+	})
+
+	s.chainHealth = chainhealth.New(&chainhealth.Config{
+		Backend:       cc.wc.BackEnd(),
+		Chain:         cc.chainSource,
+		NetworkHeight: cc.networkHeight,
+		MaxLag:        cfg.ChainHealth.MaxLag,
+		MaxTipAge:     cfg.ChainHealth.MaxTipAge,
+		Ticker:        ticker.New(cfg.ChainHealth.Interval),
+		Now:           time.Now,
+	})
+
+	if cfg.Journal.Active {
+		journalStore, err := journal.NewStore(chanDB)
+		if err != nil {
+			return nil, err
+		}
+		s.journal, err = journal.New(&journal.Config{
+			Store: journalStore,
+			PaymentThreshold: btcutil.Amount(
+				cfg.Journal.PaymentThreshold,
+			),
+			SubscribeChannelEvents: func() (*subscribe.Client,
+				error) {
+
+				return s.channelNotifier.SubscribeChannelEvents()
+			},
+			SubscribeInvoices: func() *invoices.InvoiceSubscription {
+				return s.invoices.SubscribeNotifications(0, 0)
+			},
+			PublishAnchor: func(pkScript []byte,
+				feeRate lnwallet.SatPerKWeight) (*chainhash.Hash,
+				error) {
+
+				anchorOut := &wire.TxOut{PkScript: pkScript}
+				tx, err := s.cc.wallet.SendOutputs(
+					[]*wire.TxOut{anchorOut}, feeRate,
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				txid := tx.TxHash()
+				return &txid, nil
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.DBBackup.Enabled() {
+		backupKey, err := dbbackup.LoadOrCreateKey(cfg.DBBackup.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		backupStore, err := dbbackup.NewStore(
+			cfg.DBBackup.Dir, backupKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.dbBackuper = dbbackup.NewBackuper(&dbbackup.Config{
+			WriteSnapshot: chanDB.WriteSnapshot,
+			Store:         backupStore,
+			Interval:      cfg.DBBackup.Interval,
+			NumSnapshots:  cfg.DBBackup.NumSnapshots,
+		})
+	}
+
 	chanSeries := discovery.NewChanSeries(s.chanDB.ChannelGraph())
 	gossipMessageStore, err := discovery.NewMessageStore(s.chanDB)
 	if err != nil {
@@ -673,6 +970,13 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 		return nil, err
 	}
 
+	// We'll only fall back to bootstrapping fresh peers when left without
+	// any gossip syncers if network bootstrapping is enabled at all.
+	var noSyncersTimeout time.Duration
+	if netBootstrapEnabled() {
+		noSyncersTimeout = cfg.NoSyncersTimeout
+	}
+
 	s.authGossiper = discovery.New(discovery.Config{
 		Router:               s.chanRouter,
 		Notifier:             s.cc.chainNotifier,
@@ -689,11 +993,36 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 		AnnSigner:            s.nodeSigner,
 		RotateTicker:         ticker.New(discovery.DefaultSyncerRotationInterval),
 		HistoricalSyncTicker: ticker.New(cfg.HistoricalSyncInterval),
-		NumActiveSyncers:     cfg.NumGraphSyncPeers,
+		SyncerHealthCheckTicker: ticker.New(
+			discovery.DefaultSyncerHealthCheckInterval,
+		),
+		StaleSyncerTimeout:      cfg.StaleSyncerTimeout,
+		NoSyncersTimeout:        noSyncersTimeout,
+		RequestBootstrap:        s.requestBootstrap,
+		KeepAliveUpdateInterval: cfg.KeepAliveUpdateInterval,
+		NumActiveSyncers:        cfg.NumGraphSyncPeers,
+		GossipHorizon:           cfg.GossipHorizon,
+		RequestBatchSize:        cfg.resourceBudget.GossipBatchSize,
+		IsChainSynced: func() (bool, error) {
+			synced, _, err := s.cc.wallet.IsSynced()
+			return synced, err
+		},
+		ChainSyncTicker: ticker.New(
+			discovery.DefaultChainSyncPollInterval,
+		),
 	},
 		s.identityPriv.PubKey(),
 	)
 
+	syncMgr := s.authGossiper.SyncManager()
+	s.resourceMgr = resources.NewManager(&resources.Config{
+		Profile:                resources.Profile(cfg.ResourceProfile),
+		Budget:                 cfg.resourceBudget,
+		SetCacheSizes:          chanGraph.SetCacheSizes,
+		SetBatchCommitInterval: chanGraph.SetBatchCommitInterval,
+		SetGossipBatchSize:     syncMgr.SetRequestBatchSize,
+	})
+
 	utxnStore, err := newNurseryStore(activeNetParams.GenesisHash, chanDB)
 	if err != nil {
 		srvrLog.Errorf("unable to create nursery store: %v", err)
@@ -719,8 +1048,9 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 		Signer:             cc.wallet.Cfg.Signer,
 		PublishTransaction: cc.wallet.PublishTransaction,
 		NewBatchTimer: func() <-chan time.Time {
-			return time.NewTimer(sweep.DefaultBatchWindowDuration).C
+			return time.NewTimer(cfg.Sweeper.BatchWindowDuration).C
 		},
+		FeeRateBucketSize:    cfg.Sweeper.FeeRateBucketSize,
 		SweepTxConfTarget:    6,
 		Notifier:             cc.chainNotifier,
 		ChainIO:              cc.chainIO,
@@ -746,7 +1076,7 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 		closureType htlcswitch.ChannelCloseType) {
 		// TODO(conner): Properly respect the update and error channels
 		// returned by CloseLink.
-		s.htlcSwitch.CloseLink(chanPoint, closureType, 0)
+		s.htlcSwitch.CloseLink(chanPoint, closureType, 0, 0, 0, nil)
 	}
 
 	// We will use the following channel to reliably hand off contract
@@ -761,6 +1091,20 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 			return newSweepPkScript(cc.wallet)
 		},
 		PublishTx: cc.wallet.PublishTransaction,
+		BumpCommitFee: func(anchor *lnwallet.AnchorResolution) error {
+			// We aim for the commitment to confirm within the
+			// same target we use for funding transactions.
+			feeRate, err := cc.feeEstimator.EstimateFeePerKW(6)
+			if err != nil {
+				return err
+			}
+
+			_, err = cc.wallet.BumpCommitFee(anchor, feeRate)
+			if err == lnwallet.ErrCommitFeeSufficient {
+				return nil
+			}
+			return err
+		},
 		DeliverResolutionMsg: func(msgs ...contractcourt.ResolutionMsg) error {
 			for _, msg := range msgs {
 				err := s.htlcSwitch.ProcessContractResolution(msg)
@@ -832,6 +1176,29 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 		NotifyClosedChannel: s.channelNotifier.NotifyClosedChannelEvent,
 	}, chanDB)
 
+	swapStore, err := swap.NewStore(chanDB)
+	if err != nil {
+		return nil, err
+	}
+	s.swapManager = swap.New(&swap.Config{
+		Store:      swapStore,
+		KeyRing:    cc.keyRing,
+		Notifier:   cc.chainNotifier,
+		PreimageDB: s.witnessBeacon,
+		SweepInput: s.sweeper.SweepInput,
+		BestHeight: func() (uint32, error) {
+			_, height, err := cc.chainIO.GetBestBlock()
+			return uint32(height), err
+		},
+		SubscribeInvoice: func(hash lntypes.Hash) (
+			<-chan *channeldb.Invoice, func()) {
+
+			sub := s.invoices.SubscribeSingleInvoice(hash)
+			return sub.Updates, sub.Cancel
+		},
+		SettleInvoice: s.invoices.SettleHodlInvoice,
+	})
+
 	s.breachArbiter = newBreachArbiter(&BreachConfig{
 		CloseLink: closeLink,
 		DB:        chanDB,
@@ -868,6 +1235,7 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 		PublishTransaction: cc.wallet.PublishTransaction,
 		Notifier:           cc.chainNotifier,
 		FeeEstimator:       cc.feeEstimator,
+		CommitFeeBounds:    commitFeeBounds(cfg.CommitFee),
 		SignMessage: func(pubKey *btcec.PublicKey,
 			msg []byte) (*btcec.Signature, error) {
 
@@ -990,6 +1358,18 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 			cid := lnwire.NewChanIDFromOutPoint(&chanPoint)
 			return s.htlcSwitch.UpdateShortChanID(cid)
 		},
+		WatchSplicedChannel: s.chainArb.SpliceChannel,
+		DisconnectPeer: func(pubKey *btcec.PublicKey) error {
+			peer, err := s.FindPeer(pubKey)
+			if err != nil {
+				return err
+			}
+
+			// Unlike DisconnectPeer of the server, we keep the
+			// peer persistent, so that we reconnect to it.
+			peer.Disconnect(errors.New("splice aborted"))
+			return nil
+		},
 		RequiredRemoteChanReserve: func(chanAmt,
 			dustLimit btcutil.Amount) btcutil.Amount {
 
@@ -1021,6 +1401,8 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 		ReservationTimeout:     10 * time.Minute,
 		MinChanSize:            btcutil.Amount(cfg.MinChanSize),
 		NotifyOpenChannelEvent: s.channelNotifier.NotifyOpenChannelEvent,
+		DualFundAcceptor:       s.dualFundAcceptor.Accept,
+		ChannelAcceptor:        s.channelAcceptor.Accept,
 	})
 	if err != nil {
 		return nil, err
@@ -1037,13 +1419,47 @@ func newServer(listenAddrs []net.Addr, chanDB *channeldb.DB, cc *chainControl,
 	if err != nil {
 		return nil, err
 	}
+
+	// Each time the backup file is swapped out, the new backup is also
+	// exported to the additional destinations configured.
+	var uploaders []chanbackup.Uploader
+	for _, path := range cfg.BackupExport.Paths {
+		uploaders = append(uploaders, chanbackup.NewFileUploader(path))
+	}
+	for _, url := range cfg.BackupExport.URLs {
+		uploaders = append(uploaders, chanbackup.NewHTTPUploader(
+			url, cfg.BackupExport.Timeout,
+		))
+	}
+	s.backupExporter = chanbackup.NewExporter(backupFile, uploaders...)
+
 	s.chanSubSwapper, err = chanbackup.NewSubSwapper(
-		startingChans, chanNotifier, s.cc.keyRing, backupFile,
+		startingChans, chanNotifier, s.cc.keyRing, s.backupExporter,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// We'll also verify the recoverability of the backup file's channels.
+	// lnd doesn't run a watchtower client yet, so their watchtower
+	// coverage isn't checked.
+	s.backupVerifier = chanbackup.NewVerifier(&chanbackup.VerifierConfig{
+		FetchBackup: func() (*chanbackup.Multi, error) {
+			// The backup file is swapped out on each update, so
+			// we read it anew each time.
+			multiBytes, err := ioutil.ReadFile(cfg.BackupFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			packedMulti := chanbackup.PackedMulti(multiBytes)
+			return packedMulti.Unpack(s.cc.keyRing)
+		},
+		ChanSource: s.chanDB,
+		Prober:     s,
+		Interval:   cfg.BackupVerifyInterval,
+	})
+
 	// Create the connection manager which will be responsible for
 	// maintaining persistent outbound connections and also accepting new
 	// incoming connections
@@ -1087,6 +1503,16 @@ func (s *server) Start() error {
 			go s.watchExternalIP()
 		}
 
+		if cfg.FwdLog.RetentionDays > 0 {
+			s.wg.Add(1)
+			go s.pruneForwardingLog(cfg.FwdLog.RetentionDays)
+		}
+
+		if cfg.GC.Enabled() {
+			s.wg.Add(1)
+			go s.collectGarbage(cfg.GC)
+		}
+
 		// Start the notification server. This is used so channel
 		// management goroutines can be notified when a funding
 		// transaction reaches a sufficient number of confirmations, or
@@ -1132,6 +1558,10 @@ func (s *server) Start() error {
 			startErr = err
 			return
 		}
+		if err := s.swapManager.Start(); err != nil {
+			startErr = err
+			return
+		}
 		if err := s.breachArbiter.Start(); err != nil {
 			startErr = err
 			return
@@ -1140,10 +1570,30 @@ func (s *server) Start() error {
 			startErr = err
 			return
 		}
+		if err := s.feeBudget.Start(); err != nil {
+			startErr = err
+			return
+		}
 		if err := s.chanRouter.Start(); err != nil {
 			startErr = err
 			return
 		}
+		if err := s.paymentScheduler.Start(); err != nil {
+			startErr = err
+			return
+		}
+		if err := s.rebalancer.Start(); err != nil {
+			startErr = err
+			return
+		}
+		if err := s.feePolicyMgr.Start(); err != nil {
+			startErr = err
+			return
+		}
+		if err := s.chainHealth.Start(); err != nil {
+			startErr = err
+			return
+		}
 		if err := s.fundingMgr.Start(); err != nil {
 			startErr = err
 			return
@@ -1194,6 +1644,25 @@ func (s *server) Start() error {
 			return
 		}
 
+		if err := s.backupVerifier.Start(); err != nil {
+			startErr = err
+			return
+		}
+
+		if s.journal != nil {
+			if err := s.journal.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
+		if s.dbBackuper != nil {
+			if err := s.dbBackuper.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		s.connMgr.Start()
 
 		// With all the relevant sub-systems started, we'll now attempt
@@ -1215,9 +1684,7 @@ func (s *server) Start() error {
 		// configure the set of active bootstrappers, and launch a
 		// dedicated goroutine to maintain a set of persistent
 		// connections.
-		if !cfg.NoNetBootstrap && !(cfg.Bitcoin.SimNet || cfg.Litecoinfinance.SimNet) &&
-			!(cfg.Bitcoin.RegTest || cfg.Litecoinfinance.RegTest) {
-
+		if netBootstrapEnabled() {
 			bootstrappers, err := initNetworkBootstrappers(s)
 			if err != nil {
 				startErr = err
@@ -1255,13 +1722,19 @@ func (s *server) Stop() error {
 		// Shutdown the wallet, funding manager, and the rpc server.
 		s.chanStatusMgr.Stop()
 		s.cc.chainNotifier.Stop()
+		s.feePolicyMgr.Stop()
+		s.chainHealth.Stop()
+		s.rebalancer.Stop()
+		s.paymentScheduler.Stop()
 		s.chanRouter.Stop()
+		s.feeBudget.Stop()
 		s.htlcSwitch.Stop()
 		s.sphinx.Stop()
 		s.utxoNursery.Stop()
 		s.breachArbiter.Stop()
 		s.authGossiper.Stop()
 		s.chainArb.Stop()
+		s.swapManager.Stop()
 		s.sweeper.Stop()
 		s.channelNotifier.Stop()
 		s.cc.wallet.Shutdown()
@@ -1271,6 +1744,13 @@ func (s *server) Stop() error {
 		s.invoices.Stop()
 		s.fundingMgr.Stop()
 		s.chanSubSwapper.Stop()
+		s.backupVerifier.Stop()
+		if s.journal != nil {
+			s.journal.Stop()
+		}
+		if s.dbBackuper != nil {
+			s.dbBackuper.Stop()
+		}
 
 		// Disconnect from each active peers to ensure that
 		// peerTerminationWatchers signal completion to each peer.
@@ -1295,6 +1775,143 @@ func (s *server) Stopped() bool {
 	return atomic.LoadInt32(&s.stopping) != 0
 }
 
+// fetchChannelBalances returns the balances of all of our open channels, for
+// the rebalancer to check them against their targets.
+func (s *server) fetchChannelBalances() ([]*rebalance.ChannelBalance, error) {
+	channels, err := s.chanDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]*rebalance.ChannelBalance, 0, len(channels))
+	for _, channel := range channels {
+		chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+		localBalance := channel.LocalCommitment.LocalBalance
+
+		balances = append(balances, &rebalance.ChannelBalance{
+			ChanID:       channel.ShortChanID().ToUint64(),
+			Capacity:     channel.Capacity,
+			LocalBalance: localBalance.ToSatoshis(),
+			Active:       s.htlcSwitch.HasActiveLink(chanID),
+		})
+	}
+
+	return balances, nil
+}
+
+// fetchFeePolicyBalances returns the balances of all of our open channels, for
+// the fee policy manager to evaluate their schedules.
+func (s *server) fetchFeePolicyBalances() ([]*feepolicy.ChannelBalance,
+	error) {
+
+	channels, err := s.chanDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]*feepolicy.ChannelBalance, 0, len(channels))
+	for _, channel := range channels {
+		localBalance := channel.LocalCommitment.LocalBalance
+
+		balances = append(balances, &feepolicy.ChannelBalance{
+			ChanID:       channel.ShortChanID().ToUint64(),
+			ChanPoint:    channel.FundingOutpoint,
+			Capacity:     channel.Capacity,
+			LocalBalance: localBalance.ToSatoshis(),
+		})
+	}
+
+	return balances, nil
+}
+
+// updateChannelPolicy announces a new routing policy for the target channels,
+// or for all of our channels if none are given, and applies it to the links of
+// those that are active. The fee rate is expressed in parts per million.
+func (s *server) updateChannelPolicy(baseFee lnwire.MilliSatoshi,
+	feeRate uint32, timeLockDelta uint32,
+	targetChans ...wire.OutPoint) error {
+
+	chanPolicy := routing.ChannelPolicy{
+		FeeSchema: routing.FeeSchema{
+			BaseFee: baseFee,
+			FeeRate: feeRate,
+		},
+		TimeLockDelta: timeLockDelta,
+	}
+
+	// We'll first send the new policy to the AuthenticatedGossiper so it
+	// can propagate it for our target channel(s).
+	err := s.authGossiper.PropagateChanPolicyUpdate(
+		chanPolicy, targetChans...,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Finally, we'll apply the set of active links amongst the target
+	// channels.
+	//
+	// We create a partially policy as the logic won't overwrite a valid
+	// sub-policy with a "nil" one.
+	p := htlcswitch.ForwardingPolicy{
+		BaseFee:       baseFee,
+		FeeRate:       lnwire.MilliSatoshi(feeRate),
+		TimeLockDelta: timeLockDelta,
+	}
+	err = s.htlcSwitch.UpdateForwardingPolicies(p, targetChans...)
+	if err != nil {
+		// If we're unable update the fees due to the links not being
+		// online, then we don't need to fail the call. We'll simply
+		// log the failure.
+		srvrLog.Warnf("Unable to update link fees: %v", err)
+	}
+
+	return nil
+}
+
+// addRebalanceInvoice adds the invoice paid to ourselves by a rebalance. The
+// invoice is added along with a payment request, as the invoice registry
+// derives the final CLTV delta it expects from it.
+func (s *server) addRebalanceInvoice(amt btcutil.Amount, memo string,
+	finalCLTVDelta uint16) ([32]byte, error) {
+
+	defaultDelta := cfg.Bitcoin.TimeLockDelta
+	if registeredChains.PrimaryChain() == litecoinfinanceChain {
+		defaultDelta = cfg.Litecoinfinance.TimeLockDelta
+	}
+
+	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
+		AddInvoice:        s.invoices.AddInvoice,
+		IsChannelActive:   s.htlcSwitch.HasActiveLink,
+		ChainParams:       activeNetParams.Params,
+		NodeSigner:        s.nodeSigner,
+		MaxPaymentMSat:    maxPaymentMSat,
+		DefaultCLTVExpiry: defaultDelta,
+		ChanDB:            s.chanDB,
+	}
+	addInvoiceData := &invoicesrpc.AddInvoiceData{
+		Memo:       memo,
+		Value:      amt,
+		CltvExpiry: uint64(finalCLTVDelta),
+	}
+
+	hash, _, err := invoicesrpc.AddInvoice(
+		context.Background(), addInvoiceCfg, addInvoiceData,
+	)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return *hash, nil
+}
+
+// reportForwardHoldTime passes the time a peer took to resolve an HTLC we
+// forwarded to it on to the router, which avoids slow nodes during path
+// finding.
+func (s *server) reportForwardHoldTime(peer [33]byte, holdTime time.Duration) {
+	s.chanRouter.ReportPeerHoldTime(route.Vertex(peer), holdTime)
+}
+
 // configurePortForwarding attempts to set up port forwarding for the different
 // ports that the server will be listening on.
 //
@@ -1476,6 +2093,128 @@ out:
 	}
 }
 
+// pruneForwardingLog periodically rolls the forwarding events older than the
+// given number of days into their daily summaries, and prunes them from the
+// forwarding log.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) pruneForwardingLog(retentionDays uint32) {
+	defer s.wg.Done()
+
+	fwdLog := s.chanDB.ForwardingLog()
+	retention := time.Duration(retentionDays) * 24 * time.Hour
+
+	prune := func() {
+		cutoff := time.Now().Add(-retention)
+		numPruned, err := fwdLog.PruneEvents(cutoff)
+		if err != nil {
+			srvrLog.Errorf("Unable to prune forwarding log: %v", err)
+			return
+		}
+
+		if numPruned > 0 {
+			srvrLog.Infof("Pruned %d forwarding events prior to %v "+
+				"from the forwarding log", numPruned, cutoff)
+		}
+	}
+
+	// We'll prune the events that outlived their retention while we were
+	// offline right away.
+	prune()
+
+	ticker := time.NewTicker(fwdLogPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			prune()
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// collectGarbage periodically deletes the payments and invoices selected by
+// the garbage collection policy.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) collectGarbage(policy *lncfg.GC) {
+	defer s.wg.Done()
+
+	const day = 24 * time.Hour
+
+	collect := func() {
+		now := time.Now()
+
+		paymentQuery := channeldb.PaymentDeletionQuery{
+			Failed: policy.DeleteFailedPayments,
+		}
+		if policy.PaymentRetentionDays > 0 {
+			retention := time.Duration(policy.PaymentRetentionDays)
+			paymentQuery.CompletedBefore = now.Add(-retention * day)
+		}
+		numPayments, err := s.chanDB.DeletePayments(paymentQuery)
+		if err != nil {
+			srvrLog.Errorf("Unable to delete payments: %v", err)
+		} else if numPayments > 0 {
+			srvrLog.Infof("Deleted %d payment records", numPayments)
+		}
+
+		invoiceQuery := channeldb.InvoiceDeletionQuery{
+			Canceled: policy.DeleteCanceledInvoices,
+		}
+		if policy.InvoiceRetentionDays > 0 {
+			retention := time.Duration(policy.InvoiceRetentionDays)
+			cutoff := now.Add(-retention * day)
+			invoiceQuery.SettledBefore = cutoff
+			invoiceQuery.OpenBefore = cutoff
+		}
+		numInvoices, err := s.chanDB.DeleteInvoices(invoiceQuery)
+		if err != nil {
+			srvrLog.Errorf("Unable to delete invoices: %v", err)
+		} else if numInvoices > 0 {
+			srvrLog.Infof("Deleted %d invoices", numInvoices)
+		}
+	}
+
+	collect()
+
+	ticker := time.NewTicker(gcInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			collect()
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// netBootstrapEnabled returns whether we should automatically bootstrap
+// connections to peers within the network. Bootstrapping can be disabled
+// explicitly, and is never done on simnet or regtest.
+func netBootstrapEnabled() bool {
+	return !cfg.NoNetBootstrap &&
+		!(cfg.Bitcoin.SimNet || cfg.Litecoinfinance.SimNet) &&
+		!(cfg.Bitcoin.RegTest || cfg.Litecoinfinance.RegTest)
+}
+
+// requestBootstrap signals the peer bootstrapper to dial fresh peers from our
+// DNS seeds. This is used by the gossiper once it has been left without any
+// gossip syncers for too long. If a request is already pending, this is a
+// no-op.
+func (s *server) requestBootstrap() {
+	select {
+	case s.bootstrapRequests <- struct{}{}:
+	default:
+	}
+}
+
 // initNetworkBootstrappers initializes a set of network peer bootstrappers
 // based on the server, and currently active bootstrap mechanisms as defined
 // within the current configuration.
@@ -1529,6 +2268,17 @@ func (s *server) peerBootstrapper(numTargetPeers uint32,
 	// our bootstrappers in order to avoid duplicates.
 	ignore := make(map[autopilot.NodeID]struct{})
 
+	// We'll also keep track of our DNS bootstrappers separately, as
+	// they're the only source of fresh peers we can fall back to if our
+	// view of the network has gone stale.
+	var dnsBootstrappers []discovery.NetworkPeerBootstrapper
+	for _, bootstrapper := range bootstrappers {
+		_, ok := bootstrapper.(*discovery.DNSSeedBootstrapper)
+		if ok {
+			dnsBootstrappers = append(dnsBootstrappers, bootstrapper)
+		}
+	}
+
 	// We'll start off by aggressively attempting connections to peers in
 	// order to be a part of the network as soon as possible.
 	s.initialPeerBootstrap(ignore, numTargetPeers, bootstrappers)
@@ -1645,6 +2395,57 @@ func (s *server) peerBootstrapper(numTargetPeers uint32,
 					}
 				}(addr)
 			}
+
+		// The gossiper has been left without any gossip syncers for
+		// too long, so we'll dial fresh peers from our DNS seeds,
+		// regardless of how many peers we're currently connected to,
+		// as they may not be serving any graph updates.
+		case <-s.bootstrapRequests:
+			if len(dnsBootstrappers) == 0 {
+				srvrLog.Warnf("No gossip syncers available, but " +
+					"no DNS seeds are known for the active " +
+					"chain")
+				continue
+			}
+
+			s.mu.RLock()
+			ignoreList := make(map[autopilot.NodeID]struct{})
+			for _, peer := range s.peersByPub {
+				nID := autopilot.NewNodeID(peer.addr.IdentityKey)
+				ignoreList[nID] = struct{}{}
+			}
+			s.mu.RUnlock()
+
+			peerAddrs, err := discovery.MultiSourceBootstrap(
+				ignoreList, numTargetPeers, dnsBootstrappers...,
+			)
+			if err != nil {
+				srvrLog.Errorf("Unable to retrieve bootstrap "+
+					"peers from DNS seeds: %v", err)
+				continue
+			}
+
+			srvrLog.Infof("No gossip syncers available, dialing %v "+
+				"fresh peers from DNS seeds", len(peerAddrs))
+
+			for _, addr := range peerAddrs {
+				go func(a *lnwire.NetAddress) {
+					errChan := make(chan error, 1)
+					s.connectToPeer(a, errChan)
+					select {
+					case err := <-errChan:
+						if err == nil {
+							return
+						}
+
+						srvrLog.Errorf("Unable to "+
+							"connect to %v: %v",
+							a, err)
+					case <-s.quit:
+					}
+				}(addr)
+			}
+
 		case <-s.quit:
 			return
 		}
@@ -1887,7 +2688,7 @@ func (s *server) establishPersistentConnections() error {
 	// each of the nodes.
 	selfPub := s.identityPriv.PubKey().SerializeCompressed()
 	err = sourceNode.ForEachChannel(nil, func(
-		tx *bbolt.Tx,
+		tx kvdb.Tx,
 		chanInfo *channeldb.ChannelEdgeInfo,
 		policy, _ *channeldb.ChannelEdgePolicy) error {
 
@@ -2544,9 +3345,23 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 	localFeatures := lnwire.NewRawFeatureVector()
 
 	// We'll signal that we understand the data loss protection feature,
-	// and also that we support the new gossip query features.
+	// and also that we support the new gossip query features, including
+	// their extended variant.
 	localFeatures.Set(lnwire.DataLossProtectRequired)
 	localFeatures.Set(lnwire.GossipQueriesOptional)
+	localFeatures.Set(lnwire.GossipQueriesExOptional)
+
+	// If enabled, we'll also signal that we're able to use the
+	// experimental commitment format with anchor outputs.
+	if cfg.Protocol.Anchors {
+		localFeatures.Set(lnwire.AnchorsOptional)
+	}
+
+	// Likewise, we'll signal that peers may request us to contribute funds
+	// to their channels, if we allow them to.
+	if cfg.Protocol.DualFunding {
+		localFeatures.Set(lnwire.DualFundingOptional)
+	}
 
 	// Now that we've established a connection, create a peer, and it to the
 	// set of currently active peers. Configure the peer with the incoming
@@ -2694,6 +3509,7 @@ func (s *server) peerTerminationWatcher(p *peer, ready chan struct{}) {
 	// then we need to unlock those committed outputs so they're still
 	// available for use.
 	s.fundingMgr.CancelPeerReservations(p.PubKey())
+	s.fundingMgr.CancelPeerSplices(p.PubKey())
 
 	pubKey := p.addr.IdentityKey
 
@@ -2881,6 +3697,33 @@ type openChanReq struct {
 	// output selected to fund the channel should satisfy.
 	minConfs int32
 
+	// psbtFunding indicates that the funding transaction is funded and
+	// signed outside of the wallet, based on a PSBT funding template
+	// handed to the caller.
+	psbtFunding bool
+
+	// noFundingTx indicates that the funding transaction is published by
+	// the caller, once the channel is pending, rather than by the funding
+	// manager.
+	noFundingTx bool
+
+	// coinSelectionStrategy is the strategy used to select the coins
+	// funding the channel.
+	coinSelectionStrategy lnwallet.CoinSelectionStrategy
+
+	// dustLimit is the dust limit of our commitment transactions. If
+	// zero, the default of the active chain is used.
+	dustLimit btcutil.Amount
+
+	// remoteChanReserve is the channel reserve we require the remote
+	// party to maintain. If zero, it's derived from the capacity of the
+	// channel.
+	remoteChanReserve btcutil.Amount
+
+	// zeroConf indicates that the channel is requested to be usable
+	// before its funding transaction confirms.
+	zeroConf bool
+
 	// TODO(roasbeef): add ability to specify channel constraints as well
 
 	updates chan *lnrpc.OpenStatusUpdate
@@ -2994,7 +3837,8 @@ func (s *server) DisconnectPeer(pubKey *btcec.PublicKey) error {
 	// currently connected to.
 	peer, err := s.findPeerByPubStr(pubStr)
 	if err == ErrPeerNotConnected {
-		return fmt.Errorf("peer %x is not connected", pubBytes)
+		return newCodedErrorf(lnrpc.ErrorCode_PEER_OFFLINE,
+			"peer %x is not connected", pubBytes)
 	}
 
 	srvrLog.Infof("Disconnecting from %v", peer)
@@ -3023,10 +3867,11 @@ func (s *server) DisconnectPeer(pubKey *btcec.PublicKey) error {
 func (s *server) OpenChannel(
 	req *openChanReq) (chan *lnrpc.OpenStatusUpdate, chan error) {
 
-	// The updateChan will have a buffer of 2, since we expect a ChanPending
-	// + a ChanOpen update, and we want to make sure the funding process is
+	// The updateChan will have a buffer of 3, since we expect a ChanPending
+	// + a ChanOpen update, preceded by a PsbtFund update if the channel is
+	// funded externally, and we want to make sure the funding process is
 	// not blocked if the caller is not reading the updates.
-	req.updates = make(chan *lnrpc.OpenStatusUpdate, 2)
+	req.updates = make(chan *lnrpc.OpenStatusUpdate, 3)
 	req.err = make(chan error, 1)
 
 	// First attempt to locate the target peer to open a channel with, if
@@ -3037,7 +3882,8 @@ func (s *server) OpenChannel(
 	if !ok {
 		s.mu.RUnlock()
 
-		req.err <- fmt.Errorf("peer %x is not online", pubKeyBytes)
+		req.err <- newCodedErrorf(lnrpc.ErrorCode_PEER_OFFLINE,
+			"peer %x is not online", pubKeyBytes)
 		return req.updates, req.err
 	}
 	s.mu.RUnlock()
@@ -3158,6 +4004,27 @@ func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
 	}
 }
 
+// signChanUpdate applies the given modifiers to our latest channel update for
+// the target channel, and returns the result signed by our node key. The
+// update is neither applied locally nor broadcast to the network.
+func (s *server) signChanUpdate(cid lnwire.ShortChannelID,
+	mods ...netann.ChannelUpdateModifier) (*lnwire.ChannelUpdate, error) {
+
+	update, err := s.fetchLastChanUpdate()(cid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = netann.SignChannelUpdate(
+		s.nodeSigner, s.identityPriv.PubKey(), update, mods...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
 // applyChannelUpdate applies the channel update to the different sub-systems of
 // the server.
 func (s *server) applyChannelUpdate(update *lnwire.ChannelUpdate) error {
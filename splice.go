@@ -0,0 +1,983 @@
+package lnd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/chainntnfs"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/lnpeer"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+var (
+	// ErrSpliceInProgress is returned when attempting to splice a channel
+	// which is already being spliced.
+	ErrSpliceInProgress = errors.New("channel is already being spliced")
+)
+
+// spliceReq is a request to splice a channel with the given peer, either
+// adding funds of the wallet to it, or paying funds out of it.
+type spliceReq struct {
+	chanPoint wire.OutPoint
+
+	// spliceIn indicates whether amt is added to the channel, rather than
+	// paid out of it to pkScript.
+	spliceIn bool
+	amt      btcutil.Amount
+	pkScript []byte
+
+	feeRate  lnwallet.SatPerKWeight
+	minConfs int32
+	strategy lnwallet.CoinSelectionStrategy
+
+	// resp is sent the new funding outpoint of the channel once the
+	// splice transaction is published.
+	//
+	// NOTE: This channel MUST be buffered.
+	resp chan wire.OutPoint
+
+	// NOTE: This channel MUST be buffered.
+	err chan error
+}
+
+// fail reports the failure of the splice to the caller, unless a failure was
+// reported already, as the splice may be cancelled while it's processed.
+func (r *spliceReq) fail(err error) {
+	select {
+	case r.err <- err:
+	default:
+	}
+}
+
+// spliceCtx tracks a splice with a peer until it's persisted by both parties.
+type spliceCtx struct {
+	chanState   *channeldb.OpenChannel
+	peer        lnpeer.Peer
+	isInitiator bool
+
+	// splice is the splice in progress. It's nil for a splice resumed
+	// from disk, for which only the final signature is still missing.
+	splice   *lnwallet.Splice
+	spliceTx *wire.MsgTx
+
+	// persisted indicates that we stored the splice, which is therefore
+	// resumed when the peer reconnects.
+	persisted bool
+
+	// req is the request we're splicing the channel for, if any.
+	req *spliceReq
+}
+
+// initSpliceMsg couples a spliceReq with the peer we splice the channel with.
+type initSpliceMsg struct {
+	peer lnpeer.Peer
+	*spliceReq
+}
+
+// spliceInitMsg couples an lnwire.SpliceInit message with the peer who sent
+// it.
+type spliceInitMsg struct {
+	msg  *lnwire.SpliceInit
+	peer lnpeer.Peer
+}
+
+// spliceAckMsg couples an lnwire.SpliceAck message with the peer who sent it.
+type spliceAckMsg struct {
+	msg  *lnwire.SpliceAck
+	peer lnpeer.Peer
+}
+
+// spliceCommitMsg couples an lnwire.SpliceCommit message with the peer who
+// sent it.
+type spliceCommitMsg struct {
+	msg  *lnwire.SpliceCommit
+	peer lnpeer.Peer
+}
+
+// spliceSignedMsg couples an lnwire.SpliceSigned message with the peer who
+// sent it.
+type spliceSignedMsg struct {
+	msg  *lnwire.SpliceSigned
+	peer lnpeer.Peer
+}
+
+// spliceErrorMsg is an error sent by a peer in response to a splice we're
+// negotiating with it.
+type spliceErrorMsg struct {
+	err     *lnwire.Error
+	peerKey *btcec.PublicKey
+}
+
+// spliceResumeMsg signals that a peer, with which we have a channel being
+// spliced, connected.
+type spliceResumeMsg struct {
+	channel *channeldb.OpenChannel
+	peer    lnpeer.Peer
+}
+
+// SpliceChannel splices the channel of the given request with its peer, which
+// must be connected. It returns the new funding outpoint of the channel once
+// the splice transaction is published.
+func (s *server) SpliceChannel(req *spliceReq) (wire.OutPoint, error) {
+	channel, err := s.chanDB.FetchChannel(req.chanPoint)
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+
+	peer, err := s.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+
+	return s.fundingMgr.SpliceChannel(peer, req)
+}
+
+// SpliceChannel splices the channel of the given request with the peer. It
+// returns once the splice transaction is published, after which the channel
+// moves over to its new funding outpoint as soon as the transaction
+// confirms.
+func (f *fundingManager) SpliceChannel(peer lnpeer.Peer,
+	req *spliceReq) (wire.OutPoint, error) {
+
+	req.resp = make(chan wire.OutPoint, 1)
+	req.err = make(chan error, 1)
+
+	select {
+	case f.queries <- &initSpliceMsg{peer: peer, spliceReq: req}:
+	case <-f.quit:
+		return wire.OutPoint{}, ErrFundingManagerShuttingDown
+	}
+
+	select {
+	case chanPoint := <-req.resp:
+		return chanPoint, nil
+	case err := <-req.err:
+		return wire.OutPoint{}, err
+	case <-f.quit:
+		return wire.OutPoint{}, ErrFundingManagerShuttingDown
+	}
+}
+
+// handleInitSpliceMsg creates the splice transaction of a request, pauses the
+// channel, and proposes the splice to the peer.
+func (f *fundingManager) handleInitSpliceMsg(msg *initSpliceMsg) {
+	req := msg.spliceReq
+	peer := msg.peer
+	chanID := lnwire.NewChanIDFromOutPoint(&req.chanPoint)
+
+	if f.getSpliceCtx(chanID) != nil {
+		req.err <- ErrSpliceInProgress
+		return
+	}
+
+	chanState, err := f.cfg.FindChannel(chanID)
+	if err != nil {
+		req.err <- err
+		return
+	}
+	if !chanState.IdentityPub.IsEqual(peer.IdentityKey()) {
+		req.err <- fmt.Errorf("ChannelPoint(%v) doesn't belong to "+
+			"peer %x", req.chanPoint, peer.PubKey())
+		return
+	}
+
+	// We check the channel before pausing it, so that we don't disrupt
+	// it for a splice which would be rejected anyway.
+	if err := lnwallet.CanSplice(chanState); err != nil {
+		req.err <- err
+		return
+	}
+
+	var spliceTx *wire.MsgTx
+	if req.spliceIn {
+		spliceTx, err = f.cfg.Wallet.FundSpliceIn(
+			chanState, req.amt, req.feeRate, req.minConfs,
+			req.strategy,
+		)
+	} else {
+		spliceTx, err = lnwallet.CreateSpliceOut(
+			chanState, req.amt, req.pkScript, req.feeRate,
+		)
+	}
+	if err != nil {
+		req.err <- err
+		return
+	}
+
+	ctx := &spliceCtx{
+		peer:        peer,
+		isInitiator: true,
+		spliceTx:    spliceTx,
+		req:         req,
+	}
+
+	// Until the splice transaction confirms, the channel is no longer
+	// updated off-chain, so we remove its link before loading its latest
+	// state.
+	ctx.chanState, err = f.pauseChannel(peer, chanID)
+	if err != nil {
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	ctx.splice, err = lnwallet.NewSplice(
+		ctx.chanState, f.cfg.Wallet.Cfg.Signer, spliceTx, true,
+	)
+	if err != nil {
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	fndgLog.Infof("Splicing ChannelPoint(%v) into ChannelPoint(%v) with "+
+		"capacity %v", req.chanPoint, ctx.splice.FundingOutpoint(),
+		ctx.splice.Capacity())
+
+	f.resMtx.Lock()
+	f.activeSplices[chanID] = ctx
+	f.resMtx.Unlock()
+
+	spliceInit := &lnwire.SpliceInit{
+		ChanID:   chanID,
+		SpliceTx: spliceTx,
+	}
+	if err := peer.SendMessage(false, spliceInit); err != nil {
+		f.failSplice(chanID, ctx, err)
+	}
+}
+
+// processSpliceInit sends a message to the fundingManager allowing it to
+// respond to a splice proposed by the peer.
+func (f *fundingManager) processSpliceInit(msg *lnwire.SpliceInit,
+	peer lnpeer.Peer) {
+
+	select {
+	case f.fundingMsgs <- &spliceInitMsg{msg, peer}:
+	case <-f.quit:
+		return
+	}
+}
+
+// handleSpliceInit validates a splice proposed by the peer, pauses the
+// channel, and sends our signature for the commitment of the peer spending
+// the new funding output.
+func (f *fundingManager) handleSpliceInit(fmsg *spliceInitMsg) {
+	msg := fmsg.msg
+	peer := fmsg.peer
+	chanID := msg.ChanID
+
+	if f.getSpliceCtx(chanID) != nil {
+		fndgLog.Warnf("Rejecting splice of ChannelID(%v) by peer %x: "+
+			"%v", chanID, peer.PubKey(), ErrSpliceInProgress)
+		f.sendSpliceError(peer, chanID, ErrSpliceInProgress)
+		return
+	}
+
+	chanState, err := f.cfg.FindChannel(chanID)
+	if err != nil || !chanState.IdentityPub.IsEqual(peer.IdentityKey()) {
+		fndgLog.Warnf("Peer %x proposed splice of unknown "+
+			"ChannelID(%v)", peer.PubKey(), chanID)
+		f.sendSpliceError(peer, chanID, ErrChannelNotFound)
+		return
+	}
+
+	ctx := &spliceCtx{
+		peer:     peer,
+		spliceTx: msg.SpliceTx,
+	}
+
+	ctx.chanState, err = f.pauseChannel(peer, chanID)
+	if err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	ctx.splice, err = lnwallet.NewSplice(
+		ctx.chanState, f.cfg.Wallet.Cfg.Signer, msg.SpliceTx, false,
+	)
+	if err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	commitSig, err := ctx.splice.SignRemoteCommit()
+	if err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	fndgLog.Infof("Peer %x splicing ChannelPoint(%v) into "+
+		"ChannelPoint(%v) with capacity %v", peer.PubKey(),
+		chanState.FundingOutpoint, ctx.splice.FundingOutpoint(),
+		ctx.splice.Capacity())
+
+	f.resMtx.Lock()
+	f.activeSplices[chanID] = ctx
+	f.resMtx.Unlock()
+
+	spliceAck := &lnwire.SpliceAck{
+		ChanID:    chanID,
+		CommitSig: commitSig,
+	}
+	if err := peer.SendMessage(false, spliceAck); err != nil {
+		f.failSplice(chanID, ctx, err)
+	}
+}
+
+// processSpliceAck sends a message to the fundingManager allowing it to
+// continue a splice accepted by the peer.
+func (f *fundingManager) processSpliceAck(msg *lnwire.SpliceAck,
+	peer lnpeer.Peer) {
+
+	select {
+	case f.fundingMsgs <- &spliceAckMsg{msg, peer}:
+	case <-f.quit:
+		return
+	}
+}
+
+// handleSpliceAck verifies the signature of the peer for our commitment
+// spending the new funding output, persists the splice, and sends our
+// signature for the commitment of the peer.
+func (f *fundingManager) handleSpliceAck(fmsg *spliceAckMsg) {
+	msg := fmsg.msg
+	peer := fmsg.peer
+	chanID := msg.ChanID
+
+	ctx := f.getSpliceCtx(chanID)
+	if ctx == nil || !ctx.isInitiator || ctx.persisted ||
+		ctx.peer.PubKey() != peer.PubKey() {
+
+		fndgLog.Warnf("Received unexpected SpliceAck for "+
+			"ChannelID(%v) from peer %x", chanID, peer.PubKey())
+		return
+	}
+
+	if err := ctx.splice.ReceiveCommitSig(msg.CommitSig); err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	commitSig, err := ctx.splice.SignRemoteCommit()
+	if err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	chanSplice, err := f.newChannelSplice(ctx.splice)
+	if err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	// Once the splice is persisted, it's resumed whenever the peer
+	// connects, until either the peer signs the splice transaction, or
+	// rejects the splice.
+	if err := ctx.chanState.AddSplice(chanSplice); err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+	ctx.persisted = true
+
+	spliceCommit := &lnwire.SpliceCommit{
+		ChanID:    chanID,
+		CommitSig: commitSig,
+	}
+	if err := peer.SendMessage(false, spliceCommit); err != nil {
+		fndgLog.Warnf("Unable to send SpliceCommit for ChannelID(%v) "+
+			"to peer %x, will retry on reconnection: %v", chanID,
+			peer.PubKey(), err)
+	}
+}
+
+// processSpliceCommit sends a message to the fundingManager allowing it to
+// sign the splice transaction of a splice proposed by the peer.
+func (f *fundingManager) processSpliceCommit(msg *lnwire.SpliceCommit,
+	peer lnpeer.Peer) {
+
+	select {
+	case f.fundingMsgs <- &spliceCommitMsg{msg, peer}:
+	case <-f.quit:
+		return
+	}
+}
+
+// handleSpliceCommit verifies the signature of the peer for our commitment
+// spending the new funding output, persists the splice, and sends our
+// signature for the splice transaction. From then on, the peer is able to
+// publish the splice transaction, so the splice can no longer be aborted.
+func (f *fundingManager) handleSpliceCommit(fmsg *spliceCommitMsg) {
+	msg := fmsg.msg
+	peer := fmsg.peer
+	chanID := msg.ChanID
+
+	ctx := f.getSpliceCtx(chanID)
+	if ctx == nil {
+		f.resendSpliceSigned(peer, chanID)
+		return
+	}
+	if ctx.isInitiator || ctx.peer.PubKey() != peer.PubKey() {
+		fndgLog.Warnf("Received unexpected SpliceCommit for "+
+			"ChannelID(%v) from peer %x", chanID, peer.PubKey())
+		return
+	}
+
+	if err := ctx.splice.ReceiveCommitSig(msg.CommitSig); err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	chanSplice, err := f.newChannelSplice(ctx.splice)
+	if err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	if err := ctx.chanState.AddSplice(chanSplice); err != nil {
+		f.sendSpliceError(peer, chanID, err)
+		f.failSplice(chanID, ctx, err)
+		return
+	}
+
+	// As the splice is persisted, we no longer need to track it here. In
+	// case the peer didn't receive our signature, it'll resend
+	// SpliceCommit once reconnected, which we respond to from disk.
+	f.deleteSpliceCtx(chanID)
+
+	f.sendSpliceSigned(peer, chanID, chanSplice.FundingSig)
+
+	f.wg.Add(1)
+	go f.waitForSpliceConfirmation(ctx.chanState, chanSplice)
+}
+
+// resendSpliceSigned responds to a SpliceCommit message for a splice we
+// already persisted, by sending our signature for the splice transaction once
+// more. If we don't know of the splice, we reject it, allowing the peer to
+// abort it.
+func (f *fundingManager) resendSpliceSigned(peer lnpeer.Peer,
+	chanID lnwire.ChannelID) {
+
+	chanState, err := f.cfg.FindChannel(chanID)
+	if err != nil || !chanState.IdentityPub.IsEqual(peer.IdentityKey()) {
+		fndgLog.Warnf("Received SpliceCommit for unknown "+
+			"ChannelID(%v) from peer %x", chanID, peer.PubKey())
+		return
+	}
+
+	chanSplice, err := chanState.Splice()
+	switch {
+	case err == channeldb.ErrNoSplice:
+		fndgLog.Warnf("Received SpliceCommit for ChannelID(%v) "+
+			"without pending splice from peer %x", chanID,
+			peer.PubKey())
+		f.sendSpliceError(peer, chanID, err)
+		return
+
+	case err != nil:
+		fndgLog.Errorf("Unable to fetch splice of ChannelID(%v): %v",
+			chanID, err)
+		return
+
+	case chanSplice.IsInitiator:
+		fndgLog.Warnf("Received SpliceCommit for ChannelID(%v) we're "+
+			"splicing from peer %x", chanID, peer.PubKey())
+		return
+	}
+
+	f.sendSpliceSigned(peer, chanID, chanSplice.FundingSig)
+}
+
+// sendSpliceSigned sends our signature for the splice transaction of the
+// channel to the peer.
+func (f *fundingManager) sendSpliceSigned(peer lnpeer.Peer,
+	chanID lnwire.ChannelID, fundingSig []byte) {
+
+	sig, err := lnwire.NewSigFromRawSignature(fundingSig)
+	if err != nil {
+		fndgLog.Errorf("Unable to parse splice signature of "+
+			"ChannelID(%v): %v", chanID, err)
+		return
+	}
+
+	spliceSigned := &lnwire.SpliceSigned{
+		ChanID:     chanID,
+		FundingSig: sig,
+	}
+	if err := peer.SendMessage(false, spliceSigned); err != nil {
+		fndgLog.Warnf("Unable to send SpliceSigned for ChannelID(%v) "+
+			"to peer %x: %v", chanID, peer.PubKey(), err)
+	}
+}
+
+// processSpliceSigned sends a message to the fundingManager allowing it to
+// complete, and publish, the splice transaction of a splice we proposed.
+func (f *fundingManager) processSpliceSigned(msg *lnwire.SpliceSigned,
+	peer lnpeer.Peer) {
+
+	select {
+	case f.fundingMsgs <- &spliceSignedMsg{msg, peer}:
+	case <-f.quit:
+		return
+	}
+}
+
+// handleSpliceSigned completes the splice transaction with the signature of
+// the peer, and publishes it.
+func (f *fundingManager) handleSpliceSigned(fmsg *spliceSignedMsg) {
+	msg := fmsg.msg
+	peer := fmsg.peer
+	chanID := msg.ChanID
+
+	ctx := f.getSpliceCtx(chanID)
+	if ctx == nil || !ctx.isInitiator || !ctx.persisted ||
+		ctx.peer.PubKey() != peer.PubKey() {
+
+		fndgLog.Warnf("Received unexpected SpliceSigned for "+
+			"ChannelID(%v) from peer %x", chanID, peer.PubKey())
+		return
+	}
+
+	// Whatever the outcome, the splice no longer needs to be tracked here,
+	// as the peer already persisted it.
+	f.deleteSpliceCtx(chanID)
+
+	reportErr := func(err error) {
+		fndgLog.Errorf("Unable to complete splice of ChannelID(%v): "+
+			"%v", chanID, err)
+		if ctx.req != nil {
+			ctx.req.fail(err)
+		}
+	}
+
+	chanSplice, err := ctx.chanState.Splice()
+	if err != nil {
+		reportErr(err)
+		return
+	}
+
+	spliceTx, err := lnwallet.CompleteSpliceTx(
+		ctx.chanState, chanSplice, msg.FundingSig,
+	)
+	if err != nil {
+		reportErr(err)
+		return
+	}
+
+	chanSplice.SpliceTx = spliceTx
+	chanSplice.Complete = true
+	if err := ctx.chanState.UpdateSplice(chanSplice); err != nil {
+		reportErr(err)
+		return
+	}
+
+	fndgLog.Infof("Publishing splice tx %v of ChannelPoint(%v)",
+		spliceTx.TxHash(), ctx.chanState.FundingOutpoint)
+
+	// Failing to publish the splice transaction isn't fatal, as we'll
+	// rebroadcast it on restart.
+	if err := f.cfg.PublishTransaction(spliceTx); err != nil {
+		fndgLog.Errorf("Unable to publish splice tx %v: %v",
+			spliceTx.TxHash(), err)
+	}
+
+	if ctx.req != nil {
+		ctx.req.resp <- chanSplice.FundingOutpoint
+	}
+
+	f.wg.Add(1)
+	go f.waitForSpliceConfirmation(ctx.chanState, chanSplice)
+}
+
+// IsSplicing returns whether we're negotiating a splice of the channel with
+// the given peer.
+func (f *fundingManager) IsSplicing(chanID lnwire.ChannelID,
+	peerKey *btcec.PublicKey) bool {
+
+	ctx := f.getSpliceCtx(chanID)
+	return ctx != nil && ctx.peer.IdentityKey().IsEqual(peerKey)
+}
+
+// processSpliceError sends a message to the fundingManager allowing it to
+// abort the splice rejected by the peer.
+func (f *fundingManager) processSpliceError(err *lnwire.Error,
+	peerKey *btcec.PublicKey) {
+
+	select {
+	case f.fundingMsgs <- &spliceErrorMsg{err, peerKey}:
+	case <-f.quit:
+		return
+	}
+}
+
+// handleSpliceError aborts the splice rejected by the peer.
+func (f *fundingManager) handleSpliceError(fmsg *spliceErrorMsg) {
+	chanID := fmsg.err.ChanID
+
+	ctx := f.getSpliceCtx(chanID)
+	if ctx == nil || !ctx.peer.IdentityKey().IsEqual(fmsg.peerKey) {
+		fndgLog.Warnf("Received splice error for unknown "+
+			"ChannelID(%v)", chanID)
+		return
+	}
+
+	err := fmt.Errorf("splice rejected by peer: %v",
+		string(fmsg.err.Data))
+	f.failSplice(chanID, ctx, err)
+}
+
+// processSpliceResume sends a message to the fundingManager allowing it to
+// resume the splice of the channel with the newly connected peer.
+func (f *fundingManager) processSpliceResume(channel *channeldb.OpenChannel,
+	peer lnpeer.Peer) {
+
+	select {
+	case f.fundingMsgs <- &spliceResumeMsg{channel, peer}:
+	case <-f.quit:
+		return
+	}
+}
+
+// handleSpliceResume resends our SpliceCommit message for a splice we
+// proposed, but that isn't signed by the peer yet.
+func (f *fundingManager) handleSpliceResume(fmsg *spliceResumeMsg) {
+	chanState := fmsg.channel
+	peer := fmsg.peer
+	chanID := lnwire.NewChanIDFromOutPoint(&chanState.FundingOutpoint)
+
+	chanSplice, err := chanState.Splice()
+	if err != nil {
+		fndgLog.Errorf("Unable to fetch splice of ChannelID(%v): %v",
+			chanID, err)
+		return
+	}
+
+	// Only the initiator of the splice has to make sure it gets signed.
+	if !chanSplice.IsInitiator || chanSplice.Complete {
+		return
+	}
+
+	commitSig, err := lnwire.NewSigFromRawSignature(
+		chanSplice.RemoteCommitment.CommitSig,
+	)
+	if err != nil {
+		fndgLog.Errorf("Unable to parse commitment signature of "+
+			"ChannelID(%v): %v", chanID, err)
+		return
+	}
+
+	ctx := f.getSpliceCtx(chanID)
+	if ctx == nil {
+		ctx = &spliceCtx{
+			chanState:   chanState,
+			isInitiator: true,
+			spliceTx:    chanSplice.SpliceTx,
+			persisted:   true,
+		}
+	}
+	ctx.peer = peer
+
+	f.resMtx.Lock()
+	f.activeSplices[chanID] = ctx
+	f.resMtx.Unlock()
+
+	fndgLog.Infof("Resuming splice of ChannelPoint(%v) with peer %x",
+		chanState.FundingOutpoint, peer.PubKey())
+
+	spliceCommit := &lnwire.SpliceCommit{
+		ChanID:    chanID,
+		CommitSig: commitSig,
+	}
+	if err := peer.SendMessage(false, spliceCommit); err != nil {
+		fndgLog.Warnf("Unable to send SpliceCommit for ChannelID(%v) "+
+			"to peer %x: %v", chanID, peer.PubKey(), err)
+	}
+}
+
+// CancelPeerSplices stops tracking the splices negotiated with the passed
+// node, as it disconnected. Splices which aren't persisted yet are aborted,
+// while the persisted ones are resumed once the node reconnects.
+func (f *fundingManager) CancelPeerSplices(nodePub [33]byte) {
+	f.resMtx.Lock()
+	defer f.resMtx.Unlock()
+
+	for chanID, ctx := range f.activeSplices {
+		if ctx.peer.PubKey() != nodePub {
+			continue
+		}
+
+		fndgLog.Debugf("Cancelling splice of ChannelID(%v) with peer "+
+			"%x", chanID, nodePub[:])
+
+		if !ctx.persisted && ctx.isInitiator {
+			f.cfg.Wallet.ReleaseSpliceTx(ctx.spliceTx)
+		}
+		if ctx.req != nil {
+			ctx.req.fail(fmt.Errorf("peer disconnected"))
+		}
+
+		delete(f.activeSplices, chanID)
+	}
+}
+
+// pauseChannel removes the link of the channel with the peer, such that it's
+// no longer updated, and returns its latest state.
+func (f *fundingManager) pauseChannel(peer lnpeer.Peer,
+	chanID lnwire.ChannelID) (*channeldb.OpenChannel, error) {
+
+	chanState, err := f.cfg.FindChannel(chanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := peer.WipeChannel(&chanState.FundingOutpoint); err != nil {
+		return nil, err
+	}
+
+	return f.cfg.FindChannel(chanID)
+}
+
+// failSplice aborts the splice of the channel. As the channel was paused, its
+// unsigned updates are lost, so we disconnect the peer, which causes both of
+// us to resume the channel from its persisted state once reconnected.
+func (f *fundingManager) failSplice(chanID lnwire.ChannelID, ctx *spliceCtx,
+	err error) {
+
+	fndgLog.Errorf("Splice of ChannelID(%v) failed: %v", chanID, err)
+
+	f.deleteSpliceCtx(chanID)
+
+	if ctx.persisted {
+		if err := ctx.chanState.RemoveSplice(); err != nil {
+			fndgLog.Errorf("Unable to remove splice of "+
+				"ChannelID(%v): %v", chanID, err)
+		}
+	}
+	if ctx.isInitiator {
+		f.cfg.Wallet.ReleaseSpliceTx(ctx.spliceTx)
+	}
+	if ctx.req != nil {
+		ctx.req.fail(err)
+	}
+
+	if err := f.cfg.DisconnectPeer(ctx.peer.IdentityKey()); err != nil {
+		fndgLog.Errorf("Unable to disconnect peer %x: %v",
+			ctx.peer.PubKey(), err)
+	}
+}
+
+// sendSpliceError sends the reason we reject a splice to the peer.
+func (f *fundingManager) sendSpliceError(peer lnpeer.Peer,
+	chanID lnwire.ChannelID, reason error) {
+
+	// We only let the peer know the exact reason if the splice itself is
+	// invalid, rather than us failing to process it.
+	var msg lnwire.ErrorData
+	switch reason {
+	case ErrSpliceInProgress, ErrChannelNotFound,
+		lnwallet.ErrChannelNotQuiescent, lnwallet.ErrSpliceBelowReserve:
+
+		msg = lnwire.ErrorData(reason.Error())
+
+	default:
+		msg = lnwire.ErrorData("splice failed due to internal error")
+	}
+
+	errMsg := &lnwire.Error{
+		ChanID: chanID,
+		Data:   msg,
+	}
+	if err := peer.SendMessage(true, errMsg); err != nil {
+		fndgLog.Errorf("Unable to send splice error to peer %x: %v",
+			peer.PubKey(), err)
+	}
+}
+
+// newChannelSplice returns the splice to persist, signed at the current
+// height.
+func (f *fundingManager) newChannelSplice(
+	splice *lnwallet.Splice) (*channeldb.ChannelSplice, error) {
+
+	fundingSig, err := splice.SignFundingInput()
+	if err != nil {
+		return nil, err
+	}
+
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return splice.ChannelSplice(fundingSig, uint32(bestHeight)), nil
+}
+
+// getSpliceCtx returns the splice of the channel we're negotiating, if any.
+func (f *fundingManager) getSpliceCtx(chanID lnwire.ChannelID) *spliceCtx {
+	f.resMtx.RLock()
+	defer f.resMtx.RUnlock()
+
+	return f.activeSplices[chanID]
+}
+
+// deleteSpliceCtx stops tracking the splice of the channel.
+func (f *fundingManager) deleteSpliceCtx(chanID lnwire.ChannelID) {
+	f.resMtx.Lock()
+	defer f.resMtx.Unlock()
+
+	delete(f.activeSplices, chanID)
+}
+
+// resumeSplices waits for the splice transactions signed by both parties to
+// confirm, rebroadcasting the ones we initiated.
+func (f *fundingManager) resumeSplices(channels []*channeldb.OpenChannel) {
+	for _, channel := range channels {
+		if !channel.HasChanStatus(channeldb.ChanStatusSplicing) {
+			continue
+		}
+
+		chanSplice, err := channel.Splice()
+		if err != nil {
+			fndgLog.Errorf("Unable to fetch splice of "+
+				"ChannelPoint(%v): %v", channel.FundingOutpoint,
+				err)
+			continue
+		}
+
+		// A splice we initiated that's not complete yet is resumed
+		// once the peer connects.
+		if chanSplice.IsInitiator && !chanSplice.Complete {
+			continue
+		}
+
+		if chanSplice.IsInitiator {
+			err := f.cfg.PublishTransaction(chanSplice.SpliceTx)
+			if err != nil {
+				fndgLog.Errorf("Unable to rebroadcast splice "+
+					"tx for ChannelPoint(%v): %v",
+					channel.FundingOutpoint, err)
+			}
+		}
+
+		f.wg.Add(1)
+		go f.waitForSpliceConfirmation(channel, chanSplice)
+	}
+}
+
+// waitForSpliceConfirmation waits for the splice transaction of the channel to
+// confirm, after which the channel moves over to its new funding outpoint,
+// and is resumed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *fundingManager) waitForSpliceConfirmation(
+	chanState *channeldb.OpenChannel, chanSplice *channeldb.ChannelSplice) {
+
+	defer f.wg.Done()
+
+	oldChanPoint := chanState.FundingOutpoint
+	newChanPoint := chanSplice.FundingOutpoint
+	txid := newChanPoint.Hash
+	pkScript := chanSplice.SpliceTx.TxOut[newChanPoint.Index].PkScript
+
+	numConfs := uint32(chanState.NumConfsRequired)
+	if numConfs == 0 {
+		numConfs = 1
+	}
+
+	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(
+		&txid, pkScript, numConfs, chanSplice.BroadcastHeight,
+	)
+	if err != nil {
+		fndgLog.Errorf("Unable to register for confirmation of "+
+			"splice tx %v: %v", txid, err)
+		return
+	}
+
+	fndgLog.Infof("Waiting for splice tx (%v) of ChannelPoint(%v) to "+
+		"reach %v confirmations", txid, oldChanPoint, numConfs)
+
+	var confDetails *chainntnfs.TxConfirmation
+	var ok bool
+	select {
+	case confDetails, ok = <-confNtfn.Confirmed:
+	case <-f.quit:
+		return
+	}
+	if !ok {
+		fndgLog.Warnf("ChainNotifier shutting down, cannot complete "+
+			"splice of ChannelPoint(%v)", oldChanPoint)
+		return
+	}
+
+	shortChanID := lnwire.ShortChannelID{
+		BlockHeight: confDetails.BlockHeight,
+		TxIndex:     confDetails.TxIndex,
+		TxPosition:  uint16(newChanPoint.Index),
+	}
+	if err := chanState.ApplySplice(shortChanID); err != nil {
+		fndgLog.Errorf("Unable to apply splice of ChannelPoint(%v): "+
+			"%v", oldChanPoint, err)
+		return
+	}
+
+	fndgLog.Infof("ChannelPoint(%v) spliced into ChannelPoint(%v), "+
+		"short_chan_id=%v", oldChanPoint, newChanPoint, shortChanID)
+
+	err = f.cfg.WatchSplicedChannel(oldChanPoint, chanState)
+	if err != nil {
+		fndgLog.Errorf("Unable to watch spliced ChannelPoint(%v): %v",
+			newChanPoint, err)
+	}
+
+	f.cfg.NotifyOpenChannelEvent(newChanPoint)
+
+	// The channel is announced under its new short channel ID like a
+	// newly opened channel, which is resumed from this state on restart.
+	err = f.saveChannelOpeningState(
+		&newChanPoint, fundingLockedSent, &shortChanID,
+	)
+	if err != nil {
+		fndgLog.Errorf("Unable to save opening state of "+
+			"ChannelPoint(%v): %v", newChanPoint, err)
+		return
+	}
+
+	// With the channel moved over, we resume it with the peer. Should the
+	// peer reconnect in the meantime, the channel is loaded along with
+	// its other channels.
+	peerChan := make(chan lnpeer.Peer, 1)
+	f.cfg.NotifyWhenOnline(chanState.IdentityPub, peerChan)
+
+	var peer lnpeer.Peer
+	select {
+	case peer = <-peerChan:
+	case <-f.quit:
+		return
+	}
+	if err := peer.AddNewChannel(chanState, f.quit); err != nil {
+		fndgLog.Errorf("Unable to resume ChannelPoint(%v) with peer "+
+			"%x: %v", newChanPoint, peer.PubKey(), err)
+	}
+
+	if err := f.addToRouterGraph(chanState, &shortChanID); err != nil {
+		fndgLog.Errorf("Failed adding spliced ChannelPoint(%v) to "+
+			"router graph: %v", newChanPoint, err)
+		return
+	}
+
+	if err := f.annAfterSixConfs(chanState, &shortChanID); err != nil {
+		fndgLog.Errorf("Error sending announcements of spliced "+
+			"ChannelPoint(%v): %v", newChanPoint, err)
+	}
+}
@@ -14,10 +14,14 @@ import (
 	"github.com/litecoinfinance/lnd/lnrpc/invoicesrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/routerrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/signrpc"
+	"github.com/litecoinfinance/lnd/lnrpc/towerrpc"
 	"github.com/litecoinfinance/lnd/lnrpc/walletrpc"
+	"github.com/litecoinfinance/lnd/lnrpc/wtclientrpc"
+	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/macaroons"
 	"github.com/litecoinfinance/lnd/netann"
 	"github.com/litecoinfinance/lnd/routing"
+	"github.com/litecoinfinance/lnd/sweep"
 )
 
 // subRPCServerConfigs is special sub-config in the main configuration that
@@ -55,6 +59,14 @@ type subRPCServerConfigs struct {
 	// payment related queries such as requests for estimates of off-chain
 	// fees.
 	RouterRPC *routerrpc.Config `group:"routerrpc" namespace:"routerrpc"`
+
+	// TowerRPC is a sub-RPC server that exposes the state of the
+	// watchtower, and allows its operator to manage the tower's clients.
+	TowerRPC *towerrpc.Config `group:"towerrpc" namespace:"towerrpc"`
+
+	// WatchtowerClientRPC is a sub-RPC server that exposes the sessions of
+	// the watchtower client, including the backups each can still hold.
+	WatchtowerClientRPC *wtclientrpc.Config `group:"wtclientrpc" namespace:"wtclientrpc"`
 }
 
 // PopulateDependencies attempts to iterate through all the sub-server configs
@@ -72,7 +84,11 @@ func (s *subRPCServerConfigs) PopulateDependencies(cc *chainControl,
 	chanRouter *routing.ChannelRouter,
 	routerBackend *routerrpc.RouterBackend,
 	nodeSigner *netann.NodeSigner,
-	chanDB *channeldb.DB) error {
+	chanDB *channeldb.DB,
+	sweeper *sweep.UtxoSweeper,
+	signChanUpdate func(lnwire.ShortChannelID,
+		...netann.ChannelUpdateModifier) (*lnwire.ChannelUpdate,
+		error)) error {
 
 	// First, we'll use reflect to obtain a version of the config struct
 	// that allows us to programmatically inspect its fields.
@@ -110,6 +126,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cc *chainControl,
 			subCfgValue.FieldByName("Signer").Set(
 				reflect.ValueOf(cc.signer),
 			)
+			subCfgValue.FieldByName("SignChannelUpdate").Set(
+				reflect.ValueOf(signChanUpdate),
+			)
 
 		case *walletrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -129,6 +148,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cc *chainControl,
 			subCfgValue.FieldByName("KeyRing").Set(
 				reflect.ValueOf(cc.keyRing),
 			)
+			subCfgValue.FieldByName("Sweeper").Set(
+				reflect.ValueOf(sweeper),
+			)
 
 		case *autopilotrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -149,6 +171,12 @@ func (s *subRPCServerConfigs) PopulateDependencies(cc *chainControl,
 			subCfgValue.FieldByName("ChainNotifier").Set(
 				reflect.ValueOf(cc.chainNotifier),
 			)
+			subCfgValue.FieldByName("ChainIO").Set(
+				reflect.ValueOf(cc.chainIO),
+			)
+			subCfgValue.FieldByName("FeeEstimator").Set(
+				reflect.ValueOf(cc.feeEstimator),
+			)
 
 		case *invoicesrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -204,6 +232,35 @@ func (s *subRPCServerConfigs) PopulateDependencies(cc *chainControl,
 				reflect.ValueOf(routerBackend),
 			)
 
+		case *towerrpc.Config:
+			// lnd doesn't run a watchtower of its own yet, so the
+			// Tower is left unset, and the sub-server will report
+			// the tower as inactive.
+			subCfgValue := extractReflectValue(subCfg)
+
+			subCfgValue.FieldByName("NetworkDir").Set(
+				reflect.ValueOf(networkDir),
+			)
+			subCfgValue.FieldByName("MacService").Set(
+				reflect.ValueOf(macService),
+			)
+
+		case *wtclientrpc.Config:
+			// As with the TowerRPC, lnd doesn't run a watchtower
+			// client yet, so the sub-server will report the client
+			// as inactive.
+			subCfgValue := extractReflectValue(subCfg)
+
+			subCfgValue.FieldByName("NetworkDir").Set(
+				reflect.ValueOf(networkDir),
+			)
+			subCfgValue.FieldByName("MacService").Set(
+				reflect.ValueOf(macService),
+			)
+			subCfgValue.FieldByName("Resolver").Set(
+				reflect.ValueOf(cfg.net.ResolveTCPAddr),
+			)
+
 		default:
 			return fmt.Errorf("unknown field: %v, %T", fieldName,
 				cfg)
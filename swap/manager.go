@@ -0,0 +1,339 @@
+package swap
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litecoinfinance/lnd/chainntnfs"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/contractcourt"
+	"github.com/litecoinfinance/lnd/input"
+	"github.com/litecoinfinance/lnd/keychain"
+	"github.com/litecoinfinance/lnd/lntypes"
+	"github.com/litecoinfinance/lnd/sweep"
+)
+
+// Config houses the dependencies of the Manager.
+type Config struct {
+	// Store persists the set of swaps.
+	Store Store
+
+	// KeyRing is used to derive the keys used within the on-chain HTLCs
+	// of swaps.
+	KeyRing keychain.KeyRing
+
+	// Notifier is used to watch the on-chain HTLCs of swaps.
+	Notifier chainntnfs.ChainNotifier
+
+	// PreimageDB is the global store of preimages. The preimages of loop
+	// out swaps are added to it, so their HTLCs can be claimed.
+	PreimageDB contractcourt.WitnessBeacon
+
+	// SweepInput offers the on-chain HTLC of a swap to the sweeper once
+	// we're able to spend it.
+	SweepInput func(input.Input) (chan sweep.Result, error)
+
+	// BestHeight returns the height of the current best block.
+	BestHeight func() (uint32, error)
+
+	// SubscribeInvoice subscribes to the updates of the invoice with the
+	// given payment hash. The returned closure cancels the subscription.
+	SubscribeInvoice func(lntypes.Hash) (<-chan *channeldb.Invoice, func())
+
+	// SettleInvoice settles the accepted hold invoice matching the given
+	// preimage.
+	SettleInvoice func(lntypes.Preimage) error
+}
+
+// Manager resolves the on-chain side of submarine swaps negotiated with a swap
+// provider by an external liquidity management tool. The tool obtains the
+// keys for the swaps' on-chain HTLCs from the Manager, so that it doesn't need
+// to manage any keys itself. Once a swap has been added, the Manager claims or
+// refunds its HTLC, and settles the hold invoice of loop in swaps once it has
+// been paid by the provider.
+type Manager struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *Config
+
+	// mu serializes all modifications of the swaps within the store, and
+	// guards the set of active resolvers.
+	mu        sync.Mutex
+	resolvers map[lntypes.Hash]*contractcourt.SwapResolver
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new swap manager backed by the given config.
+func New(cfg *Config) *Manager {
+	return &Manager{
+		cfg:       cfg,
+		resolvers: make(map[lntypes.Hash]*contractcourt.SwapResolver),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start resumes the resolution of all swaps that haven't reached a final
+// state yet.
+func (m *Manager) Start() error {
+	if !atomic.CompareAndSwapUint32(&m.started, 0, 1) {
+		return nil
+	}
+
+	log.Info("Swap manager starting")
+
+	swaps, err := m.cfg.Store.FetchSwaps()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, swap := range swaps {
+		if swap.State.IsFinal() {
+			continue
+		}
+
+		if err := m.launchSwap(swap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stop signals the manager for a graceful shutdown. Pending swaps are resumed
+// on the next start.
+func (m *Manager) Stop() error {
+	if !atomic.CompareAndSwapUint32(&m.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Info("Swap manager shutting down")
+
+	close(m.quit)
+
+	m.mu.Lock()
+	for _, resolver := range m.resolvers {
+		resolver.Stop()
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+
+	return nil
+}
+
+// DeriveKey derives a fresh key to be used within the on-chain HTLC of a new
+// swap. The key locator must be passed back when adding the swap.
+func (m *Manager) DeriveKey() (keychain.KeyDescriptor, error) {
+	return m.cfg.KeyRing.DeriveNextKey(keychain.KeyFamilySwap)
+}
+
+// AddSwap validates and persists a new swap, and starts resolving its
+// on-chain HTLC. Only the key locator of the swap's local key needs to be
+// set, as the key itself is derived by the manager.
+func (m *Manager) AddSwap(swap *Swap) error {
+	htlc := &swap.HTLC
+
+	switch {
+	case swap.Type != TypeLoopOut && swap.Type != TypeLoopIn:
+		return fmt.Errorf("unknown swap type: %v", swap.Type)
+
+	case swap.Type == TypeLoopOut && swap.Preimage == nil:
+		return errors.New("preimage required for loop out swaps")
+
+	case swap.Preimage != nil && !swap.Preimage.Matches(htlc.PaymentHash):
+		return errors.New("preimage doesn't match payment hash")
+
+	case htlc.RemoteKey == nil:
+		return errors.New("remote key of swap htlc not set")
+
+	case htlc.LocalKey.Family != keychain.KeyFamilySwap:
+		return fmt.Errorf("local key must be of the swap key family "+
+			"%d", keychain.KeyFamilySwap)
+	}
+
+	bestHeight, err := m.cfg.BestHeight()
+	if err != nil {
+		return err
+	}
+	if htlc.CltvExpiry <= bestHeight {
+		return fmt.Errorf("swap htlc expiry %d is not beyond the "+
+			"current height %d", htlc.CltvExpiry, bestHeight)
+	}
+
+	// We'll derive the local key ourselves, ensuring we're able to sign
+	// for it.
+	htlc.LocalKey, err = m.cfg.KeyRing.DeriveKey(htlc.LocalKey.KeyLocator)
+	if err != nil {
+		return fmt.Errorf("unable to derive local key: %v", err)
+	}
+
+	htlc.Role = swap.Type.role()
+	if htlc.HeightHint == 0 {
+		htlc.HeightHint = bestHeight
+	}
+	htlc.OutPoint = nil
+	htlc.Amount = 0
+
+	swap.State = StateInitiated
+	swap.InitiationTime = time.Now()
+	swap.LastUpdate = swap.InitiationTime
+	swap.SpendTxid = nil
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.cfg.Store.AddSwap(swap); err != nil {
+		return err
+	}
+
+	log.Infof("Added %v swap with payment hash %v", swap.Type,
+		htlc.PaymentHash)
+
+	return m.launchSwap(swap)
+}
+
+// Swaps returns all swaps known to the manager.
+func (m *Manager) Swaps() ([]*Swap, error) {
+	return m.cfg.Store.FetchSwaps()
+}
+
+// launchSwap starts the goroutines resolving the given swap.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *Manager) launchSwap(swap *Swap) error {
+	// The preimage of a loop out swap is needed to claim its HTLC, so
+	// we'll make sure it's known to the resolver.
+	if swap.Type == TypeLoopOut {
+		err := m.cfg.PreimageDB.AddPreimages(*swap.Preimage)
+		if err != nil {
+			return err
+		}
+	}
+
+	hash := swap.HTLC.PaymentHash
+	resolver := contractcourt.NewSwapResolver(
+		&swap.HTLC, contractcourt.SwapResolverConfig{
+			Notifier:   m.cfg.Notifier,
+			PreimageDB: m.cfg.PreimageDB,
+			SweepInput: m.cfg.SweepInput,
+			Checkpoint: func(*contractcourt.SwapHTLC) error {
+				return m.updateSwap(swap, StateHtlcConfirmed)
+			},
+		},
+	)
+	m.resolvers[hash] = resolver
+
+	m.wg.Add(1)
+	go m.resolveSwap(swap, resolver)
+
+	if swap.Type == TypeLoopIn && swap.Preimage != nil {
+		m.wg.Add(1)
+		go m.settleInvoice(*swap.Preimage)
+	}
+
+	return nil
+}
+
+// resolveSwap waits for the resolution of the swap's on-chain HTLC, and
+// updates the state of the swap accordingly.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) resolveSwap(swap *Swap,
+	resolver *contractcourt.SwapResolver) {
+
+	defer m.wg.Done()
+
+	hash := swap.HTLC.PaymentHash
+	defer func() {
+		m.mu.Lock()
+		delete(m.resolvers, hash)
+		m.mu.Unlock()
+	}()
+
+	resolution, err := resolver.Resolve()
+	if err != nil {
+		select {
+		case <-m.quit:
+		default:
+			log.Errorf("Unable to resolve swap with payment hash "+
+				"%v: %v", hash, err)
+		}
+		return
+	}
+
+	state := StateRefunded
+	if resolution.Outcome == contractcourt.SwapOutcomeSuccess {
+		state = StateSuccess
+	}
+
+	swap.SpendTxid = &resolution.SpendTxid
+	if err := m.updateSwap(swap, state); err != nil {
+		log.Errorf("Unable to update swap with payment hash %v: %v",
+			hash, err)
+	}
+}
+
+// settleInvoice settles the hold invoice of a loop in swap once it has been
+// paid by the swap provider.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) settleInvoice(preimage lntypes.Preimage) {
+	defer m.wg.Done()
+
+	hash := preimage.Hash()
+	updates, cancel := m.cfg.SubscribeInvoice(hash)
+	defer cancel()
+
+	for {
+		select {
+		case invoice, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			switch invoice.Terms.State {
+			case channeldb.ContractAccepted:
+				log.Infof("Settling hold invoice of swap with "+
+					"payment hash %v", hash)
+
+				err := m.cfg.SettleInvoice(preimage)
+				if err != nil {
+					log.Errorf("Unable to settle hold "+
+						"invoice %v: %v", hash, err)
+				}
+				return
+
+			case channeldb.ContractSettled,
+				channeldb.ContractCanceled:
+
+				return
+			}
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// updateSwap transitions the swap to the given state and persists it.
+func (m *Manager) updateSwap(swap *Swap, state State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	log.Infof("Swap with payment hash %v transitioning from %v to %v",
+		swap.HTLC.PaymentHash, swap.State, state)
+
+	swap.State = state
+	swap.LastUpdate = time.Now()
+
+	return m.cfg.Store.UpdateSwap(swap)
+}
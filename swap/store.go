@@ -0,0 +1,132 @@
+package swap
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
+	"github.com/litecoinfinance/lnd/lntypes"
+)
+
+var (
+	// swapsBucketKey is the key of the top-level bucket that stores all
+	// submarine swaps.
+	//
+	// maps: paymentHash -> serialized swap
+	swapsBucketKey = []byte("submarine-swaps")
+
+	// ErrSwapNotFound is returned when a swap with the target payment hash
+	// doesn't exist.
+	ErrSwapNotFound = errors.New("swap not found")
+
+	// ErrSwapExists is returned when attempting to add a swap with the
+	// payment hash of an existing one.
+	ErrSwapExists = errors.New("swap with payment hash already exists")
+)
+
+// Store persists the set of submarine swaps.
+type Store interface {
+	// AddSwap adds a new swap to the store. ErrSwapExists is returned if
+	// a swap with the same payment hash already exists.
+	AddSwap(s *Swap) error
+
+	// UpdateSwap overwrites an existing swap with the given one.
+	// ErrSwapNotFound is returned if the swap doesn't exist.
+	UpdateSwap(s *Swap) error
+
+	// FetchSwaps returns all swaps known to the store.
+	FetchSwaps() ([]*Swap, error)
+}
+
+// swapStore is an implementation of the Store interface backed by the channel
+// database.
+type swapStore struct {
+	db *channeldb.DB
+}
+
+// A compile-time check to ensure swapStore implements the Store interface.
+var _ Store = (*swapStore)(nil)
+
+// NewStore returns a new Store backed by the given channel database.
+func NewStore(db *channeldb.DB) (Store, error) {
+	err := db.Update(func(tx kvdb.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(swapsBucketKey)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &swapStore{
+		db: db,
+	}, nil
+}
+
+// AddSwap adds a new swap to the store. ErrSwapExists is returned if a swap
+// with the same payment hash already exists.
+//
+// NOTE: Part of the Store interface.
+func (s *swapStore) AddSwap(swap *Swap) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		swaps := tx.Bucket(swapsBucketKey)
+
+		hash := swap.HTLC.PaymentHash
+		if swaps.Get(hash[:]) != nil {
+			return ErrSwapExists
+		}
+
+		return putSwap(swaps, hash, swap)
+	})
+}
+
+// UpdateSwap overwrites an existing swap with the given one. ErrSwapNotFound
+// is returned if the swap doesn't exist.
+//
+// NOTE: Part of the Store interface.
+func (s *swapStore) UpdateSwap(swap *Swap) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
+		swaps := tx.Bucket(swapsBucketKey)
+
+		hash := swap.HTLC.PaymentHash
+		if swaps.Get(hash[:]) == nil {
+			return ErrSwapNotFound
+		}
+
+		return putSwap(swaps, hash, swap)
+	})
+}
+
+// FetchSwaps returns all swaps known to the store.
+//
+// NOTE: Part of the Store interface.
+func (s *swapStore) FetchSwaps() ([]*Swap, error) {
+	var swaps []*Swap
+	err := s.db.View(func(tx kvdb.Tx) error {
+		return tx.Bucket(swapsBucketKey).ForEach(func(_, v []byte) error {
+			swap, err := deserializeSwap(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			swaps = append(swaps, swap)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return swaps, nil
+}
+
+// putSwap serializes the swap and stores it under its payment hash.
+func putSwap(swaps kvdb.Bucket, hash lntypes.Hash, swap *Swap) error {
+	var b bytes.Buffer
+	if err := serializeSwap(&b, swap); err != nil {
+		return err
+	}
+
+	return swaps.Put(hash[:], b.Bytes())
+}
@@ -0,0 +1,126 @@
+package swap
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/contractcourt"
+	"github.com/litecoinfinance/lnd/keychain"
+	"github.com/litecoinfinance/lnd/lntypes"
+)
+
+// makeTestDB creates a new instance of the ChannelDB for testing purposes. A
+// callback which cleans up the created temporary directories is also returned
+// and intended to be executed after the test completes.
+func makeTestDB() (*channeldb.DB, func(), error) {
+	tempDirName, err := ioutil.TempDir("", "swap")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cdb, err := channeldb.Open(tempDirName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanUp := func() {
+		cdb.Close()
+		os.RemoveAll(tempDirName)
+	}
+
+	return cdb, cleanUp, nil
+}
+
+// TestStore asserts that swaps can be added to and updated within the store.
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to open channel db: %v", err)
+	}
+	defer cleanUp()
+
+	store, err := NewStore(cdb)
+	if err != nil {
+		t.Fatalf("unable to create store: %v", err)
+	}
+
+	_, localKey := btcec.PrivKeyFromBytes(btcec.S256(), []byte{0x01})
+	_, remoteKey := btcec.PrivKeyFromBytes(btcec.S256(), []byte{0x02})
+	preimage := lntypes.Preimage{0x01}
+
+	swap := &Swap{
+		Type:  TypeLoopOut,
+		State: StateInitiated,
+		HTLC: contractcourt.SwapHTLC{
+			Role:        contractcourt.SwapRoleReceiver,
+			PaymentHash: preimage.Hash(),
+			LocalKey: keychain.KeyDescriptor{
+				KeyLocator: keychain.KeyLocator{
+					Family: keychain.KeyFamilySwap,
+					Index:  3,
+				},
+				PubKey: localKey,
+			},
+			RemoteKey:  remoteKey,
+			CltvExpiry: 500,
+			HeightHint: 400,
+		},
+		Preimage:       &preimage,
+		InitiationTime: time.Unix(0, 1000),
+		LastUpdate:     time.Unix(0, 1000),
+	}
+	if err := store.AddSwap(swap); err != nil {
+		t.Fatalf("unable to add swap: %v", err)
+	}
+
+	// A swap with the same payment hash can't be added twice.
+	if err := store.AddSwap(swap); err != ErrSwapExists {
+		t.Fatalf("expected ErrSwapExists, got %v", err)
+	}
+
+	assertSwaps := func(expected ...*Swap) {
+		t.Helper()
+
+		swaps, err := store.FetchSwaps()
+		if err != nil {
+			t.Fatalf("unable to fetch swaps: %v", err)
+		}
+		if !reflect.DeepEqual(swaps, expected) {
+			t.Fatalf("expected swaps %v, got %v",
+				spew.Sdump(expected), spew.Sdump(swaps))
+		}
+	}
+	assertSwaps(swap)
+
+	// Once the HTLC has been resolved, its outpoint and spend should be
+	// persisted along with the new state.
+	swap.State = StateSuccess
+	swap.HTLC.OutPoint = &wire.OutPoint{
+		Hash:  chainhash.Hash{0x02},
+		Index: 1,
+	}
+	swap.HTLC.Amount = 100000
+	swap.SpendTxid = &chainhash.Hash{0x03}
+	swap.LastUpdate = time.Unix(0, 2000)
+	if err := store.UpdateSwap(swap); err != nil {
+		t.Fatalf("unable to update swap: %v", err)
+	}
+	assertSwaps(swap)
+
+	// Updating an unknown swap should fail.
+	unknown := *swap
+	unknown.HTLC.PaymentHash = lntypes.Hash{0x04}
+	if err := store.UpdateSwap(&unknown); err != ErrSwapNotFound {
+		t.Fatalf("expected ErrSwapNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,235 @@
+package swap
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/contractcourt"
+	"github.com/litecoinfinance/lnd/lntypes"
+)
+
+// Type denotes the direction of a submarine swap.
+type Type uint16
+
+const (
+	// TypeLoopOut is a swap of off-chain funds for on-chain funds. We pay
+	// the swap provider off-chain, and claim the on-chain HTLC published
+	// by the provider using the preimage.
+	TypeLoopOut Type = iota
+
+	// TypeLoopIn is a swap of on-chain funds for off-chain funds. We
+	// publish the on-chain HTLC, and the swap provider pays our hold
+	// invoice in order to learn the preimage required to claim it.
+	TypeLoopIn
+)
+
+// String returns a human readable version of the swap type.
+func (t Type) String() string {
+	switch t {
+	case TypeLoopOut:
+		return "LoopOut"
+	case TypeLoopIn:
+		return "LoopIn"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint16(t))
+	}
+}
+
+// role returns the side of the on-chain HTLC we're on for this swap type.
+func (t Type) role() contractcourt.SwapRole {
+	if t == TypeLoopIn {
+		return contractcourt.SwapRoleSender
+	}
+
+	return contractcourt.SwapRoleReceiver
+}
+
+// State describes the progress of a submarine swap.
+type State uint16
+
+const (
+	// StateInitiated is the state of a swap whose on-chain HTLC hasn't
+	// been confirmed yet.
+	StateInitiated State = iota
+
+	// StateHtlcConfirmed is the state of a swap whose on-chain HTLC has
+	// been confirmed, but not spent yet.
+	StateHtlcConfirmed
+
+	// StateSuccess is the final state of a swap whose on-chain HTLC was
+	// claimed by its receiver using the preimage.
+	StateSuccess
+
+	// StateRefunded is the final state of a swap whose on-chain HTLC was
+	// reclaimed by its sender after it expired.
+	StateRefunded
+)
+
+// String returns a human readable version of the swap state.
+func (s State) String() string {
+	switch s {
+	case StateInitiated:
+		return "Initiated"
+	case StateHtlcConfirmed:
+		return "HtlcConfirmed"
+	case StateSuccess:
+		return "Success"
+	case StateRefunded:
+		return "Refunded"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint16(s))
+	}
+}
+
+// IsFinal returns true if the swap can't make any further progress.
+func (s State) IsFinal() bool {
+	return s == StateSuccess || s == StateRefunded
+}
+
+// Swap is a submarine swap negotiated with a swap provider, whose on-chain
+// side is resolved by lnd.
+type Swap struct {
+	// Type is the direction of the swap.
+	Type Type
+
+	// State is the current state of the swap.
+	State State
+
+	// HTLC describes the on-chain HTLC of the swap. Its payment hash
+	// uniquely identifies the swap.
+	HTLC contractcourt.SwapHTLC
+
+	// Preimage is the preimage of the swap's payment hash. It is required
+	// for loop out swaps, in order to claim the on-chain HTLC. For loop in
+	// swaps, it is used to settle the hold invoice paid by the swap
+	// provider. If it's not set, the hold invoice is expected to be
+	// settled by the caller.
+	Preimage *lntypes.Preimage
+
+	// InitiationTime is the time at which the swap was added.
+	InitiationTime time.Time
+
+	// LastUpdate is the time of the last state transition of the swap.
+	LastUpdate time.Time
+
+	// SpendTxid is the hash of the transaction that spent the on-chain
+	// HTLC. It is only set once the swap has reached a final state.
+	SpendTxid *chainhash.Hash
+}
+
+// serializeSwap writes the swap to the passed io.Writer.
+func serializeSwap(w io.Writer, s *Swap) error {
+	err := channeldb.WriteElements(w,
+		uint16(s.Type), uint16(s.State), [32]byte(s.HTLC.PaymentHash),
+		s.HTLC.LocalKey, s.HTLC.RemoteKey, s.HTLC.CltvExpiry,
+		s.HTLC.HeightHint, s.HTLC.Amount,
+		uint64(s.InitiationTime.UnixNano()),
+		uint64(s.LastUpdate.UnixNano()),
+	)
+	if err != nil {
+		return err
+	}
+
+	// The remaining fields are optional, so we'll prefix each of them with
+	// a flag signalling whether they're present.
+	if err := channeldb.WriteElement(w, s.Preimage != nil); err != nil {
+		return err
+	}
+	if s.Preimage != nil {
+		err := channeldb.WriteElement(w, [32]byte(*s.Preimage))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := channeldb.WriteElement(w, s.HTLC.OutPoint != nil); err != nil {
+		return err
+	}
+	if s.HTLC.OutPoint != nil {
+		if err := channeldb.WriteElement(w, *s.HTLC.OutPoint); err != nil {
+			return err
+		}
+	}
+
+	if err := channeldb.WriteElement(w, s.SpendTxid != nil); err != nil {
+		return err
+	}
+	if s.SpendTxid != nil {
+		if err := channeldb.WriteElement(w, *s.SpendTxid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeSwap reads a swap serialized by serializeSwap from the passed
+// io.Reader.
+func deserializeSwap(r io.Reader) (*Swap, error) {
+	var (
+		s              Swap
+		swapType       uint16
+		state          uint16
+		paymentHash    [32]byte
+		initiationTime uint64
+		lastUpdate     uint64
+	)
+	err := channeldb.ReadElements(r,
+		&swapType, &state, &paymentHash, &s.HTLC.LocalKey,
+		&s.HTLC.RemoteKey, &s.HTLC.CltvExpiry, &s.HTLC.HeightHint,
+		&s.HTLC.Amount, &initiationTime, &lastUpdate,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Type = Type(swapType)
+	s.State = State(state)
+	s.HTLC.Role = s.Type.role()
+	s.HTLC.PaymentHash = paymentHash
+	s.InitiationTime = time.Unix(0, int64(initiationTime))
+	s.LastUpdate = time.Unix(0, int64(lastUpdate))
+
+	var hasPreimage bool
+	if err := channeldb.ReadElement(r, &hasPreimage); err != nil {
+		return nil, err
+	}
+	if hasPreimage {
+		var preimage [32]byte
+		if err := channeldb.ReadElement(r, &preimage); err != nil {
+			return nil, err
+		}
+		p := lntypes.Preimage(preimage)
+		s.Preimage = &p
+	}
+
+	var hasOutPoint bool
+	if err := channeldb.ReadElement(r, &hasOutPoint); err != nil {
+		return nil, err
+	}
+	if hasOutPoint {
+		var outPoint wire.OutPoint
+		if err := channeldb.ReadElement(r, &outPoint); err != nil {
+			return nil, err
+		}
+		s.HTLC.OutPoint = &outPoint
+	}
+
+	var hasSpendTxid bool
+	if err := channeldb.ReadElement(r, &hasSpendTxid); err != nil {
+		return nil, err
+	}
+	if hasSpendTxid {
+		var txid chainhash.Hash
+		if err := channeldb.ReadElement(r, &txid); err != nil {
+			return nil, err
+		}
+		s.SpendTxid = &txid
+	}
+
+	return &s, nil
+}
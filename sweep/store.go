@@ -8,8 +8,8 @@ import (
 
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
-	"github.com/coreos/bbolt"
 	"github.com/litecoinfinance/lnd/channeldb"
+	"github.com/litecoinfinance/lnd/channeldb/kvdb"
 )
 
 var (
@@ -64,7 +64,7 @@ type sweeperStore struct {
 func NewSweeperStore(db *channeldb.DB, chainHash *chainhash.Hash) (
 	SweeperStore, error) {
 
-	err := db.Update(func(tx *bbolt.Tx) error {
+	err := db.Update(func(tx kvdb.Tx) error {
 		_, err := tx.CreateBucketIfNotExists(
 			lastTxBucketKey,
 		)
@@ -98,7 +98,7 @@ func NewSweeperStore(db *channeldb.DB, chainHash *chainhash.Hash) (
 
 // migrateTxHashes migrates nursery finalized txes to the tx hashes bucket. This
 // is not implemented as a database migration, to keep the downgrade path open.
-func migrateTxHashes(tx *bbolt.Tx, txHashesBucket *bbolt.Bucket,
+func migrateTxHashes(tx kvdb.Tx, txHashesBucket kvdb.Bucket,
 	chainHash *chainhash.Hash) error {
 
 	log.Infof("Migrating UTXO nursery finalized TXIDs")
@@ -164,7 +164,7 @@ func migrateTxHashes(tx *bbolt.Tx, txHashesBucket *bbolt.Bucket,
 
 // NotifyPublishTx signals that we are about to publish a tx.
 func (s *sweeperStore) NotifyPublishTx(sweepTx *wire.MsgTx) error {
-	return s.db.Update(func(tx *bbolt.Tx) error {
+	return s.db.Update(func(tx kvdb.Tx) error {
 		lastTxBucket := tx.Bucket(lastTxBucketKey)
 		if lastTxBucket == nil {
 			return errors.New("last tx bucket does not exist")
@@ -195,7 +195,7 @@ func (s *sweeperStore) NotifyPublishTx(sweepTx *wire.MsgTx) error {
 func (s *sweeperStore) GetLastPublishedTx() (*wire.MsgTx, error) {
 	var sweepTx *wire.MsgTx
 
-	err := s.db.View(func(tx *bbolt.Tx) error {
+	err := s.db.View(func(tx kvdb.Tx) error {
 		lastTxBucket := tx.Bucket(lastTxBucketKey)
 		if lastTxBucket == nil {
 			return errors.New("last tx bucket does not exist")
@@ -226,7 +226,7 @@ func (s *sweeperStore) GetLastPublishedTx() (*wire.MsgTx, error) {
 func (s *sweeperStore) IsOurTx(hash chainhash.Hash) (bool, error) {
 	var ours bool
 
-	err := s.db.View(func(tx *bbolt.Tx) error {
+	err := s.db.View(func(tx kvdb.Tx) error {
 		txHashesBucket := tx.Bucket(txHashesBucketKey)
 		if txHashesBucket == nil {
 			return errors.New("tx hashes bucket does not exist")
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -25,12 +26,43 @@ var (
 	// for the configured max number of attempts.
 	ErrTooManyAttempts = errors.New("sweep failed after max attempts")
 
+	// ErrUnknownInput is returned when the parameters of an input that
+	// isn't currently being swept are to be updated.
+	ErrUnknownInput = errors.New("unknown input")
+
+	// ErrSweeperShuttingDown is returned when a request is made to the
+	// sweeper while it's shutting down.
+	ErrSweeperShuttingDown = errors.New("sweeper shutting down")
+
 	// DefaultMaxSweepAttempts specifies the default maximum number of times
 	// an input is included in a publish attempt before giving up and
 	// returning an error to the caller.
 	DefaultMaxSweepAttempts = 10
+
+	// DefaultFeeRateBucketSize is the default size, in sat/vbyte, of the
+	// fee rate buckets that pending inputs are grouped into. Inputs whose
+	// fee rates fall into the same bucket are swept together.
+	DefaultFeeRateBucketSize = 10
 )
 
+// Params contains the parameters that control the sweeping process of an
+// input.
+type Params struct {
+	// Fee is the fee preference of the client who requested the input to
+	// be swept. If empty, the sweeper's default confirmation target is
+	// used.
+	Fee FeePreference
+
+	// Force indicates whether the input should be swept right away,
+	// without waiting for the batch window to expire.
+	Force bool
+}
+
+// String returns a human readable interpretation of the sweep parameters.
+func (p Params) String() string {
+	return fmt.Sprintf("fee=%v, force=%v", p.Fee, p.Force)
+}
+
 // pendingInput is created when an input reaches the main loop for the first
 // time. It tracks all relevant state that is needed for sweeping.
 type pendingInput struct {
@@ -53,6 +85,70 @@ type pendingInput struct {
 	// publishAttempts records the number of attempts that have already been
 	// made to sweep this tx.
 	publishAttempts int
+
+	// params contains the parameters that control the sweeping process.
+	params Params
+
+	// lastFeeRate is the most recent fee rate used for this input within a
+	// transaction broadcast to the network.
+	lastFeeRate lnwallet.SatPerKWeight
+}
+
+// PendingInput contains information about an input that is currently being
+// swept by the UtxoSweeper.
+type PendingInput struct {
+	// OutPoint is the outpoint of the input being swept.
+	OutPoint wire.OutPoint
+
+	// WitnessType is the witness type of the input being swept.
+	WitnessType input.WitnessType
+
+	// Amount is the amount of the input being swept.
+	Amount btcutil.Amount
+
+	// LastFeeRate is the most recent fee rate used for the input being
+	// swept within a transaction broadcast to the network.
+	LastFeeRate lnwallet.SatPerKWeight
+
+	// BroadcastAttempts is the number of attempts we've made to sweep the
+	// input.
+	BroadcastAttempts int
+
+	// NextBroadcastHeight is the next height of the chain at which we'll
+	// attempt to broadcast a transaction sweeping the input.
+	NextBroadcastHeight uint32
+
+	// Params contains the sweep parameters of the input.
+	Params Params
+}
+
+// inputCluster is a group of pending inputs whose fee rates fall into the
+// same fee rate bucket, and which are therefore swept together.
+type inputCluster struct {
+	sweepFeeRate lnwallet.SatPerKWeight
+	inputs       map[wire.OutPoint]*pendingInput
+}
+
+// pendingSweepsReq is an internal message we'll use to represent an external
+// caller's intent to retrieve all of the pending inputs the UtxoSweeper is
+// attempting to sweep.
+type pendingSweepsReq struct {
+	respChan chan map[wire.OutPoint]*PendingInput
+}
+
+// updateReq is an internal message we'll use to represent an external
+// caller's intent to update the sweep parameters of a given input.
+type updateReq struct {
+	input        wire.OutPoint
+	params       Params
+	responseChan chan *updateResp
+}
+
+// updateResp is an internal message we'll use to hand off the response of a
+// updateReq from the UtxoSweeper's main event loop back to the caller.
+type updateResp struct {
+	resultChan chan Result
+	err        error
 }
 
 // UtxoSweeper is responsible for sweeping outputs back into the wallet
@@ -65,6 +161,15 @@ type UtxoSweeper struct {
 	newInputs chan *sweepInputMessage
 	spendChan chan *chainntnfs.SpendDetail
 
+	// pendingSweepsReq is a channel that will be sent requests by external
+	// callers in order to retrieve the set of pending inputs the
+	// UtxoSweeper is attempting to sweep.
+	pendingSweepsReqs chan *pendingSweepsReq
+
+	// updateReqs is a channel that will be sent requests by external
+	// callers who wish to bump the fee rate of a given input.
+	updateReqs chan *updateReq
+
 	pendingInputs map[wire.OutPoint]*pendingInput
 
 	// timer is the channel that signals expiry of the sweep batch timer.
@@ -74,8 +179,6 @@ type UtxoSweeper struct {
 
 	currentOutputScript []byte
 
-	relayFeePerKW lnwallet.SatPerKWeight
-
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -100,6 +203,12 @@ type UtxoSweeperConfig struct {
 	// be added to the sweep tx that is about to be generated.
 	NewBatchTimer func() <-chan time.Time
 
+	// FeeRateBucketSize is the size, in sat/vbyte, of the fee rate buckets
+	// that pending inputs are grouped into. Inputs whose fee rates fall
+	// into the same bucket are swept together at the highest of their fee
+	// rates.
+	FeeRateBucketSize int
+
 	// Notifier is an instance of a chain notifier we'll use to watch for
 	// certain on-chain events.
 	Notifier chainntnfs.ChainNotifier
@@ -157,11 +266,13 @@ type sweepInputMessage struct {
 func New(cfg *UtxoSweeperConfig) *UtxoSweeper {
 
 	return &UtxoSweeper{
-		cfg:           cfg,
-		newInputs:     make(chan *sweepInputMessage),
-		spendChan:     make(chan *chainntnfs.SpendDetail),
-		quit:          make(chan struct{}),
-		pendingInputs: make(map[wire.OutPoint]*pendingInput),
+		cfg:               cfg,
+		newInputs:         make(chan *sweepInputMessage),
+		spendChan:         make(chan *chainntnfs.SpendDetail),
+		pendingSweepsReqs: make(chan *pendingSweepsReq),
+		updateReqs:        make(chan *updateReq),
+		quit:              make(chan struct{}),
+		pendingInputs:     make(map[wire.OutPoint]*pendingInput),
 	}
 }
 
@@ -197,10 +308,6 @@ func (s *UtxoSweeper) Start() error {
 		}
 	}
 
-	// Retrieve relay fee for dust limit calculation. Assume that this will
-	// not change from here on.
-	s.relayFeePerKW = s.cfg.FeeEstimator.RelayFeePerKW()
-
 	// Register for block epochs to retry sweeping every block.
 	bestHash, bestHeight, err := s.cfg.ChainIO.GetBestBlock()
 	if err != nil {
@@ -276,12 +383,64 @@ func (s *UtxoSweeper) SweepInput(input input.Input) (chan Result, error) {
 	select {
 	case s.newInputs <- sweeperInput:
 	case <-s.quit:
-		return nil, fmt.Errorf("sweeper shutting down")
+		return nil, ErrSweeperShuttingDown
 	}
 
 	return sweeperInput.resultChan, nil
 }
 
+// PendingInputs returns the set of inputs that the UtxoSweeper is currently
+// attempting to sweep.
+func (s *UtxoSweeper) PendingInputs() (map[wire.OutPoint]*PendingInput, error) {
+	respChan := make(chan map[wire.OutPoint]*PendingInput, 1)
+	select {
+	case s.pendingSweepsReqs <- &pendingSweepsReq{
+		respChan: respChan,
+	}:
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+
+	select {
+	case pendingSweeps := <-respChan:
+		return pendingSweeps, nil
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+}
+
+// UpdateParams allows updating the sweep parameters of a pending input in the
+// UtxoSweeper. This function can be used to provide an updated fee preference
+// that will be used for a new sweep transaction of the input that will act as
+// a replacement transaction (RBF) of the original sweeping transaction, if
+// any. If Force is set, the input is swept right away rather than waiting for
+// the batch window to expire.
+//
+// NOTE: This currently doesn't do any fee rate validation to ensure that a bump
+// is actually successful. The responsibility of doing so should be handled by
+// the caller.
+func (s *UtxoSweeper) UpdateParams(input wire.OutPoint,
+	params Params) (chan Result, error) {
+
+	responseChan := make(chan *updateResp, 1)
+	select {
+	case s.updateReqs <- &updateReq{
+		input:        input,
+		params:       params,
+		responseChan: responseChan,
+	}:
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+
+	select {
+	case response := <-responseChan:
+		return response.resultChan, response.err
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+}
+
 // collector is the sweeper main loop. It processes new inputs, spend
 // notifications and counts down to publication of the sweep tx.
 func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch,
@@ -398,6 +557,20 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch,
 				log.Errorf("schedule sweep: %v", err)
 			}
 
+		// A new external request has been received to retrieve all of
+		// the inputs we're currently attempting to sweep.
+		case req := <-s.pendingSweepsReqs:
+			req.respChan <- s.handlePendingSweepsReq()
+
+		// A new external request has been received to bump the fee
+		// rate of a given input.
+		case req := <-s.updateReqs:
+			resultChan, err := s.handleUpdateReq(req, bestHeight)
+			req.responseChan <- &updateResp{
+				resultChan: resultChan,
+				err:        err,
+			}
+
 		// The timer expires and we are going to (re)sweep.
 		case <-s.timer:
 			log.Debugf("Sweep timer expired")
@@ -406,31 +579,7 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch,
 			// be started when new inputs arrive.
 			s.timer = nil
 
-			// Retrieve fee estimate for input filtering and final
-			// tx fee calculation.
-			satPerKW, err := s.cfg.FeeEstimator.EstimateFeePerKW(
-				s.cfg.SweepTxConfTarget,
-			)
-			if err != nil {
-				log.Errorf("estimate fee: %v", err)
-				continue
-			}
-
-			// Examine pending inputs and try to construct lists of
-			// inputs.
-			inputLists, err := s.getInputLists(bestHeight, satPerKW)
-			if err != nil {
-				log.Errorf("get input lists: %v", err)
-				continue
-			}
-
-			// Sweep selected inputs.
-			for _, inputs := range inputLists {
-				err := s.sweep(inputs, satPerKW, bestHeight)
-				if err != nil {
-					log.Errorf("sweep: %v", err)
-				}
-			}
+			s.sweepPendingInputs(bestHeight)
 
 		// A new block comes in. Things may have changed, so we retry a
 		// sweep.
@@ -454,6 +603,95 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch,
 	}
 }
 
+// sweepPendingInputs groups the pending inputs by their fee rates, and sweeps
+// each resulting cluster of inputs at its fee rate.
+func (s *UtxoSweeper) sweepPendingInputs(currentHeight int32) {
+	for _, cluster := range s.clusterBySweepFeeRate() {
+		// Examine pending inputs and try to construct lists of
+		// inputs.
+		inputLists, err := s.getInputLists(cluster, currentHeight)
+		if err != nil {
+			log.Errorf("get input lists: %v", err)
+			continue
+		}
+
+		// Sweep selected inputs.
+		for _, inputs := range inputLists {
+			err := s.sweep(
+				inputs, cluster.sweepFeeRate, currentHeight,
+			)
+			if err != nil {
+				log.Errorf("sweep: %v", err)
+			}
+		}
+	}
+}
+
+// feeRateForPreference returns a fee rate for the given fee preference. If the
+// preference is empty, the sweeper's default confirmation target is used.
+func (s *UtxoSweeper) feeRateForPreference(
+	feePreference FeePreference) (lnwallet.SatPerKWeight, error) {
+
+	if feePreference.ConfTarget == 0 && feePreference.FeeRate == 0 {
+		feePreference.ConfTarget = s.cfg.SweepTxConfTarget
+	}
+
+	return DetermineFeePerKw(s.cfg.FeeEstimator, feePreference)
+}
+
+// clusterBySweepFeeRate takes the set of pending inputs within the UtxoSweeper
+// and clusters those together with similar fee rates. Each cluster contains
+// the inputs whose fee rates fall into the same fee rate bucket, and will be
+// swept at the highest fee rate among them.
+func (s *UtxoSweeper) clusterBySweepFeeRate() []inputCluster {
+	bucketSize := s.cfg.FeeRateBucketSize
+	if bucketSize <= 0 {
+		bucketSize = 1
+	}
+	bucketFeeRate := lnwallet.SatPerKVByte(
+		bucketSize * 1000,
+	).FeePerKWeight()
+
+	clusters := make(map[lnwallet.SatPerKWeight]*inputCluster)
+	for op, input := range s.pendingInputs {
+		feeRate, err := s.feeRateForPreference(input.params.Fee)
+		if err != nil {
+			log.Warnf("Skipping input %v: %v", op, err)
+			continue
+		}
+
+		bucket := feeRate / bucketFeeRate
+		cluster, ok := clusters[bucket]
+		if !ok {
+			cluster = &inputCluster{
+				inputs: make(map[wire.OutPoint]*pendingInput),
+			}
+			clusters[bucket] = cluster
+		}
+
+		cluster.inputs[op] = input
+		if feeRate > cluster.sweepFeeRate {
+			cluster.sweepFeeRate = feeRate
+		}
+	}
+
+	// We'll sweep the clusters with the highest fee rates first.
+	buckets := make([]lnwallet.SatPerKWeight, 0, len(clusters))
+	for bucket := range clusters {
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i] > buckets[j]
+	})
+
+	inputClusters := make([]inputCluster, 0, len(buckets))
+	for _, bucket := range buckets {
+		inputClusters = append(inputClusters, *clusters[bucket])
+	}
+
+	return inputClusters
+}
+
 // scheduleSweep starts the sweep timer to create an opportunity for more inputs
 // to be added.
 func (s *UtxoSweeper) scheduleSweep(currentHeight int32) error {
@@ -464,27 +702,22 @@ func (s *UtxoSweeper) scheduleSweep(currentHeight int32) error {
 		return nil
 	}
 
-	// Retrieve fee estimate for input filtering and final tx fee
-	// calculation.
-	satPerKW, err := s.cfg.FeeEstimator.EstimateFeePerKW(
-		s.cfg.SweepTxConfTarget,
-	)
-	if err != nil {
-		return fmt.Errorf("estimate fee: %v", err)
-	}
-
 	// Examine pending inputs and try to construct lists of inputs.
-	inputLists, err := s.getInputLists(currentHeight, satPerKW)
-	if err != nil {
-		return fmt.Errorf("get input lists: %v", err)
+	var numInputLists int
+	for _, cluster := range s.clusterBySweepFeeRate() {
+		inputLists, err := s.getInputLists(cluster, currentHeight)
+		if err != nil {
+			return fmt.Errorf("get input lists: %v", err)
+		}
+		numInputLists += len(inputLists)
 	}
 
 	log.Infof("Sweep candidates at height=%v, yield %v distinct txns",
-		currentHeight, len(inputLists))
+		currentHeight, numInputLists)
 
 	// If there are no input sets, there is nothing sweepable and we can
 	// return without starting the timer.
-	if len(inputLists) == 0 {
+	if numInputLists == 0 {
 		return nil
 	}
 
@@ -533,13 +766,13 @@ func (s *UtxoSweeper) signalAndRemove(outpoint *wire.OutPoint, result Result) {
 	delete(s.pendingInputs, *outpoint)
 }
 
-// getInputLists goes through all pending inputs and constructs sweep lists,
-// each up to the configured maximum number of inputs. Negative yield inputs are
-// skipped. Transactions with an output below the dust limit are not published.
-// Those inputs remain pending and will be bundled with future inputs if
-// possible.
-func (s *UtxoSweeper) getInputLists(currentHeight int32,
-	satPerKW lnwallet.SatPerKWeight) ([]inputSet, error) {
+// getInputLists goes through the inputs of the given cluster and constructs
+// sweep lists, each up to the configured maximum number of inputs. Negative
+// yield inputs are skipped. Transactions with an output below the dust limit
+// are not published. Those inputs remain pending and will be bundled with
+// future inputs if possible.
+func (s *UtxoSweeper) getInputLists(cluster inputCluster,
+	currentHeight int32) ([]inputSet, error) {
 
 	// Filter for inputs that need to be swept. Create two lists: all
 	// sweepable inputs and a list containing only the new, never tried
@@ -552,7 +785,7 @@ func (s *UtxoSweeper) getInputLists(currentHeight int32,
 	// consisting of only new inputs to the list, to make sure that new
 	// inputs are given a good, isolated chance of being published.
 	var newInputs, retryInputs []input.Input
-	for _, input := range s.pendingInputs {
+	for _, input := range cluster.inputs {
 		// Skip inputs that have a minimum publish height that is not
 		// yet reached.
 		if input.minPublishHeight > currentHeight {
@@ -567,6 +800,11 @@ func (s *UtxoSweeper) getInputLists(currentHeight int32,
 		}
 	}
 
+	// Retrieve the relay fee for dust limit calculation. This is queried
+	// every time, as the backend's relay fee may change while we're
+	// running.
+	relayFeePerKW := s.cfg.FeeEstimator.RelayFeePerKW()
+
 	// If there is anything to retry, combine it with the new inputs and
 	// form input sets.
 	var allSets []inputSet
@@ -574,7 +812,7 @@ func (s *UtxoSweeper) getInputLists(currentHeight int32,
 		var err error
 		allSets, err = generateInputPartitionings(
 			append(retryInputs, newInputs...),
-			s.relayFeePerKW, satPerKW,
+			relayFeePerKW, cluster.sweepFeeRate,
 			s.cfg.MaxInputsPerTx,
 		)
 		if err != nil {
@@ -585,7 +823,7 @@ func (s *UtxoSweeper) getInputLists(currentHeight int32,
 	// Create sets for just the new inputs.
 	newSets, err := generateInputPartitionings(
 		newInputs,
-		s.relayFeePerKW, satPerKW,
+		relayFeePerKW, cluster.sweepFeeRate,
 		s.cfg.MaxInputsPerTx,
 	)
 	if err != nil {
@@ -669,6 +907,7 @@ func (s *UtxoSweeper) sweep(inputs inputSet,
 
 		// Record another publish attempt.
 		pi.publishAttempts++
+		pi.lastFeeRate = satPerKW
 
 		// We don't care what the result of the publish call was. Even
 		// if it is published successfully, it can still be that it
@@ -695,6 +934,80 @@ func (s *UtxoSweeper) sweep(inputs inputSet,
 	return nil
 }
 
+// handlePendingSweepsReq handles a request to retrieve all pending inputs the
+// UtxoSweeper is attempting to sweep.
+func (s *UtxoSweeper) handlePendingSweepsReq() map[wire.OutPoint]*PendingInput {
+	pendingInputs := make(
+		map[wire.OutPoint]*PendingInput, len(s.pendingInputs),
+	)
+	for _, pendingInput := range s.pendingInputs {
+		// We copy the state of the input, as it's owned by the main
+		// event loop.
+		pi := pendingInput.input
+		nextHeight := uint32(pendingInput.minPublishHeight)
+		pendingInputs[*pi.OutPoint()] = &PendingInput{
+			OutPoint:    *pi.OutPoint(),
+			WitnessType: pi.WitnessType(),
+			Amount: btcutil.Amount(
+				pi.SignDesc().Output.Value,
+			),
+			LastFeeRate:         pendingInput.lastFeeRate,
+			BroadcastAttempts:   pendingInput.publishAttempts,
+			NextBroadcastHeight: nextHeight,
+			Params:              pendingInput.params,
+		}
+	}
+
+	return pendingInputs
+}
+
+// handleUpdateReq handles an update request by simply updating the sweep
+// parameters of the pending input. Currently, no validation is done on the new
+// fee preference to ensure it will properly create a replacement transaction.
+func (s *UtxoSweeper) handleUpdateReq(req *updateReq,
+	bestHeight int32) (chan Result, error) {
+
+	// If the UtxoSweeper is already trying to sweep this input, then we
+	// can simply just increase its fee rate. This will allow the input to
+	// be batched with others which also have a similar fee rate, creating
+	// a higher fee rate transaction that replaces the original input's
+	// sweeping transaction.
+	pendingInput, ok := s.pendingInputs[req.input]
+	if !ok {
+		return nil, ErrUnknownInput
+	}
+
+	log.Debugf("Updating sweep parameters for %v from %v to %v", req.input,
+		pendingInput.params, req.params)
+
+	pendingInput.params = req.params
+
+	// We'll reset the input's publish height to the current so that a new
+	// transaction can be created that replaces the transaction currently
+	// spending the input. We only do this for inputs that have been
+	// broadcast at least once to ensure we don't spend an input before its
+	// maturity height.
+	if pendingInput.publishAttempts > 0 {
+		pendingInput.minPublishHeight = bestHeight
+	}
+
+	// The caller is notified of the final outcome of the sweep, just like
+	// the clients that offered the input in the first place.
+	resultChan := make(chan Result, 1)
+	pendingInput.listeners = append(pendingInput.listeners, resultChan)
+
+	// If the caller requested the input to be swept right away, we'll do
+	// so without waiting for the batch window to expire. Otherwise, we'll
+	// schedule a sweep to ensure the new parameters take effect.
+	if req.params.Force {
+		s.sweepPendingInputs(bestHeight)
+	} else if err := s.scheduleSweep(bestHeight); err != nil {
+		log.Errorf("Unable to schedule sweep: %v", err)
+	}
+
+	return resultChan, nil
+}
+
 // waitForSpend registers a spend notification with the chain notifier. It
 // returns a cancel function that can be used to cancel the registration.
 func (s *UtxoSweeper) waitForSpend(outpoint wire.OutPoint,
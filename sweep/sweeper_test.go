@@ -902,3 +902,101 @@ func TestGiveUp(t *testing.T) {
 
 	ctx.finish(1)
 }
+
+// TestBumpFee asserts that bumping the fee of a pending input sweeps it right
+// away when forced, at the new fee rate and separately from the inputs whose
+// fee rates fall into a different bucket.
+func TestBumpFee(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	resultChan0, err := ctx.sweeper.SweepInput(spendableInputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	resultChan1, err := ctx.sweeper.SweepInput(spendableInputs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx.tick()
+
+	// Both inputs share the default fee rate, so they're swept together.
+	sweepTx := ctx.receiveTx()
+	if len(sweepTx.TxIn) != 2 {
+		t.Fatalf("expected 2 inputs, got %v", len(sweepTx.TxIn))
+	}
+
+	pendingInputs, err := ctx.sweeper.PendingInputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pendingInputs) != 2 {
+		t.Fatalf("expected 2 pending inputs, got %v",
+			len(pendingInputs))
+	}
+	pendingInput := pendingInputs[*spendableInputs[1].OutPoint()]
+	if pendingInput.BroadcastAttempts != 1 {
+		t.Fatalf("expected 1 broadcast attempt, got %v",
+			pendingInput.BroadcastAttempts)
+	}
+	if pendingInput.LastFeeRate != 10000 {
+		t.Fatalf("expected last fee rate of 10000 sat/kw, got %v",
+			pendingInput.LastFeeRate)
+	}
+
+	// Bumping the fee of the second input and forcing its sweep should
+	// publish a replacement spending only that input, without waiting for
+	// the batch timer.
+	params := Params{
+		Fee:   FeePreference{FeeRate: 15000},
+		Force: true,
+	}
+	bumpResultChan, err := ctx.sweeper.UpdateParams(
+		*spendableInputs[1].OutPoint(), params,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bumpTx := ctx.receiveTx()
+	bumpedInput := *spendableInputs[1].OutPoint()
+	if len(bumpTx.TxIn) != 1 ||
+		bumpTx.TxIn[0].PreviousOutPoint != bumpedInput {
+
+		t.Fatalf("expected replacement to only spend bumped input")
+	}
+	inputValue0 := spendableInputs[0].SignDesc().Output.Value
+	inputValue1 := spendableInputs[1].SignDesc().Output.Value
+	sweepFee := inputValue0 + inputValue1 - sweepTx.TxOut[0].Value
+	bumpFee := inputValue1 - bumpTx.TxOut[0].Value
+	if bumpFee <= sweepFee {
+		t.Fatalf("expected replacement fee %v to exceed original "+
+			"fee %v", bumpFee, sweepFee)
+	}
+
+	pendingInputs, err = ctx.sweeper.PendingInputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pendingInput = pendingInputs[*spendableInputs[1].OutPoint()]
+	if pendingInput.Params != params {
+		t.Fatalf("expected params %v, got %v", params,
+			pendingInput.Params)
+	}
+
+	// Updating an input that isn't being swept should fail.
+	_, err = ctx.sweeper.UpdateParams(
+		*spendableInputs[2].OutPoint(), params,
+	)
+	if err != ErrUnknownInput {
+		t.Fatalf("expected ErrUnknownInput, got %v", err)
+	}
+
+	ctx.backend.mine()
+
+	ctx.expectResult(resultChan0, nil)
+	ctx.expectResult(resultChan1, nil)
+	ctx.expectResult(bumpResultChan, nil)
+
+	ctx.finish(1)
+}
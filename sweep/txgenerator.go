@@ -264,6 +264,15 @@ func getInputWitnessSizeUpperBound(inp input.Input) (int, bool, error) {
 	case input.CommitmentNoDelay:
 		return input.P2WKHWitnessSize, false, nil
 
+	// Outputs on a remote commitment transaction of a channel using anchor
+	// outputs that pay to us once the commitment has confirmed.
+	case input.CommitmentToRemoteConfirmed:
+		return input.ToRemoteConfirmedWitnessSize, false, nil
+
+	// Our anchor output on a commitment transaction.
+	case input.CommitmentAnchor:
+		return input.AnchorWitnessSize, false, nil
+
 	// Outputs on a past commitment transaction that pay directly
 	// to us.
 	case input.CommitmentTimeLock:
@@ -291,6 +300,16 @@ func getInputWitnessSizeUpperBound(inp input.Input) (int, bool, error) {
 	case input.HtlcAcceptedRemoteSuccess:
 		return input.OfferedHtlcSuccessWitnessSize, false, nil
 
+	// The on-chain HTLC of a submarine swap, that can be claimed with the
+	// preimage.
+	case input.SwapHTLCSuccess:
+		return input.SwapHTLCSuccessWitnessSize, false, nil
+
+	// The on-chain HTLC of a submarine swap, that has had its absolute
+	// timelock expire.
+	case input.SwapHTLCTimeout:
+		return input.SwapHTLCTimeoutWitnessSize, false, nil
+
 	// A nested P2SH input that has a p2wkh witness script. We'll mark this
 	// as nested P2SH so the caller can estimate the weight properly
 	// including the sigScript.
@@ -348,10 +367,11 @@ func getWeightEstimate(inputs []input.Input) ([]input.Input, int64, int, int) {
 
 		switch inp.WitnessType() {
 		case input.CommitmentTimeLock,
+			input.CommitmentToRemoteConfirmed,
 			input.HtlcOfferedTimeoutSecondLevel,
 			input.HtlcAcceptedSuccessSecondLevel:
 			csvCount++
-		case input.HtlcOfferedRemoteTimeout:
+		case input.HtlcOfferedRemoteTimeout, input.SwapHTLCTimeout:
 			cltvCount++
 		}
 		sweepInputs = append(sweepInputs, inp)
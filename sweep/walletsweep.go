@@ -30,6 +30,14 @@ type FeePreference struct {
 	FeeRate lnwallet.SatPerKWeight
 }
 
+// String returns a human readable interpretation of the fee preference.
+func (p FeePreference) String() string {
+	if p.ConfTarget != 0 {
+		return fmt.Sprintf("%v blocks", p.ConfTarget)
+	}
+	return fmt.Sprintf("%v sat/kw", int64(p.FeeRate))
+}
+
 // DetermineFeePerKw will determine the fee in sat/kw that should be paid given
 // an estimator, a confirmation target, and a manual value for sat/byte. A
 // value is chosen based on the two free parameters as one, or both of them can
@@ -44,11 +44,17 @@ const (
 )
 
 // Size returns the size of the encoded-and-encrypted blob in bytes.
+//   version:               1 byte, only if versioned
 //   nonce:                24 bytes
 //   enciphered plaintext:  n bytes
 //   MAC:                  16 bytes
 func Size(blobType Type) int {
-	return NonceSize + PlaintextSize(blobType) + CiphertextExpansion
+	size := NonceSize + PlaintextSize(blobType) + CiphertextExpansion
+	if blobType.Has(FlagVersioned) {
+		size += VersionSize
+	}
+
+	return size
 }
 
 // PlaintextSize returns the size of the encoded-but-unencrypted blob in bytes.
@@ -218,11 +224,16 @@ func (b *JusticeKit) CommitToRemoteWitnessStack() ([][]byte, error) {
 }
 
 // Encrypt encodes the blob of justice using encoding version, and then
-// creates a ciphertext using chacha20poly1305 under the chosen (nonce, key)
-// pair.
+// creates a ciphertext using xchacha20poly1305 under the given key and a
+// random nonce. If the blob type is versioned, the ciphertext is prefixed
+// with LatestVersion, which is authenticated as associated data.
 //
-// NOTE: It is the caller's responsibility to ensure that this method is only
-// called once for a given (nonce, key) pair.
+// The 24-byte nonce is drawn at random for every blob rather than derived
+// from a counter, as neither the client nor the tower keep state across
+// blobs encrypted under a key. Since keys are unique to a breach txid, and for
+// versioned blobs also to a session, a key only ever encrypts a single blob
+// unless the same state is backed up twice, and the extended nonce makes a
+// collision negligible even then.
 func (b *JusticeKit) Encrypt(key []byte, blobType Type) ([]byte, error) {
 	// Fail if the nonce is not 32-bytes.
 	if len(key) != KeySize {
@@ -243,28 +254,55 @@ func (b *JusticeKit) Encrypt(key []byte, blobType Type) ([]byte, error) {
 		return nil, err
 	}
 
-	// Allocate the ciphertext, which will contain the nonce, encrypted
-	// plaintext and MAC.
+	// Allocate the ciphertext, which will contain the version if the blob
+	// is versioned, the nonce, encrypted plaintext and MAC.
 	plaintext := ptxtBuf.Bytes()
 	ciphertext := make([]byte, Size(blobType))
 
-	// Generate a random  24-byte nonce in the ciphertext's prefix.
-	nonce := ciphertext[:NonceSize]
+	// The version prefix of a versioned blob is passed as associated
+	// data, such that it can't be altered to have the blob decrypted
+	// under a different scheme.
+	var header []byte
+	if blobType.Has(FlagVersioned) {
+		header = ciphertext[:VersionSize]
+		header[0] = byte(LatestVersion)
+	}
+
+	// Generate a random 24-byte nonce following the header.
+	nonce := ciphertext[len(header) : len(header)+NonceSize]
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, err
 	}
 
 	// Finally, encrypt the plaintext using the given nonce, storing the
 	// result in the ciphertext buffer.
-	cipher.Seal(ciphertext[NonceSize:NonceSize], nonce, plaintext, nil)
+	sealed := ciphertext[len(header)+NonceSize : len(header)+NonceSize]
+	cipher.Seal(sealed, nonce, plaintext, header)
 
 	return ciphertext, nil
 }
 
 // Decrypt unenciphers a blob of justice by decrypting the ciphertext using
 // chacha20poly1305 with the chosen (nonce, key) pair. The internal plaintext is
-// then deserialized using the given encoding version.
+// then deserialized using the given encoding version. If the blob type is
+// versioned, the ciphertext is decrypted according to its version prefix.
 func Decrypt(key, ciphertext []byte, blobType Type) (*JusticeKit, error) {
+	// Strip the version prefix of versioned blobs, failing if we don't
+	// know how to decrypt blobs of the version.
+	var header []byte
+	if blobType.Has(FlagVersioned) {
+		if len(ciphertext) < VersionSize {
+			return nil, ErrCiphertextTooSmall
+		}
+
+		header = ciphertext[:VersionSize]
+		if Version(header[0]) != VersionV1 {
+			return nil, ErrUnknownBlobVersion
+		}
+
+		ciphertext = ciphertext[VersionSize:]
+	}
+
 	switch {
 
 	// Fail if the blob's overall length is less than required for the nonce
@@ -291,7 +329,9 @@ func Decrypt(key, ciphertext []byte, blobType Type) (*JusticeKit, error) {
 	// Decrypt the ciphertext, placing the resulting plaintext in our
 	// plaintext buffer.
 	nonce := ciphertext[:NonceSize]
-	_, err = cipher.Open(plaintext[:0], nonce, ciphertext[NonceSize:], nil)
+	_, err = cipher.Open(
+		plaintext[:0], nonce, ciphertext[NonceSize:], header,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +60,10 @@ var rewardAndCommitType = blob.TypeFromFlags(
 	blob.FlagReward, blob.FlagCommitOutputs,
 )
 
+var versionedCommitType = blob.TypeFromFlags(
+	blob.FlagVersioned, blob.FlagCommitOutputs,
+)
+
 var descriptorTests = []descriptorTest{
 	{
 		name:             "to-local only",
@@ -137,6 +141,19 @@ var descriptorTests = []descriptorTest{
 		commitToLocalSig: makeSig(1),
 		encErr:           blob.ErrSweepAddressToLong,
 	},
+	{
+		name:                 "versioned to-local and p2wkh",
+		encVersion:           versionedCommitType,
+		decVersion:           versionedCommitType,
+		sweepAddr:            makeAddr(22),
+		revPubKey:            makePubKey(0),
+		delayPubKey:          makePubKey(1),
+		csvDelay:             144,
+		commitToLocalSig:     makeSig(1),
+		hasCommitToRemote:    true,
+		commitToRemotePubKey: makePubKey(2),
+		commitToRemoteSig:    makeSig(2),
+	},
 }
 
 // TestBlobJusticeKitEncryptDecrypt asserts that encrypting and decrypting a
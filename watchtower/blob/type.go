@@ -19,6 +19,20 @@ const (
 	// FlagCommitOutputs signals that the blob contains the information
 	// required to sweep commitment outputs.
 	FlagCommitOutputs
+
+	// FlagAnchorChannel signals that the breached commitment belongs to a
+	// channel using anchor outputs. The to-remote output of such a
+	// commitment is a p2wsh output that can only be spent after a
+	// confirmation, and the justice transaction may need to be
+	// accelerated using CPFP.
+	FlagAnchorChannel
+
+	// FlagVersioned signals that the blobs are prefixed with the Version
+	// of their encryption scheme, and encrypted under a key derived from
+	// both the breach txid and the secret shared by the client's session
+	// key and the tower's identity key. Without the flag, blobs are
+	// encrypted under the breach txid alone, with no version prefix.
+	FlagVersioned
 )
 
 // Type returns a Type consisting solely of this flag enabled.
@@ -33,6 +47,10 @@ func (f Flag) String() string {
 		return "FlagReward"
 	case FlagCommitOutputs:
 		return "FlagCommitOutputs"
+	case FlagAnchorChannel:
+		return "FlagAnchorChannel"
+	case FlagVersioned:
+		return "FlagVersioned"
 	default:
 		return "FlagUnknown"
 	}
@@ -68,6 +86,8 @@ func TypeFromFlags(flags ...Flag) Type {
 var knownFlags = map[Flag]struct{}{
 	FlagReward:        {},
 	FlagCommitOutputs: {},
+	FlagAnchorChannel: {},
+	FlagVersioned:     {},
 }
 
 // String returns a human readable description of a Type.
@@ -114,8 +134,16 @@ func (t Type) String() string {
 // supportedTypes is the set of all configurations known to be supported by the
 // package.
 var supportedTypes = map[Type]struct{}{
-	FlagCommitOutputs.Type():                {},
-	(FlagCommitOutputs | FlagReward).Type(): {},
+	FlagCommitOutputs.Type():                                    {},
+	(FlagCommitOutputs | FlagReward).Type():                     {},
+	(FlagCommitOutputs | FlagAnchorChannel).Type():              {},
+	(FlagCommitOutputs | FlagReward | FlagAnchorChannel).Type(): {},
+
+	(FlagCommitOutputs | FlagVersioned).Type():                     {},
+	(FlagCommitOutputs | FlagReward | FlagVersioned).Type():        {},
+	(FlagCommitOutputs | FlagAnchorChannel | FlagVersioned).Type(): {},
+	(FlagCommitOutputs | FlagReward | FlagAnchorChannel |
+		FlagVersioned).Type(): {},
 }
 
 // IsSupportedType returns true if the given type is supported by the package.
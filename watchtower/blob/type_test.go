@@ -16,19 +16,37 @@ type typeStringTest struct {
 
 var typeStringTests = []typeStringTest{
 	{
-		name:   "commit no-reward",
-		typ:    blob.TypeDefault,
-		expStr: "[FlagCommitOutputs|No-FlagReward]",
+		name: "commit no-reward",
+		typ:  blob.TypeDefault,
+		expStr: "[No-FlagVersioned|No-FlagAnchorChannel|" +
+			"FlagCommitOutputs|No-FlagReward]",
 	},
 	{
-		name:   "commit reward",
-		typ:    (blob.FlagCommitOutputs | blob.FlagReward).Type(),
-		expStr: "[FlagCommitOutputs|FlagReward]",
+		name: "commit reward",
+		typ:  (blob.FlagCommitOutputs | blob.FlagReward).Type(),
+		expStr: "[No-FlagVersioned|No-FlagAnchorChannel|" +
+			"FlagCommitOutputs|FlagReward]",
 	},
 	{
-		name:   "unknown flag",
-		typ:    unknownFlag.Type(),
-		expStr: "0000000000010000[No-FlagCommitOutputs|No-FlagReward]",
+		name: "anchor commit reward",
+		typ: (blob.FlagAnchorChannel | blob.FlagCommitOutputs |
+			blob.FlagReward).Type(),
+		expStr: "[No-FlagVersioned|FlagAnchorChannel|" +
+			"FlagCommitOutputs|FlagReward]",
+	},
+	{
+		name: "versioned commit reward",
+		typ: (blob.FlagVersioned | blob.FlagCommitOutputs |
+			blob.FlagReward).Type(),
+		expStr: "[FlagVersioned|No-FlagAnchorChannel|" +
+			"FlagCommitOutputs|FlagReward]",
+	},
+	{
+		name: "unknown flag",
+		typ:  unknownFlag.Type(),
+		expStr: "0000000000010000[No-FlagVersioned|" +
+			"No-FlagAnchorChannel|No-FlagCommitOutputs|" +
+			"No-FlagReward]",
 	},
 }
 
@@ -0,0 +1,82 @@
+package blob
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Version identifies the encryption scheme of a versioned blob, i.e. one whose
+// type has FlagVersioned set: the cipher and nonce used to encrypt it, and how
+// its key is derived. New versions allow the scheme to be upgraded, while the
+// blobs already stored by towers remain decryptable through their prefix.
+type Version uint8
+
+const (
+	// VersionSize is the length of the version prefix of a versioned blob.
+	VersionSize = 1
+
+	// VersionV1 encrypts the blob using xchacha20poly1305 with a random
+	// 24-byte nonce, under the key returned by DeriveKey. The version
+	// prefix is authenticated as associated data.
+	//
+	// blob version 1 encoding:
+	//    version:               1 byte
+	//    nonce:                24 bytes
+	//    enciphered plaintext:  n bytes
+	//    MAC:                  16 bytes
+	VersionV1 Version = 1
+
+	// LatestVersion is the version used to encrypt new versioned blobs.
+	LatestVersion = VersionV1
+)
+
+var (
+	// ErrUnknownBlobVersion signals that a versioned blob is prefixed with
+	// a version we don't know how to decrypt.
+	ErrUnknownBlobVersion = errors.New("unknown blob version")
+
+	// keyInfoV1 is the HKDF info string binding the keys of version 1
+	// blobs to their scheme.
+	keyInfoV1 = []byte("lnwt-blob-v1")
+)
+
+// SessionSecret computes the secret shared by a watchtower client's session
+// key and the tower's identity key, as the sha256 of their ECDH point. The
+// client calls it with the private session key, which it derives from its root
+// key through the keychain's tower session family, and the tower's public key.
+// The tower calls it with its private identity key and the session id, i.e.
+// the public session key.
+func SessionSecret(priv *btcec.PrivateKey, pub *btcec.PublicKey) [32]byte {
+	s := &btcec.PublicKey{}
+	x, y := btcec.S256().ScalarMult(pub.X, pub.Y, priv.D.Bytes())
+	s.X = x
+	s.Y = y
+
+	return sha256.Sum256(s.SerializeCompressed())
+}
+
+// DeriveKey returns the key under which blobs of the given type are encrypted
+// for the breach transaction with the given txid. Unversioned blobs are
+// encrypted under the txid itself. Versioned blobs are encrypted under
+// HKDF-SHA256 of the session secret, salted with the txid, such that their
+// key is unique to both the breach and the session, and can only be derived
+// by the client and the tower it negotiated the session with.
+func DeriveKey(blobType Type, breachTxID [32]byte,
+	sessionSecret [32]byte) ([]byte, error) {
+
+	if !blobType.Has(FlagVersioned) {
+		return breachTxID[:], nil
+	}
+
+	key := make([]byte, KeySize)
+	h := hkdf.New(sha256.New, sessionSecret[:], breachTxID[:], keyInfoV1)
+	if _, err := io.ReadFull(h, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
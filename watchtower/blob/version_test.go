@@ -0,0 +1,103 @@
+package blob_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/lnd/watchtower/blob"
+)
+
+// TestDeriveKey asserts that unversioned blobs are keyed by the breach txid,
+// while the keys of versioned blobs are bound to the session secret shared by
+// the client and the tower.
+func TestDeriveKey(t *testing.T) {
+	clientPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate client key: %v", err)
+	}
+	towerPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate tower key: %v", err)
+	}
+
+	// Both ends of the session should compute the same secret.
+	clientSecret := blob.SessionSecret(clientPriv, towerPriv.PubKey())
+	towerSecret := blob.SessionSecret(towerPriv, clientPriv.PubKey())
+	if clientSecret != towerSecret {
+		t.Fatalf("session secrets don't match")
+	}
+
+	breachTxID := [32]byte{0x01}
+
+	key, err := blob.DeriveKey(blob.TypeDefault, breachTxID, clientSecret)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	if !bytes.Equal(key, breachTxID[:]) {
+		t.Fatalf("unversioned key should be the breach txid")
+	}
+
+	key1, err := blob.DeriveKey(
+		versionedCommitType, breachTxID, clientSecret,
+	)
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	if len(key1) != blob.KeySize || bytes.Equal(key1, breachTxID[:]) {
+		t.Fatalf("versioned key should be derived from session secret")
+	}
+
+	// A different session should derive a different key for the same
+	// breach.
+	key2, err := blob.DeriveKey(versionedCommitType, breachTxID, [32]byte{})
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+	if bytes.Equal(key1, key2) {
+		t.Fatalf("sessions should derive distinct keys")
+	}
+}
+
+// TestVersionedBlobTampering asserts that the version prefix of a versioned
+// blob is authenticated, and that blobs of unknown versions are rejected.
+func TestVersionedBlobTampering(t *testing.T) {
+	kit := &blob.JusticeKit{
+		SweepAddress:     makeAddr(22),
+		RevocationPubKey: makePubKey(0),
+		LocalDelayPubKey: makePubKey(1),
+		CSVDelay:         144,
+		CommitToLocalSig: makeSig(1),
+	}
+
+	key, err := blob.DeriveKey(versionedCommitType, [32]byte{}, [32]byte{})
+	if err != nil {
+		t.Fatalf("unable to derive key: %v", err)
+	}
+
+	ctxt, err := kit.Encrypt(key, versionedCommitType)
+	if err != nil {
+		t.Fatalf("unable to encrypt blob: %v", err)
+	}
+	if blob.Version(ctxt[0]) != blob.LatestVersion {
+		t.Fatalf("expected blob to be prefixed with version %d, got %d",
+			blob.LatestVersion, ctxt[0])
+	}
+
+	// A blob prefixed with an unknown version should be rejected before
+	// attempting to decrypt it.
+	unknown := append([]byte(nil), ctxt...)
+	unknown[0] = byte(blob.LatestVersion + 1)
+	_, err = blob.Decrypt(key, unknown, versionedCommitType)
+	if err != blob.ErrUnknownBlobVersion {
+		t.Fatalf("expected ErrUnknownBlobVersion, got: %v", err)
+	}
+
+	// Decrypting the blob without its version prefix should fail, since
+	// the prefix is authenticated along with the ciphertext.
+	unversionedType := blob.TypeFromFlags(blob.FlagCommitOutputs)
+	_, err = blob.Decrypt(key, ctxt[blob.VersionSize:], unversionedType)
+	if err == nil {
+		t.Fatalf("expected blob without version prefix to fail")
+	}
+}
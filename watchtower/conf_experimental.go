@@ -16,6 +16,14 @@ type Conf struct {
 	ReadTimeout time.Duration `long:"readtimeout" description:"Duration the watchtower server will wait for messages to be received before hanging up on clients"`
 
 	WriteTimeout time.Duration `long:"writetimeout" description:"Duration the watchtower server will wait for messages to be written before hanging up on client connections"`
+
+	MaxClientBytes uint64 `long:"maxclientbytes" description:"The maximum number of bytes the state updates of a single client session may occupy, 0 means unlimited"`
+
+	MaxTotalBytes uint64 `long:"maxtotalbytes" description:"The maximum number of bytes the state updates of all clients may occupy combined, 0 means unlimited"`
+
+	EvictionDepth uint32 `long:"evictiondepth" description:"The number of blocks that must be mined on top of a breach before its state updates are deleted"`
+
+	MetricsListen string `long:"metricslisten" description:"The interface/port on which Prometheus metrics are served, metrics are disabled if empty (requires the monitoring build tag)"`
 }
 
 // Apply completes the passed Config struct by applying any parsed Conf options.
@@ -61,5 +69,37 @@ func (c *Conf) Apply(cfg *Config) (*Config, error) {
 		cfg.WriteTimeout = c.WriteTimeout
 	}
 
+	// If the Config has no storage quota, we will use the parsed Conf
+	// values.
+	if cfg.Quota.MaxClientBytes == 0 {
+		cfg.Quota.MaxClientBytes = c.MaxClientBytes
+	}
+	if cfg.Quota.MaxTotalBytes == 0 {
+		cfg.Quota.MaxTotalBytes = c.MaxTotalBytes
+	}
+
+	// If the Config has no eviction depth, we will use the parsed Conf
+	// value.
+	if cfg.EvictionDepth == 0 && c.EvictionDepth != 0 {
+		cfg.EvictionDepth = c.EvictionDepth
+	}
+
+	// If the Config has no metrics listening address, we will normalize
+	// the parsed Conf value, if any.
+	if cfg.MetricsListenAddr == nil && c.MetricsListen != "" {
+		if cfg.Net == nil {
+			return nil, ErrNoNetwork
+		}
+
+		addrs, err := lncfg.NormalizeAddresses(
+			[]string{c.MetricsListen}, DefaultMetricsPortStr,
+			cfg.Net.ResolveTCPAddr,
+		)
+		if err != nil {
+			return nil, err
+		}
+		cfg.MetricsListenAddr = addrs[0]
+	}
+
 	return cfg, nil
 }
@@ -9,8 +9,11 @@ import (
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/tor"
 	"github.com/litecoinfinance/lnd/watchtower/lookout"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
+	"github.com/litecoinfinance/lnd/watchtower/wtserver"
 )
 
 const (
@@ -25,11 +28,22 @@ const (
 	// DefaultWriteTimeout is the default timeout after which the tower will
 	// hang up on a client if it is unable to send a message.
 	DefaultWriteTimeout = 15 * time.Second
+
+	// DefaultEvictionDepth is the default number of blocks that must be
+	// mined on top of a breach before the tower deletes its state updates.
+	DefaultEvictionDepth = 1008
+
+	// DefaultMetricsPort is the default port on which the tower's metrics
+	// are served, if enabled.
+	DefaultMetricsPort = 9912
 )
 
 var (
 	// DefaultPeerPortStr is the default server port as a string.
 	DefaultPeerPortStr = fmt.Sprintf(":%d", DefaultPeerPort)
+
+	// DefaultMetricsPortStr is the default metrics port as a string.
+	DefaultMetricsPortStr = fmt.Sprintf(":%d", DefaultMetricsPort)
 )
 
 // Config defines the resources and parameters used to configure a Watchtower.
@@ -73,6 +87,26 @@ type Config struct {
 	// have stronger guarantees wrt. returned error types.
 	PublishTx func(*wire.MsgTx) error
 
+	// BumpFee, if non-nil, is used to CPFP justice transactions through
+	// the tower's reward output, funding the child transaction from the
+	// tower's wallet if needed. Repeated calls for the same justice
+	// transaction carry increasing fee rates, and must replace the child
+	// transaction previously published.
+	BumpFee func(justiceTx *wire.MsgTx, rewardIndex uint32,
+		fee btcutil.Amount, feeRate lnwallet.SatPerKWeight) error
+
+	// ConfRegistrar supports the ability to register for the
+	// confirmation of justice transactions. If nil, the fee rate of
+	// justice transactions isn't escalated while they remain
+	// unconfirmed.
+	ConfRegistrar lookout.ConfRegistrar
+
+	// FeeBump determines how the fee rate of unconfirmed justice
+	// transactions is escalated. Zero values are replaced by
+	// lookout.DefaultFeeBumpInterval and lookout.DefaultFeeBumpPercent
+	// respectively.
+	FeeBump lookout.FeeBumpPolicy
+
 	// ListenAddrs specifies which address to which clients may connect.
 	ListenAddrs []net.Addr
 
@@ -84,4 +118,31 @@ type Config struct {
 	// message from the other end, if the connection has stopped buffering
 	// the server's replies.
 	WriteTimeout time.Duration
+
+	// MinSweepFeeRate returns the minimum fee rate that justice
+	// transactions must currently pay in order to be relayed by the chain
+	// backend, e.g. lnwallet.MinMempoolFeePerKW of the active fee
+	// estimator. If nil, the sweep fee rate of new sessions isn't
+	// validated.
+	MinSweepFeeRate func() lnwallet.SatPerKWeight
+
+	// PolicyBounds are the initial bounds restricting the policies of
+	// sessions negotiated with clients. They can be replaced at runtime
+	// via Standalone's SetPolicyBounds.
+	PolicyBounds wtserver.PolicyBounds
+
+	// Quota bounds the space the state updates of individual clients, as
+	// well as all clients combined, may occupy within the DB. A zero
+	// limit leaves the respective storage unbounded.
+	Quota wtdb.Quota
+
+	// EvictionDepth is the number of blocks that must be mined on top of
+	// a breach before its state updates are deleted from the DB. If zero,
+	// DefaultEvictionDepth is used.
+	EvictionDepth uint32
+
+	// MetricsListenAddr, if non-nil, is the address on which the tower
+	// serves its metrics over HTTP for Prometheus to scrape. This requires
+	// lnd to be built with the monitoring tag.
+	MetricsListenAddr net.Addr
 }
@@ -15,4 +15,9 @@ var (
 	// ErrNoNetwork signals that no tor.Net is provided in the Config, which
 	// prevents resolution of listening addresses.
 	ErrNoNetwork = errors.New("no network specified, must be tor or clearnet")
+
+	// ErrMetricsUnsupported signals that a metrics listening address was
+	// configured in a build without the monitoring tag.
+	ErrMetricsUnsupported = errors.New("watchtower metrics require " +
+		"building with the monitoring tag")
 )
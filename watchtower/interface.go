@@ -1,7 +1,10 @@
 package watchtower
 
 import (
+	"time"
+
 	"github.com/litecoinfinance/lnd/watchtower/lookout"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
 	"github.com/litecoinfinance/lnd/watchtower/wtserver"
 )
 
@@ -11,4 +14,42 @@ import (
 type DB interface {
 	lookout.DB
 	wtserver.DB
+	AdminDB
+}
+
+// AdminDB abstracts the persistent functionality used by the tower's operator
+// to inspect and manage the state accumulated on behalf of clients.
+type AdminDB interface {
+	// ListSessions returns a summary of every session negotiated with a
+	// client.
+	ListSessions() ([]*wtdb.SessionSummary, error)
+
+	// Stats returns a summary of the resources occupied within the
+	// database.
+	Stats() (*wtdb.TowerStats, error)
+
+	// PruneInactiveSessions deletes all sessions that haven't been created
+	// or updated by their client since the given cutoff, along with their
+	// state updates, returning the ids of the deleted sessions.
+	PruneInactiveSessions(time.Time) ([]wtdb.SessionID, error)
+
+	// BlacklistClient prevents the client with the given session id from
+	// connecting to the tower.
+	BlacklistClient(wtdb.SessionID) error
+
+	// UnblacklistClient allows a previously blacklisted client to connect
+	// to the tower once again.
+	UnblacklistClient(wtdb.SessionID) error
+
+	// ListBlacklistedClients returns the session ids of all blacklisted
+	// clients.
+	ListBlacklistedClients() ([]wtdb.SessionID, error)
+
+	// SetQuota sets the storage quota enforced when inserting new state
+	// updates.
+	SetQuota(wtdb.Quota)
+
+	// Quota returns the storage quota enforced when inserting new state
+	// updates.
+	Quota() wtdb.Quota
 }
@@ -0,0 +1,164 @@
+package lookout
+
+import (
+	"github.com/litecoinfinance/btcd/blockchain"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwallet"
+)
+
+const (
+	// DefaultFeeBumpInterval is the default number of blocks a justice
+	// transaction may remain unconfirmed before its fee rate is escalated.
+	DefaultFeeBumpInterval = 6
+
+	// DefaultFeeBumpPercent is the default percentage by which the fee
+	// rate of an unconfirmed justice transaction is raised at each
+	// escalation.
+	DefaultFeeBumpPercent = 25
+)
+
+// FeeBumpPolicy determines how aggressively the tower escalates the fee rate
+// of justice transactions that fail to confirm, e.g. because the sweep fee rate
+// committed to by the client is no longer sufficient.
+type FeeBumpPolicy struct {
+	// Interval is the number of blocks a justice transaction may remain
+	// unconfirmed before its fee rate is escalated.
+	Interval uint32
+
+	// IncreasePercent is the percentage by which the fee rate is raised at
+	// each escalation.
+	IncreasePercent uint32
+
+	// MaxFeeRate is the highest fee rate the tower is willing to pay to
+	// confirm a justice transaction. A zero value leaves the fee rate
+	// unbounded, though escalation is still limited by the value of the
+	// tower's reward output and wallet.
+	MaxFeeRate lnwallet.SatPerKWeight
+}
+
+// nextFeeRate returns the fee rate following the given one, and false if the
+// maximum fee rate has already been reached.
+func (p *FeeBumpPolicy) nextFeeRate(
+	feeRate lnwallet.SatPerKWeight) (lnwallet.SatPerKWeight, bool) {
+
+	if p.MaxFeeRate != 0 && feeRate >= p.MaxFeeRate {
+		return feeRate, false
+	}
+
+	// Each escalation must raise the fee rate by at least one unit, such
+	// that the replacement is able to supersede its predecessor.
+	next := feeRate + feeRate*lnwallet.SatPerKWeight(p.IncreasePercent)/100
+	if next <= feeRate {
+		next = feeRate + 1
+	}
+
+	if p.MaxFeeRate != 0 && next > p.MaxFeeRate {
+		next = p.MaxFeeRate
+	}
+
+	return next, true
+}
+
+// justiceFeeRate returns the fee rate paid by the given justice transaction,
+// given the absolute fee it pays.
+func justiceFeeRate(justiceTxn *wire.MsgTx,
+	fee btcutil.Amount) lnwallet.SatPerKWeight {
+
+	weight := blockchain.GetTransactionWeight(btcutil.NewTx(justiceTxn))
+	if weight == 0 {
+		return 0
+	}
+
+	return lnwallet.SatPerKWeight(fee * 1000 / btcutil.Amount(weight))
+}
+
+// escalateFee waits for the given justice transaction to confirm, bumping its
+// fee rate using the tower's reward output whenever it remains unconfirmed for
+// the policy's interval. The feeRate is the fee rate at which the justice
+// transaction is currently being mined. It returns once the transaction
+// confirms, or the quit channel is closed.
+func (p *BreachPunisher) escalateFee(desc *JusticeDescriptor,
+	justiceTxn *wire.MsgTx, rewardIndex uint32, fee btcutil.Amount,
+	feeRate lnwallet.SatPerKWeight, quit <-chan struct{}) error {
+
+	justiceTxID := justiceTxn.TxHash()
+	confNtfn, err := p.cfg.ConfRegistrar.RegisterConfirmationsNtfn(
+		&justiceTxID, justiceTxn.TxOut[0].PkScript, 1,
+		desc.BreachHeight,
+	)
+	if err != nil {
+		return err
+	}
+	defer confNtfn.Cancel()
+
+	blockNtfn, err := p.cfg.EpochRegistrar.RegisterBlockEpochNtfn(nil)
+	if err != nil {
+		return err
+	}
+	defer blockNtfn.Cancel()
+
+	// The height at which we last escalated the fee rate is only known
+	// once we've received the first block.
+	var lastBumpHeight int32
+	for {
+		select {
+		case _, ok := <-confNtfn.Confirmed:
+			if !ok {
+				return ErrNotifierExiting
+			}
+
+			p.justiceConfirmed(desc, justiceTxn)
+
+			return nil
+
+		case epoch, ok := <-blockNtfn.Epochs:
+			if !ok {
+				return ErrNotifierExiting
+			}
+
+			if lastBumpHeight == 0 {
+				lastBumpHeight = epoch.Height
+				continue
+			}
+
+			// Blocks at or below the height of our last bump, as
+			// delivered during reorgs, don't count towards the
+			// interval.
+			if epoch.Height <= lastBumpHeight {
+				continue
+			}
+
+			blocksWaited := uint32(epoch.Height - lastBumpHeight)
+			if blocksWaited < p.cfg.FeeBump.Interval {
+				continue
+			}
+
+			if p.cfg.FeeBump.MaxFeeRate != 0 &&
+				feeRate >= p.cfg.FeeBump.MaxFeeRate {
+
+				log.Warnf("Justice txn=%s for client=%s "+
+					"unconfirmed at max fee rate %v",
+					justiceTxID, desc.SessionInfo.ID,
+					feeRate)
+
+				lastBumpHeight = epoch.Height
+				continue
+			}
+
+			log.Infof("Justice txn=%s for client=%s unconfirmed "+
+				"after %d blocks", justiceTxID,
+				desc.SessionInfo.ID, blocksWaited)
+
+			// Regardless of whether the bump succeeds, we'll wait
+			// another interval before trying again.
+			lastBumpHeight = epoch.Height
+			feeRate = p.bumpFee(
+				desc, justiceTxn, rewardIndex, fee, feeRate,
+			)
+
+		case <-quit:
+			return nil
+		}
+	}
+}
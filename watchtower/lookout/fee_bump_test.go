@@ -0,0 +1,224 @@
+package lookout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/chainntnfs"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
+)
+
+// mockChainNotifier delivers confirmations and blocks to the punisher over
+// channels controlled by the test.
+type mockChainNotifier struct {
+	confs  chan *chainntnfs.TxConfirmation
+	epochs chan *chainntnfs.BlockEpoch
+}
+
+func (m *mockChainNotifier) RegisterConfirmationsNtfn(*chainhash.Hash, []byte,
+	uint32, uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	return &chainntnfs.ConfirmationEvent{
+		Confirmed: m.confs,
+		Cancel:    func() {},
+	}, nil
+}
+
+func (m *mockChainNotifier) RegisterBlockEpochNtfn(*chainntnfs.BlockEpoch) (
+	*chainntnfs.BlockEpochEvent, error) {
+
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: m.epochs,
+		Cancel: func() {},
+	}, nil
+}
+
+// TestFeeBumpPolicyNextFeeRate asserts that each escalation raises the fee rate
+// by the policy's percentage, by at least one unit, and never beyond the
+// policy's maximum.
+func TestFeeBumpPolicyNextFeeRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  FeeBumpPolicy
+		feeRate lnwallet.SatPerKWeight
+		expRate lnwallet.SatPerKWeight
+		expOk   bool
+	}{
+		{
+			name:    "percent increase",
+			policy:  FeeBumpPolicy{IncreasePercent: 25},
+			feeRate: 1000,
+			expRate: 1250,
+			expOk:   true,
+		},
+		{
+			name:    "minimum increase",
+			policy:  FeeBumpPolicy{IncreasePercent: 1},
+			feeRate: 50,
+			expRate: 51,
+			expOk:   true,
+		},
+		{
+			name: "capped at max",
+			policy: FeeBumpPolicy{
+				IncreasePercent: 50,
+				MaxFeeRate:      1200,
+			},
+			feeRate: 1000,
+			expRate: 1200,
+			expOk:   true,
+		},
+		{
+			name: "max reached",
+			policy: FeeBumpPolicy{
+				IncreasePercent: 50,
+				MaxFeeRate:      1200,
+			},
+			feeRate: 1200,
+			expRate: 1200,
+			expOk:   false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rate, ok := test.policy.nextFeeRate(test.feeRate)
+			if ok != test.expOk {
+				t.Fatalf("expected ok=%v, got %v",
+					test.expOk, ok)
+			}
+			if rate != test.expRate {
+				t.Fatalf("expected fee rate %v, got %v",
+					test.expRate, rate)
+			}
+		})
+	}
+}
+
+// TestBreachPunisherEscalateFee asserts that the punisher escalates the fee
+// rate of a justice transaction once per interval for as long as it remains
+// unconfirmed, and stops once it confirms.
+func TestBreachPunisherEscalateFee(t *testing.T) {
+	t.Parallel()
+
+	const interval = 3
+
+	notifier := &mockChainNotifier{
+		confs:  make(chan *chainntnfs.TxConfirmation),
+		epochs: make(chan *chainntnfs.BlockEpoch),
+	}
+
+	bumps := make(chan lnwallet.SatPerKWeight, 1)
+	punisher := NewBreachPunisher(&PunisherConfig{
+		BumpFee: func(_ *wire.MsgTx, _ uint32, _ btcutil.Amount,
+			feeRate lnwallet.SatPerKWeight) error {
+
+			bumps <- feeRate
+			return nil
+		},
+		ConfRegistrar:  notifier,
+		EpochRegistrar: notifier,
+		FeeBump: FeeBumpPolicy{
+			Interval:        interval,
+			IncreasePercent: 50,
+			MaxFeeRate:      3000,
+		},
+	})
+
+	desc := &JusticeDescriptor{
+		BreachHeight: 100,
+		SessionInfo:  &wtdb.SessionInfo{},
+	}
+	justiceTxn := wire.NewMsgTx(2)
+	justiceTxn.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x00}})
+
+	quit := make(chan struct{})
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- punisher.escalateFee(
+			desc, justiceTxn, 0, 1000, 1000, quit,
+		)
+	}()
+
+	height := int32(100)
+	connectBlocks := func(numBlocks int) {
+		t.Helper()
+
+		for i := 0; i < numBlocks; i++ {
+			height++
+			select {
+			case notifier.epochs <- &chainntnfs.BlockEpoch{
+				Height: height,
+			}:
+			case <-time.After(time.Second):
+				t.Fatalf("block not received")
+			}
+		}
+	}
+
+	assertBump := func(expRate lnwallet.SatPerKWeight) {
+		t.Helper()
+
+		select {
+		case rate := <-bumps:
+			if rate != expRate {
+				t.Fatalf("expected fee rate %v, got %v",
+					expRate, rate)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected fee bump to %v", expRate)
+		}
+	}
+
+	assertNoBump := func() {
+		t.Helper()
+
+		select {
+		case rate := <-bumps:
+			t.Fatalf("unexpected fee bump to %v", rate)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	// The first block determines the starting height, so the fee rate
+	// should only be escalated once a full interval has passed.
+	connectBlocks(interval)
+	assertNoBump()
+	connectBlocks(1)
+	assertBump(1500)
+
+	connectBlocks(interval - 1)
+	assertNoBump()
+	connectBlocks(1)
+	assertBump(2250)
+
+	// The next escalation should be capped at the maximum fee rate, after
+	// which no further escalations should be attempted.
+	connectBlocks(interval)
+	assertBump(3000)
+	connectBlocks(interval)
+	assertNoBump()
+
+	// Once the justice transaction confirms, escalation should cease.
+	select {
+	case notifier.confs <- &chainntnfs.TxConfirmation{}:
+	case <-time.After(time.Second):
+		t.Fatalf("confirmation not received")
+	}
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("unable to escalate fee: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("escalation did not terminate after confirmation")
+	}
+}
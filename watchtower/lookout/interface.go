@@ -42,6 +42,16 @@ type DB interface {
 	// SetLookoutTip writes the best epoch for which the watchtower has
 	// queried for breach hints.
 	SetLookoutTip(*chainntnfs.BlockEpoch) error
+
+	// MarkBreachesConfirmed records that the breaches identified by the
+	// given hints confirmed at the given height, discarding any breaches
+	// recorded at the same height or above.
+	MarkBreachesConfirmed(uint32, []wtdb.BreachHint) error
+
+	// EvictConfirmedBreaches deletes the state updates of all breaches
+	// that confirmed at or below the given height, returning the number
+	// of state updates deleted.
+	EvictConfirmedBreaches(uint32) (uint64, error)
 }
 
 // EpochRegistrar supports the ability to register for events corresponding to
@@ -56,6 +66,18 @@ type EpochRegistrar interface {
 		*chainntnfs.BlockEpoch) (*chainntnfs.BlockEpochEvent, error)
 }
 
+// ConfRegistrar supports the ability to register for the confirmation of
+// transactions.
+type ConfRegistrar interface {
+	// RegisterConfirmationsNtfn registers an intent to be notified once
+	// the transaction with the given txid, which pays to the given
+	// pkScript, reaches numConfs confirmations. The heightHint is the
+	// lowest height at which the transaction could have confirmed.
+	RegisterConfirmationsNtfn(txid *chainhash.Hash, pkScript []byte,
+		numConfs, heightHint uint32) (*chainntnfs.ConfirmationEvent,
+		error)
+}
+
 // Punisher handles the construction and publication of justice transactions
 // once they have been detected by the Service.
 type Punisher interface {
@@ -33,6 +33,10 @@ type JusticeDescriptor struct {
 	// to be detected.
 	BreachedCommitTx *wire.MsgTx
 
+	// BreachHeight is the height at which the breached commitment
+	// transaction confirmed.
+	BreachHeight uint32
+
 	// SessionInfo contains the contract with the watchtower client and
 	// the prenegotiated terms they agreed to.
 	SessionInfo *wtdb.SessionInfo
@@ -48,6 +52,7 @@ type breachedInput struct {
 	txOut    *wire.TxOut
 	outPoint wire.OutPoint
 	witness  [][]byte
+	sequence uint32
 }
 
 // commitToLocalInput extracts the information required to spend the commit
@@ -113,9 +118,31 @@ func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
 
 	// Compute the witness script hash from the to-remote pubkey, which will
 	// be used to locate the input on the breach commitment transaction.
-	toRemoteScriptHash, err := input.CommitScriptUnencumbered(
-		toRemotePubKey,
+	var (
+		toRemoteScriptHash []byte
+		sequence           uint32
 	)
+	if p.SessionInfo.Policy.IsAnchorChannel() {
+		// Anchor channels instead use a p2wsh to-remote output that
+		// requires a confirmation before it can be spent, so the
+		// witness script is derived from the pubkey, and the input
+		// must signal a relative lock time of one block.
+		toRemoteScript, err = input.CommitScriptToRemoteConfirmed(
+			toRemotePubKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		toRemoteScriptHash, err = input.WitnessScriptHash(
+			toRemoteScript,
+		)
+		sequence = 1
+	} else {
+		toRemoteScriptHash, err = input.CommitScriptUnencumbered(
+			toRemotePubKey,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -146,6 +173,7 @@ func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
 		txOut:    toRemoteTxOut,
 		outPoint: toRemoteOutPoint,
 		witness:  buildWitness(witnessStack, toRemoteScript),
+		sequence: sequence,
 	}, nil
 }
 
@@ -164,6 +192,7 @@ func (p *JusticeDescriptor) assembleJusticeTxn(txWeight int64,
 		totalAmt += btcutil.Amount(input.txOut.Value)
 		justiceTxn.AddTxIn(&wire.TxIn{
 			PreviousOutPoint: input.outPoint,
+			Sequence:         input.sequence,
 		})
 	}
 
@@ -267,7 +296,13 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 		if err != nil {
 			return nil, err
 		}
-		weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
+		if p.SessionInfo.Policy.IsAnchorChannel() {
+			weightEstimate.AddWitnessInput(
+				input.ToRemoteConfirmedWitnessSize,
+			)
+		} else {
+			weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
+		}
 		sweepInputs = append(sweepInputs, toRemoteInput)
 	}
 
@@ -278,6 +313,38 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 	return p.assembleJusticeTxn(txWeight, sweepInputs...)
 }
 
+// rewardOutput locates the tower's reward output within the given justice
+// transaction, returning its index along with the fee paid by the justice
+// transaction. This allows the tower to accelerate the confirmation of the
+// justice transaction by spending its reward output in a child transaction.
+func (p *JusticeDescriptor) rewardOutput(
+	justiceTxn *wire.MsgTx) (uint32, btcutil.Amount, error) {
+
+	rewardIndex, _, err := findTxOutByPkScript(
+		justiceTxn, p.SessionInfo.RewardAddress,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// All inputs of the justice transaction spend outputs of the breached
+	// commitment transaction, so we can determine the fee using its
+	// outputs.
+	var fee btcutil.Amount
+	for _, txIn := range justiceTxn.TxIn {
+		prevIndex := txIn.PreviousOutPoint.Index
+		if prevIndex >= uint32(len(p.BreachedCommitTx.TxOut)) {
+			return 0, 0, ErrOutputNotFound
+		}
+		fee += btcutil.Amount(p.BreachedCommitTx.TxOut[prevIndex].Value)
+	}
+	for _, txOut := range justiceTxn.TxOut {
+		fee -= btcutil.Amount(txOut.Value)
+	}
+
+	return rewardIndex, fee, nil
+}
+
 // findTxOutByPkScript searches the given transaction for an output whose
 // pkscript matches the query. If one is found, the TxOut is returned along with
 // the index.
@@ -3,6 +3,7 @@
 package lookout_test
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/litecoinfinance/lnd/input"
 	"github.com/litecoinfinance/lnd/keychain"
+	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/watchtower/blob"
 	"github.com/litecoinfinance/lnd/watchtower/lookout"
@@ -53,6 +55,15 @@ var (
 	)
 
 	altruistCommitType = blob.FlagCommitOutputs.Type()
+
+	rewardAnchorCommitType = blob.TypeFromFlags(
+		blob.FlagReward, blob.FlagCommitOutputs,
+		blob.FlagAnchorChannel,
+	)
+
+	altruistAnchorCommitType = blob.TypeFromFlags(
+		blob.FlagCommitOutputs, blob.FlagAnchorChannel,
+	)
 )
 
 // TestJusticeDescriptor asserts that a JusticeDescriptor is able to produce the
@@ -70,6 +81,14 @@ func TestJusticeDescriptor(t *testing.T) {
 			name:     "altruist and commit type",
 			blobType: altruistCommitType,
 		},
+		{
+			name:     "reward and anchor commit type",
+			blobType: rewardAnchorCommitType,
+		},
+		{
+			name:     "altruist and anchor commit type",
+			blobType: altruistAnchorCommitType,
+		},
 	}
 
 	for _, test := range tests {
@@ -118,10 +137,35 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		t.Fatalf("unable to create to-local witness script hash: %v", err)
 	}
 
-	// Compute the to-remote witness script hash.
-	toRemoteScriptHash, err := input.CommitScriptUnencumbered(toRemotePK)
+	// Compute the to-remote witness script hash. Channels using anchor
+	// outputs pay to a p2wsh script that requires a confirmation, while
+	// other channels use a regular p2wkh output.
+	isAnchor := blobType.Has(blob.FlagAnchorChannel)
+	var (
+		toRemoteScript     []byte
+		toRemoteScriptHash []byte
+		toRemoteSequence   uint32
+	)
+	if isAnchor {
+		toRemoteScript, err = input.CommitScriptToRemoteConfirmed(
+			toRemotePK,
+		)
+		if err != nil {
+			t.Fatalf("unable to create to-remote script: %v", err)
+		}
+
+		toRemoteScriptHash, err = input.WitnessScriptHash(
+			toRemoteScript,
+		)
+		toRemoteSequence = 1
+	} else {
+		toRemoteScriptHash, err = input.CommitScriptUnencumbered(
+			toRemotePK,
+		)
+		toRemoteScript = toRemoteScriptHash
+	}
 	if err != nil {
-		t.Fatalf("unable to create to-remote script: %v", err)
+		t.Fatalf("unable to create to-remote script hash: %v", err)
 	}
 
 	// Construct the breaching commitment txn, containing the to-local and
@@ -145,7 +189,13 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	// Compute the weight estimate for our justice transaction.
 	var weightEstimate input.TxWeightEstimator
 	weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize)
-	weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
+	if isAnchor {
+		weightEstimate.AddWitnessInput(
+			input.ToRemoteConfirmedWitnessSize,
+		)
+	} else {
+		weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
+	}
 	weightEstimate.AddP2WKHOutput()
 	if blobType.Has(blob.FlagReward) {
 		weightEstimate.AddP2WKHOutput()
@@ -193,6 +243,7 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 					Hash:  breachTxID,
 					Index: 1,
 				},
+				Sequence: toRemoteSequence,
 			},
 		},
 	}
@@ -229,7 +280,7 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 			KeyLocator: toRemoteKeyLoc,
 			PubKey:     toRemotePK,
 		},
-		WitnessScript: toRemoteScriptHash,
+		WitnessScript: toRemoteScript,
 		Output:        breachTxn.TxOut[1],
 		SigHashes:     hashCache,
 		InputIndex:    1,
@@ -251,13 +302,24 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	// Compute the witness for the to-remote input. The first element is a
 	// DER-encoded signature under the to-remote pubkey. The sighash flag is
 	// also present, so we trim it.
-	toRemoteWitness, err := input.CommitSpendNoDelay(
-		signer, toRemoteSignDesc, justiceTxn,
-	)
-	if err != nil {
-		t.Fatalf("unable to sign to-remote input: %v", err)
+	var toRemoteSigRaw []byte
+	if isAnchor {
+		toRemoteSigRaw, err = signer.SignOutputRaw(
+			justiceTxn, toRemoteSignDesc,
+		)
+		if err != nil {
+			t.Fatalf("unable to sign to-remote input: %v", err)
+		}
+	} else {
+		toRemoteWitness, err := input.CommitSpendNoDelay(
+			signer, toRemoteSignDesc, justiceTxn,
+		)
+		if err != nil {
+			t.Fatalf("unable to sign to-remote input: %v", err)
+		}
+		sigWithFlag := toRemoteWitness[0]
+		toRemoteSigRaw = sigWithFlag[:len(sigWithFlag)-1]
 	}
-	toRemoteSigRaw := toRemoteWitness[0][:len(toRemoteWitness[0])-1]
 
 	// Convert the DER to-local sig into a fixed-size signature.
 	toLocalSig, err := lnwire.NewSigFromRawSignature(toLocalSigRaw)
@@ -282,13 +344,35 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	}
 
 	// Construct a breach punisher that will feed published transactions
-	// over the buffered channel.
+	// and fee bump requests over the buffered channels.
+	type bumpRequest struct {
+		tx          *wire.MsgTx
+		rewardIndex uint32
+		fee         btcutil.Amount
+		feeRate     lnwallet.SatPerKWeight
+	}
 	publications := make(chan *wire.MsgTx, 1)
+	bumps := make(chan *bumpRequest, 1)
 	punisher := lookout.NewBreachPunisher(&lookout.PunisherConfig{
 		PublishTx: func(tx *wire.MsgTx) error {
 			publications <- tx
 			return nil
 		},
+		BumpFee: func(tx *wire.MsgTx, rewardIndex uint32,
+			fee btcutil.Amount,
+			feeRate lnwallet.SatPerKWeight) error {
+
+			bumps <- &bumpRequest{
+				tx:          tx,
+				rewardIndex: rewardIndex,
+				fee:         fee,
+				feeRate:     feeRate,
+			}
+			return nil
+		},
+		FeeBump: lookout.FeeBumpPolicy{
+			IncreasePercent: lookout.DefaultFeeBumpPercent,
+		},
 	})
 
 	// Exact retribution on the offender. If no error is returned, we expect
@@ -317,7 +401,11 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	justiceTxn.TxIn[1].Witness = make([][]byte, 2)
 	justiceTxn.TxIn[1].Witness[0] = append(toRemoteSigRaw,
 		byte(txscript.SigHashAll))
-	justiceTxn.TxIn[1].Witness[1] = toRemotePK.SerializeCompressed()
+	if isAnchor {
+		justiceTxn.TxIn[1].Witness[1] = toRemoteScript
+	} else {
+		justiceTxn.TxIn[1].Witness[1] = toRemotePK.SerializeCompressed()
+	}
 
 	// Assert that the watchtower derives the same justice txn.
 	if !reflect.DeepEqual(justiceTxn, wtJusticeTxn) {
@@ -325,4 +413,46 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 			spew.Sdump(justiceTxn),
 			spew.Sdump(wtJusticeTxn))
 	}
+
+	// Only justice transactions for anchor channels with a reward output
+	// should be fee bumped.
+	if !isAnchor || !blobType.Has(blob.FlagReward) {
+		select {
+		case <-bumps:
+			t.Fatalf("justice txn should not be fee bumped")
+		default:
+		}
+		return
+	}
+
+	var bump *bumpRequest
+	select {
+	case bump = <-bumps:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("punisher did not fee bump justice txn")
+	}
+
+	rewardOut := wtJusticeTxn.TxOut[bump.rewardIndex]
+	if !bytes.Equal(rewardOut.PkScript, sessionInfo.RewardAddress) {
+		t.Fatalf("fee bump requested for output %d, which is not "+
+			"the reward output", bump.rewardIndex)
+	}
+
+	expFee := totalAmount
+	for _, txOut := range wtJusticeTxn.TxOut {
+		expFee -= btcutil.Amount(txOut.Value)
+	}
+	if bump.fee != expFee {
+		t.Fatalf("expected justice txn fee %v, got %v", expFee,
+			bump.fee)
+	}
+
+	// The child should raise the fee rate of the justice transaction.
+	weight := blockchain.GetTransactionWeight(btcutil.NewTx(wtJusticeTxn))
+	justiceFeeRate := lnwallet.SatPerKWeight(expFee * 1000 /
+		btcutil.Amount(weight))
+	if bump.feeRate <= justiceFeeRate {
+		t.Fatalf("expected bumped fee rate above %v, got %v",
+			justiceFeeRate, bump.feeRate)
+	}
 }
@@ -1,9 +1,12 @@
 package lookout
 
 import (
+	"errors"
 	"sync"
 	"sync/atomic"
 
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/watchtower/blob"
@@ -30,17 +33,69 @@ type Config struct {
 	// Punisher handles the responsibility of crafting and broadcasting
 	// justice transaction for any breached transactions.
 	Punisher Punisher
+
+	// EvictionDepth is the number of blocks that must be mined on top of
+	// a breach before its state updates are deleted from the database. If
+	// zero, state updates are never evicted.
+	EvictionDepth uint32
+
+	// NodePrivKey is the tower's identity private key, used to derive the
+	// keys of versioned blobs together with the session ids of the clients
+	// that sent them.
+	NodePrivKey *btcec.PrivateKey
+
+	// BlockBatchSize is the maximum number of queued blocks whose
+	// contents are fetched concurrently, e.g. while catching up with the
+	// chain after being offline. Blocks are still processed in order. If
+	// zero, blocks are fetched one at a time.
+	BlockBatchSize uint32
+}
+
+// DefaultBlockBatchSize is the default maximum number of blocks fetched
+// concurrently by the lookout.
+const DefaultBlockBatchSize = 10
+
+// activeBreach is a breach for which justice has been dispatched, tracked such
+// that the dispatch can be canceled if the block confirming the breach is
+// disconnected.
+type activeBreach struct {
+	// height and blockHash identify the block that confirmed the breach.
+	height    int32
+	blockHash chainhash.Hash
+
+	// disconnected is closed once the block confirming the breach has
+	// been disconnected from the chain.
+	disconnected chan struct{}
+}
+
+// Stats summarizes the breaches handled by the lookout since it was started.
+type Stats struct {
+	// MatchedBreaches is the number of breaches for which a client's
+	// encrypted blob was successfully decrypted.
+	MatchedBreaches uint64
+
+	// PunishedBreaches is the number of breaches for which a justice
+	// transaction was successfully dispatched.
+	PunishedBreaches uint64
 }
 
 // Lookout will check any incoming blocks against the transactions found in the
 // database, and in case of matches send the information needed to create a
 // penalty transaction to the punisher.
 type Lookout struct {
+	matchedBreaches  uint64 // atomic
+	punishedBreaches uint64 // atomic
+
 	started  int32 // atomic
 	shutdown int32 // atomic
 
 	cfg *Config
 
+	// activeBreaches maps the txid of each breach dispatched within the
+	// last chainntnfs.ReorgSafetyLimit blocks to the block confirming it.
+	// It is only accessed by the watchBlocks goroutine.
+	activeBreaches map[chainhash.Hash]*activeBreach
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -48,8 +103,9 @@ type Lookout struct {
 // New constructs a new Lookout from the given LookoutConfig.
 func New(cfg *Config) *Lookout {
 	return &Lookout{
-		cfg:  cfg,
-		quit: make(chan struct{}),
+		cfg:            cfg,
+		activeBreaches: make(map[chainhash.Hash]*activeBreach),
+		quit:           make(chan struct{}),
 	}
 }
 
@@ -103,10 +159,20 @@ func (l *Lookout) Stop() error {
 	return nil
 }
 
+// Stats returns a summary of the breaches handled since the lookout was
+// started.
+func (l *Lookout) Stats() Stats {
+	return Stats{
+		MatchedBreaches:  atomic.LoadUint64(&l.matchedBreaches),
+		PunishedBreaches: atomic.LoadUint64(&l.punishedBreaches),
+	}
+}
+
 // watchBlocks serially pulls incoming epochs from the epoch source and searches
 // our accepted state updates for any breached transactions. If any are found,
 // we will attempt to decrypt the state updates' encrypted blobs and exact
-// justice for the victim.
+// justice for the victim. Epochs that are already queued are handled in
+// batches, whose blocks are fetched concurrently.
 //
 // This method MUST be run as a goroutine.
 func (l *Lookout) watchBlocks(epochs *chainntnfs.BlockEpochEvent) {
@@ -116,40 +182,146 @@ func (l *Lookout) watchBlocks(epochs *chainntnfs.BlockEpochEvent) {
 	for {
 		select {
 		case epoch := <-epochs.Epochs:
-			log.Debugf("Fetching block for (height=%d, hash=%s)",
-				epoch.Height, epoch.Hash)
+			batch := l.queuedEpochs(epoch, epochs.Epochs)
+			blocks := l.fetchBlocks(batch)
+
+			for i, epoch := range batch {
+				// Blocks that couldn't be fetched have
+				// already been logged.
+				//
+				// TODO(conner): add retry logic?
+				if blocks[i] == nil {
+					continue
+				}
+
+				l.handleEpoch(epoch, blocks[i])
+			}
+
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+// queuedEpochs returns a batch of epochs starting with the given one, followed
+// by any epochs that are already queued, up to the configured batch size.
+func (l *Lookout) queuedEpochs(first *chainntnfs.BlockEpoch,
+	epochs <-chan *chainntnfs.BlockEpoch) []*chainntnfs.BlockEpoch {
+
+	batch := []*chainntnfs.BlockEpoch{first}
+	for uint32(len(batch)) < l.cfg.BlockBatchSize {
+		select {
+		case epoch, ok := <-epochs:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, epoch)
+
+		default:
+			return batch
+		}
+	}
+
+	return batch
+}
+
+// fetchBlocks concurrently fetches the blocks of the given epochs from the
+// backend. The returned blocks are in the same order as the epochs, with nil
+// entries for blocks that couldn't be fetched.
+func (l *Lookout) fetchBlocks(
+	batch []*chainntnfs.BlockEpoch) []*wire.MsgBlock {
+
+	blocks := make([]*wire.MsgBlock, len(batch))
+
+	var wg sync.WaitGroup
+	for i, epoch := range batch {
+		log.Debugf("Fetching block for (height=%d, hash=%s)",
+			epoch.Height, epoch.Hash)
+
+		wg.Add(1)
+		go func(i int, epoch *chainntnfs.BlockEpoch) {
+			defer wg.Done()
 
-			// Fetch the full block from the backend corresponding
-			// to the newly arriving epoch.
 			block, err := l.cfg.BlockFetcher.GetBlock(epoch.Hash)
 			if err != nil {
-				// TODO(conner): add retry logic?
 				log.Errorf("Unable to fetch block for "+
-					"(height=%x, hash=%s): %v",
+					"(height=%d, hash=%s): %v",
 					epoch.Height, epoch.Hash, err)
-				continue
+				return
 			}
 
-			// Process the block to see if it contains any breaches
-			// that we are monitoring on behalf of our clients.
-			err = l.processEpoch(epoch, block)
-			if err != nil {
-				log.Errorf("Unable to process %v: %v",
-					epoch, err)
-			}
+			blocks[i] = block
+		}(i, epoch)
+	}
+	wg.Wait()
 
-		case <-l.quit:
-			return
+	return blocks
+}
+
+// handleEpoch processes a newly connected block. Any breaches confirmed by
+// blocks that have since been disconnected are rearmed first, such that they
+// are punished again should they confirm on the new chain.
+func (l *Lookout) handleEpoch(epoch *chainntnfs.BlockEpoch,
+	block *wire.MsgBlock) {
+
+	l.disconnectBreaches(epoch)
+
+	// Process the block to see if it contains any breaches that we are
+	// monitoring on behalf of our clients.
+	confirmed, err := l.processEpoch(epoch, block)
+	if err != nil {
+		log.Errorf("Unable to process %v: %v", epoch, err)
+		return
+	}
+
+	// Finally, record the breaches confirmed by this block, and evict
+	// those that are now buried deep enough.
+	err = l.evictBreaches(epoch, confirmed)
+	if err != nil {
+		log.Errorf("Unable to evict breaches at height=%d: %v",
+			epoch.Height, err)
+	}
+}
+
+// disconnectBreaches cancels the dispatch of all active breaches confirmed by
+// blocks that were disconnected for the given epoch to be connected, i.e. at
+// its height or above. The breaches are no longer tracked, such that they will
+// be matched again if they confirm on the new chain. Breaches buried deeper
+// than chainntnfs.ReorgSafetyLimit are assumed to be final, and are no longer
+// tracked either.
+func (l *Lookout) disconnectBreaches(epoch *chainntnfs.BlockEpoch) {
+	for txid, breach := range l.activeBreaches {
+		switch {
+		case breach.height+chainntnfs.ReorgSafetyLimit <= epoch.Height:
+			delete(l.activeBreaches, txid)
+			continue
+
+		case breach.height < epoch.Height:
+			continue
+
+		// The same block may be delivered again, which doesn't
+		// disconnect it.
+		case breach.height == epoch.Height &&
+			breach.blockHash == *epoch.Hash:
+			continue
 		}
+
+		log.Infof("Block (height=%d, hash=%s) confirming "+
+			"breach-txid=%s was disconnected, rearming breach",
+			breach.height, breach.blockHash, txid)
+
+		close(breach.disconnected)
+		delete(l.activeBreaches, txid)
 	}
 }
 
 // processEpoch accepts an Epoch and queries the database for any matching state
 // updates for the confirmed transactions. If any are found, the lookout
 // responds by attempting to decrypt the encrypted blob and publishing the
-// justice transaction.
+// justice transaction. The hints of all breaches for which justice was
+// dispatched are returned.
 func (l *Lookout) processEpoch(epoch *chainntnfs.BlockEpoch,
-	block *wire.MsgBlock) error {
+	block *wire.MsgBlock) ([]wtdb.BreachHint, error) {
 
 	numTxnsInBlock := len(block.Transactions)
 
@@ -173,14 +345,14 @@ func (l *Lookout) processEpoch(epoch *chainntnfs.BlockEpoch,
 	// with any of our accepted state updates.
 	matches, err := l.cfg.DB.QueryMatches(txHints)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// No matches were found, we are done.
 	if len(matches) == 0 {
 		log.Debugf("No breaches found in (height=%d, hash=%s)",
 			epoch.Height, epoch.Hash)
-		return nil
+		return nil, nil
 	}
 
 	breachCountStr := "breach"
@@ -198,19 +370,32 @@ func (l *Lookout) processEpoch(epoch *chainntnfs.BlockEpoch,
 	var successes []*JusticeDescriptor
 	for _, match := range matches {
 		commitTx := hintToTx[match.Hint]
+
+		// Skip breaches that have already been dispatched from this
+		// very block, in case it was delivered more than once.
+		if _, ok := l.activeBreaches[commitTx.TxHash()]; ok {
+			continue
+		}
+
 		log.Infof("Dispatching punisher for client %s, breach-txid=%s",
 			match.ID, commitTx.TxHash())
 
-		// The decryption key for the state update should be the full
-		// txid of the breaching commitment transaction.
+		// The decryption key for the state update is derived from the
+		// full txid of the breaching commitment transaction.
 		commitTxID := commitTx.TxHash()
+		key, err := l.blobKey(match.SessionInfo, commitTxID)
+		if err != nil {
+			log.Debugf("Unable to derive blob key for client %s, "+
+				"breach-txid %s: %v", match.ID, commitTxID, err)
+			continue
+		}
 
 		// Now, decrypt the blob of justice that we received in the
 		// state update. This will contain all information required to
 		// sweep the breached commitment outputs.
+		blobType := match.SessionInfo.Policy.BlobType
 		justiceKit, err := blob.Decrypt(
-			commitTxID[:], match.EncryptedBlob,
-			match.SessionInfo.Policy.BlobType,
+			key, match.EncryptedBlob, blobType,
 		)
 		if err != nil {
 			// If the decryption fails, this implies either that the
@@ -225,6 +410,7 @@ func (l *Lookout) processEpoch(epoch *chainntnfs.BlockEpoch,
 
 		justiceDesc := &JusticeDescriptor{
 			BreachedCommitTx: commitTx,
+			BreachHeight:     uint32(epoch.Height),
 			SessionInfo:      match.SessionInfo,
 			JusticeKit:       justiceKit,
 		}
@@ -237,29 +423,133 @@ func (l *Lookout) processEpoch(epoch *chainntnfs.BlockEpoch,
 	// Now, we'll dispatch a punishment for each successful match in
 	// parallel. This will assemble the justice transaction for each and
 	// watch for their confirmation on chain.
+	atomic.AddUint64(&l.matchedBreaches, uint64(len(successes)))
+	confirmed := make([]wtdb.BreachHint, 0, len(successes))
 	for _, justiceDesc := range successes {
+		commitTxID := justiceDesc.BreachedCommitTx.TxHash()
+		confirmed = append(
+			confirmed, wtdb.NewBreachHintFromHash(&commitTxID),
+		)
+
+		// Track the breach, such that its dispatch can be canceled
+		// if this block is disconnected.
+		breach := &activeBreach{
+			height:       epoch.Height,
+			blockHash:    *epoch.Hash,
+			disconnected: make(chan struct{}),
+		}
+		l.activeBreaches[commitTxID] = breach
+
 		l.wg.Add(1)
-		go l.dispatchPunisher(justiceDesc)
+		go l.dispatchPunisher(justiceDesc, breach.disconnected)
+	}
+
+	err = l.cfg.DB.SetLookoutTip(epoch)
+	if err != nil {
+		return nil, err
 	}
 
-	return l.cfg.DB.SetLookoutTip(epoch)
+	return confirmed, nil
+}
+
+// evictBreaches records the breaches confirmed by the given epoch, and, if
+// eviction is enabled, deletes the state updates of all breaches that have
+// since reached the eviction depth. Once a breach is buried this deep, the
+// justice transaction has been dispatched long ago, and its state updates are
+// of no further use.
+func (l *Lookout) evictBreaches(epoch *chainntnfs.BlockEpoch,
+	confirmed []wtdb.BreachHint) error {
+
+	if l.cfg.EvictionDepth == 0 {
+		return nil
+	}
+
+	// Breaches are recorded for every block, even those that contain
+	// none, such that any breaches recorded for blocks that have been
+	// reorged out are discarded before they could be evicted.
+	height := uint32(epoch.Height)
+	err := l.cfg.DB.MarkBreachesConfirmed(height, confirmed)
+	if err != nil {
+		return err
+	}
+
+	if height < l.cfg.EvictionDepth {
+		return nil
+	}
+
+	numEvicted, err := l.cfg.DB.EvictConfirmedBreaches(
+		height - l.cfg.EvictionDepth,
+	)
+	if err != nil {
+		return err
+	}
+
+	if numEvicted > 0 {
+		log.Infof("Evicted %d state updates of breaches confirmed at "+
+			"or below height=%d", numEvicted,
+			height-l.cfg.EvictionDepth)
+	}
+
+	return nil
+}
+
+// blobKey returns the key under which the blob of a session with the given
+// info is encrypted for the breach transaction with the given txid. Versioned
+// blobs are encrypted under a key shared with the session, which is derived
+// using the tower's private key and the session id.
+func (l *Lookout) blobKey(info *wtdb.SessionInfo,
+	commitTxID chainhash.Hash) ([]byte, error) {
+
+	var secret [32]byte
+	if info.Policy.BlobType.Has(blob.FlagVersioned) {
+		if l.cfg.NodePrivKey == nil {
+			return nil, errors.New("tower private key required " +
+				"for versioned blobs")
+		}
+
+		sessionPub, err := btcec.ParsePubKey(info.ID[:], btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+		secret = blob.SessionSecret(l.cfg.NodePrivKey, sessionPub)
+	}
+
+	return blob.DeriveKey(info.Policy.BlobType, commitTxID, secret)
 }
 
 // dispatchPunisher accepts a justice descriptor corresponding to a successfully
 // decrypted blob.  The punisher will then construct the witness scripts and
 // witness stacks for the breached outputs. If construction of the justice
 // transaction is successful, it will be published to the network to retrieve
-// the funds and claim the watchtower's reward.
+// the funds and claim the watchtower's reward. The punishment is canceled if
+// the disconnected channel is closed, as the breach may then never confirm.
 //
 // This method MUST be run as a goroutine.
-func (l *Lookout) dispatchPunisher(desc *JusticeDescriptor) {
+func (l *Lookout) dispatchPunisher(desc *JusticeDescriptor,
+	disconnected <-chan struct{}) {
+
 	defer l.wg.Done()
 
+	// The punisher is canceled either during shutdown, or if the breach
+	// is reorged out.
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-disconnected:
+		case <-l.quit:
+		case <-done:
+			return
+		}
+		close(quit)
+	}()
+
 	// Give the justice descriptor to the punisher to construct and publish
-	// the justice transaction. The lookout's quit channel is provided so
-	// that long-running tasks that watch for on-chain events can be
-	// canceled during shutdown since this method is waitgrouped.
-	err := l.cfg.Punisher.Punish(desc, l.quit)
+	// the justice transaction. The quit channel is provided so that
+	// long-running tasks that watch for on-chain events can be canceled
+	// since this method is waitgrouped.
+	err := l.cfg.Punisher.Punish(desc, quit)
 	if err != nil {
 		log.Errorf("Unable to punish breach-txid %s for %s: %v",
 			desc.BreachedCommitTx.TxHash(), desc.SessionInfo.ID,
@@ -267,6 +557,19 @@ func (l *Lookout) dispatchPunisher(desc *JusticeDescriptor) {
 		return
 	}
 
+	// If the breach was reorged out, it will be counted once punished
+	// again on the new chain.
+	select {
+	case <-disconnected:
+		log.Infof("Punishment for client %s with breach-txid=%s "+
+			"canceled", desc.SessionInfo.ID,
+			desc.BreachedCommitTx.TxHash())
+		return
+	default:
+	}
+
+	atomic.AddUint64(&l.punishedBreaches, 1)
+
 	log.Infof("Punishment for client %s with breach-txid=%s dispatched",
 		desc.SessionInfo.ID, desc.BreachedCommitTx.TxHash())
 }
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/wire"
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/watchtower/blob"
@@ -83,6 +84,7 @@ func TestLookoutBreachMatching(t *testing.T) {
 		DB:             db,
 		EpochRegistrar: backend,
 		Punisher:       punisher,
+		EvictionDepth:  1,
 	})
 	if err := watcher.Start(); err != nil {
 		t.Fatalf("unable to start watcher: %v", err)
@@ -258,4 +260,157 @@ func TestLookoutBreachMatching(t *testing.T) {
 		t.Fatalf("only one txn should have been matched")
 	case <-time.After(50 * time.Millisecond):
 	}
+
+	// With an eviction depth of one, connecting the second block should
+	// cause the state update of the first breach to be evicted, while
+	// that of the second breach is retained.
+	queryMatches := func(hash chainhash.Hash) []wtdb.Match {
+		t.Helper()
+
+		dbMatches, err := db.QueryMatches([]wtdb.BreachHint{
+			wtdb.NewBreachHintFromHash(&hash),
+		})
+		if err != nil {
+			t.Fatalf("unable to query matches: %v", err)
+		}
+
+		return dbMatches
+	}
+
+	timeout := time.After(5 * time.Second)
+	for len(queryMatches(hash1)) != 0 {
+		select {
+		case <-timeout:
+			t.Fatalf("state update of tx1 was not evicted")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if len(queryMatches(hash2)) != 1 {
+		t.Fatalf("state update of tx2 should not be evicted")
+	}
+}
+
+// blockingPunisher is a Punisher that blocks until its quit channel is
+// closed, signaling the cancellation of the punishment.
+type blockingPunisher struct {
+	matches  chan *lookout.JusticeDescriptor
+	canceled chan *lookout.JusticeDescriptor
+}
+
+func (p *blockingPunisher) Punish(
+	info *lookout.JusticeDescriptor, quit <-chan struct{}) error {
+
+	p.matches <- info
+	<-quit
+	p.canceled <- info
+	return nil
+}
+
+// TestLookoutBreachReorg asserts that the punishment of a breach is canceled
+// if the block confirming it is disconnected, and that the breach is punished
+// again once it confirms on the new chain.
+func TestLookoutBreachReorg(t *testing.T) {
+	db := wtmock.NewTowerDB()
+	backend := lookout.NewMockBackend()
+	punisher := &blockingPunisher{
+		matches:  make(chan *lookout.JusticeDescriptor),
+		canceled: make(chan *lookout.JusticeDescriptor),
+	}
+
+	watcher := lookout.New(&lookout.Config{
+		BlockFetcher:   backend,
+		DB:             db,
+		EpochRegistrar: backend,
+		Punisher:       punisher,
+	})
+	if err := watcher.Start(); err != nil {
+		t.Fatalf("unable to start watcher: %v", err)
+	}
+
+	sessionInfo := &wtdb.SessionInfo{
+		ID: makeArray33(1),
+		Policy: wtpolicy.Policy{
+			BlobType:   blob.FlagCommitOutputs.Type(),
+			MaxUpdates: 10,
+		},
+	}
+	if err := db.InsertSessionInfo(sessionInfo); err != nil {
+		t.Fatalf("unable to insert session info: %v", err)
+	}
+
+	breachTx := wire.NewMsgTx(wire.TxVersion)
+	breachTxID := breachTx.TxHash()
+
+	kit := &blob.JusticeKit{
+		SweepAddress:     makeAddrSlice(22),
+		RevocationPubKey: makePubKey(1),
+		LocalDelayPubKey: makePubKey(1),
+		CSVDelay:         144,
+		CommitToLocalSig: makeArray64(1),
+	}
+	encBlob, err := kit.Encrypt(breachTxID[:], blob.FlagCommitOutputs.Type())
+	if err != nil {
+		t.Fatalf("unable to encrypt justice kit: %v", err)
+	}
+
+	_, err = db.InsertStateUpdate(&wtdb.SessionStateUpdate{
+		ID:            makeArray33(1),
+		Hint:          wtdb.NewBreachHintFromHash(&breachTxID),
+		EncryptedBlob: encBlob,
+		SeqNum:        1,
+	})
+	if err != nil {
+		t.Fatalf("unable to add state update to db: %v", err)
+	}
+
+	connectBlock := func(height int32, nonce uint32, txns ...*wire.MsgTx) {
+		block := &wire.MsgBlock{
+			Header:       wire.BlockHeader{Nonce: nonce},
+			Transactions: txns,
+		}
+		blockHash := block.BlockHash()
+		backend.ConnectEpoch(&chainntnfs.BlockEpoch{
+			Hash:   &blockHash,
+			Height: height,
+		}, block)
+	}
+
+	assertBreach := func(c chan *lookout.JusticeDescriptor) {
+		t.Helper()
+
+		select {
+		case desc := <-c:
+			if desc.BreachedCommitTx.TxHash() != breachTxID {
+				t.Fatalf("unexpected breach-txid %v",
+					desc.BreachedCommitTx.TxHash())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("breach not received")
+		}
+	}
+
+	// Connecting a block containing the breach should dispatch its
+	// punishment.
+	connectBlock(1, 1, breachTx)
+	assertBreach(punisher.matches)
+
+	// Now, reorg the block out by connecting a block at the same height
+	// without the breach. The pending punishment should be canceled.
+	connectBlock(1, 2)
+	assertBreach(punisher.canceled)
+
+	// Once the breach confirms on the new chain, it should be punished
+	// again.
+	connectBlock(2, 3, breachTx)
+	assertBreach(punisher.matches)
+
+	// Connecting another block on top shouldn't affect the breach.
+	connectBlock(3, 4)
+	select {
+	case <-punisher.canceled:
+		t.Fatalf("punishment should not be canceled")
+	case <-punisher.matches:
+		t.Fatalf("breach should not be matched again")
+	case <-time.After(50 * time.Millisecond):
+	}
 }
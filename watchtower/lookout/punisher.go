@@ -1,30 +1,109 @@
 package lookout
 
 import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
 	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/watchtower/blob"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
 )
 
+// ErrNotifierExiting signals that the chain notifier shut down while the
+// punisher was awaiting the confirmation of a justice transaction.
+var ErrNotifierExiting = errors.New("chain notifier exiting")
+
 // PunisherConfig houses the resources required by the Punisher.
 type PunisherConfig struct {
 	// PublishTx provides the ability to send a signed transaction to the
 	// network.
 	PublishTx func(*wire.MsgTx) error
 
+	// BumpFee, if non-nil, is used to accelerate the confirmation of
+	// justice transactions with a reward output. As these are signed by
+	// the client at the session's fee rate, the tower can only raise
+	// their effective fee rate using CPFP. It is handed the published
+	// justice transaction, the index of the tower's reward output, the
+	// fee paid by the justice transaction, and the fee rate the justice
+	// transaction and its child should pay combined. It is expected to
+	// publish a child transaction spending the reward output, adding
+	// inputs from the tower's wallet if needed, that replaces any child
+	// previously published for the same justice transaction.
+	BumpFee func(justiceTx *wire.MsgTx, rewardIndex uint32,
+		fee btcutil.Amount, feeRate lnwallet.SatPerKWeight) error
+
+	// ConfRegistrar and EpochRegistrar allow the punisher to track the
+	// confirmation of the justice transactions it publishes. If either
+	// is nil, or BumpFee is, justice transactions aren't fee bumped once
+	// published, with the exception of the initial bump of justice
+	// transactions sweeping anchor channels.
+	ConfRegistrar  ConfRegistrar
+	EpochRegistrar EpochRegistrar
+
+	// FeeBump determines how the fee rate of justice transactions that
+	// remain unconfirmed is escalated.
+	FeeBump FeeBumpPolicy
+
 	// TODO(conner) add DB tracking and spend ntfn registration to see if
 	// ours confirmed or not
 }
 
+// PunisherStats summarizes the justice transactions handled by the
+// BreachPunisher since it was created.
+type PunisherStats struct {
+	// PublishedJustice is the number of justice transactions that were
+	// successfully published.
+	PublishedJustice uint64
+
+	// ConfirmedJustice is the number of published justice transactions
+	// whose confirmation was observed. Confirmations are only tracked if
+	// the punisher has a ConfRegistrar.
+	ConfirmedJustice uint64
+
+	// Rewards maps the id of each session to the total value of the
+	// tower's reward outputs within the confirmed justice transactions
+	// of the session.
+	Rewards map[wtdb.SessionID]btcutil.Amount
+}
+
 // BreachPunisher handles the responsibility of constructing and broadcasting
 // justice transactions. Justice transactions are constructed from previously
 // accepted state updates uploaded by the watchtower's clients.
 type BreachPunisher struct {
+	publishedJustice uint64 // atomic
+	confirmedJustice uint64 // atomic
+
 	cfg *PunisherConfig
+
+	rewardsMtx sync.Mutex
+	rewards    map[wtdb.SessionID]btcutil.Amount
 }
 
 // NewBreachPunisher constructs a new BreachPunisher given a PunisherConfig.
 func NewBreachPunisher(cfg *PunisherConfig) *BreachPunisher {
 	return &BreachPunisher{
-		cfg: cfg,
+		cfg:     cfg,
+		rewards: make(map[wtdb.SessionID]btcutil.Amount),
+	}
+}
+
+// Stats returns a summary of the justice transactions handled since the
+// punisher was created.
+func (p *BreachPunisher) Stats() PunisherStats {
+	p.rewardsMtx.Lock()
+	rewards := make(map[wtdb.SessionID]btcutil.Amount, len(p.rewards))
+	for id, reward := range p.rewards {
+		rewards[id] = reward
+	}
+	p.rewardsMtx.Unlock()
+
+	return PunisherStats{
+		PublishedJustice: atomic.LoadUint64(&p.publishedJustice),
+		ConfirmedJustice: atomic.LoadUint64(&p.confirmedJustice),
+		Rewards:          rewards,
 	}
 }
 
@@ -50,8 +129,142 @@ func (p *BreachPunisher) Punish(desc *JusticeDescriptor, quit <-chan struct{}) e
 		return err
 	}
 
-	// TODO(conner): register for spend and remove from db after
-	// confirmation
+	atomic.AddUint64(&p.publishedJustice, 1)
+
+	// Justice transactions can only be fee bumped through the tower's
+	// reward output, so for altruist sessions we'll only wait for the
+	// justice transaction to confirm.
+	policy := desc.SessionInfo.Policy
+	if p.cfg.BumpFee == nil || !policy.BlobType.Has(blob.FlagReward) {
+		p.awaitConfirmation(desc, justiceTxn, quit)
+		return nil
+	}
+
+	rewardIndex, fee, err := desc.rewardOutput(justiceTxn)
+	if err != nil {
+		log.Errorf("Unable to locate reward output of justice txn=%s "+
+			"for client=%s: %v", justiceTxn.TxHash(),
+			desc.SessionInfo.ID, err)
+		p.awaitConfirmation(desc, justiceTxn, quit)
+		return nil
+	}
+	feeRate := justiceFeeRate(justiceTxn, fee)
+
+	// Justice transactions for anchor channels are bumped right away.
+	if policy.IsAnchorChannel() {
+		feeRate = p.bumpFee(desc, justiceTxn, rewardIndex, fee, feeRate)
+	}
+
+	// If we're able to track the confirmation of the justice transaction,
+	// we'll escalate its fee rate for as long as it remains unconfirmed.
+	if p.cfg.ConfRegistrar == nil || p.cfg.EpochRegistrar == nil {
+		return nil
+	}
+
+	err = p.escalateFee(desc, justiceTxn, rewardIndex, fee, feeRate, quit)
+	if err != nil {
+		log.Errorf("Unable to track confirmation of justice txn=%s "+
+			"for client=%s: %v", justiceTxn.TxHash(),
+			desc.SessionInfo.ID, err)
+	}
+
+	// TODO(conner): remove from db after confirmation
 
 	return nil
 }
+
+// bumpFee attempts to accelerate the confirmation of the given justice
+// transaction by spending the tower's reward output using CPFP, at the fee rate
+// following the given one. Failures are only logged, as the justice
+// transaction has already been published. The resulting fee rate of the
+// justice transaction is returned.
+func (p *BreachPunisher) bumpFee(desc *JusticeDescriptor,
+	justiceTxn *wire.MsgTx, rewardIndex uint32, fee btcutil.Amount,
+	feeRate lnwallet.SatPerKWeight) lnwallet.SatPerKWeight {
+
+	nextFeeRate, ok := p.cfg.FeeBump.nextFeeRate(feeRate)
+	if !ok {
+		return feeRate
+	}
+
+	log.Infof("Bumping fee of justice txn=%s for client=%s to %v using "+
+		"reward output %d", justiceTxn.TxHash(), desc.SessionInfo.ID,
+		nextFeeRate, rewardIndex)
+
+	err := p.cfg.BumpFee(justiceTxn, rewardIndex, fee, nextFeeRate)
+	if err != nil {
+		log.Errorf("Unable to bump fee of justice txn=%s for "+
+			"client=%s: %v", justiceTxn.TxHash(),
+			desc.SessionInfo.ID, err)
+		return feeRate
+	}
+
+	return nextFeeRate
+}
+
+// awaitConfirmation blocks until the given justice transaction confirms, or
+// the quit channel is closed. It returns immediately if the punisher is unable
+// to track confirmations.
+func (p *BreachPunisher) awaitConfirmation(desc *JusticeDescriptor,
+	justiceTxn *wire.MsgTx, quit <-chan struct{}) {
+
+	if p.cfg.ConfRegistrar == nil {
+		return
+	}
+
+	justiceTxID := justiceTxn.TxHash()
+	confNtfn, err := p.cfg.ConfRegistrar.RegisterConfirmationsNtfn(
+		&justiceTxID, justiceTxn.TxOut[0].PkScript, 1,
+		desc.BreachHeight,
+	)
+	if err != nil {
+		log.Errorf("Unable to track confirmation of justice txn=%s "+
+			"for client=%s: %v", justiceTxID, desc.SessionInfo.ID,
+			err)
+		return
+	}
+	defer confNtfn.Cancel()
+
+	select {
+	case _, ok := <-confNtfn.Confirmed:
+		if !ok {
+			log.Errorf("Unable to track confirmation of justice "+
+				"txn=%s for client=%s: %v", justiceTxID,
+				desc.SessionInfo.ID, ErrNotifierExiting)
+			return
+		}
+
+		p.justiceConfirmed(desc, justiceTxn)
+
+	case <-quit:
+	}
+}
+
+// justiceConfirmed records the confirmation of the given justice transaction,
+// crediting the value of the tower's reward output, if any, to the session.
+func (p *BreachPunisher) justiceConfirmed(desc *JusticeDescriptor,
+	justiceTxn *wire.MsgTx) {
+
+	log.Infof("Justice txn=%s for client=%s confirmed",
+		justiceTxn.TxHash(), desc.SessionInfo.ID)
+
+	atomic.AddUint64(&p.confirmedJustice, 1)
+
+	if !desc.SessionInfo.Policy.BlobType.Has(blob.FlagReward) {
+		return
+	}
+
+	_, rewardOutput, err := findTxOutByPkScript(
+		justiceTxn, desc.SessionInfo.RewardAddress,
+	)
+	if err != nil {
+		log.Errorf("Unable to locate reward output of justice txn=%s "+
+			"for client=%s: %v", justiceTxn.TxHash(),
+			desc.SessionInfo.ID, err)
+		return
+	}
+
+	p.rewardsMtx.Lock()
+	p.rewards[desc.SessionInfo.ID] += btcutil.Amount(rewardOutput.Value)
+	p.rewardsMtx.Unlock()
+}
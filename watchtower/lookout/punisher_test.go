@@ -0,0 +1,101 @@
+package lookout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcd/wire"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/chainntnfs"
+	"github.com/litecoinfinance/lnd/watchtower/blob"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
+	"github.com/litecoinfinance/lnd/watchtower/wtpolicy"
+)
+
+// TestBreachPunisherStats asserts that the punisher only credits the reward of
+// a justice transaction to its session once the transaction confirms, and that
+// it counts the confirmations of both reward and altruist sessions.
+func TestBreachPunisherStats(t *testing.T) {
+	t.Parallel()
+
+	notifier := &mockChainNotifier{
+		confs: make(chan *chainntnfs.TxConfirmation),
+	}
+	punisher := NewBreachPunisher(&PunisherConfig{
+		ConfRegistrar: notifier,
+	})
+
+	rewardScript := []byte{0x00, 0x01}
+	rewardSession := &wtdb.SessionInfo{
+		ID: wtdb.SessionID{0x01},
+		Policy: wtpolicy.Policy{
+			BlobType: blob.TypeFromFlags(
+				blob.FlagReward, blob.FlagCommitOutputs,
+			),
+		},
+		RewardAddress: rewardScript,
+	}
+	altruistSession := &wtdb.SessionInfo{
+		ID: wtdb.SessionID{0x02},
+		Policy: wtpolicy.Policy{
+			BlobType: blob.TypeDefault,
+		},
+	}
+
+	justiceTxn := wire.NewMsgTx(2)
+	justiceTxn.AddTxOut(&wire.TxOut{Value: 5000, PkScript: []byte{0x00}})
+	justiceTxn.AddTxOut(&wire.TxOut{Value: 300, PkScript: rewardScript})
+
+	confirm := func(session *wtdb.SessionInfo) {
+		t.Helper()
+
+		desc := &JusticeDescriptor{SessionInfo: session}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			punisher.awaitConfirmation(desc, justiceTxn, nil)
+		}()
+
+		select {
+		case notifier.confs <- &chainntnfs.TxConfirmation{}:
+		case <-time.After(time.Second):
+			t.Fatalf("confirmation not received")
+		}
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("punisher did not return after confirmation")
+		}
+	}
+
+	assertStats := func(expConfirmed uint64, expReward btcutil.Amount) {
+		t.Helper()
+
+		stats := punisher.Stats()
+		if stats.ConfirmedJustice != expConfirmed {
+			t.Fatalf("expected %d confirmed justice txns, got %d",
+				expConfirmed, stats.ConfirmedJustice)
+		}
+
+		reward := stats.Rewards[rewardSession.ID]
+		if reward != expReward {
+			t.Fatalf("expected reward %v, got %v", expReward,
+				reward)
+		}
+		if _, ok := stats.Rewards[altruistSession.ID]; ok {
+			t.Fatalf("altruist session should not earn rewards")
+		}
+	}
+
+	// The rewards of each confirmed justice transaction should add up.
+	confirm(rewardSession)
+	assertStats(1, 300)
+	confirm(rewardSession)
+	assertStats(2, 600)
+
+	// Justice transactions of altruist sessions should only be counted.
+	confirm(altruistSession)
+	assertStats(3, 600)
+}
@@ -0,0 +1,27 @@
+// +build !monitoring
+
+package watchtower
+
+import "net"
+
+// metricsServer is a stub of the tower's metrics server, used in builds
+// without the monitoring tag.
+type metricsServer struct{}
+
+// newMetricsServer returns ErrMetricsUnsupported, as the tower's metrics can't
+// be served without the monitoring tag.
+func newMetricsServer(net.Addr,
+	func() (*Stats, error)) (*metricsServer, error) {
+
+	return nil, ErrMetricsUnsupported
+}
+
+// Start is a no-op.
+func (m *metricsServer) Start() error {
+	return nil
+}
+
+// Stop is a no-op.
+func (m *metricsServer) Stop() error {
+	return nil
+}
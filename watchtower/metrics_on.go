@@ -0,0 +1,189 @@
+// +build monitoring
+
+package watchtower
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsNamespace prefixes the names of all metrics exported by the tower.
+const metricsNamespace = "watchtower"
+
+// metricsCollector is a prometheus.Collector exporting a snapshot of the
+// tower's Stats each time the metrics are scraped.
+type metricsCollector struct {
+	stats func() (*Stats, error)
+
+	numSessions      *prometheus.Desc
+	numBlobs         *prometheus.Desc
+	blobBytes        *prometheus.Desc
+	breachesDetected *prometheus.Desc
+	justicePublished *prometheus.Desc
+	justiceConfirmed *prometheus.Desc
+	sessionRewards   *prometheus.Desc
+}
+
+// newMetricsCollector creates a metricsCollector exporting the Stats returned
+// by the given closure.
+func newMetricsCollector(stats func() (*Stats, error)) *metricsCollector {
+	newDesc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", name),
+			help, labels, nil,
+		)
+	}
+
+	return &metricsCollector{
+		stats: stats,
+		numSessions: newDesc(
+			"sessions", "Number of sessions negotiated with "+
+				"clients.",
+		),
+		numBlobs: newDesc(
+			"blobs_stored", "Number of encrypted justice blobs "+
+				"stored across all sessions.",
+		),
+		blobBytes: newDesc(
+			"blobs_stored_bytes", "Total size of all stored "+
+				"encrypted justice blobs.",
+		),
+		breachesDetected: newDesc(
+			"breaches_detected_total", "Number of breaches for "+
+				"which a client's justice blob was decrypted.",
+		),
+		justicePublished: newDesc(
+			"justice_txns_broadcast_total", "Number of justice "+
+				"transactions broadcast.",
+		),
+		justiceConfirmed: newDesc(
+			"justice_txns_confirmed_total", "Number of broadcast "+
+				"justice transactions that confirmed.",
+		),
+		sessionRewards: newDesc(
+			"session_rewards_sat_total", "Total reward in "+
+				"satoshis earned by the confirmed justice "+
+				"transactions of a session.", "session_id",
+		),
+	}
+}
+
+// Describe sends the descriptors of all metrics exported by the collector.
+//
+// NOTE: Part of the prometheus.Collector interface.
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.numSessions
+	ch <- c.numBlobs
+	ch <- c.blobBytes
+	ch <- c.breachesDetected
+	ch <- c.justicePublished
+	ch <- c.justiceConfirmed
+	ch <- c.sessionRewards
+}
+
+// Collect sends the current value of all metrics exported by the collector.
+//
+// NOTE: Part of the prometheus.Collector interface.
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.stats()
+	if err != nil {
+		log.Errorf("Unable to collect watchtower metrics: %v", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.numSessions, prometheus.GaugeValue,
+		float64(stats.Storage.NumSessions),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.numBlobs, prometheus.GaugeValue,
+		float64(stats.Storage.NumUpdates),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.blobBytes, prometheus.GaugeValue,
+		float64(stats.Storage.UpdateBytes),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.breachesDetected, prometheus.CounterValue,
+		float64(stats.Breaches.MatchedBreaches),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.justicePublished, prometheus.CounterValue,
+		float64(stats.Justice.PublishedJustice),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.justiceConfirmed, prometheus.CounterValue,
+		float64(stats.Justice.ConfirmedJustice),
+	)
+	for id, reward := range stats.Justice.Rewards {
+		ch <- prometheus.MustNewConstMetric(
+			c.sessionRewards, prometheus.CounterValue,
+			float64(reward), id.String(),
+		)
+	}
+}
+
+// metricsServer serves the tower's metrics over HTTP for Prometheus to scrape.
+type metricsServer struct {
+	addr   net.Addr
+	server *http.Server
+
+	wg sync.WaitGroup
+}
+
+// newMetricsServer creates a metricsServer listening on the given address,
+// exporting the Stats returned by the given closure.
+func newMetricsServer(addr net.Addr,
+	stats func() (*Stats, error)) (*metricsServer, error) {
+
+	registry := prometheus.NewRegistry()
+	err := registry.Register(newMetricsCollector(stats))
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(
+		registry, promhttp.HandlerOpts{},
+	))
+
+	return &metricsServer{
+		addr:   addr,
+		server: &http.Server{Handler: mux},
+	}, nil
+}
+
+// Start begins serving metrics on the server's listening address.
+func (m *metricsServer) Start() error {
+	listener, err := net.Listen("tcp", m.addr.String())
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Serving watchtower metrics on %v", listener.Addr())
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		err := m.server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			log.Errorf("Unable to serve watchtower metrics: %v",
+				err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop closes the server's listener and all active connections.
+func (m *metricsServer) Stop() error {
+	err := m.server.Close()
+	m.wg.Wait()
+
+	return err
+}
@@ -0,0 +1,106 @@
+// +build monitoring
+
+package watchtower
+
+import (
+	"testing"
+
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/watchtower/lookout"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestMetricsCollector asserts that the metrics exported by the collector
+// reflect the tower's Stats at the time they're gathered.
+func TestMetricsCollector(t *testing.T) {
+	t.Parallel()
+
+	sessionID := wtdb.SessionID{0x01}
+	stats := &Stats{
+		Storage: &wtdb.TowerStats{
+			NumSessions: 3,
+			NumUpdates:  10,
+			UpdateBytes: 2048,
+		},
+		Breaches: lookout.Stats{
+			MatchedBreaches: 2,
+		},
+		Justice: lookout.PunisherStats{
+			PublishedJustice: 2,
+			ConfirmedJustice: 1,
+			Rewards: map[wtdb.SessionID]btcutil.Amount{
+				sessionID: 1500,
+			},
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	err := registry.Register(newMetricsCollector(
+		func() (*Stats, error) {
+			return stats, nil
+		},
+	))
+	if err != nil {
+		t.Fatalf("unable to register collector: %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unable to gather metrics: %v", err)
+	}
+
+	expValues := map[string]float64{
+		"watchtower_sessions":                     3,
+		"watchtower_blobs_stored":                 10,
+		"watchtower_blobs_stored_bytes":           2048,
+		"watchtower_breaches_detected_total":      2,
+		"watchtower_justice_txns_broadcast_total": 2,
+		"watchtower_justice_txns_confirmed_total": 1,
+		"watchtower_session_rewards_sat_total":    1500,
+	}
+	if len(families) != len(expValues) {
+		t.Fatalf("expected %d metrics, got %d", len(expValues),
+			len(families))
+	}
+
+	for _, family := range families {
+		expValue, ok := expValues[family.GetName()]
+		if !ok {
+			t.Fatalf("unexpected metric %v", family.GetName())
+		}
+
+		metrics := family.GetMetric()
+		if len(metrics) != 1 {
+			t.Fatalf("expected one sample of %v, got %d",
+				family.GetName(), len(metrics))
+		}
+
+		var value float64
+		switch {
+		case metrics[0].GetGauge() != nil:
+			value = metrics[0].GetGauge().GetValue()
+		case metrics[0].GetCounter() != nil:
+			value = metrics[0].GetCounter().GetValue()
+		}
+		if value != expValue {
+			t.Fatalf("expected %v to be %v, got %v",
+				family.GetName(), expValue, value)
+		}
+	}
+
+	// The per-session rewards should be labeled by the session id.
+	for _, family := range families {
+		if family.GetName() != "watchtower_session_rewards_sat_total" {
+			continue
+		}
+
+		labels := family.GetMetric()[0].GetLabel()
+		if len(labels) != 1 ||
+			labels[0].GetValue() != sessionID.String() {
+
+			t.Fatalf("unexpected session reward labels: %v",
+				labels)
+		}
+	}
+}
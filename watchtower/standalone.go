@@ -2,13 +2,41 @@ package watchtower
 
 import (
 	"net"
+	"sort"
 	"sync/atomic"
+	"time"
 
+	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/lnd/brontide"
 	"github.com/litecoinfinance/lnd/watchtower/lookout"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
+	"github.com/litecoinfinance/lnd/watchtower/wtpolicy"
 	"github.com/litecoinfinance/lnd/watchtower/wtserver"
 )
 
+// Stats summarizes the state of a watchtower.
+type Stats struct {
+	// Storage summarizes the resources occupied by the tower's clients.
+	Storage *wtdb.TowerStats
+
+	// Breaches summarizes the breaches handled since the tower was
+	// started.
+	Breaches lookout.Stats
+
+	// Justice summarizes the justice transactions published since the
+	// tower was started, and the rewards earned by them.
+	Justice lookout.PunisherStats
+}
+
+// SessionPolicy describes a policy negotiated by the tower's clients, along
+// with the number of sessions using it.
+type SessionPolicy struct {
+	wtpolicy.Policy
+
+	// NumSessions is the number of sessions negotiated under the policy.
+	NumSessions uint64
+}
+
 // Standalone encapsulates the server-side functionality required by watchtower
 // clients. A Standalone couples the two primary subsystems such that, as a
 // unit, this instance can negotiate sessions with clients, accept state updates
@@ -22,12 +50,19 @@ type Standalone struct {
 
 	// server is the client endpoint, used for negotiating sessions and
 	// uploading state updates.
-	server wtserver.Interface
+	server *wtserver.Server
 
 	// lookout is a service that monitors the chain and inspects the
 	// transactions found in new blocks against the state updates received
 	// by the server.
-	lookout lookout.Service
+	lookout *lookout.Lookout
+
+	// punisher publishes the justice transactions of the breaches found
+	// by the lookout.
+	punisher *lookout.BreachPunisher
+
+	// metrics serves the tower's metrics, if enabled.
+	metrics *metricsServer
 }
 
 // New validates the passed Config and returns a fresh Standalone instance if
@@ -49,8 +84,30 @@ func New(cfg *Config) (*Standalone, error) {
 		cfg.WriteTimeout = DefaultWriteTimeout
 	}
 
+	// Assign the default eviction depth if none is provided.
+	if cfg.EvictionDepth == 0 {
+		cfg.EvictionDepth = DefaultEvictionDepth
+	}
+
+	// Assign the default fee bump interval and increase if none are
+	// provided.
+	if cfg.FeeBump.Interval == 0 {
+		cfg.FeeBump.Interval = lookout.DefaultFeeBumpInterval
+	}
+	if cfg.FeeBump.IncreasePercent == 0 {
+		cfg.FeeBump.IncreasePercent = lookout.DefaultFeeBumpPercent
+	}
+
+	// Enforce the configured storage quota on all state updates received
+	// from now on.
+	cfg.DB.SetQuota(cfg.Quota)
+
 	punisher := lookout.NewBreachPunisher(&lookout.PunisherConfig{
-		PublishTx: cfg.PublishTx,
+		PublishTx:      cfg.PublishTx,
+		BumpFee:        cfg.BumpFee,
+		ConfRegistrar:  cfg.ConfRegistrar,
+		EpochRegistrar: cfg.EpochRegistrar,
+		FeeBump:        cfg.FeeBump,
 	})
 
 	// Initialize the lookout service with its required resources.
@@ -59,6 +116,9 @@ func New(cfg *Config) (*Standalone, error) {
 		DB:             cfg.DB,
 		EpochRegistrar: cfg.EpochRegistrar,
 		Punisher:       punisher,
+		EvictionDepth:  cfg.EvictionDepth,
+		NodePrivKey:    cfg.NodePrivKey,
+		BlockBatchSize: lookout.DefaultBlockBatchSize,
 	})
 
 	// Create a brontide listener on each of the provided listening
@@ -78,23 +138,38 @@ func New(cfg *Config) (*Standalone, error) {
 
 	// Initialize the server with its required resources.
 	server, err := wtserver.New(&wtserver.Config{
-		ChainHash:    cfg.ChainHash,
-		DB:           cfg.DB,
-		NodePrivKey:  cfg.NodePrivKey,
-		Listeners:    listeners,
-		ReadTimeout:  cfg.ReadTimeout,
-		WriteTimeout: cfg.WriteTimeout,
-		NewAddress:   cfg.NewAddress,
+		ChainHash:       cfg.ChainHash,
+		DB:              cfg.DB,
+		NodePrivKey:     cfg.NodePrivKey,
+		Listeners:       listeners,
+		ReadTimeout:     cfg.ReadTimeout,
+		WriteTimeout:    cfg.WriteTimeout,
+		NewAddress:      cfg.NewAddress,
+		MinSweepFeeRate: cfg.MinSweepFeeRate,
+		PolicyBounds:    cfg.PolicyBounds,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &Standalone{
-		cfg:     cfg,
-		server:  server,
-		lookout: lookout,
-	}, nil
+	tower := &Standalone{
+		cfg:      cfg,
+		server:   server,
+		lookout:  lookout,
+		punisher: punisher,
+	}
+
+	// Export the tower's metrics if a listening address was provided.
+	if cfg.MetricsListenAddr != nil {
+		tower.metrics, err = newMetricsServer(
+			cfg.MetricsListenAddr, tower.Stats,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tower, nil
 }
 
 // Start idempotently starts the Standalone, an error is returned if the
@@ -113,6 +188,13 @@ func (w *Standalone) Start() error {
 		w.lookout.Stop()
 		return err
 	}
+	if w.metrics != nil {
+		if err := w.metrics.Start(); err != nil {
+			w.server.Stop()
+			w.lookout.Stop()
+			return err
+		}
+	}
 
 	log.Infof("Watchtower started successfully")
 
@@ -128,6 +210,9 @@ func (w *Standalone) Stop() error {
 
 	log.Infof("Stopping watchtower")
 
+	if w.metrics != nil {
+		w.metrics.Stop()
+	}
 	w.server.Stop()
 	w.lookout.Stop()
 
@@ -135,3 +220,124 @@ func (w *Standalone) Stop() error {
 
 	return nil
 }
+
+// PubKey returns the public key clients use to authenticate the tower.
+func (w *Standalone) PubKey() *btcec.PublicKey {
+	return w.cfg.NodePrivKey.PubKey()
+}
+
+// ListenAddrs returns the addresses on which the tower accepts clients.
+func (w *Standalone) ListenAddrs() []net.Addr {
+	return w.cfg.ListenAddrs
+}
+
+// Sessions returns a summary of every session negotiated with a client.
+func (w *Standalone) Sessions() ([]*wtdb.SessionSummary, error) {
+	return w.cfg.DB.ListSessions()
+}
+
+// Stats returns a summary of the resources occupied by the tower's clients,
+// and the breaches handled since the tower was started.
+func (w *Standalone) Stats() (*Stats, error) {
+	storage, err := w.cfg.DB.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stats{
+		Storage:  storage,
+		Breaches: w.lookout.Stats(),
+		Justice:  w.punisher.Stats(),
+	}, nil
+}
+
+// BlacklistClient refuses any further connections from the client with the
+// given session id, dropping its connection if it is currently connected. The
+// client's existing sessions continue to be watched.
+func (w *Standalone) BlacklistClient(id wtdb.SessionID) error {
+	if err := w.cfg.DB.BlacklistClient(id); err != nil {
+		return err
+	}
+
+	log.Infof("Blacklisted client %s", id)
+
+	w.server.DisconnectClient(&id)
+
+	return nil
+}
+
+// UnblacklistClient allows a previously blacklisted client to connect to the
+// tower once again.
+func (w *Standalone) UnblacklistClient(id wtdb.SessionID) error {
+	if err := w.cfg.DB.UnblacklistClient(id); err != nil {
+		return err
+	}
+
+	log.Infof("Removed client %s from blacklist", id)
+
+	return nil
+}
+
+// BlacklistedClients returns the session ids of all blacklisted clients.
+func (w *Standalone) BlacklistedClients() ([]wtdb.SessionID, error) {
+	return w.cfg.DB.ListBlacklistedClients()
+}
+
+// PruneInactiveSessions deletes all sessions that have been inactive for
+// longer than the given duration, returning the ids of the deleted sessions.
+func (w *Standalone) PruneInactiveSessions(
+	inactivity time.Duration) ([]wtdb.SessionID, error) {
+
+	pruned, err := w.cfg.DB.PruneInactiveSessions(
+		time.Now().Add(-inactivity),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Pruned %d sessions inactive for more than %v",
+		len(pruned), inactivity)
+
+	return pruned, nil
+}
+
+// PolicyBounds returns the bounds currently restricting the policies of new
+// sessions.
+func (w *Standalone) PolicyBounds() wtserver.PolicyBounds {
+	return w.server.PolicyBounds()
+}
+
+// SetPolicyBounds replaces the bounds restricting the policies of new
+// sessions, allowing the operator to react to changes in the fee market
+// without restarting the tower. Existing sessions continue to be honored.
+func (w *Standalone) SetPolicyBounds(bounds wtserver.PolicyBounds) error {
+	return w.server.SetPolicyBounds(bounds)
+}
+
+// SessionPolicies returns the distinct policies negotiated by the tower's
+// clients, ordered by descending number of sessions.
+func (w *Standalone) SessionPolicies() ([]*SessionPolicy, error) {
+	sessions, err := w.cfg.DB.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	policyIndex := make(map[wtpolicy.Policy]*SessionPolicy)
+	var policies []*SessionPolicy
+	for _, session := range sessions {
+		policy, ok := policyIndex[session.Policy]
+		if !ok {
+			policy = &SessionPolicy{Policy: session.Policy}
+			policyIndex[session.Policy] = policy
+			policies = append(policies, policy)
+		}
+
+		policy.NumSessions++
+	}
+
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].NumSessions > policies[j].NumSessions
+	})
+
+	return policies, nil
+}
@@ -0,0 +1,167 @@
+package wtclient
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
+	"github.com/litecoinfinance/lnd/watchtower/wtpolicy"
+)
+
+// SessionStats accounts for the backups made within a single session.
+type SessionStats struct {
+	// ID is the session's id, i.e. the client's public key used to
+	// authenticate with the tower.
+	ID wtdb.SessionID
+
+	// Policy holds the negotiated session parameters.
+	Policy wtpolicy.Policy
+
+	// NumSent is the number of backups committed to the session, which
+	// have been or are about to be sent to the tower.
+	NumSent uint16
+
+	// NumAcked is the number of backups the tower has acked.
+	NumAcked uint16
+
+	// NumPending is the number of committed backups that have yet to be
+	// acked by the tower.
+	NumPending uint16
+
+	// ValueProtected is an estimate of the on-chain value the tower could
+	// recover through the session's acked backups. For each channel, the
+	// value of the revoked outputs of its most recent acked state is
+	// counted, since only one of a channel's revoked states can confirm.
+	ValueProtected btcutil.Amount
+}
+
+// TowerStats accounts for the backups made to a single tower, across all of
+// the sessions negotiated with it.
+type TowerStats struct {
+	// PubKey is the identity public key of the tower.
+	PubKey *btcec.PublicKey
+
+	// Sessions holds the stats of each session negotiated with the tower,
+	// ordered by session id.
+	Sessions []*SessionStats
+
+	// NumSent, NumAcked and NumPending are the totals of the tower's
+	// sessions.
+	NumSent    uint32
+	NumAcked   uint32
+	NumPending uint32
+
+	// ValueProtected is an estimate of the on-chain value the tower could
+	// recover across all of its sessions. Like for a single session, only
+	// the most recent acked state of each channel is counted.
+	ValueProtected btcutil.Amount
+}
+
+// ackedState is the most recent revoked state of a channel acked by a tower,
+// along with the value of its revoked outputs.
+type ackedState struct {
+	height uint64
+	amount btcutil.Amount
+}
+
+// recordAcked updates the given set of most recent acked states with the acked
+// updates of the session.
+func recordAcked(states map[lnwire.ChannelID]ackedState,
+	s *wtdb.ClientSession) {
+
+	for seqNum, backupID := range s.AckedUpdates {
+		state, ok := states[backupID.ChanID]
+		if ok && state.height >= backupID.CommitHeight {
+			continue
+		}
+
+		states[backupID.ChanID] = ackedState{
+			height: backupID.CommitHeight,
+			amount: s.AckedAmounts[seqNum],
+		}
+	}
+}
+
+// valueProtected sums the values of the given acked states.
+func valueProtected(states map[lnwire.ChannelID]ackedState) btcutil.Amount {
+	var total btcutil.Amount
+	for _, state := range states {
+		total += state.amount
+	}
+
+	return total
+}
+
+// SessionStats returns the accounting of the backups made to each tower the
+// client has negotiated sessions with, including exhausted sessions, ordered
+// by the towers' public keys.
+func (c *TowerClient) SessionStats() ([]*TowerStats, error) {
+	sessions, err := c.cfg.DB.ListAllClientSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	towers := make(map[uint64]*TowerStats)
+	towerAcked := make(map[uint64]map[lnwire.ChannelID]ackedState)
+	for _, s := range sessions {
+		tower, ok := towers[s.TowerID]
+		if !ok {
+			dbTower, err := c.cfg.DB.LoadTower(s.TowerID)
+			if err != nil {
+				return nil, err
+			}
+
+			tower = &TowerStats{
+				PubKey: dbTower.IdentityKey,
+			}
+			towers[s.TowerID] = tower
+			towerAcked[s.TowerID] = make(
+				map[lnwire.ChannelID]ackedState,
+			)
+		}
+
+		sessionAcked := make(map[lnwire.ChannelID]ackedState)
+		recordAcked(sessionAcked, s)
+		recordAcked(towerAcked[s.TowerID], s)
+
+		stats := &SessionStats{
+			ID:             s.ID,
+			Policy:         s.Policy,
+			NumSent:        s.SeqNum,
+			NumAcked:       uint16(len(s.AckedUpdates)),
+			NumPending:     uint16(len(s.CommittedUpdates)),
+			ValueProtected: valueProtected(sessionAcked),
+		}
+
+		tower.Sessions = append(tower.Sessions, stats)
+		tower.NumSent += uint32(stats.NumSent)
+		tower.NumAcked += uint32(stats.NumAcked)
+		tower.NumPending += uint32(stats.NumPending)
+	}
+
+	towerStats := make([]*TowerStats, 0, len(towers))
+	for towerID, tower := range towers {
+		tower.ValueProtected = valueProtected(towerAcked[towerID])
+
+		towerSessions := tower.Sessions
+		sort.Slice(towerSessions, func(i, j int) bool {
+			return bytes.Compare(
+				towerSessions[i].ID[:], towerSessions[j].ID[:],
+			) < 0
+		})
+
+		towerStats = append(towerStats, tower)
+	}
+
+	sort.Slice(towerStats, func(i, j int) bool {
+		return bytes.Compare(
+			towerStats[i].PubKey.SerializeCompressed(),
+			towerStats[j].PubKey.SerializeCompressed(),
+		) < 0
+	})
+
+	return towerStats, nil
+}
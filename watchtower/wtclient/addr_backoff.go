@@ -0,0 +1,110 @@
+package wtclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/litecoinfinance/lnd/lnwire"
+)
+
+// addrRetry records the backoff of a tower address that could not be dialed.
+type addrRetry struct {
+	// backoff is the duration the address was last backed off for.
+	backoff time.Duration
+
+	// retryAt is the earliest time at which the address may be dialed
+	// again.
+	retryAt time.Time
+}
+
+// addrBackoff tracks the exponential backoff of each tower address the client
+// failed to dial. Unreachable addresses are skipped while backed off, such that
+// the client fails over to the tower's other addresses without waiting for the
+// unreachable ones to time out again. It is shared by the client's session
+// negotiators and session queues, and is safe for concurrent use.
+type addrBackoff struct {
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu    sync.Mutex
+	addrs map[string]*addrRetry
+}
+
+// newAddrBackoff creates an addrBackoff whose backoff starts at minBackoff and
+// doubles with each failed dial, up until maxBackoff.
+func newAddrBackoff(minBackoff, maxBackoff time.Duration) *addrBackoff {
+	return &addrBackoff{
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+		addrs:      make(map[string]*addrRetry),
+	}
+}
+
+// available returns the addresses among addrs that aren't currently backed off,
+// preserving their order. If all of them are backed off, the duration until the
+// first of them can be retried is returned instead.
+func (b *addrBackoff) available(
+	addrs []*lnwire.NetAddress) ([]*lnwire.NetAddress, time.Duration) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var (
+		now       = time.Now()
+		available = make([]*lnwire.NetAddress, 0, len(addrs))
+		retryIn   time.Duration
+	)
+	for _, addr := range addrs {
+		retry, ok := b.addrs[addr.String()]
+		if !ok || !now.Before(retry.retryAt) {
+			available = append(available, addr)
+			continue
+		}
+
+		wait := retry.retryAt.Sub(now)
+		if retryIn == 0 || wait < retryIn {
+			retryIn = wait
+		}
+	}
+
+	if len(available) > 0 {
+		return available, 0
+	}
+
+	return nil, retryIn
+}
+
+// failed backs off the given address after a failed dial, doubling its prior
+// backoff.
+func (b *addrBackoff) failed(addr *lnwire.NetAddress) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := addr.String()
+	retry, ok := b.addrs[key]
+	switch {
+	case !ok:
+		retry = &addrRetry{backoff: b.minBackoff}
+		b.addrs[key] = retry
+
+	default:
+		retry.backoff *= 2
+		if retry.backoff > b.maxBackoff {
+			retry.backoff = b.maxBackoff
+		}
+	}
+
+	retry.retryAt = time.Now().Add(retry.backoff)
+}
+
+// reset clears the backoff of the given addresses, such that they are dialed
+// on the next attempt. This is used after a successful dial, as well as when
+// the addresses of a tower are replaced.
+func (b *addrBackoff) reset(addrs ...*lnwire.NetAddress) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, addr := range addrs {
+		delete(b.addrs, addr.String())
+	}
+}
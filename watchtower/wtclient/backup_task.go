@@ -14,6 +14,10 @@ import (
 	"github.com/litecoinfinance/lnd/watchtower/wtdb"
 )
 
+// htlcRiskWeight is the amount by which each HTLC of a revoked state raises
+// the risk score of its backup.
+const htlcRiskWeight btcutil.Amount = 10000
+
 // backupTask is an internal struct for computing the justice transaction for a
 // particular revoked state. A backupTask functions as a scratch pad for storing
 // computing values of the transaction itself, such as the final split in
@@ -46,8 +50,9 @@ type backupTask struct {
 
 	// session-dependent variables
 
-	blobType blob.Type
-	outputs  []*wire.TxOut
+	blobType      blob.Type
+	outputs       []*wire.TxOut
+	sessionSecret [32]byte
 }
 
 // newBackupTask initializes a new backupTask and populates all state-dependent
@@ -107,6 +112,16 @@ func newBackupTask(chanID *lnwire.ChannelID,
 	}
 }
 
+// riskScore estimates how dangerous it would be for the task's revoked state
+// to be broadcast before it's backed up, such that riskier states can be
+// backed up first. The score is the balance the justice transaction would
+// recover, plus htlcRiskWeight for each HTLC of the state, as HTLCs shorten
+// the time available to respond to the breach once they expire.
+func (t *backupTask) riskScore() int64 {
+	numHTLCs := int64(len(t.breachInfo.HtlcRetributions))
+	return int64(t.totalAmt) + numHTLCs*int64(htlcRiskWeight)
+}
+
 // inputs returns all non-dust inputs that we will attempt to spend from.
 //
 // NOTE: Ordering of the inputs is not critical as we sort the transaction with
@@ -139,7 +154,15 @@ func (t *backupTask) bindSession(session *wtdb.ClientSession) error {
 		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize)
 	}
 	if t.toRemoteInput != nil {
-		weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
+		// The to-remote output of anchor channels is a p2wsh output
+		// that requires a confirmation, rather than a p2wkh output.
+		if session.Policy.IsAnchorChannel() {
+			weightEstimate.AddWitnessInput(
+				input.ToRemoteConfirmedWitnessSize,
+			)
+		} else {
+			weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
+		}
 	}
 
 	// All justice transactions have a p2wkh output paying to the victim.
@@ -161,12 +184,43 @@ func (t *backupTask) bindSession(session *wtdb.ClientSession) error {
 		return err
 	}
 
+	// The key of versioned blobs is derived from the secret shared by the
+	// session key and the tower.
+	var sessionSecret [32]byte
+	if session.Policy.BlobType.Has(blob.FlagVersioned) {
+		if session.SessionPrivKey == nil || session.Tower == nil {
+			return ErrNoSessionSecret
+		}
+
+		sessionSecret = blob.SessionSecret(
+			session.SessionPrivKey, session.Tower.IdentityKey,
+		)
+	}
+
 	t.blobType = session.Policy.BlobType
 	t.outputs = outputs
+	t.sessionSecret = sessionSecret
 
 	return nil
 }
 
+// inputSequence returns the sequence the justice transaction's input spending
+// the given input must commit to. The tower reconstructs the justice
+// transaction with the same sequences, such that our signatures remain valid.
+// The to-remote output of anchor channels can only be spent after a
+// confirmation, which its input must signal through a relative lock time of
+// one block. The input's sign descriptor is expected to commit to the output's
+// witness script in that case.
+func (t *backupTask) inputSequence(inp input.Input) uint32 {
+	if inp.WitnessType() == input.CommitmentNoDelay &&
+		t.blobType.Has(blob.FlagAnchorChannel) {
+
+		return 1
+	}
+
+	return 0
+}
+
 // craftSessionPayload is the final stage for a backupTask, and generates the
 // encrypted payload and breach hint that should be sent to the tower. This
 // method computes the final justice transaction using the bound
@@ -206,9 +260,10 @@ func (t *backupTask) craftSessionPayload(
 	// information. This will either be contain both the to-local and
 	// to-remote outputs, or only be the to-local output.
 	inputs := t.inputs()
-	for prevOutPoint := range inputs {
+	for prevOutPoint, inp := range inputs {
 		justiceTxn.AddTxIn(&wire.TxIn{
 			PreviousOutPoint: prevOutPoint,
+			Sequence:         t.inputSequence(inp),
 		})
 	}
 
@@ -280,10 +335,15 @@ func (t *backupTask) craftSessionPayload(
 	// Compute the breach hint from the breach transaction id's prefix.
 	breachKey := t.breachInfo.BreachTransaction.TxHash()
 
-	// Then, we'll encrypt the computed justice kit using the full breach
-	// transaction id, which will allow the tower to recover the contents
-	// after the transaction is seen in the chain or mempool.
-	encBlob, err := justiceKit.Encrypt(breachKey[:], t.blobType)
+	// Then, we'll encrypt the computed justice kit using a key derived
+	// from the full breach transaction id, which will allow the tower to
+	// recover the contents after the transaction is seen in the chain or
+	// mempool.
+	encKey, err := blob.DeriveKey(t.blobType, breachKey, t.sessionSecret)
+	if err != nil {
+		return hint, nil, err
+	}
+	encBlob, err := justiceKit.Encrypt(encKey, t.blobType)
 	if err != nil {
 		return hint, nil, err
 	}
@@ -224,6 +224,9 @@ var (
 
 	blobTypeCommitReward = (blob.FlagCommitOutputs | blob.FlagReward).Type()
 
+	blobTypeCommitAnchor = (blob.FlagCommitOutputs |
+		blob.FlagAnchorChannel).Type()
+
 	addr, _ = btcutil.DecodeAddress(
 		"mrX9vMRYLfVy1BnZbc5gZjuyaqH3ZW2ZHz", &chaincfg.TestNet3Params,
 	)
@@ -376,6 +379,30 @@ var backupTaskTests = []backupTaskTest{
 		0,                       // expRewardAmt
 		wtpolicy.ErrCreatesDust, // bindErr
 	),
+	genTaskTest(
+		"commit anchor no-reward, both outputs",
+		100,                  // stateNum
+		200000,               // toLocalAmt
+		100000,               // toRemoteAmt
+		blobTypeCommitAnchor, // blobType
+		1000,                 // sweepFeeRate
+		nil,                  // rewardScript
+		299237,               // expSweepAmt
+		0,                    // expRewardAmt
+		nil,                  // bindErr
+	),
+	genTaskTest(
+		"commit anchor no-reward, to-remote output only",
+		1,                    // stateNum
+		0,                    // toLocalAmt
+		100000,               // toRemoteAmt
+		blobTypeCommitAnchor, // blobType
+		1000,                 // sweepFeeRate
+		nil,                  // rewardScript
+		99557,                // expSweepAmt
+		0,                    // expRewardAmt
+		nil,                  // bindErr
+	),
 }
 
 // TestBackupTaskBind tests the initialization and binding of a backupTask to a
@@ -3,14 +3,18 @@ package wtclient
 import (
 	"bytes"
 	"fmt"
+	"net"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcutil"
 	"github.com/litecoinfinance/lnd/input"
 	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/lnwire"
+	"github.com/litecoinfinance/lnd/watchtower/blob"
 	"github.com/litecoinfinance/lnd/watchtower/wtdb"
 	"github.com/litecoinfinance/lnd/watchtower/wtpolicy"
 	"github.com/litecoinfinance/lnd/watchtower/wtserver"
@@ -59,6 +63,24 @@ type Client interface {
 	// ForceQuit will forcibly shutdown the watchtower client. Calling this
 	// may lead to queued states being dropped.
 	ForceQuit()
+
+	// ListSessions returns the status of all sessions negotiated by the
+	// client that have not yet been exhausted.
+	ListSessions() ([]*SessionStatus, error)
+
+	// SessionStats returns the accounting of the backups made to each
+	// tower, across all sessions negotiated with it.
+	SessionStats() ([]*TowerStats, error)
+
+	// BackedUpHeight returns the highest commit height of the channel's
+	// revoked states that has been acked by a tower. The boolean is false
+	// if none of the channel's states are known to be backed up.
+	BackedUpHeight(lnwire.ChannelID) (uint64, bool)
+
+	// UpdateTowerAddresses replaces the addresses of the tower with the
+	// given public key, in order of preference. The tower's sessions
+	// remain usable, and are resumed at the new addresses.
+	UpdateTowerAddresses(*btcec.PublicKey, []net.Addr) error
 }
 
 // Config provides the TowerClient with access to the resources it requires to
@@ -89,16 +111,63 @@ type Config struct {
 	// DB provides access to the client's stable storage medium.
 	DB DB
 
+	// BuildBreachRetribution reconstructs the breach retribution of the
+	// revoked state identified by the channel id and commit height. It is
+	// used on startup to replay backups that were queued, but not yet
+	// committed to a session, before the client was last shut down. If
+	// nil, such backups are retained but not replayed.
+	BuildBreachRetribution func(lnwire.ChannelID,
+		uint64) (*lnwallet.BreachRetribution, error)
+
 	// Policy is the session policy the client will propose when creating
 	// new sessions with the tower. If the policy differs from any active
 	// sessions recorded in the database, those sessions will be ignored and
 	// new sessions will be requested immediately.
 	Policy wtpolicy.Policy
 
+	// MaxRewardBase and MaxRewardRate cap the reward the client is willing
+	// to pay a tower if Policy specifies a reward session. Towers requiring
+	// a higher reward than the one proposed in Policy will be offered the
+	// reward they require, as long as it doesn't exceed these caps. Caps
+	// below the reward proposed in Policy are raised to match it.
+	MaxRewardBase uint32
+	MaxRewardRate uint32
+
+	// ChannelPolicies override the Policy used to back up the states of
+	// specific channels or classes of channels. Each channel is backed up
+	// to sessions negotiated under the policy of the first ChannelPolicy
+	// it matches, or under Policy if it matches none. The reward caps
+	// apply to these policies as well.
+	ChannelPolicies []ChannelPolicy
+
+	// ChannelCapacity returns the capacity of the given channel. It must
+	// be set if any of the ChannelPolicies specifies a MinCapacity.
+	ChannelCapacity func(lnwire.ChannelID) (btcutil.Amount, error)
+
+	// IsAnchorChannel returns whether the given channel uses anchor
+	// outputs. If set, the client backs up the states of anchor channels
+	// under a variant of their policy with FlagAnchorChannel set, and
+	// those of all other channels under a variant without it. Sessions of
+	// both blob types are negotiated with the same towers, and used at
+	// once. If nil, channels are backed up under their policy as is.
+	IsAnchorChannel func(lnwire.ChannelID) (bool, error)
+
 	// PrivateTower is the net address of a private tower. The client will
 	// try to create all sessions with this tower.
 	PrivateTower *lnwire.NetAddress
 
+	// BackupTowers are the net addresses of towers the client will fall
+	// back to, in the given order, if a session can't be negotiated with
+	// the PrivateTower.
+	BackupTowers []*lnwire.NetAddress
+
+	// ReplacementThreshold is the number of remaining slots in the active
+	// session at which the client begins negotiating a replacement
+	// session in the background, such that backups aren't held up once
+	// the active session is exhausted. If zero, a replacement is only
+	// negotiated after the active session has been exhausted.
+	ReplacementThreshold uint16
+
 	// ChainHash identifies the chain that the client is on and for which
 	// the tower must be watching to monitor for breaches.
 	ChainHash chainhash.Hash
@@ -131,6 +200,134 @@ type Config struct {
 	MaxBackoff time.Duration
 }
 
+// ChannelPolicy assigns a session policy to a set of channels, such that their
+// states are backed up using different parameters than the client's default
+// policy, e.g. a higher sweep fee rate for large channels.
+type ChannelPolicy struct {
+	// ChanIDs are the channels to which the policy applies.
+	ChanIDs []lnwire.ChannelID
+
+	// MinCapacity, if non-zero, applies the policy to all channels with
+	// at least this capacity.
+	MinCapacity btcutil.Amount
+
+	// Policy is the session policy used to back up the matching channels.
+	Policy wtpolicy.Policy
+}
+
+// matches returns true if the policy applies to the given channel. The
+// channel's capacity is only queried if the policy selects channels by
+// capacity.
+func (p *ChannelPolicy) matches(chanID lnwire.ChannelID,
+	capacity func() (btcutil.Amount, error)) (bool, error) {
+
+	for _, id := range p.ChanIDs {
+		if id == chanID {
+			return true, nil
+		}
+	}
+
+	if p.MinCapacity == 0 {
+		return false, nil
+	}
+
+	chanCapacity, err := capacity()
+	if err != nil {
+		return false, err
+	}
+
+	return chanCapacity >= p.MinCapacity, nil
+}
+
+// commitTypePolicy returns the variant of the given policy used to back up
+// channels of the given commitment type, whose blob type has FlagAnchorChannel
+// set if and only if the channels use anchor outputs.
+func commitTypePolicy(policy wtpolicy.Policy, anchor bool) wtpolicy.Policy {
+	if anchor {
+		policy.BlobType |= blob.FlagAnchorChannel.Type()
+	} else {
+		policy.BlobType &^= blob.FlagAnchorChannel.Type()
+	}
+
+	return policy
+}
+
+// SessionStatus summarizes the state of a session negotiated by the client.
+type SessionStatus struct {
+	// ID is the session's id, i.e. the client's public key used to
+	// authenticate with the tower.
+	ID wtdb.SessionID
+
+	// TowerPubKey is the public key of the tower with which the session
+	// was negotiated.
+	TowerPubKey *btcec.PublicKey
+
+	// Policy holds the negotiated session parameters.
+	Policy wtpolicy.Policy
+
+	// NumBackups is the number of backups committed to the session.
+	NumBackups uint16
+
+	// NumPendingBackups is the number of committed backups that have yet
+	// to be acked by the tower.
+	NumPendingBackups uint16
+
+	// RemainingBackups is the number of additional backups the session
+	// can hold before it is exhausted.
+	RemainingBackups uint16
+}
+
+// policyDispatcher assigns the backups of the channels using a particular
+// session policy to sessions negotiated under that policy. Each dispatcher has
+// its own pipeline and negotiator, such that backups under one policy aren't
+// held up while a session is being negotiated under another.
+type policyDispatcher struct {
+	policy wtpolicy.Policy
+
+	// maxRewardBase and maxRewardRate are the client's reward caps, raised
+	// to the reward proposed in the policy if necessary.
+	maxRewardBase uint32
+	maxRewardRate uint32
+
+	pipeline *taskPipeline
+
+	negotiator        SessionNegotiator
+	candidateSessions map[wtdb.SessionID]*wtdb.ClientSession
+
+	sessionQueue *sessionQueue
+	prevTask     *backupTask
+
+	// negotiating is true while a session requested from the negotiator
+	// has yet to be received.
+	negotiating bool
+}
+
+// compatiblePolicy returns true if a session negotiated with the given policy
+// can be used by the dispatcher. Besides sessions matching the dispatcher's
+// policy, this includes reward sessions for which a tower required a higher
+// reward than proposed, as long as the reward is within the client's caps.
+func (d *policyDispatcher) compatiblePolicy(policy wtpolicy.Policy) bool {
+	if policy == d.policy {
+		return true
+	}
+
+	if !d.policy.BlobType.Has(blob.FlagReward) {
+		return false
+	}
+
+	if policy.RewardBase > d.maxRewardBase ||
+		policy.RewardRate > d.maxRewardRate {
+
+		return false
+	}
+
+	// Apart from the reward, the policy must match our own.
+	policy.RewardBase = d.policy.RewardBase
+	policy.RewardRate = d.policy.RewardRate
+
+	return policy == d.policy
+}
+
 // TowerClient is a concrete implementation of the Client interface, offering a
 // non-blocking, reliable subsystem for backing up revoked states to a specified
 // private tower.
@@ -141,18 +338,45 @@ type TowerClient struct {
 
 	cfg *Config
 
-	pipeline *taskPipeline
+	// dispatchers holds a policyDispatcher for the client's Policy,
+	// followed by one for each other distinct policy among the client's
+	// ChannelPolicies.
+	dispatchers []*policyDispatcher
 
-	negotiator        SessionNegotiator
-	candidateSessions map[wtdb.SessionID]*wtdb.ClientSession
+	// loadedSessions are all sessions loaded from the database on startup,
+	// irrespective of the dispatcher they were assigned to.
+	loadedSessions map[wtdb.SessionID]*wtdb.ClientSession
+
+	activeSessionsMtx sync.Mutex
 	activeSessions    sessionQueueSet
 
-	sessionQueue *sessionQueue
-	prevTask     *backupTask
+	// keyIndexMtx is shared by the negotiators of all dispatchers, which
+	// reserve session key indexes from the same DB.
+	keyIndexMtx sync.Mutex
+
+	// towers holds every tower known to the client by its ID. Each tower
+	// is shared by the candidate iterators and sessions referencing it,
+	// such that updating its addresses takes effect for all of them.
+	towersMtx sync.Mutex
+	towers    map[uint64]*wtdb.Tower
+
+	// addrBackoff tracks the backoff of the tower addresses the client
+	// failed to dial.
+	addrBackoff *addrBackoff
+
+	// chanDispatchers caches the dispatcher backing up each channel, such
+	// that the channel's capacity is only looked up once.
+	chanDispatchersMtx sync.Mutex
+	chanDispatchers    map[lnwire.ChannelID]*policyDispatcher
 
 	sweepPkScriptMu sync.RWMutex
 	sweepPkScripts  map[lnwire.ChannelID][]byte
 
+	// backedUpHeights records the highest commit height of each channel's
+	// revoked states acked by a tower.
+	backedUpMtx     sync.RWMutex
+	backedUpHeights map[lnwire.ChannelID]uint64
+
 	statTicker *time.Ticker
 	stats      clientStats
 
@@ -182,6 +406,14 @@ func New(config *Config) (*TowerClient, error) {
 		cfg.WriteTimeout = DefaultWriteTimeout
 	}
 
+	// Channel policies selecting channels by their capacity require us to
+	// be able to look it up.
+	for _, chanPolicy := range cfg.ChannelPolicies {
+		if chanPolicy.MinCapacity != 0 && cfg.ChannelCapacity == nil {
+			return nil, ErrNoChannelCapacity
+		}
+	}
+
 	// Record the tower in our database, also loading any addresses
 	// previously associated with its public key.
 	tower, err := cfg.DB.CreateTower(cfg.PrivateTower)
@@ -192,44 +424,104 @@ func New(config *Config) (*TowerClient, error) {
 	log.Infof("Using private watchtower %s, offering policy %s",
 		cfg.PrivateTower, cfg.Policy)
 
+	// Record any backup towers as well. These will only be used if a
+	// session can't be negotiated with the private tower.
+	candidates := []*wtdb.Tower{tower}
+	for _, addr := range cfg.BackupTowers {
+		backupTower, err := cfg.DB.CreateTower(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Infof("Using backup watchtower %s", addr)
+
+		candidates = append(candidates, backupTower)
+	}
+
 	c := &TowerClient{
-		cfg:            cfg,
-		pipeline:       newTaskPipeline(),
-		activeSessions: make(sessionQueueSet),
-		statTicker:     time.NewTicker(DefaultStatInterval),
-		forceQuit:      make(chan struct{}),
-	}
-	c.negotiator = newSessionNegotiator(&NegotiatorConfig{
-		DB:            cfg.DB,
-		SecretKeyRing: cfg.SecretKeyRing,
-		Policy:        cfg.Policy,
-		ChainHash:     cfg.ChainHash,
-		SendMessage:   c.sendMessage,
-		ReadMessage:   c.readMessage,
-		Dial:          c.dial,
-		Candidates:    newTowerListIterator(tower),
-		MinBackoff:    cfg.MinBackoff,
-		MaxBackoff:    cfg.MaxBackoff,
-	})
+		cfg:             cfg,
+		activeSessions:  make(sessionQueueSet),
+		chanDispatchers: make(map[lnwire.ChannelID]*policyDispatcher),
+		backedUpHeights: make(map[lnwire.ChannelID]uint64),
+		towers:          make(map[uint64]*wtdb.Tower),
+		addrBackoff:     newAddrBackoff(cfg.MinBackoff, cfg.MaxBackoff),
+		statTicker:      time.NewTicker(DefaultStatInterval),
+		forceQuit:       make(chan struct{}),
+	}
+	for _, candidate := range candidates {
+		c.towers[candidate.ID] = candidate
+	}
+
+	// Create a dispatcher for the default policy, and one for each other
+	// policy assigned to a class of channels.
+	c.dispatchers = append(
+		c.dispatchers, c.newPolicyDispatcher(cfg.Policy, candidates),
+	)
+	for _, chanPolicy := range cfg.ChannelPolicies {
+		if c.dispatcherForPolicy(chanPolicy.Policy) != nil {
+			continue
+		}
+
+		log.Infof("Offering policy %s for %d channels and channels "+
+			"with capacity of at least %v", chanPolicy.Policy,
+			len(chanPolicy.ChanIDs), chanPolicy.MinCapacity)
+
+		c.dispatchers = append(c.dispatchers, c.newPolicyDispatcher(
+			chanPolicy.Policy, candidates,
+		))
+	}
+
+	// If channels are backed up according to their commitment type, we'll
+	// also need a dispatcher for the legacy and anchor variants of each
+	// policy, which may be in use with the same tower at once.
+	if cfg.IsAnchorChannel != nil {
+		policies := make([]wtpolicy.Policy, 0, len(c.dispatchers))
+		for _, d := range c.dispatchers {
+			policies = append(policies, d.policy)
+		}
+
+		for _, policy := range policies {
+			for _, anchor := range []bool{false, true} {
+				variant := commitTypePolicy(policy, anchor)
+				if c.dispatcherForPolicy(variant) != nil {
+					continue
+				}
+
+				log.Infof("Offering policy %s for channels "+
+					"with anchor outputs=%v", variant,
+					anchor)
+
+				c.dispatchers = append(
+					c.dispatchers, c.newPolicyDispatcher(
+						variant, candidates,
+					),
+				)
+			}
+		}
+	}
 
 	// Next, load all active sessions from the db into the client. We will
-	// use any of these session if their policies match the current policy
-	// of the client, otherwise they will be ignored and new sessions will
-	// be requested.
-	c.candidateSessions, err = c.cfg.DB.ListClientSessions()
+	// use any of these session if their policies match one of the current
+	// policies of the client, otherwise they will be ignored and new
+	// sessions will be requested.
+	c.loadedSessions, err = c.cfg.DB.ListClientSessions()
 	if err != nil {
 		return nil, err
 	}
 
 	// Reload any towers from disk using the tower IDs contained in each
-	// candidate session. We will also rederive any session keys needed to
-	// be able to communicate with the towers and authenticate session
-	// requests. This prevents us from having to store the private keys on
-	// disk.
-	for _, s := range c.candidateSessions {
-		tower, err := c.cfg.DB.LoadTower(s.TowerID)
-		if err != nil {
-			return nil, err
+	// candidate session, unless they're already known. We will also
+	// rederive any session keys needed to be able to communicate with the
+	// towers and authenticate session requests. This prevents us from
+	// having to store the private keys on disk.
+	for _, s := range c.loadedSessions {
+		tower, ok := c.towers[s.TowerID]
+		if !ok {
+			tower, err = c.cfg.DB.LoadTower(s.TowerID)
+			if err != nil {
+				return nil, err
+			}
+			c.towers[s.TowerID] = tower
 		}
 
 		sessionPriv, err := DeriveSessionKey(
@@ -241,6 +533,18 @@ func New(config *Config) (*TowerClient, error) {
 
 		s.Tower = tower
 		s.SessionPrivKey = sessionPriv
+
+		for _, backupID := range s.AckedUpdates {
+			c.ackedUpdate(backupID)
+		}
+
+		// Offer the session to the dispatcher of the policy it was
+		// negotiated under. Sessions compatible with none of our
+		// policies can be used again if the client changes its
+		// configuration back.
+		if d := c.dispatcherForSession(s); d != nil {
+			d.candidateSessions[s.ID] = s
+		}
 	}
 
 	// Finally, load the sweep pkscripts that have been generated for all
@@ -253,6 +557,147 @@ func New(config *Config) (*TowerClient, error) {
 	return c, nil
 }
 
+// newPolicyDispatcher creates a policyDispatcher that negotiates sessions under
+// the given policy with the given tower candidates.
+func (c *TowerClient) newPolicyDispatcher(policy wtpolicy.Policy,
+	candidates []*wtdb.Tower) *policyDispatcher {
+
+	// We're always willing to pay the reward we propose ourselves.
+	maxRewardBase := c.cfg.MaxRewardBase
+	if maxRewardBase < policy.RewardBase {
+		maxRewardBase = policy.RewardBase
+	}
+	maxRewardRate := c.cfg.MaxRewardRate
+	if maxRewardRate < policy.RewardRate {
+		maxRewardRate = policy.RewardRate
+	}
+
+	return &policyDispatcher{
+		policy:        policy,
+		maxRewardBase: maxRewardBase,
+		maxRewardRate: maxRewardRate,
+		pipeline:      newTaskPipeline(),
+		negotiator: newSessionNegotiator(&NegotiatorConfig{
+			DB:            c.cfg.DB,
+			SecretKeyRing: c.cfg.SecretKeyRing,
+			Policy:        policy,
+			MaxRewardBase: maxRewardBase,
+			MaxRewardRate: maxRewardRate,
+			KeyIndexMtx:   &c.keyIndexMtx,
+			AddrBackoff:   c.addrBackoff,
+			ChainHash:     c.cfg.ChainHash,
+			SendMessage:   c.sendMessage,
+			ReadMessage:   c.readMessage,
+			Dial:          c.dial,
+			Candidates:    newTowerListIterator(candidates...),
+			MinBackoff:    c.cfg.MinBackoff,
+			MaxBackoff:    c.cfg.MaxBackoff,
+		}),
+		candidateSessions: make(map[wtdb.SessionID]*wtdb.ClientSession),
+	}
+}
+
+// dispatcherForPolicy returns the dispatcher negotiating sessions under the
+// given policy, or nil if the client doesn't use the policy.
+func (c *TowerClient) dispatcherForPolicy(
+	policy wtpolicy.Policy) *policyDispatcher {
+
+	for _, d := range c.dispatchers {
+		if d.policy == policy {
+			return d
+		}
+	}
+
+	return nil
+}
+
+// dispatcherForSession returns the dispatcher able to use the given session,
+// preferring the one whose policy the session was negotiated under. If none of
+// the dispatchers can use the session, nil is returned.
+func (c *TowerClient) dispatcherForSession(
+	s *wtdb.ClientSession) *policyDispatcher {
+
+	if d := c.dispatcherForPolicy(s.Policy); d != nil {
+		return d
+	}
+
+	for _, d := range c.dispatchers {
+		if d.compatiblePolicy(s.Policy) {
+			return d
+		}
+	}
+
+	return nil
+}
+
+// dispatcherForChannel returns the dispatcher backing up the states of the
+// given channel, determined by the first of the client's ChannelPolicies it
+// matches, and by the channel's commitment type if the client distinguishes
+// them. If the channel's capacity can't be looked up, the default policy is
+// used for this backup. An error is returned if the channel's commitment type
+// can't be determined, as backing it up under the wrong blob type would leave
+// the tower unable to sweep the breach.
+func (c *TowerClient) dispatcherForChannel(
+	chanID lnwire.ChannelID) (*policyDispatcher, error) {
+
+	c.chanDispatchersMtx.Lock()
+	defer c.chanDispatchersMtx.Unlock()
+
+	if d, ok := c.chanDispatchers[chanID]; ok {
+		return d, nil
+	}
+
+	var (
+		capacity    btcutil.Amount
+		capacityErr error
+		fetched     bool
+	)
+	chanCapacity := func() (btcutil.Amount, error) {
+		if !fetched {
+			capacity, capacityErr = c.cfg.ChannelCapacity(chanID)
+			fetched = true
+		}
+		return capacity, capacityErr
+	}
+
+	var (
+		policy    = c.cfg.Policy
+		cacheable = true
+	)
+	for _, chanPolicy := range c.cfg.ChannelPolicies {
+		match, err := chanPolicy.matches(chanID, chanCapacity)
+		if err != nil {
+			log.Warnf("Unable to fetch capacity of chanid=%s, "+
+				"using default policy: %v", chanID, err)
+			policy = c.cfg.Policy
+			cacheable = false
+			break
+		}
+
+		if match {
+			policy = chanPolicy.Policy
+			break
+		}
+	}
+
+	if c.cfg.IsAnchorChannel != nil {
+		anchor, err := c.cfg.IsAnchorChannel(chanID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine commitment "+
+				"type of chanid=%s: %v", chanID, err)
+		}
+
+		policy = commitTypePolicy(policy, anchor)
+	}
+
+	d := c.dispatcherForPolicy(policy)
+	if cacheable {
+		c.chanDispatchers[chanID] = d
+	}
+
+	return d, nil
+}
+
 // Start initializes the watchtower client by loading or negotiating an active
 // session and then begins processing backup tasks from the request pipeline.
 func (c *TowerClient) Start() error {
@@ -264,7 +709,7 @@ func (c *TowerClient) Start() error {
 		// committed but unacked state updates. This ensures that these
 		// sessions will be able to flush the committed updates after a
 		// restart.
-		for _, session := range c.candidateSessions {
+		for _, session := range c.loadedSessions {
 			if len(session.CommittedUpdates) > 0 {
 				log.Infof("Starting session=%s to process "+
 					"%d committed backups", session.ID,
@@ -273,20 +718,34 @@ func (c *TowerClient) Start() error {
 			}
 		}
 
-		// Now start the session negotiator, which will allow us to
-		// request new session as soon as the backupDispatcher starts
+		// Now start the session negotiators, which will allow us to
+		// request new session as soon as the backupDispatchers start
 		// up.
-		err = c.negotiator.Start()
-		if err != nil {
-			return
+		for _, d := range c.dispatchers {
+			err = d.negotiator.Start()
+			if err != nil {
+				return
+			}
 		}
 
-		// Start the task pipeline to which new backup tasks will be
+		// Start the task pipelines to which new backup tasks will be
 		// submitted from active links.
-		c.pipeline.Start()
+		for _, d := range c.dispatchers {
+			d.pipeline.Start()
+		}
 
-		c.wg.Add(1)
-		go c.backupDispatcher()
+		// Replay any backups that were queued before the client was
+		// last shut down, such that they are processed before any
+		// backups requested from now on.
+		err = c.replayQueuedBackups()
+		if err != nil {
+			return
+		}
+
+		for _, d := range c.dispatchers {
+			c.wg.Add(1)
+			go c.backupDispatcher(d)
+		}
 
 		log.Infof("Watchtower client started successfully")
 	})
@@ -298,16 +757,18 @@ func (c *TowerClient) Stop() error {
 	c.stopped.Do(func() {
 		log.Debugf("Stopping watchtower client")
 
-		// 1. Shutdown the backup queue, which will prevent any further
+		// 1. Shutdown the backup queues, which will prevent any further
 		// updates from being accepted. In practice, the links should be
 		// shutdown before the client has been stopped, so all updates
 		// would have been added prior.
-		c.pipeline.Stop()
+		for _, d := range c.dispatchers {
+			d.pipeline.Stop()
+		}
 
 		// 2. To ensure we don't hang forever on shutdown due to
 		// unintended failures, we'll delay a call to force quit the
-		// pipeline if a ForceQuitDelay is specified. This will have no
-		// effect if the pipeline shuts down cleanly before the delay
+		// pipelines if a ForceQuitDelay is specified. This will have
+		// no effect if the pipelines shut down cleanly before the delay
 		// fires.
 		//
 		// For full safety, this can be set to 0 and wait out
@@ -319,18 +780,20 @@ func (c *TowerClient) Stop() error {
 			time.AfterFunc(c.cfg.ForceQuitDelay, c.ForceQuit)
 		}
 
-		// 3. Once the backup queue has shutdown, wait for the main
-		// dispatcher to exit. The backup queue will signal it's
-		// completion to the dispatcher, which releases the wait group
+		// 3. Once the backup queues have shutdown, wait for the
+		// dispatchers to exit. Each backup queue will signal it's
+		// completion to its dispatcher, which releases the wait group
 		// after all tasks have been assigned to session queues.
 		c.wg.Wait()
 
 		// 4. Since all valid tasks have been assigned to session
 		// queues, we no longer need to negotiate sessions.
-		c.negotiator.Stop()
+		for _, d := range c.dispatchers {
+			d.negotiator.Stop()
+		}
 
 		log.Debugf("Waiting for active session queues to finish "+
-			"draining, stats: %s", c.stats)
+			"draining, stats: %s", &c.stats)
 
 		// 5. Shutdown all active session queues in parallel. These will
 		// exit once all updates have been acked by the watchtower.
@@ -345,7 +808,7 @@ func (c *TowerClient) Stop() error {
 		default:
 		}
 
-		log.Debugf("Client successfully stopped, stats: %s", c.stats)
+		log.Debugf("Client successfully stopped, stats: %s", &c.stats)
 	})
 	return nil
 }
@@ -356,22 +819,26 @@ func (c *TowerClient) ForceQuit() {
 	c.forced.Do(func() {
 		log.Infof("Force quitting watchtower client")
 
-		// 1. Shutdown the backup queue, which will prevent any further
+		// 1. Shutdown the backup queues, which will prevent any further
 		// updates from being accepted. In practice, the links should be
 		// shutdown before the client has been stopped, so all updates
 		// would have been added prior.
-		c.pipeline.ForceQuit()
+		for _, d := range c.dispatchers {
+			d.pipeline.ForceQuit()
+		}
 
-		// 2. Once the backup queue has shutdown, wait for the main
-		// dispatcher to exit. The backup queue will signal it's
-		// completion to the dispatcher, which releases the wait group
+		// 2. Once the backup queues have shutdown, wait for the
+		// dispatchers to exit. Each backup queue will signal it's
+		// completion to its dispatcher, which releases the wait group
 		// after all tasks have been assigned to session queues.
 		close(c.forceQuit)
 		c.wg.Wait()
 
 		// 3. Since all valid tasks have been assigned to session
 		// queues, we no longer need to negotiate sessions.
-		c.negotiator.Stop()
+		for _, d := range c.dispatchers {
+			d.negotiator.Stop()
+		}
 
 		// 4. Force quit all active session queues in parallel. These
 		// will exit once all updates have been acked by the watchtower.
@@ -380,7 +847,7 @@ func (c *TowerClient) ForceQuit() {
 		})
 
 		log.Infof("Watchtower client unclean shutdown complete, "+
-			"stats: %s", c.stats)
+			"stats: %s", &c.stats)
 	})
 }
 
@@ -438,25 +905,122 @@ func (c *TowerClient) BackupState(chanID *lnwire.ChannelID,
 
 	task := newBackupTask(chanID, breachInfo, sweepPkScript)
 
-	return c.pipeline.QueueBackupTask(task)
+	d, err := c.dispatcherForChannel(*chanID)
+	if err != nil {
+		return err
+	}
+
+	// Persist the backup before queueing it in memory, such that it can
+	// be replayed if we're shut down before it's committed to a session.
+	// If the backup is already queued or committed, there's nothing left
+	// to do.
+	err = c.cfg.DB.QueueBackup(&task.id)
+	switch {
+	case err == wtdb.ErrBackupAlreadyQueued:
+		log.Debugf("Backup chanid=%s commit-height=%d already queued",
+			task.id.ChanID, task.id.CommitHeight)
+		return nil
+
+	case err != nil:
+		return err
+	}
+
+	return d.pipeline.QueueBackupTask(task)
 }
 
-// nextSessionQueue attempts to fetch an active session from our set of
-// candidate sessions. Candidate sessions with a differing policy from the
-// active client's advertised policy will be ignored, but may be resumed if the
-// client is restarted with a matching policy. If no candidates were found, nil
-// is returned to signal that we need to request a new policy.
-func (c *TowerClient) nextSessionQueue() *sessionQueue {
+// replayQueuedBackups queues a backup task for each backup persisted in the
+// database that has yet to be committed to a session. Backups whose state has
+// already been committed to one of the loaded sessions are skipped.
+func (c *TowerClient) replayQueuedBackups() error {
+	queued, err := c.cfg.DB.FetchQueuedBackups()
+	if err != nil {
+		return err
+	}
+
+	if len(queued) == 0 {
+		return nil
+	}
+
+	if c.cfg.BuildBreachRetribution == nil {
+		log.Warnf("Unable to replay %d queued backups, no breach "+
+			"retribution builder configured", len(queued))
+		return nil
+	}
+
+	// Gather the backups that have already been committed to, or acked
+	// by, our sessions, in case the database wasn't able to dequeue them.
+	committed := make(map[wtdb.BackupID]struct{})
+	for _, session := range c.loadedSessions {
+		for _, update := range session.CommittedUpdates {
+			committed[update.BackupID] = struct{}{}
+		}
+		for _, backupID := range session.AckedUpdates {
+			committed[backupID] = struct{}{}
+		}
+	}
+
+	log.Infof("Replaying %d queued backups", len(queued))
+
+	for _, id := range queued {
+		if _, ok := committed[id]; ok {
+			continue
+		}
+
+		c.sweepPkScriptMu.RLock()
+		sweepPkScript, ok := c.sweepPkScripts[id.ChanID]
+		c.sweepPkScriptMu.RUnlock()
+		if !ok {
+			log.Errorf("Unable to replay backup chanid=%s "+
+				"commit-height=%d: %v", id.ChanID,
+				id.CommitHeight, ErrUnregisteredChannel)
+			continue
+		}
+
+		breachInfo, err := c.cfg.BuildBreachRetribution(
+			id.ChanID, id.CommitHeight,
+		)
+		if err != nil {
+			log.Errorf("Unable to replay backup chanid=%s "+
+				"commit-height=%d: %v", id.ChanID,
+				id.CommitHeight, err)
+			continue
+		}
+
+		chanID := id.ChanID
+		task := newBackupTask(&chanID, breachInfo, sweepPkScript)
+
+		d, err := c.dispatcherForChannel(chanID)
+		if err != nil {
+			log.Errorf("Unable to replay backup chanid=%s "+
+				"commit-height=%d: %v", id.ChanID,
+				id.CommitHeight, err)
+			continue
+		}
+
+		if err := d.pipeline.QueueBackupTask(task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextSessionQueue attempts to fetch an active session from the dispatcher's
+// set of candidate sessions. Candidate sessions with a policy incompatible with
+// the dispatcher's policy will be ignored, but may be resumed if the client is
+// restarted with a matching policy. If no candidates were found, nil is
+// returned to signal that we need to request a new policy.
+func (c *TowerClient) nextSessionQueue(d *policyDispatcher) *sessionQueue {
 	// Select any candidate session at random, and remove it from the set of
 	// candidate sessions.
 	var candidateSession *wtdb.ClientSession
-	for id, sessionInfo := range c.candidateSessions {
-		delete(c.candidateSessions, id)
+	for id, sessionInfo := range d.candidateSessions {
+		delete(d.candidateSessions, id)
 
 		// Skip any sessions with policies that don't match the current
 		// configuration. These can be used again if the client changes
 		// their configuration back.
-		if sessionInfo.Policy != c.cfg.Policy {
+		if !d.compatiblePolicy(sessionInfo.Policy) {
 			continue
 		}
 
@@ -476,60 +1040,66 @@ func (c *TowerClient) nextSessionQueue() *sessionQueue {
 	return c.getOrInitActiveQueue(candidateSession)
 }
 
-// backupDispatcher processes events coming from the taskPipeline and is
-// responsible for detecting when the client needs to renegotiate a session to
-// fulfill continuing demand. The event loop exits after all tasks have been
-// received from the upstream taskPipeline, or the taskPipeline is force quit.
+// backupDispatcher processes events coming from the dispatcher's taskPipeline
+// and is responsible for detecting when the client needs to renegotiate a
+// session under the dispatcher's policy to fulfill continuing demand. The event
+// loop exits after all tasks have been received from the upstream
+// taskPipeline, or the taskPipeline is force quit.
 //
 // NOTE: This method MUST be run as a goroutine.
-func (c *TowerClient) backupDispatcher() {
+func (c *TowerClient) backupDispatcher(d *policyDispatcher) {
 	defer c.wg.Done()
 
-	log.Tracef("Starting backup dispatcher")
-	defer log.Tracef("Stopping backup dispatcher")
+	log.Tracef("Starting backup dispatcher for policy %s", d.policy)
+	defer log.Tracef("Stopping backup dispatcher for policy %s", d.policy)
 
 	for {
 		switch {
 
 		// No active session queue and no additional sessions.
-		case c.sessionQueue == nil && len(c.candidateSessions) == 0:
-			log.Infof("Requesting new session.")
-
-			// Immediately request a new session.
-			c.negotiator.RequestSession()
+		case d.sessionQueue == nil && len(d.candidateSessions) == 0:
+			// Immediately request a new session, unless a
+			// replacement for the prior session is already being
+			// negotiated.
+			if !d.negotiating {
+				log.Infof("Requesting new session with "+
+					"policy %s.", d.policy)
+
+				d.negotiator.RequestSession()
+				d.negotiating = true
+			}
 
 			// Wait until we receive the newly negotiated session.
 			// All backups sent in the meantime are queued in the
 			// revoke queue, as we cannot process them.
 			select {
-			case session := <-c.negotiator.NewSessions():
+			case session := <-d.negotiator.NewSessions():
 				log.Infof("Acquired new session with id=%s",
 					session.ID)
-				c.candidateSessions[session.ID] = session
-				c.stats.sessionAcquired()
+				c.addCandidateSession(d, session)
 
 			case <-c.statTicker.C:
-				log.Infof("Client stats: %s", c.stats)
+				log.Infof("Client stats: %s", &c.stats)
 
 			case <-c.forceQuit:
 				return
 			}
 
 		// No active session queue but have additional sessions.
-		case c.sessionQueue == nil && len(c.candidateSessions) > 0:
+		case d.sessionQueue == nil && len(d.candidateSessions) > 0:
 			// We've exhausted the prior session, we'll pop another
 			// from the remaining sessions and continue processing
 			// backup tasks.
-			c.sessionQueue = c.nextSessionQueue()
-			if c.sessionQueue != nil {
+			d.sessionQueue = c.nextSessionQueue(d)
+			if d.sessionQueue != nil {
 				log.Debugf("Loaded next candidate session "+
-					"queue id=%s", c.sessionQueue.ID())
+					"queue id=%s", d.sessionQueue.ID())
 			}
 
 		// Have active session queue, process backups.
-		case c.sessionQueue != nil:
-			if c.prevTask != nil {
-				c.processTask(c.prevTask)
+		case d.sessionQueue != nil:
+			if d.prevTask != nil {
+				c.processTask(d, d.prevTask)
 
 				// Continue to ensure the sessionQueue is
 				// properly initialized before attempting to
@@ -543,23 +1113,20 @@ func (c *TowerClient) backupDispatcher() {
 
 			// If any sessions are negotiated while we have an
 			// active session queue, queue them for future use.
-			// This shouldn't happen with the current design, so
-			// it doesn't hurt to select here just in case. In the
-			// future, we will likely allow more asynchrony so that
-			// we can request new sessions before the session is
-			// fully empty, which this case would handle.
-			case session := <-c.negotiator.NewSessions():
-				log.Warnf("Acquired new session with id=%s",
-					"while processing tasks", session.ID)
-				c.candidateSessions[session.ID] = session
-				c.stats.sessionAcquired()
+			// This happens when a replacement is requested before
+			// the active session is fully exhausted.
+			case session := <-d.negotiator.NewSessions():
+				log.Infof("Acquired replacement session with "+
+					"id=%s while processing tasks",
+					session.ID)
+				c.addCandidateSession(d, session)
 
 			case <-c.statTicker.C:
-				log.Infof("Client stats: %s", c.stats)
+				log.Infof("Client stats: %s", &c.stats)
 
 			// Process each backup task serially from the queue of
 			// revoked states.
-			case task, ok := <-c.pipeline.NewBackupTasks():
+			case task, ok := <-d.pipeline.NewBackupTasks():
 				// All backups in the pipeline have been
 				// processed, it is now safe to exit.
 				if !ok {
@@ -571,34 +1138,37 @@ func (c *TowerClient) backupDispatcher() {
 					task.id.CommitHeight)
 
 				c.stats.taskReceived()
-				c.processTask(task)
+				c.processTask(d, task)
 			}
 		}
 	}
 }
 
-// processTask attempts to schedule the given backupTask on the active
-// sessionQueue. The task will either be accepted or rejected, afterwhich the
-// appropriate modifications to the client's state machine will be made. After
-// every invocation of processTask, the caller should ensure that the
-// sessionQueue hasn't been exhausted before proceeding to the next task. Tasks
-// that are rejected because the active sessionQueue is full will be cached as
-// the prevTask, and should be reprocessed after obtaining a new sessionQueue.
-func (c *TowerClient) processTask(task *backupTask) {
-	status, accepted := c.sessionQueue.AcceptTask(task)
+// processTask attempts to schedule the given backupTask on the dispatcher's
+// active sessionQueue. The task will either be accepted or rejected, afterwhich
+// the appropriate modifications to the dispatcher's state machine will be
+// made. After every invocation of processTask, the caller should ensure that
+// the sessionQueue hasn't been exhausted before proceeding to the next task.
+// Tasks that are rejected because the active sessionQueue is full will be
+// cached as the prevTask, and should be reprocessed after obtaining a new
+// sessionQueue.
+func (c *TowerClient) processTask(d *policyDispatcher, task *backupTask) {
+	status, accepted := d.sessionQueue.AcceptTask(task)
 	if accepted {
-		c.taskAccepted(task, status)
+		c.taskAccepted(d, task, status)
 	} else {
-		c.taskRejected(task, status)
+		c.taskRejected(d, task, status)
 	}
 }
 
 // taskAccepted processes the acceptance of a task by a sessionQueue depending
-// on the state the sessionQueue is in *after* the task is added. The client's
-// prevTask is always removed as a result of this call. The client's
-// sessionQueue will be removed if accepting the task left the sessionQueue in
-// an exhausted state.
-func (c *TowerClient) taskAccepted(task *backupTask, newStatus reserveStatus) {
+// on the state the sessionQueue is in *after* the task is added. The
+// dispatcher's prevTask is always removed as a result of this call. The
+// dispatcher's sessionQueue will be removed if accepting the task left the
+// sessionQueue in an exhausted state.
+func (c *TowerClient) taskAccepted(d *policyDispatcher, task *backupTask,
+	newStatus reserveStatus) {
+
 	log.Infof("Backup chanid=%s commit-height=%d accepted successfully",
 		task.id.ChanID, task.id.CommitHeight)
 
@@ -606,34 +1176,70 @@ func (c *TowerClient) taskAccepted(task *backupTask, newStatus reserveStatus) {
 
 	// If this task was accepted, we discard anything held in the prevTask.
 	// Either it was nil before, or is the task which was just accepted.
-	c.prevTask = nil
+	d.prevTask = nil
 
 	switch newStatus {
 
-	// The sessionQueue still has capacity after accepting this task.
+	// The sessionQueue still has capacity after accepting this task, though
+	// we may need to begin negotiating its replacement.
 	case reserveAvailable:
+		c.maybeRequestReplacement(d)
 
 	// The sessionQueue is full after accepting this task, so we will need
 	// to request a new one before proceeding.
 	case reserveExhausted:
 		c.stats.sessionExhausted()
 
-		log.Debugf("Session %s exhausted", c.sessionQueue.ID())
+		log.Debugf("Session %s exhausted", d.sessionQueue.ID())
 
 		// This task left the session exhausted, set it to nil and
 		// proceed to the next loop so we can consume another
 		// pre-negotiated session or request another.
-		c.sessionQueue = nil
+		d.sessionQueue = nil
 	}
 }
 
+// maybeRequestReplacement begins negotiating a replacement for the
+// dispatcher's active sessionQueue once its remaining slots drop to the
+// configured ReplacementThreshold. No request is made if a session is already
+// being negotiated, or if there are candidate sessions left to fall back to.
+func (c *TowerClient) maybeRequestReplacement(d *policyDispatcher) {
+	if d.negotiating || len(d.candidateSessions) > 0 {
+		return
+	}
+
+	remaining := d.sessionQueue.RemainingUpdates()
+	if remaining > c.cfg.ReplacementThreshold {
+		return
+	}
+
+	log.Infof("Session %s has %d slots remaining, requesting "+
+		"replacement session", d.sessionQueue.ID(), remaining)
+
+	d.negotiator.RequestSession()
+	d.negotiating = true
+}
+
+// addCandidateSession records a session received from the dispatcher's
+// negotiator, such that it can be used once the active sessionQueue is
+// exhausted.
+func (c *TowerClient) addCandidateSession(d *policyDispatcher,
+	session *wtdb.ClientSession) {
+
+	d.candidateSessions[session.ID] = session
+	d.negotiating = false
+	c.stats.sessionAcquired()
+}
+
 // taskRejected process the rejection of a task by a sessionQueue depending on
-// the state the was in *before* the task was rejected. The client's prevTask
-// will cache the task if the sessionQueue was exhausted before hand, and nil
-// the sessionQueue to find a new session. If the sessionQueue was not
+// the state the was in *before* the task was rejected. The dispatcher's
+// prevTask will cache the task if the sessionQueue was exhausted before hand,
+// and nil the sessionQueue to find a new session. If the sessionQueue was not
 // exhausted, the client marks the task as ineligible, as this implies we
 // couldn't construct a valid justice transaction given the session's policy.
-func (c *TowerClient) taskRejected(task *backupTask, curStatus reserveStatus) {
+func (c *TowerClient) taskRejected(d *policyDispatcher, task *backupTask,
+	curStatus reserveStatus) {
+
 	switch curStatus {
 
 	// The sessionQueue has available capacity but the task was rejected,
@@ -662,7 +1268,7 @@ func (c *TowerClient) taskRejected(task *backupTask, curStatus reserveStatus) {
 		// If this task was rejected *and* the session had available
 		// capacity, we discard anything held in the prevTask. Either it
 		// was nil before, or is the task which was just rejected.
-		c.prevTask = nil
+		d.prevTask = nil
 
 	// The sessionQueue rejected the task because it is full, we will stash
 	// this task and try to add it to the next available sessionQueue.
@@ -671,14 +1277,113 @@ func (c *TowerClient) taskRejected(task *backupTask, curStatus reserveStatus) {
 
 		log.Debugf("Session %s exhausted, backup chanid=%s "+
 			"commit-height=%d queued for next session",
-			c.sessionQueue.ID(), task.id.ChanID,
+			d.sessionQueue.ID(), task.id.ChanID,
 			task.id.CommitHeight)
 
 		// Cache the task that we pulled off, so that we can process it
 		// once a new session queue is available.
-		c.sessionQueue = nil
-		c.prevTask = task
+		d.sessionQueue = nil
+		d.prevTask = task
+	}
+}
+
+// ackedUpdate records that the tower has acked the given backup.
+func (c *TowerClient) ackedUpdate(backupID wtdb.BackupID) {
+	c.backedUpMtx.Lock()
+	defer c.backedUpMtx.Unlock()
+
+	height, ok := c.backedUpHeights[backupID.ChanID]
+	if !ok || backupID.CommitHeight > height {
+		c.backedUpHeights[backupID.ChanID] = backupID.CommitHeight
+	}
+}
+
+// BackedUpHeight returns the highest commit height of the channel's revoked
+// states that has been acked by a tower. The boolean is false if none of the
+// channel's states are known to be backed up. Since exhausted sessions aren't
+// loaded on startup, this is also the case until a tower acks the next state
+// if all of the channel's backups are held by exhausted sessions.
+func (c *TowerClient) BackedUpHeight(chanID lnwire.ChannelID) (uint64, bool) {
+	c.backedUpMtx.RLock()
+	defer c.backedUpMtx.RUnlock()
+
+	height, ok := c.backedUpHeights[chanID]
+	return height, ok
+}
+
+// UpdateTowerAddresses replaces the addresses of the tower with the given
+// public key, in order of preference. The new addresses are persisted, and take
+// effect for the tower's active sessions as well as future negotiations,
+// without having to remove and re-add the tower. Any backoff of the tower's
+// prior addresses is cleared, such that each of the new addresses is tried
+// right away.
+func (c *TowerClient) UpdateTowerAddresses(pubKey *btcec.PublicKey,
+	addrs []net.Addr) error {
+
+	if len(addrs) == 0 {
+		return ErrNoTowerAddrs
 	}
+
+	c.towersMtx.Lock()
+	defer c.towersMtx.Unlock()
+
+	var tower *wtdb.Tower
+	for _, t := range c.towers {
+		if t.IdentityKey.IsEqual(pubKey) {
+			tower = t
+			break
+		}
+	}
+	if tower == nil {
+		return wtdb.ErrTowerNotFound
+	}
+
+	err := c.cfg.DB.UpdateTowerAddresses(tower.ID, addrs)
+	if err != nil {
+		return err
+	}
+
+	c.addrBackoff.reset(tower.LNAddrs()...)
+	tower.SetAddresses(addrs)
+
+	log.Infof("Updated addresses of watchtower %x to %v",
+		pubKey.SerializeCompressed(), addrs)
+
+	return nil
+}
+
+// ListSessions returns the status of all sessions negotiated by the client that
+// have not yet been exhausted, ordered by session id. The number of backups
+// reflects those committed to the database, so tasks that were accepted by a
+// session but not yet committed are not accounted for.
+func (c *TowerClient) ListSessions() ([]*SessionStatus, error) {
+	sessions, err := c.cfg.DB.ListClientSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*SessionStatus, 0, len(sessions))
+	for _, s := range sessions {
+		tower, err := c.cfg.DB.LoadTower(s.TowerID)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, &SessionStatus{
+			ID:                s.ID,
+			TowerPubKey:       tower.IdentityKey,
+			Policy:            s.Policy,
+			NumBackups:        s.SeqNum,
+			NumPendingBackups: uint16(len(s.CommittedUpdates)),
+			RemainingBackups:  s.RemainingUpdates(),
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return bytes.Compare(statuses[i].ID[:], statuses[j].ID[:]) < 0
+	})
+
+	return statuses, nil
 }
 
 // dial connects the peer at addr using privKey as our secret key for the
@@ -769,6 +1474,8 @@ func (c *TowerClient) newSessionQueue(s *wtdb.ClientSession) *sessionQueue {
 		SendMessage:   c.sendMessage,
 		Signer:        c.cfg.Signer,
 		DB:            c.cfg.DB,
+		AckedUpdate:   c.ackedUpdate,
+		AddrBackoff:   c.addrBackoff,
 		MinBackoff:    c.cfg.MinBackoff,
 		MaxBackoff:    c.cfg.MaxBackoff,
 	})
@@ -778,6 +1485,9 @@ func (c *TowerClient) newSessionQueue(s *wtdb.ClientSession) *sessionQueue {
 // passed ClientSession. If it exists, the active sessionQueue is returned.
 // Otherwise a new sessionQueue is initialized and added to the set.
 func (c *TowerClient) getOrInitActiveQueue(s *wtdb.ClientSession) *sessionQueue {
+	c.activeSessionsMtx.Lock()
+	defer c.activeSessionsMtx.Unlock()
+
 	if sq, ok := c.activeSessions[s.ID]; ok {
 		return sq
 	}
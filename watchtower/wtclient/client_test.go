@@ -4,6 +4,7 @@ package wtclient_test
 
 import (
 	"encoding/binary"
+	"fmt"
 	"net"
 	"sync"
 	"testing"
@@ -71,13 +72,17 @@ func randPrivKey(t *testing.T) *btcec.PrivateKey {
 }
 
 type mockNet struct {
-	mu           sync.RWMutex
-	connCallback func(wtserver.Peer)
+	mu               sync.RWMutex
+	connCallback     func(wtserver.Peer)
+	unreachable      map[[33]byte]struct{}
+	unreachableAddrs map[string]struct{}
 }
 
 func newMockNet(cb func(wtserver.Peer)) *mockNet {
 	return &mockNet{
-		connCallback: cb,
+		connCallback:     cb,
+		unreachable:      make(map[[33]byte]struct{}),
+		unreachableAddrs: make(map[string]struct{}),
 	}
 }
 
@@ -106,13 +111,25 @@ func (m *mockNet) AuthDial(localPriv *btcec.PrivateKey, netAddr *lnwire.NetAddre
 		Port: 36723,
 	}
 
+	var remotePk [33]byte
+	copy(remotePk[:], netAddr.IdentityKey.SerializeCompressed())
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.unreachable[remotePk]; ok {
+		return nil, fmt.Errorf("tower %x unreachable", remotePk)
+	}
+	if _, ok := m.unreachableAddrs[netAddr.Address.String()]; ok {
+		return nil, fmt.Errorf("address %v unreachable",
+			netAddr.Address)
+	}
+
 	localPeer, remotePeer := wtmock.NewMockConn(
 		localPk, netAddr.IdentityKey, localAddr, netAddr.Address, 0,
 	)
 
-	m.mu.RLock()
 	m.connCallback(remotePeer)
-	m.mu.RUnlock()
 
 	return localPeer, nil
 }
@@ -123,6 +140,24 @@ func (m *mockNet) setConnCallback(cb func(wtserver.Peer)) {
 	m.connCallback = cb
 }
 
+// setUnreachable causes all subsequent dials to the given public key to fail.
+func (m *mockNet) setUnreachable(pubKey *btcec.PublicKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pk [33]byte
+	copy(pk[:], pubKey.SerializeCompressed())
+	m.unreachable[pk] = struct{}{}
+}
+
+// setAddrUnreachable causes all subsequent dials to the given address to fail.
+func (m *mockNet) setAddrUnreachable(addr net.Addr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.unreachableAddrs[addr.String()] = struct{}{}
+}
+
 type mockChannel struct {
 	mu            sync.Mutex
 	commitHeight  uint64
@@ -384,6 +419,12 @@ type harnessCfg struct {
 	policy             wtpolicy.Policy
 	noRegisterChan0    bool
 	noAckCreateSession bool
+	minRewardRate      uint32
+	maxRewardRate      uint32
+	replaceThreshold   uint16
+	unreachableTower   bool
+	channelPolicies    []wtclient.ChannelPolicy
+	anchorChannels     []uint64
 }
 
 func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
@@ -416,6 +457,9 @@ func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
 			return addr, nil
 		},
 		NoAckCreateSession: cfg.noAckCreateSession,
+		PolicyBounds: wtserver.PolicyBounds{
+			MinRewardRate: cfg.minRewardRate,
+		},
 	}
 
 	server, err := wtserver.New(serverCfg)
@@ -427,6 +471,10 @@ func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
 	mockNet := newMockNet(server.InboundPeerConnected)
 	clientDB := wtmock.NewClientDB()
 
+	// The harness is referenced by the client's breach retribution
+	// builder, which is only invoked once the client is restarted.
+	var h *testHarness
+
 	clientCfg := &wtclient.Config{
 		Signer: signer,
 		Dial: func(string, string) (net.Conn, error) {
@@ -437,13 +485,60 @@ func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
 		SecretKeyRing: wtmock.NewSecretKeyRing(),
 		PrivateTower:  towerAddr,
 		Policy:        cfg.policy,
+		MaxRewardRate: cfg.maxRewardRate,
 		NewAddress: func() ([]byte, error) {
 			return addrScript, nil
 		},
-		ReadTimeout:  timeout,
-		WriteTimeout: timeout,
-		MinBackoff:   time.Millisecond,
-		MaxBackoff:   10 * time.Millisecond,
+		ReplacementThreshold: cfg.replaceThreshold,
+		ReadTimeout:          timeout,
+		WriteTimeout:         timeout,
+		MinBackoff:           time.Millisecond,
+		MaxBackoff:           10 * time.Millisecond,
+		BuildBreachRetribution: func(chanID lnwire.ChannelID,
+			commitHeight uint64) (*lnwallet.BreachRetribution,
+			error) {
+
+			return h.buildBreachRetribution(chanID, commitHeight)
+		},
+		ChannelPolicies: cfg.channelPolicies,
+		ChannelCapacity: func(chanID lnwire.ChannelID) (btcutil.Amount,
+			error) {
+
+			return h.channelCapacity(chanID)
+		},
+	}
+
+	// If any of the channels use anchor outputs, have the client back up
+	// channels according to their commitment type.
+	if len(cfg.anchorChannels) > 0 {
+		anchorChans := make(map[lnwire.ChannelID]struct{})
+		for _, id := range cfg.anchorChannels {
+			anchorChans[chanIDFromInt(id)] = struct{}{}
+		}
+
+		clientCfg.IsAnchorChannel = func(
+			chanID lnwire.ChannelID) (bool, error) {
+
+			_, ok := anchorChans[chanID]
+			return ok, nil
+		}
+	}
+
+	// If the private tower should be unreachable, point the client at a
+	// tower that doesn't exist, leaving the server as its backup tower.
+	if cfg.unreachableTower {
+		unreachablePriv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("Unable to generate tower private key: %v",
+				err)
+		}
+		mockNet.setUnreachable(unreachablePriv.PubKey())
+
+		clientCfg.PrivateTower = &lnwire.NetAddress{
+			IdentityKey: unreachablePriv.PubKey(),
+			Address:     towerTCPAddr,
+		}
+		clientCfg.BackupTowers = []*lnwire.NetAddress{towerAddr}
 	}
 	client, err := wtclient.New(clientCfg)
 	if err != nil {
@@ -459,7 +554,7 @@ func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
 		t.Fatalf("Unable to start wtclient: %v", err)
 	}
 
-	h := &testHarness{
+	h = &testHarness{
 		t:         t,
 		cfg:       cfg,
 		signer:    signer,
@@ -562,6 +657,49 @@ func (h *testHarness) channel(id uint64) *mockChannel {
 	return c
 }
 
+// buildBreachRetribution returns the retribution of the channel identified by
+// chanID at the given commit height, allowing the client to replay queued
+// backups after a restart.
+func (h *testHarness) buildBreachRetribution(chanID lnwire.ChannelID,
+	commitHeight uint64) (*lnwallet.BreachRetribution, error) {
+
+	h.mu.Lock()
+	c, ok := h.channels[chanID]
+	h.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown channel %v", chanID)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	retribution, ok := c.retributions[commitHeight]
+	if !ok {
+		return nil, fmt.Errorf("unknown commit height %d",
+			commitHeight)
+	}
+
+	return retribution, nil
+}
+
+// channelCapacity returns the capacity of the channel identified by chanID,
+// allowing the client to select the policy used to back it up.
+func (h *testHarness) channelCapacity(
+	chanID lnwire.ChannelID) (btcutil.Amount, error) {
+
+	h.mu.Lock()
+	c, ok := h.channels[chanID]
+	h.mu.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("unknown channel %v", chanID)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return (c.localBalance + c.remoteBalance).ToSatoshis(), nil
+}
+
 // registerChannel registers the channel identified by id with the client.
 func (h *testHarness) registerChannel(id uint64) {
 	h.t.Helper()
@@ -732,6 +870,33 @@ func (h *testHarness) waitServerUpdates(hints []wtdb.BreachHint,
 	}
 }
 
+// assertBackedUpHeight waits until the client reports the given height as the
+// highest backed up state of the channel identified by id.
+func (h *testHarness) assertBackedUpHeight(id, expHeight uint64) {
+	h.t.Helper()
+
+	chanID := chanIDFromInt(id)
+
+	var (
+		height uint64
+		ok     bool
+	)
+	timeout := time.After(5 * time.Second)
+	for {
+		height, ok = h.client.BackedUpHeight(chanID)
+		if ok && height == expHeight {
+			return
+		}
+
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-timeout:
+			h.t.Fatalf("expected backed up height %d, got %d "+
+				"(found=%v)", expHeight, height, ok)
+		}
+	}
+}
+
 // assertUpdatesForPolicy queries the server db for matches using the provided
 // breach hints, then asserts that each match has a session with the expected
 // policy.
@@ -1082,6 +1247,48 @@ var clientTests = []clientTest{
 			h.waitServerUpdates(hints, 3*time.Second)
 		},
 	},
+	{
+		// Asserts that the client tracks the latest state of a channel
+		// acked by the tower, including across restarts.
+		name: "backed up height",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType:     blob.TypeDefault,
+				MaxUpdates:   5,
+				SweepFeeRate: 1,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				numUpdates = 7
+				chanID     = 0
+			)
+
+			// No states should be reported as backed up before
+			// any have been sent.
+			_, ok := h.client.BackedUpHeight(chanIDFromInt(chanID))
+			if ok {
+				h.t.Fatalf("expected no backed up states")
+			}
+
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+			h.waitServerUpdates(hints, 5*time.Second)
+
+			// The client processes the tower's acks after they're
+			// reflected in the server's database, so we'll wait
+			// for the latest state to be recorded.
+			h.assertBackedUpHeight(chanID, numUpdates-1)
+
+			// After a restart, the acked states of the active
+			// session should be reloaded.
+			h.client.Stop()
+			h.startClient()
+			h.assertBackedUpHeight(chanID, numUpdates-1)
+		},
+	},
 	{
 		// Asserts that the client is able to support multiple links.
 		name: "multiple link backup",
@@ -1187,6 +1394,69 @@ var clientTests = []clientTest{
 			h.assertUpdatesForPolicy(hints, h.clientCfg.Policy)
 		},
 	},
+	{
+		// Asserts that backups which were queued, but not yet
+		// committed to a session, are replayed after the client is
+		// restarted, without the channel having to request them
+		// again.
+		name: "queued backups replayed after restart",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType:     blob.TypeDefault,
+				MaxUpdates:   5,
+				SweepFeeRate: 1,
+			},
+			noAckCreateSession: true,
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 3
+			)
+
+			// Generate the retributions and queue them for backup.
+			// Since the client is unable to create a session, they
+			// remain queued.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+			h.waitServerUpdates(nil, time.Second)
+
+			// Force quit the client, dropping the backups it holds
+			// in memory.
+			h.client.ForceQuit()
+
+			// Restart the server and allow it to ack session
+			// creation.
+			h.server.Stop()
+			h.serverCfg.NoAckCreateSession = false
+			h.startServer()
+			defer h.server.Stop()
+
+			// Restart the client, which should replay the queued
+			// backups on its own.
+			h.startClient()
+			defer h.client.ForceQuit()
+
+			h.waitServerUpdates(hints, 5*time.Second)
+
+			// Requesting the same backups again shouldn't cause
+			// them to be sent twice, as they've been committed.
+			h.backupStates(chanID, 0, numUpdates, nil)
+			h.waitServerUpdates(hints, time.Second)
+
+			queued, err := h.clientDB.FetchQueuedBackups()
+			if err != nil {
+				h.t.Fatalf("unable to fetch queued backups: %v",
+					err)
+			}
+			if len(queued) != 0 {
+				h.t.Fatalf("expected no queued backups, got %d",
+					len(queued))
+			}
+		},
+	},
 	{
 		name: "create session no ack change policy",
 		cfg: harnessCfg{
@@ -1244,6 +1514,435 @@ var clientTests = []clientTest{
 			h.assertUpdatesForPolicy(hints, h.clientCfg.Policy)
 		},
 	},
+	{
+		// Asserts that the client proposes the reward required by the
+		// tower if it rejects the reward of the client's policy, as
+		// long as it's within the client's caps.
+		name: "reward session renegotiated within cap",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType: blob.TypeFromFlags(
+					blob.FlagCommitOutputs, blob.FlagReward,
+				),
+				MaxUpdates:   5,
+				RewardRate:   10000,
+				SweepFeeRate: 1,
+			},
+			minRewardRate: 20000,
+			maxRewardRate: 30000,
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 3
+			)
+
+			// Generate the retributions and back them up to the
+			// tower.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+
+			// Wait for all of the updates to be populated in the
+			// server's database.
+			h.waitServerUpdates(hints, 5*time.Second)
+
+			// The updates should have been sent under a session
+			// paying the reward required by the tower.
+			expPolicy := h.clientCfg.Policy
+			expPolicy.RewardRate = 20000
+			h.assertUpdatesForPolicy(hints, expPolicy)
+		},
+	},
+	{
+		// Asserts that the client won't negotiate a session with a
+		// tower requiring a higher reward than the client's caps.
+		name: "reward session exceeding cap",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType: blob.TypeFromFlags(
+					blob.FlagCommitOutputs, blob.FlagReward,
+				),
+				MaxUpdates:   5,
+				RewardRate:   10000,
+				SweepFeeRate: 1,
+			},
+			minRewardRate: 50000,
+			maxRewardRate: 30000,
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 3
+			)
+
+			// Generate the retributions and queue them for backup.
+			h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+
+			// Since the client is unwilling to pay the reward the
+			// tower requires, the server should have no updates.
+			h.waitServerUpdates(nil, time.Second)
+
+			// Force quit the client since it has queued backups.
+			h.client.ForceQuit()
+		},
+	},
+	{
+		// Asserts that the client negotiates replacements for
+		// exhausted sessions without dropping any backups, and that
+		// only the sessions which haven't been exhausted are listed
+		// along with their remaining slots.
+		name: "session replacement",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType:     blob.TypeDefault,
+				MaxUpdates:   5,
+				SweepFeeRate: 1,
+			},
+			replaceThreshold: 2,
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 12
+			)
+
+			// Generate enough retributions to exhaust two sessions,
+			// and back them up.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+
+			// All updates should reach the tower, spread over three
+			// sessions.
+			h.waitServerUpdates(hints, 5*time.Second)
+
+			// Stop the client to ensure all updates are acked.
+			h.client.Stop()
+
+			// Only the third session should remain, with room for
+			// three more backups.
+			sessions, err := h.client.ListSessions()
+			if err != nil {
+				h.t.Fatalf("unable to list sessions: %v", err)
+			}
+			if len(sessions) != 1 {
+				h.t.Fatalf("expected 1 session, got %d",
+					len(sessions))
+			}
+			session := sessions[0]
+			if session.NumBackups != 2 {
+				h.t.Fatalf("expected 2 backups, got %d",
+					session.NumBackups)
+			}
+			if session.RemainingBackups != 3 {
+				h.t.Fatalf("expected 3 remaining backups, "+
+					"got %d", session.RemainingBackups)
+			}
+		},
+	},
+	{
+		// Asserts that the client negotiates sessions with a backup
+		// tower if the private tower is unreachable.
+		name: "backup tower",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType:     blob.TypeDefault,
+				MaxUpdates:   5,
+				SweepFeeRate: 1,
+			},
+			unreachableTower: true,
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 7
+			)
+
+			// Generate the retributions and back them up.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+
+			// All updates should reach the backup tower.
+			h.waitServerUpdates(hints, 5*time.Second)
+		},
+	},
+	{
+		// Asserts that the states of channels matching a channel
+		// policy are backed up to sessions negotiated under that
+		// policy, while all other channels use the default policy.
+		name: "channel policies",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType:     blob.TypeDefault,
+				MaxUpdates:   5,
+				SweepFeeRate: 1,
+			},
+			channelPolicies: []wtclient.ChannelPolicy{
+				{
+					ChanIDs: []lnwire.ChannelID{
+						chanIDFromInt(1),
+					},
+					Policy: wtpolicy.Policy{
+						BlobType:     blob.TypeDefault,
+						MaxUpdates:   5,
+						SweepFeeRate: 10,
+					},
+				},
+				{
+					MinCapacity: 1000000,
+					Policy: wtpolicy.Policy{
+						BlobType:     blob.TypeDefault,
+						MaxUpdates:   5,
+						SweepFeeRate: 20,
+					},
+				},
+			},
+		},
+		fn: func(h *testHarness) {
+			const numUpdates = 7
+
+			// Channel 0 matches none of the channel policies, and
+			// channel 1 is selected by its id. Channel 2 is large
+			// enough to be selected by its capacity.
+			h.makeChannel(1, localBalance, remoteBalance)
+			h.makeChannel(2, 10*localBalance, 10*remoteBalance)
+			h.registerChannel(1)
+			h.registerChannel(2)
+
+			var (
+				allHints  []wtdb.BreachHint
+				chanHints = make(map[uint64][]wtdb.BreachHint)
+			)
+			for id := uint64(0); id < 3; id++ {
+				hints := h.advanceChannelN(id, numUpdates)
+				h.backupStates(id, 0, numUpdates, nil)
+
+				chanHints[id] = hints
+				allHints = append(allHints, hints...)
+			}
+
+			h.waitServerUpdates(allHints, 5*time.Second)
+
+			// Each channel's states should have been backed up
+			// under its own policy.
+			h.assertUpdatesForPolicy(chanHints[0], h.cfg.policy)
+			h.assertUpdatesForPolicy(
+				chanHints[1], h.cfg.channelPolicies[0].Policy,
+			)
+			h.assertUpdatesForPolicy(
+				chanHints[2], h.cfg.channelPolicies[1].Policy,
+			)
+		},
+	},
+	{
+		// Asserts that the client negotiates sessions of both the
+		// legacy and anchor blob types with the same tower, and backs
+		// up each channel to the sessions matching its commitment
+		// type.
+		name: "anchor and legacy channels",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType:     blob.TypeDefault,
+				MaxUpdates:   5,
+				SweepFeeRate: 1,
+			},
+			anchorChannels: []uint64{1},
+		},
+		fn: func(h *testHarness) {
+			const numUpdates = 7
+
+			// Channel 0 is a legacy channel, while channel 1 uses
+			// anchor outputs.
+			h.makeChannel(1, localBalance, remoteBalance)
+			h.registerChannel(1)
+
+			legacyHints := h.advanceChannelN(0, numUpdates)
+			anchorHints := h.advanceChannelN(1, numUpdates)
+
+			// Interleave the backups of both channels, such that
+			// sessions of both types are in use at once.
+			for i := uint64(0); i < numUpdates; i++ {
+				h.backupState(0, i, nil)
+				h.backupState(1, i, nil)
+			}
+
+			h.waitServerUpdates(
+				append(legacyHints, anchorHints...),
+				5*time.Second,
+			)
+
+			anchorPolicy := h.cfg.policy
+			anchorPolicy.BlobType = (blob.FlagCommitOutputs |
+				blob.FlagAnchorChannel).Type()
+
+			h.assertUpdatesForPolicy(legacyHints, h.cfg.policy)
+			h.assertUpdatesForPolicy(anchorHints, anchorPolicy)
+		},
+	},
+	{
+		// Asserts that the addresses of a tower can be updated without
+		// having to renegotiate its sessions, and that the client
+		// fails over to the next address of the tower if one of them
+		// can't be dialed.
+		name: "tower address failover",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType:     blob.TypeDefault,
+				MaxUpdates:   10,
+				SweepFeeRate: 1,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 6
+			)
+
+			// Back up the first half of the states to the tower at
+			// its original address.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates/2, nil)
+			h.waitServerUpdates(hints[:numUpdates/2], 5*time.Second)
+
+			// Now, move the tower to a new address, preceded by one
+			// that can't be dialed. The original address is no
+			// longer reachable either.
+			deadAddr := &net.TCPAddr{
+				IP:   net.IPv4(18, 28, 243, 3),
+				Port: 9911,
+			}
+			newAddr := &net.TCPAddr{
+				IP:   net.IPv4(18, 28, 243, 4),
+				Port: 9911,
+			}
+			oldAddr := h.clientCfg.PrivateTower.Address
+			h.net.setAddrUnreachable(oldAddr)
+			h.net.setAddrUnreachable(deadAddr)
+
+			err := h.client.UpdateTowerAddresses(
+				h.clientCfg.PrivateTower.IdentityKey,
+				[]net.Addr{deadAddr, newAddr},
+			)
+			if err != nil {
+				h.t.Fatalf("unable to update tower "+
+					"addresses: %v", err)
+			}
+
+			// The remaining states should be backed up through the
+			// new address.
+			h.backupStates(chanID, numUpdates/2, numUpdates, nil)
+			h.waitServerUpdates(hints, 5*time.Second)
+
+			// All of the states should have been backed up to the
+			// session negotiated before the update.
+			sessions, err := h.client.ListSessions()
+			if err != nil {
+				h.t.Fatalf("unable to list sessions: %v", err)
+			}
+			if len(sessions) != 1 {
+				h.t.Fatalf("expected 1 session, got %d",
+					len(sessions))
+			}
+			if sessions[0].NumBackups != numUpdates {
+				h.t.Fatalf("expected %d backups in session, "+
+					"got %d", numUpdates,
+					sessions[0].NumBackups)
+			}
+
+			// Updating the addresses of an unknown tower should
+			// fail.
+			err = h.client.UpdateTowerAddresses(
+				randPrivKey(h.t).PubKey(), []net.Addr{newAddr},
+			)
+			if err != wtdb.ErrTowerNotFound {
+				h.t.Fatalf("expected ErrTowerNotFound, got: %v",
+					err)
+			}
+		},
+	},
+	{
+		// Asserts that the session stats account for the backups of
+		// both exhausted and active sessions, and that each channel's
+		// value is only counted once per tower.
+		name: "session stats",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				BlobType:     blob.TypeDefault,
+				MaxUpdates:   5,
+				SweepFeeRate: 1,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 8
+			)
+
+			// Back up enough states to exhaust the first session,
+			// and wait until the client has processed all acks.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+			h.waitServerUpdates(hints, 5*time.Second)
+			h.assertBackedUpHeight(chanID, numUpdates-1)
+
+			towers, err := h.client.SessionStats()
+			if err != nil {
+				h.t.Fatalf("unable to fetch session stats: %v",
+					err)
+			}
+			if len(towers) != 1 || len(towers[0].Sessions) != 2 {
+				h.t.Fatalf("expected 1 tower with 2 sessions")
+			}
+
+			tower := towers[0]
+			if tower.NumSent != numUpdates ||
+				tower.NumAcked != numUpdates ||
+				tower.NumPending != 0 {
+
+				h.t.Fatalf("unexpected tower totals: sent=%d "+
+					"acked=%d pending=%d", tower.NumSent,
+					tower.NumAcked, tower.NumPending)
+			}
+
+			// Both outputs of each revoked state are swept, while
+			// the channel's value shouldn't be counted for each
+			// session it was backed up to.
+			expValue := (localBalance + remoteBalance).ToSatoshis()
+			if tower.ValueProtected != expValue {
+				h.t.Fatalf("expected tower to protect %v, "+
+					"got %v", expValue, tower.ValueProtected)
+			}
+			for _, session := range tower.Sessions {
+				if session.NumAcked != session.NumSent {
+					h.t.Fatalf("expected all %d backups of "+
+						"session to be acked, got %d",
+						session.NumSent,
+						session.NumAcked)
+				}
+				if session.ValueProtected != expValue {
+					h.t.Fatalf("expected session to "+
+						"protect %v, got %v", expValue,
+						session.ValueProtected)
+				}
+			}
+		},
+	},
 }
 
 // TestClient executes the client test suite, asserting the ability to backup
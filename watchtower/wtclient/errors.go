@@ -32,4 +32,25 @@ var (
 	// revoked state because the channel had not been previously registered
 	// with the client.
 	ErrUnregisteredChannel = errors.New("channel is not registered")
+
+	// ErrRewardExceedsCap signals that a tower requires a higher reward
+	// than the client is willing to pay.
+	ErrRewardExceedsCap = errors.New("tower reward exceeds cap")
+
+	// ErrInvalidRewardScript signals that a tower accepted a reward session,
+	// but returned a reward pkscript that can't be used within justice
+	// transactions.
+	ErrInvalidRewardScript = errors.New("invalid reward pkscript")
+
+	// ErrNoChannelCapacity signals that the client could not be created
+	// because a channel policy selects channels by capacity, but no means
+	// of looking up a channel's capacity was provided.
+	ErrNoChannelCapacity = errors.New("channel policy requires channel " +
+		"capacity lookup")
+
+	// ErrNoSessionSecret signals that a backup couldn't be bound to a
+	// session using versioned blobs, since the session's private key or
+	// tower, from which the blob key is derived, is unknown.
+	ErrNoSessionSecret = errors.New("session key or tower unknown, " +
+		"unable to derive blob key")
 )
@@ -23,6 +23,10 @@ type DB interface {
 	// LoadTower retrieves a tower by its tower ID.
 	LoadTower(uint64) (*wtdb.Tower, error)
 
+	// UpdateTowerAddresses replaces the addresses of the tower with the
+	// given ID. wtdb.ErrTowerNotFound is returned if no such tower exists.
+	UpdateTowerAddresses(uint64, []net.Addr) error
+
 	// NextSessionKeyIndex reserves a new session key derivation index for a
 	// particular tower id. The index is reserved for that tower until
 	// CreateClientSession is invoked for that tower and index, at which
@@ -41,6 +45,12 @@ type DB interface {
 	// still be able to accept state updates.
 	ListClientSessions() (map[wtdb.SessionID]*wtdb.ClientSession, error)
 
+	// ListAllClientSessions returns every session negotiated by the
+	// client, including those that have been exhausted. This is used to
+	// account for the backups made to each tower.
+	ListAllClientSessions() (map[wtdb.SessionID]*wtdb.ClientSession,
+		error)
+
 	// FetchChanPkScripts returns a map of all sweep pkscripts for
 	// registered channels. This is used on startup to cache the sweep
 	// pkscripts of registered channels in memory.
@@ -53,14 +63,27 @@ type DB interface {
 	// MarkBackupIneligible records that the state identified by the
 	// (channel id, commit height) tuple was ineligible for being backed up
 	// under the current policy. This state can be retried later under a
-	// different policy.
+	// different policy. The backup is also removed from the queue of
+	// pending backups.
 	MarkBackupIneligible(chanID lnwire.ChannelID, commitHeight uint64) error
 
+	// QueueBackup persists a backup that has been requested, but not yet
+	// committed to a session, so that it can be replayed if the client
+	// is restarted before doing so. ErrBackupAlreadyQueued is returned if
+	// the backup is already queued, or has already been committed to a
+	// session.
+	QueueBackup(*wtdb.BackupID) error
+
+	// FetchQueuedBackups returns all pending backups, in the order they
+	// were queued.
+	FetchQueuedBackups() ([]wtdb.BackupID, error)
+
 	// CommitUpdate writes the next state update for a particular
 	// session, so that we can be sure to resend it after a restart if it
 	// hasn't been ACK'd by the tower. The sequence number of the update
 	// should be exactly one greater than the existing entry, and less that
-	// or equal to the session's MaxUpdates.
+	// or equal to the session's MaxUpdates. The update's backup is
+	// removed from the queue of pending backups.
 	CommitUpdate(id *wtdb.SessionID, seqNum uint16,
 		update *wtdb.CommittedUpdate) (uint16, error)
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/litecoinfinance/btcd/btcec"
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/btcd/txscript"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/watchtower/blob"
 	"github.com/litecoinfinance/lnd/watchtower/wtdb"
@@ -55,11 +56,26 @@ type NegotiatorConfig struct {
 	// across all negotiation proposals for the lifetime of the negotiator.
 	Policy wtpolicy.Policy
 
+	// MaxRewardBase and MaxRewardRate cap the reward we're willing to pay
+	// towers that reject the reward proposed in Policy.
+	MaxRewardBase uint32
+	MaxRewardRate uint32
+
+	// KeyIndexMtx, if non-nil, is held from reserving a session key index
+	// until the session using it has been negotiated. Negotiators sharing
+	// a DB must share the mutex, otherwise they would negotiate sessions
+	// with a tower using the same reserved index.
+	KeyIndexMtx *sync.Mutex
+
 	// Dial initiates an outbound brontide connection to the given address
 	// using a specified private key. The peer is returned in the event of a
 	// successful connection.
 	Dial func(*btcec.PrivateKey, *lnwire.NetAddress) (wtserver.Peer, error)
 
+	// AddrBackoff tracks the backoff of tower addresses that couldn't be
+	// dialed. Backed off addresses are skipped during negotiation.
+	AddrBackoff *addrBackoff
+
 	// SendMessage writes a wtwire message to remote peer.
 	SendMessage func(wtserver.Peer, wtwire.Message) error
 
@@ -274,19 +290,7 @@ retryWithBackoff:
 		log.Debugf("Attempting session negotiation with tower=%x",
 			towerPub)
 
-		// Before proceeding, we will reserve a session key index to use
-		// with this specific tower. If one is already reserved, the
-		// existing index will be returned.
-		keyIndex, err := n.cfg.DB.NextSessionKeyIndex(tower.ID)
-		if err != nil {
-			log.Debugf("Unable to reserve session key index "+
-				"for tower=%x: %v", towerPub, err)
-			continue
-		}
-
-		// We'll now attempt the CreateSession dance with the tower to
-		// get a new session, trying all addresses if necessary.
-		err = n.createSession(tower, keyIndex)
+		err = n.negotiateWithTower(tower)
 		if err != nil {
 			log.Debugf("Session negotiation with tower=%x "+
 				"failed, trying again -- reason: %v",
@@ -299,15 +303,40 @@ retryWithBackoff:
 	}
 }
 
+// negotiateWithTower reserves a session key index for the given tower and
+// attempts to negotiate a session with it, holding the KeyIndexMtx if one is
+// configured.
+func (n *sessionNegotiator) negotiateWithTower(tower *wtdb.Tower) error {
+	if n.cfg.KeyIndexMtx != nil {
+		n.cfg.KeyIndexMtx.Lock()
+		defer n.cfg.KeyIndexMtx.Unlock()
+	}
+
+	// Before proceeding, we will reserve a session key index to use with
+	// this specific tower. If one is already reserved, the existing index
+	// will be returned.
+	keyIndex, err := n.cfg.DB.NextSessionKeyIndex(tower.ID)
+	if err != nil {
+		return fmt.Errorf("unable to reserve session key index: %v",
+			err)
+	}
+
+	// We'll now attempt the CreateSession dance with the tower to get a
+	// new session, trying all addresses if necessary.
+	return n.createSession(tower, keyIndex)
+}
+
 // createSession takes a tower an attempts to negotiate a session using any of
-// its stored addresses. This method returns after the first successful
-// negotiation, or after all addresses have failed with ErrFailedNegotiation. If
-// the tower has no addresses, ErrNoTowerAddrs is returned.
+// its stored addresses that aren't backed off. This method returns after the
+// first successful negotiation, or after all addresses have failed with
+// ErrFailedNegotiation. If the tower has no addresses, ErrNoTowerAddrs is
+// returned.
 func (n *sessionNegotiator) createSession(tower *wtdb.Tower,
 	keyIndex uint32) error {
 
 	// If the tower has no addresses, there's nothing we can do.
-	if len(tower.Addresses) == 0 {
+	towerAddrs := tower.LNAddrs()
+	if len(towerAddrs) == 0 {
 		return ErrNoTowerAddrs
 	}
 
@@ -316,8 +345,28 @@ func (n *sessionNegotiator) createSession(tower *wtdb.Tower,
 		return err
 	}
 
-	for _, lnAddr := range tower.LNAddrs() {
-		err = n.tryAddress(sessionPriv, keyIndex, tower, lnAddr)
+	// Skip any addresses we recently failed to dial. If all of them are
+	// backed off, we'll move on to the next candidate tower.
+	lnAddrs, _ := n.cfg.AddrBackoff.available(towerAddrs)
+	for _, lnAddr := range lnAddrs {
+		err = n.tryAddress(
+			sessionPriv, keyIndex, tower, lnAddr, n.cfg.Policy,
+		)
+
+		// If the tower rejected the reward we proposed, we'll propose
+		// the reward it requires instead, as long as it's within our
+		// caps.
+		if rejection, ok := err.(*rewardRejection); ok {
+			var policy wtpolicy.Policy
+			policy, err = n.rewardPolicy(&rejection.reqs)
+			if err == nil {
+				err = n.tryAddress(
+					sessionPriv, keyIndex, tower, lnAddr,
+					policy,
+				)
+			}
+		}
+
 		switch {
 		case err == ErrPermanentTowerFailure:
 			// TODO(conner): report to iterator? can then be reset
@@ -338,18 +387,68 @@ func (n *sessionNegotiator) createSession(tower *wtdb.Tower,
 	return ErrFailedNegotiation
 }
 
-// tryAddress executes a single create session dance using the given address.
-// The address should belong to the tower's set of addresses. This method only
-// returns true if all steps succeed and the new session has been persisted, and
-// fails otherwise.
+// rewardPolicy returns the policy to propose to a tower that rejected the
+// reward of our configured policy, raising the reward to the one the tower
+// requires. ErrRewardExceedsCap is returned if the required reward exceeds our
+// caps.
+func (n *sessionNegotiator) rewardPolicy(
+	reqs *wtwire.RewardRequirements) (wtpolicy.Policy, error) {
+
+	policy := n.cfg.Policy
+	if reqs.MinRewardBase > policy.RewardBase {
+		policy.RewardBase = reqs.MinRewardBase
+	}
+	if reqs.MinRewardRate > policy.RewardRate {
+		policy.RewardRate = reqs.MinRewardRate
+	}
+
+	if policy.RewardBase > n.cfg.MaxRewardBase ||
+		policy.RewardRate > n.cfg.MaxRewardRate {
+
+		return policy, ErrRewardExceedsCap
+	}
+
+	// If our reward already satisfies the tower's requirements, there's
+	// nothing we can renegotiate.
+	if policy == n.cfg.Policy {
+		return policy, fmt.Errorf("tower rejected reward base=%d "+
+			"rate=%d despite requiring base=%d rate=%d",
+			policy.RewardBase, policy.RewardRate,
+			reqs.MinRewardBase, reqs.MinRewardRate)
+	}
+
+	return policy, nil
+}
+
+// rewardRejection is returned by tryAddress if the tower rejected the reward
+// we proposed, and carries the reward the tower requires instead.
+type rewardRejection struct {
+	reqs wtwire.RewardRequirements
+}
+
+// Error returns a human readable description of the rejection.
+func (r *rewardRejection) Error() string {
+	return fmt.Sprintf("tower rejected reward, requires base=%d rate=%d",
+		r.reqs.MinRewardBase, r.reqs.MinRewardRate)
+}
+
+// tryAddress executes a single create session dance using the given address,
+// proposing the passed policy. The address should belong to the tower's set of
+// addresses. This method only returns true if all steps succeed and the new
+// session has been persisted, and fails otherwise.
 func (n *sessionNegotiator) tryAddress(privKey *btcec.PrivateKey,
-	keyIndex uint32, tower *wtdb.Tower, lnAddr *lnwire.NetAddress) error {
+	keyIndex uint32, tower *wtdb.Tower, lnAddr *lnwire.NetAddress,
+	policy wtpolicy.Policy) error {
 
-	// Connect to the tower address using our generated session key.
+	// Connect to the tower address using our generated session key. The
+	// address is backed off if it can't be dialed, such that other
+	// addresses are preferred until it may have become reachable again.
 	conn, err := n.cfg.Dial(privKey, lnAddr)
 	if err != nil {
+		n.cfg.AddrBackoff.failed(lnAddr)
 		return err
 	}
+	n.cfg.AddrBackoff.reset(lnAddr)
 
 	// Send local Init message.
 	err = n.cfg.SendMessage(conn, n.localInit)
@@ -375,7 +474,6 @@ func (n *sessionNegotiator) tryAddress(privKey *btcec.PrivateKey,
 		return err
 	}
 
-	policy := n.cfg.Policy
 	createSession := &wtwire.CreateSession{
 		BlobType:     policy.BlobType,
 		MaxUpdates:   policy.MaxUpdates,
@@ -410,8 +508,16 @@ func (n *sessionNegotiator) tryAddress(privKey *btcec.PrivateKey,
 		// handle case where we lose state, session already exists, and
 		// we want to possibly resume using the session
 
-		// TODO(conner): validate reward address
+		// If we negotiated a reward session, the tower's reward
+		// pkscript will be included within our justice transactions,
+		// so we'll make sure it's a standard script.
 		rewardPkScript := createSessionReply.Data
+		if policy.BlobType.Has(blob.FlagReward) {
+			err := validateRewardPkScript(rewardPkScript)
+			if err != nil {
+				return err
+			}
+		}
 
 		sessionID := wtdb.NewSessionIDFromPubKey(
 			privKey.PubKey(),
@@ -422,7 +528,7 @@ func (n *sessionNegotiator) tryAddress(privKey *btcec.PrivateKey,
 			KeyIndex:       keyIndex,
 			SessionPrivKey: privKey,
 			ID:             sessionID,
-			Policy:         n.cfg.Policy,
+			Policy:         policy,
 			SeqNum:         0,
 			RewardPkScript: rewardPkScript,
 		}
@@ -463,8 +569,15 @@ func (n *sessionNegotiator) tryAddress(privKey *btcec.PrivateKey,
 			return ErrPermanentTowerFailure
 		}
 
-		return fmt.Errorf("tower rejected reward rate: %v",
-			policy.RewardRate)
+		// Otherwise, the tower should have included the reward it
+		// requires, which we may be willing to pay.
+		var reqs wtwire.RewardRequirements
+		if err := reqs.Decode(createSessionReply.Data); err != nil {
+			return fmt.Errorf("tower rejected reward rate: %v",
+				policy.RewardRate)
+		}
+
+		return &rewardRejection{reqs: reqs}
 
 	case wtwire.CreateSessionCodeRejectSweepFeeRate:
 		return fmt.Errorf("tower rejected sweep fee rate: %v",
@@ -475,3 +588,19 @@ func (n *sessionNegotiator) tryAddress(privKey *btcec.PrivateKey,
 			createSessionReply.Code)
 	}
 }
+
+// validateRewardPkScript returns ErrInvalidRewardScript if the reward pkscript
+// returned by a tower isn't a standard script that can be paid to within a
+// justice transaction.
+func validateRewardPkScript(pkScript []byte) error {
+	if len(pkScript) == 0 {
+		return ErrInvalidRewardScript
+	}
+
+	switch txscript.GetScriptClass(pkScript) {
+	case txscript.NonStandardTy, txscript.NullDataTy:
+		return ErrInvalidRewardScript
+	}
+
+	return nil
+}
@@ -63,6 +63,15 @@ type sessionQueueConfig struct {
 	// DB provides access to the client's stable storage.
 	DB DB
 
+	// AckedUpdate is called once the tower has acked the update of the
+	// given backup.
+	AckedUpdate func(wtdb.BackupID)
+
+	// AddrBackoff tracks the backoff of the tower addresses that couldn't
+	// be dialed, allowing the queue to fail over to the tower's other
+	// addresses.
+	AddrBackoff *addrBackoff
+
 	// MinBackoff defines the initial backoff applied by the session
 	// queue before reconnecting to the tower after a failed or partially
 	// successful batch is sent. Subsequent backoff durations will grow
@@ -96,7 +105,6 @@ type sessionQueue struct {
 	queueCond    *sync.Cond
 
 	localInit *wtwire.Init
-	towerAddr *lnwire.NetAddress
 
 	seqNum uint16
 
@@ -114,17 +122,11 @@ func newSessionQueue(cfg *sessionQueueConfig) *sessionQueue {
 		cfg.ChainHash,
 	)
 
-	towerAddr := &lnwire.NetAddress{
-		IdentityKey: cfg.ClientSession.Tower.IdentityKey,
-		Address:     cfg.ClientSession.Tower.Addresses[0],
-	}
-
 	sq := &sessionQueue{
 		cfg:          cfg,
 		commitQueue:  list.New(),
 		pendingQueue: list.New(),
 		localInit:    localInit,
-		towerAddr:    towerAddr,
 		seqNum:       cfg.ClientSession.SeqNum,
 		retryBackoff: cfg.MinBackoff,
 		quit:         make(chan struct{}),
@@ -321,15 +323,17 @@ func (q *sessionQueue) sessionManager() {
 
 // drainBackups attempts to send all pending updates in the queue to the tower.
 func (q *sessionQueue) drainBackups() {
-	// First, check that we are able to dial this session's tower.
-	conn, err := q.cfg.Dial(q.cfg.ClientSession.SessionPrivKey, q.towerAddr)
+	// First, check that we are able to dial this session's tower. If none
+	// of its addresses can be reached, we'll wait until one of them is no
+	// longer backed off.
+	conn, retryIn, err := q.dialTower()
 	if err != nil {
-		log.Errorf("Unable to dial watchtower at %v: %v",
-			q.towerAddr, err)
+		towerPub := q.cfg.ClientSession.Tower.IdentityKey
+		log.Errorf("Unable to dial watchtower %x: %v",
+			towerPub.SerializeCompressed(), err)
 
-		q.increaseBackoff()
 		select {
-		case <-time.After(q.retryBackoff):
+		case <-time.After(retryIn):
 		case <-q.forceQuit:
 		}
 		return
@@ -385,6 +389,46 @@ func (q *sessionQueue) drainBackups() {
 	}
 }
 
+// dialTower attempts to connect to the session's tower at each of its addresses
+// that isn't backed off, in order of preference, returning the first
+// successful connection. Addresses that can't be dialed are backed off. If the
+// tower can't be reached, the duration after which it should be dialed again
+// is returned along with the error.
+func (q *sessionQueue) dialTower() (wtserver.Peer, time.Duration, error) {
+	towerAddrs := q.cfg.ClientSession.Tower.LNAddrs()
+	if len(towerAddrs) == 0 {
+		return nil, q.cfg.MaxBackoff, ErrNoTowerAddrs
+	}
+
+	addrs, retryIn := q.cfg.AddrBackoff.available(towerAddrs)
+	if len(addrs) == 0 {
+		return nil, retryIn, fmt.Errorf("all %d addresses backed off",
+			len(towerAddrs))
+	}
+
+	var err error
+	for _, addr := range addrs {
+		var conn wtserver.Peer
+		conn, err = q.cfg.Dial(q.cfg.ClientSession.SessionPrivKey, addr)
+		if err != nil {
+			log.Debugf("Unable to dial watchtower at %v, trying "+
+				"next address: %v", addr, err)
+
+			q.cfg.AddrBackoff.failed(addr)
+			continue
+		}
+
+		q.cfg.AddrBackoff.reset(addr)
+		return conn, 0, nil
+	}
+
+	// None of the addresses could be dialed, so we'll retry once the first
+	// of them is no longer backed off.
+	_, retryIn = q.cfg.AddrBackoff.available(towerAddrs)
+
+	return nil, retryIn, err
+}
+
 // nextStateUpdate returns the next wtwire.StateUpdate to upload to the tower.
 // If any committed updates are present, this method will reconstruct the state
 // update from the committed update using the current last applied value found
@@ -453,6 +497,7 @@ func (q *sessionQueue) nextStateUpdate() (*wtwire.StateUpdate, bool, error) {
 			BackupID:      task.id,
 			Hint:          hint,
 			EncryptedBlob: encBlob,
+			Amount:        task.totalAmt,
 		}
 
 		log.Debugf("Committing state update for session=%s seqnum=%d",
@@ -595,6 +640,8 @@ func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
 	log.Infof("Removing update session=%s seqnum=%d is_pending=%v "+
 		"from memory", q.ID(), stateUpdate.SeqNum, isPending)
 
+	var backupID wtdb.BackupID
+
 	q.queueCond.L.Lock()
 	if isPending {
 		// If a pending update was successfully sent, increment the
@@ -603,15 +650,19 @@ func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
 		// unchanged, which maintains the external view of the session's
 		// reserve status.
 		q.seqNum++
-		q.pendingQueue.Remove(q.pendingQueue.Front())
+		next := q.pendingQueue.Remove(q.pendingQueue.Front())
+		backupID = next.(*backupTask).id
 	} else {
 		// Otherwise, simply remove the update from the committed queue.
 		// This has no effect on the queues reserve status since the
 		// update had already been committed.
-		q.commitQueue.Remove(q.commitQueue.Front())
+		next := q.commitQueue.Remove(q.commitQueue.Front())
+		backupID = next.(updateWithSeqNum).update.BackupID
 	}
 	q.queueCond.L.Unlock()
 
+	q.cfg.AckedUpdate(backupID)
+
 	return nil
 }
 
@@ -636,6 +687,21 @@ func (q *sessionQueue) reserveStatus() reserveStatus {
 
 }
 
+// RemainingUpdates returns the number of tasks the sessionQueue can accept
+// before becoming exhausted.
+func (q *sessionQueue) RemainingUpdates() uint16 {
+	q.queueCond.L.Lock()
+	defer q.queueCond.L.Unlock()
+
+	numAllocated := uint32(q.seqNum) + uint32(q.pendingQueue.Len())
+	maxUpdates := uint32(q.cfg.ClientSession.Policy.MaxUpdates)
+	if numAllocated >= maxUpdates {
+		return 0
+	}
+
+	return uint16(maxUpdates - numAllocated)
+}
+
 // resetBackoff returns the connection backoff the minimum configured backoff.
 func (q *sessionQueue) resetBackoff() {
 	q.retryBackoff = q.cfg.MinBackoff
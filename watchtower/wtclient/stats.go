@@ -1,8 +1,15 @@
 package wtclient
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
+// clientStats tracks metrics about the client's operation. The stats are
+// updated concurrently by the dispatchers of each of the client's policies.
 type clientStats struct {
+	mu sync.Mutex
+
 	numTasksReceived     int
 	numTasksAccepted     int
 	numTasksIneligible   int
@@ -13,12 +20,18 @@ type clientStats struct {
 // taskReceived increments the number to backup requests the client has received
 // from active channels.
 func (s *clientStats) taskReceived() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.numTasksReceived++
 }
 
 // taskAccepted increments the number of tasks that have been assigned to active
 // session queues, and are awaiting upload to a tower.
 func (s *clientStats) taskAccepted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.numTasksAccepted++
 }
 
@@ -27,23 +40,35 @@ func (s *clientStats) taskAccepted() {
 // typically this means that the balance created dust outputs, so it may not be
 // worth backing up at all.
 func (s *clientStats) taskIneligible() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.numTasksIneligible++
 }
 
 // sessionAcquired increments the number of sessions that have been successfully
 // negotiated by the client during this execution.
 func (s *clientStats) sessionAcquired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.numSessionsAcquired++
 }
 
 // sessionExhausted increments the number of session that have become full as a
 // result of accepting backup tasks.
 func (s *clientStats) sessionExhausted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.numSessionsExhausted++
 }
 
 // String returns a human readable summary of the client's metrics.
-func (s clientStats) String() string {
+func (s *clientStats) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	return fmt.Sprintf("tasks(received=%d accepted=%d ineligible=%d) "+
 		"sessions(acquired=%d exhausted=%d)", s.numTasksReceived,
 		s.numTasksAccepted, s.numTasksIneligible, s.numSessionsAcquired,
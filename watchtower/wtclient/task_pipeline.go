@@ -1,17 +1,69 @@
 package wtclient
 
 import (
-	"container/list"
+	"container/heap"
 	"sync"
 	"time"
 )
 
-// taskPipeline implements a reliable, in-order queue that ensures its queue
-// fully drained before exiting. Stopping the taskPipeline prevents the pipeline
-// from accepting any further tasks, and will cause the pipeline to exit after
-// all updates have been delivered to the downstream receiver. If this process
-// hangs and is unable to make progress, users can optionally call ForceQuit to
-// abandon the reliable draining of the queue in order to permit shutdown.
+// queuedTask is a backupTask awaiting delivery by the taskPipeline.
+type queuedTask struct {
+	task *backupTask
+
+	// risk is the task's risk score at the time it was queued.
+	risk int64
+
+	// seq is the order in which the task was queued, used to deliver
+	// tasks of equal risk in FIFO order.
+	seq uint64
+}
+
+// taskHeap is a max-heap of queued tasks, ordered by their risk score and then
+// by the order in which they were queued. It implements heap.Interface.
+type taskHeap []*queuedTask
+
+// Len returns the number of queued tasks.
+func (h taskHeap) Len() int { return len(h) }
+
+// Less returns whether the task at index i should be delivered before the one
+// at index j.
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].risk != h[j].risk {
+		return h[i].risk > h[j].risk
+	}
+
+	return h[i].seq < h[j].seq
+}
+
+// Swap swaps the tasks at indexes i and j.
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+// Push appends a *queuedTask to the heap.
+func (h *taskHeap) Push(x interface{}) {
+	*h = append(*h, x.(*queuedTask))
+}
+
+// Pop removes the last task of the heap.
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+
+	return task
+}
+
+// taskPipeline implements a reliable, prioritized queue that ensures its queue
+// fully drained before exiting. Queued tasks are delivered in order of their
+// risk score, such that the most dangerous revoked states are backed up first
+// when many states are waiting, e.g. while no session is available. Tasks of
+// equal risk are delivered in the order they were queued. Stopping the
+// taskPipeline prevents the pipeline from accepting any further tasks, and will
+// cause the pipeline to exit after all updates have been delivered to the
+// downstream receiver. If this process hangs and is unable to make progress,
+// users can optionally call ForceQuit to abandon the reliable draining of the
+// queue in order to permit shutdown.
 type taskPipeline struct {
 	started sync.Once
 	stopped sync.Once
@@ -19,7 +71,8 @@ type taskPipeline struct {
 
 	queueMtx  sync.Mutex
 	queueCond *sync.Cond
-	queue     *list.List
+	queue     taskHeap
+	nextSeq   uint64
 
 	newBackupTasks chan *backupTask
 
@@ -31,7 +84,6 @@ type taskPipeline struct {
 // newTaskPipeline initializes a new taskPipeline.
 func newTaskPipeline() *taskPipeline {
 	rq := &taskPipeline{
-		queue:          list.New(),
 		newBackupTasks: make(chan *backupTask),
 		quit:           make(chan struct{}),
 		forceQuit:      make(chan struct{}),
@@ -113,7 +165,12 @@ func (q *taskPipeline) QueueBackupTask(task *backupTask) error {
 
 	// Queue the new task and signal the queue's condition variable to wake up
 	// the queueManager for processing.
-	q.queue.PushBack(task)
+	heap.Push(&q.queue, &queuedTask{
+		task: task,
+		risk: task.riskScore(),
+		seq:  q.nextSeq,
+	})
+	q.nextSeq++
 	q.queueCond.L.Unlock()
 
 	q.queueCond.Signal()
@@ -131,7 +188,7 @@ func (q *taskPipeline) queueManager() {
 
 	for {
 		q.queueCond.L.Lock()
-		for q.queue.Front() == nil {
+		for q.queue.Len() == 0 {
 			q.queueCond.Wait()
 
 			select {
@@ -152,9 +209,9 @@ func (q *taskPipeline) queueManager() {
 			}
 		}
 
-		// Pop the first element from the queue.
-		e := q.queue.Front()
-		task := q.queue.Remove(e).(*backupTask)
+		// Pop the riskiest task from the queue. Tasks queued while it
+		// awaits delivery are only considered for the next delivery.
+		task := heap.Pop(&q.queue).(*queuedTask).task
 		q.queueCond.L.Unlock()
 
 		select {
@@ -0,0 +1,67 @@
+package wtclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/watchtower/wtdb"
+)
+
+// TestTaskPipelinePriority asserts that the taskPipeline delivers queued tasks
+// in order of their risk score, and in FIFO order among tasks of equal risk.
+func TestTaskPipelinePriority(t *testing.T) {
+	t.Parallel()
+
+	newTask := func(height uint64, amt btcutil.Amount,
+		numHTLCs int) *backupTask {
+
+		return &backupTask{
+			id: wtdb.BackupID{CommitHeight: height},
+			breachInfo: &lnwallet.BreachRetribution{
+				HtlcRetributions: make(
+					[]lnwallet.HtlcRetribution, numHTLCs,
+				),
+			},
+			totalAmt: amt,
+		}
+	}
+
+	tasks := []*backupTask{
+		newTask(0, 1000, 0),
+		newTask(1, 50000, 0),
+		newTask(2, 1000, 0),
+		newTask(3, 1000, 5),
+		newTask(4, 20000, 4),
+	}
+
+	// Queue all tasks before starting the pipeline, such that they're
+	// all awaiting delivery at once.
+	pipeline := newTaskPipeline()
+	for _, task := range tasks {
+		if err := pipeline.QueueBackupTask(task); err != nil {
+			t.Fatalf("unable to queue task: %v", err)
+		}
+	}
+	pipeline.Start()
+	defer pipeline.ForceQuit()
+
+	// The HTLCs of a state raise its risk beyond its balance, such that
+	// both tasks with HTLCs should be delivered before the one with the
+	// highest balance. The remaining tasks of equal risk should be
+	// delivered in the order they were queued.
+	expHeights := []uint64{4, 3, 1, 0, 2}
+	for _, expHeight := range expHeights {
+		select {
+		case task := <-pipeline.NewBackupTasks():
+			if task.id.CommitHeight != expHeight {
+				t.Fatalf("expected task at height %d, got %d",
+					expHeight, task.id.CommitHeight)
+			}
+
+		case <-time.After(time.Second):
+			t.Fatalf("task at height %d not delivered", expHeight)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/litecoinfinance/btcd/btcec"
+	"github.com/litecoinfinance/btcutil"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/watchtower/wtpolicy"
 )
@@ -38,6 +39,11 @@ var (
 	// created because session key index differs from the reserved key
 	// index.
 	ErrIncorrectKeyIndex = errors.New("incorrect key index")
+
+	// ErrBackupAlreadyQueued signals that a backup could not be queued
+	// because it is already pending, or has already been committed to a
+	// session.
+	ErrBackupAlreadyQueued = errors.New("backup already queued")
 )
 
 // ClientSession encapsulates a SessionInfo returned from a successful
@@ -94,6 +100,22 @@ type ClientSession struct {
 	// AckedUpdates is a map from sequence number to backup id to record
 	// which revoked states were uploaded via this session.
 	AckedUpdates map[uint16]BackupID
+
+	// AckedAmounts is a map from the sequence number of each acked update
+	// to the value of the revoked outputs the update allows the tower to
+	// sweep.
+	AckedAmounts map[uint16]btcutil.Amount
+}
+
+// RemainingUpdates returns the number of sequence numbers that have yet to be
+// allocated within the session. Once zero, the session is exhausted and can't
+// accept any further updates.
+func (s *ClientSession) RemainingUpdates() uint16 {
+	if s.SeqNum >= s.Policy.MaxUpdates {
+		return 0
+	}
+
+	return s.Policy.MaxUpdates - s.SeqNum
 }
 
 // BackupID identifies a particular revoked, remote commitment by channel id and
@@ -118,4 +140,8 @@ type CommittedUpdate struct {
 	// exacting justice if the commitment transaction matching the breach
 	// hint is broadcast.
 	EncryptedBlob []byte
+
+	// Amount is the total value of the revoked outputs swept by the
+	// justice transaction, before deducting any fees or reward.
+	Amount btcutil.Amount
 }
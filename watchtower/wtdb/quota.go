@@ -0,0 +1,192 @@
+package wtdb
+
+import (
+	"errors"
+
+	"github.com/coreos/bbolt"
+)
+
+var (
+	// sessionUsageBkt is a bucket containing the number of bytes occupied
+	// by the state updates of each session.
+	//  session id -> uint64
+	sessionUsageBkt = []byte("session-usage-bucket")
+
+	// totalUsageKey is a static key used to retrieve the number of bytes
+	// occupied by the state updates of all sessions from the metadataBkt.
+	totalUsageKey = []byte("total-usage")
+
+	// ErrClientQuotaExceeded signals that a state update was rejected, as
+	// storing it would exceed the space allotted to a single client.
+	ErrClientQuotaExceeded = errors.New("client storage quota exceeded")
+
+	// ErrTowerQuotaExceeded signals that a state update was rejected, as
+	// storing it would exceed the space allotted to all clients combined.
+	ErrTowerQuotaExceeded = errors.New("tower storage quota exceeded")
+)
+
+// Quota bounds the space occupied by the state updates stored within the tower
+// database. A limit of zero disables the respective quota.
+type Quota struct {
+	// MaxClientBytes is the maximum number of bytes the state updates of
+	// a single client, as identified by its session id, may occupy.
+	MaxClientBytes uint64
+
+	// MaxTotalBytes is the maximum number of bytes the state updates of
+	// all clients may occupy combined.
+	MaxTotalBytes uint64
+}
+
+// updateUsage replaces oldSize bytes with newSize bytes in the usage accounted
+// to the given session, as well as in the tower's total usage. If quota is
+// non-nil and the change would grow either usage beyond its limit,
+// ErrClientQuotaExceeded or ErrTowerQuotaExceeded is returned respectively.
+func updateUsage(tx *bbolt.Tx, id *SessionID, oldSize, newSize uint64,
+	quota *Quota) error {
+
+	sessionUsage := tx.Bucket(sessionUsageBkt)
+	if sessionUsage == nil {
+		return ErrUninitializedDB
+	}
+
+	metadata := tx.Bucket(metadataBkt)
+	if metadata == nil {
+		return ErrUninitializedDB
+	}
+
+	clientBytes := applyDelta(
+		getUsage(sessionUsage, id[:]), oldSize, newSize,
+	)
+	totalBytes := applyDelta(
+		getUsage(metadata, totalUsageKey), oldSize, newSize,
+	)
+
+	// Shrinking usage is always permitted, such that clients can replace
+	// their updates even if the quota has been lowered in the meantime.
+	if quota != nil && newSize > oldSize {
+		switch {
+		case quota.MaxClientBytes != 0 &&
+			clientBytes > quota.MaxClientBytes:
+
+			return ErrClientQuotaExceeded
+
+		case quota.MaxTotalBytes != 0 &&
+			totalBytes > quota.MaxTotalBytes:
+
+			return ErrTowerQuotaExceeded
+		}
+	}
+
+	err := putUsage(sessionUsage, id[:], clientBytes)
+	if err != nil {
+		return err
+	}
+
+	return putUsage(metadata, totalUsageKey, totalBytes)
+}
+
+// removeSessionUsage deducts the usage accounted to the given session from the
+// tower's total usage, and removes the session's usage entry.
+func removeSessionUsage(tx *bbolt.Tx, id *SessionID) error {
+	sessionUsage := tx.Bucket(sessionUsageBkt)
+	if sessionUsage == nil {
+		return ErrUninitializedDB
+	}
+
+	metadata := tx.Bucket(metadataBkt)
+	if metadata == nil {
+		return ErrUninitializedDB
+	}
+
+	totalBytes := applyDelta(
+		getUsage(metadata, totalUsageKey),
+		getUsage(sessionUsage, id[:]), 0,
+	)
+	err := putUsage(metadata, totalUsageKey, totalBytes)
+	if err != nil {
+		return err
+	}
+
+	return sessionUsage.Delete(id[:])
+}
+
+// applyDelta replaces oldSize bytes with newSize bytes in the given usage,
+// guarding against underflow.
+func applyDelta(usage, oldSize, newSize uint64) uint64 {
+	if oldSize > usage {
+		usage = 0
+	} else {
+		usage -= oldSize
+	}
+
+	return usage + newSize
+}
+
+// getUsage returns the usage stored under the given key, or zero if none has
+// been recorded.
+func getUsage(bkt *bbolt.Bucket, key []byte) uint64 {
+	usageBytes := bkt.Get(key)
+	if len(usageBytes) != 8 {
+		return 0
+	}
+
+	return byteOrder.Uint64(usageBytes)
+}
+
+// putUsage stores the given usage under the given key.
+func putUsage(bkt *bbolt.Bucket, key []byte, usage uint64) error {
+	var usageBytes [8]byte
+	byteOrder.PutUint64(usageBytes[:], usage)
+
+	return bkt.Put(key, usageBytes[:])
+}
+
+// migrateStorageUsage initializes the usage accounted to each session, along
+// with the tower's total usage, from the state updates already stored within
+// the database.
+func migrateStorageUsage(tx *bbolt.Tx) error {
+	sessionUsage, err := tx.CreateBucketIfNotExists(sessionUsageBkt)
+	if err != nil {
+		return err
+	}
+
+	metadata := tx.Bucket(metadataBkt)
+	if metadata == nil {
+		return ErrUninitializedDB
+	}
+
+	usage := make(map[SessionID]uint64)
+	var totalBytes uint64
+
+	updates := tx.Bucket(updatesBkt)
+	if updates != nil {
+		err := updates.ForEach(func(hint, _ []byte) error {
+			updatesForHint := updates.Bucket(hint)
+			if updatesForHint == nil {
+				return nil
+			}
+
+			return updatesForHint.ForEach(func(k, v []byte) error {
+				var id SessionID
+				copy(id[:], k)
+
+				usage[id] += uint64(len(v))
+				totalBytes += uint64(len(v))
+
+				return nil
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for id, sessionBytes := range usage {
+		err := putUsage(sessionUsage, id[:], sessionBytes)
+		if err != nil {
+			return err
+		}
+	}
+
+	return putUsage(metadata, totalUsageKey, totalBytes)
+}
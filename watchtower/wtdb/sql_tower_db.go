@@ -0,0 +1,874 @@
+package wtdb
+
+import (
+	"bytes"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
+	"github.com/litecoinfinance/lnd/chainntnfs"
+	"github.com/litecoinfinance/lnd/channeldb"
+)
+
+const (
+	// sqlSchemaVersion is the version of the schema created by
+	// SQLTowerDB, recorded within the tower_metadata table such that
+	// future versions can migrate existing tables.
+	sqlSchemaVersion = 1
+
+	// sqlVersionKey is the key of the schema version within the
+	// tower_metadata table.
+	sqlVersionKey = "version"
+)
+
+// sqlSchema holds the statements creating the tables of SQLTowerDB, which
+// mirror the buckets of TowerDB. State updates are deleted along with their
+// session, and the space occupied by the updates of each session is tracked
+// within its row.
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS tower_metadata (
+		key TEXT PRIMARY KEY,
+		value BIGINT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS tower_sessions (
+		id BYTEA PRIMARY KEY,
+		info BYTEA NOT NULL,
+		last_activity BIGINT,
+		usage BIGINT NOT NULL DEFAULT 0
+	)`,
+	`CREATE TABLE IF NOT EXISTS tower_state_updates (
+		hint BYTEA NOT NULL,
+		session_id BYTEA NOT NULL
+			REFERENCES tower_sessions (id) ON DELETE CASCADE,
+		encoded BYTEA NOT NULL,
+		PRIMARY KEY (hint, session_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS tower_state_updates_session_idx
+		ON tower_state_updates (session_id)`,
+	`CREATE TABLE IF NOT EXISTS tower_blacklist (
+		session_id BYTEA PRIMARY KEY
+	)`,
+	`CREATE TABLE IF NOT EXISTS tower_confirmed_breaches (
+		height BIGINT NOT NULL,
+		hint BYTEA NOT NULL,
+		PRIMARY KEY (height, hint)
+	)`,
+	`CREATE TABLE IF NOT EXISTS tower_lookout_tip (
+		id INTEGER PRIMARY KEY CHECK (id = 0),
+		hash BYTEA NOT NULL,
+		height BIGINT NOT NULL
+	)`,
+}
+
+// SQLTowerDB is a tower database backed by Postgres, providing the same
+// persistent storage as TowerDB to the wtserver and lookout subsystems. Unlike
+// the single bolt file of TowerDB, which serializes all writes, it allows the
+// state updates of distinct sessions to be inserted concurrently, making it
+// suitable for towers serving many clients.
+type SQLTowerDB struct {
+	db *sql.DB
+
+	quotaMtx sync.RWMutex
+	quota    Quota
+}
+
+// OpenPostgresTowerDB connects to the Postgres database identified by the
+// given data source name, creating the tables of the tower database if they
+// don't exist yet. Any attempt to open a database whose schema version is
+// higher than the latest known version will fail to prevent accidental
+// reversion.
+func OpenPostgresTowerDB(dsn string) (*SQLTowerDB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	towerDB := &SQLTowerDB{
+		db: db,
+	}
+
+	err = towerDB.update(initSQLSchema)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return towerDB, nil
+}
+
+// initSQLSchema creates the tables of the tower database, and records the
+// schema version if the database is fresh.
+func initSQLSchema(tx *sql.Tx) error {
+	for _, stmt := range sqlSchema {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	var version int64
+	err := tx.QueryRow(
+		`SELECT value FROM tower_metadata WHERE key = $1`,
+		sqlVersionKey,
+	).Scan(&version)
+	switch {
+	case err == sql.ErrNoRows:
+		_, err = tx.Exec(
+			`INSERT INTO tower_metadata (key, value)
+			VALUES ($1, $2)`,
+			sqlVersionKey, sqlSchemaVersion,
+		)
+		return err
+
+	case err != nil:
+		return err
+
+	// The schema is newer than any known version, fail to prevent
+	// reversion.
+	case version > sqlSchemaVersion:
+		return channeldb.ErrDBReversion
+	}
+
+	return nil
+}
+
+// update executes the given closure within a read-write transaction, which is
+// committed if the closure succeeds and rolled back otherwise.
+func (t *SQLTowerDB) update(f func(*sql.Tx) error) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Version returns the database's current schema version.
+func (t *SQLTowerDB) Version() (uint32, error) {
+	var version int64
+	err := t.db.QueryRow(
+		`SELECT value FROM tower_metadata WHERE key = $1`,
+		sqlVersionKey,
+	).Scan(&version)
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, ErrNoDBVersion
+	case err != nil:
+		return 0, err
+	}
+
+	return uint32(version), nil
+}
+
+// Close closes the connections to the underlying database.
+func (t *SQLTowerDB) Close() error {
+	return t.db.Close()
+}
+
+// GetSessionInfo retrieves the session for the passed session id. An error is
+// returned if the session could not be found.
+func (t *SQLTowerDB) GetSessionInfo(id *SessionID) (*SessionInfo, error) {
+	var info []byte
+	err := t.db.QueryRow(
+		`SELECT info FROM tower_sessions WHERE id = $1`, id[:],
+	).Scan(&info)
+
+	return decodeSQLSession(info, err)
+}
+
+// InsertSessionInfo records a negotiated session in the tower database. An
+// error is returned if the session already exists.
+func (t *SQLTowerDB) InsertSessionInfo(session *SessionInfo) error {
+	return t.update(func(tx *sql.Tx) error {
+		dbSession, err := getSQLSessionForUpdate(tx, &session.ID)
+		switch {
+		case err == ErrSessionNotFound:
+			// proceed.
+
+		case err != nil:
+			return err
+
+		case dbSession.LastApplied > 0:
+			return ErrSessionAlreadyExists
+		}
+
+		var b bytes.Buffer
+		if err := session.Encode(&b); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			`INSERT INTO tower_sessions (id, info, last_activity)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (id) DO UPDATE SET
+				info = excluded.info,
+				last_activity = excluded.last_activity`,
+			session.ID[:], b.Bytes(), time.Now().UnixNano(),
+		)
+		return err
+	})
+}
+
+// InsertStateUpdate stores an update sent by the client after validating that
+// the update is well-formed in the context of other updates sent for the same
+// session. This include verifying that the sequence number is incremented
+// properly and the last applied values echoed by the client are sane. The
+// session's row is locked until the update is stored, such that updates of
+// the same session are applied in order.
+func (t *SQLTowerDB) InsertStateUpdate(
+	update *SessionStateUpdate) (uint16, error) {
+
+	var lastApplied uint16
+	err := t.update(func(tx *sql.Tx) error {
+		// Fetch the session corresponding to the update's session id.
+		// This will be used to validate that the update's sequence
+		// number and last applied values are sane.
+		session, err := getSQLSessionForUpdate(tx, &update.ID)
+		if err != nil {
+			return err
+		}
+
+		// Validate the update against the current state of the session.
+		err = session.AcceptUpdateSequence(
+			update.SeqNum, update.LastApplied,
+		)
+		if err != nil {
+			return err
+		}
+		lastApplied = session.LastApplied
+
+		var info, b bytes.Buffer
+		if err := session.Encode(&info); err != nil {
+			return err
+		}
+		if err := update.Encode(&b); err != nil {
+			return err
+		}
+
+		// Account for the space occupied by the update, replacing that
+		// of any prior update stored for the same hint. This fails if
+		// the client or the tower would exceed its storage quota.
+		var oldSize int64
+		err = tx.QueryRow(
+			`SELECT octet_length(encoded) FROM tower_state_updates
+			WHERE hint = $1 AND session_id = $2`,
+			update.Hint[:], update.ID[:],
+		).Scan(&oldSize)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		quota := t.Quota()
+		err = updateSQLUsage(
+			tx, &update.ID, uint64(oldSize), uint64(b.Len()),
+			&quota,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Store the updated session to persist the updated last applied
+		// values.
+		_, err = tx.Exec(
+			`UPDATE tower_sessions SET info = $2, last_activity = $3
+			WHERE id = $1`,
+			update.ID[:], info.Bytes(), time.Now().UnixNano(),
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			`INSERT INTO tower_state_updates
+				(hint, session_id, encoded)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (hint, session_id) DO UPDATE SET
+				encoded = excluded.encoded`,
+			update.Hint[:], update.ID[:], b.Bytes(),
+		)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return lastApplied, nil
+}
+
+// DeleteSession removes all data associated with a particular session id from
+// the tower's database.
+func (t *SQLTowerDB) DeleteSession(target SessionID) error {
+	return t.update(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			`DELETE FROM tower_sessions WHERE id = $1`, target[:],
+		)
+		if err != nil {
+			return err
+		}
+
+		// Fail if the session doesn't exist.
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrSessionNotFound
+		}
+
+		return nil
+	})
+}
+
+// QueryMatches searches against all known state updates for any that match the
+// passed breachHints. More than one Match will be returned for a given hint if
+// they exist in the database.
+func (t *SQLTowerDB) QueryMatches(breachHints []BreachHint) ([]Match, error) {
+	if len(breachHints) == 0 {
+		return nil, nil
+	}
+
+	hints := make(pq.ByteaArray, 0, len(breachHints))
+	for i := range breachHints {
+		hints = append(hints, breachHints[i][:])
+	}
+
+	rows, err := t.db.Query(
+		`SELECT u.hint, u.encoded, s.info
+		FROM tower_state_updates u
+		JOIN tower_sessions s ON s.id = u.session_id
+		WHERE u.hint = ANY($1)`, hints,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []Match
+	for rows.Next() {
+		var hintBytes, encoded, info []byte
+		err := rows.Scan(&hintBytes, &encoded, &info)
+		if err != nil {
+			return nil, err
+		}
+
+		// The session info contains further instructions for how to
+		// process the state update.
+		session, err := decodeSQLSession(info, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// Decode the state update containing the encrypted blob.
+		update := &SessionStateUpdate{}
+		err = update.Decode(bytes.NewReader(encoded))
+		if err != nil {
+			return nil, err
+		}
+
+		var hint BreachHint
+		copy(hint[:], hintBytes)
+
+		matches = append(matches, Match{
+			ID:            session.ID,
+			SeqNum:        update.SeqNum,
+			Hint:          hint,
+			EncryptedBlob: update.EncryptedBlob,
+			SessionInfo:   session,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// SetLookoutTip stores the provided epoch as the latest lookout tip epoch in
+// the tower database.
+func (t *SQLTowerDB) SetLookoutTip(epoch *chainntnfs.BlockEpoch) error {
+	_, err := t.db.Exec(
+		`INSERT INTO tower_lookout_tip (id, hash, height)
+		VALUES (0, $1, $2)
+		ON CONFLICT (id) DO UPDATE SET
+			hash = excluded.hash, height = excluded.height`,
+		epoch.Hash[:], epoch.Height,
+	)
+	return err
+}
+
+// GetLookoutTip retrieves the current lookout tip block epoch from the tower
+// database. A nil epoch is returned if none has been stored.
+func (t *SQLTowerDB) GetLookoutTip() (*chainntnfs.BlockEpoch, error) {
+	var (
+		hashBytes []byte
+		height    int32
+	)
+	err := t.db.QueryRow(
+		`SELECT hash, height FROM tower_lookout_tip WHERE id = 0`,
+	).Scan(&hashBytes, &height)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var hash chainhash.Hash
+	copy(hash[:], hashBytes)
+
+	return &chainntnfs.BlockEpoch{
+		Hash:   &hash,
+		Height: height,
+	}, nil
+}
+
+// MarkBreachesConfirmed records that the breaches identified by the given
+// hints confirmed at the given height, such that their state updates can be
+// evicted once the breaches are buried deep enough. Any breaches previously
+// recorded at the same height or above are discarded, as their blocks must
+// have been reorged out of the chain.
+//
+// NOTE: This method must be called for every connected block in order, even
+// if the block contains no breaches, to ensure stale records are discarded.
+func (t *SQLTowerDB) MarkBreachesConfirmed(height uint32,
+	hints []BreachHint) error {
+
+	return t.update(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			`DELETE FROM tower_confirmed_breaches
+			WHERE height >= $1`,
+			height,
+		)
+		if err != nil {
+			return err
+		}
+
+		numStale, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if numStale > 0 {
+			log.Debugf("Discarding %d breaches recorded at or "+
+				"above height=%d after reorg", numStale,
+				height)
+		}
+
+		for _, hint := range hints {
+			_, err := tx.Exec(
+				`INSERT INTO tower_confirmed_breaches
+				(height, hint) VALUES ($1, $2)
+				ON CONFLICT DO NOTHING`,
+				height, hint[:],
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// EvictConfirmedBreaches deletes the state updates of all breaches that
+// confirmed at or below the given height, returning the number of state
+// updates deleted. The storage occupied by the deleted updates is released
+// from the quotas of their clients.
+func (t *SQLTowerDB) EvictConfirmedBreaches(height uint32) (uint64, error) {
+	var numEvicted uint64
+	err := t.update(func(tx *sql.Tx) error {
+		numEvicted = 0
+
+		rows, err := tx.Query(
+			`DELETE FROM tower_state_updates u
+			USING tower_confirmed_breaches c
+			WHERE c.hint = u.hint AND c.height <= $1
+			RETURNING u.session_id, octet_length(u.encoded)`,
+			height,
+		)
+		if err != nil {
+			return err
+		}
+
+		sizes := make(map[SessionID]uint64)
+		for rows.Next() {
+			var (
+				idBytes []byte
+				size    int64
+			)
+			if err := rows.Scan(&idBytes, &size); err != nil {
+				rows.Close()
+				return err
+			}
+
+			var id SessionID
+			copy(id[:], idBytes)
+			sizes[id] += uint64(size)
+			numEvicted++
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return err
+		}
+		rows.Close()
+
+		// Release the storage of each session in a consistent order,
+		// as their rows are locked in doing so.
+		ids := make([]SessionID, 0, len(sizes))
+		for id := range sizes {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			return bytes.Compare(ids[i][:], ids[j][:]) < 0
+		})
+
+		for i := range ids {
+			err := updateSQLUsage(
+				tx, &ids[i], sizes[ids[i]], 0, nil,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(
+			`DELETE FROM tower_confirmed_breaches
+			WHERE height <= $1`,
+			height,
+		)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numEvicted, nil
+}
+
+// SetQuota sets the storage quota enforced when inserting new state updates.
+// Updates that are already stored are retained, even if they exceed the new
+// quota.
+func (t *SQLTowerDB) SetQuota(quota Quota) {
+	t.quotaMtx.Lock()
+	defer t.quotaMtx.Unlock()
+
+	t.quota = quota
+}
+
+// Quota returns the storage quota enforced when inserting new state updates.
+func (t *SQLTowerDB) Quota() Quota {
+	t.quotaMtx.RLock()
+	defer t.quotaMtx.RUnlock()
+
+	return t.quota
+}
+
+// ListSessions returns a summary of every session negotiated with a client.
+func (t *SQLTowerDB) ListSessions() ([]*SessionSummary, error) {
+	rows, err := t.db.Query(
+		`SELECT s.info, s.last_activity,
+			(SELECT COUNT(*) FROM tower_state_updates u
+			WHERE u.session_id = s.id)
+		FROM tower_sessions s`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []*SessionSummary
+	for rows.Next() {
+		var (
+			info         []byte
+			lastActivity sql.NullInt64
+			numUpdates   int64
+		)
+		err := rows.Scan(&info, &lastActivity, &numUpdates)
+		if err != nil {
+			return nil, err
+		}
+
+		session, err := decodeSQLSession(info, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		summary := &SessionSummary{
+			SessionInfo: session,
+			NumUpdates:  uint64(numUpdates),
+		}
+		if lastActivity.Valid {
+			summary.LastActivity = time.Unix(0, lastActivity.Int64)
+		}
+
+		summaries = append(summaries, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// Stats returns a summary of the resources occupied within the database. The
+// size reported is that of the entire Postgres database.
+func (t *SQLTowerDB) Stats() (*TowerStats, error) {
+	var numSessions, numUpdates, updateBytes, dbSize, numBlacklisted int64
+	err := t.db.QueryRow(
+		`SELECT
+			(SELECT COUNT(*) FROM tower_sessions),
+			(SELECT COUNT(*) FROM tower_state_updates),
+			(SELECT COALESCE(SUM(octet_length(encoded)), 0)
+				FROM tower_state_updates),
+			pg_database_size(current_database()),
+			(SELECT COUNT(*) FROM tower_blacklist)`,
+	).Scan(&numSessions, &numUpdates, &updateBytes, &dbSize,
+		&numBlacklisted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TowerStats{
+		NumSessions:    uint64(numSessions),
+		NumUpdates:     uint64(numUpdates),
+		UpdateBytes:    uint64(updateBytes),
+		DBSize:         uint64(dbSize),
+		NumBlacklisted: uint64(numBlacklisted),
+	}, nil
+}
+
+// PruneInactiveSessions deletes all sessions that haven't been created or
+// updated by their client since the given cutoff, along with their state
+// updates, returning the ids of the deleted sessions. Sessions whose activity
+// hasn't been tracked yet are considered active as of now, such that they
+// can only be pruned once they've been inactive for the same window.
+//
+// NOTE: Clients may still rely on the state updates of inactive sessions to
+// protect their channels, so the cutoff should be chosen conservatively.
+func (t *SQLTowerDB) PruneInactiveSessions(
+	cutoff time.Time) ([]SessionID, error) {
+
+	var pruned []SessionID
+	err := t.update(func(tx *sql.Tx) error {
+		pruned = nil
+
+		rows, err := tx.Query(
+			`DELETE FROM tower_sessions WHERE last_activity < $1
+			RETURNING id`,
+			cutoff.UnixNano(),
+		)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var idBytes []byte
+			if err := rows.Scan(&idBytes); err != nil {
+				rows.Close()
+				return err
+			}
+
+			var id SessionID
+			copy(id[:], idBytes)
+			pruned = append(pruned, id)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return err
+		}
+		rows.Close()
+
+		_, err = tx.Exec(
+			`UPDATE tower_sessions SET last_activity = $1
+			WHERE last_activity IS NULL`,
+			time.Now().UnixNano(),
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pruned, nil
+}
+
+// BlacklistClient prevents the client with the given session id from
+// connecting to the tower. Existing sessions and state updates of the client
+// are left untouched.
+func (t *SQLTowerDB) BlacklistClient(id SessionID) error {
+	_, err := t.db.Exec(
+		`INSERT INTO tower_blacklist (session_id) VALUES ($1)
+		ON CONFLICT DO NOTHING`, id[:],
+	)
+	return err
+}
+
+// UnblacklistClient allows a previously blacklisted client to connect to the
+// tower once again. ErrClientNotBlacklisted is returned if the client isn't
+// blacklisted.
+func (t *SQLTowerDB) UnblacklistClient(id SessionID) error {
+	res, err := t.db.Exec(
+		`DELETE FROM tower_blacklist WHERE session_id = $1`, id[:],
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrClientNotBlacklisted
+	}
+
+	return nil
+}
+
+// IsBlacklisted returns whether the client with the given session id has been
+// blacklisted.
+func (t *SQLTowerDB) IsBlacklisted(id *SessionID) (bool, error) {
+	var blacklisted bool
+	err := t.db.QueryRow(
+		`SELECT EXISTS (
+			SELECT 1 FROM tower_blacklist WHERE session_id = $1
+		)`, id[:],
+	).Scan(&blacklisted)
+	if err != nil {
+		return false, err
+	}
+
+	return blacklisted, nil
+}
+
+// ListBlacklistedClients returns the session ids of all blacklisted clients.
+func (t *SQLTowerDB) ListBlacklistedClients() ([]SessionID, error) {
+	rows, err := t.db.Query(
+		`SELECT session_id FROM tower_blacklist ORDER BY session_id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []SessionID
+	for rows.Next() {
+		var idBytes []byte
+		if err := rows.Scan(&idBytes); err != nil {
+			return nil, err
+		}
+
+		var id SessionID
+		copy(id[:], idBytes)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// getSQLSessionForUpdate retrieves the session with the given id, locking its
+// row until the transaction completes.
+func getSQLSessionForUpdate(tx *sql.Tx, id *SessionID) (*SessionInfo, error) {
+	var info []byte
+	err := tx.QueryRow(
+		`SELECT info FROM tower_sessions WHERE id = $1 FOR UPDATE`,
+		id[:],
+	).Scan(&info)
+
+	return decodeSQLSession(info, err)
+}
+
+// decodeSQLSession decodes the session info retrieved by a query, given the
+// error the query's row was scanned with. ErrSessionNotFound is returned if
+// the query returned no rows.
+func decodeSQLSession(info []byte, scanErr error) (*SessionInfo, error) {
+	switch {
+	case scanErr == sql.ErrNoRows:
+		return nil, ErrSessionNotFound
+	case scanErr != nil:
+		return nil, scanErr
+	}
+
+	var session SessionInfo
+	err := session.Decode(bytes.NewReader(info))
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// updateSQLUsage replaces oldSize bytes with newSize bytes in the usage
+// accounted to the given session. If quota is non-nil and the change would
+// grow the session's usage, or that of all sessions combined, beyond its
+// limit, ErrClientQuotaExceeded or ErrTowerQuotaExceeded is returned
+// respectively.
+//
+// NOTE: The tower's total usage is summed over all sessions without locking
+// them, so concurrent inserts by distinct clients may slightly overshoot
+// MaxTotalBytes. The quotas of individual clients are enforced exactly.
+func updateSQLUsage(tx *sql.Tx, id *SessionID, oldSize, newSize uint64,
+	quota *Quota) error {
+
+	var usage int64
+	err := tx.QueryRow(
+		`SELECT usage FROM tower_sessions WHERE id = $1 FOR UPDATE`,
+		id[:],
+	).Scan(&usage)
+	switch {
+	case err == sql.ErrNoRows:
+		return ErrSessionNotFound
+	case err != nil:
+		return err
+	}
+
+	clientBytes := applyDelta(uint64(usage), oldSize, newSize)
+
+	// Shrinking usage is always permitted, such that clients can replace
+	// their updates even if the quota has been lowered in the meantime.
+	if quota != nil && newSize > oldSize {
+		if quota.MaxClientBytes != 0 &&
+			clientBytes > quota.MaxClientBytes {
+
+			return ErrClientQuotaExceeded
+		}
+
+		if quota.MaxTotalBytes != 0 {
+			var total int64
+			err := tx.QueryRow(
+				`SELECT COALESCE(SUM(usage), 0)
+				FROM tower_sessions`,
+			).Scan(&total)
+			if err != nil {
+				return err
+			}
+
+			totalBytes := applyDelta(
+				uint64(total), oldSize, newSize,
+			)
+			if totalBytes > quota.MaxTotalBytes {
+				return ErrTowerQuotaExceeded
+			}
+		}
+	}
+
+	_, err = tx.Exec(
+		`UPDATE tower_sessions SET usage = $2 WHERE id = $1`,
+		id[:], int64(clientBytes),
+	)
+	return err
+}
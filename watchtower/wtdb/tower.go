@@ -53,6 +53,16 @@ func (t *Tower) AddAddress(addr net.Addr) {
 	t.Addresses = append([]net.Addr{addr}, t.Addresses...)
 }
 
+// SetAddresses replaces the tower's in-memory list of addresses with the given
+// addresses, which will be tried in the given order.
+func (t *Tower) SetAddresses(addrs []net.Addr) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.Addresses = make([]net.Addr, len(addrs))
+	copy(t.Addresses, addrs)
+}
+
 // LNAddrs generates a list of lnwire.NetAddress from a Tower instance's
 // addresses. This can be used to have a client try multiple addresses for the
 // same Tower.
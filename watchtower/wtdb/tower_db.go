@@ -6,6 +6,8 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/coreos/bbolt"
@@ -40,6 +42,22 @@ var (
 	//             => hint2 -> []byte{}
 	updateIndexBkt = []byte("update-index-bucket")
 
+	// sessionActivityBkt is a bucket containing the time at which each
+	// session was last created or updated by its client.
+	//  session id -> unix timestamp in nanoseconds
+	sessionActivityBkt = []byte("session-activity-bucket")
+
+	// blacklistBkt is a bucket containing the session ids of all clients
+	// that are no longer allowed to connect to the tower.
+	//  session id -> []byte{}
+	blacklistBkt = []byte("blacklist-bucket")
+
+	// confirmedBreachesBkt is a bucket containing the breach hints of all
+	// breaches that have been matched by the lookout, bucketed by the
+	// height at which the breach transaction confirmed.
+	//  height => hint -> []byte{}
+	confirmedBreachesBkt = []byte("confirmed-breaches-bucket")
+
 	// lookoutTipBkt is a bucket containing the last block epoch processed
 	// by the lookout subsystem. It has one key, lookoutTipKey.
 	//   lookoutTipKey -> block epoch
@@ -68,14 +86,55 @@ var (
 	// initialized index for tracking its own state updates.
 	ErrNoSessionHintIndex = errors.New("session hint index missing")
 
+	// ErrClientNotBlacklisted signals that a client could not be removed
+	// from the blacklist, as it isn't blacklisted.
+	ErrClientNotBlacklisted = errors.New("client not blacklisted")
+
 	byteOrder = binary.BigEndian
 )
 
+// SessionSummary describes a session negotiated with a client, along with the
+// resources it occupies within the tower.
+type SessionSummary struct {
+	// SessionInfo holds the negotiated session parameters.
+	*SessionInfo
+
+	// NumUpdates is the number of state updates stored for the session.
+	NumUpdates uint64
+
+	// LastActivity is the time at which the session was last created or
+	// updated by the client. It is zero for sessions whose activity hasn't
+	// been tracked yet.
+	LastActivity time.Time
+}
+
+// TowerStats summarizes the resources occupied within the tower database.
+type TowerStats struct {
+	// NumSessions is the number of sessions negotiated with clients.
+	NumSessions uint64
+
+	// NumUpdates is the number of state updates, and therefore encrypted
+	// blobs, stored across all sessions.
+	NumUpdates uint64
+
+	// UpdateBytes is the total size of all stored state updates.
+	UpdateBytes uint64
+
+	// DBSize is the size of the database file.
+	DBSize uint64
+
+	// NumBlacklisted is the number of blacklisted clients.
+	NumBlacklisted uint64
+}
+
 // TowerDB is single database providing a persistent storage engine for the
 // wtserver and lookout subsystems.
 type TowerDB struct {
 	db     *bbolt.DB
 	dbPath string
+
+	quotaMtx sync.RWMutex
+	quota    Quota
 }
 
 // OpenTowerDB opens the tower database given the path to the database's
@@ -182,6 +241,10 @@ func initTowerDBBuckets(tx *bbolt.Tx) error {
 		updateIndexBkt,
 		updatesBkt,
 		lookoutTipBkt,
+		sessionActivityBkt,
+		blacklistBkt,
+		sessionUsageBkt,
+		confirmedBreachesBkt,
 	}
 
 	for _, bucket := range buckets {
@@ -312,6 +375,11 @@ func (t *TowerDB) InsertSessionInfo(session *SessionInfo) error {
 			return err
 		}
 
+		err = touchSessionActivity(tx, &session.ID, time.Now())
+		if err != nil {
+			return err
+		}
+
 		// Initialize the session-hint index which will be used to track
 		// all updates added for this session. Upon deletion, we will
 		// consult the index to determine exactly which updates should
@@ -371,6 +439,11 @@ func (t *TowerDB) InsertStateUpdate(update *SessionStateUpdate) (uint16, error)
 			return err
 		}
 
+		err = touchSessionActivity(tx, &update.ID, time.Now())
+		if err != nil {
+			return err
+		}
+
 		// Create or load the hint bucket for this state update's hint
 		// and write the given update.
 		hints, err := updates.CreateBucketIfNotExists(update.Hint[:])
@@ -384,6 +457,18 @@ func (t *TowerDB) InsertStateUpdate(update *SessionStateUpdate) (uint16, error)
 			return err
 		}
 
+		// Account for the space occupied by the update, replacing that
+		// of any prior update stored for the same hint. This fails if
+		// the client or the tower would exceed its storage quota.
+		quota := t.Quota()
+		oldSize := uint64(len(hints.Get(update.ID[:])))
+		err = updateUsage(
+			tx, &update.ID, oldSize, uint64(b.Len()), &quota,
+		)
+		if err != nil {
+			return err
+		}
+
 		err = hints.Put(update.ID[:], b.Bytes())
 		if err != nil {
 			return err
@@ -405,84 +490,107 @@ func (t *TowerDB) InsertStateUpdate(update *SessionStateUpdate) (uint16, error)
 // the tower's database.
 func (t *TowerDB) DeleteSession(target SessionID) error {
 	return t.db.Update(func(tx *bbolt.Tx) error {
-		sessions := tx.Bucket(sessionsBkt)
-		if sessions == nil {
-			return ErrUninitializedDB
-		}
+		return deleteSession(tx, target)
+	})
+}
 
-		updates := tx.Bucket(updatesBkt)
-		if updates == nil {
-			return ErrUninitializedDB
-		}
+// deleteSession removes the session with the given id, along with all of its
+// state updates.
+func deleteSession(tx *bbolt.Tx, target SessionID) error {
+	sessions := tx.Bucket(sessionsBkt)
+	if sessions == nil {
+		return ErrUninitializedDB
+	}
 
-		updateIndex := tx.Bucket(updateIndexBkt)
-		if updateIndex == nil {
-			return ErrUninitializedDB
-		}
+	updates := tx.Bucket(updatesBkt)
+	if updates == nil {
+		return ErrUninitializedDB
+	}
 
-		// Fail if the session doesn't exit.
-		_, err := getSession(sessions, target[:])
-		if err != nil {
-			return err
+	updateIndex := tx.Bucket(updateIndexBkt)
+	if updateIndex == nil {
+		return ErrUninitializedDB
+	}
+
+	sessionActivity := tx.Bucket(sessionActivityBkt)
+	if sessionActivity == nil {
+		return ErrUninitializedDB
+	}
+
+	// Fail if the session doesn't exit.
+	_, err := getSession(sessions, target[:])
+	if err != nil {
+		return err
+	}
+
+	// Remove the target session.
+	err = sessions.Delete(target[:])
+	if err != nil {
+		return err
+	}
+
+	// Next, check the update index for any hints that were added
+	// under this session.
+	hints, err := getHintsForSession(updateIndex, &target)
+	if err != nil {
+		return err
+	}
+
+	for _, hint := range hints {
+		// Remove the state updates for any blobs stored under
+		// the target session identifier.
+		updatesForHint := updates.Bucket(hint[:])
+		if updatesForHint == nil {
+			continue
 		}
 
-		// Remove the target session.
-		err = sessions.Delete(target[:])
-		if err != nil {
-			return err
+		update := updatesForHint.Get(target[:])
+		if update == nil {
+			continue
 		}
 
-		// Next, check the update index for any hints that were added
-		// under this session.
-		hints, err := getHintsForSession(updateIndex, &target)
+		err := updatesForHint.Delete(target[:])
 		if err != nil {
 			return err
 		}
 
-		for _, hint := range hints {
-			// Remove the state updates for any blobs stored under
-			// the target session identifier.
-			updatesForHint := updates.Bucket(hint[:])
-			if updatesForHint == nil {
-				continue
-			}
+		// If this was the last state update, we can also remove
+		// the hint that would map to an empty set.
+		err = isBucketEmpty(updatesForHint)
+		switch {
 
-			update := updatesForHint.Get(target[:])
-			if update == nil {
-				continue
-			}
+		// Other updates exist for this hint, keep the bucket.
+		case err == errBucketNotEmpty:
+			continue
+
+		// Unexpected error.
+		case err != nil:
+			return err
 
-			err := updatesForHint.Delete(target[:])
+		// No more updates for this hint, prune hint bucket.
+		default:
+			err = updates.DeleteBucket(hint[:])
 			if err != nil {
 				return err
 			}
+		}
+	}
 
-			// If this was the last state update, we can also remove
-			// the hint that would map to an empty set.
-			err = isBucketEmpty(updatesForHint)
-			switch {
-
-			// Other updates exist for this hint, keep the bucket.
-			case err == errBucketNotEmpty:
-				continue
-
-			// Unexpected error.
-			case err != nil:
-				return err
+	// Remove the record of the session's last activity.
+	err = sessionActivity.Delete(target[:])
+	if err != nil {
+		return err
+	}
 
-			// No more updates for this hint, prune hint bucket.
-			default:
-				err = updates.DeleteBucket(hint[:])
-				if err != nil {
-					return err
-				}
-			}
-		}
+	// Release the storage accounted to the session.
+	err = removeSessionUsage(tx, &target)
+	if err != nil {
+		return err
+	}
 
-		// Finally, remove this session from the update index, which
-		// also removes any of the indexed hints beneath it.
-		return removeSessionHintBkt(updateIndex, &target)
-	})
+	// Finally, remove this session from the update index, which also
+	// removes any of the indexed hints beneath it.
+	return removeSessionHintBkt(updateIndex, &target)
 }
 
 // QueryMatches searches against all known state updates for any that match the
@@ -603,6 +711,375 @@ func (t *TowerDB) GetLookoutTip() (*chainntnfs.BlockEpoch, error) {
 	return epoch, nil
 }
 
+// MarkBreachesConfirmed records that the breaches identified by the given
+// hints confirmed at the given height, such that their state updates can be
+// evicted once the breaches are buried deep enough. Any breaches previously
+// recorded at the same height or above are discarded, as their blocks must
+// have been reorged out of the chain.
+//
+// NOTE: This method must be called for every connected block in order, even
+// if the block contains no breaches, to ensure stale records are discarded.
+func (t *TowerDB) MarkBreachesConfirmed(height uint32,
+	hints []BreachHint) error {
+
+	return t.db.Update(func(tx *bbolt.Tx) error {
+		confirmed := tx.Bucket(confirmedBreachesBkt)
+		if confirmed == nil {
+			return ErrUninitializedDB
+		}
+
+		var heightKey [4]byte
+		byteOrder.PutUint32(heightKey[:], height)
+
+		var stale [][]byte
+		c := confirmed.Cursor()
+		for k, _ := c.Seek(heightKey[:]); k != nil; k, _ = c.Next() {
+			stale = append(stale, append([]byte(nil), k...))
+		}
+
+		for _, k := range stale {
+			log.Debugf("Discarding breaches recorded at height=%d "+
+				"after reorg", byteOrder.Uint32(k))
+
+			err := confirmed.DeleteBucket(k)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(hints) == 0 {
+			return nil
+		}
+
+		confirmedAtHeight, err := confirmed.CreateBucket(heightKey[:])
+		if err != nil {
+			return err
+		}
+
+		for _, hint := range hints {
+			err := confirmedAtHeight.Put(hint[:], []byte{})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// EvictConfirmedBreaches deletes the state updates of all breaches that
+// confirmed at or below the given height, returning the number of state
+// updates deleted. The storage occupied by the deleted updates is released
+// from the quotas of their clients.
+func (t *TowerDB) EvictConfirmedBreaches(height uint32) (uint64, error) {
+	var numEvicted uint64
+	err := t.db.Update(func(tx *bbolt.Tx) error {
+		numEvicted = 0
+
+		confirmed := tx.Bucket(confirmedBreachesBkt)
+		if confirmed == nil {
+			return ErrUninitializedDB
+		}
+
+		var heightKeys [][]byte
+		c := confirmed.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if len(k) != 4 || byteOrder.Uint32(k) > height {
+				break
+			}
+
+			heightKeys = append(
+				heightKeys, append([]byte(nil), k...),
+			)
+		}
+
+		for _, heightKey := range heightKeys {
+			hints, err := getConfirmedBreaches(
+				confirmed.Bucket(heightKey),
+			)
+			if err != nil {
+				return err
+			}
+
+			for _, hint := range hints {
+				n, err := evictStateUpdates(tx, hint)
+				if err != nil {
+					return err
+				}
+				numEvicted += n
+			}
+
+			err = confirmed.DeleteBucket(heightKey)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numEvicted, nil
+}
+
+// SetQuota sets the storage quota enforced when inserting new state updates.
+// Updates that are already stored are retained, even if they exceed the new
+// quota.
+func (t *TowerDB) SetQuota(quota Quota) {
+	t.quotaMtx.Lock()
+	defer t.quotaMtx.Unlock()
+
+	t.quota = quota
+}
+
+// Quota returns the storage quota enforced when inserting new state updates.
+func (t *TowerDB) Quota() Quota {
+	t.quotaMtx.RLock()
+	defer t.quotaMtx.RUnlock()
+
+	return t.quota
+}
+
+// ListSessions returns a summary of every session negotiated with a client.
+func (t *TowerDB) ListSessions() ([]*SessionSummary, error) {
+	var summaries []*SessionSummary
+	err := t.db.View(func(tx *bbolt.Tx) error {
+		sessions := tx.Bucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		updateIndex := tx.Bucket(updateIndexBkt)
+		if updateIndex == nil {
+			return ErrUninitializedDB
+		}
+
+		sessionActivity := tx.Bucket(sessionActivityBkt)
+		if sessionActivity == nil {
+			return ErrUninitializedDB
+		}
+
+		return sessions.ForEach(func(k, v []byte) error {
+			var session SessionInfo
+			err := session.Decode(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			hints, err := getHintsForSession(
+				updateIndex, &session.ID,
+			)
+			if err != nil {
+				return err
+			}
+
+			summaries = append(summaries, &SessionSummary{
+				SessionInfo: &session,
+				NumUpdates:  uint64(len(hints)),
+				LastActivity: getSessionActivity(
+					sessionActivity, &session.ID,
+				),
+			})
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// Stats returns a summary of the resources occupied within the database.
+func (t *TowerDB) Stats() (*TowerStats, error) {
+	var stats TowerStats
+	err := t.db.View(func(tx *bbolt.Tx) error {
+		sessions := tx.Bucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		updates := tx.Bucket(updatesBkt)
+		if updates == nil {
+			return ErrUninitializedDB
+		}
+
+		blacklist := tx.Bucket(blacklistBkt)
+		if blacklist == nil {
+			return ErrUninitializedDB
+		}
+
+		stats.NumSessions = uint64(sessions.Stats().KeyN)
+		stats.NumBlacklisted = uint64(blacklist.Stats().KeyN)
+		stats.DBSize = uint64(tx.Size())
+
+		// State updates are bucketed by their breach hint, so we'll
+		// need to descend into each hint's bucket to account for them.
+		return updates.ForEach(func(hint, _ []byte) error {
+			updatesForHint := updates.Bucket(hint)
+			if updatesForHint == nil {
+				return nil
+			}
+
+			return updatesForHint.ForEach(func(_, v []byte) error {
+				stats.NumUpdates++
+				stats.UpdateBytes += uint64(len(v))
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// PruneInactiveSessions deletes all sessions that haven't been created or
+// updated by their client since the given cutoff, along with their state
+// updates, returning the ids of the deleted sessions. Sessions whose activity
+// hasn't been tracked yet are considered active as of now, such that they
+// can only be pruned once they've been inactive for the same window.
+//
+// NOTE: Clients may still rely on the state updates of inactive sessions to
+// protect their channels, so the cutoff should be chosen conservatively.
+func (t *TowerDB) PruneInactiveSessions(cutoff time.Time) ([]SessionID, error) {
+	var pruned []SessionID
+	err := t.db.Update(func(tx *bbolt.Tx) error {
+		pruned = nil
+
+		sessions := tx.Bucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		sessionActivity := tx.Bucket(sessionActivityBkt)
+		if sessionActivity == nil {
+			return ErrUninitializedDB
+		}
+
+		now := time.Now()
+
+		var untracked []SessionID
+		err := sessions.ForEach(func(k, _ []byte) error {
+			var id SessionID
+			copy(id[:], k)
+
+			lastActivity := getSessionActivity(sessionActivity, &id)
+			switch {
+			case lastActivity.IsZero():
+				untracked = append(untracked, id)
+
+			case lastActivity.Before(cutoff):
+				pruned = append(pruned, id)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range untracked {
+			err := touchSessionActivity(tx, &untracked[i], now)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, id := range pruned {
+			if err := deleteSession(tx, id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pruned, nil
+}
+
+// BlacklistClient prevents the client with the given session id from
+// connecting to the tower. Existing sessions and state updates of the client
+// are left untouched.
+func (t *TowerDB) BlacklistClient(id SessionID) error {
+	return t.db.Update(func(tx *bbolt.Tx) error {
+		blacklist := tx.Bucket(blacklistBkt)
+		if blacklist == nil {
+			return ErrUninitializedDB
+		}
+
+		return blacklist.Put(id[:], []byte{})
+	})
+}
+
+// UnblacklistClient allows a previously blacklisted client to connect to the
+// tower once again. ErrClientNotBlacklisted is returned if the client isn't
+// blacklisted.
+func (t *TowerDB) UnblacklistClient(id SessionID) error {
+	return t.db.Update(func(tx *bbolt.Tx) error {
+		blacklist := tx.Bucket(blacklistBkt)
+		if blacklist == nil {
+			return ErrUninitializedDB
+		}
+
+		if blacklist.Get(id[:]) == nil {
+			return ErrClientNotBlacklisted
+		}
+
+		return blacklist.Delete(id[:])
+	})
+}
+
+// IsBlacklisted returns whether the client with the given session id has been
+// blacklisted.
+func (t *TowerDB) IsBlacklisted(id *SessionID) (bool, error) {
+	var blacklisted bool
+	err := t.db.View(func(tx *bbolt.Tx) error {
+		blacklist := tx.Bucket(blacklistBkt)
+		if blacklist == nil {
+			return ErrUninitializedDB
+		}
+
+		blacklisted = blacklist.Get(id[:]) != nil
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return blacklisted, nil
+}
+
+// ListBlacklistedClients returns the session ids of all blacklisted clients.
+func (t *TowerDB) ListBlacklistedClients() ([]SessionID, error) {
+	var ids []SessionID
+	err := t.db.View(func(tx *bbolt.Tx) error {
+		blacklist := tx.Bucket(blacklistBkt)
+		if blacklist == nil {
+			return ErrUninitializedDB
+		}
+
+		return blacklist.ForEach(func(k, _ []byte) error {
+			var id SessionID
+			copy(id[:], k)
+			ids = append(ids, id)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 // getSession retrieves the session info from the sessions bucket identified by
 // its session id. An error is returned if the session is not found or a
 // deserialization error occurs.
@@ -633,6 +1110,33 @@ func putSession(sessions *bbolt.Bucket, session *SessionInfo) error {
 	return sessions.Put(session.ID[:], b.Bytes())
 }
 
+// touchSessionActivity records the given time as the last activity of the
+// session with the given id.
+func touchSessionActivity(tx *bbolt.Tx, id *SessionID, t time.Time) error {
+	sessionActivity := tx.Bucket(sessionActivityBkt)
+	if sessionActivity == nil {
+		return ErrUninitializedDB
+	}
+
+	var timestamp [8]byte
+	byteOrder.PutUint64(timestamp[:], uint64(t.UnixNano()))
+
+	return sessionActivity.Put(id[:], timestamp[:])
+}
+
+// getSessionActivity returns the last activity of the session with the given
+// id, or the zero time if none has been recorded.
+func getSessionActivity(sessionActivity *bbolt.Bucket,
+	id *SessionID) time.Time {
+
+	timestamp := sessionActivity.Get(id[:])
+	if len(timestamp) != 8 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, int64(byteOrder.Uint64(timestamp)))
+}
+
 // touchSessionHintBkt initializes the session-hint bucket for a particular
 // session id. This ensures that future calls to getHintsForSession or
 // putHintForSession can rely on the bucket already being created, and fail if
@@ -694,6 +1198,90 @@ func putHintForSession(updateIndex *bbolt.Bucket, id *SessionID,
 	return sessionHints.Put(hint[:], []byte{})
 }
 
+// getConfirmedBreaches returns the breach hints recorded within the given
+// bucket of the confirmedBreachesBkt.
+func getConfirmedBreaches(
+	confirmedAtHeight *bbolt.Bucket) ([]BreachHint, error) {
+
+	if confirmedAtHeight == nil {
+		return nil, nil
+	}
+
+	var hints []BreachHint
+	err := confirmedAtHeight.ForEach(func(k, _ []byte) error {
+		if len(k) != BreachHintSize {
+			return nil
+		}
+
+		var hint BreachHint
+		copy(hint[:], k)
+		hints = append(hints, hint)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hints, nil
+}
+
+// evictStateUpdates deletes the state updates stored by all sessions for the
+// given hint, and removes the hint from the sessions' update indexes. The
+// number of deleted updates is returned.
+func evictStateUpdates(tx *bbolt.Tx, hint BreachHint) (uint64, error) {
+	updates := tx.Bucket(updatesBkt)
+	if updates == nil {
+		return 0, ErrUninitializedDB
+	}
+
+	updateIndex := tx.Bucket(updateIndexBkt)
+	if updateIndex == nil {
+		return 0, ErrUninitializedDB
+	}
+
+	updatesForHint := updates.Bucket(hint[:])
+	if updatesForHint == nil {
+		return 0, nil
+	}
+
+	sizes := make(map[SessionID]uint64)
+	err := updatesForHint.ForEach(func(k, v []byte) error {
+		var id SessionID
+		copy(id[:], k)
+		sizes[id] = uint64(len(v))
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for id, size := range sizes {
+		err := updateUsage(tx, &id, size, 0, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		sessionHints := updateIndex.Bucket(id[:])
+		if sessionHints == nil {
+			continue
+		}
+
+		err = sessionHints.Delete(hint[:])
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = updates.DeleteBucket(hint[:])
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(sizes)), nil
+}
+
 // putLookoutEpoch stores the given lookout tip block epoch in provided bucket.
 func putLookoutEpoch(bkt *bbolt.Bucket, epoch *chainntnfs.BlockEpoch) error {
 	epochBytes := make([]byte, 36)
@@ -1,11 +1,15 @@
 package wtdb_test
 
 import (
+	"bytes"
+	"database/sql"
 	"encoding/binary"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/lnd/chainntnfs"
@@ -343,6 +347,266 @@ func testDeleteSession(h *towerDBHarness) {
 	}
 }
 
+// testBlacklist asserts that clients can be added to and removed from the
+// blacklist.
+func testBlacklist(h *towerDBHarness) {
+	assertBlacklisted := func(id *wtdb.SessionID, expected bool) {
+		h.t.Helper()
+
+		blacklisted, err := h.db.IsBlacklisted(id)
+		if err != nil {
+			h.t.Fatalf("unable to check blacklist: %v", err)
+		}
+		if blacklisted != expected {
+			h.t.Fatalf("expected blacklisted=%v for %s, got %v",
+				expected, id, blacklisted)
+		}
+	}
+
+	id0, id1 := id(0), id(1)
+	assertBlacklisted(id0, false)
+
+	err := h.db.UnblacklistClient(*id0)
+	if err != wtdb.ErrClientNotBlacklisted {
+		h.t.Fatalf("expected ErrClientNotBlacklisted, got: %v", err)
+	}
+
+	if err := h.db.BlacklistClient(*id0); err != nil {
+		h.t.Fatalf("unable to blacklist client: %v", err)
+	}
+	assertBlacklisted(id0, true)
+	assertBlacklisted(id1, false)
+
+	ids, err := h.db.ListBlacklistedClients()
+	if err != nil {
+		h.t.Fatalf("unable to list blacklisted clients: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []wtdb.SessionID{*id0}) {
+		h.t.Fatalf("expected blacklist %v, got %v",
+			[]wtdb.SessionID{*id0}, ids)
+	}
+
+	if err := h.db.UnblacklistClient(*id0); err != nil {
+		h.t.Fatalf("unable to unblacklist client: %v", err)
+	}
+	assertBlacklisted(id0, false)
+}
+
+// testSessionStats asserts that the sessions and their stored updates are
+// properly accounted for when listing sessions and fetching the database's
+// stats.
+func testSessionStats(h *towerDBHarness) {
+	for i := 0; i < 2; i++ {
+		h.insertSession(&wtdb.SessionInfo{
+			ID: *id(i),
+			Policy: wtpolicy.Policy{
+				MaxUpdates: 3,
+			},
+			RewardAddress: []byte{},
+		}, nil)
+	}
+
+	// Add two updates to the first session, and none to the second.
+	h.insertUpdate(updateFromInt(id(0), 1, 0), nil)
+	h.insertUpdate(updateFromInt(id(0), 2, 0), nil)
+
+	summaries, err := h.db.ListSessions()
+	if err != nil {
+		h.t.Fatalf("unable to list sessions: %v", err)
+	}
+	if len(summaries) != 2 {
+		h.t.Fatalf("expected 2 sessions, got %d", len(summaries))
+	}
+	for _, summary := range summaries {
+		var expUpdates uint64
+		if summary.ID == *id(0) {
+			expUpdates = 2
+		}
+		if summary.NumUpdates != expUpdates {
+			h.t.Fatalf("expected %d updates for %s, got %d",
+				expUpdates, summary.ID, summary.NumUpdates)
+		}
+		if summary.LastActivity.IsZero() {
+			h.t.Fatalf("expected activity of %s to be tracked",
+				summary.ID)
+		}
+	}
+
+	stats, err := h.db.Stats()
+	if err != nil {
+		h.t.Fatalf("unable to fetch stats: %v", err)
+	}
+	if stats.NumSessions != 2 {
+		h.t.Fatalf("expected 2 sessions, got %d", stats.NumSessions)
+	}
+	if stats.NumUpdates != 2 {
+		h.t.Fatalf("expected 2 updates, got %d", stats.NumUpdates)
+	}
+	if stats.UpdateBytes == 0 {
+		h.t.Fatalf("expected size of updates to be accounted for")
+	}
+}
+
+// testPruneInactiveSessions asserts that only sessions that have been inactive
+// since the cutoff are pruned.
+func testPruneInactiveSessions(h *towerDBHarness) {
+	session := &wtdb.SessionInfo{
+		ID: *id(0),
+		Policy: wtpolicy.Policy{
+			MaxUpdates: 3,
+		},
+		RewardAddress: []byte{},
+	}
+	h.insertSession(session, nil)
+	update := updateFromInt(id(0), 1, 0)
+	h.insertUpdate(update, nil)
+
+	// The session has just been updated, so a cutoff in the past shouldn't
+	// prune it.
+	pruned, err := h.db.PruneInactiveSessions(time.Now().Add(-time.Hour))
+	if err != nil {
+		h.t.Fatalf("unable to prune sessions: %v", err)
+	}
+	if len(pruned) != 0 {
+		h.t.Fatalf("expected no sessions to be pruned, got %v", pruned)
+	}
+	h.getSession(id(0), nil)
+
+	// Moving the cutoff into the future should prune the session, along
+	// with its updates.
+	pruned, err = h.db.PruneInactiveSessions(time.Now().Add(time.Hour))
+	if err != nil {
+		h.t.Fatalf("unable to prune sessions: %v", err)
+	}
+	if !reflect.DeepEqual(pruned, []wtdb.SessionID{*id(0)}) {
+		h.t.Fatalf("expected session %s to be pruned, got %v", id(0),
+			pruned)
+	}
+	h.getSession(id(0), wtdb.ErrSessionNotFound)
+
+	if matches := h.queryMatches(update.Hint); len(matches) != 0 {
+		h.t.Fatalf("expected zero updates, found: %d", len(matches))
+	}
+}
+
+// testStorageQuota asserts that state updates are rejected once they would
+// exceed the storage quota of their client or the tower, and that storage is
+// released when sessions are deleted.
+func testStorageQuota(h *towerDBHarness) {
+	for i := 0; i < 2; i++ {
+		h.insertSession(&wtdb.SessionInfo{
+			ID: *id(i),
+			Policy: wtpolicy.Policy{
+				MaxUpdates: 5,
+			},
+			RewardAddress: []byte{},
+		}, nil)
+	}
+
+	// All updates created by updateFromInt are of equal size, allowing us
+	// to express the quota in number of updates.
+	var b bytes.Buffer
+	if err := updateFromInt(id(0), 1, 0).Encode(&b); err != nil {
+		h.t.Fatalf("unable to encode update: %v", err)
+	}
+	size := uint64(b.Len())
+
+	h.db.SetQuota(wtdb.Quota{
+		MaxClientBytes: 2 * size,
+		MaxTotalBytes:  3 * size,
+	})
+
+	// The first client can only store two updates.
+	h.insertUpdate(updateFromInt(id(0), 1, 0), nil)
+	h.insertUpdate(updateFromInt(id(0), 2, 0), nil)
+	h.insertUpdate(
+		updateFromInt(id(0), 3, 0), wtdb.ErrClientQuotaExceeded,
+	)
+
+	// The second client is limited by the tower's quota instead.
+	h.insertUpdate(updateFromInt(id(1), 1, 0), nil)
+	h.insertUpdate(
+		updateFromInt(id(1), 2, 0), wtdb.ErrTowerQuotaExceeded,
+	)
+
+	// Deleting the first session should release its storage, allowing
+	// the rejected update of the second client to be stored.
+	h.deleteSession(*id(0), nil)
+	h.insertUpdate(updateFromInt(id(1), 2, 0), nil)
+
+	// Finally, lifting the quota should allow the second client to exceed
+	// its prior limit.
+	h.db.SetQuota(wtdb.Quota{})
+	h.insertUpdate(updateFromInt(id(1), 3, 0), nil)
+}
+
+// testEvictConfirmedBreaches asserts that only the state updates of breaches
+// confirmed at or below the eviction height are deleted, and that breaches
+// recorded for blocks that were reorged out are never evicted.
+func testEvictConfirmedBreaches(h *towerDBHarness) {
+	h.insertSession(&wtdb.SessionInfo{
+		ID: *id(0),
+		Policy: wtpolicy.Policy{
+			MaxUpdates: 3,
+		},
+		RewardAddress: []byte{},
+	}, nil)
+
+	var updates []*wtdb.SessionStateUpdate
+	for i := 1; i <= 3; i++ {
+		update := updateFromInt(id(0), i, 0)
+		h.insertUpdate(update, nil)
+		updates = append(updates, update)
+	}
+
+	markConfirmed := func(height uint32, hints ...wtdb.BreachHint) {
+		h.t.Helper()
+
+		err := h.db.MarkBreachesConfirmed(height, hints)
+		if err != nil {
+			h.t.Fatalf("unable to mark breaches confirmed: %v", err)
+		}
+	}
+
+	evict := func(height uint32, expEvicted uint64) {
+		h.t.Helper()
+
+		numEvicted, err := h.db.EvictConfirmedBreaches(height)
+		if err != nil {
+			h.t.Fatalf("unable to evict breaches: %v", err)
+		}
+		if numEvicted != expEvicted {
+			h.t.Fatalf("expected %d evicted updates, got %d",
+				expEvicted, numEvicted)
+		}
+	}
+
+	// Confirm the first two breaches in consecutive blocks, but reorg out
+	// the second block in favor of one without any breaches.
+	markConfirmed(10, updates[0].Hint)
+	markConfirmed(11, updates[1].Hint)
+	markConfirmed(11)
+
+	// Only the update of the first breach should be evicted.
+	evict(11, 1)
+	if matches := h.queryMatches(updates[0].Hint); len(matches) != 0 {
+		h.t.Fatalf("expected zero updates, found: %d", len(matches))
+	}
+	h.hasUpdate(updates[1].Hint)
+	h.hasUpdate(updates[2].Hint)
+
+	// Evicting the same height again should be a noop.
+	evict(11, 0)
+
+	summaries, err := h.db.ListSessions()
+	if err != nil {
+		h.t.Fatalf("unable to list sessions: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].NumUpdates != 2 {
+		h.t.Fatalf("expected single session with 2 updates")
+	}
+}
+
 type stateUpdateTest struct {
 	session    *wtdb.SessionInfo
 	sessionErr error
@@ -612,6 +876,10 @@ func TestTowerDB(t *testing.T) {
 				return wtmock.NewTowerDB(), func() {}
 			},
 		},
+		{
+			name: "postgres",
+			init: initPostgresTowerDB,
+		},
 	}
 
 	tests := []struct {
@@ -670,6 +938,26 @@ func TestTowerDB(t *testing.T) {
 			name: "lookout tip",
 			run:  testLookoutTip,
 		},
+		{
+			name: "blacklist",
+			run:  testBlacklist,
+		},
+		{
+			name: "session stats",
+			run:  testSessionStats,
+		},
+		{
+			name: "prune inactive sessions",
+			run:  testPruneInactiveSessions,
+		},
+		{
+			name: "storage quota",
+			run:  testStorageQuota,
+		},
+		{
+			name: "evict confirmed breaches",
+			run:  testEvictConfirmedBreaches,
+		},
 	}
 
 	for _, database := range dbs {
@@ -691,6 +979,45 @@ func TestTowerDB(t *testing.T) {
 	}
 }
 
+// initPostgresTowerDB opens a SQLTowerDB within a fresh schema of the Postgres
+// database identified by the key/value data source name in the
+// TOWERDB_POSTGRES_DSN environment variable. The test is skipped if it isn't
+// set.
+func initPostgresTowerDB(t *testing.T) (watchtower.DB, func()) {
+	dsn := os.Getenv("TOWERDB_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TOWERDB_POSTGRES_DSN not set")
+	}
+
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("unable to connect to postgres: %v", err)
+	}
+
+	schema := fmt.Sprintf("towerdb_test_%d", time.Now().UnixNano())
+	_, err = conn.Exec("CREATE SCHEMA " + schema)
+	if err != nil {
+		conn.Close()
+		t.Fatalf("unable to create schema: %v", err)
+	}
+
+	cleanup := func() {
+		conn.Exec("DROP SCHEMA " + schema + " CASCADE")
+		conn.Close()
+	}
+
+	db, err := wtdb.OpenPostgresTowerDB(dsn + " search_path=" + schema)
+	if err != nil {
+		cleanup()
+		t.Fatalf("unable to open db: %v", err)
+	}
+
+	return db, func() {
+		db.Close()
+		cleanup()
+	}
+}
+
 // id creates a session id from an integer.
 func id(i int) *wtdb.SessionID {
 	var id wtdb.SessionID
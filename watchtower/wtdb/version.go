@@ -23,6 +23,12 @@ var dbVersions = []version{
 		number:    0,
 		migration: nil,
 	},
+	{
+		// Accounts for the storage occupied by the state updates of
+		// each session, such that storage quotas can be enforced.
+		number:    1,
+		migration: migrateStorageUsage,
+	},
 }
 
 // getLatestDBVersion returns the last known database version.
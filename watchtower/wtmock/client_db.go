@@ -6,6 +6,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/litecoinfinance/btcutil"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/watchtower/wtdb"
 )
@@ -25,6 +26,8 @@ type ClientDB struct {
 
 	nextIndex uint32
 	indexes   map[uint64]uint32
+
+	queuedBackups []wtdb.BackupID
 }
 
 // NewClientDB initializes a new mock ClientDB.
@@ -80,21 +83,126 @@ func (m *ClientDB) LoadTower(towerID uint64) (*wtdb.Tower, error) {
 	return nil, wtdb.ErrTowerNotFound
 }
 
+// UpdateTowerAddresses replaces the addresses of the tower with the given ID.
+func (m *ClientDB) UpdateTowerAddresses(towerID uint64,
+	addrs []net.Addr) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tower, ok := m.towers[towerID]
+	if !ok {
+		return wtdb.ErrTowerNotFound
+	}
+
+	tower.SetAddresses(addrs)
+
+	return nil
+}
+
 // MarkBackupIneligible records that particular commit height is ineligible for
 // backup. This allows the client to track which updates it should not attempt
 // to retry after startup.
 func (m *ClientDB) MarkBackupIneligible(chanID lnwire.ChannelID, commitHeight uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dequeueBackup(wtdb.BackupID{
+		ChanID:       chanID,
+		CommitHeight: commitHeight,
+	})
+
 	return nil
 }
 
-// ListClientSessions returns the set of all client sessions known to the db.
+// QueueBackup records a backup that has yet to be committed to a session. If
+// the backup is already queued, or has been committed to any session,
+// wtdb.ErrBackupAlreadyQueued is returned.
+func (m *ClientDB) QueueBackup(id *wtdb.BackupID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, queued := range m.queuedBackups {
+		if queued == *id {
+			return wtdb.ErrBackupAlreadyQueued
+		}
+	}
+
+	for _, session := range m.activeSessions {
+		for _, update := range session.CommittedUpdates {
+			if update.BackupID == *id {
+				return wtdb.ErrBackupAlreadyQueued
+			}
+		}
+		for _, backupID := range session.AckedUpdates {
+			if backupID == *id {
+				return wtdb.ErrBackupAlreadyQueued
+			}
+		}
+	}
+
+	m.queuedBackups = append(m.queuedBackups, *id)
+
+	return nil
+}
+
+// FetchQueuedBackups returns the backups that have yet to be committed to a
+// session, in the order they were queued.
+func (m *ClientDB) FetchQueuedBackups() ([]wtdb.BackupID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	queued := make([]wtdb.BackupID, len(m.queuedBackups))
+	copy(queued, m.queuedBackups)
+
+	return queued, nil
+}
+
+// dequeueBackup removes the given backup from the queue, if present. The
+// caller must hold the mutex.
+func (m *ClientDB) dequeueBackup(id wtdb.BackupID) {
+	for i, queued := range m.queuedBackups {
+		if queued == id {
+			m.queuedBackups = append(
+				m.queuedBackups[:i], m.queuedBackups[i+1:]...,
+			)
+			return
+		}
+	}
+}
+
+// ListAllClientSessions returns every client session known to the db,
+// including those that have been exhausted.
+func (m *ClientDB) ListAllClientSessions() (
+	map[wtdb.SessionID]*wtdb.ClientSession, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessions := make(map[wtdb.SessionID]*wtdb.ClientSession)
+	for _, session := range m.activeSessions {
+		sessions[session.ID] = copyClientSession(session)
+	}
+
+	return sessions, nil
+}
+
+// ListClientSessions returns the set of client sessions known to the db that
+// have not been exhausted. Exhausted sessions are still returned as long as
+// they have committed updates that haven't been acked by the tower.
 func (m *ClientDB) ListClientSessions() (map[wtdb.SessionID]*wtdb.ClientSession, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	sessions := make(map[wtdb.SessionID]*wtdb.ClientSession)
 	for _, session := range m.activeSessions {
-		sessions[session.ID] = session
+		if session.RemainingUpdates() == 0 &&
+			len(session.CommittedUpdates) == 0 {
+
+			continue
+		}
+
+		sessions[session.ID] = copyClientSession(session)
 	}
 
 	return sessions, nil
@@ -131,6 +239,7 @@ func (m *ClientDB) CreateClientSession(session *wtdb.ClientSession) error {
 		RewardPkScript:   cloneBytes(session.RewardPkScript),
 		CommittedUpdates: make(map[uint16]*wtdb.CommittedUpdate),
 		AckedUpdates:     make(map[uint16]wtdb.BackupID),
+		AckedAmounts:     make(map[uint16]btcutil.Amount),
 	}
 
 	return nil
@@ -189,9 +298,11 @@ func (m *ClientDB) CommitUpdate(id *wtdb.SessionID, seqNum uint16,
 		return 0, wtdb.ErrCommitUnorderedUpdate
 	}
 
-	// Save the update and increment the sequence number.
+	// Save the update and increment the sequence number. The backup is
+	// no longer pending, as it will be retransmitted from the session.
 	session.CommittedUpdates[seqNum] = update
 	session.SeqNum++
+	m.dequeueBackup(update.BackupID)
 
 	return session.TowerLastApplied, nil
 }
@@ -233,6 +344,7 @@ func (m *ClientDB) AckUpdate(id *wtdb.SessionID, seqNum, lastApplied uint16) err
 	// with the next update.
 	delete(session.CommittedUpdates, seqNum)
 	session.AckedUpdates[seqNum] = update.BackupID
+	session.AckedAmounts[seqNum] = update.Amount
 	session.TowerLastApplied = lastApplied
 
 	return nil
@@ -266,6 +378,38 @@ func (m *ClientDB) AddChanPkScript(chanID lnwire.ChannelID, pkScript []byte) err
 	return nil
 }
 
+// copyClientSession returns a copy of the given session, such that callers can
+// inspect it without racing against updates to the db.
+func copyClientSession(s *wtdb.ClientSession) *wtdb.ClientSession {
+	committedUpdates := make(map[uint16]*wtdb.CommittedUpdate)
+	for seqNum, update := range s.CommittedUpdates {
+		committedUpdates[seqNum] = update
+	}
+
+	ackedUpdates := make(map[uint16]wtdb.BackupID)
+	for seqNum, backupID := range s.AckedUpdates {
+		ackedUpdates[seqNum] = backupID
+	}
+
+	ackedAmounts := make(map[uint16]btcutil.Amount)
+	for seqNum, amt := range s.AckedAmounts {
+		ackedAmounts[seqNum] = amt
+	}
+
+	return &wtdb.ClientSession{
+		ID:               s.ID,
+		SeqNum:           s.SeqNum,
+		TowerLastApplied: s.TowerLastApplied,
+		TowerID:          s.TowerID,
+		KeyIndex:         s.KeyIndex,
+		Policy:           s.Policy,
+		RewardPkScript:   cloneBytes(s.RewardPkScript),
+		CommittedUpdates: committedUpdates,
+		AckedUpdates:     ackedUpdates,
+		AckedAmounts:     ackedAmounts,
+	}
+}
+
 func cloneBytes(b []byte) []byte {
 	if b == nil {
 		return nil
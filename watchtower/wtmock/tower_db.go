@@ -1,7 +1,9 @@
 package wtmock
 
 import (
+	"bytes"
 	"sync"
+	"time"
 
 	"github.com/litecoinfinance/lnd/chainntnfs"
 	"github.com/litecoinfinance/lnd/watchtower/wtdb"
@@ -13,13 +15,20 @@ type TowerDB struct {
 	lastEpoch *chainntnfs.BlockEpoch
 	sessions  map[wtdb.SessionID]*wtdb.SessionInfo
 	blobs     map[wtdb.BreachHint]map[wtdb.SessionID]*wtdb.SessionStateUpdate
+	activity  map[wtdb.SessionID]time.Time
+	blacklist map[wtdb.SessionID]struct{}
+	confirmed map[uint32][]wtdb.BreachHint
+	quota     wtdb.Quota
 }
 
 // NewTowerDB initializes a fresh mock TowerDB.
 func NewTowerDB() *TowerDB {
 	return &TowerDB{
-		sessions: make(map[wtdb.SessionID]*wtdb.SessionInfo),
-		blobs:    make(map[wtdb.BreachHint]map[wtdb.SessionID]*wtdb.SessionStateUpdate),
+		sessions:  make(map[wtdb.SessionID]*wtdb.SessionInfo),
+		blobs:     make(map[wtdb.BreachHint]map[wtdb.SessionID]*wtdb.SessionStateUpdate),
+		activity:  make(map[wtdb.SessionID]time.Time),
+		blacklist: make(map[wtdb.SessionID]struct{}),
+		confirmed: make(map[uint32][]wtdb.BreachHint),
 	}
 }
 
@@ -36,21 +45,92 @@ func (db *TowerDB) InsertStateUpdate(update *wtdb.SessionStateUpdate) (uint16, e
 		return 0, wtdb.ErrSessionNotFound
 	}
 
-	err := info.AcceptUpdateSequence(update.SeqNum, update.LastApplied)
+	// Validate the update against a copy of the session, such that it
+	// remains untouched if the update exceeds the storage quota.
+	updatedInfo := *info
+	err := updatedInfo.AcceptUpdateSequence(
+		update.SeqNum, update.LastApplied,
+	)
 	if err != nil {
 		return info.LastApplied, err
 	}
 
+	if err := db.checkQuota(update); err != nil {
+		return info.LastApplied, err
+	}
+	*info = updatedInfo
+
 	sessionsToUpdates, ok := db.blobs[update.Hint]
 	if !ok {
 		sessionsToUpdates = make(map[wtdb.SessionID]*wtdb.SessionStateUpdate)
 		db.blobs[update.Hint] = sessionsToUpdates
 	}
 	sessionsToUpdates[update.ID] = update
+	db.activity[update.ID] = time.Now()
 
 	return info.LastApplied, nil
 }
 
+// checkQuota returns an error if storing the given update would exceed the
+// storage quota of its client or the tower.
+//
+// NOTE: This method MUST be called with the mutex held.
+func (db *TowerDB) checkQuota(update *wtdb.SessionStateUpdate) error {
+	newSize, err := updateSize(update)
+	if err != nil {
+		return err
+	}
+
+	var oldSize, clientBytes, totalBytes uint64
+	for hint, sessionUpdates := range db.blobs {
+		for id, stored := range sessionUpdates {
+			size, err := updateSize(stored)
+			if err != nil {
+				return err
+			}
+
+			totalBytes += size
+			if id != update.ID {
+				continue
+			}
+
+			clientBytes += size
+			if hint == update.Hint {
+				oldSize = size
+			}
+		}
+	}
+
+	// Shrinking usage is always permitted.
+	if newSize <= oldSize {
+		return nil
+	}
+
+	switch {
+	case db.quota.MaxClientBytes != 0 &&
+		clientBytes-oldSize+newSize > db.quota.MaxClientBytes:
+
+		return wtdb.ErrClientQuotaExceeded
+
+	case db.quota.MaxTotalBytes != 0 &&
+		totalBytes-oldSize+newSize > db.quota.MaxTotalBytes:
+
+		return wtdb.ErrTowerQuotaExceeded
+	}
+
+	return nil
+}
+
+// updateSize returns the number of bytes occupied by the encoded update.
+func updateSize(update *wtdb.SessionStateUpdate) (uint64, error) {
+	var b bytes.Buffer
+	if err := update.Encode(&b); err != nil {
+		return 0, err
+	}
+
+	return uint64(b.Len()), nil
+}
+
 // GetSessionInfo retrieves the session for the passed session id. An error is
 // returned if the session could not be found.
 func (db *TowerDB) GetSessionInfo(id *wtdb.SessionID) (*wtdb.SessionInfo, error) {
@@ -76,6 +156,7 @@ func (db *TowerDB) InsertSessionInfo(info *wtdb.SessionInfo) error {
 	}
 
 	db.sessions[info.ID] = info
+	db.activity[info.ID] = time.Now()
 
 	return nil
 }
@@ -86,6 +167,14 @@ func (db *TowerDB) DeleteSession(target wtdb.SessionID) error {
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
+	return db.deleteSession(target)
+}
+
+// deleteSession removes the session with the given id, along with all of its
+// state updates.
+//
+// NOTE: This method MUST be called with the mutex held.
+func (db *TowerDB) deleteSession(target wtdb.SessionID) error {
 	// Fail if the session doesn't exit.
 	if _, ok := db.sessions[target]; !ok {
 		return wtdb.ErrSessionNotFound
@@ -93,6 +182,7 @@ func (db *TowerDB) DeleteSession(target wtdb.SessionID) error {
 
 	// Remove the target session.
 	delete(db.sessions, target)
+	delete(db.activity, target)
 
 	// Remove the state updates for any blobs stored under the target
 	// session identifier.
@@ -160,3 +250,182 @@ func (db *TowerDB) GetLookoutTip() (*chainntnfs.BlockEpoch, error) {
 
 	return db.lastEpoch, nil
 }
+
+// MarkBreachesConfirmed records that the breaches identified by the given
+// hints confirmed at the given height, discarding any breaches recorded at the
+// same height or above.
+func (db *TowerDB) MarkBreachesConfirmed(height uint32,
+	hints []wtdb.BreachHint) error {
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for h := range db.confirmed {
+		if h >= height {
+			delete(db.confirmed, h)
+		}
+	}
+
+	if len(hints) > 0 {
+		db.confirmed[height] = append([]wtdb.BreachHint(nil), hints...)
+	}
+
+	return nil
+}
+
+// EvictConfirmedBreaches deletes the state updates of all breaches that
+// confirmed at or below the given height, returning the number of state
+// updates deleted.
+func (db *TowerDB) EvictConfirmedBreaches(height uint32) (uint64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var numEvicted uint64
+	for h, hints := range db.confirmed {
+		if h > height {
+			continue
+		}
+
+		for _, hint := range hints {
+			numEvicted += uint64(len(db.blobs[hint]))
+			delete(db.blobs, hint)
+		}
+		delete(db.confirmed, h)
+	}
+
+	return numEvicted, nil
+}
+
+// ListSessions returns a summary of every session negotiated with a client.
+func (db *TowerDB) ListSessions() ([]*wtdb.SessionSummary, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	summaries := make([]*wtdb.SessionSummary, 0, len(db.sessions))
+	for id, info := range db.sessions {
+		summary := &wtdb.SessionSummary{
+			SessionInfo:  info,
+			LastActivity: db.activity[id],
+		}
+		for _, sessionUpdates := range db.blobs {
+			if _, ok := sessionUpdates[id]; ok {
+				summary.NumUpdates++
+			}
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// Stats returns a summary of the resources occupied within the database.
+func (db *TowerDB) Stats() (*wtdb.TowerStats, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	stats := &wtdb.TowerStats{
+		NumSessions:    uint64(len(db.sessions)),
+		NumBlacklisted: uint64(len(db.blacklist)),
+	}
+	for _, sessionUpdates := range db.blobs {
+		for _, update := range sessionUpdates {
+			size, err := updateSize(update)
+			if err != nil {
+				return nil, err
+			}
+
+			stats.NumUpdates++
+			stats.UpdateBytes += size
+		}
+	}
+
+	return stats, nil
+}
+
+// PruneInactiveSessions deletes all sessions that haven't been created or
+// updated by their client since the given cutoff, returning their ids.
+func (db *TowerDB) PruneInactiveSessions(
+	cutoff time.Time) ([]wtdb.SessionID, error) {
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var pruned []wtdb.SessionID
+	for id := range db.sessions {
+		if !db.activity[id].Before(cutoff) {
+			continue
+		}
+
+		if err := db.deleteSession(id); err != nil {
+			return nil, err
+		}
+		pruned = append(pruned, id)
+	}
+
+	return pruned, nil
+}
+
+// BlacklistClient prevents the client with the given session id from
+// connecting to the tower.
+func (db *TowerDB) BlacklistClient(id wtdb.SessionID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.blacklist[id] = struct{}{}
+
+	return nil
+}
+
+// UnblacklistClient allows a previously blacklisted client to connect to the
+// tower once again.
+func (db *TowerDB) UnblacklistClient(id wtdb.SessionID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.blacklist[id]; !ok {
+		return wtdb.ErrClientNotBlacklisted
+	}
+	delete(db.blacklist, id)
+
+	return nil
+}
+
+// IsBlacklisted returns whether the client with the given session id has been
+// blacklisted.
+func (db *TowerDB) IsBlacklisted(id *wtdb.SessionID) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	_, ok := db.blacklist[*id]
+	return ok, nil
+}
+
+// ListBlacklistedClients returns the session ids of all blacklisted clients.
+func (db *TowerDB) ListBlacklistedClients() ([]wtdb.SessionID, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	ids := make([]wtdb.SessionID, 0, len(db.blacklist))
+	for id := range db.blacklist {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// SetQuota sets the storage quota enforced when inserting new state updates.
+func (db *TowerDB) SetQuota(quota wtdb.Quota) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.quota = quota
+}
+
+// Quota returns the storage quota enforced when inserting new state updates.
+func (db *TowerDB) Quota() wtdb.Quota {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.quota
+}
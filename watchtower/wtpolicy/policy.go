@@ -43,6 +43,11 @@ var (
 	// ErrCreatesDust signals that the session's policy would create a dust
 	// output for the victim.
 	ErrCreatesDust = errors.New("justice transaction creates dust at fee rate")
+
+	// ErrSweepFeeRateTooLow signals that the session's policy would create
+	// justice transactions with a fee rate too low to be relayed.
+	ErrSweepFeeRateTooLow = errors.New("sweep fee rate below minimum " +
+		"relay fee rate")
 )
 
 // DefaultPolicy returns a Policy containing the default parameters that can be
@@ -95,6 +100,25 @@ func (p Policy) String() string {
 		p.SweepFeeRate)
 }
 
+// IsAnchorChannel returns true if the session policy is used to back up
+// channels using anchor outputs.
+func (p *Policy) IsAnchorChannel() bool {
+	return p.BlobType.Has(blob.FlagAnchorChannel)
+}
+
+// ValidateSweepFeeRate checks that justice transactions created under the
+// policy would pay at least the given minimum fee rate. As the fee rate
+// required for transactions to be relayed changes with the state of the
+// network, this should be checked against the chain backend's current fee
+// filter.
+func (p *Policy) ValidateSweepFeeRate(minFeeRate lnwallet.SatPerKWeight) error {
+	if p.SweepFeeRate < minFeeRate {
+		return ErrSweepFeeRateTooLow
+	}
+
+	return nil
+}
+
 // ComputeAltruistOutput computes the lone output value of a justice transaction
 // that pays no reward to the tower. The value is computed using the weight of
 // of the justice transaction and subtracting an amount that satisfies the
@@ -44,8 +44,11 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 		)
 	}
 
-	// Ensure that the requested blob type is supported by our tower.
-	if !blob.IsSupportedType(req.BlobType) {
+	bounds := s.PolicyBounds()
+
+	// Ensure that the requested blob type is supported by our tower, and
+	// currently accepted by its operator.
+	if !bounds.AllowsBlobType(req.BlobType) {
 		log.Debugf("Rejecting CreateSession from %s, unsupported blob "+
 			"type %s", id, req.BlobType)
 		return s.replyCreateSession(
@@ -54,6 +57,84 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 		)
 	}
 
+	if bounds.MaxUpdates != 0 && req.MaxUpdates > bounds.MaxUpdates {
+		log.Debugf("Rejecting CreateSession from %s, max updates %d "+
+			"exceeds limit of %d", id, req.MaxUpdates,
+			bounds.MaxUpdates)
+		return s.replyCreateSession(
+			peer, id, wtwire.CreateSessionCodeRejectMaxUpdates, 0,
+			nil,
+		)
+	}
+
+	policy := wtpolicy.Policy{
+		BlobType:     req.BlobType,
+		MaxUpdates:   req.MaxUpdates,
+		RewardBase:   req.RewardBase,
+		RewardRate:   req.RewardRate,
+		SweepFeeRate: req.SweepFeeRate,
+	}
+
+	// Ensure that justice transactions created under the requested
+	// policy would currently be relayed by our chain backend, otherwise
+	// we'd be unable to act on any breach.
+	if s.cfg.MinSweepFeeRate != nil {
+		err := policy.ValidateSweepFeeRate(s.cfg.MinSweepFeeRate())
+		if err != nil {
+			log.Debugf("Rejecting CreateSession from %s, sweep fee "+
+				"rate %d sat/kw: %v", id, req.SweepFeeRate, err)
+			return s.replyCreateSession(
+				peer, id, wtwire.CreateSessionCodeRejectSweepFeeRate,
+				0, nil,
+			)
+		}
+	}
+
+	// The sweep fee rate must also fall within the range configured by
+	// the operator, which may be tightened as the fee market changes.
+	if req.SweepFeeRate < bounds.MinSweepFeeRate ||
+		(bounds.MaxSweepFeeRate != 0 &&
+			req.SweepFeeRate > bounds.MaxSweepFeeRate) {
+
+		log.Debugf("Rejecting CreateSession from %s, sweep fee rate "+
+			"%d sat/kw outside of [%d, %d]", id, req.SweepFeeRate,
+			bounds.MinSweepFeeRate, bounds.MaxSweepFeeRate)
+		return s.replyCreateSession(
+			peer, id, wtwire.CreateSessionCodeRejectSweepFeeRate, 0,
+			nil,
+		)
+	}
+
+	// If the client proposed a reward session, ensure the reward falls
+	// within the range we accept. Otherwise, we'll reply with our
+	// requirements, allowing the client to propose a new session with an
+	// adequate reward.
+	if req.BlobType.Has(blob.FlagReward) &&
+		!rewardWithinBounds(req, &bounds) {
+
+		log.Debugf("Rejecting CreateSession from %s, reward "+
+			"base=%d rate=%d outside of base=[%d, %d] "+
+			"rate=[%d, %d]", id, req.RewardBase, req.RewardRate,
+			bounds.MinRewardBase, bounds.MaxRewardBase,
+			bounds.MinRewardRate, bounds.MaxRewardRate)
+
+		reqs := &wtwire.RewardRequirements{
+			MinRewardBase: bounds.MinRewardBase,
+			MinRewardRate: bounds.MinRewardRate,
+		}
+		data, err := reqs.Encode()
+		if err != nil {
+			return s.replyCreateSession(
+				peer, id, wtwire.CodeTemporaryFailure, 0, nil,
+			)
+		}
+
+		return s.replyCreateSession(
+			peer, id, wtwire.CreateSessionCodeRejectRewardRate, 0,
+			data,
+		)
+	}
+
 	// Now that we've established that this session does not exist in the
 	// database, retrieve the sweep address that will be given to the
 	// client. This address is to be included by the client when signing
@@ -87,14 +168,8 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 	// Assemble the session info using the agreed upon parameters, reward
 	// address, and session id.
 	info := wtdb.SessionInfo{
-		ID: *id,
-		Policy: wtpolicy.Policy{
-			BlobType:     req.BlobType,
-			MaxUpdates:   req.MaxUpdates,
-			RewardBase:   req.RewardBase,
-			RewardRate:   req.RewardRate,
-			SweepFeeRate: req.SweepFeeRate,
-		},
+		ID:            *id,
+		Policy:        policy,
 		RewardAddress: rewardScript,
 	}
 
@@ -152,3 +227,23 @@ func (s *Server) replyCreateSession(peer Peer, id *wtdb.SessionID,
 		Code: code,
 	}
 }
+
+// rewardWithinBounds returns whether the reward proposed by the client falls
+// within the range permitted by the given bounds.
+func rewardWithinBounds(req *wtwire.CreateSession, bounds *PolicyBounds) bool {
+	switch {
+	case req.RewardBase < bounds.MinRewardBase:
+		return false
+
+	case bounds.MaxRewardBase != 0 && req.RewardBase > bounds.MaxRewardBase:
+		return false
+
+	case req.RewardRate < bounds.MinRewardRate:
+		return false
+
+	case bounds.MaxRewardRate != 0 && req.RewardRate > bounds.MaxRewardRate:
+		return false
+	}
+
+	return true
+}
@@ -67,4 +67,9 @@ type DB interface {
 	// DeleteSession removes all data associated with a particular session
 	// id from the tower's database.
 	DeleteSession(wtdb.SessionID) error
+
+	// IsBlacklisted returns whether the client with the given session id
+	// has been blacklisted by the tower's operator, in which case its
+	// connections are refused.
+	IsBlacklisted(*wtdb.SessionID) (bool, error)
 }
@@ -0,0 +1,126 @@
+package wtserver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/litecoinfinance/lnd/lnwallet"
+	"github.com/litecoinfinance/lnd/watchtower/blob"
+)
+
+// ErrInvalidPolicyBounds signals that a minimum of the proposed policy bounds
+// exceeds the corresponding maximum.
+var ErrInvalidPolicyBounds = errors.New("policy bound minimum exceeds maximum")
+
+// PolicyBounds restricts the session policies the server accepts from clients.
+// A zero value disables the respective bound, such that the zero PolicyBounds
+// accepts any policy the server is otherwise able to honor.
+type PolicyBounds struct {
+	// MaxUpdates is the largest number of updates a client may request
+	// for a single session.
+	MaxUpdates uint16
+
+	// MinRewardBase and MaxRewardBase bound the base reward, in litoshis,
+	// of reward sessions. Altruist sessions aren't affected.
+	MinRewardBase uint32
+	MaxRewardBase uint32
+
+	// MinRewardRate and MaxRewardRate bound the proportional reward, in
+	// millionths of the channel's value, of reward sessions. Altruist
+	// sessions aren't affected.
+	MinRewardRate uint32
+	MaxRewardRate uint32
+
+	// MinSweepFeeRate and MaxSweepFeeRate bound the fee rate justice
+	// transactions may pay. The minimum is enforced in addition to the
+	// relay fee of the chain backend.
+	MinSweepFeeRate lnwallet.SatPerKWeight
+	MaxSweepFeeRate lnwallet.SatPerKWeight
+
+	// BlobTypes is the set of blob types clients may negotiate. If empty,
+	// all blob types supported by the server are accepted.
+	BlobTypes []blob.Type
+}
+
+// Validate returns an error if the bounds can't be satisfied by any policy, or
+// permit a blob type the server doesn't support.
+func (b *PolicyBounds) Validate() error {
+	switch {
+	case b.MaxRewardBase != 0 && b.MinRewardBase > b.MaxRewardBase:
+		return fmt.Errorf("%v: reward base", ErrInvalidPolicyBounds)
+
+	case b.MaxRewardRate != 0 && b.MinRewardRate > b.MaxRewardRate:
+		return fmt.Errorf("%v: reward rate", ErrInvalidPolicyBounds)
+
+	case b.MaxSweepFeeRate != 0 && b.MinSweepFeeRate > b.MaxSweepFeeRate:
+		return fmt.Errorf("%v: sweep fee rate", ErrInvalidPolicyBounds)
+	}
+
+	for _, blobType := range b.BlobTypes {
+		if !blob.IsSupportedType(blobType) {
+			return fmt.Errorf("unsupported blob type %s", blobType)
+		}
+	}
+
+	return nil
+}
+
+// AllowsBlobType returns whether clients may negotiate sessions of the given
+// blob type.
+func (b *PolicyBounds) AllowsBlobType(blobType blob.Type) bool {
+	if !blob.IsSupportedType(blobType) {
+		return false
+	}
+	if len(b.BlobTypes) == 0 {
+		return true
+	}
+
+	for _, allowed := range b.BlobTypes {
+		if allowed == blobType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// copy returns a deep copy of the bounds, such that callers can't modify the
+// blob types of the bounds enforced by the server.
+func (b PolicyBounds) copy() PolicyBounds {
+	if b.BlobTypes != nil {
+		b.BlobTypes = append([]blob.Type(nil), b.BlobTypes...)
+	}
+
+	return b
+}
+
+// PolicyBounds returns the bounds currently restricting the policies of new
+// sessions.
+func (s *Server) PolicyBounds() PolicyBounds {
+	s.boundsMtx.RLock()
+	defer s.boundsMtx.RUnlock()
+
+	return s.bounds.copy()
+}
+
+// SetPolicyBounds replaces the bounds restricting the policies of new sessions.
+// Sessions that have already been negotiated aren't affected.
+func (s *Server) SetPolicyBounds(bounds PolicyBounds) error {
+	if err := bounds.Validate(); err != nil {
+		return err
+	}
+
+	s.boundsMtx.Lock()
+	s.bounds = bounds.copy()
+	s.boundsMtx.Unlock()
+
+	log.Infof("Updated session policy bounds: max_updates=%d "+
+		"reward_base=[%d, %d] reward_rate=[%d, %d] "+
+		"sweep_fee_rate=[%d, %d] blob_types=%v", bounds.MaxUpdates,
+		bounds.MinRewardBase, bounds.MaxRewardBase,
+		bounds.MinRewardRate, bounds.MaxRewardRate,
+		bounds.MinSweepFeeRate, bounds.MaxSweepFeeRate,
+		bounds.BlobTypes)
+
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/litecoinfinance/btcd/chaincfg/chainhash"
 	"github.com/litecoinfinance/btcd/connmgr"
 	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/watchtower/wtdb"
 	"github.com/litecoinfinance/lnd/watchtower/wtwire"
@@ -56,6 +57,18 @@ type Config struct {
 	// ChainHash identifies the network that the server is watching.
 	ChainHash chainhash.Hash
 
+	// MinSweepFeeRate returns the minimum fee rate that justice
+	// transactions must currently pay in order to be relayed by our chain
+	// backend. Sessions requesting a lower sweep fee rate are rejected. If
+	// nil, the sweep fee rate isn't validated.
+	MinSweepFeeRate func() lnwallet.SatPerKWeight
+
+	// PolicyBounds are the initial bounds restricting the policies of new
+	// sessions, which can later be replaced using SetPolicyBounds. Sessions
+	// proposing a reward below the minimum are rejected, and the client is
+	// informed of the required reward so that it can renegotiate.
+	PolicyBounds PolicyBounds
+
 	// NoAckCreateSession causes the server to not reply to create session
 	// requests, this should only be used for testing.
 	NoAckCreateSession bool
@@ -83,6 +96,9 @@ type Server struct {
 
 	localInit *wtwire.Init
 
+	boundsMtx sync.RWMutex
+	bounds    PolicyBounds
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -91,6 +107,10 @@ type Server struct {
 // clients connecting to the listener addresses, and allows them to open
 // sessions and send state updates.
 func New(cfg *Config) (*Server, error) {
+	if err := cfg.PolicyBounds.Validate(); err != nil {
+		return nil, err
+	}
+
 	localInit := wtwire.NewInitMessage(
 		lnwire.NewRawFeatureVector(wtwire.WtSessionsOptional),
 		cfg.ChainHash,
@@ -101,6 +121,7 @@ func New(cfg *Config) (*Server, error) {
 		clients:   make(map[wtdb.SessionID]Peer),
 		newPeers:  make(chan Peer),
 		localInit: localInit,
+		bounds:    cfg.PolicyBounds.copy(),
 		quit:      make(chan struct{}),
 	}
 
@@ -211,10 +232,26 @@ func (s *Server) handleClient(peer Peer) {
 	// Use the connection's remote pubkey as the client's session id.
 	id := wtdb.NewSessionIDFromPubKey(peer.RemotePub())
 
+	// Refuse the connection outright if the client has been blacklisted
+	// by the tower's operator.
+	blacklisted, err := s.cfg.DB.IsBlacklisted(&id)
+	if err != nil {
+		log.Errorf("Unable to check blacklist for client %s@%s: %v",
+			id, peer.RemoteAddr(), err)
+		peer.Close()
+		return
+	}
+	if blacklisted {
+		log.Infof("Refusing connection from blacklisted client %s@%s",
+			id, peer.RemoteAddr())
+		peer.Close()
+		return
+	}
+
 	// Register this peer in the server's client map, and defer the
 	// connection's cleanup. If the peer already exists, we will close the
 	// connection and exit immediately.
-	err := s.addPeer(&id, peer)
+	err = s.addPeer(&id, peer)
 	if err != nil {
 		peer.Close()
 		return
@@ -387,6 +424,22 @@ func (s *Server) removePeer(id *wtdb.SessionID, addr net.Addr) {
 	}
 }
 
+// DisconnectClient closes the connection of the client with the given session
+// id, if it is currently connected.
+func (s *Server) DisconnectClient(id *wtdb.SessionID) {
+	s.clientMtx.RLock()
+	peer, ok := s.clients[*id]
+	s.clientMtx.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	log.Infof("Disconnecting client %s@%s", id, peer.RemoteAddr())
+
+	peer.Close()
+}
+
 // removeAllPeers iterates through the server's current set of peers and closes
 // all open connections.
 func (s *Server) removeAllPeers() {
@@ -10,6 +10,7 @@ import (
 	"github.com/litecoinfinance/btcd/chaincfg"
 	"github.com/litecoinfinance/btcd/txscript"
 	"github.com/litecoinfinance/btcutil"
+	"github.com/litecoinfinance/lnd/lnwallet"
 	"github.com/litecoinfinance/lnd/lnwire"
 	"github.com/litecoinfinance/lnd/watchtower/blob"
 	"github.com/litecoinfinance/lnd/watchtower/wtdb"
@@ -31,6 +32,18 @@ var (
 	rewardType = (blob.FlagCommitOutputs | blob.FlagReward).Type()
 )
 
+// rewardReqsData returns the encoded reward requirements sent by the server
+// when rejecting a reward session.
+func rewardReqsData(base, rate uint32) []byte {
+	reqs := &wtwire.RewardRequirements{
+		MinRewardBase: base,
+		MinRewardRate: rate,
+	}
+	data, _ := reqs.Encode()
+
+	return data
+}
+
 // randPubKey generates a new secp keypair, and returns the public key.
 func randPubKey(t *testing.T) *btcec.PublicKey {
 	t.Helper()
@@ -62,6 +75,9 @@ func initServer(t *testing.T, db wtserver.DB,
 			return addr, nil
 		},
 		ChainHash: testnetChainHash,
+		MinSweepFeeRate: func() lnwallet.SatPerKWeight {
+			return 1
+		},
 	})
 	if err != nil {
 		t.Fatalf("unable to create server: %v", err)
@@ -158,6 +174,7 @@ type createSessionTestCase struct {
 	expReply        *wtwire.CreateSessionReply
 	expDupReply     *wtwire.CreateSessionReply
 	sendStateUpdate bool
+	bounds          *wtserver.PolicyBounds
 }
 
 var createSessionTests = []createSessionTestCase{
@@ -247,7 +264,179 @@ var createSessionTests = []createSessionTestCase{
 			Data: []byte{},
 		},
 	},
-	// TODO(conner): add policy rejection tests
+	{
+		name: "reject sweep fee rate below relay fee",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     blob.TypeDefault,
+			MaxUpdates:   1000,
+			RewardBase:   0,
+			RewardRate:   0,
+			SweepFeeRate: 0,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectSweepFeeRate,
+			Data: []byte{},
+		},
+	},
+	{
+		name: "reject max updates above bound",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     blob.TypeDefault,
+			MaxUpdates:   1000,
+			RewardBase:   0,
+			RewardRate:   0,
+			SweepFeeRate: 1,
+		},
+		bounds: &wtserver.PolicyBounds{
+			MaxUpdates: 500,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectMaxUpdates,
+			Data: []byte{},
+		},
+	},
+	{
+		name: "reject blob type not allowed",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     rewardType,
+			MaxUpdates:   1000,
+			RewardBase:   0,
+			RewardRate:   0,
+			SweepFeeRate: 1,
+		},
+		bounds: &wtserver.PolicyBounds{
+			BlobTypes: []blob.Type{blob.TypeDefault},
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectBlobType,
+			Data: []byte{},
+		},
+	},
+	{
+		name: "reject sweep fee rate below bound",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     blob.TypeDefault,
+			MaxUpdates:   1000,
+			RewardBase:   0,
+			RewardRate:   0,
+			SweepFeeRate: 1,
+		},
+		bounds: &wtserver.PolicyBounds{
+			MinSweepFeeRate: 10,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectSweepFeeRate,
+			Data: []byte{},
+		},
+	},
+	{
+		name: "reject sweep fee rate above bound",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     blob.TypeDefault,
+			MaxUpdates:   1000,
+			RewardBase:   0,
+			RewardRate:   0,
+			SweepFeeRate: 1000,
+		},
+		bounds: &wtserver.PolicyBounds{
+			MaxSweepFeeRate: 100,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectSweepFeeRate,
+			Data: []byte{},
+		},
+	},
+	{
+		name: "reject reward rate above bound",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     rewardType,
+			MaxUpdates:   1000,
+			RewardBase:   0,
+			RewardRate:   20000,
+			SweepFeeRate: 1,
+		},
+		bounds: &wtserver.PolicyBounds{
+			MaxRewardRate: 10000,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectRewardRate,
+			Data: rewardReqsData(0, 0),
+		},
+	},
+	{
+		name: "reject reward below bound",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     rewardType,
+			MaxUpdates:   1000,
+			RewardBase:   0,
+			RewardRate:   0,
+			SweepFeeRate: 1,
+		},
+		bounds: &wtserver.PolicyBounds{
+			MinRewardBase: 10,
+			MinRewardRate: 100,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectRewardRate,
+			Data: rewardReqsData(10, 100),
+		},
+	},
+	{
+		name: "accept policy within bounds",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     rewardType,
+			MaxUpdates:   500,
+			RewardBase:   10,
+			RewardRate:   100,
+			SweepFeeRate: 10,
+		},
+		bounds: &wtserver.PolicyBounds{
+			MaxUpdates:      500,
+			MinRewardBase:   10,
+			MaxRewardBase:   10,
+			MinRewardRate:   100,
+			MaxRewardRate:   100,
+			MinSweepFeeRate: 10,
+			MaxSweepFeeRate: 10,
+			BlobTypes:       []blob.Type{rewardType},
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CodeOK,
+			Data: addrScript,
+		},
+	},
 }
 
 // TestServerCreateSession checks the server's behavior in response to a
@@ -268,6 +457,14 @@ func testServerCreateSession(t *testing.T, i int, test createSessionTestCase) {
 	s := initServer(t, nil, timeoutDuration)
 	defer s.Stop()
 
+	// Restrict the policies accepted by the server if the test requires.
+	if test.bounds != nil {
+		err := s.(*wtserver.Server).SetPolicyBounds(*test.bounds)
+		if err != nil {
+			t.Fatalf("unable to set policy bounds: %v", err)
+		}
+	}
+
 	localPub := randPubKey(t)
 
 	// Create a new client and connect to server.
@@ -811,6 +1008,53 @@ func TestServerDeleteSession(t *testing.T) {
 	}
 }
 
+// TestServerBlacklistedClient asserts that the server refuses connections from
+// blacklisted clients, and accepts them again once they've been removed from
+// the blacklist.
+func TestServerBlacklistedClient(t *testing.T) {
+	t.Parallel()
+
+	db := wtmock.NewTowerDB()
+
+	const timeoutDuration = 100 * time.Millisecond
+
+	s := initServer(t, db, timeoutDuration)
+	defer s.Stop()
+
+	localPub := randPubKey(t)
+	peerPub := randPubKey(t)
+	id := wtdb.NewSessionIDFromPubKey(peerPub)
+
+	initMsg := wtwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(), testnetChainHash,
+	)
+
+	if err := db.BlacklistClient(id); err != nil {
+		t.Fatalf("unable to blacklist client: %v", err)
+	}
+
+	// The blacklisted client should be disconnected without the server
+	// ever replying to its Init message.
+	peer := wtmock.NewMockPeer(localPub, peerPub, nil, 0)
+	s.InboundPeerConnected(peer)
+	assertConnClosed(t, peer, 2*timeoutDuration)
+
+	select {
+	case <-peer.OutgoingMsgs:
+		t.Fatalf("server replied to blacklisted client")
+	default:
+	}
+
+	// Once removed from the blacklist, the client should be able to
+	// connect again.
+	if err := db.UnblacklistClient(id); err != nil {
+		t.Fatalf("unable to unblacklist client: %v", err)
+	}
+
+	peer = wtmock.NewMockPeer(localPub, peerPub, nil, 0)
+	connect(t, s, peer, initMsg, timeoutDuration)
+}
+
 func connect(t *testing.T, s wtserver.Interface, peer *wtmock.MockPeer,
 	initMsg *wtwire.Init, timeout time.Duration) {
 
@@ -903,3 +1147,67 @@ func assertConnClosed(t *testing.T, peer *wtmock.MockPeer, duration time.Duratio
 		t.Fatalf("expected connection to be closed")
 	}
 }
+
+// TestPolicyBoundsValidate asserts that policy bounds which can't be satisfied
+// by any policy, or which permit unsupported blob types, are rejected.
+func TestPolicyBoundsValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		bounds wtserver.PolicyBounds
+		valid  bool
+	}{
+		{
+			name:  "zero bounds",
+			valid: true,
+		},
+		{
+			name: "min reward base above max",
+			bounds: wtserver.PolicyBounds{
+				MinRewardBase: 2,
+				MaxRewardBase: 1,
+			},
+		},
+		{
+			name: "min reward rate above max",
+			bounds: wtserver.PolicyBounds{
+				MinRewardRate: 2,
+				MaxRewardRate: 1,
+			},
+		},
+		{
+			name: "min sweep fee rate above max",
+			bounds: wtserver.PolicyBounds{
+				MinSweepFeeRate: 2,
+				MaxSweepFeeRate: 1,
+			},
+		},
+		{
+			name: "min without max",
+			bounds: wtserver.PolicyBounds{
+				MinRewardBase:   2,
+				MinRewardRate:   2,
+				MinSweepFeeRate: 2,
+			},
+			valid: true,
+		},
+		{
+			name: "unsupported blob type",
+			bounds: wtserver.PolicyBounds{
+				BlobTypes: []blob.Type{0},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		err := test.bounds.Validate()
+		if test.valid && err != nil {
+			t.Fatalf("%s: expected bounds to be valid, got %v",
+				test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("%s: expected bounds to be invalid", test.name)
+		}
+	}
+}
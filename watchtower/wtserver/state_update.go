@@ -107,6 +107,11 @@ func (s *Server) handleStateUpdate(peer Peer, id *wtdb.SessionID,
 	case err == wtdb.ErrSessionConsumed:
 		failCode = wtwire.StateUpdateCodeMaxUpdatesExceeded
 
+	// A client that has exhausted its storage quota is treated as if it
+	// had exhausted its session, prompting it to negotiate a new one.
+	case err == wtdb.ErrClientQuotaExceeded:
+		failCode = wtwire.StateUpdateCodeMaxUpdatesExceeded
+
 	case err == wtdb.ErrUpdateOutOfOrder:
 		failCode = wtwire.StateUpdateCodeSeqNumOutOfOrder
 
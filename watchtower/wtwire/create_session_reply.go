@@ -1,6 +1,9 @@
 package wtwire
 
-import "io"
+import (
+	"bytes"
+	"io"
+)
 
 // CreateSessionCode is an error code returned by a watchtower in response to a
 // CreateSession message. The code directs the client in interpreting the payload
@@ -36,6 +39,42 @@ const (
 // the Data field, which is a varint up to 3 bytes in size.
 const MaxCreateSessionReplyDataLength = 1024
 
+// RewardRequirements is the payload of a CreateSessionReply rejecting the
+// reward proposed by the client, i.e. one with code
+// CreateSessionCodeRejectRewardRate. It encodes the minimum reward the tower
+// requires, allowing the client to propose a new session with an adequate
+// reward.
+type RewardRequirements struct {
+	// MinRewardBase is the minimum fixed amount the tower requires as its
+	// reward.
+	MinRewardBase uint32
+
+	// MinRewardRate is the minimum fraction of the total balance of the
+	// revoked commitment the tower requires as its reward, expressed in
+	// millionths.
+	MinRewardRate uint32
+}
+
+// Encode serializes the RewardRequirements, such that they can be used as the
+// Data of a CreateSessionReply.
+func (r *RewardRequirements) Encode() ([]byte, error) {
+	var b bytes.Buffer
+	err := WriteElements(&b, r.MinRewardBase, r.MinRewardRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// Decode deserializes RewardRequirements from the Data of a
+// CreateSessionReply.
+func (r *RewardRequirements) Decode(data []byte) error {
+	return ReadElements(
+		bytes.NewReader(data), &r.MinRewardBase, &r.MinRewardRate,
+	)
+}
+
 // CreateSessionReply is a message sent from watchtower to client in response to a
 // CreateSession message, and signals either an acceptance or rejection of the
 // proposed session parameters.